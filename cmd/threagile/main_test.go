@@ -80,3 +80,49 @@ func TestParseModelJson(t *testing.T) {
 		return
 	}
 }
+
+func TestLoadModelJson(t *testing.T) {
+	yamlModelFile := filepath.Join("..", "..", "test", "all.yaml")
+	yamlModel := *new(input.Model).Defaults()
+	yamlLoadError := yamlModel.Load(yamlModelFile)
+	if yamlLoadError != nil {
+		t.Errorf("unable to parse model yaml %q: %v", yamlModelFile, yamlLoadError)
+		return
+	}
+
+	yamlData, yamlMarshalError := json.MarshalIndent(yamlModel, "", "  ")
+	if yamlMarshalError != nil {
+		t.Errorf("unable to print model yaml %q: %v", yamlModelFile, yamlMarshalError)
+		return
+	}
+
+	// tab indentation is valid json but no valid yaml, so this only loads when parsed as json
+	jsonModelData, jsonMarshalError := json.MarshalIndent(yamlModel, "", "\t")
+	if jsonMarshalError != nil {
+		t.Error("unable to print model json: ", jsonMarshalError)
+		return
+	}
+
+	for _, name := range []string{"threagile.json", "threagile-upload"} {
+		jsonModelFile := filepath.Join(t.TempDir(), name)
+		_ = os.WriteFile(jsonModelFile, jsonModelData, 0600)
+
+		jsonModel := *new(input.Model).Defaults()
+		jsonLoadError := jsonModel.Load(jsonModelFile)
+		if jsonLoadError != nil {
+			t.Errorf("unable to parse model json %q: %v", jsonModelFile, jsonLoadError)
+			return
+		}
+
+		jsonData, jsonDataMarshalError := json.MarshalIndent(jsonModel, "", "  ")
+		if jsonDataMarshalError != nil {
+			t.Errorf("unable to print model json %q: %v", jsonModelFile, jsonDataMarshalError)
+			return
+		}
+
+		if string(yamlData) != string(jsonData) {
+			t.Errorf("parsing json model files is broken; diff: %v", textdiff.Unified(yamlModelFile, jsonModelFile, string(yamlData), string(jsonData)))
+			return
+		}
+	}
+}
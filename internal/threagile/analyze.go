@@ -1,11 +1,20 @@
 package threagile
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
 	"github.com/spf13/cobra"
 	"github.com/threagile/threagile/pkg/common"
 	"github.com/threagile/threagile/pkg/model"
 	"github.com/threagile/threagile/pkg/report"
+	"github.com/threagile/threagile/pkg/security/types"
 )
 
 func (what *Threagile) initAnalyze() *Threagile {
@@ -16,17 +25,42 @@ func (what *Threagile) initAnalyze() *Threagile {
 		RunE: func(cmd *cobra.Command, args []string) error {
 			cfg := what.readConfig(cmd, what.buildTimestamp)
 			commands := what.readCommands()
-			progressReporter := common.DefaultProgressReporter{Verbose: cfg.Verbose}
+			warnings := &common.Warnings{}
+			progressReporter := common.DefaultProgressReporter{Verbose: cfg.Verbose, Warnings: warnings}
+			if len(os.Getenv(common.ProgressEventsEnvName)) > 0 {
+				progressReporter.Events = common.LogStderr()
+			}
+			if _, err := types.ParseRiskGate(cfg.FailOnRisk); err != nil {
+				return err
+			}
+			defer func() {
+				if err := warnings.Write(filepath.Join(cfg.OutputFolder, cfg.WarningsFilename)); err != nil {
+					progressReporter.Infof("unable to write warnings: %v", err)
+				}
+			}()
 
-			r, err := model.ReadAndAnalyzeModel(cfg, progressReporter)
+			r, err := model.ReadAndAnalyzeModel(cmd.Context(), cfg, progressReporter)
 			if err != nil {
-				return fmt.Errorf("failed to read and analyze model: %v", err)
+				return writeFailureReport(cfg, fmt.Errorf("failed to read and analyze model: %w", err))
 			}
 
-			err = report.Generate(cfg, r, commands, progressReporter)
+			resultFiles, err := report.GenerateFiles(cmd.Context(), cfg, r, commands, progressReporter)
+			if err != nil {
+				return writeFailureReport(cfg, common.NewFailure(common.ExitCodeRenderFailure, fmt.Errorf("failed to generate reports: %w", err)))
+			}
+			err = runPostAnalysisHooks(cmd.Context(), cfg, resultFiles, progressReporter)
+			if err != nil {
+				return writeFailureReport(cfg, err)
+			}
+			err = checkRiskGate(cfg, r.ParsedModel)
+			if err != nil {
+				return writeFailureReport(cfg, err)
+			}
+			err = checkPolicyGate(cmd.Context(), cfg, r.Result)
 			if err != nil {
-				return fmt.Errorf("failed to generate reports: %v", err)
+				return writeFailureReport(cfg, err)
 			}
+			_ = os.Remove(filepath.Join(cfg.OutputFolder, cfg.FailureFilename))
 			return nil
 		},
 		CompletionOptions: cobra.CompletionOptions{
@@ -38,3 +72,314 @@ func (what *Threagile) initAnalyze() *Threagile {
 
 	return what
 }
+
+// writeFailureReport describes the failure of the run in failure.json of the output folder, so that pipelines can tell
+// the failed stage apart without parsing the log
+func writeFailureReport(cfg *common.Config, err error) error {
+	reportError := common.WriteFailureReport(filepath.Join(cfg.OutputFolder, cfg.FailureFilename), err)
+	if reportError != nil {
+		return fmt.Errorf("%w (unable to write failure report: %v)", err, reportError)
+	}
+	return err
+}
+
+// runPostAnalysisHooks hands the model and the written reports to the post-analysis hooks, e.g. to upload them
+func runPostAnalysisHooks(ctx context.Context, cfg *common.Config, resultFiles []string, progressReporter types.ProgressReporter) error {
+	event := &model.HookEvent{Stage: model.PostAnalysisHookStage, ModelFile: cfg.InputFile, OutputFolder: cfg.OutputFolder, ResultFiles: resultFiles}
+	return model.RunHooks(ctx, cfg, event, progressReporter)
+}
+
+// maxRiskGateViolationsReported limits the risks listed in the error of the risk gate, all of them are in the risks json
+const maxRiskGateViolationsReported = 10
+
+// checkRiskGate fails with the policy gate exit code when risks of the analyzed model match the risk gate of the
+// config, after the reports were generated so that the pipeline still gets them
+func checkRiskGate(cfg *common.Config, parsedModel *types.Model) error {
+	if len(cfg.FailOnRisk) == 0 {
+		return nil
+	}
+	gate, err := types.ParseRiskGate(cfg.FailOnRisk)
+	if err != nil {
+		return err
+	}
+	violations := gate.Violations(types.AllRisks(parsedModel))
+	if len(violations) == 0 {
+		return nil
+	}
+	types.SortByRiskSeverity(violations, parsedModel)
+	ids := make([]string, 0, maxRiskGateViolationsReported)
+	for _, risk := range violations {
+		if len(ids) == maxRiskGateViolationsReported {
+			ids = append(ids, "...")
+			break
+		}
+		ids = append(ids, risk.SyntheticId)
+	}
+	return common.NewFailure(common.ExitCodePolicyGateFailure, fmt.Errorf("%d risks match the risk gate %v: %v", len(violations), gate, strings.Join(ids, ", ")))
+}
+
+// checkPolicyGate fails with the policy gate exit code when the OPA/Rego gate policies deny the result of the analysis,
+// listing the violations (all of them in the details of the failure report)
+func checkPolicyGate(ctx context.Context, cfg *common.Config, result *model.Result) error {
+	violations, err := model.CheckRegoPolicyGate(ctx, cfg.PolicyGateRegoFolder, result, cfg.PluginTimeoutSeconds)
+	if err != nil || len(violations) == 0 {
+		return err
+	}
+	reported := violations
+	if len(reported) > maxRiskGateViolationsReported {
+		reported = append(reported[:maxRiskGateViolationsReported:maxRiskGateViolationsReported], "...")
+	}
+	return &common.Failure{
+		Code:    common.ExitCodePolicyGateFailure,
+		Err:     fmt.Errorf("%d violations of the policy gate %v: %v", len(violations), cfg.PolicyGateRegoFolder, strings.Join(reported, "; ")),
+		Details: violations,
+	}
+}
+
+// analysisSummary is the outcome of analyzing one of the models of analyze-all
+type analysisSummary struct {
+	Model  string                `json:"model"`
+	Output string                `json:"output"`
+	Error  string                `json:"error,omitempty"`
+	Kind   string                `json:"kind,omitempty"`
+	Stats  *types.RiskStatistics `json:"stats,omitempty"`
+}
+
+func (what *Threagile) initAnalyzeAll() *Threagile {
+	what.rootCmd.AddCommand(&cobra.Command{
+		Use:   common.AnalyzeAllCommand + " <model files, e.g. ./models/**/threagile.yaml>",
+		Short: "Analyze many models in parallel",
+		Long:  "Analyze all given models (patterns may use ** to match any number of folders) in parallel, writing the artifacts of each model into a folder of its own below --" + outputRootFlagName + " and a combined summary.json",
+		Args:  cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg := what.readConfig(cmd, what.buildTimestamp)
+			commands := what.readCommands()
+			if _, err := types.ParseRiskGate(cfg.FailOnRisk); err != nil {
+				return err
+			}
+
+			modelFiles, err := expandModelPatterns(args)
+			if err != nil {
+				return err
+			}
+			if len(modelFiles) == 0 {
+				return fmt.Errorf("no models found matching %v", strings.Join(args, " "))
+			}
+
+			outputRoot := cfg.OutputFolder
+			if len(what.flags.outputRootFlag) > 0 {
+				outputRoot = cfg.CleanPath(what.flags.outputRootFlag)
+			}
+			outputFolders := modelOutputFolders(outputRoot, modelFiles)
+
+			workers := what.flags.workersFlag
+			if workers < 1 {
+				workers = 1
+			}
+
+			summaries := make([]analysisSummary, len(modelFiles))
+			jobs := make(chan int)
+			var wg sync.WaitGroup
+			for w := 0; w < workers; w++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					for i := range jobs {
+						summaries[i] = analyzeOne(cmd.Context(), cfg, commands, modelFiles[i], outputFolders[i])
+					}
+				}()
+			}
+			for i := range modelFiles {
+				jobs <- i
+			}
+			close(jobs)
+			wg.Wait()
+
+			failures := 0
+			for _, summary := range summaries {
+				if len(summary.Error) > 0 {
+					failures++
+					cmd.Printf("FAILED %v: %v\n", summary.Model, summary.Error)
+				} else {
+					cmd.Printf("analyzed %v into %v\n", summary.Model, summary.Output)
+				}
+			}
+
+			summaryJSON, err := json.MarshalIndent(summaries, "", "  ")
+			if err != nil {
+				return fmt.Errorf("failed to marshal summary: %v", err)
+			}
+			err = os.WriteFile(filepath.Join(outputRoot, "summary.json"), summaryJSON, 0600)
+			if err != nil {
+				return fmt.Errorf("failed to write summary: %v", err)
+			}
+
+			if failures > 0 {
+				return fmt.Errorf("%d of %d models failed", failures, len(modelFiles))
+			}
+			return nil
+		},
+	})
+
+	return what
+}
+
+func analyzeOne(ctx context.Context, cfg *common.Config, commands *report.GenerateCommands, modelFile string, outputFolder string) (summary analysisSummary) {
+	summary = analysisSummary{Model: modelFile, Output: outputFolder}
+	defer func() {
+		// a model crashing the analysis fails on its own, the other models are still analyzed
+		if r := recover(); r != nil {
+			summary.Error = fmt.Sprintf("analysis crashed: %v", r)
+			summary.Kind = common.ExitCodeFailure.String()
+			summary.Stats = nil
+		}
+	}()
+
+	modelConfig := *cfg
+	modelConfig.InputFile = modelFile
+	modelConfig.OutputFolder = outputFolder
+	progressReporter := common.DefaultProgressReporter{Verbose: cfg.Verbose}
+
+	err := os.MkdirAll(outputFolder, 0700)
+	if err != nil {
+		summary.Error = err.Error()
+		return summary
+	}
+
+	r, err := model.ReadAndAnalyzeModel(ctx, &modelConfig, progressReporter)
+	if err != nil {
+		summary.Error = fmt.Sprintf("failed to read and analyze model: %v", err)
+		summary.Kind = common.ExitCodeOf(err).String()
+		return summary
+	}
+
+	resultFiles, err := report.GenerateFiles(ctx, &modelConfig, r, commands, progressReporter)
+	if err != nil {
+		summary.Error = fmt.Sprintf("failed to generate reports: %v", err)
+		summary.Kind = common.ExitCodeOf(common.NewFailure(common.ExitCodeRenderFailure, err)).String()
+		return summary
+	}
+
+	err = runPostAnalysisHooks(ctx, &modelConfig, resultFiles, progressReporter)
+	if err != nil {
+		summary.Error = err.Error()
+		summary.Kind = common.ExitCodeOf(err).String()
+		return summary
+	}
+
+	stats := types.OverallRiskStatistics(r.ParsedModel)
+	summary.Stats = &stats
+
+	err = checkRiskGate(&modelConfig, r.ParsedModel)
+	if err == nil {
+		err = checkPolicyGate(ctx, &modelConfig, r.Result)
+	}
+	if err != nil {
+		summary.Error = err.Error()
+		summary.Kind = common.ExitCodeOf(err).String()
+	}
+	return summary
+}
+
+// expandModelPatterns resolves the model file patterns, where ** (unlike with filepath.Glob) matches any number of
+// folders, so that quoted patterns work independent of the globbing support of the shell
+func expandModelPatterns(patterns []string) ([]string, error) {
+	files := make([]string, 0)
+	seen := make(map[string]bool)
+	for _, pattern := range patterns {
+		var matches []string
+		var err error
+		if strings.Contains(pattern, "**") {
+			matches, err = globRecursive(pattern)
+		} else {
+			matches, err = filepath.Glob(pattern)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid model pattern %q: %v", pattern, err)
+		}
+
+		for _, match := range matches {
+			match = filepath.Clean(match)
+			if !seen[match] {
+				seen[match] = true
+				files = append(files, match)
+			}
+		}
+	}
+	return files, nil
+}
+
+func globRecursive(pattern string) ([]string, error) {
+	parts := strings.SplitN(filepath.ToSlash(pattern), "**", 2)
+	root := filepath.FromSlash(strings.TrimSuffix(parts[0], "/"))
+	if len(root) == 0 {
+		root = "."
+	}
+	suffix := strings.TrimPrefix(parts[1], "/")
+	suffixDepth := strings.Count(suffix, "/") + 1
+
+	matches := make([]string, 0)
+	err := filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil || entry.IsDir() {
+			return err
+		}
+
+		if len(suffix) > 0 {
+			segments := strings.Split(filepath.ToSlash(path), "/")
+			if len(segments) < suffixDepth {
+				return nil
+			}
+			matched, matchError := filepath.Match(suffix, strings.Join(segments[len(segments)-suffixDepth:], "/"))
+			if matchError != nil || !matched {
+				return matchError
+			}
+		}
+
+		matches = append(matches, path)
+		return nil
+	})
+	return matches, err
+}
+
+// modelOutputFolders mirrors the folders of the models below the output root, models sharing a folder get a
+// sub-folder named like their file. Folders outside the working directory are cut down to their name, so models
+// ending up in the same output folder that way get it numbered (-2, -3, ...) in the order of the model files.
+func modelOutputFolders(outputRoot string, modelFiles []string) []string {
+	modelsPerFolder := make(map[string]int)
+	for _, modelFile := range modelFiles {
+		modelsPerFolder[filepath.Dir(modelFile)]++
+	}
+
+	outputFolders := make([]string, len(modelFiles))
+	for i, modelFile := range modelFiles {
+		folder := filepath.Dir(modelFile)
+		if filepath.IsAbs(folder) || strings.HasPrefix(filepath.ToSlash(folder), "../") || folder == ".." {
+			folder = filepath.Base(folder)
+		}
+		if modelsPerFolder[filepath.Dir(modelFile)] > 1 {
+			folder = filepath.Join(folder, strings.TrimSuffix(filepath.Base(modelFile), filepath.Ext(modelFile)))
+		}
+		outputFolders[i] = filepath.Join(outputRoot, folder)
+	}
+
+	used := make(map[string]bool)
+	for _, outputFolder := range outputFolders {
+		used[outputFolder] = true
+	}
+	taken := make(map[string]bool)
+	for i, outputFolder := range outputFolders {
+		if !taken[outputFolder] {
+			taken[outputFolder] = true
+			continue
+		}
+		for n := 2; ; n++ {
+			numbered := fmt.Sprintf("%v-%d", outputFolder, n)
+			if !used[numbered] {
+				used[numbered], taken[numbered] = true, true
+				outputFolders[i] = numbered
+				break
+			}
+		}
+	}
+	return outputFolders
+}
@@ -0,0 +1,42 @@
+package threagile
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/threagile/threagile/pkg/common"
+)
+
+func TestModelOutputFolders(t *testing.T) {
+	root := filepath.Join("out")
+	modelFiles := []string{
+		filepath.Join("team", "a", "threagile.yaml"),
+		filepath.Join("team", "b", "one.yaml"),
+		filepath.Join("team", "b", "two.yaml"),
+		filepath.Join("/", "a", "x", "threagile.yaml"),
+		filepath.Join("/", "b", "x", "threagile.yaml"),
+		filepath.Join("..", "y", "threagile.yaml"),
+		filepath.Join("y", "threagile.yaml"),
+		filepath.Join("x-2", "threagile.yaml"),
+	}
+
+	assert.Equal(t, []string{
+		filepath.Join(root, "team", "a"),
+		filepath.Join(root, "team", "b", "one"),
+		filepath.Join(root, "team", "b", "two"),
+		filepath.Join(root, "x"),
+		filepath.Join(root, "x-3"),
+		filepath.Join(root, "y"),
+		filepath.Join(root, "y-2"),
+		filepath.Join(root, "x-2"),
+	}, modelOutputFolders(root, modelFiles))
+}
+
+func TestAnalyzeOneRecovers(t *testing.T) {
+	summary := analyzeOne(context.Background(), nil, nil, "threagile.yaml", t.TempDir())
+	assert.Contains(t, summary.Error, "analysis crashed")
+	assert.Equal(t, common.ExitCodeFailure.String(), summary.Kind)
+}
@@ -0,0 +1,168 @@
+package threagile
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+
+	"github.com/threagile/threagile/pkg/common"
+	"github.com/threagile/threagile/pkg/input"
+	"github.com/threagile/threagile/pkg/macros"
+	"github.com/threagile/threagile/pkg/model"
+	"github.com/threagile/threagile/pkg/security/risks"
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+func (what *Threagile) initCompletion() *Threagile {
+	what.rootCmd.AddCommand(&cobra.Command{
+		Use:   common.CompletionCommand + " [bash|zsh|fish|powershell]",
+		Short: "Generate shell completion script",
+		Long: "Generate the completion script for the given shell, e.g.\n\n" +
+			"  source <(threagile " + common.CompletionCommand + " bash)\n" +
+			"  threagile " + common.CompletionCommand + " zsh > \"${fpath[1]}/_threagile\"\n" +
+			"  threagile " + common.CompletionCommand + " fish > ~/.config/fish/completions/threagile.fish\n\n" +
+			"Risk rule IDs, model macro IDs and the IDs of the elements of the model file are completed dynamically.",
+		ValidArgs:             []string{"bash", "zsh", "fish", "powershell"},
+		Args:                  cobra.MatchAll(cobra.ExactArgs(1), cobra.OnlyValidArgs),
+		DisableFlagsInUseLine: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			switch args[0] {
+			case "bash":
+				return what.rootCmd.GenBashCompletionV2(os.Stdout, true)
+			case "zsh":
+				return what.rootCmd.GenZshCompletion(os.Stdout)
+			case "fish":
+				return what.rootCmd.GenFishCompletion(os.Stdout, true)
+			default:
+				return what.rootCmd.GenPowerShellCompletionWithDesc(os.Stdout)
+			}
+		},
+	})
+
+	_ = what.rootCmd.MarkPersistentFlagFilename(inputFileFlagName, "yaml", "yml")
+	_ = what.rootCmd.MarkPersistentFlagFilename(compareModelFlagName, "yaml", "yml")
+	_ = what.rootCmd.MarkPersistentFlagFilename(configFlagName, "json")
+	_ = what.rootCmd.MarkPersistentFlagDirname(outputFlagName)
+	_ = what.rootCmd.MarkPersistentFlagDirname(tempDirFlagName)
+	_ = what.rootCmd.RegisterFlagCompletionFunc(skipRiskRulesFlagName, what.completeRiskRuleList)
+
+	for _, command := range what.rootCmd.Commands() {
+		switch command.Name() {
+		case common.ExplainRuleCommand:
+			command.ValidArgsFunction = what.completeRiskRule
+		case common.ExecuteModelMacroCommand:
+			command.ValidArgsFunction = completeMacro
+		case common.ExplainCommand:
+			for _, subCommand := range command.Commands() {
+				if subCommand.Name() == common.RiskItem {
+					subCommand.ValidArgsFunction = what.completeRiskId
+				}
+			}
+		}
+	}
+
+	return what
+}
+
+// completeRiskRuleList completes the last entry of a comma-separated list of risk rule IDs
+func (what *Threagile) completeRiskRuleList(_ *cobra.Command, _ []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	prefix := ""
+	if index := strings.LastIndex(toComplete, ","); index >= 0 {
+		prefix = toComplete[:index+1]
+	}
+
+	completions := make([]string, 0)
+	for _, id := range what.riskRuleIds() {
+		completions = append(completions, prefix+id)
+	}
+	return completions, cobra.ShellCompDirectiveNoFileComp | cobra.ShellCompDirectiveNoSpace
+}
+
+func (what *Threagile) completeRiskRule(_ *cobra.Command, args []string, _ string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	return what.riskRuleIds(), cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeRiskId completes synthetic risk IDs (like risk-rule@technical-asset) segment by segment: the first segment
+// with the risk rule IDs, all following ones with the IDs of the elements of the model file
+func (what *Threagile) completeRiskId(_ *cobra.Command, _ []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	completions := make([]string, 0)
+	index := strings.LastIndex(toComplete, "@")
+	if index < 0 {
+		for _, id := range what.riskRuleIds() {
+			completions = append(completions, id+"@")
+		}
+	} else {
+		for _, id := range what.modelElementIds() {
+			completions = append(completions, toComplete[:index+1]+id)
+		}
+	}
+	return completions, cobra.ShellCompDirectiveNoFileComp | cobra.ShellCompDirectiveNoSpace
+}
+
+func completeMacro(_ *cobra.Command, args []string, _ string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	completions := make([]string, 0)
+	for _, macro := range append(macros.ListBuiltInMacros(), macros.ListCustomMacros()...) {
+		details := macro.GetMacroDetails()
+		completions = append(completions, details.ID+"\t"+details.Title)
+	}
+	return completions, cobra.ShellCompDirectiveNoFileComp
+}
+
+func (what *Threagile) riskRuleIds() []string {
+	rules := risks.GetBuiltInRiskRules()
+	if len(what.flags.customRiskRulesPluginFlag) > 0 {
+		rules.Merge(model.LoadCustomRiskRules(context.Background(), strings.Split(what.flags.customRiskRulesPluginFlag, ","), what.flags.pluginTimeoutFlag, common.DefaultProgressReporter{Verbose: false}))
+	}
+
+	ids := make([]string, 0, len(rules))
+	for id := range rules {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// modelElementIds reads the IDs of all technical assets, communication links, data assets, trust boundaries and shared
+// runtimes from the model file without analyzing it, as completion must be fast and must not print anything
+func (what *Threagile) modelElementIds() []string {
+	modelYaml, err := os.ReadFile(filepath.Clean(what.flags.inputFileFlag))
+	if err != nil {
+		return nil
+	}
+
+	var modelInput input.Model
+	if yaml.Unmarshal(modelYaml, &modelInput) != nil {
+		return nil
+	}
+
+	ids := make([]string, 0)
+	for _, technicalAsset := range modelInput.TechnicalAssets {
+		ids = append(ids, technicalAsset.ID)
+		for title := range technicalAsset.CommunicationLinks {
+			ids = append(ids, technicalAsset.ID+">"+types.MakeID(title))
+		}
+	}
+	for _, dataAsset := range modelInput.DataAssets {
+		ids = append(ids, dataAsset.ID)
+	}
+	for _, trustBoundary := range modelInput.TrustBoundaries {
+		ids = append(ids, trustBoundary.ID)
+	}
+	for _, sharedRuntime := range modelInput.SharedRuntimes {
+		ids = append(ids, sharedRuntime.ID)
+	}
+	sort.Strings(ids)
+	return ids
+}
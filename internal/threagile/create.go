@@ -10,6 +10,7 @@ import (
 	"github.com/threagile/threagile/pkg/common"
 	"github.com/threagile/threagile/pkg/docs"
 	"github.com/threagile/threagile/pkg/examples"
+	"github.com/threagile/threagile/pkg/macros"
 )
 
 func (what *Threagile) initCreate() *Threagile {
@@ -70,6 +71,22 @@ func (what *Threagile) initCreate() *Threagile {
 		},
 	})
 
+	what.rootCmd.AddCommand(&cobra.Command{
+		Use:   common.InitModelCommand,
+		Short: "Create a new threagile model interactively",
+		Long:  "\n" + docs.Logo + "\n\n" + fmt.Sprintf(docs.VersionText, what.buildTimestamp) + "\n\ninteractively ask about the system and write an initial model with technical assets, data assets and trust boundaries to the model file",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg := what.readConfig(cmd, what.buildTimestamp)
+
+			err := macros.CreateModelFile(cfg.InputFile)
+			if err != nil {
+				cmd.Printf("Unable to create model: %v", err)
+				return err
+			}
+			return nil
+		},
+	})
+
 	what.rootCmd.AddCommand(&cobra.Command{
 		Use:   common.CreateEditingSupportCommand,
 		Short: "Create editing support",
@@ -106,5 +123,25 @@ func (what *Threagile) initCreate() *Threagile {
 		},
 	})
 
+	what.rootCmd.AddCommand(&cobra.Command{
+		Use:   common.BundleCommand + " <folder|file.tar.gz>",
+		Short: "Create offline bundle",
+		Long:  "\n" + docs.Logo + "\n\n" + fmt.Sprintf(docs.VersionText, what.buildTimestamp) + "\n\ncreate a self-contained bundle of the binary, plugins, example models, schema, report template and static server assets for air-gapped environments, written as gzipped tarball when the target ends with .tar.gz or .tgz and as folder otherwise",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg := what.readConfig(cmd, what.buildTimestamp)
+
+			err := examples.CreateBundle(cfg, args[0], common.DefaultProgressReporter{Verbose: cfg.Verbose})
+			if err != nil {
+				cmd.Printf("Unable to create bundle: %v", err)
+				return err
+			}
+
+			cmd.Printf("A bundle was created in %q.\n", args[0])
+			cmd.Println("After unpacking run it via 'threagile --" + appDirFlagName + " <bundle> --" + pluginDirFlagName + " <bundle>'.")
+			return nil
+		},
+	})
+
 	return what
 }
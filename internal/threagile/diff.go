@@ -0,0 +1,107 @@
+package threagile
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"github.com/threagile/threagile/pkg/common"
+	"github.com/threagile/threagile/pkg/model"
+	"github.com/threagile/threagile/pkg/report"
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+func (what *Threagile) initDiff() *Threagile {
+	what.rootCmd.AddCommand(&cobra.Command{
+		Use:   common.DiffDiagramCommand,
+		Short: "Create data-flow diagram diff of two model versions",
+		Long:  "Create a data-flow diagram highlighting technical assets and communication links added (green) or removed (red, dashed) since the model given by --" + compareModelFlagName,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(what.flags.compareModelFlag) == 0 {
+				return fmt.Errorf("missing --%v flag with the previous model version to compare against", compareModelFlagName)
+			}
+
+			cfg := what.readConfig(cmd, what.buildTimestamp)
+			progressReporter := common.DefaultProgressReporter{Verbose: cfg.Verbose}
+
+			newResult, err := model.ReadAndAnalyzeModel(cmd.Context(), cfg, progressReporter)
+			if err != nil {
+				return fmt.Errorf("failed to read and analyze model: %v", err)
+			}
+
+			compareConfig := *cfg
+			compareConfig.InputFile = filepath.Clean(what.flags.compareModelFlag)
+			oldResult, err := model.ReadAndAnalyzeModel(cmd.Context(), &compareConfig, progressReporter)
+			if err != nil {
+				return fmt.Errorf("failed to read and analyze model to compare against: %v", err)
+			}
+
+			dotFilename := filepath.Join(cfg.OutputFolder, cfg.DiffDiagramFilenameDOT)
+			err = report.WriteDataFlowDiagramDiffGraphvizDOT(cfg.FS(), oldResult.ParsedModel, newResult.ParsedModel, dotFilename, cfg.DiagramDPI, progressReporter)
+			if err != nil {
+				return fmt.Errorf("failed to write data flow diagram diff: %v", err)
+			}
+
+			progressReporter.Info("Rendering data flow diagram diff")
+			ctx, cancel := common.WithTimeout(cmd.Context(), cfg.RenderTimeoutSeconds)
+			defer cancel()
+			err = report.GenerateGraphvizImage(ctx, cfg.FS(), dotFilename, "png", filepath.Join(cfg.OutputFolder, cfg.DiffDiagramFilenamePNG), cfg.TempFolder, cfg.FontFile)
+			if err != nil {
+				return err
+			}
+			return report.GenerateGraphvizImage(ctx, cfg.FS(), dotFilename, "svg", filepath.Join(cfg.OutputFolder, cfg.DiffDiagramFilenameSVG), cfg.TempFolder, cfg.FontFile)
+		},
+	})
+
+	diff := &cobra.Command{
+		Use:   common.DiffCommand,
+		Short: "Report the risks changed since a previous model version",
+		Long: "Report the risks newly introduced, resolved, re-opened or changed in severity compared to the model given by --" + compareModelFlagName +
+			" or to the risks json of a previous run given by --" + previousRisksFlagName + ", e.g. for reviews of architecture changes in pull requests",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg := what.readConfig(cmd, what.buildTimestamp)
+			if len(what.flags.compareModelFlag) == 0 && len(cfg.PreviousRisksFile) == 0 {
+				return fmt.Errorf("missing --%v flag with the previous model version or --%v flag with the risks json to compare against", compareModelFlagName, previousRisksFlagName)
+			}
+			progressReporter := common.DefaultProgressReporter{Verbose: cfg.Verbose}
+
+			var previousRisks []*types.Risk
+			if len(what.flags.compareModelFlag) > 0 {
+				compareConfig := *cfg
+				compareConfig.InputFile = filepath.Clean(what.flags.compareModelFlag)
+				oldResult, err := model.ReadAndAnalyzeModel(cmd.Context(), &compareConfig, progressReporter)
+				if err != nil {
+					return fmt.Errorf("failed to read and analyze model to compare against: %v", err)
+				}
+				previousRisks = types.AllRisks(oldResult.ParsedModel)
+			} else {
+				var err error
+				previousRisks, err = report.ReadRisksJSON(cfg.FS(), cfg.PreviousRisksFile)
+				if err != nil {
+					return err
+				}
+			}
+
+			newResult, err := model.ReadAndAnalyzeModel(cmd.Context(), cfg, progressReporter)
+			if err != nil {
+				return fmt.Errorf("failed to read and analyze model: %v", err)
+			}
+			delta := newResult.ParsedModel.RiskDeltaSince(previousRisks)
+
+			if !what.flags.jsonFlag {
+				return report.WriteRiskDeltaText(cmd.OutOrStdout(), delta)
+			}
+			data, err := json.MarshalIndent(delta, "", "  ")
+			if err != nil {
+				return fmt.Errorf("failed to marshal risk delta to JSON: %w", err)
+			}
+			_, _ = fmt.Fprintln(cmd.OutOrStdout(), string(data))
+			return nil
+		},
+	}
+	diff.Flags().BoolVar(&what.flags.jsonFlag, jsonFlagName, false, "print the changes as json instead of human-readable")
+	what.rootCmd.AddCommand(diff)
+
+	return what
+}
@@ -0,0 +1,232 @@
+package threagile
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/wcharczuk/go-chart"
+
+	"github.com/threagile/threagile/pkg/common"
+	"github.com/threagile/threagile/pkg/docs"
+	"github.com/threagile/threagile/pkg/input"
+	"github.com/threagile/threagile/pkg/model"
+)
+
+type doctorStatus int
+
+const (
+	doctorOk doctorStatus = iota
+	doctorWarning
+	doctorFailure
+)
+
+func (what doctorStatus) String() string {
+	return [...]string{"  OK", "WARN", "FAIL"}[what]
+}
+
+// doctorCheck is the result of a single environment check, with an actionable fix for warnings and failures
+type doctorCheck struct {
+	name   string
+	status doctorStatus
+	detail string
+	fix    string
+}
+
+func (what *Threagile) initDoctor() *Threagile {
+	what.rootCmd.AddCommand(&cobra.Command{
+		Use:   common.DoctorCommand,
+		Short: "Diagnose the environment (graphviz, plugins, folders, fonts)",
+		Long:  "\n" + docs.Logo + "\n\n" + fmt.Sprintf(docs.VersionText, what.buildTimestamp) + "\n\nverify that everything needed for analyzing models and generating reports is available and print fixes for what is missing",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg := what.readConfig(cmd, what.buildTimestamp)
+
+			checks := make([]doctorCheck, 0)
+			checks = append(checks, checkGraphviz())
+			checks = append(checks, checkVerdanaFont())
+			checks = append(checks, checkChartFont())
+			checks = append(checks, checkFile("PDF report template", filepath.Join(cfg.AppFolder, cfg.TemplateFilename),
+				"set the app folder via --"+appDirFlagName+" or the template via --"+templateFileNameFlagName))
+			checks = append(checks, checkWritableFolder("output folder", cfg.OutputFolder, "--"+outputFlagName))
+			checks = append(checks, checkWritableFolder("temp folder", cfg.TempFolder, "--"+tempDirFlagName))
+			checks = append(checks, checkExecutable("RAA plugin", filepath.Join(cfg.PluginFolder, cfg.RAAPlugin),
+				"build it via 'go build -o "+filepath.Join(cfg.PluginFolder, cfg.RAAPlugin)+" ./cmd/raa' or set --"+pluginDirFlagName+" and --"+raaPluginFlagName))
+			if len(cfg.OwnerDirectoryPlugin) > 0 {
+				checks = append(checks, checkExecutable("owner directory plugin", filepath.Join(cfg.PluginFolder, cfg.OwnerDirectoryPlugin),
+					"set --"+pluginDirFlagName+" and --"+ownerDirectoryPluginFlagName+" to an existing plugin"))
+			}
+			if evaluator, ok := input.GetModelEvaluator(cfg.InputFile); ok {
+				checks = append(checks, checkModelEvaluator(evaluator))
+			}
+			for _, plugin := range cfg.RiskRulesPlugins {
+				if len(plugin) > 0 {
+					checks = append(checks, checkCustomRiskRulePlugin(cmd.Context(), plugin, cfg.PluginTimeoutSeconds))
+				}
+			}
+
+			failures := 0
+			for _, check := range checks {
+				cmd.Printf("[%v] %v: %v\n", check.status, check.name, check.detail)
+				if check.status != doctorOk && len(check.fix) > 0 {
+					cmd.Printf("       fix: %v\n", check.fix)
+				}
+				if check.status == doctorFailure {
+					failures++
+				}
+			}
+
+			if failures > 0 {
+				return fmt.Errorf("%d of %d checks failed", failures, len(checks))
+			}
+			cmd.Println()
+			cmd.Println("No problems found.")
+			return nil
+		},
+	})
+
+	return what
+}
+
+func checkGraphviz() doctorCheck {
+	check := doctorCheck{name: "graphviz"}
+	path, err := exec.LookPath("dot")
+	if err != nil {
+		check.status = doctorFailure
+		check.detail = "'dot' not found in PATH, diagrams and the PDF report cannot be rendered"
+		check.fix = "install graphviz (e.g. 'apt-get install graphviz' or 'brew install graphviz')"
+		return check
+	}
+
+	output, err := exec.Command(path, "-V").CombinedOutput() // #nosec G204
+	if err != nil {
+		check.status = doctorFailure
+		check.detail = fmt.Sprintf("%v is not runnable: %v", path, err)
+		check.fix = "reinstall graphviz"
+		return check
+	}
+
+	check.detail = strings.TrimSpace(string(output))
+	return check
+}
+
+// checkModelEvaluator verifies that the tool evaluating CUE or Jsonnet models is available
+func checkModelEvaluator(evaluator input.ModelEvaluator) doctorCheck {
+	check := doctorCheck{name: evaluator.Name + " model evaluation"}
+	path, err := exec.LookPath(evaluator.Tool)
+	if err != nil {
+		check.status = doctorFailure
+		check.detail = fmt.Sprintf("'%v' not found in PATH, the model cannot be evaluated", evaluator.Tool)
+		check.fix = "install " + evaluator.Tool + " (see " + evaluator.Install + ")"
+		return check
+	}
+
+	check.detail = path
+	return check
+}
+
+func checkVerdanaFont() doctorCheck {
+	check := doctorCheck{name: "diagram font"}
+	path, err := exec.LookPath("fc-list")
+	if err != nil {
+		check.status = doctorWarning
+		check.detail = "unable to verify that the Verdana font used in diagrams is installed ('fc-list' not found)"
+		check.fix = "install fontconfig to allow this check"
+		return check
+	}
+
+	output, err := exec.Command(path, ":family=Verdana").Output() // #nosec G204
+	if err != nil || len(strings.TrimSpace(string(output))) == 0 {
+		check.status = doctorWarning
+		check.detail = "Verdana font not installed, graphviz falls back to another font and diagram labels may be cut off"
+		check.fix = "install the Verdana font (e.g. 'apt-get install ttf-mscorefonts-installer')"
+		return check
+	}
+
+	check.detail = "Verdana installed"
+	return check
+}
+
+func checkChartFont() doctorCheck {
+	check := doctorCheck{name: "chart font"}
+	_, err := chart.GetDefaultFont()
+	if err != nil {
+		check.status = doctorFailure
+		check.detail = fmt.Sprintf("unable to load the embedded font for the report charts: %v", err)
+		check.fix = "rebuild threagile from an unmodified source tree"
+		return check
+	}
+
+	check.detail = "embedded font loaded"
+	return check
+}
+
+func checkFile(name string, filename string, fix string) doctorCheck {
+	check := doctorCheck{name: name}
+	info, err := os.Stat(filename)
+	if err != nil || !info.Mode().IsRegular() {
+		check.status = doctorFailure
+		check.detail = fmt.Sprintf("%v not found", filename)
+		check.fix = fix
+		return check
+	}
+
+	check.detail = filename
+	return check
+}
+
+func checkWritableFolder(name string, folder string, flag string) doctorCheck {
+	check := doctorCheck{name: name}
+	err := os.MkdirAll(folder, 0700)
+	if err == nil {
+		var file *os.File
+		file, err = os.CreateTemp(folder, ".threagile-doctor-*")
+		if err == nil {
+			_ = file.Close()
+			err = os.Remove(file.Name())
+		}
+	}
+	if err != nil {
+		check.status = doctorFailure
+		check.detail = fmt.Sprintf("%v is not writable: %v", folder, err)
+		check.fix = "fix the permissions of " + folder + " or choose another folder via " + flag
+		return check
+	}
+
+	check.detail = folder + " is writable"
+	return check
+}
+
+func checkExecutable(name string, filename string, fix string) doctorCheck {
+	check := checkFile(name, filename, fix)
+	if check.status != doctorOk {
+		return check
+	}
+
+	info, _ := os.Stat(filename)
+	if info.Mode().Perm()&0111 == 0 {
+		check.status = doctorFailure
+		check.detail = fmt.Sprintf("%v is not executable", filename)
+		check.fix = "chmod +x " + filename
+	}
+	return check
+}
+
+// checkCustomRiskRulePlugin runs the plugin with the info request, which fails for plugins built for an incompatible
+// version of the plugin protocol
+func checkCustomRiskRulePlugin(ctx context.Context, plugin string, timeoutSeconds int) doctorCheck {
+	check := doctorCheck{name: "custom risk rule plugin " + plugin}
+	id, err := model.CheckCustomRiskRulePlugin(ctx, plugin, timeoutSeconds)
+	if err != nil {
+		check.status = doctorFailure
+		check.detail = fmt.Sprintf("not usable: %v", err)
+		check.fix = "rebuild the plugin against this version of threagile or remove it from --" + customRiskRulesPluginFlagName
+		return check
+	}
+
+	check.detail = "provides risk rule " + id
+	return check
+}
@@ -0,0 +1,63 @@
+package threagile
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/threagile/threagile/pkg/input"
+)
+
+func TestCheckWritableFolder(t *testing.T) {
+	folder := filepath.Join(t.TempDir(), "output", "nested")
+	check := checkWritableFolder("output folder", folder, "--"+outputFlagName)
+	assert.Equal(t, doctorOk, check.status)
+	assert.DirExists(t, folder, "created if missing")
+	entries, err := os.ReadDir(folder)
+	assert.NoError(t, err)
+	assert.Empty(t, entries, "probe file removed")
+
+	file := filepath.Join(t.TempDir(), "file")
+	assert.NoError(t, os.WriteFile(file, nil, 0600))
+	check = checkWritableFolder("output folder", filepath.Join(file, "output"), "--"+outputFlagName)
+	assert.Equal(t, doctorFailure, check.status)
+	assert.Contains(t, check.fix, "--"+outputFlagName)
+}
+
+func TestCheckExecutable(t *testing.T) {
+	folder := t.TempDir()
+	plugin := filepath.Join(folder, "raa")
+
+	check := checkExecutable("RAA plugin", plugin, "build it")
+	assert.Equal(t, doctorFailure, check.status)
+	assert.Equal(t, plugin+" not found", check.detail)
+	assert.Equal(t, "build it", check.fix)
+
+	assert.NoError(t, os.WriteFile(plugin, nil, 0600))
+	check = checkExecutable("RAA plugin", plugin, "build it")
+	assert.Equal(t, doctorFailure, check.status)
+	assert.Equal(t, "chmod +x "+plugin, check.fix)
+
+	assert.NoError(t, os.Chmod(plugin, 0700))
+	check = checkExecutable("RAA plugin", plugin, "build it")
+	assert.Equal(t, doctorOk, check.status)
+	assert.Equal(t, plugin, check.detail)
+}
+
+func TestCheckModelEvaluator(t *testing.T) {
+	check := checkModelEvaluator(input.ModelEvaluator{Name: "Test", Tool: "threagile-missing-evaluator", Install: "https://example.com/install"})
+	assert.Equal(t, doctorFailure, check.status)
+	assert.Equal(t, "Test model evaluation", check.name)
+	assert.Equal(t, "install threagile-missing-evaluator (see https://example.com/install)", check.fix)
+}
+
+func TestCheckCustomRiskRulePlugin(t *testing.T) {
+	for _, plugin := range []string{filepath.Join(t.TempDir(), "missing-rule"), t.TempDir()} {
+		check := checkCustomRiskRulePlugin(context.Background(), plugin, 5)
+		assert.Equal(t, doctorFailure, check.status, plugin)
+		assert.Contains(t, check.fix, "--"+customRiskRulesPluginFlagName, plugin)
+	}
+}
@@ -16,20 +16,20 @@ import (
 
 func (what *Threagile) initExecute() *Threagile {
 	what.rootCmd.AddCommand(&cobra.Command{
-		Use:   "execute-model-macro",
+		Use:   common.ExecuteModelMacroCommand,
 		Short: "Execute model macro",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			cfg := what.readConfig(cmd, what.buildTimestamp)
 			progressReporter := common.DefaultProgressReporter{Verbose: cfg.Verbose}
 
-			r, err := model.ReadAndAnalyzeModel(cfg, progressReporter)
+			r, err := model.ReadAndAnalyzeModel(cmd.Context(), cfg, progressReporter)
 			if err != nil {
 				return fmt.Errorf("unable to read and analyze model: %v", err)
 			}
 
 			macrosId := args[0]
-			err = macros.ExecuteModelMacro(r.ModelInput, cfg.InputFile, r.ParsedModel, macrosId)
+			err = macros.ExecuteModelMacro(r.ModelInput, cfg.InputFile, r.ParsedModel, macrosId, cfg.ExecuteModelMacroAnswers)
 			if err != nil {
 				return fmt.Errorf("unable to execute model macro: %v", err)
 			}
@@ -7,8 +7,10 @@ import (
 	"github.com/threagile/threagile/pkg/docs"
 	"github.com/threagile/threagile/pkg/macros"
 	"github.com/threagile/threagile/pkg/model"
+	"github.com/threagile/threagile/pkg/report"
 	"github.com/threagile/threagile/pkg/security/risks"
 	"github.com/threagile/threagile/pkg/security/types"
+	"sort"
 	"strings"
 )
 
@@ -24,6 +26,14 @@ func (what *Threagile) initExplainNew() *Threagile {
 
 	what.rootCmd.AddCommand(explainCmd)
 
+	what.rootCmd.AddCommand(&cobra.Command{
+		Use:        common.ExplainRuleCommand,
+		Short:      "Print documentation of a risk rule (detection logic, mitigation, ASVS/CWE references)",
+		Args:       cobra.ExactArgs(1),
+		ArgAliases: []string{"rule_id"},
+		RunE:       what.explainRule,
+	})
+
 	explainCmd.AddCommand(
 		&cobra.Command{
 			Use:        common.RiskItem,
@@ -37,6 +47,11 @@ func (what *Threagile) initExplainNew() *Threagile {
 			Short: "Detailed explanation of all the risk rules",
 			RunE:  what.explainRules,
 		},
+		&cobra.Command{
+			Use:   common.PatternsItem,
+			Short: "Dry-run of the skip-risk-rules and risk tracking patterns, printing what each pattern matches",
+			RunE:  what.explainPatterns,
+		},
 		&cobra.Command{
 			Use:   common.MacrosItem,
 			Short: "Explain model macros",
@@ -57,7 +72,7 @@ func (what *Threagile) explainRisk(cmd *cobra.Command, _ []string) error {
 
 	// todo: reuse model if already loaded
 
-	result, runError := model.ReadAndAnalyzeModel(cfg, progressReporter)
+	result, runError := model.ReadAndAnalyzeModel(cmd.Context(), cfg, progressReporter)
 	if runError != nil {
 		cmd.Printf("Failed to read and analyze model: %v", runError)
 		return runError
@@ -76,7 +91,7 @@ func (what *Threagile) explainRules(cmd *cobra.Command, _ []string) error {
 	cmd.Println("----------------------")
 	cmd.Println("Custom risk rules:")
 	cmd.Println("----------------------")
-	customRiskRules := model.LoadCustomRiskRules(strings.Split(what.flags.customRiskRulesPluginFlag, ","), common.DefaultProgressReporter{Verbose: what.flags.verboseFlag})
+	customRiskRules := model.LoadCustomRiskRules(cmd.Context(), strings.Split(what.flags.customRiskRulesPluginFlag, ","), what.flags.pluginTimeoutFlag, common.DefaultProgressReporter{Verbose: what.flags.verboseFlag})
 	for _, rule := range customRiskRules {
 		cmd.Printf("%v: %v\n", rule.Category().ID, rule.Category().Description)
 	}
@@ -93,6 +108,68 @@ func (what *Threagile) explainRules(cmd *cobra.Command, _ []string) error {
 	return nil
 }
 
+func (what *Threagile) explainRule(cmd *cobra.Command, args []string) error {
+	cfg := what.readConfig(cmd, what.buildTimestamp)
+	progressReporter := common.DefaultProgressReporter{Verbose: cfg.Verbose}
+
+	rules := risks.GetBuiltInRiskRules()
+	rules.Merge(model.LoadCustomRiskRules(cmd.Context(), cfg.RiskRulesPlugins, cfg.PluginTimeoutSeconds, progressReporter))
+	rules.Merge(model.LoadRegoRiskRules(cmd.Context(), cfg.RiskRulesRegoFolder, cfg.PluginTimeoutSeconds, progressReporter))
+	rules.Merge(model.LoadScriptRiskRules(cmd.Context(), cfg.RiskRulesScripts, cfg.PluginTimeoutSeconds, progressReporter))
+	overridesError := model.ApplyRiskCategoryOverrides(cfg.RiskCategoryOverridesFile, progressReporter, rules)
+	if overridesError != nil {
+		return overridesError
+	}
+
+	rule, ok := rules[args[0]]
+	if !ok {
+		return fmt.Errorf("unknown risk rule %q (see %v for available rules)", args[0], common.ListRiskRulesCommand)
+	}
+
+	cmd.Println(report.RuleDocMarkdown(rule))
+	return nil
+}
+
+func (what *Threagile) explainPatterns(cmd *cobra.Command, _ []string) error {
+	cfg := what.readConfig(cmd, what.buildTimestamp)
+	cfg.IgnoreOrphanedRiskTracking = true // unmatched patterns are part of the output
+	progressReporter := common.DefaultProgressReporter{Verbose: cfg.Verbose}
+
+	result, runError := model.ReadAndAnalyzeModel(cmd.Context(), cfg, progressReporter)
+	if runError != nil {
+		return fmt.Errorf("failed to read and analyze model: %v", runError)
+	}
+
+	cmd.Println("Skip risk rules patterns:")
+	for _, pattern := range cfg.SkipRiskRules {
+		if len(strings.TrimSpace(pattern)) == 0 {
+			continue
+		}
+		matches := types.RiskRulesMatching(pattern, result.BuiltinRiskRules, result.CustomRiskRules)
+		cmd.Printf("\t%v: %d match(es)\n", pattern, len(matches))
+		for _, id := range matches {
+			cmd.Printf("\t\t%v\n", id)
+		}
+	}
+	cmd.Println()
+
+	cmd.Println("Risk tracking patterns:")
+	patterns := make([]string, 0)
+	for pattern := range result.ParsedModel.GetDeferredRiskTrackingDueToWildcardMatching() {
+		patterns = append(patterns, pattern)
+	}
+	sort.Strings(patterns)
+	for _, pattern := range patterns {
+		matches := result.ParsedModel.RiskIdsMatching(pattern)
+		cmd.Printf("\t%v: %d match(es)\n", pattern, len(matches))
+		for _, id := range matches {
+			cmd.Printf("\t\t%v\n", id)
+		}
+	}
+
+	return nil
+}
+
 func (what *Threagile) explainMacros(cmd *cobra.Command, args []string) {
 	cmd.Println(docs.Logo + "\n\n" + fmt.Sprintf(docs.VersionText, what.buildTimestamp))
 	cmd.Println("Explanation for the model macros:")
@@ -118,7 +195,7 @@ func (what *Threagile) explainMacros(cmd *cobra.Command, args []string) {
 
 func (what *Threagile) explainTypes(cmd *cobra.Command, args []string) {
 	cmd.Println(docs.Logo + "\n\n" + fmt.Sprintf(docs.VersionText, what.buildTimestamp))
-	fmt.Println("Explanation for the types:")
+	fmt.Fprintln(common.LogStdout(), "Explanation for the types:")
 	cmd.Println()
 	cmd.Println("The following types are available (can be extended for custom rules):")
 	cmd.Println()
@@ -0,0 +1,55 @@
+package threagile
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"github.com/threagile/threagile/pkg/common"
+	"github.com/threagile/threagile/pkg/input"
+	"github.com/threagile/threagile/pkg/interop/otm"
+)
+
+func (what *Threagile) initExport() *Threagile {
+	what.rootCmd.AddCommand(&cobra.Command{
+		Use:   common.ExportOTMCommand,
+		Short: "Export the model as Open Threat Model (OTM) file",
+		Long: "Convert the model into an Open Threat Model (OTM) file in the output folder, e.g. for IriusRisk or OWASP Threat Dragon: " +
+			"trust boundaries become trust zones, technical assets components, communication links dataflows, data assets assets and " +
+			"custom risk categories threats, the properties without counterpart in OTM are kept in the attribute threagile of each element " +
+			"(for " + common.ImportOTMCommand + ")",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg := what.readConfig(cmd, what.buildTimestamp)
+
+			read, err := input.TemplatingReader(cfg.ModelTemplating)
+			if err != nil {
+				return err
+			}
+			modelInput := new(input.Model).Defaults()
+			err = modelInput.LoadWith(cfg.InputFile, read)
+			if err != nil {
+				return err
+			}
+			document, err := otm.Export(modelInput)
+			if err != nil {
+				return err
+			}
+			data, err := json.MarshalIndent(document, "", "  ")
+			if err != nil {
+				return err
+			}
+			filename := filepath.Join(cfg.OutputFolder, cfg.OtmFilename)
+			err = os.WriteFile(filename, data, 0600)
+			if err != nil {
+				return err
+			}
+			cmd.Printf("Exported %d components, %d dataflows, %d trust zones and %d threats into %q\n",
+				len(document.Components), len(document.Dataflows), len(document.TrustZones), len(document.Threats), filename)
+			return nil
+		},
+	})
+
+	return what
+}
@@ -13,58 +13,193 @@ const (
 	verboseFlagName      = "verbose"
 	verboseFlagShorthand = "v"
 
+	logRedactionPatternFlagName = "log-redaction-pattern"
+
 	appDirFlagName    = "app-dir"
 	pluginDirFlagName = "plugin-dir"
 	outputFlagName    = "output"
 	tempDirFlagName   = "temp-dir"
 
-	serverDirFlagName  = "server-dir"
-	serverPortFlagName = "server-port"
+	serverDirFlagName       = "server-dir"
+	serverPortFlagName      = "server-port"
+	tempTTLFlagName         = "temp-ttl"
+	serverStorageFlagName   = "server-storage"
+	maxDpiFlagName          = "max-dpi"
+	maxAnalysesFlagName     = "max-analyses-per-hour"
+	maxModelsFlagName       = "max-models-per-key"
+	validateOnWriteFlagName = "validate-model-on-write"
+	templatesDirFlagName    = "templates-dir"
+	templatesIndexFlagName  = "templates-index-url"
+	webhookURLsFlagName     = "webhook-urls"
+	webhookSecretFlagName   = "webhook-secret"
+	webhookCriticalFlagName = "webhook-new-critical-only"
+
+	inputFileFlagName    = "model"
+	compareModelFlagName = "compare-model"
+	raaPluginFlagName    = "raa-run"
+
+	outputRootFlagName        = "output-root"
+	workersFlagName           = "workers"
+	dryRunFlagName            = "dry-run"
+	jsonFlagName              = "json"
+	consumerFlagName          = "consumer"
+	diagnosticsFormatFlagName = "diagnostics-format"
 
-	inputFileFlagName = "model"
-	raaPluginFlagName = "raa-run"
+	ownerDirectoryPluginFlagName = "owner-directory-run"
+	ownerDirectoryStrictFlagName = "owner-directory-strict"
+	strictRulesFlagName          = "strict-rules"
+	threatIntelFeedFlagName      = "threat-intel-feed"
+	previousRisksFlagName        = "previous-risks"
+	failOnRiskFlagName           = "fail-on-risk"
+	policyGateRegoFlagName       = "policy-gate-rego"
+	serviceMetadataURLsFlagName  = "service-metadata-urls"
+	structurizrWorkspaceFlagName = "structurizr-workspace"
+	structurizrMappingFlagName   = "structurizr-mapping"
+	structurizrAPIKeyFlagName    = "structurizr-api-key"
+	scannerFindingsFlagName      = "scanner-findings"
+	riskCommentsFlagName         = "risk-comments"
+	sbomFetchURLsFlagName        = "sbom-fetch-urls"
+	macroAnswersFlagName         = "execute-model-macro-answers"
+	preParseHooksFlagName        = "pre-parse-hooks"
+	postAnalysisHooksFlagName    = "post-analysis-hooks"
+	modelTemplatingFlagName      = "model-templating"
+
+	pluginTimeoutFlagName   = "plugin-timeout"
+	analysisTimeoutFlagName = "analysis-timeout"
+	renderTimeoutFlagName   = "render-timeout"
 
 	customRiskRulesPluginFlagName      = "custom-risk-rules-plugin"
+	customRiskRulesRegoFlagName        = "custom-risk-rules-rego"
+	customRiskRulesScriptsFlagName     = "custom-risk-rules-scripts"
+	reportSectionPluginsFlagName       = "report-section-plugins"
 	diagramDpiFlagName                 = "diagram-dpi"
+	diagramMaxPixelsFlagName           = "diagram-max-pixels"
+	diagramThemeFlagName               = "diagram-theme"
+	diagramVariantsFlagName            = "diagram-variants"
+	fontFileFlagName                   = "font"
 	skipRiskRulesFlagName              = "skip-risk-rules"
+	severityRecalibrationFlagName      = "severity-recalibration"
+	riskMergeGroupFlagName             = "risk-merge-group"
+	riskMergeKeysFlagName              = "risk-merge-keys"
 	ignoreOrphanedRiskTrackingFlagName = "ignore-orphaned-risk-tracking"
+	autoSeedTagsAvailableFlagName      = "auto-seed-tags"
 	templateFileNameFlagName           = "background"
+	riskCategoryOverridesFlagName      = "risk-category-overrides"
+	tagTaxonomyFlagName                = "tag-taxonomy"
 
 	generateDataFlowDiagramFlagName     = "generate-data-flow-diagram"
 	generateDataAssetDiagramFlagName    = "generate-data-asset-diagram"
 	generateRisksJSONFlagName           = "generate-risks-json"
+	generateRisksSARIFFlagName          = "generate-risks-sarif"
+	generateDueDatesICSFlagName         = "generate-due-dates-ics"
 	generateTechnicalAssetsJSONFlagName = "generate-technical-assets-json"
 	generateStatsJSONFlagName           = "generate-stats-json"
 	generateRisksExcelFlagName          = "generate-risks-excel"
 	generateTagsExcelFlagName           = "generate-tags-excel"
 	generateReportPDFFlagName           = "generate-report-pdf"
+	generateRulesDocFlagName            = "generate-rules-doc"
+	generateRisksPerOwnerFlagName       = "generate-risks-per-owner"
+	generateRiskMatrixFlagName          = "generate-risk-matrix"
+	generateExcelWorkbookFlagName       = "generate-excel-workbook"
+	generateAssetSheetsFlagName         = "generate-asset-sheets"
+	generateMitigationChecklistFlagName = "generate-mitigation-checklist"
 )
 
+// structurizrAPISecretEnvName is the environment variable of the Structurizr API secret, overriding the config file
+const structurizrAPISecretEnvName = "THREAGILE_STRUCTURIZR_API_SECRET"
+
+// serverAdminKeyEnvName is the environment variable of the key of the admin endpoints, overriding the config file
+const serverAdminKeyEnvName = "THREAGILE_SERVER_ADMIN_KEY"
+
 type Flags struct {
-	configFlag      string
-	verboseFlag     bool
-	interactiveFlag bool
-	appDirFlag      string
-	pluginDirFlag   string
-	outputDirFlag   string
-	tempDirFlag     string
-	inputFileFlag   string
-	raaPluginFlag   string
-	serverPortFlag  int
-	serverDirFlag   string
+	configFlag          string
+	verboseFlag         bool
+	logRedactionFlag    []string
+	interactiveFlag     bool
+	appDirFlag          string
+	pluginDirFlag       string
+	outputDirFlag       string
+	tempDirFlag         string
+	inputFileFlag       string
+	raaPluginFlag       string
+	serverPortFlag      int
+	serverDirFlag       string
+	tempTTLFlag         int
+	serverStorageFlag   string
+	maxDpiFlag          int
+	maxAnalysesFlag     int
+	maxModelsFlag       int
+	validateOnWriteFlag bool
+	templatesDirFlag    string
+	templatesIndexFlag  string
+	webhookURLsFlag     string
+	webhookSecretFlag   string
+	webhookCriticalFlag bool
+
+	compareModelFlag      string
+	outputRootFlag        string
+	workersFlag           int
+	dryRunFlag            bool
+	jsonFlag              bool
+	consumerFlag          []string
+	diagnosticsFormatFlag string
+
+	ownerDirectoryPluginFlag string
+	ownerDirectoryStrictFlag bool
+	strictRulesFlag          bool
+	threatIntelFeedFlag      string
+	previousRisksFlag        string
+	failOnRiskFlag           string
+	policyGateRegoFlag       string
+	serviceMetadataURLsFlag  string
+	structurizrWorkspaceFlag string
+	structurizrMappingFlag   string
+	structurizrAPIKeyFlag    string
+	scannerFindingsFlag      string
+	riskCommentsFlag         string
+	sbomFetchURLsFlag        bool
+	macroAnswersFlag         string
+	preParseHooksFlag        string
+	postAnalysisHooksFlag    string
+	modelTemplatingFlag      string
+
+	pluginTimeoutFlag   int
+	analysisTimeoutFlag int
+	renderTimeoutFlag   int
 
 	skipRiskRulesFlag              string
+	severityRecalibrationFlag      map[string]int
+	riskMergeGroupFlag             []string
+	riskMergeKeysFlag              string
 	customRiskRulesPluginFlag      string
+	customRiskRulesRegoFlag        string
+	customRiskRulesScriptsFlag     string
+	reportSectionPluginsFlag       string
 	ignoreOrphanedRiskTrackingFlag bool
+	autoSeedTagsAvailableFlag      bool
 	templateFileNameFlag           string
+	riskCategoryOverridesFlag      string
+	tagTaxonomyFlag                string
 	diagramDpiFlag                 int
+	diagramMaxPixelsFlag           int
+	diagramThemeFlag               string
+	diagramVariantsFlag            string
+	fontFileFlag                   string
 
 	generateDataFlowDiagramFlag     bool
 	generateDataAssetDiagramFlag    bool
 	generateRisksJSONFlag           bool
+	generateRisksSARIFFlag          bool
+	generateDueDatesICSFlag         bool
 	generateTechnicalAssetsJSONFlag bool
 	generateStatsJSONFlag           bool
 	generateRisksExcelFlag          bool
 	generateTagsExcelFlag           bool
 	generateReportPDFFlag           bool
+	generateRulesDocFlag            bool
+	generateRisksPerOwnerFlag       bool
+	generateRiskMatrixFlag          bool
+	generateExcelWorkbookFlag       bool
+	generateAssetSheetsFlag         bool
+	generateMitigationChecklistFlag bool
 }
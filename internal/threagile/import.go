@@ -0,0 +1,109 @@
+package threagile
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+
+	"github.com/threagile/threagile/pkg/common"
+	"github.com/threagile/threagile/pkg/importer"
+	"github.com/threagile/threagile/pkg/input"
+	"github.com/threagile/threagile/pkg/interop/otm"
+)
+
+func (what *Threagile) initImport() *Threagile {
+	what.rootCmd.AddCommand(&cobra.Command{
+		Use:   common.ImportDrawIOCommand + " <file.drawio|file.xml>",
+		Short: "Create a model stub from a draw.io diagram",
+		Long: "Convert a diagrams.net (draw.io) architecture diagram into a model stub written to the model file: labeled containers become trust boundaries, " +
+			"cylinders datastores, actors external entities, other labeled shapes processes and arrows communication links, " +
+			"refined by the shape properties type, technology, tags, internet, protocol and trust-boundary-type",
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return what.runImport(cmd, args[0], importer.ImportDrawIO)
+		},
+	})
+
+	importOpenAPI := &cobra.Command{
+		Use:   common.ImportOpenAPICommand + " <openapi.yaml|openapi.json>",
+		Short: "Create a model stub from an OpenAPI spec",
+		Long: "Convert an OpenAPI 3 or Swagger 2 spec into a model stub written to the model file: the API becomes a process, its schemas data assets " +
+			"and each consumer (see --consumer, or the extension x-consumers of the spec) an external entity with a communication link to the API, " +
+			"rated by the servers and security schemes of the spec",
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return what.runImport(cmd, args[0], func(reader io.Reader, filename string) (*input.Model, []string, error) {
+				return importer.ImportOpenAPI(reader, filename, what.flags.consumerFlag)
+			})
+		},
+	}
+	importOpenAPI.Flags().StringSliceVar(&what.flags.consumerFlag, consumerFlagName, nil, "consumer of the API, repeatable (default: the x-consumers of the spec or API Consumer)")
+	what.rootCmd.AddCommand(importOpenAPI)
+
+	what.rootCmd.AddCommand(&cobra.Command{
+		Use:   common.ImportTerraformCommand + " <terraform.tfstate|show.json>",
+		Short: "Create a model stub from a Terraform state",
+		Long: "Convert a Terraform state (or the output of terraform show -json of a state or a plan, for HCL configurations) into a model stub written to the model file: " +
+			"networks and subnets become trust boundaries, VMs, databases, buckets, queues, load balancers and functions technical assets " +
+			"and ingress rules between security groups communication links",
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return what.runImport(cmd, args[0], importer.ImportTerraform)
+		},
+	})
+
+	what.rootCmd.AddCommand(&cobra.Command{
+		Use:   common.ImportOTMCommand + " <file.otm.json>",
+		Short: "Create a model from an Open Threat Model (OTM) file",
+		Long: "Convert an Open Threat Model (OTM) file, e.g. of IriusRisk or OWASP Threat Dragon, into a model written to the model file: " +
+			"trust zones become trust boundaries (the untrusted ones the internet), components technical assets, dataflows communication links, " +
+			"assets data assets and threats custom risk categories, models exported by " + common.ExportOTMCommand + " get back all of their properties",
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return what.runImport(cmd, args[0], otm.Import)
+		},
+	})
+
+	return what
+}
+
+// runImport converts the file with the importer and writes the model stub to the model file, printing the notes
+func (what *Threagile) runImport(cmd *cobra.Command, filename string, importFile func(reader io.Reader, filename string) (*input.Model, []string, error)) error {
+	cfg := what.readConfig(cmd, what.buildTimestamp)
+
+	file, err := os.Open(filepath.Clean(filename))
+	if err != nil {
+		return err
+	}
+	defer func() { _ = file.Close() }()
+	modelInput, notes, err := importFile(file, filename)
+	if err != nil {
+		return err
+	}
+	for _, note := range notes {
+		cmd.Println("Note: " + note)
+	}
+	return writeImportedModel(cmd, cfg.InputFile, modelInput)
+}
+
+// writeImportedModel writes the model stub of an importer to the model file, which must not exist yet
+func writeImportedModel(cmd *cobra.Command, modelFile string, modelInput *input.Model) error {
+	if _, err := os.Stat(modelFile); err == nil {
+		return fmt.Errorf("model file %q already exists", modelFile)
+	}
+	yamlBytes, err := yaml.Marshal(modelInput)
+	if err != nil {
+		return err
+	}
+	err = os.WriteFile(filepath.Clean(modelFile), yamlBytes, 0600)
+	if err != nil {
+		return err
+	}
+	cmd.Printf("Imported %d technical assets, %d data assets and %d trust boundaries into %q, please refine their ratings by editing the model file\n",
+		len(modelInput.TechnicalAssets), len(modelInput.DataAssets), len(modelInput.TrustBoundaries), modelFile)
+	return nil
+}
@@ -23,7 +23,7 @@ func (what *Threagile) initList() *Threagile {
 			cmd.Println("----------------------")
 			cmd.Println("Custom risk rules:")
 			cmd.Println("----------------------")
-			customRiskRules := model.LoadCustomRiskRules(strings.Split(what.flags.customRiskRulesPluginFlag, ","), common.DefaultProgressReporter{Verbose: what.flags.verboseFlag})
+			customRiskRules := model.LoadCustomRiskRules(cmd.Context(), strings.Split(what.flags.customRiskRulesPluginFlag, ","), what.flags.pluginTimeoutFlag, common.DefaultProgressReporter{Verbose: what.flags.verboseFlag})
 			for id, customRule := range customRiskRules {
 				cmd.Println(id, "-->", customRule.Category().Title, "--> with tags:", customRule.SupportedTags())
 			}
@@ -0,0 +1,34 @@
+package threagile
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/threagile/threagile/pkg/common"
+	"github.com/threagile/threagile/pkg/lsp"
+	"github.com/threagile/threagile/pkg/model"
+	"github.com/threagile/threagile/pkg/security/risks"
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+func (what *Threagile) initLanguageServer() *Threagile {
+	what.rootCmd.AddCommand(&cobra.Command{
+		Use:   common.LanguageServerCommand,
+		Short: "Run a language server for model files on stdin and stdout",
+		Long: "Run a Language Server Protocol server for yaml model files on stdin and stdout for editors: diagnostics of the problems found by " +
+			common.ValidateModelCommand + ", completion of enum values, ids of referenced elements and keys, hover docs of the fields and " +
+			"go-to-definition of referenced ids",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg := what.readConfig(cmd, what.buildTimestamp)
+
+			server := lsp.NewServer(cfg, risks.GetBuiltInRiskRules(), func(reporter types.ProgressReporter) types.RiskRules {
+				customRiskRules := model.LoadCustomRiskRules(cmd.Context(), cfg.RiskRulesPlugins, cfg.PluginTimeoutSeconds, reporter)
+				customRiskRules.Merge(model.LoadRegoRiskRules(cmd.Context(), cfg.RiskRulesRegoFolder, cfg.PluginTimeoutSeconds, reporter))
+				customRiskRules.Merge(model.LoadScriptRiskRules(cmd.Context(), cfg.RiskRulesScripts, cfg.PluginTimeoutSeconds, reporter))
+				return customRiskRules
+			})
+			return server.Serve(cmd.Context(), cmd.InOrStdin(), cmd.OutOrStdout())
+		},
+	})
+
+	return what
+}
@@ -0,0 +1,156 @@
+package threagile
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/threagile/threagile/pkg/common"
+	"github.com/threagile/threagile/pkg/input"
+)
+
+func (what *Threagile) initScanAnnotations() *Threagile {
+	scan := &cobra.Command{
+		Use:   common.ScanAnnotationsCommand + " <source folders>",
+		Short: "Update technical assets from annotations in source code",
+		Long: "Scan the source folders for annotations like '// threagile:asset id=payment-service technology=web-service tags=pci' " +
+			"and add the annotated technical assets to the model file or update the annotated attributes of the existing ones (found by id)",
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg := what.readConfig(cmd, what.buildTimestamp)
+
+			annotations, err := input.ScanAnnotations(args)
+			if err != nil {
+				return err
+			}
+			cmd.Printf("Found %d annotations\n", len(annotations))
+
+			return what.updateModel(cmd, cfg, func(modelInput *input.Model, changes *[]string) error {
+				return modelInput.ApplyAnnotations(annotations, what.flags.dryRunFlag, changes)
+			})
+		},
+	}
+
+	scan.Flags().BoolVar(&what.flags.dryRunFlag, dryRunFlagName, false, "only print the changes instead of updating the model file")
+	what.rootCmd.AddCommand(scan)
+
+	return what
+}
+
+func (what *Threagile) initImportServiceMetadata() *Threagile {
+	importMetadata := &cobra.Command{
+		Use:   common.ImportServiceMetadataCommand + " [metadata urls]",
+		Short: "Update communication links from metadata endpoints of running services",
+		Long: "Query the metadata endpoints (given as arguments or via --" + serviceMetadataURLsFlagName + ") of running services, each returning " +
+			"a json fragment like {\"id\": \"payment-service\", \"dependencies\": [{\"target\": \"payment-db\", \"protocol\": \"jdbc-encrypted\"}]}, " +
+			"and merge the declared dependencies into the communication links of the technical assets in the model file",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg := what.readConfig(cmd, what.buildTimestamp)
+
+			urls := args
+			if len(urls) == 0 {
+				urls = cfg.ServiceMetadataURLs
+			}
+			services := make([]*input.ServiceMetadata, 0)
+			for _, url := range urls {
+				url = strings.TrimSpace(url)
+				if len(url) == 0 {
+					continue
+				}
+				service, err := input.FetchServiceMetadata(cmd.Context(), url)
+				if err != nil {
+					return err
+				}
+				services = append(services, service)
+			}
+			if len(services) == 0 {
+				return fmt.Errorf("no service metadata urls given (as arguments or via --%v)", serviceMetadataURLsFlagName)
+			}
+			cmd.Printf("Fetched metadata of %d services\n", len(services))
+
+			return what.updateModel(cmd, cfg, func(modelInput *input.Model, changes *[]string) error {
+				return modelInput.ApplyServiceMetadata(services, what.flags.dryRunFlag, changes)
+			})
+		},
+	}
+
+	importMetadata.Flags().BoolVar(&what.flags.dryRunFlag, dryRunFlagName, false, "only print the changes instead of updating the model file")
+	what.rootCmd.AddCommand(importMetadata)
+
+	return what
+}
+
+func (what *Threagile) initSyncStructurizr() *Threagile {
+	syncStructurizr := &cobra.Command{
+		Use:   common.SyncStructurizrCommand,
+		Short: "Update technical assets and communication links from a Structurizr workspace",
+		Long: "Fetch the Structurizr workspace (via --" + structurizrWorkspaceFlagName + ", signed with --" + structurizrAPIKeyFlagName + " and the API secret of the config file or of environment variable " + structurizrAPISecretEnvName + ") " +
+			"and merge its containers mapped to technical assets in the mapping file (via --" + structurizrMappingFlagName + ", e.g. containers: {\"Web Application\": web-app}) " +
+			"and their relationships among each other into the technical assets and communication links of the model file, keeping architecture docs and threat model aligned",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg := what.readConfig(cmd, what.buildTimestamp)
+
+			if len(cfg.StructurizrWorkspace) == 0 {
+				return fmt.Errorf("no structurizr workspace given (via --%v)", structurizrWorkspaceFlagName)
+			}
+			if len(cfg.StructurizrMapping) == 0 {
+				return fmt.Errorf("no structurizr mapping given (via --%v)", structurizrMappingFlagName)
+			}
+			mapping, err := input.LoadStructurizrMapping(cfg.StructurizrMapping)
+			if err != nil {
+				return err
+			}
+			workspace, err := input.FetchStructurizrWorkspace(cmd.Context(), cfg.StructurizrWorkspace, cfg.StructurizrAPIKey, cfg.StructurizrAPISecret)
+			if err != nil {
+				return err
+			}
+
+			return what.updateModel(cmd, cfg, func(modelInput *input.Model, changes *[]string) error {
+				return modelInput.ApplyStructurizrWorkspace(workspace, mapping, what.flags.dryRunFlag, changes)
+			})
+		},
+	}
+
+	syncStructurizr.Flags().BoolVar(&what.flags.dryRunFlag, dryRunFlagName, false, "only print the changes instead of updating the model file")
+	what.rootCmd.AddCommand(syncStructurizr)
+
+	return what
+}
+
+// updateModel applies changes to the model file, printing them and (unless a dry run) writing the model back
+func (what *Threagile) updateModel(cmd *cobra.Command, cfg *common.Config, apply func(modelInput *input.Model, changes *[]string) error) error {
+	if evaluator, ok := input.GetModelEvaluator(cfg.InputFile); ok {
+		return fmt.Errorf("changes cannot be applied to %v models, as they are only evaluated", evaluator.Name)
+	}
+
+	modelInput := new(input.Model).Defaults()
+	err := modelInput.Load(cfg.InputFile)
+	if err != nil {
+		return fmt.Errorf("unable to load model: %v", err)
+	}
+
+	changes := make([]string, 0)
+	err = apply(modelInput, &changes)
+	if err != nil {
+		return err
+	}
+	for _, change := range changes {
+		cmd.Println(change)
+	}
+	if what.flags.dryRunFlag || len(changes) == 0 {
+		return nil
+	}
+
+	modelData, err := input.Marshal(cfg.InputFile, modelInput)
+	if err != nil {
+		return fmt.Errorf("unable to write model: %v", err)
+	}
+	err = os.WriteFile(cfg.InputFile, modelData, 0600)
+	if err != nil {
+		return fmt.Errorf("unable to write model: %v", err)
+	}
+	cmd.Printf("Model file %v successfully updated\n", cfg.InputFile)
+	return nil
+}
@@ -9,6 +9,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"runtime"
 	"strings"
 
 	"github.com/chzyer/readline"
@@ -62,28 +63,75 @@ func (what *Threagile) initRoot() *Threagile {
 	what.rootCmd.PersistentFlags().StringVar(&what.flags.outputDirFlag, outputFlagName, defaultConfig.OutputFolder, "output directory")
 	what.rootCmd.PersistentFlags().StringVar(&what.flags.tempDirFlag, tempDirFlagName, defaultConfig.TempFolder, "temporary folder location")
 
-	what.rootCmd.PersistentFlags().StringVar(&what.flags.inputFileFlag, inputFileFlagName, defaultConfig.InputFile, "input model yaml file")
+	what.rootCmd.PersistentFlags().StringVar(&what.flags.inputFileFlag, inputFileFlagName, defaultConfig.InputFile, "input model file (yaml, json, or cue and jsonnet evaluated via their command line tools)")
+	what.rootCmd.PersistentFlags().StringVar(&what.flags.compareModelFlag, compareModelFlagName, "", "previous version of the input model yaml file to compare against")
+	what.rootCmd.PersistentFlags().StringVar(&what.flags.outputRootFlag, outputRootFlagName, "", "output root directory of "+common.AnalyzeAllCommand+", receiving a folder per model and the summary (defaults to the output directory)")
+	what.rootCmd.PersistentFlags().IntVar(&what.flags.workersFlag, workersFlagName, runtime.NumCPU(), "number of models "+common.AnalyzeAllCommand+" analyzes in parallel")
 	what.rootCmd.PersistentFlags().StringVar(&what.flags.raaPluginFlag, raaPluginFlagName, defaultConfig.RAAPlugin, "RAA calculation run file name")
+	what.rootCmd.PersistentFlags().StringVar(&what.flags.ownerDirectoryPluginFlag, ownerDirectoryPluginFlagName, defaultConfig.OwnerDirectoryPlugin, "owner directory lookup run file name (e.g. a bridge to LDAP or SCIM)")
+	what.rootCmd.PersistentFlags().BoolVar(&what.flags.ownerDirectoryStrictFlag, ownerDirectoryStrictFlagName, defaultConfig.OwnerDirectoryStrict, "fail instead of warn when an owner is unknown or inactive in the owner directory")
+	what.rootCmd.PersistentFlags().BoolVar(&what.flags.strictRulesFlag, strictRulesFlagName, defaultConfig.StrictRules, "fail instead of continuing with the remaining risk rules when a risk rule fails")
+	what.rootCmd.PersistentFlags().StringVar(&what.flags.threatIntelFeedFlag, threatIntelFeedFlagName, defaultConfig.ThreatIntelFeed, "threat intel feed file or URL with exploitation trends of technologies, raising RAA and likelihood")
+	what.rootCmd.PersistentFlags().StringVar(&what.flags.previousRisksFlag, previousRisksFlagName, defaultConfig.PreviousRisksFile, "risks json of the previous assessment to report the changes since")
+	what.rootCmd.PersistentFlags().StringVar(&what.flags.failOnRiskFlag, failOnRiskFlagName, defaultConfig.FailOnRisk, "fail (with exit code 6) when risks at or above a severity with given statuses exist, e.g. severity=high,status=unchecked|in-discussion (statuses default to the ones still at risk)")
+	what.rootCmd.PersistentFlags().StringVar(&what.flags.policyGateRegoFlag, policyGateRegoFlagName, defaultConfig.PolicyGateRegoFolder, "folder with OPA/Rego policies (*.rego) in package threagile.gate, failing (with exit code 6) when their deny rules list violations of the risks, stats and technical assets")
+	what.rootCmd.PersistentFlags().StringVar(&what.flags.serviceMetadataURLsFlag, serviceMetadataURLsFlagName, strings.Join(defaultConfig.ServiceMetadataURLs, ","), "comma-separated list of metadata endpoints of running services declaring their dependencies, used by "+common.ImportServiceMetadataCommand)
+	what.rootCmd.PersistentFlags().StringVar(&what.flags.structurizrWorkspaceFlag, structurizrWorkspaceFlagName, defaultConfig.StructurizrWorkspace, "Structurizr workspace API url (e.g. https://api.structurizr.com/workspace/1234) or exported workspace json file, used by "+common.SyncStructurizrCommand)
+	what.rootCmd.PersistentFlags().StringVar(&what.flags.structurizrMappingFlag, structurizrMappingFlagName, defaultConfig.StructurizrMapping, "yaml or json file mapping the Structurizr containers (by id or name) to technical asset ids, and optionally container technologies to technologies and relationship technologies to protocols, used by "+common.SyncStructurizrCommand)
+	what.rootCmd.PersistentFlags().StringVar(&what.flags.structurizrAPIKeyFlag, structurizrAPIKeyFlagName, defaultConfig.StructurizrAPIKey, "API key of the Structurizr workspace, signing the requests together with the API secret of the config file or of environment variable "+structurizrAPISecretEnvName)
+	what.rootCmd.PersistentFlags().StringVar(&what.flags.scannerFindingsFlag, scannerFindingsFlagName, strings.Join(defaultConfig.ScannerFindingsFiles, ","), "comma-separated list of vulnerability scanner outputs (SARIF or DefectDojo json) to correlate with the modeled risks, mapped to technical assets by technical_asset (SARIF properties) or service (DefectDojo) or by tags matching asset IDs or asset tags")
+	what.rootCmd.PersistentFlags().StringVar(&what.flags.riskCommentsFlag, riskCommentsFlagName, defaultConfig.RiskCommentsFile, "json file with the comments of the risks by synthetic risk id (as stored by the server), shown with the risks in the reports")
+	what.rootCmd.PersistentFlags().BoolVar(&what.flags.sbomFetchURLsFlag, sbomFetchURLsFlagName, defaultConfig.SBOMFetchURLs, "fetch the SBOMs of technical assets given as http(s) URL (never done for the models of the server)")
+	what.rootCmd.PersistentFlags().StringVar(&what.flags.macroAnswersFlag, macroAnswersFlagName, defaultConfig.ExecuteModelMacroAnswers, "yaml or json file with the answers of the questions (by their ID) of "+common.ExecuteModelMacroCommand+" to execute the macro without prompts, e.g. in CI pipelines")
+	what.rootCmd.PersistentFlags().StringVar(&what.flags.modelTemplatingFlag, modelTemplatingFlagName, defaultConfig.ModelTemplating, "expand the model file and its includes before parsing them: env (${VAR} or ${VAR:-default} replaced by environment variables) or go-template (Go templates with the environment variables as data, e.g. {{ .VAR }}, {{ env \"VAR\" }} or {{ envOr \"VAR\" \"default\" }})")
+	what.rootCmd.PersistentFlags().StringVar(&what.flags.preParseHooksFlag, preParseHooksFlagName, strings.Join(defaultConfig.PreParseHooks, ","), "comma-separated list of commands run before parsing the model file and each of its includes, getting the file as argument and its content on stdin and in the file of THREAGILE_MODEL_DATA_FILE, which they rewrite to replace the content (e.g. to scrub secrets)")
+	what.rootCmd.PersistentFlags().StringVar(&what.flags.postAnalysisHooksFlag, postAnalysisHooksFlagName, strings.Join(defaultConfig.PostAnalysisHooks, ","), "comma-separated list of commands run after analyzing the model and writing the reports, getting the model file and the written files as arguments (e.g. to upload them)")
+	what.rootCmd.PersistentFlags().IntVar(&what.flags.pluginTimeoutFlag, pluginTimeoutFlagName, defaultConfig.PluginTimeoutSeconds, "seconds each call of a plugin may take before it is killed (0 for no limit)")
+	what.rootCmd.PersistentFlags().IntVar(&what.flags.analysisTimeoutFlag, analysisTimeoutFlagName, defaultConfig.AnalysisTimeoutSeconds, "seconds reading and analyzing the model may take (0 for no limit)")
+	what.rootCmd.PersistentFlags().IntVar(&what.flags.renderTimeoutFlag, renderTimeoutFlagName, defaultConfig.RenderTimeoutSeconds, "seconds generating the diagrams and reports may take (0 for no limit)")
 
 	what.rootCmd.PersistentFlags().BoolVarP(&what.flags.interactiveFlag, interactiveFlagName, interactiveFlagShorthand, defaultConfig.Interactive, "interactive mode")
 	what.rootCmd.PersistentFlags().BoolVarP(&what.flags.verboseFlag, verboseFlagName, verboseFlagShorthand, defaultConfig.Verbose, "verbose output")
+	what.rootCmd.PersistentFlags().StringArrayVar(&what.flags.logRedactionFlag, logRedactionPatternFlagName, defaultConfig.LogRedactionPatterns, "regular expression of sensitive data to mask in the log output in addition to the default secret patterns, only its group named secret if any (repeatable)")
 
 	what.rootCmd.PersistentFlags().StringVar(&what.flags.configFlag, configFlagName, "", "config file")
 
 	what.rootCmd.PersistentFlags().StringVar(&what.flags.customRiskRulesPluginFlag, customRiskRulesPluginFlagName, strings.Join(defaultConfig.RiskRulesPlugins, ","), "comma-separated list of plugins file names with custom risk rules to load")
+	what.rootCmd.PersistentFlags().StringVar(&what.flags.customRiskRulesRegoFlag, customRiskRulesRegoFlagName, defaultConfig.RiskRulesRegoFolder, "folder with OPA/Rego policies (*.rego) defining custom risk rules in packages below threagile.rules, evaluated by the opa tool against the parsed model")
+	what.rootCmd.PersistentFlags().StringVar(&what.flags.customRiskRulesScriptsFlag, customRiskRulesScriptsFlagName, strings.Join(defaultConfig.RiskRulesScripts, ","), "comma-separated list of JavaScript modules (*.js) defining custom risk rules by exporting category, supportedTags and generateRisks (getting the parsed model, returning risk objects), run sandboxed by the embedded JavaScript interpreter")
+	what.rootCmd.PersistentFlags().StringVar(&what.flags.reportSectionPluginsFlag, reportSectionPluginsFlagName, strings.Join(defaultConfig.ReportSectionPlugins, ","), "comma-separated list of plugins file names adding organization-specific chapters to the report")
 	what.rootCmd.PersistentFlags().IntVar(&what.flags.diagramDpiFlag, diagramDpiFlagName, defaultConfig.DiagramDPI, "DPI used to render: maximum is "+fmt.Sprintf("%d", common.MaxGraphvizDPI)+"")
-	what.rootCmd.PersistentFlags().StringVar(&what.flags.skipRiskRulesFlag, skipRiskRulesFlagName, strings.Join(defaultConfig.SkipRiskRules, ","), "comma-separated list of risk rules (by their ID) to skip")
+	what.rootCmd.PersistentFlags().IntVar(&what.flags.maxDpiFlag, maxDpiFlagName, defaultConfig.MaxGraphvizDPI, "maximum DPI of the auto-scaled diagrams and of the ones requested from the server")
+	what.rootCmd.PersistentFlags().IntVar(&what.flags.diagramMaxPixelsFlag, diagramMaxPixelsFlagName, defaultConfig.DiagramMaxPixels, "choose the DPI of each diagram by its number of nodes and edges (up to the maximum DPI) so that its image stays below this many pixels, instead of using the diagram DPI")
+	what.rootCmd.PersistentFlags().StringVar(&what.flags.diagramThemeFlag, diagramThemeFlagName, defaultConfig.DiagramTheme, "diagram theme: "+strings.Join(report.DiagramThemeNames(), ", ")+" or a yaml theme file")
+	what.rootCmd.PersistentFlags().StringVar(&what.flags.diagramVariantsFlag, diagramVariantsFlagName, strings.Join(defaultConfig.DiagramVariants, ","), "comma-separated list of diagram themes to additionally render the diagrams in (e.g. dark,grayscale), written with the theme as file name suffix")
+	what.rootCmd.PersistentFlags().StringVar(&what.flags.fontFileFlag, fontFileFlagName, defaultConfig.FontFile, "ttf font file used in the diagrams and reports instead of the bundled one (covering Latin, Greek and Cyrillic), e.g. for CJK asset titles")
+	what.rootCmd.PersistentFlags().StringVar(&what.flags.skipRiskRulesFlag, skipRiskRulesFlagName, strings.Join(defaultConfig.SkipRiskRules, ","), "comma-separated list of risk rules (by their ID, wildcards like unencrypted-* allowed) to skip")
+	what.rootCmd.PersistentFlags().StringToIntVar(&what.flags.severityRecalibrationFlag, severityRecalibrationFlagName, defaultConfig.SeverityRecalibration, "severity levels to shift the risks by, keyed by the criticality of their trust boundary (or the business criticality), e.g. mission-critical=1,archive=-1")
+	what.rootCmd.PersistentFlags().StringArrayVar(&what.flags.riskMergeGroupFlag, riskMergeGroupFlagName, nil, "group of overlapping risk categories whose duplicate risks are merged, as name=category-id,category-id (repeatable)")
+	what.rootCmd.PersistentFlags().StringVar(&what.flags.riskMergeKeysFlag, riskMergeKeysFlagName, strings.Join(defaultConfig.RiskMerge.Keys, ","), "comma-separated list of the elements of risks (e.g. technical_asset, communication_link) that must be equal for merging them")
 	what.rootCmd.PersistentFlags().BoolVar(&what.flags.ignoreOrphanedRiskTrackingFlag, ignoreOrphanedRiskTrackingFlagName, defaultConfig.IgnoreOrphanedRiskTracking, "ignore orphaned risk tracking (just log them) not matching a concrete risk")
+	what.rootCmd.PersistentFlags().BoolVar(&what.flags.autoSeedTagsAvailableFlag, autoSeedTagsAvailableFlagName, defaultConfig.AutoSeedTagsAvailable, "add the tags used by elements but missing in tags_available, warning about each, instead of failing")
 	what.rootCmd.PersistentFlags().StringVar(&what.flags.templateFileNameFlag, templateFileNameFlagName, defaultConfig.TemplateFilename, "background pdf file")
+	what.rootCmd.PersistentFlags().StringVar(&what.flags.riskCategoryOverridesFlag, riskCategoryOverridesFlagName, defaultConfig.RiskCategoryOverridesFile, "yaml file with organization-level overrides of risk category texts and severities")
+	what.rootCmd.PersistentFlags().StringVar(&what.flags.tagTaxonomyFlag, tagTaxonomyFlagName, defaultConfig.TagTaxonomyFilename, "yaml file defining the allowed tags, their hierarchy and descriptions")
 
 	what.rootCmd.PersistentFlags().BoolVar(&what.flags.generateDataFlowDiagramFlag, generateDataFlowDiagramFlagName, true, "generate data flow diagram")
 	what.rootCmd.PersistentFlags().BoolVar(&what.flags.generateDataAssetDiagramFlag, generateDataAssetDiagramFlagName, true, "generate data asset diagram")
 	what.rootCmd.PersistentFlags().BoolVar(&what.flags.generateRisksJSONFlag, generateRisksJSONFlagName, true, "generate risks json")
+	what.rootCmd.PersistentFlags().BoolVar(&what.flags.generateRisksSARIFFlag, generateRisksSARIFFlagName, false, "generate risks as SARIF 2.1.0 log (risk categories as rules), e.g. for the upload to code scanning tools")
+	what.rootCmd.PersistentFlags().BoolVar(&what.flags.generateDueDatesICSFlag, generateDueDatesICSFlagName, false, "generate iCalendar (.ics) of the due dates of the risks not handled yet and of the next model review, e.g. for calendar subscriptions")
 	what.rootCmd.PersistentFlags().BoolVar(&what.flags.generateTechnicalAssetsJSONFlag, generateTechnicalAssetsJSONFlagName, true, "generate technical assets json")
 	what.rootCmd.PersistentFlags().BoolVar(&what.flags.generateStatsJSONFlag, generateStatsJSONFlagName, true, "generate stats json")
 	what.rootCmd.PersistentFlags().BoolVar(&what.flags.generateRisksExcelFlag, generateRisksExcelFlagName, true, "generate risks excel")
 	what.rootCmd.PersistentFlags().BoolVar(&what.flags.generateTagsExcelFlag, generateTagsExcelFlagName, true, "generate tags excel")
 	what.rootCmd.PersistentFlags().BoolVar(&what.flags.generateReportPDFFlag, generateReportPDFFlagName, true, "generate report pdf, including diagrams")
+	what.rootCmd.PersistentFlags().BoolVar(&what.flags.generateRulesDocFlag, generateRulesDocFlagName, false, "generate markdown and html documentation of all active risk rules")
+	what.rootCmd.PersistentFlags().BoolVar(&what.flags.generateRisksPerOwnerFlag, generateRisksPerOwnerFlagName, false, "generate separate risks json and excel files per technical asset owner")
+	what.rootCmd.PersistentFlags().BoolVar(&what.flags.generateRiskMatrixFlag, generateRiskMatrixFlagName, true, "generate risk matrix chart (png and svg)")
+	what.rootCmd.PersistentFlags().BoolVar(&what.flags.generateExcelWorkbookFlag, generateExcelWorkbookFlagName, false, "generate a single excel workbook with risks, tags, asset inventory, boundary-crossing links and stats sheets")
+	what.rootCmd.PersistentFlags().BoolVar(&what.flags.generateAssetSheetsFlag, generateAssetSheetsFlagName, false, "generate a one-page pdf and html per technical asset with its attributes, data, links, open risks and mitigation checklist")
+	what.rootCmd.PersistentFlags().BoolVar(&what.flags.generateMitigationChecklistFlag, generateMitigationChecklistFlagName, false, "generate a checklist (markdown and csv) of mitigation actions for all open risks grouped by asset and priority")
 
 	return what
 }
@@ -213,11 +261,19 @@ func (what *Threagile) readCommands() *report.GenerateCommands {
 	commands.DataFlowDiagram = what.flags.generateDataFlowDiagramFlag
 	commands.DataAssetDiagram = what.flags.generateDataAssetDiagramFlag
 	commands.RisksJSON = what.flags.generateRisksJSONFlag
+	commands.RisksSARIF = what.flags.generateRisksSARIFFlag
+	commands.DueDatesICS = what.flags.generateDueDatesICSFlag
 	commands.StatsJSON = what.flags.generateStatsJSONFlag
 	commands.TechnicalAssetsJSON = what.flags.generateTechnicalAssetsJSONFlag
 	commands.RisksExcel = what.flags.generateRisksExcelFlag
 	commands.TagsExcel = what.flags.generateTagsExcelFlag
 	commands.ReportPDF = what.flags.generateReportPDFFlag
+	commands.RulesDoc = what.flags.generateRulesDocFlag
+	commands.RisksPerOwner = what.flags.generateRisksPerOwnerFlag
+	commands.RiskMatrix = what.flags.generateRiskMatrixFlag
+	commands.ExcelWorkbook = what.flags.generateExcelWorkbookFlag
+	commands.AssetSheets = what.flags.generateAssetSheetsFlag
+	commands.MitigationChecklist = what.flags.generateMitigationChecklistFlag
 	return commands
 }
 
@@ -235,6 +291,42 @@ func (what *Threagile) readConfig(cmd *cobra.Command, buildTimestamp string) *co
 	if isFlagOverridden(flags, serverDirFlagName) {
 		cfg.ServerFolder = cfg.CleanPath(what.flags.serverDirFlag)
 	}
+	if isFlagOverridden(flags, tempTTLFlagName) {
+		cfg.TempWorkspaceTTLMinutes = what.flags.tempTTLFlag
+	}
+	if isFlagOverridden(flags, serverStorageFlagName) {
+		cfg.ServerStorage = what.flags.serverStorageFlag
+	}
+	if isFlagOverridden(flags, maxDpiFlagName) {
+		cfg.MaxGraphvizDPI = what.flags.maxDpiFlag
+	}
+	if isFlagOverridden(flags, maxAnalysesFlagName) {
+		cfg.MaxAnalysesPerHour = what.flags.maxAnalysesFlag
+	}
+	if isFlagOverridden(flags, maxModelsFlagName) {
+		cfg.MaxModelsPerKey = what.flags.maxModelsFlag
+	}
+	if isFlagOverridden(flags, validateOnWriteFlagName) {
+		cfg.ValidateModelOnWrite = what.flags.validateOnWriteFlag
+	}
+	if adminKey := os.Getenv(serverAdminKeyEnvName); len(adminKey) > 0 {
+		cfg.ServerAdminKey = adminKey // not as flag, which would show up in the process list
+	}
+	if isFlagOverridden(flags, templatesDirFlagName) {
+		cfg.TemplatesFolder = cfg.CleanPath(what.flags.templatesDirFlag)
+	}
+	if isFlagOverridden(flags, templatesIndexFlagName) {
+		cfg.TemplatesIndexURL = what.flags.templatesIndexFlag
+	}
+	if isFlagOverridden(flags, webhookURLsFlagName) {
+		cfg.WebhookURLs = strings.Split(what.flags.webhookURLsFlag, ",")
+	}
+	if isFlagOverridden(flags, webhookSecretFlagName) {
+		cfg.WebhookSecret = what.flags.webhookSecretFlag
+	}
+	if isFlagOverridden(flags, webhookCriticalFlagName) {
+		cfg.WebhookNewCriticalOnly = what.flags.webhookCriticalFlag
+	}
 
 	if isFlagOverridden(flags, appDirFlagName) {
 		cfg.AppFolder = cfg.CleanPath(what.flags.appDirFlag)
@@ -260,21 +352,150 @@ func (what *Threagile) readConfig(cmd *cobra.Command, buildTimestamp string) *co
 		cfg.RAAPlugin = what.flags.raaPluginFlag
 	}
 
+	if isFlagOverridden(flags, ownerDirectoryPluginFlagName) {
+		cfg.OwnerDirectoryPlugin = what.flags.ownerDirectoryPluginFlag
+	}
+	if isFlagOverridden(flags, ownerDirectoryStrictFlagName) {
+		cfg.OwnerDirectoryStrict = what.flags.ownerDirectoryStrictFlag
+	}
+	if isFlagOverridden(flags, strictRulesFlagName) {
+		cfg.StrictRules = what.flags.strictRulesFlag
+	}
+
+	if isFlagOverridden(flags, threatIntelFeedFlagName) {
+		cfg.ThreatIntelFeed = what.flags.threatIntelFeedFlag
+	}
+	if isFlagOverridden(flags, previousRisksFlagName) {
+		cfg.PreviousRisksFile = what.flags.previousRisksFlag
+	}
+	if isFlagOverridden(flags, failOnRiskFlagName) {
+		cfg.FailOnRisk = what.flags.failOnRiskFlag
+	}
+	if isFlagOverridden(flags, policyGateRegoFlagName) {
+		cfg.PolicyGateRegoFolder = what.flags.policyGateRegoFlag
+	}
+	if isFlagOverridden(flags, macroAnswersFlagName) {
+		cfg.ExecuteModelMacroAnswers = cfg.CleanPath(what.flags.macroAnswersFlag)
+	}
+	if isFlagOverridden(flags, serviceMetadataURLsFlagName) {
+		cfg.ServiceMetadataURLs = strings.Split(what.flags.serviceMetadataURLsFlag, ",")
+	}
+	if isFlagOverridden(flags, structurizrWorkspaceFlagName) {
+		cfg.StructurizrWorkspace = what.flags.structurizrWorkspaceFlag
+	}
+	if isFlagOverridden(flags, structurizrMappingFlagName) {
+		cfg.StructurizrMapping = cfg.CleanPath(what.flags.structurizrMappingFlag)
+	}
+	if isFlagOverridden(flags, structurizrAPIKeyFlagName) {
+		cfg.StructurizrAPIKey = what.flags.structurizrAPIKeyFlag
+	}
+	if secret := os.Getenv(structurizrAPISecretEnvName); len(secret) > 0 {
+		cfg.StructurizrAPISecret = secret // not as flag, which would show up in the process list
+	}
+	if isFlagOverridden(flags, scannerFindingsFlagName) {
+		cfg.ScannerFindingsFiles = strings.Split(what.flags.scannerFindingsFlag, ",")
+	}
+	if isFlagOverridden(flags, riskCommentsFlagName) {
+		cfg.RiskCommentsFile = cfg.CleanPath(what.flags.riskCommentsFlag)
+	}
+	if isFlagOverridden(flags, sbomFetchURLsFlagName) {
+		cfg.SBOMFetchURLs = what.flags.sbomFetchURLsFlag
+	}
+
+	if isFlagOverridden(flags, modelTemplatingFlagName) {
+		cfg.ModelTemplating = what.flags.modelTemplatingFlag
+	}
+
+	if isFlagOverridden(flags, preParseHooksFlagName) {
+		cfg.PreParseHooks = strings.Split(what.flags.preParseHooksFlag, ",")
+	}
+
+	if isFlagOverridden(flags, postAnalysisHooksFlagName) {
+		cfg.PostAnalysisHooks = strings.Split(what.flags.postAnalysisHooksFlag, ",")
+	}
+
+	if isFlagOverridden(flags, pluginTimeoutFlagName) {
+		cfg.PluginTimeoutSeconds = what.flags.pluginTimeoutFlag
+	}
+	if isFlagOverridden(flags, analysisTimeoutFlagName) {
+		cfg.AnalysisTimeoutSeconds = what.flags.analysisTimeoutFlag
+	}
+	if isFlagOverridden(flags, renderTimeoutFlagName) {
+		cfg.RenderTimeoutSeconds = what.flags.renderTimeoutFlag
+	}
+
 	if isFlagOverridden(flags, customRiskRulesPluginFlagName) {
 		cfg.RiskRulesPlugins = strings.Split(what.flags.customRiskRulesPluginFlag, ",")
 	}
+	if isFlagOverridden(flags, customRiskRulesRegoFlagName) {
+		cfg.RiskRulesRegoFolder = what.flags.customRiskRulesRegoFlag
+	}
+	if isFlagOverridden(flags, customRiskRulesScriptsFlagName) {
+		cfg.RiskRulesScripts = strings.Split(what.flags.customRiskRulesScriptsFlag, ",")
+	}
+	if isFlagOverridden(flags, reportSectionPluginsFlagName) {
+		cfg.ReportSectionPlugins = strings.Split(what.flags.reportSectionPluginsFlag, ",")
+	}
 	if isFlagOverridden(flags, skipRiskRulesFlagName) {
 		cfg.SkipRiskRules = strings.Split(what.flags.skipRiskRulesFlag, ",")
 	}
+	if isFlagOverridden(flags, severityRecalibrationFlagName) {
+		cfg.SeverityRecalibration = what.flags.severityRecalibrationFlag
+	}
+	if isFlagOverridden(flags, riskMergeGroupFlagName) {
+		cfg.RiskMerge.Groups = make(map[string][]string)
+		for _, group := range what.flags.riskMergeGroupFlag {
+			name, categories, found := strings.Cut(group, "=")
+			if !found || len(strings.TrimSpace(name)) == 0 {
+				cmd.Printf("WARNING: ignoring risk merge group %q without name\n", group)
+				continue
+			}
+			cfg.RiskMerge.Groups[strings.TrimSpace(name)] = strings.Split(categories, ",")
+		}
+	}
+	if isFlagOverridden(flags, riskMergeKeysFlagName) {
+		cfg.RiskMerge.Keys = strings.Split(what.flags.riskMergeKeysFlag, ",")
+	}
 	if isFlagOverridden(flags, ignoreOrphanedRiskTrackingFlagName) {
 		cfg.IgnoreOrphanedRiskTracking = what.flags.ignoreOrphanedRiskTrackingFlag
 	}
+	if isFlagOverridden(flags, autoSeedTagsAvailableFlagName) {
+		cfg.AutoSeedTagsAvailable = what.flags.autoSeedTagsAvailableFlag
+	}
 	if isFlagOverridden(flags, diagramDpiFlagName) {
 		cfg.DiagramDPI = what.flags.diagramDpiFlag
 	}
+	if isFlagOverridden(flags, diagramMaxPixelsFlagName) {
+		cfg.DiagramMaxPixels = what.flags.diagramMaxPixelsFlag
+	}
+	if isFlagOverridden(flags, diagramThemeFlagName) {
+		cfg.DiagramTheme = what.flags.diagramThemeFlag
+	}
+	if isFlagOverridden(flags, diagramVariantsFlagName) {
+		cfg.DiagramVariants = strings.Split(what.flags.diagramVariantsFlag, ",")
+	}
+	if isFlagOverridden(flags, fontFileFlagName) {
+		cfg.FontFile = what.flags.fontFileFlag
+	}
 	if isFlagOverridden(flags, templateFileNameFlagName) {
 		cfg.TemplateFilename = what.flags.templateFileNameFlag
 	}
+	if isFlagOverridden(flags, riskCategoryOverridesFlagName) {
+		cfg.RiskCategoryOverridesFile = cfg.CleanPath(what.flags.riskCategoryOverridesFlag)
+	}
+	if isFlagOverridden(flags, tagTaxonomyFlagName) {
+		cfg.TagTaxonomyFilename = cfg.CleanPath(what.flags.tagTaxonomyFlag)
+	}
+	if isFlagOverridden(flags, logRedactionPatternFlagName) {
+		cfg.LogRedactionPatterns = what.flags.logRedactionFlag
+	}
+
+	redactionError := common.EnableLogRedaction(cfg.LogRedactionPatterns)
+	if redactionError != nil {
+		cmd.Printf("WARNING: only the default log redaction patterns apply: %v\n", redactionError)
+		_ = common.EnableLogRedaction(nil)
+	}
+	what.rootCmd.SetErr(common.LogStderr())
 	return cfg
 }
 
@@ -1,6 +1,8 @@
 package threagile
 
 import (
+	"strings"
+
 	"github.com/spf13/cobra"
 	"github.com/threagile/threagile/pkg/common"
 	"github.com/threagile/threagile/pkg/server"
@@ -12,6 +14,7 @@ func (what *Threagile) initServer() *Threagile {
 	serverCmd := &cobra.Command{
 		Use:   "server",
 		Short: "Run server",
+		Long:  "Run server, offering the admin endpoints (like the dashboard across all keys) with the admin key of the config file or of environment variable " + serverAdminKeyEnvName,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			cfg := what.readConfig(cmd, what.buildTimestamp)
 			cfg.ServerMode = true
@@ -19,13 +22,23 @@ func (what *Threagile) initServer() *Threagile {
 			if serverError != nil {
 				return serverError
 			}
-			server.RunServer(cfg)
-			return nil
+			return server.RunServer(cfg)
 		},
 	}
 
 	serverCmd.PersistentFlags().IntVar(&what.flags.serverPortFlag, serverPortFlagName, defaultConfig.ServerPort, "server port")
 	serverCmd.PersistentFlags().StringVar(&what.flags.serverDirFlag, serverDirFlagName, defaultConfig.DataFolder, "base folder for server mode (default: "+common.DataDir+")")
+	serverCmd.PersistentFlags().IntVar(&what.flags.tempTTLFlag, tempTTLFlagName, defaultConfig.TempWorkspaceTTLMinutes, "minutes after which temp workspaces left behind (e.g. by crashed renders) are removed")
+	serverCmd.PersistentFlags().StringVar(&what.flags.serverStorageFlag, serverStorageFlagName, defaultConfig.ServerStorage, "where keys and models are kept: "+common.ServerStorageFile+" (in the server folder) or "+common.ServerStorageMemory+" (lost on exit, for tests and demos)")
+
+	serverCmd.PersistentFlags().IntVar(&what.flags.maxAnalysesFlag, maxAnalysesFlagName, defaultConfig.MaxAnalysesPerHour, "maximum renderings per hour of each key (and its tokens), 0 is unlimited")
+	serverCmd.PersistentFlags().IntVar(&what.flags.maxModelsFlag, maxModelsFlagName, defaultConfig.MaxModelsPerKey, "maximum stored models of each key, 0 is unlimited")
+	serverCmd.PersistentFlags().StringVar(&what.flags.templatesDirFlag, templatesDirFlagName, defaultConfig.TemplatesFolder, "folder of model files offered as templates of new models (by file name)")
+	serverCmd.PersistentFlags().StringVar(&what.flags.templatesIndexFlag, templatesIndexFlagName, defaultConfig.TemplatesIndexURL, "url of a json array of templates (id, title, description and url of the model file) offered as templates of new models")
+	serverCmd.PersistentFlags().StringVar(&what.flags.webhookURLsFlag, webhookURLsFlagName, strings.Join(defaultConfig.WebhookURLs, ","), "comma-separated list of urls receiving a json summary (POST) when an analysis of a stored model completes")
+	serverCmd.PersistentFlags().StringVar(&what.flags.webhookSecretFlag, webhookSecretFlagName, defaultConfig.WebhookSecret, "key signing the webhooks with HMAC-SHA256 of their timestamp (header X-Threagile-Timestamp) and body in header X-Threagile-Signature (prefer the config file over the command line)")
+	serverCmd.PersistentFlags().BoolVar(&what.flags.webhookCriticalFlag, webhookCriticalFlagName, defaultConfig.WebhookNewCriticalOnly, "only send the webhooks when critical risks appear which the previous analysis of the model did not have")
+	serverCmd.PersistentFlags().BoolVar(&what.flags.validateOnWriteFlag, validateOnWriteFlagName, defaultConfig.ValidateModelOnWrite, "reject changes resulting in invalid models (with the list of errors), multi-step changes can be done via bulk operations or edit sessions")
 
 	what.rootCmd.AddCommand(serverCmd)
 
@@ -1,9 +1,13 @@
 package threagile
 
 import (
+	"context"
 	"os"
+	"os/signal"
 
 	"github.com/spf13/cobra"
+
+	"github.com/threagile/threagile/pkg/common"
 )
 
 type Threagile struct {
@@ -13,14 +17,17 @@ type Threagile struct {
 }
 
 func (what *Threagile) Execute() {
-	err := what.rootCmd.Execute()
+	// interrupting the run cancels the analysis, killing running plugins and renderings
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	err := what.rootCmd.ExecuteContext(ctx)
+	stop()
 	if err != nil {
-		what.rootCmd.Println(err)
-		os.Exit(1)
+		what.rootCmd.PrintErrln(err)
+		os.Exit(int(common.ExitCodeOf(err)))
 	}
 }
 
 func (what *Threagile) Init(buildTimestamp string) *Threagile {
 	what.buildTimestamp = buildTimestamp
-	return what.initRoot().initAnalyze().initCreate().initExecute().initExplain().initList().initPrint().initQuit().initServer().initVersion()
+	return what.initRoot().initAnalyze().initAnalyzeAll().initCreate().initDiff().initDoctor().initExecute().initExplain().initList().initPrint().initQuit().initScanAnnotations().initImportServiceMetadata().initSyncStructurizr().initImport().initExport().initServer().initValidate().initLanguageServer().initVersion().initCompletion()
 }
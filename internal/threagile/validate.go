@@ -0,0 +1,64 @@
+package threagile
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/threagile/threagile/pkg/common"
+	"github.com/threagile/threagile/pkg/input"
+	"github.com/threagile/threagile/pkg/lsp"
+	"github.com/threagile/threagile/pkg/model"
+	"github.com/threagile/threagile/pkg/security/risks"
+)
+
+// formats of the problems printed by the validation
+const (
+	diagnosticsFormatProblems = "problems"
+	diagnosticsFormatJSON     = "json" // located in the model files, like the diagnostics of the language server
+)
+
+func (what *Threagile) initValidate() *Threagile {
+	validate := &cobra.Command{
+		Use:   common.ValidateModelCommand,
+		Short: "Validate the model, printing all problems found as json",
+		Long:  "Parse the model with all consistency checks (without running the risk rules) and print all problems found as json array (with file, field, message, severity and code) instead of stopping at the first one, or with --diagnostics-format json as diagnostics located in the model files",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if what.flags.diagnosticsFormatFlag != diagnosticsFormatProblems && what.flags.diagnosticsFormatFlag != diagnosticsFormatJSON {
+				return fmt.Errorf("unknown diagnostics format %q: %v or %v", what.flags.diagnosticsFormatFlag, diagnosticsFormatProblems, diagnosticsFormatJSON)
+			}
+			cfg := what.readConfig(cmd, what.buildTimestamp)
+			progressReporter := common.DefaultProgressReporter{Verbose: cfg.Verbose}
+
+			customRiskRules := model.LoadCustomRiskRules(cmd.Context(), cfg.RiskRulesPlugins, cfg.PluginTimeoutSeconds, progressReporter)
+			customRiskRules.Merge(model.LoadRegoRiskRules(cmd.Context(), cfg.RiskRulesRegoFolder, cfg.PluginTimeoutSeconds, progressReporter))
+			customRiskRules.Merge(model.LoadScriptRiskRules(cmd.Context(), cfg.RiskRulesScripts, cfg.PluginTimeoutSeconds, progressReporter))
+			problems := model.ValidateModel(cfg, risks.GetBuiltInRiskRules(), customRiskRules)
+			var printed any = problems
+			if what.flags.diagnosticsFormatFlag == diagnosticsFormatJSON {
+				printed = lsp.Diagnostics(problems, input.ReadModelFile)
+			}
+			data, err := json.MarshalIndent(printed, "", "  ")
+			if err != nil {
+				return fmt.Errorf("failed to marshal problems to JSON: %w", err)
+			}
+			_, _ = fmt.Fprintln(cmd.OutOrStdout(), string(data))
+
+			errorCount := 0
+			for _, problem := range problems {
+				if problem.Severity == model.ValidationError {
+					errorCount++
+				}
+			}
+			if errorCount > 0 {
+				return common.NewFailure(common.ExitCodeValidationError, fmt.Errorf("model %v is invalid: %v errors found", cfg.InputFile, errorCount))
+			}
+			return nil
+		},
+	}
+	validate.Flags().StringVar(&what.flags.diagnosticsFormatFlag, diagnosticsFormatFlagName, diagnosticsFormatProblems, "print the problems as "+diagnosticsFormatProblems+" (with file, field, message, severity and code) or as "+diagnosticsFormatJSON+" diagnostics located in the model files (with file, zero-based range, severity, code and message) for editor extensions and CI annotations")
+	what.rootCmd.AddCommand(validate)
+
+	return what
+}
@@ -0,0 +1,57 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// CreateKey creates a new key, the models of a key are stored encrypted with it
+func (c *Client) CreateKey(ctx context.Context) (string, error) {
+	body, err := c.do(ctx, request{method: http.MethodPost, path: "/auth/keys"})
+	if err != nil {
+		return "", err
+	}
+	var payload struct {
+		Key string `json:"key"`
+	}
+	err = json.Unmarshal(body, &payload)
+	if err != nil || len(payload.Key) == 0 {
+		return "", fmt.Errorf("invalid response of /auth/keys: %v", err)
+	}
+	return payload.Key, nil
+}
+
+// DeleteKey deletes the key together with all its models
+func (c *Client) DeleteKey(ctx context.Context, key string) error {
+	_, err := c.do(ctx, request{method: http.MethodDelete, path: "/auth/keys", header: map[string]string{"key": key}})
+	return err
+}
+
+// Login creates a session token of the key (invalidating the previous one) and uses it for the following calls
+func (c *Client) Login(ctx context.Context, key string) error {
+	body, err := c.do(ctx, request{method: http.MethodPost, path: "/auth/tokens", header: map[string]string{"key": key}})
+	if err != nil {
+		return err
+	}
+	var payload struct {
+		Token string `json:"token"`
+	}
+	err = json.Unmarshal(body, &payload)
+	if err != nil || len(payload.Token) == 0 {
+		return fmt.Errorf("invalid response of /auth/tokens: %v", err)
+	}
+	c.Token = payload.Token
+	return nil
+}
+
+// Logout deletes the session token
+func (c *Client) Logout(ctx context.Context) error {
+	_, err := c.do(ctx, request{method: http.MethodDelete, path: "/auth/tokens", header: c.tokenHeader()})
+	if err != nil {
+		return err
+	}
+	c.Token = ""
+	return nil
+}
@@ -0,0 +1,147 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	DefaultRetries    = 3
+	DefaultRetryDelay = 500 * time.Millisecond
+)
+
+// Client calls the REST API of a threagile server (see the server command), most calls require a session token of a
+// key set via Login
+type Client struct {
+	BaseURL    string
+	HTTPClient *http.Client
+	Retries    int           // attempts after the first one for transient failures (throttling, unavailable server)
+	RetryDelay time.Duration // delay before the first retry, doubled for each further one
+	Token      string        // session token sent with the model calls
+}
+
+func New(baseURL string) *Client {
+	return &Client{
+		BaseURL:    strings.TrimSuffix(baseURL, "/"),
+		HTTPClient: &http.Client{Timeout: 10 * time.Minute}, // analyses and renderings may take a while
+		Retries:    DefaultRetries,
+		RetryDelay: DefaultRetryDelay,
+	}
+}
+
+// Error is a failed call, with the error message returned by the server
+type Error struct {
+	Method     string
+	Path       string
+	StatusCode int
+	Code       string // the error code of the server, like model_not_found
+	Message    string
+}
+
+func (what *Error) Error() string {
+	if len(what.Message) == 0 {
+		return fmt.Sprintf("%v %v: %v", what.Method, what.Path, http.StatusText(what.StatusCode))
+	}
+	return fmt.Sprintf("%v %v: %v (%v)", what.Method, what.Path, what.Message, http.StatusText(what.StatusCode))
+}
+
+// request is a call kept as plain data, so that it can be sent again when retrying
+type request struct {
+	method      string
+	path        string
+	header      map[string]string
+	body        []byte
+	contentType string
+}
+
+// do sends the request and returns the body of a successful response; throttled (429) and unavailable (503) calls are
+// retried as the server did not process them, network failures and gateway errors only for idempotent methods
+func (c *Client) do(ctx context.Context, call request) ([]byte, error) {
+	delay := c.RetryDelay
+	for attempt := 0; ; attempt++ {
+		body, statusCode, err := c.send(ctx, call)
+		if err == nil && statusCode >= 200 && statusCode < 300 {
+			return body, nil
+		}
+		if err == nil {
+			err = errorOf(call, statusCode, body)
+		}
+		if attempt >= c.Retries || !retryable(call.method, statusCode) {
+			return nil, err
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(delay):
+		}
+		delay *= 2
+	}
+}
+
+func (c *Client) send(ctx context.Context, call request) ([]byte, int, error) {
+	httpRequest, err := http.NewRequestWithContext(ctx, call.method, c.BaseURL+call.path, bytes.NewReader(call.body))
+	if err != nil {
+		return nil, 0, err
+	}
+	if len(call.contentType) > 0 {
+		httpRequest.Header.Set("Content-Type", call.contentType)
+	}
+	for name, value := range call.header {
+		httpRequest.Header.Set(name, value)
+	}
+
+	response, err := c.HTTPClient.Do(httpRequest)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer func() { _ = response.Body.Close() }()
+
+	body, err := io.ReadAll(response.Body)
+	if err != nil {
+		return nil, response.StatusCode, err
+	}
+	return body, response.StatusCode, nil
+}
+
+// retryable tells whether a failed call can be sent again, a status code of 0 is a network failure
+func retryable(method string, statusCode int) bool {
+	switch statusCode {
+	case http.StatusTooManyRequests, http.StatusServiceUnavailable:
+		return true
+	case 0, http.StatusBadGateway, http.StatusGatewayTimeout:
+		return method == http.MethodGet || method == http.MethodPut || method == http.MethodDelete
+	}
+	return false
+}
+
+func errorOf(call request, statusCode int, body []byte) error {
+	var payload struct {
+		Error string `json:"error"`
+		Code  string `json:"code"`
+	}
+	_ = json.Unmarshal(body, &payload)
+	return &Error{Method: call.method, Path: call.path, StatusCode: statusCode, Code: payload.Code, Message: payload.Error}
+}
+
+func (c *Client) tokenHeader() map[string]string {
+	return map[string]string{"token": c.Token}
+}
+
+func (c *Client) getJSON(ctx context.Context, path string, result any) error {
+	body, err := c.do(ctx, request{method: http.MethodGet, path: path, header: c.tokenHeader()})
+	if err != nil {
+		return err
+	}
+	err = json.Unmarshal(body, result)
+	if err != nil {
+		return fmt.Errorf("invalid response of %v: %w", path, err)
+	}
+	return nil
+}
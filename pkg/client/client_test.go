@@ -0,0 +1,76 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClientRetriesThrottledCalls(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		calls++
+		if calls == 1 {
+			writer.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		assert.Equal(t, "/auth/tokens", request.URL.Path)
+		assert.Equal(t, "secret", request.Header.Get("key"))
+		_, _ = writer.Write([]byte(`{"token": "session"}`))
+	}))
+	defer server.Close()
+
+	client := New(server.URL)
+	client.RetryDelay = 0
+	assert.NoError(t, client.Login(context.Background(), "secret"))
+	assert.Equal(t, "session", client.Token)
+	assert.Equal(t, 2, calls)
+}
+
+func TestClientReturnsServerError(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		calls++
+		writer.WriteHeader(http.StatusBadGateway)
+		_, _ = writer.Write([]byte(`{"error": "model not found", "code": "model_not_found"}`))
+	}))
+	defer server.Close()
+
+	client := New(server.URL)
+	client.RetryDelay = 0
+	_, err := client.CreateModel(context.Background())
+	var clientError *Error
+	assert.True(t, errors.As(err, &clientError))
+	assert.Equal(t, http.StatusBadGateway, clientError.StatusCode)
+	assert.Equal(t, "model not found", clientError.Message)
+	assert.Equal(t, "model_not_found", clientError.Code)
+	assert.Equal(t, 1, calls) // creating is not idempotent
+
+	_, err = client.GetModel(context.Background(), "id")
+	assert.Error(t, err)
+	assert.Equal(t, 1+1+DefaultRetries, calls)
+}
+
+func TestClientUploadsModel(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		assert.Equal(t, http.MethodPut, request.Method)
+		assert.Equal(t, "/models/id", request.URL.Path)
+		assert.Equal(t, "session", request.Header.Get("token"))
+		file, header, err := request.FormFile("file")
+		assert.NoError(t, err)
+		assert.Equal(t, "threagile.yaml", header.Filename)
+		data, _ := io.ReadAll(file)
+		assert.Equal(t, "title: test", string(data))
+		writer.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+
+	client := New(server.URL)
+	client.Token = "session"
+	assert.NoError(t, client.ImportModel(context.Background(), "id", "threagile.yaml", []byte("title: test")))
+}
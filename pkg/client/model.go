@@ -0,0 +1,159 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+// Model is an entry of the model list
+type Model struct {
+	ID                string    `json:"id"`
+	Title             string    `json:"title"`
+	TimestampCreated  time.Time `json:"timestamp_created"`
+	TimestampModified time.Time `json:"timestamp_modified"`
+	Error             string    `json:"error,omitempty"` // when the server can't read the model (anymore)
+}
+
+// CheckResult is the outcome of validating a model without storing it
+type CheckResult struct {
+	Message  string   `json:"message"`
+	Valid    bool     `json:"valid"`
+	Warnings []string `json:"warnings"`
+}
+
+// Artifact is a rendered output of a stored model
+type Artifact string
+
+const (
+	ReportPDF           Artifact = "report-pdf"
+	RisksExcel          Artifact = "risks-excel"
+	TagsExcel           Artifact = "tags-excel"
+	DataFlowDiagram     Artifact = "data-flow-diagram"
+	DataAssetDiagram    Artifact = "data-asset-diagram"
+	DataFlowDiagramDOT  Artifact = "data-flow-diagram.gv"
+	DataAssetDiagramDOT Artifact = "data-asset-diagram.gv"
+	RiskMatrix          Artifact = "risk-matrix.png"
+	AnalysisResult      Artifact = "analysis" // zip of all the outputs
+)
+
+// CreateModel creates an empty model and returns its id
+func (c *Client) CreateModel(ctx context.Context) (string, error) {
+	body, err := c.do(ctx, request{method: http.MethodPost, path: "/models", header: c.tokenHeader()})
+	if err != nil {
+		return "", err
+	}
+	var payload struct {
+		ID string `json:"id"`
+	}
+	err = json.Unmarshal(body, &payload)
+	if err != nil || len(payload.ID) == 0 {
+		return "", fmt.Errorf("invalid response of /models: %v", err)
+	}
+	return payload.ID, nil
+}
+
+func (c *Client) ListModels(ctx context.Context) ([]Model, error) {
+	models := make([]Model, 0)
+	return models, c.getJSON(ctx, "/models", &models)
+}
+
+// GetModel returns the model file (yaml)
+func (c *Client) GetModel(ctx context.Context, modelID string) ([]byte, error) {
+	return c.do(ctx, request{method: http.MethodGet, path: "/models/" + url.PathEscape(modelID), header: c.tokenHeader()})
+}
+
+// ImportModel replaces the model by the given model file (yaml, json or a zip with the model and its images), the
+// server only stores it when it passes the analysis
+func (c *Client) ImportModel(ctx context.Context, modelID string, filename string, data []byte) error {
+	call, err := uploadRequest(http.MethodPut, "/models/"+url.PathEscape(modelID), filename, data)
+	if err != nil {
+		return err
+	}
+	call.header = c.tokenHeader()
+	_, err = c.do(ctx, call)
+	return err
+}
+
+func (c *Client) DeleteModel(ctx context.Context, modelID string) error {
+	_, err := c.do(ctx, request{method: http.MethodDelete, path: "/models/" + url.PathEscape(modelID), header: c.tokenHeader()})
+	return err
+}
+
+// Check validates a model file without storing it
+func (c *Client) Check(ctx context.Context, filename string, data []byte) (*CheckResult, error) {
+	call, err := uploadRequest(http.MethodPost, "/direct/check", filename, data)
+	if err != nil {
+		return nil, err
+	}
+	body, err := c.do(ctx, call)
+	if err != nil {
+		return nil, err
+	}
+	result := new(CheckResult)
+	err = json.Unmarshal(body, result)
+	if err != nil {
+		return nil, fmt.Errorf("invalid response of /direct/check: %w", err)
+	}
+	return result, nil
+}
+
+// Analyze analyzes a model file without storing it and returns the zip of all the outputs, a dpi of 0 uses the default
+func (c *Client) Analyze(ctx context.Context, filename string, data []byte, dpi int) ([]byte, error) {
+	call, err := uploadRequest(http.MethodPost, "/direct/analyze"+dpiQuery(dpi), filename, data)
+	if err != nil {
+		return nil, err
+	}
+	return c.do(ctx, call)
+}
+
+// Download returns an artifact of the stored model, a dpi of 0 uses the default
+func (c *Client) Download(ctx context.Context, modelID string, artifact Artifact, dpi int) ([]byte, error) {
+	return c.do(ctx, request{method: http.MethodGet, path: "/models/" + url.PathEscape(modelID) + "/" + string(artifact) + dpiQuery(dpi), header: c.tokenHeader()})
+}
+
+func (c *Client) Risks(ctx context.Context, modelID string) ([]*types.Risk, error) {
+	risks := make([]*types.Risk, 0)
+	return risks, c.getJSON(ctx, "/models/"+url.PathEscape(modelID)+"/risks", &risks)
+}
+
+func (c *Client) RiskStatistics(ctx context.Context, modelID string) (*types.RiskStatistics, error) {
+	statistics := new(types.RiskStatistics)
+	return statistics, c.getJSON(ctx, "/models/"+url.PathEscape(modelID)+"/stats", statistics)
+}
+
+func (c *Client) TechnicalAssets(ctx context.Context, modelID string) (map[string]*types.TechnicalAsset, error) {
+	assets := make(map[string]*types.TechnicalAsset)
+	return assets, c.getJSON(ctx, "/models/"+url.PathEscape(modelID)+"/technical-assets", &assets)
+}
+
+func uploadRequest(method string, path string, filename string, data []byte) (request, error) {
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	part, err := writer.CreateFormFile("file", filename)
+	if err == nil {
+		_, err = part.Write(data)
+	}
+	if err == nil {
+		err = writer.Close()
+	}
+	if err != nil {
+		return request{}, fmt.Errorf("unable to prepare upload of %q: %w", filename, err)
+	}
+	return request{method: method, path: path, body: body.Bytes(), contentType: writer.FormDataContentType()}, nil
+}
+
+func dpiQuery(dpi int) string {
+	if dpi <= 0 {
+		return ""
+	}
+	return "?dpi=" + strconv.Itoa(dpi)
+}
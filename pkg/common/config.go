@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
 	"runtime"
 	"strings"
 )
@@ -15,6 +16,8 @@ type Config struct {
 	Verbose        bool
 	Interactive    bool
 
+	LogRedactionPatterns []string // regular expressions of sensitive data masked in the log output in addition to the default ones (masking only the group named secret, if any)
+
 	AppFolder    string
 	PluginFolder string
 	DataFolder   string
@@ -28,31 +31,103 @@ type Config struct {
 	DataAssetDiagramFilenamePNG string
 	DataFlowDiagramFilenameDOT  string
 	DataAssetDiagramFilenameDOT string
+	DiffDiagramFilenameDOT      string
+	DiffDiagramFilenamePNG      string
+	DiffDiagramFilenameSVG      string
 	ReportFilename              string
 	ExcelRisksFilename          string
 	ExcelTagsFilename           string
+	ExcelWorkbookFilename       string
+	AssetSheetFilename          string
+	AssetSheetHTMLFilename      string
+
+	MitigationChecklistMarkdownFilename string
+	MitigationChecklistCSVFilename      string
+
 	JsonRisksFilename           string
 	JsonTechnicalAssetsFilename string
 	JsonStatsFilename           string
+	JsonRuleFailuresFilename    string
+	JsonExposureFilename        string
+	SarifRisksFilename          string
+	OtmFilename                 string
+	IcsDueDatesFilename         string
+	FailureFilename             string
+	WarningsFilename            string // non-fatal warnings of the run, e.g. unused tags or orphaned risk tracking
+	CheckpointFilename          string // completed generation stages, to resume a failed generation
+	RulesDocMarkdownFilename    string
+	RulesDocHTMLFilename        string
+	RiskMatrixFilenamePNG       string
+	RiskMatrixFilenameSVG       string
 	TemplateFilename            string
 	TechnologyFilename          string
-
-	RAAPlugin         string
-	RiskRulesPlugins  []string
-	SkipRiskRules     []string
-	ExecuteModelMacro string
-	RiskExcel         RiskExcelConfig
+	TagTaxonomyFilename         string
+
+	RAAPlugin                 string
+	OwnerDirectoryPlugin      string
+	OwnerDirectoryStrict      bool
+	StrictRules               bool
+	RiskRulesPlugins          []string
+	RiskRulesRegoFolder       string   // OPA/Rego policies defining custom risk rules
+	RiskRulesScripts          []string // JavaScript modules defining custom risk rules, run by the embedded JavaScript interpreter
+	ReportSectionPlugins      []string
+	SkipRiskRules             []string
+	OnlyRiskRules             []string // risk rules (wildcards allowed) to run exclusively, all of them if empty
+	RiskCategoryOverridesFile string
+	ExecuteModelMacro         string
+	ExecuteModelMacroAnswers  string // answers of the macro questions to execute it without prompts
+	RiskExcel                 RiskExcelConfig
+	RiskMerge                 RiskMergeConfig
+	ThreatIntelFeed           string
+	ThreatIntelCacheHours     int
+	SeverityRecalibration     map[string]int // severity levels to shift risks by, keyed by criticality of their trust boundary (or business criticality)
+	PreviousRisksFile         string         // risks json of the previous assessment to report the changes since
+	FailOnRisk                string         // risk gate like "severity=high,status=unchecked" failing the analysis when risks match it
+	PolicyGateRegoFolder      string         // OPA/Rego policies (package threagile.gate) whose deny rules fail the analysis
+	ServiceMetadataURLs       []string       // metadata endpoints of running services declaring their dependencies
+	StructurizrWorkspace      string         // Structurizr workspace API url (or exported json file) to synchronize containers and relationships from
+	StructurizrMapping        string         // file mapping the Structurizr containers to technical assets, only mapped ones are synchronized
+	StructurizrAPIKey         string         // API key and secret signing the requests of the Structurizr workspace API
+	StructurizrAPISecret      string         // (see StructurizrAPIKey), no flag but environment variable THREAGILE_STRUCTURIZR_API_SECRET
+	ScannerFindingsFiles      []string       // SARIF or DefectDojo json of vulnerability scanners, correlated with the modeled risks
+	RiskCommentsFile          string         // json of the comments of the risks by synthetic risk id (as stored by the server), shown with the risks
+	SBOMFetchURLs             bool           // fetches the SBOMs of technical assets given as http(s) URL, never done for the models of the server
+	ModelTemplating           string         // expands the model files before parsing them: env for ${VAR} or go-template
+	PreParseHooks             []string       // commands run before parsing the model file and each include, which may replace their data
+	PostAnalysisHooks         []string       // commands run after analyzing the model and writing the reports
+
+	PluginTimeoutSeconds   int // limit of each call of a plugin (RAA, owner directory, custom rules and report sections)
+	AnalysisTimeoutSeconds int // limit of reading and analyzing the model
+	RenderTimeoutSeconds   int // limit of generating the diagrams and reports
 
 	ServerMode               bool
 	DiagramDPI               int
+	DiagramMaxPixels         int      // auto-scales the DPI of the diagrams (up to MaxGraphvizDPI) to keep their images below this many pixels, 0 renders all with DiagramDPI
+	DiagramTheme             string   // built-in diagram theme or yaml file with a custom one
+	DiagramVariants          []string // built-in diagram themes to additionally render the diagrams in
+	FontFile                 string   // ttf font used in the diagrams and reports instead of the bundled one, e.g. for CJK
 	ServerPort               int
 	GraphvizDPI              int
 	MaxGraphvizDPI           int
 	BackupHistoryFilesToKeep int
+	TempWorkspaceTTLMinutes  int        // age after which the server removes temp workspaces left behind (e.g. by crashed renders)
+	ServerStorage            string     // where the server keeps keys and models, in the server folder or only in memory
+	FileSystem               FileSystem `json:"-"` // injected by programs embedding Threagile and tests, the one of the OS if nil (see FS)
+	MaxAnalysesPerHour       int        // per key (shared by its tokens) of renderings not served from stored results, 0 is unlimited
+	MaxModelsPerKey          int        // 0 is unlimited
+	ValidateModelOnWrite     bool       // rejects changes of the server API resulting in models the analysis fails for
+	ServerAdminKey           string     // of the admin endpoints (like the dashboard across all keys) as header admin-key, empty disables them; or environment variable THREAGILE_SERVER_ADMIN_KEY
+	TemplatesFolder          string     // of model files offered by the server as templates of new models (by file name)
+	TemplatesIndexURL        string     // json array of templates (id, title, description and url of the model file) offered by the server
+	WebhookURLs              []string   // receive a json summary when an analysis of a stored model completes
+	WebhookSecret            string     // HMAC-SHA256 key signing the timestamp and body of the webhooks (header X-Threagile-Signature), empty sends them unsigned
+	WebhookNewCriticalOnly   bool       // only notify when critical risks appear which the previous analysis of the model did not have
+	Tenants                  []Tenant
 
 	AddModelTitle              bool
 	KeepDiagramSourceFiles     bool
 	IgnoreOrphanedRiskTracking bool
+	AutoSeedTagsAvailable      bool
 
 	Attractiveness Attractiveness
 }
@@ -63,12 +138,31 @@ type RiskExcelConfig struct {
 	WidthOfColumns map[string]float64
 }
 
+// Tenant isolates the keys and models of a business unit on a shared server: its keys are stored below a folder of
+// their own and are created with the Secret (as header tenant-secret), afterwards the tenant is derived from the key
+type Tenant struct {
+	Name               string // folder name of the tenant below the server folder
+	Secret             string
+	MaxAnalysesPerHour int    // per key, overriding the one of the server when > 0
+	MaxModelsPerKey    int    // overriding the one of the server when > 0
+	MasterKey          string // optionally mixed into the encryption of the models, set it before creating keys of the tenant
+}
+
+// RiskMergeConfig defines which risk categories overlap (Groups, by group name) and which
+// elements of a risk (Keys, e.g. "technical_asset") must be equal for risks to be merged
+type RiskMergeConfig struct {
+	Groups map[string][]string
+	Keys   []string
+}
+
 func (c *Config) Defaults(buildTimestamp string) *Config {
 	*c = Config{
 		BuildTimestamp: buildTimestamp,
 		Verbose:        false,
 		Interactive:    false,
 
+		LogRedactionPatterns: make([]string, 0),
+
 		AppFolder:    AppDir,
 		PluginFolder: PluginDir,
 		DataFolder:   DataDir,
@@ -82,34 +176,108 @@ func (c *Config) Defaults(buildTimestamp string) *Config {
 		DataAssetDiagramFilenamePNG: DataAssetDiagramFilenamePNG,
 		DataFlowDiagramFilenameDOT:  DataFlowDiagramFilenameDOT,
 		DataAssetDiagramFilenameDOT: DataAssetDiagramFilenameDOT,
+		DiffDiagramFilenameDOT:      DiffDiagramFilenameDOT,
+		DiffDiagramFilenamePNG:      DiffDiagramFilenamePNG,
+		DiffDiagramFilenameSVG:      DiffDiagramFilenameSVG,
 		ReportFilename:              ReportFilename,
 		ExcelRisksFilename:          ExcelRisksFilename,
 		ExcelTagsFilename:           ExcelTagsFilename,
+		ExcelWorkbookFilename:       ExcelWorkbookFilename,
+		AssetSheetFilename:          AssetSheetFilename,
+		AssetSheetHTMLFilename:      AssetSheetHTMLFilename,
+
+		MitigationChecklistMarkdownFilename: MitigationChecklistMarkdownFilename,
+		MitigationChecklistCSVFilename:      MitigationChecklistCSVFilename,
+
 		JsonRisksFilename:           JsonRisksFilename,
 		JsonTechnicalAssetsFilename: JsonTechnicalAssetsFilename,
 		JsonStatsFilename:           JsonStatsFilename,
+		JsonRuleFailuresFilename:    JsonRuleFailuresFilename,
+		JsonExposureFilename:        JsonExposureFilename,
+		SarifRisksFilename:          SarifRisksFilename,
+		OtmFilename:                 OtmFilename,
+		IcsDueDatesFilename:         IcsDueDatesFilename,
+		FailureFilename:             FailureFilename,
+		WarningsFilename:            WarningsFilename,
+		CheckpointFilename:          CheckpointFilename,
+		RulesDocMarkdownFilename:    RulesDocMarkdownFilename,
+		RulesDocHTMLFilename:        RulesDocHTMLFilename,
+		RiskMatrixFilenamePNG:       RiskMatrixFilenamePNG,
+		RiskMatrixFilenameSVG:       RiskMatrixFilenameSVG,
 		TemplateFilename:            TemplateFilename,
 		TechnologyFilename:          "",
-
-		RAAPlugin:         RAAPluginName,
-		RiskRulesPlugins:  make([]string, 0),
-		SkipRiskRules:     make([]string, 0),
-		ExecuteModelMacro: "",
+		TagTaxonomyFilename:         "",
+
+		RAAPlugin:                 RAAPluginName,
+		OwnerDirectoryPlugin:      "",
+		OwnerDirectoryStrict:      false,
+		StrictRules:               false,
+		RiskRulesPlugins:          make([]string, 0),
+		RiskRulesRegoFolder:       "",
+		RiskRulesScripts:          make([]string, 0),
+		ReportSectionPlugins:      make([]string, 0),
+		SkipRiskRules:             make([]string, 0),
+		OnlyRiskRules:             make([]string, 0),
+		RiskCategoryOverridesFile: "",
+		ExecuteModelMacro:         "",
+		ExecuteModelMacroAnswers:  "",
 		RiskExcel: RiskExcelConfig{
 			HideColumns:   make([]string, 0),
 			SortByColumns: make([]string, 0),
 		},
+		RiskMerge: RiskMergeConfig{
+			Groups: make(map[string][]string),
+			Keys:   []string{"technical_asset", "communication_link"},
+		},
+		ThreatIntelFeed:       "",
+		ThreatIntelCacheHours: DefaultThreatIntelCacheHours,
+		SeverityRecalibration: make(map[string]int),
+		ServiceMetadataURLs:   make([]string, 0),
+		StructurizrWorkspace:  "",
+		StructurizrMapping:    "",
+		StructurizrAPIKey:     "",
+		StructurizrAPISecret:  "",
+		ScannerFindingsFiles:  make([]string, 0),
+		RiskCommentsFile:      "",
+		SBOMFetchURLs:         false,
+		ModelTemplating:       "",
+		PreParseHooks:         make([]string, 0),
+		PostAnalysisHooks:     make([]string, 0),
+		PreviousRisksFile:     "",
+		FailOnRisk:            "",
+		PolicyGateRegoFolder:  "",
+
+		PluginTimeoutSeconds:   DefaultPluginTimeoutSeconds,
+		AnalysisTimeoutSeconds: DefaultAnalysisTimeoutSeconds,
+		RenderTimeoutSeconds:   DefaultRenderTimeoutSeconds,
 
 		ServerMode:               false,
 		DiagramDPI:               DefaultDiagramDPI,
+		DiagramMaxPixels:         0,
+		DiagramTheme:             "",
+		DiagramVariants:          make([]string, 0),
+		FontFile:                 "",
 		ServerPort:               DefaultServerPort,
 		GraphvizDPI:              DefaultGraphvizDPI,
 		MaxGraphvizDPI:           MaxGraphvizDPI,
 		BackupHistoryFilesToKeep: DefaultBackupHistoryFilesToKeep,
+		TempWorkspaceTTLMinutes:  DefaultTempWorkspaceTTLMinutes,
+		ServerStorage:            ServerStorageFile,
+		MaxAnalysesPerHour:       0,
+		MaxModelsPerKey:          0,
+		ValidateModelOnWrite:     false,
+		ServerAdminKey:           "",
+		TemplatesFolder:          "",
+		TemplatesIndexURL:        "",
+		WebhookURLs:              make([]string, 0),
+		WebhookSecret:            "",
+		WebhookNewCriticalOnly:   false,
+		Tenants:                  make([]Tenant, 0),
 
 		AddModelTitle:              false,
 		KeepDiagramSourceFiles:     false,
 		IgnoreOrphanedRiskTracking: false,
+		AutoSeedTagsAvailable:      false,
 
 		Attractiveness: Attractiveness{
 			Quantity: 0,
@@ -191,6 +359,54 @@ func (c *Config) Load(configFilename string) error {
 
 	c.TechnologyFilename = c.CleanPath(c.TechnologyFilename)
 
+	if len(c.TagTaxonomyFilename) > 0 {
+		c.TagTaxonomyFilename = c.CleanPath(c.TagTaxonomyFilename)
+	}
+
+	if len(c.RiskCategoryOverridesFile) > 0 {
+		c.RiskCategoryOverridesFile = c.CleanPath(c.RiskCategoryOverridesFile)
+	}
+
+	if len(c.RiskRulesRegoFolder) > 0 {
+		c.RiskRulesRegoFolder = c.CleanPath(c.RiskRulesRegoFolder)
+	}
+
+	if len(c.PolicyGateRegoFolder) > 0 {
+		c.PolicyGateRegoFolder = c.CleanPath(c.PolicyGateRegoFolder)
+	}
+
+	for i, scriptFile := range c.RiskRulesScripts {
+		if len(scriptFile) > 0 {
+			c.RiskRulesScripts[i] = c.CleanPath(scriptFile)
+		}
+	}
+
+	for i, filename := range c.ScannerFindingsFiles {
+		if len(filename) > 0 {
+			c.ScannerFindingsFiles[i] = c.CleanPath(filename)
+		}
+	}
+
+	if len(c.RiskCommentsFile) > 0 {
+		c.RiskCommentsFile = c.CleanPath(c.RiskCommentsFile)
+	}
+
+	if len(c.ThreatIntelFeed) > 0 && !strings.Contains(c.ThreatIntelFeed, "://") {
+		c.ThreatIntelFeed = c.CleanPath(c.ThreatIntelFeed)
+	}
+
+	if len(c.PreviousRisksFile) > 0 {
+		c.PreviousRisksFile = c.CleanPath(c.PreviousRisksFile)
+	}
+
+	if len(c.StructurizrWorkspace) > 0 && !strings.Contains(c.StructurizrWorkspace, "://") {
+		c.StructurizrWorkspace = c.CleanPath(c.StructurizrWorkspace)
+	}
+
+	if len(c.StructurizrMapping) > 0 {
+		c.StructurizrMapping = c.CleanPath(c.StructurizrMapping)
+	}
+
 	serverFolderError := c.CheckServerFolder()
 	if serverFolderError != nil {
 		errorList = append(errorList, serverFolderError)
@@ -203,6 +419,30 @@ func (c *Config) Load(configFilename string) error {
 	return nil
 }
 
+// TenantKeyFolder is the folder of the key folders of the tenant, apart from the ones of the server and other tenants
+func (c *Config) TenantKeyFolder(tenant Tenant) string {
+	return filepath.Join(c.ServerFolder, TenantsDir, tenant.Name, c.KeyFolder)
+}
+
+var tenantNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+func (c *Config) checkTenants() error {
+	names, secrets := make(map[string]bool), make(map[string]bool)
+	for _, tenant := range c.Tenants {
+		if !tenantNamePattern.MatchString(tenant.Name) {
+			return fmt.Errorf("invalid tenant name %q (use lower case letters, digits, - and _)", tenant.Name)
+		}
+		if names[tenant.Name] {
+			return fmt.Errorf("duplicate tenant %q", tenant.Name)
+		}
+		if len(tenant.Secret) == 0 || secrets[tenant.Secret] {
+			return fmt.Errorf("tenant %q needs a secret of its own", tenant.Name)
+		}
+		names[tenant.Name], secrets[tenant.Secret] = true, true
+	}
+	return nil
+}
+
 func (c *Config) CheckServerFolder() error {
 	if c.ServerMode {
 		c.ServerFolder = c.CleanPath(c.ServerFolder)
@@ -211,9 +451,27 @@ func (c *Config) CheckServerFolder() error {
 			return serverDirError
 		}
 
-		keyDirError := os.MkdirAll(filepath.Join(c.ServerFolder, c.KeyFolder), 0700)
-		if keyDirError != nil {
-			return fmt.Errorf("failed to create key dir %q: %v", filepath.Join(c.ServerFolder, c.KeyFolder), keyDirError)
+		tenantsError := c.checkTenants()
+		if tenantsError != nil {
+			return tenantsError
+		}
+
+		switch c.ServerStorage {
+		case "", ServerStorageFile:
+			keyDirs := []string{filepath.Join(c.ServerFolder, c.KeyFolder)}
+			for _, tenant := range c.Tenants {
+				keyDirs = append(keyDirs, c.TenantKeyFolder(tenant))
+			}
+			for _, keyDir := range keyDirs {
+				keyDirError := os.MkdirAll(keyDir, 0700)
+				if keyDirError != nil {
+					return fmt.Errorf("failed to create key dir %q: %v", keyDir, keyDirError)
+				}
+			}
+		case ServerStorageMemory:
+			// keys and models are kept in memory only
+		default:
+			return fmt.Errorf("unknown server storage %q (use %q or %q)", c.ServerStorage, ServerStorageFile, ServerStorageMemory)
 		}
 	}
 
@@ -226,6 +484,9 @@ func (c *Config) Merge(config Config, values map[string]any) {
 		case strings.ToLower("Verbose"):
 			c.Verbose = config.Verbose
 
+		case strings.ToLower("LogRedactionPatterns"):
+			c.LogRedactionPatterns = config.LogRedactionPatterns
+
 		case strings.ToLower("AppFolder"):
 			c.AppFolder = config.AppFolder
 
@@ -262,6 +523,15 @@ func (c *Config) Merge(config Config, values map[string]any) {
 		case strings.ToLower("DataAssetDiagramFilenameDOT"):
 			c.DataAssetDiagramFilenameDOT = config.DataAssetDiagramFilenameDOT
 
+		case strings.ToLower("DiffDiagramFilenameDOT"):
+			c.DiffDiagramFilenameDOT = config.DiffDiagramFilenameDOT
+
+		case strings.ToLower("DiffDiagramFilenamePNG"):
+			c.DiffDiagramFilenamePNG = config.DiffDiagramFilenamePNG
+
+		case strings.ToLower("DiffDiagramFilenameSVG"):
+			c.DiffDiagramFilenameSVG = config.DiffDiagramFilenameSVG
+
 		case strings.ToLower("ReportFilename"):
 			c.ReportFilename = config.ReportFilename
 
@@ -271,6 +541,21 @@ func (c *Config) Merge(config Config, values map[string]any) {
 		case strings.ToLower("ExcelTagsFilename"):
 			c.ExcelTagsFilename = config.ExcelTagsFilename
 
+		case strings.ToLower("ExcelWorkbookFilename"):
+			c.ExcelWorkbookFilename = config.ExcelWorkbookFilename
+
+		case strings.ToLower("AssetSheetFilename"):
+			c.AssetSheetFilename = config.AssetSheetFilename
+
+		case strings.ToLower("AssetSheetHTMLFilename"):
+			c.AssetSheetHTMLFilename = config.AssetSheetHTMLFilename
+
+		case strings.ToLower("MitigationChecklistMarkdownFilename"):
+			c.MitigationChecklistMarkdownFilename = config.MitigationChecklistMarkdownFilename
+
+		case strings.ToLower("MitigationChecklistCSVFilename"):
+			c.MitigationChecklistCSVFilename = config.MitigationChecklistCSVFilename
+
 		case strings.ToLower("JsonRisksFilename"):
 			c.JsonRisksFilename = config.JsonRisksFilename
 
@@ -280,18 +565,75 @@ func (c *Config) Merge(config Config, values map[string]any) {
 		case strings.ToLower("JsonStatsFilename"):
 			c.JsonStatsFilename = config.JsonStatsFilename
 
+		case strings.ToLower("JsonRuleFailuresFilename"):
+			c.JsonRuleFailuresFilename = config.JsonRuleFailuresFilename
+
+		case strings.ToLower("JsonExposureFilename"):
+			c.JsonExposureFilename = config.JsonExposureFilename
+
+		case strings.ToLower("SarifRisksFilename"):
+			c.SarifRisksFilename = config.SarifRisksFilename
+
+		case strings.ToLower("OtmFilename"):
+			c.OtmFilename = config.OtmFilename
+
+		case strings.ToLower("IcsDueDatesFilename"):
+			c.IcsDueDatesFilename = config.IcsDueDatesFilename
+
+		case strings.ToLower("FailureFilename"):
+			c.FailureFilename = config.FailureFilename
+
+		case strings.ToLower("WarningsFilename"):
+			c.WarningsFilename = config.WarningsFilename
+
+		case strings.ToLower("CheckpointFilename"):
+			c.CheckpointFilename = config.CheckpointFilename
+
+		case strings.ToLower("RulesDocMarkdownFilename"):
+			c.RulesDocMarkdownFilename = config.RulesDocMarkdownFilename
+
+		case strings.ToLower("RulesDocHTMLFilename"):
+			c.RulesDocHTMLFilename = config.RulesDocHTMLFilename
+
+		case strings.ToLower("RiskMatrixFilenamePNG"):
+			c.RiskMatrixFilenamePNG = config.RiskMatrixFilenamePNG
+
+		case strings.ToLower("RiskMatrixFilenameSVG"):
+			c.RiskMatrixFilenameSVG = config.RiskMatrixFilenameSVG
+
 		case strings.ToLower("TemplateFilename"):
 			c.TemplateFilename = config.TemplateFilename
 
 		case strings.ToLower("TechnologyFilename"):
 			c.TechnologyFilename = config.TechnologyFilename
 
+		case strings.ToLower("TagTaxonomyFilename"):
+			c.TagTaxonomyFilename = config.TagTaxonomyFilename
+
 		case strings.ToLower("RAAPlugin"):
 			c.RAAPlugin = config.RAAPlugin
 
+		case strings.ToLower("OwnerDirectoryPlugin"):
+			c.OwnerDirectoryPlugin = config.OwnerDirectoryPlugin
+
+		case strings.ToLower("OwnerDirectoryStrict"):
+			c.OwnerDirectoryStrict = config.OwnerDirectoryStrict
+
+		case strings.ToLower("StrictRules"):
+			c.StrictRules = config.StrictRules
+
 		case strings.ToLower("RiskRulesPlugins"):
 			c.RiskRulesPlugins = config.RiskRulesPlugins
 
+		case strings.ToLower("RiskRulesRegoFolder"):
+			c.RiskRulesRegoFolder = config.RiskRulesRegoFolder
+
+		case strings.ToLower("RiskRulesScripts"):
+			c.RiskRulesScripts = config.RiskRulesScripts
+
+		case strings.ToLower("ReportSectionPlugins"):
+			c.ReportSectionPlugins = config.ReportSectionPlugins
+
 		case strings.ToLower("RiskExcel"):
 			configMap, mapOk := values[key].(map[string]any)
 			if !mapOk {
@@ -317,15 +659,118 @@ func (c *Config) Merge(config Config, values map[string]any) {
 				}
 			}
 
+		case strings.ToLower("RiskMerge"):
+			configMap, mapOk := values[key].(map[string]any)
+			if !mapOk {
+				continue
+			}
+
+			for valueName := range configMap {
+				switch strings.ToLower(valueName) {
+				case strings.ToLower("Groups"):
+					if c.RiskMerge.Groups == nil {
+						c.RiskMerge.Groups = make(map[string][]string)
+					}
+
+					for name, value := range config.RiskMerge.Groups {
+						c.RiskMerge.Groups[name] = value
+					}
+
+				case strings.ToLower("Keys"):
+					c.RiskMerge.Keys = config.RiskMerge.Keys
+				}
+			}
+
+		case strings.ToLower("ThreatIntelFeed"):
+			c.ThreatIntelFeed = config.ThreatIntelFeed
+
+		case strings.ToLower("ThreatIntelCacheHours"):
+			c.ThreatIntelCacheHours = config.ThreatIntelCacheHours
+
+		case strings.ToLower("ServiceMetadataURLs"):
+			c.ServiceMetadataURLs = config.ServiceMetadataURLs
+
+		case strings.ToLower("StructurizrWorkspace"):
+			c.StructurizrWorkspace = config.StructurizrWorkspace
+
+		case strings.ToLower("StructurizrMapping"):
+			c.StructurizrMapping = config.StructurizrMapping
+
+		case strings.ToLower("StructurizrAPIKey"):
+			c.StructurizrAPIKey = config.StructurizrAPIKey
+
+		case strings.ToLower("StructurizrAPISecret"):
+			c.StructurizrAPISecret = config.StructurizrAPISecret
+
+		case strings.ToLower("ScannerFindingsFiles"):
+			c.ScannerFindingsFiles = config.ScannerFindingsFiles
+
+		case strings.ToLower("RiskCommentsFile"):
+			c.RiskCommentsFile = config.RiskCommentsFile
+
+		case strings.ToLower("SBOMFetchURLs"):
+			c.SBOMFetchURLs = config.SBOMFetchURLs
+
+		case strings.ToLower("ModelTemplating"):
+			c.ModelTemplating = config.ModelTemplating
+
+		case strings.ToLower("PreParseHooks"):
+			c.PreParseHooks = config.PreParseHooks
+
+		case strings.ToLower("PostAnalysisHooks"):
+			c.PostAnalysisHooks = config.PostAnalysisHooks
+
+		case strings.ToLower("PluginTimeoutSeconds"):
+			c.PluginTimeoutSeconds = config.PluginTimeoutSeconds
+
+		case strings.ToLower("AnalysisTimeoutSeconds"):
+			c.AnalysisTimeoutSeconds = config.AnalysisTimeoutSeconds
+
+		case strings.ToLower("RenderTimeoutSeconds"):
+			c.RenderTimeoutSeconds = config.RenderTimeoutSeconds
+
+		case strings.ToLower("PreviousRisksFile"):
+			c.PreviousRisksFile = config.PreviousRisksFile
+
+		case strings.ToLower("FailOnRisk"):
+			c.FailOnRisk = config.FailOnRisk
+
+		case strings.ToLower("PolicyGateRegoFolder"):
+			c.PolicyGateRegoFolder = config.PolicyGateRegoFolder
+
+		case strings.ToLower("SeverityRecalibration"):
+			c.SeverityRecalibration = config.SeverityRecalibration
+
 		case strings.ToLower("SkipRiskRules"):
 			c.SkipRiskRules = config.SkipRiskRules
 
+		case strings.ToLower("OnlyRiskRules"):
+			c.OnlyRiskRules = config.OnlyRiskRules
+
+		case strings.ToLower("RiskCategoryOverridesFile"):
+			c.RiskCategoryOverridesFile = config.RiskCategoryOverridesFile
+
 		case strings.ToLower("ExecuteModelMacro"):
 			c.ExecuteModelMacro = config.ExecuteModelMacro
 
+		case strings.ToLower("ExecuteModelMacroAnswers"):
+			c.ExecuteModelMacroAnswers = config.ExecuteModelMacroAnswers
+
 		case strings.ToLower("DiagramDPI"):
 			c.DiagramDPI = config.DiagramDPI
 
+		case strings.ToLower("DiagramMaxPixels"):
+			c.DiagramMaxPixels = config.DiagramMaxPixels
+
+		case strings.ToLower("DiagramTheme"):
+			c.DiagramTheme = config.DiagramTheme
+
+		case strings.ToLower("DiagramVariants"):
+			c.DiagramVariants = config.DiagramVariants
+
+		case strings.ToLower("FontFile"):
+			c.FontFile = config.FontFile
+
 		case strings.ToLower("ServerPort"):
 			c.ServerPort = config.ServerPort
 
@@ -338,6 +783,42 @@ func (c *Config) Merge(config Config, values map[string]any) {
 		case strings.ToLower("BackupHistoryFilesToKeep"):
 			c.BackupHistoryFilesToKeep = config.BackupHistoryFilesToKeep
 
+		case strings.ToLower("TempWorkspaceTTLMinutes"):
+			c.TempWorkspaceTTLMinutes = config.TempWorkspaceTTLMinutes
+
+		case strings.ToLower("ServerStorage"):
+			c.ServerStorage = config.ServerStorage
+
+		case strings.ToLower("MaxAnalysesPerHour"):
+			c.MaxAnalysesPerHour = config.MaxAnalysesPerHour
+
+		case strings.ToLower("MaxModelsPerKey"):
+			c.MaxModelsPerKey = config.MaxModelsPerKey
+
+		case strings.ToLower("ValidateModelOnWrite"):
+			c.ValidateModelOnWrite = config.ValidateModelOnWrite
+
+		case strings.ToLower("ServerAdminKey"):
+			c.ServerAdminKey = config.ServerAdminKey
+
+		case strings.ToLower("TemplatesFolder"):
+			c.TemplatesFolder = config.TemplatesFolder
+
+		case strings.ToLower("TemplatesIndexURL"):
+			c.TemplatesIndexURL = config.TemplatesIndexURL
+
+		case strings.ToLower("WebhookURLs"):
+			c.WebhookURLs = config.WebhookURLs
+
+		case strings.ToLower("WebhookSecret"):
+			c.WebhookSecret = config.WebhookSecret
+
+		case strings.ToLower("WebhookNewCriticalOnly"):
+			c.WebhookNewCriticalOnly = config.WebhookNewCriticalOnly
+
+		case strings.ToLower("Tenants"):
+			c.Tenants = config.Tenants
+
 		case strings.ToLower("AddModelTitle"):
 			c.AddModelTitle = config.AddModelTitle
 
@@ -347,6 +828,9 @@ func (c *Config) Merge(config Config, values map[string]any) {
 		case strings.ToLower("IgnoreOrphanedRiskTracking"):
 			c.IgnoreOrphanedRiskTracking = config.IgnoreOrphanedRiskTracking
 
+		case strings.ToLower("AutoSeedTagsAvailable"):
+			c.AutoSeedTagsAvailable = config.AutoSeedTagsAvailable
+
 		case strings.ToLower("Attractiveness"):
 			c.Attractiveness = config.Attractiveness
 		}
@@ -1,13 +1,14 @@
 package common
 
 const (
-	TempDir   = "/dev/shm" // TODO: make configurable via cmdline arg?
-	AppDir    = "/app"
-	PluginDir = "/app"
-	DataDir   = "/data"
-	OutputDir = "."
-	ServerDir = "/server"
-	KeyDir    = "keys"
+	TempDir    = "/dev/shm" // TODO: make configurable via cmdline arg?
+	AppDir     = "/app"
+	PluginDir  = "/app"
+	DataDir    = "/data"
+	OutputDir  = "."
+	ServerDir  = "/server"
+	KeyDir     = "keys"
+	TenantsDir = "tenants"
 
 	DefaultServerPort = 8080
 
@@ -15,14 +16,35 @@ const (
 	ReportFilename              = "report.pdf"
 	ExcelRisksFilename          = "risks.xlsx"
 	ExcelTagsFilename           = "tags.xlsx"
+	ExcelWorkbookFilename       = "workbook.xlsx"
+	AssetSheetFilename          = "asset-sheet.pdf"
+	AssetSheetHTMLFilename      = "asset-sheet.html"
 	JsonRisksFilename           = "risks.json"
 	JsonTechnicalAssetsFilename = "technical-assets.json"
 	JsonStatsFilename           = "stats.json"
+	JsonRuleFailuresFilename    = "rule-failures.json"
+	JsonExposureFilename        = "exposure.json"
+	SarifRisksFilename          = "risks.sarif"
+	OtmFilename                 = "threat-model.otm.json"
+	IcsDueDatesFilename         = "due-dates.ics"
+	FailureFilename             = "failure.json"
+	WarningsFilename            = "warnings.json"
+	CheckpointFilename          = "generation-checkpoint.json"
+	RulesDocMarkdownFilename    = "risk-rules.md"
+	RulesDocHTMLFilename        = "risk-rules.html"
+	RiskMatrixFilenamePNG       = "risk-matrix.png"
+	RiskMatrixFilenameSVG       = "risk-matrix.svg"
 	TemplateFilename            = "background.pdf"
 	DataFlowDiagramFilenameDOT  = "data-flow-diagram.gv"
 	DataFlowDiagramFilenamePNG  = "data-flow-diagram.png"
 	DataAssetDiagramFilenameDOT = "data-asset-diagram.gv"
 	DataAssetDiagramFilenamePNG = "data-asset-diagram.png"
+	DiffDiagramFilenameDOT      = "data-flow-diagram-diff.gv"
+	DiffDiagramFilenamePNG      = "data-flow-diagram-diff.png"
+	DiffDiagramFilenameSVG      = "data-flow-diagram-diff.svg"
+
+	MitigationChecklistMarkdownFilename = "mitigation-checklist.md"
+	MitigationChecklistCSVFilename      = "mitigation-checklist.csv"
 
 	RAAPluginName = "raa_calc"
 
@@ -31,20 +53,50 @@ const (
 	MinGraphvizDPI                  = 20
 	MaxGraphvizDPI                  = 300
 	DefaultBackupHistoryFilesToKeep = 50
+	DefaultThreatIntelCacheHours    = 24
+	DefaultTempWorkspaceTTLMinutes  = 120
+
+	DefaultPluginTimeoutSeconds   = 60
+	DefaultAnalysisTimeoutSeconds = 600
+	DefaultRenderTimeoutSeconds   = 600
+)
+
+const (
+	ServerStorageFile   = "file"
+	ServerStorageMemory = "memory"
 )
 
 const (
-	AnalyzeModelCommand         = "analyze-model"
-	CreateExampleModelCommand   = "create-example-model"
-	CreateStubModelCommand      = "create-stub-model"
-	CreateEditingSupportCommand = "create-editing-support"
-	ListTypesCommand            = "list-types"
-	ListRiskRulesCommand        = "list-risk-rules"
-	ListModelMacrosCommand      = "list-model-macros"
-	Print3rdPartyCommand        = "print-3rd-party-licenses"
-	PrintLicenseCommand         = "print-license"
+	AnalyzeModelCommand          = "analyze-model"
+	AnalyzeAllCommand            = "analyze-all"
+	ScanAnnotationsCommand       = "scan-annotations"
+	ImportServiceMetadataCommand = "import-service-metadata"
+	SyncStructurizrCommand       = "sync-structurizr"
+	ImportDrawIOCommand          = "import-drawio"
+	ImportOpenAPICommand         = "import-openapi"
+	ImportTerraformCommand       = "import-terraform"
+	ImportOTMCommand             = "import-otm"
+	ExportOTMCommand             = "export-otm"
+	DiffDiagramCommand           = "diff-diagram"
+	DiffCommand                  = "diff"
+	ValidateModelCommand         = "validate-model"
+	LanguageServerCommand        = "lsp"
+	CreateExampleModelCommand    = "create-example-model"
+	CreateStubModelCommand       = "create-stub-model"
+	InitModelCommand             = "init"
+	CreateEditingSupportCommand  = "create-editing-support"
+	ListTypesCommand             = "list-types"
+	ListRiskRulesCommand         = "list-risk-rules"
+	ListModelMacrosCommand       = "list-model-macros"
+	ExplainRuleCommand           = "explain-rule"
+	ExecuteModelMacroCommand     = "execute-model-macro"
+	Print3rdPartyCommand         = "print-3rd-party-licenses"
+	PrintLicenseCommand          = "print-license"
 
+	BundleCommand       = "bundle"
+	CompletionCommand   = "completion"
 	CreateCommand       = "create"
+	DoctorCommand       = "doctor"
 	ExplainCommand      = "explain"
 	ListCommand         = "list"
 	PrintCommand        = "print"
@@ -59,6 +111,7 @@ const (
 	LicenseItem        = "license"
 	MacrosItem         = "macros"
 	ModelItem          = "model"
+	PatternsItem       = "patterns"
 	RiskItem           = "risk"
 	RulesItem          = "rules"
 	StubItem           = "stub"
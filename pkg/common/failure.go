@@ -0,0 +1,125 @@
+package common
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+)
+
+// ExitCode tells scripts and pipelines which stage of a run failed
+type ExitCode int
+
+const (
+	ExitCodeFailure           ExitCode = 1 // any failure not covered below (e.g. invalid arguments, missing plugins)
+	ExitCodeParseError        ExitCode = 2 // the model is no valid yaml or does not match the schema
+	ExitCodeValidationError   ExitCode = 3 // the model is inconsistent (e.g. unknown references or orphaned risk tracking)
+	ExitCodeRuleFailure       ExitCode = 4 // the risks could not be evaluated (e.g. invalid overrides or recalibration)
+	ExitCodeRenderFailure     ExitCode = 5 // diagrams or reports could not be generated
+	ExitCodePolicyGateFailure ExitCode = 6 // the analysis succeeded but the result violates a policy gate
+	ExitCodeTimeout           ExitCode = 7 // a phase or plugin exceeded its timeout or the run was canceled
+)
+
+func (what ExitCode) String() string {
+	switch what {
+	case ExitCodeParseError:
+		return "parse-error"
+	case ExitCodeValidationError:
+		return "validation-error"
+	case ExitCodeRuleFailure:
+		return "rule-failure"
+	case ExitCodeRenderFailure:
+		return "render-failure"
+	case ExitCodePolicyGateFailure:
+		return "policy-gate-failure"
+	case ExitCodeTimeout:
+		return "timeout"
+	}
+	return "failure"
+}
+
+// Failure is an error of a specific stage, determining the exit code of the run
+type Failure struct {
+	Code    ExitCode
+	Err     error
+	Details []string // e.g. the violations of a policy gate
+}
+
+func NewFailure(code ExitCode, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &Failure{Code: code, Err: err}
+}
+
+func (what *Failure) Error() string {
+	return what.Err.Error()
+}
+
+func (what *Failure) Unwrap() error {
+	return what.Err
+}
+
+// ExitCodeOf returns the exit code of the innermost failure wrapped in err, so that the stage which actually failed
+// wins over the ones only passing the error on
+func ExitCodeOf(err error) ExitCode {
+	failure := innermostFailure(err)
+	if failure != nil {
+		return failure.Code
+	}
+	return ExitCodeFailure
+}
+
+func innermostFailure(err error) *Failure {
+	var innermost *Failure
+	for err != nil {
+		if failure, ok := err.(*Failure); ok {
+			innermost = failure
+		}
+		if joined, ok := err.(interface{ Unwrap() []error }); ok {
+			for _, wrapped := range joined.Unwrap() {
+				if failure := innermostFailure(wrapped); failure != nil {
+					return failure
+				}
+			}
+			return innermost
+		}
+		err = errors.Unwrap(err)
+	}
+	return innermost
+}
+
+// FailureReport is the machine-readable description of a failed run, written as failure.json
+type FailureReport struct {
+	ExitCode int      `json:"exit_code"`
+	Kind     string   `json:"kind"`
+	Message  string   `json:"message"`
+	Details  []string `json:"details,omitempty"`
+}
+
+func WriteFailureReport(filename string, err error) error {
+	code := ExitCodeOf(err)
+	report := FailureReport{ExitCode: int(code), Kind: code.String(), Message: err.Error()}
+	if failure := innermostFailure(err); failure != nil {
+		report.Details = failure.Details
+	}
+	data, marshalError := json.MarshalIndent(report, "", "  ")
+	if marshalError != nil {
+		return marshalError
+	}
+	return os.WriteFile(filename, data, 0600)
+}
+
+// ReadFailureReport reads the failure.json of a run, e.g. of a sub-process, as failure again
+func ReadFailureReport(filename string) (*Failure, error) {
+	data, err := os.ReadFile(filepath.Clean(filename))
+	if err != nil {
+		return nil, err
+	}
+	var report FailureReport
+	err = json.Unmarshal(data, &report)
+	if err != nil {
+		return nil, err
+	}
+	return &Failure{Code: ExitCode(report.ExitCode), Err: errors.New(report.Message), Details: report.Details}, nil
+}
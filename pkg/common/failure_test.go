@@ -0,0 +1,31 @@
+package common
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExitCodeOfInnermostFailure(t *testing.T) {
+	assert.Equal(t, ExitCodeFailure, ExitCodeOf(errors.New("plain")))
+	assert.Equal(t, ExitCodeFailure, ExitCodeOf(nil))
+
+	timeout := NewFailure(ExitCodeTimeout, errors.New("plugin timed out"))
+	rendering := NewFailure(ExitCodeRenderFailure, fmt.Errorf("failed to generate reports: %w", timeout))
+	assert.Equal(t, ExitCodeTimeout, ExitCodeOf(rendering), "the stage which actually failed")
+	assert.Equal(t, ExitCodeRenderFailure, ExitCodeOf(NewFailure(ExitCodeRenderFailure, errors.New("no dot"))))
+
+	policy := &Failure{Code: ExitCodePolicyGateFailure, Err: errors.New("policy violated"), Details: []string{"no tls"}}
+	joined := fmt.Errorf("analysis failed: %w", errors.Join(errors.New("plain"), policy))
+	assert.Equal(t, ExitCodePolicyGateFailure, ExitCodeOf(NewFailure(ExitCodeRenderFailure, joined)))
+
+	filename := filepath.Join(t.TempDir(), "failure.json")
+	assert.NoError(t, WriteFailureReport(filename, NewFailure(ExitCodeRenderFailure, fmt.Errorf("wrapped: %w", policy))))
+	failure, err := ReadFailureReport(filename)
+	assert.NoError(t, err)
+	assert.Equal(t, ExitCodePolicyGateFailure, failure.Code)
+	assert.Equal(t, []string{"no tls"}, failure.Details)
+}
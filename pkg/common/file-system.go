@@ -0,0 +1,307 @@
+package common
+
+import (
+	"fmt"
+	"io/fs"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// FileSystem is where the model files are read from and the outputs (and the server storage) go to, the one of the OS
+// unless another one is injected via the config, e.g. in memory for tests; only graphviz renders its images in the temp
+// folder of the OS, from where they are copied
+type FileSystem interface {
+	Mkdir(path string, perm fs.FileMode) error
+	MkdirAll(path string, perm fs.FileMode) error
+	MkdirTemp(dir string, pattern string) (string, error)
+	Stat(path string) (fs.FileInfo, error)
+	ReadDir(path string) ([]fs.FileInfo, error) // sorted by name
+	ReadFile(path string) ([]byte, error)
+	WriteFile(path string, data []byte, perm fs.FileMode) error
+	Remove(path string) error
+	RemoveAll(path string) error
+	Glob(pattern string) ([]string, error)
+	Chtimes(path string, accessTime time.Time, modificationTime time.Time) error
+}
+
+// FS is the file system injected, or else the one of the OS
+func (c *Config) FS() FileSystem {
+	if c.FileSystem == nil {
+		return OSFileSystem{}
+	}
+	return c.FileSystem
+}
+
+// OSFileSystem is the file system of the OS
+type OSFileSystem struct{}
+
+func (OSFileSystem) Mkdir(path string, perm fs.FileMode) error {
+	return os.Mkdir(path, perm)
+}
+
+func (OSFileSystem) MkdirAll(path string, perm fs.FileMode) error {
+	return os.MkdirAll(path, perm)
+}
+
+func (OSFileSystem) MkdirTemp(dir string, pattern string) (string, error) {
+	return os.MkdirTemp(dir, pattern)
+}
+
+func (OSFileSystem) Stat(path string) (fs.FileInfo, error) {
+	return os.Stat(path)
+}
+
+func (OSFileSystem) ReadDir(path string) ([]fs.FileInfo, error) {
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		return nil, err
+	}
+	infos := make([]fs.FileInfo, 0, len(entries))
+	for _, entry := range entries {
+		info, infoError := entry.Info()
+		if infoError != nil {
+			continue // removed after the listing, as by a concurrent cleanup
+		}
+		infos = append(infos, info)
+	}
+	return infos, nil
+}
+
+func (OSFileSystem) ReadFile(path string) ([]byte, error) {
+	return os.ReadFile(filepath.Clean(path))
+}
+
+func (OSFileSystem) WriteFile(path string, data []byte, perm fs.FileMode) error {
+	return os.WriteFile(filepath.Clean(path), data, perm)
+}
+
+func (OSFileSystem) Remove(path string) error {
+	return os.Remove(path)
+}
+
+func (OSFileSystem) RemoveAll(path string) error {
+	return os.RemoveAll(path)
+}
+
+func (OSFileSystem) Glob(pattern string) ([]string, error) {
+	return filepath.Glob(pattern)
+}
+
+func (OSFileSystem) Chtimes(path string, accessTime time.Time, modificationTime time.Time) error {
+	return os.Chtimes(path, accessTime, modificationTime)
+}
+
+// MemoryFileSystem keeps everything in memory only, so that tests and demos neither need writable folders nor leave
+// anything behind; the root is always there, so that any (absolute or relative) folder can be created in it
+type MemoryFileSystem struct {
+	lock  sync.RWMutex
+	files map[string]*memoryFile
+}
+
+type memoryFile struct {
+	name    string
+	data    []byte
+	mode    fs.FileMode
+	modTime time.Time
+}
+
+func (what *memoryFile) Name() string       { return what.name }
+func (what *memoryFile) Size() int64        { return int64(len(what.data)) }
+func (what *memoryFile) ModTime() time.Time { return what.modTime }
+func (what *memoryFile) IsDir() bool        { return what.mode.IsDir() }
+func (what *memoryFile) Sys() any           { return nil }
+func (what *memoryFile) Mode() fs.FileMode  { return what.mode }
+
+func NewMemoryFileSystem() *MemoryFileSystem {
+	return &MemoryFileSystem{files: make(map[string]*memoryFile)}
+}
+
+func (what *MemoryFileSystem) Mkdir(path string, perm fs.FileMode) error {
+	what.lock.Lock()
+	defer what.lock.Unlock()
+	return what.mkdir(filepath.Clean(path), perm)
+}
+
+func (what *MemoryFileSystem) mkdir(path string, perm fs.FileMode) error {
+	if _, exists := what.files[path]; exists || what.isDir(path) {
+		return &fs.PathError{Op: "mkdir", Path: path, Err: fs.ErrExist}
+	}
+	if !what.isDir(filepath.Dir(path)) {
+		return &fs.PathError{Op: "mkdir", Path: path, Err: fs.ErrNotExist}
+	}
+	what.files[path] = &memoryFile{name: filepath.Base(path), mode: fs.ModeDir | perm.Perm(), modTime: time.Now()}
+	return nil
+}
+
+func (what *MemoryFileSystem) MkdirAll(path string, perm fs.FileMode) error {
+	what.lock.Lock()
+	defer what.lock.Unlock()
+	return what.mkdirAll(path, perm)
+}
+
+func (what *MemoryFileSystem) mkdirAll(path string, perm fs.FileMode) error {
+	for path = filepath.Clean(path); !what.isDir(path); path = filepath.Dir(path) {
+		if _, exists := what.files[path]; exists {
+			return &fs.PathError{Op: "mkdir", Path: path, Err: fs.ErrExist}
+		}
+		what.files[path] = &memoryFile{name: filepath.Base(path), mode: fs.ModeDir | perm.Perm(), modTime: time.Now()}
+	}
+	return nil
+}
+
+// MkdirTemp creates a new folder like os.MkdirTemp (in the path of the default temp folder of the OS if dir is empty),
+// creating the folder it is created in if missing, as the memory file system starts empty
+func (what *MemoryFileSystem) MkdirTemp(dir string, pattern string) (string, error) {
+	what.lock.Lock()
+	defer what.lock.Unlock()
+	if len(dir) == 0 {
+		dir = os.TempDir()
+	}
+	if err := what.mkdirAll(dir, 0700); err != nil {
+		return "", err
+	}
+	prefix, suffix, found := strings.Cut(pattern, "*")
+	if !found {
+		prefix, suffix = pattern, ""
+	}
+	for attempt := 0; attempt < 10000; attempt++ {
+		path := filepath.Join(dir, prefix+strconv.FormatUint(uint64(rand.Uint32()), 10)+suffix) // #nosec G404 // only unique, not secret
+		err := what.mkdir(path, 0700)
+		if err == nil || !os.IsExist(err) {
+			return path, err
+		}
+	}
+	return "", &fs.PathError{Op: "mkdirtemp", Path: filepath.Join(dir, pattern), Err: fs.ErrExist}
+}
+
+func (what *MemoryFileSystem) Stat(path string) (fs.FileInfo, error) {
+	what.lock.RLock()
+	defer what.lock.RUnlock()
+	path = filepath.Clean(path)
+	if file, exists := what.files[path]; exists {
+		return file, nil
+	}
+	if what.isDir(path) {
+		return &memoryFile{name: filepath.Base(path), mode: fs.ModeDir | 0700}, nil
+	}
+	return nil, &fs.PathError{Op: "stat", Path: path, Err: fs.ErrNotExist}
+}
+
+func (what *MemoryFileSystem) ReadDir(path string) ([]fs.FileInfo, error) {
+	what.lock.RLock()
+	defer what.lock.RUnlock()
+	path = filepath.Clean(path)
+	if !what.isDir(path) {
+		return nil, &fs.PathError{Op: "readdir", Path: path, Err: fs.ErrNotExist}
+	}
+	infos := make([]fs.FileInfo, 0)
+	for filePath, file := range what.files {
+		if filepath.Dir(filePath) == path && filePath != path {
+			infos = append(infos, file)
+		}
+	}
+	sort.Slice(infos, func(i, j int) bool {
+		return infos[i].Name() < infos[j].Name()
+	})
+	return infos, nil
+}
+
+func (what *MemoryFileSystem) ReadFile(path string) ([]byte, error) {
+	what.lock.RLock()
+	defer what.lock.RUnlock()
+	path = filepath.Clean(path)
+	file, exists := what.files[path]
+	if !exists || file.IsDir() {
+		return nil, &fs.PathError{Op: "open", Path: path, Err: fs.ErrNotExist}
+	}
+	return append([]byte(nil), file.data...), nil
+}
+
+func (what *MemoryFileSystem) WriteFile(path string, data []byte, perm fs.FileMode) error {
+	what.lock.Lock()
+	defer what.lock.Unlock()
+	path = filepath.Clean(path)
+	if !what.isDir(filepath.Dir(path)) {
+		return &fs.PathError{Op: "open", Path: path, Err: fs.ErrNotExist}
+	}
+	if file, exists := what.files[path]; exists && file.IsDir() {
+		return &fs.PathError{Op: "open", Path: path, Err: fs.ErrExist}
+	}
+	what.files[path] = &memoryFile{name: filepath.Base(path), data: append([]byte(nil), data...), mode: perm.Perm(), modTime: time.Now()}
+	return nil
+}
+
+func (what *MemoryFileSystem) Remove(path string) error {
+	what.lock.Lock()
+	defer what.lock.Unlock()
+	path = filepath.Clean(path)
+	if _, exists := what.files[path]; !exists {
+		return &fs.PathError{Op: "remove", Path: path, Err: fs.ErrNotExist}
+	}
+	prefix := path + string(filepath.Separator)
+	for filePath := range what.files {
+		if strings.HasPrefix(filePath, prefix) {
+			return &fs.PathError{Op: "remove", Path: path, Err: fmt.Errorf("directory not empty")}
+		}
+	}
+	delete(what.files, path)
+	return nil
+}
+
+func (what *MemoryFileSystem) RemoveAll(path string) error {
+	what.lock.Lock()
+	defer what.lock.Unlock()
+	path = filepath.Clean(path)
+	prefix := path + string(filepath.Separator)
+	for filePath := range what.files {
+		if filePath == path || strings.HasPrefix(filePath, prefix) {
+			delete(what.files, filePath)
+		}
+	}
+	return nil
+}
+
+func (what *MemoryFileSystem) Glob(pattern string) ([]string, error) {
+	what.lock.RLock()
+	defer what.lock.RUnlock()
+	matches := make([]string, 0)
+	for filePath := range what.files {
+		matched, err := filepath.Match(pattern, filePath)
+		if err != nil {
+			return nil, err
+		}
+		if matched {
+			matches = append(matches, filePath)
+		}
+	}
+	sort.Strings(matches)
+	return matches, nil
+}
+
+// Chtimes changes the modification time only, as the access time is not kept
+func (what *MemoryFileSystem) Chtimes(path string, _ time.Time, modificationTime time.Time) error {
+	what.lock.Lock()
+	defer what.lock.Unlock()
+	path = filepath.Clean(path)
+	file, exists := what.files[path]
+	if !exists {
+		return &fs.PathError{Op: "chtimes", Path: path, Err: fs.ErrNotExist}
+	}
+	file.modTime = modificationTime
+	return nil
+}
+
+// isDir treats the root as existing folder
+func (what *MemoryFileSystem) isDir(path string) bool {
+	if path == "." || path == string(filepath.Separator) || path == filepath.VolumeName(path)+string(filepath.Separator) {
+		return true
+	}
+	file, exists := what.files[path]
+	return exists && file.IsDir()
+}
@@ -0,0 +1,53 @@
+package common
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoryFileSystem(t *testing.T) {
+	fileSystem := NewMemoryFileSystem()
+	keyFolder := filepath.Join("/data", "keys", "abc")
+	modelFolder := filepath.Join(keyFolder, "model")
+
+	assert.Error(t, fileSystem.Mkdir(modelFolder, 0700))
+	assert.NoError(t, fileSystem.MkdirAll(keyFolder, 0700))
+	assert.NoError(t, fileSystem.Mkdir(modelFolder, 0700))
+	assert.NoError(t, fileSystem.WriteFile(filepath.Join(modelFolder, "threagile.yaml"), []byte("title: test"), 0600))
+	assert.Error(t, fileSystem.WriteFile(filepath.Join(modelFolder, "history", "backup"), []byte("title: old"), 0600))
+
+	data, err := fileSystem.ReadFile(filepath.Join(modelFolder, "threagile.yaml"))
+	assert.NoError(t, err)
+	assert.Equal(t, "title: test", string(data))
+
+	infos, err := fileSystem.ReadDir(keyFolder)
+	assert.NoError(t, err)
+	assert.Len(t, infos, 1)
+	assert.True(t, infos[0].IsDir())
+	assert.Equal(t, "model", infos[0].Name())
+
+	matches, err := fileSystem.Glob(filepath.Join("/data", "keys", "*", "model", "threagile.yaml"))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{filepath.Join(modelFolder, "threagile.yaml")}, matches)
+
+	assert.Error(t, fileSystem.Remove(modelFolder))
+	assert.NoError(t, fileSystem.RemoveAll(modelFolder))
+	_, err = fileSystem.Stat(filepath.Join(modelFolder, "threagile.yaml"))
+	assert.True(t, os.IsNotExist(err))
+	_, err = fileSystem.Stat(keyFolder)
+	assert.NoError(t, err)
+
+	tempFolder, err := fileSystem.MkdirTemp(filepath.Join("/tmp", "threagile"), "render-*")
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(filepath.Base(tempFolder), "render-"))
+	otherTempFolder, err := fileSystem.MkdirTemp(filepath.Join("/tmp", "threagile"), "render-*")
+	assert.NoError(t, err)
+	assert.NotEqual(t, tempFolder, otherTempFolder)
+	info, err := fileSystem.Stat(tempFolder)
+	assert.NoError(t, err)
+	assert.True(t, info.IsDir())
+}
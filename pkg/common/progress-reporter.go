@@ -5,23 +5,59 @@ Copyright © 2023 NAME HERE <EMAIL ADDRESS>
 package common
 
 import (
+	"encoding/json"
 	"fmt"
+	"io"
 	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
 )
 
+// ProgressEventsEnvName names the environment variable making the analysis pass its progress on as ProgressEvents on
+// stderr, so that the server gets them from its sub-process without parsing the log
+const ProgressEventsEnvName = "THREAGILE_PROGRESS_EVENTS"
+
+const (
+	ProgressEventInfo    = "info"
+	ProgressEventWarning = "warning"
+	ProgressEventError   = "error"
+)
+
+// ProgressEvent is a message of the progress reporter, written as one line of json
+type ProgressEvent struct {
+	Level   string `json:"level"` // info, warning or error
+	Message string `json:"message"`
+}
+
+// ParseProgressEvent parses a line of output written as ProgressEvent, other lines are no events
+func ParseProgressEvent(line string) (ProgressEvent, bool) {
+	var event ProgressEvent
+	if !strings.HasPrefix(line, "{") || json.Unmarshal([]byte(line), &event) != nil || len(event.Level) == 0 {
+		return ProgressEvent{}, false
+	}
+	return event, true
+}
+
 type DefaultProgressReporter struct {
 	Verbose       bool
 	SuppressError bool
+	Warnings      *Warnings // collects the warnings (including suppressed errors) in addition to printing them, if set
+	Events        io.Writer // receives all messages (verbose or not) as ProgressEvents in addition to printing them, if set
 }
 
 func (r DefaultProgressReporter) Info(a ...any) {
 	if r.Verbose {
-		fmt.Println(a...)
+		fmt.Fprintln(logStdout, a...)
 	}
+	r.event(ProgressEventInfo, fmt.Sprintln(a...))
 }
 
-func (DefaultProgressReporter) Warn(a ...any) {
-	fmt.Println(a...)
+func (r DefaultProgressReporter) Warn(a ...any) {
+	fmt.Fprintln(logStdout, a...)
+	r.Warnings.add(fmt.Sprintln(a...))
+	r.event(ProgressEventWarning, fmt.Sprintln(a...))
 }
 
 func (r DefaultProgressReporter) Error(v ...any) {
@@ -29,20 +65,24 @@ func (r DefaultProgressReporter) Error(v ...any) {
 		r.Warn(v...)
 		return
 	}
+	r.event(ProgressEventError, fmt.Sprintln(v...))
 	log.Fatal(v...)
 }
 
 func (r DefaultProgressReporter) Infof(format string, a ...any) {
 	if r.Verbose {
-		fmt.Printf(format, a...)
-		fmt.Println()
+		fmt.Fprintf(logStdout, format, a...)
+		fmt.Fprintln(logStdout)
 	}
+	r.event(ProgressEventInfo, fmt.Sprintf(format, a...))
 }
 
-func (DefaultProgressReporter) Warnf(format string, a ...any) {
-	fmt.Print("WARNING: ")
-	fmt.Printf(format, a...)
-	fmt.Println()
+func (r DefaultProgressReporter) Warnf(format string, a ...any) {
+	fmt.Fprint(logStdout, "WARNING: ")
+	fmt.Fprintf(logStdout, format, a...)
+	fmt.Fprintln(logStdout)
+	r.Warnings.add(fmt.Sprintf(format, a...))
+	r.event(ProgressEventWarning, fmt.Sprintf(format, a...))
 }
 
 func (r DefaultProgressReporter) Errorf(format string, v ...any) {
@@ -50,5 +90,67 @@ func (r DefaultProgressReporter) Errorf(format string, v ...any) {
 		r.Warnf(format, v...)
 		return
 	}
+	r.event(ProgressEventError, fmt.Sprintf(format, v...))
 	log.Fatalf(format, v...)
 }
+
+func (r DefaultProgressReporter) event(level string, message string) {
+	message = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(message), "WARNING:"))
+	if r.Events == nil || len(message) == 0 {
+		return
+	}
+	data, err := json.Marshal(ProgressEvent{Level: level, Message: message})
+	if err == nil {
+		_, _ = fmt.Fprintln(r.Events, string(data))
+	}
+}
+
+// Warnings are the non-fatal warnings of a run, written as warnings.json so that the server gets them from its
+// sub-process without parsing the log
+type Warnings struct {
+	lock     sync.Mutex
+	messages []string
+}
+
+func (what *Warnings) add(message string) {
+	if what == nil {
+		return
+	}
+	message = strings.TrimSpace(message)
+	message = strings.TrimSpace(strings.TrimPrefix(message, "WARNING:"))
+	if len(message) == 0 {
+		return
+	}
+	what.lock.Lock()
+	defer what.lock.Unlock()
+	what.messages = append(what.messages, message)
+}
+
+func (what *Warnings) Messages() []string {
+	what.lock.Lock()
+	defer what.lock.Unlock()
+	return append(make([]string, 0, len(what.messages)), what.messages...)
+}
+
+// Write writes the warnings as json array, also when there are none, so that readers can tell them from a failed run
+func (what *Warnings) Write(filename string) error {
+	data, err := json.MarshalIndent(what.Messages(), "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filename, data, 0600)
+}
+
+// ReadWarnings reads the warnings.json of a run, e.g. of a sub-process
+func ReadWarnings(filename string) ([]string, error) {
+	data, err := os.ReadFile(filepath.Clean(filename))
+	if err != nil {
+		return nil, err
+	}
+	warnings := make([]string, 0)
+	err = json.Unmarshal(data, &warnings)
+	if err != nil {
+		return nil, err
+	}
+	return warnings, nil
+}
@@ -0,0 +1,62 @@
+package common
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProgressReporterCollectsWarnings(t *testing.T) {
+	warnings := &Warnings{}
+	reporter := DefaultProgressReporter{SuppressError: true, Warnings: warnings}
+	reporter.Info("not a warning")
+	reporter.Warnf("Tag is available but not used: %v", "vault")
+	reporter.Warn("stale checkpoint")
+	reporter.Error("WARNING: Custom risk rule \"x\" not loaded: missing\n")
+	assert.Equal(t, []string{"Tag is available but not used: vault", "stale checkpoint", "Custom risk rule \"x\" not loaded: missing"}, warnings.Messages())
+
+	DefaultProgressReporter{}.Warnf("not collected")
+
+	filename := filepath.Join(t.TempDir(), WarningsFilename)
+	assert.NoError(t, warnings.Write(filename))
+	read, err := ReadWarnings(filename)
+	assert.NoError(t, err)
+	assert.Equal(t, warnings.Messages(), read)
+
+	assert.NoError(t, (&Warnings{}).Write(filename))
+	read, err = ReadWarnings(filename)
+	assert.NoError(t, err)
+	assert.Empty(t, read)
+	assert.NotNil(t, read)
+	_, err = ReadWarnings(filepath.Join(t.TempDir(), "missing.json"))
+	assert.Error(t, err)
+}
+
+func TestProgressReporterEvents(t *testing.T) {
+	var events bytes.Buffer
+	reporter := DefaultProgressReporter{SuppressError: true, Events: &events}
+	reporter.Info("Parsing model")
+	reporter.Warnf("Tag is available but not used: %v", "vault")
+	reporter.Error("WARNING: Custom risk rule \"x\" not loaded\n")
+	reporter.Info()
+
+	parsed := make([]ProgressEvent, 0)
+	for _, line := range strings.Split(strings.TrimSpace(events.String()), "\n") {
+		event, ok := ParseProgressEvent(line)
+		assert.True(t, ok, line)
+		parsed = append(parsed, event)
+	}
+	assert.Equal(t, []ProgressEvent{
+		{Level: ProgressEventInfo, Message: "Parsing model"},
+		{Level: ProgressEventWarning, Message: "Tag is available but not used: vault"},
+		{Level: ProgressEventWarning, Message: "Custom risk rule \"x\" not loaded"},
+	}, parsed, "verbose or not, without empty messages")
+
+	_, ok := ParseProgressEvent("Writing report pdf")
+	assert.False(t, ok)
+	_, ok = ParseProgressEvent(`{"title": "some json"}`)
+	assert.False(t, ok)
+}
@@ -0,0 +1,119 @@
+package common
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// redactionMask replaces the sensitive data in the log output
+const redactionMask = "****"
+
+// DefaultRedactionPatterns mask the usual secrets (passwords, tokens and keys) in the log output, as verbose output
+// and error messages may quote the model, its files and the urls called, which ends up in shared CI and server logs
+var DefaultRedactionPatterns = []string{
+	`(?i)(?:password|passwd|pwd|secret|token|api[_-]?key|access[_-]?key|private[_-]?key|credentials?)["']?\s*[:=]\s*["']?(?P<secret>[^\s"',;]+)`,
+	`(?i)\b(?:bearer|basic)\s+(?P<secret>[A-Za-z0-9._~+/=-]{8,})`,
+	`://[^/\s:@]+:(?P<secret>[^@\s/]+)@`,
+	`\b(?:AKIA|ASIA)[0-9A-Z]{16}\b`,
+	`\bgh[pousr]_[A-Za-z0-9]{36,}\b`,
+	`\beyJ[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+`,
+	`-----BEGIN [A-Z ]*PRIVATE KEY-----(?s:.*?)-----END [A-Z ]*PRIVATE KEY-----`,
+}
+
+// Redactor masks the matches of its patterns, only the group named secret of patterns having one (so that e.g. the
+// name of a password stays readable), otherwise the whole match
+type Redactor struct {
+	patterns []*regexp.Regexp
+}
+
+func NewRedactor(patterns []string) (*Redactor, error) {
+	redactor := &Redactor{patterns: make([]*regexp.Regexp, 0, len(patterns))}
+	for _, pattern := range patterns {
+		compiled, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid redaction pattern %q: %w", pattern, err)
+		}
+		redactor.patterns = append(redactor.patterns, compiled)
+	}
+	return redactor, nil
+}
+
+func (what *Redactor) Redact(text string) string {
+	for _, pattern := range what.patterns {
+		secret := pattern.SubexpIndex("secret")
+		matches := pattern.FindAllStringSubmatchIndex(text, -1)
+		if len(matches) == 0 {
+			continue
+		}
+		var redacted strings.Builder
+		last := 0
+		for _, match := range matches {
+			start, end := match[0], match[1]
+			if secret >= 0 {
+				start, end = match[2*secret], match[2*secret+1]
+			}
+			if start < 0 || start < last {
+				continue
+			}
+			redacted.WriteString(text[last:start])
+			redacted.WriteString(redactionMask)
+			last = end
+		}
+		redacted.WriteString(text[last:])
+		text = redacted.String()
+	}
+	return text
+}
+
+// Writer redacts everything written to the writer, each write on its own (as the log and the progress reporters write
+// whole lines at once)
+func (what *Redactor) Writer(writer io.Writer) io.Writer {
+	return &redactingWriter{redactor: what, writer: writer}
+}
+
+type redactingWriter struct {
+	redactor *Redactor
+	writer   io.Writer
+}
+
+func (what *redactingWriter) Write(data []byte) (int, error) {
+	_, err := io.WriteString(what.writer, what.redactor.Redact(string(data)))
+	if err != nil {
+		return 0, err
+	}
+	return len(data), nil
+}
+
+// the outputs of the log and of the progress reporters, the redacting ones once EnableLogRedaction was called
+var (
+	logStdout io.Writer = os.Stdout
+	logStderr io.Writer = os.Stderr
+)
+
+// EnableLogRedaction masks the matches of the default and the given patterns in the output of the log, of the progress
+// reporters and of everything else printed to LogStdout and LogStderr (like the macros and the request log of the
+// server); not synchronized, so call it before logging anything, e.g. right after reading the config
+func EnableLogRedaction(patterns []string) error {
+	redactor, err := NewRedactor(append(append(make([]string, 0), DefaultRedactionPatterns...), patterns...))
+	if err != nil {
+		return err
+	}
+	logStdout = redactor.Writer(os.Stdout)
+	logStderr = redactor.Writer(os.Stderr)
+	log.SetOutput(logStderr)
+	return nil
+}
+
+// LogStdout is where the log output (other than the one of the log package) goes to, redacted once enabled
+func LogStdout() io.Writer {
+	return logStdout
+}
+
+// LogStderr is where the error output goes to, redacted once enabled
+func LogStderr() io.Writer {
+	return logStderr
+}
@@ -0,0 +1,23 @@
+package common
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// WithTimeout limits the context to the given number of seconds, zero or less means no limit (but still cancelable)
+func WithTimeout(ctx context.Context, seconds int) (context.Context, context.CancelFunc) {
+	if seconds <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, time.Duration(seconds)*time.Second)
+}
+
+// CheckCanceled returns a timeout failure naming the phase when the context timed out or was canceled
+func CheckCanceled(ctx context.Context, phase string) error {
+	if ctx.Err() == nil {
+		return nil
+	}
+	return NewFailure(ExitCodeTimeout, fmt.Errorf("%v aborted: %w", phase, ctx.Err()))
+}
@@ -20,10 +20,26 @@ const (
 		" docker run --rm -it -v \"$(pwd)\":app/work threagile/threagile " + common.CreateExampleModelCommand + " -output app/work \n\n" +
 		"If you want to create a minimal stub model (via docker) as a starting point for your own model just run: \n" +
 		" docker run --rm -it -v \"$(pwd)\":app/work threagile/threagile " + common.CreateStubModelCommand + " -output app/work \n\n" +
+		"If you want to create an initial model by answering some questions about your system (via docker) just run: \n" +
+		" docker run --rm -it -v \"$(pwd)\":app/work threagile/threagile " + common.InitModelCommand + " -model app/work/threagile.yaml \n\n" +
+		"If you want to create an initial model from a draw.io (diagrams.net) architecture diagram (via docker) just run: \n" +
+		" docker run --rm -it -v \"$(pwd)\":app/work threagile/threagile " + common.ImportDrawIOCommand + " app/work/architecture.drawio -model app/work/threagile.yaml \n\n" +
+		"If you want to create an initial model from the OpenAPI spec of your API (via docker) just run: \n" +
+		" docker run --rm -it -v \"$(pwd)\":app/work threagile/threagile " + common.ImportOpenAPICommand + " app/work/openapi.yaml -consumer \"Web Shop\" -model app/work/threagile.yaml \n\n" +
+		"If you want to create an initial model from the Terraform state of your cloud infrastructure (via docker) just run: \n" +
+		" docker run --rm -it -v \"$(pwd)\":app/work threagile/threagile " + common.ImportTerraformCommand + " app/work/terraform.tfstate -model app/work/threagile.yaml \n\n" +
+		"If you want to create a model from an Open Threat Model (OTM) file of another threat modeling tool (via docker) just run: \n" +
+		" docker run --rm -it -v \"$(pwd)\":app/work threagile/threagile " + common.ImportOTMCommand + " app/work/threat-model.otm.json -model app/work/threagile.yaml \n\n" +
+		"If you want to export your model as Open Threat Model (OTM) file for other threat modeling tools (via docker) just run: \n" +
+		" docker run --rm -it -v \"$(pwd)\":app/work threagile/threagile " + common.ExportOTMCommand + " -model app/work/threagile.yaml -output app/work \n\n" +
 		"If you want to execute Threagile on a model yaml file (via docker):  \n" +
 		" docker run --rm -it -v \"$(pwd)\":app/work threagile/threagile analyze-model -verbose -model -output app/work \n\n" +
 		"If you want to execute Threagile in interactive mode (via docker):  \n" +
 		" docker run --rm -it -v \"$(pwd)\":app/work threagile/threagile -i -verbose -model -output app/work \n\n" +
+		"If you want to find all problems of a model yaml file at once (printed as json array): \n" +
+		" docker run --rm -v \"$(pwd)\":app/work threagile/threagile " + common.ValidateModelCommand + " -model app/work/threagile.yaml \n\n" +
+		"If you want to see the risks changed by a new version of a model yaml file (e.g. in a pull request), compared to the previous version: \n" +
+		" docker run --rm -v \"$(pwd)\":app/work threagile/threagile " + common.DiffCommand + " -model app/work/threagile.yaml -compare-model app/work/threagile-previous.yaml \n\n" +
 		"If you want to run Threagile as a server (REST API) on some port (here 8080):  \n" +
 		" docker run --rm -it --shm-size=256m  -p 8080:8080 --name --mount 'type=volume,src=threagile-storage,dst=/data,readonly=false' threagile/threagile server --server-port 8080 \n\n" +
 		"If you want to find out about the different enum values usable in the model yaml file: \n" +
@@ -33,7 +49,9 @@ const (
 		"If you want to list all available model macros (which are macros capable of reading a model yaml file, asking you questions in a wizard-style and then update the model yaml file accordingly): \n" +
 		" docker run --rm -it threagile/threagile " + common.ListModelMacrosCommand + " \n\n" +
 		"If you want to execute a certain model macro on the model yaml file (here the macro add-build-pipeline): \n" +
-		" docker run --rm -it -v \"$(pwd)\":app/work threagile/threagile -model app/work/threagile.yaml -output app/work execute-model-macro add-build-pipeline"
+		" docker run --rm -it -v \"$(pwd)\":app/work threagile/threagile -model app/work/threagile.yaml -output app/work execute-model-macro add-build-pipeline\n\n" +
+		"If you want to execute it without prompts (e.g. in CI pipelines), with the answers of its questions by their ID in a yaml file: \n" +
+		" docker run --rm -v \"$(pwd)\":app/work threagile/threagile -model app/work/threagile.yaml -output app/work -execute-model-macro-answers app/work/answers.yaml execute-model-macro add-build-pipeline"
 	ThirdPartyLicenses = " - golang (Google Go License): https://golang.org/LICENSE\n" +
 		" - go-yaml (MIT License): https://github.com/go-yaml/yaml/blob/v3/LICENSE\n" +
 		" - graphviz (CPL License): https://graphviz.gitlab.io/license/\n" +
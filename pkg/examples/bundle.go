@@ -0,0 +1,201 @@
+package examples
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/threagile/threagile/pkg/common"
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+// bundleEntry is a file or folder of the bundle: the first existing source is used, sources are either relative to
+// the app folder (the layout of the container image) or to the source tree (the layout of a checkout)
+type bundleEntry struct {
+	name     string
+	sources  []string
+	required bool
+}
+
+// bundleWriter receives the files of the bundle, either writing them to a folder or to a tarball
+type bundleWriter interface {
+	addFile(name string, source string, mode fs.FileMode) error
+	close() error
+}
+
+// CreateBundle assembles everything needed to run the CLI and the server without the official container (binary,
+// plugins, example models, schema, report template and static server assets) into the target, which is written as
+// gzipped tarball when named *.tar.gz or *.tgz and as folder otherwise
+func CreateBundle(config *common.Config, target string, progressReporter types.ProgressReporter) error {
+	executable, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("unable to locate the threagile binary: %w", err)
+	}
+
+	entries := []bundleEntry{
+		{name: "threagile", sources: []string{executable}, required: true},
+		{name: "LICENSE.txt", sources: []string{"LICENSE.txt"}},
+		{name: common.TemplateFilename, sources: []string{config.TemplateFilename, filepath.Join("report", "template", common.TemplateFilename)}, required: true},
+		{name: "schema.json", sources: []string{"schema.json", filepath.Join("support", "schema.json")}, required: true},
+		{name: "live-templates.txt", sources: []string{"live-templates.txt", filepath.Join("support", "live-templates.txt")}},
+		{name: "openapi.yaml", sources: []string{"openapi.yaml", filepath.Join("support", "openapi.yaml")}},
+		{name: "threagile-example-model.yaml", sources: []string{"threagile-example-model.yaml", filepath.Join("demo", "example", "threagile.yaml")}},
+		{name: "threagile-stub-model.yaml", sources: []string{"threagile-stub-model.yaml", filepath.Join("demo", "stub", "threagile.yaml")}},
+		{name: "server", sources: []string{"server"}, required: true},
+	}
+
+	plugins := []string{config.RAAPlugin, "raa_dummy", "risk_demo_rule"}
+	if len(config.OwnerDirectoryPlugin) > 0 {
+		plugins = append(plugins, config.OwnerDirectoryPlugin)
+	}
+	for _, plugin := range plugins {
+		entries = append(entries, bundleEntry{name: plugin, sources: []string{filepath.Join(config.PluginFolder, plugin)}})
+	}
+
+	writer, err := newBundleWriter(target)
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		source := findBundleSource(config.AppFolder, entry.sources)
+		if len(source) == 0 {
+			if entry.required {
+				_ = writer.close()
+				return fmt.Errorf("unable to find %v in app folder %v", entry.name, config.AppFolder)
+			}
+			progressReporter.Warnf("skipping %v: not found", entry.name)
+			continue
+		}
+
+		progressReporter.Infof("adding %v", entry.name)
+		err = addToBundle(writer, entry.name, source)
+		if err != nil {
+			_ = writer.close()
+			return fmt.Errorf("unable to add %v to bundle: %w", entry.name, err)
+		}
+	}
+
+	return writer.close()
+}
+
+func findBundleSource(appFolder string, sources []string) string {
+	for _, source := range sources {
+		if !filepath.IsAbs(source) {
+			source = filepath.Join(appFolder, source)
+		}
+		if _, err := os.Stat(source); err == nil {
+			return filepath.Clean(source)
+		}
+	}
+	return ""
+}
+
+func addToBundle(writer bundleWriter, name string, source string) error {
+	return filepath.WalkDir(source, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil || entry.IsDir() {
+			return err
+		}
+
+		relativePath, err := filepath.Rel(source, path)
+		if err != nil {
+			return err
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+
+		return writer.addFile(filepath.ToSlash(filepath.Join(name, relativePath)), path, info.Mode().Perm())
+	})
+}
+
+func newBundleWriter(target string) (bundleWriter, error) {
+	if !strings.HasSuffix(target, ".tar.gz") && !strings.HasSuffix(target, ".tgz") {
+		return &folderBundleWriter{folder: target}, os.MkdirAll(target, 0750)
+	}
+
+	file, err := os.Create(filepath.Clean(target))
+	if err != nil {
+		return nil, err
+	}
+
+	gzipWriter := gzip.NewWriter(file)
+	return &tarBundleWriter{file: file, gzipWriter: gzipWriter, tarWriter: tar.NewWriter(gzipWriter)}, nil
+}
+
+type folderBundleWriter struct {
+	folder string
+}
+
+func (what *folderBundleWriter) addFile(name string, source string, mode fs.FileMode) error {
+	destination := filepath.Join(what.folder, filepath.FromSlash(name))
+	err := os.MkdirAll(filepath.Dir(destination), 0750)
+	if err != nil {
+		return err
+	}
+
+	_, err = copyFile(source, destination)
+	if err != nil {
+		return err
+	}
+
+	return os.Chmod(destination, mode)
+}
+
+func (what *folderBundleWriter) close() error {
+	return nil
+}
+
+type tarBundleWriter struct {
+	file       *os.File
+	gzipWriter *gzip.Writer
+	tarWriter  *tar.Writer
+}
+
+func (what *tarBundleWriter) addFile(name string, source string, mode fs.FileMode) error {
+	info, err := os.Stat(source)
+	if err != nil {
+		return err
+	}
+
+	header, err := tar.FileInfoHeader(info, "")
+	if err != nil {
+		return err
+	}
+	header.Name = name
+	header.Mode = int64(mode)
+
+	err = what.tarWriter.WriteHeader(header)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.Open(filepath.Clean(source))
+	if err != nil {
+		return err
+	}
+	defer func() { _ = file.Close() }()
+
+	_, err = io.Copy(what.tarWriter, file)
+	return err
+}
+
+func (what *tarBundleWriter) close() error {
+	tarError := what.tarWriter.Close()
+	gzipError := what.gzipWriter.Close()
+	fileError := what.file.Close()
+	if tarError != nil {
+		return tarError
+	}
+	if gzipError != nil {
+		return gzipError
+	}
+	return fileError
+}
@@ -0,0 +1,93 @@
+package examples
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/threagile/threagile/pkg/common"
+)
+
+// bundleTestConfig answers the config of an app folder laid out like a checkout, with a RAA plugin only
+func bundleTestConfig(t *testing.T) *common.Config {
+	appFolder := t.TempDir()
+	files := map[string]os.FileMode{
+		filepath.Join("report", "template", common.TemplateFilename): 0600,
+		filepath.Join("support", "schema.json"):                      0600,
+		filepath.Join("server", "static", "css", "style.css"):        0600,
+		filepath.Join("plugins", common.RAAPluginName):               0700,
+	}
+	for name, mode := range files {
+		assert.NoError(t, os.MkdirAll(filepath.Dir(filepath.Join(appFolder, name)), 0700))
+		assert.NoError(t, os.WriteFile(filepath.Join(appFolder, name), []byte(name), mode))
+	}
+
+	config := new(common.Config).Defaults("")
+	config.AppFolder = appFolder
+	config.PluginFolder = filepath.Join(appFolder, "plugins")
+	return config
+}
+
+func TestCreateBundleFolder(t *testing.T) {
+	config := bundleTestConfig(t)
+	target := filepath.Join(t.TempDir(), "bundle")
+	assert.NoError(t, CreateBundle(config, target, common.DefaultProgressReporter{}))
+
+	assert.FileExists(t, filepath.Join(target, "threagile"))
+	for name, source := range map[string]string{
+		common.TemplateFilename: filepath.Join("report", "template", common.TemplateFilename),
+		"schema.json":           filepath.Join("support", "schema.json"),
+		filepath.Join("server", "static", "css", "style.css"): filepath.Join("server", "static", "css", "style.css"),
+	} {
+		data, err := os.ReadFile(filepath.Join(target, name))
+		assert.NoError(t, err, name)
+		assert.Equal(t, source, string(data), "copied from the layout of the checkout")
+	}
+	info, err := os.Stat(filepath.Join(target, common.RAAPluginName))
+	assert.NoError(t, err)
+	assert.Equal(t, os.FileMode(0700), info.Mode().Perm(), "plugins still executable")
+	assert.NoFileExists(t, filepath.Join(target, "risk_demo_rule"), "optional files skipped")
+}
+
+func TestCreateBundleTarball(t *testing.T) {
+	config := bundleTestConfig(t)
+	target := filepath.Join(t.TempDir(), "bundle.tar.gz")
+	assert.NoError(t, CreateBundle(config, target, common.DefaultProgressReporter{}))
+
+	file, err := os.Open(target)
+	assert.NoError(t, err)
+	defer func() { _ = file.Close() }()
+	gzipReader, err := gzip.NewReader(file)
+	assert.NoError(t, err)
+	tarReader := tar.NewReader(gzipReader)
+	modes := make(map[string]int64)
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			break
+		}
+		assert.NoError(t, err)
+		modes[header.Name] = header.Mode
+	}
+
+	assert.Equal(t, map[string]int64{
+		"threagile":                   modes["threagile"],
+		common.TemplateFilename:       0600,
+		"schema.json":                 0600,
+		"server/static/css/style.css": 0600,
+		common.RAAPluginName:          0700,
+	}, modes)
+}
+
+func TestCreateBundleWithoutRequiredFiles(t *testing.T) {
+	config := bundleTestConfig(t)
+	assert.NoError(t, os.RemoveAll(filepath.Join(config.AppFolder, "server")))
+
+	err := CreateBundle(config, filepath.Join(t.TempDir(), "bundle.tgz"), common.DefaultProgressReporter{})
+	assert.ErrorContains(t, err, "unable to find server in app folder "+config.AppFolder)
+}
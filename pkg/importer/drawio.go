@@ -0,0 +1,345 @@
+package importer
+
+import (
+	"bytes"
+	"compress/flate"
+	"encoding/base64"
+	"encoding/xml"
+	"fmt"
+	"html"
+	"io"
+	"net/url"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/threagile/threagile/pkg/input"
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+// The draw.io (diagrams.net) convention of ImportDrawIO, shapes can carry properties (Edit Data, Ctrl+M) to refine it:
+//   - labeled containers (containers, swimlanes and groups) become trust boundaries, nested ones nested trust boundaries,
+//     with the type of the property trust-boundary-type (default network-on-prem)
+//   - other labeled shapes become technical assets: cylinders datastores, actors external entities and all others
+//     processes, unless the property type says otherwise, and the properties technology, tags (comma-separated) and
+//     internet (true) are taken over
+//   - arrows between technical assets become communication links of their source, with the label as title and the
+//     protocol of the property protocol (default unknown-protocol)
+//   - unlabeled shapes and unlabeled containers (just grouping their content) are skipped
+
+type drawioFile struct {
+	XMLName    xml.Name
+	Diagrams   []drawioDiagram  `xml:"diagram"`
+	GraphModel drawioGraphModel // when the file is a graph model without the mxfile around
+}
+
+type drawioDiagram struct {
+	Name       string            `xml:"name,attr"`
+	Compressed string            `xml:",chardata"`
+	GraphModel *drawioGraphModel `xml:"mxGraphModel"`
+}
+
+type drawioGraphModel struct {
+	Root drawioRoot `xml:"root"`
+}
+
+type drawioRoot struct {
+	Cells       []drawioCell   `xml:"mxCell"`
+	Objects     []drawioObject `xml:"object"`
+	UserObjects []drawioObject `xml:"UserObject"`
+}
+
+// drawioObject wraps the cell of a shape with properties
+type drawioObject struct {
+	Attributes []xml.Attr `xml:",any,attr"`
+	Cell       drawioCell `xml:"mxCell"`
+}
+
+type drawioCell struct {
+	Id     string `xml:"id,attr"`
+	Value  string `xml:"value,attr"`
+	Style  string `xml:"style,attr"`
+	Vertex string `xml:"vertex,attr"`
+	Edge   string `xml:"edge,attr"`
+	Parent string `xml:"parent,attr"`
+	Source string `xml:"source,attr"`
+	Target string `xml:"target,attr"`
+
+	properties map[string]string
+}
+
+// ImportDrawIO converts the diagrams.net diagram (all of its pages) into a model stub (see the convention above),
+// returning the notes about the shapes not taken over
+func ImportDrawIO(reader io.Reader, filename string) (*input.Model, []string, error) {
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to read draw.io file: %w", err)
+	}
+	var file drawioFile
+	err = xml.Unmarshal(data, &file)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to parse draw.io file: %w", err)
+	}
+	if file.XMLName.Local == "mxGraphModel" {
+		err = xml.Unmarshal(data, &file.GraphModel)
+		if err != nil {
+			return nil, nil, fmt.Errorf("unable to parse draw.io file: %w", err)
+		}
+		file.Diagrams = []drawioDiagram{{GraphModel: &file.GraphModel}}
+	} else if file.XMLName.Local != "mxfile" {
+		return nil, nil, fmt.Errorf("unable to parse draw.io file: unexpected root element %q", file.XMLName.Local)
+	}
+
+	model := newModel(strings.TrimSuffix(filepath.Base(filename), filepath.Ext(filename)), "draw.io diagram "+filepath.Base(filename))
+	importer := &drawioImporter{model: model, keys: make(map[string]bool), ids: make(map[string]bool), notes: make([]string, 0)}
+	for _, diagram := range file.Diagrams {
+		if diagram.GraphModel == nil {
+			diagram.GraphModel, err = decompressDrawIODiagram(diagram.Compressed)
+			if err != nil {
+				return nil, nil, fmt.Errorf("unable to parse page %q of draw.io file: %w", diagram.Name, err)
+			}
+		}
+		importer.importPage(diagram.GraphModel.Root)
+	}
+	model.SeedTagsAvailable()
+	return model, importer.notes, nil
+}
+
+// decompressDrawIODiagram decodes the page content of older draw.io versions: url-encoded, deflated and base64-encoded
+func decompressDrawIODiagram(compressed string) (*drawioGraphModel, error) {
+	deflated, err := base64.StdEncoding.DecodeString(strings.TrimSpace(compressed))
+	if err != nil {
+		return nil, err
+	}
+	encoded, err := io.ReadAll(flate.NewReader(bytes.NewReader(deflated)))
+	if err != nil {
+		return nil, err
+	}
+	decoded, err := url.QueryUnescape(string(encoded))
+	if err != nil {
+		return nil, err
+	}
+	graphModel := new(drawioGraphModel)
+	err = xml.Unmarshal([]byte(decoded), graphModel)
+	return graphModel, err
+}
+
+type drawioImporter struct {
+	model *input.Model
+	keys  map[string]bool // of the elements in the model file
+	ids   map[string]bool
+	notes []string
+}
+
+// drawioElement is a shape taken over, by its cell id
+type drawioElement struct {
+	key      string
+	id       string
+	boundary bool
+}
+
+func (what *drawioImporter) importPage(root drawioRoot) {
+	cells := make(map[string]*drawioCell)
+	order := make([]string, 0)
+	add := func(cell drawioCell) {
+		cells[cell.Id] = &cell
+		order = append(order, cell.Id)
+	}
+	for _, cell := range root.Cells {
+		add(cell)
+	}
+	for _, object := range append(root.Objects, root.UserObjects...) {
+		cell := object.Cell
+		cell.properties = make(map[string]string)
+		for _, attribute := range object.Attributes {
+			switch attribute.Name.Local {
+			case "id":
+				cell.Id = attribute.Value
+			case "label":
+				cell.Value = attribute.Value
+			default:
+				cell.properties[attribute.Name.Local] = attribute.Value
+			}
+		}
+		add(cell)
+	}
+
+	elements := make(map[string]*drawioElement)
+	edgeLabels := make(map[string]string) // the labels placed on arrows by their cell id
+	for _, cellId := range order {
+		cell := cells[cellId]
+		label := drawioLabel(cell.Value)
+		if cell.Vertex != "1" || len(label) == 0 {
+			continue
+		}
+		if parent, ok := cells[cell.Parent]; ok && parent.Edge == "1" {
+			if _, ok := edgeLabels[parent.Id]; !ok {
+				edgeLabels[parent.Id] = label
+			}
+			continue
+		}
+		if isDrawIOContainer(cell) {
+			elements[cellId] = what.addTrustBoundary(cell, label)
+		} else {
+			elements[cellId] = what.addTechnicalAsset(cell, label)
+		}
+	}
+
+	// the contents of the trust boundaries, from the innermost boundary around them
+	for _, cellId := range order {
+		element, ok := elements[cellId]
+		if !ok {
+			continue
+		}
+		boundary := enclosingDrawIOBoundary(cells, elements, cells[cellId].Parent)
+		if boundary == nil {
+			continue
+		}
+		trustBoundary := what.model.TrustBoundaries[boundary.key]
+		if element.boundary {
+			trustBoundary.TrustBoundariesNested = append(trustBoundary.TrustBoundariesNested, element.id)
+		} else {
+			trustBoundary.TechnicalAssetsInside = append(trustBoundary.TechnicalAssetsInside, element.id)
+		}
+		what.model.TrustBoundaries[boundary.key] = trustBoundary
+	}
+
+	for _, cellId := range order {
+		cell := cells[cellId]
+		if cell.Edge == "1" {
+			what.addCommunicationLink(elements, cell, edgeLabels[cellId])
+		}
+	}
+}
+
+func (what *drawioImporter) newId(label string) string {
+	id := types.MakeID(label)
+	if len(id) == 0 {
+		id = "element"
+	}
+	return unique(what.ids, id, "-")
+}
+
+func (what *drawioImporter) addTrustBoundary(cell *drawioCell, label string) *drawioElement {
+	element := &drawioElement{key: unique(what.keys, label, " "), id: what.newId(label), boundary: true}
+	trustBoundaryType := types.NetworkOnPrem
+	if value, ok := cell.properties["trust-boundary-type"]; ok {
+		parsed, err := types.ParseTrustBoundary(value)
+		if err != nil {
+			what.notes = append(what.notes, fmt.Sprintf("trust boundary %q: unknown trust-boundary-type %q, using %v", label, value, trustBoundaryType))
+		} else {
+			trustBoundaryType = parsed
+		}
+	}
+	what.model.TrustBoundaries[element.key] = input.TrustBoundary{
+		ID:          element.id,
+		Description: label,
+		Type:        trustBoundaryType.String(),
+	}
+	return element
+}
+
+func (what *drawioImporter) addTechnicalAsset(cell *drawioCell, label string) *drawioElement {
+	element := &drawioElement{key: unique(what.keys, label, " "), id: what.newId(label)}
+	assetType := types.Process
+	style := strings.ToLower(cell.Style)
+	if strings.Contains(style, "cylinder") {
+		assetType = types.Datastore
+	} else if strings.Contains(style, "actor") {
+		assetType = types.ExternalEntity
+	}
+	if value, ok := cell.properties["type"]; ok {
+		parsed, err := types.ParseTechnicalAssetType(value)
+		if err != nil {
+			what.notes = append(what.notes, fmt.Sprintf("technical asset %q: unknown type %q, using %v", label, value, assetType))
+		} else {
+			assetType = parsed
+		}
+	}
+
+	asset := newTechnicalAsset(element.id, label, assetType)
+	if technology, ok := cell.properties["technology"]; ok && len(technology) > 0 {
+		asset.Technology = technology
+	}
+	for _, tag := range strings.Split(cell.properties["tags"], ",") {
+		if tag = strings.TrimSpace(tag); len(tag) > 0 {
+			asset.Tags = append(asset.Tags, tag)
+		}
+	}
+	asset.Internet = strings.EqualFold(cell.properties["internet"], "true")
+	what.model.TechnicalAssets[element.key] = asset
+	return element
+}
+
+func (what *drawioImporter) addCommunicationLink(elements map[string]*drawioElement, cell *drawioCell, placedLabel string) {
+	source, target := elements[cell.Source], elements[cell.Target]
+	if source == nil || target == nil || source.boundary || target.boundary {
+		what.notes = append(what.notes, fmt.Sprintf("arrow %q: skipped as it does not connect two technical assets", cell.Id))
+		return
+	}
+	title := drawioLabel(cell.Value)
+	if len(title) == 0 {
+		title = placedLabel
+	}
+	targetAsset := what.model.TechnicalAssets[target.key]
+	if len(title) == 0 {
+		title = "Access " + targetAsset.Description
+	}
+
+	link := newCommunicationLink(target.id, title)
+	if value, ok := cell.properties["protocol"]; ok {
+		protocol, err := types.ParseProtocol(value)
+		if err != nil {
+			what.notes = append(what.notes, fmt.Sprintf("arrow %q: unknown protocol %q, using %v", title, value, link.Protocol))
+		} else {
+			link.Protocol = protocol.String()
+		}
+	}
+	sourceAsset := what.model.TechnicalAssets[source.key]
+	links := make(map[string]bool)
+	for linkTitle := range sourceAsset.CommunicationLinks {
+		links[linkTitle] = true
+	}
+	sourceAsset.CommunicationLinks[unique(links, title, " ")] = link
+	what.model.TechnicalAssets[source.key] = sourceAsset
+}
+
+// enclosingDrawIOBoundary is the trust boundary of the closest labeled container around the cell, skipping unlabeled
+// ones just grouping shapes
+func enclosingDrawIOBoundary(cells map[string]*drawioCell, elements map[string]*drawioElement, parentId string) *drawioElement {
+	for visited := make(map[string]bool); !visited[parentId]; {
+		visited[parentId] = true
+		if element, ok := elements[parentId]; ok && element.boundary {
+			return element
+		}
+		parent, ok := cells[parentId]
+		if !ok {
+			return nil
+		}
+		parentId = parent.Parent
+	}
+	return nil
+}
+
+func isDrawIOContainer(cell *drawioCell) bool {
+	if _, ok := cell.properties["trust-boundary-type"]; ok {
+		return true
+	}
+	for _, entry := range strings.Split(cell.Style, ";") {
+		if entry == "container=1" || entry == "swimlane" || entry == "group" || strings.HasPrefix(entry, "shape=swimlane") {
+			return true
+		}
+	}
+	return false
+}
+
+var (
+	drawioLineBreaks = regexp.MustCompile(`(?i)<br\s*/?>|</div>|</p>`)
+	drawioTags       = regexp.MustCompile(`<[^>]*>`)
+)
+
+// drawioLabel is the plain text of the (possibly html) label
+func drawioLabel(value string) string {
+	text := drawioTags.ReplaceAllString(drawioLineBreaks.ReplaceAllString(value, " "), "")
+	return strings.Join(strings.Fields(html.UnescapeString(text)), " ")
+}
@@ -0,0 +1,78 @@
+package importer
+
+import (
+	"bytes"
+	"compress/flate"
+	"encoding/base64"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const drawioGraphModelXML = `<mxGraphModel><root>
+	<mxCell id="0"/>
+	<mxCell id="1" parent="0"/>
+	<object label="Cloud" trust-boundary-type="network-cloud-provider" id="cloud"><mxCell style="swimlane" vertex="1" parent="1"/></object>
+	<mxCell id="cluster" value="Cluster" style="rounded=0;container=1;" vertex="1" parent="cloud"/>
+	<mxCell id="grouping" value="" style="group" vertex="1" parent="cluster"/>
+	<object label="Web&lt;br&gt;Server" technology="web-server" tags="pci, frontend" internet="true" id="web"><mxCell style="rounded=1;" vertex="1" parent="grouping"/></object>
+	<mxCell id="db" value="Customer DB" style="shape=cylinder3;" vertex="1" parent="cloud"/>
+	<mxCell id="user" value="Customer" style="shape=umlActor;" vertex="1" parent="1"/>
+	<mxCell id="note" value="" style="text;" vertex="1" parent="1"/>
+	<mxCell id="browse" value="" edge="1" source="user" target="web" parent="1"/>
+	<mxCell id="browse-label" value="Browse" style="edgeLabel;" vertex="1" parent="browse"/>
+	<object label="Query" protocol="jdbc-encrypted" id="query"><mxCell edge="1" source="web" target="db" parent="1"/></object>
+	<mxCell id="dangling" value="Into the cloud" edge="1" source="user" target="cloud" parent="1"/>
+</root></mxGraphModel>`
+
+func TestImportDrawIO(t *testing.T) {
+	model, notes, err := ImportDrawIO(strings.NewReader(drawioGraphModelXML), "shop.drawio")
+	assert.NoError(t, err)
+	assert.Equal(t, "shop", model.Title)
+	assert.Equal(t, []string{`arrow "dangling": skipped as it does not connect two technical assets`}, notes)
+
+	assert.Len(t, model.TrustBoundaries, 2)
+	assert.Equal(t, "network-cloud-provider", model.TrustBoundaries["Cloud"].Type)
+	assert.Equal(t, []string{"cluster"}, model.TrustBoundaries["Cloud"].TrustBoundariesNested)
+	assert.Equal(t, []string{"customer-db"}, model.TrustBoundaries["Cloud"].TechnicalAssetsInside)
+	assert.Equal(t, "network-on-prem", model.TrustBoundaries["Cluster"].Type)
+	assert.Equal(t, []string{"web-server"}, model.TrustBoundaries["Cluster"].TechnicalAssetsInside, "through the unlabeled group")
+
+	assert.Len(t, model.TechnicalAssets, 3)
+	web := model.TechnicalAssets["Web Server"]
+	assert.Equal(t, "web-server", web.ID)
+	assert.Equal(t, "process", web.Type)
+	assert.Equal(t, "web-server", web.Technology)
+	assert.Equal(t, []string{"pci", "frontend"}, web.Tags)
+	assert.Equal(t, []string{"frontend", "pci"}, model.TagsAvailable)
+	assert.True(t, web.Internet)
+	assert.Equal(t, "jdbc-encrypted", web.CommunicationLinks["Query"].Protocol)
+	assert.Equal(t, "customer-db", web.CommunicationLinks["Query"].Target)
+	assert.Equal(t, "datastore", model.TechnicalAssets["Customer DB"].Type)
+
+	customer := model.TechnicalAssets["Customer"]
+	assert.Equal(t, "external-entity", customer.Type)
+	assert.Equal(t, "web-server", customer.CommunicationLinks["Browse"].Target)
+	assert.Equal(t, "unknown-protocol", customer.CommunicationLinks["Browse"].Protocol)
+}
+
+func TestImportDrawIOCompressedPages(t *testing.T) {
+	var deflated bytes.Buffer
+	writer, err := flate.NewWriter(&deflated, flate.BestCompression)
+	assert.NoError(t, err)
+	_, err = writer.Write([]byte(url.QueryEscape(drawioGraphModelXML)))
+	assert.NoError(t, err)
+	assert.NoError(t, writer.Close())
+	file := `<mxfile><diagram name="Page-1">` + base64.StdEncoding.EncodeToString(deflated.Bytes()) + `</diagram>` +
+		`<diagram name="Page-2"><mxGraphModel><root><mxCell id="web" value="Web Server" vertex="1" parent="1"/></root></mxGraphModel></diagram></mxfile>`
+
+	model, _, err := ImportDrawIO(strings.NewReader(file), "shop.xml")
+	assert.NoError(t, err)
+	assert.Len(t, model.TechnicalAssets, 4)
+	assert.Equal(t, "web-server-2", model.TechnicalAssets["Web Server 2"].ID, "the pages share the ids")
+
+	_, _, err = ImportDrawIO(strings.NewReader(`<svg/>`), "shop.svg")
+	assert.ErrorContains(t, err, "unexpected root element")
+}
@@ -0,0 +1,76 @@
+// Package importer converts architecture descriptions of other tools into model stubs, with the technical assets, trust
+// boundaries and communication links pre-populated and their ratings left to be refined in the model file
+package importer
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/threagile/threagile/pkg/docs"
+	"github.com/threagile/threagile/pkg/input"
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+func newModel(title string, source string) *input.Model {
+	model := new(input.Model).Defaults()
+	model.ThreagileVersion = docs.ThreagileVersion
+	model.Title = title
+	model.Date = time.Now().Format("2006-01-02")
+	model.BusinessCriticality = types.Important.String()
+	model.AppDescription = input.Overview{Description: "Imported from " + source}
+	return model
+}
+
+func newTechnicalAsset(id string, description string, assetType types.TechnicalAssetType) input.TechnicalAsset {
+	return input.TechnicalAsset{
+		ID:                     id,
+		Description:            description,
+		Type:                   assetType.String(),
+		Usage:                  types.Business.String(),
+		Size:                   types.Service.String(),
+		Technology:             types.UnknownTechnology,
+		Machine:                types.Virtual.String(),
+		Encryption:             types.NoneEncryption.String(),
+		Confidentiality:        types.Internal.String(),
+		Integrity:              types.Operational.String(),
+		Availability:           types.Operational.String(),
+		JustificationCiaRating: "TODO: rate the technical asset",
+		CustomDevelopedParts:   assetType != types.ExternalEntity,
+		CommunicationLinks:     make(map[string]input.CommunicationLink),
+	}
+}
+
+func newCommunicationLink(targetId string, description string) input.CommunicationLink {
+	return input.CommunicationLink{
+		Target:         targetId,
+		Description:    description,
+		Protocol:       types.UnknownProtocol.String(),
+		Authentication: types.NoneAuthentication.String(),
+		Authorization:  types.NoneAuthorization.String(),
+		Usage:          types.Business.String(),
+	}
+}
+
+// unique is the name or, when already taken, the name with a number suffix (joined by the separator), marked as taken
+func unique(taken map[string]bool, name string, separator string) string {
+	result := name
+	for i := 2; taken[result]; i++ {
+		result = name + separator + strconv.Itoa(i)
+	}
+	taken[result] = true
+	return result
+}
+
+func newDataAsset(id string, description string, origin string) input.DataAsset {
+	return input.DataAsset{
+		ID:                     id,
+		Description:            description,
+		Usage:                  types.Business.String(),
+		Origin:                 origin,
+		Quantity:               types.Many.String(),
+		Confidentiality:        types.Internal.String(),
+		Integrity:              types.Operational.String(),
+		Availability:           types.Operational.String(),
+		JustificationCiaRating: "TODO: rate the data asset",
+	}
+}
@@ -0,0 +1,381 @@
+package importer
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/threagile/threagile/pkg/input"
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+// The OpenAPI convention of ImportOpenAPI (OpenAPI 3 and Swagger 2 specs, as yaml or json):
+//   - the API becomes a process with the technology web-service-rest, on the internet when a server is not local
+//   - the schemas (components.schemas or definitions) become data assets, processed by the API
+//   - each consumer (given or listed in the extension x-consumers of the spec) becomes an external entity with a
+//     communication link to the API, sending the schemas of the request bodies and receiving those of the responses
+//   - the link is rated by the weakest the spec allows: the protocol of the servers (http over https), the
+//     authentication of the security schemes (operations without security make it none) and readonly when all
+//     operations are
+
+type openapiDocument struct {
+	OpenAPI string `yaml:"openapi"`
+	Swagger string `yaml:"swagger"`
+	Info    struct {
+		Title       string `yaml:"title"`
+		Description string `yaml:"description"`
+	} `yaml:"info"`
+	Servers []struct {
+		URL string `yaml:"url"`
+	} `yaml:"servers"`
+	Host       string                     `yaml:"host"`    // Swagger 2
+	Schemes    []string                   `yaml:"schemes"` // Swagger 2
+	Paths      map[string]openapiPathItem `yaml:"paths"`
+	Security   []map[string][]string      `yaml:"security"`
+	Components struct {
+		Schemas         map[string]*openapiSchema        `yaml:"schemas"`
+		SecuritySchemes map[string]openapiSecurityScheme `yaml:"securitySchemes"`
+	} `yaml:"components"`
+	Definitions         map[string]*openapiSchema        `yaml:"definitions"`         // Swagger 2
+	SecurityDefinitions map[string]openapiSecurityScheme `yaml:"securityDefinitions"` // Swagger 2
+	Consumers           []string                         `yaml:"x-consumers"`
+}
+
+type openapiPathItem struct {
+	Get     *openapiOperation `yaml:"get"`
+	Put     *openapiOperation `yaml:"put"`
+	Post    *openapiOperation `yaml:"post"`
+	Delete  *openapiOperation `yaml:"delete"`
+	Options *openapiOperation `yaml:"options"`
+	Head    *openapiOperation `yaml:"head"`
+	Patch   *openapiOperation `yaml:"patch"`
+	Trace   *openapiOperation `yaml:"trace"`
+}
+
+type openapiOperation struct {
+	RequestBody *struct {
+		Content map[string]openapiMediaType `yaml:"content"`
+	} `yaml:"requestBody"`
+	Parameters []struct {
+		In     string         `yaml:"in"`
+		Schema *openapiSchema `yaml:"schema"` // of body parameters in Swagger 2
+	} `yaml:"parameters"`
+	Responses map[string]struct {
+		Content map[string]openapiMediaType `yaml:"content"`
+		Schema  *openapiSchema              `yaml:"schema"` // Swagger 2
+	} `yaml:"responses"`
+	Security *[]map[string][]string `yaml:"security"` // overriding the one of the spec, when given
+}
+
+type openapiMediaType struct {
+	Schema *openapiSchema `yaml:"schema"`
+}
+
+type openapiSchema struct {
+	Ref         string           `yaml:"$ref"`
+	Description string           `yaml:"description"`
+	Items       *openapiSchema   `yaml:"items"`
+	AllOf       []*openapiSchema `yaml:"allOf"`
+	OneOf       []*openapiSchema `yaml:"oneOf"`
+	AnyOf       []*openapiSchema `yaml:"anyOf"`
+}
+
+type openapiSecurityScheme struct {
+	Type   string `yaml:"type"`
+	Scheme string `yaml:"scheme"`
+}
+
+// ImportOpenAPI converts the API spec into a model stub (see the convention above) with a communication link of each
+// consumer (the API Consumer when neither given nor listed in the spec), returning the notes about the guesses made
+func ImportOpenAPI(reader io.Reader, filename string, consumers []string) (*input.Model, []string, error) {
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to read OpenAPI spec: %w", err)
+	}
+	var spec openapiDocument
+	err = yaml.Unmarshal(data, &spec) // json is yaml as well
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to parse OpenAPI spec: %w", err)
+	}
+	if !strings.HasPrefix(spec.OpenAPI, "3.") && spec.Swagger != "2.0" {
+		return nil, nil, fmt.Errorf("unable to parse OpenAPI spec: neither OpenAPI 3 nor Swagger 2")
+	}
+
+	title := strings.TrimSpace(spec.Info.Title)
+	if len(title) == 0 {
+		title = strings.TrimSuffix(filepath.Base(filename), filepath.Ext(filename))
+	}
+	model := newModel(title, "OpenAPI spec "+filepath.Base(filename))
+	importer := &openapiImporter{spec: &spec, model: model, ids: make(map[string]bool), schemaIds: make(map[string]string), notes: make([]string, 0)}
+
+	importer.addDataAssets()
+	api := importer.addAPI(title)
+	if len(consumers) == 0 {
+		consumers = spec.Consumers
+	}
+	if len(consumers) == 0 {
+		consumers = []string{"API Consumer"}
+	}
+	link := importer.newLink(api)
+	keys := map[string]bool{title: true}
+	for _, consumer := range consumers {
+		asset := newTechnicalAsset(importer.newId(consumer), consumer, types.ExternalEntity)
+		asset.Internet = model.TechnicalAssets[title].Internet
+		asset.CommunicationLinks["Call "+title] = link
+		model.TechnicalAssets[unique(keys, consumer, " ")] = asset
+	}
+	return model, importer.notes, nil
+}
+
+type openapiImporter struct {
+	spec      *openapiDocument
+	model     *input.Model
+	ids       map[string]bool
+	schemaIds map[string]string // the data asset ids by schema name
+	notes     []string
+}
+
+func (what *openapiImporter) newId(name string) string {
+	id := types.MakeID(name)
+	if len(id) == 0 {
+		id = "element"
+	}
+	return unique(what.ids, id, "-")
+}
+
+// note adds the note, once for all operations
+func (what *openapiImporter) note(text string) {
+	for _, note := range what.notes {
+		if note == text {
+			return
+		}
+	}
+	what.notes = append(what.notes, text)
+}
+
+func (what *openapiImporter) schemas() map[string]*openapiSchema {
+	if what.spec.Components.Schemas != nil {
+		return what.spec.Components.Schemas
+	}
+	return what.spec.Definitions
+}
+
+func (what *openapiImporter) addDataAssets() {
+	names := make([]string, 0)
+	for name := range what.schemas() {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		description := strings.TrimSpace(what.schemas()[name].Description)
+		if len(description) == 0 {
+			description = name
+		}
+		what.schemaIds[name] = what.newId(name)
+		what.model.DataAssets[name] = newDataAsset(what.schemaIds[name], description, "API "+what.model.Title)
+	}
+}
+
+func (what *openapiImporter) addAPI(title string) string {
+	id := what.newId(title)
+	description := strings.TrimSpace(what.spec.Info.Description)
+	if len(description) == 0 {
+		description = title
+	}
+	asset := newTechnicalAsset(id, description, types.Process)
+	asset.Technology = types.WebServiceREST
+	for _, serverURL := range what.serverURLs() {
+		asset.Internet = asset.Internet || !isLocalHost(serverURL.Hostname())
+	}
+	for name := range what.schemaIds {
+		asset.DataAssetsProcessed = append(asset.DataAssetsProcessed, what.schemaIds[name])
+	}
+	sort.Strings(asset.DataAssetsProcessed)
+	what.model.TechnicalAssets[title] = asset
+	return id
+}
+
+// serverURLs are the absolute ones the API is served at
+func (what *openapiImporter) serverURLs() []*url.URL {
+	urls := make([]*url.URL, 0)
+	add := func(value string) {
+		parsed, err := url.Parse(value)
+		if err == nil && len(parsed.Scheme) > 0 && len(parsed.Host) > 0 && !strings.Contains(parsed.Host, "{") {
+			urls = append(urls, parsed)
+		}
+	}
+	for _, server := range what.spec.Servers {
+		add(server.URL)
+	}
+	for _, scheme := range what.spec.Schemes {
+		if len(what.spec.Host) > 0 {
+			add(scheme + "://" + what.spec.Host)
+		}
+	}
+	return urls
+}
+
+// newLink is the link of the consumers to the API
+func (what *openapiImporter) newLink(apiId string) input.CommunicationLink {
+	link := newCommunicationLink(apiId, "Call "+what.model.Title)
+	urls := what.serverURLs()
+	for _, serverURL := range urls {
+		if strings.EqualFold(serverURL.Scheme, "http") || link.Protocol == types.UnknownProtocol.String() {
+			link.Protocol = strings.ToLower(serverURL.Scheme)
+		}
+	}
+	if _, err := types.ParseProtocol(link.Protocol); err != nil || len(urls) == 0 {
+		link.Protocol = types.UnknownProtocol.String()
+		what.note("no http or https server declared, the protocol is left unknown")
+	}
+
+	sent, received := make(map[string]bool), make(map[string]bool)
+	authentication, authorization := types.ClientCertificate, types.NoneAuthorization
+	operations, readonly := 0, true
+	for _, path := range what.spec.Paths {
+		for method, operation := range path.operations() {
+			operations++
+			readonly = readonly && (method == "get" || method == "head" || method == "options" || method == "trace")
+			if operation.RequestBody != nil {
+				for _, mediaType := range operation.RequestBody.Content {
+					what.collectSchemas(mediaType.Schema, sent)
+				}
+			}
+			for _, parameter := range operation.Parameters {
+				if parameter.In == "body" {
+					what.collectSchemas(parameter.Schema, sent)
+				}
+			}
+			for _, response := range operation.Responses {
+				what.collectSchemas(response.Schema, received)
+				for _, mediaType := range response.Content {
+					what.collectSchemas(mediaType.Schema, received)
+				}
+			}
+
+			requirements := what.spec.Security
+			if operation.Security != nil {
+				requirements = *operation.Security
+			}
+			operationAuthentication, operationAuthorization := what.authenticationOf(requirements)
+			if operationAuthentication < authentication {
+				authentication = operationAuthentication
+			}
+			if operationAuthorization > authorization {
+				authorization = operationAuthorization
+			}
+		}
+	}
+	if operations == 0 {
+		authentication, readonly = types.NoneAuthentication, false
+		what.note("no operations declared, the link carries no data assets")
+	}
+	link.Authentication = authentication.String()
+	if authentication == types.NoneAuthentication {
+		authorization = types.NoneAuthorization
+	}
+	link.Authorization = authorization.String()
+	link.Readonly = readonly
+	link.DataAssetsSent = sortedKeys(sent)
+	link.DataAssetsReceived = sortedKeys(received)
+	return link
+}
+
+func (what openapiPathItem) operations() map[string]*openapiOperation {
+	operations := make(map[string]*openapiOperation)
+	for method, operation := range map[string]*openapiOperation{
+		"get": what.Get, "put": what.Put, "post": what.Post, "delete": what.Delete,
+		"options": what.Options, "head": what.Head, "patch": what.Patch, "trace": what.Trace,
+	} {
+		if operation != nil {
+			operations[method] = operation
+		}
+	}
+	return operations
+}
+
+// collectSchemas adds the data asset ids of the schemas referenced (as a whole, an array or a composition) to the ids
+func (what *openapiImporter) collectSchemas(schema *openapiSchema, ids map[string]bool) {
+	if schema == nil {
+		return
+	}
+	if len(schema.Ref) > 0 {
+		name := schema.Ref[strings.LastIndex(schema.Ref, "/")+1:]
+		if id, ok := what.schemaIds[name]; ok {
+			ids[id] = true
+		}
+		return
+	}
+	what.collectSchemas(schema.Items, ids)
+	for _, composed := range append(append(append([]*openapiSchema{}, schema.AllOf...), schema.OneOf...), schema.AnyOf...) {
+		what.collectSchemas(composed, ids)
+	}
+}
+
+// authenticationOf is the authentication of the security requirements, any of which is sufficient, and the
+// authorization when they pass the identity of the end user (OAuth 2 or OpenID Connect)
+func (what *openapiImporter) authenticationOf(requirements []map[string][]string) (types.Authentication, types.Authorization) {
+	if len(requirements) == 0 {
+		return types.NoneAuthentication, types.NoneAuthorization
+	}
+	securitySchemes := what.spec.Components.SecuritySchemes
+	if securitySchemes == nil {
+		securitySchemes = what.spec.SecurityDefinitions
+	}
+	weakest, authorization := types.ClientCertificate, types.TechnicalUser
+	for _, requirement := range requirements {
+		strongest := types.NoneAuthentication // all schemes of a requirement are needed
+		for name := range requirement {
+			securityScheme, ok := securitySchemes[name]
+			if !ok {
+				what.note(fmt.Sprintf("unknown security scheme %q, treated as no authentication", name))
+				continue
+			}
+			authentication := types.Token
+			switch strings.ToLower(securityScheme.Type) {
+			case "basic":
+				authentication = types.Credentials
+			case "http":
+				if strings.EqualFold(securityScheme.Scheme, "basic") {
+					authentication = types.Credentials
+				}
+			case "mutualtls":
+				authentication = types.ClientCertificate
+			case "oauth2", "openidconnect":
+				authorization = types.EndUserIdentityPropagation
+			}
+			if authentication > strongest {
+				strongest = authentication
+			}
+		}
+		if strongest < weakest {
+			weakest = strongest
+		}
+	}
+	return weakest, authorization
+}
+
+// isLocalHost tells whether the host is not reachable from the internet
+func isLocalHost(host string) bool {
+	host = strings.ToLower(host)
+	if host == "localhost" || strings.HasSuffix(host, ".local") || strings.HasSuffix(host, ".internal") || strings.HasSuffix(host, ".localhost") {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && (ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast())
+}
+
+func sortedKeys(values map[string]bool) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,113 @@
+package importer
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const openapiSpecYAML = `openapi: 3.0.3
+info:
+  title: Order API
+servers:
+  - url: https://api.example.com/v1
+  - url: http://localhost:8080
+paths:
+  /orders:
+    get:
+      security: []
+      responses:
+        200:
+          content:
+            application/json:
+              schema:
+                type: array
+                items:
+                  $ref: '#/components/schemas/Order'
+    post:
+      requestBody:
+        content:
+          application/json:
+            schema:
+              $ref: '#/components/schemas/Order'
+      responses:
+        '201':
+          content:
+            application/json:
+              schema:
+                allOf:
+                  - $ref: '#/components/schemas/Receipt'
+security:
+  - bearer: []
+components:
+  securitySchemes:
+    bearer:
+      type: http
+      scheme: bearer
+  schemas:
+    Order:
+      description: An order of a customer
+      type: object
+    Receipt:
+      type: object
+x-consumers:
+  - Web Shop
+  - Mobile App
+`
+
+func TestImportOpenAPI(t *testing.T) {
+	model, notes, err := ImportOpenAPI(strings.NewReader(openapiSpecYAML), "orders.yaml", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "Order API", model.Title)
+	assert.Empty(t, notes)
+
+	assert.Len(t, model.DataAssets, 2)
+	assert.Equal(t, "order", model.DataAssets["Order"].ID)
+	assert.Equal(t, "An order of a customer", model.DataAssets["Order"].Description)
+
+	api := model.TechnicalAssets["Order API"]
+	assert.Equal(t, "order-api", api.ID)
+	assert.Equal(t, "web-service-rest", api.Technology)
+	assert.True(t, api.Internet)
+	assert.Equal(t, []string{"order", "receipt"}, api.DataAssetsProcessed)
+
+	assert.Len(t, model.TechnicalAssets, 3)
+	shop := model.TechnicalAssets["Web Shop"]
+	assert.Equal(t, "external-entity", shop.Type)
+	link := shop.CommunicationLinks["Call Order API"]
+	assert.Equal(t, "order-api", link.Target)
+	assert.Equal(t, "http", link.Protocol, "the weakest server")
+	assert.Equal(t, "none", link.Authentication, "listing the orders needs none")
+	assert.Equal(t, "none", link.Authorization)
+	assert.False(t, link.Readonly)
+	assert.Equal(t, []string{"order"}, link.DataAssetsSent)
+	assert.Equal(t, []string{"order", "receipt"}, link.DataAssetsReceived)
+	assert.Equal(t, "mobile-app", model.TechnicalAssets["Mobile App"].ID)
+}
+
+func TestImportSwagger(t *testing.T) {
+	spec := `{"swagger": "2.0", "info": {"title": "Users"}, "host": "users.internal", "schemes": ["https"],
+		"securityDefinitions": {"oauth": {"type": "oauth2"}}, "security": [{"oauth": []}],
+		"paths": {"/users/{id}": {"get": {"responses": {"200": {"schema": {"$ref": "#/definitions/User"}}}}}},
+		"definitions": {"User": {"type": "object"}}}`
+	model, notes, err := ImportOpenAPI(strings.NewReader(spec), "users.json", []string{"Admin Portal"})
+	assert.NoError(t, err)
+	assert.Empty(t, notes)
+	assert.False(t, model.TechnicalAssets["Users"].Internet)
+	link := model.TechnicalAssets["Admin Portal"].CommunicationLinks["Call Users"]
+	assert.Equal(t, "https", link.Protocol)
+	assert.Equal(t, "token", link.Authentication)
+	assert.Equal(t, "end-user-identity-propagation", link.Authorization)
+	assert.True(t, link.Readonly)
+	assert.Equal(t, []string{"user"}, link.DataAssetsReceived)
+
+	model, notes, err = ImportOpenAPI(strings.NewReader(`{"swagger": "2.0"}`), "empty.json", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "empty", model.Title)
+	assert.Contains(t, model.TechnicalAssets, "API Consumer")
+	assert.Len(t, notes, 2)
+
+	_, _, err = ImportOpenAPI(strings.NewReader(`asyncapi: 2.6.0`), "events.yaml", nil)
+	assert.ErrorContains(t, err, "neither OpenAPI 3 nor Swagger 2")
+}
@@ -0,0 +1,485 @@
+package importer
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/threagile/threagile/pkg/input"
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+// The Terraform convention of ImportTerraform, reading a state file or the json of terraform show -json (of a state or
+// a plan, which is the way to import HCL configurations):
+//   - networks (VPCs) become trust boundaries of the type network-cloud-provider, their subnets nested trust
+//     boundaries of the type network-virtual-lan
+//   - VMs, databases, buckets, queues, load balancers and functions (see terraformAssetKinds) become technical
+//     assets, titled by their tag Name or resource name, inside the trust boundary of their subnet (or network, when
+//     in several subnets)
+//   - ingress rules of security groups allowing another security group become communication links from the technical
+//     assets of that group to the ones of the allowing group, with the protocol guessed from the port, ingress rules
+//     allowing 0.0.0.0/0 put the technical assets of the allowing group on the internet
+//   - all other resources are skipped
+
+type terraformState struct {
+	Version   int `json:"version"`
+	Resources []struct {
+		Module    string `json:"module"`
+		Mode      string `json:"mode"`
+		Type      string `json:"type"`
+		Name      string `json:"name"`
+		Instances []struct {
+			IndexKey   any            `json:"index_key"`
+			Attributes map[string]any `json:"attributes"`
+		} `json:"instances"`
+	} `json:"resources"`
+	Values        *terraformValues `json:"values"`         // terraform show -json of a state
+	PlannedValues *terraformValues `json:"planned_values"` // terraform show -json of a plan
+}
+
+type terraformValues struct {
+	RootModule terraformModule `json:"root_module"`
+}
+
+type terraformModule struct {
+	Resources []struct {
+		Address string         `json:"address"`
+		Mode    string         `json:"mode"`
+		Type    string         `json:"type"`
+		Name    string         `json:"name"`
+		Index   any            `json:"index"`
+		Values  map[string]any `json:"values"`
+	} `json:"resources"`
+	ChildModules []terraformModule `json:"child_modules"`
+}
+
+// terraformResource is a managed resource (an instance of it, when created with count or for_each)
+type terraformResource struct {
+	address      string
+	resourceType string
+	name         string
+	attributes   map[string]any
+}
+
+type terraformAssetKind struct {
+	assetType  types.TechnicalAssetType
+	technology string
+	machine    types.TechnicalAssetMachine
+}
+
+var (
+	terraformVM           = terraformAssetKind{types.Process, types.ApplicationServer, types.Virtual}
+	terraformDatabase     = terraformAssetKind{types.Datastore, types.Database, types.Virtual}
+	terraformStorage      = terraformAssetKind{types.Datastore, types.BlockStorage, types.Virtual}
+	terraformQueue        = terraformAssetKind{types.Datastore, types.MessageQueue, types.Virtual}
+	terraformLoadBalancer = terraformAssetKind{types.Process, types.LoadBalancer, types.Virtual}
+	terraformFunction     = terraformAssetKind{types.Process, types.Function, types.Serverless}
+)
+
+// terraformAssetKinds are the resource types (of AWS, Azure and Google Cloud) taken over as technical assets
+var terraformAssetKinds = map[string]terraformAssetKind{
+	"aws_instance":                    terraformVM,
+	"azurerm_linux_virtual_machine":   terraformVM,
+	"azurerm_virtual_machine":         terraformVM,
+	"azurerm_windows_virtual_machine": terraformVM,
+	"google_compute_instance":         terraformVM,
+
+	"aws_db_instance":              terraformDatabase,
+	"aws_dynamodb_table":           terraformDatabase,
+	"aws_elasticache_cluster":      terraformDatabase,
+	"aws_rds_cluster":              terraformDatabase,
+	"azurerm_cosmosdb_account":     terraformDatabase,
+	"azurerm_mssql_server":         terraformDatabase,
+	"azurerm_mysql_server":         terraformDatabase,
+	"azurerm_postgresql_server":    terraformDatabase,
+	"google_sql_database_instance": terraformDatabase,
+
+	"aws_efs_file_system":     terraformStorage,
+	"aws_s3_bucket":           terraformStorage,
+	"azurerm_storage_account": terraformStorage,
+	"google_storage_bucket":   terraformStorage,
+
+	"aws_kinesis_stream":       terraformQueue,
+	"aws_mq_broker":            terraformQueue,
+	"aws_sns_topic":            terraformQueue,
+	"aws_sqs_queue":            terraformQueue,
+	"azurerm_servicebus_queue": terraformQueue,
+	"google_pubsub_topic":      terraformQueue,
+
+	"aws_alb":                     terraformLoadBalancer,
+	"aws_elb":                     terraformLoadBalancer,
+	"aws_lb":                      terraformLoadBalancer,
+	"azurerm_application_gateway": terraformLoadBalancer,
+	"azurerm_lb":                  terraformLoadBalancer,
+
+	"aws_lambda_function":            terraformFunction,
+	"azurerm_function_app":           terraformFunction,
+	"google_cloudfunctions_function": terraformFunction,
+}
+
+var (
+	terraformNetworkTypes = map[string]bool{"aws_vpc": true, "azurerm_virtual_network": true, "google_compute_network": true}
+	terraformSubnetTypes  = map[string]bool{"aws_subnet": true, "azurerm_subnet": true, "google_compute_subnetwork": true}
+
+	// terraformReferencedTypes are not taken over but consulted for the trust boundaries and communication links
+	terraformReferencedTypes = map[string]bool{"aws_db_subnet_group": true, "aws_security_group": true,
+		"aws_security_group_rule": true, "aws_vpc_security_group_ingress_rule": true}
+)
+
+// ImportTerraform converts the Terraform state (see the convention above) into a model stub, returning the notes
+// about the resources not taken over
+func ImportTerraform(reader io.Reader, filename string) (*input.Model, []string, error) {
+	var state terraformState
+	err := json.NewDecoder(reader).Decode(&state)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to parse Terraform state: %w", err)
+	}
+	resources := make([]*terraformResource, 0)
+	switch {
+	case state.Values != nil:
+		resources = state.Values.RootModule.resources(resources)
+	case state.PlannedValues != nil:
+		resources = state.PlannedValues.RootModule.resources(resources)
+	case state.Version > 0:
+		for _, resource := range state.Resources {
+			if resource.Mode != "managed" {
+				continue
+			}
+			address := resource.Type + "." + resource.Name
+			if len(resource.Module) > 0 {
+				address = resource.Module + "." + address
+			}
+			for _, instance := range resource.Instances {
+				resources = append(resources, &terraformResource{
+					address:      address + terraformIndex(instance.IndexKey),
+					resourceType: resource.Type,
+					name:         resource.Name + terraformIndex(instance.IndexKey),
+					attributes:   instance.Attributes,
+				})
+			}
+		}
+	default:
+		return nil, nil, fmt.Errorf("unable to parse Terraform state: neither a state file nor the json of terraform show")
+	}
+
+	model := newModel(strings.TrimSuffix(filepath.Base(filename), filepath.Ext(filename)), "Terraform state "+filepath.Base(filename))
+	importer := &terraformImporter{model: model, resources: resources, keys: make(map[string]bool), ids: make(map[string]bool),
+		boundaries: make(map[*terraformResource]string), assets: make(map[*terraformResource]string), notes: make([]string, 0)}
+	importer.addTrustBoundaries()
+	importer.addTechnicalAssets()
+	importer.addCommunicationLinks()
+	return model, importer.notes, nil
+}
+
+func (what terraformModule) resources(resources []*terraformResource) []*terraformResource {
+	for _, resource := range what.Resources {
+		if resource.Mode == "managed" {
+			resources = append(resources, &terraformResource{
+				address:      resource.Address,
+				resourceType: resource.Type,
+				name:         resource.Name + terraformIndex(resource.Index),
+				attributes:   resource.Values,
+			})
+		}
+	}
+	for _, childModule := range what.ChildModules {
+		resources = childModule.resources(resources)
+	}
+	return resources
+}
+
+func terraformIndex(index any) string {
+	switch value := index.(type) {
+	case float64:
+		return "[" + strconv.Itoa(int(value)) + "]"
+	case string:
+		return "[" + strconv.Quote(value) + "]"
+	}
+	return ""
+}
+
+type terraformImporter struct {
+	model      *input.Model
+	resources  []*terraformResource
+	keys       map[string]bool // of the elements in the model file
+	ids        map[string]bool
+	boundaries map[*terraformResource]string // the keys of the trust boundaries of the networks and subnets
+	assets     map[*terraformResource]string // the keys of the technical assets
+	notes      []string
+}
+
+// title is the tag Name of the resource, if any, or its resource name
+func (what *terraformResource) title() string {
+	if tags, ok := what.attributes["tags"].(map[string]any); ok {
+		if name, ok := tags["Name"].(string); ok && len(strings.TrimSpace(name)) > 0 {
+			return strings.TrimSpace(name)
+		}
+	}
+	return what.name
+}
+
+// references tells whether the value (an id, self link or name) references the resource
+func (what *terraformResource) references(value string) bool {
+	if len(value) == 0 {
+		return false
+	}
+	for _, attribute := range []string{"id", "arn", "self_link", "name"} {
+		if terraformString(what.attributes, attribute) == value {
+			return true
+		}
+	}
+	return false
+}
+
+func (what *terraformImporter) find(resourceTypes map[string]bool, reference string) *terraformResource {
+	for _, resource := range what.resources {
+		if resourceTypes[resource.resourceType] && resource.references(reference) {
+			return resource
+		}
+	}
+	return nil
+}
+
+func (what *terraformImporter) newElement(title string) (string, string) {
+	id := types.MakeID(title)
+	if len(id) == 0 {
+		id = "element"
+	}
+	return unique(what.keys, title, " "), unique(what.ids, id, "-")
+}
+
+func (what *terraformImporter) addTrustBoundaries() {
+	for _, resource := range what.resources {
+		if terraformNetworkTypes[resource.resourceType] || terraformSubnetTypes[resource.resourceType] {
+			key, id := what.newElement(resource.title())
+			boundaryType := types.NetworkCloudProvider
+			if terraformSubnetTypes[resource.resourceType] {
+				boundaryType = types.NetworkVirtualLAN
+			}
+			what.model.TrustBoundaries[key] = input.TrustBoundary{
+				ID:          id,
+				Description: "Imported from " + resource.address,
+				Type:        boundaryType.String(),
+			}
+			what.boundaries[resource] = key
+		}
+	}
+	for _, resource := range what.resources {
+		if !terraformSubnetTypes[resource.resourceType] {
+			continue
+		}
+		for _, attribute := range []string{"vpc_id", "network", "virtual_network_name"} {
+			if network := what.find(terraformNetworkTypes, terraformString(resource.attributes, attribute)); network != nil {
+				trustBoundary := what.model.TrustBoundaries[what.boundaries[network]]
+				trustBoundary.TrustBoundariesNested = append(trustBoundary.TrustBoundariesNested, what.model.TrustBoundaries[what.boundaries[resource]].ID)
+				what.model.TrustBoundaries[what.boundaries[network]] = trustBoundary
+				break
+			}
+		}
+	}
+}
+
+func (what *terraformImporter) addTechnicalAssets() {
+	skipped := make(map[string]bool)
+	for _, resource := range what.resources {
+		kind, ok := terraformAssetKinds[resource.resourceType]
+		if !ok {
+			if !terraformNetworkTypes[resource.resourceType] && !terraformSubnetTypes[resource.resourceType] && !terraformReferencedTypes[resource.resourceType] {
+				skipped[resource.resourceType] = true
+			}
+			continue
+		}
+		key, id := what.newElement(resource.title())
+		asset := newTechnicalAsset(id, "Imported from "+resource.address, kind.assetType)
+		asset.Technology = kind.technology
+		asset.Machine = kind.machine.String()
+		asset.CustomDevelopedParts = kind.assetType == types.Process && kind.technology != types.LoadBalancer
+		asset.Internet = terraformBool(resource.attributes, "associate_public_ip_address") || terraformBool(resource.attributes, "publicly_accessible") ||
+			(kind == terraformLoadBalancer && resource.attributes["internal"] == false)
+		what.model.TechnicalAssets[key] = asset
+		what.assets[resource] = key
+
+		if boundary := what.boundaryOf(resource); boundary != nil {
+			trustBoundary := what.model.TrustBoundaries[what.boundaries[boundary]]
+			trustBoundary.TechnicalAssetsInside = append(trustBoundary.TechnicalAssetsInside, id)
+			what.model.TrustBoundaries[what.boundaries[boundary]] = trustBoundary
+		}
+	}
+	if len(skipped) > 0 {
+		what.notes = append(what.notes, "skipped resource types: "+strings.Join(sortedKeys(skipped), ", "))
+	}
+}
+
+// boundaryOf is the subnet the resource is in or, when in several subnets, their network
+func (what *terraformImporter) boundaryOf(resource *terraformResource) *terraformResource {
+	subnetIds := make([]string, 0)
+	for _, attribute := range []string{"subnet_id", "subnet_ids", "subnets"} {
+		subnetIds = append(subnetIds, terraformStrings(resource.attributes, attribute)...)
+	}
+	if subnetGroupName := terraformString(resource.attributes, "db_subnet_group_name"); len(subnetGroupName) > 0 {
+		if subnetGroup := what.find(map[string]bool{"aws_db_subnet_group": true}, subnetGroupName); subnetGroup != nil {
+			subnetIds = append(subnetIds, terraformStrings(subnetGroup.attributes, "subnet_ids")...)
+		}
+	}
+	subnets := make(map[*terraformResource]bool)
+	var subnet *terraformResource
+	for _, subnetId := range subnetIds {
+		if found := what.find(terraformSubnetTypes, subnetId); found != nil {
+			subnets[found], subnet = true, found
+		}
+	}
+	if len(subnets) == 1 {
+		return subnet
+	}
+	networkId := terraformString(resource.attributes, "vpc_id")
+	if subnet != nil {
+		networkId = terraformString(subnet.attributes, "vpc_id")
+	}
+	return what.find(terraformNetworkTypes, networkId)
+}
+
+// terraformIngress is an ingress rule of a security group, from another security group or the internet
+type terraformIngress struct {
+	securityGroup       string
+	sourceSecurityGroup string
+	fromInternet        bool
+	fromPort, toPort    int
+}
+
+func (what *terraformImporter) ingressRules() []terraformIngress {
+	rules := make([]terraformIngress, 0)
+	add := func(securityGroup string, attributes map[string]any, sourceAttributes []string, cidrAttributes []string) {
+		rule := terraformIngress{securityGroup: securityGroup, fromPort: terraformInt(attributes, "from_port"), toPort: terraformInt(attributes, "to_port")}
+		for _, cidrAttribute := range cidrAttributes {
+			for _, cidr := range terraformStrings(attributes, cidrAttribute) {
+				rule.fromInternet = rule.fromInternet || cidr == "0.0.0.0/0" || cidr == "::/0"
+			}
+		}
+		if rule.fromInternet {
+			rules = append(rules, rule)
+			rule.fromInternet = false
+		}
+		for _, sourceAttribute := range sourceAttributes {
+			for _, sourceSecurityGroup := range terraformStrings(attributes, sourceAttribute) {
+				rule.sourceSecurityGroup = sourceSecurityGroup
+				rules = append(rules, rule)
+			}
+		}
+	}
+	for _, resource := range what.resources {
+		switch resource.resourceType {
+		case "aws_security_group":
+			ingress, _ := resource.attributes["ingress"].([]any)
+			for _, item := range ingress {
+				if attributes, ok := item.(map[string]any); ok {
+					add(terraformString(resource.attributes, "id"), attributes, []string{"security_groups"}, []string{"cidr_blocks", "ipv6_cidr_blocks"})
+				}
+			}
+		case "aws_security_group_rule":
+			if terraformString(resource.attributes, "type") == "ingress" {
+				add(terraformString(resource.attributes, "security_group_id"), resource.attributes, []string{"source_security_group_id"}, []string{"cidr_blocks", "ipv6_cidr_blocks"})
+			}
+		case "aws_vpc_security_group_ingress_rule":
+			add(terraformString(resource.attributes, "security_group_id"), resource.attributes, []string{"referenced_security_group_id"}, []string{"cidr_ipv4", "cidr_ipv6"})
+		}
+	}
+	return rules
+}
+
+// assetsInSecurityGroup are the technical assets of the security group, by its id or name
+func (what *terraformImporter) assetsInSecurityGroup(securityGroup string) []*terraformResource {
+	names := make(map[string]bool)
+	if group := what.find(map[string]bool{"aws_security_group": true}, securityGroup); group != nil {
+		names[terraformString(group.attributes, "id")], names[terraformString(group.attributes, "name")] = true, true
+	}
+	names[securityGroup] = true
+	assets := make([]*terraformResource, 0)
+	for _, resource := range what.resources {
+		if _, ok := what.assets[resource]; !ok {
+			continue
+		}
+		for _, attribute := range []string{"vpc_security_group_ids", "security_groups", "security_group_ids"} {
+			for _, member := range terraformStrings(resource.attributes, attribute) {
+				if names[member] {
+					assets = append(assets, resource)
+				}
+			}
+		}
+	}
+	return assets
+}
+
+func (what *terraformImporter) addCommunicationLinks() {
+	for _, rule := range what.ingressRules() {
+		targets := what.assetsInSecurityGroup(rule.securityGroup)
+		if rule.fromInternet {
+			for _, target := range targets {
+				asset := what.model.TechnicalAssets[what.assets[target]]
+				asset.Internet = true
+				what.model.TechnicalAssets[what.assets[target]] = asset
+			}
+			continue
+		}
+		title, protocol := "All Ports", types.UnknownProtocol
+		if rule.fromPort == rule.toPort && rule.fromPort > 0 {
+			title = "Port " + strconv.Itoa(rule.fromPort)
+			protocol = types.ProtocolOfPort(rule.fromPort)
+		} else if rule.fromPort > 0 || rule.toPort > 0 {
+			title = fmt.Sprintf("Ports %v-%v", rule.fromPort, rule.toPort)
+		}
+		for _, source := range what.assetsInSecurityGroup(rule.sourceSecurityGroup) {
+			for _, target := range targets {
+				if source == target {
+					continue
+				}
+				sourceAsset := what.model.TechnicalAssets[what.assets[source]]
+				linkTitle := "Access " + what.assets[target] + " on " + title
+				link := newCommunicationLink(what.model.TechnicalAssets[what.assets[target]].ID, linkTitle)
+				link.Protocol = protocol.String()
+				links := make(map[string]bool)
+				for existing := range sourceAsset.CommunicationLinks {
+					links[existing] = true
+				}
+				sourceAsset.CommunicationLinks[unique(links, linkTitle, " ")] = link
+				what.model.TechnicalAssets[what.assets[source]] = sourceAsset
+			}
+		}
+	}
+}
+
+func terraformString(attributes map[string]any, name string) string {
+	value, _ := attributes[name].(string)
+	return value
+}
+
+// terraformStrings are the strings of the list (or the single string) of the attribute
+func terraformStrings(attributes map[string]any, name string) []string {
+	switch value := attributes[name].(type) {
+	case string:
+		if len(value) > 0 {
+			return []string{value}
+		}
+	case []any:
+		values := make([]string, 0, len(value))
+		for _, item := range value {
+			if text, ok := item.(string); ok && len(text) > 0 {
+				values = append(values, text)
+			}
+		}
+		return values
+	}
+	return nil
+}
+
+func terraformInt(attributes map[string]any, name string) int {
+	value, _ := attributes[name].(float64)
+	return int(value)
+}
+
+func terraformBool(attributes map[string]any, name string) bool {
+	value, _ := attributes[name].(bool)
+	return value
+}
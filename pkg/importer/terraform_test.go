@@ -0,0 +1,73 @@
+package importer
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const terraformStateJSON = `{"version": 4, "resources": [
+	{"mode": "managed", "type": "aws_vpc", "name": "main", "instances": [{"attributes": {"id": "vpc-1", "tags": {"Name": "Shop VPC"}}}]},
+	{"mode": "managed", "type": "aws_subnet", "name": "public", "instances": [{"attributes": {"id": "subnet-a", "vpc_id": "vpc-1"}}]},
+	{"mode": "managed", "type": "aws_subnet", "name": "private", "instances": [{"attributes": {"id": "subnet-b", "vpc_id": "vpc-1"}}]},
+	{"mode": "managed", "type": "aws_lb", "name": "front", "instances": [{"attributes": {"id": "lb-1", "internal": false, "subnets": ["subnet-a", "subnet-b"], "security_groups": ["sg-lb"]}}]},
+	{"mode": "managed", "type": "aws_instance", "name": "app", "instances": [
+		{"index_key": 0, "attributes": {"id": "i-1", "subnet_id": "subnet-a", "vpc_security_group_ids": ["sg-app"]}},
+		{"index_key": 1, "attributes": {"id": "i-2", "subnet_id": "subnet-a", "vpc_security_group_ids": ["sg-app"]}}]},
+	{"mode": "managed", "type": "aws_db_subnet_group", "name": "db", "instances": [{"attributes": {"id": "db-group", "name": "db-group", "subnet_ids": ["subnet-b"]}}]},
+	{"mode": "managed", "type": "aws_db_instance", "name": "orders", "instances": [{"attributes": {"id": "db-1", "db_subnet_group_name": "db-group", "vpc_security_group_ids": ["sg-db"]}}]},
+	{"mode": "managed", "type": "aws_security_group", "name": "lb", "instances": [{"attributes": {"id": "sg-lb", "ingress": [{"from_port": 443, "to_port": 443, "cidr_blocks": ["0.0.0.0/0"]}]}}]},
+	{"mode": "managed", "type": "aws_security_group", "name": "app", "instances": [{"attributes": {"id": "sg-app", "ingress": [{"from_port": 8000, "to_port": 8100, "security_groups": ["sg-lb"]}]}}]},
+	{"mode": "managed", "type": "aws_security_group_rule", "name": "db", "instances": [{"attributes": {"type": "ingress", "security_group_id": "sg-db", "source_security_group_id": "sg-app", "from_port": 5432, "to_port": 5432}}]},
+	{"mode": "managed", "type": "aws_iam_role", "name": "app", "instances": [{"attributes": {"id": "role"}}]},
+	{"mode": "data", "type": "aws_ami", "name": "ubuntu", "instances": [{"attributes": {"id": "ami-1"}}]}
+]}`
+
+func TestImportTerraformState(t *testing.T) {
+	model, notes, err := ImportTerraform(strings.NewReader(terraformStateJSON), "shop.tfstate")
+	assert.NoError(t, err)
+	assert.Equal(t, "shop", model.Title)
+	assert.Equal(t, []string{"skipped resource types: aws_iam_role"}, notes)
+
+	assert.Len(t, model.TrustBoundaries, 3)
+	vpc := model.TrustBoundaries["Shop VPC"]
+	assert.Equal(t, "network-cloud-provider", vpc.Type)
+	assert.Equal(t, []string{"public", "private"}, vpc.TrustBoundariesNested)
+	assert.Equal(t, []string{"front"}, vpc.TechnicalAssetsInside, "in several subnets")
+	assert.Equal(t, "network-virtual-lan", model.TrustBoundaries["public"].Type)
+	assert.Equal(t, []string{"app-0", "app-1"}, model.TrustBoundaries["public"].TechnicalAssetsInside)
+	assert.Equal(t, []string{"orders"}, model.TrustBoundaries["private"].TechnicalAssetsInside, "through the db subnet group")
+
+	assert.Len(t, model.TechnicalAssets, 4)
+	front := model.TechnicalAssets["front"]
+	assert.Equal(t, "load-balancer", front.Technology)
+	assert.True(t, front.Internet)
+	assert.Equal(t, "Imported from aws_instance.app[0]", model.TechnicalAssets["app[0]"].Description)
+	assert.False(t, model.TechnicalAssets["app[0]"].Internet)
+	assert.Equal(t, "datastore", model.TechnicalAssets["orders"].Type)
+
+	assert.Len(t, front.CommunicationLinks, 2)
+	toApp := front.CommunicationLinks["Access app[0] on Ports 8000-8100"]
+	assert.Equal(t, "app-0", toApp.Target)
+	assert.Equal(t, "unknown-protocol", toApp.Protocol)
+	toDatabase := model.TechnicalAssets["app[1]"].CommunicationLinks["Access orders on Port 5432"]
+	assert.Equal(t, "orders", toDatabase.Target)
+	assert.Equal(t, "sql-access-protocol", toDatabase.Protocol)
+}
+
+func TestImportTerraformShowJSON(t *testing.T) {
+	plan := `{"format_version": "1.2", "planned_values": {"root_module": {"child_modules": [{"resources": [
+		{"address": "module.queue.aws_sqs_queue.jobs[\"eu\"]", "mode": "managed", "type": "aws_sqs_queue", "name": "jobs", "index": "eu", "values": {}},
+		{"address": "module.queue.aws_lambda_function.worker", "mode": "managed", "type": "aws_lambda_function", "name": "worker", "values": {"tags": {"Name": "Worker"}}}
+	]}]}}}`
+	model, notes, err := ImportTerraform(strings.NewReader(plan), "plan.json")
+	assert.NoError(t, err)
+	assert.Empty(t, notes)
+	assert.Equal(t, "message-queue", model.TechnicalAssets[`jobs["eu"]`].Technology)
+	assert.Equal(t, "jobs-eu", model.TechnicalAssets[`jobs["eu"]`].ID)
+	assert.Equal(t, "serverless", model.TechnicalAssets["Worker"].Machine)
+
+	_, _, err = ImportTerraform(strings.NewReader(`{"resource": {}}`), "main.tf.json")
+	assert.ErrorContains(t, err, "neither a state file nor the json of terraform show")
+}
@@ -0,0 +1,259 @@
+package input
+
+import (
+	"bufio"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+const maxAnnotatedFileSize = 1024 * 1024
+
+var (
+	annotationPattern      = regexp.MustCompile(`threagile:asset\s+(.*)$`)
+	annotationValuePattern = regexp.MustCompile(`([a-z_-]+)=("([^"]*)"|[^\s"]+)`)
+	annotationSkipFolders  = []string{".git", ".svn", ".hg", "node_modules", "vendor", ".idea", ".vscode"}
+)
+
+// Annotation is a threat model annotation found in source code, declaring (attributes of) a technical asset like
+// `// threagile:asset id=payment-service technology=web-service tags=pci,payment`
+type Annotation struct {
+	File   string
+	Line   int
+	ID     string
+	Values map[string]string
+}
+
+func (what Annotation) String() string {
+	return fmt.Sprintf("%v:%d", what.File, what.Line)
+}
+
+// ScanAnnotations walks the source folders and collects the threagile:asset annotations of all text files
+func ScanAnnotations(folders []string) ([]Annotation, error) {
+	annotations := make([]Annotation, 0)
+	for _, folder := range folders {
+		walkError := filepath.WalkDir(folder, func(path string, entry fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if entry.IsDir() {
+				if path != folder && slices.Contains(annotationSkipFolders, entry.Name()) {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			info, infoError := entry.Info()
+			if infoError != nil || !info.Mode().IsRegular() || info.Size() > maxAnnotatedFileSize {
+				return nil
+			}
+
+			fileAnnotations, scanError := scanFileAnnotations(path)
+			if scanError != nil {
+				return scanError
+			}
+			annotations = append(annotations, fileAnnotations...)
+			return nil
+		})
+		if walkError != nil {
+			return nil, fmt.Errorf("unable to scan %q for annotations: %v", folder, walkError)
+		}
+	}
+	return annotations, nil
+}
+
+func scanFileAnnotations(filename string) ([]Annotation, error) {
+	file, openError := os.Open(filepath.Clean(filename))
+	if openError != nil {
+		return nil, openError
+	}
+	defer func() { _ = file.Close() }()
+
+	annotations := make([]Annotation, 0)
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxAnnotatedFileSize)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		match := annotationPattern.FindStringSubmatch(scanner.Text())
+		if match == nil {
+			continue
+		}
+
+		annotation := Annotation{File: filename, Line: lineNumber, Values: make(map[string]string)}
+		for _, value := range annotationValuePattern.FindAllStringSubmatch(match[1], -1) {
+			if strings.HasPrefix(value[2], `"`) {
+				annotation.Values[value[1]] = value[3]
+			} else {
+				annotation.Values[value[1]] = value[2]
+			}
+		}
+		annotation.ID = annotation.Values["id"]
+		delete(annotation.Values, "id")
+		annotations = append(annotations, annotation)
+	}
+
+	if scanner.Err() != nil {
+		return nil, fmt.Errorf("unable to read %v: %v", filename, scanner.Err())
+	}
+	return annotations, nil
+}
+
+// ApplyAnnotations creates the annotated technical assets missing in the model and updates the annotated attributes
+// of the existing ones (found by id), collecting a description of each change; annotations without id (like the ones
+// only mentioned in docs or comments) are skipped
+func (model *Model) ApplyAnnotations(annotations []Annotation, dryRun bool, changes *[]string) error {
+	for _, annotation := range annotations {
+		if len(annotation.ID) == 0 {
+			*changes = append(*changes, fmt.Sprintf("skipping annotation without asset id (%v)", annotation))
+			continue
+		}
+
+		title, asset, found := model.technicalAssetByID(annotation.ID)
+		if !found {
+			title = annotation.ID
+			if len(annotation.Values["title"]) > 0 {
+				title = annotation.Values["title"]
+			}
+			if other, exists := model.TechnicalAssets[title]; exists {
+				return fmt.Errorf("invalid annotation at %v: title %q is already used by technical asset %q", annotation, title, other.ID)
+			}
+			asset = TechnicalAsset{ID: annotation.ID}
+			*changes = append(*changes, fmt.Sprintf("adding technical asset %q (%v)", annotation.ID, annotation))
+		} else if len(annotation.Values["title"]) > 0 && annotation.Values["title"] != title {
+			if other, exists := model.TechnicalAssets[annotation.Values["title"]]; exists {
+				return fmt.Errorf("invalid annotation at %v: title %q is already used by technical asset %q", annotation, annotation.Values["title"], other.ID)
+			}
+			*changes = append(*changes, fmt.Sprintf("renaming technical asset %q to %q (%v)", annotation.ID, annotation.Values["title"], annotation))
+			if !dryRun {
+				delete(model.TechnicalAssets, title)
+			}
+			title = annotation.Values["title"]
+		}
+
+		for _, key := range sortedKeys(annotation.Values) {
+			if key == "title" {
+				continue
+			}
+			changed, applyError := asset.applyAnnotationValue(key, annotation.Values[key])
+			if applyError != nil {
+				return fmt.Errorf("invalid annotation at %v: %v", annotation, applyError)
+			}
+			if changed {
+				*changes = append(*changes, fmt.Sprintf("setting %v of technical asset %q to %q (%v)", key, annotation.ID, annotation.Values[key], annotation))
+			}
+		}
+
+		if !dryRun {
+			model.TechnicalAssets[title] = asset
+		}
+	}
+	return nil
+}
+
+func (model *Model) technicalAssetByID(id string) (string, TechnicalAsset, bool) {
+	for title, asset := range model.TechnicalAssets {
+		if asset.ID == id {
+			return title, asset, true
+		}
+	}
+	return "", TechnicalAsset{}, false
+}
+
+// applyAnnotationValue sets the attribute of the given (yaml) name and reports whether this changed its value
+func (what *TechnicalAsset) applyAnnotationValue(key string, value string) (bool, error) {
+	switch strings.ReplaceAll(key, "-", "_") {
+	case "description":
+		return setAnnotatedString(&what.Description, value), nil
+	case "type":
+		return setAnnotatedString(&what.Type, value), nil
+	case "usage":
+		return setAnnotatedString(&what.Usage, value), nil
+	case "size":
+		return setAnnotatedString(&what.Size, value), nil
+	case "technology":
+		return setAnnotatedString(&what.Technology, value), nil
+	case "machine":
+		return setAnnotatedString(&what.Machine, value), nil
+	case "encryption":
+		return setAnnotatedString(&what.Encryption, value), nil
+	case "owner":
+		return setAnnotatedString(&what.Owner, value), nil
+	case "confidentiality":
+		return setAnnotatedString(&what.Confidentiality, value), nil
+	case "integrity":
+		return setAnnotatedString(&what.Integrity, value), nil
+	case "availability":
+		return setAnnotatedString(&what.Availability, value), nil
+	case "technologies":
+		return setAnnotatedList(&what.Technologies, value), nil
+	case "tags":
+		return setAnnotatedList(&what.Tags, value), nil
+	case "data_assets_processed":
+		return setAnnotatedList(&what.DataAssetsProcessed, value), nil
+	case "data_assets_stored":
+		return setAnnotatedList(&what.DataAssetsStored, value), nil
+	case "data_formats_accepted":
+		return setAnnotatedList(&what.DataFormatsAccepted, value), nil
+	case "internet":
+		return setAnnotatedBool(&what.Internet, value)
+	case "out_of_scope":
+		return setAnnotatedBool(&what.OutOfScope, value)
+	case "used_as_client_by_human":
+		return setAnnotatedBool(&what.UsedAsClientByHuman, value)
+	case "multi_tenant":
+		return setAnnotatedBool(&what.MultiTenant, value)
+	case "redundant":
+		return setAnnotatedBool(&what.Redundant, value)
+	case "custom_developed_parts":
+		return setAnnotatedBool(&what.CustomDevelopedParts, value)
+	}
+	return false, fmt.Errorf("unsupported attribute %q", key)
+}
+
+func setAnnotatedString(field *string, value string) bool {
+	if *field == value {
+		return false
+	}
+	*field = value
+	return true
+}
+
+func setAnnotatedList(field *[]string, value string) bool {
+	list := make([]string, 0)
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); len(item) > 0 {
+			list = append(list, item)
+		}
+	}
+	if slices.Equal(*field, list) {
+		return false
+	}
+	*field = list
+	return true
+}
+
+func setAnnotatedBool(field *bool, value string) (bool, error) {
+	parsed, parseError := strconv.ParseBool(value)
+	if parseError != nil {
+		return false, fmt.Errorf("%q is no boolean", value)
+	}
+	if *field == parsed {
+		return false, nil
+	}
+	*field = parsed
+	return true, nil
+}
+
+func sortedKeys(values map[string]string) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	return keys
+}
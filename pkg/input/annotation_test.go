@@ -0,0 +1,55 @@
+package input
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScanAndApplyAnnotations(t *testing.T) {
+	sourceFolder := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(sourceFolder, "main.go"), []byte(`package main
+
+// threagile:asset id=payment-service technology=web-service tags=pci,payment internet=true
+func main() {}
+`), 0600))
+	assert.NoError(t, os.MkdirAll(filepath.Join(sourceFolder, "web", "node_modules"), 0700))
+	assert.NoError(t, os.WriteFile(filepath.Join(sourceFolder, "web", "app.js"), []byte(`/* threagile:asset id=web-app title="Web App" owner="Team Web" */`), 0600))
+	assert.NoError(t, os.WriteFile(filepath.Join(sourceFolder, "web", "node_modules", "lib.js"), []byte(`// threagile:asset id=ignored`), 0600))
+
+	annotations, err := ScanAnnotations([]string{sourceFolder})
+	assert.NoError(t, err)
+	assert.Len(t, annotations, 2)
+
+	model := new(Model).Defaults()
+	model.TechnicalAssets["Payment Service"] = TechnicalAsset{ID: "payment-service", Technology: "unknown-technology", Tags: []string{"pci", "payment"}}
+
+	changes := make([]string, 0)
+	assert.NoError(t, model.ApplyAnnotations(annotations, false, &changes))
+	assert.Len(t, changes, 4)
+
+	paymentService := model.TechnicalAssets["Payment Service"]
+	assert.Equal(t, "web-service", paymentService.Technology)
+	assert.True(t, paymentService.Internet)
+	assert.Equal(t, []string{"pci", "payment"}, paymentService.Tags)
+	assert.Equal(t, "Team Web", model.TechnicalAssets["Web App"].Owner)
+
+	changes = make([]string, 0)
+	assert.NoError(t, model.ApplyAnnotations(annotations, false, &changes))
+	assert.Empty(t, changes)
+
+	assert.Error(t, model.ApplyAnnotations([]Annotation{{ID: "web-app", Values: map[string]string{"color": "red"}}}, false, &changes))
+
+	changes = make([]string, 0)
+	assert.NoError(t, model.ApplyAnnotations([]Annotation{{File: "README.md", Line: 3, Values: map[string]string{"technology": "web-service"}}}, false, &changes))
+	assert.Equal(t, []string{"skipping annotation without asset id (README.md:3)"}, changes)
+
+	err = model.ApplyAnnotations([]Annotation{{ID: "web-app", Values: map[string]string{"title": "Payment Service"}}}, false, &changes)
+	assert.ErrorContains(t, err, `title "Payment Service" is already used by technical asset "payment-service"`)
+	assert.Equal(t, "web-app", model.TechnicalAssets["Web App"].ID, "not removed")
+	assert.Equal(t, "payment-service", model.TechnicalAssets["Payment Service"].ID, "not overwritten")
+	err = model.ApplyAnnotations([]Annotation{{ID: "checkout", Values: map[string]string{"title": "Web App"}}}, false, &changes)
+	assert.ErrorContains(t, err, `title "Web App" is already used by technical asset "web-app"`)
+}
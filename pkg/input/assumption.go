@@ -0,0 +1,52 @@
+package input
+
+import "fmt"
+
+// Assumption is something the threat model takes for granted (in the section assumptions) or deliberately leaves out
+// of its scope (in the section scope_exclusions), documented with who is accountable for it and why
+type Assumption struct {
+	Description     string   `yaml:"description,omitempty" json:"description,omitempty"`
+	Owner           string   `yaml:"owner,omitempty" json:"owner,omitempty"`
+	Rationale       string   `yaml:"rationale,omitempty" json:"rationale,omitempty"`
+	TechnicalAssets []string `yaml:"technical_assets,omitempty" json:"technical_assets,omitempty"` // the whole target if empty
+}
+
+func (what *Assumption) Merge(other Assumption) error {
+	var mergeError error
+	what.Description, mergeError = new(Strings).MergeSingleton(what.Description, other.Description)
+	if mergeError != nil {
+		return fmt.Errorf("failed to merge description: %v", mergeError)
+	}
+
+	what.Owner, mergeError = new(Strings).MergeSingleton(what.Owner, other.Owner)
+	if mergeError != nil {
+		return fmt.Errorf("failed to merge owner: %v", mergeError)
+	}
+
+	what.Rationale, mergeError = new(Strings).MergeSingleton(what.Rationale, other.Rationale)
+	if mergeError != nil {
+		return fmt.Errorf("failed to merge rationale: %v", mergeError)
+	}
+
+	what.TechnicalAssets = new(Strings).MergeUniqueSlice(what.TechnicalAssets, other.TechnicalAssets)
+
+	return nil
+}
+
+func (what *Assumption) MergeMap(first map[string]Assumption, second map[string]Assumption) (map[string]Assumption, error) {
+	for mapKey, mapValue := range second {
+		mapItem, ok := first[mapKey]
+		if ok {
+			mergeError := mapItem.Merge(mapValue)
+			if mergeError != nil {
+				return first, fmt.Errorf("failed to merge %q: %v", mapKey, mergeError)
+			}
+
+			first[mapKey] = mapItem
+		} else {
+			first[mapKey] = mapValue
+		}
+	}
+
+	return first, nil
+}
@@ -0,0 +1,27 @@
+package input
+
+// ElementAnnotation is a free-form note of a review attached to an element of the model (by its id), kept alongside
+// the model without affecting its analysis
+type ElementAnnotation struct {
+	Element string `yaml:"element,omitempty" json:"element,omitempty"` // id of a data asset, technical asset, communication link, trust boundary, shared runtime, security control or risk
+	Note    string `yaml:"note,omitempty" json:"note,omitempty"`
+	Author  string `yaml:"author,omitempty" json:"author,omitempty"`
+	Date    string `yaml:"date,omitempty" json:"date,omitempty"`
+}
+
+// MergeList appends the annotations of an included model, skipping the ones already present (e.g. included twice)
+func (what *ElementAnnotation) MergeList(first []ElementAnnotation, second []ElementAnnotation) []ElementAnnotation {
+	for _, annotation := range second {
+		found := false
+		for _, existing := range first {
+			if existing == annotation {
+				found = true
+				break
+			}
+		}
+		if !found {
+			first = append(first, annotation)
+		}
+	}
+	return first
+}
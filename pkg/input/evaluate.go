@@ -0,0 +1,97 @@
+package input
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/threagile/threagile/pkg/common"
+)
+
+// evaluationTimeout limits each run of an evaluator tool, unless the context given ends it earlier
+const evaluationTimeout = 5 * time.Minute
+
+// ModelEvaluator turns a model defined in a configuration language into JSON of the model schema via its command line
+// tool, so that models can use types, constraints and functions for definitions beyond plain YAML
+type ModelEvaluator struct {
+	Name      string   // language name for messages
+	Tool      string   // binary expected in PATH
+	Arguments []string // arguments preceding the model file name, making the tool print the model as JSON
+	Install   string   // how to install the tool
+}
+
+var modelEvaluators = map[string]ModelEvaluator{
+	".cue":       {Name: "CUE", Tool: "cue", Arguments: []string{"export", "--out", "json"}, Install: "https://cuelang.org/docs/introduction/installation/"},
+	".jsonnet":   {Name: "Jsonnet", Tool: "jsonnet", Install: "https://jsonnet.org/learning/getting_started.html"},
+	".libsonnet": {Name: "Jsonnet", Tool: "jsonnet", Install: "https://jsonnet.org/learning/getting_started.html"},
+}
+
+// GetModelEvaluator returns the evaluator for the model file, or false for YAML and JSON models read as they are
+func GetModelEvaluator(filename string) (ModelEvaluator, bool) {
+	evaluator, ok := modelEvaluators[strings.ToLower(filepath.Ext(filename))]
+	return evaluator, ok
+}
+
+// Evaluate runs the tool on the model file and returns the resulting JSON
+func (what ModelEvaluator) Evaluate(filename string) ([]byte, error) {
+	return what.EvaluateWithContext(context.Background(), filename)
+}
+
+// EvaluateWithContext runs the tool on the model file, killing it once the context is done or it ran for longer than
+// the evaluation timeout
+func (what ModelEvaluator) EvaluateWithContext(ctx context.Context, filename string) ([]byte, error) {
+	path, lookError := exec.LookPath(what.Tool)
+	if lookError != nil {
+		return nil, fmt.Errorf("unable to evaluate %v model: %q not found in PATH (see %v)", what.Name, what.Tool, what.Install)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, evaluationTimeout)
+	defer cancel()
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, path, append(what.Arguments, filepath.Clean(filename))...) // #nosec G204
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	runError := cmd.Run()
+	if ctx.Err() != nil {
+		return nil, common.NewFailure(common.ExitCodeTimeout, fmt.Errorf("evaluating %v model %v aborted: %w", what.Name, filepath.Base(filename), ctx.Err()))
+	}
+	if runError != nil {
+		return nil, fmt.Errorf("unable to evaluate %v model: %v: %v", what.Name, runError, strings.TrimSpace(stderr.String()))
+	}
+
+	return stdout.Bytes(), nil
+}
+
+// ReadModelFile reads a YAML or JSON model file, or evaluates a model defined in one of the supported configuration
+// languages into JSON
+func ReadModelFile(filename string) ([]byte, error) {
+	return FileReaderWithContext(context.Background(), os.ReadFile)(filename)
+}
+
+// FileReader reads the YAML or JSON model files with the function, e.g. of another file system than the one of the OS;
+// models defined in one of the configuration languages are still evaluated from the OS one, as the tools evaluating
+// them read the files themselves
+func FileReader(readFile func(filename string) ([]byte, error)) ModelReader {
+	return FileReaderWithContext(context.Background(), readFile)
+}
+
+// FileReaderWithContext is a FileReader whose evaluations of models are aborted once the context is done
+func FileReaderWithContext(ctx context.Context, readFile func(filename string) ([]byte, error)) ModelReader {
+	return func(filename string) ([]byte, error) {
+		if evaluator, ok := GetModelEvaluator(filename); ok {
+			return evaluator.EvaluateWithContext(ctx, filename)
+		}
+
+		modelData, readError := readFile(filepath.Clean(filename))
+		if readError != nil {
+			return nil, fmt.Errorf("unable to read model file: %v", readError)
+		}
+		return modelData, nil
+	}
+}
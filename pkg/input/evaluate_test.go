@@ -0,0 +1,70 @@
+package input
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/threagile/threagile/pkg/common"
+)
+
+// TestEvaluatorCommand is the evaluator tool run by the tests (the test binary itself, so that they need neither the
+// tool nor a shell), doing what THREAGILE_TEST_EVALUATOR tells
+func TestEvaluatorCommand(t *testing.T) {
+	switch os.Getenv("THREAGILE_TEST_EVALUATOR") {
+	case "print":
+		evaluated, _ := os.ReadFile(filepath.Clean(os.Getenv("THREAGILE_TEST_EVALUATED_MODEL")))
+		fmt.Print(string(evaluated))
+	case "hang":
+		time.Sleep(time.Minute)
+	default:
+		return
+	}
+	os.Exit(0)
+}
+
+func testEvaluator(t *testing.T) {
+	previous := modelEvaluators[".jsonnet"]
+	t.Cleanup(func() { modelEvaluators[".jsonnet"] = previous })
+	modelEvaluators[".jsonnet"] = ModelEvaluator{Name: "Jsonnet", Tool: os.Args[0], Arguments: []string{"-test.run=^TestEvaluatorCommand$", "--"}}
+}
+
+func TestLoadModelJsonnet(t *testing.T) {
+	yamlModel := new(Model).Defaults()
+	assert.NoError(t, yamlModel.Load(filepath.Join("..", "..", "test", "all.yaml")))
+	yamlData, err := json.MarshalIndent(yamlModel, "", "  ")
+	assert.NoError(t, err)
+
+	testEvaluator(t)
+	evaluatedModelFile := filepath.Join(t.TempDir(), "evaluated.json")
+	assert.NoError(t, os.WriteFile(evaluatedModelFile, yamlData, 0600))
+	t.Setenv("THREAGILE_TEST_EVALUATOR", "print")
+	t.Setenv("THREAGILE_TEST_EVALUATED_MODEL", evaluatedModelFile)
+
+	jsonnetModelFile := filepath.Join(t.TempDir(), "threagile.jsonnet")
+	assert.NoError(t, os.WriteFile(jsonnetModelFile, []byte("{}"), 0600))
+	jsonnetModel := new(Model).Defaults()
+	assert.NoError(t, jsonnetModel.Load(jsonnetModelFile))
+	jsonnetData, err := json.MarshalIndent(jsonnetModel, "", "  ")
+	assert.NoError(t, err)
+	assert.Equal(t, string(yamlData), string(jsonnetData))
+}
+
+func TestEvaluateTimeout(t *testing.T) {
+	testEvaluator(t)
+	t.Setenv("THREAGILE_TEST_EVALUATOR", "hang")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	started := time.Now()
+	_, err := FileReaderWithContext(ctx, os.ReadFile)("threagile.jsonnet")
+	assert.ErrorContains(t, err, "evaluating Jsonnet model threagile.jsonnet aborted")
+	assert.Equal(t, common.ExitCodeTimeout, common.ExitCodeOf(err))
+	assert.Less(t, time.Since(started), 30*time.Second, "killed instead of waited for")
+}
@@ -0,0 +1,93 @@
+package input
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// IsJSON tells whether a model (or model include) is given in JSON rather than YAML, by the content being an object
+// (as uploads and stored models have no telling file name) or else by the file extension
+func IsJSON(filename string, data []byte) bool {
+	if bytes.HasPrefix(bytes.TrimSpace(data), []byte("{")) {
+		return true
+	}
+	return strings.ToLower(filepath.Ext(filename)) == ".json"
+}
+
+// Unmarshal parses a model in YAML or JSON, both share the same schema
+func Unmarshal(filename string, data []byte, target any) error {
+	if IsJSON(filename, data) {
+		unmarshalError := json.Unmarshal(data, target)
+		if unmarshalError != nil {
+			return fmt.Errorf("unable to parse model json: %v", unmarshalError)
+		}
+		return nil
+	}
+
+	unmarshalError := yaml.Unmarshal(data, target)
+	if unmarshalError != nil {
+		return fmt.Errorf("unable to parse model yaml: %v", unmarshalError)
+	}
+	return nil
+}
+
+// Marshal writes the model in the format of the given file, JSON for .json files and YAML otherwise
+func Marshal(filename string, model *Model) ([]byte, error) {
+	if strings.ToLower(filepath.Ext(filename)) == ".json" {
+		return json.MarshalIndent(model, "", "  ")
+	}
+	return yaml.Marshal(model)
+}
+
+// LocateField follows the field of a validation problem (like "technical_assets.Some Component.communication_links.Some
+// Traffic.protocol", with titles possibly containing dots) in the model data as far as it can, answering the (one-based)
+// line it got to and how much of the field it followed, which is nothing when not even its first key is in the data
+func LocateField(data []byte, field string) (int, int) {
+	var root yaml.Node
+	if yaml.Unmarshal(data, &root) != nil || len(root.Content) == 0 {
+		return 0, 0
+	}
+	node, line, rest := root.Content[0], 0, field
+	for len(rest) > 0 {
+		var next, keyNode *yaml.Node
+		matched := ""
+		switch node.Kind {
+		case yaml.MappingNode:
+			for i := 0; i+1 < len(node.Content); i += 2 {
+				key := node.Content[i].Value
+				if (rest == key || strings.HasPrefix(rest, key+".")) && len(key) > len(matched) {
+					matched, keyNode, next = key, node.Content[i], node.Content[i+1]
+				}
+			}
+		case yaml.SequenceNode: // like the custom risk categories, found by id
+			for _, item := range node.Content {
+				if id := idOf(item); id != nil && (rest == id.Value || strings.HasPrefix(rest, id.Value+".")) && len(id.Value) > len(matched) {
+					matched, keyNode, next = id.Value, id, item
+				}
+			}
+		}
+		if next == nil {
+			break
+		}
+		node, line = next, keyNode.Line
+		rest = strings.TrimPrefix(strings.TrimPrefix(rest, matched), ".")
+	}
+	return line, len(field) - len(rest)
+}
+
+func idOf(node *yaml.Node) *yaml.Node {
+	if node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == "id" {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
@@ -5,50 +5,55 @@ Copyright © 2023 NAME HERE <EMAIL ADDRESS>
 package input
 
 import (
+	"errors"
 	"fmt"
-	"log"
-	"os"
 	"path/filepath"
 	"slices"
 	"sort"
 	"strings"
 
 	"github.com/mpvl/unique"
-
-	"gopkg.in/yaml.v3"
 )
 
 // === Model Type Stuff ======================================
 
 type Model struct { // TODO: Eventually remove this and directly use ParsedModelRoot? But then the error messages for model errors are not quite as good anymore...
-	ThreagileVersion                              string                    `yaml:"threagile_version,omitempty" json:"threagile_version,omitempty"`
-	Includes                                      []string                  `yaml:"includes,omitempty" json:"includes,omitempty"`
-	Title                                         string                    `yaml:"title,omitempty" json:"title,omitempty"`
-	Author                                        Author                    `yaml:"author,omitempty" json:"author,omitempty"`
-	Contributors                                  []Author                  `yaml:"contributors,omitempty" json:"contributors,omitempty"`
-	Date                                          string                    `yaml:"date,omitempty" json:"date,omitempty"`
-	AppDescription                                Overview                  `yaml:"application_description,omitempty" json:"application_description,omitempty"`
-	BusinessOverview                              Overview                  `yaml:"business_overview,omitempty" json:"business_overview,omitempty"`
-	TechnicalOverview                             Overview                  `yaml:"technical_overview,omitempty" json:"technical_overview,omitempty"`
-	BusinessCriticality                           string                    `yaml:"business_criticality,omitempty" json:"business_criticality,omitempty"`
-	ManagementSummaryComment                      string                    `yaml:"management_summary_comment,omitempty" json:"management_summary_comment,omitempty"`
-	SecurityRequirements                          map[string]string         `yaml:"security_requirements,omitempty" json:"security_requirements,omitempty"`
-	Questions                                     map[string]string         `yaml:"questions,omitempty" json:"questions,omitempty"`
-	AbuseCases                                    map[string]string         `yaml:"abuse_cases,omitempty" json:"abuse_cases,omitempty"`
-	TagsAvailable                                 []string                  `yaml:"tags_available,omitempty" json:"tags_available,omitempty"`
-	DataAssets                                    map[string]DataAsset      `yaml:"data_assets,omitempty" json:"data_assets,omitempty"`
-	TechnicalAssets                               map[string]TechnicalAsset `yaml:"technical_assets,omitempty" json:"technical_assets,omitempty"`
-	TrustBoundaries                               map[string]TrustBoundary  `yaml:"trust_boundaries,omitempty" json:"trust_boundaries,omitempty"`
-	SharedRuntimes                                map[string]SharedRuntime  `yaml:"shared_runtimes,omitempty" json:"shared_runtimes,omitempty"`
-	CustomRiskCategories                          RiskCategories            `yaml:"custom_risk_categories,omitempty" json:"custom_risk_categories,omitempty"`
-	RiskTracking                                  map[string]RiskTracking   `yaml:"risk_tracking,omitempty" json:"risk_tracking,omitempty"`
-	DiagramTweakNodesep                           int                       `yaml:"diagram_tweak_nodesep,omitempty" json:"diagram_tweak_nodesep,omitempty"`
-	DiagramTweakRanksep                           int                       `yaml:"diagram_tweak_ranksep,omitempty" json:"diagram_tweak_ranksep,omitempty"`
-	DiagramTweakEdgeLayout                        string                    `yaml:"diagram_tweak_edge_layout,omitempty" json:"diagram_tweak_edge_layout,omitempty"`
-	DiagramTweakSuppressEdgeLabels                bool                      `yaml:"diagram_tweak_suppress_edge_labels,omitempty" json:"diagram_tweak_suppress_edge_labels,omitempty"`
-	DiagramTweakLayoutLeftToRight                 bool                      `yaml:"diagram_tweak_layout_left_to_right,omitempty" json:"diagram_tweak_layout_left_to_right,omitempty"`
-	DiagramTweakInvisibleConnectionsBetweenAssets []string                  `yaml:"diagram_tweak_invisible_connections_between_assets,omitempty" json:"diagram_tweak_invisible_connections_between_assets,omitempty"`
-	DiagramTweakSameRankAssets                    []string                  `yaml:"diagram_tweak_same_rank_assets,omitempty" json:"diagram_tweak_same_rank_assets,omitempty"`
+	ThreagileVersion                              string                     `yaml:"threagile_version,omitempty" json:"threagile_version,omitempty"`
+	Includes                                      []string                   `yaml:"includes,omitempty" json:"includes,omitempty"`
+	Title                                         string                     `yaml:"title,omitempty" json:"title,omitempty"`
+	Author                                        Author                     `yaml:"author,omitempty" json:"author,omitempty"`
+	Contributors                                  []Author                   `yaml:"contributors,omitempty" json:"contributors,omitempty"`
+	Date                                          string                     `yaml:"date,omitempty" json:"date,omitempty"`
+	NextReviewDate                                string                     `yaml:"next_review_date,omitempty" json:"next_review_date,omitempty"`
+	AppDescription                                Overview                   `yaml:"application_description,omitempty" json:"application_description,omitempty"`
+	BusinessOverview                              Overview                   `yaml:"business_overview,omitempty" json:"business_overview,omitempty"`
+	TechnicalOverview                             Overview                   `yaml:"technical_overview,omitempty" json:"technical_overview,omitempty"`
+	BusinessCriticality                           string                     `yaml:"business_criticality,omitempty" json:"business_criticality,omitempty"`
+	ManagementSummaryComment                      string                     `yaml:"management_summary_comment,omitempty" json:"management_summary_comment,omitempty"`
+	SecurityRequirements                          map[string]string          `yaml:"security_requirements,omitempty" json:"security_requirements,omitempty"`
+	Questions                                     map[string]string          `yaml:"questions,omitempty" json:"questions,omitempty"`
+	AbuseCases                                    map[string]string          `yaml:"abuse_cases,omitempty" json:"abuse_cases,omitempty"`
+	TagsAvailable                                 []string                   `yaml:"tags_available,omitempty" json:"tags_available,omitempty"`
+	DataAssets                                    map[string]DataAsset       `yaml:"data_assets,omitempty" json:"data_assets,omitempty"`
+	TechnicalAssets                               map[string]TechnicalAsset  `yaml:"technical_assets,omitempty" json:"technical_assets,omitempty"`
+	TrustBoundaries                               map[string]TrustBoundary   `yaml:"trust_boundaries,omitempty" json:"trust_boundaries,omitempty"`
+	SharedRuntimes                                map[string]SharedRuntime   `yaml:"shared_runtimes,omitempty" json:"shared_runtimes,omitempty"`
+	SecurityControls                              map[string]SecurityControl `yaml:"security_controls,omitempty" json:"security_controls,omitempty"`
+	PenTestFindings                               map[string]PenTestFinding  `yaml:"findings,omitempty" json:"findings,omitempty"`
+	Assumptions                                   map[string]Assumption      `yaml:"assumptions,omitempty" json:"assumptions,omitempty"`
+	ScopeExclusions                               map[string]Assumption      `yaml:"scope_exclusions,omitempty" json:"scope_exclusions,omitempty"`
+	CustomRiskCategories                          RiskCategories             `yaml:"custom_risk_categories,omitempty" json:"custom_risk_categories,omitempty"`
+	RiskTracking                                  map[string]RiskTracking    `yaml:"risk_tracking,omitempty" json:"risk_tracking,omitempty"`
+	Annotations                                   []ElementAnnotation        `yaml:"annotations,omitempty" json:"annotations,omitempty"`
+	DiagramTweakNodesep                           int                        `yaml:"diagram_tweak_nodesep,omitempty" json:"diagram_tweak_nodesep,omitempty"`
+	DiagramTweakRanksep                           int                        `yaml:"diagram_tweak_ranksep,omitempty" json:"diagram_tweak_ranksep,omitempty"`
+	DiagramTweakEdgeLayout                        string                     `yaml:"diagram_tweak_edge_layout,omitempty" json:"diagram_tweak_edge_layout,omitempty"`
+	DiagramTweakSuppressEdgeLabels                bool                       `yaml:"diagram_tweak_suppress_edge_labels,omitempty" json:"diagram_tweak_suppress_edge_labels,omitempty"`
+	DiagramTweakLayoutLeftToRight                 bool                       `yaml:"diagram_tweak_layout_left_to_right,omitempty" json:"diagram_tweak_layout_left_to_right,omitempty"`
+	DiagramTweakInvisibleConnectionsBetweenAssets []string                   `yaml:"diagram_tweak_invisible_connections_between_assets,omitempty" json:"diagram_tweak_invisible_connections_between_assets,omitempty"`
+	DiagramTweakSameRankAssets                    []string                   `yaml:"diagram_tweak_same_rank_assets,omitempty" json:"diagram_tweak_same_rank_assets,omitempty"`
+
+	sources map[string]string // the model files the fields and elements were loaded from, see SourceOf
 }
 
 func (model *Model) Defaults() *Model {
@@ -60,6 +65,10 @@ func (model *Model) Defaults() *Model {
 		TechnicalAssets:      make(map[string]TechnicalAsset),
 		TrustBoundaries:      make(map[string]TrustBoundary),
 		SharedRuntimes:       make(map[string]SharedRuntime),
+		SecurityControls:     make(map[string]SecurityControl),
+		PenTestFindings:      make(map[string]PenTestFinding),
+		Assumptions:          make(map[string]Assumption),
+		ScopeExclusions:      make(map[string]Assumption),
 		CustomRiskCategories: make(RiskCategories, 0),
 		RiskTracking:         make(map[string]RiskTracking),
 	}
@@ -68,52 +77,137 @@ func (model *Model) Defaults() *Model {
 }
 
 func (model *Model) Load(inputFilename string) error {
-	modelYaml, readError := os.ReadFile(filepath.Clean(inputFilename))
+	return model.LoadWith(inputFilename, ReadModelFile)
+}
+
+// LoadWith loads the model like Load, reading the model file and its includes with the reader, e.g. expanding their
+// templating
+func (model *Model) LoadWith(inputFilename string, read ModelReader) error {
+	modelData, readError := read(inputFilename)
 	if readError != nil {
-		log.Fatal("Unable to read model file: ", readError)
+		return readError
 	}
 
-	unmarshalError := yaml.Unmarshal(modelYaml, &model)
+	return model.LoadData(inputFilename, modelData, read)
+}
+
+// LoadData loads the model from the data of the model file, e.g. as preprocessed by hooks, merging its includes
+// relative to the model file
+func (model *Model) LoadData(inputFilename string, modelData []byte, read ModelReader) error {
+	unmarshalError := Unmarshal(inputFilename, modelData, &model)
 	if unmarshalError != nil {
-		log.Fatal("Unable to parse model yaml: ", unmarshalError)
+		return unmarshalError
+	}
+	var fileStructure map[string]any
+	if Unmarshal(inputFilename, modelData, &fileStructure) == nil {
+		model.recordSources(inputFilename, fileStructure)
 	}
+	model.sources[""] = inputFilename
 
 	for _, includeFile := range model.Includes {
-		mergeError := model.Merge(filepath.Dir(inputFilename), includeFile)
+		mergeError := model.mergeWith(filepath.Dir(inputFilename), includeFile, read)
 		if mergeError != nil {
-			log.Fatalf("Unable to merge model include %q: %v", includeFile, mergeError)
+			return fmt.Errorf("unable to merge model include %q: %w", includeFile, mergeError)
 		}
 	}
 
 	return nil
 }
 
+// SourceOf answers the model file the field of a validation problem (like "technical_assets.Some Component.usage") was
+// loaded from, which is the loaded one for fields of none of its includes and nothing for models not loaded from files
+func (model *Model) SourceOf(field string) string {
+	source, longest := model.sources[""], 0
+	for prefix, filename := range model.sources {
+		if len(prefix) > longest && (field == prefix || strings.HasPrefix(field, prefix+".")) {
+			source, longest = filename, len(prefix)
+		}
+	}
+	return source
+}
+
+// recordSources records the model file as source of the top-level fields of its data and of the elements in them (by
+// their title, or by their id in lists like the custom risk categories)
+func (model *Model) recordSources(filename string, fileStructure map[string]any) {
+	if model.sources == nil {
+		model.sources = make(map[string]string)
+	}
+	for key, value := range fileStructure {
+		model.sources[key] = filename
+		switch elements := value.(type) {
+		case map[string]any:
+			for title := range elements {
+				model.sources[key+"."+title] = filename
+			}
+		case []any:
+			for _, item := range elements {
+				if element, ok := item.(map[string]any); ok {
+					if id, ok := element["id"].(string); ok {
+						model.sources[key+"."+id] = filename
+					}
+				}
+			}
+		}
+	}
+}
+
 func (model *Model) Merge(dir string, includeFilename string) error {
-	modelYaml, readError := os.ReadFile(filepath.Clean(filepath.Join(dir, includeFilename)))
+	return model.mergeWith(dir, includeFilename, ReadModelFile)
+}
+
+// FileError is an error of an include (like a syntax error or a conflicting definition), or of its reading of a
+// further include, telling the model file it is located in
+type FileError struct {
+	File string
+	Err  error
+}
+
+func (what *FileError) Error() string {
+	return what.Err.Error()
+}
+
+func (what *FileError) Unwrap() error {
+	return what.Err
+}
+
+// mergeWith merges the include, its errors are FileErrors of it (but for its own read error located at the includer)
+func (model *Model) mergeWith(dir string, includeFilename string, read ModelReader) error {
+	filename := filepath.Join(dir, includeFilename)
+	modelData, readError := read(filename)
 	if readError != nil {
-		return fmt.Errorf("unable to read model file: %v", readError)
+		return readError
 	}
 
+	mergeError := model.mergeData(dir, includeFilename, modelData, read)
+	var fileError *FileError
+	if mergeError != nil && !errors.As(mergeError, &fileError) {
+		return &FileError{File: filename, Err: mergeError}
+	}
+	return mergeError
+}
+
+func (model *Model) mergeData(dir string, includeFilename string, modelData []byte, read ModelReader) error {
 	var fileStructure map[string]any
-	unmarshalStructureError := yaml.Unmarshal(modelYaml, &fileStructure)
+	unmarshalStructureError := Unmarshal(includeFilename, modelData, &fileStructure)
 	if unmarshalStructureError != nil {
 		return fmt.Errorf("unable to parse model structure: %v", unmarshalStructureError)
 	}
 
 	var includedModel Model
-	unmarshalError := yaml.Unmarshal(modelYaml, &includedModel)
+	unmarshalError := Unmarshal(includeFilename, modelData, &includedModel)
 	if unmarshalError != nil {
-		return fmt.Errorf("unable to parse model yaml: %v", unmarshalError)
+		return unmarshalError
 	}
+	model.recordSources(filepath.Join(dir, includeFilename), fileStructure)
 
 	var mergeError error
 	for item := range fileStructure {
 		switch strings.ToLower(item) {
 		case strings.ToLower("includes"):
 			for _, includeFile := range includedModel.Includes {
-				mergeError = model.Merge(filepath.Join(dir, filepath.Dir(includeFilename)), includeFile)
+				mergeError = model.mergeWith(filepath.Join(dir, filepath.Dir(includeFilename)), includeFile, read)
 				if mergeError != nil {
-					return fmt.Errorf("failed to merge model include %q: %v", includeFile, mergeError)
+					return fmt.Errorf("failed to merge model include %q: %w", includeFile, mergeError)
 				}
 			}
 
@@ -147,6 +241,12 @@ func (model *Model) Merge(dir string, includeFilename string) error {
 				return fmt.Errorf("failed to merge date: %v", mergeError)
 			}
 
+		case strings.ToLower("next_review_date"):
+			model.NextReviewDate, mergeError = new(Strings).MergeSingleton(model.NextReviewDate, includedModel.NextReviewDate)
+			if mergeError != nil {
+				return fmt.Errorf("failed to merge next_review_date: %v", mergeError)
+			}
+
 		case strings.ToLower("application_description"):
 			mergeError = model.AppDescription.Merge(includedModel.AppDescription)
 			if mergeError != nil {
@@ -219,6 +319,30 @@ func (model *Model) Merge(dir string, includeFilename string) error {
 				return fmt.Errorf("failed to merge shared runtimes: %v", mergeError)
 			}
 
+		case strings.ToLower("security_controls"):
+			model.SecurityControls, mergeError = new(SecurityControl).MergeMap(model.SecurityControls, includedModel.SecurityControls)
+			if mergeError != nil {
+				return fmt.Errorf("failed to merge security controls: %v", mergeError)
+			}
+
+		case strings.ToLower("findings"):
+			model.PenTestFindings, mergeError = new(PenTestFinding).MergeMap(model.PenTestFindings, includedModel.PenTestFindings)
+			if mergeError != nil {
+				return fmt.Errorf("failed to merge pen-test findings: %v", mergeError)
+			}
+
+		case strings.ToLower("assumptions"):
+			model.Assumptions, mergeError = new(Assumption).MergeMap(model.Assumptions, includedModel.Assumptions)
+			if mergeError != nil {
+				return fmt.Errorf("failed to merge assumptions: %v", mergeError)
+			}
+
+		case strings.ToLower("scope_exclusions"):
+			model.ScopeExclusions, mergeError = new(Assumption).MergeMap(model.ScopeExclusions, includedModel.ScopeExclusions)
+			if mergeError != nil {
+				return fmt.Errorf("failed to merge scope exclusions: %v", mergeError)
+			}
+
 		case strings.ToLower("custom_risk_categories"):
 			mergeError = model.CustomRiskCategories.Add(includedModel.CustomRiskCategories...)
 			if mergeError != nil {
@@ -231,6 +355,9 @@ func (model *Model) Merge(dir string, includeFilename string) error {
 				return fmt.Errorf("failed to merge risk tracking: %v", mergeError)
 			}
 
+		case strings.ToLower("annotations"):
+			model.Annotations = new(ElementAnnotation).MergeList(model.Annotations, includedModel.Annotations)
+
 		case "diagram_tweak_nodesep":
 			model.DiagramTweakNodesep = includedModel.DiagramTweakNodesep
 
@@ -272,6 +399,43 @@ func (model *Model) AddTagToModelInput(tag string, dryRun bool, changes *[]strin
 	}
 }
 
+// SeedTagsAvailable registers all tags used on elements but missing from tags_available and returns the added tags
+func (model *Model) SeedTagsAvailable() []string {
+	used := make([]string, 0)
+	for _, dataAsset := range model.DataAssets {
+		used = append(used, dataAsset.Tags...)
+	}
+	for _, technicalAsset := range model.TechnicalAssets {
+		used = append(used, technicalAsset.Tags...)
+		for _, commLink := range technicalAsset.CommunicationLinks {
+			used = append(used, commLink.Tags...)
+		}
+	}
+	for _, trustBoundary := range model.TrustBoundaries {
+		used = append(used, trustBoundary.Tags...)
+	}
+	for _, sharedRuntime := range model.SharedRuntimes {
+		used = append(used, sharedRuntime.Tags...)
+	}
+
+	available := make([]string, 0, len(model.TagsAvailable))
+	for _, tag := range model.TagsAvailable {
+		available = append(available, NormalizeTag(tag))
+	}
+
+	added := make([]string, 0)
+	for _, tag := range used {
+		tag = NormalizeTag(tag)
+		if len(tag) > 0 && !slices.Contains(available, tag) && !slices.Contains(added, tag) {
+			added = append(added, tag)
+		}
+	}
+	sort.Strings(added)
+
+	model.TagsAvailable = append(model.TagsAvailable, added...)
+	return added
+}
+
 func NormalizeTag(tag string) string {
 	return strings.TrimSpace(strings.ToLower(tag))
 }
@@ -0,0 +1,21 @@
+package input
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSeedTagsAvailable(t *testing.T) {
+	model := &Model{
+		TagsAvailable: []string{"AWS"},
+		TechnicalAssets: map[string]TechnicalAsset{"Shop": {Tags: []string{"aws", " Linux"},
+			CommunicationLinks: map[string]CommunicationLink{"Database": {Tags: []string{"tls"}}}}},
+		DataAssets:      map[string]DataAsset{"Customers": {Tags: []string{"pii", "linux"}}},
+		TrustBoundaries: map[string]TrustBoundary{"DMZ": {Tags: []string{""}}},
+	}
+
+	assert.Equal(t, []string{"linux", "pii", "tls"}, model.SeedTagsAvailable())
+	assert.Equal(t, []string{"AWS", "linux", "pii", "tls"}, model.TagsAvailable)
+	assert.Empty(t, model.SeedTagsAvailable(), "all of them available now")
+}
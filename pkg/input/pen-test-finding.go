@@ -0,0 +1,77 @@
+package input
+
+import "fmt"
+
+type PenTestFinding struct {
+	ID              string   `yaml:"id,omitempty" json:"id,omitempty"`
+	Description     string   `yaml:"description,omitempty" json:"description,omitempty"`
+	Date            string   `yaml:"date,omitempty" json:"date,omitempty"`
+	Tester          string   `yaml:"tester,omitempty" json:"tester,omitempty"`
+	Reference       string   `yaml:"reference,omitempty" json:"reference,omitempty"`
+	Severity        string   `yaml:"severity,omitempty" json:"severity,omitempty"`
+	Result          string   `yaml:"result,omitempty" json:"result,omitempty"`
+	TechnicalAssets []string `yaml:"technical_assets,omitempty" json:"technical_assets,omitempty"`
+	RiskCategories  []string `yaml:"risk_categories,omitempty" json:"risk_categories,omitempty"`
+}
+
+func (what *PenTestFinding) Merge(other PenTestFinding) error {
+	var mergeError error
+	what.ID, mergeError = new(Strings).MergeSingleton(what.ID, other.ID)
+	if mergeError != nil {
+		return fmt.Errorf("failed to merge id: %v", mergeError)
+	}
+
+	what.Description, mergeError = new(Strings).MergeSingleton(what.Description, other.Description)
+	if mergeError != nil {
+		return fmt.Errorf("failed to merge description: %v", mergeError)
+	}
+
+	what.Date, mergeError = new(Strings).MergeSingleton(what.Date, other.Date)
+	if mergeError != nil {
+		return fmt.Errorf("failed to merge date: %v", mergeError)
+	}
+
+	what.Tester, mergeError = new(Strings).MergeSingleton(what.Tester, other.Tester)
+	if mergeError != nil {
+		return fmt.Errorf("failed to merge tester: %v", mergeError)
+	}
+
+	what.Reference, mergeError = new(Strings).MergeSingleton(what.Reference, other.Reference)
+	if mergeError != nil {
+		return fmt.Errorf("failed to merge reference: %v", mergeError)
+	}
+
+	what.Severity, mergeError = new(Strings).MergeSingleton(what.Severity, other.Severity)
+	if mergeError != nil {
+		return fmt.Errorf("failed to merge severity: %v", mergeError)
+	}
+
+	what.Result, mergeError = new(Strings).MergeSingleton(what.Result, other.Result)
+	if mergeError != nil {
+		return fmt.Errorf("failed to merge result: %v", mergeError)
+	}
+
+	what.TechnicalAssets = new(Strings).MergeUniqueSlice(what.TechnicalAssets, other.TechnicalAssets)
+
+	what.RiskCategories = new(Strings).MergeUniqueSlice(what.RiskCategories, other.RiskCategories)
+
+	return nil
+}
+
+func (what *PenTestFinding) MergeMap(first map[string]PenTestFinding, second map[string]PenTestFinding) (map[string]PenTestFinding, error) {
+	for mapKey, mapValue := range second {
+		mapItem, ok := first[mapKey]
+		if ok {
+			mergeError := mapItem.Merge(mapValue)
+			if mergeError != nil {
+				return first, fmt.Errorf("failed to merge pen-test finding %q: %v", mapKey, mergeError)
+			}
+
+			first[mapKey] = mapItem
+		} else {
+			first[mapKey] = mapValue
+		}
+	}
+
+	return first, nil
+}
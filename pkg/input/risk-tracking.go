@@ -8,6 +8,8 @@ type RiskTracking struct {
 	Ticket        string `yaml:"ticket,omitempty" json:"ticket,omitempty"`
 	Date          string `yaml:"date,omitempty" json:"date,omitempty"`
 	CheckedBy     string `yaml:"checked_by,omitempty" json:"checked_by,omitempty"`
+	Assignee      string `yaml:"assignee,omitempty" json:"assignee,omitempty"`
+	DueDate       string `yaml:"due_date,omitempty" json:"due_date,omitempty"`
 }
 
 func (what *RiskTracking) Merge(other RiskTracking) error {
@@ -37,6 +39,16 @@ func (what *RiskTracking) Merge(other RiskTracking) error {
 		return fmt.Errorf("failed to merge checked_by: %v", mergeError)
 	}
 
+	what.Assignee, mergeError = new(Strings).MergeSingleton(what.Assignee, other.Assignee)
+	if mergeError != nil {
+		return fmt.Errorf("failed to merge assignee: %v", mergeError)
+	}
+
+	what.DueDate, mergeError = new(Strings).MergeSingleton(what.DueDate, other.DueDate)
+	if mergeError != nil {
+		return fmt.Errorf("failed to merge due_date: %v", mergeError)
+	}
+
 	return nil
 }
 
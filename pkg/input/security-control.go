@@ -0,0 +1,61 @@
+package input
+
+import "fmt"
+
+type SecurityControl struct {
+	ID                  string   `yaml:"id,omitempty" json:"id,omitempty"`
+	Description         string   `yaml:"description,omitempty" json:"description,omitempty"`
+	Type                string   `yaml:"type,omitempty" json:"type,omitempty"`
+	LikelihoodReduction int      `yaml:"likelihood_reduction,omitempty" json:"likelihood_reduction,omitempty"`
+	Mitigates           []string `yaml:"mitigates,omitempty" json:"mitigates,omitempty"`
+	TechnicalAssets     []string `yaml:"technical_assets,omitempty" json:"technical_assets,omitempty"`
+	TrustBoundaries     []string `yaml:"trust_boundaries,omitempty" json:"trust_boundaries,omitempty"`
+}
+
+func (what *SecurityControl) Merge(other SecurityControl) error {
+	var mergeError error
+	what.ID, mergeError = new(Strings).MergeSingleton(what.ID, other.ID)
+	if mergeError != nil {
+		return fmt.Errorf("failed to merge id: %v", mergeError)
+	}
+
+	what.Description, mergeError = new(Strings).MergeSingleton(what.Description, other.Description)
+	if mergeError != nil {
+		return fmt.Errorf("failed to merge description: %v", mergeError)
+	}
+
+	what.Type, mergeError = new(Strings).MergeSingleton(what.Type, other.Type)
+	if mergeError != nil {
+		return fmt.Errorf("failed to merge type: %v", mergeError)
+	}
+
+	if what.LikelihoodReduction == 0 {
+		what.LikelihoodReduction = other.LikelihoodReduction
+	}
+
+	what.Mitigates = new(Strings).MergeUniqueSlice(what.Mitigates, other.Mitigates)
+
+	what.TechnicalAssets = new(Strings).MergeUniqueSlice(what.TechnicalAssets, other.TechnicalAssets)
+
+	what.TrustBoundaries = new(Strings).MergeUniqueSlice(what.TrustBoundaries, other.TrustBoundaries)
+
+	return nil
+}
+
+func (what *SecurityControl) MergeMap(first map[string]SecurityControl, second map[string]SecurityControl) (map[string]SecurityControl, error) {
+	for mapKey, mapValue := range second {
+		mapItem, ok := first[mapKey]
+		if ok {
+			mergeError := mapItem.Merge(mapValue)
+			if mergeError != nil {
+				return first, fmt.Errorf("failed to merge security control %q: %v", mapKey, mergeError)
+			}
+
+			first[mapKey] = mapItem
+		} else {
+			first[mapKey] = mapValue
+		}
+	}
+
+	return first, nil
+}
@@ -0,0 +1,173 @@
+package input
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"time"
+)
+
+const maxServiceMetadataSize = 1024 * 1024
+
+// ServiceMetadata is the model fragment a running service publishes at its metadata endpoint, e.g.
+// {"id": "payment-service", "dependencies": [{"target": "payment-db", "protocol": "jdbc-encrypted"}]}
+type ServiceMetadata struct {
+	ID           string              `json:"id"`
+	Dependencies []ServiceDependency `json:"dependencies,omitempty"`
+
+	Source string `json:"-"`
+}
+
+// ServiceDependency declares a communication link of the service, only the attributes given are applied to the model
+type ServiceDependency struct {
+	Title              string   `json:"title,omitempty"` // title of the communication link, defaults to the target
+	Target             string   `json:"target"`
+	Description        string   `json:"description,omitempty"`
+	Protocol           string   `json:"protocol,omitempty"`
+	Authentication     string   `json:"authentication,omitempty"`
+	Authorization      string   `json:"authorization,omitempty"`
+	Usage              string   `json:"usage,omitempty"`
+	Tags               []string `json:"tags,omitempty"`
+	DataAssetsSent     []string `json:"data_assets_sent,omitempty"`
+	DataAssetsReceived []string `json:"data_assets_received,omitempty"`
+	VPN                *bool    `json:"vpn,omitempty"`
+	IpFiltered         *bool    `json:"ip_filtered,omitempty"`
+	Readonly           *bool    `json:"readonly,omitempty"`
+}
+
+// FetchServiceMetadata queries the metadata endpoint of a running service
+func FetchServiceMetadata(ctx context.Context, url string) (*ServiceMetadata, error) {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	request, requestError := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if requestError != nil {
+		return nil, fmt.Errorf("invalid service metadata url %q: %v", url, requestError)
+	}
+	request.Header.Set("Accept", "application/json")
+
+	response, getError := http.DefaultClient.Do(request) // #nosec G107 // URL is configured by the operator
+	if getError != nil {
+		return nil, fmt.Errorf("unable to fetch service metadata %q: %v", url, getError)
+	}
+	defer func() { _ = response.Body.Close() }()
+
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unable to fetch service metadata %q: %v", url, response.Status)
+	}
+
+	data, readError := io.ReadAll(io.LimitReader(response.Body, maxServiceMetadataSize))
+	if readError != nil {
+		return nil, fmt.Errorf("unable to fetch service metadata %q: %v", url, readError)
+	}
+
+	metadata := &ServiceMetadata{Source: url}
+	unmarshalError := json.Unmarshal(data, metadata)
+	if unmarshalError != nil {
+		return nil, fmt.Errorf("unable to parse service metadata %q: %v", url, unmarshalError)
+	}
+	if len(metadata.ID) == 0 {
+		return nil, fmt.Errorf("service metadata %q is missing the technical asset id", url)
+	}
+	return metadata, nil
+}
+
+// ApplyServiceMetadata merges the declared dependencies of the services into the communication links of their technical
+// assets (found by id), adding missing links and updating the declared attributes of existing ones (found by title or
+// else by target), collecting a description of each change
+func (model *Model) ApplyServiceMetadata(services []*ServiceMetadata, dryRun bool, changes *[]string) error {
+	for _, service := range services {
+		assetTitle, asset, found := model.technicalAssetByID(service.ID)
+		if !found {
+			return fmt.Errorf("technical asset %q of service metadata %q not found in model", service.ID, service.Source)
+		}
+
+		links := make(map[string]CommunicationLink)
+		for title, link := range asset.CommunicationLinks {
+			links[title] = link
+		}
+
+		for _, dependency := range service.Dependencies {
+			if len(dependency.Target) == 0 {
+				return fmt.Errorf("dependency of service metadata %q is missing the target", service.Source)
+			}
+			if _, _, targetFound := model.technicalAssetByID(dependency.Target); !targetFound {
+				return fmt.Errorf("target %q of service metadata %q not found in model", dependency.Target, service.Source)
+			}
+
+			title := dependency.Title
+			if len(title) == 0 {
+				title = dependency.Target
+			}
+			if _, ok := links[title]; !ok && len(dependency.Title) == 0 {
+				existingTitles := make([]string, 0, len(links))
+				for existingTitle := range links {
+					existingTitles = append(existingTitles, existingTitle)
+				}
+				slices.Sort(existingTitles) // the first of several links to the target, regardless of the map order
+				for _, existingTitle := range existingTitles {
+					if links[existingTitle].Target == dependency.Target {
+						title = existingTitle
+						break
+					}
+				}
+			}
+
+			link, ok := links[title]
+			if !ok {
+				link = CommunicationLink{Target: dependency.Target}
+				*changes = append(*changes, fmt.Sprintf("adding communication link %q from %q to %q (%v)", title, service.ID, dependency.Target, service.Source))
+			}
+			for _, attribute := range link.applyDependency(dependency) {
+				*changes = append(*changes, fmt.Sprintf("setting %v of communication link %q of %q (%v)", attribute, title, service.ID, service.Source))
+			}
+			links[title] = link
+		}
+
+		if !dryRun {
+			asset.CommunicationLinks = links
+			model.TechnicalAssets[assetTitle] = asset
+		}
+	}
+	return nil
+}
+
+// applyDependency sets the declared attributes and returns the names of those that changed
+func (what *CommunicationLink) applyDependency(dependency ServiceDependency) []string {
+	changed := make([]string, 0)
+	setString := func(name string, field *string, value string) {
+		if len(value) > 0 && *field != value {
+			*field = value
+			changed = append(changed, name)
+		}
+	}
+	setList := func(name string, field *[]string, value []string) {
+		if value != nil && !slices.Equal(*field, value) {
+			*field = value
+			changed = append(changed, name)
+		}
+	}
+	setBool := func(name string, field *bool, value *bool) {
+		if value != nil && *field != *value {
+			*field = *value
+			changed = append(changed, name)
+		}
+	}
+
+	setString("target", &what.Target, dependency.Target)
+	setString("description", &what.Description, dependency.Description)
+	setString("protocol", &what.Protocol, dependency.Protocol)
+	setString("authentication", &what.Authentication, dependency.Authentication)
+	setString("authorization", &what.Authorization, dependency.Authorization)
+	setString("usage", &what.Usage, dependency.Usage)
+	setList("tags", &what.Tags, dependency.Tags)
+	setList("data_assets_sent", &what.DataAssetsSent, dependency.DataAssetsSent)
+	setList("data_assets_received", &what.DataAssetsReceived, dependency.DataAssetsReceived)
+	setBool("vpn", &what.VPN, dependency.VPN)
+	setBool("ip_filtered", &what.IpFiltered, dependency.IpFiltered)
+	setBool("readonly", &what.Readonly, dependency.Readonly)
+	return changed
+}
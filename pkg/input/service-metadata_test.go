@@ -0,0 +1,57 @@
+package input
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFetchAndApplyServiceMetadata(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, _ *http.Request) {
+		_, _ = writer.Write([]byte(`{"id": "payment-service", "dependencies": [
+			{"target": "payment-db", "protocol": "jdbc-encrypted", "readonly": false},
+			{"title": "Fraud Check", "target": "fraud-service", "protocol": "https", "data_assets_sent": ["payment"]}
+		]}`))
+	}))
+	defer server.Close()
+
+	service, err := FetchServiceMetadata(context.Background(), server.URL)
+	assert.NoError(t, err)
+	assert.Equal(t, "payment-service", service.ID)
+	assert.Len(t, service.Dependencies, 2)
+
+	model := new(Model).Defaults()
+	model.TechnicalAssets["Payment Service"] = TechnicalAsset{ID: "payment-service", CommunicationLinks: map[string]CommunicationLink{
+		"Database Access": {Target: "payment-db", Protocol: "jdbc", Authentication: "credentials", Readonly: true},
+	}}
+	model.TechnicalAssets["Payment DB"] = TechnicalAsset{ID: "payment-db"}
+	model.TechnicalAssets["Fraud Service"] = TechnicalAsset{ID: "fraud-service"}
+
+	changes := make([]string, 0)
+	assert.NoError(t, model.ApplyServiceMetadata([]*ServiceMetadata{service}, false, &changes))
+	assert.Len(t, changes, 5)
+
+	links := model.TechnicalAssets["Payment Service"].CommunicationLinks
+	assert.Len(t, links, 2)
+	assert.Equal(t, CommunicationLink{Target: "payment-db", Protocol: "jdbc-encrypted", Authentication: "credentials"}, links["Database Access"])
+	assert.Equal(t, "https", links["Fraud Check"].Protocol)
+	assert.Equal(t, []string{"payment"}, links["Fraud Check"].DataAssetsSent)
+
+	changes = make([]string, 0)
+	assert.NoError(t, model.ApplyServiceMetadata([]*ServiceMetadata{service}, false, &changes))
+	assert.Empty(t, changes)
+
+	model.TechnicalAssets["Payment Service"].CommunicationLinks["Audit Access"] = CommunicationLink{Target: "payment-db", Protocol: "jdbc"}
+	for run := 0; run < 10; run++ {
+		changes = make([]string, 0)
+		dbService := &ServiceMetadata{ID: "payment-service", Source: server.URL, Dependencies: []ServiceDependency{{Target: "payment-db", Usage: "devops"}}}
+		assert.NoError(t, model.ApplyServiceMetadata([]*ServiceMetadata{dbService}, true, &changes))
+		assert.Equal(t, []string{`setting usage of communication link "Audit Access" of "payment-service" (` + server.URL + `)`}, changes, "the first link to the target")
+	}
+
+	unknownService := &ServiceMetadata{ID: "unknown-service", Source: server.URL}
+	assert.Error(t, model.ApplyServiceMetadata([]*ServiceMetadata{unknownService}, false, &changes))
+}
@@ -0,0 +1,270 @@
+package input
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/md5" // #nosec G501 // required by the signature scheme of the Structurizr API
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const maxStructurizrWorkspaceSize = 16 * 1024 * 1024
+
+// StructurizrWorkspace is the part of a Structurizr workspace (json as served by its API or exported) synchronized
+// into the model: the containers of the software systems and their relationships
+type StructurizrWorkspace struct {
+	ID    int64 `json:"id"`
+	Model struct {
+		SoftwareSystems []struct {
+			ID         string                 `json:"id"`
+			Name       string                 `json:"name"`
+			Containers []StructurizrContainer `json:"containers,omitempty"`
+		} `json:"softwareSystems,omitempty"`
+	} `json:"model"`
+
+	Source string `json:"-"`
+}
+
+type StructurizrContainer struct {
+	ID            string                    `json:"id"`
+	Name          string                    `json:"name"`
+	Description   string                    `json:"description,omitempty"`
+	Technology    string                    `json:"technology,omitempty"`
+	Relationships []StructurizrRelationship `json:"relationships,omitempty"`
+}
+
+type StructurizrRelationship struct {
+	ID                   string `json:"id"`
+	SourceID             string `json:"sourceId"`
+	DestinationID        string `json:"destinationId"`
+	Description          string `json:"description,omitempty"`
+	Technology           string `json:"technology,omitempty"`
+	LinkedRelationshipID string `json:"linkedRelationshipId,omitempty"` // set for relationships implied by ones of nested elements
+}
+
+// StructurizrMapping guards the synchronization: only the containers mapped to technical assets are taken over, e.g.
+// containers: {"Web Application": web-app}, technologies: {"Java and Spring MVC": web-server}, protocols: {"JSON/HTTPS": https}
+type StructurizrMapping struct {
+	Containers   map[string]string `yaml:"containers" json:"containers"`     // container id or name -> technical asset id
+	Technologies map[string]string `yaml:"technologies" json:"technologies"` // container technology -> technical asset technology, others are not taken over
+	Protocols    map[string]string `yaml:"protocols" json:"protocols"`       // relationship technology -> communication link protocol, others are not taken over
+}
+
+// LoadStructurizrMapping reads the mapping file (yaml or json)
+func LoadStructurizrMapping(filename string) (*StructurizrMapping, error) {
+	data, readError := os.ReadFile(filepath.Clean(filename))
+	if readError != nil {
+		return nil, fmt.Errorf("unable to read structurizr mapping %q: %v", filename, readError)
+	}
+	mapping := new(StructurizrMapping)
+	unmarshalError := Unmarshal(filename, data, mapping)
+	if unmarshalError != nil {
+		return nil, fmt.Errorf("invalid structurizr mapping %q: %v", filename, unmarshalError)
+	}
+	if len(mapping.Containers) == 0 {
+		return nil, fmt.Errorf("structurizr mapping %q maps no containers", filename)
+	}
+	return mapping, nil
+}
+
+// FetchStructurizrWorkspace reads the workspace from a json file or else from the workspace API (e.g.
+// https://api.structurizr.com/workspace/1234), signing the request with the API key and secret if given
+func FetchStructurizrWorkspace(ctx context.Context, source string, apiKey string, apiSecret string) (*StructurizrWorkspace, error) {
+	var data []byte
+	if !strings.Contains(source, "://") {
+		var readError error
+		data, readError = os.ReadFile(filepath.Clean(source))
+		if readError != nil {
+			return nil, fmt.Errorf("unable to read structurizr workspace %q: %v", source, readError)
+		}
+	} else {
+		ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+		defer cancel()
+
+		request, requestError := http.NewRequestWithContext(ctx, http.MethodGet, source, nil)
+		if requestError != nil {
+			return nil, fmt.Errorf("invalid structurizr workspace url %q: %v", source, requestError)
+		}
+		request.Header.Set("Accept", "application/json")
+		if len(apiKey) > 0 {
+			nonce := strconv.FormatInt(time.Now().UnixMilli(), 10)
+			request.Header.Set("Nonce", nonce)
+			request.Header.Set("X-Authorization", apiKey+":"+structurizrSignature(apiSecret, http.MethodGet, request.URL.RequestURI(), nonce))
+		}
+
+		response, getError := http.DefaultClient.Do(request) // #nosec G107 // URL is configured by the operator
+		if getError != nil {
+			return nil, fmt.Errorf("unable to fetch structurizr workspace %q: %v", source, getError)
+		}
+		defer func() { _ = response.Body.Close() }()
+
+		if response.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("unable to fetch structurizr workspace %q: %v", source, response.Status)
+		}
+
+		var readError error
+		data, readError = io.ReadAll(io.LimitReader(response.Body, maxStructurizrWorkspaceSize))
+		if readError != nil {
+			return nil, fmt.Errorf("unable to fetch structurizr workspace %q: %v", source, readError)
+		}
+	}
+
+	workspace := &StructurizrWorkspace{Source: source}
+	unmarshalError := json.Unmarshal(data, workspace)
+	if unmarshalError != nil {
+		return nil, fmt.Errorf("unable to parse structurizr workspace %q: %v", source, unmarshalError)
+	}
+	return workspace, nil
+}
+
+// structurizrSignature is the HMAC of a request without body as expected by the workspace API
+func structurizrSignature(apiSecret string, method string, path string, nonce string) string {
+	emptyContent := md5.Sum(nil) // #nosec G401 // required by the signature scheme of the Structurizr API
+	content := method + "\n" + path + "\n" + hex.EncodeToString(emptyContent[:]) + "\n\n" + nonce + "\n"
+	mac := hmac.New(sha256.New, []byte(apiSecret))
+	_, _ = mac.Write([]byte(content))
+	return base64.StdEncoding.EncodeToString([]byte(hex.EncodeToString(mac.Sum(nil))))
+}
+
+// ApplyStructurizrWorkspace synchronizes the mapped containers of the workspace into their technical assets (creating
+// missing ones and taking over name, description and mapped technology) and their relationships among each other into
+// communication links (found by target, else added), collecting a description of each change; nothing is removed, and
+// containers mapped to the same technical asset or renaming a technical asset like another one are rejected
+func (model *Model) ApplyStructurizrWorkspace(workspace *StructurizrWorkspace, mapping *StructurizrMapping, dryRun bool, changes *[]string) error {
+	type syncedAsset struct {
+		title       string
+		renamedFrom string
+		asset       TechnicalAsset
+		links       map[string]CommunicationLink
+	}
+	assets := make(map[string]*syncedAsset)
+	containerIDs := make(map[string]string) // technical asset id -> container id
+	assetIDs := make(map[string]string)     // container id -> technical asset id
+	containers := make([]StructurizrContainer, 0)
+
+	for _, system := range workspace.Model.SoftwareSystems {
+		for _, container := range system.Containers {
+			assetID, mapped := mapping.Containers[container.ID]
+			if !mapped {
+				assetID, mapped = mapping.Containers[container.Name]
+			}
+			if !mapped || len(assetID) == 0 {
+				continue
+			}
+			if len(container.Name) == 0 {
+				return fmt.Errorf("container %q of structurizr workspace %q is missing the name", container.ID, workspace.Source)
+			}
+			if otherContainerID, taken := containerIDs[assetID]; taken {
+				return fmt.Errorf("containers %q and %q of structurizr workspace %q are both mapped to technical asset %q", otherContainerID, container.ID, workspace.Source, assetID)
+			}
+			containerIDs[assetID] = container.ID
+			assetIDs[container.ID] = assetID
+			containers = append(containers, container)
+
+			title, asset, found := model.technicalAssetByID(assetID)
+			renamedFrom := ""
+			if !found || title != container.Name {
+				if other, exists := model.TechnicalAssets[container.Name]; exists && other.ID != assetID {
+					return fmt.Errorf("container %q of structurizr workspace %q names technical asset %q like technical asset %q", container.ID, workspace.Source, assetID, other.ID)
+				}
+				for _, synced := range assets {
+					if synced.title == container.Name {
+						return fmt.Errorf("container %q of structurizr workspace %q names technical asset %q like technical asset %q", container.ID, workspace.Source, assetID, synced.asset.ID)
+					}
+				}
+			}
+			if !found {
+				title = container.Name
+				asset = TechnicalAsset{ID: assetID}
+				*changes = append(*changes, fmt.Sprintf("adding technical asset %q for container %q (%v)", assetID, container.Name, workspace.Source))
+			} else if title != container.Name {
+				*changes = append(*changes, fmt.Sprintf("renaming technical asset %q to %q (%v)", assetID, container.Name, workspace.Source))
+				renamedFrom, title = title, container.Name
+			}
+
+			if len(container.Description) > 0 && asset.Description != container.Description {
+				asset.Description = container.Description
+				*changes = append(*changes, fmt.Sprintf("setting description of technical asset %q (%v)", assetID, workspace.Source))
+			}
+			if technology := mapping.Technologies[container.Technology]; len(technology) > 0 && asset.Technology != technology {
+				asset.Technology = technology
+				*changes = append(*changes, fmt.Sprintf("setting technology of technical asset %q to %q (%v)", assetID, technology, workspace.Source))
+			}
+
+			links := make(map[string]CommunicationLink)
+			for linkTitle, link := range asset.CommunicationLinks {
+				links[linkTitle] = link
+			}
+			assets[assetID] = &syncedAsset{title: title, renamedFrom: renamedFrom, asset: asset, links: links}
+		}
+	}
+
+	for _, container := range containers {
+		source := assets[assetIDs[container.ID]]
+		claimed := make(map[string]bool) // links already taken by other relationships, so parallel ones do not collapse
+		for _, relationship := range container.Relationships {
+			targetID, mapped := assetIDs[relationship.DestinationID]
+			if !mapped || len(relationship.LinkedRelationshipID) > 0 {
+				continue
+			}
+
+			title := ""
+			for _, existingTitle := range sortedLinkTitles(source.links) {
+				if source.links[existingTitle].Target == targetID && !claimed[existingTitle] && (len(title) == 0 || existingTitle == relationship.Description) {
+					title = existingTitle
+				}
+			}
+			if len(title) == 0 {
+				title = relationship.Description
+				if len(title) == 0 || claimed[title] {
+					title = strings.TrimSpace(title + " " + targetID)
+				}
+			}
+			claimed[title] = true
+
+			link, ok := source.links[title]
+			if !ok {
+				link = CommunicationLink{Target: targetID}
+				*changes = append(*changes, fmt.Sprintf("adding communication link %q from %q to %q (%v)", title, source.asset.ID, targetID, workspace.Source))
+			}
+			dependency := ServiceDependency{Target: targetID, Description: relationship.Description, Protocol: mapping.Protocols[relationship.Technology]}
+			for _, attribute := range link.applyDependency(dependency) {
+				*changes = append(*changes, fmt.Sprintf("setting %v of communication link %q of %q (%v)", attribute, title, source.asset.ID, workspace.Source))
+			}
+			source.links[title] = link
+		}
+	}
+
+	if !dryRun {
+		for _, synced := range assets {
+			if len(synced.renamedFrom) > 0 {
+				delete(model.TechnicalAssets, synced.renamedFrom)
+			}
+		}
+		for _, synced := range assets {
+			synced.asset.CommunicationLinks = synced.links
+			model.TechnicalAssets[synced.title] = synced.asset
+		}
+	}
+	return nil
+}
+
+func sortedLinkTitles(links map[string]CommunicationLink) []string {
+	titles := make([]string, 0, len(links))
+	for title := range links {
+		titles = append(titles, title)
+	}
+	slices.Sort(titles)
+	return titles
+}
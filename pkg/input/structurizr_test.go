@@ -0,0 +1,103 @@
+package input
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const structurizrTestWorkspace = `{"id": 1234, "model": {"softwareSystems": [{"id": "1", "name": "Shop", "containers": [
+	{"id": "2", "name": "Web Shop", "description": "Sells the products", "technology": "Java and Spring MVC", "relationships": [
+		{"id": "5", "sourceId": "2", "destinationId": "3", "description": "Reads and writes orders", "technology": "JDBC"},
+		{"id": "6", "sourceId": "2", "destinationId": "4", "description": "Logs to", "technology": "Syslog"},
+		{"id": "7", "sourceId": "2", "destinationId": "3", "description": "Implied", "linkedRelationshipId": "8"}
+	]},
+	{"id": "3", "name": "Order Database", "technology": "PostgreSQL"},
+	{"id": "4", "name": "Log Collector"}
+]}]}}`
+
+func TestFetchAndApplyStructurizrWorkspace(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		authorization := strings.SplitN(request.Header.Get("X-Authorization"), ":", 2)
+		if len(authorization) != 2 || authorization[0] != "key" ||
+			authorization[1] != structurizrSignature("secret", http.MethodGet, request.URL.RequestURI(), request.Header.Get("Nonce")) {
+			writer.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_, _ = writer.Write([]byte(structurizrTestWorkspace))
+	}))
+	defer server.Close()
+
+	_, err := FetchStructurizrWorkspace(context.Background(), server.URL+"/workspace/1234", "key", "wrong")
+	assert.ErrorContains(t, err, "401")
+	workspace, err := FetchStructurizrWorkspace(context.Background(), server.URL+"/workspace/1234", "key", "secret")
+	assert.NoError(t, err)
+	assert.Len(t, workspace.Model.SoftwareSystems[0].Containers, 3)
+
+	mappingFile := filepath.Join(t.TempDir(), "mapping.yaml")
+	assert.NoError(t, os.WriteFile(mappingFile, []byte(`
+containers:
+  "2": web-shop
+  Order Database: order-db
+technologies:
+  Java and Spring MVC: web-server
+  PostgreSQL: database
+protocols:
+  JDBC: jdbc-encrypted
+`), 0600))
+	mapping, err := LoadStructurizrMapping(mappingFile)
+	assert.NoError(t, err)
+
+	model := new(Model).Defaults()
+	model.TechnicalAssets["Shop"] = TechnicalAsset{ID: "web-shop", Technology: "web-application", CommunicationLinks: map[string]CommunicationLink{
+		"Database Access": {Target: "order-db", Protocol: "jdbc", Authentication: "credentials"},
+	}}
+	model.TechnicalAssets["Log Collector"] = TechnicalAsset{ID: "log-collector"}
+
+	changes := make([]string, 0)
+	assert.NoError(t, model.ApplyStructurizrWorkspace(workspace, mapping, true, &changes))
+	assert.NotEmpty(t, changes)
+	assert.Contains(t, model.TechnicalAssets, "Shop", "dry run")
+	assert.NotContains(t, model.TechnicalAssets, "Order Database", "dry run")
+
+	changes = make([]string, 0)
+	assert.NoError(t, model.ApplyStructurizrWorkspace(workspace, mapping, false, &changes))
+	assert.Len(t, changes, 7)
+	assert.NotContains(t, model.TechnicalAssets, "Shop", "renamed")
+	shop := model.TechnicalAssets["Web Shop"]
+	assert.Equal(t, "web-server", shop.Technology)
+	assert.Equal(t, "Sells the products", shop.Description)
+	assert.Equal(t, map[string]CommunicationLink{
+		"Database Access": {Target: "order-db", Description: "Reads and writes orders", Protocol: "jdbc-encrypted", Authentication: "credentials"},
+	}, shop.CommunicationLinks, "unmapped and implied relationships skipped")
+	assert.Equal(t, TechnicalAsset{ID: "order-db", Technology: "database", CommunicationLinks: map[string]CommunicationLink{}}, model.TechnicalAssets["Order Database"])
+	assert.Equal(t, TechnicalAsset{ID: "log-collector"}, model.TechnicalAssets["Log Collector"], "unmapped container not touched")
+
+	changes = make([]string, 0)
+	assert.NoError(t, model.ApplyStructurizrWorkspace(workspace, mapping, false, &changes))
+	assert.Empty(t, changes, "already in sync")
+}
+
+func TestApplyStructurizrWorkspaceRejectsCollisions(t *testing.T) {
+	workspace := &StructurizrWorkspace{Source: "workspace.json"}
+	assert.NoError(t, json.Unmarshal([]byte(structurizrTestWorkspace), workspace))
+
+	model := new(Model).Defaults()
+	model.TechnicalAssets["Log Collector"] = TechnicalAsset{ID: "log-collector"}
+	changes := make([]string, 0)
+	err := model.ApplyStructurizrWorkspace(workspace, &StructurizrMapping{Containers: map[string]string{"2": "web-shop", "3": "web-shop"}}, false, &changes)
+	assert.ErrorContains(t, err, `containers "2" and "3" of structurizr workspace "workspace.json" are both mapped to technical asset "web-shop"`)
+
+	model.TechnicalAssets["Logging"] = TechnicalAsset{ID: "logging"}
+	err = model.ApplyStructurizrWorkspace(workspace, &StructurizrMapping{Containers: map[string]string{"4": "logging"}}, false, &changes)
+	assert.ErrorContains(t, err, `names technical asset "logging" like technical asset "log-collector"`)
+	assert.Equal(t, TechnicalAsset{ID: "logging"}, model.TechnicalAssets["Logging"], "not renamed")
+	assert.Equal(t, TechnicalAsset{ID: "log-collector"}, model.TechnicalAssets["Log Collector"], "not overwritten")
+}
@@ -29,6 +29,7 @@ type TechnicalAsset struct {
 	DataAssetsStored        []string                     `yaml:"data_assets_stored,omitempty" json:"data_assets_stored,omitempty"`
 	DataFormatsAccepted     []string                     `yaml:"data_formats_accepted,omitempty" json:"data_formats_accepted,omitempty"`
 	DiagramTweakOrder       int                          `yaml:"diagram_tweak_order,omitempty" json:"diagram_tweak_order,omitempty"`
+	SBOM                    string                       `yaml:"sbom,omitempty" json:"sbom,omitempty"` // CycloneDX or SPDX json file (relative to the model file) or URL
 	CommunicationLinks      map[string]CommunicationLink `yaml:"communication_links,omitempty" json:"communication_links,omitempty"`
 }
 
@@ -134,6 +135,11 @@ func (what *TechnicalAsset) Merge(other TechnicalAsset) error {
 		what.DiagramTweakOrder = other.DiagramTweakOrder
 	}
 
+	what.SBOM, mergeError = new(Strings).MergeSingleton(what.SBOM, other.SBOM)
+	if mergeError != nil {
+		return fmt.Errorf("failed to merge sbom: %v", mergeError)
+	}
+
 	what.CommunicationLinks, mergeError = new(CommunicationLink).MergeMap(what.CommunicationLinks, other.CommunicationLinks)
 	if mergeError != nil {
 		return fmt.Errorf("failed to merge communication_links: %v", mergeError)
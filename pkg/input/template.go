@@ -0,0 +1,122 @@
+package input
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"text/template"
+)
+
+// templating of the model files, expanded before parsing them so that values like server names, owners or tags can be
+// injected per environment
+const (
+	NoTemplating         = ""
+	EnvTemplating        = "env"         // ${VAR} or ${VAR:-default} replaced by environment variables, $${ escapes ${
+	GoTemplateTemplating = "go-template" // Go templates with the environment variables as data, e.g. {{ .VAR }} or {{ env "VAR" }}
+)
+
+// TemplatingValues lists the supported templating of the model files
+func TemplatingValues() []string {
+	return []string{NoTemplating, EnvTemplating, GoTemplateTemplating}
+}
+
+// ModelReader reads the model files, the one given and its includes
+type ModelReader func(filename string) ([]byte, error)
+
+// TemplatingReader reads the model files expanding their templating
+func TemplatingReader(templating string) (ModelReader, error) {
+	return TemplatingReaderOf(ReadModelFile, templating)
+}
+
+// TemplatingReaderOf reads the model files with the reader expanding their templating
+func TemplatingReaderOf(read ModelReader, templating string) (ModelReader, error) {
+	switch templating {
+	case NoTemplating:
+		return read, nil
+
+	case EnvTemplating, GoTemplateTemplating:
+		return func(filename string) ([]byte, error) {
+			modelData, readError := read(filename)
+			if readError != nil {
+				return nil, readError
+			}
+			return ExpandTemplating(filename, modelData, templating)
+		}, nil
+	}
+
+	return nil, fmt.Errorf("unknown model templating %q (must be one of %q)", templating, TemplatingValues())
+}
+
+var envVariablePattern = regexp.MustCompile(`\$\$\{|\$\{([A-Za-z_][A-Za-z0-9_]*)(:-([^}]*))?}`)
+
+// ExpandTemplating expands the templating of the model file content with the environment variables, failing with all
+// variables missing (without default)
+func ExpandTemplating(filename string, modelData []byte, templating string) ([]byte, error) {
+	switch templating {
+	case NoTemplating:
+		return modelData, nil
+
+	case EnvTemplating:
+		missing := make(map[string]bool)
+		expanded := envVariablePattern.ReplaceAllFunc(modelData, func(match []byte) []byte {
+			groups := envVariablePattern.FindSubmatch(match)
+			if groups[1] == nil {
+				return []byte("${")
+			}
+			if value, ok := os.LookupEnv(string(groups[1])); ok {
+				return []byte(value)
+			}
+			if groups[2] != nil {
+				return groups[3]
+			}
+			missing[string(groups[1])] = true
+			return match
+		})
+		if len(missing) > 0 {
+			names := make([]string, 0, len(missing))
+			for name := range missing {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			return nil, fmt.Errorf("unable to expand model file %q: environment variables not set: %v (set them or use ${NAME:-default})", filename, strings.Join(names, ", "))
+		}
+		return expanded, nil
+
+	case GoTemplateTemplating:
+		variables := make(map[string]string)
+		for _, variable := range os.Environ() {
+			name, value, _ := strings.Cut(variable, "=")
+			variables[name] = value
+		}
+		functions := template.FuncMap{
+			"env": func(name string) (string, error) {
+				if value, ok := variables[name]; ok {
+					return value, nil
+				}
+				return "", fmt.Errorf("environment variable %v not set", name)
+			},
+			"envOr": func(name string, defaultValue string) string {
+				if value, ok := variables[name]; ok {
+					return value
+				}
+				return defaultValue
+			},
+		}
+		modelTemplate, parseError := template.New(filepath.Base(filename)).Option("missingkey=error").Funcs(functions).Parse(string(modelData))
+		if parseError != nil {
+			return nil, fmt.Errorf("unable to parse template of model file %q: %v", filename, parseError)
+		}
+		var expanded bytes.Buffer
+		executeError := modelTemplate.Execute(&expanded, variables)
+		if executeError != nil {
+			return nil, fmt.Errorf("unable to expand model file %q: %v", filename, executeError)
+		}
+		return expanded.Bytes(), nil
+	}
+
+	return nil, fmt.Errorf("unknown model templating %q (must be one of %q)", templating, TemplatingValues())
+}
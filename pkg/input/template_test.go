@@ -0,0 +1,46 @@
+package input
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExpandTemplating(t *testing.T) {
+	t.Setenv("THREAGILE_TEST_HOST", "shop.example.com")
+	t.Setenv("THREAGILE_TEST_OWNER", "ops@example.com")
+
+	expanded, err := ExpandTemplating("threagile.yaml", []byte("title: ${THREAGILE_TEST_HOST}\nowner: ${THREAGILE_TEST_TEAM:-unknown} $${literal}\n"), EnvTemplating)
+	assert.NoError(t, err)
+	assert.Equal(t, "title: shop.example.com\nowner: unknown ${literal}\n", string(expanded))
+
+	_, err = ExpandTemplating("threagile.yaml", []byte("title: ${THREAGILE_TEST_MISSING_B} ${THREAGILE_TEST_MISSING_A} ${THREAGILE_TEST_MISSING_B}\n"), EnvTemplating)
+	assert.EqualError(t, err, `unable to expand model file "threagile.yaml": environment variables not set: THREAGILE_TEST_MISSING_A, THREAGILE_TEST_MISSING_B (set them or use ${NAME:-default})`)
+
+	expanded, err = ExpandTemplating("threagile.yaml", []byte(`title: {{ .THREAGILE_TEST_HOST }} {{ env "THREAGILE_TEST_OWNER" }} {{ envOr "THREAGILE_TEST_TEAM" "unknown" }}`), GoTemplateTemplating)
+	assert.NoError(t, err)
+	assert.Equal(t, "title: shop.example.com ops@example.com unknown", string(expanded))
+
+	_, err = ExpandTemplating("threagile.yaml", []byte(`title: {{ env "THREAGILE_TEST_MISSING" }}`), GoTemplateTemplating)
+	assert.ErrorContains(t, err, "environment variable THREAGILE_TEST_MISSING not set")
+	_, err = ExpandTemplating("threagile.yaml", []byte(`title: {{ .THREAGILE_TEST_MISSING }}`), GoTemplateTemplating)
+	assert.ErrorContains(t, err, `map has no entry for key "THREAGILE_TEST_MISSING"`)
+
+	_, err = TemplatingReader("jinja")
+	assert.ErrorContains(t, err, `unknown model templating "jinja"`)
+}
+
+func TestLoadWithTemplating(t *testing.T) {
+	t.Setenv("THREAGILE_TEST_TAG", "aws")
+	folder := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(folder, "threagile.yaml"), []byte("title: Shop\nincludes: [tags.yaml]\n"), 0600))
+	assert.NoError(t, os.WriteFile(filepath.Join(folder, "tags.yaml"), []byte("tags_available: [${THREAGILE_TEST_TAG}]\n"), 0600))
+
+	read, err := TemplatingReader(EnvTemplating)
+	assert.NoError(t, err)
+	modelInput := new(Model).Defaults()
+	assert.NoError(t, modelInput.LoadWith(filepath.Join(folder, "threagile.yaml"), read))
+	assert.Equal(t, []string{"aws"}, modelInput.TagsAvailable, "includes expanded as well")
+}
@@ -6,6 +6,7 @@ type TrustBoundary struct {
 	ID                    string   `yaml:"id,omitempty" json:"id,omitempty"`
 	Description           string   `yaml:"description,omitempty" json:"description,omitempty"`
 	Type                  string   `yaml:"type,omitempty" json:"type,omitempty"`
+	Criticality           string   `yaml:"criticality,omitempty" json:"criticality,omitempty"`
 	Tags                  []string `yaml:"tags,omitempty" json:"tags,omitempty"`
 	TechnicalAssetsInside []string `yaml:"technical_assets_inside,omitempty" json:"technical_assets_inside,omitempty"`
 	TrustBoundariesNested []string `yaml:"trust_boundaries_nested,omitempty" json:"trust_boundaries_nested,omitempty"`
@@ -28,6 +29,11 @@ func (what *TrustBoundary) Merge(other TrustBoundary) error {
 		return fmt.Errorf("failed to merge type: %v", mergeError)
 	}
 
+	what.Criticality, mergeError = new(Strings).MergeSingleton(what.Criticality, other.Criticality)
+	if mergeError != nil {
+		return fmt.Errorf("failed to merge criticality: %v", mergeError)
+	}
+
 	what.Tags = new(Strings).MergeUniqueSlice(what.Tags, other.Tags)
 
 	what.TechnicalAssetsInside = new(Strings).MergeUniqueSlice(what.TechnicalAssetsInside, other.TechnicalAssetsInside)
@@ -0,0 +1,303 @@
+package otm
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/threagile/threagile/pkg/input"
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+// Export converts the model into an OTM document
+func Export(modelInput *input.Model) (*OTM, error) {
+	exporter := &otmExporter{
+		model:            modelInput,
+		document:         &OTM{OTMVersion: Version},
+		boundaryOfAsset:  make(map[string]string),
+		parentOfBoundary: make(map[string]string),
+	}
+	for _, boundary := range modelInput.TrustBoundaries {
+		for _, assetId := range boundary.TechnicalAssetsInside {
+			exporter.boundaryOfAsset[assetId] = boundary.ID
+		}
+		for _, nestedId := range boundary.TrustBoundariesNested {
+			exporter.parentOfBoundary[nestedId] = boundary.ID
+		}
+	}
+
+	steps := []func() error{exporter.exportProject, exporter.exportAssets, exporter.exportTrustZones, exporter.exportComponents, exporter.exportThreats}
+	for _, step := range steps {
+		err := step()
+		if err != nil {
+			return nil, err
+		}
+	}
+	return exporter.document, nil
+}
+
+type otmExporter struct {
+	model            *input.Model
+	document         *OTM
+	boundaryOfAsset  map[string]string // trust boundary ids by technical asset id
+	parentOfBoundary map[string]string // trust boundary ids by nested trust boundary id
+	internetUsed     bool
+	outsideUsed      bool
+}
+
+func (what *otmExporter) exportProject() error {
+	project := *what.model
+	project.Includes = nil // merged already
+	project.DataAssets = nil
+	project.TechnicalAssets = nil
+	project.TrustBoundaries = nil
+	project.CustomRiskCategories = nil
+	attributes, err := threagileAttributes(project)
+	if err != nil {
+		return err
+	}
+	id := types.MakeID(what.model.Title)
+	if len(id) == 0 {
+		id = "threat-model"
+	}
+	what.document.Project = Project{
+		Name:         what.model.Title,
+		ID:           id,
+		Description:  what.model.BusinessOverview.Description,
+		Owner:        what.model.Author.Name,
+		OwnerContact: what.model.Author.Contact,
+		Tags:         what.model.TagsAvailable,
+		Attributes:   attributes,
+	}
+	return nil
+}
+
+func (what *otmExporter) exportAssets() error {
+	for _, title := range sortedTitles(what.model.DataAssets) {
+		dataAsset := what.model.DataAssets[title]
+		attributes, err := threagileAttributes(dataAsset)
+		if err != nil {
+			return err
+		}
+		what.document.Assets = append(what.document.Assets, Asset{
+			ID:          dataAsset.ID,
+			Name:        title,
+			Description: dataAsset.Description,
+			Risk: AssetRisk{
+				Confidentiality: enumRating(dataAsset.Confidentiality, types.ConfidentialityValues()),
+				Integrity:       enumRating(dataAsset.Integrity, types.CriticalityValues()),
+				Availability:    enumRating(dataAsset.Availability, types.CriticalityValues()),
+				Comment:         dataAsset.JustificationCiaRating,
+			},
+			Attributes: attributes,
+		})
+	}
+	return nil
+}
+
+func (what *otmExporter) exportTrustZones() error {
+	for _, title := range sortedTitles(what.model.TrustBoundaries) {
+		boundary := what.model.TrustBoundaries[title]
+		properties := boundary
+		properties.TechnicalAssetsInside = nil
+		properties.TrustBoundariesNested = nil
+		attributes, err := threagileAttributes(properties)
+		if err != nil {
+			return err
+		}
+		trustZone := TrustZone{
+			ID:          boundary.ID,
+			Name:        title,
+			Type:        boundary.Type,
+			Description: boundary.Description,
+			Risk:        TrustZoneRisk{TrustRating: trustBoundaryTrustRating},
+			Attributes:  attributes,
+		}
+		if parentId, nested := what.parentOfBoundary[boundary.ID]; nested {
+			trustZone.Parent = &Parent{TrustZone: parentId}
+		}
+		what.document.TrustZones = append(what.document.TrustZones, trustZone)
+	}
+	return nil
+}
+
+func (what *otmExporter) exportComponents() error {
+	for _, title := range sortedTitles(what.model.TechnicalAssets) {
+		asset := what.model.TechnicalAssets[title]
+		properties := asset
+		properties.CommunicationLinks = nil
+		attributes, err := threagileAttributes(properties)
+		if err != nil {
+			return err
+		}
+		component := Component{
+			ID:          asset.ID,
+			Name:        title,
+			Type:        asset.Technology,
+			Description: asset.Description,
+			Parent:      Parent{TrustZone: what.trustZoneOf(asset)},
+			Tags:        asset.Tags,
+			Attributes:  attributes,
+		}
+		if len(component.Type) == 0 && len(asset.Technologies) > 0 {
+			component.Type = asset.Technologies[0]
+		}
+		if len(asset.DataAssetsProcessed) > 0 || len(asset.DataAssetsStored) > 0 {
+			component.Assets = &ComponentAssets{Processed: asset.DataAssetsProcessed, Stored: asset.DataAssetsStored}
+		}
+		what.document.Components = append(what.document.Components, component)
+
+		for _, linkTitle := range sortedTitles(asset.CommunicationLinks) {
+			link := asset.CommunicationLinks[linkTitle]
+			attributes, err := threagileAttributes(link)
+			if err != nil {
+				return err
+			}
+			what.document.Dataflows = append(what.document.Dataflows, Dataflow{
+				ID:            asset.ID + ">" + types.MakeID(linkTitle),
+				Name:          linkTitle,
+				Description:   link.Description,
+				Bidirectional: len(link.DataAssetsReceived) > 0,
+				Source:        asset.ID,
+				Destination:   link.Target,
+				Assets:        union(link.DataAssetsSent, link.DataAssetsReceived),
+				Tags:          link.Tags,
+				Attributes:    attributes,
+			})
+		}
+	}
+
+	if what.internetUsed {
+		what.document.TrustZones = append(what.document.TrustZones, TrustZone{ID: internetTrustZoneId, Name: "Internet", Risk: TrustZoneRisk{TrustRating: internetTrustRating}})
+	}
+	if what.outsideUsed {
+		what.document.TrustZones = append(what.document.TrustZones, TrustZone{ID: outsideTrustZoneId, Name: "Outside Trust Boundaries", Risk: TrustZoneRisk{TrustRating: outsideTrustRating}})
+	}
+	return nil
+}
+
+// trustZoneOf is the trust boundary of the technical asset or, outside of trust boundaries, the internet or the zone
+// of the remaining technical assets
+func (what *otmExporter) trustZoneOf(asset input.TechnicalAsset) string {
+	if boundaryId, inside := what.boundaryOfAsset[asset.ID]; inside {
+		return boundaryId
+	}
+	if asset.Internet {
+		what.internetUsed = true
+		return internetTrustZoneId
+	}
+	what.outsideUsed = true
+	return outsideTrustZoneId
+}
+
+// exportThreats converts the custom risk categories into threats, identified at the components and dataflows of the
+// technical assets and communication links most relevant to their risks
+func (what *otmExporter) exportThreats() error {
+	components := make(map[string]*Component)
+	for i := range what.document.Components {
+		components[what.document.Components[i].ID] = &what.document.Components[i]
+	}
+	dataflows := make(map[string]*Dataflow)
+	for i := range what.document.Dataflows {
+		dataflows[what.document.Dataflows[i].ID] = &what.document.Dataflows[i]
+	}
+
+	for _, category := range what.model.CustomRiskCategories {
+		attributes, err := threagileAttributes(category)
+		if err != nil {
+			return err
+		}
+		threat := Threat{ID: category.ID, Name: category.Title, Description: category.Description, Attributes: attributes}
+		if len(category.STRIDE) > 0 {
+			threat.Categories = []string{category.STRIDE}
+		}
+		if category.CWE > 0 {
+			threat.CWEs = []string{"CWE-" + strconv.Itoa(category.CWE)}
+		}
+
+		instance := ThreatInstance{Threat: category.ID, State: threatStateExposed}
+		if len(category.Mitigation) > 0 {
+			mitigationId := category.ID + "-mitigation"
+			what.document.Mitigations = append(what.document.Mitigations, Mitigation{ID: mitigationId, Name: "Mitigation of " + category.Title, Description: category.Mitigation, RiskReduction: defaultRiskReduction})
+			instance.Mitigations = []MitigationInstance{{Mitigation: mitigationId, State: mitigationStateRequired}}
+		}
+
+		for _, riskTitle := range sortedTitles(category.RisksIdentified) {
+			risk := category.RisksIdentified[riskTitle]
+			threat.Risk.Likelihood = maxInt(threat.Risk.Likelihood, enumRating(risk.ExploitationLikelihood, types.RiskExploitationLikelihoodValues()))
+			threat.Risk.Impact = maxInt(threat.Risk.Impact, enumRating(risk.ExploitationImpact, types.RiskExploitationImpactValues()))
+			if component, ok := components[risk.MostRelevantTechnicalAsset]; ok && !identified(component.Threats, category.ID) {
+				component.Threats = append(component.Threats, instance)
+			}
+			if dataflow, ok := dataflows[risk.MostRelevantCommunicationLink]; ok && !identified(dataflow.Threats, category.ID) {
+				dataflow.Threats = append(dataflow.Threats, instance)
+			}
+		}
+		what.document.Threats = append(what.document.Threats, threat)
+	}
+	return nil
+}
+
+// threagileAttributes keeps the element in the attribute threagile
+func threagileAttributes(element any) (Attributes, error) {
+	data, err := json.Marshal(element)
+	if err != nil {
+		return nil, fmt.Errorf("unable to convert %T: %w", element, err)
+	}
+	return Attributes{threagileAttribute: data}, nil
+}
+
+// enumRating is the rating of the enum value of the model, the lowest one for unknown values
+func enumRating(value string, values []types.TypeEnum) int {
+	for _, candidate := range values {
+		if candidate.String() == value {
+			return rating(candidate, values)
+		}
+	}
+	return 0
+}
+
+func identified(instances []ThreatInstance, threatId string) bool {
+	for _, instance := range instances {
+		if instance.Threat == threatId {
+			return true
+		}
+	}
+	return false
+}
+
+func union(first []string, second []string) []string {
+	result := append(make([]string, 0, len(first)+len(second)), first...)
+	for _, value := range second {
+		if !contains(result, value) {
+			result = append(result, value)
+		}
+	}
+	return result
+}
+
+func contains(values []string, value string) bool {
+	for _, candidate := range values {
+		if candidate == value {
+			return true
+		}
+	}
+	return false
+}
+
+func maxInt(a int, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+func sortedTitles[T any](elements map[string]T) []string {
+	titles := make([]string, 0, len(elements))
+	for title := range elements {
+		titles = append(titles, title)
+	}
+	sort.Strings(titles)
+	return titles
+}
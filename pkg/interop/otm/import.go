@@ -0,0 +1,492 @@
+package otm
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/threagile/threagile/pkg/docs"
+	"github.com/threagile/threagile/pkg/input"
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+// Import converts the OTM document into a model, returning the notes about the elements not taken over (or only
+// partially), the elements exported by Threagile get their properties back from the attribute threagile
+func Import(reader io.Reader, filename string) (*input.Model, []string, error) {
+	var document OTM
+	err := json.NewDecoder(reader).Decode(&document)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to parse OTM file: %w", err)
+	}
+	if len(document.OTMVersion) == 0 {
+		return nil, nil, fmt.Errorf("unable to parse OTM file: otmVersion missing")
+	}
+
+	importer := &otmImporter{
+		document:      &document,
+		ids:           make(map[string]bool),
+		dataAssetIds:  make(map[string]string),
+		boundaryIds:   make(map[string]string),
+		internetZones: make(map[string]bool),
+		assetTitles:   make(map[string]string),
+		dataflowLinks: make(map[string]dataflowLink),
+		notes:         make([]string, 0),
+	}
+	importer.technologies = make(types.TechnologyMap)
+	err = importer.technologies.LoadDefault()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	steps := []func(filename string) error{importer.importProject, importer.importAssets, importer.importTrustZones, importer.importComponents, importer.importDataflows, importer.importThreats}
+	for _, step := range steps {
+		err = step(filename)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	importer.model.SeedTagsAvailable()
+	return importer.model, importer.notes, nil
+}
+
+type otmImporter struct {
+	document      *OTM
+	model         *input.Model
+	technologies  types.TechnologyMap
+	ids           map[string]bool   // taken by the elements of the model
+	dataAssetIds  map[string]string // by asset id
+	boundaryIds   map[string]string // by trust zone id
+	internetZones map[string]bool   // trust zones taken as the internet, like the ones rated untrusted
+	assetTitles   map[string]string // technical asset titles by component id
+	dataflowLinks map[string]dataflowLink
+	notes         []string
+}
+
+// dataflowLink is the communication link a dataflow became
+type dataflowLink struct {
+	sourceTitle string
+	linkTitle   string
+}
+
+func (what *otmImporter) importProject(filename string) error {
+	project := what.document.Project
+	what.model = new(input.Model).Defaults()
+	found, err := fromThreagileAttributes(project.Attributes, what.model)
+	if err != nil {
+		return err
+	}
+	if !found {
+		what.model.ThreagileVersion = docs.ThreagileVersion
+		what.model.Date = time.Now().Format("2006-01-02")
+		what.model.BusinessCriticality = types.Important.String()
+		what.model.AppDescription = input.Overview{Description: "Imported from OTM file " + filepath.Base(filename)}
+		what.model.TagsAvailable = project.Tags
+	}
+	what.model.Title = project.Name
+	if len(what.model.Title) == 0 {
+		what.model.Title = strings.TrimSuffix(filepath.Base(filename), filepath.Ext(filename))
+	}
+	if len(project.Description) > 0 {
+		what.model.BusinessOverview.Description = project.Description
+	}
+	if len(project.Owner) > 0 {
+		what.model.Author.Name = project.Owner
+	}
+	if len(project.OwnerContact) > 0 {
+		what.model.Author.Contact = project.OwnerContact
+	}
+	what.model.DataAssets = make(map[string]input.DataAsset)
+	what.model.TechnicalAssets = make(map[string]input.TechnicalAsset)
+	what.model.TrustBoundaries = make(map[string]input.TrustBoundary)
+	what.model.CustomRiskCategories = make(input.RiskCategories, 0)
+	return nil
+}
+
+func (what *otmImporter) importAssets(_ string) error {
+	titles := make(map[string]bool)
+	for _, asset := range what.document.Assets {
+		var dataAsset input.DataAsset
+		found, err := fromThreagileAttributes(asset.Attributes, &dataAsset)
+		if err != nil {
+			return err
+		}
+		if !found {
+			dataAsset = input.DataAsset{
+				Usage:                  types.Business.String(),
+				Quantity:               types.Many.String(),
+				JustificationCiaRating: "TODO: rate the data asset",
+			}
+		}
+		dataAsset.ID = what.newId(asset.ID, asset.Name)
+		dataAsset.Description = asset.Description
+		dataAsset.Confidentiality = enumOfRating(asset.Risk.Confidentiality, types.ConfidentialityValues()).String()
+		dataAsset.Integrity = enumOfRating(asset.Risk.Integrity, types.CriticalityValues()).String()
+		dataAsset.Availability = enumOfRating(asset.Risk.Availability, types.CriticalityValues()).String()
+		if len(asset.Risk.Comment) > 0 {
+			dataAsset.JustificationCiaRating = asset.Risk.Comment
+		}
+		what.dataAssetIds[asset.ID] = dataAsset.ID
+		what.model.DataAssets[uniqueTitle(titles, asset.Name, dataAsset.ID)] = dataAsset
+	}
+	return nil
+}
+
+func (what *otmImporter) importTrustZones(_ string) error {
+	titles := make(map[string]bool)
+	nested := make(map[string]string)
+	for _, trustZone := range what.document.TrustZones {
+		if trustZone.ID == outsideTrustZoneId {
+			continue
+		}
+		var boundary input.TrustBoundary
+		found, err := fromThreagileAttributes(trustZone.Attributes, &boundary)
+		if err != nil {
+			return err
+		}
+		if !found {
+			boundary.Type = types.NetworkOnPrem.String()
+		}
+		if trustZone.ID == internetTrustZoneId || (!found && trustZone.Risk.TrustRating == internetTrustRating) {
+			what.internetZones[trustZone.ID] = true
+			continue
+		}
+		boundary.ID = what.newId(trustZone.ID, trustZone.Name)
+		boundary.Description = trustZone.Description
+		if _, err := types.ParseTrustBoundary(trustZone.Type); err == nil {
+			boundary.Type = trustZone.Type
+		} else if !found && len(trustZone.Type) > 0 {
+			what.notes = append(what.notes, fmt.Sprintf("trust zone %q: type %q taken as %v", trustZone.Name, trustZone.Type, boundary.Type))
+		}
+		what.boundaryIds[trustZone.ID] = boundary.ID
+		if trustZone.Parent != nil && len(trustZone.Parent.TrustZone) > 0 {
+			nested[boundary.ID] = trustZone.Parent.TrustZone
+		}
+		what.model.TrustBoundaries[uniqueTitle(titles, trustZone.Name, boundary.ID)] = boundary
+	}
+
+	for title, boundary := range what.model.TrustBoundaries {
+		for nestedId, parentZoneId := range nested {
+			if what.boundaryIds[parentZoneId] == boundary.ID {
+				boundary.TrustBoundariesNested = append(boundary.TrustBoundariesNested, nestedId)
+			}
+		}
+		sort.Strings(boundary.TrustBoundariesNested)
+		what.model.TrustBoundaries[title] = boundary
+	}
+	return nil
+}
+
+func (what *otmImporter) importComponents(_ string) error {
+	titles := make(map[string]bool)
+	parents := make(map[string]Parent)
+	for _, component := range what.document.Components {
+		parents[component.ID] = component.Parent
+	}
+	for _, component := range what.document.Components {
+		zoneId := trustZoneOf(component.ID, parents)
+		internet := what.internetZones[zoneId]
+		assetType := types.Process
+		if internet {
+			assetType = types.ExternalEntity
+		}
+		var asset input.TechnicalAsset
+		found, err := fromThreagileAttributes(component.Attributes, &asset)
+		if err != nil {
+			return err
+		}
+		if !found {
+			asset = newTechnicalAsset(assetType)
+		}
+		asset.ID = what.newId(component.ID, component.Name)
+		asset.Description = component.Description
+		asset.Tags = component.Tags
+		asset.Internet = internet
+		what.takeOverTechnology(&asset, component, found)
+
+		asset.DataAssetsProcessed, asset.DataAssetsStored = nil, nil
+		if component.Assets != nil {
+			asset.DataAssetsProcessed = what.dataAssetsOf(component.Assets.Processed, "component "+component.Name)
+			asset.DataAssetsStored = what.dataAssetsOf(component.Assets.Stored, "component "+component.Name)
+		}
+		asset.CommunicationLinks = make(map[string]input.CommunicationLink)
+
+		title := uniqueTitle(titles, component.Name, asset.ID)
+		what.assetTitles[component.ID] = title
+		what.model.TechnicalAssets[title] = asset
+		if boundaryId, inBoundary := what.boundaryIds[zoneId]; inBoundary {
+			for boundaryTitle, boundary := range what.model.TrustBoundaries {
+				if boundary.ID == boundaryId {
+					boundary.TechnicalAssetsInside = append(boundary.TechnicalAssetsInside, asset.ID)
+					what.model.TrustBoundaries[boundaryTitle] = boundary
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func newTechnicalAsset(assetType types.TechnicalAssetType) input.TechnicalAsset {
+	return input.TechnicalAsset{
+		Type:                   assetType.String(),
+		Usage:                  types.Business.String(),
+		Size:                   types.Service.String(),
+		Technology:             types.UnknownTechnology,
+		Machine:                types.Virtual.String(),
+		Encryption:             types.NoneEncryption.String(),
+		Confidentiality:        types.Internal.String(),
+		Integrity:              types.Operational.String(),
+		Availability:           types.Operational.String(),
+		JustificationCiaRating: "TODO: rate the technical asset",
+		CustomDevelopedParts:   assetType != types.ExternalEntity,
+	}
+}
+
+// takeOverTechnology sets the type of the component as technology, when it is a known one (and differs from the one
+// exported for components exported by Threagile)
+func (what *otmImporter) takeOverTechnology(asset *input.TechnicalAsset, component Component, exported bool) {
+	if len(component.Type) == 0 {
+		return
+	}
+	exportedTechnology := asset.Technology
+	if len(exportedTechnology) == 0 && len(asset.Technologies) > 0 {
+		exportedTechnology = asset.Technologies[0]
+	}
+	if exported && component.Type == exportedTechnology {
+		return
+	}
+	if what.technologies.Get(component.Type) == nil {
+		what.notes = append(what.notes, fmt.Sprintf("component %q: unknown technology %q, please set the technology", component.Name, component.Type))
+		return
+	}
+	asset.Technology = component.Type
+	asset.Technologies = nil
+}
+
+// trustZoneOf is the trust zone of the component, the one of the enclosing component for nested components
+func trustZoneOf(componentId string, parents map[string]Parent) string {
+	visited := make(map[string]bool)
+	for !visited[componentId] {
+		visited[componentId] = true
+		parent := parents[componentId]
+		if len(parent.TrustZone) > 0 || len(parent.Component) == 0 {
+			return parent.TrustZone
+		}
+		componentId = parent.Component
+	}
+	return ""
+}
+
+func (what *otmImporter) importDataflows(_ string) error {
+	titles := make(map[string]map[string]bool)
+	for _, dataflow := range what.document.Dataflows {
+		sourceTitle, sourceFound := what.assetTitles[dataflow.Source]
+		targetTitle, targetFound := what.assetTitles[dataflow.Destination]
+		if !sourceFound || !targetFound {
+			what.notes = append(what.notes, fmt.Sprintf("dataflow %q skipped: source and destination have to be components", dataflow.Name))
+			continue
+		}
+		var link input.CommunicationLink
+		found, err := fromThreagileAttributes(dataflow.Attributes, &link)
+		if err != nil {
+			return err
+		}
+		if !found {
+			link = input.CommunicationLink{
+				Protocol:       types.UnknownProtocol.String(),
+				Authentication: types.NoneAuthentication.String(),
+				Authorization:  types.NoneAuthorization.String(),
+				Usage:          types.Business.String(),
+			}
+		}
+		link.Target = what.model.TechnicalAssets[targetTitle].ID
+		link.Description = dataflow.Description
+		link.Tags = dataflow.Tags
+		dataAssets := what.dataAssetsOf(dataflow.Assets, "dataflow "+dataflow.Name)
+		if !found || !sameElements(union(link.DataAssetsSent, link.DataAssetsReceived), dataAssets) {
+			link.DataAssetsSent, link.DataAssetsReceived = dataAssets, nil
+			if dataflow.Bidirectional {
+				link.DataAssetsReceived = dataAssets
+			}
+		}
+
+		source := what.model.TechnicalAssets[sourceTitle]
+		if titles[source.ID] == nil {
+			titles[source.ID] = make(map[string]bool)
+		}
+		linkTitle := uniqueTitle(titles[source.ID], dataflow.Name, link.Target)
+		source.CommunicationLinks[linkTitle] = link
+		what.dataflowLinks[dataflow.ID] = dataflowLink{sourceTitle: sourceTitle, linkTitle: linkTitle}
+	}
+	return nil
+}
+
+// importThreats converts the threats into custom risk categories with a risk for each component or dataflow they are
+// identified at, the threats exported by Threagile keep their risks
+func (what *otmImporter) importThreats(_ string) error {
+	mitigations := make(map[string]Mitigation)
+	for _, mitigation := range what.document.Mitigations {
+		mitigations[mitigation.ID] = mitigation
+	}
+	for _, threat := range what.document.Threats {
+		category := new(input.RiskCategory)
+		found, err := fromThreagileAttributes(threat.Attributes, category)
+		if err != nil {
+			return err
+		}
+		if !found {
+			category.Function = types.Architecture.String()
+		}
+		category.ID = what.newId(threat.ID, threat.Name)
+		category.Title = threat.Name
+		category.Description = threat.Description
+		if !found {
+			category.STRIDE = what.strideOf(threat)
+			for _, cwe := range threat.CWEs {
+				if number, err := strconv.Atoi(strings.TrimPrefix(strings.ToUpper(cwe), "CWE-")); err == nil {
+					category.CWE = number
+					break
+				}
+			}
+			what.addRisks(category, threat, mitigations)
+		}
+		what.model.CustomRiskCategories = append(what.model.CustomRiskCategories, category)
+	}
+	return nil
+}
+
+func (what *otmImporter) strideOf(threat Threat) string {
+	for _, threatCategory := range threat.Categories {
+		if stride, err := types.ParseSTRIDE(types.MakeID(threatCategory)); err == nil {
+			return stride.String()
+		}
+	}
+	what.notes = append(what.notes, fmt.Sprintf("threat %q: no STRIDE category, taken as %v", threat.Name, types.InformationDisclosure))
+	return types.InformationDisclosure.String()
+}
+
+func (what *otmImporter) addRisks(category *input.RiskCategory, threat Threat, mitigations map[string]Mitigation) {
+	likelihood := enumOfRating(threat.Risk.Likelihood, types.RiskExploitationLikelihoodValues()).(types.RiskExploitationLikelihood)
+	impact := enumOfRating(threat.Risk.Impact, types.RiskExploitationImpactValues()).(types.RiskExploitationImpact)
+	newRisk := func() input.RiskIdentified {
+		return input.RiskIdentified{
+			Severity:               types.CalculateSeverity(likelihood, impact).String(),
+			ExploitationLikelihood: likelihood.String(),
+			ExploitationImpact:     impact.String(),
+			DataBreachProbability:  types.Possible.String(),
+		}
+	}
+	mitigationTexts := make([]string, 0)
+	addMitigations := func(instances []MitigationInstance) {
+		for _, instance := range instances {
+			mitigation, exists := mitigations[instance.Mitigation]
+			if exists && !contains(mitigationTexts, mitigation.Name+": "+mitigation.Description) {
+				mitigationTexts = append(mitigationTexts, mitigation.Name+": "+mitigation.Description)
+			}
+		}
+	}
+
+	category.RisksIdentified = make(map[string]input.RiskIdentified)
+	for _, component := range what.document.Components {
+		for _, instance := range component.Threats {
+			if instance.Threat != threat.ID {
+				continue
+			}
+			assetId := what.model.TechnicalAssets[what.assetTitles[component.ID]].ID
+			risk := newRisk()
+			risk.MostRelevantTechnicalAsset = assetId
+			risk.DataBreachTechnicalAssets = []string{assetId}
+			category.RisksIdentified[threat.Name+" at "+component.Name] = risk
+			addMitigations(instance.Mitigations)
+		}
+	}
+	for _, dataflow := range what.document.Dataflows {
+		for _, instance := range dataflow.Threats {
+			link, imported := what.dataflowLinks[dataflow.ID]
+			if instance.Threat != threat.ID || !imported {
+				continue
+			}
+			source := what.model.TechnicalAssets[link.sourceTitle]
+			risk := newRisk()
+			risk.MostRelevantCommunicationLink = source.ID + ">" + types.MakeID(link.linkTitle)
+			risk.DataBreachTechnicalAssets = []string{source.ID, source.CommunicationLinks[link.linkTitle].Target}
+			category.RisksIdentified[threat.Name+" at "+link.linkTitle+" of "+link.sourceTitle] = risk
+			addMitigations(instance.Mitigations)
+		}
+	}
+	category.Mitigation = strings.Join(mitigationTexts, "\n")
+}
+
+func (what *otmImporter) dataAssetsOf(assetIds []string, of string) []string {
+	dataAssets := make([]string, 0)
+	for _, assetId := range assetIds {
+		dataAssetId, exists := what.dataAssetIds[assetId]
+		if !exists {
+			what.notes = append(what.notes, fmt.Sprintf("%v: unknown asset %q skipped", of, assetId))
+			continue
+		}
+		dataAssets = append(dataAssets, dataAssetId)
+	}
+	if len(dataAssets) == 0 {
+		return nil
+	}
+	return dataAssets
+}
+
+// newId is the id of the element (or of its name, if not usable as id), unique across the model
+func (what *otmImporter) newId(id string, name string) string {
+	result := types.MakeID(id)
+	if len(result) == 0 {
+		result = types.MakeID(name)
+	}
+	if len(result) == 0 {
+		result = "element"
+	}
+	return uniqueName(what.ids, result, "-")
+}
+
+// fromThreagileAttributes reads the element from the attribute threagile, if any
+func fromThreagileAttributes(attributes Attributes, element any) (bool, error) {
+	data, exists := attributes[threagileAttribute]
+	if !exists {
+		return false, nil
+	}
+	err := json.Unmarshal(data, element)
+	if err != nil {
+		return false, fmt.Errorf("invalid attribute %v of %T: %w", threagileAttribute, element, err)
+	}
+	return true, nil
+}
+
+func uniqueTitle(taken map[string]bool, title string, fallback string) string {
+	if len(strings.TrimSpace(title)) == 0 {
+		title = fallback
+	}
+	return uniqueName(taken, title, " ")
+}
+
+// uniqueName is the name or, when already taken, the name with a number suffix (joined by the separator), marked as taken
+func uniqueName(taken map[string]bool, name string, separator string) string {
+	result := name
+	for i := 2; taken[result]; i++ {
+		result = name + separator + strconv.Itoa(i)
+	}
+	taken[result] = true
+	return result
+}
+
+func sameElements(first []string, second []string) bool {
+	if len(first) != len(second) {
+		return false
+	}
+	for _, value := range first {
+		if !contains(second, value) {
+			return false
+		}
+	}
+	return true
+}
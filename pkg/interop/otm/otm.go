@@ -0,0 +1,185 @@
+// Package otm converts models from and to the Open Threat Model (OTM) format, the json interchange format of threat
+// modeling tools like IriusRisk and OWASP Threat Dragon: trust boundaries are trust zones, technical assets components,
+// communication links dataflows, data assets assets and custom risk categories threats with their mitigation.
+//
+// The Threagile properties without counterpart in OTM are kept in the attribute threagile of each element (and of the
+// project for the model itself), so that models survive the round trip through other tools unchanged apart from the
+// elements changed there.
+package otm
+
+import (
+	"encoding/json"
+	"math"
+
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+// Version is the OTM version written
+const Version = "0.2.0"
+
+const (
+	threagileAttribute = "threagile"
+
+	// trust zones of the technical assets outside of trust boundaries, not trust boundaries themselves
+	internetTrustZoneId = "internet"
+	outsideTrustZoneId  = "outside-trust-boundaries"
+
+	internetTrustRating      = 0
+	outsideTrustRating       = 50
+	trustBoundaryTrustRating = 80
+
+	threatStateExposed      = "EXPOSED"
+	mitigationStateRequired = "REQUIRED"
+	defaultRiskReduction    = 50
+)
+
+type OTM struct {
+	OTMVersion  string       `json:"otmVersion"`
+	Project     Project      `json:"project"`
+	Assets      []Asset      `json:"assets,omitempty"`
+	TrustZones  []TrustZone  `json:"trustZones,omitempty"`
+	Components  []Component  `json:"components,omitempty"`
+	Dataflows   []Dataflow   `json:"dataflows,omitempty"`
+	Threats     []Threat     `json:"threats,omitempty"`
+	Mitigations []Mitigation `json:"mitigations,omitempty"`
+}
+
+type Project struct {
+	Name         string     `json:"name"`
+	ID           string     `json:"id"`
+	Description  string     `json:"description,omitempty"`
+	Owner        string     `json:"owner,omitempty"`
+	OwnerContact string     `json:"ownerContact,omitempty"`
+	Tags         []string   `json:"tags,omitempty"`
+	Attributes   Attributes `json:"attributes,omitempty"`
+}
+
+type Asset struct {
+	ID          string     `json:"id"`
+	Name        string     `json:"name"`
+	Description string     `json:"description,omitempty"`
+	Risk        AssetRisk  `json:"risk"`
+	Attributes  Attributes `json:"attributes,omitempty"`
+}
+
+// AssetRisk rates the asset from 0 (not needed) to 100 (of highest need)
+type AssetRisk struct {
+	Confidentiality int    `json:"confidentiality"`
+	Integrity       int    `json:"integrity"`
+	Availability    int    `json:"availability"`
+	Comment         string `json:"comment,omitempty"`
+}
+
+type TrustZone struct {
+	ID          string        `json:"id"`
+	Name        string        `json:"name"`
+	Type        string        `json:"type,omitempty"`
+	Description string        `json:"description,omitempty"`
+	Risk        TrustZoneRisk `json:"risk"`
+	Parent      *Parent       `json:"parent,omitempty"`
+	Attributes  Attributes    `json:"attributes,omitempty"`
+}
+
+// TrustZoneRisk rates the trust in the zone from 0 (untrusted, like the internet) to 100 (fully trusted)
+type TrustZoneRisk struct {
+	TrustRating int `json:"trustRating"`
+}
+
+// Parent is either a trust zone or a component
+type Parent struct {
+	TrustZone string `json:"trustZone,omitempty"`
+	Component string `json:"component,omitempty"`
+}
+
+type Component struct {
+	ID          string           `json:"id"`
+	Name        string           `json:"name"`
+	Type        string           `json:"type,omitempty"`
+	Description string           `json:"description,omitempty"`
+	Parent      Parent           `json:"parent"`
+	Tags        []string         `json:"tags,omitempty"`
+	Assets      *ComponentAssets `json:"assets,omitempty"`
+	Threats     []ThreatInstance `json:"threats,omitempty"`
+	Attributes  Attributes       `json:"attributes,omitempty"`
+}
+
+type ComponentAssets struct {
+	Processed []string `json:"processed,omitempty"`
+	Stored    []string `json:"stored,omitempty"`
+}
+
+type Dataflow struct {
+	ID            string           `json:"id"`
+	Name          string           `json:"name"`
+	Description   string           `json:"description,omitempty"`
+	Bidirectional bool             `json:"bidirectional,omitempty"`
+	Source        string           `json:"source"`
+	Destination   string           `json:"destination"`
+	Assets        []string         `json:"assets,omitempty"`
+	Tags          []string         `json:"tags,omitempty"`
+	Threats       []ThreatInstance `json:"threats,omitempty"`
+	Attributes    Attributes       `json:"attributes,omitempty"`
+}
+
+// ThreatInstance is a threat identified at a component or dataflow, with the state of its mitigations
+type ThreatInstance struct {
+	Threat      string               `json:"threat"`
+	State       string               `json:"state"`
+	Mitigations []MitigationInstance `json:"mitigations,omitempty"`
+}
+
+type MitigationInstance struct {
+	Mitigation string `json:"mitigation"`
+	State      string `json:"state"`
+}
+
+type Threat struct {
+	ID          string     `json:"id"`
+	Name        string     `json:"name"`
+	Description string     `json:"description,omitempty"`
+	Categories  []string   `json:"categories,omitempty"`
+	CWEs        []string   `json:"cwes,omitempty"`
+	Risk        ThreatRisk `json:"risk"`
+	Attributes  Attributes `json:"attributes,omitempty"`
+}
+
+// ThreatRisk rates the likelihood and impact from 0 to 100
+type ThreatRisk struct {
+	Likelihood        int    `json:"likelihood"`
+	LikelihoodComment string `json:"likelihoodComment,omitempty"`
+	Impact            int    `json:"impact"`
+	ImpactComment     string `json:"impactComment,omitempty"`
+}
+
+type Mitigation struct {
+	ID            string     `json:"id"`
+	Name          string     `json:"name"`
+	Description   string     `json:"description,omitempty"`
+	RiskReduction int        `json:"riskReduction"`
+	Attributes    Attributes `json:"attributes,omitempty"`
+}
+
+// Attributes are the tool specific properties of an element
+type Attributes map[string]json.RawMessage
+
+// rating maps the enum value onto the OTM scale from 0 to 100
+func rating(value types.TypeEnum, values []types.TypeEnum) int {
+	for i, candidate := range values {
+		if candidate == value {
+			return int(math.Round(float64(i) * 100 / float64(len(values)-1)))
+		}
+	}
+	return 0
+}
+
+// enumOfRating maps the rating of the OTM scale from 0 to 100 onto the nearest enum value
+func enumOfRating(rating int, values []types.TypeEnum) types.TypeEnum {
+	index := int(math.Round(float64(rating) * float64(len(values)-1) / 100))
+	if index < 0 {
+		return values[0]
+	}
+	if index >= len(values) {
+		return values[len(values)-1]
+	}
+	return values[index]
+}
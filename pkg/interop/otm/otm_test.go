@@ -0,0 +1,196 @@
+package otm
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
+
+	"github.com/threagile/threagile/pkg/input"
+)
+
+const otmTestModel = `threagile_version: 1.0.0
+title: Web Shop
+author:
+  name: Security Team
+business_overview:
+  description: Selling things online
+business_criticality: important
+tags_available: [aws]
+data_assets:
+  Customer Data:
+    id: customer-data
+    usage: business
+    quantity: many
+    confidentiality: confidential
+    integrity: critical
+    availability: operational
+    justification_cia_rating: personal data
+technical_assets:
+  Customer:
+    id: customer
+    type: external-entity
+    technology: browser
+    internet: true
+    communication_links:
+      Shop Traffic:
+        target: shop
+        protocol: https
+        authentication: session-id
+        authorization: end-user-identity-propagation
+        usage: business
+        data_assets_sent: [customer-data]
+        data_assets_received: [customer-data]
+  Shop:
+    id: shop
+    type: process
+    technology: web-server
+    tags: [aws]
+    data_assets_processed: [customer-data]
+  Database:
+    id: database
+    type: datastore
+    technology: database
+    data_assets_stored: [customer-data]
+  Admin Laptop:
+    id: admin-laptop
+    type: external-entity
+    technology: desktop
+trust_boundaries:
+  Cloud:
+    id: cloud
+    type: network-cloud-provider
+    trust_boundaries_nested: [db-subnet]
+    technical_assets_inside: [shop]
+  DB Subnet:
+    id: db-subnet
+    type: network-virtual-lan
+    technical_assets_inside: [database]
+custom_risk_categories:
+  - id: scraping
+    title: Scraping
+    description: Competitors copy the catalog
+    mitigation: Rate limit the catalog
+    function: business-side
+    stride: information-disclosure
+    cwe: 799
+    risks_identified:
+      Scraping at Shop:
+        severity: medium
+        exploitation_likelihood: likely
+        exploitation_impact: medium
+        data_breach_probability: improbable
+        most_relevant_technical_asset: shop
+`
+
+func TestExportImportRoundTrip(t *testing.T) {
+	modelInput := new(input.Model).Defaults()
+	assert.NoError(t, yaml.Unmarshal([]byte(otmTestModel), modelInput))
+
+	document, err := Export(modelInput)
+	assert.NoError(t, err)
+	assert.Equal(t, Version, document.OTMVersion)
+	assert.Equal(t, "web-shop", document.Project.ID)
+	assert.Equal(t, []string{"cloud", "db-subnet", internetTrustZoneId, outsideTrustZoneId}, trustZoneIds(document.TrustZones))
+	assert.Equal(t, &Parent{TrustZone: "cloud"}, document.TrustZones[1].Parent)
+	assert.Equal(t, AssetRisk{Confidentiality: 75, Integrity: 75, Availability: 25, Comment: "personal data"}, document.Assets[0].Risk)
+	components := make(map[string]Component)
+	for _, component := range document.Components {
+		components[component.ID] = component
+	}
+	assert.Equal(t, internetTrustZoneId, components["customer"].Parent.TrustZone)
+	assert.Equal(t, outsideTrustZoneId, components["admin-laptop"].Parent.TrustZone)
+	assert.Equal(t, "web-server", components["shop"].Type)
+	assert.Equal(t, []ThreatInstance{{Threat: "scraping", State: threatStateExposed, Mitigations: []MitigationInstance{{Mitigation: "scraping-mitigation", State: mitigationStateRequired}}}}, components["shop"].Threats)
+	assert.Equal(t, []Dataflow{{ID: "customer>shop-traffic", Name: "Shop Traffic", Bidirectional: true, Source: "customer", Destination: "shop", Assets: []string{"customer-data"}, Attributes: document.Dataflows[0].Attributes}}, document.Dataflows)
+	assert.Equal(t, []string{"CWE-799"}, document.Threats[0].CWEs)
+
+	data, err := json.Marshal(document)
+	assert.NoError(t, err)
+	imported, notes, err := Import(bytes.NewReader(data), "web-shop.otm.json")
+	assert.NoError(t, err)
+	assert.Empty(t, notes)
+	assert.Equal(t, modelInput.Title, imported.Title)
+	assert.Equal(t, modelInput.Author, imported.Author)
+	assert.Equal(t, modelInput.DataAssets, imported.DataAssets)
+	assert.Equal(t, modelInput.TrustBoundaries, imported.TrustBoundaries)
+	assert.Equal(t, modelInput.CustomRiskCategories, imported.CustomRiskCategories)
+	for title, asset := range modelInput.TechnicalAssets {
+		if asset.CommunicationLinks == nil {
+			asset.CommunicationLinks = make(map[string]input.CommunicationLink)
+		}
+		assert.Equal(t, asset, imported.TechnicalAssets[title], title)
+	}
+}
+
+func TestImportForeignOTM(t *testing.T) {
+	document := `{
+		"otmVersion": "0.2.0",
+		"project": {"name": "Payments", "id": "payments", "owner": "Jo"},
+		"assets": [{"id": "a1", "name": "Card Data", "risk": {"confidentiality": 100, "integrity": 60, "availability": 10}}],
+		"trustZones": [
+			{"id": "f0ba7722", "name": "Internet", "risk": {"trustRating": 0}},
+			{"id": "2ab4effa", "name": "Private Secured", "type": "private-secured", "risk": {"trustRating": 100}}
+		],
+		"components": [
+			{"id": "c1", "name": "Mobile App", "type": "ios-device-client", "parent": {"trustZone": "f0ba7722"}},
+			{"id": "c2", "name": "Payment API", "type": "web-service-rest", "parent": {"trustZone": "2ab4effa"},
+				"assets": {"processed": ["a1", "a2"]},
+				"threats": [{"threat": "t1", "state": "EXPOSED", "mitigations": [{"mitigation": "m1", "state": "REQUIRED"}]}]},
+			{"id": "c3", "name": "Worker", "type": "batch-processing", "parent": {"component": "c2"}}
+		],
+		"dataflows": [
+			{"id": "d1", "name": "Pay", "source": "c1", "destination": "c2", "assets": ["a1"]},
+			{"id": "d2", "name": "Sync", "source": "c2", "destination": "2ab4effa"}
+		],
+		"threats": [{"id": "t1", "name": "Card Theft", "categories": ["Information Disclosure"], "cwes": ["CWE-311"], "risk": {"likelihood": 80, "impact": 100}}],
+		"mitigations": [{"id": "m1", "name": "Encryption", "description": "Encrypt the card data", "riskReduction": 80}]
+	}`
+	model, notes, err := Import(strings.NewReader(document), "payments.otm.json")
+	assert.NoError(t, err)
+	assert.Equal(t, "Payments", model.Title)
+	assert.Equal(t, "Jo", model.Author.Name)
+	assert.Equal(t, []string{
+		`trust zone "Private Secured": type "private-secured" taken as network-on-prem`,
+		`component "Mobile App": unknown technology "ios-device-client", please set the technology`,
+		`component Payment API: unknown asset "a2" skipped`,
+		`dataflow "Sync" skipped: source and destination have to be components`,
+	}, notes)
+
+	cardData := model.DataAssets["Card Data"]
+	assert.Equal(t, []string{"strictly-confidential", "important", "archive"}, []string{cardData.Confidentiality, cardData.Integrity, cardData.Availability})
+
+	app := model.TechnicalAssets["Mobile App"]
+	assert.True(t, app.Internet)
+	assert.Equal(t, "external-entity", app.Type)
+	assert.Equal(t, "unknown-technology", app.Technology)
+	assert.Equal(t, []string{"a1"}, app.CommunicationLinks["Pay"].DataAssetsSent)
+	assert.Equal(t, "c2", app.CommunicationLinks["Pay"].Target)
+	api := model.TechnicalAssets["Payment API"]
+	assert.Equal(t, "web-service-rest", api.Technology)
+	assert.Equal(t, []string{"a1"}, api.DataAssetsProcessed)
+	assert.NotContains(t, model.TrustBoundaries, "Internet")
+	assert.Equal(t, []string{"c2", "c3"}, model.TrustBoundaries["Private Secured"].TechnicalAssetsInside, "with the nested component")
+
+	category := model.CustomRiskCategories[0]
+	assert.Equal(t, "information-disclosure", category.STRIDE)
+	assert.Equal(t, 311, category.CWE)
+	assert.Equal(t, "Encryption: Encrypt the card data", category.Mitigation)
+	risk := category.RisksIdentified["Card Theft at Payment API"]
+	assert.Equal(t, "c2", risk.MostRelevantTechnicalAsset)
+	assert.Equal(t, []string{"very-likely", "very-high", "high"}, []string{risk.ExploitationLikelihood, risk.ExploitationImpact, risk.Severity})
+
+	_, _, err = Import(strings.NewReader(`{"project": {}}`), "empty.json")
+	assert.ErrorContains(t, err, "otmVersion missing")
+}
+
+func trustZoneIds(trustZones []TrustZone) []string {
+	ids := make([]string, 0)
+	for _, trustZone := range trustZones {
+		ids = append(ids, trustZone.ID)
+	}
+	return ids
+}
@@ -0,0 +1,61 @@
+package lsp
+
+import (
+	"regexp"
+	"strconv"
+
+	"github.com/threagile/threagile/pkg/input"
+	"github.com/threagile/threagile/pkg/model"
+)
+
+const diagnosticSource = "threagile"
+
+// yamlErrorLinePattern finds the line of yaml syntax errors, which are not located at a field
+var yamlErrorLinePattern = regexp.MustCompile(`line (\d+)`)
+
+// FileDiagnostic is a problem found by the validation located in its model file, for editor extensions and CI
+// annotations not talking to the language server (the positions are zero-based, like the ones of the protocol)
+type FileDiagnostic struct {
+	File     string    `json:"file"`
+	Range    TextRange `json:"range"`
+	Severity string    `json:"severity"`
+	Code     string    `json:"code"`
+	Source   string    `json:"source"`
+	Message  string    `json:"message"`
+	Field    string    `json:"field,omitempty"`
+}
+
+// Diagnostics locates the problems in their model files (read by the reader), problems which can't be located are
+// answered at the first line
+func Diagnostics(problems []model.ValidationProblem, read input.ModelReader) []FileDiagnostic {
+	documents := make(map[string]*document)
+	diagnostics := make([]FileDiagnostic, 0, len(problems))
+	for _, problem := range problems {
+		doc, ok := documents[problem.File]
+		if !ok {
+			text, _ := read(problem.File) // unreadable files are located at the first line
+			doc = newDocument("", problem.File, string(text))
+			documents[problem.File] = doc
+		}
+		diagnostics = append(diagnostics, FileDiagnostic{
+			File:     problem.File,
+			Range:    lineRange(doc.lines, problemLine(doc, problem)),
+			Severity: problem.Severity,
+			Code:     problem.Code,
+			Source:   diagnosticSource,
+			Message:  problem.Message,
+			Field:    problem.Field,
+		})
+	}
+	return diagnostics
+}
+
+// problemLine is the line of the field of the problem, or else the line named by the message of yaml syntax errors
+func problemLine(doc *document, problem model.ValidationProblem) int {
+	line, found := doc.fieldLine(problem.Field)
+	if match := yamlErrorLinePattern.FindStringSubmatch(problem.Message); !found && match != nil {
+		line, _ = strconv.Atoi(match[1])
+		line--
+	}
+	return line
+}
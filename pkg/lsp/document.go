@@ -0,0 +1,253 @@
+package lsp
+
+import (
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/threagile/threagile/pkg/input"
+	"github.com/threagile/threagile/pkg/model"
+)
+
+// sequenceItem stands for the items of a list in the paths of yaml keys
+const sequenceItem = "[]"
+
+const (
+	kindDataAsset         = "data asset"
+	kindTechnicalAsset    = "technical asset"
+	kindCommunicationLink = "communication link"
+	kindTrustBoundary     = "trust boundary"
+	kindSharedRuntime     = "shared runtime"
+	kindSecurityControl   = "security control"
+	kindTag               = "tag"
+	kindAnyElement        = "element"
+)
+
+// references are the fields (by their yaml key) referencing other elements of the model by their id
+var references = map[string]string{
+	"data_assets_processed":            kindDataAsset,
+	"data_assets_stored":               kindDataAsset,
+	"data_assets_sent":                 kindDataAsset,
+	"data_assets_received":             kindDataAsset,
+	"most_relevant_data_asset":         kindDataAsset,
+	"target":                           kindTechnicalAsset,
+	"technical_assets":                 kindTechnicalAsset, // of trust boundaries, security controls and findings
+	"technical_assets_inside":          kindTechnicalAsset,
+	"technical_assets_running":         kindTechnicalAsset,
+	"data_breach_technical_assets":     kindTechnicalAsset,
+	"most_relevant_technical_asset":    kindTechnicalAsset,
+	"most_relevant_communication_link": kindCommunicationLink,
+	"trust_boundaries":                 kindTrustBoundary, // of security controls
+	"trust_boundaries_nested":          kindTrustBoundary,
+	"most_relevant_trust_boundary":     kindTrustBoundary,
+	"most_relevant_shared_runtime":     kindSharedRuntime,
+	"tags":                             kindTag,
+	"element":                          kindAnyElement, // of annotations
+}
+
+// sections are the top-level fields of the model defining elements with their titles as keys
+var sections = map[string]string{
+	"data_assets":       kindDataAsset,
+	"technical_assets":  kindTechnicalAsset,
+	"trust_boundaries":  kindTrustBoundary,
+	"shared_runtimes":   kindSharedRuntime,
+	"security_controls": kindSecurityControl,
+}
+
+var keyPattern = regexp.MustCompile(`^("[^"]*"|'[^']*'|[^\s#'"][^:#]*?)\s*:(\s|$)`)
+
+// document is a model file opened in the editor, its text taking precedence over the file on disk
+type document struct {
+	uri      string
+	filename string
+	text     string
+	lines    []string
+}
+
+func newDocument(uri string, filename string, text string) *document {
+	return &document{uri: uri, filename: filename, text: text, lines: strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n")}
+}
+
+// lineInfo is the structure of a line of yaml, as far as it is needed to locate it without parsing the whole (maybe
+// invalid while editing) document
+type lineInfo struct {
+	indent     int  // of the key, or of the dash of list items
+	item       bool // whether the line starts a list item
+	keyIndent  int  // of the key (or value) after the dash of list items
+	key        string
+	hasKey     bool
+	valueStart int // index of the value after the key (or the dash)
+}
+
+func parseLine(text string) (lineInfo, bool) {
+	content := strings.TrimLeft(text, " ")
+	if len(strings.TrimSpace(content)) == 0 || strings.HasPrefix(content, "#") {
+		return lineInfo{}, false
+	}
+	info := lineInfo{indent: len(text) - len(content)}
+	info.keyIndent, info.valueStart = info.indent, info.indent
+	if content == "-" || strings.HasPrefix(content, "- ") {
+		afterDash := strings.TrimLeft(content[1:], " ")
+		info.item = true
+		info.keyIndent = len(text) - len(afterDash)
+		info.valueStart = info.keyIndent
+		content = afterDash
+	}
+	if match := keyPattern.FindStringSubmatchIndex(content); match != nil {
+		info.key = strings.Trim(content[match[2]:match[3]], `"'`)
+		info.hasKey = true
+		info.valueStart = info.keyIndent + match[1]
+	}
+	return info, true
+}
+
+// path answers the yaml keys leading to the line (with sequenceItem for list items), by the indentation of the lines
+// before it
+func (what *document) path(line int, info lineInfo) []string {
+	path := make([]string, 0)
+	if info.item {
+		path = append(path, sequenceItem)
+	}
+	threshold := info.indent
+	for index := line - 1; index >= 0 && threshold > 0; index-- {
+		parent, ok := parseLine(what.lines[index])
+		if !ok || parent.indent >= threshold {
+			continue
+		}
+		if parent.hasKey && parent.keyIndent < threshold {
+			path = append([]string{parent.key}, path...)
+		}
+		if parent.item {
+			path = append([]string{sequenceItem}, path...)
+		}
+		threshold = parent.indent
+	}
+	return path
+}
+
+// referencedKind is the kind of elements referenced by the value at the path, if any
+func referencedKind(path []string) (string, bool) {
+	for len(path) > 0 && path[len(path)-1] == sequenceItem {
+		path = path[:len(path)-1]
+	}
+	if len(path) < 2 { // the top-level fields do not reference elements
+		return "", false
+	}
+	kind, ok := references[path[len(path)-1]]
+	return kind, ok
+}
+
+// wordAt answers the value (like an id) under the cursor and where it starts and ends
+func wordAt(text string, index int) (string, int, int) {
+	isWord := func(c byte) bool {
+		return !strings.ContainsRune(" \t,[]{}'\"#:", rune(c))
+	}
+	start, end := index, index
+	for start > 0 && isWord(text[start-1]) {
+		start--
+	}
+	for end < len(text) && isWord(text[end]) {
+		end++
+	}
+	return text[start:end], start, end
+}
+
+// definition is where an element of the model is defined, to complete and jump to references of it
+type definition struct {
+	kind     string
+	id       string
+	title    string
+	filename string
+	line     int
+	start    int // of the id in the line, counted in UTF-16 code units
+	end      int
+}
+
+// collectDefinitions answers the elements defined in the model file and its includes (read by the reader)
+func collectDefinitions(filename string, read input.ModelReader, visited map[string]bool) []definition {
+	if visited[filename] {
+		return nil
+	}
+	visited[filename] = true
+	data, err := read(filename)
+	if err != nil {
+		return nil
+	}
+	var root yaml.Node
+	if yaml.Unmarshal(data, &root) != nil || len(root.Content) == 0 || root.Content[0].Kind != yaml.MappingNode {
+		return nil
+	}
+
+	definitions := make([]definition, 0)
+	lines := strings.Split(string(data), "\n")
+	add := func(kind string, id string, title string, node *yaml.Node) {
+		start, end := 0, 0
+		if node.Line-1 < len(lines) {
+			text := lines[node.Line-1]
+			index := runeIndex(text, node.Column-1)
+			if index < len(text) && (text[index] == '"' || text[index] == '\'') {
+				index++
+			}
+			start, end = characterOffset(text, index), characterOffset(text, index+len(id))
+		}
+		definitions = append(definitions, definition{kind: kind, id: id, title: title, filename: filename, line: node.Line - 1, start: start, end: end})
+	}
+	top := root.Content[0].Content
+	for i := 0; i+1 < len(top); i += 2 {
+		key, value := top[i].Value, top[i+1]
+		switch {
+		case key == "includes" && value.Kind == yaml.SequenceNode:
+			for _, include := range value.Content {
+				definitions = append(definitions, collectDefinitions(filepath.Join(filepath.Dir(filename), include.Value), read, visited)...)
+			}
+
+		case key == "tags_available" && value.Kind == yaml.SequenceNode:
+			for _, tag := range value.Content {
+				add(kindTag, tag.Value, tag.Value, tag)
+			}
+
+		case len(sections[key]) > 0 && value.Kind == yaml.MappingNode:
+			for j := 0; j+1 < len(value.Content); j += 2 {
+				title, element := value.Content[j], value.Content[j+1]
+				id := mappingValue(element, "id")
+				if id == nil {
+					continue
+				}
+				add(sections[key], id.Value, title.Value, id)
+				if links := mappingValue(element, "communication_links"); sections[key] == kindTechnicalAsset && links != nil && links.Kind == yaml.MappingNode {
+					for k := 0; k+1 < len(links.Content); k += 2 {
+						linkID, idError := model.CreateDataFlowId(id.Value, links.Content[k].Value)
+						if idError == nil {
+							add(kindCommunicationLink, linkID, links.Content[k].Value, links.Content[k])
+						}
+					}
+				}
+			}
+		}
+	}
+	return definitions
+}
+
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	if node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// fieldLine locates the field of a validation problem (like "technical_assets.Some Component.communication_links.Some
+// Traffic.protocol", with titles possibly containing dots) in the document, as far as it can be followed
+func (what *document) fieldLine(field string) (int, bool) {
+	line, followed := input.LocateField([]byte(what.text), field)
+	if followed == 0 {
+		return 0, false
+	}
+	return line - 1, true
+}
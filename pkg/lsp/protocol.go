@@ -0,0 +1,224 @@
+package lsp
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// the subset of the Language Server Protocol (https://microsoft.github.io/language-server-protocol/) used by the server
+
+const (
+	errorCodeParseError     = -32700
+	errorCodeMethodNotFound = -32601
+	errorCodeInvalidParams  = -32602
+)
+
+const (
+	severityError   = 1
+	severityWarning = 2
+)
+
+const (
+	messageTypeError   = 1
+	messageTypeWarning = 2
+	messageTypeInfo    = 3
+)
+
+const (
+	completionItemKindProperty  = 10
+	completionItemKindValue     = 12
+	completionItemKindReference = 18
+)
+
+type message struct {
+	JSONRPC string           `json:"jsonrpc"`
+	ID      *json.RawMessage `json:"id,omitempty"` // missing for notifications
+	Method  string           `json:"method,omitempty"`
+	Params  json.RawMessage  `json:"params,omitempty"`
+	Result  any              `json:"result,omitempty"`
+	Error   *responseError   `json:"error,omitempty"`
+}
+
+type responseError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// Position is a zero-based position in a text document, like the ones of the protocol
+type Position struct {
+	Line      int `json:"line"`
+	Character int `json:"character"` // in UTF-16 code units
+}
+
+// TextRange is the range of text between two positions in a text document
+type TextRange struct {
+	Start Position `json:"start"`
+	End   Position `json:"end"`
+}
+
+type location struct {
+	URI   string    `json:"uri"`
+	Range TextRange `json:"range"`
+}
+
+type diagnostic struct {
+	Range    TextRange `json:"range"`
+	Severity int       `json:"severity"`
+	Code     string    `json:"code,omitempty"`
+	Source   string    `json:"source"`
+	Message  string    `json:"message"`
+}
+
+type textDocumentItem struct {
+	URI  string `json:"uri"`
+	Text string `json:"text"`
+}
+
+type textDocumentPositionParams struct {
+	TextDocument struct {
+		URI string `json:"uri"`
+	} `json:"textDocument"`
+	Position Position `json:"position"`
+}
+
+type didOpenParams struct {
+	TextDocument textDocumentItem `json:"textDocument"`
+}
+
+type didChangeParams struct {
+	TextDocument struct {
+		URI string `json:"uri"`
+	} `json:"textDocument"`
+	ContentChanges []struct {
+		Text string `json:"text"` // the whole document, as only full synchronization is offered
+	} `json:"contentChanges"`
+}
+
+type textDocumentParams struct {
+	TextDocument struct {
+		URI string `json:"uri"`
+	} `json:"textDocument"`
+}
+
+type completionItem struct {
+	Label         string         `json:"label"`
+	Kind          int            `json:"kind"`
+	Detail        string         `json:"detail,omitempty"`
+	Documentation *markupContent `json:"documentation,omitempty"`
+	InsertText    string         `json:"insertText,omitempty"`
+}
+
+type markupContent struct {
+	Kind  string `json:"kind"`
+	Value string `json:"value"`
+}
+
+type hover struct {
+	Contents markupContent `json:"contents"`
+}
+
+// connection reads and writes the JSON-RPC messages framed by Content-Length headers
+type connection struct {
+	reader *textproto.Reader
+	writer io.Writer
+	lock   sync.Mutex
+}
+
+func newConnection(in io.Reader, out io.Writer) *connection {
+	return &connection{reader: textproto.NewReader(bufio.NewReader(in)), writer: out}
+}
+
+func (what *connection) read() (*message, error) {
+	header, err := what.reader.ReadMIMEHeader()
+	if err != nil {
+		return nil, err
+	}
+	length, err := strconv.Atoi(header.Get("Content-Length"))
+	if err != nil || length < 0 {
+		return nil, fmt.Errorf("invalid Content-Length header: %q", header.Get("Content-Length"))
+	}
+	data := make([]byte, length)
+	_, err = io.ReadFull(what.reader.R, data)
+	if err != nil {
+		return nil, err
+	}
+	msg := new(message)
+	err = json.Unmarshal(data, msg)
+	if err != nil {
+		return nil, &responseError{Code: errorCodeParseError, Message: err.Error()}
+	}
+	return msg, nil
+}
+
+func (what *connection) write(msg *message) error {
+	msg.JSONRPC = "2.0"
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	what.lock.Lock()
+	defer what.lock.Unlock()
+	_, err = fmt.Fprintf(what.writer, "Content-Length: %d\r\n\r\n%s", len(data), data)
+	return err
+}
+
+func (what *responseError) Error() string {
+	return what.Message
+}
+
+// byteOffset is the index into the line of the character position counted in UTF-16 code units
+func byteOffset(line string, character int) int {
+	units := 0
+	for index, r := range line {
+		if units >= character {
+			return index
+		}
+		units++
+		if r >= 0x10000 {
+			units++
+		}
+	}
+	return len(line)
+}
+
+// runeIndex is the index into the line of the character position counted in characters, like yaml counts its columns
+func runeIndex(line string, column int) int {
+	count := 0
+	for index := range line {
+		if count == column {
+			return index
+		}
+		count++
+	}
+	return len(line)
+}
+
+// characterOffset is the position counted in UTF-16 code units of the index into the line
+func characterOffset(line string, index int) int {
+	if index > len(line) {
+		index = len(line)
+	}
+	units := 0
+	for _, r := range line[:index] {
+		units++
+		if r >= 0x10000 {
+			units++
+		}
+	}
+	return units
+}
+
+func lineRange(lines []string, line int) TextRange {
+	if line < 0 || line >= len(lines) {
+		return TextRange{}
+	}
+	text := lines[line]
+	start := len(text) - len(strings.TrimLeft(text, " "))
+	return TextRange{Start: Position{Line: line, Character: characterOffset(text, start)}, End: Position{Line: line, Character: characterOffset(text, len(text))}}
+}
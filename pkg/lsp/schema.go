@@ -0,0 +1,117 @@
+package lsp
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// schema is the part of the JSON schema of the model files (support/schema.json) offering completion and hover docs
+type schema struct {
+	Description          string             `json:"description"`
+	Type                 any                `json:"type"` // a type or a list of types
+	Enum                 []any              `json:"enum"`
+	Properties           map[string]*schema `json:"properties"`
+	AdditionalProperties *schema            `json:"-"` // of maps keyed by titles, like the technical assets
+	Items                *schema            `json:"items"`
+}
+
+func (what *schema) UnmarshalJSON(data []byte) error {
+	type plain schema
+	var fields struct {
+		plain
+		AdditionalProperties json.RawMessage `json:"additionalProperties"`
+	}
+	err := json.Unmarshal(data, &fields)
+	if err != nil {
+		return err
+	}
+	*what = schema(fields.plain)
+	if strings.HasPrefix(strings.TrimSpace(string(fields.AdditionalProperties)), "{") { // may also be a boolean
+		what.AdditionalProperties = new(schema)
+		return json.Unmarshal(fields.AdditionalProperties, what.AdditionalProperties)
+	}
+	return nil
+}
+
+func loadSchema(appFolder string) (*schema, error) {
+	data, err := os.ReadFile(filepath.Clean(filepath.Join(appFolder, "schema.json")))
+	if err != nil {
+		return nil, fmt.Errorf("unable to read model schema: %v", err)
+	}
+	root := new(schema)
+	err = json.Unmarshal(data, root)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse model schema: %v", err)
+	}
+	return root, nil
+}
+
+// resolve answers the schema of the field at the path of yaml keys ("[]" for the items of lists), nil if unknown
+func (what *schema) resolve(path []string) *schema {
+	current := what
+	for _, key := range path {
+		if current == nil {
+			return nil
+		}
+		switch {
+		case key == sequenceItem:
+			current = current.Items
+		case current.Properties[key] != nil:
+			current = current.Properties[key]
+		default:
+			current = current.AdditionalProperties
+		}
+	}
+	return current
+}
+
+func (what *schema) hasType(name string) bool {
+	switch value := what.Type.(type) {
+	case string:
+		return value == name
+	case []any:
+		for _, item := range value {
+			if item == name {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// values are the values to complete: the enum values or else true and false for booleans
+func (what *schema) values() []string {
+	result := make([]string, 0, len(what.Enum))
+	for _, value := range what.Enum {
+		result = append(result, fmt.Sprint(value))
+	}
+	if len(result) == 0 && what.hasType("boolean") {
+		result = append(result, "true", "false")
+	}
+	return result
+}
+
+func (what *schema) propertyNames() []string {
+	names := make([]string, 0, len(what.Properties))
+	for name := range what.Properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// documentation is the markdown of the hover docs of the field
+func (what *schema) documentation(key string) string {
+	text := "**" + key + "**"
+	if len(what.Description) > 0 {
+		text += ": " + what.Description
+	}
+	if values := what.values(); len(what.Enum) > 0 {
+		text += "\n\nOne of: `" + strings.Join(values, "`, `") + "`"
+	}
+	return text
+}
@@ -0,0 +1,422 @@
+package lsp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/threagile/threagile/pkg/common"
+	"github.com/threagile/threagile/pkg/docs"
+	"github.com/threagile/threagile/pkg/input"
+	"github.com/threagile/threagile/pkg/model"
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+// Server is a language server for the yaml model files, offering the problems found by the validation as diagnostics,
+// completion of enum values, ids and keys, hover docs of the fields and go-to-definition of referenced ids
+type Server struct {
+	config              *common.Config
+	builtinRiskRules    types.RiskRules
+	customRiskRules     types.RiskRules
+	loadCustomRiskRules func(reporter types.ProgressReporter) types.RiskRules
+	schema              *schema
+	documents           map[string]*document
+	includeDiagnostics  map[string]map[string]bool // the uris of the includes problems were published for, by document
+	conn                *connection
+	shutdown            bool
+}
+
+// NewServer creates a server validating with the rules, the custom ones are loaded once the editor is connected (so
+// that their warnings can be shown there). As the model is validated while typing, neither hooks nor fetches of
+// remote data configured are run for it.
+func NewServer(config *common.Config, builtinRiskRules types.RiskRules, loadCustomRiskRules func(reporter types.ProgressReporter) types.RiskRules) *Server {
+	offline := *config
+	offline.PreParseHooks, offline.PostAnalysisHooks = nil, nil
+	offline.SBOMFetchURLs, offline.ServiceMetadataURLs, offline.StructurizrWorkspace = false, nil, ""
+	return &Server{
+		config:              &offline,
+		builtinRiskRules:    builtinRiskRules,
+		customRiskRules:     make(types.RiskRules),
+		loadCustomRiskRules: loadCustomRiskRules,
+		documents:           make(map[string]*document),
+		includeDiagnostics:  make(map[string]map[string]bool),
+	}
+}
+
+// Serve answers the messages of the editor until it exits (or closes the input)
+func (s *Server) Serve(ctx context.Context, in io.Reader, out io.Writer) error {
+	s.conn = newConnection(in, out)
+	for ctx.Err() == nil {
+		msg, err := s.conn.read()
+		var parseError *responseError
+		if errors.As(err, &parseError) {
+			_ = s.conn.write(&message{ID: nullID(), Error: parseError})
+			continue
+		}
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if msg.Method == "exit" {
+			if !s.shutdown {
+				return fmt.Errorf("exit without shutdown")
+			}
+			return nil
+		}
+
+		result, handleError := s.handle(msg)
+		if msg.ID == nil { // notifications are not answered
+			continue
+		}
+		response := &message{ID: msg.ID, Result: result}
+		if handleError != nil {
+			response.Result, response.Error = nil, handleError
+		} else if result == nil {
+			response.Result = json.RawMessage("null")
+		}
+		err = s.conn.write(response)
+		if err != nil {
+			return err
+		}
+	}
+	return ctx.Err()
+}
+
+func (s *Server) handle(msg *message) (any, *responseError) {
+	switch msg.Method {
+	case "initialize":
+		return map[string]any{
+			"capabilities": map[string]any{
+				"textDocumentSync":   map[string]any{"openClose": true, "change": 1, "save": true}, // full synchronization
+				"completionProvider": map[string]any{"triggerCharacters": []string{":", " ", "-"}},
+				"hoverProvider":      true,
+				"definitionProvider": true,
+			},
+			"serverInfo": map[string]any{"name": "threagile", "version": docs.ThreagileVersion},
+		}, nil
+
+	case "initialized":
+		var err error
+		s.schema, err = loadSchema(s.config.AppFolder)
+		if err != nil {
+			s.logMessage(messageTypeWarning, fmt.Sprintf("%v, completing and describing ids only", err))
+		}
+		if s.loadCustomRiskRules != nil {
+			s.customRiskRules = s.loadCustomRiskRules(&logReporter{server: s, verbose: s.config.Verbose})
+		}
+		return nil, nil
+
+	case "shutdown":
+		s.shutdown = true
+		return nil, nil
+
+	case "textDocument/didOpen":
+		var params didOpenParams
+		if err := json.Unmarshal(msg.Params, &params); err != nil {
+			return nil, invalidParams(err)
+		}
+		s.update(params.TextDocument.URI, params.TextDocument.Text)
+		return nil, nil
+
+	case "textDocument/didChange":
+		var params didChangeParams
+		if err := json.Unmarshal(msg.Params, &params); err != nil {
+			return nil, invalidParams(err)
+		}
+		if len(params.ContentChanges) > 0 {
+			s.update(params.TextDocument.URI, params.ContentChanges[len(params.ContentChanges)-1].Text)
+		}
+		return nil, nil
+
+	case "textDocument/didSave":
+		var params textDocumentParams
+		if err := json.Unmarshal(msg.Params, &params); err != nil {
+			return nil, invalidParams(err)
+		}
+		if doc, ok := s.documents[params.TextDocument.URI]; ok {
+			s.publishDiagnostics(doc)
+		}
+		return nil, nil
+
+	case "textDocument/didClose":
+		var params textDocumentParams
+		if err := json.Unmarshal(msg.Params, &params); err != nil {
+			return nil, invalidParams(err)
+		}
+		delete(s.documents, params.TextDocument.URI)
+		uris := []string{params.TextDocument.URI}
+		for uri := range s.includeDiagnostics[params.TextDocument.URI] {
+			uris = append(uris, uri)
+		}
+		delete(s.includeDiagnostics, params.TextDocument.URI)
+		sort.Strings(uris[1:])
+		for _, uri := range uris {
+			_ = s.conn.write(&message{Method: "textDocument/publishDiagnostics", Params: mustMarshal(map[string]any{"uri": uri, "diagnostics": []diagnostic{}})})
+		}
+		return nil, nil
+
+	case "textDocument/completion", "textDocument/hover", "textDocument/definition":
+		var params textDocumentPositionParams
+		if err := json.Unmarshal(msg.Params, &params); err != nil {
+			return nil, invalidParams(err)
+		}
+		doc, ok := s.documents[params.TextDocument.URI]
+		if !ok || params.Position.Line < 0 || params.Position.Line >= len(doc.lines) {
+			return nil, nil
+		}
+		switch msg.Method {
+		case "textDocument/completion":
+			return s.complete(doc, params.Position), nil
+		case "textDocument/hover":
+			return s.hover(doc, params.Position), nil
+		default:
+			return s.definition(doc, params.Position), nil
+		}
+	}
+
+	if msg.ID != nil {
+		return nil, &responseError{Code: errorCodeMethodNotFound, Message: "method not supported: " + msg.Method}
+	}
+	return nil, nil
+}
+
+func (s *Server) update(uri string, text string) {
+	filename := uri
+	if parsed, err := url.Parse(uri); err == nil && parsed.Scheme == "file" {
+		filename = filepath.FromSlash(parsed.Path)
+	}
+	doc := newDocument(uri, filename, text)
+	s.documents[uri] = doc
+	s.publishDiagnostics(doc)
+}
+
+// read reads the model files preferring the (maybe unsaved) content of the open documents
+func (s *Server) read(filename string) ([]byte, error) {
+	for _, doc := range s.documents {
+		if filepath.Clean(doc.filename) == filepath.Clean(filename) {
+			return []byte(doc.text), nil
+		}
+	}
+	return input.ReadModelFile(filename)
+}
+
+// publishDiagnostics publishes the problems of the model of the document, the ones located in its includes under
+// their own uri (clearing the ones published for them before)
+func (s *Server) publishDiagnostics(doc *document) {
+	diagnostics := map[string][]diagnostic{doc.uri: {}}
+	if strings.ToLower(filepath.Ext(doc.filename)) == ".yaml" || strings.ToLower(filepath.Ext(doc.filename)) == ".yml" {
+		config := *s.config
+		config.InputFile = doc.filename
+		for _, located := range Diagnostics(model.ValidateModelWith(&config, s.read, s.builtinRiskRules, s.customRiskRules), s.read) {
+			severity := severityError
+			if located.Severity == model.ValidationWarning {
+				severity = severityWarning
+			}
+			uri := s.uriOf(located.File, doc)
+			diagnostics[uri] = append(diagnostics[uri], diagnostic{Range: located.Range, Severity: severity, Code: located.Code, Source: diagnosticSource, Message: located.Message})
+		}
+	}
+	for uri := range s.includeDiagnostics[doc.uri] {
+		if _, ok := diagnostics[uri]; !ok {
+			diagnostics[uri] = []diagnostic{}
+		}
+	}
+	s.includeDiagnostics[doc.uri] = make(map[string]bool)
+	uris := make([]string, 0, len(diagnostics))
+	for uri, list := range diagnostics {
+		uris = append(uris, uri)
+		if uri != doc.uri && len(list) > 0 {
+			s.includeDiagnostics[doc.uri][uri] = true
+		}
+	}
+	sort.Strings(uris)
+	for _, uri := range uris {
+		_ = s.conn.write(&message{Method: "textDocument/publishDiagnostics", Params: mustMarshal(map[string]any{"uri": uri, "diagnostics": diagnostics[uri]})})
+	}
+}
+
+// uriOf answers the uri of the model file, the one of its document when it is open
+func (s *Server) uriOf(filename string, doc *document) string {
+	for uri, open := range s.documents {
+		if filepath.Clean(open.filename) == filepath.Clean(filename) {
+			return uri
+		}
+	}
+	if !strings.HasPrefix(doc.uri, "file:") {
+		return filename
+	}
+	path := filepath.ToSlash(filename)
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+	return (&url.URL{Scheme: "file", Path: path}).String()
+}
+
+func (s *Server) definitions(doc *document) []definition {
+	return collectDefinitions(doc.filename, s.read, make(map[string]bool))
+}
+
+// complete offers the values of the field at the cursor (enum values, booleans or ids of the referenced elements) or
+// else the keys of the enclosing object
+func (s *Server) complete(doc *document, at Position) []completionItem {
+	text := doc.lines[at.Line]
+	prefix := text[:byteOffset(text, at.Character)]
+	info, ok := parseLine(prefix)
+	if !ok {
+		info = lineInfo{indent: len(prefix), keyIndent: len(prefix)}
+	}
+	path := doc.path(at.Line, info)
+
+	items := make([]completionItem, 0)
+	field := s.schema.resolve(path)
+	valueCompletion := info.hasKey
+	if info.hasKey {
+		path = append(path, info.key)
+		field = s.schema.resolve(path)
+	} else if info.item && (field == nil || len(field.Properties) == 0) { // list items being values rather than objects
+		valueCompletion = true
+	}
+
+	if valueCompletion {
+		if kind, ok := referencedKind(path); ok {
+			for _, element := range s.definitions(doc) {
+				if element.kind == kind || (kind == kindAnyElement && element.kind != kindTag) {
+					items = append(items, completionItem{Label: element.id, Kind: completionItemKindReference, Detail: element.kind + " " + element.title})
+				}
+			}
+			return items
+		}
+		if field != nil {
+			for _, value := range field.values() {
+				items = append(items, completionItem{Label: value, Kind: completionItemKindValue})
+			}
+		}
+		return items
+	}
+
+	if field != nil {
+		for _, name := range field.propertyNames() {
+			property := field.Properties[name]
+			items = append(items, completionItem{Label: name, Kind: completionItemKindProperty, Detail: property.Description, InsertText: name + ": "})
+		}
+	}
+	return items
+}
+
+// hover describes the field of the key at the cursor, or the element referenced by the id at the cursor
+func (s *Server) hover(doc *document, at Position) *hover {
+	text := doc.lines[at.Line]
+	index := byteOffset(text, at.Character)
+	info, ok := parseLine(text)
+	if !ok {
+		return nil
+	}
+	path := doc.path(at.Line, info)
+
+	if info.hasKey && index >= info.keyIndent && index < info.keyIndent+len(info.key)+2 {
+		field := s.schema.resolve(append(path, info.key))
+		if field == nil {
+			return nil
+		}
+		return &hover{Contents: markupContent{Kind: "markdown", Value: field.documentation(info.key)}}
+	}
+
+	if element, found := s.referencedElement(doc, path, info, text, index); found {
+		return &hover{Contents: markupContent{Kind: "markdown", Value: fmt.Sprintf("%v **%v** (`%v`)", element.kind, element.title, element.id)}}
+	}
+	return nil
+}
+
+// definition answers where the element referenced by the id at the cursor is defined
+func (s *Server) definition(doc *document, at Position) []location {
+	text := doc.lines[at.Line]
+	info, ok := parseLine(text)
+	if !ok {
+		return nil
+	}
+	element, found := s.referencedElement(doc, doc.path(at.Line, info), info, text, byteOffset(text, at.Character))
+	if !found {
+		return nil
+	}
+	uri := doc.uri
+	if filepath.Clean(element.filename) != filepath.Clean(doc.filename) {
+		uri = (&url.URL{Scheme: "file", Path: filepath.ToSlash(element.filename)}).String()
+	}
+	return []location{{URI: uri, Range: TextRange{Start: Position{Line: element.line, Character: element.start}, End: Position{Line: element.line, Character: element.end}}}}
+}
+
+func (s *Server) referencedElement(doc *document, path []string, info lineInfo, text string, index int) (definition, bool) {
+	if info.hasKey {
+		path = append(path, info.key)
+	}
+	kind, ok := referencedKind(path)
+	if !ok || index < info.valueStart {
+		return definition{}, false
+	}
+	id, _, _ := wordAt(text, index)
+	for _, element := range s.definitions(doc) {
+		if element.id == id && (element.kind == kind || (kind == kindAnyElement && element.kind != kindTag)) {
+			return element, true
+		}
+	}
+	return definition{}, false
+}
+
+func (s *Server) logMessage(messageType int, text string) {
+	_ = s.conn.write(&message{Method: "window/logMessage", Params: mustMarshal(map[string]any{"type": messageType, "message": text})})
+}
+
+// logReporter shows the progress of loading the custom risk rules in the log of the editor, as the output of the
+// server is the connection to the editor
+type logReporter struct {
+	server  *Server
+	verbose bool
+}
+
+func (what *logReporter) Info(a ...any) {
+	if what.verbose {
+		what.server.logMessage(messageTypeInfo, fmt.Sprint(a...))
+	}
+}
+
+func (what *logReporter) Warn(a ...any) {
+	what.server.logMessage(messageTypeWarning, fmt.Sprint(a...))
+}
+
+func (what *logReporter) Error(a ...any) {
+	what.server.logMessage(messageTypeError, fmt.Sprint(a...))
+}
+
+func (what *logReporter) Infof(format string, a ...any) {
+	what.Info(fmt.Sprintf(format, a...))
+}
+
+func (what *logReporter) Warnf(format string, a ...any) {
+	what.Warn(fmt.Sprintf(format, a...))
+}
+
+func (what *logReporter) Errorf(format string, a ...any) {
+	what.Error(fmt.Sprintf(format, a...))
+}
+
+func invalidParams(err error) *responseError {
+	return &responseError{Code: errorCodeInvalidParams, Message: err.Error()}
+}
+
+func nullID() *json.RawMessage {
+	id := json.RawMessage("null")
+	return &id
+}
+
+func mustMarshal(value any) json.RawMessage {
+	data, _ := json.Marshal(value)
+	return data
+}
@@ -0,0 +1,344 @@
+package lsp
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/textproto"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/threagile/threagile/pkg/common"
+	"github.com/threagile/threagile/pkg/model"
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+const testModel = `threagile_version: 1.0.0
+title: Shop
+date: 2024-01-01
+business_criticality: important
+tags_available:
+  - aws
+data_assets:
+  Orders:
+    id: orders
+    usage: business
+    quantity: lots
+    confidentiality: confidential
+    integrity: critical
+    availability: operational
+technical_assets:
+  Web Shop:
+    id: web-shop
+    type: process
+    usage: business
+    technology: web-server
+    size: application
+    machine: container
+    encryption: none
+    confidentiality: internal
+    integrity: important
+    availability: important
+    data_assets_processed:
+      - orders
+    tags:
+      -
+    communication_links:
+      Database Access:
+        target: order-db
+        protocol:
+        authentication: none
+        authorization: none
+        usage: business
+  Order DB:
+    id: order-db
+    type: datastore
+    usage: business
+    technology: database
+    size: component
+    machine: container
+    encryption: none
+    confidentiality: confidential
+    integrity: critical
+    availability: important
+`
+
+func TestServer(t *testing.T) {
+	modelFile := filepath.Join(t.TempDir(), "threagile.yaml")
+	assert.NoError(t, os.WriteFile(modelFile, []byte("title: stale version on disk\n"), 0600))
+	uri := "file://" + filepath.ToSlash(modelFile)
+	lines := strings.Split(testModel, "\n")
+	lineOf := func(text string) int {
+		for index, line := range lines {
+			if strings.Contains(line, text) {
+				return index
+			}
+		}
+		t.Fatalf("missing line %q", text)
+		return -1
+	}
+	atLine := func(line int, character int) map[string]any {
+		return map[string]any{"textDocument": map[string]any{"uri": uri}, "position": Position{Line: line, Character: character}}
+	}
+	at := func(text string, offset int) map[string]any {
+		return atLine(lineOf(text), strings.Index(lines[lineOf(text)], text)+offset)
+	}
+
+	var in bytes.Buffer
+	id := 0
+	send := func(method string, params any) {
+		data, _ := json.Marshal(params)
+		msg := map[string]any{"jsonrpc": "2.0", "method": method, "params": json.RawMessage(data)}
+		if !strings.Contains(method, "/did") && method != "initialized" && method != "exit" {
+			id++
+			msg["id"] = id
+		}
+		data, _ = json.Marshal(msg)
+		_, _ = fmt.Fprintf(&in, "Content-Length: %d\r\n\r\n%s", len(data), data)
+	}
+	send("initialize", map[string]any{})
+	send("initialized", map[string]any{})
+	send("textDocument/didOpen", map[string]any{"textDocument": map[string]any{"uri": uri, "languageId": "yaml", "version": 1, "text": testModel}})
+	send("textDocument/completion", at("protocol:", 9))               // 2: enum values
+	send("textDocument/completion", at("target: order-db", 8))        // 3: technical asset ids
+	send("textDocument/completion", atLine(lineOf("    tags:")+1, 7)) // 4: tags
+	send("textDocument/completion", at("    data_assets_processed:", 4))
+	send("textDocument/hover", at("quantity: lots", 2))         // 6: field docs
+	send("textDocument/hover", at("- orders", 3))               // 7: referenced element
+	send("textDocument/definition", at("target: order-db", 10)) // 8: defined id
+	send("textDocument/definition", at("title: Shop", 8))       // 9: nothing referenced
+	send("unknown/method", map[string]any{})
+	send("shutdown", nil)
+	send("exit", nil)
+
+	config := new(common.Config).Defaults("")
+	config.AppFolder = filepath.Join("..", "..", "support")
+	server := NewServer(config, make(types.RiskRules), func(reporter types.ProgressReporter) types.RiskRules {
+		reporter.Warn("no custom rules")
+		return make(types.RiskRules)
+	})
+	var out bytes.Buffer
+	assert.NoError(t, server.Serve(context.Background(), &in, &out))
+
+	responses, errorCodes, notifications := readMessages(t, &out)
+
+	assert.Contains(t, string(responses[1]), `"hoverProvider":true`)
+	assert.Len(t, notifications["window/logMessage"], 1)
+
+	var published struct {
+		URI         string       `json:"uri"`
+		Diagnostics []diagnostic `json:"diagnostics"`
+	}
+	assert.Len(t, notifications["textDocument/publishDiagnostics"], 1)
+	assert.NoError(t, json.Unmarshal(notifications["textDocument/publishDiagnostics"][0], &published))
+	assert.Equal(t, uri, published.URI)
+	assert.Len(t, published.Diagnostics, 2, "the open document is validated rather than the file on disk")
+	for _, problem := range published.Diagnostics {
+		if strings.Contains(problem.Message, "quantity") {
+			assert.Equal(t, lineOf("quantity: lots"), problem.Range.Start.Line)
+			assert.Equal(t, severityError, problem.Severity)
+		} else {
+			assert.Equal(t, lineOf("protocol:"), problem.Range.Start.Line)
+		}
+	}
+
+	labels := func(id int) []string {
+		var items []completionItem
+		assert.NoError(t, json.Unmarshal(responses[id], &items))
+		result := make([]string, 0, len(items))
+		for _, item := range items {
+			result = append(result, item.Label)
+		}
+		return result
+	}
+	assert.Contains(t, labels(2), "https")
+	assert.ElementsMatch(t, []string{"web-shop", "order-db"}, labels(3))
+	assert.Equal(t, []string{"aws"}, labels(4))
+	assert.Contains(t, labels(5), "communication_links")
+	assert.Contains(t, labels(5), "technology")
+
+	assert.Contains(t, string(responses[6]), "many")
+	assert.Contains(t, string(responses[7]), "**Orders**")
+
+	var locations []location
+	assert.NoError(t, json.Unmarshal(responses[8], &locations))
+	assert.Equal(t, []location{{URI: uri, Range: TextRange{Start: Position{Line: lineOf("id: order-db"), Character: 8}, End: Position{Line: lineOf("id: order-db"), Character: 16}}}}, locations)
+	assert.Equal(t, "null", string(responses[9]))
+
+	assert.Equal(t, errorCodeMethodNotFound, errorCodes[10])
+	assert.Equal(t, "null", string(responses[11]))
+}
+
+// readMessages answers the results and error codes of the responses by id and the notifications by method
+func readMessages(t *testing.T, out io.Reader) (map[int]json.RawMessage, map[int]int, map[string][]json.RawMessage) {
+	responses := make(map[int]json.RawMessage)
+	errorCodes := make(map[int]int)
+	notifications := make(map[string][]json.RawMessage)
+	reader := textproto.NewReader(bufio.NewReader(out))
+	for {
+		header, err := reader.ReadMIMEHeader()
+		if err == io.EOF {
+			break
+		}
+		assert.NoError(t, err)
+		length, _ := strconv.Atoi(header.Get("Content-Length"))
+		data := make([]byte, length)
+		_, err = io.ReadFull(reader.R, data)
+		assert.NoError(t, err)
+		var msg struct {
+			ID     *int            `json:"id"`
+			Method string          `json:"method"`
+			Params json.RawMessage `json:"params"`
+			Result json.RawMessage `json:"result"`
+			Error  *responseError  `json:"error"`
+		}
+		assert.NoError(t, json.Unmarshal(data, &msg))
+		if msg.ID == nil {
+			notifications[msg.Method] = append(notifications[msg.Method], msg.Params)
+		} else if msg.Error != nil {
+			errorCodes[*msg.ID] = msg.Error.Code
+		} else {
+			responses[*msg.ID] = msg.Result
+		}
+	}
+
+	return responses, errorCodes, notifications
+}
+
+func TestServerPublishesProblemsOfIncludes(t *testing.T) {
+	folder := t.TempDir()
+	modelFile := filepath.Join(folder, "threagile.yaml")
+	includeFile := filepath.Join(folder, "assets.yaml")
+	assert.NoError(t, os.WriteFile(includeFile, []byte("data_assets:\n  Orders:\n    id: orders\n    usage: business\n    quantity: lots\n"), 0600))
+	uri := "file://" + filepath.ToSlash(modelFile)
+	includeURI := "file://" + filepath.ToSlash(includeFile)
+
+	var in bytes.Buffer
+	send := func(method string, id int, params any) {
+		msg := map[string]any{"jsonrpc": "2.0", "method": method, "params": params}
+		if id > 0 {
+			msg["id"] = id
+		}
+		data, _ := json.Marshal(msg)
+		_, _ = fmt.Fprintf(&in, "Content-Length: %d\r\n\r\n%s", len(data), data)
+	}
+	send("textDocument/didOpen", 0, map[string]any{"textDocument": map[string]any{"uri": uri, "text": "title: Shop\nincludes:\n  - assets.yaml\n"}})
+	send("textDocument/didClose", 0, map[string]any{"textDocument": map[string]any{"uri": uri}})
+	send("shutdown", 1, nil)
+	send("exit", 0, nil)
+
+	server := NewServer(new(common.Config).Defaults(""), make(types.RiskRules), nil)
+	var out bytes.Buffer
+	assert.NoError(t, server.Serve(context.Background(), &in, &out))
+	_, _, notifications := readMessages(t, &out)
+
+	published := make([]struct {
+		URI         string       `json:"uri"`
+		Diagnostics []diagnostic `json:"diagnostics"`
+	}, len(notifications["textDocument/publishDiagnostics"]))
+	for index, params := range notifications["textDocument/publishDiagnostics"] {
+		assert.NoError(t, json.Unmarshal(params, &published[index]))
+	}
+	assert.Len(t, published, 4)
+	assert.Equal(t, includeURI, published[0].URI)
+	quantityProblems := 0
+	for _, problem := range published[0].Diagnostics {
+		if strings.Contains(problem.Message, "quantity") {
+			quantityProblems++
+			assert.Equal(t, 4, problem.Range.Start.Line, "located in the include")
+		}
+	}
+	assert.Equal(t, 1, quantityProblems)
+	assert.Equal(t, uri, published[1].URI)
+	assert.Equal(t, uri, published[2].URI)
+	assert.Empty(t, published[2].Diagnostics)
+	assert.Equal(t, includeURI, published[3].URI)
+	assert.Empty(t, published[3].Diagnostics, "cleared once the including document is closed")
+}
+
+func TestServerRunsNoHooksOrFetches(t *testing.T) {
+	config := new(common.Config).Defaults("")
+	config.PreParseHooks = []string{"scrub-secrets"}
+	config.SBOMFetchURLs = true
+	config.StructurizrWorkspace = "https://structurizr.example.com/workspace/1"
+	server := NewServer(config, make(types.RiskRules), nil)
+	assert.Empty(t, server.config.PreParseHooks)
+	assert.False(t, server.config.SBOMFetchURLs)
+	assert.Empty(t, server.config.StructurizrWorkspace)
+	assert.Equal(t, []string{"scrub-secrets"}, config.PreParseHooks, "the config given is kept")
+}
+
+func TestDefinitionsCountUTF16CodeUnits(t *testing.T) {
+	text := "technical_assets:\n  Dönerbude 😀:\n    id: \"döner-😀\"\n"
+	definitions := collectDefinitions("model.yaml", func(string) ([]byte, error) { return []byte(text), nil }, make(map[string]bool))
+	assert.Len(t, definitions, 1)
+	assert.Equal(t, "döner-😀", definitions[0].id)
+	assert.Equal(t, 2, definitions[0].line)
+	assert.Equal(t, 9, definitions[0].start, "after the quote")
+	assert.Equal(t, 9+8, definitions[0].end, "the emoji is two code units")
+}
+
+func TestDocumentPath(t *testing.T) {
+	doc := newDocument("file:///model.yaml", "/model.yaml", `custom_risk_categories:
+  - id: my-risk
+    risks_identified:
+      My Risk:
+        severity: high
+        data_breach_technical_assets:
+          - web-shop
+`)
+	for line, expected := range map[int][]string{
+		1: {"custom_risk_categories", sequenceItem},
+		2: {"custom_risk_categories", sequenceItem},
+		4: {"custom_risk_categories", sequenceItem, "risks_identified", "My Risk"},
+		6: {"custom_risk_categories", sequenceItem, "risks_identified", "My Risk", "data_breach_technical_assets", sequenceItem},
+	} {
+		info, ok := parseLine(doc.lines[line])
+		assert.True(t, ok)
+		assert.Equal(t, expected, doc.path(line, info), doc.lines[line])
+	}
+
+	line, found := doc.fieldLine("custom_risk_categories.my-risk.risks_identified.My Risk.severity")
+	assert.True(t, found)
+	assert.Equal(t, 4, line)
+}
+
+func TestDiagnostics(t *testing.T) {
+	lines := strings.Split(testModel, "\n")
+	problems := []model.ValidationProblem{
+		{File: "threagile.yaml", Field: "data_assets.Orders.quantity", Message: "unknown 'quantity' value", Severity: model.ValidationError, Code: model.ProblemCodeInvalidField},
+		{File: "threagile.yaml", Message: "yaml: line 3: did not find expected key", Severity: model.ValidationError, Code: model.ProblemCodeModelUnreadable},
+		{File: "missing.yaml", Message: "unable to read", Severity: model.ValidationError, Code: model.ProblemCodeModelUnreadable},
+	}
+	reads := 0
+	diagnostics := Diagnostics(problems, func(filename string) ([]byte, error) {
+		reads++
+		if filename == "threagile.yaml" {
+			return []byte(testModel), nil
+		}
+		return nil, os.ErrNotExist
+	})
+
+	assert.Equal(t, 2, reads, "each file is read once")
+	assert.Len(t, diagnostics, 3)
+	assert.Equal(t, FileDiagnostic{
+		File:     "threagile.yaml",
+		Range:    lineRange(lines, 10),
+		Severity: model.ValidationError,
+		Code:     model.ProblemCodeInvalidField,
+		Source:   "threagile",
+		Message:  "unknown 'quantity' value",
+		Field:    "data_assets.Orders.quantity",
+	}, diagnostics[0])
+	assert.Equal(t, Position{Line: 10, Character: 4}, diagnostics[0].Range.Start)
+	assert.Equal(t, 2, diagnostics[1].Range.Start.Line, "the line of the yaml error")
+	assert.Equal(t, TextRange{}, diagnostics[2].Range)
+}
@@ -0,0 +1,505 @@
+package macros
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/threagile/threagile/pkg/input"
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+// The convention of the add-k8s-workloads macro:
+//   - Deployments, StatefulSets and DaemonSets become containerized technical assets (datastores for the images of
+//     well-known databases), running in a shared runtime of the cluster
+//   - each namespace becomes a trust boundary of the type network-policy-namespace-isolation
+//   - Ingresses become reverse proxies on the internet, with communication links to the workloads of their services
+//   - ingress rules of NetworkPolicies allowing pods become communication links from these pods to the selected ones,
+//     with the protocol guessed from the port
+//   - Services of the type LoadBalancer or NodePort put their workloads on the internet
+//
+// As the manifests are read from the paths answered, the macro is a local one, not offered by the server.
+
+type AddK8sWorkloadsMacro struct {
+	macroState        map[string][]string
+	questionsAnswered []string
+	objects           []k8sObject
+}
+
+type k8sObject struct {
+	Kind     string `yaml:"kind"`
+	Metadata struct {
+		Name      string            `yaml:"name"`
+		Namespace string            `yaml:"namespace"`
+		Labels    map[string]string `yaml:"labels"`
+	} `yaml:"metadata"`
+	Spec  k8sSpec     `yaml:"spec"`
+	Items []k8sObject `yaml:"items"` // of a List
+}
+
+type k8sSpec struct {
+	Template struct { // of workloads
+		Metadata struct {
+			Labels map[string]string `yaml:"labels"`
+		} `yaml:"metadata"`
+		Spec struct {
+			Containers []struct {
+				Image string `yaml:"image"`
+			} `yaml:"containers"`
+		} `yaml:"spec"`
+	} `yaml:"template"`
+
+	Type     string         `yaml:"type"`     // of services
+	Selector map[string]any `yaml:"selector"` // of services
+
+	TLS            []any              `yaml:"tls"` // of ingresses
+	DefaultBackend *k8sIngressBackend `yaml:"defaultBackend"`
+	Rules          []struct {
+		HTTP struct {
+			Paths []struct {
+				Backend k8sIngressBackend `yaml:"backend"`
+			} `yaml:"paths"`
+		} `yaml:"http"`
+	} `yaml:"rules"`
+
+	PodSelector k8sLabelSelector `yaml:"podSelector"` // of network policies
+	Ingress     []struct {
+		From []struct {
+			PodSelector       *k8sLabelSelector `yaml:"podSelector"`
+			NamespaceSelector *k8sLabelSelector `yaml:"namespaceSelector"`
+		} `yaml:"from"`
+		Ports []struct {
+			Port any `yaml:"port"`
+		} `yaml:"ports"`
+	} `yaml:"ingress"`
+}
+
+type k8sIngressBackend struct {
+	ServiceName string `yaml:"serviceName"` // networking.k8s.io/v1beta1
+	Service     struct {
+		Name string `yaml:"name"`
+	} `yaml:"service"`
+}
+
+type k8sLabelSelector struct {
+	MatchLabels map[string]string `yaml:"matchLabels"`
+}
+
+// k8sDatabaseImages are the image names of workloads taken over as datastores
+var k8sDatabaseImages = []string{"cassandra", "couchdb", "elasticsearch", "mariadb", "mongo", "mysql", "postgres", "redis"}
+
+func NewAddK8sWorkloads() *AddK8sWorkloadsMacro {
+	return &AddK8sWorkloadsMacro{
+		macroState:        make(map[string][]string),
+		questionsAnswered: make([]string, 0),
+	}
+}
+
+func (m *AddK8sWorkloadsMacro) GetMacroDetails() MacroDetails {
+	return MacroDetails{
+		ID:          "add-k8s-workloads",
+		Title:       "Add Kubernetes Workloads",
+		Description: "This model macro adds the workloads, ingresses and network policies of Kubernetes manifests to the model.",
+	}
+}
+
+func (m *AddK8sWorkloadsMacro) IsLocal() bool {
+	return true
+}
+
+func (m *AddK8sWorkloadsMacro) GetNextQuestion(_ *types.Model) (nextQuestion MacroQuestion, err error) {
+	switch len(m.questionsAnswered) {
+	case 0:
+		return MacroQuestion{
+			ID:              "manifests",
+			Title:           "Which Kubernetes manifests (yaml files or directories, comma-separated) shall be added?",
+			Description:     "Deployments, StatefulSets, DaemonSets, Services, Ingresses and NetworkPolicies are taken over, other objects are ignored.",
+			PossibleAnswers: nil,
+			MultiSelect:     false,
+			DefaultAnswer:   "",
+		}, nil
+	case 1:
+		return MacroQuestion{
+			ID:              "cluster-name",
+			Title:           "What is the name of the cluster?",
+			Description:     "This name is the title of the shared runtime of the workloads.",
+			PossibleAnswers: nil,
+			MultiSelect:     false,
+			DefaultAnswer:   "Kubernetes Cluster",
+		}, nil
+	}
+	return NoMoreQuestions(), nil
+}
+
+func (m *AddK8sWorkloadsMacro) ApplyAnswer(questionID string, answer ...string) (message string, validResult bool, err error) {
+	if questionID == "manifests" && len(strings.TrimSpace(strings.Join(answer, ""))) == 0 {
+		return "Please enter the Kubernetes manifests", false, nil
+	}
+	if questionID == "cluster-name" && (len(answer) == 0 || len(strings.TrimSpace(answer[0])) == 0) {
+		return "Please enter the name of the cluster", false, nil
+	}
+	m.macroState[questionID] = answer
+	m.questionsAnswered = append(m.questionsAnswered, questionID)
+	return "Answer processed", true, nil
+}
+
+func (m *AddK8sWorkloadsMacro) GoBack() (message string, validResult bool, err error) {
+	if len(m.questionsAnswered) == 0 {
+		return "Cannot go back further", false, nil
+	}
+	lastQuestionID := m.questionsAnswered[len(m.questionsAnswered)-1]
+	m.questionsAnswered = m.questionsAnswered[:len(m.questionsAnswered)-1]
+	delete(m.macroState, lastQuestionID)
+	return "Undo successful", true, nil
+}
+
+func (m *AddK8sWorkloadsMacro) GetFinalChangeImpact(modelInput *input.Model, parsedModel *types.Model) (changes []string, message string, validResult bool, err error) {
+	changeLogCollector := make([]string, 0)
+	message, validResult, err = m.applyChange(modelInput, parsedModel, &changeLogCollector, true)
+	return changeLogCollector, message, validResult, err
+}
+
+func (m *AddK8sWorkloadsMacro) Execute(modelInput *input.Model, parsedModel *types.Model) (message string, validResult bool, err error) {
+	changeLogCollector := make([]string, 0)
+	message, validResult, err = m.applyChange(modelInput, parsedModel, &changeLogCollector, false)
+	return message, validResult, err
+}
+
+// readK8sManifests reads the objects of the yaml files (all documents of them) and of the yaml files in the directories
+func readK8sManifests(paths string) ([]k8sObject, error) {
+	files := make([]string, 0)
+	for _, path := range strings.Split(paths, ",") {
+		path = filepath.Clean(strings.TrimSpace(path))
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read Kubernetes manifests: %w", err)
+		}
+		if !info.IsDir() {
+			files = append(files, path)
+			continue
+		}
+		err = filepath.WalkDir(path, func(file string, entry os.DirEntry, err error) error {
+			if err == nil && !entry.IsDir() && (strings.HasSuffix(file, ".yaml") || strings.HasSuffix(file, ".yml")) {
+				files = append(files, file)
+			}
+			return err
+		})
+		if err != nil {
+			return nil, fmt.Errorf("unable to read Kubernetes manifests: %w", err)
+		}
+	}
+
+	objects := make([]k8sObject, 0)
+	for _, file := range files {
+		data, err := os.ReadFile(filepath.Clean(file))
+		if err != nil {
+			return nil, fmt.Errorf("unable to read Kubernetes manifest: %w", err)
+		}
+		decoder := yaml.NewDecoder(bytes.NewReader(data))
+		for {
+			var object k8sObject
+			err = decoder.Decode(&object)
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			if err != nil {
+				return nil, fmt.Errorf("unable to parse Kubernetes manifest %v: %w", file, err)
+			}
+			objects = append(objects, append([]k8sObject{object}, object.Items...)...)
+		}
+	}
+	for i := range objects {
+		if len(objects[i].Metadata.Namespace) == 0 {
+			objects[i].Metadata.Namespace = "default"
+		}
+	}
+	return objects, nil
+}
+
+// k8sWorkload is a workload or an ingress, added as technical asset
+type k8sWorkload struct {
+	object *k8sObject
+	title  string
+	id     string
+}
+
+func (m *AddK8sWorkloadsMacro) applyChange(modelInput *input.Model, parsedModel *types.Model, changeLogCollector *[]string, dryRun bool) (message string, validResult bool, err error) {
+	if m.objects == nil { // the manifests are read once all questions are answered, not while answering them
+		objects, err := readK8sManifests(strings.Join(m.macroState["manifests"], ","))
+		if err != nil {
+			return err.Error(), false, nil
+		}
+		m.objects = objects
+	}
+	workloads, ingresses := make([]*k8sWorkload, 0), make([]*k8sWorkload, 0)
+	for i := range m.objects {
+		object := &m.objects[i]
+		title := object.Metadata.Name
+		if object.Metadata.Namespace != "default" {
+			title += " (" + object.Metadata.Namespace + ")"
+		}
+		switch object.Kind {
+		case "Deployment", "StatefulSet", "DaemonSet":
+			workloads = append(workloads, &k8sWorkload{object: object, title: title, id: types.MakeID(title)})
+		case "Ingress":
+			title = "Ingress " + title
+			ingresses = append(ingresses, &k8sWorkload{object: object, title: title, id: types.MakeID(title)})
+		}
+	}
+	if len(workloads) == 0 {
+		return "No workloads found in the Kubernetes manifests", false, nil
+	}
+
+	internet := make(map[*k8sObject]bool)
+	for i := range m.objects {
+		service := &m.objects[i]
+		if service.Kind == "Service" && (service.Spec.Type == "LoadBalancer" || service.Spec.Type == "NodePort") {
+			for _, workload := range m.selectedByService(workloads, service) {
+				internet[workload.object] = true
+			}
+		}
+	}
+	links := make(map[*k8sObject]map[string]input.CommunicationLink)
+	for _, ingress := range ingresses {
+		links[ingress.object] = m.ingressLinks(workloads, ingress)
+	}
+	for i := range m.objects {
+		if m.objects[i].Kind == "NetworkPolicy" {
+			m.addNetworkPolicyLinks(workloads, &m.objects[i], links)
+		}
+	}
+
+	namespaces := make(map[string][]string)
+	added := make([]string, 0)
+	for _, asset := range append(workloads, ingresses...) {
+		if _, exists := parsedModel.TechnicalAssets[asset.id]; exists {
+			continue
+		}
+		techAsset := newK8sTechnicalAsset(asset, internet[asset.object], links[asset.object])
+		*changeLogCollector = append(*changeLogCollector, "adding technical asset: "+asset.id)
+		if !dryRun {
+			if modelInput.TechnicalAssets == nil {
+				modelInput.TechnicalAssets = make(map[string]input.TechnicalAsset)
+			}
+			modelInput.TechnicalAssets[asset.title] = techAsset
+		}
+		namespaces[asset.object.Metadata.Namespace] = append(namespaces[asset.object.Metadata.Namespace], asset.id)
+		added = append(added, asset.id)
+	}
+
+	namespaceNames := make([]string, 0)
+	for namespace := range namespaces {
+		namespaceNames = append(namespaceNames, namespace)
+	}
+	sort.Strings(namespaceNames)
+	for _, namespace := range namespaceNames {
+		m.addToTrustBoundary(modelInput, parsedModel, "Namespace "+namespace, types.NetworkPolicyNamespaceIsolation, namespaces[namespace], changeLogCollector, dryRun)
+	}
+	m.addToSharedRuntime(modelInput, parsedModel, m.macroState["cluster-name"][0], added, changeLogCollector, dryRun)
+	return "Changeset valid", true, nil
+}
+
+func newK8sTechnicalAsset(asset *k8sWorkload, internet bool, links map[string]input.CommunicationLink) input.TechnicalAsset {
+	techAsset := input.TechnicalAsset{
+		ID:                     asset.id,
+		Description:            asset.object.Kind + " " + asset.object.Metadata.Name + " of namespace " + asset.object.Metadata.Namespace,
+		Type:                   types.Process.String(),
+		Usage:                  types.Business.String(),
+		Size:                   types.Service.String(),
+		Technology:             types.ApplicationServer,
+		Tags:                   []string{},
+		Internet:               internet,
+		Machine:                types.Container.String(),
+		Encryption:             types.NoneEncryption.String(),
+		Confidentiality:        types.Internal.String(),
+		Integrity:              types.Operational.String(),
+		Availability:           types.Operational.String(),
+		JustificationCiaRating: "TODO: rate the technical asset",
+		CustomDevelopedParts:   true,
+		CommunicationLinks:     links,
+	}
+	if asset.object.Kind == "Ingress" {
+		techAsset.Technology = types.ReverseProxy
+		techAsset.Internet = true
+		techAsset.CustomDevelopedParts = false
+		if len(asset.object.Spec.TLS) > 0 {
+			techAsset.Encryption = types.Transparent.String()
+		}
+		return techAsset
+	}
+	for _, container := range asset.object.Spec.Template.Spec.Containers {
+		image := container.Image[strings.LastIndex(container.Image, "/")+1:]
+		for _, database := range k8sDatabaseImages {
+			if strings.HasPrefix(image, database) {
+				techAsset.Type = types.Datastore.String()
+				techAsset.Technology = types.Database
+				techAsset.CustomDevelopedParts = false
+			}
+		}
+	}
+	return techAsset
+}
+
+// selectedByService are the workloads in the namespace of the service with pods matching its selector
+func (m *AddK8sWorkloadsMacro) selectedByService(workloads []*k8sWorkload, service *k8sObject) []*k8sWorkload {
+	selector := make(map[string]string)
+	for name, value := range service.Spec.Selector {
+		if text, ok := value.(string); ok {
+			selector[name] = text
+		}
+	}
+	selected := make([]*k8sWorkload, 0)
+	if len(selector) == 0 { // services without selector have their endpoints managed elsewhere
+		return selected
+	}
+	for _, workload := range workloads {
+		if workload.object.Metadata.Namespace == service.Metadata.Namespace && k8sLabelsMatch(selector, workload.object.Spec.Template.Metadata.Labels) {
+			selected = append(selected, workload)
+		}
+	}
+	return selected
+}
+
+func (m *AddK8sWorkloadsMacro) ingressLinks(workloads []*k8sWorkload, ingress *k8sWorkload) map[string]input.CommunicationLink {
+	backends := make([]k8sIngressBackend, 0)
+	if ingress.object.Spec.DefaultBackend != nil {
+		backends = append(backends, *ingress.object.Spec.DefaultBackend)
+	}
+	for _, rule := range ingress.object.Spec.Rules {
+		for _, path := range rule.HTTP.Paths {
+			backends = append(backends, path.Backend)
+		}
+	}
+	links := make(map[string]input.CommunicationLink)
+	for _, backend := range backends {
+		serviceName := backend.Service.Name
+		if len(serviceName) == 0 {
+			serviceName = backend.ServiceName
+		}
+		for i := range m.objects {
+			service := &m.objects[i]
+			if service.Kind != "Service" || service.Metadata.Name != serviceName || service.Metadata.Namespace != ingress.object.Metadata.Namespace {
+				continue
+			}
+			for _, workload := range m.selectedByService(workloads, service) {
+				links["Ingress Traffic to "+workload.title] = newK8sCommunicationLink(workload, types.HTTP)
+			}
+		}
+	}
+	return links
+}
+
+// addNetworkPolicyLinks adds the links of the pods allowed by the ingress rules of the network policy
+func (m *AddK8sWorkloadsMacro) addNetworkPolicyLinks(workloads []*k8sWorkload, policy *k8sObject, links map[*k8sObject]map[string]input.CommunicationLink) {
+	for _, target := range workloads {
+		if target.object.Metadata.Namespace != policy.Metadata.Namespace || !k8sLabelsMatch(policy.Spec.PodSelector.MatchLabels, target.object.Spec.Template.Metadata.Labels) {
+			continue
+		}
+		for _, rule := range policy.Spec.Ingress {
+			protocol := types.UnknownProtocol
+			if len(rule.Ports) > 0 {
+				if port, ok := rule.Ports[0].Port.(int); ok {
+					protocol = types.ProtocolOfPort(port)
+				}
+			}
+			for _, from := range rule.From {
+				if from.PodSelector == nil {
+					continue // ip blocks and whole namespaces are no workloads
+				}
+				for _, source := range workloads {
+					sameNamespace := source.object.Metadata.Namespace == policy.Metadata.Namespace
+					if source == target || (from.NamespaceSelector == nil && !sameNamespace) || !k8sLabelsMatch(from.PodSelector.MatchLabels, source.object.Spec.Template.Metadata.Labels) {
+						continue
+					}
+					if links[source.object] == nil {
+						links[source.object] = make(map[string]input.CommunicationLink)
+					}
+					links[source.object]["Access "+target.title] = newK8sCommunicationLink(target, protocol)
+				}
+			}
+		}
+	}
+}
+
+func newK8sCommunicationLink(target *k8sWorkload, protocol types.Protocol) input.CommunicationLink {
+	return input.CommunicationLink{
+		Target:         target.id,
+		Description:    "Access " + target.title,
+		Protocol:       protocol.String(),
+		Authentication: types.NoneAuthentication.String(),
+		Authorization:  types.NoneAuthorization.String(),
+		Tags:           []string{},
+		Usage:          types.Business.String(),
+	}
+}
+
+// k8sLabelsMatch tells whether the labels have all the labels of the selector, an empty selector matches all
+func k8sLabelsMatch(selector map[string]string, labels map[string]string) bool {
+	for name, value := range selector {
+		if labels[name] != value {
+			return false
+		}
+	}
+	return true
+}
+
+func (m *AddK8sWorkloadsMacro) addToTrustBoundary(modelInput *input.Model, parsedModel *types.Model, title string, trustBoundaryType types.TrustBoundaryType, ids []string, changeLogCollector *[]string, dryRun bool) {
+	id := types.MakeID(title)
+	if existing, exists := parsedModel.TrustBoundaries[id]; exists {
+		*changeLogCollector = append(*changeLogCollector, "filling existing trust boundary: "+id)
+		if !dryRun {
+			trustBoundary := modelInput.TrustBoundaries[existing.Title]
+			trustBoundary.TechnicalAssetsInside = append(trustBoundary.TechnicalAssetsInside, ids...)
+			modelInput.TrustBoundaries[existing.Title] = trustBoundary
+		}
+		return
+	}
+	*changeLogCollector = append(*changeLogCollector, "adding trust boundary: "+id)
+	if !dryRun {
+		if modelInput.TrustBoundaries == nil {
+			modelInput.TrustBoundaries = make(map[string]input.TrustBoundary)
+		}
+		modelInput.TrustBoundaries[title] = input.TrustBoundary{
+			ID:                    id,
+			Description:           title,
+			Type:                  trustBoundaryType.String(),
+			Tags:                  []string{},
+			TechnicalAssetsInside: ids,
+		}
+	}
+}
+
+func (m *AddK8sWorkloadsMacro) addToSharedRuntime(modelInput *input.Model, parsedModel *types.Model, title string, ids []string, changeLogCollector *[]string, dryRun bool) {
+	if len(ids) == 0 {
+		return
+	}
+	id := types.MakeID(title)
+	if existing, exists := parsedModel.SharedRuntimes[id]; exists {
+		*changeLogCollector = append(*changeLogCollector, "filling existing shared runtime: "+id)
+		if !dryRun {
+			sharedRuntime := modelInput.SharedRuntimes[existing.Title]
+			sharedRuntime.TechnicalAssetsRunning = append(sharedRuntime.TechnicalAssetsRunning, ids...)
+			modelInput.SharedRuntimes[existing.Title] = sharedRuntime
+		}
+		return
+	}
+	*changeLogCollector = append(*changeLogCollector, "adding shared runtime: "+id)
+	if !dryRun {
+		if modelInput.SharedRuntimes == nil {
+			modelInput.SharedRuntimes = make(map[string]input.SharedRuntime)
+		}
+		modelInput.SharedRuntimes[title] = input.SharedRuntime{
+			ID:                     id,
+			Description:            title,
+			Tags:                   []string{},
+			TechnicalAssetsRunning: ids,
+		}
+	}
+}
@@ -0,0 +1,109 @@
+package macros
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/threagile/threagile/pkg/input"
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+const k8sTestManifests = `apiVersion: apps/v1
+kind: Deployment
+metadata: {name: web, namespace: shop}
+spec:
+  template:
+    metadata: {labels: {app: web}}
+    spec: {containers: [{image: registry.example.com/shop/web:1.2}]}
+---
+apiVersion: apps/v1
+kind: StatefulSet
+metadata: {name: orders-db, namespace: shop}
+spec:
+  template:
+    metadata: {labels: {app: orders-db}}
+    spec: {containers: [{image: postgres:16}]}
+---
+apiVersion: v1
+kind: Service
+metadata: {name: web, namespace: shop}
+spec: {selector: {app: web}, ports: [{port: 80}]}
+---
+apiVersion: networking.k8s.io/v1
+kind: Ingress
+metadata: {name: shop, namespace: shop}
+spec:
+  tls: [{hosts: [shop.example.com]}]
+  rules: [{http: {paths: [{path: /, backend: {service: {name: web, port: {number: 80}}}}]}}]
+---
+apiVersion: networking.k8s.io/v1
+kind: NetworkPolicy
+metadata: {name: db, namespace: shop}
+spec:
+  podSelector: {matchLabels: {app: orders-db}}
+  ingress: [{from: [{podSelector: {matchLabels: {app: web}}}, {ipBlock: {cidr: 10.0.0.0/8}}], ports: [{port: 5432}]}]
+---
+apiVersion: v1
+kind: List
+items:
+  - apiVersion: apps/v1
+    kind: DaemonSet
+    metadata: {name: log-agent}
+    spec: {template: {metadata: {labels: {app: log-agent}}}}
+  - apiVersion: v1
+    kind: Service
+    metadata: {name: log-agent}
+    spec: {type: LoadBalancer, selector: {app: log-agent}}
+`
+
+func TestAddK8sWorkloads(t *testing.T) {
+	manifests := filepath.Join(t.TempDir(), "shop.yaml")
+	assert.NoError(t, os.WriteFile(manifests, []byte(k8sTestManifests), 0600))
+	parsedModel := &types.Model{
+		TechnicalAssets: map[string]*types.TechnicalAsset{},
+		TrustBoundaries: map[string]*types.TrustBoundary{"namespace-shop": {Id: "namespace-shop", Title: "Namespace shop"}},
+		SharedRuntimes:  map[string]*types.SharedRuntime{},
+	}
+	modelInput := new(input.Model).Defaults()
+	modelInput.TrustBoundaries["Namespace shop"] = input.TrustBoundary{ID: "namespace-shop", TechnicalAssetsInside: []string{"shop-gateway"}}
+
+	macro := NewAddK8sWorkloads()
+	assert.NoError(t, answerMacroQuestions(macro, parsedModel, MacroAnswers{"manifests": {filepath.Join(t.TempDir(), "missing.yaml")}}))
+	_, message, validResult, _ := macro.GetFinalChangeImpact(modelInput, parsedModel)
+	assert.False(t, validResult, message)
+
+	macro = NewAddK8sWorkloads()
+	assert.NoError(t, answerMacroQuestions(macro, parsedModel, MacroAnswers{"manifests": {manifests}}))
+	changes, _, validResult, err := macro.GetFinalChangeImpact(modelInput, parsedModel)
+	assert.NoError(t, err)
+	assert.True(t, validResult)
+	assert.Equal(t, []string{
+		"adding technical asset: web-shop", "adding technical asset: orders-db-shop", "adding technical asset: log-agent",
+		"adding technical asset: ingress-shop-shop", "adding trust boundary: namespace-default",
+		"filling existing trust boundary: namespace-shop", "adding shared runtime: kubernetes-cluster",
+	}, changes)
+	assert.Empty(t, modelInput.TechnicalAssets, "dry run")
+
+	_, validResult, err = macro.Execute(modelInput, parsedModel)
+	assert.NoError(t, err)
+	assert.True(t, validResult)
+	web := modelInput.TechnicalAssets["web (shop)"]
+	assert.Equal(t, "container", web.Machine)
+	assert.False(t, web.Internet)
+	assert.Equal(t, "orders-db-shop", web.CommunicationLinks["Access orders-db (shop)"].Target)
+	assert.Equal(t, "sql-access-protocol", web.CommunicationLinks["Access orders-db (shop)"].Protocol)
+	assert.Len(t, web.CommunicationLinks, 1, "not from the ip block")
+	assert.Equal(t, "datastore", modelInput.TechnicalAssets["orders-db (shop)"].Type)
+	assert.True(t, modelInput.TechnicalAssets["log-agent"].Internet)
+
+	ingress := modelInput.TechnicalAssets["Ingress shop (shop)"]
+	assert.True(t, ingress.Internet)
+	assert.Equal(t, "reverse-proxy", ingress.Technology)
+	assert.Equal(t, "web-shop", ingress.CommunicationLinks["Ingress Traffic to web (shop)"].Target)
+
+	assert.Equal(t, []string{"shop-gateway", "web-shop", "orders-db-shop", "ingress-shop-shop"}, modelInput.TrustBoundaries["Namespace shop"].TechnicalAssetsInside)
+	assert.Equal(t, "network-policy-namespace-isolation", modelInput.TrustBoundaries["Namespace default"].Type)
+	assert.Len(t, modelInput.SharedRuntimes["Kubernetes Cluster"].TechnicalAssetsRunning, 4)
+}
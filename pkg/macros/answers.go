@@ -0,0 +1,139 @@
+package macros
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/threagile/threagile/pkg/common"
+	"github.com/threagile/threagile/pkg/security/types"
+	"gopkg.in/yaml.v3"
+)
+
+// MacroAnswers are the answers of the macro questions (keyed by question ID) for the execution without prompts, e.g.
+// in CI pipelines: a single value or, for questions allowing multiple selected values, a list of values
+type MacroAnswers map[string][]string
+
+// LoadMacroAnswers reads the answers from a yaml (or json) file like
+//
+//	source-repository: GitHub
+//	deploy-targets: [some-server, some-database]
+func LoadMacroAnswers(filename string) (MacroAnswers, error) {
+	data, err := os.ReadFile(filepath.Clean(filename))
+	if err != nil {
+		return nil, fmt.Errorf("unable to read macro answers file %q: %w", filename, err)
+	}
+	var values map[string]any
+	err = yaml.Unmarshal(data, &values)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse macro answers file %q: %w", filename, err)
+	}
+	answers := make(MacroAnswers)
+	for questionID, value := range values {
+		switch value := value.(type) {
+		case []any:
+			answers[questionID] = make([]string, 0, len(value))
+			for _, item := range value {
+				answers[questionID] = append(answers[questionID], fmt.Sprint(item))
+			}
+		case nil:
+			answers[questionID] = []string{}
+		default:
+			answers[questionID] = []string{fmt.Sprint(value)}
+		}
+	}
+	return answers, nil
+}
+
+// answerMacroQuestions applies the given answers to the questions of the macro (falling back to their default answers)
+// and fails on questions without any answer as well as on answers not accepted
+func answerMacroQuestions(macros Macros, parsedModel *types.Model, answers MacroAnswers) error {
+	answered := make(map[string]bool)
+	_, err := applyAnswers(macros, parsedModel, answers, answered, true, func(question MacroQuestion, answer []string) {
+		fmt.Fprintln(common.LogStdout(), question.Title, strings.Join(answer, ", "))
+	})
+	if err != nil {
+		return err
+	}
+
+	unused := make([]string, 0)
+	for questionID := range answers {
+		if !answered[questionID] {
+			unused = append(unused, questionID)
+		}
+	}
+	sort.Strings(unused)
+	for _, questionID := range unused {
+		fmt.Fprintf(common.LogStdout(), "WARNING: answer of question %q not used, as the macro did not ask it\n", questionID)
+	}
+	return nil
+}
+
+// ApplyAnswers applies the given answers to the questions of the macro as they are asked, without falling back to the
+// default answers, and answers the first question left unanswered (or NoMoreQuestions when all are answered); as the
+// macros are stateful, it lets clients without a session (like the server) replay the answers given so far
+func ApplyAnswers(macros Macros, parsedModel *types.Model, answers MacroAnswers) (MacroQuestion, error) {
+	return applyAnswers(macros, parsedModel, answers, make(map[string]bool), false, nil)
+}
+
+// ApplyAnswersOrDefaults applies the given answers to the questions of the macro like ApplyAnswers, but falls back to
+// the default answers and fails on questions without any answer
+func ApplyAnswersOrDefaults(macros Macros, parsedModel *types.Model, answers MacroAnswers) error {
+	_, err := applyAnswers(macros, parsedModel, answers, make(map[string]bool), true, nil)
+	return err
+}
+
+// applyAnswers answers the questions until none is left or (unless falling back to the default answers) the first
+// question without an answer, tracking the questions answered and reporting each answer applied if asked to
+func applyAnswers(macros Macros, parsedModel *types.Model, answers MacroAnswers, answered map[string]bool, useDefaults bool, applied func(question MacroQuestion, answer []string)) (MacroQuestion, error) {
+	for {
+		nextQuestion, err := macros.GetNextQuestion(parsedModel)
+		if err != nil {
+			return nextQuestion, err
+		}
+		if nextQuestion.NoMoreQuestions() {
+			return nextQuestion, nil
+		}
+		if answered[nextQuestion.ID] {
+			return nextQuestion, fmt.Errorf("answer of question %q (%v) not accepted", nextQuestion.ID, nextQuestion.Title)
+		}
+
+		answer, ok := answers[nextQuestion.ID]
+		if !ok {
+			if !useDefaults {
+				return nextQuestion, nil
+			}
+			if len(nextQuestion.DefaultAnswer) == 0 {
+				return nextQuestion, fmt.Errorf("missing answer of question %q (%v)", nextQuestion.ID, nextQuestion.Title)
+			}
+			answer = []string{nextQuestion.DefaultAnswer}
+		}
+		answered[nextQuestion.ID] = true
+		answer = append([]string{}, answer...) // as the values are normalized below
+		if len(answer) > 1 && !nextQuestion.MultiSelect {
+			return nextQuestion, fmt.Errorf("question %q (%v) allows a single answer only", nextQuestion.ID, nextQuestion.Title)
+		}
+		for i, value := range answer {
+			if !nextQuestion.IsMatchingValueConstraint(value) {
+				return nextQuestion, fmt.Errorf("answer %q of question %q does not match any allowed value: %v", value, nextQuestion.ID, strings.Join(nextQuestion.PossibleAnswers, ", "))
+			}
+			for _, possibleAnswer := range nextQuestion.PossibleAnswers {
+				if strings.EqualFold(possibleAnswer, value) {
+					answer[i] = possibleAnswer // as the macros compare the exact values
+				}
+			}
+		}
+		if applied != nil {
+			applied(nextQuestion, answer)
+		}
+		message, validResult, err := macros.ApplyAnswer(nextQuestion.ID, answer...)
+		if err != nil {
+			return nextQuestion, err
+		}
+		if !validResult {
+			return nextQuestion, fmt.Errorf("invalid answer of question %q: %v", nextQuestion.ID, message)
+		}
+	}
+}
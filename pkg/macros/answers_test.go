@@ -0,0 +1,92 @@
+package macros
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+func TestLoadMacroAnswers(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "answers.yaml")
+	assert.NoError(t, os.WriteFile(filename, []byte("vault-name: HashiCorp Vault\nclients: [web-server, api]\nmulti-tenant: false\nnothing:\n"), 0600))
+
+	answers, err := LoadMacroAnswers(filename)
+	assert.NoError(t, err)
+	assert.Equal(t, MacroAnswers{
+		"vault-name":   {"HashiCorp Vault"},
+		"clients":      {"web-server", "api"},
+		"multi-tenant": {"false"},
+		"nothing":      {},
+	}, answers)
+}
+
+func TestAnswerMacroQuestions(t *testing.T) {
+	parsedModel := &types.Model{TechnicalAssets: map[string]*types.TechnicalAsset{"web-server": {Id: "web-server"}, "api": {Id: "api"}}}
+	answers := MacroAnswers{
+		"vault-name":            {"HashiCorp Vault"},
+		"storage-type":          {"in-memory (no persistent storage of secrets)"},
+		"authentication-type":   {authenticationTypes[0]},
+		"clients":               {"web-server", "api"},
+		"within-trust-boundary": {"No"},
+	}
+
+	macro := NewAddVault()
+	assert.NoError(t, answerMacroQuestions(macro, parsedModel, answers))
+	assert.Equal(t, []string{"In-Memory (no persistent storage of secrets)"}, macro.macroState["storage-type"], "answers are matched case-insensitive")
+	assert.Equal(t, []string{"No"}, macro.macroState["multi-tenant"], "default answer of question not answered")
+	assert.Equal(t, []string{"web-server", "api"}, macro.macroState["clients"])
+
+	delete(answers, "authentication-type")
+	assert.ErrorContains(t, answerMacroQuestions(NewAddVault(), parsedModel, answers), `missing answer of question "authentication-type"`)
+
+	answers["authentication-type"] = []string{"Password"}
+	assert.ErrorContains(t, answerMacroQuestions(NewAddVault(), parsedModel, answers), "does not match any allowed value")
+
+	answers["authentication-type"] = authenticationTypes[:2]
+	assert.ErrorContains(t, answerMacroQuestions(NewAddVault(), parsedModel, answers), "allows a single answer only")
+}
+
+func TestApplyAnswers(t *testing.T) {
+	parsedModel := &types.Model{TechnicalAssets: map[string]*types.TechnicalAsset{"web-server": {Id: "web-server"}}}
+	answers := MacroAnswers{
+		"vault-name":   {"HashiCorp Vault"},
+		"storage-type": {"in-memory (no persistent storage of secrets)"},
+	}
+
+	nextQuestion, err := ApplyAnswers(NewAddVault(), parsedModel, answers)
+	assert.NoError(t, err)
+	assert.Equal(t, "authentication-type", nextQuestion.ID, "first question without answer")
+	assert.Equal(t, []string{"in-memory (no persistent storage of secrets)"}, answers["storage-type"], "answers given are not changed")
+
+	answers["authentication-type"] = []string{authenticationTypes[0]}
+	answers["clients"] = []string{"web-server"}
+	answers["within-trust-boundary"] = []string{"No"}
+	answers["multi-tenant"] = []string{"No"}
+	nextQuestion, err = ApplyAnswers(NewAddVault(), parsedModel, answers)
+	assert.NoError(t, err)
+	assert.True(t, nextQuestion.NoMoreQuestions())
+
+	answers["authentication-type"] = []string{"Password"}
+	_, err = ApplyAnswers(NewAddVault(), parsedModel, answers)
+	assert.ErrorContains(t, err, "does not match any allowed value")
+}
+
+func TestDefaultQuestions(t *testing.T) {
+	questions, err := DefaultQuestions(NewBuildPipeline(), new(types.Model))
+	assert.NoError(t, err)
+	assert.Greater(t, len(questions), 3)
+	assert.Equal(t, "source-repository", questions[0].ID)
+	assert.Equal(t, "Git", questions[0].DefaultAnswer)
+
+	for _, macro := range ListBuiltInMacros() {
+		_, err = DefaultQuestions(macro, new(types.Model))
+		assert.NoError(t, err, macro.GetMacroDetails().ID)
+	}
+
+	questions, err = DefaultQuestions(NewAddK8sWorkloads(), new(types.Model))
+	assert.NoError(t, err)
+	assert.Len(t, questions, 1, "no answers applied to local macros")
+}
@@ -13,9 +13,9 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/threagile/threagile/pkg/common"
 	"github.com/threagile/threagile/pkg/input"
 	"github.com/threagile/threagile/pkg/security/types"
-	"gopkg.in/yaml.v3"
 )
 
 type Macros interface {
@@ -31,6 +31,7 @@ func ListBuiltInMacros() []Macros {
 	return []Macros{
 		NewBuildPipeline(),
 		NewAddVault(),
+		NewAddK8sWorkloads(),
 		NewPrettyPrint(),
 		newRemoveUnusedTags(),
 		NewSeedRiskTracking(),
@@ -38,6 +39,28 @@ func ListBuiltInMacros() []Macros {
 	}
 }
 
+// LocalMacros work with files of the machine they run on (like reading the paths answered), so they are available on
+// the command line only
+type LocalMacros interface {
+	IsLocal() bool
+}
+
+func IsLocalMacro(macros Macros) bool {
+	local, ok := macros.(LocalMacros)
+	return ok && local.IsLocal()
+}
+
+// ListServerMacros lists the built-in and custom macros the server offers, which are all but the local ones
+func ListServerMacros() []Macros {
+	serverMacros := make([]Macros, 0)
+	for _, macro := range append(ListBuiltInMacros(), ListCustomMacros()...) {
+		if !IsLocalMacro(macro) {
+			serverMacros = append(serverMacros, macro)
+		}
+	}
+	return serverMacros
+}
+
 func ListCustomMacros() []Macros {
 	// TODO: implement
 	return []Macros{}
@@ -55,7 +78,22 @@ func GetMacroByID(id string) (Macros, error) {
 	return nil, fmt.Errorf("unknown macro id: %v", id)
 }
 
-func ExecuteModelMacro(modelInput *input.Model, inputFile string, parsedModel *types.Model, macroID string) error {
+// GetServerMacroByID is like GetMacroByID, but does not find the local macros
+func GetServerMacroByID(id string) (Macros, error) {
+	macro, err := GetMacroByID(id)
+	if err == nil && IsLocalMacro(macro) {
+		return nil, fmt.Errorf("macro available on the command line only: %v", id)
+	}
+	return macro, err
+}
+
+// ExecuteModelMacro asks the questions of the macro and applies it to the model file when confirmed, or without any
+// prompt if an answers file (see LoadMacroAnswers) is given
+func ExecuteModelMacro(modelInput *input.Model, inputFile string, parsedModel *types.Model, macroID string, answersFile string) error {
+	if evaluator, ok := input.GetModelEvaluator(inputFile); ok {
+		return fmt.Errorf("model macros cannot update %v models, as they are only evaluated; apply the changes to %v manually", evaluator.Name, inputFile)
+	}
+
 	macros, err := GetMacroByID(macroID)
 	if err != nil {
 		return err
@@ -63,39 +101,89 @@ func ExecuteModelMacro(modelInput *input.Model, inputFile string, parsedModel *t
 
 	macroDetails := macros.GetMacroDetails()
 
-	fmt.Println("Executing model macro:", macroDetails.ID)
-	fmt.Println()
-	fmt.Println()
+	fmt.Fprintln(common.LogStdout(), "Executing model macro:", macroDetails.ID)
+	fmt.Fprintln(common.LogStdout())
+	fmt.Fprintln(common.LogStdout())
 	printBorder(len(macroDetails.Title), true)
-	fmt.Println(macroDetails.Title)
+	fmt.Fprintln(common.LogStdout(), macroDetails.Title)
 	printBorder(len(macroDetails.Title), true)
 	if len(macroDetails.Description) > 0 {
-		fmt.Println(macroDetails.Description)
+		fmt.Fprintln(common.LogStdout(), macroDetails.Description)
+	}
+	fmt.Fprintln(common.LogStdout())
+	if len(answersFile) > 0 {
+		answers, err := LoadMacroAnswers(answersFile)
+		if err != nil {
+			return err
+		}
+		err = answerMacroQuestions(macros, parsedModel, answers)
+		if err != nil {
+			return err
+		}
+		err = executeMacroChanges(macros, modelInput, parsedModel)
+		if err != nil {
+			return err
+		}
+	} else {
+		reader := bufio.NewReader(os.Stdin)
+		quit, err := askMacroQuestions(reader, macros, parsedModel)
+		if err != nil || quit {
+			return err
+		}
+
+		applied, err := confirmMacroChanges(reader, macros, modelInput, parsedModel)
+		if err != nil || !applied {
+			return err
+		}
+	}
+
+	backupFilename := inputFile + ".backup"
+	fmt.Fprintln(common.LogStdout(), "Creating backup model file:", backupFilename) // TODO add random files in /dev/shm space?
+	_, err = copyFile(inputFile, backupFilename)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(common.LogStdout(), "Updating model")
+	modelBytes, err := input.Marshal(inputFile, modelInput)
+	if err != nil {
+		return err
+	}
+	/*
+		modelBytes = model.ReformatYAML(modelBytes)
+	*/
+	fmt.Fprintln(common.LogStdout(), "Writing model file:", inputFile)
+	err = os.WriteFile(inputFile, modelBytes, 0400)
+	if err != nil {
+		return err
 	}
-	fmt.Println()
-	reader := bufio.NewReader(os.Stdin)
+	fmt.Fprintln(common.LogStdout(), "Model file successfully updated")
+	return nil
+}
+
+// askMacroQuestions runs the interactive question loop of the given macro and returns whether the user quit
+func askMacroQuestions(reader *bufio.Reader, macros Macros, parsedModel *types.Model) (quit bool, err error) {
 	for {
 		nextQuestion, err := macros.GetNextQuestion(parsedModel)
 		if err != nil {
-			return err
+			return false, err
 		}
 		if nextQuestion.NoMoreQuestions() {
 			break
 		}
-		fmt.Println()
+		fmt.Fprintln(common.LogStdout())
 		printBorder(len(nextQuestion.Title), false)
-		fmt.Println(nextQuestion.Title)
+		fmt.Fprintln(common.LogStdout(), nextQuestion.Title)
 		printBorder(len(nextQuestion.Title), false)
 		if len(nextQuestion.Description) > 0 {
-			fmt.Println(nextQuestion.Description)
+			fmt.Fprintln(common.LogStdout(), nextQuestion.Description)
 		}
 		resultingMultiValueSelection := make([]string, 0)
 		if nextQuestion.IsValueConstrained() {
 			if nextQuestion.MultiSelect {
 				selectedValues := make(map[string]bool)
 				for {
-					fmt.Println("Please select (multiple executions possible) from the following values (use number to select/deselect):")
-					fmt.Println("    0:", "SELECTION PROCESS FINISHED: CONTINUE TO NEXT QUESTION")
+					fmt.Fprintln(common.LogStdout(), "Please select (multiple executions possible) from the following values (use number to select/deselect):")
+					fmt.Fprintln(common.LogStdout(), "    0:", "SELECTION PROCESS FINISHED: CONTINUE TO NEXT QUESTION")
 					for i, val := range nextQuestion.PossibleAnswers {
 						number := i + 1
 						padding, selected := "", " "
@@ -105,15 +193,15 @@ func ExecuteModelMacro(modelInput *input.Model, inputFile string, parsedModel *t
 						if val, exists := selectedValues[val]; exists && val {
 							selected = "*"
 						}
-						fmt.Println(" "+selected+" "+padding+strconv.Itoa(number)+":", val)
+						fmt.Fprintln(common.LogStdout(), " "+selected+" "+padding+strconv.Itoa(number)+":", val)
 					}
-					fmt.Println()
-					fmt.Print("Enter number to select/deselect (or 0 when finished): ")
+					fmt.Fprintln(common.LogStdout())
+					fmt.Fprint(common.LogStdout(), "Enter number to select/deselect (or 0 when finished): ")
 					answer, err := reader.ReadString('\n')
 					// convert CRLF to LF
 					answer = strings.TrimSpace(strings.Replace(answer, "\n", "", -1))
 					if err != nil {
-						return err
+						return false, err
 					}
 					if val, err := strconv.Atoi(answer); err == nil { // flip selection
 						if val == 0 {
@@ -129,32 +217,32 @@ func ExecuteModelMacro(modelInput *input.Model, inputFile string, parsedModel *t
 					}
 				}
 			} else {
-				fmt.Println("Please choose from the following values (enter value directly or use number):")
+				fmt.Fprintln(common.LogStdout(), "Please choose from the following values (enter value directly or use number):")
 				for i, val := range nextQuestion.PossibleAnswers {
 					number := i + 1
 					padding := ""
 					if number < 10 {
 						padding = " "
 					}
-					fmt.Println("   "+padding+strconv.Itoa(number)+":", val)
+					fmt.Fprintln(common.LogStdout(), "   "+padding+strconv.Itoa(number)+":", val)
 				}
 			}
 		}
 		message := ""
 		validResult := true
 		if !nextQuestion.IsValueConstrained() || !nextQuestion.MultiSelect {
-			fmt.Println()
-			fmt.Println("Enter your answer (use 'BACK' to go one step back or 'QUIT' to quit without executing the model macro)")
-			fmt.Print("Answer")
+			fmt.Fprintln(common.LogStdout())
+			fmt.Fprintln(common.LogStdout(), "Enter your answer (use 'BACK' to go one step back or 'QUIT' to quit without executing the model macro)")
+			fmt.Fprint(common.LogStdout(), "Answer")
 			if len(nextQuestion.DefaultAnswer) > 0 {
-				fmt.Print(" (default '" + nextQuestion.DefaultAnswer + "')")
+				fmt.Fprint(common.LogStdout(), " (default '"+nextQuestion.DefaultAnswer+"')")
 			}
-			fmt.Print(": ")
+			fmt.Fprint(common.LogStdout(), ": ")
 			answer, err := reader.ReadString('\n')
 			// convert CRLF to LF
 			answer = strings.TrimSpace(strings.Replace(answer, "\n", "", -1))
 			if err != nil {
-				return err
+				return false, err
 			}
 			if len(answer) == 0 && len(nextQuestion.DefaultAnswer) > 0 { // accepting the default
 				answer = nextQuestion.DefaultAnswer
@@ -166,16 +254,16 @@ func ExecuteModelMacro(modelInput *input.Model, inputFile string, parsedModel *t
 				}
 			}
 			if strings.ToLower(answer) == "quit" {
-				fmt.Println("Quitting without executing the model macro")
-				return nil
+				fmt.Fprintln(common.LogStdout(), "Quitting without executing the model macro")
+				return true, nil
 			} else if strings.ToLower(answer) == "back" {
 				message, validResult, _ = macros.GoBack()
 			} else if len(answer) > 0 { // individual answer
 				if nextQuestion.IsValueConstrained() {
 					if !nextQuestion.IsMatchingValueConstraint(answer) {
-						fmt.Println()
-						fmt.Println(">>> INVALID <<<")
-						fmt.Println("Answer does not match any allowed value. Please try again:")
+						fmt.Fprintln(common.LogStdout())
+						fmt.Fprintln(common.LogStdout(), ">>> INVALID <<<")
+						fmt.Fprintln(common.LogStdout(), "Answer does not match any allowed value. Please try again:")
 						continue
 					}
 				}
@@ -185,97 +273,109 @@ func ExecuteModelMacro(modelInput *input.Model, inputFile string, parsedModel *t
 			message, validResult, _ = macros.ApplyAnswer(nextQuestion.ID, resultingMultiValueSelection...)
 		}
 		if err != nil {
-			return err
+			return false, err
 		}
 		if !validResult {
-			fmt.Println()
-			fmt.Println(">>> INVALID <<<")
+			fmt.Fprintln(common.LogStdout())
+			fmt.Fprintln(common.LogStdout(), ">>> INVALID <<<")
 		}
-		fmt.Println(message)
-		fmt.Println()
+		fmt.Fprintln(common.LogStdout(), message)
+		fmt.Fprintln(common.LogStdout())
 	}
+	return false, nil
+}
+
+// confirmMacroChanges shows the changes of the given macro and executes it on the model input when confirmed by the user
+func confirmMacroChanges(reader *bufio.Reader, macros Macros, modelInput *input.Model, parsedModel *types.Model) (applied bool, err error) {
 	for {
-		fmt.Println()
-		fmt.Println()
-		fmt.Println("#################################################################")
-		fmt.Println("Do you want to execute the model macro (updating the model file)?")
-		fmt.Println("#################################################################")
-		fmt.Println()
-		fmt.Println("The following changes will be applied:")
+		fmt.Fprintln(common.LogStdout())
+		fmt.Fprintln(common.LogStdout())
+		fmt.Fprintln(common.LogStdout(), "#################################################################")
+		fmt.Fprintln(common.LogStdout(), "Do you want to execute the model macro (updating the model file)?")
+		fmt.Fprintln(common.LogStdout(), "#################################################################")
+		fmt.Fprintln(common.LogStdout())
+		fmt.Fprintln(common.LogStdout(), "The following changes will be applied:")
 		var changes []string
 		message := ""
 
 		changes, message, validResult, err := macros.GetFinalChangeImpact(modelInput, parsedModel)
 		if err != nil {
-			return err
+			return false, err
 		}
 		for _, change := range changes {
-			fmt.Println(" -", change)
+			fmt.Fprintln(common.LogStdout(), " -", change)
 		}
 		if !validResult {
-			fmt.Println()
-			fmt.Println(">>> INVALID <<<")
+			fmt.Fprintln(common.LogStdout())
+			fmt.Fprintln(common.LogStdout(), ">>> INVALID <<<")
 		}
-		fmt.Println()
-		fmt.Println(message)
-		fmt.Println()
-		fmt.Print("Apply these changes to the model file?\nType Yes or No: ")
+		fmt.Fprintln(common.LogStdout())
+		fmt.Fprintln(common.LogStdout(), message)
+		fmt.Fprintln(common.LogStdout())
+		fmt.Fprint(common.LogStdout(), "Apply these changes to the model file?\nType Yes or No: ")
 		answer, err := reader.ReadString('\n')
 		// convert CRLF to LF
 		answer = strings.TrimSpace(strings.Replace(answer, "\n", "", -1))
 		if err != nil {
-			return err
+			return false, err
 		}
 		answer = strings.ToLower(answer)
-		fmt.Println()
+		fmt.Fprintln(common.LogStdout())
 		if answer == "yes" || answer == "y" {
 			message, validResult, err = macros.Execute(modelInput, parsedModel)
 			if err != nil {
-				return err
+				return false, err
 			}
 			if !validResult {
-				fmt.Println()
-				fmt.Println(">>> INVALID <<<")
-			}
-			fmt.Println(message)
-			fmt.Println()
-			backupFilename := inputFile + ".backup"
-			fmt.Println("Creating backup model file:", backupFilename) // TODO add random files in /dev/shm space?
-			_, err = copyFile(inputFile, backupFilename)
-			if err != nil {
-				return err
+				fmt.Fprintln(common.LogStdout())
+				fmt.Fprintln(common.LogStdout(), ">>> INVALID <<<")
 			}
-			fmt.Println("Updating model")
-			yamlBytes, err := yaml.Marshal(modelInput)
-			if err != nil {
-				return err
-			}
-			/*
-				yamlBytes = model.ReformatYAML(yamlBytes)
-			*/
-			fmt.Println("Writing model file:", inputFile)
-			err = os.WriteFile(inputFile, yamlBytes, 0400)
-			if err != nil {
-				return err
-			}
-			fmt.Println("Model file successfully updated")
-			return nil
+			fmt.Fprintln(common.LogStdout(), message)
+			fmt.Fprintln(common.LogStdout())
+			return true, nil
 		} else if answer == "no" || answer == "n" {
-			fmt.Println("Quitting without executing the model macro")
-			return nil
+			fmt.Fprintln(common.LogStdout(), "Quitting without executing the model macro")
+			return false, nil
 		}
 	}
 }
 
+// executeMacroChanges shows the changes of the given macro and executes it on the model input without confirmation
+func executeMacroChanges(macros Macros, modelInput *input.Model, parsedModel *types.Model) error {
+	fmt.Fprintln(common.LogStdout())
+	fmt.Fprintln(common.LogStdout(), "The following changes will be applied:")
+	changes, message, validResult, err := macros.GetFinalChangeImpact(modelInput, parsedModel)
+	if err != nil {
+		return err
+	}
+	for _, change := range changes {
+		fmt.Fprintln(common.LogStdout(), " -", change)
+	}
+	if !validResult {
+		return fmt.Errorf("invalid changes of model macro: %v", message)
+	}
+	fmt.Fprintln(common.LogStdout())
+	message, validResult, err = macros.Execute(modelInput, parsedModel)
+	if err != nil {
+		return err
+	}
+	if !validResult {
+		return fmt.Errorf("model macro failed: %v", message)
+	}
+	fmt.Fprintln(common.LogStdout(), message)
+	fmt.Fprintln(common.LogStdout())
+	return nil
+}
+
 func printBorder(length int, bold bool) {
 	char := "-"
 	if bold {
 		char = "="
 	}
 	for i := 1; i <= length; i++ {
-		fmt.Print(char)
+		fmt.Fprint(common.LogStdout(), char)
 	}
-	fmt.Println()
+	fmt.Fprintln(common.LogStdout())
 }
 
 func copyFile(src, dst string) (int64, error) {
@@ -331,6 +431,38 @@ func (what MacroQuestion) NoMoreQuestions() bool {
 	return what.ID == NoMoreQuestionsID
 }
 
+// DefaultQuestions lists the questions of the macro as asked when answering each of them with its default answer (or
+// the first possible answer), as the following questions depend on the answers given and the model the macro is applied
+// to; it uses the state of the macro, so pass a new one. Local macros get no answers applied (which may read files), so
+// only their first question is listed.
+func DefaultQuestions(macros Macros, parsedModel *types.Model) ([]MacroQuestion, error) {
+	questions := make([]MacroQuestion, 0)
+	asked := make(map[string]bool)
+	for {
+		nextQuestion, err := macros.GetNextQuestion(parsedModel)
+		if err != nil {
+			return questions, err
+		}
+		if nextQuestion.NoMoreQuestions() || asked[nextQuestion.ID] {
+			return questions, nil
+		}
+		asked[nextQuestion.ID] = true
+		questions = append(questions, nextQuestion)
+		if IsLocalMacro(macros) {
+			return questions, nil
+		}
+
+		answer := nextQuestion.DefaultAnswer
+		if len(answer) == 0 && nextQuestion.IsValueConstrained() {
+			answer = nextQuestion.PossibleAnswers[0]
+		}
+		_, validResult, err := macros.ApplyAnswer(nextQuestion.ID, answer)
+		if err != nil || !validResult {
+			return questions, err
+		}
+	}
+}
+
 func (what MacroQuestion) IsValueConstrained() bool {
 	return what.PossibleAnswers != nil && len(what.PossibleAnswers) > 0
 }
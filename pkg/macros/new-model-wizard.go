@@ -0,0 +1,331 @@
+package macros
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/threagile/threagile/pkg/common"
+	"github.com/threagile/threagile/pkg/docs"
+	"github.com/threagile/threagile/pkg/input"
+	"github.com/threagile/threagile/pkg/security/types"
+	"gopkg.in/yaml.v3"
+)
+
+// NewModelWizard asks some basic questions about the system and creates an initial model from the answers.
+// It is driven by the same question loop as the model macros, but fills an empty model instead of an existing one.
+type NewModelWizard struct {
+	macroState                map[string][]string
+	questionsAnswered         []string
+	withinTrustBoundary       bool
+	technicalAssetTitles      []string
+	internetFacingAssetTitles []string
+}
+
+func NewNewModelWizard() *NewModelWizard {
+	return &NewModelWizard{
+		macroState:        make(map[string][]string),
+		questionsAnswered: make([]string, 0),
+	}
+}
+
+func (m *NewModelWizard) GetMacroDetails() MacroDetails {
+	return MacroDetails{
+		ID:          "new-model",
+		Title:       "Create New Model",
+		Description: "This wizard creates an initial model with technical assets, data assets and a trust boundary.",
+	}
+}
+
+func (m *NewModelWizard) GetNextQuestion(_ *types.Model) (nextQuestion MacroQuestion, err error) {
+	counter := len(m.questionsAnswered)
+	if counter > 6 && !m.withinTrustBoundary {
+		counter++
+	}
+	switch counter {
+	case 0:
+		return MacroQuestion{
+			ID:              "title",
+			Title:           "What is the name of the system to model?",
+			Description:     "This is used as the title of the model and the report.",
+			PossibleAnswers: nil,
+			MultiSelect:     false,
+			DefaultAnswer:   "",
+		}, nil
+	case 1:
+		return MacroQuestion{
+			ID:              "author",
+			Title:           "Who is the author of the model?",
+			Description:     "",
+			PossibleAnswers: nil,
+			MultiSelect:     false,
+			DefaultAnswer:   "",
+		}, nil
+	case 2:
+		return MacroQuestion{
+			ID:              "business-criticality",
+			Title:           "How critical is the system for the business?",
+			Description:     "",
+			PossibleAnswers: enumValues(types.CriticalityValues()),
+			MultiSelect:     false,
+			DefaultAnswer:   types.Important.String(),
+		}, nil
+	case 3:
+		return MacroQuestion{
+			ID:              "technical-assets",
+			Title:           "Which technical assets (components) does the system consist of?",
+			Description:     "Enter a comma-separated list of titles, like 'Web Frontend, Backend API, Customer Database'.",
+			PossibleAnswers: nil,
+			MultiSelect:     false,
+			DefaultAnswer:   "",
+		}, nil
+	case 4:
+		return MacroQuestion{
+			ID:              "data-assets",
+			Title:           "Which data assets are processed by the system?",
+			Description:     "Enter a comma-separated list of titles, like 'Customer Addresses, Payment Details'.",
+			PossibleAnswers: nil,
+			MultiSelect:     false,
+			DefaultAnswer:   "",
+		}, nil
+	case 5:
+		return MacroQuestion{
+			ID:              "internet-facing",
+			Title:           "Select all technical assets that are directly accessible from the internet:",
+			Description:     "",
+			PossibleAnswers: m.technicalAssetTitles,
+			MultiSelect:     true,
+			DefaultAnswer:   "",
+		}, nil
+	case 6:
+		return MacroQuestion{
+			ID:              "within-trust-boundary",
+			Title:           "Are the technical assets placed within a network trust boundary?",
+			Description:     "",
+			PossibleAnswers: []string{"Yes", "No"},
+			MultiSelect:     false,
+			DefaultAnswer:   "Yes",
+		}, nil
+	case 7:
+		return MacroQuestion{
+			ID:          "trust-boundary-type",
+			Title:       "Of which type shall the trust boundary be?",
+			Description: "",
+			PossibleAnswers: []string{types.NetworkOnPrem.String(),
+				types.NetworkDedicatedHoster.String(),
+				types.NetworkVirtualLAN.String(),
+				types.NetworkCloudProvider.String(),
+				types.NetworkCloudSecurityGroup.String(),
+				types.NetworkPolicyNamespaceIsolation.String()},
+			MultiSelect:   false,
+			DefaultAnswer: types.NetworkCloudProvider.String(),
+		}, nil
+	}
+	return NoMoreQuestions(), nil
+}
+
+func (m *NewModelWizard) ApplyAnswer(questionID string, answer ...string) (message string, validResult bool, err error) {
+	switch questionID {
+	case "title":
+		if len(answer) == 0 || len(strings.TrimSpace(answer[0])) == 0 {
+			return "Please enter a title", false, nil
+		}
+	case "technical-assets":
+		titles := splitList(answer)
+		if len(titles) == 0 {
+			return "Please enter at least one technical asset", false, nil
+		}
+		m.technicalAssetTitles = titles
+	case "internet-facing":
+		m.internetFacingAssetTitles = answer
+	case "within-trust-boundary":
+		m.withinTrustBoundary = len(answer) > 0 && strings.EqualFold(answer[0], "yes")
+	}
+
+	m.macroState[questionID] = answer
+	m.questionsAnswered = append(m.questionsAnswered, questionID)
+	return "Answer processed", true, nil
+}
+
+func (m *NewModelWizard) GoBack() (message string, validResult bool, err error) {
+	if len(m.questionsAnswered) == 0 {
+		return "Cannot go back further", false, nil
+	}
+	lastQuestionID := m.questionsAnswered[len(m.questionsAnswered)-1]
+	m.questionsAnswered = m.questionsAnswered[:len(m.questionsAnswered)-1]
+	delete(m.macroState, lastQuestionID)
+	switch lastQuestionID {
+	case "technical-assets":
+		m.technicalAssetTitles = nil
+	case "internet-facing":
+		m.internetFacingAssetTitles = nil
+	case "within-trust-boundary":
+		m.withinTrustBoundary = false
+	}
+	return "Undo successful", true, nil
+}
+
+func (m *NewModelWizard) GetFinalChangeImpact(modelInput *input.Model, parsedModel *types.Model) (changes []string, message string, validResult bool, err error) {
+	changeLogCollector := make([]string, 0)
+	message, validResult, err = m.applyChange(modelInput, parsedModel, &changeLogCollector, true)
+	return changeLogCollector, message, validResult, err
+}
+
+func (m *NewModelWizard) Execute(modelInput *input.Model, parsedModel *types.Model) (message string, validResult bool, err error) {
+	changeLogCollector := make([]string, 0)
+	message, validResult, err = m.applyChange(modelInput, parsedModel, &changeLogCollector, false)
+	return message, validResult, err
+}
+
+func (m *NewModelWizard) applyChange(modelInput *input.Model, _ *types.Model, changeLogCollector *[]string, dryRun bool) (message string, validResult bool, err error) {
+	title := m.answer("title")
+	*changeLogCollector = append(*changeLogCollector, "setting title: "+title)
+	if !dryRun {
+		modelInput.ThreagileVersion = docs.ThreagileVersion
+		modelInput.Title = title
+		modelInput.Author = input.Author{Name: m.answer("author")}
+		modelInput.Date = time.Now().Format("2006-01-02")
+		modelInput.BusinessCriticality = m.answer("business-criticality")
+		modelInput.AppDescription = input.Overview{Description: "Description of " + title}
+	}
+
+	dataAssetIds := make([]string, 0)
+	for _, dataAssetTitle := range splitList(m.macroState["data-assets"]) {
+		id := types.MakeID(dataAssetTitle)
+		dataAssetIds = append(dataAssetIds, id)
+		*changeLogCollector = append(*changeLogCollector, "adding data asset: "+id)
+		if !dryRun {
+			modelInput.DataAssets[dataAssetTitle] = input.DataAsset{
+				ID:                     id,
+				Description:            dataAssetTitle,
+				Usage:                  types.Business.String(),
+				Quantity:               types.Few.String(),
+				Confidentiality:        types.Internal.String(),
+				Integrity:              types.Operational.String(),
+				Availability:           types.Operational.String(),
+				JustificationCiaRating: "TODO: rate the data asset",
+			}
+		}
+	}
+
+	technicalAssetIds := make([]string, 0)
+	for _, technicalAssetTitle := range m.technicalAssetTitles {
+		id := types.MakeID(technicalAssetTitle)
+		technicalAssetIds = append(technicalAssetIds, id)
+		*changeLogCollector = append(*changeLogCollector, "adding technical asset: "+id)
+		if !dryRun {
+			modelInput.TechnicalAssets[technicalAssetTitle] = input.TechnicalAsset{
+				ID:                     id,
+				Description:            technicalAssetTitle,
+				Type:                   types.Process.String(),
+				Usage:                  types.Business.String(),
+				Size:                   types.Service.String(),
+				Technology:             types.UnknownTechnology,
+				Internet:               contains(m.internetFacingAssetTitles, technicalAssetTitle),
+				Machine:                types.Virtual.String(),
+				Encryption:             types.NoneEncryption.String(),
+				Confidentiality:        types.Internal.String(),
+				Integrity:              types.Operational.String(),
+				Availability:           types.Operational.String(),
+				JustificationCiaRating: "TODO: rate the technical asset",
+				CustomDevelopedParts:   true,
+				DataAssetsProcessed:    dataAssetIds,
+			}
+		}
+	}
+
+	if m.withinTrustBoundary {
+		*changeLogCollector = append(*changeLogCollector, "adding trust boundary: network")
+		if !dryRun {
+			modelInput.TrustBoundaries["Network"] = input.TrustBoundary{
+				ID:                    "network",
+				Description:           "Network",
+				Type:                  m.answer("trust-boundary-type"),
+				TechnicalAssetsInside: technicalAssetIds,
+			}
+		}
+	}
+
+	return "Changes for the new model successfully applied; please refine assets, communication links and ratings by editing the model file", true, nil
+}
+
+func (m *NewModelWizard) answer(questionID string) string {
+	if values := m.macroState[questionID]; len(values) > 0 {
+		return strings.TrimSpace(values[0])
+	}
+	return ""
+}
+
+func splitList(answer []string) []string {
+	items := make([]string, 0)
+	for _, value := range answer {
+		for _, item := range strings.Split(value, ",") {
+			item = strings.TrimSpace(item)
+			if len(item) > 0 && !contains(items, item) {
+				items = append(items, item)
+			}
+		}
+	}
+	return items
+}
+
+func enumValues(values []types.TypeEnum) []string {
+	result := make([]string, 0, len(values))
+	for _, value := range values {
+		result = append(result, value.String())
+	}
+	return result
+}
+
+func contains(values []string, value string) bool {
+	for _, item := range values {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
+
+// CreateModelFile interactively asks about the system via the new model wizard and writes the resulting initial model
+func CreateModelFile(outputFile string) error {
+	if _, err := os.Stat(outputFile); err == nil {
+		return fmt.Errorf("model file %q already exists", outputFile)
+	}
+
+	wizard := NewNewModelWizard()
+	wizardDetails := wizard.GetMacroDetails()
+	fmt.Fprintln(common.LogStdout())
+	printBorder(len(wizardDetails.Title), true)
+	fmt.Fprintln(common.LogStdout(), wizardDetails.Title)
+	printBorder(len(wizardDetails.Title), true)
+	fmt.Fprintln(common.LogStdout(), wizardDetails.Description)
+	fmt.Fprintln(common.LogStdout())
+
+	modelInput := new(input.Model).Defaults()
+	parsedModel := new(types.Model)
+	reader := bufio.NewReader(os.Stdin)
+	quit, err := askMacroQuestions(reader, wizard, parsedModel)
+	if err != nil || quit {
+		return err
+	}
+
+	applied, err := confirmMacroChanges(reader, wizard, modelInput, parsedModel)
+	if err != nil || !applied {
+		return err
+	}
+
+	yamlBytes, err := yaml.Marshal(modelInput)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(common.LogStdout(), "Writing model file:", outputFile)
+	err = os.WriteFile(filepath.Clean(outputFile), yamlBytes, 0600)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(common.LogStdout(), "Model file successfully created")
+	return nil
+}
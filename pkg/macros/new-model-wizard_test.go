@@ -0,0 +1,113 @@
+package macros
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/threagile/threagile/pkg/common"
+	"github.com/threagile/threagile/pkg/input"
+	"github.com/threagile/threagile/pkg/model"
+	"github.com/threagile/threagile/pkg/security/types"
+	"gopkg.in/yaml.v3"
+)
+
+// answerWizard answers the questions of the wizard in order, checking that they are the expected ones
+func answerWizard(t *testing.T, wizard *NewModelWizard, answers ...[]string) {
+	for _, answer := range answers {
+		question, err := wizard.GetNextQuestion(nil)
+		assert.NoError(t, err)
+		_, ok, err := wizard.ApplyAnswer(question.ID, answer...)
+		assert.NoError(t, err)
+		assert.True(t, ok, question.ID)
+	}
+}
+
+// writtenWizardModel answers the model the wizard writes, as read again from its yaml
+func writtenWizardModel(t *testing.T, wizard *NewModelWizard) *input.Model {
+	modelInput := new(input.Model).Defaults()
+	_, ok, err := wizard.Execute(modelInput, new(types.Model))
+	assert.NoError(t, err)
+	assert.True(t, ok)
+
+	yamlBytes, err := yaml.Marshal(modelInput)
+	assert.NoError(t, err)
+	written := new(input.Model).Defaults()
+	assert.NoError(t, yaml.Unmarshal(yamlBytes, written))
+	return written
+}
+
+func TestNewModelWizardWithoutTrustBoundary(t *testing.T) {
+	wizard := NewNewModelWizard()
+	answerWizard(t, wizard, []string{"Shop"}, []string{"Alice"}, []string{types.Important.String()}, []string{"Web Frontend, Database"})
+
+	question, err := wizard.GetNextQuestion(nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "data-assets", question.ID)
+	answerWizard(t, wizard, []string{"Customer Addresses"})
+
+	question, err = wizard.GetNextQuestion(nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "internet-facing", question.ID)
+	assert.Equal(t, []string{"Web Frontend", "Database"}, question.PossibleAnswers, "the technical assets answered before")
+	answerWizard(t, wizard, []string{"Web Frontend"}, []string{"No"})
+
+	question, err = wizard.GetNextQuestion(nil)
+	assert.NoError(t, err)
+	assert.True(t, question.NoMoreQuestions(), "the trust boundary type skipped")
+
+	modelInput := writtenWizardModel(t, wizard)
+	assert.Equal(t, "Shop", modelInput.Title)
+	assert.Empty(t, modelInput.TrustBoundaries)
+	assert.True(t, modelInput.TechnicalAssets["Web Frontend"].Internet)
+	assert.False(t, modelInput.TechnicalAssets["Database"].Internet)
+
+	parsedModel, err := model.ParseModel(&common.Config{}, modelInput, make(types.RiskRules), make(types.RiskRules))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"customer-addresses"}, parsedModel.TechnicalAssets["web-frontend"].DataAssetsProcessed)
+}
+
+func TestNewModelWizardGoBack(t *testing.T) {
+	wizard := NewNewModelWizard()
+	answerWizard(t, wizard, []string{"Shop"}, []string{"Alice"}, []string{types.Important.String()}, []string{"Web Frontend, Database"},
+		[]string{"Customer Addresses"}, []string{"Web Frontend"}, []string{"No"})
+
+	for _, questionID := range []string{"within-trust-boundary", "internet-facing", "data-assets", "technical-assets"} {
+		assert.Equal(t, questionID, wizard.questionsAnswered[len(wizard.questionsAnswered)-1])
+		_, ok, err := wizard.GoBack()
+		assert.NoError(t, err)
+		assert.True(t, ok)
+	}
+	assert.Empty(t, wizard.technicalAssetTitles, "undone with their question")
+	assert.Empty(t, wizard.internetFacingAssetTitles, "undone with their question")
+
+	question, err := wizard.GetNextQuestion(nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "technical-assets", question.ID)
+	answerWizard(t, wizard, []string{"Backend API"}, nil, nil, []string{"Yes"})
+
+	question, err = wizard.GetNextQuestion(nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "trust-boundary-type", question.ID)
+	answerWizard(t, wizard, []string{types.NetworkCloudProvider.String()})
+	question, err = wizard.GetNextQuestion(nil)
+	assert.NoError(t, err)
+	assert.True(t, question.NoMoreQuestions())
+
+	modelInput := writtenWizardModel(t, wizard)
+	assert.Len(t, modelInput.TechnicalAssets, 1)
+	assert.False(t, modelInput.TechnicalAssets["Backend API"].Internet)
+	assert.Equal(t, []string{"backend-api"}, modelInput.TrustBoundaries["Network"].TechnicalAssetsInside)
+
+	parsedModel, err := model.ParseModel(&common.Config{}, modelInput, make(types.RiskRules), make(types.RiskRules))
+	assert.NoError(t, err)
+	assert.Equal(t, types.NetworkCloudProvider, parsedModel.TrustBoundaries["network"].Type)
+
+	for len(wizard.questionsAnswered) > 0 {
+		_, ok, err := wizard.GoBack()
+		assert.NoError(t, err)
+		assert.True(t, ok)
+	}
+	_, ok, err := wizard.GoBack()
+	assert.NoError(t, err)
+	assert.False(t, ok, "cannot go back further")
+}
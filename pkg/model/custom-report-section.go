@@ -0,0 +1,70 @@
+package model
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+// CustomReportSection is a report section provided by a plugin, which answers "-get-info" with its title and
+// "-generate-paragraphs" (receiving the parsed model) with the paragraphs of the section
+type CustomReportSection struct {
+	SectionTitle string `json:"title" yaml:"title"`
+
+	runner *runner
+	ctx    context.Context // the section interface has no context, so the one of loading the section is used for its runs
+}
+
+func (what *CustomReportSection) Title() string {
+	return what.SectionTitle
+}
+
+func (what *CustomReportSection) GenerateParagraphs(parsedModel *types.Model) ([]*types.ReportParagraph, error) {
+	if what.runner == nil {
+		return nil, nil
+	}
+
+	paragraphs := make([]*types.ReportParagraph, 0)
+	runError := what.runner.Run(what.ctx, parsedModel, &paragraphs, "-generate-paragraphs")
+	if runError != nil {
+		return nil, fmt.Errorf("failed to generate paragraphs for custom report section %q: %v", what.runner.Filename, runError)
+	}
+
+	return paragraphs, nil
+}
+
+func LoadCustomReportSections(ctx context.Context, pluginFiles []string, timeoutSeconds int, reporter types.ProgressReporter) []types.ReportSection {
+	customReportSections := make([]types.ReportSection, 0)
+	for _, pluginFile := range pluginFiles {
+		if len(pluginFile) == 0 {
+			continue
+		}
+
+		newRunner, loadError := new(runner).Load(pluginFile, timeoutSeconds)
+		if loadError != nil {
+			reporter.Error(fmt.Sprintf("WARNING: Custom report section %q not loaded: %v\n", pluginFile, loadError))
+			continue
+		}
+
+		section := new(CustomReportSection)
+		runError := newRunner.Run(ctx, nil, &section, "-get-info")
+		if runError != nil {
+			reporter.Error(fmt.Sprintf("WARNING: Failed to get info for custom report section %q: %v\n", pluginFile, runError))
+			continue
+		}
+
+		if len(strings.TrimSpace(section.SectionTitle)) == 0 {
+			reporter.Error(fmt.Sprintf("WARNING: Custom report section %q has no title\n", pluginFile))
+			continue
+		}
+
+		section.runner = newRunner
+		section.ctx = ctx
+		customReportSections = append(customReportSections, section)
+		reporter.Info("Custom report section loaded:", section.SectionTitle)
+	}
+
+	return customReportSections
+}
@@ -0,0 +1,68 @@
+package model
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
+
+	"github.com/threagile/threagile/pkg/common"
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+// TestMain lets the test binary act as the plugin THREAGILE_TEST_PLUGIN tells, as plugins are run with their
+// request as only argument (if any)
+func TestMain(m *testing.M) {
+	if plugin := os.Getenv("THREAGILE_TEST_PLUGIN"); len(plugin) > 0 {
+		os.Exit(runTestPlugin(plugin, strings.Join(os.Args[1:], " ")))
+	}
+	os.Exit(m.Run())
+}
+
+func runTestPlugin(plugin string, request string) int {
+	switch strings.TrimSpace(plugin + " " + request) {
+	case "report-section -get-info":
+		fmt.Println("title: Internal Controls")
+	case "report-section -generate-paragraphs":
+		modelData, _ := io.ReadAll(os.Stdin)
+		var parsedModel types.Model
+		if err := yaml.Unmarshal(modelData, &parsedModel); err != nil {
+			_, _ = fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		fmt.Printf("- headline: Controls\n  text: Mapped for <b>%v</b>\n- text: No exceptions\n", parsedModel.Title)
+	case "untitled-report-section -get-info":
+		fmt.Println("title: ' '")
+	case "owner-directory":
+		fmt.Println("Team Shop: {name: Alice, email: alice@example.com}\nTeam Legacy: {name: Bob, active: false}")
+	default:
+		_, _ = fmt.Fprintln(os.Stderr, "unsupported request", request)
+		return 2
+	}
+	return 0
+}
+
+func TestLoadCustomReportSections(t *testing.T) {
+	plugin := os.Args[0]
+	missing := filepath.Join(t.TempDir(), "missing-section")
+
+	t.Setenv("THREAGILE_TEST_PLUGIN", "report-section")
+	sections := LoadCustomReportSections(context.Background(), []string{"", missing, plugin}, 10, common.DefaultProgressReporter{SuppressError: true})
+	assert.Len(t, sections, 1, "the missing plugin skipped")
+	assert.Equal(t, "Internal Controls", sections[0].Title())
+
+	paragraphs, err := sections[0].GenerateParagraphs(&types.Model{Title: "Shop"})
+	assert.NoError(t, err)
+	assert.Equal(t, []*types.ReportParagraph{{Headline: "Controls", Text: "Mapped for <b>Shop</b>"}, {Text: "No exceptions"}}, paragraphs)
+
+	t.Setenv("THREAGILE_TEST_PLUGIN", "untitled-report-section")
+	assert.Empty(t, LoadCustomReportSections(context.Background(), []string{plugin}, 10, common.DefaultProgressReporter{SuppressError: true}), "sections without title skipped")
+	_, err = sections[0].GenerateParagraphs(&types.Model{Title: "Shop"})
+	assert.ErrorContains(t, err, "unsupported request -generate-paragraphs")
+}
@@ -1,6 +1,7 @@
 package model
 
 import (
+	"context"
 	"fmt"
 	"strings"
 
@@ -12,6 +13,7 @@ type CustomRiskCategory struct {
 
 	Tags   []string `json:"tags,omitempty" yaml:"tags,omitempty"`
 	runner *runner
+	ctx    context.Context // the rule interface has no context, so the one of loading the rule is used for its runs
 }
 
 func (what *CustomRiskCategory) Init(category *types.RiskCategory, tags []string) *CustomRiskCategory {
@@ -37,7 +39,7 @@ func (what *CustomRiskCategory) GenerateRisks(parsedModel *types.Model) ([]*type
 	}
 
 	generatedRisks := make([]*types.Risk, 0)
-	runError := what.runner.Run(parsedModel, &generatedRisks, "-generate-risks")
+	runError := what.runner.Run(what.ctx, parsedModel, &generatedRisks, "-generate-risks")
 	if runError != nil {
 		return nil, fmt.Errorf("Failed to generate risks for custom risk rule %q: %v\n", what.runner.Filename, runError)
 	}
@@ -45,7 +47,7 @@ func (what *CustomRiskCategory) GenerateRisks(parsedModel *types.Model) ([]*type
 	return generatedRisks, nil
 }
 
-func LoadCustomRiskRules(pluginFiles []string, reporter types.ProgressReporter) types.RiskRules {
+func LoadCustomRiskRules(ctx context.Context, pluginFiles []string, timeoutSeconds int, reporter types.ProgressReporter) types.RiskRules {
 	customRiskRuleList := make([]string, 0)
 	customRiskRules := make(types.RiskRules)
 	if len(pluginFiles) > 0 {
@@ -53,18 +55,19 @@ func LoadCustomRiskRules(pluginFiles []string, reporter types.ProgressReporter)
 
 		for _, pluginFile := range pluginFiles {
 			if len(pluginFile) > 0 {
-				newRunner, loadError := new(runner).Load(pluginFile)
+				newRunner, loadError := new(runner).Load(pluginFile, timeoutSeconds)
 				if loadError != nil {
 					reporter.Error(fmt.Sprintf("WARNING: Custom risk rule %q not loaded: %v\n", pluginFile, loadError))
 				}
 
 				risk := new(CustomRiskCategory)
-				runError := newRunner.Run(nil, &risk, "-get-info")
+				runError := newRunner.Run(ctx, nil, &risk, "-get-info")
 				if runError != nil {
 					reporter.Error(fmt.Sprintf("WARNING: Failed to get info for custom risk rule %q: %v\n", pluginFile, runError))
 				}
 
 				risk.runner = newRunner
+				risk.ctx = ctx
 				customRiskRules[risk.ID] = risk
 				customRiskRuleList = append(customRiskRuleList, risk.ID)
 				reporter.Info("Custom risk rule loaded:", risk.ID)
@@ -76,3 +79,23 @@ func LoadCustomRiskRules(pluginFiles []string, reporter types.ProgressReporter)
 
 	return customRiskRules
 }
+
+// CheckCustomRiskRulePlugin verifies that the given custom risk rule plugin can be run and answers the info request
+// with a risk category, returning the ID of that category
+func CheckCustomRiskRulePlugin(ctx context.Context, pluginFile string, timeoutSeconds int) (string, error) {
+	newRunner, loadError := new(runner).Load(pluginFile, timeoutSeconds)
+	if loadError != nil {
+		return "", loadError
+	}
+
+	risk := new(CustomRiskCategory)
+	runError := newRunner.Run(ctx, nil, &risk, "-get-info")
+	if runError != nil {
+		return "", runError
+	}
+
+	if len(risk.ID) == 0 {
+		return "", fmt.Errorf("no risk category returned by %q", pluginFile)
+	}
+	return risk.ID, nil
+}
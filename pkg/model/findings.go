@@ -0,0 +1,281 @@
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+// ScannerFinding is a finding as read from the scanner output, before it is mapped to the technical assets
+type ScannerFinding struct {
+	types.ScannerFinding
+	Tags []string // technical asset IDs or tags of technical assets the finding applies to
+}
+
+var cweExpression = regexp.MustCompile(`(?i)cwe[-/:]?(\d+)`)
+
+// applyScannerFindings imports the findings of the scanner outputs, maps them to the technical assets (by their ID in
+// technical_asset or service, or by tags matching asset IDs or asset tags) and correlates them with the generated risks
+func applyScannerFindings(parsedModel *types.Model, filenames []string, progressReporter types.ProgressReporter) error {
+	parsedModel.ScannerFindings = make([]*types.ScannerFinding, 0)
+	for _, filename := range filenames {
+		if len(filename) == 0 {
+			continue
+		}
+
+		findings, err := LoadScannerFindings(filename)
+		if err != nil {
+			return err
+		}
+		unmapped := 0
+		for _, finding := range findings {
+			technicalAssets := mapScannerFinding(parsedModel, finding)
+			if len(technicalAssets) == 0 {
+				unmapped++
+				continue
+			}
+			for _, technicalAssetId := range technicalAssets {
+				mapped := finding.ScannerFinding
+				mapped.TechnicalAsset = technicalAssetId
+				parsedModel.ScannerFindings = append(parsedModel.ScannerFindings, &mapped)
+			}
+		}
+		progressReporter.Infof("Imported %d scanner findings of %v", len(findings)-unmapped, filename)
+		if unmapped > 0 {
+			progressReporter.Warnf("%d scanner findings of %v not mapped to any technical asset (set technical_asset or tags)", unmapped, filename)
+		}
+	}
+	parsedModel.CorrelateScannerFindings()
+	return nil
+}
+
+func mapScannerFinding(parsedModel *types.Model, finding *ScannerFinding) []string {
+	if _, ok := parsedModel.TechnicalAssets[finding.TechnicalAsset]; ok {
+		return []string{finding.TechnicalAsset}
+	}
+
+	technicalAssets := make([]string, 0)
+	for _, id := range parsedModel.SortedTechnicalAssetIDs() {
+		technicalAsset := parsedModel.TechnicalAssets[id]
+		for _, tag := range finding.Tags {
+			if tag == id || technicalAsset.IsTaggedWithAny(tag) {
+				technicalAssets = append(technicalAssets, id)
+				break
+			}
+		}
+	}
+	return technicalAssets
+}
+
+// LoadScannerFindings reads the findings of a SARIF log or DefectDojo findings json (as exported by its API or in its
+// generic findings import format), skipping inactive, mitigated and false positive DefectDojo findings
+func LoadScannerFindings(filename string) ([]*ScannerFinding, error) {
+	data, err := os.ReadFile(filepath.Clean(filename))
+	if err != nil {
+		return nil, fmt.Errorf("unable to read scanner findings %q: %v", filename, err)
+	}
+
+	var format struct {
+		Runs     json.RawMessage `json:"runs"`
+		Results  json.RawMessage `json:"results"`
+		Findings json.RawMessage `json:"findings"`
+	}
+	err = json.Unmarshal(data, &format)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse scanner findings %q (expected SARIF or DefectDojo json): %v", filename, err)
+	}
+
+	var findings []*ScannerFinding
+	switch {
+	case format.Runs != nil:
+		findings, err = parseSARIFFindings(data)
+	case format.Results != nil || format.Findings != nil:
+		findings, err = parseDefectDojoFindings(data, filepath.Base(filename))
+	default:
+		return nil, fmt.Errorf("unable to parse scanner findings %q: neither SARIF (runs) nor DefectDojo (results or findings) json", filename)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse scanner findings %q: %v", filename, err)
+	}
+	return findings, nil
+}
+
+type sarifProperties struct {
+	TechnicalAsset   string   `json:"technical_asset"`
+	Tags             []string `json:"tags"`
+	SecuritySeverity string   `json:"security-severity"`
+}
+
+func parseSARIFFindings(data []byte) ([]*ScannerFinding, error) {
+	var log struct {
+		Runs []struct {
+			Tool struct {
+				Driver struct {
+					Name  string `json:"name"`
+					Rules []struct {
+						ID               string                `json:"id"`
+						Name             string                `json:"name"`
+						ShortDescription struct{ Text string } `json:"shortDescription"`
+						Properties       sarifProperties       `json:"properties"`
+					} `json:"rules"`
+				} `json:"driver"`
+			} `json:"tool"`
+			Properties sarifProperties `json:"properties"`
+			Results    []struct {
+				RuleID    string                `json:"ruleId"`
+				Level     string                `json:"level"`
+				Message   struct{ Text string } `json:"message"`
+				Locations []struct {
+					PhysicalLocation struct {
+						ArtifactLocation struct {
+							URI string `json:"uri"`
+						} `json:"artifactLocation"`
+					} `json:"physicalLocation"`
+				} `json:"locations"`
+				Properties sarifProperties `json:"properties"`
+			} `json:"results"`
+		} `json:"runs"`
+	}
+	err := json.Unmarshal(data, &log)
+	if err != nil {
+		return nil, err
+	}
+
+	findings := make([]*ScannerFinding, 0)
+	for _, run := range log.Runs {
+		rules := make(map[string]int)
+		for i, rule := range run.Tool.Driver.Rules {
+			rules[rule.ID] = i
+		}
+		for _, result := range run.Results {
+			finding := &ScannerFinding{
+				ScannerFinding: types.ScannerFinding{
+					Source: run.Tool.Driver.Name,
+					ID:     result.RuleID,
+					Title:  result.Message.Text,
+				},
+				Tags: append(append([]string{}, run.Properties.Tags...), result.Properties.Tags...),
+			}
+			finding.TechnicalAsset = firstNonEmpty(result.Properties.TechnicalAsset, run.Properties.TechnicalAsset)
+			if len(result.Locations) > 0 {
+				finding.Location = result.Locations[0].PhysicalLocation.ArtifactLocation.URI
+			}
+
+			securitySeverity := result.Properties.SecuritySeverity
+			if i, ok := rules[result.RuleID]; ok {
+				rule := run.Tool.Driver.Rules[i]
+				finding.Title = firstNonEmpty(rule.ShortDescription.Text, rule.Name, finding.Title)
+				finding.CWE = cweOf(rule.Properties.Tags)
+				securitySeverity = firstNonEmpty(securitySeverity, rule.Properties.SecuritySeverity)
+			}
+			finding.Severity = sarifSeverity(securitySeverity, result.Level)
+			findings = append(findings, finding)
+		}
+	}
+	return findings, nil
+}
+
+// sarifSeverity maps the security-severity (a CVSS score) of the result or its rule, or else the level of the result
+func sarifSeverity(securitySeverity string, level string) types.RiskSeverity {
+	if score, err := strconv.ParseFloat(securitySeverity, 64); err == nil {
+		switch {
+		case score >= 9:
+			return types.CriticalSeverity
+		case score >= 7:
+			return types.HighSeverity
+		case score >= 4:
+			return types.MediumSeverity
+		default:
+			return types.LowSeverity
+		}
+	}
+	switch level {
+	case "error":
+		return types.HighSeverity
+	case "warning", "": // warning is the default level of SARIF
+		return types.MediumSeverity
+	default:
+		return types.LowSeverity
+	}
+}
+
+func parseDefectDojoFindings(data []byte, source string) ([]*ScannerFinding, error) {
+	type defectDojoFinding struct {
+		Title            string   `json:"title"`
+		Severity         string   `json:"severity"`
+		CWE              int      `json:"cwe"`
+		VulnIDFromTool   string   `json:"vuln_id_from_tool"`
+		UniqueIDFromTool string   `json:"unique_id_from_tool"`
+		FilePath         string   `json:"file_path"`
+		ComponentName    string   `json:"component_name"`
+		Service          string   `json:"service"`
+		Tags             []string `json:"tags"`
+		Active           *bool    `json:"active"`
+		IsMitigated      bool     `json:"is_mitigated"`
+		FalsePositive    bool     `json:"false_p"`
+		VulnerabilityIDs []struct {
+			VulnerabilityID string `json:"vulnerability_id"`
+		} `json:"vulnerability_ids"`
+	}
+	var export struct {
+		Results  []defectDojoFinding `json:"results"`  // API
+		Findings []defectDojoFinding `json:"findings"` // generic findings import
+	}
+	err := json.Unmarshal(data, &export)
+	if err != nil {
+		return nil, err
+	}
+
+	findings := make([]*ScannerFinding, 0)
+	for _, item := range append(export.Results, export.Findings...) {
+		if (item.Active != nil && !*item.Active) || item.IsMitigated || item.FalsePositive {
+			continue
+		}
+		id := firstNonEmpty(item.VulnIDFromTool, item.UniqueIDFromTool)
+		if len(item.VulnerabilityIDs) > 0 {
+			id = firstNonEmpty(item.VulnerabilityIDs[0].VulnerabilityID, id)
+		}
+		severity, severityError := types.ParseRiskSeverity(item.Severity)
+		if severityError != nil { // info
+			severity = types.LowSeverity
+		}
+		findings = append(findings, &ScannerFinding{
+			ScannerFinding: types.ScannerFinding{
+				Source:         source,
+				ID:             id,
+				Title:          item.Title,
+				Severity:       severity,
+				CWE:            item.CWE,
+				Location:       firstNonEmpty(item.FilePath, item.ComponentName),
+				TechnicalAsset: item.Service,
+			},
+			Tags: item.Tags,
+		})
+	}
+	return findings, nil
+}
+
+func cweOf(tags []string) int {
+	for _, tag := range tags {
+		if match := cweExpression.FindStringSubmatch(tag); match != nil {
+			cwe, _ := strconv.Atoi(match[1])
+			return cwe
+		}
+	}
+	return 0
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if len(strings.TrimSpace(value)) > 0 {
+			return value
+		}
+	}
+	return ""
+}
@@ -0,0 +1,92 @@
+package model
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/threagile/threagile/pkg/common"
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+const sarifTestFindings = `{
+	"version": "2.1.0",
+	"runs": [{
+		"tool": {"driver": {"name": "CodeQL", "rules": [
+			{"id": "js/sql-injection", "shortDescription": {"text": "Database query built from user-controlled sources"},
+				"properties": {"tags": ["security", "external/cwe/cwe-089"], "security-severity": "8.8"}},
+			{"id": "js/log-injection", "name": "Log injection", "properties": {"tags": ["security", "external/cwe/cwe-117"]}}
+		]}},
+		"properties": {"tags": ["shop"]},
+		"results": [
+			{"ruleId": "js/sql-injection", "level": "error", "message": {"text": "query"},
+				"locations": [{"physicalLocation": {"artifactLocation": {"uri": "src/orders.js"}}}]},
+			{"ruleId": "js/log-injection", "level": "note", "message": {"text": "log"}, "properties": {"technical_asset": "backend"}}
+		]
+	}]
+}`
+
+const defectDojoTestFindings = `{
+	"count": 3,
+	"results": [
+		{"title": "Outdated TLS", "severity": "High", "cwe": 326, "service": "", "tags": ["public"], "active": true,
+			"vulnerability_ids": [{"vulnerability_id": "CVE-2014-3566"}]},
+		{"title": "Fixed", "severity": "Critical", "cwe": 89, "service": "shop", "active": false},
+		{"title": "Verbose banner", "severity": "Info", "service": "unknown"}
+	]
+}`
+
+func TestLoadScannerFindings(t *testing.T) {
+	folder := t.TempDir()
+	sarifFile, defectDojoFile := filepath.Join(folder, "codeql.sarif"), filepath.Join(folder, "defectdojo.json")
+	assert.NoError(t, os.WriteFile(sarifFile, []byte(sarifTestFindings), 0600))
+	assert.NoError(t, os.WriteFile(defectDojoFile, []byte(defectDojoTestFindings), 0600))
+	assert.NoError(t, os.WriteFile(filepath.Join(folder, "broken.json"), []byte(`{"name": "no findings"}`), 0600))
+
+	findings, err := LoadScannerFindings(sarifFile)
+	assert.NoError(t, err)
+	assert.Len(t, findings, 2)
+	assert.Equal(t, types.ScannerFinding{Source: "CodeQL", ID: "js/sql-injection", Title: "Database query built from user-controlled sources",
+		Severity: types.HighSeverity, CWE: 89, Location: "src/orders.js"}, findings[0].ScannerFinding)
+	assert.Equal(t, []string{"shop"}, findings[0].Tags)
+	assert.Equal(t, "Log injection", findings[1].Title)
+	assert.Equal(t, types.LowSeverity, findings[1].Severity, "from the level")
+	assert.Equal(t, "backend", findings[1].TechnicalAsset)
+
+	findings, err = LoadScannerFindings(defectDojoFile)
+	assert.NoError(t, err)
+	assert.Len(t, findings, 2, "without the inactive finding")
+	assert.Equal(t, "CVE-2014-3566", findings[0].ID)
+	assert.Equal(t, types.HighSeverity, findings[0].Severity)
+	assert.Equal(t, types.LowSeverity, findings[1].Severity, "info")
+
+	_, err = LoadScannerFindings(filepath.Join(folder, "broken.json"))
+	assert.ErrorContains(t, err, "neither SARIF (runs) nor DefectDojo (results or findings) json")
+
+	parsedModel := &types.Model{
+		TechnicalAssets: map[string]*types.TechnicalAsset{
+			"shop":    {Id: "shop", Tags: []string{"public"}},
+			"backend": {Id: "backend"},
+			"gateway": {Id: "gateway", Tags: []string{"public"}},
+		},
+		BuiltInRiskCategories: types.RiskCategories{{ID: "sql-nosql-injection", CWE: 89}},
+		GeneratedRisksByCategory: map[string][]*types.Risk{"sql-nosql-injection": {
+			{CategoryId: "sql-nosql-injection", SyntheticId: "sql-nosql-injection@shop", MostRelevantTechnicalAssetId: "shop", Severity: types.ElevatedSeverity, RiskStatus: types.Unchecked},
+		}},
+	}
+	assert.NoError(t, applyScannerFindings(parsedModel, []string{sarifFile, defectDojoFile}, common.DefaultProgressReporter{}))
+	assert.Len(t, parsedModel.ScannerFindings, 4, "the TLS finding mapped to both public assets, the unknown service not mapped")
+	assert.Equal(t, []string{"sql-nosql-injection@shop"}, parsedModel.ScannerFindings[0].CorrelatedRisks)
+
+	exposures := parsedModel.Exposures()
+	assert.Len(t, exposures, 3)
+	assert.Equal(t, "gateway", exposures[0].TechnicalAsset, "high finding, sorted by ID before shop")
+	assert.Equal(t, "shop", exposures[1].TechnicalAsset)
+	assert.Equal(t, types.HighSeverity, exposures[1].HighestSeverity)
+	assert.Equal(t, []string{"sql-nosql-injection@shop"}, exposures[1].Risks)
+	assert.Equal(t, []string{"sql-nosql-injection@shop"}, exposures[1].ConfirmedRisks)
+	assert.Equal(t, 1, exposures[1].UnmodeledFindings)
+	assert.Equal(t, "backend", exposures[2].TechnicalAsset)
+}
@@ -0,0 +1,143 @@
+package model
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/threagile/threagile/pkg/common"
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+// stages of an analysis run hooks are called at
+const (
+	PreParseHookStage     = "pre-parse"     // before parsing the model, hooks may replace the model data
+	PostAnalysisHookStage = "post-analysis" // after analyzing the model and writing the reports
+)
+
+// HookEvent tells the hooks about the run
+type HookEvent struct {
+	Stage        string
+	ModelFile    string // before parsing the model file or one of its includes, the hooks are called for each of them
+	ModelData    []byte // model as read from the model file before parsing, hooks may replace it (e.g. scrubbing secrets) without changing the file
+	OutputFolder string
+	ResultFiles  []string // reports and diagrams written by the analysis, after it only
+}
+
+// Hook is a callback registered by programs embedding Threagile, failing the run when returning an error
+type Hook func(ctx context.Context, event *HookEvent) error
+
+var registeredHooks = make(map[string][]Hook)
+
+// RegisterHook adds the hook to the stage, called in the order of registration before the hook commands of the config
+// (not synchronized, so register the hooks before running analyses, e.g. in init functions)
+func RegisterHook(stage string, hook Hook) {
+	registeredHooks[stage] = append(registeredHooks[stage], hook)
+}
+
+// hasHooks tells whether any hook is registered or configured for the stage
+func hasHooks(config *common.Config, stage string) bool {
+	return len(registeredHooks[stage]) > 0 || len(hookCommands(config, stage)) > 0
+}
+
+// RunHooks calls the registered hooks and then the hook commands configured for the stage of the event
+//
+// The hook commands get the model file followed by the result files as arguments and the event in the environment
+// variables THREAGILE_HOOK_STAGE, THREAGILE_MODEL_FILE and THREAGILE_OUTPUT_FOLDER. Before parsing they get the model
+// data on stdin and in the temporary file of THREAGILE_MODEL_DATA_FILE, which they rewrite to replace the model data.
+// Anything they print is reported.
+func RunHooks(ctx context.Context, config *common.Config, event *HookEvent, progressReporter types.ProgressReporter) error {
+	for _, hook := range registeredHooks[event.Stage] {
+		err := hook(ctx, event)
+		if err != nil {
+			return fmt.Errorf("%v hook failed: %w", event.Stage, err)
+		}
+	}
+
+	for _, command := range hookCommands(config, event.Stage) {
+		progressReporter.Infof("Running %v hook: %v", event.Stage, command)
+		err := runHookCommand(ctx, command, config.PluginTimeoutSeconds, event, progressReporter)
+		if err != nil {
+			return fmt.Errorf("%v hook %q failed: %w", event.Stage, command, err)
+		}
+	}
+	return nil
+}
+
+func hookCommands(config *common.Config, stage string) []string {
+	var commands []string
+	switch stage {
+	case PreParseHookStage:
+		commands = config.PreParseHooks
+	case PostAnalysisHookStage:
+		commands = config.PostAnalysisHooks
+	}
+
+	nonEmpty := make([]string, 0, len(commands))
+	for _, command := range commands {
+		if len(strings.TrimSpace(command)) > 0 {
+			nonEmpty = append(nonEmpty, strings.TrimSpace(command))
+		}
+	}
+	return nonEmpty
+}
+
+// runHookCommand runs the command line (split at whitespace, without a shell) of the hook
+func runHookCommand(ctx context.Context, command string, timeoutSeconds int, event *HookEvent, progressReporter types.ProgressReporter) error {
+	ctx, cancel := common.WithTimeout(ctx, timeoutSeconds)
+	defer cancel()
+
+	fields := strings.Fields(command)
+	arguments := append(append(fields[1:], event.ModelFile), event.ResultFiles...)
+	hook := exec.CommandContext(ctx, fields[0], arguments...) // #nosec G204
+	hook.Env = append(os.Environ(),
+		"THREAGILE_HOOK_STAGE="+event.Stage,
+		"THREAGILE_MODEL_FILE="+event.ModelFile,
+		"THREAGILE_OUTPUT_FOLDER="+event.OutputFolder,
+	)
+	dataFilename := ""
+	if event.Stage == PreParseHookStage {
+		dataFile, err := os.CreateTemp("", "threagile-hook-*"+filepath.Ext(event.ModelFile))
+		if err != nil {
+			return err
+		}
+		dataFilename = dataFile.Name()
+		defer func() { _ = os.Remove(dataFilename) }()
+		_, err = dataFile.Write(event.ModelData)
+		if closeError := dataFile.Close(); err == nil {
+			err = closeError
+		}
+		if err != nil {
+			return err
+		}
+		hook.Env = append(hook.Env, "THREAGILE_MODEL_DATA_FILE="+dataFilename)
+		hook.Stdin = bytes.NewReader(event.ModelData)
+	}
+
+	var stdout, stderr bytes.Buffer
+	hook.Stdout = &stdout
+	hook.Stderr = &stderr
+	runError := hook.Run()
+	if ctx.Err() != nil {
+		return common.NewFailure(common.ExitCodeTimeout, fmt.Errorf("killed: %w", ctx.Err()))
+	}
+	if runError != nil {
+		return fmt.Errorf("%v: %v", runError, strings.TrimSpace(stderr.String()))
+	}
+
+	if output := strings.TrimSpace(stdout.String()); len(output) > 0 {
+		progressReporter.Info(output)
+	}
+	if len(dataFilename) > 0 {
+		modelData, err := os.ReadFile(filepath.Clean(dataFilename))
+		if err != nil {
+			return err
+		}
+		event.ModelData = modelData
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+package model
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/threagile/threagile/pkg/common"
+)
+
+// TestHookCommand is the hook command run by the tests (the test binary itself, so that they need no shell), doing
+// what THREAGILE_TEST_HOOK tells
+func TestHookCommand(t *testing.T) {
+	switch os.Getenv("THREAGILE_TEST_HOOK") {
+	case "scrub":
+		dataFilename := os.Getenv("THREAGILE_MODEL_DATA_FILE")
+		modelData, _ := os.ReadFile(filepath.Clean(dataFilename))
+		_ = os.WriteFile(dataFilename, []byte(strings.ReplaceAll(string(modelData), "secret-token-123", "REDACTED")), 0600)
+		fmt.Println("scrubbed", filepath.Base(os.Getenv("THREAGILE_MODEL_FILE")))
+	case "upload":
+		arguments := os.Args[len(os.Args)-4:]
+		_ = os.WriteFile(filepath.Join(os.Getenv("THREAGILE_OUTPUT_FOLDER"), "uploaded.txt"), []byte(os.Getenv("THREAGILE_HOOK_STAGE")+" "+strings.Join(arguments, " ")), 0600)
+	case "failing":
+		_, _ = fmt.Fprintln(os.Stderr, "broken")
+		os.Exit(3)
+	default:
+		return
+	}
+	os.Exit(0)
+}
+
+func TestHooks(t *testing.T) {
+	folder := t.TempDir()
+	modelFile := filepath.Join(folder, "threagile.yaml")
+	assert.NoError(t, os.WriteFile(modelFile, []byte("title: Shop\nincludes:\n  - team.yaml\n"), 0600))
+	assert.NoError(t, os.WriteFile(filepath.Join(folder, "team.yaml"), []byte("author:\n  name: secret-token-123\n"), 0600))
+	hookCommand := os.Args[0] + " -test.run=^TestHookCommand$ --"
+
+	registeredHooks = make(map[string][]Hook)
+	defer func() { registeredHooks = make(map[string][]Hook) }()
+	hookedFiles := make([]string, 0)
+	RegisterHook(PreParseHookStage, func(ctx context.Context, event *HookEvent) error {
+		hookedFiles = append(hookedFiles, filepath.Base(event.ModelFile))
+		event.ModelData = []byte(strings.Replace(string(event.ModelData), "Shop", "Web Shop", 1))
+		return nil
+	})
+
+	t.Setenv("THREAGILE_TEST_HOOK", "scrub")
+	config := new(common.Config).Defaults("")
+	config.InputFile = modelFile
+	config.OutputFolder = folder
+	config.PreParseHooks = []string{hookCommand}
+	modelInput, err := loadModelInput(context.Background(), config, common.DefaultProgressReporter{})
+	assert.NoError(t, err)
+	assert.Equal(t, "Web Shop", modelInput.Title, "changed by the registered hook")
+	assert.Equal(t, "REDACTED", modelInput.Author.Name, "scrubbed in the include by the hook command, its output not replacing the model")
+	assert.Equal(t, []string{"threagile.yaml", "team.yaml"}, hookedFiles)
+	unchanged, _ := os.ReadFile(filepath.Join(folder, "team.yaml"))
+	assert.Contains(t, string(unchanged), "secret-token-123")
+
+	t.Setenv("THREAGILE_TEST_HOOK", "upload")
+	config.PostAnalysisHooks = []string{hookCommand + " --bucket reports"}
+	event := &HookEvent{Stage: PostAnalysisHookStage, ModelFile: modelFile, OutputFolder: folder, ResultFiles: []string{filepath.Join(folder, "report.pdf")}}
+	assert.NoError(t, RunHooks(context.Background(), config, event, common.DefaultProgressReporter{}))
+	uploaded, _ := os.ReadFile(filepath.Join(folder, "uploaded.txt"))
+	assert.Equal(t, "post-analysis --bucket reports "+modelFile+" "+filepath.Join(folder, "report.pdf"), string(uploaded))
+
+	t.Setenv("THREAGILE_TEST_HOOK", "failing")
+	_, err = loadModelInput(context.Background(), config, common.DefaultProgressReporter{})
+	assert.ErrorContains(t, err, "exit status 3: broken")
+	assert.Equal(t, common.ExitCodeFailure, common.ExitCodeOf(err), "no parse error")
+}
@@ -0,0 +1,54 @@
+package model
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+// applyOwnerDirectory hands all owners of the model to a directory lookup run (e.g. a bridge to LDAP or SCIM)
+// and expects a map of owner to contact info in return; owners missing there or marked inactive are reported as stale
+func applyOwnerDirectory(ctx context.Context, parsedModel *types.Model, binFolder, directoryPlugin string, strict bool, timeoutSeconds int, progressReporter types.ProgressReporter) error {
+	if len(directoryPlugin) == 0 {
+		return nil
+	}
+
+	progressReporter.Infof("Applying owner directory lookup: %v", directoryPlugin)
+
+	runner, loadError := new(runner).Load(filepath.Join(binFolder, directoryPlugin), timeoutSeconds)
+	if loadError != nil {
+		return fmt.Errorf("owner directory %q not loaded: %v", directoryPlugin, loadError)
+	}
+
+	owners := parsedModel.Owners()
+	contacts := make(map[string]*types.OwnerContact)
+	runError := runner.Run(ctx, owners, &contacts)
+	if runError != nil {
+		return fmt.Errorf("owner directory %q not applied: %v", directoryPlugin, runError)
+	}
+
+	parsedModel.OwnerContacts = make(map[string]*types.OwnerContact)
+	for _, owner := range owners {
+		contact, ok := contacts[owner]
+		if !ok || contact == nil {
+			if strict {
+				return fmt.Errorf("owner not found in directory: %v", owner)
+			}
+			progressReporter.Warnf("Owner not found in directory: %v", owner)
+			continue
+		}
+
+		if !contact.IsActive() {
+			if strict {
+				return fmt.Errorf("owner is no longer active in directory: %v", owner)
+			}
+			progressReporter.Warnf("Owner is no longer active in directory: %v", owner)
+		}
+
+		parsedModel.OwnerContacts[owner] = contact
+	}
+
+	return nil
+}
@@ -0,0 +1,60 @@
+package model
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/threagile/threagile/pkg/common"
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+type ownerDirectoryTestReporter struct {
+	common.DefaultProgressReporter
+	warnings *[]string
+}
+
+func (what ownerDirectoryTestReporter) Warnf(format string, a ...any) {
+	*what.warnings = append(*what.warnings, fmt.Sprintf(format, a...))
+}
+
+func ownerDirectoryTestModel(owners ...string) *types.Model {
+	parsedModel := &types.Model{TechnicalAssets: make(map[string]*types.TechnicalAsset)}
+	for _, owner := range owners {
+		parsedModel.TechnicalAssets[owner] = &types.TechnicalAsset{Id: owner, Owner: owner}
+	}
+	return parsedModel
+}
+
+func TestApplyOwnerDirectory(t *testing.T) {
+	t.Setenv("THREAGILE_TEST_PLUGIN", "owner-directory")
+	binFolder, plugin := filepath.Split(os.Args[0])
+	reporter := ownerDirectoryTestReporter{warnings: new([]string)}
+
+	parsedModel := ownerDirectoryTestModel("Team Shop", "Team Legacy", "Team Gone")
+	assert.NoError(t, applyOwnerDirectory(context.Background(), parsedModel, binFolder, plugin, false, 10, reporter))
+	assert.Equal(t, []string{"Owner not found in directory: Team Gone", "Owner is no longer active in directory: Team Legacy"}, *reporter.warnings)
+	assert.Equal(t, "Team Shop (Alice, alice@example.com)", parsedModel.OwnerWithContact("Team Shop"), "active without being marked so")
+	assert.Equal(t, "Team Legacy (Bob)", parsedModel.OwnerWithContact("Team Legacy"))
+	assert.Equal(t, "Team Gone", parsedModel.OwnerWithContact("Team Gone"))
+
+	*reporter.warnings = nil
+	assert.NoError(t, applyOwnerDirectory(context.Background(), ownerDirectoryTestModel("Team Shop"), binFolder, plugin, true, 10, reporter))
+	assert.Empty(t, *reporter.warnings)
+}
+
+func TestApplyOwnerDirectoryStrict(t *testing.T) {
+	t.Setenv("THREAGILE_TEST_PLUGIN", "owner-directory")
+	binFolder, plugin := filepath.Split(os.Args[0])
+	reporter := ownerDirectoryTestReporter{warnings: new([]string)}
+
+	err := applyOwnerDirectory(context.Background(), ownerDirectoryTestModel("Team Shop", "Team Gone"), binFolder, plugin, true, 10, reporter)
+	assert.EqualError(t, err, "owner not found in directory: Team Gone")
+	err = applyOwnerDirectory(context.Background(), ownerDirectoryTestModel("Team Shop", "Team Legacy"), binFolder, plugin, true, 10, reporter)
+	assert.EqualError(t, err, "owner is no longer active in directory: Team Legacy")
+	assert.Empty(t, *reporter.warnings)
+}
@@ -1,9 +1,12 @@
 package model
 
 import (
+	"errors"
 	"fmt"
 	"path/filepath"
 	"regexp"
+	"slices"
+	"strconv"
 	"strings"
 	"time"
 
@@ -20,10 +23,11 @@ func ParseModel(config *common.Config, modelInput *input.Model, builtinRiskRules
 	}
 
 	technologies.PropagateAttributes()
+	problems := make(modelProblems, 0)
 
 	businessCriticality, err := types.ParseCriticality(modelInput.BusinessCriticality)
 	if err != nil {
-		return nil, fmt.Errorf("unknown 'business_criticality' value of application: %v", modelInput.BusinessCriticality)
+		problems.add("business_criticality", fmt.Errorf("unknown 'business_criticality' value of application: %v", modelInput.BusinessCriticality))
 	}
 
 	reportDate := time.Now()
@@ -31,7 +35,16 @@ func ParseModel(config *common.Config, modelInput *input.Model, builtinRiskRules
 		var parseError error
 		reportDate, parseError = time.Parse("2006-01-02", modelInput.Date)
 		if parseError != nil {
-			return nil, fmt.Errorf("unable to parse 'date' value of model file (expected format: '2006-01-02')")
+			problems.add("date", fmt.Errorf("unable to parse 'date' value of model file (expected format: '2006-01-02')"))
+		}
+	}
+
+	var nextReviewDate time.Time
+	if len(modelInput.NextReviewDate) > 0 {
+		var parseError error
+		nextReviewDate, parseError = time.Parse("2006-01-02", modelInput.NextReviewDate)
+		if parseError != nil {
+			problems.add("next_review_date", fmt.Errorf("unable to parse 'next_review_date' value of model file (expected format: '2006-01-02')"))
 		}
 	}
 
@@ -41,6 +54,7 @@ func ParseModel(config *common.Config, modelInput *input.Model, builtinRiskRules
 		Author:                         modelInput.Author,
 		Contributors:                   modelInput.Contributors,
 		Date:                           types.Date{Time: reportDate},
+		NextReviewDate:                 types.Date{Time: nextReviewDate},
 		AppDescription:                 removePathElementsFromImageFiles(modelInput.AppDescription),
 		BusinessOverview:               removePathElementsFromImageFiles(modelInput.BusinessOverview),
 		TechnicalOverview:              removePathElementsFromImageFiles(modelInput.TechnicalOverview),
@@ -59,6 +73,18 @@ func ParseModel(config *common.Config, modelInput *input.Model, builtinRiskRules
 		DiagramTweakSameRankAssets:                    modelInput.DiagramTweakSameRankAssets,
 	}
 
+	if len(config.TagTaxonomyFilename) > 0 {
+		taxonomyLoadError := parsedModel.TagTaxonomy.LoadFromFile(config.TagTaxonomyFilename)
+		if taxonomyLoadError != nil {
+			return nil, taxonomyLoadError
+		}
+
+		taxonomyError := parsedModel.CheckTagsAvailableInTaxonomy()
+		if taxonomyError != nil {
+			problems.add("tags_available", taxonomyError)
+		}
+	}
+
 	parsedModel.CommunicationLinks = make(map[string]*types.CommunicationLink)
 	parsedModel.AllSupportedTags = make(map[string]bool)
 	parsedModel.IncomingTechnicalCommunicationLinksMappedByTargetId = make(map[string][]*types.CommunicationLink)
@@ -80,35 +106,36 @@ func ParseModel(config *common.Config, modelInput *input.Model, builtinRiskRules
 
 		usage, err := types.ParseUsage(asset.Usage)
 		if err != nil {
-			return nil, fmt.Errorf("unknown 'usage' value of data asset %q: %v", title, asset.Usage)
+			problems.add("data_assets."+title+".usage", fmt.Errorf("unknown 'usage' value of data asset %q: %v", title, asset.Usage))
 		}
 		quantity, err := types.ParseQuantity(asset.Quantity)
 		if err != nil {
-			return nil, fmt.Errorf("unknown 'quantity' value of data asset %q: %v", title, asset.Quantity)
+			problems.add("data_assets."+title+".quantity", fmt.Errorf("unknown 'quantity' value of data asset %q: %v", title, asset.Quantity))
 		}
 		confidentiality, err := types.ParseConfidentiality(asset.Confidentiality)
 		if err != nil {
-			return nil, fmt.Errorf("unknown 'confidentiality' value of data asset %q: %v", title, asset.Confidentiality)
+			problems.add("data_assets."+title+".confidentiality", fmt.Errorf("unknown 'confidentiality' value of data asset %q: %v", title, asset.Confidentiality))
 		}
 		integrity, err := types.ParseCriticality(asset.Integrity)
 		if err != nil {
-			return nil, fmt.Errorf("unknown 'integrity' value of data asset %q: %v", title, asset.Integrity)
+			problems.add("data_assets."+title+".integrity", fmt.Errorf("unknown 'integrity' value of data asset %q: %v", title, asset.Integrity))
 		}
 		availability, err := types.ParseCriticality(asset.Availability)
 		if err != nil {
-			return nil, fmt.Errorf("unknown 'availability' value of data asset %q: %v", title, asset.Availability)
+			problems.add("data_assets."+title+".availability", fmt.Errorf("unknown 'availability' value of data asset %q: %v", title, asset.Availability))
 		}
 
 		err = checkIdSyntax(id)
 		if err != nil {
-			return nil, err
+			problems.add("data_assets."+title+".id", err)
 		}
 		if _, exists := parsedModel.DataAssets[id]; exists {
-			return nil, fmt.Errorf("duplicate id used: %v", id)
+			problems.add("data_assets."+title+".id", fmt.Errorf("duplicate id used: %v", id))
+			continue
 		}
 		tags, err := parsedModel.CheckTags(lowerCaseAndTrim(asset.Tags), "data asset '"+title+"'")
 		if err != nil {
-			return nil, err
+			problems.add("data_assets."+title+".tags", err)
 		}
 		parsedModel.DataAssets[id] = &types.DataAsset{
 			Id:                     id,
@@ -133,7 +160,7 @@ func ParseModel(config *common.Config, modelInput *input.Model, builtinRiskRules
 
 		usage, err := types.ParseUsage(asset.Usage)
 		if err != nil {
-			return nil, fmt.Errorf("unknown 'usage' value of technical asset %q: %v", title, asset.Usage)
+			problems.add("technical_assets."+title+".usage", fmt.Errorf("unknown 'usage' value of technical asset %q: %v", title, asset.Usage))
 		}
 
 		var dataAssetsStored = make([]string, 0)
@@ -146,7 +173,8 @@ func ParseModel(config *common.Config, modelInput *input.Model, builtinRiskRules
 
 				err := parsedModel.CheckDataAssetTargetExists(referencedAsset, fmt.Sprintf("technical asset %q", title))
 				if err != nil {
-					return nil, err
+					problems.add("technical_assets."+title+".data_assets_stored", err)
+					continue
 				}
 				dataAssetsStored = append(dataAssetsStored, referencedAsset)
 			}
@@ -162,7 +190,8 @@ func ParseModel(config *common.Config, modelInput *input.Model, builtinRiskRules
 
 				err := parsedModel.CheckDataAssetTargetExists(referencedAsset, "technical asset '"+title+"'")
 				if err != nil {
-					return nil, err
+					problems.add("technical_assets."+title+".data_assets_processed", err)
+					continue
 				}
 				dataAssetsProcessed = append(dataAssetsProcessed, referencedAsset)
 			}
@@ -170,11 +199,11 @@ func ParseModel(config *common.Config, modelInput *input.Model, builtinRiskRules
 
 		technicalAssetType, err := types.ParseTechnicalAssetType(asset.Type)
 		if err != nil {
-			return nil, fmt.Errorf("unknown 'type' value of technical asset %q: %v", title, asset.Type)
+			problems.add("technical_assets."+title+".type", fmt.Errorf("unknown 'type' value of technical asset %q: %v", title, asset.Type))
 		}
 		technicalAssetSize, err := types.ParseTechnicalAssetSize(asset.Size)
 		if err != nil {
-			return nil, fmt.Errorf("unknown 'size' value of technical asset %q: %v", title, asset.Size)
+			problems.add("technical_assets."+title+".size", fmt.Errorf("unknown 'size' value of technical asset %q: %v", title, asset.Size))
 		}
 
 		technicalAssetTechnologies := make([]*types.Technology, 0)
@@ -186,7 +215,8 @@ func ParseModel(config *common.Config, modelInput *input.Model, builtinRiskRules
 		for _, technologyName := range allTechnologies {
 			technicalAssetTechnology := technologies.Get(technologyName)
 			if technicalAssetTechnology == nil {
-				return nil, fmt.Errorf("unknown 'technology' value of technical asset %q: %v", title, asset.Technology)
+				problems.add("technical_assets."+title+".technologies", fmt.Errorf("unknown 'technology' value of technical asset %q: %v", title, asset.Technology))
+				continue
 			}
 
 			technicalAssetTechnologies = append(technicalAssetTechnologies, technicalAssetTechnology)
@@ -194,23 +224,23 @@ func ParseModel(config *common.Config, modelInput *input.Model, builtinRiskRules
 
 		encryption, err := types.ParseEncryptionStyle(asset.Encryption)
 		if err != nil {
-			return nil, fmt.Errorf("unknown 'encryption' value of technical asset %q: %v", title, asset.Encryption)
+			problems.add("technical_assets."+title+".encryption", fmt.Errorf("unknown 'encryption' value of technical asset %q: %v", title, asset.Encryption))
 		}
 		technicalAssetMachine, err := types.ParseTechnicalAssetMachine(asset.Machine)
 		if err != nil {
-			return nil, fmt.Errorf("unknown 'machine' value of technical asset %q: %v", title, asset.Machine)
+			problems.add("technical_assets."+title+".machine", fmt.Errorf("unknown 'machine' value of technical asset %q: %v", title, asset.Machine))
 		}
 		confidentiality, err := types.ParseConfidentiality(asset.Confidentiality)
 		if err != nil {
-			return nil, fmt.Errorf("unknown 'confidentiality' value of technical asset %q: %v", title, asset.Confidentiality)
+			problems.add("technical_assets."+title+".confidentiality", fmt.Errorf("unknown 'confidentiality' value of technical asset %q: %v", title, asset.Confidentiality))
 		}
 		integrity, err := types.ParseCriticality(asset.Integrity)
 		if err != nil {
-			return nil, fmt.Errorf("unknown 'integrity' value of technical asset %q: %v", title, asset.Integrity)
+			problems.add("technical_assets."+title+".integrity", fmt.Errorf("unknown 'integrity' value of technical asset %q: %v", title, asset.Integrity))
 		}
 		availability, err := types.ParseCriticality(asset.Availability)
 		if err != nil {
-			return nil, fmt.Errorf("unknown 'availability' value of technical asset %q: %v", title, asset.Availability)
+			problems.add("technical_assets."+title+".availability", fmt.Errorf("unknown 'availability' value of technical asset %q: %v", title, asset.Availability))
 		}
 
 		dataFormatsAccepted := make([]types.DataFormat, 0)
@@ -218,7 +248,8 @@ func ParseModel(config *common.Config, modelInput *input.Model, builtinRiskRules
 			for _, dataFormatName := range asset.DataFormatsAccepted {
 				dataFormat, err := types.ParseDataFormat(dataFormatName)
 				if err != nil {
-					return nil, fmt.Errorf("unknown 'data_formats_accepted' value of technical asset %q: %v", title, dataFormatName)
+					problems.add("technical_assets."+title+".data_formats_accepted", fmt.Errorf("unknown 'data_formats_accepted' value of technical asset %q: %v", title, dataFormatName))
+					continue
 				}
 				dataFormatsAccepted = append(dataFormatsAccepted, dataFormat)
 			}
@@ -233,19 +264,19 @@ func ParseModel(config *common.Config, modelInput *input.Model, builtinRiskRules
 
 				authentication, err := types.ParseAuthentication(commLink.Authentication)
 				if err != nil {
-					return nil, fmt.Errorf("unknown 'authentication' value of technical asset %q communication link %q: %v", title, commLinkTitle, commLink.Authentication)
+					problems.add("technical_assets."+title+".communication_links."+commLinkTitle+".authentication", fmt.Errorf("unknown 'authentication' value of technical asset %q communication link %q: %v", title, commLinkTitle, commLink.Authentication))
 				}
 				authorization, err := types.ParseAuthorization(commLink.Authorization)
 				if err != nil {
-					return nil, fmt.Errorf("unknown 'authorization' value of technical asset %q communication link %q: %v", title, commLinkTitle, commLink.Authorization)
+					problems.add("technical_assets."+title+".communication_links."+commLinkTitle+".authorization", fmt.Errorf("unknown 'authorization' value of technical asset %q communication link %q: %v", title, commLinkTitle, commLink.Authorization))
 				}
 				usage, err := types.ParseUsage(commLink.Usage)
 				if err != nil {
-					return nil, fmt.Errorf("unknown 'usage' value of technical asset %q communication link %q: %v", title, commLinkTitle, commLink.Usage)
+					problems.add("technical_assets."+title+".communication_links."+commLinkTitle+".usage", fmt.Errorf("unknown 'usage' value of technical asset %q communication link %q: %v", title, commLinkTitle, commLink.Usage))
 				}
 				protocol, err := types.ParseProtocol(commLink.Protocol)
 				if err != nil {
-					return nil, fmt.Errorf("unknown 'protocol' value of technical asset %q communication link %q: %v", title, commLinkTitle, commLink.Protocol)
+					problems.add("technical_assets."+title+".communication_links."+commLinkTitle+".protocol", fmt.Errorf("unknown 'protocol' value of technical asset %q communication link %q: %v", title, commLinkTitle, commLink.Protocol))
 				}
 
 				if commLink.DataAssetsSent != nil {
@@ -254,7 +285,8 @@ func ParseModel(config *common.Config, modelInput *input.Model, builtinRiskRules
 						if !contains(dataAssetsSent, referencedAsset) {
 							err := parsedModel.CheckDataAssetTargetExists(referencedAsset, fmt.Sprintf("communication link %q of technical asset %q", commLinkTitle, title))
 							if err != nil {
-								return nil, err
+								problems.add("technical_assets."+title+".communication_links."+commLinkTitle+".data_assets_sent", err)
+								continue
 							}
 
 							dataAssetsSent = append(dataAssetsSent, referencedAsset)
@@ -274,7 +306,8 @@ func ParseModel(config *common.Config, modelInput *input.Model, builtinRiskRules
 
 						err := parsedModel.CheckDataAssetTargetExists(referencedAsset, "communication link '"+commLinkTitle+"' of technical asset '"+title+"'")
 						if err != nil {
-							return nil, err
+							problems.add("technical_assets."+title+".communication_links."+commLinkTitle+".data_assets_received", err)
+							continue
 						}
 						dataAssetsReceived = append(dataAssetsReceived, referencedAsset)
 
@@ -289,13 +322,13 @@ func ParseModel(config *common.Config, modelInput *input.Model, builtinRiskRules
 				}
 
 				dataFlowTitle := fmt.Sprintf("%v", commLinkTitle)
-				commLinkId, err := createDataFlowId(id, dataFlowTitle)
+				commLinkId, err := CreateDataFlowId(id, dataFlowTitle)
 				if err != nil {
-					return nil, err
+					problems.add("technical_assets."+title+".communication_links."+commLinkTitle, err)
 				}
 				tags, err := parsedModel.CheckTags(lowerCaseAndTrim(commLink.Tags), "communication link '"+commLinkTitle+"' of technical asset '"+title+"'")
 				if err != nil {
-					return nil, err
+					problems.add("technical_assets."+title+".communication_links."+commLinkTitle+".tags", err)
 				}
 				commLink := &types.CommunicationLink{
 					Id:                     commLinkId,
@@ -327,14 +360,15 @@ func ParseModel(config *common.Config, modelInput *input.Model, builtinRiskRules
 
 		err = checkIdSyntax(id)
 		if err != nil {
-			return nil, err
+			problems.add("technical_assets."+title+".id", err)
 		}
 		if _, exists := parsedModel.TechnicalAssets[id]; exists {
-			return nil, fmt.Errorf("duplicate id used: %v", id)
+			problems.add("technical_assets."+title+".id", fmt.Errorf("duplicate id used: %v", id))
+			continue
 		}
 		tags, err := parsedModel.CheckTags(lowerCaseAndTrim(asset.Tags), fmt.Sprintf("technical asset %q", title))
 		if err != nil {
-			return nil, err
+			problems.add("technical_assets."+title+".tags", err)
 		}
 		parsedModel.TechnicalAssets[id] = &types.TechnicalAsset{
 			Id:                      id,
@@ -364,6 +398,7 @@ func ParseModel(config *common.Config, modelInput *input.Model, builtinRiskRules
 			DataFormatsAccepted:     dataFormatsAccepted,
 			CommunicationLinks:      communicationLinks,
 			DiagramTweakOrder:       asset.DiagramTweakOrder,
+			SBOM:                    asset.SBOM,
 		}
 	}
 
@@ -394,8 +429,9 @@ func ParseModel(config *common.Config, modelInput *input.Model, builtinRiskRules
 				continue
 			}
 			targetTechAsset := parsedModel.TechnicalAssets[commLink.TargetId]
-			if targetTechAsset == nil {
-				return nil, fmt.Errorf("missing target technical asset %q for communication link: %q", commLink.TargetId, commLink.Title)
+			if targetTechAsset == nil { // the model consistency check (linking) of communication links
+				problems.add("technical_assets."+techAsset.Title+".communication_links."+commLink.Title+".target", fmt.Errorf("missing target technical asset %q for communication link: %q", commLink.TargetId, commLink.Title))
+				continue
 			}
 			dataAssetsProcessedByTarget := targetTechAsset.DataAssetsProcessed
 			for _, dataAssetSent := range commLink.DataAssetsSent {
@@ -427,10 +463,12 @@ func ParseModel(config *common.Config, modelInput *input.Model, builtinRiskRules
 				technicalAssetsInside[i] = strings.ToLower(parsedInsideAsset)
 				_, found := parsedModel.TechnicalAssets[technicalAssetsInside[i]]
 				if !found {
-					return nil, fmt.Errorf("missing referenced technical asset %q at trust boundary %q", technicalAssetsInside[i], title)
+					problems.add("trust_boundaries."+title+".technical_assets_inside", fmt.Errorf("missing referenced technical asset %q at trust boundary %q", technicalAssetsInside[i], title))
+					continue
 				}
 				if checklistToAvoidAssetBeingModeledInMultipleTrustBoundaries[technicalAssetsInside[i]] {
-					return nil, fmt.Errorf("referenced technical asset %q at trust boundary %q is modeled in multiple trust boundaries", technicalAssetsInside[i], title)
+					problems.add("trust_boundaries."+title+".technical_assets_inside", fmt.Errorf("referenced technical asset %q at trust boundary %q is modeled in multiple trust boundaries", technicalAssetsInside[i], title))
+					continue
 				}
 				checklistToAvoidAssetBeingModeledInMultipleTrustBoundaries[technicalAssetsInside[i]] = true
 				//fmt.Println("asset "+technicalAssetsInside[i]+" at i="+strconv.Itoa(i))
@@ -448,27 +486,36 @@ func ParseModel(config *common.Config, modelInput *input.Model, builtinRiskRules
 
 		trustBoundaryType, err := types.ParseTrustBoundary(boundary.Type)
 		if err != nil {
-			return nil, fmt.Errorf("unknown 'type' of trust boundary %q: %v", title, boundary.Type)
+			problems.add("trust_boundaries."+title+".type", fmt.Errorf("unknown 'type' of trust boundary %q: %v", title, boundary.Type))
+		}
+		trustBoundaryCriticality := parsedModel.BusinessCriticality
+		if len(boundary.Criticality) > 0 {
+			trustBoundaryCriticality, err = types.ParseCriticality(boundary.Criticality)
+			if err != nil {
+				problems.add("trust_boundaries."+title+".criticality", fmt.Errorf("unknown 'criticality' of trust boundary %q: %v", title, boundary.Criticality))
+			}
 		}
 		tags, err := parsedModel.CheckTags(lowerCaseAndTrim(boundary.Tags), fmt.Sprintf("trust boundary %q", title))
 		if err != nil {
-			return nil, err
+			problems.add("trust_boundaries."+title+".tags", err)
 		}
 		trustBoundary := &types.TrustBoundary{
 			Id:                    id,
 			Title:                 title, //fmt.Sprintf("%v", boundary["title"]),
 			Description:           withDefault(fmt.Sprintf("%v", boundary.Description), title),
 			Type:                  trustBoundaryType,
+			Criticality:           trustBoundaryCriticality,
 			Tags:                  tags,
 			TechnicalAssetsInside: technicalAssetsInside,
 			TrustBoundariesNested: trustBoundariesNested,
 		}
 		err = checkIdSyntax(id)
 		if err != nil {
-			return nil, err
+			problems.add("trust_boundaries."+title+".id", err)
 		}
 		if _, exists := parsedModel.TrustBoundaries[id]; exists {
-			return nil, fmt.Errorf("duplicate id used: %v", id)
+			problems.add("trust_boundaries."+title+".id", fmt.Errorf("duplicate id used: %v", id))
+			continue
 		}
 		parsedModel.TrustBoundaries[id] = trustBoundary
 		for _, technicalAsset := range trustBoundary.TechnicalAssetsInside {
@@ -478,7 +525,7 @@ func ParseModel(config *common.Config, modelInput *input.Model, builtinRiskRules
 	}
 	err = parsedModel.CheckNestedTrustBoundariesExisting()
 	if err != nil {
-		return nil, err
+		problems.add("trust_boundaries", err)
 	}
 
 	// Shared Runtime ===============================================================================
@@ -494,14 +541,15 @@ func ParseModel(config *common.Config, modelInput *input.Model, builtinRiskRules
 				assetId := fmt.Sprintf("%v", parsedRunningAsset)
 				err := parsedModel.CheckTechnicalAssetExists(assetId, "shared runtime '"+title+"'", false)
 				if err != nil {
-					return nil, err
+					problems.add("shared_runtimes."+title+".technical_assets_running", err)
+					continue
 				}
 				technicalAssetsRunning[i] = assetId
 			}
 		}
 		tags, err := parsedModel.CheckTags(lowerCaseAndTrim(inputRuntime.Tags), "shared runtime '"+title+"'")
 		if err != nil {
-			return nil, err
+			problems.add("shared_runtimes."+title+".tags", err)
 		}
 		sharedRuntime := &types.SharedRuntime{
 			Id:                     id,
@@ -512,10 +560,11 @@ func ParseModel(config *common.Config, modelInput *input.Model, builtinRiskRules
 		}
 		err = checkIdSyntax(id)
 		if err != nil {
-			return nil, err
+			problems.add("shared_runtimes."+title+".id", err)
 		}
 		if _, exists := parsedModel.SharedRuntimes[id]; exists {
-			return nil, fmt.Errorf("duplicate id used: %v", id)
+			problems.add("shared_runtimes."+title+".id", fmt.Errorf("duplicate id used: %v", id))
+			continue
 		}
 		parsedModel.SharedRuntimes[id] = sharedRuntime
 	}
@@ -532,12 +581,12 @@ func ParseModel(config *common.Config, modelInput *input.Model, builtinRiskRules
 	for _, customRiskCategoryCategory := range modelInput.CustomRiskCategories {
 		function, err := types.ParseRiskFunction(customRiskCategoryCategory.Function)
 		if err != nil {
-			return nil, fmt.Errorf("unknown 'function' value of individual risk category %q: %v", customRiskCategoryCategory.Title, customRiskCategoryCategory.Function)
+			problems.add("custom_risk_categories."+customRiskCategoryCategory.ID+".function", fmt.Errorf("unknown 'function' value of individual risk category %q: %v", customRiskCategoryCategory.Title, customRiskCategoryCategory.Function))
 		}
 
 		stride, err := types.ParseSTRIDE(customRiskCategoryCategory.STRIDE)
 		if err != nil {
-			return nil, fmt.Errorf("unknown 'stride' value of individual risk category  %q: %v", customRiskCategoryCategory.Title, customRiskCategoryCategory.STRIDE)
+			problems.add("custom_risk_categories."+customRiskCategoryCategory.ID+".stride", fmt.Errorf("unknown 'stride' value of individual risk category  %q: %v", customRiskCategoryCategory.Title, customRiskCategoryCategory.STRIDE))
 		}
 
 		cat := &types.RiskCategory{
@@ -565,11 +614,12 @@ func ParseModel(config *common.Config, modelInput *input.Model, builtinRiskRules
 
 		err = checkIdSyntax(customRiskCategoryCategory.ID)
 		if err != nil {
-			return nil, err
+			problems.add("custom_risk_categories."+customRiskCategoryCategory.ID+".id", err)
 		}
 
 		if !parsedModel.CustomRiskCategories.Add(cat) {
-			return nil, fmt.Errorf("duplicate id used: %v", customRiskCategoryCategory.ID)
+			problems.add("custom_risk_categories."+customRiskCategoryCategory.ID+".id", fmt.Errorf("duplicate id used: %v", customRiskCategoryCategory.ID))
+			continue
 		}
 
 		// NOW THE INDIVIDUAL RISK INSTANCES:
@@ -581,22 +631,22 @@ func ParseModel(config *common.Config, modelInput *input.Model, builtinRiskRules
 				var dataBreachTechnicalAssetIDs []string
 				severity, err := types.ParseRiskSeverity(individualRiskInstance.Severity)
 				if err != nil {
-					return nil, fmt.Errorf("unknown 'severity' value of individual risk instance %q: %v", title, individualRiskInstance.Severity)
+					problems.add("custom_risk_categories."+customRiskCategoryCategory.ID+".risks_identified."+title+".severity", fmt.Errorf("unknown 'severity' value of individual risk instance %q: %v", title, individualRiskInstance.Severity))
 				}
 				exploitationLikelihood, err := types.ParseRiskExploitationLikelihood(individualRiskInstance.ExploitationLikelihood)
 				if err != nil {
-					return nil, fmt.Errorf("unknown 'exploitation_likelihood' value of individual risk instance %q: %v", title, individualRiskInstance.ExploitationLikelihood)
+					problems.add("custom_risk_categories."+customRiskCategoryCategory.ID+".risks_identified."+title+".exploitation_likelihood", fmt.Errorf("unknown 'exploitation_likelihood' value of individual risk instance %q: %v", title, individualRiskInstance.ExploitationLikelihood))
 				}
 				exploitationImpact, err := types.ParseRiskExploitationImpact(individualRiskInstance.ExploitationImpact)
 				if err != nil {
-					return nil, fmt.Errorf("unknown 'exploitation_impact' value of individual risk instance %q: %v", title, individualRiskInstance.ExploitationImpact)
+					problems.add("custom_risk_categories."+customRiskCategoryCategory.ID+".risks_identified."+title+".exploitation_impact", fmt.Errorf("unknown 'exploitation_impact' value of individual risk instance %q: %v", title, individualRiskInstance.ExploitationImpact))
 				}
 
 				if len(individualRiskInstance.MostRelevantDataAsset) > 0 {
 					mostRelevantDataAssetId = fmt.Sprintf("%v", individualRiskInstance.MostRelevantDataAsset)
 					err := parsedModel.CheckDataAssetTargetExists(mostRelevantDataAssetId, fmt.Sprintf("individual risk %q", title))
 					if err != nil {
-						return nil, err
+						problems.add("custom_risk_categories."+customRiskCategoryCategory.ID+".risks_identified."+title+".most_relevant_data_asset", err)
 					}
 				}
 
@@ -604,7 +654,7 @@ func ParseModel(config *common.Config, modelInput *input.Model, builtinRiskRules
 					mostRelevantTechnicalAssetId = fmt.Sprintf("%v", individualRiskInstance.MostRelevantTechnicalAsset)
 					err := parsedModel.CheckTechnicalAssetExists(mostRelevantTechnicalAssetId, fmt.Sprintf("individual risk %q", title), false)
 					if err != nil {
-						return nil, err
+						problems.add("custom_risk_categories."+customRiskCategoryCategory.ID+".risks_identified."+title+".most_relevant_technical_asset", err)
 					}
 				}
 
@@ -612,7 +662,7 @@ func ParseModel(config *common.Config, modelInput *input.Model, builtinRiskRules
 					mostRelevantCommunicationLinkId = fmt.Sprintf("%v", individualRiskInstance.MostRelevantCommunicationLink)
 					err := parsedModel.CheckCommunicationLinkExists(mostRelevantCommunicationLinkId, fmt.Sprintf("individual risk %q", title))
 					if err != nil {
-						return nil, err
+						problems.add("custom_risk_categories."+customRiskCategoryCategory.ID+".risks_identified."+title+".most_relevant_communication_link", err)
 					}
 				}
 
@@ -620,7 +670,7 @@ func ParseModel(config *common.Config, modelInput *input.Model, builtinRiskRules
 					mostRelevantTrustBoundaryId = fmt.Sprintf("%v", individualRiskInstance.MostRelevantTrustBoundary)
 					err := parsedModel.CheckTrustBoundaryExists(mostRelevantTrustBoundaryId, fmt.Sprintf("individual risk %q", title))
 					if err != nil {
-						return nil, err
+						problems.add("custom_risk_categories."+customRiskCategoryCategory.ID+".risks_identified."+title+".most_relevant_trust_boundary", err)
 					}
 				}
 
@@ -628,13 +678,13 @@ func ParseModel(config *common.Config, modelInput *input.Model, builtinRiskRules
 					mostRelevantSharedRuntimeId = fmt.Sprintf("%v", individualRiskInstance.MostRelevantSharedRuntime)
 					err := parsedModel.CheckSharedRuntimeExists(mostRelevantSharedRuntimeId, fmt.Sprintf("individual risk %q", title))
 					if err != nil {
-						return nil, err
+						problems.add("custom_risk_categories."+customRiskCategoryCategory.ID+".risks_identified."+title+".most_relevant_shared_runtime", err)
 					}
 				}
 
 				dataBreachProbability, err = types.ParseDataBreachProbability(individualRiskInstance.DataBreachProbability)
 				if err != nil {
-					return nil, fmt.Errorf("unknown 'data_breach_probability' value of individual risk instance %q: %v", title, individualRiskInstance.DataBreachProbability)
+					problems.add("custom_risk_categories."+customRiskCategoryCategory.ID+".risks_identified."+title+".data_breach_probability", fmt.Errorf("unknown 'data_breach_probability' value of individual risk instance %q: %v", title, individualRiskInstance.DataBreachProbability))
 				}
 
 				if individualRiskInstance.DataBreachTechnicalAssets != nil {
@@ -643,7 +693,7 @@ func ParseModel(config *common.Config, modelInput *input.Model, builtinRiskRules
 						assetId := fmt.Sprintf("%v", parsedReferencedAsset)
 						err := parsedModel.CheckTechnicalAssetExists(assetId, fmt.Sprintf("data breach technical assets of individual risk %q", title), false)
 						if err != nil {
-							return nil, err
+							problems.add("custom_risk_categories."+customRiskCategoryCategory.ID+".risks_identified."+title+".data_breach_technical_assets", err)
 						}
 						dataBreachTechnicalAssetIDs[i] = assetId
 					}
@@ -654,6 +704,7 @@ func ParseModel(config *common.Config, modelInput *input.Model, builtinRiskRules
 					Title:                           title,
 					CategoryId:                      cat.ID,
 					Severity:                        severity,
+					InherentSeverity:                severity,
 					ExploitationLikelihood:          exploitationLikelihood,
 					ExploitationImpact:              exploitationImpact,
 					MostRelevantDataAssetId:         mostRelevantDataAssetId,
@@ -668,6 +719,129 @@ func ParseModel(config *common.Config, modelInput *input.Model, builtinRiskRules
 		}
 	}
 
+	// Security Controls ===============================================================================
+	parsedModel.SecurityControls = make(map[string]*types.SecurityControl)
+	for title, inputControl := range modelInput.SecurityControls {
+		id := fmt.Sprintf("%v", inputControl.ID)
+
+		for _, assetId := range inputControl.TechnicalAssets {
+			err := parsedModel.CheckTechnicalAssetExists(assetId, "security control '"+title+"'", false)
+			if err != nil {
+				problems.add("security_controls."+title+".technical_assets", err)
+			}
+		}
+		for _, boundaryId := range inputControl.TrustBoundaries {
+			err := parsedModel.CheckTrustBoundaryExists(boundaryId, "security control '"+title+"'")
+			if err != nil {
+				problems.add("security_controls."+title+".trust_boundaries", err)
+			}
+		}
+		if inputControl.LikelihoodReduction < 0 {
+			problems.add("security_controls."+title+".likelihood_reduction", fmt.Errorf("negative 'likelihood_reduction' of security control %q: %v", title, inputControl.LikelihoodReduction))
+		}
+
+		mitigates := make([]string, 0, len(inputControl.Mitigates))
+		for _, categoryId := range inputControl.Mitigates {
+			category := types.GetRiskCategory(&parsedModel, categoryId)
+			if category == nil {
+				problems.add("security_controls."+title+".mitigates", fmt.Errorf("missing referenced risk category at security control %q: %v", title, categoryId))
+				continue
+			}
+			mitigates = append(mitigates, category.ID) // as found regardless of case, the id of the risks
+		}
+		controlType := strings.ToLower(strings.TrimSpace(inputControl.Type))
+		if len(controlType) > 0 && !slices.Contains(types.SecurityControlTypes, controlType) {
+			problems.add("security_controls."+title+".type", fmt.Errorf("unknown 'type' value of security control %q (must be one of %v): %v", title, types.SecurityControlTypes, inputControl.Type))
+		}
+
+		securityControl := &types.SecurityControl{
+			Id:                  id,
+			Title:               title,
+			Description:         withDefault(fmt.Sprintf("%v", inputControl.Description), title),
+			Type:                controlType,
+			LikelihoodReduction: inputControl.LikelihoodReduction,
+			Mitigates:           mitigates,
+			TechnicalAssets:     inputControl.TechnicalAssets,
+			TrustBoundaries:     inputControl.TrustBoundaries,
+		}
+		err := checkIdSyntax(id)
+		if err != nil {
+			problems.add("security_controls."+title+".id", err)
+		}
+		if _, exists := parsedModel.SecurityControls[id]; exists {
+			problems.add("security_controls."+title+".id", fmt.Errorf("duplicate id used: %v", id))
+			continue
+		}
+		parsedModel.SecurityControls[id] = securityControl
+	}
+
+	// Pen-Test Findings ===============================================================================
+	parsedModel.PenTestFindings = make(map[string]*types.PenTestFinding)
+	for title, inputFinding := range modelInput.PenTestFindings {
+		id := fmt.Sprintf("%v", inputFinding.ID)
+
+		for _, assetId := range inputFinding.TechnicalAssets {
+			err := parsedModel.CheckTechnicalAssetExists(assetId, "pen-test finding '"+title+"'", false)
+			if err != nil {
+				problems.add("findings."+title+".technical_assets", err)
+			}
+		}
+		riskCategories := make([]string, 0, len(inputFinding.RiskCategories))
+		for _, categoryId := range inputFinding.RiskCategories {
+			category := types.GetRiskCategory(&parsedModel, categoryId)
+			if category == nil {
+				problems.add("findings."+title+".risk_categories", fmt.Errorf("missing referenced risk category at pen-test finding %q: %v", title, categoryId))
+				continue
+			}
+			riskCategories = append(riskCategories, category.ID) // as found regardless of case, the id of the risks
+		}
+
+		var date time.Time
+		if len(inputFinding.Date) > 0 {
+			var parseError error
+			date, parseError = time.Parse("2006-01-02", inputFinding.Date)
+			if parseError != nil {
+				problems.add("findings."+title+".date", fmt.Errorf("unable to parse 'date' of pen-test finding %q: %v", title, inputFinding.Date))
+			}
+		}
+
+		severity, err := types.ParseRiskSeverity(inputFinding.Severity)
+		if err != nil {
+			problems.add("findings."+title+".severity", fmt.Errorf("unknown 'severity' value of pen-test finding %q: %v", title, inputFinding.Severity))
+		}
+
+		result := strings.ToLower(strings.TrimSpace(inputFinding.Result))
+		if result != types.PenTestConfirmed && result != types.PenTestNotConfirmed { // no default, as either one claims evidence
+			problems.add("findings."+title+".result", fmt.Errorf("missing or unknown 'result' value of pen-test finding %q (must be %v or %v): %v", title, types.PenTestConfirmed, types.PenTestNotConfirmed, inputFinding.Result))
+		}
+
+		finding := &types.PenTestFinding{
+			Id:              id,
+			Title:           title,
+			Description:     withDefault(fmt.Sprintf("%v", inputFinding.Description), title),
+			Date:            types.Date{Time: date},
+			Tester:          strings.TrimSpace(inputFinding.Tester),
+			Reference:       strings.TrimSpace(inputFinding.Reference),
+			Severity:        severity,
+			Confirmed:       result == types.PenTestConfirmed,
+			TechnicalAssets: inputFinding.TechnicalAssets,
+			RiskCategories:  riskCategories,
+		}
+		err = checkIdSyntax(id)
+		if err != nil {
+			problems.add("findings."+title+".id", err)
+		}
+		if _, exists := parsedModel.PenTestFindings[id]; exists {
+			problems.add("findings."+title+".id", fmt.Errorf("duplicate id used: %v", id))
+			continue
+		}
+		parsedModel.PenTestFindings[id] = finding
+	}
+
+	// Assumptions and Scope Exclusions ===============================================================================
+	parsedModel.Assumptions = parseAssumptions(&parsedModel, "assumptions", "assumption", modelInput.Assumptions, &problems)
+	parsedModel.ScopeExclusions = parseAssumptions(&parsedModel, "scope_exclusions", "scope exclusion", modelInput.ScopeExclusions, &problems)
+
 	// Risk Tracking ===============================================================================
 	parsedModel.RiskTracking = make(map[string]*types.RiskTracking)
 	for syntheticRiskId, riskTracking := range modelInput.RiskTracking {
@@ -679,13 +853,25 @@ func ParseModel(config *common.Config, modelInput *input.Model, builtinRiskRules
 			var parseError error
 			date, parseError = time.Parse("2006-01-02", riskTracking.Date)
 			if parseError != nil {
-				return nil, fmt.Errorf("unable to parse 'date' of risk tracking %q: %v", syntheticRiskId, riskTracking.Date)
+				problems.add("risk_tracking."+syntheticRiskId+".date", fmt.Errorf("unable to parse 'date' of risk tracking %q: %v", syntheticRiskId, riskTracking.Date))
+				continue
+			}
+		}
+
+		var dueDate time.Time
+		if len(riskTracking.DueDate) > 0 {
+			var parseError error
+			dueDate, parseError = time.Parse("2006-01-02", riskTracking.DueDate)
+			if parseError != nil {
+				problems.add("risk_tracking."+syntheticRiskId+".due_date", fmt.Errorf("unable to parse 'due_date' of risk tracking %q: %v", syntheticRiskId, riskTracking.DueDate))
+				continue
 			}
 		}
 
 		status, err := types.ParseRiskStatus(riskTracking.Status)
 		if err != nil {
-			return nil, fmt.Errorf("unknown 'status' value of risk tracking %q: %v", syntheticRiskId, riskTracking.Status)
+			problems.add("risk_tracking."+syntheticRiskId+".status", fmt.Errorf("unknown 'status' value of risk tracking %q: %v", syntheticRiskId, riskTracking.Status))
+			continue
 		}
 
 		tracking := &types.RiskTracking{
@@ -695,19 +881,43 @@ func ParseModel(config *common.Config, modelInput *input.Model, builtinRiskRules
 			Ticket:          ticket,
 			Date:            types.Date{Time: date},
 			Status:          status,
+			Assignee:        strings.TrimSpace(riskTracking.Assignee),
+			DueDate:         types.Date{Time: dueDate},
 		}
 
 		parsedModel.RiskTracking[syntheticRiskId] = tracking
 	}
 
-	// ====================== model consistency check (linking)
-	for _, technicalAsset := range parsedModel.TechnicalAssets {
-		for _, commLink := range technicalAsset.CommunicationLinks {
-			err := parsedModel.CheckTechnicalAssetExists(commLink.TargetId, "communication link '"+commLink.Title+"' of technical asset '"+technicalAsset.Title+"'", false)
-			if err != nil {
-				return nil, err
+	// Annotations ===============================================================================
+	// the annotated elements are not checked, as the review notes may outlive them (e.g. a risk mitigated since)
+	parsedModel.Annotations = make([]*types.ElementAnnotation, 0, len(modelInput.Annotations))
+	for i, inputAnnotation := range modelInput.Annotations {
+		field := "annotations." + strconv.Itoa(i)
+		element := strings.TrimSpace(inputAnnotation.Element)
+		if len(element) == 0 {
+			problems.add(field+".element", fmt.Errorf("missing 'element' of annotation #%d", i+1))
+			continue
+		}
+		var date time.Time
+		if len(inputAnnotation.Date) > 0 {
+			var parseError error
+			date, parseError = time.Parse("2006-01-02", inputAnnotation.Date)
+			if parseError != nil {
+				problems.add(field+".date", fmt.Errorf("unable to parse 'date' of annotation of %q: %v", element, inputAnnotation.Date))
+				continue
 			}
 		}
+		parsedModel.Annotations = append(parsedModel.Annotations, &types.ElementAnnotation{
+			Element: element,
+			Note:    strings.TrimSpace(inputAnnotation.Note),
+			Author:  strings.TrimSpace(inputAnnotation.Author),
+			Date:    types.Date{Time: date},
+		})
+	}
+
+	if len(problems) > 0 {
+		problems.locate(modelInput)
+		return nil, errors.Join(problems...)
 	}
 
 	/*
@@ -732,6 +942,37 @@ func ParseModel(config *common.Config, modelInput *input.Model, builtinRiskRules
 	return &parsedModel, nil
 }
 
+// parseAssumptions parses the assumptions or scope exclusions (of the section of the model), which need the owner
+// accountable for them and the rationale to be of use for the readers of the report
+func parseAssumptions(parsedModel *types.Model, section string, kind string, inputAssumptions map[string]input.Assumption, problems *modelProblems) map[string]*types.Assumption {
+	assumptions := make(map[string]*types.Assumption)
+	for title, inputAssumption := range inputAssumptions {
+		field := section + "." + title
+		for _, assetId := range inputAssumption.TechnicalAssets {
+			err := parsedModel.CheckTechnicalAssetExists(assetId, kind+" '"+title+"'", false)
+			if err != nil {
+				problems.add(field+".technical_assets", err)
+			}
+		}
+		owner := strings.TrimSpace(inputAssumption.Owner)
+		if len(owner) == 0 {
+			problems.add(field+".owner", fmt.Errorf("missing 'owner' of %v %q", kind, title))
+		}
+		rationale := strings.TrimSpace(inputAssumption.Rationale)
+		if len(rationale) == 0 {
+			problems.add(field+".rationale", fmt.Errorf("missing 'rationale' of %v %q", kind, title))
+		}
+		assumptions[title] = &types.Assumption{
+			Title:           title,
+			Description:     strings.TrimSpace(inputAssumption.Description),
+			Owner:           owner,
+			Rationale:       rationale,
+			TechnicalAssets: inputAssumption.TechnicalAssets,
+		}
+	}
+	return assumptions
+}
+
 func checkIdSyntax(id string) error {
 	validIdSyntax := regexp.MustCompile(`^[a-zA-Z0-9\-]+$`)
 	if !validIdSyntax.MatchString(id) {
@@ -740,7 +981,8 @@ func checkIdSyntax(id string) error {
 	return nil
 }
 
-func createDataFlowId(sourceAssetId, title string) (string, error) {
+// CreateDataFlowId is the id of the communication link with the title of the technical asset with the id
+func CreateDataFlowId(sourceAssetId, title string) (string, error) {
 	reg, err := regexp.Compile("[^A-Za-z0-9]+")
 	if err != nil {
 		return "", err
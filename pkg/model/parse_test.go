@@ -150,6 +150,128 @@ func TestInferAvailability_ExpectHighestAvailability(t *testing.T) {
 	assert.Equal(t, types.Operational, parsedModel.TechnicalAssets[taWithArchiveAvailabilityDataAsset.ID].Availability)
 }
 
+func TestParseAnnotations(t *testing.T) {
+	ta := make(map[string]input.TechnicalAsset)
+	ta["Web Server"] = createTechnicalAsset(types.Internal, types.Operational, types.Operational)
+	modelInput := createInputModel(ta, make(map[string]input.DataAsset))
+	modelInput.Annotations = []input.ElementAnnotation{
+		{Element: ta["Web Server"].ID, Note: "Is the admin UI exposed?", Author: "Alice", Date: "2024-03-02"},
+		{Element: " removed-asset ", Note: "Kept after the asset was removed"},
+	}
+
+	parsedModel, err := ParseModel(&common.Config{}, modelInput, make(types.RiskRules), make(types.RiskRules))
+	assert.NoError(t, err)
+	assert.Len(t, parsedModel.Annotations, 2)
+	assert.Equal(t, "Alice", parsedModel.Annotations[0].Author)
+	assert.Equal(t, "2024-03-02", parsedModel.Annotations[0].Date.Format("2006-01-02"))
+	assert.Equal(t, "removed-asset", parsedModel.Annotations[1].Element)
+	title, found := parsedModel.ElementTitle(ta["Web Server"].ID)
+	assert.True(t, found)
+	assert.Equal(t, "Web Server", title)
+	_, found = parsedModel.ElementTitle("removed-asset")
+	assert.False(t, found)
+
+	modelInput.Annotations = []input.ElementAnnotation{{Element: ta["Web Server"].ID, Date: "March"}, {Note: "no element"}}
+	_, err = ParseModel(&common.Config{}, modelInput, make(types.RiskRules), make(types.RiskRules))
+	assert.ErrorContains(t, err, "unable to parse 'date' of annotation")
+	assert.ErrorContains(t, err, "missing 'element' of annotation #2")
+}
+
+func TestParseAssumptions(t *testing.T) {
+	ta := make(map[string]input.TechnicalAsset)
+	ta["Web Server"] = createTechnicalAsset(types.Internal, types.Operational, types.Operational)
+	modelInput := createInputModel(ta, make(map[string]input.DataAsset))
+	modelInput.Assumptions = map[string]input.Assumption{
+		"Patched Hosts": {Owner: " Ops ", Rationale: "Managed hosting", TechnicalAssets: []string{ta["Web Server"].ID}},
+	}
+	modelInput.ScopeExclusions = map[string]input.Assumption{
+		"Mobile App": {Description: "Assessed separately", Owner: "Mobile Team", Rationale: "Own threat model"},
+	}
+
+	parsedModel, err := ParseModel(&common.Config{}, modelInput, make(types.RiskRules), make(types.RiskRules))
+	assert.NoError(t, err)
+	assert.Equal(t, []*types.Assumption{{Title: "Patched Hosts", Owner: "Ops", Rationale: "Managed hosting", TechnicalAssets: []string{ta["Web Server"].ID}}}, parsedModel.SortedAssumptions())
+	assert.Equal(t, "Assessed separately", parsedModel.ScopeExclusions["Mobile App"].Description)
+
+	modelInput.Assumptions = map[string]input.Assumption{"Patched Hosts": {Rationale: "Managed hosting", TechnicalAssets: []string{"missing-asset"}}}
+	modelInput.ScopeExclusions = map[string]input.Assumption{"Mobile App": {Owner: "Mobile Team"}}
+	_, err = ParseModel(&common.Config{}, modelInput, make(types.RiskRules), make(types.RiskRules))
+	fields := make([]string, 0)
+	for _, problem := range ModelProblems(err) {
+		fields = append(fields, problem.Field)
+	}
+	assert.ElementsMatch(t, []string{"assumptions.Patched Hosts.owner", "assumptions.Patched Hosts.technical_assets", "scope_exclusions.Mobile App.rationale"}, fields)
+}
+
+func TestParsePenTestFindings(t *testing.T) {
+	ta := make(map[string]input.TechnicalAsset)
+	ta["Web Server"] = createTechnicalAsset(types.Internal, types.Operational, types.Operational)
+	modelInput := createInputModel(ta, make(map[string]input.DataAsset))
+	modelInput.PenTestFindings = map[string]input.PenTestFinding{
+		"Admin UI": {ID: "admin-ui", Result: "Confirmed", RiskCategories: []string{"Test-Rule"}},
+	}
+	rules := types.RiskRules{"test-rule": &overrideTestRule{}}
+
+	parsedModel, err := ParseModel(&common.Config{}, modelInput, rules, make(types.RiskRules))
+	assert.NoError(t, err)
+	finding := parsedModel.PenTestFindings["admin-ui"]
+	assert.True(t, finding.Confirmed)
+	assert.Equal(t, []string{"test-rule"}, finding.RiskCategories, "by the id of the risks")
+	assert.True(t, finding.Covers(&types.Risk{CategoryId: "test-rule", MostRelevantTechnicalAssetId: ta["Web Server"].ID}))
+
+	modelInput.PenTestFindings = map[string]input.PenTestFinding{
+		"Admin UI": {ID: "admin-ui", RiskCategories: []string{"test-rule", "missing-rule"}},
+	}
+	_, err = ParseModel(&common.Config{}, modelInput, rules, make(types.RiskRules))
+	fields := make([]string, 0)
+	for _, problem := range ModelProblems(err) {
+		fields = append(fields, problem.Field)
+	}
+	assert.ElementsMatch(t, []string{"findings.Admin UI.result", "findings.Admin UI.risk_categories"}, fields)
+}
+
+func TestParseSecurityControls(t *testing.T) {
+	ta := make(map[string]input.TechnicalAsset)
+	ta["Web Server"] = createTechnicalAsset(types.Internal, types.Operational, types.Operational)
+	modelInput := createInputModel(ta, make(map[string]input.DataAsset))
+	modelInput.SecurityControls = map[string]input.SecurityControl{
+		"Web Application Firewall": {ID: "waf", Type: " WAF", Mitigates: []string{"Test-Rule"}, TechnicalAssets: []string{ta["Web Server"].ID}},
+	}
+	rules := types.RiskRules{"test-rule": &overrideTestRule{}}
+
+	parsedModel, err := ParseModel(&common.Config{}, modelInput, rules, make(types.RiskRules))
+	assert.NoError(t, err)
+	control := parsedModel.SecurityControls["waf"]
+	assert.Equal(t, "waf", control.Type)
+	assert.Equal(t, []string{"test-rule"}, control.Mitigates, "by the id of the risks")
+
+	modelInput.SecurityControls = map[string]input.SecurityControl{
+		"Web Application Firewall": {ID: "waf", Type: "magic", Mitigates: []string{"test-rule", "missing-rule"}},
+	}
+	_, err = ParseModel(&common.Config{}, modelInput, rules, make(types.RiskRules))
+	fields := make([]string, 0)
+	for _, problem := range ModelProblems(err) {
+		fields = append(fields, problem.Field)
+	}
+	assert.ElementsMatch(t, []string{"security_controls.Web Application Firewall.type", "security_controls.Web Application Firewall.mitigates"}, fields)
+}
+
+func TestParseIndividualRisks(t *testing.T) {
+	ta := make(map[string]input.TechnicalAsset)
+	ta["Web Server"] = createTechnicalAsset(types.Internal, types.Operational, types.Operational)
+	modelInput := createInputModel(ta, make(map[string]input.DataAsset))
+	modelInput.CustomRiskCategories = input.RiskCategories{{ID: "manual-review", Title: "Manual Review", Function: "business-side", STRIDE: "tampering",
+		RisksIdentified: map[string]input.RiskIdentified{"Unreviewed <b>Web Server</b>": {Severity: "high", ExploitationLikelihood: "likely", ExploitationImpact: "medium",
+			DataBreachProbability: "possible", MostRelevantTechnicalAsset: ta["Web Server"].ID}}}}
+
+	parsedModel, err := ParseModel(&common.Config{}, modelInput, make(types.RiskRules), make(types.RiskRules))
+	assert.NoError(t, err)
+	risks := parsedModel.GeneratedRisksByCategory["manual-review"]
+	assert.Len(t, risks, 1)
+	assert.Equal(t, types.HighSeverity, risks[0].Severity)
+	assert.Equal(t, types.HighSeverity, risks[0].InherentSeverity, "like the ones of the rules")
+}
+
 func createInputModel(technicalAssets map[string]input.TechnicalAsset, dataAssets map[string]input.DataAsset) *input.Model {
 	return &input.Model{
 		TechnicalAssets: technicalAssets,
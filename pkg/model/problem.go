@@ -0,0 +1,57 @@
+package model
+
+import (
+	"errors"
+
+	"github.com/threagile/threagile/pkg/input"
+)
+
+// ModelProblem is a reason for the model to be invalid, located by the path of the model field it is about (like
+// "technical_assets.Some Component.communication_links.Some Traffic.protocol")
+type ModelProblem struct {
+	File    string `json:"file,omitempty"` // the model file (or include) the field was loaded from
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+func (what *ModelProblem) Error() string {
+	return what.Message
+}
+
+// modelProblems collects all problems found while parsing the model, instead of stopping at the first one
+type modelProblems []error
+
+func (what *modelProblems) add(field string, err error) {
+	*what = append(*what, &ModelProblem{Field: field, Message: err.Error()})
+}
+
+// locate sets the model files of the problems to the ones their fields were loaded from
+func (what *modelProblems) locate(modelInput *input.Model) {
+	for _, err := range *what {
+		if problem, ok := err.(*ModelProblem); ok {
+			problem.File = modelInput.SourceOf(problem.Field)
+		}
+	}
+}
+
+// ModelProblems unwraps the problems of the error returned by ParseModel, errors not located at a model field are
+// answered without field
+func ModelProblems(err error) []*ModelProblem {
+	if err == nil {
+		return nil
+	}
+	reasons := []error{err}
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		reasons = joined.Unwrap()
+	}
+	problems := make([]*ModelProblem, 0, len(reasons))
+	for _, reason := range reasons {
+		var problem *ModelProblem
+		if errors.As(reason, &problem) {
+			problems = append(problems, problem)
+		} else {
+			problems = append(problems, &ModelProblem{Message: reason.Error()})
+		}
+	}
+	return problems
+}
@@ -1,9 +1,14 @@
 package model
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"path/filepath"
+	"runtime/debug"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/threagile/threagile/pkg/common"
 	"github.com/threagile/threagile/pkg/input"
@@ -17,6 +22,17 @@ type ReadResult struct {
 	IntroTextRAA     string
 	BuiltinRiskRules types.RiskRules
 	CustomRiskRules  types.RiskRules
+	ReportSections   []types.ReportSection
+	RiskRuleFailures []RiskRuleFailure
+	Result           *Result // snapshot of the analyzed model for the writers of the outputs
+}
+
+// RiskRuleFailure is a risk rule which failed (returned an error or panicked) while generating its risks, the analysis
+// continues without its risks unless the rules are strict
+type RiskRuleFailure struct {
+	RuleID string `json:"rule_id" yaml:"rule_id"`
+	Error  string `json:"error" yaml:"error"`
+	Stack  string `json:"stack,omitempty" yaml:"stack,omitempty"`
 }
 
 func (what ReadResult) ExplainRisk(cfg *common.Config, risk string, reporter common.DefaultProgressReporter) error {
@@ -25,22 +41,50 @@ func (what ReadResult) ExplainRisk(cfg *common.Config, risk string, reporter com
 
 // TODO: consider about splitting this function into smaller ones for better reusability
 
-func ReadAndAnalyzeModel(config *common.Config, progressReporter types.ProgressReporter) (*ReadResult, error) {
+func ReadAndAnalyzeModel(ctx context.Context, config *common.Config, progressReporter types.ProgressReporter) (*ReadResult, error) {
 	progressReporter.Infof("Writing into output directory: %v", config.OutputFolder)
 	progressReporter.Infof("Parsing model: %v", config.InputFile)
+	timing := Timing{Started: time.Now()}
+
+	// the plugins keep the unlimited context, as custom report sections are run later on when rendering the report
+	analysisCtx, cancel := common.WithTimeout(ctx, config.AnalysisTimeoutSeconds)
+	defer cancel()
 
 	builtinRiskRules := risks.GetBuiltInRiskRules()
-	customRiskRules := LoadCustomRiskRules(config.RiskRulesPlugins, progressReporter)
+	customRiskRules := LoadCustomRiskRules(ctx, config.RiskRulesPlugins, config.PluginTimeoutSeconds, progressReporter)
+	customRiskRules.Merge(LoadRegoRiskRules(ctx, config.RiskRulesRegoFolder, config.PluginTimeoutSeconds, progressReporter))
+	customRiskRules.Merge(LoadScriptRiskRules(ctx, config.RiskRulesScripts, config.PluginTimeoutSeconds, progressReporter))
 
-	modelInput := new(input.Model).Defaults()
-	loadError := modelInput.Load(config.InputFile)
+	overridesError := ApplyRiskCategoryOverrides(config.RiskCategoryOverridesFile, progressReporter, builtinRiskRules, customRiskRules)
+	if overridesError != nil {
+		return nil, common.NewFailure(common.ExitCodeRuleFailure, overridesError)
+	}
+
+	modelInput, loadError := loadModelInput(analysisCtx, config, progressReporter)
 	if loadError != nil {
-		return nil, fmt.Errorf("unable to load model yaml: %v", loadError)
+		return nil, loadError
+	}
+
+	if config.AutoSeedTagsAvailable {
+		for _, tag := range modelInput.SeedTagsAvailable() {
+			progressReporter.Warnf("Tag is used but missing in tags_available, adding it: %v", tag)
+		}
 	}
 
 	parsedModel, parseError := ParseModel(config, modelInput, builtinRiskRules, customRiskRules)
 	if parseError != nil {
-		return nil, fmt.Errorf("unable to parse model yaml: %v", parseError)
+		return nil, common.NewFailure(common.ExitCodeValidationError, fmt.Errorf("unable to parse model yaml: %v", parseError))
+	}
+
+	timing.Parsing = time.Since(timing.Started)
+
+	for _, tag := range parsedModel.TagsNotUsed() {
+		progressReporter.Warnf("Tag is available but not used: %v", tag)
+	}
+
+	sbomError := applySBOMs(ctx, parsedModel, filepath.Dir(config.InputFile), config.SBOMFetchURLs && !config.ServerMode, progressReporter)
+	if sbomError != nil {
+		return nil, common.NewFailure(common.ExitCodeValidationError, sbomError)
 	}
 
 	/**
@@ -51,68 +95,204 @@ func ReadAndAnalyzeModel(config *common.Config, progressReporter types.ProgressR
 	_ = os.WriteFile("parsed-model.yaml", yamlData, 0600)
 	/**/
 
-	introTextRAA := applyRAA(parsedModel, config.PluginFolder, config.RAAPlugin, progressReporter)
+	threatIntel := new(ThreatIntelFeed)
+	threatIntelError := threatIntel.Load(config.ThreatIntelFeed, config.TempFolder, time.Duration(config.ThreatIntelCacheHours)*time.Hour, progressReporter)
+	if threatIntelError != nil {
+		return nil, threatIntelError
+	}
+
+	introTextRAA := applyRAA(analysisCtx, parsedModel, config.PluginFolder, config.RAAPlugin, config.PluginTimeoutSeconds, progressReporter)
+	threatIntel.ApplyToRAA(parsedModel, progressReporter)
+	if canceledError := common.CheckCanceled(analysisCtx, "RAA calculation"); canceledError != nil {
+		return nil, canceledError
+	}
+
+	directoryError := applyOwnerDirectory(analysisCtx, parsedModel, config.PluginFolder, config.OwnerDirectoryPlugin, config.OwnerDirectoryStrict, config.PluginTimeoutSeconds, progressReporter)
+	if canceledError := common.CheckCanceled(analysisCtx, "owner directory lookup"); canceledError != nil {
+		return nil, canceledError
+	}
+	if directoryError != nil {
+		return nil, common.NewFailure(common.ExitCodeValidationError, directoryError)
+	}
+
+	riskGenerationStarted := time.Now()
+	riskRuleFailures := applyRiskGeneration(analysisCtx, parsedModel, builtinRiskRules.Merge(customRiskRules), config.SkipRiskRules, config.OnlyRiskRules, progressReporter)
+	timing.RiskGeneration = time.Since(riskGenerationStarted)
+	if canceledError := common.CheckCanceled(analysisCtx, "risk generation"); canceledError != nil {
+		return nil, canceledError
+	}
+	if config.StrictRules && len(riskRuleFailures) > 0 {
+		return nil, common.NewFailure(common.ExitCodeRuleFailure, fmt.Errorf("risk rule %q failed: %v", riskRuleFailures[0].RuleID, riskRuleFailures[0].Error))
+	}
+	threatIntel.ApplyToRisks(parsedModel)
+	parsedModel.ApplySecurityControls()
+	parsedModel.ApplyPenTestFindings()
+	err := parsedModel.RecalibrateSeverities(config.SeverityRecalibration)
+	if err != nil {
+		return nil, common.NewFailure(common.ExitCodeRuleFailure, fmt.Errorf("unable to recalibrate risk severities: %v", err))
+	}
+
+	err = parsedModel.ApplyWildcardRiskTrackingEvaluation(config.IgnoreOrphanedRiskTracking, progressReporter)
+	if err != nil {
+		return nil, common.NewFailure(common.ExitCodeValidationError, fmt.Errorf("unable to apply wildcard risk tracking evaluation: %v", err))
+	}
 
-	applyRiskGeneration(parsedModel, builtinRiskRules.Merge(customRiskRules), config.SkipRiskRules, progressReporter)
-	err := parsedModel.ApplyWildcardRiskTrackingEvaluation(config.IgnoreOrphanedRiskTracking, progressReporter)
+	// merged before checking the risk tracking, which carries the tracking of the merged-away risks over
+	err = parsedModel.MergeDuplicateRisks(config.RiskMerge.Groups, config.RiskMerge.Keys, progressReporter)
 	if err != nil {
-		return nil, fmt.Errorf("unable to apply wildcard risk tracking evaluation: %v", err)
+		return nil, common.NewFailure(common.ExitCodeRuleFailure, fmt.Errorf("unable to merge duplicate risks: %v", err))
 	}
 
 	err = parsedModel.CheckRiskTracking(config.IgnoreOrphanedRiskTracking, progressReporter)
 	if err != nil {
-		return nil, fmt.Errorf("unable to check risk tracking: %v", err)
+		return nil, common.NewFailure(common.ExitCodeValidationError, fmt.Errorf("unable to check risk tracking: %v", err))
 	}
 
+	parsedModel.ApplyResidualSeverities()
+
+	commentsError := applyRiskComments(parsedModel, config.RiskCommentsFile, progressReporter)
+	if commentsError != nil {
+		return nil, common.NewFailure(common.ExitCodeValidationError, commentsError)
+	}
+
+	findingsError := applyScannerFindings(parsedModel, config.ScannerFindingsFiles, progressReporter)
+	if findingsError != nil {
+		return nil, common.NewFailure(common.ExitCodeValidationError, findingsError)
+	}
+
+	timing.Total = time.Since(timing.Started)
 	return &ReadResult{
 		ModelInput:       modelInput,
 		ParsedModel:      parsedModel,
 		IntroTextRAA:     introTextRAA,
 		BuiltinRiskRules: builtinRiskRules,
 		CustomRiskRules:  customRiskRules,
+		ReportSections:   LoadCustomReportSections(ctx, config.ReportSectionPlugins, config.PluginTimeoutSeconds, progressReporter),
+		RiskRuleFailures: riskRuleFailures,
+		Result:           NewResult(parsedModel, riskRuleFailures, timing),
 	}, nil
 }
 
-func applyRiskGeneration(parsedModel *types.Model, rules types.RiskRules,
-	skipRiskRules []string,
-	progressReporter types.ProgressReporter) {
+// loadModelInput loads the model file with its templating expanded, the model file and each of its includes as
+// preprocessed by the pre-parse hooks if there are any
+func loadModelInput(ctx context.Context, config *common.Config, progressReporter types.ProgressReporter) (*input.Model, error) {
+	read, templatingError := input.TemplatingReaderOf(modelReader(ctx, config), config.ModelTemplating)
+	if templatingError != nil {
+		return nil, templatingError
+	}
+
+	if hasHooks(config, PreParseHookStage) {
+		readUnhooked := read
+		read = func(filename string) ([]byte, error) {
+			modelData, readError := readUnhooked(filename)
+			if readError != nil {
+				return nil, readError
+			}
+			event := &HookEvent{Stage: PreParseHookStage, ModelFile: filename, ModelData: modelData, OutputFolder: config.OutputFolder}
+			hookError := RunHooks(ctx, config, event, progressReporter)
+			if hookError != nil {
+				return nil, common.NewFailure(common.ExitCodeOf(hookError), hookError) // not a parse error
+			}
+			return event.ModelData, nil
+		}
+	}
+
+	modelInput := new(input.Model).Defaults()
+	loadError := modelInput.LoadWith(config.InputFile, read)
+	if loadError != nil {
+		var failure *common.Failure
+		if errors.As(loadError, &failure) {
+			return nil, loadError // of a hook
+		}
+		return nil, common.NewFailure(common.ExitCodeParseError, fmt.Errorf("unable to load model yaml: %v", loadError))
+	}
+	return modelInput, nil
+}
+
+// modelReader reads the model files from the file system injected, if any
+func modelReader(ctx context.Context, config *common.Config) input.ModelReader {
+	return input.FileReaderWithContext(ctx, config.FS().ReadFile)
+}
+
+func ApplyRiskCategoryOverrides(filename string, progressReporter types.ProgressReporter, rules ...types.RiskRules) error {
+	if len(filename) == 0 {
+		return nil
+	}
+
+	progressReporter.Infof("Loading risk category overrides: %v", filename)
+	overrides := new(RiskCategoryOverrides)
+	loadError := overrides.Load(filename)
+	if loadError != nil {
+		return loadError
+	}
+
+	for _, ruleSet := range rules {
+		overrides.ApplyTo(ruleSet, progressReporter)
+	}
+
+	for _, id := range overrides.Unknown(rules...) {
+		progressReporter.Warnf("Risk category override references unknown risk category: %v", id)
+	}
+
+	return nil
+}
+
+func applyRiskGeneration(ctx context.Context, parsedModel *types.Model, rules types.RiskRules,
+	skipRiskRules []string, onlyRiskRules []string,
+	progressReporter types.ProgressReporter) []RiskRuleFailure {
 	progressReporter.Info("Applying risk generation")
 
-	skippedRules := make(map[string]bool)
-	if len(skipRiskRules) > 0 {
-		for _, id := range skipRiskRules {
-			skippedRules[id] = true
+	failures := make([]RiskRuleFailure, 0)
+
+	unusedSkipPatterns := make(map[string]bool)
+	for _, pattern := range skipRiskRules {
+		if len(strings.TrimSpace(pattern)) > 0 {
+			unusedSkipPatterns[strings.TrimSpace(pattern)] = true
 		}
 	}
 
 	for id, rule := range rules {
-		_, ok := skippedRules[id]
-		if ok {
-			progressReporter.Infof("Skipping risk rule: %v", id)
-			delete(skippedRules, id)
+		if ctx.Err() != nil {
+			break // the caller reports the cancellation, the risks generated so far are not used
+		}
+
+		matchingSkipPatterns := types.SkipPatternsMatching(skipRiskRules, id)
+		if len(matchingSkipPatterns) > 0 {
+			progressReporter.Infof("Skipping risk rule: %v (matched by %v)", id, strings.Join(matchingSkipPatterns, ", "))
+			for _, pattern := range matchingSkipPatterns {
+				delete(unusedSkipPatterns, pattern)
+			}
+			continue
+		}
+		if len(onlyRiskRules) > 0 && !types.IsSkippedRiskRule(onlyRiskRules, id) {
+			progressReporter.Infof("Skipping risk rule: %v (not selected)", id)
 			continue
 		}
 
 		parsedModel.AddToListOfSupportedTags(rule.SupportedTags())
-		newRisks, riskError := rule.GenerateRisks(parsedModel)
-		if riskError != nil {
-			progressReporter.Warnf("Error generating risks for %q: %v", id, riskError)
+		newRisks, failure := generateRisksIsolated(ctx, id, rule, parsedModel)
+		if failure != nil {
+			progressReporter.Warnf("Error generating risks for %q: %v", id, failure.Error)
+			failures = append(failures, *failure)
 			continue
 		}
 
+		for _, risk := range newRisks {
+			risk.InherentSeverity = risk.Severity
+		}
+
 		if len(newRisks) > 0 {
 			parsedModel.GeneratedRisksByCategory[id] = newRisks
 		}
 	}
 
-	if len(skippedRules) > 0 {
+	if len(unusedSkipPatterns) > 0 {
 		keys := make([]string, 0)
-		for k := range skippedRules {
+		for k := range unusedSkipPatterns {
 			keys = append(keys, k)
 		}
-		if len(keys) > 0 {
-			progressReporter.Infof("Unknown risk rules to skip: %v", keys)
-		}
+		sort.Strings(keys)
+		progressReporter.Infof("Unknown risk rules to skip: %v", keys)
 	}
 
 	// save also in map keyed by synthetic risk-id
@@ -122,18 +302,50 @@ func applyRiskGeneration(parsedModel *types.Model, rules types.RiskRules,
 			parsedModel.GeneratedRisksBySyntheticId[strings.ToLower(risk.SyntheticId)] = risk
 		}
 	}
+
+	sort.Slice(failures, func(i, j int) bool {
+		return failures[i].RuleID < failures[j].RuleID
+	})
+	return failures
+}
+
+// contextRiskRule is implemented by the risk rules running scripts or tools (like the script and rego risk rules), so
+// that they stop with the analysis
+type contextRiskRule interface {
+	GenerateRisksWithContext(ctx context.Context, parsedModel *types.Model) ([]*types.Risk, error)
+}
+
+// generateRisksIsolated recovers from a panicking rule, so that a single faulty rule does not abort the whole analysis
+func generateRisksIsolated(ctx context.Context, id string, rule types.RiskRule, parsedModel *types.Model) (risks []*types.Risk, failure *RiskRuleFailure) {
+	defer func() {
+		if r := recover(); r != nil {
+			risks = nil
+			failure = &RiskRuleFailure{RuleID: id, Error: fmt.Sprintf("panic: %v", r), Stack: string(debug.Stack())}
+		}
+	}()
+
+	var err error
+	if withContext, ok := rule.(contextRiskRule); ok {
+		risks, err = withContext.GenerateRisksWithContext(ctx, parsedModel)
+	} else {
+		risks, err = rule.GenerateRisks(parsedModel)
+	}
+	if err != nil {
+		return nil, &RiskRuleFailure{RuleID: id, Error: err.Error()}
+	}
+	return risks, nil
 }
 
-func applyRAA(parsedModel *types.Model, binFolder, raaPlugin string, progressReporter types.ProgressReporter) string {
+func applyRAA(ctx context.Context, parsedModel *types.Model, binFolder, raaPlugin string, timeoutSeconds int, progressReporter types.ProgressReporter) string {
 	progressReporter.Infof("Applying RAA calculation: %v", raaPlugin)
 
-	runner, loadError := new(runner).Load(filepath.Join(binFolder, raaPlugin))
+	runner, loadError := new(runner).Load(filepath.Join(binFolder, raaPlugin), timeoutSeconds)
 	if loadError != nil {
 		progressReporter.Warnf("raa %q not loaded: %v\n", raaPlugin, loadError)
 		return ""
 	}
 
-	runError := runner.Run(parsedModel, parsedModel)
+	runError := runner.Run(ctx, parsedModel, parsedModel)
 	if runError != nil {
 		progressReporter.Warnf("raa %q not applied: %v\n", raaPlugin, runError)
 		return ""
@@ -0,0 +1,120 @@
+package model
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/threagile/threagile/pkg/common"
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+type panickingTestRule struct{}
+
+func (*panickingTestRule) Category() *types.RiskCategory {
+	return &types.RiskCategory{ID: "panicking-rule", Title: "Panicking Rule"}
+}
+
+func (*panickingTestRule) SupportedTags() []string {
+	return []string{}
+}
+
+func (*panickingTestRule) GenerateRisks(*types.Model) ([]*types.Risk, error) {
+	var technicalAsset *types.TechnicalAsset
+	return []*types.Risk{{CategoryId: technicalAsset.Id}}, nil
+}
+
+type failingTestRule struct{}
+
+func (*failingTestRule) Category() *types.RiskCategory {
+	return &types.RiskCategory{ID: "failing-rule", Title: "Failing Rule"}
+}
+
+func (*failingTestRule) SupportedTags() []string {
+	return []string{}
+}
+
+func (*failingTestRule) GenerateRisks(*types.Model) ([]*types.Risk, error) {
+	return nil, fmt.Errorf("plugin not reachable")
+}
+
+func TestRiskGenerationContinuesAfterFailingRules(t *testing.T) {
+	parsedModel := &types.Model{
+		AllSupportedTags:            make(map[string]bool),
+		GeneratedRisksByCategory:    make(map[string][]*types.Risk),
+		GeneratedRisksBySyntheticId: make(map[string]*types.Risk),
+	}
+	rules := types.RiskRules{
+		"panicking-rule": new(panickingTestRule),
+		"failing-rule":   new(failingTestRule),
+		"test-rule":      new(overrideTestRule),
+	}
+
+	failures := applyRiskGeneration(context.Background(), parsedModel, rules, nil, nil, common.DefaultProgressReporter{SuppressError: true})
+
+	assert.Len(t, parsedModel.GeneratedRisksByCategory["test-rule"], 1)
+	assert.Len(t, failures, 2)
+	assert.Equal(t, "failing-rule", failures[0].RuleID)
+	assert.Equal(t, "plugin not reachable", failures[0].Error)
+	assert.Empty(t, failures[0].Stack)
+	assert.Equal(t, "panicking-rule", failures[1].RuleID)
+	assert.Contains(t, failures[1].Error, "nil pointer dereference")
+	assert.Contains(t, failures[1].Stack, "GenerateRisks")
+}
+
+func TestRiskGenerationStopsWhenCanceled(t *testing.T) {
+	parsedModel := &types.Model{
+		AllSupportedTags:            make(map[string]bool),
+		GeneratedRisksByCategory:    make(map[string][]*types.Risk),
+		GeneratedRisksBySyntheticId: make(map[string]*types.Risk),
+	}
+	rules := types.RiskRules{
+		"test-rule": new(overrideTestRule),
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	failures := applyRiskGeneration(ctx, parsedModel, rules, nil, nil, common.DefaultProgressReporter{SuppressError: true})
+
+	assert.Empty(t, parsedModel.GeneratedRisksByCategory["test-rule"])
+	assert.Empty(t, failures)
+	assert.Equal(t, common.ExitCodeTimeout, common.ExitCodeOf(common.CheckCanceled(ctx, "risk generation")))
+}
+
+func TestRiskGenerationOfSelectedRulesOnly(t *testing.T) {
+	parsedModel := &types.Model{
+		AllSupportedTags:            make(map[string]bool),
+		GeneratedRisksByCategory:    make(map[string][]*types.Risk),
+		GeneratedRisksBySyntheticId: make(map[string]*types.Risk),
+	}
+	rules := types.RiskRules{
+		"failing-rule": new(failingTestRule),
+		"test-rule":    new(overrideTestRule),
+	}
+
+	failures := applyRiskGeneration(context.Background(), parsedModel, rules, nil, []string{"test-*"}, common.DefaultProgressReporter{SuppressError: true})
+
+	assert.Len(t, parsedModel.GeneratedRisksByCategory["test-rule"], 1)
+	assert.Empty(t, failures, "the failing rule is not selected")
+}
+
+func TestLoadModelInputFromFileSystem(t *testing.T) {
+	fileSystem := common.NewMemoryFileSystem()
+	folder := filepath.Join("/models", "shop")
+	assert.NoError(t, fileSystem.MkdirAll(folder, 0700))
+	assert.NoError(t, fileSystem.WriteFile(filepath.Join(folder, "threagile.yaml"), []byte("title: Shop\nincludes: [tags.yaml]\n"), 0600))
+	assert.NoError(t, fileSystem.WriteFile(filepath.Join(folder, "tags.yaml"), []byte("tags_available: [aws]\n"), 0600))
+
+	config := &common.Config{InputFile: filepath.Join(folder, "threagile.yaml"), FileSystem: fileSystem}
+	modelInput, err := loadModelInput(context.Background(), config, common.DefaultProgressReporter{})
+	assert.NoError(t, err)
+	assert.Equal(t, "Shop", modelInput.Title)
+	assert.Equal(t, []string{"aws"}, modelInput.TagsAvailable)
+
+	config.InputFile = filepath.Join(folder, "missing.yaml")
+	_, err = loadModelInput(context.Background(), config, common.DefaultProgressReporter{})
+	assert.Error(t, err)
+}
@@ -0,0 +1,58 @@
+package model
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+// regoGateQuery is the rule of the gate policies listing the violations as messages
+const regoGateQuery = "data.threagile.gate.deny"
+
+// regoGateInput is what the gate policies are evaluated against, the same data as the risks, stats and technical
+// assets json outputs
+type regoGateInput struct {
+	Risks           []*types.Risk                    `json:"risks"`
+	RiskCategories  map[string]*types.RiskCategory   `json:"risk_categories"`
+	Stats           types.RiskStatistics             `json:"stats"`
+	TechnicalAssets map[string]*types.TechnicalAsset `json:"technical_assets"`
+}
+
+// CheckRegoPolicyGate evaluates the OPA/Rego policies of the folder against the result of the analysis, answering the
+// (sorted) violations the policies deny, none if the folder is empty:
+//
+//	package threagile.gate
+//
+//	deny contains msg if {
+//		some risk in input.risks
+//		risk.severity == "critical"
+//		risk.risk_status == "unchecked"
+//		input.technical_assets[risk.most_relevant_technical_asset].internet
+//		msg := sprintf("unchecked critical risk %v on internet-facing asset", [risk.synthetic_id])
+//	}
+func CheckRegoPolicyGate(ctx context.Context, folder string, result *Result, timeoutSeconds int) ([]string, error) {
+	if len(folder) == 0 {
+		return nil, nil
+	}
+
+	inputData, marshalError := json.Marshal(regoGateInput{
+		Risks:           result.Risks,
+		RiskCategories:  result.RiskCategories,
+		Stats:           result.Statistics,
+		TechnicalAssets: result.TechnicalAssetsById(),
+	})
+	if marshalError != nil {
+		return nil, fmt.Errorf("unable to marshal result for policy gate: %v", marshalError)
+	}
+
+	violations := make([]string, 0)
+	err := evalRego(ctx, folder, regoGateQuery, inputData, timeoutSeconds, &violations)
+	if err != nil {
+		return nil, fmt.Errorf("unable to evaluate policy gate %q: %w", folder, err)
+	}
+	sort.Strings(violations)
+	return violations, nil
+}
@@ -0,0 +1,49 @@
+package model
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+// fakeGateOPA stands in for the opa tool, denying unchecked critical risks on internet-facing assets
+const fakeGateOPA = `#!/bin/sh
+for query; do :; done
+[ "$query" = "data.threagile.gate.deny" ] || { echo "unexpected query $query" >&2; exit 2; }
+input=$(cat)
+case "$input" in
+*'"internet":true'*'"severity":"critical"'*|*'"severity":"critical"'*'"internet":true'*)
+	echo '{"result": [{"expressions": [{"value": ["unchecked critical risk on shop", "b-violation"]}]}]}' ;;
+*)
+	echo '{"result": [{"expressions": [{"value": []}]}]}' ;;
+esac
+`
+
+func TestCheckRegoPolicyGate(t *testing.T) {
+	toolFolder := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(toolFolder, "opa"), []byte(fakeGateOPA), 0700)) // #nosec G306
+	t.Setenv("PATH", toolFolder+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	violations, err := CheckRegoPolicyGate(context.Background(), "", &Result{}, 10)
+	assert.NoError(t, err)
+	assert.Empty(t, violations, "no gate without policies")
+
+	shop := &types.TechnicalAsset{Id: "shop", Title: "Shop", Internet: true}
+	result := &Result{
+		Risks:           []*types.Risk{{SyntheticId: "xss@shop", Severity: types.CriticalSeverity, RiskStatus: types.Unchecked, MostRelevantTechnicalAssetId: "shop"}},
+		TechnicalAssets: []*types.TechnicalAsset{shop},
+	}
+	violations, err = CheckRegoPolicyGate(context.Background(), t.TempDir(), result, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"b-violation", "unchecked critical risk on shop"}, violations, "sorted")
+
+	shop.Internet = false
+	violations, err = CheckRegoPolicyGate(context.Background(), t.TempDir(), result, 10)
+	assert.NoError(t, err)
+	assert.Empty(t, violations)
+}
@@ -0,0 +1,221 @@
+package model
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/threagile/threagile/pkg/common"
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+const (
+	// regoRulesPackage is the package below which the policies define their rules, each in a package of its own with
+	// the rules category, risks and (optionally) supported_tags
+	regoRulesPackage = "threagile.rules"
+	opaTool          = "opa"
+	opaInstall       = "https://www.openpolicyagent.org/docs/latest/#running-opa"
+)
+
+// RegoRiskRule is a custom risk rule written as OPA/Rego policy, evaluated by the opa tool against the parsed model
+//
+//	package threagile.rules.unencrypted_secrets
+//
+//	category := {"id": "unencrypted-secrets", "title": "Unencrypted Secrets", "function": "operations", "stride": "information-disclosure"}
+//
+//	risks contains risk if {
+//		some asset in input.technical_assets
+//		asset.encryption == "none"
+//		risk := {"title": sprintf("<b>Unencrypted Secrets</b> at <b>%v</b>", [asset.title]), "severity": "elevated",
+//			"exploitation_likelihood": "likely", "exploitation_impact": "medium", "most_relevant_technical_asset": asset.id}
+//	}
+//
+// Risks without synthetic_id get one from the category and their most relevant elements, risks without severity the one
+// of their likelihood and impact.
+type RegoRiskRule struct {
+	category      types.RiskCategory
+	supportedTags []string
+	folder        string
+	rulePackage   string
+	timeout       int
+}
+
+func (what *RegoRiskRule) Category() *types.RiskCategory {
+	return &what.category
+}
+
+func (what *RegoRiskRule) SupportedTags() []string {
+	return what.supportedTags
+}
+
+func (what *RegoRiskRule) GenerateRisks(parsedModel *types.Model) ([]*types.Risk, error) {
+	return what.GenerateRisksWithContext(context.Background(), parsedModel)
+}
+
+// GenerateRisksWithContext evaluates the risks of the rule, the opa tool being killed once the context is done or the
+// timeout of the rule has passed
+func (what *RegoRiskRule) GenerateRisksWithContext(ctx context.Context, parsedModel *types.Model) ([]*types.Risk, error) {
+	modelData, marshalError := json.Marshal(parsedModel)
+	if marshalError != nil {
+		return nil, fmt.Errorf("unable to convert model for rego risk rule %q: %v", what.category.ID, marshalError)
+	}
+
+	var rawRisks []json.RawMessage
+	evalError := evalRego(ctx, what.folder, "data."+regoRulesPackage+"."+what.rulePackage+".risks", modelData, what.timeout, &rawRisks)
+	if evalError != nil {
+		return nil, fmt.Errorf("failed to generate risks for rego risk rule %q: %w", what.category.ID, evalError)
+	}
+
+	return unmarshalRuleRisks("rego risk rule", what.category.ID, rawRisks)
+}
+
+// unmarshalRuleRisks converts the risks returned by a rego or script risk rule, giving risks without synthetic_id one
+// from the category and their most relevant elements, and risks without severity the one of their likelihood and impact
+func unmarshalRuleRisks(kind string, categoryId string, rawRisks []json.RawMessage) ([]*types.Risk, error) {
+	generatedRisks := make([]*types.Risk, 0, len(rawRisks))
+	for _, rawRisk := range rawRisks {
+		risk := new(types.Risk)
+		unmarshalError := json.Unmarshal(rawRisk, risk)
+		if unmarshalError != nil {
+			return nil, fmt.Errorf("invalid risk of %v %q: %v", kind, categoryId, unmarshalError)
+		}
+		var given struct {
+			Severity *string `json:"severity"`
+		}
+		_ = json.Unmarshal(rawRisk, &given)
+		if given.Severity == nil {
+			risk.Severity = types.CalculateSeverity(risk.ExploitationLikelihood, risk.ExploitationImpact)
+		}
+
+		risk.CategoryId = categoryId
+		if len(risk.SyntheticId) == 0 {
+			risk.SyntheticId = risk.CategoryId
+			for _, id := range []string{risk.MostRelevantDataAssetId, risk.MostRelevantTechnicalAssetId, risk.MostRelevantCommunicationLinkId, risk.MostRelevantTrustBoundaryId, risk.MostRelevantSharedRuntimeId} {
+				if len(id) > 0 {
+					risk.SyntheticId += "@" + id
+				}
+			}
+		}
+		generatedRisks = append(generatedRisks, risk)
+	}
+	return generatedRisks, nil
+}
+
+// LoadRegoRiskRules loads the rules of the Rego policies (*.rego) of the folder, each package below threagile.rules
+// defining one risk rule
+func LoadRegoRiskRules(ctx context.Context, folder string, timeoutSeconds int, reporter types.ProgressReporter) types.RiskRules {
+	rules := make(types.RiskRules)
+	if len(folder) == 0 {
+		return rules
+	}
+
+	reporter.Info("Loading rego risk rules:", folder)
+	loadedRules, loadError := loadRegoRiskRules(ctx, folder, timeoutSeconds)
+	if loadError != nil {
+		reporter.Error(fmt.Sprintf("WARNING: Rego risk rules of %q not loaded: %v\n", folder, loadError))
+		return rules
+	}
+
+	ids := make([]string, 0, len(loadedRules))
+	for _, rule := range loadedRules {
+		rules[rule.category.ID] = rule
+		ids = append(ids, rule.category.ID)
+	}
+	sort.Strings(ids)
+	reporter.Info("Loaded rego risk rules:", strings.Join(ids, ", "))
+	return rules
+}
+
+func loadRegoRiskRules(ctx context.Context, folder string, timeoutSeconds int) ([]*RegoRiskRule, error) {
+	info, statError := os.Stat(folder)
+	if statError != nil {
+		return nil, statError
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("%q is no folder", folder)
+	}
+
+	var packages map[string]struct {
+		Category      *types.RiskCategory `json:"category"`
+		SupportedTags []string            `json:"supported_tags"`
+	}
+	evalError := evalRego(ctx, folder, "data."+regoRulesPackage, nil, timeoutSeconds, &packages)
+	if evalError != nil {
+		return nil, evalError
+	}
+
+	names := make([]string, 0, len(packages))
+	for name := range packages {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	rules := make([]*RegoRiskRule, 0, len(names))
+	for _, name := range names {
+		rulePackage := packages[name]
+		if rulePackage.Category == nil || len(rulePackage.Category.ID) == 0 {
+			return nil, fmt.Errorf("package %v.%v defines no category with id", regoRulesPackage, name)
+		}
+		rules = append(rules, &RegoRiskRule{
+			category:      *rulePackage.Category,
+			supportedTags: rulePackage.SupportedTags,
+			folder:        folder,
+			rulePackage:   name,
+			timeout:       timeoutSeconds,
+		})
+	}
+	return rules, nil
+}
+
+// evalRego evaluates the query on the policies of the folder via the opa tool, with the input if given, and unmarshals
+// the value of the query (left unchanged when undefined)
+func evalRego(ctx context.Context, folder string, query string, input []byte, timeoutSeconds int, value any) error {
+	path, lookError := exec.LookPath(opaTool)
+	if lookError != nil {
+		return fmt.Errorf("%q not found in PATH (see %v)", opaTool, opaInstall)
+	}
+
+	ctx, cancel := common.WithTimeout(ctx, timeoutSeconds)
+	defer cancel()
+
+	arguments := []string{"eval", "--format", "json", "--data", filepath.Clean(folder)}
+	if input != nil {
+		arguments = append(arguments, "--stdin-input")
+	}
+	opa := exec.CommandContext(ctx, path, append(arguments, query)...) // #nosec G204
+	if input != nil {
+		opa.Stdin = bytes.NewReader(input)
+	}
+	var stdout, stderr bytes.Buffer
+	opa.Stdout = &stdout
+	opa.Stderr = &stderr
+	runError := opa.Run()
+	if ctx.Err() != nil {
+		return common.NewFailure(common.ExitCodeTimeout, fmt.Errorf("%v killed: %w", opaTool, ctx.Err()))
+	}
+	if runError != nil {
+		return fmt.Errorf("%v: %v", runError, strings.TrimSpace(stdout.String()+stderr.String()))
+	}
+
+	var output struct {
+		Result []struct {
+			Expressions []struct {
+				Value json.RawMessage `json:"value"`
+			} `json:"expressions"`
+		} `json:"result"`
+	}
+	unmarshalError := json.Unmarshal(stdout.Bytes(), &output)
+	if unmarshalError != nil {
+		return fmt.Errorf("unable to parse output of %v: %v", opaTool, unmarshalError)
+	}
+	if len(output.Result) == 0 || len(output.Result[0].Expressions) == 0 {
+		return nil
+	}
+	return json.Unmarshal(output.Result[0].Expressions[0].Value, value)
+}
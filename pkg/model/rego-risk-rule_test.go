@@ -0,0 +1,64 @@
+package model
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/threagile/threagile/pkg/common"
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+// fakeOPA stands in for the opa tool, answering the queries of the rules with the results of this policy:
+//
+//	package threagile.rules.unencrypted_secrets
+//
+//	category := {"id": "unencrypted-secrets", "title": "Unencrypted Secrets", "stride": "information-disclosure"}
+//	supported_tags := ["vault"]
+//
+//	risks contains risk if {
+//		some asset in input.technical_assets
+//		asset.encryption == "none"
+//		risk := {"title": asset.title, "exploitation_likelihood": "likely", "exploitation_impact": "high", "most_relevant_technical_asset": asset.id}
+//	}
+const fakeOPA = `#!/bin/sh
+for query; do :; done
+case "$query" in
+data.threagile.rules)
+	echo '{"result": [{"expressions": [{"value": {"unencrypted_secrets": {"category": {"id": "unencrypted-secrets", "title": "Unencrypted Secrets", "stride": "information-disclosure"}, "supported_tags": ["vault"]}}}]}]}' ;;
+data.threagile.rules.unencrypted_secrets.risks)
+	grep -q '"id":"shop"' || exit 1
+	echo '{"result": [{"expressions": [{"value": [{"title": "Shop", "exploitation_likelihood": "likely", "exploitation_impact": "high", "most_relevant_technical_asset": "shop"}, {"title": "Shop Override", "severity": "low", "synthetic_id": "custom"}]}]}]}' ;;
+*)
+	echo "unexpected query $query" >&2; exit 2 ;;
+esac
+`
+
+func TestRegoRiskRules(t *testing.T) {
+	toolFolder := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(toolFolder, "opa"), []byte(fakeOPA), 0700)) // #nosec G306
+	t.Setenv("PATH", toolFolder+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	rules := LoadRegoRiskRules(context.Background(), t.TempDir(), 10, common.DefaultProgressReporter{SuppressError: true})
+	assert.Len(t, rules, 1)
+	rule := rules["unencrypted-secrets"]
+	assert.Equal(t, types.InformationDisclosure, rule.Category().STRIDE)
+	assert.Equal(t, []string{"vault"}, rule.SupportedTags())
+
+	risks, err := rule.GenerateRisks(&types.Model{TechnicalAssets: map[string]*types.TechnicalAsset{"shop": {Id: "shop", Title: "Shop"}}})
+	assert.NoError(t, err)
+	assert.Len(t, risks, 2)
+	assert.Equal(t, "unencrypted-secrets@shop", risks[0].SyntheticId)
+	assert.Equal(t, "unencrypted-secrets", risks[0].CategoryId)
+	assert.Equal(t, types.CalculateSeverity(types.Likely, types.HighImpact), risks[0].Severity, "from likelihood and impact")
+	assert.Equal(t, "custom", risks[1].SyntheticId)
+	assert.Equal(t, types.LowSeverity, risks[1].Severity)
+
+	_, err = rule.GenerateRisks(&types.Model{})
+	assert.ErrorContains(t, err, `failed to generate risks for rego risk rule "unencrypted-secrets"`)
+
+	assert.Empty(t, LoadRegoRiskRules(context.Background(), filepath.Join(toolFolder, "missing"), 10, common.DefaultProgressReporter{SuppressError: true}))
+}
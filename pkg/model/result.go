@@ -0,0 +1,107 @@
+package model
+
+import (
+	"sort"
+	"time"
+
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+// Result is the outcome of an analysis as consumed by the writers of the outputs, the built-in ones as well as the
+// ones of programs embedding Threagile; it is taken once the analysis is complete, so writers need not know how the
+// parsed model keeps the risks. Its slices are its own, but the risks, assets and categories in them are the ones of
+// the parsed model, so writers must treat them as read-only.
+type Result struct {
+	Title            string
+	Risks            []*types.Risk                  // as answered by types.AllRisks, like the risks json always had them
+	RiskCategories   map[string]*types.RiskCategory // of the risks, by id
+	TechnicalAssets  []*types.TechnicalAsset        // sorted by title
+	DataAssets       []*types.DataAsset             // sorted by title
+	TrustBoundaries  []*types.TrustBoundary         // sorted by title
+	Statistics       types.RiskStatistics
+	Exposures        []*types.Exposure
+	RiskRuleFailures []RiskRuleFailure
+	Timing           Timing
+
+	parsedModel *types.Model
+}
+
+// Timing tells when the analysis started and how long its phases took
+type Timing struct {
+	Started        time.Time     `json:"started"`
+	Parsing        time.Duration `json:"parsing"`         // loading and parsing the model
+	RiskGeneration time.Duration `json:"risk_generation"` // running the risk rules
+	Total          time.Duration `json:"total"`
+}
+
+// NewResult takes the snapshot of the analyzed model
+func NewResult(parsedModel *types.Model, riskRuleFailures []RiskRuleFailure, timing Timing) *Result {
+	result := &Result{
+		Title:            parsedModel.Title,
+		Risks:            types.AllRisks(parsedModel),
+		RiskCategories:   make(map[string]*types.RiskCategory),
+		TechnicalAssets:  make([]*types.TechnicalAsset, 0, len(parsedModel.TechnicalAssets)),
+		DataAssets:       make([]*types.DataAsset, 0, len(parsedModel.DataAssets)),
+		TrustBoundaries:  make([]*types.TrustBoundary, 0, len(parsedModel.TrustBoundaries)),
+		Statistics:       types.OverallRiskStatistics(parsedModel),
+		Exposures:        parsedModel.Exposures(),
+		RiskRuleFailures: riskRuleFailures,
+		Timing:           timing,
+		parsedModel:      parsedModel,
+	}
+	for _, risk := range result.Risks {
+		if category := types.GetRiskCategory(parsedModel, risk.CategoryId); category != nil {
+			result.RiskCategories[risk.CategoryId] = category
+		}
+	}
+	for _, technicalAsset := range parsedModel.TechnicalAssets {
+		result.TechnicalAssets = append(result.TechnicalAssets, technicalAsset)
+	}
+	sort.Slice(result.TechnicalAssets, func(i, j int) bool {
+		return result.TechnicalAssets[i].Title < result.TechnicalAssets[j].Title
+	})
+	for _, dataAsset := range parsedModel.DataAssets {
+		result.DataAssets = append(result.DataAssets, dataAsset)
+	}
+	sort.Slice(result.DataAssets, func(i, j int) bool {
+		return result.DataAssets[i].Title < result.DataAssets[j].Title
+	})
+	for _, trustBoundary := range parsedModel.TrustBoundaries {
+		result.TrustBoundaries = append(result.TrustBoundaries, trustBoundary)
+	}
+	sort.Slice(result.TrustBoundaries, func(i, j int) bool {
+		return result.TrustBoundaries[i].Title < result.TrustBoundaries[j].Title
+	})
+	return result
+}
+
+// Category answers the risk category of the risk, nil for risks of unknown categories
+func (what *Result) Category(risk *types.Risk) *types.RiskCategory {
+	return what.RiskCategories[risk.CategoryId]
+}
+
+// RisksOfCategory answers the risks of the category in the order of the risks
+func (what *Result) RisksOfCategory(categoryId string) []*types.Risk {
+	risks := make([]*types.Risk, 0)
+	for _, risk := range what.Risks {
+		if risk.CategoryId == categoryId {
+			risks = append(risks, risk)
+		}
+	}
+	return risks
+}
+
+// TechnicalAssetsById answers the technical assets keyed by their id
+func (what *Result) TechnicalAssetsById() map[string]*types.TechnicalAsset {
+	technicalAssets := make(map[string]*types.TechnicalAsset)
+	for _, technicalAsset := range what.TechnicalAssets {
+		technicalAssets[technicalAsset.Id] = technicalAsset
+	}
+	return technicalAssets
+}
+
+// ParsedModel is the model the result was taken from, for the writers rendering the whole model (like the diagrams and
+// the PDF report); treat it as read-only
+func (what *Result) ParsedModel() *types.Model {
+	return what.parsedModel
+}
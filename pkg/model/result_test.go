@@ -0,0 +1,32 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+func TestNewResult(t *testing.T) {
+	category := &types.RiskCategory{ID: "test-rule", Title: "Test Rule"}
+	parsedModel := &types.Model{
+		Title:                 "Result Test",
+		BuiltInRiskCategories: types.RiskCategories{category},
+		TechnicalAssets:       map[string]*types.TechnicalAsset{"web": {Id: "web", Title: "Web"}, "db": {Id: "db", Title: "Database"}},
+		GeneratedRisksByCategory: map[string][]*types.Risk{category.ID: {
+			{CategoryId: category.ID, SyntheticId: "test-rule@db", Title: "Low", Severity: types.LowSeverity},
+			{CategoryId: category.ID, SyntheticId: "test-rule@web", Title: "High", Severity: types.HighSeverity},
+		}, "unknown": {{CategoryId: "unknown", SyntheticId: "unknown@web", Severity: types.MediumSeverity}}},
+	}
+
+	result := NewResult(parsedModel, nil, Timing{})
+	assert.Equal(t, "Result Test", result.Title)
+	assert.ElementsMatch(t, types.AllRisks(parsedModel), result.Risks)
+	assert.Same(t, category, result.Category(result.RisksOfCategory(category.ID)[0]))
+	assert.Nil(t, result.Category(result.RisksOfCategory("unknown")[0]))
+	assert.Len(t, result.RisksOfCategory(category.ID), 2)
+	assert.Equal(t, "Database", result.TechnicalAssets[0].Title, "sorted by title")
+	assert.Contains(t, result.TechnicalAssetsById(), "web")
+	assert.Same(t, parsedModel, result.ParsedModel())
+}
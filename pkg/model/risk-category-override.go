@@ -0,0 +1,163 @@
+package model
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+// RiskCategoryOverride replaces texts and defaults of an existing risk category; empty fields keep the original value
+type RiskCategoryOverride struct {
+	Title          string `json:"title,omitempty" yaml:"title,omitempty"`
+	Description    string `json:"description,omitempty" yaml:"description,omitempty"`
+	Impact         string `json:"impact,omitempty" yaml:"impact,omitempty"`
+	ASVS           string `json:"asvs,omitempty" yaml:"asvs,omitempty"`
+	CheatSheet     string `json:"cheat_sheet,omitempty" yaml:"cheat_sheet,omitempty"`
+	Action         string `json:"action,omitempty" yaml:"action,omitempty"`
+	Mitigation     string `json:"mitigation,omitempty" yaml:"mitigation,omitempty"`
+	Check          string `json:"check,omitempty" yaml:"check,omitempty"`
+	DetectionLogic string `json:"detection_logic,omitempty" yaml:"detection_logic,omitempty"`
+	RiskAssessment string `json:"risk_assessment,omitempty" yaml:"risk_assessment,omitempty"`
+	FalsePositives string `json:"false_positives,omitempty" yaml:"false_positives,omitempty"`
+	CWE            int    `json:"cwe,omitempty" yaml:"cwe,omitempty"`
+	Severity       string `json:"severity,omitempty" yaml:"severity,omitempty"`
+}
+
+// RiskCategoryOverrides maps risk category IDs to their overrides
+type RiskCategoryOverrides map[string]RiskCategoryOverride
+
+func (what *RiskCategoryOverrides) Load(filename string) error {
+	*what = make(RiskCategoryOverrides)
+	if len(filename) == 0 {
+		return nil
+	}
+
+	data, readError := os.ReadFile(filepath.Clean(filename))
+	if readError != nil {
+		return fmt.Errorf("unable to read risk category overrides file %q: %v", filename, readError)
+	}
+
+	var file struct {
+		RiskCategories RiskCategoryOverrides `yaml:"risk_categories"`
+	}
+	unmarshalError := yaml.Unmarshal(data, &file)
+	if unmarshalError != nil {
+		return fmt.Errorf("unable to parse risk category overrides file %q: %v", filename, unmarshalError)
+	}
+
+	for id, override := range file.RiskCategories {
+		if len(override.Severity) > 0 {
+			_, parseError := types.ParseRiskSeverity(override.Severity)
+			if parseError != nil {
+				return fmt.Errorf("unknown 'severity' value of risk category override %q: %v", id, override.Severity)
+			}
+		}
+		(*what)[id] = override
+	}
+
+	return nil
+}
+
+// ApplyTo wraps all rules with an override so that the changed category and severity show up in all outputs
+func (what RiskCategoryOverrides) ApplyTo(rules types.RiskRules, progressReporter types.ProgressReporter) {
+	for id, override := range what {
+		rule, ok := rules[id]
+		if !ok {
+			continue
+		}
+
+		progressReporter.Infof("Applying risk category override: %v", id)
+		rules[id] = new(overriddenRiskRule).Init(rule, override)
+	}
+}
+
+// Unknown returns the IDs of overrides not matching any of the given rules
+func (what RiskCategoryOverrides) Unknown(rules ...types.RiskRules) []string {
+	unknown := make([]string, 0)
+	for id := range what {
+		found := false
+		for _, ruleSet := range rules {
+			if _, ok := ruleSet[id]; ok {
+				found = true
+				break
+			}
+		}
+		if !found {
+			unknown = append(unknown, id)
+		}
+	}
+	return unknown
+}
+
+type overriddenRiskRule struct {
+	rule     types.RiskRule
+	category types.RiskCategory
+	severity *types.RiskSeverity
+}
+
+func (what *overriddenRiskRule) Init(rule types.RiskRule, override RiskCategoryOverride) *overriddenRiskRule {
+	*what = overriddenRiskRule{
+		rule:     rule,
+		category: *rule.Category(),
+	}
+
+	what.category.Title = withDefault(override.Title, what.category.Title)
+	what.category.Description = withDefault(override.Description, what.category.Description)
+	what.category.Impact = withDefault(override.Impact, what.category.Impact)
+	what.category.ASVS = withDefault(override.ASVS, what.category.ASVS)
+	what.category.CheatSheet = withDefault(override.CheatSheet, what.category.CheatSheet)
+	what.category.Action = withDefault(override.Action, what.category.Action)
+	what.category.Mitigation = withDefault(override.Mitigation, what.category.Mitigation)
+	what.category.Check = withDefault(override.Check, what.category.Check)
+	what.category.DetectionLogic = withDefault(override.DetectionLogic, what.category.DetectionLogic)
+	what.category.RiskAssessment = withDefault(override.RiskAssessment, what.category.RiskAssessment)
+	what.category.FalsePositives = withDefault(override.FalsePositives, what.category.FalsePositives)
+	if override.CWE > 0 {
+		what.category.CWE = override.CWE
+	}
+
+	if len(override.Severity) > 0 {
+		severity, parseError := types.ParseRiskSeverity(override.Severity)
+		if parseError == nil {
+			what.severity = &severity
+		}
+	}
+
+	return what
+}
+
+func (what *overriddenRiskRule) Category() *types.RiskCategory {
+	return &what.category
+}
+
+func (what *overriddenRiskRule) SupportedTags() []string {
+	return what.rule.SupportedTags()
+}
+
+func (what *overriddenRiskRule) GenerateRisks(parsedModel *types.Model) ([]*types.Risk, error) {
+	return what.GenerateRisksWithContext(context.Background(), parsedModel)
+}
+
+func (what *overriddenRiskRule) GenerateRisksWithContext(ctx context.Context, parsedModel *types.Model) ([]*types.Risk, error) {
+	var generatedRisks []*types.Risk
+	var riskError error
+	if withContext, ok := what.rule.(contextRiskRule); ok {
+		generatedRisks, riskError = withContext.GenerateRisksWithContext(ctx, parsedModel)
+	} else {
+		generatedRisks, riskError = what.rule.GenerateRisks(parsedModel)
+	}
+	if riskError != nil || what.severity == nil {
+		return generatedRisks, riskError
+	}
+
+	for _, risk := range generatedRisks {
+		risk.Severity = *what.severity
+	}
+
+	return generatedRisks, nil
+}
@@ -0,0 +1,57 @@
+package model
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/threagile/threagile/pkg/common"
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+type overrideTestRule struct{}
+
+func (*overrideTestRule) Category() *types.RiskCategory {
+	return &types.RiskCategory{ID: "test-rule", Title: "Test Rule", Impact: "original impact", Mitigation: "original mitigation"}
+}
+
+func (*overrideTestRule) SupportedTags() []string {
+	return []string{"test"}
+}
+
+func (*overrideTestRule) GenerateRisks(*types.Model) ([]*types.Risk, error) {
+	return []*types.Risk{{CategoryId: "test-rule", Severity: types.LowSeverity}}, nil
+}
+
+func TestRiskCategoryOverridesReplaceTextsAndSeverity(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "overrides.yaml")
+	assert.NoError(t, os.WriteFile(filename, []byte(`risk_categories:
+  test-rule:
+    mitigation: our mitigation
+    severity: high
+  unknown-rule:
+    impact: whatever
+`), 0600))
+
+	rules := types.RiskRules{"test-rule": new(overrideTestRule)}
+	assert.NoError(t, ApplyRiskCategoryOverrides(filename, common.DefaultProgressReporter{SuppressError: true}, rules))
+
+	category := rules["test-rule"].Category()
+	assert.Equal(t, "Test Rule", category.Title)
+	assert.Equal(t, "original impact", category.Impact)
+	assert.Equal(t, "our mitigation", category.Mitigation)
+	assert.Equal(t, []string{"test"}, rules["test-rule"].SupportedTags())
+
+	generatedRisks, err := rules["test-rule"].GenerateRisks(nil)
+	assert.NoError(t, err)
+	assert.Equal(t, types.HighSeverity, generatedRisks[0].Severity)
+}
+
+func TestRiskCategoryOverridesInvalidSeverity(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "overrides.yaml")
+	assert.NoError(t, os.WriteFile(filename, []byte("risk_categories:\n  test-rule:\n    severity: dramatic\n"), 0600))
+
+	assert.Error(t, new(RiskCategoryOverrides).Load(filename))
+}
@@ -0,0 +1,40 @@
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+// applyRiskComments adds the comments of the json file (by synthetic risk id, as the server stores them) to the risks
+// they comment, the ones of merged duplicates to the risk they were merged into; comments of risks not identified
+// anymore are ignored (like orphaned risk tracking, they are kept in the file)
+func applyRiskComments(parsedModel *types.Model, filename string, progressReporter types.ProgressReporter) error {
+	if len(filename) == 0 {
+		return nil
+	}
+
+	progressReporter.Infof("Loading risk comments: %v", filename)
+	data, readError := os.ReadFile(filepath.Clean(filename))
+	if readError != nil {
+		return fmt.Errorf("unable to read risk comments: %w", readError)
+	}
+	comments := make(map[string][]types.RiskComment)
+	parseError := json.Unmarshal(data, &comments)
+	if parseError != nil {
+		return fmt.Errorf("unable to parse risk comments %v: %w", filename, parseError)
+	}
+
+	for _, risks := range parsedModel.GeneratedRisksByCategory {
+		for _, risk := range risks {
+			risk.Comments = nil
+			for _, syntheticId := range append([]string{risk.SyntheticId}, risk.MergedRiskIds...) {
+				risk.Comments = append(risk.Comments, comments[syntheticId]...)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,35 @@
+package model
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/threagile/threagile/pkg/common"
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+func TestApplyRiskComments(t *testing.T) {
+	commentsFile := filepath.Join(t.TempDir(), "risk-comments.json")
+	assert.NoError(t, os.WriteFile(commentsFile, []byte(`{
+		"missing-waf@shop": [{"id": "1", "author": "Alice", "text": "Planned for Q3", "date": "2024-03-01T10:00:00Z"}],
+		"missing-waf@shop-copy": [{"id": "2", "author": "Bob", "text": "Same here", "date": "2024-03-02T10:00:00Z"}],
+		"missing-waf@gone": [{"id": "3", "author": "Bob", "text": "Orphaned", "date": "2024-03-03T10:00:00Z"}]
+	}`), 0600))
+	risk := &types.Risk{CategoryId: "missing-waf", SyntheticId: "missing-waf@shop", MergedRiskIds: []string{"missing-waf@shop-copy"}}
+	uncommented := &types.Risk{CategoryId: "missing-waf", SyntheticId: "missing-waf@backend"}
+	parsedModel := &types.Model{GeneratedRisksByCategory: map[string][]*types.Risk{"missing-waf": {risk, uncommented}}}
+
+	assert.NoError(t, applyRiskComments(parsedModel, commentsFile, common.DefaultProgressReporter{}))
+	if assert.Len(t, risk.Comments, 2, "including the ones of the merged duplicate") {
+		assert.Equal(t, "Planned for Q3", risk.Comments[0].Text)
+		assert.Equal(t, "Bob", risk.Comments[1].Author)
+	}
+	assert.Empty(t, uncommented.Comments)
+
+	assert.NoError(t, os.WriteFile(commentsFile, []byte(`[]`), 0600))
+	assert.ErrorContains(t, applyRiskComments(parsedModel, commentsFile, common.DefaultProgressReporter{}), "unable to parse risk comments")
+	assert.NoError(t, applyRiskComments(parsedModel, "", common.DefaultProgressReporter{}))
+}
@@ -4,23 +4,28 @@ package model
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"gopkg.in/yaml.v3"
 	"os"
 	"os/exec"
+
+	"github.com/threagile/threagile/pkg/common"
 )
 
 type runner struct {
-	Filename    string
-	Parameters  []string
-	In          any
-	Out         any
-	ErrorOutput string
+	Filename       string
+	Parameters     []string
+	In             any
+	Out            any
+	ErrorOutput    string
+	TimeoutSeconds int // limit of each run, the plugin is killed when exceeding it
 }
 
-func (p *runner) Load(filename string) (*runner, error) {
+func (p *runner) Load(filename string, timeoutSeconds int) (*runner, error) {
 	*p = runner{
-		Filename: filename,
+		Filename:       filename,
+		TimeoutSeconds: timeoutSeconds,
 	}
 
 	fileInfo, statError := os.Stat(filename)
@@ -35,15 +40,19 @@ func (p *runner) Load(filename string) (*runner, error) {
 	return p, nil
 }
 
-func (p *runner) Run(in any, out any, parameters ...string) error {
+func (p *runner) Run(ctx context.Context, in any, out any, parameters ...string) error {
 	*p = runner{
-		Filename:   p.Filename,
-		Parameters: parameters,
-		In:         in,
-		Out:        out,
+		Filename:       p.Filename,
+		Parameters:     parameters,
+		In:             in,
+		Out:            out,
+		TimeoutSeconds: p.TimeoutSeconds,
 	}
 
-	plugin := exec.Command(p.Filename, p.Parameters...) // #nosec G204
+	ctx, cancel := common.WithTimeout(ctx, p.TimeoutSeconds)
+	defer cancel()
+
+	plugin := exec.CommandContext(ctx, p.Filename, p.Parameters...) // #nosec G204
 	stdin, stdinError := plugin.StdinPipe()
 	if stdinError != nil {
 		return stdinError
@@ -78,6 +87,9 @@ func (p *runner) Run(in any, out any, parameters ...string) error {
 
 	waitError := plugin.Wait()
 	p.ErrorOutput = stderrBuf.String()
+	if ctx.Err() != nil {
+		return common.NewFailure(common.ExitCodeTimeout, fmt.Errorf("run %q killed: %w", p.Filename, ctx.Err()))
+	}
 	if waitError != nil {
 		return fmt.Errorf("%v: %v", waitError, p.ErrorOutput)
 	}
@@ -0,0 +1,237 @@
+package model
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+// maxSBOMSize limits the SBOMs read and fetched
+const maxSBOMSize = 50 * 1024 * 1024
+
+// applySBOMs loads the SBOMs of the technical assets (files relative to the model file or, if fetchURLs, http(s) URLs),
+// so that the risk rules can tell which assets contain components with known vulnerabilities
+func applySBOMs(ctx context.Context, parsedModel *types.Model, modelFolder string, fetchURLs bool, progressReporter types.ProgressReporter) error {
+	for _, id := range parsedModel.SortedTechnicalAssetIDs() {
+		technicalAsset := parsedModel.TechnicalAssets[id]
+		if len(technicalAsset.SBOM) == 0 {
+			continue
+		}
+
+		components, err := LoadSBOM(ctx, technicalAsset.SBOM, modelFolder, fetchURLs)
+		if err != nil {
+			return fmt.Errorf("technical asset %q: %w", id, err)
+		}
+		technicalAsset.SBOMComponents = components
+		progressReporter.Infof("SBOM of %v lists %d components, %d of them with known vulnerabilities", id, len(components), len(technicalAsset.VulnerableSBOMComponents()))
+	}
+	return nil
+}
+
+// LoadSBOM reads the components of a CycloneDX or SPDX json SBOM from a file (relative to the model folder, not outside
+// of it) or fetches it from an http(s) URL (only if fetchURLs, as the models may come from others, like the ones of the
+// server), with the vulnerabilities the SBOM lists for them (CycloneDX vulnerabilities, SPDX security advisory references)
+func LoadSBOM(ctx context.Context, source string, modelFolder string, fetchURLs bool) ([]*types.SBOMComponent, error) {
+	var data []byte
+	var err error
+	if strings.Contains(source, "://") {
+		if !fetchURLs || !(strings.HasPrefix(source, "http://") || strings.HasPrefix(source, "https://")) {
+			return nil, fmt.Errorf("unable to read SBOM %q: fetching SBOMs from URLs is not enabled", source)
+		}
+		data, err = fetchSBOM(ctx, source)
+	} else {
+		if !filepath.IsLocal(source) {
+			return nil, fmt.Errorf("unable to read SBOM %q: only files in the folder of the model are read", source)
+		}
+		data, err = readSBOM(filepath.Join(modelFolder, source))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to read SBOM %q: %v", source, err)
+	}
+
+	var format struct {
+		BOMFormat   string `json:"bomFormat"`
+		SPDXVersion string `json:"spdxVersion"`
+	}
+	err = json.Unmarshal(data, &format)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse SBOM %q (expected CycloneDX or SPDX json): %v", source, err)
+	}
+
+	var components []*types.SBOMComponent
+	switch {
+	case strings.EqualFold(format.BOMFormat, "CycloneDX"):
+		components, err = parseCycloneDX(data)
+	case len(format.SPDXVersion) > 0:
+		components, err = parseSPDX(data)
+	default:
+		return nil, fmt.Errorf("unable to parse SBOM %q: neither CycloneDX (bomFormat) nor SPDX (spdxVersion) json", source)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse SBOM %q: %v", source, err)
+	}
+
+	sort.Slice(components, func(i, j int) bool {
+		return components[i].String() < components[j].String()
+	})
+	return components, nil
+}
+
+func readSBOM(filename string) ([]byte, error) {
+	file, err := os.Open(filepath.Clean(filename))
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = file.Close() }()
+	return readLimitedSBOM(file)
+}
+
+func fetchSBOM(ctx context.Context, url string) ([]byte, error) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	client := http.Client{Timeout: 30 * time.Second}
+	response, err := client.Do(request) // #nosec G107 // URL is part of the model, fetched only when enabled
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = response.Body.Close() }()
+
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%v", response.Status)
+	}
+	return readLimitedSBOM(response.Body)
+}
+
+// readLimitedSBOM reads the SBOM, failing (instead of truncating it) when it is larger than maxSBOMSize
+func readLimitedSBOM(reader io.Reader) ([]byte, error) {
+	data, err := io.ReadAll(io.LimitReader(reader, maxSBOMSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > maxSBOMSize {
+		return nil, fmt.Errorf("larger than %d bytes", maxSBOMSize)
+	}
+	return data, nil
+}
+
+type cycloneDXComponent struct {
+	BOMRef     string               `json:"bom-ref"`
+	Group      string               `json:"group"`
+	Name       string               `json:"name"`
+	Version    string               `json:"version"`
+	PURL       string               `json:"purl"`
+	Components []cycloneDXComponent `json:"components"`
+}
+
+func parseCycloneDX(data []byte) ([]*types.SBOMComponent, error) {
+	var bom struct {
+		Components      []cycloneDXComponent `json:"components"`
+		Vulnerabilities []struct {
+			ID      string `json:"id"`
+			Ratings []struct {
+				Severity string `json:"severity"`
+			} `json:"ratings"`
+			Affects []struct {
+				Ref string `json:"ref"`
+			} `json:"affects"`
+		} `json:"vulnerabilities"`
+	}
+	err := json.Unmarshal(data, &bom)
+	if err != nil {
+		return nil, err
+	}
+
+	components := make([]*types.SBOMComponent, 0)
+	componentsByRef := make(map[string]*types.SBOMComponent)
+	var collect func([]cycloneDXComponent)
+	collect = func(cycloneDXComponents []cycloneDXComponent) {
+		for _, cycloneDXComponent := range cycloneDXComponents {
+			name := cycloneDXComponent.Name
+			if len(cycloneDXComponent.Group) > 0 {
+				name = cycloneDXComponent.Group + "/" + name
+			}
+			component := &types.SBOMComponent{Name: name, Version: cycloneDXComponent.Version, PURL: cycloneDXComponent.PURL}
+			components = append(components, component)
+			for _, ref := range []string{cycloneDXComponent.BOMRef, cycloneDXComponent.PURL} {
+				if len(ref) > 0 {
+					componentsByRef[ref] = component
+				}
+			}
+			collect(cycloneDXComponent.Components)
+		}
+	}
+	collect(bom.Components)
+
+	for _, vulnerability := range bom.Vulnerabilities {
+		severity := "unknown"
+		for _, rating := range vulnerability.Ratings {
+			if sbomSeverityRank(rating.Severity) > sbomSeverityRank(severity) {
+				severity = strings.ToLower(rating.Severity)
+			}
+		}
+		for _, affected := range vulnerability.Affects {
+			if component, ok := componentsByRef[affected.Ref]; ok {
+				component.Vulnerabilities = append(component.Vulnerabilities, types.SBOMVulnerability{ID: vulnerability.ID, Severity: severity})
+			}
+		}
+	}
+	return components, nil
+}
+
+func parseSPDX(data []byte) ([]*types.SBOMComponent, error) {
+	var document struct {
+		DocumentDescribes []string `json:"documentDescribes"`
+		Packages          []struct {
+			SPDXID       string `json:"SPDXID"`
+			Name         string `json:"name"`
+			VersionInfo  string `json:"versionInfo"`
+			ExternalRefs []struct {
+				ReferenceCategory string `json:"referenceCategory"`
+				ReferenceType     string `json:"referenceType"`
+				ReferenceLocator  string `json:"referenceLocator"`
+			} `json:"externalRefs"`
+		} `json:"packages"`
+	}
+	err := json.Unmarshal(data, &document)
+	if err != nil {
+		return nil, err
+	}
+
+	components := make([]*types.SBOMComponent, 0)
+	for _, spdxPackage := range document.Packages {
+		if contains(document.DocumentDescribes, spdxPackage.SPDXID) {
+			continue // the asset itself
+		}
+		component := &types.SBOMComponent{Name: spdxPackage.Name, Version: spdxPackage.VersionInfo}
+		for _, ref := range spdxPackage.ExternalRefs {
+			switch {
+			case ref.ReferenceType == "purl":
+				component.PURL = ref.ReferenceLocator
+			case strings.EqualFold(ref.ReferenceCategory, "SECURITY") && ref.ReferenceType == "advisory":
+				component.Vulnerabilities = append(component.Vulnerabilities, types.SBOMVulnerability{ID: ref.ReferenceLocator, Severity: "unknown"})
+			}
+		}
+		components = append(components, component)
+	}
+	return components, nil
+}
+
+func sbomSeverityRank(severity string) int {
+	for rank, candidate := range []string{"unknown", "none", "info", "low", "medium", "high", "critical"} {
+		if strings.EqualFold(severity, candidate) {
+			return rank
+		}
+	}
+	return 0
+}
@@ -0,0 +1,92 @@
+package model
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/threagile/threagile/pkg/common"
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+const cycloneDXTestSBOM = `{
+	"bomFormat": "CycloneDX",
+	"specVersion": "1.5",
+	"components": [
+		{"bom-ref": "pkg:maven/org.apache.logging.log4j/log4j-core@2.14.1", "group": "org.apache.logging.log4j", "name": "log4j-core", "version": "2.14.1",
+			"purl": "pkg:maven/org.apache.logging.log4j/log4j-core@2.14.1",
+			"components": [{"bom-ref": "nested", "name": "log4j-api", "version": "2.14.1"}]},
+		{"bom-ref": "gson", "group": "com.google.code.gson", "name": "gson", "version": "2.10.1"}
+	],
+	"vulnerabilities": [
+		{"id": "CVE-2021-44228", "ratings": [{"severity": "medium"}, {"severity": "critical"}], "affects": [{"ref": "pkg:maven/org.apache.logging.log4j/log4j-core@2.14.1"}]},
+		{"id": "CVE-2021-45046", "affects": [{"ref": "pkg:maven/org.apache.logging.log4j/log4j-core@2.14.1"}, {"ref": "unknown"}]}
+	]
+}`
+
+const spdxTestSBOM = `{
+	"spdxVersion": "SPDX-2.3",
+	"documentDescribes": ["SPDXRef-shop"],
+	"packages": [
+		{"SPDXID": "SPDXRef-shop", "name": "shop", "versionInfo": "1.0.0"},
+		{"SPDXID": "SPDXRef-lodash", "name": "lodash", "versionInfo": "4.17.20", "externalRefs": [
+			{"referenceCategory": "PACKAGE-MANAGER", "referenceType": "purl", "referenceLocator": "pkg:npm/lodash@4.17.20"},
+			{"referenceCategory": "SECURITY", "referenceType": "advisory", "referenceLocator": "https://nvd.nist.gov/vuln/detail/CVE-2021-23337"}
+		]},
+		{"SPDXID": "SPDXRef-express", "name": "express", "versionInfo": "4.19.2"}
+	]
+}`
+
+func TestLoadSBOM(t *testing.T) {
+	folder := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(folder, "shop.cdx.json"), []byte(cycloneDXTestSBOM), 0600))
+	assert.NoError(t, os.WriteFile(filepath.Join(folder, "broken.json"), []byte(`{"name": "no sbom"}`), 0600))
+
+	components, err := LoadSBOM(context.Background(), "shop.cdx.json", folder, false)
+	assert.NoError(t, err)
+	assert.Equal(t, []*types.SBOMComponent{
+		{Name: "com.google.code.gson/gson", Version: "2.10.1"},
+		{Name: "log4j-api", Version: "2.14.1"},
+		{Name: "org.apache.logging.log4j/log4j-core", Version: "2.14.1", PURL: "pkg:maven/org.apache.logging.log4j/log4j-core@2.14.1", Vulnerabilities: []types.SBOMVulnerability{
+			{ID: "CVE-2021-44228", Severity: "critical"},
+			{ID: "CVE-2021-45046", Severity: "unknown"},
+		}},
+	}, components)
+
+	sbomServer := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		_, _ = writer.Write([]byte(spdxTestSBOM))
+	}))
+	defer sbomServer.Close()
+	components, err = LoadSBOM(context.Background(), sbomServer.URL+"/shop.spdx.json", folder, true)
+	assert.NoError(t, err)
+	assert.Equal(t, []*types.SBOMComponent{
+		{Name: "express", Version: "4.19.2"},
+		{Name: "lodash", Version: "4.17.20", PURL: "pkg:npm/lodash@4.17.20", Vulnerabilities: []types.SBOMVulnerability{{ID: "https://nvd.nist.gov/vuln/detail/CVE-2021-23337", Severity: "unknown"}}},
+	}, components)
+
+	_, err = LoadSBOM(context.Background(), "broken.json", folder, false)
+	assert.ErrorContains(t, err, "neither CycloneDX (bomFormat) nor SPDX (spdxVersion) json")
+	_, err = LoadSBOM(context.Background(), "missing.json", folder, false)
+	assert.ErrorContains(t, err, `unable to read SBOM "missing.json"`)
+	_, err = LoadSBOM(context.Background(), sbomServer.URL+"/shop.spdx.json", folder, false)
+	assert.ErrorContains(t, err, "fetching SBOMs from URLs is not enabled")
+	_, err = LoadSBOM(context.Background(), "file:///etc/passwd", folder, true)
+	assert.ErrorContains(t, err, "fetching SBOMs from URLs is not enabled")
+	for _, outside := range []string{filepath.Join(folder, "shop.cdx.json"), filepath.Join("..", filepath.Base(folder), "shop.cdx.json")} {
+		_, err = LoadSBOM(context.Background(), outside, folder, false)
+		assert.ErrorContains(t, err, "only files in the folder of the model are read", outside)
+	}
+
+	parsedModel := &types.Model{TechnicalAssets: map[string]*types.TechnicalAsset{
+		"shop":     {Id: "shop", SBOM: "shop.cdx.json"},
+		"database": {Id: "database"},
+	}}
+	assert.NoError(t, applySBOMs(context.Background(), parsedModel, folder, false, common.DefaultProgressReporter{}))
+	assert.Len(t, parsedModel.TechnicalAssets["shop"].VulnerableSBOMComponents(), 1)
+	assert.Empty(t, parsedModel.TechnicalAssets["database"].SBOMComponents)
+}
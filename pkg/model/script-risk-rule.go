@@ -0,0 +1,251 @@
+package model
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/dop251/goja"
+
+	"github.com/threagile/threagile/pkg/common"
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+// ScriptRiskRule is a custom risk rule written as JavaScript module, run by the embedded interpreter with the parsed
+// model as object and returning the risks as objects, e.g.
+//
+//	exports.category = {id: "unencrypted-secrets", title: "Unencrypted Secrets", function: "operations", stride: "information-disclosure"};
+//	exports.supportedTags = ["vault"];
+//	exports.generateRisks = model => Object.values(model.technical_assets || {})
+//		.filter(asset => asset.encryption === "none")
+//		.map(asset => ({title: `<b>Unencrypted Secrets</b> at <b>${asset.title}</b>`,
+//			exploitation_likelihood: "likely", exploitation_impact: "medium", most_relevant_technical_asset: asset.id}));
+//
+// Risks without synthetic_id get one from the category and their most relevant elements, risks without severity the one
+// of their likelihood and impact. The scripts run sandboxed: there is neither require nor any access to the file system,
+// the network or other processes, and console output goes to stderr.
+type ScriptRiskRule struct {
+	category      types.RiskCategory
+	supportedTags []string
+	filename      string
+	program       *goja.Program
+	timeout       int
+}
+
+func (what *ScriptRiskRule) Category() *types.RiskCategory {
+	return &what.category
+}
+
+func (what *ScriptRiskRule) SupportedTags() []string {
+	return what.supportedTags
+}
+
+func (what *ScriptRiskRule) GenerateRisks(parsedModel *types.Model) ([]*types.Risk, error) {
+	return what.GenerateRisksWithContext(context.Background(), parsedModel)
+}
+
+// GenerateRisksWithContext runs the script in a runtime of its own, interrupted once the context is done or the
+// timeout of the rule has passed
+func (what *ScriptRiskRule) GenerateRisksWithContext(ctx context.Context, parsedModel *types.Model) ([]*types.Risk, error) {
+	modelData, marshalError := json.Marshal(parsedModel)
+	if marshalError != nil {
+		return nil, fmt.Errorf("unable to convert model for script risk rule %q: %v", what.category.ID, marshalError)
+	}
+
+	var rawRisks []json.RawMessage
+	runError := runRuleScript(ctx, what.filename, what.program, modelData, what.timeout, &rawRisks)
+	if runError != nil {
+		return nil, fmt.Errorf("failed to generate risks for script risk rule %q: %w", what.category.ID, runError)
+	}
+
+	return unmarshalRuleRisks("script risk rule", what.category.ID, rawRisks)
+}
+
+// LoadScriptRiskRules loads the risk rules of the JavaScript modules (*.js), each defining one risk rule by exporting
+// its category, supportedTags (optional) and generateRisks
+func LoadScriptRiskRules(ctx context.Context, scriptFiles []string, timeoutSeconds int, reporter types.ProgressReporter) types.RiskRules {
+	rules := make(types.RiskRules)
+	ids := make([]string, 0)
+	for _, scriptFile := range scriptFiles {
+		if len(scriptFile) == 0 {
+			continue
+		}
+
+		reporter.Info("Loading script risk rule:", scriptFile)
+		rule, loadError := loadScriptRiskRule(ctx, scriptFile, timeoutSeconds)
+		if loadError != nil {
+			reporter.Error(fmt.Sprintf("WARNING: Script risk rule %q not loaded: %v\n", scriptFile, loadError))
+			continue
+		}
+		rules[rule.category.ID] = rule
+		ids = append(ids, rule.category.ID)
+	}
+
+	if len(ids) > 0 {
+		reporter.Info("Loaded script risk rules:", strings.Join(ids, ", "))
+	}
+	return rules
+}
+
+func loadScriptRiskRule(ctx context.Context, scriptFile string, timeoutSeconds int) (*ScriptRiskRule, error) {
+	info, statError := os.Stat(scriptFile)
+	if statError != nil {
+		return nil, statError
+	}
+	if !info.Mode().IsRegular() {
+		return nil, fmt.Errorf("%q is not a regular file", scriptFile)
+	}
+	source, readError := os.ReadFile(filepath.Clean(scriptFile))
+	if readError != nil {
+		return nil, readError
+	}
+	// wrapped like node wraps its CommonJS modules, so that the declarations of the script stay its own
+	program, compileError := goja.Compile(filepath.Base(scriptFile), "(function (exports, module) {\n"+string(source)+"\n})", true)
+	if compileError != nil {
+		return nil, compileError
+	}
+
+	var ruleInfo struct {
+		Category      *types.RiskCategory `json:"category"`
+		SupportedTags []string            `json:"supported_tags"`
+	}
+	runError := runRuleScript(ctx, scriptFile, program, nil, timeoutSeconds, &ruleInfo)
+	if runError != nil {
+		return nil, runError
+	}
+	if ruleInfo.Category == nil || len(ruleInfo.Category.ID) == 0 {
+		return nil, fmt.Errorf("no category with id exported")
+	}
+
+	return &ScriptRiskRule{
+		category:      *ruleInfo.Category,
+		supportedTags: ruleInfo.SupportedTags,
+		filename:      scriptFile,
+		program:       program,
+		timeout:       timeoutSeconds,
+	}, nil
+}
+
+// runRuleScript runs the rule module in a new runtime and unmarshals what it answers: its category and supported tags
+// without model data, else the risks its generateRisks function (which may return a promise) answers for the model
+func runRuleScript(ctx context.Context, scriptFile string, program *goja.Program, modelData []byte, timeoutSeconds int, result any) error {
+	ctx, cancel := common.WithTimeout(ctx, timeoutSeconds)
+	defer cancel()
+
+	vm := goja.New()
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			vm.Interrupt(ctx.Err())
+		case <-done:
+		}
+	}()
+
+	output, runError := evalRuleScript(vm, scriptFile, program, modelData)
+	var interrupted *goja.InterruptedError
+	if errors.As(runError, &interrupted) {
+		return common.NewFailure(common.ExitCodeTimeout, fmt.Errorf("%v interrupted: %w", filepath.Base(scriptFile), ctx.Err()))
+	}
+	if runError != nil {
+		return runError
+	}
+
+	unmarshalError := json.Unmarshal([]byte(output), result)
+	if unmarshalError != nil {
+		return fmt.Errorf("unable to parse result of %v: %v", filepath.Base(scriptFile), unmarshalError)
+	}
+	return nil
+}
+
+func evalRuleScript(vm *goja.Runtime, scriptFile string, program *goja.Program, modelData []byte) (string, error) {
+	console := vm.NewObject()
+	for _, level := range []string{"log", "info", "warn", "error", "debug"} {
+		_ = console.Set(level, func(call goja.FunctionCall) goja.Value {
+			values := make([]string, 0, len(call.Arguments))
+			for _, argument := range call.Arguments {
+				values = append(values, argument.String())
+			}
+			_, _ = fmt.Fprintln(common.LogStderr(), filepath.Base(scriptFile)+":", strings.Join(values, " "))
+			return goja.Undefined()
+		})
+	}
+	_ = vm.Set("console", console)
+
+	wrapper, runError := vm.RunProgram(program)
+	if runError != nil {
+		return "", runError
+	}
+	load, ok := goja.AssertFunction(wrapper)
+	if !ok {
+		return "", fmt.Errorf("%v is no module", filepath.Base(scriptFile))
+	}
+	module := vm.NewObject()
+	exports := vm.NewObject()
+	_ = module.Set("exports", exports)
+	_, runError = load(goja.Undefined(), exports, module)
+	if runError != nil {
+		return "", runError
+	}
+	rule := module.Get("exports").ToObject(vm)
+
+	var answer goja.Value
+	if modelData == nil {
+		info := vm.NewObject()
+		for name, property := range map[string]string{"category": "category", "supportedTags": "supported_tags"} {
+			value, valueError := exportedValue(rule.Get(name))
+			if valueError != nil {
+				return "", valueError
+			}
+			_ = info.Set(property, value)
+		}
+		answer = info
+	} else {
+		generateRisks, ok := goja.AssertFunction(rule.Get("generateRisks"))
+		if !ok {
+			return "", fmt.Errorf("no generateRisks function exported")
+		}
+		parse, _ := goja.AssertFunction(vm.Get("JSON").ToObject(vm).Get("parse"))
+		model, parseError := parse(goja.Undefined(), vm.ToValue(string(modelData)))
+		if parseError != nil {
+			return "", parseError
+		}
+		answer, runError = generateRisks(goja.Undefined(), model)
+		if runError != nil {
+			return "", runError
+		}
+		if promise, isPromise := answer.Export().(*goja.Promise); isPromise {
+			switch promise.State() {
+			case goja.PromiseStateFulfilled:
+				answer = promise.Result()
+			case goja.PromiseStateRejected:
+				return "", fmt.Errorf("generateRisks rejected: %v", promise.Result())
+			default:
+				return "", fmt.Errorf("generateRisks returned a promise never settled")
+			}
+		}
+	}
+	if answer == nil || goja.IsUndefined(answer) || goja.IsNull(answer) {
+		return "[]", nil
+	}
+
+	stringify, _ := goja.AssertFunction(vm.Get("JSON").ToObject(vm).Get("stringify"))
+	output, runError := stringify(goja.Undefined(), answer)
+	if runError != nil {
+		return "", runError
+	}
+	return output.String(), nil
+}
+
+// exportedValue answers the exported value, or the one answered by the exported function
+func exportedValue(value goja.Value) (goja.Value, error) {
+	if function, ok := goja.AssertFunction(value); ok {
+		return function(goja.Undefined())
+	}
+	return value, nil
+}
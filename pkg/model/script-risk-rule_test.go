@@ -0,0 +1,78 @@
+package model
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/threagile/threagile/pkg/common"
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+const testRuleScript = `exports.category = {id: "unencrypted-secrets", title: "Unencrypted Secrets", stride: "information-disclosure"};
+exports.supportedTags = () => ["vault"];
+exports.generateRisks = async model => Object.values(model.technical_assets || {})
+	.filter(asset => asset.id === "shop" && console.log("matched", asset.id) === undefined)
+	.map(asset => ({title: asset.title, exploitation_likelihood: "likely", exploitation_impact: "high", most_relevant_technical_asset: asset.id}))
+	.concat(model.technical_assets ? [{title: "Shop Override", severity: "low", synthetic_id: "custom"}] : []);
+`
+
+func TestScriptRiskRules(t *testing.T) {
+	folder := t.TempDir()
+	scriptFile := filepath.Join(folder, "unencrypted-secrets.js")
+	assert.NoError(t, os.WriteFile(scriptFile, []byte(testRuleScript), 0600))
+	assert.NoError(t, os.WriteFile(filepath.Join(folder, "broken.js"), []byte(`exports.category = {title: "No ID"};`), 0600))
+
+	rules := LoadScriptRiskRules(context.Background(), []string{scriptFile, filepath.Join(folder, "broken.js"), filepath.Join(folder, "missing.js")}, 10, common.DefaultProgressReporter{SuppressError: true})
+	assert.Len(t, rules, 1)
+	rule := rules["unencrypted-secrets"]
+	assert.Equal(t, types.InformationDisclosure, rule.Category().STRIDE)
+	assert.Equal(t, []string{"vault"}, rule.SupportedTags())
+
+	risks, err := rule.GenerateRisks(&types.Model{TechnicalAssets: map[string]*types.TechnicalAsset{"shop": {Id: "shop", Title: "Shop"}}})
+	assert.NoError(t, err)
+	assert.Len(t, risks, 2)
+	assert.Equal(t, "unencrypted-secrets@shop", risks[0].SyntheticId)
+	assert.Equal(t, "unencrypted-secrets", risks[0].CategoryId)
+	assert.Equal(t, types.CalculateSeverity(types.Likely, types.HighImpact), risks[0].Severity, "from likelihood and impact")
+	assert.Equal(t, "custom", risks[1].SyntheticId)
+	assert.Equal(t, types.LowSeverity, risks[1].Severity)
+
+	risks, err = rule.GenerateRisks(&types.Model{})
+	assert.NoError(t, err)
+	assert.Empty(t, risks)
+}
+
+func TestScriptRiskRulesSandboxed(t *testing.T) {
+	scriptFile := filepath.Join(t.TempDir(), "sandboxed.js")
+	assert.NoError(t, os.WriteFile(scriptFile, []byte(`exports.category = {id: "sandboxed"};
+exports.generateRisks = () => [{title: [typeof require, typeof process, typeof module.exports].join(" ")}];
+`), 0600))
+
+	rules := LoadScriptRiskRules(context.Background(), []string{scriptFile}, 10, common.DefaultProgressReporter{SuppressError: true})
+	risks, err := rules["sandboxed"].GenerateRisks(&types.Model{})
+	assert.NoError(t, err)
+	if assert.Len(t, risks, 1) {
+		assert.Equal(t, "undefined undefined object", risks[0].Title)
+	}
+}
+
+func TestScriptRiskRulesInterrupted(t *testing.T) {
+	scriptFile := filepath.Join(t.TempDir(), "endless.js")
+	assert.NoError(t, os.WriteFile(scriptFile, []byte(`exports.category = {id: "endless"};
+exports.generateRisks = () => { for (;;) {} };
+`), 0600))
+
+	rules := LoadScriptRiskRules(context.Background(), []string{scriptFile}, 1, common.DefaultProgressReporter{SuppressError: true})
+	rule := rules["endless"].(*ScriptRiskRule)
+	_, err := rule.GenerateRisks(&types.Model{})
+	assert.Equal(t, common.ExitCodeTimeout, common.ExitCodeOf(err), "timeout of the rule")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = rule.GenerateRisksWithContext(ctx, &types.Model{})
+	assert.Equal(t, common.ExitCodeTimeout, common.ExitCodeOf(err), "context of the call")
+}
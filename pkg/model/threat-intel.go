@@ -0,0 +1,157 @@
+package model
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+// ThreatIntelEntry holds the exploitation trend of a technology class, e.g. derived from KEV or EPSS data
+type ThreatIntelEntry struct {
+	ActivelyExploited bool    `json:"actively_exploited,omitempty" yaml:"actively_exploited,omitempty"`
+	ExploitationTrend float64 `json:"exploitation_trend,omitempty" yaml:"exploitation_trend,omitempty"` // probability between 0 and 1
+}
+
+// ThreatIntelFeed maps technology names (or their parent technology) to their exploitation trend
+type ThreatIntelFeed map[string]ThreatIntelEntry
+
+// Load reads the feed from a file or fetches it from an http(s) URL, reusing a cached copy younger than maxAge
+func (what *ThreatIntelFeed) Load(source string, cacheFolder string, maxAge time.Duration, progressReporter types.ProgressReporter) error {
+	*what = make(ThreatIntelFeed)
+	if len(source) == 0 {
+		return nil
+	}
+
+	filename := source
+	if strings.HasPrefix(source, "http://") || strings.HasPrefix(source, "https://") {
+		hash := sha256.Sum256([]byte(source))
+		filename = filepath.Join(cacheFolder, "threat-intel-"+hex.EncodeToString(hash[:8])+".yaml")
+		fetchError := fetchThreatIntelFeed(source, filename, maxAge)
+		if fetchError != nil {
+			if _, statError := os.Stat(filename); statError != nil {
+				return fetchError
+			}
+			progressReporter.Warnf("Using stale threat intel cache: %v", fetchError)
+		}
+	}
+
+	data, readError := os.ReadFile(filepath.Clean(filename))
+	if readError != nil {
+		return fmt.Errorf("unable to read threat intel feed %q: %v", source, readError)
+	}
+
+	var feed struct {
+		Technologies ThreatIntelFeed `yaml:"technologies"`
+	}
+	unmarshalError := yaml.Unmarshal(data, &feed)
+	if unmarshalError != nil {
+		return fmt.Errorf("unable to parse threat intel feed %q: %v", source, unmarshalError)
+	}
+
+	for name, entry := range feed.Technologies {
+		if entry.ExploitationTrend < 0 || entry.ExploitationTrend > 1 {
+			return fmt.Errorf("invalid 'exploitation_trend' of technology %q in threat intel feed (expected value between 0 and 1): %v", name, entry.ExploitationTrend)
+		}
+		(*what)[strings.ToLower(strings.TrimSpace(name))] = entry
+	}
+
+	return nil
+}
+
+func fetchThreatIntelFeed(url string, filename string, maxAge time.Duration) error {
+	if info, statError := os.Stat(filename); statError == nil && time.Since(info.ModTime()) < maxAge {
+		return nil
+	}
+
+	client := http.Client{Timeout: 30 * time.Second}
+	response, getError := client.Get(url) // #nosec G107 // URL is configured by the operator
+	if getError != nil {
+		return fmt.Errorf("unable to fetch threat intel feed %q: %v", url, getError)
+	}
+	defer func() { _ = response.Body.Close() }()
+
+	if response.StatusCode != http.StatusOK {
+		return fmt.Errorf("unable to fetch threat intel feed %q: %v", url, response.Status)
+	}
+
+	data, readError := io.ReadAll(io.LimitReader(response.Body, 10*1024*1024))
+	if readError != nil {
+		return fmt.Errorf("unable to read threat intel feed %q: %v", url, readError)
+	}
+
+	return os.WriteFile(filename, data, 0600)
+}
+
+// Lookup returns the strongest exploitation trend of all technologies (and their parents) of a technical asset
+func (what ThreatIntelFeed) Lookup(technicalAsset *types.TechnicalAsset) (ThreatIntelEntry, bool) {
+	var result ThreatIntelEntry
+	found := false
+	for _, technology := range technicalAsset.Technologies {
+		for _, name := range []string{technology.Name, technology.Parent} {
+			entry, ok := what[strings.ToLower(name)]
+			if !ok || len(name) == 0 {
+				continue
+			}
+			found = true
+			result.ActivelyExploited = result.ActivelyExploited || entry.ActivelyExploited
+			if entry.ExploitationTrend > result.ExploitationTrend {
+				result.ExploitationTrend = entry.ExploitationTrend
+			}
+		}
+	}
+	return result, found
+}
+
+// ApplyToRAA raises the RAA of technical assets using technologies with an exploitation trend (actively exploited ones count as at least 0.5)
+func (what ThreatIntelFeed) ApplyToRAA(parsedModel *types.Model, progressReporter types.ProgressReporter) {
+	for _, technicalAsset := range parsedModel.TechnicalAssets {
+		entry, ok := what.Lookup(technicalAsset)
+		if !ok {
+			continue
+		}
+
+		weight := entry.ExploitationTrend
+		if entry.ActivelyExploited && weight < 0.5 {
+			weight = 0.5
+		}
+
+		raa := technicalAsset.RAA * (1 + weight)
+		if raa > 100 {
+			raa = 100
+		}
+		progressReporter.Infof("Threat intel raises RAA of %v from %.0f to %.0f", technicalAsset.Id, technicalAsset.RAA, raa)
+		technicalAsset.RAA = raa
+	}
+}
+
+// ApplyToRisks raises the likelihood (and with it the severity) of risks whose most relevant technical asset uses actively exploited technologies
+func (what ThreatIntelFeed) ApplyToRisks(parsedModel *types.Model) {
+	for _, risks := range parsedModel.GeneratedRisksByCategory {
+		for _, risk := range risks {
+			technicalAsset, ok := parsedModel.TechnicalAssets[risk.MostRelevantTechnicalAssetId]
+			if !ok {
+				continue
+			}
+
+			entry, found := what.Lookup(technicalAsset)
+			if !found || !entry.ActivelyExploited || risk.ExploitationLikelihood >= types.Frequent {
+				continue
+			}
+
+			likelihood := risk.ExploitationLikelihood + 1
+			levels := int(types.CalculateSeverity(likelihood, risk.ExploitationImpact)) - int(types.CalculateSeverity(risk.ExploitationLikelihood, risk.ExploitationImpact))
+			risk.ExploitationLikelihood = likelihood
+			risk.Severity = risk.Severity.Shift(levels)
+		}
+	}
+}
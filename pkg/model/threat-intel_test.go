@@ -0,0 +1,60 @@
+package model
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/threagile/threagile/pkg/common"
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+const threatIntelTestFeed = `technologies:
+  web-server:
+    actively_exploited: true
+    exploitation_trend: 0.2
+`
+
+func TestThreatIntelFeedRaisesRAAAndLikelihood(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "feed.yaml")
+	assert.NoError(t, os.WriteFile(filename, []byte(threatIntelTestFeed), 0600))
+
+	feed := new(ThreatIntelFeed)
+	assert.NoError(t, feed.Load(filename, t.TempDir(), time.Hour, common.DefaultProgressReporter{}))
+
+	asset := &types.TechnicalAsset{Id: "web", RAA: 40, Technologies: types.TechnologyList{{Name: "web-server"}}}
+	risk := &types.Risk{MostRelevantTechnicalAssetId: "web", ExploitationLikelihood: types.Likely, ExploitationImpact: types.HighImpact, Severity: types.CalculateSeverity(types.Likely, types.HighImpact)}
+	parsedModel := &types.Model{
+		TechnicalAssets:          map[string]*types.TechnicalAsset{"web": asset},
+		GeneratedRisksByCategory: map[string][]*types.Risk{"rule": {risk}},
+	}
+
+	feed.ApplyToRAA(parsedModel, common.DefaultProgressReporter{})
+	feed.ApplyToRisks(parsedModel)
+
+	assert.Equal(t, 60., asset.RAA)
+	assert.Equal(t, types.VeryLikely, risk.ExploitationLikelihood)
+	assert.Equal(t, types.CalculateSeverity(types.VeryLikely, types.HighImpact), risk.Severity)
+}
+
+func TestThreatIntelFeedIsCached(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, _ *http.Request) {
+		requests++
+		_, _ = writer.Write([]byte(threatIntelTestFeed))
+	}))
+	defer server.Close()
+
+	cacheFolder := t.TempDir()
+	for i := 0; i < 2; i++ {
+		feed := new(ThreatIntelFeed)
+		assert.NoError(t, feed.Load(server.URL, cacheFolder, time.Hour, common.DefaultProgressReporter{}))
+		assert.True(t, (*feed)["web-server"].ActivelyExploited)
+	}
+	assert.Equal(t, 1, requests)
+}
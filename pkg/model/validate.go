@@ -0,0 +1,92 @@
+package model
+
+import (
+	"errors"
+	"sort"
+
+	"github.com/threagile/threagile/pkg/common"
+	"github.com/threagile/threagile/pkg/input"
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+const (
+	ValidationError   = "error"
+	ValidationWarning = "warning"
+)
+
+// codes of the validation problems, stable for tools to branch on unlike the messages
+const (
+	ProblemCodeTemplatingFailed = "templating-failed"
+	ProblemCodeModelUnreadable  = "model-unreadable" // like a syntax error or a missing include
+	ProblemCodeInvalidField     = "invalid-field"
+	ProblemCodeInvalidModel     = "invalid-model" // problems not located at a field
+	ProblemCodeTagNotAvailable  = "tag-not-available"
+	ProblemCodeTagNotUsed       = "tag-not-used"
+)
+
+// ValidationProblem is a problem found by ValidateModel: an error making the model invalid or a warning about
+// something likely not intended
+type ValidationProblem struct {
+	File     string `json:"file"`
+	Field    string `json:"field,omitempty"`
+	Message  string `json:"message"`
+	Severity string `json:"severity"`
+	Code     string `json:"code"`
+}
+
+// ValidateModel loads and parses the input model like the analysis does (without running the risk rules) and answers
+// all problems found, instead of stopping at the first one
+func ValidateModel(config *common.Config, builtinRiskRules types.RiskRules, customRiskRules types.RiskRules) []ValidationProblem {
+	return ValidateModelWith(config, input.ReadModelFile, builtinRiskRules, customRiskRules)
+}
+
+// ValidateModelWith validates the model like ValidateModel, reading the model file and its includes with the reader
+// (e.g. the unsaved content of an editor)
+func ValidateModelWith(config *common.Config, readModelFile input.ModelReader, builtinRiskRules types.RiskRules, customRiskRules types.RiskRules) []ValidationProblem {
+	problems := make([]ValidationProblem, 0)
+	read, templatingError := input.TemplatingReaderOf(readModelFile, config.ModelTemplating)
+	if templatingError != nil {
+		return append(problems, ValidationProblem{File: config.InputFile, Message: templatingError.Error(), Severity: ValidationError, Code: ProblemCodeTemplatingFailed})
+	}
+	modelInput := new(input.Model).Defaults()
+	loadError := modelInput.LoadWith(config.InputFile, read)
+	if loadError != nil {
+		file := config.InputFile
+		var fileError *input.FileError
+		if errors.As(loadError, &fileError) {
+			file = fileError.File
+		}
+		return append(problems, ValidationProblem{File: file, Message: loadError.Error(), Severity: ValidationError, Code: ProblemCodeModelUnreadable})
+	}
+
+	if config.AutoSeedTagsAvailable {
+		for _, tag := range modelInput.SeedTagsAvailable() {
+			problems = append(problems, ValidationProblem{File: modelInput.SourceOf("tags_available"), Field: "tags_available", Message: "tag is used but missing in tags_available: " + tag, Severity: ValidationWarning, Code: ProblemCodeTagNotAvailable})
+		}
+	}
+
+	parsedModel, parseError := ParseModel(config, modelInput, builtinRiskRules, customRiskRules)
+	if parseError != nil {
+		modelProblems := ModelProblems(parseError)
+		sort.SliceStable(modelProblems, func(i, j int) bool { // as the model elements are parsed in random order
+			return modelProblems[i].Field < modelProblems[j].Field
+		})
+		for _, problem := range modelProblems {
+			code := ProblemCodeInvalidField
+			if len(problem.Field) == 0 {
+				code = ProblemCodeInvalidModel
+			}
+			file := problem.File
+			if len(file) == 0 {
+				file = config.InputFile
+			}
+			problems = append(problems, ValidationProblem{File: file, Field: problem.Field, Message: problem.Message, Severity: ValidationError, Code: code})
+		}
+		return problems
+	}
+
+	for _, tag := range parsedModel.TagsNotUsed() {
+		problems = append(problems, ValidationProblem{File: modelInput.SourceOf("tags_available"), Field: "tags_available", Message: "tag is available but not used: " + tag, Severity: ValidationWarning, Code: ProblemCodeTagNotUsed})
+	}
+	return problems
+}
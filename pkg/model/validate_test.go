@@ -0,0 +1,72 @@
+package model
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/threagile/threagile/pkg/common"
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+func TestValidateModelFindsAllProblems(t *testing.T) {
+	stub, err := os.ReadFile(filepath.Join("..", "..", "demo", "stub", "threagile.yaml"))
+	assert.NoError(t, err)
+	broken := strings.NewReplacer(
+		"usage: business # values: business, devops\n    tags:\n    origin:", "usage: bogus\n    tags:\n    origin:",
+		"target: some-other-component", "target: missing-component",
+		"quantity: many", "quantity: lots",
+	).Replace(string(stub))
+	modelFile := filepath.Join(t.TempDir(), "threagile.yaml")
+	assert.NoError(t, os.WriteFile(modelFile, []byte(broken), 0600))
+
+	problems := ValidateModel(&common.Config{InputFile: modelFile}, make(types.RiskRules), make(types.RiskRules))
+
+	assert.Equal(t, []ValidationProblem{
+		{File: modelFile, Field: "data_assets.Some Data Asset.quantity", Message: `unknown 'quantity' value of data asset "Some Data Asset": lots`, Severity: ValidationError, Code: ProblemCodeInvalidField},
+		{File: modelFile, Field: "data_assets.Some Data Asset.usage", Message: `unknown 'usage' value of data asset "Some Data Asset": bogus`, Severity: ValidationError, Code: ProblemCodeInvalidField},
+		{File: modelFile, Field: "technical_assets.Some Technical Asset.communication_links.Some Traffic.target", Message: `missing target technical asset "missing-component" for communication link: "Some Traffic"`, Severity: ValidationError, Code: ProblemCodeInvalidField},
+	}, problems)
+}
+
+func TestValidateModelWithoutProblems(t *testing.T) {
+	modelFile := filepath.Join("..", "..", "demo", "stub", "threagile.yaml")
+
+	for _, problem := range ValidateModel(&common.Config{InputFile: modelFile}, make(types.RiskRules), make(types.RiskRules)) {
+		assert.Equal(t, ValidationWarning, problem.Severity, problem.Message)
+	}
+}
+
+func TestValidateModelLocatesProblemsInIncludes(t *testing.T) {
+	stub, err := os.ReadFile(filepath.Join("..", "..", "demo", "stub", "threagile.yaml"))
+	assert.NoError(t, err)
+	folder := t.TempDir()
+	modelFile := filepath.Join(folder, "threagile.yaml")
+	assert.NoError(t, os.WriteFile(modelFile, []byte("includes:\n  - assets.yaml\n"+string(stub)), 0600))
+	assert.NoError(t, os.WriteFile(filepath.Join(folder, "assets.yaml"), []byte(`data_assets:
+  Other Data Asset:
+    id: other-data-asset
+    usage: business
+    quantity: lots
+    confidentiality: internal
+    integrity: operational
+    availability: operational
+`), 0600))
+
+	problems := ValidateModel(&common.Config{InputFile: modelFile}, make(types.RiskRules), make(types.RiskRules))
+	assert.Contains(t, problems, ValidationProblem{File: filepath.Join(folder, "assets.yaml"), Field: "data_assets.Other Data Asset.quantity", Message: `unknown 'quantity' value of data asset "Other Data Asset": lots`, Severity: ValidationError, Code: ProblemCodeInvalidField})
+
+	assert.NoError(t, os.WriteFile(filepath.Join(folder, "assets.yaml"), []byte("includes:\n  - broken.yaml\n"), 0600))
+	assert.NoError(t, os.WriteFile(filepath.Join(folder, "broken.yaml"), []byte("title: [\n"), 0600))
+	problems = ValidateModel(&common.Config{InputFile: modelFile}, make(types.RiskRules), make(types.RiskRules))
+	assert.Len(t, problems, 1)
+	assert.Equal(t, filepath.Join(folder, "broken.yaml"), problems[0].File, "the syntax error of the nested include")
+
+	assert.NoError(t, os.Remove(filepath.Join(folder, "broken.yaml")))
+	problems = ValidateModel(&common.Config{InputFile: modelFile}, make(types.RiskRules), make(types.RiskRules))
+	assert.Len(t, problems, 1)
+	assert.Equal(t, filepath.Join(folder, "assets.yaml"), problems[0].File, "the include including the missing one")
+}
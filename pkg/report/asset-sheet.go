@@ -0,0 +1,265 @@
+package report
+
+import (
+	"fmt"
+	"html"
+	"strings"
+
+	"github.com/jung-kurt/gofpdf"
+	"github.com/shopspring/decimal"
+	"github.com/threagile/threagile/pkg/common"
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+// assetSheet is the content of the one-page summary of a single technical asset, written as PDF and HTML
+type assetSheet struct {
+	title       string
+	description string
+	attributes  [][2]string
+	processed   string
+	stored      string
+	links       [][2]string
+	openRisks   []*types.Risk
+	checklist   []*types.RiskCategory
+}
+
+func newAssetSheet(parsedModel *types.Model, technicalAsset *types.TechnicalAsset) *assetSheet {
+	sheet := &assetSheet{title: technicalAsset.Title, description: technicalAsset.Description}
+
+	trustBoundaryTitle := "none"
+	if trustBoundary, ok := parsedModel.TrustBoundaries[technicalAsset.GetTrustBoundaryId(parsedModel)]; ok {
+		trustBoundaryTitle = trustBoundary.Title
+	}
+	sheet.attributes = [][2]string{
+		{"ID", technicalAsset.Id},
+		{"Owner", technicalAsset.Owner},
+		{"Type / Usage / Size", technicalAsset.Type.String() + " / " + technicalAsset.Usage.String() + " / " + technicalAsset.Size.String()},
+		{"Technologies", technicalAsset.Technologies.String()},
+		{"Machine", technicalAsset.Machine.String()},
+		{"Encryption", technicalAsset.Encryption.String()},
+		{"Internet", fmt.Sprintf("%t", technicalAsset.Internet)},
+		{"Trust Boundary", trustBoundaryTitle},
+		{"CIA Rating", technicalAsset.Confidentiality.String() + " / " + technicalAsset.Integrity.String() + " / " + technicalAsset.Availability.String()},
+		{"Highest CIA", technicalAsset.HighestConfidentiality(parsedModel).String() + " / " + technicalAsset.HighestIntegrity(parsedModel).String() + " / " + technicalAsset.HighestAvailability(parsedModel).String()},
+		{"RAA", decimal.NewFromFloat(technicalAsset.RAA).StringFixed(0) + " %"},
+		{"Tags", strings.Join(technicalAsset.Tags, ", ")},
+	}
+	if technicalAsset.OutOfScope {
+		sheet.attributes = append(sheet.attributes, [2]string{"Out of Scope", technicalAsset.JustificationOutOfScope})
+	}
+
+	sheet.processed = dataAssetTitles(technicalAsset.DataAssetsProcessedSorted(parsedModel))
+	sheet.stored = dataAssetTitles(technicalAsset.DataAssetsStoredSorted(parsedModel))
+
+	for _, link := range technicalAsset.CommunicationLinksSorted() {
+		sheet.links = append(sheet.links, [2]string{"Outgoing", link.Title + " to " + technicalAssetTitle(parsedModel, link.TargetId) +
+			" (" + link.Protocol.String() + ", " + link.Authentication.String() + ")"})
+	}
+	for _, link := range parsedModel.IncomingTechnicalCommunicationLinksMappedByTargetId[technicalAsset.Id] {
+		sheet.links = append(sheet.links, [2]string{"Incoming", link.Title + " from " + technicalAssetTitle(parsedModel, link.SourceId) +
+			" (" + link.Protocol.String() + ", " + link.Authentication.String() + ")"})
+	}
+
+	sheet.openRisks = types.ReduceToOnlyStillAtRisk(parsedModel, technicalAsset.GeneratedRisks(parsedModel))
+	checklistCategories := make(map[string]bool)
+	for _, risk := range sheet.openRisks {
+		category := types.GetRiskCategory(parsedModel, risk.CategoryId)
+		if category == nil || checklistCategories[category.ID] {
+			continue
+		}
+		checklistCategories[category.ID] = true
+		sheet.checklist = append(sheet.checklist, category)
+	}
+	return sheet
+}
+
+// WriteAssetSheetPDF writes a one-page summary of a single technical asset (attributes, data handled, communication links,
+// open risks and a mitigation checklist) meant to be handed over to the team owning the asset
+func WriteAssetSheetPDF(fileSystem common.FileSystem, parsedModel *types.Model, technicalAsset *types.TechnicalAsset, filename string, fontFile string) error {
+	sheet := newAssetSheet(parsedModel, technicalAsset)
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	err := addUnicodeFonts(pdf, fontFile)
+	if err != nil {
+		return fmt.Errorf("error adding fonts: %w", err)
+	}
+	pdf.SetCreator(parsedModel.Author.Homepage, true)
+	pdf.SetAuthor(parsedModel.Author.Name, true)
+	pdf.SetTitle("Asset Sheet: "+sheet.title, true)
+	pdf.SetSubject("Asset Sheet: "+sheet.title+" ("+parsedModel.Title+")", true)
+	pdf.SetMargins(15, 15, 15)
+	pdf.SetAutoPageBreak(true, 15)
+	pdf.SetFooterFunc(func() {
+		pdf.SetY(-12)
+		pdf.SetFont(fontFamily, "", 8)
+		pdf.SetTextColor(127, 127, 127)
+		pdf.CellFormat(0, 4, "Asset Sheet via Threagile - "+parsedModel.Title, "", 0, "L", false, 0, "")
+	})
+	uni := keepUTF8
+	pdf.AddPage()
+
+	pdf.SetFont(fontFamily, "B", fontSizeHeadline)
+	pdf.SetTextColor(0, 0, 0)
+	pdf.MultiCell(0, 9, uni(sheet.title), "", "L", false)
+	pdf.SetFont(fontFamily, "", fontSizeSmall)
+	pdf.SetTextColor(100, 100, 100)
+	pdf.MultiCell(0, 5, uni(sheet.description), "", "L", false)
+	pdf.Ln(3)
+
+	assetSheetHeadline(pdf, "Attributes")
+	for _, attribute := range sheet.attributes {
+		assetSheetKeyValue(pdf, uni, attribute[0], attribute[1])
+	}
+	pdf.Ln(3)
+
+	assetSheetHeadline(pdf, "Data Handled")
+	assetSheetKeyValue(pdf, uni, "Processed", sheet.processed)
+	assetSheetKeyValue(pdf, uni, "Stored", sheet.stored)
+	pdf.Ln(3)
+
+	assetSheetHeadline(pdf, "Communication Links")
+	if len(sheet.links) == 0 {
+		assetSheetText(pdf, uni, "none")
+	}
+	for _, link := range sheet.links {
+		assetSheetKeyValue(pdf, uni, link[0], link[1])
+	}
+	pdf.Ln(3)
+
+	assetSheetHeadline(pdf, fmt.Sprintf("Open Risks (%d)", len(sheet.openRisks)))
+	if len(sheet.openRisks) == 0 {
+		assetSheetText(pdf, uni, "none")
+	}
+	for _, risk := range sheet.openRisks {
+		color := makeColor(rgbHexColorOfSeverity(risk.Severity))
+		pdf.SetTextColor(int(color.R), int(color.G), int(color.B))
+		pdf.SetFont(fontFamily, "B", fontSizeSmall)
+		pdf.CellFormat(25, 5, risk.Severity.Title(), "", 0, "L", false, 0, "")
+		pdf.SetFont(fontFamily, "", fontSizeSmall)
+		pdf.SetTextColor(0, 0, 0)
+		pdf.MultiCell(0, 5, uni(removeFormattingTags(risk.Title)), "", "L", false)
+	}
+	pdf.Ln(3)
+
+	assetSheetHeadline(pdf, "Mitigation Checklist")
+	for _, category := range sheet.checklist {
+		x, y := pdf.GetX(), pdf.GetY()
+		pdf.SetDrawColor(0, 0, 0)
+		pdf.Rect(x+1, y+1, 3, 3, "D")
+		pdf.SetX(x + 7)
+		pdf.SetFont(fontFamily, "B", fontSizeSmall)
+		pdf.MultiCell(0, 5, uni(category.Title+": "+category.Action), "", "L", false)
+		pdf.SetX(x + 7)
+		pdf.SetFont(fontFamily, "", fontSizeSmall)
+		pdf.MultiCell(0, 5, uni(removeFormattingTags(firstParagraph(category.Mitigation))), "", "L", false)
+	}
+	if len(sheet.checklist) == 0 {
+		assetSheetText(pdf, uni, "none")
+	}
+
+	err = writePDF(fileSystem, pdf, filename)
+	if err != nil {
+		return fmt.Errorf("error writing asset sheet %q: %w", filename, err)
+	}
+	return nil
+}
+
+// WriteAssetSheetHTML writes the summary of WriteAssetSheetPDF as HTML page, e.g. to be put into the wiki of the team
+func WriteAssetSheetHTML(fileSystem common.FileSystem, parsedModel *types.Model, technicalAsset *types.TechnicalAsset, filename string) error {
+	sheet := newAssetSheet(parsedModel, technicalAsset)
+	escape := html.EscapeString
+
+	var text strings.Builder
+	text.WriteString("<!DOCTYPE html>\n<html>\n<head>\n<meta charset=\"utf-8\">\n")
+	text.WriteString(fmt.Sprintf("<title>Asset Sheet: %v</title>\n</head>\n<body>\n", escape(sheet.title)))
+	text.WriteString(fmt.Sprintf("<h1>%v</h1>\n<p>%v</p>\n", escape(sheet.title), escape(sheet.description)))
+
+	assetSheetHTMLTable(&text, "Attributes", sheet.attributes)
+	assetSheetHTMLTable(&text, "Data Handled", [][2]string{{"Processed", sheet.processed}, {"Stored", sheet.stored}})
+	assetSheetHTMLTable(&text, "Communication Links", sheet.links)
+
+	// the titles of the risks and the texts of the categories are formatted like in the report, of which only the bold
+	// markup is kept
+	text.WriteString(fmt.Sprintf("<h2>Open Risks (%d)</h2>\n", len(sheet.openRisks)))
+	if len(sheet.openRisks) == 0 {
+		text.WriteString("<p>none</p>\n")
+	} else {
+		text.WriteString("<table>\n")
+		for _, risk := range sheet.openRisks {
+			text.WriteString(fmt.Sprintf("<tr><th align=\"left\" style=\"color: %v\">%v</th><td>%v</td></tr>\n",
+				rgbHexColorOfSeverity(risk.Severity), escape(risk.Severity.Title()), assetSheetHTMLText(risk.Title)))
+		}
+		text.WriteString("</table>\n")
+	}
+
+	text.WriteString("<h2>Mitigation Checklist</h2>\n")
+	if len(sheet.checklist) == 0 {
+		text.WriteString("<p>none</p>\n")
+	} else {
+		text.WriteString("<ul>\n")
+		for _, category := range sheet.checklist {
+			text.WriteString(fmt.Sprintf("<li><input type=\"checkbox\"> <b>%v: %v</b><br>%v</li>\n",
+				escape(category.Title), assetSheetHTMLText(category.Action), assetSheetHTMLText(firstParagraph(category.Mitigation))))
+		}
+		text.WriteString("</ul>\n")
+	}
+	text.WriteString(fmt.Sprintf("<p><small>Asset Sheet via Threagile - %v</small></p>\n</body>\n</html>\n", escape(parsedModel.Title)))
+
+	err := fileSystem.WriteFile(filename, []byte(text.String()), 0600)
+	if err != nil {
+		return fmt.Errorf("error writing asset sheet %q: %w", filename, err)
+	}
+	return nil
+}
+
+// assetSheetHTMLText escapes the formatted text of the report (e.g. including asset titles of the model) for the HTML
+// page, keeping its bold markup and dropping the other one (the links keep their text, like in plainText)
+func assetSheetHTMLText(text string) string {
+	text = strings.NewReplacer("<i>", "", "</i>", "", "<u>", "", "</u>", "", "</a>", "").Replace(text)
+	escaped := html.EscapeString(linkTagRegEx.ReplaceAllString(text, ""))
+	return strings.NewReplacer("&lt;b&gt;", "<b>", "&lt;/b&gt;", "</b>").Replace(escaped)
+}
+
+func assetSheetHTMLTable(text *strings.Builder, headline string, rows [][2]string) {
+	text.WriteString(fmt.Sprintf("<h2>%v</h2>\n", html.EscapeString(headline)))
+	if len(rows) == 0 {
+		text.WriteString("<p>none</p>\n")
+		return
+	}
+	text.WriteString("<table>\n")
+	for _, row := range rows {
+		text.WriteString(fmt.Sprintf("<tr><th align=\"left\">%v</th><td>%v</td></tr>\n", html.EscapeString(row[0]), html.EscapeString(row[1])))
+	}
+	text.WriteString("</table>\n")
+}
+
+func assetSheetHeadline(pdf *gofpdf.Fpdf, headline string) {
+	pdf.SetFont(fontFamily, "B", fontSizeBody)
+	pdf.SetTextColor(0, 0, 0)
+	pdf.CellFormat(0, 7, headline, "B", 1, "L", false, 0, "")
+	pdf.Ln(1)
+}
+
+func assetSheetKeyValue(pdf *gofpdf.Fpdf, uni func(string) string, key string, value string) {
+	pdf.SetFont(fontFamily, "", fontSizeSmall)
+	pdf.SetTextColor(100, 100, 100)
+	pdf.CellFormat(40, 5, uni(key+":"), "", 0, "L", false, 0, "")
+	pdf.SetTextColor(0, 0, 0)
+	pdf.MultiCell(0, 5, uni(value), "", "L", false)
+}
+
+func assetSheetText(pdf *gofpdf.Fpdf, uni func(string) string, text string) {
+	pdf.SetFont(fontFamily, "", fontSizeSmall)
+	pdf.SetTextColor(100, 100, 100)
+	pdf.MultiCell(0, 5, uni(text), "", "L", false)
+}
+
+func dataAssetTitles(dataAssets []*types.DataAsset) string {
+	if len(dataAssets) == 0 {
+		return "none"
+	}
+	titles := make([]string, 0, len(dataAssets))
+	for _, dataAsset := range dataAssets {
+		titles = append(titles, dataAsset.Title)
+	}
+	return strings.Join(titles, ", ")
+}
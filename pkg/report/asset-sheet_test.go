@@ -0,0 +1,42 @@
+package report
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/threagile/threagile/pkg/common"
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+func TestNewAssetSheet(t *testing.T) {
+	parsedModel := reportTestModel()
+
+	sheet := newAssetSheet(parsedModel, parsedModel.TechnicalAssets["shop"])
+	assert.Contains(t, sheet.attributes, [2]string{"Owner", "Team Shop"})
+	assert.Equal(t, [][2]string{{"Outgoing", "Database Access to Database (jdbc-encrypted, none)"}}, sheet.links)
+	assert.Len(t, sheet.openRisks, 1, "only the ones still at risk")
+	assert.Equal(t, []*types.RiskCategory{parsedModel.BuiltInRiskCategories[0]}, sheet.checklist)
+
+	sheet = newAssetSheet(parsedModel, parsedModel.TechnicalAssets["database"])
+	assert.Equal(t, [][2]string{{"Incoming", "Database Access from Shop & Co (jdbc-encrypted, none)"}}, sheet.links)
+	assert.Empty(t, sheet.openRisks)
+	assert.Empty(t, sheet.checklist)
+}
+
+func TestWriteAssetSheetHTML(t *testing.T) {
+	parsedModel := reportTestModel()
+	fileSystem := common.NewMemoryFileSystem()
+	assert.NoError(t, WriteAssetSheetHTML(fileSystem, parsedModel, parsedModel.TechnicalAssets["shop"], "asset-sheet-shop.html"))
+	data, err := fileSystem.ReadFile("asset-sheet-shop.html")
+	assert.NoError(t, err)
+	page := string(data)
+
+	assert.True(t, strings.HasPrefix(page, "<!DOCTYPE html>"))
+	assert.Contains(t, page, "<h1>Shop &amp; Co</h1>")
+	assert.Contains(t, page, `<tr><th align="left">Owner</th><td>Team Shop</td></tr>`)
+	assert.Contains(t, page, "<h2>Open Risks (1)</h2>")
+	assert.Contains(t, page, `<tr><th align="left" style="color: `+rgbHexColorHighRisk()+`">High</th><td><b>Test Rule</b> at <b>Shop &amp; Co</b></td></tr>`)
+	assert.Contains(t, page, `<li><input type="checkbox"> <b>Test Rule: Harden <b>everything</b></b><br>Apply the hardening guide to versions &lt; 2.</li>`)
+	assert.NotContains(t, page, "Mitigated")
+}
@@ -0,0 +1,144 @@
+package report
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/threagile/threagile/pkg/common"
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+var linkTagRegEx = regexp.MustCompile(`<a\s[^>]*>`)
+
+// checklistItem is a single open risk with the mitigation and check texts of its category
+type checklistItem struct {
+	asset    *types.TechnicalAsset // nil for risks of no technical asset
+	risk     *types.Risk
+	category *types.RiskCategory
+}
+
+// mitigationChecklist collects the open risks of all in-scope technical assets ordered by asset title and by severity,
+// followed by the ones of no (known) technical asset, like the risks of custom rules about the model as a whole
+func mitigationChecklist(parsedModel *types.Model) []checklistItem {
+	items := make([]checklistItem, 0)
+	addItems := func(technicalAsset *types.TechnicalAsset, risks []*types.Risk) {
+		for _, risk := range types.ReduceToOnlyStillAtRisk(parsedModel, risks) {
+			category := types.GetRiskCategory(parsedModel, risk.CategoryId)
+			if category == nil {
+				category = &types.RiskCategory{ID: risk.CategoryId, Title: risk.CategoryId}
+			}
+			items = append(items, checklistItem{asset: technicalAsset, risk: risk, category: category})
+		}
+	}
+	for _, technicalAsset := range sortedTechnicalAssetsByTitle(parsedModel) {
+		if !technicalAsset.OutOfScope {
+			addItems(technicalAsset, technicalAsset.GeneratedRisks(parsedModel))
+		}
+	}
+
+	otherRisks := make([]*types.Risk, 0)
+	for _, risk := range types.AllRisks(parsedModel) {
+		if _, found := parsedModel.TechnicalAssets[risk.MostRelevantTechnicalAssetId]; !found {
+			otherRisks = append(otherRisks, risk)
+		}
+	}
+	types.SortByRiskSeverity(otherRisks, parsedModel)
+	addItems(nil, otherRisks)
+	return items
+}
+
+// WriteMitigationChecklistMarkdown writes the mitigation actions of all open risks as a Markdown task list,
+// grouped by technical asset and severity
+func WriteMitigationChecklistMarkdown(fileSystem common.FileSystem, parsedModel *types.Model, filename string) error {
+	var text strings.Builder
+	text.WriteString("# Mitigation Checklist: " + parsedModel.Title + "\n")
+
+	var currentAsset *types.TechnicalAsset
+	var currentSeverity types.RiskSeverity
+	for index, item := range mitigationChecklist(parsedModel) {
+		if index == 0 || item.asset != currentAsset {
+			currentAsset = item.asset
+			currentSeverity = -1
+			if item.asset == nil {
+				text.WriteString("\n## Other Risks\n")
+			} else {
+				text.WriteString("\n## " + item.asset.Title + "\n")
+				if len(item.asset.Owner) > 0 {
+					text.WriteString("\nOwner: " + item.asset.Owner + "\n")
+				}
+			}
+		}
+		if item.risk.Severity != currentSeverity {
+			currentSeverity = item.risk.Severity
+			text.WriteString("\n### " + item.risk.Severity.Title() + "\n\n")
+		}
+
+		text.WriteString("- [ ] **" + plainText(item.risk.Title) + "** (`" + item.risk.SyntheticId + "`)\n")
+		if len(item.category.Action) > 0 {
+			text.WriteString("  - Action: " + plainText(item.category.Action) + "\n")
+		}
+		if len(item.category.Mitigation) > 0 {
+			text.WriteString("  - Mitigation: " + plainText(item.category.Mitigation) + "\n")
+		}
+		if len(item.category.Check) > 0 {
+			text.WriteString("  - Check: " + plainText(item.category.Check) + "\n")
+		}
+	}
+
+	err := fileSystem.WriteFile(filename, []byte(text.String()), 0600)
+	if err != nil {
+		return fmt.Errorf("error writing mitigation checklist %q: %w", filename, err)
+	}
+	return nil
+}
+
+// WriteMitigationChecklistCSV writes the mitigation actions of all open risks as CSV, one row per risk, suitable for
+// importing into issue trackers and sprint planning tools
+func WriteMitigationChecklistCSV(fileSystem common.FileSystem, parsedModel *types.Model, filename string) error {
+	var buffer bytes.Buffer
+	writer := csv.NewWriter(&buffer)
+	err := writer.Write([]string{"Technical Asset", "Owner", "Priority", "Risk", "Synthetic ID", "Category", "Action", "Mitigation", "Check"})
+	if err != nil {
+		return fmt.Errorf("error writing %s: %w", filename, err)
+	}
+	for _, item := range mitigationChecklist(parsedModel) {
+		var assetTitle, assetOwner string
+		if item.asset != nil {
+			assetTitle, assetOwner = item.asset.Title, item.asset.Owner
+		}
+		err = writer.Write([]string{
+			assetTitle,
+			assetOwner,
+			item.risk.Severity.Title(),
+			plainText(item.risk.Title),
+			item.risk.SyntheticId,
+			item.category.Title,
+			plainText(item.category.Action),
+			plainText(item.category.Mitigation),
+			plainText(item.category.Check),
+		})
+		if err != nil {
+			return fmt.Errorf("error writing %s: %w", filename, err)
+		}
+	}
+
+	writer.Flush()
+	if writer.Error() != nil {
+		return fmt.Errorf("error writing %s: %w", filename, writer.Error())
+	}
+	err = fileSystem.WriteFile(filename, buffer.Bytes(), 0600)
+	if err != nil {
+		return fmt.Errorf("error writing %s: %w", filename, err)
+	}
+	return nil
+}
+
+// plainText turns the basic HTML used in rule and risk texts into a single line of text: besides the formatting tags
+// (see removeFormattingTags) the line breaks and the links (keeping their text) are removed
+func plainText(content string) string {
+	content = strings.NewReplacer("<br>", " ", "<br/>", " ", "<p>", " ", "</a>", "").Replace(removeFormattingTags(content))
+	return strings.Join(strings.Fields(linkTagRegEx.ReplaceAllString(content, "")), " ")
+}
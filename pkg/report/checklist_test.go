@@ -0,0 +1,53 @@
+package report
+
+import (
+	"encoding/csv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/threagile/threagile/pkg/common"
+)
+
+func TestMitigationChecklistMarkdown(t *testing.T) {
+	fileSystem := common.NewMemoryFileSystem()
+	assert.NoError(t, WriteMitigationChecklistMarkdown(fileSystem, reportTestModel(), "checklist.md"))
+	markdown, err := fileSystem.ReadFile("checklist.md")
+	assert.NoError(t, err)
+
+	assert.Equal(t, `# Mitigation Checklist: Report Test
+
+## Shop & Co
+
+Owner: Team Shop
+
+### High
+
+- [ ] **Test Rule at Shop & Co** (`+"`test-rule@shop`"+`)
+  - Action: Harden everything
+  - Mitigation: Apply the hardening guide to versions < 2. Then repeat.
+  - Check: Hardened?
+
+## Other Risks
+
+### Medium
+
+- [ ] **Test Rule of the model** (`+"`test-rule@model`"+`)
+  - Action: Harden everything
+  - Mitigation: Apply the hardening guide to versions < 2. Then repeat.
+  - Check: Hardened?
+`, string(markdown))
+}
+
+func TestMitigationChecklistCSV(t *testing.T) {
+	fileSystem := common.NewMemoryFileSystem()
+	assert.NoError(t, WriteMitigationChecklistCSV(fileSystem, reportTestModel(), "checklist.csv"))
+	data, err := fileSystem.ReadFile("checklist.csv")
+	assert.NoError(t, err)
+
+	rows, err := csv.NewReader(strings.NewReader(string(data))).ReadAll()
+	assert.NoError(t, err)
+	assert.Len(t, rows, 3, "header and the open risks")
+	assert.Equal(t, []string{"Shop & Co", "Team Shop", "High", "Test Rule at Shop & Co", "test-rule@shop"}, rows[1][:5])
+	assert.Equal(t, []string{"", "", "Medium", "Test Rule of the model", "test-rule@model"}, rows[2][:5])
+}
@@ -0,0 +1,122 @@
+package report
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/threagile/threagile/pkg/common"
+)
+
+// checkpoint records the generation stages completed for a fingerprint of the model and the settings, so that a
+// retry after a failure (e.g. the render timeout hit by the report of a very large model) resumes from the last good
+// stage instead of starting over; it is removed once all stages are completed
+type checkpoint struct {
+	fileSystem  common.FileSystem
+	filename    string
+	Fingerprint string                       `json:"fingerprint"`
+	Stages      map[string]map[string]string `json:"stages"` // sha256 of the files written by each completed stage
+}
+
+// loadCheckpoint continues the checkpoint of a previous run with the same fingerprint, or else starts a new one
+func loadCheckpoint(fileSystem common.FileSystem, filename string, fingerprint string) *checkpoint {
+	fresh := &checkpoint{fileSystem: fileSystem, filename: filename, Fingerprint: fingerprint, Stages: make(map[string]map[string]string)}
+	data, err := fileSystem.ReadFile(filename)
+	if err != nil {
+		return fresh
+	}
+	previous := new(checkpoint)
+	if json.Unmarshal(data, previous) != nil || previous.Fingerprint != fingerprint || previous.Stages == nil {
+		return fresh
+	}
+	previous.fileSystem, previous.filename = fileSystem, filename
+	return previous
+}
+
+// completed tells whether the stage was completed and all its files are still there unchanged
+func (what *checkpoint) completed(stage string, files []string) bool {
+	hashes, ok := what.Stages[stage]
+	if !ok {
+		return false
+	}
+	for _, file := range files {
+		hash, err := hashFile(what.fileSystem, file)
+		if err != nil || hash != hashes[file] {
+			return false
+		}
+	}
+	return true
+}
+
+func (what *checkpoint) complete(stage string, files []string) error {
+	hashes := make(map[string]string)
+	for _, file := range files {
+		hash, err := hashFile(what.fileSystem, file)
+		if err == nil { // files not written (e.g. a diagram graphviz failed to render) are not recorded, so that a retry renders them again
+			hashes[file] = hash
+		}
+	}
+	what.Stages[stage] = hashes
+	data, err := json.Marshal(what)
+	if err != nil {
+		return fmt.Errorf("unable to write checkpoint: %w", err)
+	}
+	err = what.fileSystem.WriteFile(what.filename, data, 0600)
+	if err != nil {
+		return fmt.Errorf("unable to write checkpoint: %w", err)
+	}
+	return nil
+}
+
+func (what *checkpoint) remove() error {
+	err := what.fileSystem.Remove(what.filename)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("unable to remove checkpoint: %w", err)
+	}
+	return nil
+}
+
+// generationFingerprint covers everything the outputs are generated from
+func generationFingerprint(parts ...any) (string, error) {
+	hasher := sha256.New()
+	for _, part := range parts {
+		data, err := json.Marshal(part)
+		if err != nil {
+			return "", fmt.Errorf("unable to fingerprint generation: %w", err)
+		}
+		hasher.Write(data)
+	}
+	return hex.EncodeToString(hasher.Sum(nil)), nil
+}
+
+// generationInputFiles answers the hashes of the files the settings only name, so that changing the template, the
+// previous risks, the font, the diagram theme or a plugin starts over as well (files missing hash as empty)
+func generationInputFiles(config *common.Config) map[string]string {
+	osFiles := []string{filepath.Join(config.AppFolder, config.TemplateFilename), config.FontFile, config.DiagramTheme}
+	osFiles = append(osFiles, config.RiskRulesPlugins...)
+	osFiles = append(osFiles, config.RiskRulesScripts...)
+	osFiles = append(osFiles, config.ReportSectionPlugins...)
+
+	hashes := make(map[string]string)
+	for _, filename := range osFiles {
+		if len(filename) > 0 {
+			hashes[filename], _ = hashFile(common.OSFileSystem{}, filename)
+		}
+	}
+	if len(config.PreviousRisksFile) > 0 {
+		hashes[config.PreviousRisksFile], _ = hashFile(config.FS(), config.PreviousRisksFile)
+	}
+	return hashes
+}
+
+func hashFile(fileSystem common.FileSystem, filename string) (string, error) {
+	data, err := fileSystem.ReadFile(filename)
+	if err != nil {
+		return "", err
+	}
+	hash := sha256.Sum256(data)
+	return hex.EncodeToString(hash[:]), nil
+}
@@ -0,0 +1,114 @@
+package report
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/threagile/threagile/pkg/common"
+	"github.com/threagile/threagile/pkg/model"
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+type silentProgressReporter struct{}
+
+func (silentProgressReporter) Info(...any)  {}
+func (silentProgressReporter) Warn(...any)  {}
+func (silentProgressReporter) Error(...any) {}
+
+func TestGenerationResumesFromLastGoodStage(t *testing.T) {
+	config := new(common.Config).Defaults("")
+	config.OutputFolder = t.TempDir()
+	readResult := &model.ReadResult{ParsedModel: &types.Model{Title: "Checkpoint Test"}}
+	commands := new(GenerateCommands).Defaults()
+
+	runs := make(map[string]int)
+	failing := "report"
+	stage := func(name string) generationStage {
+		file := filepath.Join(config.OutputFolder, name+".txt")
+		return generationStage{name: name, files: []string{file}, run: func() error {
+			runs[name]++
+			if name == failing {
+				return errors.New("render timeout")
+			}
+			return os.WriteFile(file, []byte(name), 0600)
+		}}
+	}
+	stages := []generationStage{stage("diagram"), stage("excel"), stage("report")}
+	checkpointFile := filepath.Join(config.OutputFolder, config.CheckpointFilename)
+
+	assert.Error(t, runGenerationStages(context.Background(), config, readResult, commands, stages, silentProgressReporter{}))
+	assert.FileExists(t, checkpointFile)
+
+	failing = ""
+	assert.NoError(t, runGenerationStages(context.Background(), config, readResult, commands, stages, silentProgressReporter{}))
+	assert.Equal(t, map[string]int{"diagram": 1, "excel": 1, "report": 2}, runs)
+	assert.NoFileExists(t, checkpointFile)
+
+	// a changed output of a completed stage is generated again, together with all the following stages
+	failing = "report"
+	assert.Error(t, runGenerationStages(context.Background(), config, readResult, commands, stages, silentProgressReporter{}))
+	assert.NoError(t, os.WriteFile(filepath.Join(config.OutputFolder, "excel.txt"), []byte("edited"), 0600))
+	failing = ""
+	assert.NoError(t, runGenerationStages(context.Background(), config, readResult, commands, stages, silentProgressReporter{}))
+	assert.Equal(t, map[string]int{"diagram": 2, "excel": 3, "report": 4}, runs)
+}
+
+func TestGenerationStartsOverForChangedModel(t *testing.T) {
+	config := new(common.Config).Defaults("")
+	config.OutputFolder = t.TempDir()
+	commands := new(GenerateCommands).Defaults()
+
+	runs, failing := 0, true
+	file := filepath.Join(config.OutputFolder, "diagram.txt")
+	stages := []generationStage{
+		{name: "diagram", files: []string{file}, run: func() error {
+			runs++
+			return os.WriteFile(file, []byte("diagram"), 0600)
+		}},
+		{name: "report", run: func() error {
+			if failing {
+				return errors.New("render timeout")
+			}
+			return nil
+		}},
+	}
+
+	assert.Error(t, runGenerationStages(context.Background(), config, &model.ReadResult{ParsedModel: &types.Model{Title: "Before"}}, commands, stages, silentProgressReporter{}))
+	failing = false
+	assert.NoError(t, runGenerationStages(context.Background(), config, &model.ReadResult{ParsedModel: &types.Model{Title: "After"}}, commands, stages, silentProgressReporter{}))
+	assert.Equal(t, 2, runs)
+}
+
+func TestGenerationStartsOverForChangedPreviousRisks(t *testing.T) {
+	config := new(common.Config).Defaults("")
+	config.OutputFolder = t.TempDir()
+	config.PreviousRisksFile = filepath.Join(config.OutputFolder, "previous-risks.json")
+	assert.NoError(t, os.WriteFile(config.PreviousRisksFile, []byte("[]"), 0600))
+	readResult := &model.ReadResult{ParsedModel: &types.Model{Title: "Checkpoint Test"}}
+	commands := new(GenerateCommands).Defaults()
+
+	runs, failing := 0, true
+	file := filepath.Join(config.OutputFolder, "diagram.txt")
+	stages := []generationStage{
+		{name: "diagram", files: []string{file}, run: func() error {
+			runs++
+			return os.WriteFile(file, []byte("diagram"), 0600)
+		}},
+		{name: "report", run: func() error {
+			if failing {
+				return errors.New("render timeout")
+			}
+			return nil
+		}},
+	}
+
+	assert.Error(t, runGenerationStages(context.Background(), config, readResult, commands, stages, silentProgressReporter{}))
+	assert.NoError(t, os.WriteFile(config.PreviousRisksFile, []byte(`[{"synthetic_id":"test@shop"}]`), 0600))
+	failing = false
+	assert.NoError(t, runGenerationStages(context.Background(), config, readResult, commands, stages, silentProgressReporter{}))
+	assert.Equal(t, 2, runs)
+}
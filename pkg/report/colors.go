@@ -4,6 +4,7 @@ import (
 	"encoding/hex"
 
 	"github.com/jung-kurt/gofpdf"
+	"github.com/threagile/threagile/pkg/security/types"
 )
 
 const (
@@ -89,6 +90,21 @@ func rgbHexColorLowRisk() string {
 	return "#23465F"
 }
 
+func colorRiskSeverity(pdf *gofpdf.Fpdf, severity types.RiskSeverity) {
+	switch severity {
+	case types.CriticalSeverity:
+		colorCriticalRisk(pdf)
+	case types.HighSeverity:
+		colorHighRisk(pdf)
+	case types.ElevatedSeverity:
+		colorElevatedRisk(pdf)
+	case types.MediumSeverity:
+		colorMediumRisk(pdf)
+	default:
+		colorLowRisk(pdf)
+	}
+}
+
 func rgbHexColorOutOfScope() string {
 	return "#7F7F7F"
 }
@@ -0,0 +1,436 @@
+package report
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+const (
+	DefaultDiagramTheme      = "default"
+	LightDiagramTheme        = "light"
+	DarkDiagramTheme         = "dark"
+	HighContrastDiagramTheme = "high-contrast"
+	GrayscaleDiagramTheme    = "grayscale"
+)
+
+// DiagramTheme defines the colors, font and shapes used in the data flow and data asset diagrams; a theme file only
+// needs to contain the values differing from the default theme
+type DiagramTheme struct {
+	Font             string                 `json:"font,omitempty" yaml:"font,omitempty"`
+	Background       string                 `json:"background,omitempty" yaml:"background,omitempty"`
+	Severity         DiagramSeverityColors  `json:"severity,omitempty" yaml:"severity,omitempty"`
+	Asset            DiagramAssetColors     `json:"asset,omitempty" yaml:"asset,omitempty"`
+	Link             DiagramLinkColors      `json:"link,omitempty" yaml:"link,omitempty"`
+	TrustBoundary    DiagramBoundaryColors  `json:"trust_boundary,omitempty" yaml:"trust_boundary,omitempty"`
+	DataAsset        DiagramDataAssetColors `json:"data_asset,omitempty" yaml:"data_asset,omitempty"`
+	Shapes           DiagramShapes          `json:"shapes,omitempty" yaml:"shapes,omitempty"`
+	TechnologyShapes map[string]string      `json:"technology_shapes,omitempty" yaml:"technology_shapes,omitempty"`
+}
+
+// DiagramSeverityColors are the fill colors of the nodes in the data asset diagram by the highest risk severity
+type DiagramSeverityColors struct {
+	Critical   string `json:"critical,omitempty" yaml:"critical,omitempty"`
+	High       string `json:"high,omitempty" yaml:"high,omitempty"`
+	Elevated   string `json:"elevated,omitempty" yaml:"elevated,omitempty"`
+	Medium     string `json:"medium,omitempty" yaml:"medium,omitempty"`
+	Low        string `json:"low,omitempty" yaml:"low,omitempty"`
+	None       string `json:"none,omitempty" yaml:"none,omitempty"`
+	OutOfScope string `json:"out_of_scope,omitempty" yaml:"out_of_scope,omitempty"`
+}
+
+// DiagramAssetColors are the colors of the technical asset nodes in the data flow diagram
+type DiagramAssetColors struct {
+	Fill                       string `json:"fill,omitempty" yaml:"fill,omitempty"`
+	FillModelForgery           string `json:"fill_model_forgery,omitempty" yaml:"fill_model_forgery,omitempty"`
+	FillInternet               string `json:"fill_internet,omitempty" yaml:"fill_internet,omitempty"`
+	FillOutOfScope             string `json:"fill_out_of_scope,omitempty" yaml:"fill_out_of_scope,omitempty"`
+	FillCustomDeveloped        string `json:"fill_custom_developed,omitempty" yaml:"fill_custom_developed,omitempty"`
+	Border                     string `json:"border,omitempty" yaml:"border,omitempty"`
+	BorderConfidential         string `json:"border_confidential,omitempty" yaml:"border_confidential,omitempty"`
+	BorderStrictlyConfidential string `json:"border_strictly_confidential,omitempty" yaml:"border_strictly_confidential,omitempty"`
+	Label                      string `json:"label,omitempty" yaml:"label,omitempty"`
+	LabelCritical              string `json:"label_critical,omitempty" yaml:"label_critical,omitempty"`
+	LabelMissionCritical       string `json:"label_mission_critical,omitempty" yaml:"label_mission_critical,omitempty"`
+	Technology                 string `json:"technology,omitempty" yaml:"technology,omitempty"`
+	Size                       string `json:"size,omitempty" yaml:"size,omitempty"`
+	RAA                        string `json:"raa,omitempty" yaml:"raa,omitempty"`
+}
+
+// DiagramLinkColors are the colors of the communication links in the data flow diagram
+type DiagramLinkColors struct {
+	Arrow                     string `json:"arrow,omitempty" yaml:"arrow,omitempty"`
+	ArrowConfidential         string `json:"arrow_confidential,omitempty" yaml:"arrow_confidential,omitempty"`
+	ArrowStrictlyConfidential string `json:"arrow_strictly_confidential,omitempty" yaml:"arrow_strictly_confidential,omitempty"`
+	ArrowModelForgery         string `json:"arrow_model_forgery,omitempty" yaml:"arrow_model_forgery,omitempty"`
+	ArrowDevOps               string `json:"arrow_devops,omitempty" yaml:"arrow_devops,omitempty"`
+	ArrowVPN                  string `json:"arrow_vpn,omitempty" yaml:"arrow_vpn,omitempty"`
+	ArrowIpFiltered           string `json:"arrow_ip_filtered,omitempty" yaml:"arrow_ip_filtered,omitempty"`
+	Label                     string `json:"label,omitempty" yaml:"label,omitempty"`
+	LabelCritical             string `json:"label_critical,omitempty" yaml:"label_critical,omitempty"`
+	LabelMissionCritical      string `json:"label_mission_critical,omitempty" yaml:"label_mission_critical,omitempty"`
+	DataAssetStored           string `json:"data_asset_stored,omitempty" yaml:"data_asset_stored,omitempty"`
+	DataAssetProcessed        string `json:"data_asset_processed,omitempty" yaml:"data_asset_processed,omitempty"`
+}
+
+// DiagramBoundaryColors are the colors of the trust boundary clusters in the data flow diagram
+type DiagramBoundaryColors struct {
+	Border                         string `json:"border,omitempty" yaml:"border,omitempty"`
+	Label                          string `json:"label,omitempty" yaml:"label,omitempty"`
+	Background                     string `json:"background,omitempty" yaml:"background,omitempty"`
+	BackgroundNested               string `json:"background_nested,omitempty" yaml:"background_nested,omitempty"`
+	LabelNamespace                 string `json:"label_namespace,omitempty" yaml:"label_namespace,omitempty"`
+	BackgroundNamespace            string `json:"background_namespace,omitempty" yaml:"background_namespace,omitempty"`
+	LabelExecutionEnvironment      string `json:"label_execution_environment,omitempty" yaml:"label_execution_environment,omitempty"`
+	BackgroundExecutionEnvironment string `json:"background_execution_environment,omitempty" yaml:"background_execution_environment,omitempty"`
+}
+
+// DiagramDataAssetColors are the colors of the data asset nodes in the data asset diagram
+type DiagramDataAssetColors struct {
+	Label string `json:"label,omitempty" yaml:"label,omitempty"`
+}
+
+// DiagramShapes are the node shapes by technical asset type, technology_shapes takes precedence over them
+type DiagramShapes struct {
+	ExternalEntity string `json:"external_entity,omitempty" yaml:"external_entity,omitempty"`
+	Process        string `json:"process,omitempty" yaml:"process,omitempty"`
+	Datastore      string `json:"datastore,omitempty" yaml:"datastore,omitempty"`
+	HumanClient    string `json:"human_client,omitempty" yaml:"human_client,omitempty"`
+}
+
+func (what *DiagramTheme) Defaults() *DiagramTheme {
+	*what = DiagramTheme{
+		Font:       "Verdana",
+		Background: "",
+		Severity: DiagramSeverityColors{
+			Critical:   rgbHexColorCriticalRisk(),
+			High:       rgbHexColorHighRisk(),
+			Elevated:   rgbHexColorElevatedRisk(),
+			Medium:     rgbHexColorMediumRisk(),
+			Low:        rgbHexColorLowRisk(),
+			None:       "#444444", // since black is too dark here as fill color
+			OutOfScope: rgbHexColorOutOfScope(),
+		},
+		Asset: DiagramAssetColors{
+			Fill:                       VeryLightGray,
+			FillModelForgery:           LightPink,
+			FillInternet:               ExtremeLightBlue,
+			FillOutOfScope:             OutOfScopeFancy,
+			FillCustomDeveloped:        CustomDevelopedParts,
+			Border:                     Black,
+			BorderConfidential:         Amber,
+			BorderStrictlyConfidential: Red,
+			Label:                      Black,
+			LabelCritical:              Amber,
+			LabelMissionCritical:       Red,
+			Technology:                 DarkBlue,
+			Size:                       LightGray,
+			RAA:                        "#603112",
+		},
+		Link: DiagramLinkColors{
+			Arrow:                     Black,
+			ArrowConfidential:         Amber,
+			ArrowStrictlyConfidential: Red,
+			ArrowModelForgery:         Pink,
+			ArrowDevOps:               MiddleLightGray,
+			ArrowVPN:                  DarkBlue,
+			ArrowIpFiltered:           Brown,
+			Label:                     Gray,
+			LabelCritical:             Amber,
+			LabelMissionCritical:      Red,
+			DataAssetStored:           "blue",
+			DataAssetProcessed:        "#666666",
+		},
+		TrustBoundary: DiagramBoundaryColors{
+			Border:                         rgbHexColorTwilight(),
+			Label:                          rgbHexColorTwilight(),
+			Background:                     "#FAFAFA",
+			BackgroundNested:               "#F1F1F1",
+			LabelNamespace:                 "#222222",
+			BackgroundNamespace:            "#DFF4FF",
+			LabelExecutionEnvironment:      "#555555",
+			BackgroundExecutionEnvironment: "#FFFFF0",
+		},
+		DataAsset: DiagramDataAssetColors{
+			Label: "white",
+		},
+		Shapes: DiagramShapes{
+			ExternalEntity: "box",
+			Process:        "ellipse",
+			Datastore:      "cylinder",
+			HumanClient:    "octagon",
+		},
+		TechnologyShapes: make(map[string]string),
+	}
+
+	return what
+}
+
+// HighContrast is a theme with saturated colors and a white background, e.g. for projectors and low-vision readers
+func (what *DiagramTheme) HighContrast() *DiagramTheme {
+	what.Defaults()
+	what.Background = "#FFFFFF"
+	what.Severity = DiagramSeverityColors{
+		Critical:   "#D00000",
+		High:       "#A00000",
+		Elevated:   "#E06000",
+		Medium:     "#905000",
+		Low:        "#003080",
+		None:       "#000000",
+		OutOfScope: "#606060",
+	}
+	what.Asset.Fill = "#FFFFFF"
+	what.Asset.FillModelForgery = "#FFC0E0"
+	what.Asset.FillInternet = "#C0FFFF"
+	what.Asset.FillOutOfScope = "#C0C0FF"
+	what.Asset.FillCustomDeveloped = "#FFFF60"
+	what.Asset.BorderConfidential = "#E06000"
+	what.Asset.BorderStrictlyConfidential = "#D00000"
+	what.Asset.LabelCritical = "#E06000"
+	what.Asset.LabelMissionCritical = "#D00000"
+	what.Asset.Technology = "#000080"
+	what.Asset.Size = "#000000"
+	what.Asset.RAA = "#000000"
+	what.Link.ArrowConfidential = "#E06000"
+	what.Link.ArrowStrictlyConfidential = "#D00000"
+	what.Link.ArrowModelForgery = "#FF00A0"
+	what.Link.ArrowDevOps = "#606060"
+	what.Link.ArrowVPN = "#000080"
+	what.Link.ArrowIpFiltered = "#603000"
+	what.Link.Label = "#000000"
+	what.Link.LabelCritical = "#E06000"
+	what.Link.LabelMissionCritical = "#D00000"
+	what.Link.DataAssetProcessed = "#000000"
+	what.TrustBoundary = DiagramBoundaryColors{
+		Border:                         "#0000C0",
+		Label:                          "#0000C0",
+		Background:                     "#FFFFFF",
+		BackgroundNested:               "#F0F0F0",
+		LabelNamespace:                 "#000000",
+		BackgroundNamespace:            "#E0F4FF",
+		LabelExecutionEnvironment:      "#000000",
+		BackgroundExecutionEnvironment: "#FFFFE0",
+	}
+	return what
+}
+
+// Dark is a theme with light lines and labels on a dark background, e.g. for documentation sites in dark mode
+func (what *DiagramTheme) Dark() *DiagramTheme {
+	what.Defaults()
+	what.Background = "#1E1E1E"
+	what.Severity.None = "#5A5A5A"
+	what.Severity.OutOfScope = "#6E6E6E"
+	what.Asset.Fill = "#3C3C3C"
+	what.Asset.FillModelForgery = "#6E3550"
+	what.Asset.FillInternet = "#1F4E5F"
+	what.Asset.FillOutOfScope = "#3A3D6B"
+	what.Asset.FillCustomDeveloped = "#5F5A1F"
+	what.Asset.Border = "#D4D4D4"
+	what.Asset.BorderConfidential = "#F0A030"
+	what.Asset.BorderStrictlyConfidential = "#FF5050"
+	what.Asset.Label = "#FFFFFF"
+	what.Asset.LabelCritical = "#F0A030"
+	what.Asset.LabelMissionCritical = "#FF5050"
+	what.Asset.Technology = "#9CDCFE"
+	what.Asset.Size = "#B0B0B0"
+	what.Asset.RAA = "#E0B080"
+	what.Link.Arrow = "#D4D4D4"
+	what.Link.ArrowConfidential = "#F0A030"
+	what.Link.ArrowStrictlyConfidential = "#FF5050"
+	what.Link.ArrowModelForgery = "#FF80C0"
+	what.Link.ArrowDevOps = "#808080"
+	what.Link.ArrowVPN = "#6090FF"
+	what.Link.ArrowIpFiltered = "#C08040"
+	what.Link.Label = "#C0C0C0"
+	what.Link.LabelCritical = "#F0A030"
+	what.Link.LabelMissionCritical = "#FF5050"
+	what.Link.DataAssetStored = "#6090FF"
+	what.Link.DataAssetProcessed = "#A0A0A0"
+	what.TrustBoundary = DiagramBoundaryColors{
+		Border:                         "#7B8CFF",
+		Label:                          "#7B8CFF",
+		Background:                     "#252526",
+		BackgroundNested:               "#2D2D30",
+		LabelNamespace:                 "#D4D4D4",
+		BackgroundNamespace:            "#1F3040",
+		LabelExecutionEnvironment:      "#B0B0B0",
+		BackgroundExecutionEnvironment: "#30302A",
+	}
+	return what
+}
+
+// Grayscale is a theme without colors for black and white printing, where severities differ by brightness
+func (what *DiagramTheme) Grayscale() *DiagramTheme {
+	what.Defaults()
+	what.Background = "#FFFFFF"
+	what.Severity = DiagramSeverityColors{
+		Critical:   "#000000",
+		High:       "#303030",
+		Elevated:   "#505050",
+		Medium:     "#707070",
+		Low:        "#909090",
+		None:       "#A8A8A8",
+		OutOfScope: "#C0C0C0",
+	}
+	what.Asset.Fill = "#F0F0F0"
+	what.Asset.FillModelForgery = "#D0D0D0"
+	what.Asset.FillInternet = "#FFFFFF"
+	what.Asset.FillOutOfScope = "#E0E0E0"
+	what.Asset.FillCustomDeveloped = "#F8F8F8"
+	what.Asset.BorderConfidential = "#404040"
+	what.Asset.BorderStrictlyConfidential = "#000000"
+	what.Asset.Border = "#808080"
+	what.Asset.LabelCritical = "#000000"
+	what.Asset.LabelMissionCritical = "#000000"
+	what.Asset.Label = "#303030"
+	what.Asset.Technology = "#404040"
+	what.Asset.Size = "#606060"
+	what.Asset.RAA = "#404040"
+	what.Link.Arrow = "#808080"
+	what.Link.ArrowConfidential = "#404040"
+	what.Link.ArrowStrictlyConfidential = "#000000"
+	what.Link.ArrowModelForgery = "#B0B0B0"
+	what.Link.ArrowDevOps = "#A0A0A0"
+	what.Link.ArrowVPN = "#202020"
+	what.Link.ArrowIpFiltered = "#606060"
+	what.Link.Label = "#404040"
+	what.Link.LabelCritical = "#202020"
+	what.Link.LabelMissionCritical = "#000000"
+	what.Link.DataAssetStored = "#000000"
+	what.Link.DataAssetProcessed = "#808080"
+	what.TrustBoundary = DiagramBoundaryColors{
+		Border:                         "#404040",
+		Label:                          "#404040",
+		Background:                     "#FAFAFA",
+		BackgroundNested:               "#F0F0F0",
+		LabelNamespace:                 "#202020",
+		BackgroundNamespace:            "#E8E8E8",
+		LabelExecutionEnvironment:      "#505050",
+		BackgroundExecutionEnvironment: "#F6F6F6",
+	}
+	return what
+}
+
+// LoadDiagramTheme returns the built-in theme of the given name or reads the theme from the given yaml file, where
+// missing values are taken from the default theme
+func LoadDiagramTheme(nameOrFilename string) (*DiagramTheme, error) {
+	switch strings.ToLower(strings.TrimSpace(nameOrFilename)) {
+	case "", DefaultDiagramTheme, LightDiagramTheme:
+		return new(DiagramTheme).Defaults(), nil
+	case DarkDiagramTheme:
+		return new(DiagramTheme).Dark(), nil
+	case HighContrastDiagramTheme:
+		return new(DiagramTheme).HighContrast(), nil
+	case GrayscaleDiagramTheme:
+		return new(DiagramTheme).Grayscale(), nil
+	}
+
+	data, readError := os.ReadFile(filepath.Clean(nameOrFilename))
+	if readError != nil {
+		return nil, fmt.Errorf("unable to read diagram theme %q (neither a file nor one of %v): %w", nameOrFilename, DiagramThemeNames(), readError)
+	}
+
+	theme := new(DiagramTheme).Defaults()
+	unmarshalError := yaml.Unmarshal(data, theme)
+	if unmarshalError != nil {
+		return nil, fmt.Errorf("unable to parse diagram theme %q: %w", nameOrFilename, unmarshalError)
+	}
+
+	escapeThemeValues(reflect.ValueOf(theme).Elem())
+	return theme, nil
+}
+
+// escapeThemeValues encodes the colors, fonts and shapes of a theme file like the labels, as they are written into
+// quoted attribute values and HTML labels of the diagrams, which e.g. a quote of a font name would end otherwise
+func escapeThemeValues(value reflect.Value) {
+	switch value.Kind() {
+	case reflect.String:
+		value.SetString(encode(value.String()))
+	case reflect.Struct:
+		for i := 0; i < value.NumField(); i++ {
+			escapeThemeValues(value.Field(i))
+		}
+	case reflect.Map:
+		for _, key := range value.MapKeys() {
+			value.SetMapIndex(key, reflect.ValueOf(encode(value.MapIndex(key).String())))
+		}
+	}
+}
+
+// DiagramThemeNames lists the built-in themes
+func DiagramThemeNames() []string {
+	return []string{DefaultDiagramTheme, LightDiagramTheme, DarkDiagramTheme, HighContrastDiagramTheme, GrayscaleDiagramTheme}
+}
+
+func isBuiltInDiagramTheme(name string) bool {
+	for _, builtInName := range DiagramThemeNames() {
+		if strings.EqualFold(builtInName, strings.TrimSpace(name)) {
+			return true
+		}
+	}
+	return false
+}
+
+func (what *DiagramTheme) severityColor(severity types.RiskSeverity) string {
+	switch severity {
+	case types.CriticalSeverity:
+		return what.Severity.Critical
+	case types.HighSeverity:
+		return what.Severity.High
+	case types.ElevatedSeverity:
+		return what.Severity.Elevated
+	case types.MediumSeverity:
+		return what.Severity.Medium
+	case types.LowSeverity:
+		return what.Severity.Low
+	default:
+		return what.Severity.None
+	}
+}
+
+func (what *DiagramTheme) dataBreachProbabilityColor(probability types.DataBreachProbability) string {
+	switch probability {
+	case types.Probable:
+		return what.Severity.High
+	case types.Possible:
+		return what.Severity.Medium
+	case types.Improbable:
+		return what.Severity.Low
+	default:
+		return what.Severity.None
+	}
+}
+
+// shape returns the node shape of the technical asset: the shape of the first of its technologies with a configured
+// technology shape, otherwise the shape of its type
+func (what *DiagramTheme) shape(technicalAsset *types.TechnicalAsset) string {
+	for _, technology := range technicalAsset.Technologies {
+		if technology == nil {
+			continue
+		}
+		if shape, ok := what.TechnologyShapes[technology.Name]; ok && len(shape) > 0 {
+			return shape
+		}
+	}
+
+	if technicalAsset.UsedAsClientByHuman {
+		return what.Shapes.HumanClient
+	}
+
+	switch technicalAsset.Type {
+	case types.ExternalEntity:
+		return what.Shapes.ExternalEntity
+	case types.Process:
+		return what.Shapes.Process
+	case types.Datastore:
+		return what.Shapes.Datastore
+	}
+
+	return what.Shapes.Process
+}
@@ -0,0 +1,73 @@
+package report
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/threagile/threagile/pkg/common"
+	"github.com/threagile/threagile/pkg/model"
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+func TestDiagramThemeFileValuesAreEscaped(t *testing.T) {
+	themeFile := filepath.Join(t.TempDir(), "theme.yaml")
+	assert.NoError(t, os.WriteFile(themeFile, []byte("font: 'Ver\"dana'\nasset:\n  fill_model_forgery: '#FFFFFF\" penwidth=\"9'\ntechnology_shapes:\n  web-server: 'box\" color=\"red'\n"), 0600))
+	theme, err := LoadDiagramTheme(themeFile)
+	assert.NoError(t, err)
+	assert.Equal(t, "Ver&quot;dana", theme.Font)
+	assert.Equal(t, new(DiagramTheme).Defaults().Link.Arrow, theme.Link.Arrow, "the defaults kept")
+
+	asset := &types.TechnicalAsset{Id: "asset", Title: "Web Server", Type: types.Process, Machine: types.Virtual, Technologies: types.TechnologyList{{Name: "web-server"}}}
+	parsedModel := &types.Model{TechnicalAssets: map[string]*types.TechnicalAsset{asset.Id: asset}}
+	fileSystem := common.NewMemoryFileSystem()
+	assert.NoError(t, WriteDataFlowDiagramGraphvizDOT(fileSystem, parsedModel, "data-flow-diagram.gv", 120, false, theme, silentProgressReporter{}))
+	dot, err := fileSystem.ReadFile("data-flow-diagram.gv")
+	assert.NoError(t, err)
+
+	assert.Contains(t, string(dot), `fontname="Ver&quot;dana"`)
+	assert.Contains(t, string(dot), `shape="box&quot; color=&quot;red"`)
+	assert.Contains(t, string(dot), `fillcolor="#FFFFFF&quot; penwidth=&quot;9"`)
+	assert.False(t, strings.Contains(string(dot), `penwidth="9"`) || strings.Contains(string(dot), `color="red"`), "no attributes injected")
+}
+
+func TestGenerateDiagramVariants(t *testing.T) {
+	config := new(common.Config).Defaults("")
+	config.FileSystem = common.NewMemoryFileSystem()
+	config.OutputFolder = filepath.Join(t.TempDir(), "output")
+	assert.NoError(t, config.FileSystem.MkdirAll(config.OutputFolder, 0700))
+	config.KeepDiagramSourceFiles = true
+	config.DiagramVariants = []string{"dark", "", "Grayscale"}
+	asset := &types.TechnicalAsset{Id: "shop", Title: "Shop", Type: types.Process}
+	parsedModel := &types.Model{Title: "Variant Test", TechnicalAssets: map[string]*types.TechnicalAsset{asset.Id: asset}}
+	readResult := &model.ReadResult{ParsedModel: parsedModel, Result: model.NewResult(parsedModel, nil, model.Timing{})}
+
+	files, err := GenerateFiles(context.Background(), config, readResult, &GenerateCommands{DataFlowDiagram: true}, silentProgressReporter{})
+	assert.NoError(t, err)
+	output := func(filename string) string {
+		return filepath.Join(config.OutputFolder, filename)
+	}
+	assert.Equal(t, []string{
+		output("data-flow-diagram.png"), output("data-flow-diagram.gv"),
+		output("data-flow-diagram-dark.png"), output("data-flow-diagram-dark.gv"),
+		output("data-flow-diagram-grayscale.png"), output("data-flow-diagram-grayscale.gv"),
+	}, files)
+
+	dot := make(map[string]string)
+	for _, variant := range []string{"", "-dark", "-grayscale"} {
+		data, err := config.FileSystem.ReadFile(output("data-flow-diagram" + variant + ".gv"))
+		assert.NoError(t, err, variant)
+		dot[variant] = string(data)
+	}
+	assert.NotContains(t, dot[""], "bgcolor=")
+	assert.Contains(t, dot["-dark"], `bgcolor="#1E1E1E"`)
+	assert.Contains(t, dot["-dark"], `<font color="#FFFFFF">Shop</font>`, "light labels")
+	assert.NotEqual(t, dot[""], dot["-grayscale"])
+
+	config.DiagramVariants = []string{"sepia"}
+	_, err = GenerateFiles(context.Background(), config, readResult, &GenerateCommands{DataFlowDiagram: true}, silentProgressReporter{})
+	assert.ErrorContains(t, err, `unknown diagram variant "sepia"`)
+}
@@ -29,6 +29,12 @@ func (what *ExcelColumns) GetColumns() ExcelColumns {
 		"R": {Title: "Date", Width: 18},
 		"S": {Title: "Checked by", Width: 20},
 		"T": {Title: "Ticket", Width: 20},
+		"U": {Title: "Merged Risks", Width: 30},
+		"V": {Title: "Inherent Severity", Width: 12},
+		"W": {Title: "Residual Severity", Width: 12},
+		"X": {Title: "Assignee", Width: 20},
+		"Y": {Title: "Due Date", Width: 18},
+		"Z": {Title: "Comments", Width: 80},
 	}
 
 	return *what
@@ -466,7 +466,7 @@ func (what *ExcelStyles) Get(column string, status types.RiskStatus, severity ty
 	case "L", "M", "N":
 		return what.mitigation
 
-	case "O":
+	case "O", "U":
 		return what.graySmall
 
 	case "P":
@@ -493,13 +493,13 @@ func (what *ExcelStyles) Get(column string, status types.RiskStatus, severity ty
 			return what.blackCenter
 		}
 
-	case "Q":
+	case "Q", "Z":
 		return what.blackSmall
 
-	case "R", "S":
+	case "R", "S", "V", "W", "Y":
 		return what.blackCenter
 
-	case "T":
+	case "T", "X":
 		return what.blackLeft
 	}
 
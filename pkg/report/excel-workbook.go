@@ -0,0 +1,255 @@
+package report
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/shopspring/decimal"
+	"github.com/threagile/threagile/pkg/common"
+	"github.com/threagile/threagile/pkg/model"
+	"github.com/threagile/threagile/pkg/security/types"
+	"github.com/xuri/excelize/v2"
+)
+
+// WriteWorkbookExcelToFile writes a single workbook with the risks, the tag matrix, the technical and data asset inventory,
+// the communication links crossing trust boundaries and the risk statistics on separate sheets
+func WriteWorkbookExcelToFile(fileSystem common.FileSystem, result *model.Result, filename string, config *common.Config) error {
+	parsedModel := result.ParsedModel()
+	excel := excelize.NewFile()
+	err := excel.SetDocProps(&excelize.DocProperties{
+		Category:       "Threat Model Workbook",
+		ContentStatus:  "Final",
+		Creator:        parsedModel.Author.Name,
+		Description:    parsedModel.Title + " via Threagile",
+		Identifier:     "xlsx",
+		Keywords:       "Threat Model",
+		LastModifiedBy: parsedModel.Author.Name,
+		Revision:       "0",
+		Subject:        parsedModel.Title,
+		Title:          parsedModel.Title,
+		Language:       "en-US",
+		Version:        "1.0.0",
+	})
+	if err != nil {
+		return fmt.Errorf("failed to set doc properties: %w", err)
+	}
+
+	cellStyles, err := new(ExcelStyles).Init(excel)
+	if err != nil {
+		return fmt.Errorf("unable to create cell styles: %w", err)
+	}
+
+	sheets := []struct {
+		name  string
+		write func(sheetName string) error
+	}{
+		{"Risks", func(sheetName string) error {
+			return writeRisksSheet(excel, sheetName, parsedModel, config, cellStyles)
+		}},
+		{"Tags", func(sheetName string) error {
+			err := writeTagsSheet(excel, sheetName, parsedModel, cellStyles)
+			if err != nil {
+				return err
+			}
+			return writeTagsSheetsPerElementType(excel, "Tags - ", parsedModel, cellStyles)
+		}},
+		{"Technical Assets", func(sheetName string) error {
+			return writeTableSheet(excel, sheetName, technicalAssetsTable(parsedModel), cellStyles)
+		}},
+		{"Data Assets", func(sheetName string) error {
+			return writeTableSheet(excel, sheetName, dataAssetsTable(parsedModel), cellStyles)
+		}},
+		{"Boundary Crossing Links", func(sheetName string) error {
+			return writeTableSheet(excel, sheetName, boundaryCrossingLinksTable(parsedModel), cellStyles)
+		}},
+		{"Stats", func(sheetName string) error {
+			return writeTableSheet(excel, sheetName, statsTable(parsedModel), cellStyles)
+		}},
+	}
+
+	for _, sheet := range sheets {
+		_, err = excel.NewSheet(sheet.name)
+		if err != nil {
+			return fmt.Errorf("failed to add sheet %q: %w", sheet.name, err)
+		}
+		err = sheet.write(sheet.name)
+		if err != nil {
+			return fmt.Errorf("failed to write sheet %q: %w", sheet.name, err)
+		}
+	}
+
+	err = excel.DeleteSheet("Sheet1")
+	if err != nil {
+		return fmt.Errorf("failed to delete sheet: %w", err)
+	}
+	excel.SetActiveSheet(0)
+	return saveExcel(fileSystem, excel, filename)
+}
+
+// writeTableSheet writes a simple table with a frozen header row, the first row of the given table being the header
+func writeTableSheet(excel *excelize.File, sheetName string, table [][]string, cellStyles *ExcelStyles) error {
+	for rowIndex, row := range table {
+		for columnIndex, value := range row {
+			cellName, err := excelize.CoordinatesToCellName(columnIndex+1, rowIndex+1)
+			if err != nil {
+				return fmt.Errorf("failed to get cell coordinates from [%d, %d]: %w", columnIndex+1, rowIndex+1, err)
+			}
+			err = excel.SetCellValue(sheetName, cellName, value)
+			if err != nil {
+				return fmt.Errorf("unable to set cell value: %w", err)
+			}
+		}
+	}
+	if len(table) == 0 || len(table[0]) == 0 {
+		return nil
+	}
+
+	lastColumn, err := excelize.ColumnNumberToName(len(table[0]))
+	if err != nil {
+		return err
+	}
+	err = excel.SetCellStyle(sheetName, "A1", lastColumn+"1", cellStyles.headCenterBold)
+	if err != nil {
+		return fmt.Errorf("unable to set cell style: %w", err)
+	}
+	if len(table) > 1 {
+		err = excel.SetCellStyle(sheetName, "A2", "A"+strconv.Itoa(len(table)), cellStyles.blackLeftBold)
+		if err != nil {
+			return fmt.Errorf("unable to set cell style: %w", err)
+		}
+		if len(table[0]) > 1 {
+			err = excel.SetCellStyle(sheetName, "B2", lastColumn+strconv.Itoa(len(table)), cellStyles.blackLeft)
+			if err != nil {
+				return fmt.Errorf("unable to set cell style: %w", err)
+			}
+		}
+	}
+
+	err = excel.SetColWidth(sheetName, "A", "A", 40)
+	if err != nil {
+		return err
+	}
+	if len(table[0]) > 1 {
+		err = excel.SetColWidth(sheetName, "B", lastColumn, 20)
+		if err != nil {
+			return err
+		}
+	}
+
+	return excel.SetPanes(sheetName, &excelize.Panes{
+		Freeze:      true,
+		Split:       false,
+		XSplit:      0,
+		YSplit:      1,
+		TopLeftCell: "A2",
+		ActivePane:  "bottomLeft",
+	})
+}
+
+func technicalAssetsTable(parsedModel *types.Model) [][]string {
+	table := [][]string{{"Title", "ID", "Type", "Usage", "Size", "Technologies", "Machine", "Internet", "Encryption",
+		"Owner", "Confidentiality", "Integrity", "Availability", "RAA", "Out of Scope", "Tags"}}
+	for _, asset := range sortedTechnicalAssetsByTitle(parsedModel) {
+		table = append(table, []string{
+			asset.Title,
+			asset.Id,
+			asset.Type.String(),
+			asset.Usage.String(),
+			asset.Size.String(),
+			asset.Technologies.String(),
+			asset.Machine.String(),
+			strconv.FormatBool(asset.Internet),
+			asset.Encryption.String(),
+			asset.Owner,
+			asset.Confidentiality.String(),
+			asset.Integrity.String(),
+			asset.Availability.String(),
+			decimal.NewFromFloat(asset.RAA).StringFixed(0),
+			strconv.FormatBool(asset.OutOfScope),
+			strings.Join(asset.Tags, ", "),
+		})
+	}
+	return table
+}
+
+func dataAssetsTable(parsedModel *types.Model) [][]string {
+	table := [][]string{{"Title", "ID", "Usage", "Quantity", "Origin", "Owner", "Confidentiality", "Integrity", "Availability",
+		"Data Breach Probability", "Tags"}}
+	for _, asset := range sortedDataAssetsByTitle(parsedModel) {
+		table = append(table, []string{
+			asset.Title,
+			asset.Id,
+			asset.Usage.String(),
+			asset.Quantity.String(),
+			asset.Origin,
+			asset.Owner,
+			asset.Confidentiality.String(),
+			asset.Integrity.String(),
+			asset.Availability.String(),
+			asset.IdentifiedDataBreachProbability(parsedModel).Title(),
+			strings.Join(asset.Tags, ", "),
+		})
+	}
+	return table
+}
+
+func boundaryCrossingLinksTable(parsedModel *types.Model) [][]string {
+	table := [][]string{{"Title", "ID", "Source", "Target", "Protocol", "Authentication", "Authorization", "Usage", "VPN",
+		"IP Filtered", "Data Sent", "Data Received", "Tags"}}
+	links := make([]*types.CommunicationLink, 0)
+	for _, link := range parsedModel.CommunicationLinks {
+		if link.IsAcrossTrustBoundary(parsedModel) {
+			links = append(links, link)
+		}
+	}
+	sort.Sort(types.ByTechnicalCommunicationLinkIdSort(links))
+	for _, link := range links {
+		table = append(table, []string{
+			link.Title,
+			link.Id,
+			technicalAssetTitle(parsedModel, link.SourceId),
+			technicalAssetTitle(parsedModel, link.TargetId),
+			link.Protocol.String(),
+			link.Authentication.String(),
+			link.Authorization.String(),
+			link.Usage.String(),
+			strconv.FormatBool(link.VPN),
+			strconv.FormatBool(link.IpFiltered),
+			strings.Join(link.DataAssetsSent, ", "),
+			strings.Join(link.DataAssetsReceived, ", "),
+			strings.Join(link.Tags, ", "),
+		})
+	}
+	return table
+}
+
+func statsTable(parsedModel *types.Model) [][]string {
+	statuses := types.RiskStatusValues()
+	header := []string{"Severity"}
+	for _, status := range statuses {
+		header = append(header, status.(types.RiskStatus).Title())
+	}
+	header = append(header, "Total", "Overdue")
+
+	table := [][]string{header}
+	statistics := types.OverallRiskStatistics(parsedModel)
+	for _, severity := range []types.RiskSeverity{types.CriticalSeverity, types.HighSeverity, types.ElevatedSeverity, types.MediumSeverity, types.LowSeverity} {
+		row, total := []string{severity.Title()}, 0
+		for _, status := range statuses {
+			count := statistics.Risks[severity.String()][status.(types.RiskStatus).String()]
+			row = append(row, strconv.Itoa(count))
+			total += count
+		}
+		table = append(table, append(row, strconv.Itoa(total), strconv.Itoa(statistics.Overdue[severity.String()])))
+	}
+	return table
+}
+
+func technicalAssetTitle(parsedModel *types.Model, id string) string {
+	if asset, ok := parsedModel.TechnicalAssets[id]; ok {
+		return asset.Title
+	}
+	return id
+}
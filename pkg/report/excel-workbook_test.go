@@ -0,0 +1,59 @@
+package report
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/threagile/threagile/pkg/common"
+	"github.com/threagile/threagile/pkg/model"
+	"github.com/xuri/excelize/v2"
+)
+
+func readExcelTestFile(t *testing.T, fileSystem common.FileSystem, filename string) *excelize.File {
+	data, err := fileSystem.ReadFile(filename)
+	assert.NoError(t, err)
+	excel, err := excelize.OpenReader(bytes.NewReader(data))
+	assert.NoError(t, err)
+	return excel
+}
+
+func TestWriteWorkbookExcelToFile(t *testing.T) {
+	parsedModel := reportTestModel()
+	fileSystem := common.NewMemoryFileSystem()
+	config := new(common.Config).Defaults("")
+	assert.NoError(t, WriteWorkbookExcelToFile(fileSystem, model.NewResult(parsedModel, nil, model.Timing{}), "workbook.xlsx", config))
+
+	excel := readExcelTestFile(t, fileSystem, "workbook.xlsx")
+	assert.Equal(t, []string{"Risks", "Tags", "Tags - Data Assets", "Tags - Trust Boundaries", "Tags - Shared Runtimes",
+		"Technical Assets", "Data Assets", "Boundary Crossing Links", "Stats"}, excel.GetSheetList())
+
+	risks, err := excel.GetRows("Risks")
+	assert.NoError(t, err)
+	assert.Len(t, risks, 4, "header and the risks")
+	assert.Contains(t, risks[1], "Test Rule at Shop & Co")
+
+	technicalAssets, err := excel.GetRows("Technical Assets")
+	assert.NoError(t, err)
+	assert.Len(t, technicalAssets, 3)
+	assert.Equal(t, []string{"Database", "database", "datastore"}, technicalAssets[1][:3], "sorted by title")
+	assert.Equal(t, []string{"Shop & Co", "shop", "process"}, technicalAssets[2][:3])
+
+	dataAssets, err := excel.GetRows("Data Assets")
+	assert.NoError(t, err)
+	assert.Len(t, dataAssets, 3)
+	assert.Equal(t, "Customers", dataAssets[1][0])
+	assert.Equal(t, "pci", dataAssets[1][len(dataAssets[0])-1])
+
+	links, err := excel.GetRows("Boundary Crossing Links")
+	assert.NoError(t, err)
+	assert.Len(t, links, 2)
+	assert.Equal(t, []string{"Database Access", "shop>database", "Shop & Co", "Database", "jdbc-encrypted"}, links[1][:5])
+
+	stats, err := excel.GetRows("Stats")
+	assert.NoError(t, err)
+	assert.Len(t, stats, 6, "header and one row per severity")
+	assert.Equal(t, "High", stats[2][0])
+	assert.Equal(t, "1", stats[2][len(stats[0])-2], "total of the high risks")
+	assert.Equal(t, "0", stats[1][len(stats[0])-2], "total of the critical risks")
+}
@@ -4,16 +4,18 @@ import (
 	"fmt"
 	"github.com/shopspring/decimal"
 	"github.com/threagile/threagile/pkg/common"
+	"github.com/threagile/threagile/pkg/model"
 	"github.com/threagile/threagile/pkg/security/types"
 	"github.com/xuri/excelize/v2"
 	"sort"
 	"strconv"
 	"strings"
+	"time"
 	"unicode/utf8"
 )
 
-func WriteRisksExcelToFile(parsedModel *types.Model, filename string, config *common.Config) error {
-	columns := new(ExcelColumns).GetColumns()
+func WriteRisksExcelToFile(fileSystem common.FileSystem, result *model.Result, filename string, config *common.Config) error {
+	parsedModel := result.ParsedModel()
 	excel := excelize.NewFile()
 	sheetName := parsedModel.Title
 
@@ -45,6 +47,37 @@ func WriteRisksExcelToFile(parsedModel *types.Model, filename string, config *co
 		return fmt.Errorf("failed to delete sheet: %w", deleteSheetError)
 	}
 
+	cellStyles, createCellStylesError := new(ExcelStyles).Init(excel)
+	if createCellStylesError != nil {
+		return fmt.Errorf("unable to create cell styles: %w", createCellStylesError)
+	}
+
+	writeSheetError := writeRisksSheet(excel, sheetName, parsedModel, config, cellStyles)
+	if writeSheetError != nil {
+		return writeSheetError
+	}
+
+	excel.SetActiveSheet(sheetIndex)
+
+	// save file
+	return saveExcel(fileSystem, excel, filename)
+}
+
+func saveExcel(fileSystem common.FileSystem, excel *excelize.File, filename string) error {
+	buffer, err := excel.WriteToBuffer()
+	if err != nil {
+		return fmt.Errorf("unable to save excel file: %w", err)
+	}
+	err = fileSystem.WriteFile(filename, buffer.Bytes(), 0600)
+	if err != nil {
+		return fmt.Errorf("unable to save excel file: %w", err)
+	}
+	return nil
+}
+
+func writeRisksSheet(excel *excelize.File, sheetName string, parsedModel *types.Model, config *common.Config, cellStyles *ExcelStyles) error {
+	columns := new(ExcelColumns).GetColumns()
+
 	orientation := "landscape"
 	size := 9 // A4
 	setPageLayoutError := excel.SetPageLayout(sheetName, &excelize.PageLayoutOptions{Orientation: &orientation, Size: &size})
@@ -73,12 +106,8 @@ func WriteRisksExcelToFile(parsedModel *types.Model, filename string, config *co
 		}
 	}
 
-	cellStyles, createCellStylesError := new(ExcelStyles).Init(excel)
-	if createCellStylesError != nil {
-		return fmt.Errorf("unable to create cell styles: %w", createCellStylesError)
-	}
-
 	// get sorted risks
+	now := time.Now()
 	riskItems := make([]RiskItem, 0)
 	for _, category := range types.SortedRiskCategories(parsedModel) {
 		risks := types.SortedRisksOfCategory(parsedModel, category)
@@ -102,6 +131,13 @@ func WriteRisksExcelToFile(parsedModel *types.Model, filename string, config *co
 			if !riskTracking.Date.IsZero() {
 				date = riskTracking.Date.Format("2006-01-02")
 			}
+			dueDate := ""
+			if !riskTracking.DueDate.IsZero() {
+				dueDate = riskTracking.DueDate.Format("2006-01-02")
+				if riskTracking.IsOverdue(now) {
+					dueDate += " (overdue)"
+				}
+			}
 
 			riskItems = append(riskItems, RiskItem{
 				Columns: []string{
@@ -125,6 +161,12 @@ func WriteRisksExcelToFile(parsedModel *types.Model, filename string, config *co
 					date,
 					riskTracking.CheckedBy,
 					riskTracking.Ticket,
+					strings.Join(risk.MergedRiskIds, ", "),
+					risk.InherentSeverity.Title(),
+					risk.ResidualSeverity.Title(),
+					riskTracking.Assignee,
+					dueDate,
+					strings.Join(riskCommentLines(risk), "\n"),
 				},
 				Status:   riskTracking.Status,
 				Severity: risk.Severity,
@@ -145,11 +187,17 @@ func WriteRisksExcelToFile(parsedModel *types.Model, filename string, config *co
 	}
 
 	// set header style
-	setCellStyleError := excel.SetCellStyle(sheetName, "A1", "T1", cellStyles.headCenterBoldItalic)
+	setCellStyleError := excel.SetCellStyle(sheetName, "A1", "Z1", cellStyles.headCenterBoldItalic)
 	if setCellStyleError != nil {
 		return fmt.Errorf("unable to set cell style: %w", setCellStyleError)
 	}
 
+	// filters in the header row, e.g. to select the risks of an assignee
+	autoFilterError := excel.AutoFilter(sheetName, "A1:Z"+strconv.Itoa(len(riskItems)+1), nil)
+	if autoFilterError != nil {
+		return fmt.Errorf("unable to set auto filter: %w", autoFilterError)
+	}
+
 	// fix column width
 	cols, colsError := excel.GetCols(sheetName)
 	if colsError == nil {
@@ -233,19 +281,11 @@ func WriteRisksExcelToFile(parsedModel *types.Model, filename string, config *co
 		return fmt.Errorf("unable to freeze header: %w", freezeError)
 	}
 
-	excel.SetActiveSheet(sheetIndex)
-
-	// save file
-	saveAsError := excel.SaveAs(filename)
-	if saveAsError != nil {
-		return fmt.Errorf("unable to save excel file: %w", saveAsError)
-	}
-
 	return nil
 }
 
-func WriteTagsExcelToFile(parsedModel *types.Model, filename string) error { // TODO: eventually when len(sortedTagsAvailable) == 0 is: write a hint in the Excel that no tags are used
-	excelRow := 0
+func WriteTagsExcelToFile(fileSystem common.FileSystem, result *model.Result, filename string) error { // TODO: eventually when len(sortedTagsAvailable) == 0 is: write a hint in the Excel that no tags are used
+	parsedModel := result.ParsedModel()
 	excel := excelize.NewFile()
 	sheetName := parsedModel.Title
 	err := excel.SetDocProps(&excelize.DocProperties{
@@ -268,9 +308,107 @@ func WriteTagsExcelToFile(parsedModel *types.Model, filename string) error { //
 
 	sheetIndex, _ := excel.NewSheet(sheetName)
 	_ = excel.DeleteSheet("Sheet1")
+
+	cellStyles, createCellStylesError := new(ExcelStyles).Init(excel)
+	if createCellStylesError != nil {
+		return fmt.Errorf("unable to create cell styles: %w", createCellStylesError)
+	}
+
+	err = writeTagsSheet(excel, sheetName, parsedModel, cellStyles)
+	if err != nil {
+		return err
+	}
+
+	err = writeTagsSheetsPerElementType(excel, "", parsedModel, cellStyles)
+	if err != nil {
+		return err
+	}
+
+	excel.SetActiveSheet(sheetIndex)
+	return saveExcel(fileSystem, excel, filename)
+}
+
+// taggedElement is a single row of a tag matrix
+type taggedElement struct {
+	title string
+	tags  []string
+}
+
+// writeTagsSheet writes the tag matrix of all model elements, i.e. technical assets with their outgoing communication links,
+// data assets, trust boundaries and shared runtimes
+func writeTagsSheet(excel *excelize.File, sheetName string, parsedModel *types.Model, cellStyles *ExcelStyles) error {
+	elements := make([]taggedElement, 0)
+	for _, techAsset := range sortedTechnicalAssetsByTitle(parsedModel) {
+		elements = append(elements, taggedElement{title: techAsset.Title, tags: techAsset.Tags})
+		for _, commLink := range techAsset.CommunicationLinksSorted() {
+			elements = append(elements, taggedElement{title: commLink.Title, tags: commLink.Tags})
+		}
+	}
+	elements = append(elements, dataAssetTagRows(parsedModel)...)
+	elements = append(elements, trustBoundaryTagRows(parsedModel)...)
+	elements = append(elements, sharedRuntimeTagRows(parsedModel)...)
+
+	return writeTagMatrix(excel, sheetName, parsedModel, "Element", elements, cellStyles) // TODO is "Element" the correct generic name when referencing assets, links, trust boundaries etc.? Eventually add separate column "type of element" like "technical asset" or "data asset"?
+}
+
+// writeTagsSheetsPerElementType writes a separate tag matrix for data assets, trust boundaries and shared runtimes,
+// as tag-driven rules apply to those elements too
+func writeTagsSheetsPerElementType(excel *excelize.File, sheetNamePrefix string, parsedModel *types.Model, cellStyles *ExcelStyles) error {
+	sheets := []struct {
+		title         string
+		elementHeader string
+		elements      []taggedElement
+	}{
+		{"Data Assets", "Data Asset", dataAssetTagRows(parsedModel)},
+		{"Trust Boundaries", "Trust Boundary", trustBoundaryTagRows(parsedModel)},
+		{"Shared Runtimes", "Shared Runtime", sharedRuntimeTagRows(parsedModel)},
+	}
+
+	for _, sheet := range sheets {
+		sheetName := sheetNamePrefix + sheet.title
+		_, err := excel.NewSheet(sheetName)
+		if err != nil {
+			return fmt.Errorf("failed to add sheet %q: %w", sheetName, err)
+		}
+
+		err = writeTagMatrix(excel, sheetName, parsedModel, sheet.elementHeader, sheet.elements, cellStyles)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func dataAssetTagRows(parsedModel *types.Model) []taggedElement {
+	elements := make([]taggedElement, 0)
+	for _, dataAsset := range sortedDataAssetsByTitle(parsedModel) {
+		elements = append(elements, taggedElement{title: dataAsset.Title, tags: dataAsset.Tags})
+	}
+	return elements
+}
+
+func trustBoundaryTagRows(parsedModel *types.Model) []taggedElement {
+	elements := make([]taggedElement, 0)
+	for _, trustBoundary := range sortedTrustBoundariesByTitle(parsedModel) {
+		elements = append(elements, taggedElement{title: trustBoundary.Title, tags: trustBoundary.Tags})
+	}
+	return elements
+}
+
+func sharedRuntimeTagRows(parsedModel *types.Model) []taggedElement {
+	elements := make([]taggedElement, 0)
+	for _, sharedRuntime := range sortedSharedRuntimesByTitle(parsedModel) {
+		elements = append(elements, taggedElement{title: sharedRuntime.Title, tags: sharedRuntime.Tags})
+	}
+	return elements
+}
+
+// writeTagMatrix writes one row per element with an X for each tag used, followed by a row with the totals per tag
+func writeTagMatrix(excel *excelize.File, sheetName string, parsedModel *types.Model, elementHeader string, elements []taggedElement, cellStyles *ExcelStyles) error {
+	excelRow := 0
 	orientation := "landscape"
 	size := 9
-	err = excel.SetPageLayout(sheetName, &excelize.PageLayoutOptions{Orientation: &orientation, Size: &size}) // A4
+	err := excel.SetPageLayout(sheetName, &excelize.PageLayoutOptions{Orientation: &orientation, Size: &size}) // A4
 	if err != nil {
 		return err
 	}
@@ -288,7 +426,7 @@ func WriteTagsExcelToFile(parsedModel *types.Model, filename string) error { //
 		return err
 	}
 
-	err = excel.SetCellValue(sheetName, "A1", "Element") // TODO is "Element" the correct generic name when referencing assets, links, trust boundaries etc.? Eventually add separate column "type of element" like "technical asset" or "data asset"?
+	err = excel.SetCellValue(sheetName, "A1", elementHeader)
 	if err != nil {
 		return err
 	}
@@ -320,42 +458,18 @@ func WriteTagsExcelToFile(parsedModel *types.Model, filename string) error { //
 		return err
 	}
 
-	cellStyles, createCellStylesError := new(ExcelStyles).Init(excel)
-	if createCellStylesError != nil {
-		return fmt.Errorf("unable to create cell styles: %w", createCellStylesError)
-	}
-
 	excelRow++ // as we have a header line
 	if len(sortedTagsAvailable) > 0 {
-		for _, techAsset := range sortedTechnicalAssetsByTitle(parsedModel) {
-			err := writeRow(excel, &excelRow, sheetName, lastColumn, cellStyles.blackLeftBold, cellStyles.blackCenter, sortedTagsAvailable, techAsset.Title, techAsset.Tags)
-			if err != nil {
-				return fmt.Errorf("unable to write row: %w", err)
-			}
-			for _, commLink := range techAsset.CommunicationLinksSorted() {
-				err := writeRow(excel, &excelRow, sheetName, lastColumn, cellStyles.blackLeftBold, cellStyles.blackCenter, sortedTagsAvailable, commLink.Title, commLink.Tags)
-				if err != nil {
-					return fmt.Errorf("unable to write row: %w", err)
-				}
-			}
-		}
-		for _, dataAsset := range sortedDataAssetsByTitle(parsedModel) {
-			err := writeRow(excel, &excelRow, sheetName, lastColumn, cellStyles.blackLeftBold, cellStyles.blackCenter, sortedTagsAvailable, dataAsset.Title, dataAsset.Tags)
-			if err != nil {
-				return fmt.Errorf("unable to write row: %w", err)
-			}
-		}
-		for _, trustBoundary := range sortedTrustBoundariesByTitle(parsedModel) {
-			err := writeRow(excel, &excelRow, sheetName, lastColumn, cellStyles.blackLeftBold, cellStyles.blackCenter, sortedTagsAvailable, trustBoundary.Title, trustBoundary.Tags)
+		for _, element := range elements {
+			err := writeRow(excel, &excelRow, sheetName, lastColumn, cellStyles.blackLeftBold, cellStyles.blackCenter, sortedTagsAvailable, element.title, element.tags)
 			if err != nil {
 				return fmt.Errorf("unable to write row: %w", err)
 			}
 		}
-		for _, sharedRuntime := range sortedSharedRuntimesByTitle(parsedModel) {
-			err := writeRow(excel, &excelRow, sheetName, lastColumn, cellStyles.blackLeftBold, cellStyles.blackCenter, sortedTagsAvailable, sharedRuntime.Title, sharedRuntime.Tags)
-			if err != nil {
-				return fmt.Errorf("unable to write row: %w", err)
-			}
+
+		err = writeTotalsRow(excel, &excelRow, sheetName, cellStyles, sortedTagsAvailable, elements)
+		if err != nil {
+			return fmt.Errorf("unable to write totals row: %w", err)
 		}
 	}
 
@@ -366,13 +480,51 @@ func WriteTagsExcelToFile(parsedModel *types.Model, filename string) error { //
 	if err != nil {
 		return fmt.Errorf("unable to set cell style: %w", err)
 	}
+	return nil
+}
 
-	excel.SetActiveSheet(sheetIndex)
-	err = excel.SaveAs(filename)
+func writeTotalsRow(excel *excelize.File, excelRow *int, sheetName string, cellStyles *ExcelStyles, sortedTags []string, elements []taggedElement) error {
+	*excelRow++
+
+	firstCellName, err := excelize.CoordinatesToCellName(1, *excelRow)
 	if err != nil {
-		return fmt.Errorf("unable to save excel file: %w", err)
+		return fmt.Errorf("failed to get cell coordinates from [%d, %d]: %w", 1, *excelRow, err)
 	}
-	return nil
+
+	err = excel.SetCellValue(sheetName, firstCellName, "Total")
+	if err != nil {
+		return err
+	}
+
+	for i, tag := range sortedTags {
+		count := 0
+		for _, element := range elements {
+			if contains(element.tags, tag) {
+				count++
+			}
+		}
+
+		cellName, coordinatesToCellNameError := excelize.CoordinatesToCellName(i+2, *excelRow)
+		if coordinatesToCellNameError != nil {
+			return fmt.Errorf("failed to get cell coordinates from [%d, %d]: %w", i+2, *excelRow, coordinatesToCellNameError)
+		}
+
+		err = excel.SetCellValue(sheetName, cellName, count)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = excel.SetCellStyle(sheetName, firstCellName, firstCellName, cellStyles.headCenterBold)
+	if err != nil {
+		return err
+	}
+
+	lastCellName, err := excelize.CoordinatesToCellName(len(sortedTags)+1, *excelRow)
+	if err != nil {
+		return fmt.Errorf("failed to get cell coordinates from [%d, %d]: %w", len(sortedTags)+1, *excelRow, err)
+	}
+	return excel.SetCellStyle(sheetName, "B"+strconv.Itoa(*excelRow), lastCellName, cellStyles.headCenter)
 }
 
 func sortedTrustBoundariesByTitle(parsedModel *types.Model) []*types.TrustBoundary {
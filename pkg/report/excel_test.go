@@ -0,0 +1,44 @@
+package report
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/threagile/threagile/pkg/common"
+	"github.com/threagile/threagile/pkg/model"
+)
+
+func TestWriteTagsExcelToFile(t *testing.T) {
+	fileSystem := common.NewMemoryFileSystem()
+	assert.NoError(t, WriteTagsExcelToFile(fileSystem, model.NewResult(reportTestModel(), nil, model.Timing{}), "tags.xlsx"))
+
+	excel := readExcelTestFile(t, fileSystem, "tags.xlsx")
+	assert.Equal(t, []string{"Report Test", "Data Assets", "Trust Boundaries", "Shared Runtimes"}, excel.GetSheetList())
+
+	all, err := excel.GetRows("Report Test")
+	assert.NoError(t, err)
+	assert.Equal(t, [][]string{
+		{"Element", "aws", "pci"},
+		{"Database", "", "X"},
+		{"Shop & Co", "X"},
+		{"Database Access", "", "X"},
+		{"Customers", "", "X"},
+		{"Logs"},
+		{"DMZ", "X"},
+		{"Internal"},
+		{"Cluster", "X", "X"},
+		{"Total", "3", "4"},
+	}, all, "all elements, the unused tag left out")
+
+	dataAssets, err := excel.GetRows("Data Assets")
+	assert.NoError(t, err)
+	assert.Equal(t, [][]string{{"Data Asset", "aws", "pci"}, {"Customers", "", "X"}, {"Logs"}, {"Total", "0", "1"}}, dataAssets)
+
+	trustBoundaries, err := excel.GetRows("Trust Boundaries")
+	assert.NoError(t, err)
+	assert.Equal(t, [][]string{{"Trust Boundary", "aws", "pci"}, {"DMZ", "X"}, {"Internal"}, {"Total", "1", "0"}}, trustBoundaries)
+
+	sharedRuntimes, err := excel.GetRows("Shared Runtimes")
+	assert.NoError(t, err)
+	assert.Equal(t, [][]string{{"Shared Runtime", "aws", "pci"}, {"Cluster", "X", "X"}, {"Total", "1", "1"}}, sharedRuntimes)
+}
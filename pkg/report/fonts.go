@@ -0,0 +1,99 @@
+package report
+
+import (
+	"fmt"
+	"html"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/jung-kurt/gofpdf"
+	"golang.org/x/image/font/gofont/gobold"
+	"golang.org/x/image/font/gofont/gobolditalic"
+	"golang.org/x/image/font/gofont/goitalic"
+	"golang.org/x/image/font/gofont/goregular"
+)
+
+// fontFamily is the unicode font family the PDFs are written in, either the bundled Go fonts (covering Latin, Greek
+// and Cyrillic) or the configured font file (e.g. one with CJK)
+const fontFamily = "unicode"
+
+var systemFontConfigs = []string{"/etc/fonts/fonts.conf", "/usr/local/etc/fonts/fonts.conf", "/opt/homebrew/etc/fonts/fonts.conf"}
+
+// keepUTF8 passes the text unchanged, as the unicode fonts take UTF-8 (unlike the core fonts needing cp1252)
+func keepUTF8(text string) string {
+	return text
+}
+
+func addUnicodeFonts(pdf *gofpdf.Fpdf, fontFile string) error {
+	if len(fontFile) > 0 {
+		data, err := os.ReadFile(filepath.Clean(fontFile))
+		if err != nil {
+			return fmt.Errorf("unable to read font file %q: %w", fontFile, err)
+		}
+		// the single font file is used for all styles
+		for _, style := range []string{"", "B", "I", "BI"} {
+			pdf.AddUTF8FontFromBytes(fontFamily, style, data)
+		}
+		return pdf.Error()
+	}
+
+	pdf.AddUTF8FontFromBytes(fontFamily, "", goregular.TTF)
+	pdf.AddUTF8FontFromBytes(fontFamily, "B", gobold.TTF)
+	pdf.AddUTF8FontFromBytes(fontFamily, "I", goitalic.TTF)
+	pdf.AddUTF8FontFromBytes(fontFamily, "BI", gobolditalic.TTF)
+	return pdf.Error()
+}
+
+// graphvizFontEnvironment writes the unicode fonts into a folder below the temp folder and returns the environment of
+// the graphviz call adding them to the system fonts, so that labels in scripts missing from the diagram theme font (or
+// from all installed fonts, like in slim container images) fall back to them instead of rendering garbled
+func graphvizFontEnvironment(tempFolder string, fontFile string) ([]string, func(), error) {
+	if runtime.GOOS == "windows" { // graphviz on windows comes with its own fontconfig setup
+		return os.Environ(), func() {}, nil
+	}
+
+	fontFolder, err := os.MkdirTemp(tempFolder, "fonts-")
+	if err != nil {
+		return nil, nil, fmt.Errorf("error creating font folder: %v", err)
+	}
+	removeFontFolder := func() { _ = os.RemoveAll(fontFolder) }
+
+	fonts := map[string][]byte{"Go-Regular.ttf": goregular.TTF, "Go-Bold.ttf": gobold.TTF}
+	if len(fontFile) > 0 {
+		data, err := os.ReadFile(filepath.Clean(fontFile))
+		if err != nil {
+			removeFontFolder()
+			return nil, nil, fmt.Errorf("unable to read font file %q: %w", fontFile, err)
+		}
+		fonts = map[string][]byte{filepath.Base(fontFile): data}
+	}
+	for name, data := range fonts {
+		err = os.WriteFile(filepath.Join(fontFolder, name), data, 0600)
+		if err != nil {
+			removeFontFolder()
+			return nil, nil, fmt.Errorf("error writing font %s: %v", name, err)
+		}
+	}
+
+	includes := systemFontConfigs
+	if config := os.Getenv("FONTCONFIG_FILE"); len(config) > 0 {
+		includes = []string{config}
+	}
+	var fontConfig strings.Builder
+	fontConfig.WriteString("<?xml version=\"1.0\"?>\n<!DOCTYPE fontconfig SYSTEM \"fonts.dtd\">\n<fontconfig>\n")
+	for _, include := range includes {
+		fontConfig.WriteString("  <include ignore_missing=\"yes\">" + html.EscapeString(include) + "</include>\n")
+	}
+	fontConfig.WriteString("  <dir>" + html.EscapeString(fontFolder) + "</dir>\n  <cachedir>" + html.EscapeString(fontFolder) + "</cachedir>\n</fontconfig>\n")
+	fontConfigFile := filepath.Join(fontFolder, "fonts.conf")
+	err = os.WriteFile(fontConfigFile, []byte(fontConfig.String()), 0600)
+	if err != nil {
+		removeFontFolder()
+		return nil, nil, fmt.Errorf("error writing %s: %v", fontConfigFile, err)
+	}
+
+	// GDFONTPATH for graphviz builds rendering with gd instead of cairo
+	return append(os.Environ(), "FONTCONFIG_FILE="+fontConfigFile, "GDFONTPATH="+fontFolder), removeFontFolder, nil
+}
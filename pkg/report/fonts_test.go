@@ -0,0 +1,98 @@
+package report
+
+import (
+	"encoding/xml"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/threagile/threagile/pkg/common"
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+// nonLatinTestModel has titles in Cyrillic, Greek and CJK, which the core PDF fonts would render garbled
+func nonLatinTestModel() *types.Model {
+	webServer := &types.TechnicalAsset{Id: "web-server", Title: "Веб-сервер", Description: "Обслуживает клиентов",
+		Type: types.Process, Technologies: types.TechnologyList{{Name: "web-server"}}, Owner: "Ομάδα λειτουργιών",
+		DataAssetsProcessed: []string{"customer-data"},
+		CommunicationLinks: []*types.CommunicationLink{{Id: "web-server>db", Title: "Запросы", SourceId: "web-server", TargetId: "db",
+			Protocol: types.JdbcEncrypted, DataAssetsSent: []string{"customer-data"}}}}
+	database := &types.TechnicalAsset{Id: "db", Title: "顧客データベース", Type: types.Datastore,
+		Technologies: types.TechnologyList{{Name: "database"}}, DataAssetsStored: []string{"customer-data"}}
+	return &types.Model{
+		Title:           "Модель угроз",
+		TechnicalAssets: map[string]*types.TechnicalAsset{webServer.Id: webServer, database.Id: database},
+		DataAssets:      map[string]*types.DataAsset{"customer-data": {Id: "customer-data", Title: "Δεδομένα πελατών"}},
+		IncomingTechnicalCommunicationLinksMappedByTargetId: map[string][]*types.CommunicationLink{"db": webServer.CommunicationLinks},
+	}
+}
+
+func TestDataFlowDiagramKeepsNonLatinTitles(t *testing.T) {
+	theme, err := LoadDiagramTheme("")
+	assert.NoError(t, err)
+
+	fileSystem := common.NewMemoryFileSystem()
+	assert.NoError(t, fileSystem.MkdirAll("/output", 0700))
+	filename := filepath.Join("/output", "data-flow-diagram.gv")
+	assert.NoError(t, WriteDataFlowDiagramGraphvizDOT(fileSystem, nonLatinTestModel(), filename, 120, true, theme, silentProgressReporter{}))
+
+	dot, err := fileSystem.ReadFile(filename)
+	assert.NoError(t, err)
+	for _, title := range []string{"Модель угроз", "Веб-сервер", "顧客データベース"} {
+		assert.Contains(t, string(dot), title)
+	}
+}
+
+func TestAssetSheetWithNonLatinTitles(t *testing.T) {
+	parsedModel := nonLatinTestModel()
+	fileSystem := common.NewMemoryFileSystem()
+	assert.NoError(t, fileSystem.MkdirAll("/output", 0700))
+	filename := filepath.Join("/output", "asset-sheet.pdf")
+
+	assert.NoError(t, WriteAssetSheetPDF(fileSystem, parsedModel, parsedModel.TechnicalAssets["web-server"], filename, ""))
+	pdf, err := fileSystem.ReadFile(filename)
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(pdf), "%PDF-"))
+}
+
+func TestAssetSheetWithMissingFontFile(t *testing.T) {
+	parsedModel := nonLatinTestModel()
+	filename := filepath.Join(t.TempDir(), "asset-sheet.pdf")
+
+	assert.Error(t, WriteAssetSheetPDF(common.OSFileSystem{}, parsedModel, parsedModel.TechnicalAssets["web-server"], filename, filepath.Join(t.TempDir(), "missing.ttf")))
+}
+
+func TestGraphvizFontEnvironmentAddsBundledFonts(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("graphviz on windows keeps its own fontconfig setup")
+	}
+
+	tempFolder := filepath.Join(t.TempDir(), "R&D <fonts>")
+	assert.NoError(t, os.Mkdir(tempFolder, 0700))
+	t.Setenv("FONTCONFIG_FILE", "/etc/fonts/fonts.conf&<local>")
+	env, removeFonts, err := graphvizFontEnvironment(tempFolder, "")
+	assert.NoError(t, err)
+
+	fontConfigFile := ""
+	for _, variable := range env {
+		if strings.HasPrefix(variable, "FONTCONFIG_FILE=") {
+			fontConfigFile = strings.TrimPrefix(variable, "FONTCONFIG_FILE=")
+		}
+	}
+	fontConfig, err := os.ReadFile(fontConfigFile)
+	assert.NoError(t, err)
+	var parsed struct {
+		Includes []string `xml:"include"`
+		Dir      string   `xml:"dir"`
+	}
+	assert.NoError(t, xml.Unmarshal(fontConfig, &parsed), "paths escaped")
+	assert.Equal(t, []string{"/etc/fonts/fonts.conf&<local>"}, parsed.Includes)
+	assert.Equal(t, filepath.Dir(fontConfigFile), parsed.Dir)
+	assert.FileExists(t, filepath.Join(filepath.Dir(fontConfigFile), "Go-Regular.ttf"))
+
+	removeFonts()
+	assert.NoDirExists(t, filepath.Dir(fontConfigFile))
+}
@@ -1,26 +1,35 @@
 package report
 
 import (
+	"context"
 	"crypto/sha256"
 	"encoding/hex"
 	"fmt"
-	"io"
-	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/threagile/threagile/pkg/common"
 	"github.com/threagile/threagile/pkg/model"
+	"github.com/threagile/threagile/pkg/security/types"
 )
 
 type GenerateCommands struct {
 	DataFlowDiagram     bool
 	DataAssetDiagram    bool
 	RisksJSON           bool
+	RisksSARIF          bool
+	DueDatesICS         bool
 	TechnicalAssetsJSON bool
 	StatsJSON           bool
 	RisksExcel          bool
 	TagsExcel           bool
 	ReportPDF           bool
+	RulesDoc            bool
+	RisksPerOwner       bool
+	RiskMatrix          bool
+	ExcelWorkbook       bool
+	AssetSheets         bool
+	MitigationChecklist bool
 }
 
 func (c *GenerateCommands) Defaults() *GenerateCommands {
@@ -28,16 +37,34 @@ func (c *GenerateCommands) Defaults() *GenerateCommands {
 		DataFlowDiagram:     true,
 		DataAssetDiagram:    true,
 		RisksJSON:           true,
+		RisksSARIF:          false,
+		DueDatesICS:         false,
 		TechnicalAssetsJSON: true,
 		StatsJSON:           true,
 		RisksExcel:          true,
 		TagsExcel:           true,
 		ReportPDF:           true,
+		RulesDoc:            false,
+		RisksPerOwner:       false,
+		RiskMatrix:          true,
+		ExcelWorkbook:       false,
+		AssetSheets:         false,
+		MitigationChecklist: false,
 	}
 	return c
 }
 
-func Generate(config *common.Config, readResult *model.ReadResult, commands *GenerateCommands, progressReporter progressReporter) error {
+func Generate(ctx context.Context, config *common.Config, readResult *model.ReadResult, commands *GenerateCommands, progressReporter progressReporter) error {
+	_, err := GenerateFiles(ctx, config, readResult, commands, progressReporter)
+	return err
+}
+
+// GenerateFiles generates the reports and diagrams like Generate and returns the files written (or kept from the
+// previous run)
+func GenerateFiles(ctx context.Context, config *common.Config, readResult *model.ReadResult, commands *GenerateCommands, progressReporter progressReporter) ([]string, error) {
+	ctx, cancel := common.WithTimeout(ctx, config.RenderTimeoutSeconds)
+	defer cancel()
+
 	generateDataFlowDiagram := commands.DataFlowDiagram
 	generateDataAssetsDiagram := commands.DataAssetDiagram
 	if commands.ReportPDF { // as the PDF report includes both diagrams
@@ -45,135 +72,459 @@ func Generate(config *common.Config, readResult *model.ReadResult, commands *Gen
 		generateDataAssetsDiagram = true
 	}
 
-	diagramDPI := config.DiagramDPI
-	if diagramDPI < common.MinGraphvizDPI {
-		diagramDPI = common.MinGraphvizDPI
-	} else if diagramDPI > common.MaxGraphvizDPI {
-		diagramDPI = common.MaxGraphvizDPI
+	dataFlowDiagramDPI := DataFlowDiagramDPI(config, readResult.ParsedModel)
+	dataAssetDiagramDPI := DataAssetDiagramDPI(config, readResult.ParsedModel)
+	diagramTheme, err := LoadDiagramTheme(config.DiagramTheme)
+	if err != nil {
+		return nil, err
 	}
-	// Data-flow Diagram rendering
-	if generateDataFlowDiagram {
-		gvFile := filepath.Join(config.OutputFolder, config.DataFlowDiagramFilenameDOT)
-		if !config.KeepDiagramSourceFiles {
-			tmpFileGV, err := os.CreateTemp(config.TempFolder, config.DataFlowDiagramFilenameDOT)
-			if err != nil {
-				return err
-			}
-			gvFile = tmpFileGV.Name()
-			defer func() { _ = os.Remove(gvFile) }()
+	for _, variant := range config.DiagramVariants {
+		if len(variant) > 0 && !isBuiltInDiagramTheme(variant) {
+			return nil, fmt.Errorf("unknown diagram variant %q (must be one of %v)", variant, DiagramThemeNames())
 		}
-		dotFile, err := WriteDataFlowDiagramGraphvizDOT(readResult.ParsedModel, gvFile, diagramDPI, config.AddModelTitle, progressReporter)
-		if err != nil {
-			return fmt.Errorf("error while generating data flow diagram: %s", err)
+	}
+	result := readResult.Result
+	if result == nil { // read results not of an analysis, e.g. of models parsed by the server only
+		result = model.NewResult(readResult.ParsedModel, readResult.RiskRuleFailures, model.Timing{})
+	}
+	stages := make([]generationStage, 0)
+	output := func(filename string) string {
+		return filepath.Join(config.OutputFolder, filename)
+	}
+	diagramFiles := func(filenameDOT string, filenamePNG string) []string {
+		if config.KeepDiagramSourceFiles {
+			return []string{output(filenamePNG), output(filenameDOT)}
 		}
+		return []string{output(filenamePNG)}
+	}
 
-		err = GenerateDataFlowDiagramGraphvizImage(dotFile, config.OutputFolder,
-			config.TempFolder, config.DataFlowDiagramFilenamePNG, progressReporter, config.KeepDiagramSourceFiles)
-		if err != nil {
-			progressReporter.Warn(err)
-		}
+	// Data-flow Diagram rendering
+	if generateDataFlowDiagram {
+		stages = append(stages, generationStage{name: "data flow diagram",
+			files: diagramFiles(config.DataFlowDiagramFilenameDOT, config.DataFlowDiagramFilenamePNG),
+			run: func() error {
+				return writeDataFlowDiagram(ctx, config, readResult.ParsedModel, dataFlowDiagramDPI, diagramTheme,
+					config.DataFlowDiagramFilenameDOT, config.DataFlowDiagramFilenamePNG, progressReporter)
+			}})
 	}
 	// Data Asset Diagram rendering
 	if generateDataAssetsDiagram {
-		gvFile := filepath.Join(config.OutputFolder, config.DataAssetDiagramFilenameDOT)
-		if !config.KeepDiagramSourceFiles {
-			tmpFile, err := os.CreateTemp(config.TempFolder, config.DataAssetDiagramFilenameDOT)
-			if err != nil {
-				return err
-			}
-			gvFile = tmpFile.Name()
-			defer func() { _ = os.Remove(gvFile) }()
+		stages = append(stages, generationStage{name: "data asset diagram",
+			files: diagramFiles(config.DataAssetDiagramFilenameDOT, config.DataAssetDiagramFilenamePNG),
+			run: func() error {
+				return writeDataAssetDiagram(ctx, config, readResult.ParsedModel, dataAssetDiagramDPI, diagramTheme,
+					config.DataAssetDiagramFilenameDOT, config.DataAssetDiagramFilenamePNG, progressReporter)
+			}})
+	}
+	// additional style variants of the diagrams, e.g. for documentation sites in dark mode and printed reports
+	for _, variant := range config.DiagramVariants {
+		if len(variant) == 0 {
+			continue
 		}
-		dotFile, err := WriteDataAssetDiagramGraphvizDOT(readResult.ParsedModel, gvFile, diagramDPI, progressReporter)
+		variantTheme, err := LoadDiagramTheme(variant)
 		if err != nil {
-			return fmt.Errorf("error while generating data asset diagram: %s", err)
+			return nil, err
 		}
-		err = GenerateDataAssetDiagramGraphvizImage(dotFile, config.OutputFolder,
-			config.TempFolder, config.DataAssetDiagramFilenamePNG, progressReporter)
-		if err != nil {
-			progressReporter.Warn(err)
+		if generateDataFlowDiagram {
+			filenameDOT, filenamePNG := ownerFilename(config.DataFlowDiagramFilenameDOT, variant), ownerFilename(config.DataFlowDiagramFilenamePNG, variant)
+			stages = append(stages, generationStage{name: "data flow diagram " + variant,
+				files: diagramFiles(filenameDOT, filenamePNG),
+				run: func() error {
+					return writeDataFlowDiagram(ctx, config, readResult.ParsedModel, dataFlowDiagramDPI, variantTheme, filenameDOT, filenamePNG, progressReporter)
+				}})
+		}
+		if generateDataAssetsDiagram {
+			filenameDOT, filenamePNG := ownerFilename(config.DataAssetDiagramFilenameDOT, variant), ownerFilename(config.DataAssetDiagramFilenamePNG, variant)
+			stages = append(stages, generationStage{name: "data asset diagram " + variant,
+				files: diagramFiles(filenameDOT, filenamePNG),
+				run: func() error {
+					return writeDataAssetDiagram(ctx, config, readResult.ParsedModel, dataAssetDiagramDPI, variantTheme, filenameDOT, filenamePNG, progressReporter)
+				}})
 		}
 	}
 
 	// risks as risks json
 	if commands.RisksJSON {
-		progressReporter.Info("Writing risks json")
-		err := WriteRisksJSON(readResult.ParsedModel, filepath.Join(config.OutputFolder, config.JsonRisksFilename))
-		if err != nil {
-			return fmt.Errorf("error while writing risks json: %s", err)
-		}
+		stages = append(stages, generationStage{name: "risks json", files: []string{output(config.JsonRisksFilename)}, run: func() error {
+			progressReporter.Info("Writing risks json")
+			err := WriteRisksJSON(config.FS(), result, output(config.JsonRisksFilename))
+			if err != nil {
+				return fmt.Errorf("error while writing risks json: %s", err)
+			}
+			return nil
+		}})
+	}
+
+	// risks as SARIF log
+	if commands.RisksSARIF {
+		stages = append(stages, generationStage{name: "risks sarif", files: []string{output(config.SarifRisksFilename)}, run: func() error {
+			progressReporter.Info("Writing risks sarif")
+			err := WriteRisksSARIF(config.FS(), readResult.ParsedModel, filepath.Base(config.InputFile), output(config.SarifRisksFilename))
+			if err != nil {
+				return fmt.Errorf("error while writing risks sarif: %s", err)
+			}
+			return nil
+		}})
+	}
+
+	// due dates as iCalendar
+	if commands.DueDatesICS {
+		stages = append(stages, generationStage{name: "due dates ics", files: []string{output(config.IcsDueDatesFilename)}, run: func() error {
+			progressReporter.Info("Writing due dates ics")
+			err := WriteDueDatesICS(config.FS(), readResult.ParsedModel, "", false, output(config.IcsDueDatesFilename))
+			if err != nil {
+				return fmt.Errorf("error while writing due dates ics: %s", err)
+			}
+			return nil
+		}})
 	}
 
 	// technical assets json
 	if commands.TechnicalAssetsJSON {
-		progressReporter.Info("Writing technical assets json")
-		err := WriteTechnicalAssetsJSON(readResult.ParsedModel, filepath.Join(config.OutputFolder, config.JsonTechnicalAssetsFilename))
-		if err != nil {
-			return fmt.Errorf("error while writing technical assets json: %s", err)
-		}
+		stages = append(stages, generationStage{name: "technical assets json", files: []string{output(config.JsonTechnicalAssetsFilename)}, run: func() error {
+			progressReporter.Info("Writing technical assets json")
+			err := WriteTechnicalAssetsJSON(config.FS(), result, output(config.JsonTechnicalAssetsFilename))
+			if err != nil {
+				return fmt.Errorf("error while writing technical assets json: %s", err)
+			}
+			return nil
+		}})
 	}
 
 	// risks as risks json
 	if commands.StatsJSON {
-		progressReporter.Info("Writing stats json")
-		err := WriteStatsJSON(readResult.ParsedModel, filepath.Join(config.OutputFolder, config.JsonStatsFilename))
-		if err != nil {
-			return fmt.Errorf("error while writing stats json: %s", err)
-		}
+		stages = append(stages, generationStage{name: "stats json", files: []string{output(config.JsonStatsFilename)}, run: func() error {
+			progressReporter.Info("Writing stats json")
+			err := WriteStatsJSON(config.FS(), result, output(config.JsonStatsFilename))
+			if err != nil {
+				return fmt.Errorf("error while writing stats json: %s", err)
+			}
+			return nil
+		}})
+	}
+
+	// exposure json, correlating the modeled risks with the imported scanner findings
+	if len(readResult.ParsedModel.ScannerFindings) > 0 {
+		stages = append(stages, generationStage{name: "exposure json", files: []string{output(config.JsonExposureFilename)}, run: func() error {
+			progressReporter.Info("Writing exposure json")
+			err := WriteExposureJSON(config.FS(), result, output(config.JsonExposureFilename))
+			if err != nil {
+				return fmt.Errorf("error while writing exposure json: %s", err)
+			}
+			return nil
+		}})
+	}
+
+	// failed risk rules json, so that missing risks of a faulty rule do not go unnoticed
+	if len(result.RiskRuleFailures) > 0 {
+		stages = append(stages, generationStage{name: "risk rule failures json", files: []string{output(config.JsonRuleFailuresFilename)}, run: func() error {
+			progressReporter.Info("Writing risk rule failures json")
+			err := WriteRiskRuleFailuresJSON(config.FS(), result, output(config.JsonRuleFailuresFilename))
+			if err != nil {
+				return fmt.Errorf("error while writing risk rule failures json: %s", err)
+			}
+			return nil
+		}})
 	}
 
 	// risks Excel
 	if commands.RisksExcel {
-		progressReporter.Info("Writing risks excel")
-		err := WriteRisksExcelToFile(readResult.ParsedModel, filepath.Join(config.OutputFolder, config.ExcelRisksFilename), config)
-		if err != nil {
-			return err
-		}
+		stages = append(stages, generationStage{name: "risks excel", files: []string{output(config.ExcelRisksFilename)}, run: func() error {
+			progressReporter.Info("Writing risks excel")
+			return WriteRisksExcelToFile(config.FS(), result, output(config.ExcelRisksFilename), config)
+		}})
 	}
 
 	// tags Excel
 	if commands.TagsExcel {
-		progressReporter.Info("Writing tags excel")
-		err := WriteTagsExcelToFile(readResult.ParsedModel, filepath.Join(config.OutputFolder, config.ExcelTagsFilename))
-		if err != nil {
-			return err
+		stages = append(stages, generationStage{name: "tags excel", files: []string{output(config.ExcelTagsFilename)}, run: func() error {
+			progressReporter.Info("Writing tags excel")
+			return WriteTagsExcelToFile(config.FS(), result, output(config.ExcelTagsFilename))
+		}})
+	}
+
+	// combined Excel workbook
+	if commands.ExcelWorkbook {
+		stages = append(stages, generationStage{name: "excel workbook", files: []string{output(config.ExcelWorkbookFilename)}, run: func() error {
+			progressReporter.Info("Writing excel workbook")
+			return WriteWorkbookExcelToFile(config.FS(), result, output(config.ExcelWorkbookFilename), config)
+		}})
+	}
+
+	// per-asset one-pagers
+	if commands.AssetSheets {
+		files := make([]string, 0)
+		for _, technicalAsset := range sortedTechnicalAssetsByTitle(readResult.ParsedModel) {
+			files = append(files, output(ownerFilename(config.AssetSheetFilename, technicalAsset.Id)), output(ownerFilename(config.AssetSheetHTMLFilename, technicalAsset.Id)))
+		}
+		stages = append(stages, generationStage{name: "asset sheets", files: files, run: func() error {
+			progressReporter.Info("Writing asset sheets")
+			for _, technicalAsset := range sortedTechnicalAssetsByTitle(readResult.ParsedModel) {
+				if canceledError := common.CheckCanceled(ctx, "rendering asset sheets"); canceledError != nil {
+					return canceledError
+				}
+				err := WriteAssetSheetPDF(config.FS(), readResult.ParsedModel, technicalAsset, output(ownerFilename(config.AssetSheetFilename, technicalAsset.Id)), config.FontFile)
+				if err != nil {
+					return err
+				}
+				err = WriteAssetSheetHTML(config.FS(), readResult.ParsedModel, technicalAsset, output(ownerFilename(config.AssetSheetHTMLFilename, technicalAsset.Id)))
+				if err != nil {
+					return err
+				}
+			}
+			return nil
+		}})
+	}
+
+	// mitigation checklist
+	if commands.MitigationChecklist {
+		stages = append(stages, generationStage{name: "mitigation checklist",
+			files: []string{output(config.MitigationChecklistMarkdownFilename), output(config.MitigationChecklistCSVFilename)},
+			run: func() error {
+				progressReporter.Info("Writing mitigation checklist")
+				err := WriteMitigationChecklistMarkdown(config.FS(), readResult.ParsedModel, output(config.MitigationChecklistMarkdownFilename))
+				if err != nil {
+					return err
+				}
+				return WriteMitigationChecklistCSV(config.FS(), readResult.ParsedModel, output(config.MitigationChecklistCSVFilename))
+			}})
+	}
+
+	// per-owner risk extracts
+	if commands.RisksPerOwner {
+		files := make([]string, 0)
+		for _, owner := range readResult.ParsedModel.RiskOwners() {
+			files = append(files, output(ownerFilename(config.JsonRisksFilename, owner)), output(ownerFilename(config.ExcelRisksFilename, owner)))
 		}
+		stages = append(stages, generationStage{name: "risks per owner", files: files, run: func() error {
+			progressReporter.Info("Writing risks per owner")
+			for _, owner := range readResult.ParsedModel.RiskOwners() {
+				ownerResult := model.NewResult(readResult.ParsedModel.RisksOfOwner(owner), nil, result.Timing)
+				err := WriteRisksJSON(config.FS(), ownerResult, output(ownerFilename(config.JsonRisksFilename, owner)))
+				if err != nil {
+					return fmt.Errorf("error while writing risks json of owner %q: %s", owner, err)
+				}
+				err = WriteRisksExcelToFile(config.FS(), ownerResult, output(ownerFilename(config.ExcelRisksFilename, owner)), config)
+				if err != nil {
+					return fmt.Errorf("error while writing risks excel of owner %q: %s", owner, err)
+				}
+			}
+			return nil
+		}})
+	}
+
+	// risk matrix chart
+	if commands.RiskMatrix {
+		stages = append(stages, generationStage{name: "risk matrix",
+			files: []string{output(config.RiskMatrixFilenamePNG), output(config.RiskMatrixFilenameSVG)},
+			run: func() error {
+				progressReporter.Info("Writing risk matrix")
+				err := WriteRiskMatrixPNG(config.FS(), result.Risks, "Risk Matrix", output(config.RiskMatrixFilenamePNG))
+				if err != nil {
+					return err
+				}
+				return WriteRiskMatrixSVG(config.FS(), result.Risks, "Risk Matrix", output(config.RiskMatrixFilenameSVG))
+			}})
+	}
+
+	// rules documentation
+	if commands.RulesDoc {
+		stages = append(stages, generationStage{name: "rules documentation",
+			files: []string{output(config.RulesDocMarkdownFilename), output(config.RulesDocHTMLFilename)},
+			run: func() error {
+				progressReporter.Info("Writing rules documentation")
+				activeRules := activeRiskRules(readResult, config.SkipRiskRules, config.OnlyRiskRules)
+				err := WriteRulesDocMarkdown(config.FS(), activeRules, output(config.RulesDocMarkdownFilename))
+				if err != nil {
+					return err
+				}
+				return WriteRulesDocHTML(config.FS(), activeRules, output(config.RulesDocHTMLFilename))
+			}})
 	}
 
 	if commands.ReportPDF {
-		// hash the YAML input file
-		f, err := os.Open(config.InputFile)
+		stages = append(stages, generationStage{name: "report pdf", files: []string{output(config.ReportFilename)}, run: func() error {
+			return writeReportPDF(ctx, config, readResult, result, progressReporter)
+		}})
+	}
+
+	// outputs of the writers registered by programs embedding Threagile
+	for _, writer := range registeredWriters {
+		writer := writer
+		files := make([]string, 0)
+		for _, filename := range writer.Files(config) {
+			files = append(files, output(filename))
+		}
+		stages = append(stages, generationStage{name: writer.Name(), files: files, run: func() error {
+			progressReporter.Info("Writing " + writer.Name())
+			err := writer.Write(ctx, config, result)
+			if err != nil {
+				return fmt.Errorf("error while writing %v: %w", writer.Name(), err)
+			}
+			return nil
+		}})
+	}
+
+	err = runGenerationStages(ctx, config, readResult, commands, stages, progressReporter)
+	if err != nil {
+		return nil, err
+	}
+
+	files := make([]string, 0)
+	for _, stage := range stages {
+		files = append(files, stage.files...)
+	}
+	return files, nil
+}
+
+// generationStage writes some of the outputs, a stage completed by a previous failed run with the same model and
+// settings is skipped as long as its files are still there unchanged
+type generationStage struct {
+	name  string
+	files []string
+	run   func() error
+}
+
+func runGenerationStages(ctx context.Context, config *common.Config, readResult *model.ReadResult, commands *GenerateCommands, stages []generationStage, progressReporter progressReporter) error {
+	fingerprint, err := generationFingerprint(config, generationInputFiles(config), commands, readResult.ParsedModel, readResult.IntroTextRAA)
+	if err != nil {
+		progressReporter.Warn(err) // generating without checkpoint then
+	}
+	progress := loadCheckpoint(config.FS(), filepath.Join(config.OutputFolder, config.CheckpointFilename), fingerprint)
+
+	resuming := len(fingerprint) > 0
+	for _, stage := range stages {
+		if canceledError := common.CheckCanceled(ctx, "rendering "+stage.name); canceledError != nil {
+			return canceledError
+		}
+		// once a stage is generated again the following ones are as well, as they might depend on its outputs (like the
+		// report on the diagrams)
+		if resuming && progress.completed(stage.name, stage.files) {
+			progressReporter.Info("Skipping " + stage.name + " (completed by the previous run)")
+			continue
+		}
+		resuming = false
+
+		err := stage.run()
 		if err != nil {
 			return err
 		}
-		defer func() { _ = f.Close() }()
-		hasher := sha256.New()
-		if _, err := io.Copy(hasher, f); err != nil {
-			return err
+		if len(fingerprint) > 0 {
+			err = progress.complete(stage.name, stage.files)
+			if err != nil {
+				progressReporter.Warn(err)
+			}
 		}
-		modelHash := hex.EncodeToString(hasher.Sum(nil))
-		// report PDF
-		progressReporter.Info("Writing report pdf")
-
-		pdfReporter := pdfReporter{}
-		err = pdfReporter.WriteReportPDF(filepath.Join(config.OutputFolder, config.ReportFilename),
-			filepath.Join(config.AppFolder, config.TemplateFilename),
-			filepath.Join(config.OutputFolder, config.DataFlowDiagramFilenamePNG),
-			filepath.Join(config.OutputFolder, config.DataAssetDiagramFilenamePNG),
-			config.InputFile,
-			config.SkipRiskRules,
-			config.BuildTimestamp,
-			modelHash,
-			readResult.IntroTextRAA,
-			readResult.CustomRiskRules,
-			config.TempFolder,
-			readResult.ParsedModel)
+	}
+	return progress.remove()
+}
+
+func writeReportPDF(ctx context.Context, config *common.Config, readResult *model.ReadResult, result *model.Result, progressReporter progressReporter) error {
+	if canceledError := common.CheckCanceled(ctx, "rendering report pdf"); canceledError != nil {
+		return canceledError
+	}
+
+	// hash the YAML input file
+	modelData, err := config.FS().ReadFile(config.InputFile)
+	if err != nil {
+		return err
+	}
+	modelHash := sha256.Sum256(modelData)
+	var previousRisks []*types.Risk
+	if len(config.PreviousRisksFile) > 0 {
+		previousRisks, err = ReadRisksJSON(config.FS(), config.PreviousRisksFile)
 		if err != nil {
-			return err
+			return fmt.Errorf("error while reading previous risks: %s", err)
 		}
 	}
 
-	return nil
+	// report PDF
+	progressReporter.Info("Writing report pdf")
+
+	pdfReporter := pdfReporter{}
+	return pdfReporter.WriteReportPDF(config.FS(), filepath.Join(config.OutputFolder, config.ReportFilename),
+		filepath.Join(config.AppFolder, config.TemplateFilename),
+		filepath.Join(config.OutputFolder, config.DataFlowDiagramFilenamePNG),
+		filepath.Join(config.OutputFolder, config.DataAssetDiagramFilenamePNG),
+		config.InputFile,
+		config.SkipRiskRules,
+		config.BuildTimestamp,
+		hex.EncodeToString(modelHash[:]),
+		readResult.IntroTextRAA,
+		readResult.CustomRiskRules,
+		readResult.ReportSections,
+		previousRisks,
+		config.TempFolder,
+		config.FontFile,
+		result)
+}
+
+func writeDataFlowDiagram(ctx context.Context, config *common.Config, parsedModel *types.Model, dpi int, theme *DiagramTheme, filenameDOT string, filenamePNG string, progressReporter progressReporter) error {
+	gvFile, removeGvFile, err := diagramSourceFile(config, filenameDOT)
+	if err != nil {
+		return err
+	}
+	defer removeGvFile()
+	err = WriteDataFlowDiagramGraphvizDOT(config.FS(), parsedModel, gvFile, dpi, config.AddModelTitle, theme, progressReporter)
+	if err != nil {
+		return fmt.Errorf("error while generating data flow diagram: %s", err)
+	}
+
+	err = GenerateDataFlowDiagramGraphvizImage(ctx, config.FS(), gvFile, config.OutputFolder,
+		config.TempFolder, filenamePNG, config.FontFile, progressReporter, config.KeepDiagramSourceFiles)
+	if err != nil {
+		progressReporter.Warn(err)
+	}
+	return common.CheckCanceled(ctx, "rendering data flow diagram")
+}
+
+func writeDataAssetDiagram(ctx context.Context, config *common.Config, parsedModel *types.Model, dpi int, theme *DiagramTheme, filenameDOT string, filenamePNG string, progressReporter progressReporter) error {
+	gvFile, removeGvFile, err := diagramSourceFile(config, filenameDOT)
+	if err != nil {
+		return err
+	}
+	defer removeGvFile()
+	err = WriteDataAssetDiagramGraphvizDOT(config.FS(), parsedModel, gvFile, dpi, theme, progressReporter)
+	if err != nil {
+		return fmt.Errorf("error while generating data asset diagram: %s", err)
+	}
+	err = GenerateDataAssetDiagramGraphvizImage(ctx, config.FS(), gvFile, config.OutputFolder,
+		config.TempFolder, filenamePNG, config.FontFile, progressReporter)
+	if err != nil {
+		progressReporter.Warn(err)
+	}
+	return common.CheckCanceled(ctx, "rendering data asset diagram")
+}
+
+// diagramSourceFile answers where the DOT file of a diagram goes to: the output folder when the diagram source files are
+// kept, else a temp folder removed afterwards
+func diagramSourceFile(config *common.Config, filenameDOT string) (string, func(), error) {
+	if config.KeepDiagramSourceFiles {
+		return filepath.Join(config.OutputFolder, filenameDOT), func() {}, nil
+	}
+	tempFolder, err := config.FS().MkdirTemp(config.TempFolder, "diagram-")
+	if err != nil {
+		return "", nil, err
+	}
+	return filepath.Join(tempFolder, filenameDOT), func() { _ = config.FS().RemoveAll(tempFolder) }, nil
+}
+
+// ownerFilename turns "risks.xlsx" into "risks-<owner>.xlsx" (also used to suffix the diagram variants)
+func ownerFilename(filename string, owner string) string {
+	extension := filepath.Ext(filename)
+	return strings.TrimSuffix(filename, extension) + "-" + types.MakeID(owner) + extension
+}
+
+func activeRiskRules(readResult *model.ReadResult, skipRiskRules []string, onlyRiskRules []string) types.RiskRules {
+	rules := make(types.RiskRules)
+	rules.Merge(readResult.BuiltinRiskRules)
+	rules.Merge(readResult.CustomRiskRules)
+	for id := range rules {
+		if types.IsSkippedRiskRule(skipRiskRules, id) || (len(onlyRiskRules) > 0 && !types.IsSkippedRiskRule(onlyRiskRules, id)) {
+			delete(rules, id)
+		}
+	}
+	return rules
 }
 
 type progressReporter interface {
@@ -0,0 +1,150 @@
+package report
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/threagile/threagile/pkg/common"
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+// diagramChange marks an element of the diff diagram by how the newer model version changed it
+type diagramChange int
+
+const (
+	diagramUnchanged diagramChange = iota
+	diagramAdded
+	diagramRemoved
+)
+
+func (what diagramChange) String() string {
+	return [...]string{"unchanged", "added", "removed"}[what]
+}
+
+// dataFlowDiagramDiff holds the technical assets and communication links of two model versions, marked by their change
+type dataFlowDiagramDiff struct {
+	technicalAssets    map[string]diagramChange
+	communicationLinks map[string]diagramChange
+	titles             map[string]string
+	links              map[string]*types.CommunicationLink
+}
+
+func newDataFlowDiagramDiff(oldModel *types.Model, newModel *types.Model) *dataFlowDiagramDiff {
+	diff := &dataFlowDiagramDiff{
+		technicalAssets:    make(map[string]diagramChange),
+		communicationLinks: make(map[string]diagramChange),
+		titles:             make(map[string]string),
+		links:              make(map[string]*types.CommunicationLink),
+	}
+
+	for id, asset := range oldModel.TechnicalAssets {
+		diff.technicalAssets[id] = diagramRemoved
+		diff.titles[id] = asset.Title
+	}
+	for id, asset := range newModel.TechnicalAssets {
+		if _, ok := diff.technicalAssets[id]; ok {
+			diff.technicalAssets[id] = diagramUnchanged
+		} else {
+			diff.technicalAssets[id] = diagramAdded
+		}
+		diff.titles[id] = asset.Title
+	}
+
+	for id, link := range oldModel.CommunicationLinks {
+		diff.communicationLinks[id] = diagramRemoved
+		diff.links[id] = link
+	}
+	for id, link := range newModel.CommunicationLinks {
+		if _, ok := diff.communicationLinks[id]; ok {
+			diff.communicationLinks[id] = diagramUnchanged
+		} else {
+			diff.communicationLinks[id] = diagramAdded
+		}
+		diff.links[id] = link
+	}
+
+	return diff
+}
+
+func WriteDataFlowDiagramDiffGraphvizDOT(fileSystem common.FileSystem, oldModel *types.Model, newModel *types.Model, diagramFilenameDOT string, dpi int,
+	progressReporter progressReporter) error {
+	progressReporter.Info("Writing data flow diagram diff input")
+	if err := checkNodeHashes(append(diagramNodeIds(oldModel), diagramNodeIds(newModel)...), hash); err != nil {
+		return err
+	}
+
+	diff := newDataFlowDiagramDiff(oldModel, newModel)
+	rankdir := "TB"
+	if newModel.DiagramTweakLayoutLeftToRight {
+		rankdir = "LR"
+	}
+
+	var dotContent strings.Builder
+	dotContent.WriteString("digraph generatedModelDiff { concentrate=false \n")
+	dotContent.WriteString(`	graph [
+		fontname="Verdana"
+		dpi=` + strconv.Itoa(dpi) + `
+		splines=spline
+		rankdir="` + rankdir + `"
+	];
+	node [
+		fontname="Verdana"
+		fontsize="20"
+	];
+	edge [
+		shape="none"
+		fontname="Verdana"
+		fontsize="18"
+	];
+`)
+
+	for _, id := range sortedKeysOfDiagramChanges(diff.technicalAssets) {
+		color, style := diagramChangeStyle(diff.technicalAssets[id])
+		dotContent.WriteString("  " + hash(id) + ` [ shape="box" style="rounded,` + style + `" color="` + color + `" fontcolor="` + color + `" penwidth="3.0"
+		label=<<b>` + encode(diff.titles[id]) + `</b>> ];
+`)
+	}
+
+	for _, id := range sortedKeysOfDiagramChanges(diff.communicationLinks) {
+		link := diff.links[id]
+		color, style := diagramChangeStyle(diff.communicationLinks[id])
+		dotContent.WriteString("  " + hash(link.SourceId) + " -> " + hash(link.TargetId) +
+			` [ color="` + color + `" style="` + style + `" penwidth="2.5" arrowsize="2.0" xlabel="` + encode(link.Protocol.String()) + `" fontcolor="` + color + "\" ];\n")
+	}
+
+	dotContent.WriteString("}")
+
+	err := fileSystem.WriteFile(diagramFilenameDOT, []byte(dotContent.String()+"\n"), 0600)
+	if err != nil {
+		return fmt.Errorf("error writing %s: %v", diagramFilenameDOT, err)
+	}
+	return nil
+}
+
+// GenerateGraphvizImage renders a DOT file into the given format (e.g. png or svg) via the graphviz dot binary
+func GenerateGraphvizImage(ctx context.Context, fileSystem common.FileSystem, dotFilename string, format string, targetFilename string, tempFolder string, fontFile string) error {
+	return renderGraphvizFile(ctx, fileSystem, dotFilename, format, targetFilename, tempFolder, fontFile, false)
+}
+
+func diagramChangeStyle(change diagramChange) (color string, style string) {
+	switch change {
+	case diagramAdded:
+		return Green, "solid"
+	case diagramRemoved:
+		return Red, "dashed"
+	default:
+		return MiddleLightGray, "solid"
+	}
+}
+
+func sortedKeysOfDiagramChanges(changes map[string]diagramChange) []string {
+	keys := make([]string, 0, len(changes))
+	for key := range changes {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
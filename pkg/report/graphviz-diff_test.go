@@ -0,0 +1,40 @@
+package report
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/threagile/threagile/pkg/common"
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+func diffTestModel(assetIds ...string) *types.Model {
+	parsedModel := &types.Model{TechnicalAssets: make(map[string]*types.TechnicalAsset), CommunicationLinks: make(map[string]*types.CommunicationLink)}
+	for _, id := range assetIds {
+		parsedModel.TechnicalAssets[id] = &types.TechnicalAsset{Id: id, Title: strings.ToUpper(id)}
+	}
+	for i := 1; i < len(assetIds); i++ {
+		link := &types.CommunicationLink{Id: assetIds[i-1] + ">" + assetIds[i], SourceId: assetIds[i-1], TargetId: assetIds[i], Protocol: types.HTTPS}
+		parsedModel.CommunicationLinks[link.Id] = link
+	}
+	return parsedModel
+}
+
+func TestDataFlowDiagramDiff(t *testing.T) {
+	diff := newDataFlowDiagramDiff(diffTestModel("client", "shop", "database"), diffTestModel("client", "shop", "cache"))
+	assert.Equal(t, map[string]diagramChange{"client": diagramUnchanged, "shop": diagramUnchanged, "database": diagramRemoved, "cache": diagramAdded}, diff.technicalAssets)
+	assert.Equal(t, map[string]diagramChange{"client>shop": diagramUnchanged, "shop>database": diagramRemoved, "shop>cache": diagramAdded}, diff.communicationLinks)
+}
+
+func TestWriteDataFlowDiagramDiffGraphvizDOT(t *testing.T) {
+	fileSystem := common.NewMemoryFileSystem()
+	assert.NoError(t, WriteDataFlowDiagramDiffGraphvizDOT(fileSystem, diffTestModel("client", "shop"), diffTestModel("client", "cache"), "diff.gv", 120, silentProgressReporter{}))
+	dot, err := fileSystem.ReadFile("diff.gv")
+	assert.NoError(t, err)
+
+	assert.Contains(t, string(dot), hash("cache")+` [ shape="box" style="rounded,solid" color="`+Green+`"`)
+	assert.Contains(t, string(dot), hash("shop")+` [ shape="box" style="rounded,dashed" color="`+Red+`"`)
+	assert.Contains(t, string(dot), hash("client")+` [ shape="box" style="rounded,solid" color="`+MiddleLightGray+`"`)
+	assert.Contains(t, string(dot), hash("client")+" -> "+hash("shop")+` [ color="`+Red+`" style="dashed"`)
+}
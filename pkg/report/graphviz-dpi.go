@@ -0,0 +1,78 @@
+package report
+
+import (
+	"github.com/threagile/threagile/pkg/common"
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+// the estimated size of the diagrams, as graphviz lays out the nodes and edges with the fonts and separations of the
+// generated DOT (only known after the layout, which is too expensive to run once per DPI)
+const (
+	diagramSquareInchesPerNode = 9.0 // a node of about 3 x 1.5 inches plus the space around it
+	diagramSquareInchesPerEdge = 1.5 // the label and routing space of an edge
+	diagramDPIDecrement        = 0.9 // of each round of the auction
+)
+
+// DataFlowDiagramDPI is the DPI the data flow diagram of the model is rendered with (see autoScaledDPI)
+func DataFlowDiagramDPI(config *common.Config, parsedModel *types.Model) int {
+	return autoScaledDPI(config, dataFlowDiagramSize(parsedModel))
+}
+
+// DataAssetDiagramDPI is the DPI the data asset diagram of the model is rendered with (see autoScaledDPI)
+func DataAssetDiagramDPI(config *common.Config, parsedModel *types.Model) int {
+	return autoScaledDPI(config, dataAssetDiagramSize(parsedModel))
+}
+
+// diagramSize is the number of nodes and edges of a diagram
+type diagramSize struct {
+	nodes int
+	edges int
+}
+
+func dataFlowDiagramSize(parsedModel *types.Model) diagramSize {
+	size := diagramSize{nodes: len(parsedModel.TechnicalAssets) + len(parsedModel.TrustBoundaries)}
+	for _, technicalAsset := range parsedModel.TechnicalAssets {
+		size.edges += len(technicalAsset.CommunicationLinks)
+	}
+	return size
+}
+
+func dataAssetDiagramSize(parsedModel *types.Model) diagramSize {
+	size := diagramSize{nodes: len(parsedModel.DataAssets)}
+	for _, technicalAsset := range parsedModel.TechnicalAssets {
+		if len(technicalAsset.DataAssetsStored) > 0 || len(technicalAsset.DataAssetsProcessed) > 0 {
+			size.nodes++
+			size.edges += len(technicalAsset.DataAssetsStored) + len(technicalAsset.DataAssetsProcessed)
+		}
+	}
+	return size
+}
+
+// pixels estimates the number of pixels of the image of the diagram rendered with the DPI
+func (what diagramSize) pixels(dpi int) float64 {
+	squareInches := diagramSquareInchesPerNode*float64(what.nodes) + diagramSquareInchesPerEdge*float64(what.edges)
+	return squareInches * float64(dpi) * float64(dpi)
+}
+
+// autoScaledDPI is the DPI of the diagram: the configured one, or when the images are limited to maxPixels the highest
+// DPI (starting at the maximum and lowered step by step, like a dutch auction) whose image is estimated to stay below
+// them, so that small models are rendered sharp and large ones do not turn into gigantic images
+func autoScaledDPI(config *common.Config, size diagramSize) int {
+	dpi, maxDPI := config.DiagramDPI, common.MaxGraphvizDPI
+	if config.DiagramMaxPixels > 0 {
+		if config.MaxGraphvizDPI > 0 && config.MaxGraphvizDPI < maxDPI {
+			maxDPI = config.MaxGraphvizDPI
+		}
+		dpi = maxDPI
+		for dpi > common.MinGraphvizDPI && size.pixels(dpi) > float64(config.DiagramMaxPixels) {
+			dpi = int(float64(dpi) * diagramDPIDecrement)
+		}
+	}
+	if dpi < common.MinGraphvizDPI {
+		return common.MinGraphvizDPI
+	}
+	if dpi > maxDPI {
+		return maxDPI
+	}
+	return dpi
+}
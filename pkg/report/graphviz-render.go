@@ -0,0 +1,71 @@
+package report
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+
+	"github.com/threagile/threagile/pkg/common"
+)
+
+// renderGraphvizFile renders the DOT file of the file system into the target file of it, via copies of both in the
+// temp folder of the OS, as graphviz only knows the file system of the OS
+func renderGraphvizFile(ctx context.Context, fileSystem common.FileSystem, dotFilename string, format string, targetFilename string, tempFolder string, fontFile string, keepTempFiles bool) error {
+	tmpFileDOT, err := os.CreateTemp(tempFolder, "diagram-*-.gv")
+	if err != nil {
+		return fmt.Errorf("error creating temp file: %v", err)
+	}
+	_ = tmpFileDOT.Close()
+	if !keepTempFiles {
+		defer func() { _ = os.Remove(tmpFileDOT.Name()) }()
+	}
+	tmpFileTarget, err := os.CreateTemp(tempFolder, "diagram-*-."+format)
+	if err != nil {
+		return fmt.Errorf("error creating temp file: %v", err)
+	}
+	_ = tmpFileTarget.Close()
+	if !keepTempFiles {
+		defer func() { _ = os.Remove(tmpFileTarget.Name()) }()
+	}
+
+	inputDOT, err := fileSystem.ReadFile(dotFilename)
+	if err != nil {
+		return fmt.Errorf("error reading %s: %v", dotFilename, err)
+	}
+	err = os.WriteFile(tmpFileDOT.Name(), inputDOT, 0600)
+	if err != nil {
+		return fmt.Errorf("error creating %s: %v", tmpFileDOT.Name(), err)
+	}
+	err = renderWithDot(ctx, tmpFileDOT.Name(), format, tmpFileTarget.Name(), tempFolder, fontFile)
+	if err != nil {
+		return err
+	}
+	image, err := os.ReadFile(tmpFileTarget.Name())
+	if err != nil {
+		return fmt.Errorf("failed to copy to file %s: %v", tmpFileTarget.Name(), err)
+	}
+	err = fileSystem.WriteFile(targetFilename, image, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %v", targetFilename, err)
+	}
+	return nil
+}
+
+// renderWithDot calls the graphviz dot binary, which has to be installed
+func renderWithDot(ctx context.Context, dotFilename string, format string, targetFilename string, tempFolder string, fontFile string) error {
+	env, removeFonts, err := graphvizFontEnvironment(tempFolder, fontFile)
+	if err != nil {
+		return err
+	}
+	defer removeFonts()
+	cmd := exec.CommandContext(ctx, "dot", "-T"+format, dotFilename, "-o", targetFilename) // #nosec G204
+	cmd.Env = env
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	err = cmd.Run()
+	if err != nil {
+		return fmt.Errorf("graph rendering call failed with error: %v", err)
+	}
+	return nil
+}
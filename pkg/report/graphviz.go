@@ -1,23 +1,26 @@
 package report
 
 import (
+	"context"
 	"fmt"
 	"hash/fnv"
-	"os"
-	"os/exec"
 	"path/filepath"
 	"regexp"
 	"sort"
 	"strconv"
 	"strings"
 
+	"github.com/threagile/threagile/pkg/common"
 	"github.com/threagile/threagile/pkg/security/types"
 )
 
-func WriteDataFlowDiagramGraphvizDOT(parsedModel *types.Model,
-	diagramFilenameDOT string, dpi int, addModelTitle bool,
-	progressReporter progressReporter) (*os.File, error) {
+func WriteDataFlowDiagramGraphvizDOT(fileSystem common.FileSystem, parsedModel *types.Model,
+	diagramFilenameDOT string, dpi int, addModelTitle bool, theme *DiagramTheme,
+	progressReporter progressReporter) error {
 	progressReporter.Info("Writing data flow diagram input")
+	if err := checkNodeHashes(diagramNodeIds(parsedModel), hash); err != nil {
+		return err
+	}
 
 	var dotContent strings.Builder
 	dotContent.WriteString("digraph generatedModel { concentrate=false \n")
@@ -51,7 +54,7 @@ func WriteDataFlowDiagramGraphvizDOT(parsedModel *types.Model,
 			splines = "false"
 			drawSpaceLinesForLayoutUnfortunatelyFurtherSeparatesAllRanks = false
 		default:
-			return nil, fmt.Errorf("unknown value for diagram_tweak_suppress_edge_labels (spline, polyline, ortho, curved, false): %s", parsedModel.DiagramTweakEdgeLayout)
+			return fmt.Errorf("unknown value for diagram_tweak_suppress_edge_labels (spline, polyline, ortho, curved, false): %s", parsedModel.DiagramTweakEdgeLayout)
 		}
 	}
 	rankdir := "TB"
@@ -60,11 +63,11 @@ func WriteDataFlowDiagramGraphvizDOT(parsedModel *types.Model,
 	}
 	modelTitle := ""
 	if addModelTitle {
-		modelTitle = `label="` + parsedModel.Title + `"`
+		modelTitle = `label="` + encode(parsedModel.Title) + `"`
 	}
-	dotContent.WriteString(`	graph [ ` + modelTitle + `
+	dotContent.WriteString(`	graph [ ` + modelTitle + backgroundColor(theme) + `
 		labelloc=t
-		fontname="Verdana"
+		fontname="` + theme.Font + `"
 		fontsize=40
         outputorder="nodesfirst"
 		dpi=` + strconv.Itoa(dpi) + `
@@ -73,12 +76,12 @@ func WriteDataFlowDiagramGraphvizDOT(parsedModel *types.Model,
 ` + tweaks + `
 	];
 	node [
-		fontname="Verdana"
+		fontname="` + theme.Font + `"
 		fontsize="20"
 	];
 	edge [
 		shape="none"
-		fontname="Verdana"
+		fontname="` + theme.Font + `"
 		fontsize="18"
 	];
 `)
@@ -112,24 +115,24 @@ func WriteDataFlowDiagramGraphvizDOT(parsedModel *types.Model,
 											];`)
 			}
 			snippet.WriteString("\n subgraph cluster_" + hash(trustBoundary.Id) + " {\n")
-			color, fontColor, bgColor, style, fontname := rgbHexColorTwilight(), rgbHexColorTwilight() /*"#550E0C"*/, "#FAFAFA", "dashed", "Verdana"
+			color, fontColor, bgColor, style, fontname := theme.TrustBoundary.Border, theme.TrustBoundary.Label /*"#550E0C"*/, theme.TrustBoundary.Background, "dashed", theme.Font
 			penWidth := 4.5
 			if len(trustBoundary.TrustBoundariesNested) > 0 {
 				//color, fontColor, style, fontname = Blue, Blue, "dashed", "Verdana"
 				penWidth = 5.5
 			}
 			if len(trustBoundary.ParentTrustBoundaryID(parsedModel)) > 0 {
-				bgColor = "#F1F1F1"
+				bgColor = theme.TrustBoundary.BackgroundNested
 			}
 			if trustBoundary.Type == types.NetworkPolicyNamespaceIsolation {
-				fontColor, bgColor = "#222222", "#DFF4FF"
+				fontColor, bgColor = theme.TrustBoundary.LabelNamespace, theme.TrustBoundary.BackgroundNamespace
 			}
 			if trustBoundary.Type == types.ExecutionEnvironment {
-				fontColor, bgColor, style = "#555555", "#FFFFF0", "dotted"
+				fontColor, bgColor, style = theme.TrustBoundary.LabelExecutionEnvironment, theme.TrustBoundary.BackgroundExecutionEnvironment, "dotted"
 			}
 			snippet.WriteString(`	graph [
       dpi=` + strconv.Itoa(dpi) + `
-      label=<<table border="0" cellborder="0" cellpadding="0"><tr><td><b>` + trustBoundary.Title + `</b> (` + trustBoundary.Type.String() + `)</td></tr></table>>
+      label=<<table border="0" cellborder="0" cellpadding="0"><tr><td><b>` + encode(trustBoundary.Title) + `</b> (` + trustBoundary.Type.String() + `)</td></tr></table>>
       fontsize="21"
       style="` + style + `"
       color="` + color + `"
@@ -200,7 +203,7 @@ func WriteDataFlowDiagramGraphvizDOT(parsedModel *types.Model,
 	}
 	sort.Sort(types.ByOrderAndIdSort(techAssets))
 	for _, technicalAsset := range techAssets {
-		dotContent.WriteString(makeTechAssetNode(parsedModel, technicalAsset, false))
+		dotContent.WriteString(makeTechAssetNode(parsedModel, technicalAsset, false, theme))
 		dotContent.WriteString("\n")
 	}
 
@@ -224,8 +227,8 @@ func WriteDataFlowDiagramGraphvizDOT(parsedModel *types.Model,
 					dir = "both"
 				}
 			}
-			arrowStyle = ` style="` + determineArrowLineStyle(dataFlow) + `" penwidth="` + determineArrowPenWidth(dataFlow, parsedModel) + `" arrowtail="` + readOrWriteTail + `" arrowhead="` + readOrWriteHead + `" dir="` + dir + `" arrowsize="2.0" `
-			arrowColor = ` color="` + determineArrowColor(dataFlow, parsedModel) + `"`
+			arrowStyle = ` style="` + determineArrowLineStyle(dataFlow) + `" penwidth="` + determineArrowPenWidth(dataFlow, parsedModel, theme) + `" arrowtail="` + readOrWriteTail + `" arrowhead="` + readOrWriteHead + `" dir="` + dir + `" arrowsize="2.0" `
+			arrowColor = ` color="` + determineArrowColor(dataFlow, parsedModel, theme) + `"`
 			tweaks := ""
 			if dataFlow.DiagramTweakWeight > 0 {
 				tweaks += " weight=\"" + strconv.Itoa(dataFlow.DiagramTweakWeight) + "\" "
@@ -235,7 +238,7 @@ func WriteDataFlowDiagramGraphvizDOT(parsedModel *types.Model,
 			dotContent.WriteString("  " + hash(sourceId) + " -> " + hash(targetId) +
 				` [` + arrowColor + ` ` + arrowStyle + tweaks + ` constraint=` + strconv.FormatBool(dataFlow.DiagramTweakConstraint) + ` `)
 			if !parsedModel.DiagramTweakSuppressEdgeLabels {
-				dotContent.WriteString(` xlabel="` + encode(dataFlow.Protocol.String()) + `" fontcolor="` + determineLabelColor(dataFlow, parsedModel) + `" `)
+				dotContent.WriteString(` xlabel="` + encode(dataFlow.Protocol.String()) + `" fontcolor="` + determineLabelColor(dataFlow, parsedModel, theme) + `" `)
 			}
 			dotContent.WriteString(" ];\n")
 		}
@@ -243,13 +246,13 @@ func WriteDataFlowDiagramGraphvizDOT(parsedModel *types.Model,
 
 	diagramInvisibleConnectionsTweaks, err := makeDiagramInvisibleConnectionsTweaks(parsedModel)
 	if err != nil {
-		return nil, fmt.Errorf("error while making diagram invisible connections tweaks: %s", err)
+		return fmt.Errorf("error while making diagram invisible connections tweaks: %s", err)
 	}
 	dotContent.WriteString(diagramInvisibleConnectionsTweaks)
 
 	diagramSameRankNodeTweaks, err := makeDiagramSameRankNodeTweaks(parsedModel)
 	if err != nil {
-		return nil, fmt.Errorf("error while making diagram same-rank node tweaks: %s", err)
+		return fmt.Errorf("error while making diagram same-rank node tweaks: %s", err)
 	}
 	dotContent.WriteString(diagramSameRankNodeTweaks)
 
@@ -258,60 +261,55 @@ func WriteDataFlowDiagramGraphvizDOT(parsedModel *types.Model,
 	//fmt.Println(dotContent.String())
 
 	// Write the DOT file
-	file, err := os.Create(filepath.Clean(diagramFilenameDOT))
-	if err != nil {
-		return nil, fmt.Errorf("error creating %s: %v", diagramFilenameDOT, err)
-	}
-	defer func() { _ = file.Close() }()
-	_, err = fmt.Fprintln(file, dotContent.String())
+	err = fileSystem.WriteFile(diagramFilenameDOT, []byte(dotContent.String()+"\n"), 0600)
 	if err != nil {
-		return nil, fmt.Errorf("error writing %s: %v", diagramFilenameDOT, err)
+		return fmt.Errorf("error writing %s: %v", diagramFilenameDOT, err)
 	}
-	return file, nil
+	return nil
 }
 
 // Pen Widths:
 
-func determineArrowPenWidth(cl *types.CommunicationLink, parsedModel *types.Model) string {
-	if determineArrowColor(cl, parsedModel) == Pink {
+func determineArrowPenWidth(cl *types.CommunicationLink, parsedModel *types.Model, theme *DiagramTheme) string {
+	if determineArrowColor(cl, parsedModel, theme) == theme.Link.ArrowModelForgery {
 		return fmt.Sprintf("%f", 3.0)
 	}
-	if determineArrowColor(cl, parsedModel) != Black {
+	if determineArrowColor(cl, parsedModel, theme) != theme.Link.Arrow {
 		return fmt.Sprintf("%f", 2.5)
 	}
 	return fmt.Sprintf("%f", 1.5)
 }
 
-func determineLabelColor(cl *types.CommunicationLink, parsedModel *types.Model) string {
+func determineLabelColor(cl *types.CommunicationLink, parsedModel *types.Model, theme *DiagramTheme) string {
 	// TODO: Just move into main.go and let the generated risk determine the color, don't duplicate the logic here
 	/*
 		if dataFlow.Protocol.IsEncrypted() {
-			return Gray
+			return theme.Link.Label
 		} else {*/
 	// check for red
 	for _, sentDataAsset := range cl.DataAssetsSent {
 		if parsedModel.DataAssets[sentDataAsset].Integrity == types.MissionCritical {
-			return Red
+			return theme.Link.LabelMissionCritical
 		}
 	}
 	for _, receivedDataAsset := range cl.DataAssetsReceived {
 		if parsedModel.DataAssets[receivedDataAsset].Integrity == types.MissionCritical {
-			return Red
+			return theme.Link.LabelMissionCritical
 		}
 	}
 	// check for amber
 	for _, sentDataAsset := range cl.DataAssetsSent {
 		if parsedModel.DataAssets[sentDataAsset].Integrity == types.Critical {
-			return Amber
+			return theme.Link.LabelCritical
 		}
 	}
 	for _, receivedDataAsset := range cl.DataAssetsReceived {
 		if parsedModel.DataAssets[receivedDataAsset].Integrity == types.Critical {
-			return Amber
+			return theme.Link.LabelCritical
 		}
 	}
 	// default
-	return Gray
+	return theme.Link.Label
 }
 
 func determineArrowLineStyle(cl *types.CommunicationLink) string {
@@ -325,43 +323,43 @@ func determineArrowLineStyle(cl *types.CommunicationLink) string {
 }
 
 // pink when model forgery attempt (i.e. nothing being sent and received)
-func determineArrowColor(cl *types.CommunicationLink, parsedModel *types.Model) string {
+func determineArrowColor(cl *types.CommunicationLink, parsedModel *types.Model, theme *DiagramTheme) string {
 	// TODO: Just move into main.go and let the generated risk determine the color, don't duplicate the logic here
 	if len(cl.DataAssetsSent) == 0 && len(cl.DataAssetsReceived) == 0 ||
 		cl.Protocol == types.UnknownProtocol {
-		return Pink // pink, because it's strange when too many technical communication links transfer no data... some ok, but many in a diagram ist a sign of model forgery...
+		return theme.Link.ArrowModelForgery // pink, because it's strange when too many technical communication links transfer no data... some ok, but many in a diagram ist a sign of model forgery...
 	}
 	if cl.Usage == types.DevOps {
-		return MiddleLightGray
+		return theme.Link.ArrowDevOps
 	} else if cl.VPN {
-		return DarkBlue
+		return theme.Link.ArrowVPN
 	} else if cl.IpFiltered {
-		return Brown
+		return theme.Link.ArrowIpFiltered
 	}
 	// check for red
 	for _, sentDataAsset := range cl.DataAssetsSent {
 		if parsedModel.DataAssets[sentDataAsset].Confidentiality == types.StrictlyConfidential {
-			return Red
+			return theme.Link.ArrowStrictlyConfidential
 		}
 	}
 	for _, receivedDataAsset := range cl.DataAssetsReceived {
 		if parsedModel.DataAssets[receivedDataAsset].Confidentiality == types.StrictlyConfidential {
-			return Red
+			return theme.Link.ArrowStrictlyConfidential
 		}
 	}
 	// check for amber
 	for _, sentDataAsset := range cl.DataAssetsSent {
 		if parsedModel.DataAssets[sentDataAsset].Confidentiality == types.Confidential {
-			return Amber
+			return theme.Link.ArrowConfidential
 		}
 	}
 	for _, receivedDataAsset := range cl.DataAssetsReceived {
 		if parsedModel.DataAssets[receivedDataAsset].Confidentiality == types.Confidential {
-			return Amber
+			return theme.Link.ArrowConfidential
 		}
 	}
 	// default
-	return Black
+	return theme.Link.Arrow
 	/*
 		} else if dataFlow.Authentication != NoneAuthentication {
 			return Black
@@ -393,55 +391,10 @@ func determineArrowColor(cl *types.CommunicationLink, parsedModel *types.Model)
 	*/
 }
 
-func GenerateDataFlowDiagramGraphvizImage(dotFile *os.File, targetDir string,
-	tempFolder, dataFlowDiagramFilenamePNG string, progressReporter progressReporter, keepGraphVizDataFile bool) error {
+func GenerateDataFlowDiagramGraphvizImage(ctx context.Context, fileSystem common.FileSystem, dotFilename string, targetDir string,
+	tempFolder, dataFlowDiagramFilenamePNG string, fontFile string, progressReporter progressReporter, keepGraphVizDataFile bool) error {
 	progressReporter.Info("Rendering data flow diagram input")
-	// tmp files
-	tmpFileDOT, err := os.CreateTemp(tempFolder, "diagram-*-.gv")
-	if err != nil {
-		return fmt.Errorf("error creating temp file: %v", err)
-	}
-	if !keepGraphVizDataFile {
-		defer func() { _ = os.Remove(tmpFileDOT.Name()) }()
-	}
-
-	tmpFilePNG, err := os.CreateTemp(tempFolder, "diagram-*-.png")
-	if err != nil {
-		return fmt.Errorf("error creating temp file: %v", err)
-	}
-	if !keepGraphVizDataFile {
-		defer func() { _ = os.Remove(tmpFilePNG.Name()) }()
-	}
-
-	// copy into tmp file as input
-	inputDOT, err := os.ReadFile(dotFile.Name())
-	if err != nil {
-		return fmt.Errorf("error reading %s: %v", dotFile.Name(), err)
-	}
-	err = os.WriteFile(tmpFileDOT.Name(), inputDOT, 0600)
-	if err != nil {
-		return fmt.Errorf("error creating %s: %v", tmpFileDOT.Name(), err)
-	}
-
-	// exec
-
-	cmd := exec.Command("dot", "-Tpng", tmpFileDOT.Name(), "-o", tmpFilePNG.Name()) // #nosec G204
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
-	err = cmd.Run()
-	if err != nil {
-		return fmt.Errorf("graph rendering call failed with error: %v", err)
-	}
-	// copy into resulting file
-	inputPNG, err := os.ReadFile(tmpFilePNG.Name())
-	if err != nil {
-		return fmt.Errorf("failed to copy to file %s: %v", tmpFilePNG.Name(), err)
-	}
-	err = os.WriteFile(filepath.Join(targetDir, dataFlowDiagramFilenamePNG), inputPNG, 0600)
-	if err != nil {
-		return fmt.Errorf("failed to create %s: %v", filepath.Join(targetDir, dataFlowDiagramFilenamePNG), err)
-	}
-	return nil
+	return renderGraphvizFile(ctx, fileSystem, dotFilename, "png", filepath.Join(targetDir, dataFlowDiagramFilenamePNG), tempFolder, fontFile, keepGraphVizDataFile)
 }
 
 func makeDiagramSameRankNodeTweaks(parsedModel *types.Model) (string, error) {
@@ -493,17 +446,20 @@ func makeDiagramInvisibleConnectionsTweaks(parsedModel *types.Model) (string, er
 	return tweak, nil
 }
 
-func WriteDataAssetDiagramGraphvizDOT(parsedModel *types.Model, diagramFilenameDOT string, dpi int,
-	progressReporter progressReporter) (*os.File, error) {
+func WriteDataAssetDiagramGraphvizDOT(fileSystem common.FileSystem, parsedModel *types.Model, diagramFilenameDOT string, dpi int, theme *DiagramTheme,
+	progressReporter progressReporter) error {
 	progressReporter.Info("Writing data asset diagram input")
+	if err := checkNodeHashes(diagramNodeIds(parsedModel), hash); err != nil {
+		return err
+	}
 
 	var dotContent strings.Builder
 	dotContent.WriteString("digraph generatedModel { concentrate=true \n")
 
 	// Metadata init ===============================================================================
-	dotContent.WriteString(`	graph [
+	dotContent.WriteString(`	graph [` + backgroundColor(theme) + `
 		dpi=` + strconv.Itoa(dpi) + `
-		fontname="Verdana"
+		fontname="` + theme.Font + `"
 		labelloc="c"
 		fontsize="20"
 		splines=false
@@ -513,13 +469,13 @@ func WriteDataAssetDiagramGraphvizDOT(parsedModel *types.Model, diagramFilenameD
         outputorder="nodesfirst"
 	];
 	node [
-		fontcolor="white"
-		fontname="Verdana"
+		fontcolor="` + theme.DataAsset.Label + `"
+		fontname="` + theme.Font + `"
 		fontsize="20"
 	];
 	edge [
 		shape="none"
-		fontname="Verdana"
+		fontname="` + theme.Font + `"
 		fontsize="18"
 	];
 `)
@@ -532,7 +488,7 @@ func WriteDataAssetDiagramGraphvizDOT(parsedModel *types.Model, diagramFilenameD
 	sort.Sort(types.ByOrderAndIdSort(techAssets))
 	for _, technicalAsset := range techAssets {
 		if len(technicalAsset.DataAssetsStored) > 0 || len(technicalAsset.DataAssetsProcessed) > 0 {
-			dotContent.WriteString(makeTechAssetNode(parsedModel, technicalAsset, true))
+			dotContent.WriteString(makeTechAssetNode(parsedModel, technicalAsset, true, theme))
 			dotContent.WriteString("\n")
 		}
 	}
@@ -545,7 +501,7 @@ func WriteDataAssetDiagramGraphvizDOT(parsedModel *types.Model, diagramFilenameD
 
 	types.SortByDataAssetDataBreachProbabilityAndTitle(parsedModel, dataAssets)
 	for _, dataAsset := range dataAssets {
-		dotContent.WriteString(makeDataAssetNode(parsedModel, dataAsset))
+		dotContent.WriteString(makeDataAssetNode(parsedModel, dataAsset, theme))
 		dotContent.WriteString("\n")
 	}
 
@@ -555,7 +511,7 @@ func WriteDataAssetDiagramGraphvizDOT(parsedModel *types.Model, diagramFilenameD
 			targetId := technicalAsset.Id
 			dotContent.WriteString("\n")
 			dotContent.WriteString(hash(sourceId) + " -> " + hash(targetId) +
-				` [ color="blue" style="solid" ];`)
+				` [ color="` + theme.Link.DataAssetStored + `" style="solid" ];`)
 			dotContent.WriteString("\n")
 		}
 		for _, sourceId := range technicalAsset.DataAssetsProcessed {
@@ -563,7 +519,7 @@ func WriteDataAssetDiagramGraphvizDOT(parsedModel *types.Model, diagramFilenameD
 				targetId := technicalAsset.Id
 				dotContent.WriteString("\n")
 				dotContent.WriteString(hash(sourceId) + " -> " + hash(targetId) +
-					` [ color="#666666" style="dashed" ];`)
+					` [ color="` + theme.Link.DataAssetProcessed + `" style="dashed" ];`)
 				dotContent.WriteString("\n")
 			}
 		}
@@ -572,91 +528,48 @@ func WriteDataAssetDiagramGraphvizDOT(parsedModel *types.Model, diagramFilenameD
 	dotContent.WriteString("}")
 
 	// Write the DOT file
-	file, err := os.Create(filepath.Clean(diagramFilenameDOT))
+	err := fileSystem.WriteFile(diagramFilenameDOT, []byte(dotContent.String()+"\n"), 0600)
 	if err != nil {
-		return nil, fmt.Errorf("error creating %s: %v", diagramFilenameDOT, err)
+		return fmt.Errorf("error writing %s: %v", diagramFilenameDOT, err)
 	}
-	defer func() { _ = file.Close() }()
-	_, err = fmt.Fprintln(file, dotContent.String())
-	if err != nil {
-		return nil, fmt.Errorf("error writing %s: %v", diagramFilenameDOT, err)
-	}
-	return file, nil
+	return nil
 }
 
-func makeDataAssetNode(parsedModel *types.Model, dataAsset *types.DataAsset) string {
-	var color string
-	switch dataAsset.IdentifiedDataBreachProbabilityStillAtRisk(parsedModel) {
-	case types.Probable:
-		color = rgbHexColorHighRisk()
-	case types.Possible:
-		color = rgbHexColorMediumRisk()
-	case types.Improbable:
-		color = rgbHexColorLowRisk()
-	default:
-		color = "#444444" // since black is too dark here as fill color
-	}
+func makeDataAssetNode(parsedModel *types.Model, dataAsset *types.DataAsset, theme *DiagramTheme) string {
+	color := theme.dataBreachProbabilityColor(dataAsset.IdentifiedDataBreachProbabilityStillAtRisk(parsedModel))
 	if !dataAsset.IsDataBreachPotentialStillAtRisk(parsedModel) {
-		color = "#444444" // since black is too dark here as fill color
+		color = theme.Severity.None
 	}
 	return "  " + hash(dataAsset.Id) + ` [ label=<<b>` + encode(dataAsset.Title) + `</b>> penwidth="3.0" style="filled" fillcolor="` + color + `" color="` + color + "\"\n  ]; "
 }
 
-func makeTechAssetNode(parsedModel *types.Model, technicalAsset *types.TechnicalAsset, simplified bool) string {
+func makeTechAssetNode(parsedModel *types.Model, technicalAsset *types.TechnicalAsset, simplified bool, theme *DiagramTheme) string {
 	if simplified {
-		color := rgbHexColorOutOfScope()
+		color := theme.Severity.OutOfScope
 		if !technicalAsset.OutOfScope {
 			generatedRisks := technicalAsset.GeneratedRisks(parsedModel)
-			switch types.HighestSeverityStillAtRisk(parsedModel, generatedRisks) {
-			case types.CriticalSeverity:
-				color = rgbHexColorCriticalRisk()
-			case types.HighSeverity:
-				color = rgbHexColorHighRisk()
-			case types.ElevatedSeverity:
-				color = rgbHexColorElevatedRisk()
-			case types.MediumSeverity:
-				color = rgbHexColorMediumRisk()
-			case types.LowSeverity:
-				color = rgbHexColorLowRisk()
-			default:
-				color = "#444444" // since black is too dark here as fill color
-			}
+			color = theme.severityColor(types.HighestSeverityStillAtRisk(parsedModel, generatedRisks))
 			if len(types.ReduceToOnlyStillAtRisk(parsedModel, generatedRisks)) == 0 {
-				color = "#444444" // since black is too dark here as fill color
+				color = theme.Severity.None
 			}
 		}
 		return "  " + hash(technicalAsset.Id) + ` [ shape="box" style="filled" fillcolor="` + color + `"
 				label=<<b>` + encode(technicalAsset.Title) + `</b>> penwidth="3.0" color="` + color + `" ];
 				`
 	} else {
-		var shape, title string
+		shape, title := theme.shape(technicalAsset), technicalAsset.Title
 		var lineBreak = ""
-		switch technicalAsset.Type {
-		case types.ExternalEntity:
-			shape = "box"
-			title = technicalAsset.Title
-		case types.Process:
-			shape = "ellipse"
-			title = technicalAsset.Title
-		case types.Datastore:
-			shape = "cylinder"
-			title = technicalAsset.Title
-			if technicalAsset.Redundant {
-				lineBreak = "<br/>"
-			}
-		}
-
-		if technicalAsset.UsedAsClientByHuman {
-			shape = "octagon"
+		if technicalAsset.Type == types.Datastore && technicalAsset.Redundant {
+			lineBreak = "<br/>"
 		}
 
 		// RAA = Relative Attacker Attractiveness
 		raa := technicalAsset.RAA
 		var attackerAttractivenessLabel string
 		if technicalAsset.OutOfScope {
-			attackerAttractivenessLabel = "<font point-size=\"15\" color=\"#603112\">RAA: out of scope</font>"
+			attackerAttractivenessLabel = "<font point-size=\"15\" color=\"" + theme.Asset.RAA + "\">RAA: out of scope</font>"
 		} else {
-			attackerAttractivenessLabel = "<font point-size=\"15\" color=\"#603112\">RAA: " + fmt.Sprintf("%.0f", raa) + " %</font>"
+			attackerAttractivenessLabel = "<font point-size=\"15\" color=\"" + theme.Asset.RAA + "\">RAA: " + fmt.Sprintf("%.0f", raa) + " %</font>"
 		}
 
 		compartmentBorder := "0"
@@ -665,10 +578,10 @@ func makeTechAssetNode(parsedModel *types.Model, technicalAsset *types.Technical
 		}
 
 		return "  " + hash(technicalAsset.Id) + ` [
-	label=<<table border="0" cellborder="` + compartmentBorder + `" cellpadding="2" cellspacing="0"><tr><td><font point-size="15" color="` + DarkBlue + `">` + lineBreak + technicalAsset.Technologies.String() + `</font><br/><font point-size="15" color="` + LightGray + `">` + technicalAsset.Size.String() + `</font></td></tr><tr><td><b><font color="` + determineTechnicalAssetLabelColor(technicalAsset, parsedModel) + `">` + encode(title) + `</font></b><br/></td></tr><tr><td>` + attackerAttractivenessLabel + `</td></tr></table>>
-	shape=` + shape + ` style="` + determineShapeBorderLineStyle(technicalAsset) + `,` + determineShapeStyle(technicalAsset) + `" penwidth="` + determineShapeBorderPenWidth(technicalAsset, parsedModel) + `" fillcolor="` + determineShapeFillColor(technicalAsset, parsedModel) + `"
+	label=<<table border="0" cellborder="` + compartmentBorder + `" cellpadding="2" cellspacing="0"><tr><td><font point-size="15" color="` + theme.Asset.Technology + `">` + lineBreak + encode(technicalAsset.Technologies.String()) + `</font><br/><font point-size="15" color="` + theme.Asset.Size + `">` + technicalAsset.Size.String() + `</font></td></tr><tr><td><b><font color="` + determineTechnicalAssetLabelColor(technicalAsset, parsedModel, theme) + `">` + encode(title) + `</font></b><br/></td></tr><tr><td>` + attackerAttractivenessLabel + `</td></tr></table>>
+	shape="` + shape + `" style="` + determineShapeBorderLineStyle(technicalAsset) + `,` + determineShapeStyle(technicalAsset) + `" penwidth="` + determineShapeBorderPenWidth(technicalAsset, parsedModel, theme) + `" fillcolor="` + determineShapeFillColor(technicalAsset, parsedModel, theme) + `"
 	peripheries=` + strconv.Itoa(determineShapePeripheries(technicalAsset)) + `
-	color="` + determineShapeBorderColor(technicalAsset, parsedModel) + "\"\n  ]; "
+	color="` + determineShapeBorderColor(technicalAsset, parsedModel, theme) + "\"\n  ]; "
 	}
 }
 
@@ -676,18 +589,18 @@ func determineShapeStyle(ta *types.TechnicalAsset) string {
 	return "filled"
 }
 
-func determineShapeFillColor(ta *types.TechnicalAsset, parsedModel *types.Model) string {
-	fillColor := VeryLightGray
+func determineShapeFillColor(ta *types.TechnicalAsset, parsedModel *types.Model, theme *DiagramTheme) string {
+	fillColor := theme.Asset.Fill
 	if (len(ta.DataAssetsProcessed) == 0 && len(ta.DataAssetsStored) == 0) || ta.Technologies.IsUnknown() {
-		fillColor = LightPink // lightPink, because it's strange when too many technical assets process no data... some ok, but many in a diagram ist a sign of model forgery...
+		fillColor = theme.Asset.FillModelForgery // lightPink, because it's strange when too many technical assets process no data... some ok, but many in a diagram ist a sign of model forgery...
 	} else if len(ta.CommunicationLinks) == 0 && len(parsedModel.IncomingTechnicalCommunicationLinksMappedByTargetId[ta.Id]) == 0 {
-		fillColor = LightPink
+		fillColor = theme.Asset.FillModelForgery
 	} else if ta.Internet {
-		fillColor = ExtremeLightBlue
+		fillColor = theme.Asset.FillInternet
 	} else if ta.OutOfScope {
-		fillColor = OutOfScopeFancy
+		fillColor = theme.Asset.FillOutOfScope
 	} else if ta.CustomDevelopedParts {
-		fillColor = CustomDevelopedParts
+		fillColor = theme.Asset.FillCustomDeveloped
 	}
 	switch ta.Machine {
 	case types.Physical:
@@ -701,11 +614,11 @@ func determineShapeFillColor(ta *types.TechnicalAsset, parsedModel *types.Model)
 	return fillColor
 }
 
-func determineShapeBorderPenWidth(ta *types.TechnicalAsset, parsedModel *types.Model) string {
-	if determineShapeBorderColor(ta, parsedModel) == Pink {
+func determineShapeBorderPenWidth(ta *types.TechnicalAsset, parsedModel *types.Model, theme *DiagramTheme) string {
+	if determineShapeBorderColor(ta, parsedModel, theme) == Pink {
 		return fmt.Sprintf("%f", 3.5)
 	}
-	if determineShapeBorderColor(ta, parsedModel) != Black {
+	if determineShapeBorderColor(ta, parsedModel, theme) != theme.Asset.Border {
 		return fmt.Sprintf("%f", 3.0)
 	}
 	return fmt.Sprintf("%f", 2.0)
@@ -714,26 +627,26 @@ func determineShapeBorderPenWidth(ta *types.TechnicalAsset, parsedModel *types.M
 // red when mission-critical integrity, but still unauthenticated (non-readonly) channels access it
 // amber when critical integrity, but still unauthenticated (non-readonly) channels access it
 // pink when model forgery attempt (i.e. nothing being processed)
-func determineShapeBorderColor(ta *types.TechnicalAsset, parsedModel *types.Model) string {
+func determineShapeBorderColor(ta *types.TechnicalAsset, parsedModel *types.Model, theme *DiagramTheme) string {
 	// Check for red
 	if ta.Confidentiality == types.StrictlyConfidential {
-		return Red
+		return theme.Asset.BorderStrictlyConfidential
 	}
 	for _, processedDataAsset := range ta.DataAssetsProcessed {
 		if parsedModel.DataAssets[processedDataAsset].Confidentiality == types.StrictlyConfidential {
-			return Red
+			return theme.Asset.BorderStrictlyConfidential
 		}
 	}
 	// Check for amber
 	if ta.Confidentiality == types.Confidential {
-		return Amber
+		return theme.Asset.BorderConfidential
 	}
 	for _, processedDataAsset := range ta.DataAssetsProcessed {
 		if parsedModel.DataAssets[processedDataAsset].Confidentiality == types.Confidential {
-			return Amber
+			return theme.Asset.BorderConfidential
 		}
 	}
-	return Black
+	return theme.Asset.Border
 	/*
 		if what.Integrity == MissionCritical {
 			for _, dataFlow := range IncomingTechnicalCommunicationLinksMappedByTargetId[what.ID] {
@@ -775,37 +688,37 @@ func determineShapeBorderLineStyle(ta *types.TechnicalAsset) string {
 }
 
 // red when >= confidential data stored in unencrypted technical asset
-func determineTechnicalAssetLabelColor(ta *types.TechnicalAsset, model *types.Model) string {
+func determineTechnicalAssetLabelColor(ta *types.TechnicalAsset, model *types.Model, theme *DiagramTheme) string {
 	// TODO: Just move into main.go and let the generated risk determine the color, don't duplicate the logic here
 	// Check for red
 	if ta.Integrity == types.MissionCritical {
-		return Red
+		return theme.Asset.LabelMissionCritical
 	}
 	for _, storedDataAsset := range ta.DataAssetsStored {
 		if model.DataAssets[storedDataAsset].Integrity == types.MissionCritical {
-			return Red
+			return theme.Asset.LabelMissionCritical
 		}
 	}
 	for _, processedDataAsset := range ta.DataAssetsProcessed {
 		if model.DataAssets[processedDataAsset].Integrity == types.MissionCritical {
-			return Red
+			return theme.Asset.LabelMissionCritical
 		}
 	}
 	// Check for amber
 	if ta.Integrity == types.Critical {
-		return Amber
+		return theme.Asset.LabelCritical
 	}
 	for _, storedDataAsset := range ta.DataAssetsStored {
 		if model.DataAssets[storedDataAsset].Integrity == types.Critical {
-			return Amber
+			return theme.Asset.LabelCritical
 		}
 	}
 	for _, processedDataAsset := range ta.DataAssetsProcessed {
 		if model.DataAssets[processedDataAsset].Integrity == types.Critical {
-			return Amber
+			return theme.Asset.LabelCritical
 		}
 	}
-	return Black
+	return theme.Asset.Label
 	/*
 		if what.Encrypted {
 			return Black
@@ -831,58 +744,77 @@ func determineTechnicalAssetLabelColor(ta *types.TechnicalAsset, model *types.Mo
 	*/
 }
 
-func GenerateDataAssetDiagramGraphvizImage(dotFile *os.File, targetDir string,
-	tempFolder, dataAssetDiagramFilenamePNG string, progressReporter progressReporter) error { // TODO dedupe with other render...() method here
+func GenerateDataAssetDiagramGraphvizImage(ctx context.Context, fileSystem common.FileSystem, dotFilename string, targetDir string,
+	tempFolder, dataAssetDiagramFilenamePNG string, fontFile string, progressReporter progressReporter) error {
 	progressReporter.Info("Rendering data asset diagram input")
-	// tmp files
-	tmpFileDOT, err := os.CreateTemp(tempFolder, "diagram-*-.gv")
-	if err != nil {
-		return fmt.Errorf("error creating temp file: %v", err)
-	}
-	defer func() { _ = os.Remove(tmpFileDOT.Name()) }()
+	return renderGraphvizFile(ctx, fileSystem, dotFilename, "png", filepath.Join(targetDir, dataAssetDiagramFilenamePNG), tempFolder, fontFile, false)
+}
 
-	tmpFilePNG, err := os.CreateTemp(tempFolder, "diagram-*-.png")
-	if err != nil {
-		return fmt.Errorf("error creating temp file: %v", err)
+func backgroundColor(theme *DiagramTheme) string {
+	if len(theme.Background) == 0 {
+		return ""
 	}
-	defer func() { _ = os.Remove(tmpFilePNG.Name()) }()
+	return `
+		bgcolor="` + theme.Background + `"`
+}
 
-	// copy into tmp file as input
-	inputDOT, err := os.ReadFile(dotFile.Name())
-	if err != nil {
-		return fmt.Errorf("error reading %s: %v", dotFile.Name(), err)
-	}
-	err = os.WriteFile(tmpFileDOT.Name(), inputDOT, 0600)
-	if err != nil {
-		return fmt.Errorf("error creating %s: %v", tmpFileDOT.Name(), err)
+// hash is the node name of an element in the DOT files, 64 bits as colliding ids would silently merge their nodes
+// (see checkNodeHashes)
+func hash(s string) string {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(s))
+	return fmt.Sprintf("%v", h.Sum64())
+}
+
+// checkNodeHashes fails when different ids of the diagram elements have the same hash, which is very unlikely, but
+// would merge their nodes without any notice
+func checkNodeHashes(ids []string, hashOf func(string) string) error {
+	idsByHash := make(map[string]string)
+	for _, id := range ids {
+		nodeHash := hashOf(id)
+		if other, exists := idsByHash[nodeHash]; exists && other != id {
+			return fmt.Errorf("diagram node hash collision of the ids %q and %q (rename one of them)", other, id)
+		}
+		idsByHash[nodeHash] = id
 	}
+	return nil
+}
 
-	// exec
-	cmd := exec.Command("dot", "-Tpng", tmpFileDOT.Name(), "-o", tmpFilePNG.Name()) // #nosec G204
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
-	err = cmd.Run()
-	if err != nil {
-		return fmt.Errorf("graph rendering call failed with error: %v", err)
+// diagramNodeIds are the ids of the elements the diagrams have nodes (or clusters) of
+func diagramNodeIds(parsedModel *types.Model) []string {
+	ids := make([]string, 0, len(parsedModel.TechnicalAssets)+len(parsedModel.DataAssets)+len(parsedModel.TrustBoundaries))
+	for id := range parsedModel.TechnicalAssets {
+		ids = append(ids, id)
 	}
-	// copy into resulting file
-	inputPNG, err := os.ReadFile(tmpFilePNG.Name())
-	if err != nil {
-		return fmt.Errorf("failed to copy to file %s: %v", tmpFilePNG.Name(), err)
+	for id := range parsedModel.DataAssets {
+		ids = append(ids, id)
 	}
-	err = os.WriteFile(filepath.Join(targetDir, dataAssetDiagramFilenamePNG), inputPNG, 0600)
-	if err != nil {
-		return fmt.Errorf("failed to create %s: %v", filepath.Join(targetDir, dataAssetDiagramFilenamePNG), err)
+	for id := range parsedModel.TrustBoundaries {
+		ids = append(ids, id)
 	}
-	return nil
+	sort.Strings(ids) // for the same error in each run
+	return ids
 }
 
-func hash(s string) string {
-	h := fnv.New32a()
-	_, _ = h.Write([]byte(s))
-	return fmt.Sprintf("%v", h.Sum32())
-}
+// labelEscaper covers the HTML-like labels as well as the quoted strings of DOT, as graphviz resolves the entities in
+// both (avoiding the backslash escape sequences of quoted strings too)
+var labelEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;", `"`, "&quot;", "'", "&#39;", `\`, "&#92;")
 
+// encode escapes arbitrary text for diagram labels, dropping the characters not allowed in the (XML based) HTML-like
+// labels and replacing invalid UTF-8
 func encode(value string) string {
-	return strings.ReplaceAll(value, "&", "&amp;")
+	value = strings.Map(func(r rune) rune {
+		if !isLabelCharacter(r) {
+			return -1
+		}
+		return r
+	}, strings.ToValidUTF8(value, "\uFFFD"))
+	return labelEscaper.Replace(value)
+}
+
+func isLabelCharacter(r rune) bool {
+	if r < ' ' {
+		return r == '\t' || r == '\n' || r == '\r'
+	}
+	return r != 0x7f && r != 0xfffe && r != 0xffff
 }
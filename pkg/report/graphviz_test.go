@@ -0,0 +1,98 @@
+package report
+
+import (
+	"encoding/xml"
+	"html"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+	"testing/quick"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/threagile/threagile/pkg/common"
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+var entityPattern = regexp.MustCompile(`&(amp|lt|gt|quot|#39|#92);`)
+
+func TestEncodeLeavesNoMarkup(t *testing.T) {
+	property := func(value string) bool {
+		encoded := encode(value)
+		return !strings.ContainsAny(entityPattern.ReplaceAllString(encoded, ""), `&<>"'\`)
+	}
+	assert.NoError(t, quick.Check(property, &quick.Config{MaxCount: 10000}))
+}
+
+func TestEncodeKeepsText(t *testing.T) {
+	property := func(value string) bool {
+		if !utf8.ValidString(value) || strings.IndexFunc(value, func(r rune) bool { return !isLabelCharacter(r) }) >= 0 {
+			return true // see TestEncodeDropsControlCharacters
+		}
+		return html.UnescapeString(encode(value)) == value
+	}
+	assert.NoError(t, quick.Check(property, &quick.Config{MaxCount: 10000}))
+}
+
+func TestEncodeIsValidHTMLLabel(t *testing.T) {
+	// graphviz parses the HTML-like labels as XML
+	property := func(value string) bool {
+		var element struct {
+			Text string `xml:",chardata"`
+		}
+		err := xml.Unmarshal([]byte("<b>"+encode(value)+"</b>"), &element)
+		return err == nil
+	}
+	assert.NoError(t, quick.Check(property, &quick.Config{MaxCount: 10000}))
+}
+
+func TestEncodeDropsControlCharacters(t *testing.T) {
+	assert.Equal(t, "a\tb\nc", encode("a\x00\tb\x1b\nc\x7f\uffff"))
+	assert.Equal(t, "a�b", encode("a\xffb"))
+}
+
+func TestDataFlowDiagramEscapesLabels(t *testing.T) {
+	theme, err := LoadDiagramTheme("")
+	assert.NoError(t, err)
+
+	title := `<script>"Tom & Jerry's" \N</script>`
+	asset := &types.TechnicalAsset{Id: "asset", Title: title, Type: types.Process, Technologies: types.TechnologyList{{Name: "web-server"}}}
+	parsedModel := &types.Model{
+		Title:           title,
+		TechnicalAssets: map[string]*types.TechnicalAsset{asset.Id: asset},
+		TrustBoundaries: map[string]*types.TrustBoundary{"boundary": {Id: "boundary", Title: title, TechnicalAssetsInside: []string{asset.Id}}},
+	}
+
+	filename := filepath.Join(t.TempDir(), "data-flow-diagram.gv")
+	assert.NoError(t, WriteDataFlowDiagramGraphvizDOT(common.OSFileSystem{}, parsedModel, filename, 120, true, theme, silentProgressReporter{}))
+
+	dot, err := os.ReadFile(filename)
+	assert.NoError(t, err)
+	assert.NotContains(t, string(dot), title)
+	assert.Equal(t, 3, strings.Count(string(dot), encode(title)))
+}
+
+func TestCheckNodeHashes(t *testing.T) {
+	ids := []string{"web-server", "web-app", "database", "web-server"}
+	assert.NoError(t, checkNodeHashes(ids, hash), "same id twice is no collision")
+	assert.NoError(t, checkNodeHashes(ids[:2], func(id string) string { return id }), "distinct ids of distinct hashes")
+
+	firstLetter := func(id string) string { return id[:1] }
+	assert.EqualError(t, checkNodeHashes(ids, firstLetter), `diagram node hash collision of the ids "web-server" and "web-app" (rename one of them)`)
+}
+
+func TestAutoScaledDPI(t *testing.T) {
+	config := &common.Config{DiagramDPI: 500}
+	assert.Equal(t, common.MaxGraphvizDPI, autoScaledDPI(config, diagramSize{nodes: 3}), "fixed DPI within bounds")
+
+	config = &common.Config{DiagramDPI: 100, DiagramMaxPixels: 20000000, MaxGraphvizDPI: 250}
+	small := autoScaledDPI(config, diagramSize{nodes: 3, edges: 2})
+	assert.Equal(t, 250, small, "small models with the maximum DPI")
+	large := autoScaledDPI(config, diagramSize{nodes: 200, edges: 400})
+	assert.Less(t, large, small)
+	assert.LessOrEqual(t, diagramSize{nodes: 200, edges: 400}.pixels(large), float64(config.DiagramMaxPixels))
+	assert.Greater(t, diagramSize{nodes: 200, edges: 400}.pixels(int(float64(large)/diagramDPIDecrement)+1), float64(config.DiagramMaxPixels), "highest fitting DPI")
+	assert.Equal(t, common.MinGraphvizDPI, autoScaledDPI(config, diagramSize{nodes: 100000}), "never below the minimum")
+}
@@ -0,0 +1,127 @@
+package report
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/threagile/threagile/pkg/common"
+	"github.com/threagile/threagile/pkg/docs"
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+const (
+	icsDateFormat      = "20060102"
+	icsTimestampFormat = "20060102T150405Z"
+	icsLineLength      = 75 // octets, longer content lines are folded
+)
+
+// WriteDueDatesICS writes an iCalendar file with the due dates of the risks not handled yet and the next review date of
+// the model as all-day events, so that teams can subscribe their calendars to the upcoming threat model obligations;
+// the model id (if any) keeps the events of models with the same title apart, and calendars published to anyone holding
+// the link (like the ones of the server) hold only the dates and risk ids
+func WriteDueDatesICS(fileSystem common.FileSystem, parsedModel *types.Model, modelId string, datesOnly bool, filename string) error {
+	err := fileSystem.WriteFile(filename, []byte(dueDatesICS(parsedModel, modelId, datesOnly, time.Now())), 0600)
+	if err != nil {
+		return fmt.Errorf("failed to write due dates to ICS file: %w", err)
+	}
+	return nil
+}
+
+func dueDatesICS(parsedModel *types.Model, modelId string, datesOnly bool, now time.Time) string {
+	title := parsedModel.Title
+	if datesOnly {
+		title = modelId
+	}
+	var ics strings.Builder
+	writeICSLine(&ics, "BEGIN:VCALENDAR")
+	writeICSLine(&ics, "VERSION:2.0")
+	writeICSLine(&ics, "PRODID:-//Threagile//Threagile "+docs.ThreagileVersion+"//EN")
+	writeICSLine(&ics, "CALSCALE:GREGORIAN")
+	writeICSLine(&ics, "X-WR-CALNAME:"+escapeICSText("Threat Model: "+title))
+
+	timestamp := now.UTC().Format(icsTimestampFormat)
+	if !parsedModel.NextReviewDate.IsZero() {
+		writeICSEvent(&ics, icsUID(parsedModel.Title, modelId, "next-review"), timestamp, parsedModel.NextReviewDate,
+			"Threat model review: "+title,
+			"Next review of the threat model "+title+" is due.")
+	}
+	for _, category := range types.SortedRiskCategories(parsedModel) {
+		for _, risk := range types.SortedRisksOfCategory(parsedModel, category) {
+			tracking := risk.GetRiskTrackingWithDefault(parsedModel)
+			if !tracking.IsDue() {
+				continue
+			}
+			uid := icsUID(parsedModel.Title, modelId, risk.SyntheticId)
+			if datesOnly {
+				writeICSEvent(&ics, uid, timestamp, tracking.DueDate, "Risk due: "+risk.SyntheticId, "Risk ID: "+risk.SyntheticId)
+				continue
+			}
+			description := []string{
+				"Severity: " + risk.Severity.Title(),
+				"Status: " + tracking.Status.Title(),
+				"Risk category: " + category.Title,
+				"Risk ID: " + risk.SyntheticId,
+			}
+			if len(tracking.Assignee) > 0 {
+				description = append(description, "Assignee: "+tracking.Assignee)
+			}
+			if len(tracking.Ticket) > 0 {
+				description = append(description, "Ticket: "+tracking.Ticket)
+			}
+			description = append(description, "Mitigation: "+category.Mitigation)
+			writeICSEvent(&ics, uid, timestamp, tracking.DueDate,
+				"Risk due: "+removeFormattingTags(risk.Title), strings.Join(description, "\n"))
+		}
+	}
+	writeICSLine(&ics, "END:VCALENDAR")
+	return ics.String()
+}
+
+func writeICSEvent(ics *strings.Builder, uid string, timestamp string, date types.Date, summary string, description string) {
+	writeICSLine(ics, "BEGIN:VEVENT")
+	writeICSLine(ics, "UID:"+uid)
+	writeICSLine(ics, "DTSTAMP:"+timestamp)
+	writeICSLine(ics, "DTSTART;VALUE=DATE:"+date.Format(icsDateFormat))
+	writeICSLine(ics, "DTEND;VALUE=DATE:"+date.AddDate(0, 0, 1).Format(icsDateFormat))
+	writeICSLine(ics, "SUMMARY:"+escapeICSText(summary))
+	writeICSLine(ics, "DESCRIPTION:"+escapeICSText(description))
+	writeICSLine(ics, "TRANSP:TRANSPARENT")
+	writeICSLine(ics, "END:VEVENT")
+}
+
+// icsUID is stable across exports, so that subscribed calendars update the events instead of duplicating them; it is
+// keyed by the id of the model if it has one, as several models may have the same title
+func icsUID(modelTitle string, modelId string, id string) string {
+	if len(modelId) > 0 {
+		modelTitle = modelId
+	}
+	hash := sha256.Sum256([]byte(modelTitle + "\n" + id))
+	return hex.EncodeToString(hash[:16]) + "@threagile"
+}
+
+func escapeICSText(text string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`).Replace(text)
+}
+
+// writeICSLine writes the content line with CRLF, folded into lines of at most 75 octets (without splitting UTF-8
+// characters) continued by a leading space
+func writeICSLine(ics *strings.Builder, line string) {
+	limit := icsLineLength
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !isUTF8Start(line[cut]) {
+			cut--
+		}
+		ics.WriteString(line[:cut] + "\r\n ")
+		line = line[cut:]
+		limit = icsLineLength - 1 // the leading space of the continuation line
+	}
+	ics.WriteString(line + "\r\n")
+}
+
+func isUTF8Start(b byte) bool {
+	return b&0xC0 != 0x80
+}
@@ -0,0 +1,79 @@
+package report
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+func TestDueDatesICS(t *testing.T) {
+	category := &types.RiskCategory{ID: "sql-injection", Title: "SQL Injection", Mitigation: "Use prepared statements; escape, validate."}
+	open := &types.Risk{CategoryId: category.ID, SyntheticId: "sql-injection@db", Title: "<b>SQL Injection</b> at <b>Database</b>", Severity: types.HighSeverity}
+	mitigated := &types.Risk{CategoryId: category.ID, SyntheticId: "sql-injection@cache", Title: "SQL Injection at Cache", Severity: types.MediumSeverity}
+	dueDate := types.Date{Time: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)}
+	parsedModel := &types.Model{
+		Title:                    "Some Model",
+		NextReviewDate:           types.Date{Time: time.Date(2024, 6, 30, 0, 0, 0, 0, time.UTC)},
+		BuiltInRiskCategories:    types.RiskCategories{category},
+		GeneratedRisksByCategory: map[string][]*types.Risk{category.ID: {open, mitigated}},
+		RiskTracking: map[string]*types.RiskTracking{
+			open.SyntheticId:      {SyntheticRiskId: open.SyntheticId, Status: types.InProgress, Assignee: "Team Payments", DueDate: dueDate},
+			mitigated.SyntheticId: {SyntheticRiskId: mitigated.SyntheticId, Status: types.Mitigated, DueDate: dueDate},
+		},
+	}
+
+	ics := dueDatesICS(parsedModel, "", false, time.Date(2024, 2, 1, 12, 0, 0, 0, time.UTC))
+
+	assert.True(t, strings.HasPrefix(ics, "BEGIN:VCALENDAR\r\nVERSION:2.0\r\n"))
+	assert.True(t, strings.HasSuffix(ics, "END:VEVENT\r\nEND:VCALENDAR\r\n"))
+	assert.Equal(t, 2, strings.Count(ics, "BEGIN:VEVENT"), "the review and the risk not handled yet")
+	assert.Contains(t, ics, "DTSTART;VALUE=DATE:20240630\r\nDTEND;VALUE=DATE:20240701\r\nSUMMARY:Threat model review: Some Model\r\n")
+	assert.Contains(t, ics, "DTSTAMP:20240201T120000Z\r\nDTSTART;VALUE=DATE:20240301\r\n")
+	assert.Contains(t, ics, "SUMMARY:Risk due: SQL Injection at Database\r\n")
+	unfolded := strings.ReplaceAll(ics, "\r\n ", "")
+	assert.Contains(t, unfolded, `Assignee: Team Payments\nMitigation: Use prepared statements\; escape\, validate.`)
+	assert.NotContains(t, ics, "Cache")
+	assert.Equal(t, ics, dueDatesICS(parsedModel, "", false, time.Date(2024, 2, 1, 12, 0, 0, 0, time.UTC)), "stable UIDs")
+	for _, line := range strings.Split(ics, "\r\n") {
+		assert.LessOrEqual(t, len(line), icsLineLength)
+	}
+}
+
+func TestDueDatesICSOfModelIdDatesOnly(t *testing.T) {
+	category := &types.RiskCategory{ID: "sql-injection", Title: "SQL Injection", Mitigation: "Use prepared statements."}
+	open := &types.Risk{CategoryId: category.ID, SyntheticId: "sql-injection@db", Title: "<b>SQL Injection</b> at <b>Database</b>", Severity: types.HighSeverity}
+	parsedModel := &types.Model{
+		Title:                    "Some Model",
+		NextReviewDate:           types.Date{Time: time.Date(2024, 6, 30, 0, 0, 0, 0, time.UTC)},
+		BuiltInRiskCategories:    types.RiskCategories{category},
+		GeneratedRisksByCategory: map[string][]*types.Risk{category.ID: {open}},
+		RiskTracking: map[string]*types.RiskTracking{
+			open.SyntheticId: {SyntheticRiskId: open.SyntheticId, Status: types.InProgress, Assignee: "Team Payments", Ticket: "PAY-42", DueDate: types.Date{Time: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)}},
+		},
+	}
+	now := time.Date(2024, 2, 1, 12, 0, 0, 0, time.UTC)
+
+	ics := dueDatesICS(parsedModel, "model-1", true, now)
+
+	unfolded := strings.ReplaceAll(ics, "\r\n ", "")
+	assert.Contains(t, unfolded, "SUMMARY:Risk due: sql-injection@db\r\nDESCRIPTION:Risk ID: sql-injection@db\r\n")
+	assert.Contains(t, unfolded, "DTSTART;VALUE=DATE:20240301\r\n")
+	for _, published := range []string{"Some Model", "Team Payments", "PAY-42", "prepared statements", "Database"} {
+		assert.NotContains(t, unfolded, published)
+	}
+	uids := func(ics string) []string {
+		result := make([]string, 0)
+		for _, line := range strings.Split(ics, "\r\n") {
+			if strings.HasPrefix(line, "UID:") {
+				result = append(result, line)
+			}
+		}
+		return result
+	}
+	assert.Len(t, uids(ics), 2)
+	assert.NotEqual(t, uids(ics), uids(dueDatesICS(parsedModel, "model-2", true, now)), "models of the same title")
+	assert.Equal(t, uids(ics), uids(dueDatesICS(parsedModel, "model-1", false, now)))
+}
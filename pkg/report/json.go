@@ -3,12 +3,14 @@ package report
 import (
 	"encoding/json"
 	"fmt"
-	"os"
+	"path/filepath"
 
+	"github.com/threagile/threagile/pkg/common"
+	"github.com/threagile/threagile/pkg/model"
 	"github.com/threagile/threagile/pkg/security/types"
 )
 
-func WriteRisksJSON(parsedModel *types.Model, filename string) error {
+func WriteRisksJSON(fileSystem common.FileSystem, result *model.Result, filename string) error {
 	/*
 		remainingRisks := make([]model.Risk, 0)
 		for _, category := range model.SortedRiskCategories() {
@@ -18,39 +20,78 @@ func WriteRisksJSON(parsedModel *types.Model, filename string) error {
 			}
 		}
 	*/
-	jsonBytes, err := json.Marshal(types.AllRisks(parsedModel))
+	jsonBytes, err := json.Marshal(result.Risks)
 	if err != nil {
 		return fmt.Errorf("failed to marshal risks to JSON: %w", err)
 	}
-	err = os.WriteFile(filename, jsonBytes, 0600)
+	err = fileSystem.WriteFile(filename, jsonBytes, 0600)
 	if err != nil {
 		return fmt.Errorf("failed to write risks to JSON file: %w", err)
 	}
 	return nil
 }
 
+func ReadRisksJSON(fileSystem common.FileSystem, filename string) ([]*types.Risk, error) {
+	jsonBytes, err := fileSystem.ReadFile(filepath.Clean(filename))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read risks JSON file: %w", err)
+	}
+	risks := make([]*types.Risk, 0)
+	err = json.Unmarshal(jsonBytes, &risks)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal risks from JSON: %w", err)
+	}
+	return risks, nil
+}
+
 // TODO: also a "data assets" json?
 
-func WriteTechnicalAssetsJSON(parsedModel *types.Model, filename string) error {
-	jsonBytes, err := json.Marshal(parsedModel.TechnicalAssets)
+func WriteTechnicalAssetsJSON(fileSystem common.FileSystem, result *model.Result, filename string) error {
+	jsonBytes, err := json.Marshal(result.TechnicalAssetsById())
 	if err != nil {
 		return fmt.Errorf("failed to marshal technical assets to JSON: %w", err)
 	}
-	err = os.WriteFile(filename, jsonBytes, 0600)
+	err = fileSystem.WriteFile(filename, jsonBytes, 0600)
 	if err != nil {
 		return fmt.Errorf("failed to write technical assets to JSON file: %w", err)
 	}
 	return nil
 }
 
-func WriteStatsJSON(parsedModel *types.Model, filename string) error {
-	jsonBytes, err := json.Marshal(types.OverallRiskStatistics(parsedModel))
+func WriteStatsJSON(fileSystem common.FileSystem, result *model.Result, filename string) error {
+	jsonBytes, err := json.Marshal(result.Statistics)
 	if err != nil {
 		return fmt.Errorf("failed to marshal stats to JSON: %w", err)
 	}
-	err = os.WriteFile(filename, jsonBytes, 0600)
+	err = fileSystem.WriteFile(filename, jsonBytes, 0600)
 	if err != nil {
 		return fmt.Errorf("failed to write stats to JSON file: %w", err)
 	}
 	return nil
 }
+
+// WriteExposureJSON writes the exposure of the technical assets, combining the modeled risks with the imported scanner
+// findings
+func WriteExposureJSON(fileSystem common.FileSystem, result *model.Result, filename string) error {
+	jsonBytes, err := json.Marshal(result.Exposures)
+	if err != nil {
+		return fmt.Errorf("failed to marshal exposure to JSON: %w", err)
+	}
+	err = fileSystem.WriteFile(filename, jsonBytes, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to write exposure to JSON file: %w", err)
+	}
+	return nil
+}
+
+func WriteRiskRuleFailuresJSON(fileSystem common.FileSystem, result *model.Result, filename string) error {
+	jsonBytes, err := json.Marshal(result.RiskRuleFailures)
+	if err != nil {
+		return fmt.Errorf("failed to marshal risk rule failures to JSON: %w", err)
+	}
+	err = fileSystem.WriteFile(filename, jsonBytes, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to write risk rule failures to JSON file: %w", err)
+	}
+	return nil
+}
@@ -1,6 +1,7 @@
 package report
 
 import (
+	"bytes"
 	"fmt"
 	"image"
 	"log"
@@ -15,7 +16,9 @@ import (
 
 	"github.com/jung-kurt/gofpdf"
 	"github.com/jung-kurt/gofpdf/contrib/gofpdi"
+	"github.com/threagile/threagile/pkg/common"
 	"github.com/threagile/threagile/pkg/docs"
+	"github.com/threagile/threagile/pkg/model"
 	"github.com/threagile/threagile/pkg/security/risks"
 	"github.com/threagile/threagile/pkg/security/types"
 	"github.com/wcharczuk/go-chart"
@@ -26,6 +29,7 @@ const fontSizeHeadline, fontSizeHeadlineSmall, fontSizeBody, fontSizeSmall, font
 const /*dataFlowDiagramFullscreen,*/ allowedPdfLandscapePages, embedDiagramLegendPage = /*false,*/ true, false
 
 type pdfReporter struct {
+	fileSystem                    common.FileSystem
 	isLandscapePage               bool
 	pdf                           *gofpdf.Fpdf
 	coverTemplateId               int
@@ -36,6 +40,14 @@ type pdfReporter struct {
 	tocLinkIdByAssetId            map[string]int
 	homeLink                      int
 	currentChapterTitleBreadcrumb string
+	riskDelta                     *types.RiskDelta
+	customSections                []*customSection
+}
+
+// customSection is a report section with its paragraphs already generated, as they are needed for the table of contents
+type customSection struct {
+	title      string
+	paragraphs []*types.ReportParagraph
 }
 
 func (r *pdfReporter) initReport() {
@@ -46,9 +58,12 @@ func (r *pdfReporter) initReport() {
 	r.homeLink = 0
 	r.currentChapterTitleBreadcrumb = ""
 	r.tocLinkIdByAssetId = make(map[string]int)
+	r.riskDelta = nil
+	r.customSections = make([]*customSection, 0)
 }
 
-func (r *pdfReporter) WriteReportPDF(reportFilename string,
+func (r *pdfReporter) WriteReportPDF(fileSystem common.FileSystem,
+	reportFilename string,
 	templateFilename string,
 	dataFlowDiagramFilenamePNG string,
 	dataAssetDiagramFilenamePNG string,
@@ -58,53 +73,90 @@ func (r *pdfReporter) WriteReportPDF(reportFilename string,
 	modelHash string,
 	introTextRAA string,
 	customRiskRules types.RiskRules,
+	reportSections []types.ReportSection,
+	previousRisks []*types.Risk,
 	tempFolder string,
-	model *types.Model) error {
+	fontFile string,
+	result *model.Result) (err error) {
 	defer func() {
 		value := recover()
 		if value != nil {
-			fmt.Printf("error creating PDF report: %v", value)
+			fmt.Fprintf(common.LogStdout(), "error creating PDF report: %v", value)
+			err = fmt.Errorf("error creating PDF report: %v", value) // not to be checkpointed as completed
 		}
 	}()
 
 	r.initReport()
-	r.createPdfAndInitMetadata(model)
+	r.fileSystem = fileSystem
+	parsedModel := result.ParsedModel()
+	if previousRisks != nil {
+		r.riskDelta = parsedModel.RiskDeltaSince(previousRisks)
+	}
+	for _, section := range reportSections {
+		paragraphs, err := section.GenerateParagraphs(parsedModel)
+		if err != nil {
+			return fmt.Errorf("error creating report section %q: %w", section.Title(), err)
+		}
+		r.customSections = append(r.customSections, &customSection{title: section.Title(), paragraphs: paragraphs})
+	}
+	r.createPdfAndInitMetadata(parsedModel)
+	err = addUnicodeFonts(r.pdf, fontFile)
+	if err != nil {
+		return fmt.Errorf("error adding fonts: %w", err)
+	}
 	r.parseBackgroundTemplate(templateFilename)
-	r.createCover(model)
-	r.createTableOfContents(model)
-	err := r.createManagementSummary(model, tempFolder)
+	r.createCover(parsedModel)
+	r.createTableOfContents(parsedModel)
+	err = r.createManagementSummary(parsedModel, tempFolder)
 	if err != nil {
 		return fmt.Errorf("error creating management summary: %w", err)
 	}
-	r.createImpactInitialRisks(model)
-	err = r.createRiskMitigationStatus(model, tempFolder)
+	r.createImpactInitialRisks(parsedModel)
+	err = r.createRiskMitigationStatus(parsedModel, tempFolder)
 	if err != nil {
 		return fmt.Errorf("error creating risk mitigation status: %w", err)
 	}
-	r.createImpactRemainingRisks(model)
-	err = r.createTargetDescription(model, filepath.Dir(modelFilename))
+	r.createImpactRemainingRisks(parsedModel)
+	err = r.createInherentVsResidualRisks(parsedModel, tempFolder)
+	if err != nil {
+		return fmt.Errorf("error creating inherent vs. residual risks: %w", err)
+	}
+	r.createChangesSinceLastAssessment(parsedModel)
+	r.createExposure(parsedModel)
+	err = r.createTargetDescription(parsedModel, filepath.Dir(modelFilename))
 	if err != nil {
 		return fmt.Errorf("error creating target description: %w", err)
 	}
-	r.embedDataFlowDiagram(dataFlowDiagramFilenamePNG, tempFolder)
-	r.createSecurityRequirements(model)
-	r.createAbuseCases(model)
-	r.createTagListing(model)
-	r.createSTRIDE(model)
-	r.createAssignmentByFunction(model)
-	r.createRAA(model, introTextRAA)
-	r.embedDataRiskMapping(dataAssetDiagramFilenamePNG, tempFolder)
+	err = r.embedDataFlowDiagram(dataFlowDiagramFilenamePNG, tempFolder)
+	if err != nil {
+		return fmt.Errorf("error embedding data flow diagram: %w", err)
+	}
+	r.createAssumptions(parsedModel)
+	r.createSecurityRequirements(parsedModel)
+	r.createSecurityControls(parsedModel)
+	r.createPenTestFindings(parsedModel)
+	r.createAbuseCases(parsedModel)
+	r.createTagListing(parsedModel)
+	r.createSTRIDE(parsedModel)
+	r.createAssignmentByFunction(parsedModel)
+	r.createRAA(parsedModel, introTextRAA)
+	err = r.embedDataRiskMapping(dataAssetDiagramFilenamePNG, tempFolder)
+	if err != nil {
+		return fmt.Errorf("error embedding data asset diagram: %w", err)
+	}
 	//createDataRiskQuickWins()
-	r.createOutOfScopeAssets(model)
-	r.createModelFailures(model)
-	r.createQuestions(model)
-	r.createRiskCategories(model)
-	r.createTechnicalAssets(model)
-	r.createDataAssets(model)
-	r.createTrustBoundaries(model)
-	r.createSharedRuntimes(model)
-	r.createRiskRulesChecked(model, modelFilename, skipRiskRules, buildTimestamp, modelHash, customRiskRules)
-	r.createDisclaimer(model)
+	r.createOutOfScopeAssets(parsedModel)
+	r.createModelFailures(parsedModel)
+	r.createQuestions(parsedModel)
+	r.createRiskCategories(parsedModel)
+	r.createTechnicalAssets(parsedModel)
+	r.createDataAssets(parsedModel)
+	r.createTrustBoundaries(parsedModel)
+	r.createSharedRuntimes(parsedModel)
+	r.createCustomSections()
+	r.createAnnotations(parsedModel)
+	r.createRiskRulesChecked(parsedModel, modelFilename, skipRiskRules, buildTimestamp, modelHash, customRiskRules)
+	r.createDisclaimer(parsedModel)
 	err = r.writeReportToFile(reportFilename)
 	if err != nil {
 		return fmt.Errorf("error writing report to file: %w", err)
@@ -129,7 +181,7 @@ func (r *pdfReporter) createPdfAndInitMetadata(model *types.Model) {
 	})
 	r.pdf.SetFooterFunc(func() {
 		r.addBreadcrumb(model)
-		r.pdf.SetFont("Helvetica", "", 10)
+		r.pdf.SetFont(fontFamily, "", 10)
 		r.pdf.SetTextColor(127, 127, 127)
 		r.pdf.Text(8.6, 284, "Threat Model Report via Threagile") //: "+parsedModel.Title)
 		r.pdf.Link(8.4, 281, 54.6, 4, r.homeLink)
@@ -149,8 +201,8 @@ func (r *pdfReporter) createPdfAndInitMetadata(model *types.Model) {
 
 func (r *pdfReporter) addBreadcrumb(parsedModel *types.Model) {
 	if len(r.currentChapterTitleBreadcrumb) > 0 {
-		uni := r.pdf.UnicodeTranslatorFromDescriptor("")
-		r.pdf.SetFont("Helvetica", "", 10)
+		uni := keepUTF8
+		r.pdf.SetFont(fontFamily, "", 10)
 		r.pdf.SetTextColor(127, 127, 127)
 		r.pdf.Text(46.7, 24.5, uni(r.currentChapterTitleBreadcrumb+"   -   "+parsedModel.Title))
 	}
@@ -173,37 +225,37 @@ func (r *pdfReporter) parseBackgroundTemplate(templateFilename string) {
 }
 
 func (r *pdfReporter) createCover(parsedModel *types.Model) {
-	uni := r.pdf.UnicodeTranslatorFromDescriptor("")
+	uni := keepUTF8
 	r.pdf.AddPage()
 	gofpdi.UseImportedTemplate(r.pdf, r.coverTemplateId, 0, 0, 0, 300)
-	r.pdf.SetFont("Helvetica", "B", 28)
+	r.pdf.SetFont(fontFamily, "B", 28)
 	r.pdf.SetTextColor(0, 0, 0)
 	r.pdf.Text(40, 110, "Threat Model Report")
 	r.pdf.Text(40, 125, uni(parsedModel.Title))
-	r.pdf.SetFont("Helvetica", "", 12)
+	r.pdf.SetFont(fontFamily, "", 12)
 	reportDate := parsedModel.Date
 	if reportDate.IsZero() {
 		reportDate = types.Date{Time: time.Now()}
 	}
 	r.pdf.Text(40.7, 145, reportDate.Format("2 January 2006"))
 	r.pdf.Text(40.7, 153, uni(parsedModel.Author.Name))
-	r.pdf.SetFont("Helvetica", "", 10)
+	r.pdf.SetFont(fontFamily, "", 10)
 	r.pdf.SetTextColor(80, 80, 80)
 	r.pdf.Text(8.6, 275, parsedModel.Author.Homepage)
-	r.pdf.SetFont("Helvetica", "", 12)
+	r.pdf.SetFont(fontFamily, "", 12)
 	r.pdf.SetTextColor(0, 0, 0)
 }
 
 func (r *pdfReporter) createTableOfContents(parsedModel *types.Model) {
-	uni := r.pdf.UnicodeTranslatorFromDescriptor("")
+	uni := keepUTF8
 	r.pdf.AddPage()
 	r.currentChapterTitleBreadcrumb = "Table of Contents"
 	r.homeLink = r.pdf.AddLink()
 	r.defineLinkTarget("{home}")
 	gofpdi.UseImportedTemplate(r.pdf, r.contentTemplateId, 0, 0, 0, 300)
-	r.pdf.SetFont("Helvetica", "B", fontSizeHeadline)
+	r.pdf.SetFont(fontFamily, "B", fontSizeHeadline)
 	r.pdf.Text(11, 40, "Table of Contents")
-	r.pdf.SetFont("Helvetica", "", fontSizeBody)
+	r.pdf.SetFont(fontFamily, "", fontSizeBody)
 	r.pdf.SetY(46)
 
 	r.pdf.SetLineWidth(0.25)
@@ -213,9 +265,9 @@ func (r *pdfReporter) createTableOfContents(parsedModel *types.Model) {
 	// ===============
 
 	var y float64 = 50
-	r.pdf.SetFont("Helvetica", "B", fontSizeBody)
+	r.pdf.SetFont(fontFamily, "B", fontSizeBody)
 	r.pdf.Text(11, y, "Results Overview")
-	r.pdf.SetFont("Helvetica", "", fontSizeBody)
+	r.pdf.SetFont(fontFamily, "", fontSizeBody)
 
 	y += 6
 	r.pdf.Text(11, y, "    "+"Management Summary")
@@ -259,6 +311,28 @@ func (r *pdfReporter) createTableOfContents(parsedModel *types.Model) {
 	r.pdf.Line(15.6, y+1.3, 11+171.5, y+1.3)
 	r.pdf.Link(10, y-5, 172.5, 6.5, r.pdf.AddLink())
 
+	y += 6
+	r.pdf.Text(11, y, "    "+"Inherent vs. Residual Risks")
+	r.pdf.Text(175, y, "{inherent-vs-residual-risks}")
+	r.pdf.Line(15.6, y+1.3, 11+171.5, y+1.3)
+	r.pdf.Link(10, y-5, 172.5, 6.5, r.pdf.AddLink())
+
+	if r.riskDelta != nil {
+		y += 6
+		r.pdf.Text(11, y, "    "+"Changes since Last Assessment")
+		r.pdf.Text(175, y, "{changes-since-last-assessment}")
+		r.pdf.Line(15.6, y+1.3, 11+171.5, y+1.3)
+		r.pdf.Link(10, y-5, 172.5, 6.5, r.pdf.AddLink())
+	}
+
+	if len(parsedModel.ScannerFindings) > 0 {
+		y += 6
+		r.pdf.Text(11, y, "    "+"Exposure")
+		r.pdf.Text(175, y, "{exposure}")
+		r.pdf.Line(15.6, y+1.3, 11+171.5, y+1.3)
+		r.pdf.Link(10, y-5, 172.5, 6.5, r.pdf.AddLink())
+	}
+
 	y += 6
 	r.pdf.Text(11, y, "    "+"Application Overview")
 	r.pdf.Text(175, y, "{target-overview}")
@@ -271,12 +345,36 @@ func (r *pdfReporter) createTableOfContents(parsedModel *types.Model) {
 	r.pdf.Line(15.6, y+1.3, 11+171.5, y+1.3)
 	r.pdf.Link(10, y-5, 172.5, 6.5, r.pdf.AddLink())
 
+	if len(parsedModel.Assumptions) > 0 || len(parsedModel.ScopeExclusions) > 0 {
+		y += 6
+		r.pdf.Text(11, y, "    "+"Assumptions and Scope Exclusions")
+		r.pdf.Text(175, y, "{assumptions}")
+		r.pdf.Line(15.6, y+1.3, 11+171.5, y+1.3)
+		r.pdf.Link(10, y-5, 172.5, 6.5, r.pdf.AddLink())
+	}
+
 	y += 6
 	r.pdf.Text(11, y, "    "+"Security Requirements")
 	r.pdf.Text(175, y, "{security-requirements}")
 	r.pdf.Line(15.6, y+1.3, 11+171.5, y+1.3)
 	r.pdf.Link(10, y-5, 172.5, 6.5, r.pdf.AddLink())
 
+	if len(parsedModel.SecurityControls) > 0 {
+		y += 6
+		r.pdf.Text(11, y, "    "+"Security Controls")
+		r.pdf.Text(175, y, "{security-controls}")
+		r.pdf.Line(15.6, y+1.3, 11+171.5, y+1.3)
+		r.pdf.Link(10, y-5, 172.5, 6.5, r.pdf.AddLink())
+	}
+
+	if len(parsedModel.PenTestFindings) > 0 {
+		y += 6
+		r.pdf.Text(11, y, "    "+"Pen-Test Findings")
+		r.pdf.Text(175, y, "{pen-test-findings}")
+		r.pdf.Line(15.6, y+1.3, 11+171.5, y+1.3)
+		r.pdf.Link(10, y-5, 172.5, 6.5, r.pdf.AddLink())
+	}
+
 	y += 6
 	r.pdf.Text(11, y, "    "+"Abuse Cases")
 	r.pdf.Text(175, y, "{abuse-cases}")
@@ -378,10 +476,10 @@ func (r *pdfReporter) createTableOfContents(parsedModel *types.Model) {
 			r.pageBreakInLists()
 			y = 40
 		}
-		r.pdf.SetFont("Helvetica", "B", fontSizeBody)
+		r.pdf.SetFont(fontFamily, "B", fontSizeBody)
 		r.pdf.SetTextColor(0, 0, 0)
 		r.pdf.Text(11, y, "Risks by Vulnerability category")
-		r.pdf.SetFont("Helvetica", "", fontSizeBody)
+		r.pdf.SetFont(fontFamily, "", fontSizeBody)
 		y += 6
 		r.pdf.Text(11, y, "    "+"Identified Risks by Vulnerability category")
 		r.pdf.Text(175, y, "{intro-risks-by-vulnerability-category}")
@@ -433,10 +531,10 @@ func (r *pdfReporter) createTableOfContents(parsedModel *types.Model) {
 			r.pageBreakInLists()
 			y = 40
 		}
-		r.pdf.SetFont("Helvetica", "B", fontSizeBody)
+		r.pdf.SetFont(fontFamily, "B", fontSizeBody)
 		r.pdf.SetTextColor(0, 0, 0)
 		r.pdf.Text(11, y, "Risks by Technical Asset")
-		r.pdf.SetFont("Helvetica", "", fontSizeBody)
+		r.pdf.SetFont(fontFamily, "", fontSizeBody)
 		y += 6
 		r.pdf.Text(11, y, "    "+"Identified Risks by Technical Asset")
 		r.pdf.Text(175, y, "{intro-risks-by-technical-asset}")
@@ -493,10 +591,10 @@ func (r *pdfReporter) createTableOfContents(parsedModel *types.Model) {
 			r.pageBreakInLists()
 			y = 40
 		}
-		r.pdf.SetFont("Helvetica", "B", fontSizeBody)
+		r.pdf.SetFont(fontFamily, "B", fontSizeBody)
 		r.pdfColorBlack()
 		r.pdf.Text(11, y, "Data Breach Probabilities by Data Asset")
-		r.pdf.SetFont("Helvetica", "", fontSizeBody)
+		r.pdf.SetFont(fontFamily, "", fontSizeBody)
 		y += 6
 		r.pdf.Text(11, y, "    "+"Identified Data Breach Probabilities by Data Asset")
 		r.pdf.Text(175, y, "{intro-risks-by-data-asset}")
@@ -544,10 +642,10 @@ func (r *pdfReporter) createTableOfContents(parsedModel *types.Model) {
 			r.pageBreakInLists()
 			y = 40
 		}
-		r.pdf.SetFont("Helvetica", "B", fontSizeBody)
+		r.pdf.SetFont(fontFamily, "B", fontSizeBody)
 		r.pdfColorBlack()
 		r.pdf.Text(11, y, "Trust Boundaries")
-		r.pdf.SetFont("Helvetica", "", fontSizeBody)
+		r.pdf.SetFont(fontFamily, "", fontSizeBody)
 		for _, key := range types.SortedKeysOfTrustBoundaries(parsedModel) {
 			trustBoundary := parsedModel.TrustBoundaries[key]
 			y += 6
@@ -577,10 +675,10 @@ func (r *pdfReporter) createTableOfContents(parsedModel *types.Model) {
 			r.pageBreakInLists()
 			y = 40
 		}
-		r.pdf.SetFont("Helvetica", "B", fontSizeBody)
+		r.pdf.SetFont(fontFamily, "B", fontSizeBody)
 		r.pdfColorBlack()
 		r.pdf.Text(11, y, "Shared Runtime")
-		r.pdf.SetFont("Helvetica", "", fontSizeBody)
+		r.pdf.SetFont(fontFamily, "", fontSizeBody)
 		for _, key := range types.SortedKeysOfSharedRuntime(parsedModel) {
 			sharedRuntime := parsedModel.SharedRuntimes[key]
 			y += 6
@@ -598,6 +696,56 @@ func (r *pdfReporter) createTableOfContents(parsedModel *types.Model) {
 
 	// ===============
 
+	if len(r.customSections) > 0 {
+		y += 6
+		y += 6
+		if y > 260 { // 260 instead of 275 for major group headlines to avoid "Schusterjungen"
+			r.pageBreakInLists()
+			y = 40
+		}
+		r.pdf.SetFont(fontFamily, "B", fontSizeBody)
+		r.pdfColorBlack()
+		r.pdf.Text(11, y, "Additional Sections")
+		r.pdf.SetFont(fontFamily, "", fontSizeBody)
+		for i, section := range r.customSections {
+			y += 6
+			if y > 275 {
+				r.pageBreakInLists()
+				y = 40
+			}
+			r.pdf.Text(11, y, "    "+uni(section.title))
+			r.pdf.Text(175, y, "{section:"+strconv.Itoa(i)+"}")
+			r.pdf.Line(15.6, y+1.3, 11+171.5, y+1.3)
+			r.pdf.Link(10, y-5, 172.5, 6.5, r.pdf.AddLink())
+		}
+	}
+
+	// ===============
+
+	if len(parsedModel.Annotations) > 0 {
+		y += 6
+		y += 6
+		if y > 260 { // 260 instead of 275 for major group headlines to avoid "Schusterjungen"
+			r.pageBreakInLists()
+			y = 40
+		}
+		r.pdf.SetFont(fontFamily, "B", fontSizeBody)
+		r.pdfColorBlack()
+		r.pdf.Text(11, y, "Appendix")
+		r.pdf.SetFont(fontFamily, "", fontSizeBody)
+		y += 6
+		if y > 275 {
+			r.pageBreakInLists()
+			y = 40
+		}
+		r.pdf.Text(11, y, "    "+"Review Annotations")
+		r.pdf.Text(175, y, "{annotations}")
+		r.pdf.Line(15.6, y+1.3, 11+171.5, y+1.3)
+		r.pdf.Link(10, y-5, 172.5, 6.5, r.pdf.AddLink())
+	}
+
+	// ===============
+
 	y += 6
 	y += 6
 	if y > 260 { // 260 instead of 275 for major group headlines to avoid "Schusterjungen"
@@ -605,9 +753,9 @@ func (r *pdfReporter) createTableOfContents(parsedModel *types.Model) {
 		y = 40
 	}
 	r.pdfColorBlack()
-	r.pdf.SetFont("Helvetica", "B", fontSizeBody)
+	r.pdf.SetFont(fontFamily, "B", fontSizeBody)
 	r.pdf.Text(11, y, "About Threagile")
-	r.pdf.SetFont("Helvetica", "", fontSizeBody)
+	r.pdf.SetFont(fontFamily, "", fontSizeBody)
 	y += 6
 	if y > 275 {
 		r.pageBreakInLists()
@@ -674,9 +822,9 @@ func (r *pdfReporter) createDisclaimer(parsedModel *types.Model) {
 	r.defineLinkTarget("{disclaimer}")
 	gofpdi.UseImportedTemplate(r.pdf, r.contentTemplateId, 0, 0, 0, 300)
 	r.pdfColorDisclaimer()
-	r.pdf.SetFont("Helvetica", "B", fontSizeHeadline)
+	r.pdf.SetFont(fontFamily, "B", fontSizeHeadline)
 	r.pdf.Text(11, 40, "Disclaimer")
-	r.pdf.SetFont("Helvetica", "", fontSizeBody)
+	r.pdf.SetFont(fontFamily, "", fontSizeBody)
 	r.pdf.SetY(46)
 
 	var disclaimer strings.Builder
@@ -722,7 +870,7 @@ func (r *pdfReporter) createDisclaimer(parsedModel *types.Model) {
 }
 
 func (r *pdfReporter) createManagementSummary(parsedModel *types.Model, tempFolder string) error {
-	uni := r.pdf.UnicodeTranslatorFromDescriptor("")
+	uni := keepUTF8
 	r.pdf.SetTextColor(0, 0, 0)
 	title := "Management Summary"
 	r.addHeadline(title, false)
@@ -758,7 +906,7 @@ func (r *pdfReporter) createManagementSummary(parsedModel *types.Model, tempFold
 		"In total <b>"+strconv.Itoa(types.TotalRiskCount(parsedModel))+" initial risks</b> in <b>"+strconv.Itoa(len(parsedModel.GeneratedRisksByCategory))+" categories</b> have "+
 		"been identified during the threat modeling process:<br><br>") // TODO plural singular stuff risk/s category/ies has/have
 
-	r.pdf.SetFont("Helvetica", "B", fontSizeBody)
+	r.pdf.SetFont(fontFamily, "B", fontSizeBody)
 
 	r.pdf.CellFormat(17, 6, "", "0", 0, "", false, 0, "")
 	r.pdf.CellFormat(10, 6, "", "0", 0, "", false, 0, "")
@@ -806,9 +954,9 @@ func (r *pdfReporter) createManagementSummary(parsedModel *types.Model, tempFold
 	colorRiskStatusMitigated(r.pdf)
 	r.pdf.CellFormat(23, 6, "", "0", 0, "", false, 0, "")
 	r.pdf.CellFormat(10, 6, strconv.Itoa(countStatusMitigated), "0", 0, "R", false, 0, "")
-	r.pdf.SetFont("Helvetica", "BI", fontSizeBody)
+	r.pdf.SetFont(fontFamily, "BI", fontSizeBody)
 	r.pdf.CellFormat(60, 6, "mitigated", "0", 0, "", false, 0, "")
-	r.pdf.SetFont("Helvetica", "B", fontSizeBody)
+	r.pdf.SetFont(fontFamily, "B", fontSizeBody)
 	r.pdf.Ln(-1)
 
 	colorLowRisk(r.pdf)
@@ -818,12 +966,12 @@ func (r *pdfReporter) createManagementSummary(parsedModel *types.Model, tempFold
 	colorRiskStatusFalsePositive(r.pdf)
 	r.pdf.CellFormat(23, 6, "", "0", 0, "", false, 0, "")
 	r.pdf.CellFormat(10, 6, strconv.Itoa(countStatusFalsePositive), "0", 0, "R", false, 0, "")
-	r.pdf.SetFont("Helvetica", "BI", fontSizeBody)
+	r.pdf.SetFont(fontFamily, "BI", fontSizeBody)
 	r.pdf.CellFormat(60, 6, "false positive", "0", 0, "", false, 0, "")
-	r.pdf.SetFont("Helvetica", "B", fontSizeBody)
+	r.pdf.SetFont(fontFamily, "B", fontSizeBody)
 	r.pdf.Ln(-1)
 
-	r.pdf.SetFont("Helvetica", "", fontSizeBody)
+	r.pdf.SetFont(fontFamily, "", fontSizeBody)
 
 	// pie chart: risk severity
 	pieChartRiskSeverity := chart.PieChart{
@@ -1047,7 +1195,7 @@ func (r *pdfReporter) createRiskMitigationStatus(parsedModel *types.Model, tempF
 	}
 
 	// draw the X-Axis legend on my own
-	r.pdf.SetFont("Helvetica", "", fontSizeSmall)
+	r.pdf.SetFont(fontFamily, "", fontSizeSmall)
 	r.pdfColorBlack()
 	r.pdf.Text(24.02, 169, "Low ("+strconv.Itoa(len(risksLow))+")")
 	r.pdf.Text(46.10, 169, "Medium ("+strconv.Itoa(len(risksMedium))+")")
@@ -1055,7 +1203,7 @@ func (r *pdfReporter) createRiskMitigationStatus(parsedModel *types.Model, tempF
 	r.pdf.Text(97.95, 169, "High ("+strconv.Itoa(len(risksHigh))+")")
 	r.pdf.Text(121.65, 169, "Critical ("+strconv.Itoa(len(risksCritical))+")")
 
-	r.pdf.SetFont("Helvetica", "B", fontSizeBody)
+	r.pdf.SetFont(fontFamily, "B", fontSizeBody)
 	r.pdf.Ln(20)
 
 	colorRiskStatusUnchecked(r.pdf)
@@ -1081,19 +1229,19 @@ func (r *pdfReporter) createRiskMitigationStatus(parsedModel *types.Model, tempF
 	colorRiskStatusMitigated(r.pdf)
 	r.pdf.CellFormat(150, 6, "", "0", 0, "", false, 0, "")
 	r.pdf.CellFormat(10, 6, strconv.Itoa(countStatusMitigated), "0", 0, "R", false, 0, "")
-	r.pdf.SetFont("Helvetica", "BI", fontSizeBody)
+	r.pdf.SetFont(fontFamily, "BI", fontSizeBody)
 	r.pdf.CellFormat(60, 6, "mitigated", "0", 0, "", false, 0, "")
-	r.pdf.SetFont("Helvetica", "B", fontSizeBody)
+	r.pdf.SetFont(fontFamily, "B", fontSizeBody)
 	r.pdf.Ln(-1)
 	colorRiskStatusFalsePositive(r.pdf)
 	r.pdf.CellFormat(150, 6, "", "0", 0, "", false, 0, "")
 	r.pdf.CellFormat(10, 6, strconv.Itoa(countStatusFalsePositive), "0", 0, "R", false, 0, "")
-	r.pdf.SetFont("Helvetica", "BI", fontSizeBody)
+	r.pdf.SetFont(fontFamily, "BI", fontSizeBody)
 	r.pdf.CellFormat(60, 6, "false positive", "0", 0, "", false, 0, "")
-	r.pdf.SetFont("Helvetica", "B", fontSizeBody)
+	r.pdf.SetFont(fontFamily, "B", fontSizeBody)
 	r.pdf.Ln(-1)
 
-	r.pdf.SetFont("Helvetica", "", fontSizeBody)
+	r.pdf.SetFont(fontFamily, "", fontSizeBody)
 
 	r.pdfColorBlack()
 	if count == 0 {
@@ -1101,9 +1249,13 @@ func (r *pdfReporter) createRiskMitigationStatus(parsedModel *types.Model, tempF
 			"After removal of risks with status <i>mitigated</i> and <i>false positive</i> "+
 			"<b>"+strconv.Itoa(count)+" remain unmitigated</b>.")
 	} else {
+		overdueText := ""
+		if overdue := len(types.OverdueRisks(parsedModel, time.Now())); overdue > 0 {
+			overdueText = " (<b>" + strconv.Itoa(overdue) + " of them overdue</b>, i.e. past the due date of their risk tracking)"
+		}
 		html.Write(5, "<br><br><br><br><br><br><br><br><br><br><br><br><br><br><br>"+
 			"After removal of risks with status <i>mitigated</i> and <i>false positive</i> "+
-			"the following <b>"+strconv.Itoa(count)+" remain unmitigated</b>:")
+			"the following <b>"+strconv.Itoa(count)+" remain unmitigated</b>"+overdueText+":")
 
 		countCritical := len(types.ReduceToOnlyStillAtRisk(parsedModel, types.FilteredByOnlyCriticalRisks(parsedModel)))
 		countHigh := len(types.ReduceToOnlyStillAtRisk(parsedModel, types.FilteredByOnlyHighRisks(parsedModel)))
@@ -1174,7 +1326,7 @@ func (r *pdfReporter) createRiskMitigationStatus(parsedModel *types.Model, tempF
 		_ = r.embedPieChart(pieChartRemainingRiskSeverity, 15.0, 216, tempFolder)
 		_ = r.embedPieChart(pieChartRemainingRisksByFunction, 110.0, 216, tempFolder)
 
-		r.pdf.SetFont("Helvetica", "B", fontSizeBody)
+		r.pdf.SetFont(fontFamily, "B", fontSizeBody)
 		r.pdf.Ln(8)
 
 		colorCriticalRisk(r.pdf)
@@ -1221,7 +1373,7 @@ func (r *pdfReporter) createRiskMitigationStatus(parsedModel *types.Model, tempF
 		r.pdf.CellFormat(10, 6, strconv.Itoa(countOperation), "0", 0, "R", false, 0, "")
 		r.pdf.CellFormat(60, 6, "operations related", "0", 0, "", false, 0, "")
 		r.pdf.Ln(-1)
-		r.pdf.SetFont("Helvetica", "", fontSizeBody)
+		r.pdf.SetFont(fontFamily, "", fontSizeBody)
 	}
 	return nil
 }
@@ -1268,6 +1420,24 @@ func (r *pdfReporter) embedPieChart(pieChart chart.PieChart, x float64, y float6
 	return nil
 }
 
+func (r *pdfReporter) embedRiskMatrix(risks []*types.Risk, title string, x float64, y float64, tempFolder string) error {
+	tmpFilePNG, err := os.CreateTemp(tempFolder, "risk-matrix-*-.png")
+	if err != nil {
+		return fmt.Errorf("error creating temporary file for risk matrix: %w", err)
+	}
+	defer func() { _ = os.Remove(tmpFilePNG.Name()) }()
+	defer func() { _ = tmpFilePNG.Close() }()
+	err = RenderRiskMatrix(NewRiskMatrix(risks), title, chart.PNG, tmpFilePNG)
+	if err != nil {
+		return fmt.Errorf("error rendering risk matrix: %w", err)
+	}
+	var options gofpdf.ImageOptions
+	options.ImageType = ""
+	r.pdf.RegisterImage(tmpFilePNG.Name(), "")
+	r.pdf.ImageOptions(tmpFilePNG.Name(), x, y, 92, 0, false, options, 0, "")
+	return nil
+}
+
 func makeColor(hexColor string) drawing.Color {
 	_, i := utf8.DecodeRuneInString(hexColor)
 	return drawing.ColorFromHex(hexColor[i:]) // = remove first char, which is # in rgb hex here
@@ -1281,6 +1451,219 @@ func (r *pdfReporter) createImpactRemainingRisks(parsedModel *types.Model) {
 	r.renderImpactAnalysis(parsedModel, false)
 }
 
+func (r *pdfReporter) createInherentVsResidualRisks(parsedModel *types.Model, tempFolder string) error {
+	r.pdf.SetTextColor(0, 0, 0)
+	chapTitle := "Inherent vs. Residual Risks"
+	r.addHeadline(chapTitle, false)
+	r.defineLinkTarget("{inherent-vs-residual-risks}")
+	r.currentChapterTitleBreadcrumb = chapTitle
+
+	html := r.pdf.HTMLBasicNew()
+	html.Write(5, "The <b>inherent severity</b> of a risk is the one computed by its risk rule. The <b>residual severity</b> "+
+		"takes threat intel, modeled security controls and environment criticality into account and is lowered to "+
+		"<i>low</i> once risk tracking marks the risk as <i>mitigated</i> or <i>false positive</i>. The matrices position "+
+		"all identified risks and the ones still at risk by their exploitation likelihood and impact.<br><br>")
+
+	y := r.pdf.GetY()
+	err := r.embedRiskMatrix(types.AllRisks(parsedModel), "Identified Risks", 11, y, tempFolder)
+	if err != nil {
+		return err
+	}
+	err = r.embedRiskMatrix(types.FilteredByStillAtRisk(parsedModel), "Still at Risk", 107, y, tempFolder)
+	if err != nil {
+		return err
+	}
+	r.pdf.SetY(y + 80)
+
+	inherent, residual := types.CountByInherentSeverity(parsedModel), types.CountByResidualSeverity(parsedModel)
+	r.pdf.SetFont(fontFamily, "B", fontSizeBody)
+	r.pdfColorBlack()
+	r.pdf.CellFormat(40, 6, "Severity", "B", 0, "", false, 0, "")
+	r.pdf.CellFormat(30, 6, "Inherent", "B", 0, "R", false, 0, "")
+	r.pdf.CellFormat(30, 6, "Residual", "B", 0, "R", false, 0, "")
+	r.pdf.Ln(-1)
+	for _, severity := range []types.RiskSeverity{types.CriticalSeverity, types.HighSeverity, types.ElevatedSeverity, types.MediumSeverity, types.LowSeverity} {
+		switch severity {
+		case types.CriticalSeverity:
+			colorCriticalRisk(r.pdf)
+		case types.HighSeverity:
+			colorHighRisk(r.pdf)
+		case types.ElevatedSeverity:
+			colorElevatedRisk(r.pdf)
+		case types.MediumSeverity:
+			colorMediumRisk(r.pdf)
+		default:
+			colorLowRisk(r.pdf)
+		}
+		r.pdf.CellFormat(40, 6, severity.Title(), "0", 0, "", false, 0, "")
+		r.pdf.CellFormat(30, 6, strconv.Itoa(inherent[severity]), "0", 0, "R", false, 0, "")
+		r.pdf.CellFormat(30, 6, strconv.Itoa(residual[severity]), "0", 0, "R", false, 0, "")
+		r.pdf.Ln(-1)
+	}
+	r.pdf.SetFont(fontFamily, "", fontSizeBody)
+	r.pdfColorBlack()
+	return nil
+}
+
+func (r *pdfReporter) createChangesSinceLastAssessment(parsedModel *types.Model) {
+	if r.riskDelta == nil {
+		return
+	}
+	uni := keepUTF8
+	r.pdf.SetTextColor(0, 0, 0)
+	chapTitle := "Changes since Last Assessment"
+	r.addHeadline(chapTitle, false)
+	r.defineLinkTarget("{changes-since-last-assessment}")
+	r.currentChapterTitleBreadcrumb = chapTitle
+
+	html := r.pdf.HTMLBasicNew()
+	html.Write(5, "Compared to the previous assessment <b>"+strconv.Itoa(len(r.riskDelta.New))+" new</b>, <b>"+
+		strconv.Itoa(len(r.riskDelta.Resolved))+" resolved</b> and <b>"+strconv.Itoa(len(r.riskDelta.Reopened))+" re-opened</b> "+
+		"risks have been identified. Resolved risks are either gone from the architecture or marked as <i>mitigated</i> or "+
+		"<i>false positive</i> by now, re-opened risks were marked so previously but are at risk again.<br>")
+
+	reportDate := parsedModel.Date
+	if reportDate.IsZero() {
+		reportDate = types.Date{Time: time.Now()}
+	}
+	for _, section := range []struct {
+		title string
+		risks []*types.Risk
+	}{
+		{"New Risks", r.riskDelta.New},
+		{"Resolved Risks", r.riskDelta.Resolved},
+		{"Re-opened Risks", r.riskDelta.Reopened},
+	} {
+		r.pdf.SetFont(fontFamily, "", fontSizeBody)
+		r.pdfColorBlack()
+		html.Write(5, "<br><b><i>"+section.title+"</i></b><br><br>")
+		if len(section.risks) == 0 {
+			r.pdfColorGray()
+			html.Write(5, "none<br>")
+			continue
+		}
+		for _, risk := range section.risks {
+			if r.pdf.GetY() > 260 {
+				r.pageBreak()
+				r.pdf.SetY(36)
+			}
+			date := reportDate
+			if tracking := risk.GetRiskTracking(parsedModel); tracking != nil && !tracking.Date.IsZero() {
+				date = tracking.Date
+			}
+			switch risk.Severity {
+			case types.CriticalSeverity:
+				colorCriticalRisk(r.pdf)
+			case types.HighSeverity:
+				colorHighRisk(r.pdf)
+			case types.ElevatedSeverity:
+				colorElevatedRisk(r.pdf)
+			case types.MediumSeverity:
+				colorMediumRisk(r.pdf)
+			default:
+				colorLowRisk(r.pdf)
+			}
+			r.pdf.SetFont(fontFamily, "", fontSizeSmall)
+			r.pdf.CellFormat(22, 5, date.Format("2006-01-02"), "0", 0, "", false, 0, "")
+			r.pdf.CellFormat(20, 5, risk.Severity.Title(), "0", 0, "", false, 0, "")
+			r.pdf.MultiCell(140, 5, uni(risk.Title), "0", "0", false)
+			r.pdfColorGray()
+			r.pdf.SetFont(fontFamily, "", fontSizeVerySmall)
+			r.pdf.CellFormat(42, 4, "", "0", 0, "", false, 0, "")
+			r.pdf.MultiCell(140, 4, uni(risk.SyntheticId), "0", "0", false)
+		}
+	}
+	r.pdf.SetFont(fontFamily, "", fontSizeBody)
+	r.pdfColorBlack()
+}
+
+// createExposure correlates the imported scanner findings with the modeled risks per technical asset, telling which
+// risks are confirmed by findings and which findings are not covered by the model
+func (r *pdfReporter) createExposure(parsedModel *types.Model) {
+	if len(parsedModel.ScannerFindings) == 0 {
+		return
+	}
+	uni := keepUTF8
+	r.pdf.SetTextColor(0, 0, 0)
+	chapTitle := "Exposure"
+	r.addHeadline(chapTitle, false)
+	r.defineLinkTarget("{exposure}")
+	r.currentChapterTitleBreadcrumb = chapTitle
+
+	exposures := parsedModel.Exposures()
+	confirmed, unmodeled := 0, 0
+	for _, exposure := range exposures {
+		confirmed += len(exposure.ConfirmedRisks)
+		unmodeled += exposure.UnmodeledFindings
+	}
+	html := r.pdf.HTMLBasicNew()
+	html.Write(5, "The <b>"+strconv.Itoa(len(parsedModel.ScannerFindings))+" imported scanner findings</b> confirm <b>"+
+		strconv.Itoa(confirmed)+" modeled risks</b> (findings of the same CWE at the most relevant technical asset of the risk), "+
+		"<b>"+strconv.Itoa(unmodeled)+" findings</b> are not covered by the modeled risks. "+
+		"The technical assets are listed by the highest severity of their findings and risks still at risk:<br>")
+
+	for _, exposure := range exposures {
+		if len(exposure.Findings) == 0 {
+			continue
+		}
+		if r.pdf.GetY() > 250 {
+			r.pageBreak()
+			r.pdf.SetY(36)
+		}
+		title := exposure.TechnicalAsset
+		if technicalAsset, ok := parsedModel.TechnicalAssets[exposure.TechnicalAsset]; ok {
+			title = technicalAsset.Title
+		}
+		r.pdf.SetFont(fontFamily, "", fontSizeBody)
+		r.pdfColorBlack()
+		html.Write(5, "<br><b>"+uni(title)+"</b>: "+strconv.Itoa(len(exposure.Findings))+" findings, "+
+			strconv.Itoa(len(exposure.Risks))+" risks still at risk, "+strconv.Itoa(len(exposure.ConfirmedRisks))+" of all modeled risks confirmed<br><br>")
+		for _, finding := range exposure.Findings {
+			if r.pdf.GetY() > 260 {
+				r.pageBreak()
+				r.pdf.SetY(36)
+			}
+			switch finding.Severity {
+			case types.CriticalSeverity:
+				colorCriticalRisk(r.pdf)
+			case types.HighSeverity:
+				colorHighRisk(r.pdf)
+			case types.ElevatedSeverity:
+				colorElevatedRisk(r.pdf)
+			case types.MediumSeverity:
+				colorMediumRisk(r.pdf)
+			default:
+				colorLowRisk(r.pdf)
+			}
+			cwe := ""
+			if finding.CWE > 0 {
+				cwe = "CWE-" + strconv.Itoa(finding.CWE)
+			}
+			r.pdf.SetFont(fontFamily, "", fontSizeSmall)
+			r.pdf.CellFormat(20, 5, finding.Severity.Title(), "0", 0, "", false, 0, "")
+			r.pdf.CellFormat(22, 5, cwe, "0", 0, "", false, 0, "")
+			r.pdf.MultiCell(140, 5, uni(finding.Title), "0", "0", false)
+			r.pdfColorGray()
+			r.pdf.SetFont(fontFamily, "", fontSizeVerySmall)
+			correlation := "not modeled"
+			if len(finding.CorrelatedRisks) > 0 {
+				correlation = "confirms " + strings.Join(finding.CorrelatedRisks, ", ")
+			}
+			details := []string{finding.Source, finding.ID, finding.Location, correlation}
+			nonEmpty := make([]string, 0, len(details))
+			for _, detail := range details {
+				if len(detail) > 0 {
+					nonEmpty = append(nonEmpty, detail)
+				}
+			}
+			r.pdf.CellFormat(42, 4, "", "0", 0, "", false, 0, "")
+			r.pdf.MultiCell(140, 4, uni(strings.Join(nonEmpty, " | ")), "0", "0", false)
+		}
+	}
+	r.pdf.SetFont(fontFamily, "", fontSizeBody)
+	r.pdfColorBlack()
+}
+
 func (r *pdfReporter) renderImpactAnalysis(parsedModel *types.Model, initialRisks bool) {
 	r.pdf.SetTextColor(0, 0, 0)
 	count, catCount := types.TotalRiskCount(parsedModel), len(parsedModel.GeneratedRisksByCategory)
@@ -1321,10 +1704,10 @@ func (r *pdfReporter) renderImpactAnalysis(parsedModel *types.Model, initialRisk
 		"(taking the severity ratings into account and using the highest for each category):<br>")
 	html.Write(5, strBuilder.String())
 	strBuilder.Reset()
-	r.pdf.SetFont("Helvetica", "", fontSizeSmall)
+	r.pdf.SetFont(fontFamily, "", fontSizeSmall)
 	r.pdfColorGray()
 	html.Write(5, "Risk finding paragraphs are clickable and link to the corresponding chapter.")
-	r.pdf.SetFont("Helvetica", "", fontSizeBody)
+	r.pdf.SetFont(fontFamily, "", fontSizeBody)
 
 	r.addCategories(parsedModel, types.GetRiskCategories(parsedModel, types.CategoriesOfOnlyCriticalRisks(parsedModel, parsedModel.GeneratedRisksByCategory, initialRisks)),
 		types.CriticalSeverity, false, initialRisks, true, false)
@@ -1342,7 +1725,7 @@ func (r *pdfReporter) renderImpactAnalysis(parsedModel *types.Model, initialRisk
 }
 
 func (r *pdfReporter) createOutOfScopeAssets(parsedModel *types.Model) {
-	uni := r.pdf.UnicodeTranslatorFromDescriptor("")
+	uni := keepUTF8
 	r.pdf.SetTextColor(0, 0, 0)
 	assets := "Assets"
 	count := len(parsedModel.OutOfScopeTechnicalAssets())
@@ -1361,10 +1744,10 @@ func (r *pdfReporter) createOutOfScopeAssets(parsedModel *types.Model) {
 		"overall risk analysis:<br>")
 	html.Write(5, strBuilder.String())
 	strBuilder.Reset()
-	r.pdf.SetFont("Helvetica", "", fontSizeSmall)
+	r.pdf.SetFont(fontFamily, "", fontSizeSmall)
 	r.pdfColorGray()
 	html.Write(5, "Technical asset paragraphs are clickable and link to the corresponding chapter.")
-	r.pdf.SetFont("Helvetica", "", fontSizeBody)
+	r.pdf.SetFont(fontFamily, "", fontSizeBody)
 
 	outOfScopeAssetCount := 0
 	for _, technicalAsset := range sortedTechnicalAssetsByRAAAndTitle(parsedModel) {
@@ -1438,10 +1821,10 @@ func (r *pdfReporter) createModelFailures(parsedModel *types.Model) {
 		"in the model against the architecture design:<br>")
 	html.Write(5, strBuilder.String())
 	strBuilder.Reset()
-	r.pdf.SetFont("Helvetica", "", fontSizeSmall)
+	r.pdf.SetFont(fontFamily, "", fontSizeSmall)
 	r.pdfColorGray()
 	html.Write(5, "Risk finding paragraphs are clickable and link to the corresponding chapter.")
-	r.pdf.SetFont("Helvetica", "", fontSizeBody)
+	r.pdf.SetFont(fontFamily, "", fontSizeBody)
 
 	modelFailuresByCategory := types.FilterByModelFailures(parsedModel, parsedModel.GeneratedRisksByCategory)
 	if len(modelFailuresByCategory) == 0 {
@@ -1465,7 +1848,7 @@ func (r *pdfReporter) createModelFailures(parsedModel *types.Model) {
 }
 
 func (r *pdfReporter) createRAA(parsedModel *types.Model, introTextRAA string) {
-	uni := r.pdf.UnicodeTranslatorFromDescriptor("")
+	uni := keepUTF8
 	r.pdf.SetTextColor(0, 0, 0)
 	chapTitle := "RAA Analysis"
 	r.addHeadline(chapTitle, false)
@@ -1478,10 +1861,10 @@ func (r *pdfReporter) createRAA(parsedModel *types.Model, introTextRAA string) {
 	strBuilder.WriteString("<br>")
 	html.Write(5, strBuilder.String())
 	strBuilder.Reset()
-	r.pdf.SetFont("Helvetica", "", fontSizeSmall)
+	r.pdf.SetFont(fontFamily, "", fontSizeSmall)
 	r.pdfColorGray()
 	html.Write(5, "Technical asset paragraphs are clickable and link to the corresponding chapter.")
-	r.pdf.SetFont("Helvetica", "", fontSizeBody)
+	r.pdf.SetFont(fontFamily, "", fontSizeBody)
 
 	for _, technicalAsset := range sortedTechnicalAssetsByRAAAndTitle(parsedModel) {
 		if technicalAsset.OutOfScope {
@@ -1537,7 +1920,7 @@ func (r *pdfReporter) createRAA(parsedModel *types.Model, introTextRAA string) {
 
 /*
 func createDataRiskQuickWins() {
-	uni := r.pdf.UnicodeTranslatorFromDescriptor("")
+	uni := keepUTF8
 	r.pdf.SetTextColor(0, 0, 0)
 	assets := "assets"
 	count := len(model.SortedTechnicalAssetsByQuickWinsAndTitle())
@@ -1558,10 +1941,10 @@ func createDataRiskQuickWins() {
 		"This list can be used to prioritize on efforts with the greatest effects of reducing data asset risks:<br>")
 	html.Write(5, strBuilder.String())
 	strBuilder.Reset()
-	r.pdf.SetFont("Helvetica", "", fontSizeSmall)
+	r.pdf.SetFont(fontFamily, "", fontSizeSmall)
 	r.pdfColorGray()
 	html.Write(5, "Technical asset paragraphs are clickable and link to the corresponding chapter.")
-	r.pdf.SetFont("Helvetica", "", fontSizeBody)
+	r.pdf.SetFont(fontFamily, "", fontSizeBody)
 
 	for _, technicalAsset := range model.SortedTechnicalAssetsByQuickWinsAndTitle() {
 		quickWins := technicalAsset.QuickWins()
@@ -1743,10 +2126,10 @@ func (r *pdfReporter) createAssignmentByFunction(parsedModel *types.Model) {
 	html := r.pdf.HTMLBasicNew()
 	html.Write(5, intro.String())
 	intro.Reset()
-	r.pdf.SetFont("Helvetica", "", fontSizeSmall)
+	r.pdf.SetFont(fontFamily, "", fontSizeSmall)
 	r.pdfColorGray()
 	html.Write(5, "Risk finding paragraphs are clickable and link to the corresponding chapter.")
-	r.pdf.SetFont("Helvetica", "", fontSizeBody)
+	r.pdf.SetFont(fontFamily, "", fontSizeBody)
 
 	oldLeft, _, _, _ := r.pdf.GetMargins()
 
@@ -1756,7 +2139,7 @@ func (r *pdfReporter) createAssignmentByFunction(parsedModel *types.Model) {
 	} else {
 		html.Write(5, "<br><br><br>")
 	}
-	r.pdf.SetFont("Helvetica", "", fontSizeBody)
+	r.pdf.SetFont(fontFamily, "", fontSizeBody)
 	r.pdf.SetTextColor(0, 0, 0)
 	html.Write(5, "<b>"+types.BusinessSide.Title()+"</b>")
 	r.pdf.SetLeftMargin(15)
@@ -1783,7 +2166,7 @@ func (r *pdfReporter) createAssignmentByFunction(parsedModel *types.Model) {
 	} else {
 		html.Write(5, "<br><br><br>")
 	}
-	r.pdf.SetFont("Helvetica", "", fontSizeBody)
+	r.pdf.SetFont(fontFamily, "", fontSizeBody)
 	r.pdf.SetTextColor(0, 0, 0)
 	html.Write(5, "<b>"+types.Architecture.Title()+"</b>")
 	r.pdf.SetLeftMargin(15)
@@ -1810,7 +2193,7 @@ func (r *pdfReporter) createAssignmentByFunction(parsedModel *types.Model) {
 	} else {
 		html.Write(5, "<br><br><br>")
 	}
-	r.pdf.SetFont("Helvetica", "", fontSizeBody)
+	r.pdf.SetFont(fontFamily, "", fontSizeBody)
 	r.pdf.SetTextColor(0, 0, 0)
 	html.Write(5, "<b>"+types.Development.Title()+"</b>")
 	r.pdf.SetLeftMargin(15)
@@ -1837,7 +2220,7 @@ func (r *pdfReporter) createAssignmentByFunction(parsedModel *types.Model) {
 	} else {
 		html.Write(5, "<br><br><br>")
 	}
-	r.pdf.SetFont("Helvetica", "", fontSizeBody)
+	r.pdf.SetFont(fontFamily, "", fontSizeBody)
 	r.pdf.SetTextColor(0, 0, 0)
 	html.Write(5, "<b>"+types.Operations.Title()+"</b>")
 	r.pdf.SetLeftMargin(15)
@@ -1894,10 +2277,10 @@ func (r *pdfReporter) createSTRIDE(parsedModel *types.Model) {
 	html := r.pdf.HTMLBasicNew()
 	html.Write(5, intro.String())
 	intro.Reset()
-	r.pdf.SetFont("Helvetica", "", fontSizeSmall)
+	r.pdf.SetFont(fontFamily, "", fontSizeSmall)
 	r.pdfColorGray()
 	html.Write(5, "Risk finding paragraphs are clickable and link to the corresponding chapter.")
-	r.pdf.SetFont("Helvetica", "", fontSizeBody)
+	r.pdf.SetFont(fontFamily, "", fontSizeBody)
 
 	oldLeft, _, _, _ := r.pdf.GetMargins()
 
@@ -1907,7 +2290,7 @@ func (r *pdfReporter) createSTRIDE(parsedModel *types.Model) {
 	} else {
 		html.Write(5, "<br><br><br>")
 	}
-	r.pdf.SetFont("Helvetica", "", fontSizeBody)
+	r.pdf.SetFont(fontFamily, "", fontSizeBody)
 	r.pdf.SetTextColor(0, 0, 0)
 	html.Write(5, "<b>"+types.Spoofing.Title()+"</b>")
 	r.pdf.SetLeftMargin(15)
@@ -1934,7 +2317,7 @@ func (r *pdfReporter) createSTRIDE(parsedModel *types.Model) {
 	} else {
 		html.Write(5, "<br><br><br>")
 	}
-	r.pdf.SetFont("Helvetica", "", fontSizeBody)
+	r.pdf.SetFont(fontFamily, "", fontSizeBody)
 	r.pdf.SetTextColor(0, 0, 0)
 	html.Write(5, "<b>"+types.Tampering.Title()+"</b>")
 	r.pdf.SetLeftMargin(15)
@@ -1961,7 +2344,7 @@ func (r *pdfReporter) createSTRIDE(parsedModel *types.Model) {
 	} else {
 		html.Write(5, "<br><br><br>")
 	}
-	r.pdf.SetFont("Helvetica", "", fontSizeBody)
+	r.pdf.SetFont(fontFamily, "", fontSizeBody)
 	r.pdf.SetTextColor(0, 0, 0)
 	html.Write(5, "<b>"+types.Repudiation.Title()+"</b>")
 	r.pdf.SetLeftMargin(15)
@@ -1988,7 +2371,7 @@ func (r *pdfReporter) createSTRIDE(parsedModel *types.Model) {
 	} else {
 		html.Write(5, "<br><br><br>")
 	}
-	r.pdf.SetFont("Helvetica", "", fontSizeBody)
+	r.pdf.SetFont(fontFamily, "", fontSizeBody)
 	r.pdf.SetTextColor(0, 0, 0)
 	html.Write(5, "<b>"+types.InformationDisclosure.Title()+"</b>")
 	r.pdf.SetLeftMargin(15)
@@ -2015,7 +2398,7 @@ func (r *pdfReporter) createSTRIDE(parsedModel *types.Model) {
 	} else {
 		html.Write(5, "<br><br><br>")
 	}
-	r.pdf.SetFont("Helvetica", "", fontSizeBody)
+	r.pdf.SetFont(fontFamily, "", fontSizeBody)
 	r.pdf.SetTextColor(0, 0, 0)
 	html.Write(5, "<b>"+types.DenialOfService.Title()+"</b>")
 	r.pdf.SetLeftMargin(15)
@@ -2042,7 +2425,7 @@ func (r *pdfReporter) createSTRIDE(parsedModel *types.Model) {
 	} else {
 		html.Write(5, "<br><br><br>")
 	}
-	r.pdf.SetFont("Helvetica", "", fontSizeBody)
+	r.pdf.SetFont(fontFamily, "", fontSizeBody)
 	r.pdf.SetTextColor(0, 0, 0)
 	html.Write(5, "<b>"+types.ElevationOfPrivilege.Title()+"</b>")
 	r.pdf.SetLeftMargin(15)
@@ -2067,8 +2450,67 @@ func (r *pdfReporter) createSTRIDE(parsedModel *types.Model) {
 	r.pdf.SetDashPattern([]float64{}, 0)
 }
 
+// createAssumptions lists what the threat model takes for granted and what it leaves out of its scope, each with the
+// owner accountable for it and the rationale
+func (r *pdfReporter) createAssumptions(parsedModel *types.Model) {
+	if len(parsedModel.Assumptions) == 0 && len(parsedModel.ScopeExclusions) == 0 {
+		return
+	}
+
+	uni := keepUTF8
+	r.pdf.SetTextColor(0, 0, 0)
+	chapTitle := "Assumptions and Scope Exclusions"
+	r.addHeadline(chapTitle, false)
+	r.defineLinkTarget("{assumptions}")
+	r.currentChapterTitleBreadcrumb = chapTitle
+
+	html := r.pdf.HTMLBasicNew()
+	html.Write(5, "This chapter lists the assumptions the threat model is based on and the parts deliberately left out of "+
+		"its scope. Risks arising where an assumption does not hold or in an excluded part are not covered by this report.")
+	r.pdfColorBlack()
+	for _, section := range []struct {
+		title       string
+		assumptions []*types.Assumption
+	}{
+		{title: "Assumptions", assumptions: parsedModel.SortedAssumptions()},
+		{title: "Scope Exclusions", assumptions: parsedModel.SortedScopeExclusions()},
+	} {
+		if len(section.assumptions) == 0 {
+			continue
+		}
+		if r.pdf.GetY() > 250 {
+			r.pageBreak()
+			r.pdf.SetY(36)
+		} else {
+			html.Write(5, "<br><br><br>")
+		}
+		html.Write(5, "<u><b>"+section.title+"</b></u>")
+		for _, assumption := range section.assumptions {
+			if r.pdf.GetY() > 250 {
+				r.pageBreak()
+				r.pdf.SetY(36)
+			} else {
+				html.Write(5, "<br><br>")
+			}
+			html.Write(5, "<b>"+uni(assumption.Title)+"</b>")
+			if len(assumption.Description) > 0 {
+				html.Write(5, "<br>"+uni(assumption.Description))
+			}
+			html.Write(5, "<br><i>Owner:</i> "+uni(assumption.Owner))
+			html.Write(5, "<br><i>Rationale:</i> "+uni(assumption.Rationale))
+			if len(assumption.TechnicalAssets) > 0 {
+				concerned := make([]string, 0)
+				for _, id := range assumption.TechnicalAssets {
+					concerned = append(concerned, parsedModel.TechnicalAssets[id].Title)
+				}
+				html.Write(5, "<br><i>Technical assets:</i> "+uni(strings.Join(concerned, ", ")))
+			}
+		}
+	}
+}
+
 func (r *pdfReporter) createSecurityRequirements(parsedModel *types.Model) {
-	uni := r.pdf.UnicodeTranslatorFromDescriptor("")
+	uni := keepUTF8
 	r.pdf.SetTextColor(0, 0, 0)
 	chapTitle := "Security Requirements"
 	r.addHeadline(chapTitle, false)
@@ -2099,6 +2541,117 @@ func (r *pdfReporter) createSecurityRequirements(parsedModel *types.Model) {
 		"taken into account as well. Also custom individual security requirements might exist for the project.</i>")
 }
 
+func (r *pdfReporter) createSecurityControls(parsedModel *types.Model) {
+	if len(parsedModel.SecurityControls) == 0 {
+		return
+	}
+
+	uni := keepUTF8
+	r.pdf.SetTextColor(0, 0, 0)
+	chapTitle := "Security Controls"
+	r.addHeadline(chapTitle, false)
+	r.defineLinkTarget("{security-controls}")
+	r.currentChapterTitleBreadcrumb = chapTitle
+
+	html := r.pdf.HTMLBasicNew()
+	html.Write(5, "This chapter lists the security controls which have been modeled for the target. Risks of the mitigated "+
+		"categories at protected technical assets have their exploitation likelihood lowered accordingly.")
+	r.pdfColorBlack()
+	for _, control := range parsedModel.SortedSecurityControls() {
+		if r.pdf.GetY() > 250 {
+			r.pageBreak()
+			r.pdf.SetY(36)
+		} else {
+			html.Write(5, "<br><br><br>")
+		}
+		html.Write(5, "<b>"+uni(control.Title)+"</b>")
+		if len(control.Type) > 0 {
+			html.Write(5, " ("+uni(control.Type)+")")
+		}
+		html.Write(5, "<br>"+uni(control.Description))
+
+		protected := make([]string, 0)
+		for _, id := range control.TechnicalAssets {
+			protected = append(protected, parsedModel.TechnicalAssets[id].Title)
+		}
+		for _, id := range control.TrustBoundaries {
+			protected = append(protected, parsedModel.TrustBoundaries[id].Title)
+		}
+		if len(protected) > 0 {
+			html.Write(5, "<br><i>Protects:</i> "+uni(strings.Join(protected, ", ")))
+		}
+		if len(control.Mitigates) > 0 {
+			html.Write(5, "<br><i>Mitigates:</i> "+uni(strings.Join(control.Mitigates, ", "))+
+				" (likelihood reduced by "+strconv.Itoa(control.LikelihoodReduction)+" level(s))")
+		}
+	}
+}
+
+// createPenTestFindings lists the findings of penetration tests with the modeled risks they confirmed or did not confirm
+func (r *pdfReporter) createPenTestFindings(parsedModel *types.Model) {
+	if len(parsedModel.PenTestFindings) == 0 {
+		return
+	}
+
+	uni := keepUTF8
+	r.pdf.SetTextColor(0, 0, 0)
+	chapTitle := "Pen-Test Findings"
+	r.addHeadline(chapTitle, false)
+	r.defineLinkTarget("{pen-test-findings}")
+	r.currentChapterTitleBreadcrumb = chapTitle
+
+	html := r.pdf.HTMLBasicNew()
+	html.Write(5, "This chapter lists the findings of penetration tests linked to the model as validation evidence: "+
+		"<b>confirmed</b> findings prove the covered risks exploitable, <b>not confirmed</b> ones document risks the testers "+
+		"tried but were not able to exploit.")
+	r.pdfColorBlack()
+	for _, finding := range parsedModel.SortedPenTestFindings() {
+		if r.pdf.GetY() > 250 {
+			r.pageBreak()
+			r.pdf.SetY(36)
+		} else {
+			html.Write(5, "<br><br><br>")
+		}
+		html.Write(5, "<b>"+uni(finding.Title)+"</b> ("+strings.ReplaceAll(finding.Result(), "-", " ")+", "+finding.Severity.String()+")")
+		html.Write(5, "<br>"+uni(finding.Description))
+
+		details := make([]string, 0)
+		if !finding.Date.IsZero() {
+			details = append(details, finding.Date.Format("2006-01-02"))
+		}
+		if len(finding.Tester) > 0 {
+			details = append(details, finding.Tester)
+		}
+		if len(finding.Reference) > 0 {
+			details = append(details, finding.Reference)
+		}
+		if len(details) > 0 {
+			html.Write(5, "<br><i>Test:</i> "+uni(strings.Join(details, ", ")))
+		}
+		if len(finding.TechnicalAssets) > 0 {
+			covered := make([]string, 0)
+			for _, id := range finding.TechnicalAssets {
+				covered = append(covered, parsedModel.TechnicalAssets[id].Title)
+			}
+			html.Write(5, "<br><i>Technical assets:</i> "+uni(strings.Join(covered, ", ")))
+		}
+		html.Write(5, "<br><i>Risk categories:</i> "+uni(strings.Join(finding.RiskCategories, ", ")))
+
+		risks := make([]string, 0)
+		for _, risk := range types.AllRisks(parsedModel) {
+			if finding.Covers(risk) {
+				risks = append(risks, risk.SyntheticId)
+			}
+		}
+		sort.Strings(risks)
+		if len(risks) == 0 {
+			html.Write(5, "<br><i>Modeled risks:</i> none (the model does not contain the tested risks)")
+		} else {
+			html.Write(5, "<br><i>Modeled risks "+strings.ReplaceAll(finding.Result(), "-", " ")+":</i> "+uni(strings.Join(risks, ", ")))
+		}
+	}
+}
+
 func sortedKeysOfSecurityRequirements(parsedModel *types.Model) []string {
 	keys := make([]string, 0)
 	for k := range parsedModel.SecurityRequirements {
@@ -2149,7 +2702,7 @@ func sortedKeysOfAbuseCases(parsedModel *types.Model) []string {
 }
 
 func (r *pdfReporter) createQuestions(parsedModel *types.Model) {
-	uni := r.pdf.UnicodeTranslatorFromDescriptor("")
+	uni := keepUTF8
 	r.pdf.SetTextColor(0, 0, 0)
 	questions := "Questions"
 	count := len(parsedModel.Questions)
@@ -2292,7 +2845,7 @@ func sortedTechnicalAssetsByTitle(parsedModel *types.Model) []*types.TechnicalAs
 }
 
 func (r *pdfReporter) createRiskCategories(parsedModel *types.Model) {
-	uni := r.pdf.UnicodeTranslatorFromDescriptor("")
+	uni := keepUTF8
 	// category title
 	title := "Identified Risks by Vulnerability category"
 	r.pdfColorBlack()
@@ -2413,10 +2966,10 @@ func (r *pdfReporter) createRiskCategories(parsedModel *types.Model) {
 			"controls have been applied properly in order to mitigate each risk.<br>")
 		html.Write(5, text.String())
 		text.Reset()
-		r.pdf.SetFont("Helvetica", "", fontSizeSmall)
+		r.pdf.SetFont(fontFamily, "", fontSizeSmall)
 		r.pdfColorGray()
 		html.Write(5, "Risk finding paragraphs are clickable and link to the corresponding chapter.<br>")
-		r.pdf.SetFont("Helvetica", "", fontSizeBody)
+		r.pdf.SetFont(fontFamily, "", fontSizeBody)
 		oldLeft, _, _, _ := r.pdf.GetMargins()
 		headlineCriticalWritten, headlineHighWritten, headlineElevatedWritten, headlineMediumWritten, headlineLowWritten := false, false, false, false, false
 		for _, risk := range risksStr {
@@ -2431,7 +2984,7 @@ func (r *pdfReporter) createRiskCategories(parsedModel *types.Model) {
 			case types.CriticalSeverity:
 				colorCriticalRisk(r.pdf)
 				if !headlineCriticalWritten {
-					r.pdf.SetFont("Helvetica", "", fontSizeBody)
+					r.pdf.SetFont(fontFamily, "", fontSizeBody)
 					r.pdf.SetLeftMargin(oldLeft)
 					text.WriteString("<br><b><i>Critical Risk Severity</i></b><br><br>")
 					html.Write(5, text.String())
@@ -2441,7 +2994,7 @@ func (r *pdfReporter) createRiskCategories(parsedModel *types.Model) {
 			case types.HighSeverity:
 				colorHighRisk(r.pdf)
 				if !headlineHighWritten {
-					r.pdf.SetFont("Helvetica", "", fontSizeBody)
+					r.pdf.SetFont(fontFamily, "", fontSizeBody)
 					r.pdf.SetLeftMargin(oldLeft)
 					text.WriteString("<br><b><i>High Risk Severity</i></b><br><br>")
 					html.Write(5, text.String())
@@ -2451,7 +3004,7 @@ func (r *pdfReporter) createRiskCategories(parsedModel *types.Model) {
 			case types.ElevatedSeverity:
 				colorElevatedRisk(r.pdf)
 				if !headlineElevatedWritten {
-					r.pdf.SetFont("Helvetica", "", fontSizeBody)
+					r.pdf.SetFont(fontFamily, "", fontSizeBody)
 					r.pdf.SetLeftMargin(oldLeft)
 					text.WriteString("<br><b><i>Elevated Risk Severity</i></b><br><br>")
 					html.Write(5, text.String())
@@ -2461,7 +3014,7 @@ func (r *pdfReporter) createRiskCategories(parsedModel *types.Model) {
 			case types.MediumSeverity:
 				colorMediumRisk(r.pdf)
 				if !headlineMediumWritten {
-					r.pdf.SetFont("Helvetica", "", fontSizeBody)
+					r.pdf.SetFont(fontFamily, "", fontSizeBody)
 					r.pdf.SetLeftMargin(oldLeft)
 					text.WriteString("<br><b><i>Medium Risk Severity</i></b><br><br>")
 					html.Write(5, text.String())
@@ -2471,7 +3024,7 @@ func (r *pdfReporter) createRiskCategories(parsedModel *types.Model) {
 			case types.LowSeverity:
 				colorLowRisk(r.pdf)
 				if !headlineLowWritten {
-					r.pdf.SetFont("Helvetica", "", fontSizeBody)
+					r.pdf.SetFont(fontFamily, "", fontSizeBody)
 					r.pdf.SetLeftMargin(oldLeft)
 					text.WriteString("<br><b><i>Low Risk Severity</i></b><br><br>")
 					html.Write(5, text.String())
@@ -2486,15 +3039,18 @@ func (r *pdfReporter) createRiskCategories(parsedModel *types.Model) {
 			}
 			posY := r.pdf.GetY()
 			r.pdf.SetLeftMargin(oldLeft + 10)
-			r.pdf.SetFont("Helvetica", "", fontSizeBody)
+			r.pdf.SetFont(fontFamily, "", fontSizeBody)
 			text.WriteString(uni(risk.Title) + ": Exploitation likelihood is <i>" + risk.ExploitationLikelihood.Title() + "</i> with <i>" + risk.ExploitationImpact.Title() + "</i> impact.")
 			text.WriteString("<br>")
 			html.Write(5, text.String())
 			text.Reset()
 			r.pdfColorGray()
-			r.pdf.SetFont("Helvetica", "", fontSizeVerySmall)
+			r.pdf.SetFont(fontFamily, "", fontSizeVerySmall)
 			r.pdf.MultiCell(215, 5, uni(risk.SyntheticId), "0", "0", false)
-			r.pdf.SetFont("Helvetica", "", fontSizeBody)
+			r.writeMergedRisks(risk)
+			r.writePenTestValidation(parsedModel, risk)
+			r.writeRiskComments(risk)
+			r.pdf.SetFont(fontFamily, "", fontSizeBody)
 			if len(risk.MostRelevantSharedRuntimeId) > 0 {
 				r.pdf.Link(20, posY, 180, r.pdf.GetY()-posY, r.tocLinkIdByAssetId[risk.MostRelevantSharedRuntimeId])
 			} else if len(risk.MostRelevantTrustBoundaryId) > 0 {
@@ -2511,8 +3067,33 @@ func (r *pdfReporter) createRiskCategories(parsedModel *types.Model) {
 	}
 }
 
+func (r *pdfReporter) writeMergedRisks(risk *types.Risk) {
+	if len(risk.MergedRiskIds) == 0 {
+		return
+	}
+	uni := keepUTF8
+	r.pdf.MultiCell(215, 5, uni("merged duplicates: "+strings.Join(risk.MergedRiskIds, ", ")), "0", "0", false)
+}
+
+func (r *pdfReporter) writePenTestValidation(parsedModel *types.Model, risk *types.Risk) {
+	validation := risk.PenTestValidation(parsedModel)
+	if len(validation) == 0 {
+		return
+	}
+	uni := keepUTF8
+	r.pdf.MultiCell(215, 5, uni("pen-test: "+validation+" ("+strings.Join(risk.PenTestFindings, ", ")+")"), "0", "0", false)
+}
+
+func (r *pdfReporter) writeRiskComments(risk *types.Risk) {
+	if len(risk.Comments) == 0 {
+		return
+	}
+	uni := keepUTF8
+	r.pdf.MultiCell(215, 5, uni("comments:\n"+strings.Join(riskCommentLines(risk), "\n")), "0", "0", false)
+}
+
 func (r *pdfReporter) writeRiskTrackingStatus(parsedModel *types.Model, risk *types.Risk) {
-	uni := r.pdf.UnicodeTranslatorFromDescriptor("")
+	uni := keepUTF8
 	tracking := risk.GetRiskTrackingWithDefault(parsedModel)
 	r.pdfColorBlack()
 	r.pdf.CellFormat(10, 6, "", "0", 0, "", false, 0, "")
@@ -2532,9 +3113,9 @@ func (r *pdfReporter) writeRiskTrackingStatus(parsedModel *types.Model, risk *ty
 	default:
 		r.pdfColorBlack()
 	}
-	r.pdf.SetFont("Helvetica", "", fontSizeSmall)
+	r.pdf.SetFont(fontFamily, "", fontSizeSmall)
 	if tracking.Status == types.Unchecked {
-		r.pdf.SetFont("Helvetica", "B", fontSizeSmall)
+		r.pdf.SetFont(fontFamily, "B", fontSizeSmall)
 	}
 	r.pdf.CellFormat(25, 4, tracking.Status.Title(), "0", 0, "B", false, 0, "")
 	if tracking.Status != types.Unchecked {
@@ -2551,15 +3132,35 @@ func (r *pdfReporter) writeRiskTrackingStatus(parsedModel *types.Model, risk *ty
 		r.pdfColorBlack()
 		r.pdf.CellFormat(10, 4, "", "0", 0, "", false, 0, "")
 		r.pdf.MultiCell(170, 4, uni(justificationStr), "0", "0", false)
-		r.pdf.SetFont("Helvetica", "", fontSizeBody)
+		r.pdf.SetFont(fontFamily, "", fontSizeBody)
 	} else {
 		r.pdf.Ln(-1)
 	}
+	if len(tracking.Assignee) > 0 || !tracking.DueDate.IsZero() {
+		assignment := "assigned to " + tracking.Assignee
+		if len(tracking.Assignee) == 0 {
+			assignment = "unassigned"
+		}
+		if !tracking.DueDate.IsZero() {
+			assignment += ", due " + tracking.DueDate.Format("2006-01-02")
+		}
+		r.pdf.SetFont(fontFamily, "", fontSizeSmall)
+		r.pdfColorGray()
+		r.pdf.CellFormat(10, 4, "", "0", 0, "", false, 0, "")
+		r.pdf.CellFormat(90, 4, uni(assignment), "0", 0, "", false, 0, "")
+		if tracking.IsOverdue(time.Now()) {
+			r.pdf.SetFont(fontFamily, "B", fontSizeSmall)
+			colorRiskStatusUnchecked(r.pdf)
+			r.pdf.CellFormat(20, 4, "overdue", "0", 0, "", false, 0, "")
+		}
+		r.pdf.Ln(-1)
+		r.pdf.SetFont(fontFamily, "", fontSizeBody)
+	}
 	r.pdfColorBlack()
 }
 
 func (r *pdfReporter) createTechnicalAssets(parsedModel *types.Model) {
-	uni := r.pdf.UnicodeTranslatorFromDescriptor("")
+	uni := keepUTF8
 	// category title
 	title := "Identified Risks by Technical Asset"
 	r.pdfColorBlack()
@@ -2634,15 +3235,15 @@ func (r *pdfReporter) createTechnicalAssets(parsedModel *types.Model) {
 			r.pageBreak()
 			r.pdf.SetY(36)
 		}
-		r.pdf.SetFont("Helvetica", "B", fontSizeBody)
+		r.pdf.SetFont(fontFamily, "B", fontSizeBody)
 		r.pdfColorBlack()
 		r.pdf.CellFormat(190, 6, "Identified Risks of Asset", "0", 0, "", false, 0, "")
 		r.pdfColorGray()
 		oldLeft, _, _, _ := r.pdf.GetMargins()
 		if len(risksStr) > 0 {
-			r.pdf.SetFont("Helvetica", "", fontSizeSmall)
+			r.pdf.SetFont(fontFamily, "", fontSizeSmall)
 			html.Write(5, "Risk finding paragraphs are clickable and link to the corresponding chapter.")
-			r.pdf.SetFont("Helvetica", "", fontSizeBody)
+			r.pdf.SetFont(fontFamily, "", fontSizeBody)
 			r.pdf.SetLeftMargin(15)
 			/*
 				r.pdf.Ln(-1)
@@ -2665,7 +3266,7 @@ func (r *pdfReporter) createTechnicalAssets(parsedModel *types.Model) {
 				case types.CriticalSeverity:
 					colorCriticalRisk(r.pdf)
 					if !headlineCriticalWritten {
-						r.pdf.SetFont("Helvetica", "", fontSizeBody)
+						r.pdf.SetFont(fontFamily, "", fontSizeBody)
 						r.pdf.SetLeftMargin(oldLeft + 3)
 						html.Write(5, "<br><b><i>Critical Risk Severity</i></b><br><br>")
 						headlineCriticalWritten = true
@@ -2673,7 +3274,7 @@ func (r *pdfReporter) createTechnicalAssets(parsedModel *types.Model) {
 				case types.HighSeverity:
 					colorHighRisk(r.pdf)
 					if !headlineHighWritten {
-						r.pdf.SetFont("Helvetica", "", fontSizeBody)
+						r.pdf.SetFont(fontFamily, "", fontSizeBody)
 						r.pdf.SetLeftMargin(oldLeft + 3)
 						html.Write(5, "<br><b><i>High Risk Severity</i></b><br><br>")
 						headlineHighWritten = true
@@ -2681,7 +3282,7 @@ func (r *pdfReporter) createTechnicalAssets(parsedModel *types.Model) {
 				case types.ElevatedSeverity:
 					colorElevatedRisk(r.pdf)
 					if !headlineElevatedWritten {
-						r.pdf.SetFont("Helvetica", "", fontSizeBody)
+						r.pdf.SetFont(fontFamily, "", fontSizeBody)
 						r.pdf.SetLeftMargin(oldLeft + 3)
 						html.Write(5, "<br><b><i>Elevated Risk Severity</i></b><br><br>")
 						headlineElevatedWritten = true
@@ -2689,7 +3290,7 @@ func (r *pdfReporter) createTechnicalAssets(parsedModel *types.Model) {
 				case types.MediumSeverity:
 					colorMediumRisk(r.pdf)
 					if !headlineMediumWritten {
-						r.pdf.SetFont("Helvetica", "", fontSizeBody)
+						r.pdf.SetFont(fontFamily, "", fontSizeBody)
 						r.pdf.SetLeftMargin(oldLeft + 3)
 						html.Write(5, "<br><b><i>Medium Risk Severity</i></b><br><br>")
 						headlineMediumWritten = true
@@ -2697,7 +3298,7 @@ func (r *pdfReporter) createTechnicalAssets(parsedModel *types.Model) {
 				case types.LowSeverity:
 					colorLowRisk(r.pdf)
 					if !headlineLowWritten {
-						r.pdf.SetFont("Helvetica", "", fontSizeBody)
+						r.pdf.SetFont(fontFamily, "", fontSizeBody)
 						r.pdf.SetLeftMargin(oldLeft + 3)
 						html.Write(5, "<br><b><i>Low Risk Severity</i></b><br><br>")
 						headlineLowWritten = true
@@ -2710,17 +3311,20 @@ func (r *pdfReporter) createTechnicalAssets(parsedModel *types.Model) {
 				}
 				posY := r.pdf.GetY()
 				r.pdf.SetLeftMargin(oldLeft + 10)
-				r.pdf.SetFont("Helvetica", "", fontSizeBody)
+				r.pdf.SetFont(fontFamily, "", fontSizeBody)
 				text.WriteString(uni(risk.Title) + ": Exploitation likelihood is <i>" + risk.ExploitationLikelihood.Title() + "</i> with <i>" + risk.ExploitationImpact.Title() + "</i> impact.")
 				text.WriteString("<br>")
 				html.Write(5, text.String())
 				text.Reset()
 
-				r.pdf.SetFont("Helvetica", "", fontSizeVerySmall)
+				r.pdf.SetFont(fontFamily, "", fontSizeVerySmall)
 				r.pdfColorGray()
 				r.pdf.MultiCell(215, 5, uni(risk.SyntheticId), "0", "0", false)
+				r.writeMergedRisks(risk)
+				r.writePenTestValidation(parsedModel, risk)
+				r.writeRiskComments(risk)
 				r.pdf.Link(20, posY, 180, r.pdf.GetY()-posY, r.tocLinkIdByAssetId[risk.CategoryId])
-				r.pdf.SetFont("Helvetica", "", fontSizeBody)
+				r.pdf.SetFont(fontFamily, "", fontSizeBody)
 				r.writeRiskTrackingStatus(parsedModel, risk)
 				r.pdf.SetLeftMargin(oldLeft)
 			}
@@ -2728,7 +3332,7 @@ func (r *pdfReporter) createTechnicalAssets(parsedModel *types.Model) {
 			r.pdf.Ln(-1)
 			r.pdf.Ln(-1)
 			r.pdfColorGray()
-			r.pdf.SetFont("Helvetica", "", fontSizeBody)
+			r.pdf.SetFont(fontFamily, "", fontSizeBody)
 			r.pdf.SetLeftMargin(15)
 			text := "No risksStr were identified."
 			if technicalAsset.OutOfScope {
@@ -2746,11 +3350,11 @@ func (r *pdfReporter) createTechnicalAssets(parsedModel *types.Model) {
 			r.pdf.SetY(36)
 		}
 		r.pdfColorBlack()
-		r.pdf.SetFont("Helvetica", "B", fontSizeBody)
+		r.pdf.SetFont(fontFamily, "B", fontSizeBody)
 		r.pdf.CellFormat(190, 6, "Asset Information", "0", 0, "", false, 0, "")
 		r.pdf.Ln(-1)
 		r.pdf.Ln(-1)
-		r.pdf.SetFont("Helvetica", "", fontSizeBody)
+		r.pdf.SetFont(fontFamily, "", fontSizeBody)
 		r.pdfColorGray()
 		r.pdf.CellFormat(5, 6, "", "0", 0, "", false, 0, "")
 		r.pdf.CellFormat(40, 6, "ID:", "0", 0, "", false, 0, "")
@@ -2950,16 +3554,16 @@ func (r *pdfReporter) createTechnicalAssets(parsedModel *types.Model) {
 			r.pdf.SetY(36)
 		}
 		r.pdfColorBlack()
-		r.pdf.SetFont("Helvetica", "B", fontSizeBody)
+		r.pdf.SetFont(fontFamily, "B", fontSizeBody)
 		r.pdf.CellFormat(190, 6, "Asset Rating", "0", 0, "", false, 0, "")
 		r.pdf.Ln(-1)
 		r.pdf.Ln(-1)
-		r.pdf.SetFont("Helvetica", "", fontSizeBody)
+		r.pdf.SetFont(fontFamily, "", fontSizeBody)
 		r.pdfColorGray()
 		r.pdf.CellFormat(5, 6, "", "0", 0, "", false, 0, "")
 		r.pdf.CellFormat(40, 6, "Owner:", "0", 0, "", false, 0, "")
 		r.pdfColorBlack()
-		r.pdf.MultiCell(145, 6, uni(technicalAsset.Owner), "0", "0", false)
+		r.pdf.MultiCell(145, 6, uni(parsedModel.OwnerWithContact(technicalAsset.Owner)), "0", "0", false)
 		if r.pdf.GetY() > 270 {
 			r.pageBreak()
 			r.pdf.SetY(36)
@@ -3009,6 +3613,10 @@ func (r *pdfReporter) createTechnicalAssets(parsedModel *types.Model) {
 		r.pdfColorBlack()
 		r.pdf.MultiCell(145, 6, uni(technicalAsset.JustificationCiaRating), "0", "0", false)
 
+		if len(technicalAsset.SBOM) > 0 {
+			r.writeSBOMOfTechnicalAsset(technicalAsset)
+		}
+
 		if technicalAsset.OutOfScope {
 			r.pdf.Ln(-1)
 			r.pdf.Ln(4)
@@ -3017,11 +3625,11 @@ func (r *pdfReporter) createTechnicalAssets(parsedModel *types.Model) {
 				r.pdf.SetY(36)
 			}
 			r.pdfColorBlack()
-			r.pdf.SetFont("Helvetica", "B", fontSizeBody)
+			r.pdf.SetFont(fontFamily, "B", fontSizeBody)
 			r.pdf.CellFormat(190, 6, "Asset Out-of-Scope Justification", "0", 0, "", false, 0, "")
 			r.pdf.Ln(-1)
 			r.pdf.Ln(-1)
-			r.pdf.SetFont("Helvetica", "", fontSizeBody)
+			r.pdf.SetFont(fontFamily, "", fontSizeBody)
 			r.pdf.MultiCell(190, 6, uni(technicalAsset.JustificationOutOfScope), "0", "0", false)
 			r.pdf.Ln(-1)
 		}
@@ -3034,15 +3642,15 @@ func (r *pdfReporter) createTechnicalAssets(parsedModel *types.Model) {
 				r.pdf.SetY(36)
 			}
 			r.pdfColorBlack()
-			r.pdf.SetFont("Helvetica", "B", fontSizeBody)
+			r.pdf.SetFont(fontFamily, "B", fontSizeBody)
 			r.pdf.CellFormat(190, 6, "Outgoing Communication Links: "+strconv.Itoa(len(technicalAsset.CommunicationLinks)), "0", 0, "", false, 0, "")
-			r.pdf.SetFont("Helvetica", "", fontSizeSmall)
+			r.pdf.SetFont(fontFamily, "", fontSizeSmall)
 			r.pdfColorGray()
 			html.Write(5, "Target technical asset names are clickable and link to the corresponding chapter.")
-			r.pdf.SetFont("Helvetica", "", fontSizeBody)
+			r.pdf.SetFont(fontFamily, "", fontSizeBody)
 			r.pdf.Ln(-1)
 			r.pdf.Ln(-1)
-			r.pdf.SetFont("Helvetica", "", fontSizeBody)
+			r.pdf.SetFont(fontFamily, "", fontSizeBody)
 			for _, outgoingCommLink := range technicalAsset.CommunicationLinksSorted() {
 				if r.pdf.GetY() > 270 {
 					r.pageBreak()
@@ -3204,15 +3812,15 @@ func (r *pdfReporter) createTechnicalAssets(parsedModel *types.Model) {
 				r.pdf.SetY(36)
 			}
 			r.pdfColorBlack()
-			r.pdf.SetFont("Helvetica", "B", fontSizeBody)
+			r.pdf.SetFont(fontFamily, "B", fontSizeBody)
 			r.pdf.CellFormat(190, 6, "Incoming Communication Links: "+strconv.Itoa(len(incomingCommLinks)), "0", 0, "", false, 0, "")
-			r.pdf.SetFont("Helvetica", "", fontSizeSmall)
+			r.pdf.SetFont(fontFamily, "", fontSizeSmall)
 			r.pdfColorGray()
 			html.Write(5, "Source technical asset names are clickable and link to the corresponding chapter.")
-			r.pdf.SetFont("Helvetica", "", fontSizeBody)
+			r.pdf.SetFont(fontFamily, "", fontSizeBody)
 			r.pdf.Ln(-1)
 			r.pdf.Ln(-1)
-			r.pdf.SetFont("Helvetica", "", fontSizeBody)
+			r.pdf.SetFont(fontFamily, "", fontSizeBody)
 			for _, incomingCommLink := range incomingCommLinks {
 				if r.pdf.GetY() > 270 {
 					r.pageBreak()
@@ -3370,8 +3978,58 @@ func (r *pdfReporter) createTechnicalAssets(parsedModel *types.Model) {
 	}
 }
 
+// writeSBOMOfTechnicalAsset summarizes the components of the SBOM, listing the ones with known vulnerabilities
+func (r *pdfReporter) writeSBOMOfTechnicalAsset(technicalAsset *types.TechnicalAsset) {
+	uni := keepUTF8
+	r.pdf.Ln(-1)
+	r.pdf.Ln(4)
+	if r.pdf.GetY() > 260 { // 260 only for major titles (to avoid "Schusterjungen"), for the rest attributes 270
+		r.pageBreak()
+		r.pdf.SetY(36)
+	}
+	r.pdfColorBlack()
+	r.pdf.SetFont(fontFamily, "B", fontSizeBody)
+	r.pdf.CellFormat(190, 6, "Software Bill of Materials", "0", 0, "", false, 0, "")
+	r.pdf.Ln(-1)
+	r.pdf.Ln(-1)
+	r.pdf.SetFont(fontFamily, "", fontSizeBody)
+	r.pdfColorGray()
+	r.pdf.CellFormat(5, 6, "", "0", 0, "", false, 0, "")
+	r.pdf.CellFormat(40, 6, "SBOM:", "0", 0, "", false, 0, "")
+	r.pdfColorBlack()
+	r.pdf.MultiCell(145, 6, uni(technicalAsset.SBOM), "0", "0", false)
+
+	vulnerableComponents := technicalAsset.VulnerableSBOMComponents()
+	r.pdfColorGray()
+	r.pdf.CellFormat(5, 6, "", "0", 0, "", false, 0, "")
+	r.pdf.CellFormat(40, 6, "Components:", "0", 0, "", false, 0, "")
+	r.pdfColorBlack()
+	r.pdf.MultiCell(145, 6, fmt.Sprintf("%d components, %d of them with known vulnerabilities", len(technicalAsset.SBOMComponents), len(vulnerableComponents)), "0", "0", false)
+
+	for i, component := range vulnerableComponents {
+		if r.pdf.GetY() > 270 {
+			r.pageBreak()
+			r.pdf.SetY(36)
+		}
+		label := ""
+		if i == 0 {
+			label = "Vulnerable:"
+		}
+		vulnerabilities := make([]string, 0, len(component.Vulnerabilities))
+		for _, vulnerability := range component.Vulnerabilities {
+			vulnerabilities = append(vulnerabilities, vulnerability.ID+" ("+vulnerability.Severity+")")
+		}
+		r.pdfColorGray()
+		r.pdf.CellFormat(5, 6, "", "0", 0, "", false, 0, "")
+		r.pdf.CellFormat(40, 6, label, "0", 0, "", false, 0, "")
+		colorHighRisk(r.pdf)
+		r.pdf.MultiCell(145, 6, uni(component.String()+": "+strings.Join(vulnerabilities, ", ")), "0", "0", false)
+	}
+	r.pdfColorBlack()
+}
+
 func (r *pdfReporter) createDataAssets(parsedModel *types.Model) {
-	uni := r.pdf.UnicodeTranslatorFromDescriptor("")
+	uni := keepUTF8
 	title := "Identified Data Breach Probabilities by Data Asset"
 	r.pdfColorBlack()
 	r.addHeadline(title, false)
@@ -3386,10 +4044,10 @@ func (r *pdfReporter) createDataAssets(parsedModel *types.Model) {
 		"and <b>"+strconv.Itoa(len(types.FilteredByOnlyLowRisks(parsedModel)))+" as low</b>. "+
 		"<br><br>These risks are distributed across <b>"+strconv.Itoa(len(parsedModel.DataAssets))+" data assets</b>. ")
 	html.Write(5, "The following sub-chapters of this section describe the derived data breach probabilities grouped by data asset.<br>") // TODO more explanation text
-	r.pdf.SetFont("Helvetica", "", fontSizeSmall)
+	r.pdf.SetFont(fontFamily, "", fontSizeSmall)
 	r.pdfColorGray()
 	html.Write(5, "Technical asset names and risk IDs are clickable and link to the corresponding chapter.")
-	r.pdf.SetFont("Helvetica", "", fontSizeBody)
+	r.pdf.SetFont(fontFamily, "", fontSizeBody)
 	r.currentChapterTitleBreadcrumb = title
 	for _, dataAsset := range sortedDataAssetsByDataBreachProbabilityAndTitle(parsedModel) {
 		if r.pdf.GetY() > 280 { // 280 as only small font previously (not 250)
@@ -3425,13 +4083,13 @@ func (r *pdfReporter) createDataAssets(parsedModel *types.Model) {
 		html.Write(5, uni(dataAsset.Description))
 		html.Write(5, "<br><br>")
 
-		r.pdf.SetFont("Helvetica", "", fontSizeBody)
+		r.pdf.SetFont(fontFamily, "", fontSizeBody)
 		/*
 			r.pdfColorGray()
 			r.pdf.CellFormat(5, 6, "", "0", 0, "", false, 0, "")
 			r.pdf.CellFormat(40, 6, "Indirect Breach:", "0", 0, "", false, 0, "")
 			r.pdfColorBlack()
-			r.pdf.SetFont("Helvetica", "B", fontSizeBody)
+			r.pdf.SetFont(fontFamily, "B", fontSizeBody)
 			probability := dataAsset.IdentifiedDataBreachProbability()
 			dataBreachText := probability.String()
 			switch probability {
@@ -3449,7 +4107,7 @@ func (r *pdfReporter) createDataAssets(parsedModel *types.Model) {
 				dataBreachText = "none"
 			}
 			r.pdf.MultiCell(145, 6, dataBreachText, "0", "0", false)
-			r.pdf.SetFont("Helvetica", "", fontSizeBody)
+			r.pdf.SetFont(fontFamily, "", fontSizeBody)
 			if r.pdf.GetY() > 265 {
 				r.pageBreak()
 				r.pdf.SetY(36)
@@ -3517,7 +4175,7 @@ func (r *pdfReporter) createDataAssets(parsedModel *types.Model) {
 		r.pdf.CellFormat(5, 6, "", "0", 0, "", false, 0, "")
 		r.pdf.CellFormat(40, 6, "Owner:", "0", 0, "", false, 0, "")
 		r.pdfColorBlack()
-		r.pdf.MultiCell(145, 6, uni(dataAsset.Owner), "0", "0", false)
+		r.pdf.MultiCell(145, 6, uni(parsedModel.OwnerWithContact(dataAsset.Owner)), "0", "0", false)
 		if r.pdf.GetY() > 265 {
 			r.pageBreak()
 			r.pdf.SetY(36)
@@ -3703,9 +4361,9 @@ func (r *pdfReporter) createDataAssets(parsedModel *types.Model) {
 					posY := r.pdf.GetY()
 					risksResponsible := techAssetResponsible.GeneratedRisks()
 					risksResponsibleStillAtRisk := model.ReduceToOnlyStillAtRisk(risksResponsible)
-					r.pdf.SetFont("Helvetica", "", fontSizeSmall)
+					r.pdf.SetFont(fontFamily, "", fontSizeSmall)
 					r.pdf.MultiCell(185, 6, uni(techAssetResponsible.Title)+": "+strconv.Itoa(len(risksResponsibleStillAtRisk))+" / "+strconv.Itoa(len(risksResponsible))+" "+riskStr, "0", "0", false)
-					r.pdf.SetFont("Helvetica", "", fontSizeBody)
+					r.pdf.SetFont(fontFamily, "", fontSizeBody)
 					r.pdf.Link(20, posY, 180, r.pdf.GetY()-posY, tocLinkIdByAssetId[techAssetResponsible.ID])
 				}
 				r.pdfColorBlack()
@@ -3716,7 +4374,7 @@ func (r *pdfReporter) createDataAssets(parsedModel *types.Model) {
 		r.pdf.CellFormat(5, 6, "", "0", 0, "", false, 0, "")
 		r.pdf.CellFormat(40, 6, "Data Breach:", "0", 0, "", false, 0, "")
 		r.pdfColorBlack()
-		r.pdf.SetFont("Helvetica", "B", fontSizeBody)
+		r.pdf.SetFont(fontFamily, "B", fontSizeBody)
 		dataBreachProbability := dataAsset.IdentifiedDataBreachProbabilityStillAtRisk(parsedModel)
 		riskText := dataBreachProbability.String()
 		switch dataBreachProbability {
@@ -3734,7 +4392,7 @@ func (r *pdfReporter) createDataAssets(parsedModel *types.Model) {
 			riskText = "none"
 		}
 		r.pdf.MultiCell(145, 6, riskText, "0", "0", false)
-		r.pdf.SetFont("Helvetica", "", fontSizeBody)
+		r.pdf.SetFont(fontFamily, "", fontSizeBody)
 		if r.pdf.GetY() > 265 {
 			r.pageBreak()
 			r.pdf.SetY(36)
@@ -3781,9 +4439,9 @@ func (r *pdfReporter) createDataAssets(parsedModel *types.Model) {
 				}
 				r.pdf.CellFormat(10, 6, "", "0", 0, "", false, 0, "")
 				posY := r.pdf.GetY()
-				r.pdf.SetFont("Helvetica", "", fontSizeVerySmall)
+				r.pdf.SetFont(fontFamily, "", fontSizeVerySmall)
 				r.pdf.MultiCell(185, 5, dataBreachRisk.DataBreachProbability.Title()+": "+uni(dataBreachRisk.SyntheticId), "0", "0", false)
-				r.pdf.SetFont("Helvetica", "", fontSizeBody)
+				r.pdf.SetFont(fontFamily, "", fontSizeBody)
 				r.pdf.Link(20, posY, 180, r.pdf.GetY()-posY, r.tocLinkIdByAssetId[dataBreachRisk.CategoryId])
 			}
 			r.pdfColorBlack()
@@ -3792,7 +4450,7 @@ func (r *pdfReporter) createDataAssets(parsedModel *types.Model) {
 }
 
 func (r *pdfReporter) createTrustBoundaries(parsedModel *types.Model) {
-	uni := r.pdf.UnicodeTranslatorFromDescriptor("")
+	uni := keepUTF8
 	title := "Trust Boundaries"
 	r.pdfColorBlack()
 	r.addHeadline(title, false)
@@ -3821,7 +4479,7 @@ func (r *pdfReporter) createTrustBoundaries(parsedModel *types.Model) {
 		html.Write(5, uni(trustBoundary.Description))
 		html.Write(5, "<br><br>")
 
-		r.pdf.SetFont("Helvetica", "", fontSizeBody)
+		r.pdf.SetFont(fontFamily, "", fontSizeBody)
 
 		r.pdfColorGray()
 		r.pdf.CellFormat(5, 6, "", "0", 0, "", false, 0, "")
@@ -3907,9 +4565,62 @@ func (r *pdfReporter) createTrustBoundaries(parsedModel *types.Model) {
 			boundariesNestedText = "none"
 		}
 		r.pdf.MultiCell(145, 6, uni(boundariesNestedText), "0", "0", false)
+
+		r.writeRisksOfTrustBoundary(parsedModel, trustBoundary.Id)
+	}
+
+	if len(types.SortedRisksOfTrustBoundary(parsedModel, "")) > 0 {
+		if r.pdf.GetY() > 250 {
+			r.pageBreak()
+			r.pdf.SetY(36)
+		} else {
+			html.Write(5, "<br><br><br>")
+		}
+		r.pdfColorBlack()
+		html.Write(5, "<b>Outside of Trust Boundaries</b><br>")
+		html.Write(5, "Risks of technical assets not placed inside any trust boundary.")
+		html.Write(5, "<br><br>")
+		r.pdf.SetFont(fontFamily, "", fontSizeBody)
+		r.writeRisksOfTrustBoundary(parsedModel, "")
 	}
 }
 
+// writeRisksOfTrustBoundary lists the risks still at risk located in the trust boundary (see types.Risk.TrustBoundaryId),
+// so the network and platform teams responsible for a boundary find their slice of the risks in one place
+func (r *pdfReporter) writeRisksOfTrustBoundary(parsedModel *types.Model, trustBoundaryId string) {
+	uni := keepUTF8
+	html := r.pdf.HTMLBasicNew()
+	if r.pdf.GetY() > 265 {
+		r.pageBreak()
+		r.pdf.SetY(36)
+	}
+	r.pdfColorGray()
+	r.pdf.CellFormat(5, 6, "", "0", 0, "", false, 0, "")
+	r.pdf.CellFormat(40, 6, "Risks:", "0", 0, "", false, 0, "")
+	risks := types.ReduceToOnlyStillAtRisk(parsedModel, types.SortedRisksOfTrustBoundary(parsedModel, trustBoundaryId))
+	if len(risks) == 0 {
+		r.pdf.MultiCell(145, 6, "none", "0", "0", false)
+		r.pdfColorBlack()
+		return
+	}
+
+	oldLeft, _, _, _ := r.pdf.GetMargins()
+	r.pdf.SetLeftMargin(oldLeft + 45)
+	r.pdf.SetY(r.pdf.GetY() + 0.5)
+	for _, risk := range risks {
+		if r.pdf.GetY() > 265 {
+			r.pageBreak()
+			r.pdf.SetY(36)
+		}
+		colorRiskSeverity(r.pdf, risk.Severity)
+		posY := r.pdf.GetY()
+		html.Write(5, risk.Severity.Title()+": "+uni(risk.Title)+"<br>")
+		r.pdf.Link(oldLeft+45, posY, 145, r.pdf.GetY()-posY, r.tocLinkIdByAssetId[risk.CategoryId])
+	}
+	r.pdf.SetLeftMargin(oldLeft)
+	r.pdfColorBlack()
+}
+
 func questionsUnanswered(parsedModel *types.Model) int {
 	result := 0
 	for _, answer := range parsedModel.Questions {
@@ -3921,7 +4632,7 @@ func questionsUnanswered(parsedModel *types.Model) int {
 }
 
 func (r *pdfReporter) createSharedRuntimes(parsedModel *types.Model) {
-	uni := r.pdf.UnicodeTranslatorFromDescriptor("")
+	uni := keepUTF8
 	title := "Shared Runtimes"
 	r.pdfColorBlack()
 	r.addHeadline(title, false)
@@ -3947,7 +4658,7 @@ func (r *pdfReporter) createSharedRuntimes(parsedModel *types.Model) {
 		html.Write(5, uni(sharedRuntime.Description))
 		html.Write(5, "<br><br>")
 
-		r.pdf.SetFont("Helvetica", "", fontSizeBody)
+		r.pdf.SetFont(fontFamily, "", fontSizeBody)
 
 		r.pdfColorGray()
 		r.pdf.CellFormat(5, 6, "", "0", 0, "", false, 0, "")
@@ -4001,6 +4712,80 @@ func (r *pdfReporter) createSharedRuntimes(parsedModel *types.Model) {
 	}
 }
 
+func (r *pdfReporter) createCustomSections() {
+	uni := keepUTF8
+	for i, section := range r.customSections {
+		r.pdfColorBlack()
+		r.addHeadline(uni(section.title), false)
+		r.defineLinkTarget("{section:" + strconv.Itoa(i) + "}")
+		r.currentChapterTitleBreadcrumb = section.title
+
+		html := r.pdf.HTMLBasicNew()
+		for j, paragraph := range section.paragraphs {
+			if r.pdf.GetY() > 250 {
+				r.pageBreak()
+				r.pdf.SetY(36)
+			} else if j > 0 {
+				html.Write(5, "<br><br>")
+			}
+			if len(paragraph.Headline) > 0 {
+				html.Write(5, "<b>"+uni(paragraph.Headline)+"</b><br>")
+			}
+			html.Write(5, uni(paragraph.Text))
+		}
+	}
+}
+
+// createAnnotations lists the review notes of the model by the element they are attached to
+func (r *pdfReporter) createAnnotations(parsedModel *types.Model) {
+	if len(parsedModel.Annotations) == 0 {
+		return
+	}
+
+	uni := keepUTF8
+	r.pdf.SetTextColor(0, 0, 0)
+	chapTitle := "Review Annotations"
+	r.addHeadline(chapTitle, false)
+	r.defineLinkTarget("{annotations}")
+	r.currentChapterTitleBreadcrumb = chapTitle
+
+	html := r.pdf.HTMLBasicNew()
+	html.Write(5, "This appendix lists the notes reviewers attached to the elements of the model. They are kept alongside "+
+		"the model as review feedback and do not affect the analysis.")
+	r.pdfColorBlack()
+	element := ""
+	for _, annotation := range parsedModel.SortedAnnotations() {
+		if r.pdf.GetY() > 250 {
+			r.pageBreak()
+			r.pdf.SetY(36)
+		} else if annotation.Element != element {
+			html.Write(5, "<br><br><br>")
+		} else {
+			html.Write(5, "<br><br>")
+		}
+		if annotation.Element != element {
+			element = annotation.Element
+			title, found := parsedModel.ElementTitle(element)
+			if !found {
+				title = "not in the model"
+			}
+			html.Write(5, "<b>"+uni(element)+"</b> ("+uni(title)+")<br>")
+		}
+
+		details := make([]string, 0)
+		if len(annotation.Author) > 0 {
+			details = append(details, annotation.Author)
+		}
+		if !annotation.Date.IsZero() {
+			details = append(details, annotation.Date.Format("2006-01-02"))
+		}
+		if len(details) > 0 {
+			html.Write(5, "<i>"+uni(strings.Join(details, ", "))+":</i> ")
+		}
+		html.Write(5, uni(annotation.Note))
+	}
+}
+
 func (r *pdfReporter) createRiskRulesChecked(parsedModel *types.Model, modelFilename string, skipRiskRules []string, buildTimestamp string, modelHash string, customRiskRules types.RiskRules) {
 	r.pdf.SetTextColor(0, 0, 0)
 	title := "Risk Rules Checked by Threagile"
@@ -4011,7 +4796,7 @@ func (r *pdfReporter) createRiskRulesChecked(parsedModel *types.Model, modelFile
 	html := r.pdf.HTMLBasicNew()
 	var strBuilder strings.Builder
 	r.pdfColorGray()
-	r.pdf.SetFont("Helvetica", "", fontSizeSmall)
+	r.pdf.SetFont(fontFamily, "", fontSizeSmall)
 	timestamp := time.Now()
 	strBuilder.WriteString("<b>Threagile Version:</b> " + docs.ThreagileVersion)
 	strBuilder.WriteString("<br><b>Threagile Build Timestamp:</b> " + buildTimestamp)
@@ -4021,7 +4806,7 @@ func (r *pdfReporter) createRiskRulesChecked(parsedModel *types.Model, modelFile
 	html.Write(5, strBuilder.String())
 	strBuilder.Reset()
 	r.pdfColorBlack()
-	r.pdf.SetFont("Helvetica", "", fontSizeBody)
+	r.pdf.SetFont(fontFamily, "", fontSizeBody)
 	strBuilder.WriteString("<br><br>Threagile (see <a href=\"https://threagile.io\">https://threagile.io</a> for more details) is an open-source toolkit for agile threat modeling, created by Christian Schneider (<a href=\"https://christian-schneider.net\">https://christian-schneider.net</a>): It allows to model an architecture with its assets in an agile fashion as a YAML file " +
 		"directly inside the IDE. Upon execution of the Threagile toolkit all standard risk rules (as well as individual custom rules if present) " +
 		"are checked against the architecture model. At the time the Threagile toolkit was executed on the model input file " +
@@ -4035,21 +4820,21 @@ func (r *pdfReporter) createRiskRulesChecked(parsedModel *types.Model, modelFile
 
 	for id, customRule := range customRiskRules {
 		r.pdf.Ln(-1)
-		r.pdf.SetFont("Helvetica", "B", fontSizeBody)
-		if contains(skipRiskRules, id) {
+		r.pdf.SetFont(fontFamily, "B", fontSizeBody)
+		if types.IsSkippedRiskRule(skipRiskRules, id) {
 			skipped = "SKIPPED - "
 		} else {
 			skipped = ""
 		}
 		r.pdf.CellFormat(190, 3, skipped+customRule.Category().Title, "0", 0, "", false, 0, "")
 		r.pdf.Ln(-1)
-		r.pdf.SetFont("Helvetica", "", fontSizeSmall)
+		r.pdf.SetFont(fontFamily, "", fontSizeSmall)
 		r.pdf.CellFormat(190, 6, id, "0", 0, "", false, 0, "")
 		r.pdf.Ln(-1)
-		r.pdf.SetFont("Helvetica", "I", fontSizeBody)
+		r.pdf.SetFont(fontFamily, "I", fontSizeBody)
 		r.pdf.CellFormat(190, 6, "Custom Risk Rule", "0", 0, "", false, 0, "")
 		r.pdf.Ln(-1)
-		r.pdf.SetFont("Helvetica", "", fontSizeBody)
+		r.pdf.SetFont(fontFamily, "", fontSizeBody)
 		r.pdfColorGray()
 		r.pdf.CellFormat(5, 6, "", "0", 0, "", false, 0, "")
 		r.pdf.CellFormat(25, 6, "STRIDE:", "0", 0, "", false, 0, "")
@@ -4075,16 +4860,16 @@ func (r *pdfReporter) createRiskRulesChecked(parsedModel *types.Model, modelFile
 	sort.Sort(types.ByRiskCategoryTitleSort(parsedModel.CustomRiskCategories))
 	for _, individualRiskCategory := range parsedModel.CustomRiskCategories {
 		r.pdf.Ln(-1)
-		r.pdf.SetFont("Helvetica", "B", fontSizeBody)
+		r.pdf.SetFont(fontFamily, "B", fontSizeBody)
 		r.pdf.CellFormat(190, 3, individualRiskCategory.Title, "0", 0, "", false, 0, "")
 		r.pdf.Ln(-1)
-		r.pdf.SetFont("Helvetica", "", fontSizeSmall)
+		r.pdf.SetFont(fontFamily, "", fontSizeSmall)
 		r.pdf.CellFormat(190, 6, individualRiskCategory.ID, "0", 0, "", false, 0, "")
 		r.pdf.Ln(-1)
-		r.pdf.SetFont("Helvetica", "I", fontSizeBody)
+		r.pdf.SetFont(fontFamily, "I", fontSizeBody)
 		r.pdf.CellFormat(190, 6, "Individual Risk category", "0", 0, "", false, 0, "")
 		r.pdf.Ln(-1)
-		r.pdf.SetFont("Helvetica", "", fontSizeBody)
+		r.pdf.SetFont(fontFamily, "", fontSizeBody)
 		r.pdfColorGray()
 		r.pdf.CellFormat(5, 6, "", "0", 0, "", false, 0, "")
 		r.pdf.CellFormat(25, 6, "STRIDE:", "0", 0, "", false, 0, "")
@@ -4109,18 +4894,18 @@ func (r *pdfReporter) createRiskRulesChecked(parsedModel *types.Model, modelFile
 
 	for _, rule := range risks.GetBuiltInRiskRules() {
 		r.pdf.Ln(-1)
-		r.pdf.SetFont("Helvetica", "B", fontSizeBody)
-		if contains(skipRiskRules, rule.Category().ID) {
+		r.pdf.SetFont(fontFamily, "B", fontSizeBody)
+		if types.IsSkippedRiskRule(skipRiskRules, rule.Category().ID) {
 			skipped = "SKIPPED - "
 		} else {
 			skipped = ""
 		}
 		r.pdf.CellFormat(190, 3, skipped+rule.Category().Title, "0", 0, "", false, 0, "")
 		r.pdf.Ln(-1)
-		r.pdf.SetFont("Helvetica", "", fontSizeSmall)
+		r.pdf.SetFont(fontFamily, "", fontSizeSmall)
 		r.pdf.CellFormat(190, 6, rule.Category().ID, "0", 0, "", false, 0, "")
 		r.pdf.Ln(-1)
-		r.pdf.SetFont("Helvetica", "", fontSizeBody)
+		r.pdf.SetFont(fontFamily, "", fontSizeBody)
 		r.pdfColorGray()
 		r.pdf.CellFormat(5, 6, "", "0", 0, "", false, 0, "")
 		r.pdf.CellFormat(25, 6, "STRIDE:", "0", 0, "", false, 0, "")
@@ -4145,7 +4930,7 @@ func (r *pdfReporter) createRiskRulesChecked(parsedModel *types.Model, modelFile
 }
 
 func (r *pdfReporter) createTargetDescription(parsedModel *types.Model, baseFolder string) error {
-	uni := r.pdf.UnicodeTranslatorFromDescriptor("")
+	uni := keepUTF8
 	r.pdf.SetTextColor(0, 0, 0)
 	title := "Application Overview"
 	r.addHeadline(title, false)
@@ -4255,10 +5040,11 @@ func (r *pdfReporter) addCustomImages(customImages []map[string]string, baseFold
 			extension := strings.ToLower(filepath.Ext(imageFilenameWithoutPath))
 			if extension == ".jpeg" || extension == ".jpg" || extension == ".png" || extension == ".gif" {
 				imageFullFilename := filepath.Join(baseFolder, imageFilenameWithoutPath)
-				heightWhenWidthIsFix, err := getHeightWhenWidthIsFix(imageFullFilename, 180)
+				imageConfig, err := r.registerImage(imageFullFilename)
 				if err != nil {
 					return fmt.Errorf("error getting height of image file: %w", err)
 				}
+				heightWhenWidthIsFix := float64(imageConfig.Height) / (float64(imageConfig.Width) / 180)
 				if r.pdf.GetY()+heightWhenWidthIsFix > 250 {
 					r.pageBreak()
 					r.pdf.SetY(36)
@@ -4271,7 +5057,6 @@ func (r *pdfReporter) addCustomImages(customImages []map[string]string, baseFold
 
 				var options gofpdf.ImageOptions
 				options.ImageType = ""
-				r.pdf.RegisterImage(imageFullFilename, "")
 				r.pdf.ImageOptions(imageFullFilename, 15, r.pdf.GetY()+50, 170, 0, true, options, 0, "")
 			} else {
 				log.Print("Ignoring custom image file: ", imageFilenameWithoutPath)
@@ -4281,34 +5066,23 @@ func (r *pdfReporter) addCustomImages(customImages []map[string]string, baseFold
 	return nil
 }
 
-// fileExists checks if a file exists and is not a directory before we
-// try using it to prevent further errors.
-func fileExists(filename string) bool {
-	info, err := os.Stat(filename)
-	if os.IsNotExist(err) {
-		return false
-	}
-	return !info.IsDir()
-}
-
-func getHeightWhenWidthIsFix(imageFullFilename string, width float64) (float64, error) {
-	if !fileExists(imageFullFilename) {
-		return 0, fmt.Errorf("image file does not exist (or is not readable as file): %s", filepath.Base(imageFullFilename))
-	}
-	/* #nosec imageFullFilename is not tainted (see caller restricting it to image files of model folder only) */
-	file, err := os.Open(imageFullFilename)
-	defer func() { _ = file.Close() }()
+// registerImage registers the image file (of the model folder or the output folder) under its filename, so that it can
+// be placed by its filename like an image of the OS file system, and answers its dimensions
+func (r *pdfReporter) registerImage(imageFullFilename string) (image.Config, error) {
+	/* #nosec imageFullFilename is not tainted (see callers restricting it to image files of model folder or the diagrams) */
+	data, err := r.fileSystem.ReadFile(imageFullFilename)
 	if err != nil {
-		return 0, fmt.Errorf("error opening image file: %w", err)
+		return image.Config{}, fmt.Errorf("image file does not exist (or is not readable as file): %s", filepath.Base(imageFullFilename))
 	}
-	img, _, err := image.DecodeConfig(file)
+	imageConfig, format, err := image.DecodeConfig(bytes.NewReader(data))
 	if err != nil {
-		return 0, fmt.Errorf("error decoding image file: %w", err)
+		return image.Config{}, fmt.Errorf("error decoding image file: %w", err)
 	}
-	return float64(img.Height) / (float64(img.Width) / width), nil
+	r.pdf.RegisterImageOptionsReader(imageFullFilename, gofpdf.ImageOptions{ImageType: format}, bytes.NewReader(data))
+	return imageConfig, r.pdf.Error()
 }
 
-func (r *pdfReporter) embedDataFlowDiagram(diagramFilenamePNG string, tempFolder string) {
+func (r *pdfReporter) embedDataFlowDiagram(diagramFilenamePNG string, tempFolder string) error {
 	r.pdf.SetTextColor(0, 0, 0)
 	title := "Data-Flow Diagram"
 	r.addHeadline(title, false)
@@ -4325,13 +5099,12 @@ func (r *pdfReporter) embedDataFlowDiagram(diagramFilenamePNG string, tempFolder
 	html.Write(5, intro.String())
 
 	// check to rotate the image if it is wider than high
-	/* #nosec diagramFilenamePNG is not tainted */
-	imagePath, _ := os.Open(diagramFilenamePNG)
-	defer func() { _ = imagePath.Close() }()
-	srcImage, _, _ := image.Decode(imagePath)
-	srcDimensions := srcImage.Bounds()
+	srcDimensions, err := r.registerImage(diagramFilenamePNG)
+	if err != nil {
+		return err
+	}
 	// wider than high?
-	muchWiderThanHigh := srcDimensions.Dx() > int(float64(srcDimensions.Dy())*1.25)
+	muchWiderThanHigh := srcDimensions.Width > int(float64(srcDimensions.Height)*1.25)
 	// fresh page (eventually landscape)?
 	r.isLandscapePage = false
 	_ = tempFolder
@@ -4351,7 +5124,7 @@ func (r *pdfReporter) embedDataFlowDiagram(diagramFilenamePNG string, tempFolder
 				rotatedFile, err := os.CreateTemp(tempFolder, "diagram-*-.png")
 				checkErr(err)
 				defer os.Remove(rotatedFile.Title())
-				dstImage := image.NewRGBA(image.Rect(0, 0, srcDimensions.Dy(), srcDimensions.Dx()))
+				dstImage := image.NewRGBA(image.Rect(0, 0, srcDimensions.Height, srcDimensions.Width))
 				err = graphics.Rotate(dstImage, srcImage, &graphics.RotateOptions{-1 * math.Pi / 2.0})
 				checkErr(err)
 				newImage, _ := os.Create(rotatedFile.Title())
@@ -4369,7 +5142,6 @@ func (r *pdfReporter) embedDataFlowDiagram(diagramFilenamePNG string, tempFolder
 	// embed in PDF
 	var options gofpdf.ImageOptions
 	options.ImageType = ""
-	r.pdf.RegisterImage(diagramFilenamePNG, "")
 	var maxWidth, maxHeight, newWidth int
 	var embedWidth, embedHeight float64
 	if allowedPdfLandscapePages && muchWiderThanHigh {
@@ -4380,7 +5152,7 @@ func (r *pdfReporter) embedDataFlowDiagram(diagramFilenamePNG string, tempFolder
 		r.pdf.Ln(10)
 		maxWidth, maxHeight = 190, 200 // reduced height as a text paragraph is above
 	}
-	newWidth = srcDimensions.Dx() / (srcDimensions.Dy() / maxHeight)
+	newWidth = srcDimensions.Width / (srcDimensions.Height / maxHeight)
 	if newWidth <= maxWidth {
 		embedWidth, embedHeight = 0, float64(maxHeight)
 	} else {
@@ -4394,9 +5166,10 @@ func (r *pdfReporter) embedDataFlowDiagram(diagramFilenamePNG string, tempFolder
 		r.pdf.AddPage()
 		gofpdi.UseImportedTemplate(r.pdf, r.diagramLegendTemplateId, 0, 0, 0, 300)
 	}
+	return nil
 }
 
-func (r *pdfReporter) embedDataRiskMapping(diagramFilenamePNG string, tempFolder string) {
+func (r *pdfReporter) embedDataRiskMapping(diagramFilenamePNG string, tempFolder string) error {
 	r.pdf.SetTextColor(0, 0, 0)
 	title := "Data Mapping"
 	r.addHeadline(title, false)
@@ -4416,13 +5189,12 @@ func (r *pdfReporter) embedDataRiskMapping(diagramFilenamePNG string, tempFolder
 
 	// TODO dedupe with code from other diagram embedding (almost same code)
 	// check to rotate the image if it is wider than high
-	/* #nosec diagramFilenamePNG is not tainted */
-	imagePath, _ := os.Open(diagramFilenamePNG)
-	defer func() { _ = imagePath.Close() }()
-	srcImage, _, _ := image.Decode(imagePath)
-	srcDimensions := srcImage.Bounds()
+	srcDimensions, err := r.registerImage(diagramFilenamePNG)
+	if err != nil {
+		return err
+	}
 	// wider than high?
-	widerThanHigh := srcDimensions.Dx() > srcDimensions.Dy()
+	widerThanHigh := srcDimensions.Width > srcDimensions.Height
 	pinnedWidth, pinnedHeight := 190.0, 195.0
 	// fresh page (eventually landscape)?
 	r.isLandscapePage = false
@@ -4442,7 +5214,7 @@ func (r *pdfReporter) embedDataRiskMapping(diagramFilenamePNG string, tempFolder
 				rotatedFile, err := os.CreateTemp(tempFolder, "diagram-*-.png")
 				checkErr(err)
 				defer os.Remove(rotatedFile.Title())
-				dstImage := image.NewRGBA(image.Rect(0, 0, srcDimensions.Dy(), srcDimensions.Dx()))
+				dstImage := image.NewRGBA(image.Rect(0, 0, srcDimensions.Height, srcDimensions.Width))
 				err = graphics.Rotate(dstImage, srcImage, &graphics.RotateOptions{-1 * math.Pi / 2.0})
 				checkErr(err)
 				newImage, _ := os.Create(rotatedFile.Title())
@@ -4462,7 +5234,6 @@ func (r *pdfReporter) embedDataRiskMapping(diagramFilenamePNG string, tempFolder
 	r.pdf.Ln(10)
 	var options gofpdf.ImageOptions
 	options.ImageType = ""
-	r.pdf.RegisterImage(diagramFilenamePNG, "")
 	if widerThanHigh {
 		pinnedHeight = 0
 	} else {
@@ -4470,16 +5241,27 @@ func (r *pdfReporter) embedDataRiskMapping(diagramFilenamePNG string, tempFolder
 	}
 	r.pdf.ImageOptions(diagramFilenamePNG, 10, r.pdf.GetY(), pinnedWidth, pinnedHeight, true, options, 0, "")
 	r.isLandscapePage = false
+	return nil
 }
 
 func (r *pdfReporter) writeReportToFile(reportFilename string) error {
-	err := r.pdf.OutputFileAndClose(reportFilename)
+	err := writePDF(r.fileSystem, r.pdf, reportFilename)
 	if err != nil {
 		return fmt.Errorf("error writing PDF report file: %w", err)
 	}
 	return nil
 }
 
+// writePDF writes the PDF to the file system and closes it
+func writePDF(fileSystem common.FileSystem, pdf *gofpdf.Fpdf, filename string) error {
+	var buffer bytes.Buffer
+	err := pdf.Output(&buffer)
+	if err != nil {
+		return err
+	}
+	return fileSystem.WriteFile(filename, buffer.Bytes(), 0600)
+}
+
 func (r *pdfReporter) addHeadline(headline string, small bool) {
 	r.pdf.AddPage()
 	gofpdi.UseImportedTemplate(r.pdf, r.contentTemplateId, 0, 0, 0, 300)
@@ -4487,9 +5269,9 @@ func (r *pdfReporter) addHeadline(headline string, small bool) {
 	if small {
 		fontSize = fontSizeHeadlineSmall
 	}
-	r.pdf.SetFont("Helvetica", "B", float64(fontSize))
+	r.pdf.SetFont(fontFamily, "B", float64(fontSize))
 	r.pdf.Text(11, 40, headline)
-	r.pdf.SetFont("Helvetica", "", fontSizeBody)
+	r.pdf.SetFont(fontFamily, "", fontSizeBody)
 	r.pdf.SetX(17)
 	r.pdf.SetY(46)
 }
@@ -0,0 +1,41 @@
+package report
+
+import (
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+// reportTestModel is the parsed model shared by the tests of the reports: a shop (its title to be escaped) accessing a
+// database, in trust boundaries and a shared runtime, with an open risk of each and a mitigated one
+func reportTestModel() *types.Model {
+	category := &types.RiskCategory{ID: "test-rule", Title: "Test Rule", Action: "Harden <b>everything</b>",
+		Mitigation: `Apply the <a href="https://example.com/guide"><i>hardening guide</i></a> to versions < 2.<br>Then repeat.`, Check: "Hardened?"}
+	link := &types.CommunicationLink{Id: "shop>database", Title: "Database Access", SourceId: "shop", TargetId: "database",
+		Protocol: types.JdbcEncrypted, DataAssetsSent: []string{"customers"}, Tags: []string{"pci"}}
+	shop := &types.TechnicalAsset{Id: "shop", Title: "Shop & Co", Owner: "Team Shop", Type: types.Process, Tags: []string{"aws"},
+		CommunicationLinks: []*types.CommunicationLink{link}}
+	database := &types.TechnicalAsset{Id: "database", Title: "Database", Type: types.Datastore, Tags: []string{"pci"}}
+	dmz := &types.TrustBoundary{Id: "dmz", Title: "DMZ", Tags: []string{"aws"}, TechnicalAssetsInside: []string{"shop"}}
+	internal := &types.TrustBoundary{Id: "internal", Title: "Internal", TechnicalAssetsInside: []string{"database"}}
+	return &types.Model{
+		Title:                 "Report Test",
+		TagsAvailable:         []string{"pci", "aws", "unused"},
+		BuiltInRiskCategories: []*types.RiskCategory{category},
+		TechnicalAssets:       map[string]*types.TechnicalAsset{shop.Id: shop, database.Id: database},
+		CommunicationLinks:    map[string]*types.CommunicationLink{link.Id: link},
+		IncomingTechnicalCommunicationLinksMappedByTargetId: map[string][]*types.CommunicationLink{database.Id: {link}},
+		DataAssets: map[string]*types.DataAsset{
+			"customers": {Id: "customers", Title: "Customers", Owner: "Team Shop", Tags: []string{"pci"}},
+			"logs":      {Id: "logs", Title: "Logs"},
+		},
+		TrustBoundaries: map[string]*types.TrustBoundary{dmz.Id: dmz, internal.Id: internal},
+		SharedRuntimes: map[string]*types.SharedRuntime{
+			"cluster": {Id: "cluster", Title: "Cluster", Tags: []string{"aws", "pci"}, TechnicalAssetsRunning: []string{"shop", "database"}},
+		},
+		DirectContainingTrustBoundaryMappedByTechnicalAssetId: map[string]*types.TrustBoundary{shop.Id: dmz, database.Id: internal},
+		GeneratedRisksByCategory: map[string][]*types.Risk{category.ID: {
+			{CategoryId: category.ID, SyntheticId: "test-rule@shop", Title: "<b>Test Rule</b> at <b>Shop & Co</b>", Severity: types.HighSeverity, MostRelevantTechnicalAssetId: shop.Id},
+			{CategoryId: category.ID, SyntheticId: "test-rule@model", Title: "<b>Test Rule</b> of the model", Severity: types.MediumSeverity},
+			{CategoryId: category.ID, SyntheticId: "test-rule@shop@mitigated", Title: "Mitigated", RiskStatus: types.Mitigated, MostRelevantTechnicalAssetId: shop.Id},
+		}},
+	}
+}
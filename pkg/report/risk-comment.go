@@ -0,0 +1,41 @@
+package report
+
+import (
+	"strings"
+
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+// riskCommentLines answers the comments of the risk as lines of their date, author and text, each reply indented below
+// the comment it answers
+func riskCommentLines(risk *types.Risk) []string {
+	replies := make(map[string][]types.RiskComment)
+	known := make(map[string]bool)
+	for _, comment := range risk.Comments {
+		known[comment.Id] = true
+	}
+	threads := make([]types.RiskComment, 0)
+	for _, comment := range risk.Comments {
+		if len(comment.ReplyTo) > 0 && known[comment.ReplyTo] {
+			replies[comment.ReplyTo] = append(replies[comment.ReplyTo], comment)
+		} else {
+			threads = append(threads, comment)
+		}
+	}
+
+	lines := make([]string, 0, len(risk.Comments))
+	var add func(comments []types.RiskComment, depth int)
+	add = func(comments []types.RiskComment, depth int) {
+		for _, comment := range comments {
+			author := comment.Author
+			if len(author) == 0 {
+				author = "anonymous"
+			}
+			text := strings.Join(strings.Fields(comment.Text), " ")
+			lines = append(lines, strings.Repeat("    ", depth)+comment.Date.Format("2006-01-02")+" "+author+": "+text)
+			add(replies[comment.Id], depth+1)
+		}
+	}
+	add(threads, 0)
+	return lines
+}
@@ -0,0 +1,51 @@
+package report
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+// WriteRiskDeltaText writes the risk delta human-readable, one line per risk grouped by the kind of change
+func WriteRiskDeltaText(writer io.Writer, delta *types.RiskDelta) error {
+	_, err := fmt.Fprintf(writer, "%v new, %v resolved, %v re-opened and %v severity-changed risks\n",
+		len(delta.New), len(delta.Resolved), len(delta.Reopened), len(delta.SeverityChanged))
+	if err != nil {
+		return err
+	}
+	for _, group := range []struct {
+		title string
+		risks []*types.Risk
+	}{
+		{"New risks", delta.New},
+		{"Resolved risks", delta.Resolved},
+		{"Re-opened risks", delta.Reopened},
+	} {
+		if len(group.risks) == 0 {
+			continue
+		}
+		if _, err = fmt.Fprintf(writer, "\n%v:\n", group.title); err != nil {
+			return err
+		}
+		for _, risk := range group.risks {
+			if _, err = fmt.Fprintf(writer, "  %-8v  %v (%v)\n", risk.Severity, removeFormattingTags(risk.Title), risk.SyntheticId); err != nil {
+				return err
+			}
+		}
+	}
+	if len(delta.SeverityChanged) == 0 {
+		return nil
+	}
+	if _, err = fmt.Fprintf(writer, "\nSeverity changes:\n"); err != nil {
+		return err
+	}
+	for _, change := range delta.SeverityChanged {
+		_, err = fmt.Fprintf(writer, "  %-8v -> %-8v  %v (%v)\n", change.PreviousSeverity, change.Risk.Severity,
+			removeFormattingTags(change.Risk.Title), change.Risk.SyntheticId)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
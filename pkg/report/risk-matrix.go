@@ -0,0 +1,193 @@
+package report
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+
+	"github.com/threagile/threagile/pkg/common"
+	"github.com/threagile/threagile/pkg/security/types"
+	"github.com/wcharczuk/go-chart"
+)
+
+const (
+	riskMatrixWidth       = 800
+	riskMatrixHeight      = 640
+	riskMatrixMarginLeft  = 130
+	riskMatrixMarginTop   = 60
+	riskMatrixMarginRight = 20
+	riskMatrixMarginBelow = 80
+)
+
+// RiskMatrix counts risks per exploitation likelihood (rows) and exploitation impact (columns)
+type RiskMatrix [4][4]int
+
+func NewRiskMatrix(risks []*types.Risk) *RiskMatrix {
+	matrix := new(RiskMatrix)
+	for _, risk := range risks {
+		likelihood, impact := int(risk.ExploitationLikelihood), int(risk.ExploitationImpact)
+		if likelihood < 0 || likelihood >= len(matrix) || impact < 0 || impact >= len(matrix[likelihood]) {
+			continue
+		}
+		matrix[likelihood][impact]++
+	}
+	return matrix
+}
+
+func (what *RiskMatrix) Count(likelihood types.RiskExploitationLikelihood, impact types.RiskExploitationImpact) int {
+	return what[likelihood][impact]
+}
+
+func (what *RiskMatrix) Total() int {
+	total := 0
+	for _, row := range what {
+		for _, count := range row {
+			total += count
+		}
+	}
+	return total
+}
+
+func WriteRiskMatrixPNG(fileSystem common.FileSystem, risks []*types.Risk, title string, filename string) error {
+	return writeRiskMatrix(fileSystem, risks, title, filename, chart.PNG)
+}
+
+func WriteRiskMatrixSVG(fileSystem common.FileSystem, risks []*types.Risk, title string, filename string) error {
+	return writeRiskMatrix(fileSystem, risks, title, filename, chart.SVG)
+}
+
+func writeRiskMatrix(fileSystem common.FileSystem, risks []*types.Risk, title string, filename string, provider chart.RendererProvider) error {
+	var buffer bytes.Buffer
+	err := RenderRiskMatrix(NewRiskMatrix(risks), title, provider, &buffer)
+	if err != nil {
+		return fmt.Errorf("error rendering risk matrix %q: %w", filename, err)
+	}
+	err = fileSystem.WriteFile(filename, buffer.Bytes(), 0600)
+	if err != nil {
+		return fmt.Errorf("error creating risk matrix file %q: %w", filename, err)
+	}
+	return nil
+}
+
+// RenderRiskMatrix draws the likelihood×impact grid with each cell colored by its calculated severity
+// and a bubble sized by the number of risks falling into that cell
+func RenderRiskMatrix(matrix *RiskMatrix, title string, provider chart.RendererProvider, w io.Writer) error {
+	renderer, err := provider(riskMatrixWidth, riskMatrixHeight)
+	if err != nil {
+		return err
+	}
+	font, err := chart.GetDefaultFont()
+	if err != nil {
+		return err
+	}
+	renderer.SetFont(font)
+
+	likelihoods := types.RiskExploitationLikelihoodValues()
+	impacts := types.RiskExploitationImpactValues()
+	cellWidth := (riskMatrixWidth - riskMatrixMarginLeft - riskMatrixMarginRight) / len(impacts)
+	cellHeight := (riskMatrixHeight - riskMatrixMarginTop - riskMatrixMarginBelow) / len(likelihoods)
+	maxCount := 0
+	for _, row := range matrix {
+		for _, count := range row {
+			if count > maxCount {
+				maxCount = count
+			}
+		}
+	}
+
+	renderer.SetFontColor(makeColor(Black))
+	renderer.SetFontSize(16)
+	centeredText(renderer, fmt.Sprintf("%v (%d)", title, matrix.Total()), riskMatrixWidth/2, riskMatrixMarginTop/2)
+
+	for row, likelihoodValue := range likelihoods {
+		likelihood := likelihoodValue.(types.RiskExploitationLikelihood)
+		top := riskMatrixMarginTop + (len(likelihoods)-1-row)*cellHeight // most likely on top
+		for column, impactValue := range impacts {
+			impact := impactValue.(types.RiskExploitationImpact)
+			left := riskMatrixMarginLeft + column*cellWidth
+			severityColor := makeColor(rgbHexColorOfSeverity(types.CalculateSeverity(likelihood, impact)))
+
+			renderer.SetFillColor(severityColor.WithAlpha(48))
+			renderer.SetStrokeColor(makeColor(MoreLightGray))
+			renderer.SetStrokeWidth(1)
+			renderer.MoveTo(left, top)
+			renderer.LineTo(left+cellWidth, top)
+			renderer.LineTo(left+cellWidth, top+cellHeight)
+			renderer.LineTo(left, top+cellHeight)
+			renderer.Close()
+			renderer.FillStroke()
+
+			count := matrix.Count(likelihood, impact)
+			if count == 0 {
+				continue
+			}
+			maxRadius := math.Min(float64(cellWidth), float64(cellHeight))/2 - 6
+			radius := 12 + (maxRadius-12)*math.Sqrt(float64(count)/float64(maxCount))
+			renderer.SetFillColor(severityColor)
+			renderer.SetStrokeColor(makeColor(darkenHexColor(rgbHexColorOfSeverity(types.CalculateSeverity(likelihood, impact)))))
+			drawBubble(renderer, radius, left+cellWidth/2, top+cellHeight/2)
+
+			renderer.SetFontColor(makeColor("#FFFFFF"))
+			renderer.SetFontSize(14)
+			centeredText(renderer, strconv.Itoa(count), left+cellWidth/2, top+cellHeight/2)
+		}
+
+		renderer.SetFontColor(makeColor(Gray))
+		renderer.SetFontSize(12)
+		label := likelihood.Title()
+		renderer.Text(label, riskMatrixMarginLeft-10-renderer.MeasureText(label).Width(), top+cellHeight/2+renderer.MeasureText(label).Height()/2)
+	}
+
+	renderer.SetFontColor(makeColor(Gray))
+	renderer.SetFontSize(12)
+	bottom := riskMatrixMarginTop + len(likelihoods)*cellHeight
+	for column, impactValue := range impacts {
+		centeredText(renderer, impactValue.(types.RiskExploitationImpact).Title(), riskMatrixMarginLeft+column*cellWidth+cellWidth/2, bottom+16)
+	}
+	renderer.SetFontSize(13)
+	renderer.SetFontColor(makeColor(Black))
+	centeredText(renderer, "Exploitation Impact", riskMatrixMarginLeft+len(impacts)*cellWidth/2, bottom+50)
+	renderer.SetTextRotation(-math.Pi / 2)
+	renderer.Text("Exploitation Likelihood", 24, riskMatrixMarginTop+len(likelihoods)*cellHeight/2+renderer.MeasureText("Exploitation Likelihood").Width()/2)
+	renderer.ClearTextRotation()
+
+	return renderer.Save(w)
+}
+
+// drawBubble approximates the circle by a polygon, as the quad curves of the raster renderer look rather square
+func drawBubble(renderer chart.Renderer, radius float64, x int, y int) {
+	const segments = 48
+	for i := 0; i <= segments; i++ {
+		angle := 2 * math.Pi * float64(i) / segments
+		px, py := x+int(math.Round(radius*math.Cos(angle))), y+int(math.Round(radius*math.Sin(angle)))
+		if i == 0 {
+			renderer.MoveTo(px, py)
+		} else {
+			renderer.LineTo(px, py)
+		}
+	}
+	renderer.Close()
+	renderer.FillStroke()
+}
+
+func centeredText(renderer chart.Renderer, text string, x int, y int) {
+	box := renderer.MeasureText(text)
+	renderer.Text(text, x-box.Width()/2, y+box.Height()/2)
+}
+
+func rgbHexColorOfSeverity(severity types.RiskSeverity) string {
+	switch severity {
+	case types.CriticalSeverity:
+		return rgbHexColorCriticalRisk()
+	case types.HighSeverity:
+		return rgbHexColorHighRisk()
+	case types.ElevatedSeverity:
+		return rgbHexColorElevatedRisk()
+	case types.MediumSeverity:
+		return rgbHexColorMediumRisk()
+	default:
+		return rgbHexColorLowRisk()
+	}
+}
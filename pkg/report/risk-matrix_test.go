@@ -0,0 +1,48 @@
+package report
+
+import (
+	"bytes"
+	"image/png"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/threagile/threagile/pkg/common"
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+func riskMatrixTestRisks() []*types.Risk {
+	return []*types.Risk{
+		{SyntheticId: "a", ExploitationLikelihood: types.Frequent, ExploitationImpact: types.VeryHighImpact},
+		{SyntheticId: "b", ExploitationLikelihood: types.Frequent, ExploitationImpact: types.VeryHighImpact},
+		{SyntheticId: "c", ExploitationLikelihood: types.Unlikely, ExploitationImpact: types.MediumImpact},
+		{SyntheticId: "d", ExploitationLikelihood: types.RiskExploitationLikelihood(7), ExploitationImpact: types.LowImpact},
+	}
+}
+
+func TestNewRiskMatrix(t *testing.T) {
+	matrix := NewRiskMatrix(riskMatrixTestRisks())
+
+	assert.Equal(t, 2, matrix.Count(types.Frequent, types.VeryHighImpact))
+	assert.Equal(t, 1, matrix.Count(types.Unlikely, types.MediumImpact))
+	assert.Equal(t, 0, matrix.Count(types.Unlikely, types.LowImpact))
+	assert.Equal(t, 3, matrix.Total(), "risks of unknown likelihood left out")
+}
+
+func TestWriteRiskMatrix(t *testing.T) {
+	fileSystem := common.NewMemoryFileSystem()
+	assert.NoError(t, WriteRiskMatrixSVG(fileSystem, riskMatrixTestRisks(), "Risk Matrix", "risk-matrix.svg"))
+	svg, err := fileSystem.ReadFile("risk-matrix.svg")
+	assert.NoError(t, err)
+	assert.Contains(t, string(svg), ">Risk Matrix (3)</text>")
+	assert.Contains(t, string(svg), ">2</text>")
+	assert.Contains(t, string(svg), ">1</text>")
+	assert.Contains(t, string(svg), ">Exploitation Likelihood</text>")
+
+	assert.NoError(t, WriteRiskMatrixPNG(fileSystem, riskMatrixTestRisks(), "Risk Matrix", "risk-matrix.png"))
+	data, err := fileSystem.ReadFile("risk-matrix.png")
+	assert.NoError(t, err)
+	image, err := png.Decode(bytes.NewReader(data))
+	assert.NoError(t, err)
+	assert.Equal(t, riskMatrixWidth, image.Bounds().Dx())
+	assert.Equal(t, riskMatrixHeight, image.Bounds().Dy())
+}
@@ -0,0 +1,143 @@
+package report
+
+import (
+	"fmt"
+	"html"
+	"sort"
+	"strings"
+
+	"github.com/threagile/threagile/pkg/common"
+	"github.com/threagile/threagile/pkg/docs"
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+func WriteRulesDocMarkdown(fileSystem common.FileSystem, rules types.RiskRules, filename string) error {
+	err := fileSystem.WriteFile(filename, []byte(RulesDocMarkdown(rules)), 0600)
+	if err != nil {
+		return fmt.Errorf("failed to write rules documentation markdown file: %w", err)
+	}
+	return nil
+}
+
+func WriteRulesDocHTML(fileSystem common.FileSystem, rules types.RiskRules, filename string) error {
+	err := fileSystem.WriteFile(filename, []byte(RulesDocHTML(rules)), 0600)
+	if err != nil {
+		return fmt.Errorf("failed to write rules documentation html file: %w", err)
+	}
+	return nil
+}
+
+func RulesDocMarkdown(rules types.RiskRules) string {
+	var text strings.Builder
+	text.WriteString("# Threagile Risk Rules\n\n")
+	text.WriteString(fmt.Sprintf("Generated by Threagile %v. The following %d risk rules are active:\n\n", docs.ThreagileVersion, len(rules)))
+	for _, category := range sortedRuleCategories(rules) {
+		text.WriteString(fmt.Sprintf("- [%v](#%v)\n", category.Title, category.ID))
+	}
+	for _, id := range sortedRuleIDs(rules) {
+		text.WriteString("\n")
+		text.WriteString(RuleDocMarkdown(rules[id]))
+	}
+	return text.String()
+}
+
+func RuleDocMarkdown(rule types.RiskRule) string {
+	category := rule.Category()
+
+	var text strings.Builder
+	text.WriteString(fmt.Sprintf("## %v\n\n", category.Title))
+	text.WriteString(fmt.Sprintf("<a id=\"%v\"></a>\n\n", category.ID))
+	text.WriteString("| | |\n|---|---|\n")
+	for _, row := range ruleDocAttributes(rule) {
+		text.WriteString(fmt.Sprintf("| **%v** | %v |\n", row[0], strings.ReplaceAll(row[1], "|", "\\|")))
+	}
+	for _, section := range ruleDocSections(category) {
+		text.WriteString(fmt.Sprintf("\n### %v\n\n%v\n", section[0], section[1]))
+	}
+	return text.String()
+}
+
+func RulesDocHTML(rules types.RiskRules) string {
+	var text strings.Builder
+	text.WriteString("<!DOCTYPE html>\n<html>\n<head>\n<meta charset=\"utf-8\">\n<title>Threagile Risk Rules</title>\n</head>\n<body>\n")
+	text.WriteString("<h1>Threagile Risk Rules</h1>\n")
+	text.WriteString(fmt.Sprintf("<p>Generated by Threagile %v. The following %d risk rules are active:</p>\n<ul>\n", html.EscapeString(docs.ThreagileVersion), len(rules)))
+	for _, category := range sortedRuleCategories(rules) {
+		text.WriteString(fmt.Sprintf("<li><a href=\"#%v\">%v</a></li>\n", html.EscapeString(category.ID), html.EscapeString(category.Title)))
+	}
+	text.WriteString("</ul>\n")
+	for _, id := range sortedRuleIDs(rules) {
+		rule := rules[id]
+		category := rule.Category()
+		text.WriteString(fmt.Sprintf("<h2 id=\"%v\">%v</h2>\n<table>\n", html.EscapeString(category.ID), html.EscapeString(category.Title)))
+		for _, row := range ruleDocAttributes(rule) {
+			text.WriteString(fmt.Sprintf("<tr><th align=\"left\">%v</th><td>%v</td></tr>\n", row[0], row[1]))
+		}
+		text.WriteString("</table>\n")
+		for _, section := range ruleDocSections(category) {
+			text.WriteString(fmt.Sprintf("<h3>%v</h3>\n<p>%v</p>\n", section[0], section[1]))
+		}
+	}
+	text.WriteString("</body>\n</html>\n")
+	return text.String()
+}
+
+// ruleDocAttributes returns the attributes of the rule as HTML (which the Markdown shows as well): the texts of the
+// category are HTML already, like in the report, the others are escaped
+func ruleDocAttributes(rule types.RiskRule) [][2]string {
+	category := rule.Category()
+	rows := [][2]string{
+		{"ID", html.EscapeString(category.ID)},
+		{"STRIDE", html.EscapeString(category.STRIDE.Title())},
+		{"Function", html.EscapeString(category.Function.Title())},
+		{"Action", category.Action},
+		{"ASVS", category.ASVS},
+		{"Cheat Sheet", category.CheatSheet},
+	}
+	if category.CWE > 0 {
+		rows = append(rows, [2]string{"CWE", fmt.Sprintf("CWE-%d (https://cwe.mitre.org/data/definitions/%d.html)", category.CWE, category.CWE)})
+	}
+	if tags := rule.SupportedTags(); len(tags) > 0 {
+		rows = append(rows, [2]string{"Supported Tags", html.EscapeString(strings.Join(tags, ", "))})
+	}
+	if category.ModelFailurePossibleReason {
+		rows = append(rows, [2]string{"Model Failure", "possible reason for a modeling failure"})
+	}
+	return rows
+}
+
+// ruleDocSections returns the texts of the category given, which are HTML
+func ruleDocSections(category *types.RiskCategory) [][2]string {
+	sections := make([][2]string, 0)
+	for _, section := range [][2]string{
+		{"Description", category.Description},
+		{"Impact", category.Impact},
+		{"Detection Logic", category.DetectionLogic},
+		{"Risk Assessment", category.RiskAssessment},
+		{"Mitigation", category.Mitigation},
+		{"Check", category.Check},
+		{"False Positives", category.FalsePositives},
+	} {
+		if len(strings.TrimSpace(section[1])) > 0 {
+			sections = append(sections, section)
+		}
+	}
+	return sections
+}
+
+func sortedRuleIDs(rules types.RiskRules) []string {
+	ids := make([]string, 0, len(rules))
+	for id := range rules {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+func sortedRuleCategories(rules types.RiskRules) []*types.RiskCategory {
+	categories := make([]*types.RiskCategory, 0, len(rules))
+	for _, id := range sortedRuleIDs(rules) {
+		categories = append(categories, rules[id].Category())
+	}
+	return categories
+}
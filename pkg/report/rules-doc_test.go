@@ -0,0 +1,39 @@
+package report
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+type docTestRule struct{}
+
+func (docTestRule) Category() *types.RiskCategory {
+	return &types.RiskCategory{ID: "test-rule", Title: "Test & Rule", Action: "Harden <b>everything</b>",
+		Mitigation: "Apply the hardening guide.<br>Then <i>repeat</i>.", STRIDE: types.Tampering, Function: types.Operations}
+}
+
+func (docTestRule) SupportedTags() []string {
+	return []string{"<vault>"}
+}
+
+func (docTestRule) GenerateRisks(*types.Model) ([]*types.Risk, error) {
+	return nil, nil
+}
+
+func TestRulesDocHTML(t *testing.T) {
+	doc := RulesDocHTML(types.RiskRules{"test-rule": docTestRule{}})
+	assert.Contains(t, doc, `<h2 id="test-rule">Test &amp; Rule</h2>`)
+	assert.Contains(t, doc, `<tr><th align="left">Action</th><td>Harden <b>everything</b></td></tr>`, "HTML of the category as it is")
+	assert.Contains(t, doc, `<tr><th align="left">Supported Tags</th><td>&lt;vault&gt;</td></tr>`)
+	assert.Contains(t, doc, "<h3>Mitigation</h3>\n<p>Apply the hardening guide.<br>Then <i>repeat</i>.</p>")
+}
+
+func TestRuleDocMarkdown(t *testing.T) {
+	doc := RuleDocMarkdown(docTestRule{})
+	assert.Contains(t, doc, "## Test & Rule\n\n<a id=\"test-rule\"></a>\n")
+	assert.Contains(t, doc, "| **Action** | Harden <b>everything</b> |\n")
+	assert.Contains(t, doc, "| **Supported Tags** | &lt;vault&gt; |\n")
+	assert.Contains(t, doc, "### Mitigation\n\nApply the hardening guide.<br>Then <i>repeat</i>.\n")
+}
@@ -0,0 +1,230 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/threagile/threagile/pkg/common"
+	"github.com/threagile/threagile/pkg/docs"
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+const (
+	sarifVersion = "2.1.0"
+	sarifSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+)
+
+// the subset of SARIF 2.1.0 (https://docs.oasis-open.org/sarif/sarif/v2.1.0/sarif-v2.1.0.html) the risks are mapped to:
+// each risk category is a rule and each risk a result of its rule, identified by its synthetic id
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	Version        string      `json:"version"`
+	InformationUri string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	Id               string              `json:"id"`
+	Name             string              `json:"name"`
+	ShortDescription sarifMessage        `json:"shortDescription"`
+	FullDescription  sarifMessage        `json:"fullDescription"`
+	Help             sarifMessage        `json:"help"`
+	HelpUri          string              `json:"helpUri,omitempty"`
+	Properties       sarifRuleProperties `json:"properties"`
+}
+
+type sarifRuleProperties struct {
+	Tags             []string `json:"tags"`
+	SecuritySeverity string   `json:"security-severity"` // as used by code scanning tools to rank the rules
+	CWE              string   `json:"cwe,omitempty"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleId              string                `json:"ruleId"`
+	RuleIndex           int                   `json:"ruleIndex"`
+	Level               string                `json:"level"`
+	Message             sarifMessage          `json:"message"`
+	Locations           []sarifLocation       `json:"locations"`
+	PartialFingerprints map[string]string     `json:"partialFingerprints"`
+	Suppressions        []sarifSuppression    `json:"suppressions,omitempty"`
+	Properties          sarifResultProperties `json:"properties"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation  `json:"physicalLocation"`
+	LogicalLocations []sarifLogicalLocation `json:"logicalLocations,omitempty"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+}
+
+type sarifArtifactLocation struct {
+	Uri string `json:"uri"`
+}
+
+type sarifLogicalLocation struct {
+	Name               string `json:"name"`
+	FullyQualifiedName string `json:"fullyQualifiedName"`
+	Kind               string `json:"kind"`
+}
+
+type sarifSuppression struct {
+	Kind          string `json:"kind"`
+	Status        string `json:"status"`
+	Justification string `json:"justification,omitempty"`
+}
+
+type sarifResultProperties struct {
+	SyntheticId            string `json:"synthetic_id"`
+	Severity               string `json:"severity"`
+	RiskStatus             string `json:"risk_status"`
+	ExploitationLikelihood string `json:"exploitation_likelihood"`
+	ExploitationImpact     string `json:"exploitation_impact"`
+}
+
+// WriteRisksSARIF writes the risks as SARIF log, located in the model file (as the risks stem from the model and not
+// from any source code), so that they can be uploaded to code scanning tools
+func WriteRisksSARIF(fileSystem common.FileSystem, parsedModel *types.Model, modelFilename string, filename string) error {
+	jsonBytes, err := json.MarshalIndent(risksSARIF(parsedModel, modelFilename), "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal risks to SARIF: %w", err)
+	}
+	err = fileSystem.WriteFile(filename, jsonBytes, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to write risks to SARIF file: %w", err)
+	}
+	return nil
+}
+
+func risksSARIF(parsedModel *types.Model, modelFilename string) sarifLog {
+	rules := make([]sarifRule, 0)
+	results := make([]sarifResult, 0)
+	for _, category := range types.SortedRiskCategories(parsedModel) {
+		risks := types.SortedRisksOfCategory(parsedModel, category)
+		ruleIndex := len(rules)
+		rules = append(rules, sarifCategoryRule(category, risks))
+		for _, risk := range risks {
+			if !risk.RiskStatus.IsStillAtRisk() {
+				continue // mitigated risks and false positives are no findings, so that code scanning tools close their alerts
+			}
+			results = append(results, sarifRiskResult(parsedModel, risk, ruleIndex, modelFilename))
+		}
+	}
+	return sarifLog{
+		Schema:  sarifSchema,
+		Version: sarifVersion,
+		Runs: []sarifRun{{
+			Tool: sarifTool{Driver: sarifDriver{
+				Name:           "Threagile",
+				Version:        docs.ThreagileVersion,
+				InformationUri: "https://threagile.io",
+				Rules:          rules,
+			}},
+			Results: results,
+		}},
+	}
+}
+
+func sarifCategoryRule(category *types.RiskCategory, risks []*types.Risk) sarifRule {
+	highestSeverity := types.LowSeverity
+	for _, risk := range risks {
+		if risk.Severity > highestSeverity {
+			highestSeverity = risk.Severity
+		}
+	}
+	rule := sarifRule{
+		Id:               category.ID,
+		Name:             category.Title,
+		ShortDescription: sarifMessage{Text: category.Title},
+		FullDescription:  sarifMessage{Text: removeFormattingTags(category.Description)},
+		Help:             sarifMessage{Text: removeFormattingTags(category.Mitigation)},
+		HelpUri:          category.CheatSheet,
+		Properties: sarifRuleProperties{
+			Tags:             []string{"security", category.STRIDE.String(), category.Function.String()},
+			SecuritySeverity: sarifSecuritySeverity(highestSeverity),
+		},
+	}
+	if category.CWE > 0 {
+		rule.Properties.CWE = "CWE-" + strconv.Itoa(category.CWE)
+	}
+	return rule
+}
+
+func sarifRiskResult(parsedModel *types.Model, risk *types.Risk, ruleIndex int, modelFilename string) sarifResult {
+	location := sarifLocation{PhysicalLocation: sarifPhysicalLocation{ArtifactLocation: sarifArtifactLocation{Uri: modelFilename}}}
+	if technicalAsset, ok := parsedModel.TechnicalAssets[risk.MostRelevantTechnicalAssetId]; ok {
+		location.LogicalLocations = append(location.LogicalLocations, sarifLogicalLocation{
+			Name: technicalAsset.Title, FullyQualifiedName: technicalAsset.Id, Kind: "resource",
+		})
+	}
+	result := sarifResult{
+		RuleId:              risk.CategoryId,
+		RuleIndex:           ruleIndex,
+		Level:               sarifLevel(risk.Severity),
+		Message:             sarifMessage{Text: removeFormattingTags(risk.Title)},
+		Locations:           []sarifLocation{location},
+		PartialFingerprints: map[string]string{"syntheticId/v1": risk.SyntheticId}, // keeps the identity of the risk across runs
+		Properties: sarifResultProperties{
+			SyntheticId:            risk.SyntheticId,
+			Severity:               risk.Severity.String(),
+			RiskStatus:             risk.RiskStatus.String(),
+			ExploitationLikelihood: risk.ExploitationLikelihood.String(),
+			ExploitationImpact:     risk.ExploitationImpact.String(),
+		},
+	}
+	if risk.RiskStatus == types.Accepted { // as tracked in the model (with the justification)
+		result.Suppressions = []sarifSuppression{{
+			Kind:          "external",
+			Status:        "accepted",
+			Justification: risk.GetRiskTrackingWithDefault(parsedModel).Justification,
+		}}
+	}
+	return result
+}
+
+func sarifLevel(severity types.RiskSeverity) string {
+	switch severity {
+	case types.CriticalSeverity, types.HighSeverity:
+		return "error"
+	case types.ElevatedSeverity, types.MediumSeverity:
+		return "warning"
+	}
+	return "note"
+}
+
+// sarifSecuritySeverity is the CVSS-like score the code scanning tools derive their severity from
+func sarifSecuritySeverity(severity types.RiskSeverity) string {
+	switch severity {
+	case types.CriticalSeverity:
+		return "9.5"
+	case types.HighSeverity:
+		return "8.0"
+	case types.ElevatedSeverity:
+		return "6.5"
+	case types.MediumSeverity:
+		return "5.0"
+	}
+	return "2.0"
+}
@@ -0,0 +1,59 @@
+package report
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/threagile/threagile/pkg/common"
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+func TestWriteRisksSARIF(t *testing.T) {
+	category := &types.RiskCategory{ID: "sql-injection", Title: "SQL Injection", Description: "<b>SQL</b> injection", CWE: 89}
+	open := &types.Risk{CategoryId: category.ID, SyntheticId: "sql-injection@db", Title: "<b>SQL Injection</b> at <b>Database</b>",
+		Severity: types.HighSeverity, RiskStatus: types.Unchecked, MostRelevantTechnicalAssetId: "db"}
+	accepted := &types.Risk{CategoryId: category.ID, SyntheticId: "sql-injection@cache", Title: "SQL Injection at Cache",
+		Severity: types.MediumSeverity, RiskStatus: types.Accepted}
+	mitigated := &types.Risk{CategoryId: category.ID, SyntheticId: "sql-injection@search", Title: "SQL Injection at Search",
+		Severity: types.MediumSeverity, RiskStatus: types.Mitigated}
+	falsePositive := &types.Risk{CategoryId: category.ID, SyntheticId: "sql-injection@reports", Title: "SQL Injection at Reports",
+		Severity: types.LowSeverity, RiskStatus: types.FalsePositive}
+	parsedModel := &types.Model{
+		TechnicalAssets:          map[string]*types.TechnicalAsset{"db": {Id: "db", Title: "Database"}},
+		BuiltInRiskCategories:    types.RiskCategories{category},
+		GeneratedRisksByCategory: map[string][]*types.Risk{category.ID: {open, accepted, mitigated, falsePositive}},
+		RiskTracking:             map[string]*types.RiskTracking{accepted.SyntheticId: {SyntheticRiskId: accepted.SyntheticId, Justification: "not reachable", Status: types.Accepted}},
+	}
+
+	filename := filepath.Join(t.TempDir(), "risks.sarif")
+	assert.NoError(t, WriteRisksSARIF(common.OSFileSystem{}, parsedModel, "threagile.yaml", filename))
+	jsonBytes, err := os.ReadFile(filename)
+	assert.NoError(t, err)
+	var log sarifLog
+	assert.NoError(t, json.Unmarshal(jsonBytes, &log))
+
+	assert.Equal(t, "2.1.0", log.Version)
+	assert.Len(t, log.Runs, 1)
+	rules := log.Runs[0].Tool.Driver.Rules
+	assert.Len(t, rules, 1)
+	assert.Equal(t, "sql-injection", rules[0].Id)
+	assert.Equal(t, "SQL injection", rules[0].FullDescription.Text)
+	assert.Equal(t, "CWE-89", rules[0].Properties.CWE)
+	assert.Equal(t, "8.0", rules[0].Properties.SecuritySeverity)
+
+	results := log.Runs[0].Results
+	assert.Len(t, results, 2, "without the mitigated risks and false positives")
+	assert.Equal(t, "sql-injection@db", results[0].PartialFingerprints["syntheticId/v1"])
+	assert.Equal(t, "error", results[0].Level)
+	assert.Equal(t, "SQL Injection at Database", results[0].Message.Text)
+	assert.Equal(t, "threagile.yaml", results[0].Locations[0].PhysicalLocation.ArtifactLocation.Uri)
+	assert.Equal(t, "db", results[0].Locations[0].LogicalLocations[0].FullyQualifiedName)
+	assert.Empty(t, results[0].Suppressions)
+	assert.Equal(t, "warning", results[1].Level)
+	assert.Equal(t, "sql-injection@cache", results[1].PartialFingerprints["syntheticId/v1"])
+	assert.Equal(t, "accepted", results[1].Suppressions[0].Status)
+	assert.Equal(t, "not reachable", results[1].Suppressions[0].Justification)
+}
@@ -0,0 +1,24 @@
+package report
+
+import (
+	"context"
+
+	"github.com/threagile/threagile/pkg/common"
+	"github.com/threagile/threagile/pkg/model"
+)
+
+// Writer writes an output of the analysis result in addition to the built-in ones, e.g. in the format of a third party
+// tool; it is registered by programs embedding Threagile
+type Writer interface {
+	Name() string
+	Files(config *common.Config) []string // names of the files written into the output folder, to resume failed generations
+	Write(ctx context.Context, config *common.Config, result *model.Result) error
+}
+
+var registeredWriters = make([]Writer, 0)
+
+// RegisterWriter adds the writer, run in the order of registration after the built-in outputs (not synchronized, so
+// register the writers before generating outputs, e.g. in init functions)
+func RegisterWriter(writer Writer) {
+	registeredWriters = append(registeredWriters, writer)
+}
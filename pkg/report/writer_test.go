@@ -0,0 +1,70 @@
+package report
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/threagile/threagile/pkg/common"
+	"github.com/threagile/threagile/pkg/model"
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+type titleTestWriter struct{}
+
+func (titleTestWriter) Name() string {
+	return "title text"
+}
+
+func (titleTestWriter) Files(*common.Config) []string {
+	return []string{"title.txt"}
+}
+
+func (titleTestWriter) Write(_ context.Context, config *common.Config, result *model.Result) error {
+	return os.WriteFile(filepath.Join(config.OutputFolder, "title.txt"), []byte(result.Title), 0600)
+}
+
+func TestRegisteredWriters(t *testing.T) {
+	previousWriters := registeredWriters
+	defer func() { registeredWriters = previousWriters }()
+	RegisterWriter(titleTestWriter{})
+
+	config := new(common.Config).Defaults("")
+	config.OutputFolder = t.TempDir()
+	parsedModel := &types.Model{Title: "Writer Test", GeneratedRisksByCategory: map[string][]*types.Risk{"test-rule": {{CategoryId: "test-rule", SyntheticId: "test-rule@shop", MostRelevantTechnicalAssetId: "shop"}}}}
+	readResult := &model.ReadResult{ParsedModel: parsedModel, Result: model.NewResult(parsedModel, nil, model.Timing{})}
+
+	files, err := GenerateFiles(context.Background(), config, readResult, &GenerateCommands{RisksJSON: true}, silentProgressReporter{})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{filepath.Join(config.OutputFolder, config.JsonRisksFilename), filepath.Join(config.OutputFolder, "title.txt")}, files, "no exposure json without scanner findings")
+	title, err := os.ReadFile(filepath.Join(config.OutputFolder, "title.txt"))
+	assert.NoError(t, err)
+	assert.Equal(t, "Writer Test", string(title))
+	risks, err := ReadRisksJSON(common.OSFileSystem{}, filepath.Join(config.OutputFolder, config.JsonRisksFilename))
+	assert.NoError(t, err)
+	assert.Len(t, risks, 1)
+}
+
+func TestGenerateFilesIntoInjectedFileSystem(t *testing.T) {
+	config := new(common.Config).Defaults("")
+	config.FileSystem = common.NewMemoryFileSystem()
+	config.OutputFolder = filepath.Join(t.TempDir(), "output")
+	assert.NoError(t, config.FileSystem.MkdirAll(config.OutputFolder, 0700))
+	shop := &types.TechnicalAsset{Id: "shop", Title: "Shop", Type: types.Process}
+	parsedModel := &types.Model{Title: "File System Test", TechnicalAssets: map[string]*types.TechnicalAsset{shop.Id: shop},
+		GeneratedRisksByCategory: map[string][]*types.Risk{"test-rule": {{CategoryId: "test-rule", SyntheticId: "test-rule@shop", MostRelevantTechnicalAssetId: "shop"}}}}
+	readResult := &model.ReadResult{ParsedModel: parsedModel, Result: model.NewResult(parsedModel, nil, model.Timing{})}
+
+	commands := &GenerateCommands{RisksJSON: true, RisksSARIF: true, DueDatesICS: true, RisksExcel: true, TagsExcel: true, ExcelWorkbook: true,
+		AssetSheets: true, MitigationChecklist: true, RiskMatrix: true, RulesDoc: true}
+	files, err := GenerateFiles(context.Background(), config, readResult, commands, silentProgressReporter{})
+	assert.NoError(t, err)
+	assert.Len(t, files, 14)
+	for _, file := range files {
+		_, err = config.FileSystem.Stat(file)
+		assert.NoError(t, err, file)
+	}
+	assert.NoDirExists(t, config.OutputFolder, "nothing written to the file system of the OS")
+}
@@ -53,7 +53,7 @@ func (r *AccidentalSecretLeakRule) GenerateRisks(parsedModel *types.Model) ([]*t
 		}
 
 		var risk *types.Risk
-		if techAsset.IsTaggedWithAny("git") {
+		if techAsset.IsTaggedWithAnyInTaxonomy(parsedModel, "git") {
 			risk = r.createRisk(parsedModel, techAsset, "Git", "Git Leak Prevention")
 		} else {
 			risk = r.createRisk(parsedModel, techAsset, "", "")
@@ -131,7 +131,7 @@ func (r *AccidentalSecretLeakRule) ExplainRisk(parsedModel *types.Model, risk st
 						fmt.Sprintf("  - technology: %v (has either [%q, %q])", techAsset.Technologies.String(), types.SourcecodeRepository, types.ArtifactRegistry),
 					}...)
 
-					if techAsset.IsTaggedWithAny("git") {
+					if techAsset.IsTaggedWithAnyInTaxonomy(parsedModel, "git") {
 						explanation = append(explanation, "  is tagged with 'git'")
 					}
 
@@ -132,3 +132,32 @@ func TestAccidentalSecretLeakRuleGenerateRisksTechAssetProcessStrictlyConfidenti
 	assert.Equal(t, len(risks), 1)
 	assert.Equal(t, types.HighImpact, risks[0].ExploitationImpact)
 }
+
+func TestAccidentalSecretLeakRuleExplainRisksTaxonomyChildOfGit(t *testing.T) {
+	rule := NewAccidentalSecretLeakRule()
+	parsedModel := &types.Model{
+		TagTaxonomy: types.TagTaxonomy{
+			"git":    {},
+			"gitlab": {Parent: "git"},
+		},
+		TechnicalAssets: map[string]*types.TechnicalAsset{
+			"ta1": {
+				Id: "ta1",
+				Technologies: types.TechnologyList{
+					{
+						Name: "gitlab",
+						Attributes: map[string]bool{
+							types.SourcecodeRepository: true,
+							types.MayContainSecrets:    true,
+						},
+					},
+				},
+				Tags: []string{"gitlab"},
+			},
+		},
+	}
+
+	explanation := rule.ExplainRisk(parsedModel, rule.Category().ID+"@ta1")
+
+	assert.Contains(t, explanation, "  is tagged with 'git'")
+}
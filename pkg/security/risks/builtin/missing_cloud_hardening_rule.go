@@ -83,20 +83,20 @@ func (r *MissingCloudHardeningRule) GenerateRisks(input *types.Model) ([]*types.
 	techAssetIDsWithSubtagSpecificCloudRisks := make(map[string]bool)
 
 	for _, trustBoundary := range input.TrustBoundaries {
-		taggedOuterTB := trustBoundary.IsTaggedWithAny(r.SupportedTags()...) // false = generic cloud risks only // true = cloud-individual risks
+		taggedOuterTB := trustBoundary.IsTaggedWithAnyInTaxonomy(input, r.SupportedTags()...) // false = generic cloud risks only // true = cloud-individual risks
 		if taggedOuterTB || trustBoundary.Type.IsWithinCloud() {
-			r.addTrustBoundaryAccordingToBaseTag(trustBoundary, trustBoundariesWithUnspecificCloudRisks,
+			r.addTrustBoundaryAccordingToBaseTag(input, trustBoundary, trustBoundariesWithUnspecificCloudRisks,
 				trustBoundaryIDsAWS, trustBoundaryIDsAzure, trustBoundaryIDsGCP, trustBoundaryIDsOCP)
 			for _, techAssetID := range trustBoundary.RecursivelyAllTechnicalAssetIDsInside(input) {
 				added := false
 				tA := input.TechnicalAssets[techAssetID]
-				if tA.IsTaggedWithAny(r.SupportedTags()...) {
-					addAccordingToBaseTag(tA, tA.Tags,
+				if tA.IsTaggedWithAnyInTaxonomy(input, r.SupportedTags()...) {
+					addAccordingToBaseTag(input, tA, input.TagTaxonomy.Expand(tA.Tags),
 						techAssetIDsWithSubtagSpecificCloudRisks,
 						techAssetIDsAWS, techAssetIDsAzure, techAssetIDsGCP, techAssetIDsOCP)
 					added = true
 				} else if taggedOuterTB {
-					addAccordingToBaseTag(tA, trustBoundary.Tags,
+					addAccordingToBaseTag(input, tA, input.TagTaxonomy.Expand(trustBoundary.Tags),
 						techAssetIDsWithSubtagSpecificCloudRisks,
 						techAssetIDsAWS, techAssetIDsAzure, techAssetIDsGCP, techAssetIDsOCP)
 					added = true
@@ -109,31 +109,31 @@ func (r *MissingCloudHardeningRule) GenerateRisks(input *types.Model) ([]*types.
 	}
 
 	// now loop over all technical assets, trust boundaries, and shared runtimes model-wide by tag
-	for _, tA := range input.TechnicalAssetsTaggedWithAny(r.SupportedTags()...) {
-		addAccordingToBaseTag(tA, tA.Tags,
+	for _, tA := range input.TechnicalAssetsTaggedWithAnyInTaxonomy(r.SupportedTags()...) {
+		addAccordingToBaseTag(input, tA, input.TagTaxonomy.Expand(tA.Tags),
 			techAssetIDsWithSubtagSpecificCloudRisks,
 			techAssetIDsAWS, techAssetIDsAzure, techAssetIDsGCP, techAssetIDsOCP)
 	}
-	for _, tB := range input.TrustBoundariesTaggedWithAny(r.SupportedTags()...) {
+	for _, tB := range input.TrustBoundariesTaggedWithAnyInTaxonomy(r.SupportedTags()...) {
 		for _, candidateID := range tB.RecursivelyAllTechnicalAssetIDsInside(input) {
 			tA := input.TechnicalAssets[candidateID]
-			if tA.IsTaggedWithAny(r.SupportedTags()...) {
-				addAccordingToBaseTag(tA, tA.Tags,
+			if tA.IsTaggedWithAnyInTaxonomy(input, r.SupportedTags()...) {
+				addAccordingToBaseTag(input, tA, input.TagTaxonomy.Expand(tA.Tags),
 					techAssetIDsWithSubtagSpecificCloudRisks,
 					techAssetIDsAWS, techAssetIDsAzure, techAssetIDsGCP, techAssetIDsOCP)
 			} else {
-				addAccordingToBaseTag(tA, tB.Tags,
+				addAccordingToBaseTag(input, tA, input.TagTaxonomy.Expand(tB.Tags),
 					techAssetIDsWithSubtagSpecificCloudRisks,
 					techAssetIDsAWS, techAssetIDsAzure, techAssetIDsGCP, techAssetIDsOCP)
 			}
 		}
 	}
-	for _, sR := range input.SharedRuntimesTaggedWithAny(r.SupportedTags()...) {
-		r.addSharedRuntimeAccordingToBaseTag(sR, sharedRuntimesWithUnspecificCloudRisks,
+	for _, sR := range input.SharedRuntimesTaggedWithAnyInTaxonomy(r.SupportedTags()...) {
+		r.addSharedRuntimeAccordingToBaseTag(input, sR, sharedRuntimesWithUnspecificCloudRisks,
 			sharedRuntimeIDsAWS, sharedRuntimeIDsAzure, sharedRuntimeIDsGCP, sharedRuntimeIDsOCP)
 		for _, candidateID := range sR.TechnicalAssetsRunning {
 			tA := input.TechnicalAssets[candidateID]
-			addAccordingToBaseTag(tA, sR.Tags,
+			addAccordingToBaseTag(input, tA, input.TagTaxonomy.Expand(sR.Tags),
 				techAssetIDsWithSubtagSpecificCloudRisks,
 				techAssetIDsAWS, techAssetIDsAzure, techAssetIDsGCP, techAssetIDsOCP)
 		}
@@ -277,23 +277,24 @@ func (r *MissingCloudHardeningRule) GenerateRisks(input *types.Model) ([]*types.
 	return risks, nil
 }
 
-func (r *MissingCloudHardeningRule) addTrustBoundaryAccordingToBaseTag(trustBoundary *types.TrustBoundary,
+func (r *MissingCloudHardeningRule) addTrustBoundaryAccordingToBaseTag(input *types.Model, trustBoundary *types.TrustBoundary,
 	trustBoundariesWithUnspecificCloudRisks map[string]bool,
 	trustBoundaryIDsAWS map[string]bool,
 	trustBoundaryIDsAzure map[string]bool,
 	trustBoundaryIDsGCP map[string]bool,
 	trustBoundaryIDsOCP map[string]bool) {
-	if trustBoundary.IsTaggedWithAny(r.SupportedTags()...) {
-		if trustBoundary.IsTaggedWithBaseTag("aws") {
+	if trustBoundary.IsTaggedWithAnyInTaxonomy(input, r.SupportedTags()...) {
+		tags := input.TagTaxonomy.Expand(trustBoundary.Tags) // the provider of tags matching only through their ancestors
+		if types.IsTaggedWithBaseTag(tags, "aws") {
 			trustBoundaryIDsAWS[trustBoundary.Id] = true
 		}
-		if trustBoundary.IsTaggedWithBaseTag("azure") {
+		if types.IsTaggedWithBaseTag(tags, "azure") {
 			trustBoundaryIDsAzure[trustBoundary.Id] = true
 		}
-		if trustBoundary.IsTaggedWithBaseTag("gcp") {
+		if types.IsTaggedWithBaseTag(tags, "gcp") {
 			trustBoundaryIDsGCP[trustBoundary.Id] = true
 		}
-		if trustBoundary.IsTaggedWithBaseTag("ocp") {
+		if types.IsTaggedWithBaseTag(tags, "ocp") {
 			trustBoundaryIDsOCP[trustBoundary.Id] = true
 		}
 	} else {
@@ -301,23 +302,24 @@ func (r *MissingCloudHardeningRule) addTrustBoundaryAccordingToBaseTag(trustBoun
 	}
 }
 
-func (r *MissingCloudHardeningRule) addSharedRuntimeAccordingToBaseTag(sharedRuntime *types.SharedRuntime,
+func (r *MissingCloudHardeningRule) addSharedRuntimeAccordingToBaseTag(input *types.Model, sharedRuntime *types.SharedRuntime,
 	sharedRuntimesWithUnspecificCloudRisks map[string]bool,
 	sharedRuntimeIDsAWS map[string]bool,
 	sharedRuntimeIDsAzure map[string]bool,
 	sharedRuntimeIDsGCP map[string]bool,
 	sharedRuntimeIDsOCP map[string]bool) {
-	if sharedRuntime.IsTaggedWithAny(r.SupportedTags()...) {
-		if sharedRuntime.IsTaggedWithBaseTag("aws") {
+	if sharedRuntime.IsTaggedWithAnyInTaxonomy(input, r.SupportedTags()...) {
+		tags := input.TagTaxonomy.Expand(sharedRuntime.Tags) // the provider of tags matching only through their ancestors
+		if types.IsTaggedWithBaseTag(tags, "aws") {
 			sharedRuntimeIDsAWS[sharedRuntime.Id] = true
 		}
-		if sharedRuntime.IsTaggedWithBaseTag("azure") {
+		if types.IsTaggedWithBaseTag(tags, "azure") {
 			sharedRuntimeIDsAzure[sharedRuntime.Id] = true
 		}
-		if sharedRuntime.IsTaggedWithBaseTag("gcp") {
+		if types.IsTaggedWithBaseTag(tags, "gcp") {
 			sharedRuntimeIDsGCP[sharedRuntime.Id] = true
 		}
-		if sharedRuntime.IsTaggedWithBaseTag("ocp") {
+		if types.IsTaggedWithBaseTag(tags, "ocp") {
 			sharedRuntimeIDsOCP[sharedRuntime.Id] = true
 		}
 	} else {
@@ -325,13 +327,13 @@ func (r *MissingCloudHardeningRule) addSharedRuntimeAccordingToBaseTag(sharedRun
 	}
 }
 
-func addAccordingToBaseTag(techAsset *types.TechnicalAsset, tags []string,
+func addAccordingToBaseTag(input *types.Model, techAsset *types.TechnicalAsset, tags []string,
 	techAssetIDsWithTagSpecificCloudRisks map[string]bool,
 	techAssetIDsAWS map[string]bool,
 	techAssetIDsAzure map[string]bool,
 	techAssetIDsGCP map[string]bool,
 	techAssetIDsOCP map[string]bool) {
-	if techAsset.IsTaggedWithAny(specificSubTagsAWS...) {
+	if techAsset.IsTaggedWithAnyInTaxonomy(input, specificSubTagsAWS...) {
 		techAssetIDsWithTagSpecificCloudRisks[techAsset.Id] = true
 	}
 	if types.IsTaggedWithBaseTag(tags, "aws") {
@@ -0,0 +1,115 @@
+package builtin
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+func TestMissingCloudHardeningRuleGenerateRisksTaxonomyChildOfProviderRisksCreated(t *testing.T) {
+	rule := NewMissingCloudHardeningRule()
+
+	risks, err := rule.GenerateRisks(&types.Model{
+		TagTaxonomy: types.TagTaxonomy{
+			"aws":            {},
+			"payment-bucket": {Parent: "aws"},
+		},
+		TechnicalAssets: map[string]*types.TechnicalAsset{
+			"ta1": {
+				Id:    "ta1",
+				Title: "Payment Bucket",
+				Tags:  []string{"payment-bucket"},
+			},
+		},
+		TrustBoundaries: map[string]*types.TrustBoundary{
+			"tb1": {
+				Id:                    "tb1",
+				Title:                 "Cloud",
+				Type:                  types.NetworkCloudProvider,
+				TechnicalAssetsInside: []string{"ta1"},
+			},
+		},
+	})
+
+	assert.Nil(t, err)
+	titles := make([]string, 0)
+	for _, risk := range risks {
+		titles = append(titles, risk.Title)
+	}
+	assert.Contains(t, titles, "<b>Missing Cloud Hardening (AWS)</b> risk at <b>Payment Bucket</b>: <u>CIS Benchmark for AWS</u>")
+}
+
+func TestMissingCloudHardeningRuleGenerateRisksTaxonomyChildOfTaggedTrustBoundaryRisksCreated(t *testing.T) {
+	rule := NewMissingCloudHardeningRule()
+
+	risks, err := rule.GenerateRisks(&types.Model{
+		TagTaxonomy: types.TagTaxonomy{
+			"azure":        {},
+			"landing-zone": {Parent: "azure"},
+		},
+		TechnicalAssets: map[string]*types.TechnicalAsset{
+			"ta1": {
+				Id:    "ta1",
+				Title: "Web Server",
+			},
+		},
+		TrustBoundaries: map[string]*types.TrustBoundary{
+			"tb1": {
+				Id:                    "tb1",
+				Title:                 "Landing Zone",
+				Type:                  types.NetworkCloudProvider,
+				Tags:                  []string{"landing-zone"},
+				TechnicalAssetsInside: []string{"ta1"},
+			},
+		},
+	})
+
+	assert.Nil(t, err)
+	assert.Len(t, risks, 1)
+	assert.Equal(t, "<b>Missing Cloud Hardening (Azure)</b> risk at <b>Landing Zone</b>: <u>CIS Benchmark for Microsoft Azure</u>", risks[0].Title)
+}
+
+func TestMissingCloudHardeningRuleGenerateRisksTaxonomyChildOfProviderOutsideCloudRisksCreated(t *testing.T) {
+	rule := NewMissingCloudHardeningRule()
+
+	risks, err := rule.GenerateRisks(&types.Model{
+		TagTaxonomy: types.TagTaxonomy{
+			"gcp":         {},
+			"gke-cluster": {Parent: "gcp"},
+			"aws":         {},
+			"aws:s3":      {Parent: "aws"},
+			"report-s3":   {Parent: "aws:s3"},
+		},
+		TechnicalAssets: map[string]*types.TechnicalAsset{
+			"ta1": {
+				Id:    "ta1",
+				Title: "Report Bucket",
+				Tags:  []string{"report-s3"},
+			},
+			"ta2": {
+				Id:    "ta2",
+				Title: "Web Server",
+			},
+		},
+		SharedRuntimes: map[string]*types.SharedRuntime{
+			"sr1": {
+				Id:                     "sr1",
+				Title:                  "Cluster",
+				Tags:                   []string{"gke-cluster"},
+				TechnicalAssetsRunning: []string{"ta2"},
+			},
+		},
+	})
+
+	assert.Nil(t, err)
+	titles := make([]string, 0)
+	for _, risk := range risks {
+		titles = append(titles, risk.Title)
+	}
+	assert.ElementsMatch(t, []string{
+		"<b>Missing Cloud Hardening (GCP)</b> risk at <b>Cluster</b>: <u>CIS Benchmark for Google Cloud Computing Platform</u>",
+		"<b>Missing Cloud Hardening (AWS)</b> risk at <b>Report Bucket</b>: <u>CIS Benchmark for AWS</u>",
+		"<b>Missing Cloud Hardening (S3)</b> risk at <b>Report Bucket</b>: <u>Security Best Practices for AWS S3</u>",
+	}, titles)
+}
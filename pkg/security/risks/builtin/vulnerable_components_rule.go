@@ -0,0 +1,83 @@
+package builtin
+
+import (
+	"strconv"
+
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+type VulnerableComponentsRule struct{}
+
+func NewVulnerableComponentsRule() *VulnerableComponentsRule {
+	return &VulnerableComponentsRule{}
+}
+
+func (*VulnerableComponentsRule) Category() *types.RiskCategory {
+	return &types.RiskCategory{
+		ID:    "vulnerable-components",
+		Title: "Vulnerable Components",
+		Description: "The software bill of materials (SBOM) of the technical asset contains components with known vulnerabilities, " +
+			"which attackers can exploit with publicly available information and often with ready-made exploits.",
+		Impact:     "If this risk is unmitigated, attackers might be able to compromise the technical asset by exploiting the known vulnerabilities of its components.",
+		ASVS:       "V14 - Configuration Verification Requirements",
+		CheatSheet: "https://cheatsheetseries.owasp.org/cheatsheets/Vulnerable_Dependency_Management_Cheat_Sheet.html",
+		Action:     "Dependency Management",
+		Mitigation: "Update the vulnerable components to versions fixing the vulnerabilities, or verify and document that the vulnerabilities are " +
+			"not exploitable in the way the components are used (e.g. as VEX statement in the SBOM).",
+		Check:    "Are the vulnerable components listed in the report updated or their vulnerabilities assessed as not exploitable?",
+		Function: types.Operations,
+		STRIDE:   types.ElevationOfPrivilege,
+		DetectionLogic: "In-scope technical assets whose SBOM (CycloneDX or SPDX json referenced via 'sbom' of the technical asset) lists " +
+			"components with known vulnerabilities.",
+		RiskAssessment: "The likelihood is raised for components with vulnerabilities rated as high or critical in the SBOM, " +
+			"the impact depends on the sensitivity of the technical asset.",
+		FalsePositives:             "Vulnerabilities in code paths not used by the technical asset can be considered as false positives after individual review.",
+		ModelFailurePossibleReason: false,
+		CWE:                        1395,
+	}
+}
+
+func (*VulnerableComponentsRule) SupportedTags() []string {
+	return []string{}
+}
+
+func (r *VulnerableComponentsRule) GenerateRisks(input *types.Model) ([]*types.Risk, error) {
+	risks := make([]*types.Risk, 0)
+	for _, id := range input.SortedTechnicalAssetIDs() {
+		technicalAsset := input.TechnicalAssets[id]
+		vulnerableComponents := technicalAsset.VulnerableSBOMComponents()
+		if technicalAsset.OutOfScope || len(vulnerableComponents) == 0 {
+			continue
+		}
+
+		likelihood := types.Likely
+		for _, component := range vulnerableComponents {
+			if component.HasVulnerabilityOfSeverity("high", "critical") {
+				likelihood = types.VeryLikely
+			}
+		}
+		impact := types.MediumImpact
+		if technicalAsset.Confidentiality == types.StrictlyConfidential || technicalAsset.Integrity == types.MissionCritical || technicalAsset.Availability == types.MissionCritical {
+			impact = types.HighImpact
+		}
+		risks = append(risks, r.createRisk(technicalAsset, len(vulnerableComponents), likelihood, impact))
+	}
+	return risks, nil
+}
+
+func (r *VulnerableComponentsRule) createRisk(technicalAsset *types.TechnicalAsset, vulnerableComponents int, likelihood types.RiskExploitationLikelihood, impact types.RiskExploitationImpact) *types.Risk {
+	title := "<b>Vulnerable Components</b> in <b>" + technicalAsset.Title + "</b> (" + strconv.Itoa(vulnerableComponents) +
+		" of " + strconv.Itoa(len(technicalAsset.SBOMComponents)) + " components of the SBOM)"
+	risk := &types.Risk{
+		CategoryId:                   r.Category().ID,
+		Severity:                     types.CalculateSeverity(likelihood, impact),
+		ExploitationLikelihood:       likelihood,
+		ExploitationImpact:           impact,
+		Title:                        title,
+		MostRelevantTechnicalAssetId: technicalAsset.Id,
+		DataBreachProbability:        types.Possible,
+		DataBreachTechnicalAssetIDs:  []string{technicalAsset.Id},
+	}
+	risk.SyntheticId = risk.CategoryId + "@" + technicalAsset.Id
+	return risk
+}
@@ -0,0 +1,59 @@
+package builtin
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+func TestVulnerableComponentsRuleGenerateRisksWithoutVulnerableComponentsNotRisksCreated(t *testing.T) {
+	rule := NewVulnerableComponentsRule()
+
+	risks, err := rule.GenerateRisks(&types.Model{
+		TechnicalAssets: map[string]*types.TechnicalAsset{
+			"ta1": {
+				SBOMComponents: []*types.SBOMComponent{{Name: "gson", Version: "2.10.1"}},
+			},
+			"ta2": {
+				OutOfScope:     true,
+				SBOMComponents: []*types.SBOMComponent{{Name: "log4j-core", Vulnerabilities: []types.SBOMVulnerability{{ID: "CVE-2021-44228", Severity: "critical"}}}},
+			},
+		},
+	})
+
+	assert.Nil(t, err)
+	assert.Empty(t, risks)
+}
+
+func TestVulnerableComponentsRuleGenerateRisksRisksCreated(t *testing.T) {
+	rule := NewVulnerableComponentsRule()
+
+	risks, err := rule.GenerateRisks(&types.Model{
+		TechnicalAssets: map[string]*types.TechnicalAsset{
+			"shop": {
+				Id:    "shop",
+				Title: "Shop",
+				SBOMComponents: []*types.SBOMComponent{
+					{Name: "gson", Version: "2.10.1"},
+					{Name: "lodash", Vulnerabilities: []types.SBOMVulnerability{{ID: "CVE-2021-23337", Severity: "medium"}}},
+				},
+			},
+			"payment": {
+				Id:              "payment",
+				Title:           "Payment",
+				Confidentiality: types.StrictlyConfidential,
+				SBOMComponents:  []*types.SBOMComponent{{Name: "log4j-core", Vulnerabilities: []types.SBOMVulnerability{{ID: "CVE-2021-44228", Severity: "critical"}}}},
+			},
+		},
+	})
+
+	assert.Nil(t, err)
+	assert.Len(t, risks, 2)
+	assert.Equal(t, "vulnerable-components@payment", risks[0].SyntheticId)
+	assert.Equal(t, types.VeryLikely, risks[0].ExploitationLikelihood)
+	assert.Equal(t, types.HighImpact, risks[0].ExploitationImpact)
+	assert.Equal(t, "<b>Vulnerable Components</b> in <b>Shop</b> (1 of 2 components of the SBOM)", risks[1].Title)
+	assert.Equal(t, types.Likely, risks[1].ExploitationLikelihood)
+	assert.Equal(t, types.MediumImpact, risks[1].ExploitationImpact)
+}
@@ -3,6 +3,7 @@ package risks
 import (
 	"embed"
 	"fmt"
+	"github.com/threagile/threagile/pkg/common"
 	"github.com/threagile/threagile/pkg/script"
 	"github.com/threagile/threagile/pkg/security/risks/builtin"
 	"github.com/threagile/threagile/pkg/security/types"
@@ -51,6 +52,7 @@ func GetBuiltInRiskRules() types.RiskRules {
 		builtin.NewUnnecessaryDataTransferRule(),
 		builtin.NewUnnecessaryTechnicalAssetRule(),
 		builtin.NewUntrustedDeserializationRule(),
+		builtin.NewVulnerableComponentsRule(),
 		builtin.NewWrongCommunicationLinkContentRule(),
 		builtin.NewWrongTrustBoundaryContentRule(),
 		builtin.NewXmlExternalEntityRule(),
@@ -60,14 +62,14 @@ func GetBuiltInRiskRules() types.RiskRules {
 
 	scriptRules, scriptError := GetScriptRiskRules()
 	if scriptError != nil {
-		fmt.Printf("error loading script risk rules: %v\n", scriptError)
+		_, _ = fmt.Fprintf(common.LogStderr(), "error loading script risk rules: %v\n", scriptError)
 		return rules
 	}
 
 	for id, rule := range scriptRules {
 		builtinRule, ok := rules[id]
 		if ok && builtinRule != nil {
-			fmt.Printf("WARNING: script risk rule %q shadows built-in risk rule\n", id)
+			_, _ = fmt.Fprintf(common.LogStderr(), "WARNING: script risk rule %q shadows built-in risk rule\n", id)
 		}
 
 		rules[id] = rule
@@ -0,0 +1,34 @@
+package types
+
+import (
+	"sort"
+)
+
+// Assumption is something the threat model takes for granted or deliberately leaves out of its scope, with the owner
+// accountable for it and the rationale
+type Assumption struct {
+	Title           string   `json:"title,omitempty" yaml:"title,omitempty"`
+	Description     string   `json:"description,omitempty" yaml:"description,omitempty"`
+	Owner           string   `json:"owner,omitempty" yaml:"owner,omitempty"`
+	Rationale       string   `json:"rationale,omitempty" yaml:"rationale,omitempty"`
+	TechnicalAssets []string `json:"technical_assets,omitempty" yaml:"technical_assets,omitempty"` // the whole target if empty
+}
+
+func (parsedModel *Model) SortedAssumptions() []*Assumption {
+	return sortedAssumptions(parsedModel.Assumptions)
+}
+
+func (parsedModel *Model) SortedScopeExclusions() []*Assumption {
+	return sortedAssumptions(parsedModel.ScopeExclusions)
+}
+
+func sortedAssumptions(assumptions map[string]*Assumption) []*Assumption {
+	result := make([]*Assumption, 0, len(assumptions))
+	for _, assumption := range assumptions {
+		result = append(result, assumption)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Title < result[j].Title
+	})
+	return result
+}
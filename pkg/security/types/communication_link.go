@@ -36,6 +36,11 @@ func (what CommunicationLink) IsTaggedWithBaseTag(baseTag string) bool {
 	return IsTaggedWithBaseTag(what.Tags, baseTag)
 }
 
+// IsTaggedWithAnyInTaxonomy also matches the taxonomy ancestors of the tags
+func (what CommunicationLink) IsTaggedWithAnyInTaxonomy(model *Model, tags ...string) bool {
+	return containsCaseInsensitiveAny(model.TagTaxonomy.Expand(what.Tags), tags...)
+}
+
 func (what CommunicationLink) IsAcrossTrustBoundary(parsedModel *Model) bool {
 	trustBoundaryOfSourceAsset, trustBoundaryOfSourceAssetOk := parsedModel.DirectContainingTrustBoundaryMappedByTechnicalAssetId[what.SourceId]
 	trustBoundaryOfTargetAsset, trustBoundaryOfTargetAssetOk := parsedModel.DirectContainingTrustBoundaryMappedByTechnicalAssetId[what.TargetId]
@@ -31,6 +31,11 @@ func (what DataAsset) IsTaggedWithBaseTag(baseTag string) bool {
 	return IsTaggedWithBaseTag(what.Tags, baseTag)
 }
 
+// IsTaggedWithAnyInTaxonomy also matches the taxonomy ancestors of the tags
+func (what DataAsset) IsTaggedWithAnyInTaxonomy(model *Model, tags ...string) bool {
+	return containsCaseInsensitiveAny(model.TagTaxonomy.Expand(what.Tags), tags...)
+}
+
 func (what DataAsset) IdentifiedRisksByResponsibleTechnicalAssetId(model *Model) map[string][]*Risk {
 	uniqueTechAssetIDsResponsibleForThisDataAsset := make(map[string]interface{})
 	for _, techAsset := range what.ProcessedByTechnicalAssetsSorted(model) {
@@ -0,0 +1,57 @@
+package types
+
+import (
+	"sort"
+)
+
+// ElementAnnotation is a free-form note of a review attached to an element of the model, shown in the report but not
+// affecting the analysis
+type ElementAnnotation struct {
+	Element string `json:"element,omitempty" yaml:"element,omitempty"` // id of the annotated element
+	Note    string `json:"note,omitempty" yaml:"note,omitempty"`
+	Author  string `json:"author,omitempty" yaml:"author,omitempty"`
+	Date    Date   `json:"date,omitempty" yaml:"date,omitempty"`
+}
+
+// SortedAnnotations sorts the annotations by element and the notes of an element by date (oldest first, as they
+// usually reply to each other)
+func (parsedModel *Model) SortedAnnotations() []*ElementAnnotation {
+	result := make([]*ElementAnnotation, len(parsedModel.Annotations))
+	copy(result, parsedModel.Annotations)
+	sort.SliceStable(result, func(i, j int) bool {
+		if result[i].Element != result[j].Element {
+			return result[i].Element < result[j].Element
+		}
+		return result[i].Date.Before(result[j].Date.Time)
+	})
+	return result
+}
+
+// ElementTitle is the title of the element of the model with the id (the synthetic id of a risk being its title),
+// false if the model has no such element (anymore)
+func (parsedModel *Model) ElementTitle(id string) (string, bool) {
+	if asset, ok := parsedModel.DataAssets[id]; ok {
+		return asset.Title, true
+	}
+	if asset, ok := parsedModel.TechnicalAssets[id]; ok {
+		return asset.Title, true
+	}
+	if link, ok := parsedModel.CommunicationLinks[id]; ok {
+		return link.Title, true
+	}
+	if boundary, ok := parsedModel.TrustBoundaries[id]; ok {
+		return boundary.Title, true
+	}
+	if runtime, ok := parsedModel.SharedRuntimes[id]; ok {
+		return runtime.Title, true
+	}
+	if control, ok := parsedModel.SecurityControls[id]; ok {
+		return control.Title, true
+	}
+	for _, risk := range AllRisks(parsedModel) {
+		if risk.SyntheticId == id {
+			return risk.Title, true
+		}
+	}
+	return "", false
+}
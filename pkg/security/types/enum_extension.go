@@ -0,0 +1,90 @@
+package types
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Enum extensions let programs embedding Threagile add values to the enums, which are then accepted when parsing models
+// and listed with the built-in values (e.g. in /meta/types). Like the hooks, the registrations are not synchronized,
+// so register the values before parsing models, e.g. in init functions.
+
+type customProtocol struct {
+	TypeDescription
+	encrypted bool
+}
+
+type customTrustBoundaryType struct {
+	TypeDescription
+	networkBoundary bool
+}
+
+var (
+	customProtocols          = make([]customProtocol, 0)
+	customTrustBoundaryTypes = make([]customTrustBoundaryType, 0)
+	customTechnologies       = make(TechnologyMap)
+)
+
+// RegisterProtocol adds a protocol, e.g. a proprietary one, telling whether it is encrypted
+func RegisterProtocol(name string, description string, encrypted bool) (Protocol, error) {
+	name = strings.TrimSpace(name)
+	if err := checkCustomEnumName(name); err != nil {
+		return UnknownProtocol, err
+	}
+	if _, err := UnknownProtocol.find(name); err == nil {
+		return UnknownProtocol, fmt.Errorf("protocol %q already exists", name)
+	}
+
+	customProtocols = append(customProtocols, customProtocol{TypeDescription: TypeDescription{Name: name, Description: description}, encrypted: encrypted})
+	return Protocol(len(ProtocolTypeDescription) + len(customProtocols) - 1), nil
+}
+
+// RegisterTrustBoundaryType adds a trust boundary type, telling whether it is a network boundary (as opposed to a
+// logical group like execution-environment)
+func RegisterTrustBoundaryType(name string, description string, networkBoundary bool) (TrustBoundaryType, error) {
+	name = strings.TrimSpace(name)
+	if err := checkCustomEnumName(name); err != nil {
+		return NetworkOnPrem, err
+	}
+	if _, err := NetworkOnPrem.find(name); err == nil {
+		return NetworkOnPrem, fmt.Errorf("trust boundary type %q already exists", name)
+	}
+
+	customTrustBoundaryTypes = append(customTrustBoundaryTypes, customTrustBoundaryType{TypeDescription: TypeDescription{Name: name, Description: description}, networkBoundary: networkBoundary})
+	return TrustBoundaryType(len(TrustBoundaryTypeDescription) + len(customTrustBoundaryTypes) - 1), nil
+}
+
+// RegisterTechnology adds a technology (or replaces one of the same name) to the ones loaded from technologies.yaml,
+// before the additional technologies of the config
+func RegisterTechnology(technology Technology) error {
+	technology.Name = strings.TrimSpace(technology.Name)
+	if err := checkCustomEnumName(technology.Name); err != nil {
+		return err
+	}
+
+	customTechnologies[technology.Name] = technology
+	return nil
+}
+
+func checkCustomEnumName(name string) error {
+	if len(name) == 0 || strings.ContainsAny(name, " \t\n,") {
+		return fmt.Errorf("invalid name %q (must not be empty nor contain whitespace or commas)", name)
+	}
+	return nil
+}
+
+func (what Protocol) custom() (customProtocol, bool) {
+	index := int(what) - len(ProtocolTypeDescription)
+	if index < 0 || index >= len(customProtocols) {
+		return customProtocol{}, false
+	}
+	return customProtocols[index], true
+}
+
+func (what TrustBoundaryType) custom() (customTrustBoundaryType, bool) {
+	index := int(what) - len(TrustBoundaryTypeDescription)
+	if index < 0 || index >= len(customTrustBoundaryTypes) {
+		return customTrustBoundaryType{}, false
+	}
+	return customTrustBoundaryTypes[index], true
+}
@@ -0,0 +1,51 @@
+package types
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/threagile/threagile/pkg/common"
+)
+
+func TestRegisterCustomEnumValues(t *testing.T) {
+	t.Cleanup(func() {
+		customProtocols = make([]customProtocol, 0)
+		customTrustBoundaryTypes = make([]customTrustBoundaryType, 0)
+		customTechnologies = make(TechnologyMap)
+	})
+
+	protocol, err := RegisterProtocol("opc-ua-secure", "OPC Unified Architecture, signed and encrypted", true)
+	assert.NoError(t, err)
+	parsedProtocol, err := ParseProtocol("opc-ua-secure")
+	assert.NoError(t, err)
+	assert.Equal(t, protocol, parsedProtocol)
+	assert.True(t, parsedProtocol.IsEncrypted())
+	assert.Equal(t, "OPC Unified Architecture, signed and encrypted", parsedProtocol.Explain())
+	assert.Contains(t, ProtocolValues(), TypeEnum(protocol))
+	data, err := json.Marshal(protocol)
+	assert.NoError(t, err)
+	assert.Equal(t, `"opc-ua-secure"`, string(data))
+	var unmarshalled Protocol
+	assert.NoError(t, json.Unmarshal(data, &unmarshalled))
+	assert.Equal(t, protocol, unmarshalled)
+
+	_, err = RegisterProtocol("HTTPS", "duplicate", true)
+	assert.ErrorContains(t, err, `protocol "HTTPS" already exists`)
+	_, err = RegisterProtocol("opc ua", "", false)
+	assert.ErrorContains(t, err, "invalid name")
+
+	boundaryType, err := RegisterTrustBoundaryType("network-ot-zone", "OT zone of the IEC 62443 zone model", true)
+	assert.NoError(t, err)
+	parsedBoundaryType, err := ParseTrustBoundary("network-ot-zone")
+	assert.NoError(t, err)
+	assert.Equal(t, boundaryType, parsedBoundaryType)
+	assert.True(t, parsedBoundaryType.IsNetworkBoundary())
+	assert.Len(t, TrustBoundaryTypeValues(), len(TrustBoundaryTypeDescription)+1)
+
+	assert.NoError(t, RegisterTechnology(Technology{Name: "plc", Description: "Programmable logic controller"}))
+	technologies := make(TechnologyMap)
+	assert.NoError(t, technologies.LoadWithConfig(new(common.Config).Defaults(""), "technologies.yaml"))
+	assert.Equal(t, "Programmable logic controller", technologies["plc"].Description)
+}
@@ -6,7 +6,6 @@ package types
 
 import (
 	"fmt"
-	"regexp"
 	"slices"
 	"sort"
 	"strings"
@@ -25,6 +24,7 @@ type Model struct {
 	Author                                        input.Author                  `json:"author,omitempty" yaml:"author,omitempty"`
 	Contributors                                  []input.Author                `yaml:"contributors,omitempty" json:"contributors,omitempty"`
 	Date                                          Date                          `json:"date,omitempty" yaml:"date,omitempty"`
+	NextReviewDate                                Date                          `json:"next_review_date,omitempty" yaml:"next_review_date,omitempty"`
 	AppDescription                                input.Overview                `yaml:"application_description,omitempty" json:"application_description,omitempty"`
 	BusinessOverview                              input.Overview                `json:"business_overview,omitempty" yaml:"business_overview,omitempty"`
 	TechnicalOverview                             input.Overview                `json:"technical_overview,omitempty" yaml:"technical_overview,omitempty"`
@@ -34,6 +34,12 @@ type Model struct {
 	Questions                                     map[string]string             `json:"questions,omitempty" yaml:"questions,omitempty"`
 	AbuseCases                                    map[string]string             `json:"abuse_cases,omitempty" yaml:"abuse_cases,omitempty"`
 	TagsAvailable                                 []string                      `json:"tags_available,omitempty" yaml:"tags_available,omitempty"`
+	TagTaxonomy                                   TagTaxonomy                   `json:"tag_taxonomy,omitempty" yaml:"tag_taxonomy,omitempty"`
+	SecurityControls                              map[string]*SecurityControl   `json:"security_controls,omitempty" yaml:"security_controls,omitempty"`
+	PenTestFindings                               map[string]*PenTestFinding    `json:"pen_test_findings,omitempty" yaml:"pen_test_findings,omitempty"`
+	Assumptions                                   map[string]*Assumption        `json:"assumptions,omitempty" yaml:"assumptions,omitempty"`
+	ScopeExclusions                               map[string]*Assumption        `json:"scope_exclusions,omitempty" yaml:"scope_exclusions,omitempty"`
+	OwnerContacts                                 map[string]*OwnerContact      `json:"owner_contacts,omitempty" yaml:"owner_contacts,omitempty"`
 	DataAssets                                    map[string]*DataAsset         `json:"data_assets,omitempty" yaml:"data_assets,omitempty"`
 	TechnicalAssets                               map[string]*TechnicalAsset    `json:"technical_assets,omitempty" yaml:"technical_assets,omitempty"`
 	TrustBoundaries                               map[string]*TrustBoundary     `json:"trust_boundaries,omitempty" yaml:"trust_boundaries,omitempty"`
@@ -41,6 +47,7 @@ type Model struct {
 	CustomRiskCategories                          RiskCategories                `json:"custom_risk_categories,omitempty" yaml:"custom_risk_categories,omitempty"`
 	BuiltInRiskCategories                         RiskCategories                `json:"built_in_risk_categories,omitempty" yaml:"built_in_risk_categories,omitempty"`
 	RiskTracking                                  map[string]*RiskTracking      `json:"risk_tracking,omitempty" yaml:"risk_tracking,omitempty"`
+	Annotations                                   []*ElementAnnotation          `json:"annotations,omitempty" yaml:"annotations,omitempty"`
 	CommunicationLinks                            map[string]*CommunicationLink `json:"communication_links,omitempty" yaml:"communication_links,omitempty"`
 	AllSupportedTags                              map[string]bool               `json:"all_supported_tags,omitempty" yaml:"all_supported_tags,omitempty"`
 	DiagramTweakNodesep                           int                           `json:"diagram_tweak_nodesep,omitempty" yaml:"diagram_tweak_nodesep,omitempty"`
@@ -56,6 +63,7 @@ type Model struct {
 	DirectContainingTrustBoundaryMappedByTechnicalAssetId map[string]*TrustBoundary       `json:"direct_containing_trust_boundary_mapped_by_technical_asset_id,omitempty" yaml:"direct_containing_trust_boundary_mapped_by_technical_asset_id,omitempty"`
 	GeneratedRisksByCategory                              map[string][]*Risk              `json:"generated_risks_by_category,omitempty" yaml:"generated_risks_by_category,omitempty"`
 	GeneratedRisksBySyntheticId                           map[string]*Risk                `json:"generated_risks_by_synthetic_id,omitempty" yaml:"generated_risks_by_synthetic_id,omitempty"`
+	ScannerFindings                                       []*ScannerFinding               `json:"scanner_findings,omitempty" yaml:"scanner_findings,omitempty"`
 }
 
 func (parsedModel *Model) AddToListOfSupportedTags(tags []string) {
@@ -67,7 +75,7 @@ func (parsedModel *Model) AddToListOfSupportedTags(tags []string) {
 func (parsedModel *Model) GetDeferredRiskTrackingDueToWildcardMatching() map[string]*RiskTracking {
 	deferredRiskTrackingDueToWildcardMatching := make(map[string]*RiskTracking)
 	for syntheticRiskId, riskTracking := range parsedModel.RiskTracking {
-		if strings.Contains(syntheticRiskId, "*") { // contains a wildcard char
+		if HasRiskIdWildcard(syntheticRiskId) {
 			deferredRiskTrackingDueToWildcardMatching[syntheticRiskId] = riskTracking
 		}
 	}
@@ -104,9 +112,8 @@ func (parsedModel *Model) ApplyWildcardRiskTrackingEvaluation(ignoreOrphanedRisk
 		progressReporter.Infof("Applying wildcard risk tracking for risk id: %v", syntheticRiskIdPattern)
 
 		foundSome := false
-		var matchingRiskIdExpression = regexp.MustCompile(strings.ReplaceAll(regexp.QuoteMeta(syntheticRiskIdPattern), `\*`, `[^@]+`))
-		for syntheticRiskId := range parsedModel.GeneratedRisksBySyntheticId {
-			if matchingRiskIdExpression.Match([]byte(syntheticRiskId)) && parsedModel.HasNotYetAnyDirectNonWildcardRiskTracking(syntheticRiskId) {
+		for _, syntheticRiskId := range parsedModel.RiskIdsMatching(syntheticRiskIdPattern) {
+			if parsedModel.HasNotYetAnyDirectNonWildcardRiskTracking(syntheticRiskId) {
 				foundSome = true
 				parsedModel.RiskTracking[syntheticRiskId] = &RiskTracking{
 					SyntheticRiskId: strings.TrimSpace(syntheticRiskId),
@@ -115,6 +122,8 @@ func (parsedModel *Model) ApplyWildcardRiskTrackingEvaluation(ignoreOrphanedRisk
 					Ticket:          riskTracking.Ticket,
 					Status:          riskTracking.Status,
 					Date:            riskTracking.Date,
+					Assignee:        riskTracking.Assignee,
+					DueDate:         riskTracking.DueDate,
 				}
 			}
 		}
@@ -132,10 +141,19 @@ func (parsedModel *Model) ApplyWildcardRiskTrackingEvaluation(ignoreOrphanedRisk
 
 func (parsedModel *Model) CheckRiskTracking(ignoreOrphanedRiskTracking bool, progressReporter ProgressReporter) error {
 	progressReporter.Info("Checking risk tracking")
+	mergedRiskIds := make(map[string]bool) // of risks merged into others, whose tracking is kept
+	for _, risk := range parsedModel.GeneratedRisksBySyntheticId {
+		for _, mergedRiskId := range risk.MergedRiskIds {
+			mergedRiskIds[strings.ToLower(mergedRiskId)] = true
+		}
+	}
 	for _, tracking := range parsedModel.RiskTracking {
+		if mergedRiskIds[strings.ToLower(tracking.SyntheticRiskId)] {
+			continue
+		}
 		if _, ok := parsedModel.GeneratedRisksBySyntheticId[tracking.SyntheticRiskId]; !ok {
 			if ignoreOrphanedRiskTracking {
-				progressReporter.Infof("Risk tracking references unknown risk (risk id not found): %v", tracking.SyntheticRiskId)
+				progressReporter.Warnf("Risk tracking references unknown risk (risk id not found): %v", tracking.SyntheticRiskId)
 			} else {
 				return fmt.Errorf("Risk tracking references unknown risk (risk id not found) - you might want to use the option -ignore-orphaned-risk-tracking: %v"+
 					"\n\nNOTE: For risk tracking each risk-id needs to be defined (the string with the @ sign in it). "+
@@ -161,6 +179,19 @@ func (parsedModel *Model) CheckTagExists(referencedTag, where string) error {
 	return nil
 }
 
+func (parsedModel *Model) CheckTagsAvailableInTaxonomy() error {
+	if parsedModel.TagTaxonomy.IsEmpty() {
+		return nil
+	}
+
+	for _, tag := range parsedModel.TagsAvailable {
+		if !parsedModel.TagTaxonomy.Contains(tag) {
+			return fmt.Errorf("tag not defined in tag taxonomy: %v", tag)
+		}
+	}
+	return nil
+}
+
 func (parsedModel *Model) CheckDataAssetTargetExists(referencedAsset, where string) error {
 	if _, ok := parsedModel.DataAssets[referencedAsset]; !ok {
 		return fmt.Errorf("missing referenced data asset target at %v: %v", where, referencedAsset)
@@ -247,6 +278,17 @@ func (parsedModel *Model) SortedTechnicalAssetIDs() []string {
 	return res
 }
 
+func (parsedModel *Model) TagsNotUsed() []string {
+	used := parsedModel.TagsActuallyUsed()
+	result := make([]string, 0)
+	for _, tag := range parsedModel.TagsAvailable {
+		if !slices.Contains(used, tag) {
+			result = append(result, tag)
+		}
+	}
+	return result
+}
+
 func (parsedModel *Model) TagsActuallyUsed() []string {
 	result := make([]string, 0)
 	for _, tag := range parsedModel.TagsAvailable {
@@ -261,6 +303,72 @@ func (parsedModel *Model) TagsActuallyUsed() []string {
 	return result
 }
 
+func (parsedModel *Model) Owners() []string {
+	owners := make(map[string]bool)
+	for _, asset := range parsedModel.TechnicalAssets {
+		if len(asset.Owner) > 0 {
+			owners[asset.Owner] = true
+		}
+	}
+	for _, asset := range parsedModel.DataAssets {
+		if len(asset.Owner) > 0 {
+			owners[asset.Owner] = true
+		}
+	}
+
+	result := make([]string, 0, len(owners))
+	for owner := range owners {
+		result = append(result, owner)
+	}
+	sort.Strings(result)
+	return result
+}
+
+// OwnerWithContact appends the contact info looked up in the owner directory (if any)
+func (parsedModel *Model) OwnerWithContact(owner string) string {
+	if contact, ok := parsedModel.OwnerContacts[owner]; ok && contact != nil {
+		if details := contact.String(); len(details) > 0 {
+			return owner + " (" + details + ")"
+		}
+	}
+	return owner
+}
+
+// RiskOwners returns the owners of the most relevant assets of the generated risks, sorted
+func (parsedModel *Model) RiskOwners() []string {
+	owners := make(map[string]bool)
+	for _, categoryRisks := range parsedModel.GeneratedRisksByCategory {
+		for _, risk := range categoryRisks {
+			if owner := risk.Owner(parsedModel); len(owner) > 0 {
+				owners[owner] = true
+			}
+		}
+	}
+
+	result := make([]string, 0, len(owners))
+	for owner := range owners {
+		result = append(result, owner)
+	}
+	sort.Strings(result)
+	return result
+}
+
+// RisksOfOwner returns a shallow copy of the model that only contains the generated risks of the given owner
+func (parsedModel *Model) RisksOfOwner(owner string) *Model {
+	result := *parsedModel
+	result.GeneratedRisksByCategory = make(map[string][]*Risk)
+	result.GeneratedRisksBySyntheticId = make(map[string]*Risk)
+	for categoryId, categoryRisks := range parsedModel.GeneratedRisksByCategory {
+		for _, risk := range categoryRisks {
+			if strings.EqualFold(risk.Owner(parsedModel), owner) {
+				result.GeneratedRisksByCategory[categoryId] = append(result.GeneratedRisksByCategory[categoryId], risk)
+				result.GeneratedRisksBySyntheticId[strings.ToLower(risk.SyntheticId)] = risk
+			}
+		}
+	}
+	return &result
+}
+
 func (parsedModel *Model) TechnicalAssetsTaggedWithAny(tags ...string) []*TechnicalAsset {
 	result := make([]*TechnicalAsset, 0)
 	for _, candidate := range parsedModel.TechnicalAssets {
@@ -313,6 +421,39 @@ func (parsedModel *Model) SharedRuntimesTaggedWithAny(tags ...string) []*SharedR
 	return result
 }
 
+// TechnicalAssetsTaggedWithAnyInTaxonomy also matches the taxonomy ancestors of the tags
+func (parsedModel *Model) TechnicalAssetsTaggedWithAnyInTaxonomy(tags ...string) []*TechnicalAsset {
+	result := make([]*TechnicalAsset, 0)
+	for _, candidate := range parsedModel.TechnicalAssets {
+		if candidate.IsTaggedWithAnyInTaxonomy(parsedModel, tags...) {
+			result = append(result, candidate)
+		}
+	}
+	return result
+}
+
+// TrustBoundariesTaggedWithAnyInTaxonomy also matches the taxonomy ancestors of the tags
+func (parsedModel *Model) TrustBoundariesTaggedWithAnyInTaxonomy(tags ...string) []*TrustBoundary {
+	result := make([]*TrustBoundary, 0)
+	for _, candidate := range parsedModel.TrustBoundaries {
+		if candidate.IsTaggedWithAnyInTaxonomy(parsedModel, tags...) {
+			result = append(result, candidate)
+		}
+	}
+	return result
+}
+
+// SharedRuntimesTaggedWithAnyInTaxonomy also matches the taxonomy ancestors of the tags
+func (parsedModel *Model) SharedRuntimesTaggedWithAnyInTaxonomy(tags ...string) []*SharedRuntime {
+	result := make([]*SharedRuntime, 0)
+	for _, candidate := range parsedModel.SharedRuntimes {
+		if candidate.IsTaggedWithAnyInTaxonomy(parsedModel, tags...) {
+			result = append(result, candidate)
+		}
+	}
+	return result
+}
+
 func (parsedModel *Model) OutOfScopeTechnicalAssets() []*TechnicalAsset {
 	assets := make([]*TechnicalAsset, 0)
 	for _, asset := range parsedModel.TechnicalAssets {
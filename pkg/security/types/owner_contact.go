@@ -0,0 +1,26 @@
+package types
+
+import "strings"
+
+type OwnerContact struct {
+	Name       string `json:"name,omitempty" yaml:"name,omitempty"`
+	Email      string `json:"email,omitempty" yaml:"email,omitempty"`
+	Phone      string `json:"phone,omitempty" yaml:"phone,omitempty"`
+	Department string `json:"department,omitempty" yaml:"department,omitempty"`
+	Active     *bool  `json:"active,omitempty" yaml:"active,omitempty"` // missing means active, as not all directories know
+}
+
+// IsActive is false only for contacts marked inactive by the directory
+func (what OwnerContact) IsActive() bool {
+	return what.Active == nil || *what.Active
+}
+
+func (what OwnerContact) String() string {
+	parts := make([]string, 0)
+	for _, part := range []string{what.Name, what.Email, what.Phone, what.Department} {
+		if len(strings.TrimSpace(part)) > 0 {
+			parts = append(parts, strings.TrimSpace(part))
+		}
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,26 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOwnerWithContact(t *testing.T) {
+	model := &Model{
+		TechnicalAssets: map[string]*TechnicalAsset{"a": {Owner: "Team B"}, "b": {Owner: "Team A"}},
+		DataAssets:      map[string]*DataAsset{"c": {Owner: "Team A"}},
+		OwnerContacts:   map[string]*OwnerContact{"Team A": {Name: "Alice", Email: "alice@example.com"}},
+	}
+
+	assert.Equal(t, []string{"Team A", "Team B"}, model.Owners())
+	assert.Equal(t, "Team A (Alice, alice@example.com)", model.OwnerWithContact("Team A"))
+	assert.Equal(t, "Team B", model.OwnerWithContact("Team B"))
+}
+
+func TestOwnerContactIsActive(t *testing.T) {
+	active, inactive := true, false
+	assert.True(t, OwnerContact{Name: "Alice"}.IsActive(), "unless marked inactive")
+	assert.True(t, OwnerContact{Name: "Alice", Active: &active}.IsActive())
+	assert.False(t, OwnerContact{Name: "Bob", Active: &inactive}.IsActive())
+}
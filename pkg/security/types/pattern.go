@@ -0,0 +1,70 @@
+package types
+
+import (
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// IsSkippedRiskRule checks a risk rule ID against skip patterns like "unencrypted-*" (see path.Match for the syntax)
+func IsSkippedRiskRule(skipRiskRules []string, id string) bool {
+	return len(SkipPatternsMatching(skipRiskRules, id)) > 0
+}
+
+// SkipPatternsMatching returns all skip patterns matching the given risk rule ID
+func SkipPatternsMatching(skipRiskRules []string, id string) []string {
+	result := make([]string, 0)
+	for _, pattern := range skipRiskRules {
+		pattern = strings.TrimSpace(pattern)
+		if len(pattern) == 0 {
+			continue
+		}
+		if matched, matchError := path.Match(pattern, id); matchError == nil && matched {
+			result = append(result, pattern)
+		}
+	}
+	return result
+}
+
+// RiskRulesMatching returns the sorted IDs of all rules matching the given skip pattern
+func RiskRulesMatching(pattern string, rules ...RiskRules) []string {
+	result := make([]string, 0)
+	for _, ruleSet := range rules {
+		for id := range ruleSet {
+			if IsSkippedRiskRule([]string{pattern}, id) && !contains(result, id) {
+				result = append(result, id)
+			}
+		}
+	}
+	sort.Strings(result)
+	return result
+}
+
+// HasRiskIdWildcard checks whether a synthetic risk ID used in risk tracking is a pattern
+func HasRiskIdWildcard(syntheticRiskId string) bool {
+	return strings.ContainsAny(syntheticRiskId, "*?")
+}
+
+// RiskIdPattern compiles a synthetic risk ID pattern: "*" matches within one @-delimited part,
+// "**" matches across parts and "?" matches a single character
+func RiskIdPattern(syntheticRiskIdPattern string) *regexp.Regexp {
+	expression := regexp.QuoteMeta(syntheticRiskIdPattern)
+	expression = strings.ReplaceAll(expression, `\*\*`, `.+`)
+	expression = strings.ReplaceAll(expression, `\*`, `[^@]+`)
+	expression = strings.ReplaceAll(expression, `\?`, `[^@]`)
+	return regexp.MustCompile("^" + expression + "$")
+}
+
+// RiskIdsMatching returns the sorted synthetic IDs of all generated risks matching the given pattern
+func (parsedModel *Model) RiskIdsMatching(syntheticRiskIdPattern string) []string {
+	matchingRiskIdExpression := RiskIdPattern(syntheticRiskIdPattern)
+	result := make([]string, 0)
+	for syntheticRiskId := range parsedModel.GeneratedRisksBySyntheticId {
+		if matchingRiskIdExpression.MatchString(syntheticRiskId) {
+			result = append(result, syntheticRiskId)
+		}
+	}
+	sort.Strings(result)
+	return result
+}
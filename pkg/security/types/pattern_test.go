@@ -0,0 +1,30 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsSkippedRiskRule(t *testing.T) {
+	assert.True(t, IsSkippedRiskRule([]string{"unencrypted-*"}, "unencrypted-asset"))
+	assert.True(t, IsSkippedRiskRule([]string{"", " sql-nosql-injection "}, "sql-nosql-injection"))
+	assert.False(t, IsSkippedRiskRule([]string{"unencrypted-*"}, "missing-waf"))
+	assert.Equal(t, []string{"unencrypted-*", "*-asset"}, SkipPatternsMatching([]string{"unencrypted-*", "*-asset", "missing-*"}, "unencrypted-asset"))
+}
+
+func TestRiskIdsMatching(t *testing.T) {
+	model := &Model{GeneratedRisksBySyntheticId: map[string]*Risk{
+		"unencrypted-asset@db":                 {},
+		"unencrypted-communication@a>b@a@b":    {},
+		"missing-authentication@a>b@a@b":       {},
+		"missing-authentication@a>c@a@c":       {},
+		"missing-authentication-second@x@y@z1": {},
+	}}
+
+	assert.Equal(t, []string{"unencrypted-asset@db"}, model.RiskIdsMatching("unencrypted-*@*"))
+	assert.Equal(t, []string{"unencrypted-asset@db", "unencrypted-communication@a>b@a@b"}, model.RiskIdsMatching("unencrypted-**"))
+	assert.Equal(t, []string{"missing-authentication@a>b@a@b", "missing-authentication@a>c@a@c"}, model.RiskIdsMatching("missing-authentication@*@a@?"))
+	assert.True(t, HasRiskIdWildcard("missing-authentication@*"))
+	assert.False(t, HasRiskIdWildcard("missing-authentication@a"))
+}
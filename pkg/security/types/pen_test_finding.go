@@ -0,0 +1,84 @@
+package types
+
+import (
+	"sort"
+)
+
+const (
+	PenTestConfirmed    = "confirmed"     // the tester was able to exploit the risk
+	PenTestNotConfirmed = "not-confirmed" // the tester tried but was not able to exploit the risk
+)
+
+// PenTestFinding is a finding of an external penetration test, linking the test to the technical assets and risk
+// categories it covered as evidence of whether the modeled risks are exploitable
+type PenTestFinding struct {
+	Id              string       `json:"id,omitempty" yaml:"id,omitempty"`
+	Title           string       `json:"title,omitempty" yaml:"title,omitempty"`
+	Description     string       `json:"description,omitempty" yaml:"description,omitempty"`
+	Date            Date         `json:"date,omitempty" yaml:"date,omitempty"`
+	Tester          string       `json:"tester,omitempty" yaml:"tester,omitempty"`
+	Reference       string       `json:"reference,omitempty" yaml:"reference,omitempty"` // e.g. section or ticket of the pen-test report
+	Severity        RiskSeverity `json:"severity,omitempty" yaml:"severity,omitempty"`
+	Confirmed       bool         `json:"confirmed" yaml:"confirmed"`
+	TechnicalAssets []string     `json:"technical_assets,omitempty" yaml:"technical_assets,omitempty"` // all technical assets if empty
+	RiskCategories  []string     `json:"risk_categories,omitempty" yaml:"risk_categories,omitempty"`
+}
+
+// Covers checks whether the finding covers the risk, i.e. its category and (unless the finding is not limited to
+// technical assets) its most relevant technical asset
+func (what PenTestFinding) Covers(risk *Risk) bool {
+	if !contains(what.RiskCategories, risk.CategoryId) {
+		return false
+	}
+	return len(what.TechnicalAssets) == 0 || contains(what.TechnicalAssets, risk.MostRelevantTechnicalAssetId)
+}
+
+// Result is the validation result of the finding as in the model
+func (what PenTestFinding) Result() string {
+	if what.Confirmed {
+		return PenTestConfirmed
+	}
+	return PenTestNotConfirmed
+}
+
+func (parsedModel *Model) SortedPenTestFindings() []*PenTestFinding {
+	result := make([]*PenTestFinding, 0, len(parsedModel.PenTestFindings))
+	for _, finding := range parsedModel.PenTestFindings {
+		result = append(result, finding)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if !result[i].Date.Equal(result[j].Date.Time) {
+			return result[i].Date.After(result[j].Date.Time)
+		}
+		return result[i].Title < result[j].Title
+	})
+	return result
+}
+
+// ApplyPenTestFindings links the generated risks with the pen-test findings covering them as validation evidence
+func (parsedModel *Model) ApplyPenTestFindings() {
+	for _, risks := range parsedModel.GeneratedRisksByCategory {
+		for _, risk := range risks {
+			risk.PenTestFindings = nil
+			for _, finding := range parsedModel.SortedPenTestFindings() {
+				if finding.Covers(risk) {
+					risk.PenTestFindings = append(risk.PenTestFindings, finding.Id)
+				}
+			}
+		}
+	}
+}
+
+// PenTestValidation tells whether the risk has been confirmed by any pen-test finding covering it, or not confirmed by
+// all of them, and is empty for risks not covered by pen-tests
+func (what Risk) PenTestValidation(model *Model) string {
+	if len(what.PenTestFindings) == 0 {
+		return ""
+	}
+	for _, id := range what.PenTestFindings {
+		if finding, ok := model.PenTestFindings[id]; ok && finding.Confirmed {
+			return PenTestConfirmed
+		}
+	}
+	return PenTestNotConfirmed
+}
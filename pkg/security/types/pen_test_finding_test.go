@@ -0,0 +1,29 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApplyPenTestFindings(t *testing.T) {
+	webXSS := &Risk{CategoryId: "xss", MostRelevantTechnicalAssetId: "web"}
+	adminXSS := &Risk{CategoryId: "xss", MostRelevantTechnicalAssetId: "admin"}
+	webSQLi := &Risk{CategoryId: "sql-nosql-injection", MostRelevantTechnicalAssetId: "web"}
+	model := &Model{
+		PenTestFindings: map[string]*PenTestFinding{
+			"stored-xss":   {Id: "stored-xss", Title: "Stored XSS", Confirmed: true, TechnicalAssets: []string{"web"}, RiskCategories: []string{"xss"}},
+			"xss-attempts": {Id: "xss-attempts", Title: "XSS Attempts", RiskCategories: []string{"xss"}},
+		},
+		GeneratedRisksByCategory: map[string][]*Risk{"xss": {webXSS, adminXSS}, "sql-nosql-injection": {webSQLi}},
+	}
+
+	model.ApplyPenTestFindings()
+
+	assert.Equal(t, []string{"stored-xss", "xss-attempts"}, webXSS.PenTestFindings)
+	assert.Equal(t, PenTestConfirmed, webXSS.PenTestValidation(model))
+	assert.Equal(t, []string{"xss-attempts"}, adminXSS.PenTestFindings)
+	assert.Equal(t, PenTestNotConfirmed, adminXSS.PenTestValidation(model))
+	assert.Empty(t, webSQLi.PenTestFindings)
+	assert.Empty(t, webSQLi.PenTestValidation(model))
+}
@@ -64,7 +64,7 @@ const (
 )
 
 func ProtocolValues() []TypeEnum {
-	return []TypeEnum{
+	values := []TypeEnum{
 		UnknownProtocol,
 		HTTP,
 		HTTPS,
@@ -113,6 +113,10 @@ func ProtocolValues() []TypeEnum {
 		InProcessLibraryCall,
 		ContainerSpawning,
 	}
+	for index := range customProtocols {
+		values = append(values, Protocol(len(ProtocolTypeDescription)+index))
+	}
+	return values
 }
 
 var ProtocolTypeDescription = [...]TypeDescription{
@@ -175,12 +179,34 @@ func ParseProtocol(value string) (protocol Protocol, err error) {
 	return protocol, fmt.Errorf("unable to parse into type: %v", value)
 }
 
+// wellKnownPortProtocols are the protocols usually spoken on the well-known ports
+var wellKnownPortProtocols = map[int]Protocol{
+	21: FTP, 22: SSH, 25: SMTP, 80: HTTP, 389: LDAP, 443: HTTPS, 445: SMB, 465: SmtpEncrypted, 587: SmtpEncrypted,
+	636: LDAPS, 1433: SqlAccessProtocol, 1521: SqlAccessProtocol, 2049: NFS, 3306: SqlAccessProtocol,
+	5432: SqlAccessProtocol, 6379: NosqlAccessProtocol, 8080: HTTP, 8443: HTTPS, 9042: NosqlAccessProtocol,
+	27017: NosqlAccessProtocol,
+}
+
+// ProtocolOfPort guesses the protocol from the port (e.g. of a firewall rule), unknown-protocol when not well-known
+func ProtocolOfPort(port int) Protocol {
+	if protocol, ok := wellKnownPortProtocols[port]; ok {
+		return protocol
+	}
+	return UnknownProtocol
+}
+
 func (what Protocol) String() string {
 	// NOTE: maintain list also in schema.json for validation in IDEs
+	if custom, ok := what.custom(); ok {
+		return custom.Name
+	}
 	return ProtocolTypeDescription[what].Name
 }
 
 func (what Protocol) Explain() string {
+	if custom, ok := what.custom(); ok {
+		return custom.Description
+	}
 	return ProtocolTypeDescription[what].Description
 }
 
@@ -189,6 +215,9 @@ func (what Protocol) IsProcessLocal() bool {
 }
 
 func (what Protocol) IsEncrypted() bool {
+	if custom, ok := what.custom(); ok {
+		return custom.encrypted
+	}
 	return what == HTTPS || what == WSS || what == JdbcEncrypted || what == OdbcEncrypted ||
 		what == NosqlAccessProtocolEncrypted || what == SqlAccessProtocolEncrypted || what == BinaryEncrypted || what == TextEncrypted || what == SSH || what == SshTunnel ||
 		what == FTPS || what == SFTP || what == SCP || what == LDAPS || what == ReverseProxyWebProtocolEncrypted ||
@@ -249,6 +278,11 @@ func (what Protocol) find(value string) (Protocol, error) {
 			return Protocol(index), nil
 		}
 	}
+	for index, custom := range customProtocols {
+		if strings.EqualFold(value, custom.Name) {
+			return Protocol(len(ProtocolTypeDescription) + index), nil
+		}
+	}
 
 	return Protocol(0), fmt.Errorf("unknown protocol value %q", value)
 }
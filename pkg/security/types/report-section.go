@@ -0,0 +1,13 @@
+package types
+
+// ReportSection is an organization-specific chapter (e.g. a mapping to internal controls) added to the report
+type ReportSection interface {
+	Title() string
+	GenerateParagraphs(*Model) ([]*ReportParagraph, error)
+}
+
+// ReportParagraph is a part of a report section, the text may use the basic html tags <b>, <i>, <u> and <br>
+type ReportParagraph struct {
+	Headline string `json:"headline,omitempty" yaml:"headline,omitempty"`
+	Text     string `json:"text,omitempty" yaml:"text,omitempty"`
+}
@@ -0,0 +1,52 @@
+package types
+
+import "time"
+
+// ApplyResidualSeverities sets the tracking status (with assignee and due date) and residual severity of all risks: their
+// (control adjusted) severity, lowered to low once risk tracking marks them as mitigated or false positive
+func (parsedModel *Model) ApplyResidualSeverities() {
+	for _, risks := range parsedModel.GeneratedRisksByCategory {
+		for _, risk := range risks {
+			tracking := risk.GetRiskTrackingWithDefault(parsedModel)
+			risk.RiskStatus = tracking.Status
+			risk.Assignee = tracking.Assignee
+			risk.DueDate = nil
+			if !tracking.DueDate.IsZero() {
+				risk.DueDate = &Date{Time: tracking.DueDate.Time}
+			}
+			risk.ResidualSeverity = risk.Severity
+			if !risk.RiskStatus.IsStillAtRisk() {
+				risk.ResidualSeverity = LowSeverity
+			}
+		}
+	}
+}
+
+// OverdueRisks are the risks past the due date of their risk tracking, see RiskTracking.IsOverdue
+func OverdueRisks(parsedModel *Model, now time.Time) []*Risk {
+	result := make([]*Risk, 0)
+	for _, risk := range AllRisks(parsedModel) {
+		if risk.GetRiskTrackingWithDefault(parsedModel).IsOverdue(now) {
+			result = append(result, risk)
+		}
+	}
+	return result
+}
+
+// CountByInherentSeverity counts all risks per inherent severity
+func CountByInherentSeverity(parsedModel *Model) map[RiskSeverity]int {
+	result := make(map[RiskSeverity]int)
+	for _, risk := range AllRisks(parsedModel) {
+		result[risk.InherentSeverity]++
+	}
+	return result
+}
+
+// CountByResidualSeverity counts all risks per residual severity
+func CountByResidualSeverity(parsedModel *Model) map[RiskSeverity]int {
+	result := make(map[RiskSeverity]int)
+	for _, risk := range AllRisks(parsedModel) {
+		result[risk.ResidualSeverity]++
+	}
+	return result
+}
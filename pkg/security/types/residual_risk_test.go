@@ -0,0 +1,46 @@
+package types
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApplyResidualSeverities(t *testing.T) {
+	open := &Risk{SyntheticId: "open", InherentSeverity: HighSeverity, Severity: ElevatedSeverity}
+	mitigated := &Risk{SyntheticId: "mitigated", InherentSeverity: HighSeverity, Severity: HighSeverity}
+	model := &Model{
+		GeneratedRisksByCategory: map[string][]*Risk{"rule": {open, mitigated}},
+		RiskTracking:             map[string]*RiskTracking{"mitigated": {SyntheticRiskId: "mitigated", Status: Mitigated}},
+	}
+
+	model.ApplyResidualSeverities()
+
+	assert.Equal(t, ElevatedSeverity, open.ResidualSeverity)
+	assert.Equal(t, LowSeverity, mitigated.ResidualSeverity)
+	assert.Equal(t, map[RiskSeverity]int{HighSeverity: 2}, CountByInherentSeverity(model))
+	assert.Equal(t, map[RiskSeverity]int{ElevatedSeverity: 1, LowSeverity: 1}, CountByResidualSeverity(model))
+}
+
+func TestOverdueRisks(t *testing.T) {
+	dueDate := Date{Time: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)}
+	open := &Risk{SyntheticId: "open"}
+	mitigated := &Risk{SyntheticId: "mitigated"}
+	unassigned := &Risk{SyntheticId: "unassigned"}
+	model := &Model{
+		GeneratedRisksByCategory: map[string][]*Risk{"rule": {open, mitigated, unassigned}},
+		RiskTracking: map[string]*RiskTracking{
+			"open":      {SyntheticRiskId: "open", Status: InProgress, Assignee: "Alice", DueDate: dueDate},
+			"mitigated": {SyntheticRiskId: "mitigated", Status: Mitigated, Assignee: "Bob", DueDate: dueDate},
+		},
+	}
+
+	model.ApplyResidualSeverities()
+
+	assert.Equal(t, "Alice", open.Assignee)
+	assert.Equal(t, &dueDate, open.DueDate)
+	assert.Nil(t, unassigned.DueDate)
+	assert.Empty(t, OverdueRisks(model, time.Date(2024, 3, 1, 23, 0, 0, 0, time.UTC)), "due at the end of the due date")
+	assert.Equal(t, []*Risk{open}, OverdueRisks(model, time.Date(2024, 3, 2, 1, 0, 0, 0, time.UTC)))
+}
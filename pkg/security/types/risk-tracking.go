@@ -1,5 +1,7 @@
 package types
 
+import "time"
+
 type RiskTracking struct {
 	SyntheticRiskId string     `json:"synthetic_risk_id,omitempty" yaml:"synthetic_risk_id,omitempty"`
 	Justification   string     `json:"justification,omitempty" yaml:"justification,omitempty"`
@@ -7,4 +9,25 @@ type RiskTracking struct {
 	CheckedBy       string     `json:"checked_by,omitempty" yaml:"checked_by,omitempty"`
 	Status          RiskStatus `json:"status,omitempty" yaml:"status,omitempty"`
 	Date            Date       `json:"date,omitempty" yaml:"date,omitempty"`
+	Assignee        string     `json:"assignee,omitempty" yaml:"assignee,omitempty"`
+	DueDate         Date       `json:"due_date,omitempty" yaml:"due_date,omitempty"`
+}
+
+// IsDue is true for risks with a due date not handled yet, i.e. still unchecked, in discussion or in progress
+func (what RiskTracking) IsDue() bool {
+	if what.DueDate.IsZero() {
+		return false
+	}
+	switch what.Status {
+	case Unchecked, InDiscussion, InProgress:
+		return true
+	default:
+		return false
+	}
+}
+
+// IsOverdue is true once the due date has passed without the risk being handled (the due date itself is the last day
+// to handle it)
+func (what RiskTracking) IsOverdue(now time.Time) bool {
+	return what.IsDue() && what.DueDate.AddDate(0, 0, 1).Before(now)
 }
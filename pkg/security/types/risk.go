@@ -3,7 +3,11 @@ package types
 type Risk struct {
 	CategoryId                      string                     `yaml:"category,omitempty" json:"category,omitempty"`       // used for better JSON marshalling, is assigned in risk evaluation phase automatically
 	RiskStatus                      RiskStatus                 `yaml:"risk_status,omitempty" json:"risk_status,omitempty"` // used for better JSON marshalling, is assigned in risk evaluation phase automatically
+	Assignee                        string                     `yaml:"assignee,omitempty" json:"assignee,omitempty"`       // from risk tracking, is assigned in risk evaluation phase automatically
+	DueDate                         *Date                      `yaml:"due_date,omitempty" json:"due_date,omitempty"`       // from risk tracking, is assigned in risk evaluation phase automatically
 	Severity                        RiskSeverity               `yaml:"severity,omitempty" json:"severity,omitempty"`
+	InherentSeverity                RiskSeverity               `yaml:"inherent_severity" json:"inherent_severity"` // as computed by the rule, before threat intel, controls and recalibration
+	ResidualSeverity                RiskSeverity               `yaml:"residual_severity" json:"residual_severity"` // after controls and risk tracking
 	ExploitationLikelihood          RiskExploitationLikelihood `yaml:"exploitation_likelihood,omitempty" json:"exploitation_likelihood,omitempty"`
 	ExploitationImpact              RiskExploitationImpact     `yaml:"exploitation_impact,omitempty" json:"exploitation_impact,omitempty"`
 	Title                           string                     `yaml:"title,omitempty" json:"title,omitempty"`
@@ -15,6 +19,10 @@ type Risk struct {
 	MostRelevantCommunicationLinkId string                     `yaml:"most_relevant_communication_link,omitempty" json:"most_relevant_communication_link,omitempty"`
 	DataBreachProbability           DataBreachProbability      `yaml:"data_breach_probability,omitempty" json:"data_breach_probability,omitempty"`
 	DataBreachTechnicalAssetIDs     []string                   `yaml:"data_breach_technical_assets,omitempty" json:"data_breach_technical_assets,omitempty"`
+	MergedRiskIds                   []string                   `yaml:"merged_risks,omitempty" json:"merged_risks,omitempty"` // synthetic IDs of duplicate risks collapsed into this one
+	AppliedSecurityControls         []string                   `yaml:"applied_security_controls,omitempty" json:"applied_security_controls,omitempty"`
+	PenTestFindings                 []string                   `yaml:"pen_test_findings,omitempty" json:"pen_test_findings,omitempty"` // IDs of the pen-test findings covering the risk
+	Comments                        []RiskComment              `yaml:"comments,omitempty" json:"comments,omitempty"`                   // in the order they were written, including the ones of the merged duplicates
 	// TODO: refactor all "ID" here to "ID"?
 }
 
@@ -38,3 +46,26 @@ func (what Risk) IsRiskTracked(model *Model) bool {
 	}
 	return false
 }
+
+// Owner returns the owner of the most relevant technical asset or, if there is none, of the most relevant data asset
+func (what Risk) Owner(model *Model) string {
+	if technicalAsset, ok := model.TechnicalAssets[what.MostRelevantTechnicalAssetId]; ok && len(technicalAsset.Owner) > 0 {
+		return technicalAsset.Owner
+	}
+	if dataAsset, ok := model.DataAssets[what.MostRelevantDataAssetId]; ok && len(dataAsset.Owner) > 0 {
+		return dataAsset.Owner
+	}
+	return ""
+}
+
+// TrustBoundaryId returns the trust boundary the risk is located in, which is the most relevant trust boundary or else
+// the one directly containing the most relevant technical asset, empty for risks outside any trust boundary
+func (what Risk) TrustBoundaryId(model *Model) string {
+	if trustBoundary, ok := model.TrustBoundaries[what.MostRelevantTrustBoundaryId]; ok {
+		return trustBoundary.Id
+	}
+	if trustBoundary, ok := model.DirectContainingTrustBoundaryMappedByTechnicalAssetId[what.MostRelevantTechnicalAssetId]; ok && trustBoundary != nil {
+		return trustBoundary.Id
+	}
+	return ""
+}
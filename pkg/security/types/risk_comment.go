@@ -0,0 +1,12 @@
+package types
+
+import "time"
+
+// RiskComment is a comment of a risk (or a reply to one) as discussed on the server, shown with the risk in the outputs
+type RiskComment struct {
+	Id      string    `yaml:"id" json:"id"`
+	Author  string    `yaml:"author" json:"author"`
+	Text    string    `yaml:"text" json:"text"`
+	ReplyTo string    `yaml:"reply_to,omitempty" json:"reply_to,omitempty"` // id of the comment of the same risk this one answers, if any
+	Date    time.Time `yaml:"date" json:"date"`
+}
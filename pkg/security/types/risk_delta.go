@@ -0,0 +1,74 @@
+package types
+
+import "sort"
+
+// RiskDelta lists the changes of the risks compared to a previous assessment
+type RiskDelta struct {
+	New             []*Risk               `json:"new"`              // not identified in the previous assessment
+	Resolved        []*Risk               `json:"resolved"`         // at risk in the previous assessment, but gone or no longer at risk now
+	Reopened        []*Risk               `json:"reopened"`         // not at risk in the previous assessment, but at risk again now
+	SeverityChanged []*RiskSeverityChange `json:"severity_changed"` // identified in both assessments, but with another severity now
+}
+
+// RiskSeverityChange is a risk with the severity it had in the previous assessment
+type RiskSeverityChange struct {
+	Risk             *Risk        `json:"risk"`
+	PreviousSeverity RiskSeverity `json:"previous_severity"`
+}
+
+// RiskDeltaSince compares the current risks (including their tracking status) with the risks of a previous assessment
+func (parsedModel *Model) RiskDeltaSince(previousRisks []*Risk) *RiskDelta {
+	delta := &RiskDelta{
+		New:      make([]*Risk, 0),
+		Resolved: make([]*Risk, 0),
+		Reopened: make([]*Risk, 0),
+
+		SeverityChanged: make([]*RiskSeverityChange, 0),
+	}
+
+	previousById := make(map[string]*Risk)
+	for _, risk := range previousRisks {
+		previousById[risk.SyntheticId] = risk
+	}
+
+	currentById := make(map[string]*Risk)
+	for _, risk := range AllRisks(parsedModel) {
+		currentById[risk.SyntheticId] = risk
+		stillAtRisk := risk.GetRiskTrackingWithDefault(parsedModel).Status.IsStillAtRisk()
+		previous, found := previousById[risk.SyntheticId]
+		switch {
+		case !found:
+			delta.New = append(delta.New, risk)
+		case previous.RiskStatus.IsStillAtRisk() && !stillAtRisk:
+			delta.Resolved = append(delta.Resolved, risk)
+		case !previous.RiskStatus.IsStillAtRisk() && stillAtRisk:
+			delta.Reopened = append(delta.Reopened, risk)
+		}
+		if found && previous.Severity != risk.Severity {
+			delta.SeverityChanged = append(delta.SeverityChanged, &RiskSeverityChange{Risk: risk, PreviousSeverity: previous.Severity})
+		}
+	}
+
+	for _, previous := range previousRisks {
+		if _, found := currentById[previous.SyntheticId]; !found && previous.RiskStatus.IsStillAtRisk() {
+			delta.Resolved = append(delta.Resolved, previous)
+		}
+	}
+
+	for _, risks := range [][]*Risk{delta.New, delta.Resolved, delta.Reopened} {
+		SortByRiskSeverity(risks, parsedModel)
+	}
+	sort.SliceStable(delta.SeverityChanged, func(i, j int) bool { // the biggest increases first
+		return delta.SeverityChanged[i].Change() > delta.SeverityChanged[j].Change()
+	})
+	return delta
+}
+
+func (what *RiskDelta) IsEmpty() bool {
+	return len(what.New) == 0 && len(what.Resolved) == 0 && len(what.Reopened) == 0 && len(what.SeverityChanged) == 0
+}
+
+// Change is the number of severity levels the risk was raised (positive) or lowered (negative) by
+func (what *RiskSeverityChange) Change() int {
+	return int(what.Risk.Severity) - int(what.PreviousSeverity)
+}
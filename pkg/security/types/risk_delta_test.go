@@ -0,0 +1,74 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRiskDeltaSince(t *testing.T) {
+	model := &Model{
+		GeneratedRisksByCategory: map[string][]*Risk{"rule": {
+			{SyntheticId: "unchanged"},
+			{SyntheticId: "new"},
+			{SyntheticId: "mitigated-now"},
+			{SyntheticId: "reopened"},
+		}},
+		RiskTracking: map[string]*RiskTracking{"mitigated-now": {SyntheticRiskId: "mitigated-now", Status: Mitigated}},
+	}
+	previousRisks := []*Risk{
+		{SyntheticId: "unchanged"},
+		{SyntheticId: "mitigated-now"},
+		{SyntheticId: "reopened", RiskStatus: FalsePositive},
+		{SyntheticId: "gone"},
+		{SyntheticId: "gone-but-mitigated", RiskStatus: Mitigated},
+	}
+
+	delta := model.RiskDeltaSince(previousRisks)
+
+	assert.False(t, delta.IsEmpty())
+	assert.Equal(t, []string{"new"}, riskIds(delta.New))
+	assert.ElementsMatch(t, []string{"mitigated-now", "gone"}, riskIds(delta.Resolved))
+	assert.Equal(t, []string{"reopened"}, riskIds(delta.Reopened))
+
+	model.ApplyResidualSeverities()
+	assert.True(t, model.RiskDeltaSince(AllRisks(model)).IsEmpty())
+}
+
+func TestRiskDeltaSinceSeverityChanges(t *testing.T) {
+	model := &Model{
+		GeneratedRisksByCategory: map[string][]*Risk{"rule": {
+			{SyntheticId: "raised", Severity: CriticalSeverity},
+			{SyntheticId: "lowered", Severity: LowSeverity},
+			{SyntheticId: "slightly-raised", Severity: HighSeverity},
+			{SyntheticId: "unchanged", Severity: MediumSeverity},
+		}},
+	}
+	previousRisks := []*Risk{
+		{SyntheticId: "raised", Severity: MediumSeverity},
+		{SyntheticId: "lowered", Severity: HighSeverity},
+		{SyntheticId: "slightly-raised", Severity: ElevatedSeverity},
+		{SyntheticId: "unchanged", Severity: MediumSeverity},
+	}
+
+	delta := model.RiskDeltaSince(previousRisks)
+
+	assert.False(t, delta.IsEmpty())
+	assert.Empty(t, delta.New)
+	assert.Empty(t, delta.Resolved)
+	assert.Len(t, delta.SeverityChanged, 3)
+	assert.Equal(t, "raised", delta.SeverityChanged[0].Risk.SyntheticId, "the biggest increase first")
+	assert.Equal(t, MediumSeverity, delta.SeverityChanged[0].PreviousSeverity)
+	assert.Equal(t, 3, delta.SeverityChanged[0].Change())
+	assert.Equal(t, "slightly-raised", delta.SeverityChanged[1].Risk.SyntheticId)
+	assert.Equal(t, "lowered", delta.SeverityChanged[2].Risk.SyntheticId)
+	assert.Equal(t, -3, delta.SeverityChanged[2].Change())
+}
+
+func riskIds(risks []*Risk) []string {
+	ids := make([]string, 0, len(risks))
+	for _, risk := range risks {
+		ids = append(ids, risk.SyntheticId)
+	}
+	return ids
+}
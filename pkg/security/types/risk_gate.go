@@ -0,0 +1,82 @@
+package types
+
+import (
+	"fmt"
+	"strings"
+)
+
+// RiskGate fails a run when risks at or above a severity with one of the statuses exist, e.g. to break CI builds on new
+// critical risks
+type RiskGate struct {
+	Severity RiskSeverity
+	Statuses []RiskStatus
+}
+
+// ParseRiskGate parses a gate like "severity=high,status=unchecked" (several statuses separated by | or given
+// repeatedly), defaulting to all severities and the statuses still at risk
+func ParseRiskGate(spec string) (*RiskGate, error) {
+	gate := &RiskGate{Severity: LowSeverity, Statuses: make([]RiskStatus, 0)}
+	for _, condition := range strings.Split(spec, ",") {
+		condition = strings.TrimSpace(condition)
+		if len(condition) == 0 {
+			continue
+		}
+		key, value, found := strings.Cut(condition, "=")
+		if !found {
+			return nil, fmt.Errorf("invalid risk gate condition %q: expected key=value", condition)
+		}
+		switch strings.TrimSpace(key) {
+		case "severity":
+			severity, err := ParseRiskSeverity(strings.TrimSpace(value))
+			if err != nil {
+				return nil, fmt.Errorf("invalid risk gate severity: %w", err)
+			}
+			gate.Severity = severity
+		case "status":
+			for _, name := range strings.Split(value, "|") {
+				status, err := ParseRiskStatus(strings.TrimSpace(name))
+				if err != nil {
+					return nil, fmt.Errorf("invalid risk gate status: %w", err)
+				}
+				gate.Statuses = append(gate.Statuses, status)
+			}
+		default:
+			return nil, fmt.Errorf("unknown risk gate condition %q (use severity or status)", key)
+		}
+	}
+	return gate, nil
+}
+
+// Violations are the risks failing the gate, ordered like the given risks
+func (what *RiskGate) Violations(risks []*Risk) []*Risk {
+	violations := make([]*Risk, 0)
+	for _, risk := range risks {
+		if risk.Severity >= what.Severity && what.matchesStatus(risk.RiskStatus) {
+			violations = append(violations, risk)
+		}
+	}
+	return violations
+}
+
+func (what *RiskGate) matchesStatus(status RiskStatus) bool {
+	if len(what.Statuses) == 0 {
+		return status.IsStillAtRisk()
+	}
+	for _, gateStatus := range what.Statuses {
+		if gateStatus == status {
+			return true
+		}
+	}
+	return false
+}
+
+func (what *RiskGate) String() string {
+	statuses := make([]string, 0, len(what.Statuses))
+	for _, status := range what.Statuses {
+		statuses = append(statuses, status.String())
+	}
+	if len(statuses) == 0 {
+		return "severity>=" + what.Severity.String() + " still at risk"
+	}
+	return "severity>=" + what.Severity.String() + " status=" + strings.Join(statuses, "|")
+}
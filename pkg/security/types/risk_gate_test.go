@@ -0,0 +1,50 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseRiskGate(t *testing.T) {
+	gate, err := ParseRiskGate("severity=high, status=unchecked|in-discussion,status=accepted")
+	assert.NoError(t, err)
+	assert.Equal(t, HighSeverity, gate.Severity)
+	assert.Equal(t, []RiskStatus{Unchecked, InDiscussion, Accepted}, gate.Statuses)
+	assert.Equal(t, "severity>=high status=unchecked|in-discussion|accepted", gate.String())
+
+	gate, err = ParseRiskGate("")
+	assert.NoError(t, err)
+	assert.Equal(t, LowSeverity, gate.Severity)
+	assert.Empty(t, gate.Statuses)
+
+	_, err = ParseRiskGate("severity=urgent")
+	assert.Error(t, err)
+	_, err = ParseRiskGate("status=unknown")
+	assert.Error(t, err)
+	_, err = ParseRiskGate("owner=team-a")
+	assert.Error(t, err)
+	_, err = ParseRiskGate("high")
+	assert.Error(t, err)
+}
+
+func TestRiskGateViolations(t *testing.T) {
+	risks := []*Risk{
+		{SyntheticId: "critical-unchecked", Severity: CriticalSeverity, RiskStatus: Unchecked},
+		{SyntheticId: "high-accepted", Severity: HighSeverity, RiskStatus: Accepted},
+		{SyntheticId: "high-mitigated", Severity: HighSeverity, RiskStatus: Mitigated},
+		{SyntheticId: "medium-unchecked", Severity: MediumSeverity, RiskStatus: Unchecked},
+	}
+
+	gate, _ := ParseRiskGate("severity=high")
+	assert.Equal(t, []string{"critical-unchecked", "high-accepted"}, riskIds(gate.Violations(risks)), "still at risk by default")
+
+	gate, _ = ParseRiskGate("severity=high,status=unchecked")
+	assert.Equal(t, []string{"critical-unchecked"}, riskIds(gate.Violations(risks)))
+
+	gate, _ = ParseRiskGate("status=mitigated")
+	assert.Equal(t, []string{"high-mitigated"}, riskIds(gate.Violations(risks)))
+
+	gate, _ = ParseRiskGate("severity=critical,status=accepted")
+	assert.Empty(t, gate.Violations(risks))
+}
@@ -0,0 +1,112 @@
+package types
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// MergeDuplicateRisks collapses risks of overlapping categories (e.g. a custom rule duplicating a built-in one)
+// flagging the same elements into the most severe of them, which keeps the IDs of the others as cross-reference. The
+// most severe risk takes over the risk tracking of the others when it has none of its own.
+func (parsedModel *Model) MergeDuplicateRisks(groups map[string][]string, keys []string, progressReporter ProgressReporter) error {
+	for _, key := range keys {
+		if _, ok := riskMergeKeys[strings.ToLower(strings.TrimSpace(key))]; !ok {
+			return fmt.Errorf("unknown risk merge key: %v", key)
+		}
+	}
+
+	groupNames := make([]string, 0, len(groups))
+	for name := range groups {
+		groupNames = append(groupNames, name)
+	}
+	sort.Strings(groupNames)
+
+	for _, name := range groupNames {
+		buckets := make(map[string][]*Risk)
+		bucketKeys := make([]string, 0)
+		for _, categoryId := range groups[name] {
+			for _, risk := range parsedModel.GeneratedRisksByCategory[categoryId] {
+				bucketKey := risk.mergeKey(keys)
+				if _, ok := buckets[bucketKey]; !ok {
+					bucketKeys = append(bucketKeys, bucketKey)
+				}
+				buckets[bucketKey] = append(buckets[bucketKey], risk)
+			}
+		}
+
+		for _, bucketKey := range bucketKeys {
+			duplicates := buckets[bucketKey]
+			if len(duplicates) < 2 {
+				continue
+			}
+
+			sort.SliceStable(duplicates, func(i, j int) bool {
+				if duplicates[i].Severity != duplicates[j].Severity {
+					return duplicates[i].Severity > duplicates[j].Severity
+				}
+				return duplicates[i].SyntheticId < duplicates[j].SyntheticId
+			})
+
+			primary := duplicates[0]
+			for _, duplicate := range duplicates[1:] {
+				progressReporter.Infof("Merging risk %v into %v (merge group %v)", duplicate.SyntheticId, primary.SyntheticId, name)
+				primary.MergedRiskIds = append(primary.MergedRiskIds, duplicate.SyntheticId)
+				primary.MergedRiskIds = append(primary.MergedRiskIds, duplicate.MergedRiskIds...)
+				parsedModel.removeGeneratedRisk(groups[name], duplicate)
+				parsedModel.carryOverRiskTracking(duplicate, primary)
+			}
+		}
+	}
+
+	return nil
+}
+
+var riskMergeKeys = map[string]func(risk *Risk) string{
+	"technical_asset":    func(risk *Risk) string { return risk.MostRelevantTechnicalAssetId },
+	"data_asset":         func(risk *Risk) string { return risk.MostRelevantDataAssetId },
+	"trust_boundary":     func(risk *Risk) string { return risk.MostRelevantTrustBoundaryId },
+	"shared_runtime":     func(risk *Risk) string { return risk.MostRelevantSharedRuntimeId },
+	"communication_link": func(risk *Risk) string { return risk.MostRelevantCommunicationLinkId },
+}
+
+func (what *Risk) mergeKey(keys []string) string {
+	parts := make([]string, 0, len(keys))
+	for _, key := range keys {
+		parts = append(parts, riskMergeKeys[strings.ToLower(strings.TrimSpace(key))](what))
+	}
+	return strings.Join(parts, "@")
+}
+
+func (parsedModel *Model) removeGeneratedRisk(categoryIds []string, risk *Risk) {
+	for _, categoryId := range categoryIds {
+		risks := parsedModel.GeneratedRisksByCategory[categoryId]
+		for i, candidate := range risks {
+			if candidate == risk {
+				risks = append(risks[:i], risks[i+1:]...)
+				break
+			}
+		}
+
+		if len(risks) == 0 {
+			delete(parsedModel.GeneratedRisksByCategory, categoryId)
+		} else {
+			parsedModel.GeneratedRisksByCategory[categoryId] = risks
+		}
+	}
+
+	delete(parsedModel.GeneratedRisksBySyntheticId, strings.ToLower(risk.SyntheticId))
+}
+
+func (parsedModel *Model) carryOverRiskTracking(from *Risk, to *Risk) {
+	tracking, ok := parsedModel.RiskTracking[from.SyntheticId]
+	if !ok {
+		return
+	}
+	if _, tracked := parsedModel.RiskTracking[to.SyntheticId]; tracked {
+		return
+	}
+	carried := *tracking
+	carried.SyntheticRiskId = to.SyntheticId
+	parsedModel.RiskTracking[to.SyntheticId] = &carried
+}
@@ -0,0 +1,68 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type noopProgressReporter struct{}
+
+func (noopProgressReporter) Info(...any)           {}
+func (noopProgressReporter) Warn(...any)           {}
+func (noopProgressReporter) Error(...any)          {}
+func (noopProgressReporter) Infof(string, ...any)  {}
+func (noopProgressReporter) Warnf(string, ...any)  {}
+func (noopProgressReporter) Errorf(string, ...any) {}
+
+func TestMergeDuplicateRisks(t *testing.T) {
+	builtin := &Risk{CategoryId: "unencrypted-asset", SyntheticId: "unencrypted-asset@db", Severity: MediumSeverity, MostRelevantTechnicalAssetId: "db"}
+	custom := &Risk{CategoryId: "custom-encryption", SyntheticId: "custom-encryption@db", Severity: HighSeverity, MostRelevantTechnicalAssetId: "db"}
+	other := &Risk{CategoryId: "unencrypted-asset", SyntheticId: "unencrypted-asset@cache", Severity: MediumSeverity, MostRelevantTechnicalAssetId: "cache"}
+	model := &Model{
+		GeneratedRisksByCategory: map[string][]*Risk{
+			"unencrypted-asset": {builtin, other},
+			"custom-encryption": {custom},
+		},
+		GeneratedRisksBySyntheticId: map[string]*Risk{
+			builtin.SyntheticId: builtin,
+			custom.SyntheticId:  custom,
+			other.SyntheticId:   other,
+		},
+	}
+
+	groups := map[string][]string{"encryption": {"unencrypted-asset", "custom-encryption"}}
+	assert.NoError(t, model.MergeDuplicateRisks(groups, []string{"technical_asset"}, noopProgressReporter{}))
+	assert.Equal(t, []string{"unencrypted-asset@db"}, custom.MergedRiskIds)
+	assert.Equal(t, []*Risk{other}, model.GeneratedRisksByCategory["unencrypted-asset"])
+	assert.NotContains(t, model.GeneratedRisksBySyntheticId, builtin.SyntheticId)
+
+	assert.Error(t, model.MergeDuplicateRisks(groups, []string{"color"}, noopProgressReporter{}))
+}
+
+func TestMergeDuplicateRisksCarriesOverRiskTracking(t *testing.T) {
+	builtin := &Risk{CategoryId: "unencrypted-asset", SyntheticId: "unencrypted-asset@db", Severity: MediumSeverity, MostRelevantTechnicalAssetId: "db"}
+	custom := &Risk{CategoryId: "custom-encryption", SyntheticId: "custom-encryption@db", Severity: HighSeverity, MostRelevantTechnicalAssetId: "db"}
+	model := &Model{
+		GeneratedRisksByCategory: map[string][]*Risk{
+			"unencrypted-asset": {builtin},
+			"custom-encryption": {custom},
+		},
+		GeneratedRisksBySyntheticId: map[string]*Risk{
+			builtin.SyntheticId: builtin,
+			custom.SyntheticId:  custom,
+		},
+		RiskTracking: map[string]*RiskTracking{
+			builtin.SyntheticId: {SyntheticRiskId: builtin.SyntheticId, Status: Mitigated, Justification: "encrypted at rest"},
+		},
+	}
+
+	groups := map[string][]string{"encryption": {"unencrypted-asset", "custom-encryption"}}
+	assert.NoError(t, model.MergeDuplicateRisks(groups, []string{"technical_asset"}, noopProgressReporter{}))
+	assert.Equal(t, Mitigated, custom.GetRiskTrackingWithDefault(model).Status, "taken over from the merged-away risk")
+	assert.Equal(t, custom.SyntheticId, model.RiskTracking[custom.SyntheticId].SyntheticRiskId)
+	assert.NoError(t, model.CheckRiskTracking(false, noopProgressReporter{}), "the tracking of the merged-away risk is no orphan")
+
+	model.RiskTracking["unknown@db"] = &RiskTracking{SyntheticRiskId: "unknown@db"}
+	assert.Error(t, model.CheckRiskTracking(false, noopProgressReporter{}))
+}
@@ -7,6 +7,7 @@ package types
 import (
 	"sort"
 	"strings"
+	"time"
 )
 
 func GetRiskCategory(parsedModel *Model, categoryID string) *RiskCategory {
@@ -125,7 +126,8 @@ func SortByRiskCategoryHighestContainingRiskSeveritySortStillAtRisk(parsedModel
 
 type RiskStatistics struct {
 	// TODO add also some more like before / after (i.e. with mitigation applied)
-	Risks map[string]map[string]int `yaml:"risks" json:"risks"`
+	Risks   map[string]map[string]int `yaml:"risks" json:"risks"`
+	Overdue map[string]int            `yaml:"overdue" json:"overdue"` // by severity, the risks past the due date of their risk tracking
 }
 
 func SortByRiskSeverity(risks []*Risk, parsedModel *Model) {
@@ -198,6 +200,19 @@ func SortedRisksOfCategory(parsedModel *Model, category *RiskCategory) []*Risk {
 	return risks
 }
 
+// SortedRisksOfTrustBoundary returns the risks located in the trust boundary (see Risk.TrustBoundaryId) sorted by
+// severity, an empty id returns the risks outside any trust boundary
+func SortedRisksOfTrustBoundary(parsedModel *Model, trustBoundaryId string) []*Risk {
+	risks := make([]*Risk, 0)
+	for _, risk := range AllRisks(parsedModel) {
+		if risk.TrustBoundaryId(parsedModel) == trustBoundaryId {
+			risks = append(risks, risk)
+		}
+	}
+	SortByRiskSeverity(risks, parsedModel)
+	return risks
+}
+
 func CountRisks(risksByCategory map[string][]*Risk) int {
 	result := 0
 	for _, risks := range risksByCategory {
@@ -825,5 +840,12 @@ func OverallRiskStatistics(parsedModel *Model) RiskStatistics {
 			result.Risks[risk.Severity.String()][risk.RiskStatus.String()]++
 		}
 	}
+	result.Overdue = make(map[string]int)
+	for severity := range result.Risks {
+		result.Overdue[severity] = 0
+	}
+	for _, risk := range OverdueRisks(parsedModel, time.Now()) {
+		result.Overdue[risk.Severity.String()]++
+	}
 	return result
 }
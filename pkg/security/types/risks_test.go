@@ -0,0 +1,39 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSortedRisksOfTrustBoundary(t *testing.T) {
+	boundaryRisk := &Risk{Severity: LowSeverity, MostRelevantTrustBoundaryId: "dmz"}
+	assetRisk := &Risk{Severity: HighSeverity, MostRelevantTechnicalAssetId: "web"}
+	outside := &Risk{Severity: MediumSeverity, MostRelevantTechnicalAssetId: "client"}
+	dmz := &TrustBoundary{Id: "dmz"}
+	model := &Model{
+		TrustBoundaries: map[string]*TrustBoundary{"dmz": dmz},
+		DirectContainingTrustBoundaryMappedByTechnicalAssetId: map[string]*TrustBoundary{"web": dmz},
+		GeneratedRisksByCategory:                              map[string][]*Risk{"rule": {boundaryRisk, assetRisk, outside}},
+	}
+
+	assert.Equal(t, []*Risk{assetRisk, boundaryRisk}, SortedRisksOfTrustBoundary(model, "dmz"))
+	assert.Equal(t, []*Risk{outside}, SortedRisksOfTrustBoundary(model, ""))
+}
+
+func TestRisksOfOwner(t *testing.T) {
+	shopRisk := &Risk{SyntheticId: "rule@shop", MostRelevantTechnicalAssetId: "shop"}
+	customerRisk := &Risk{SyntheticId: "rule@customers", MostRelevantDataAssetId: "customers"}
+	unownedRisk := &Risk{SyntheticId: "rule@client", MostRelevantTechnicalAssetId: "client"}
+	model := &Model{
+		TechnicalAssets:          map[string]*TechnicalAsset{"shop": {Id: "shop", Owner: "Team Shop"}, "client": {Id: "client"}},
+		DataAssets:               map[string]*DataAsset{"customers": {Id: "customers", Owner: "Team CRM"}},
+		GeneratedRisksByCategory: map[string][]*Risk{"rule": {shopRisk, customerRisk, unownedRisk}},
+	}
+
+	assert.Equal(t, []string{"Team CRM", "Team Shop"}, model.RiskOwners())
+	ownerModel := model.RisksOfOwner("team shop")
+	assert.Equal(t, map[string][]*Risk{"rule": {shopRisk}}, ownerModel.GeneratedRisksByCategory)
+	assert.Equal(t, map[string]*Risk{"rule@shop": shopRisk}, ownerModel.GeneratedRisksBySyntheticId)
+	assert.Len(t, model.GeneratedRisksByCategory["rule"], 3, "the model itself unchanged")
+}
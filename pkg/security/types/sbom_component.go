@@ -0,0 +1,53 @@
+package types
+
+import "strings"
+
+// SBOMComponent is a component listed in the software bill of materials (SBOM) of a technical asset
+type SBOMComponent struct {
+	Name            string              `json:"name" yaml:"name"`
+	Version         string              `json:"version,omitempty" yaml:"version,omitempty"`
+	PURL            string              `json:"purl,omitempty" yaml:"purl,omitempty"`
+	Vulnerabilities []SBOMVulnerability `json:"vulnerabilities,omitempty" yaml:"vulnerabilities,omitempty"`
+}
+
+// SBOMVulnerability is a known vulnerability of an SBOM component, with the severity as rated in the SBOM (critical,
+// high, medium, low, info, none or unknown)
+type SBOMVulnerability struct {
+	ID       string `json:"id" yaml:"id"`
+	Severity string `json:"severity,omitempty" yaml:"severity,omitempty"`
+}
+
+func (what SBOMComponent) String() string {
+	if len(what.Version) == 0 {
+		return what.Name
+	}
+	return what.Name + " " + what.Version
+}
+
+// IsVulnerable tells whether the component has known vulnerabilities
+func (what SBOMComponent) IsVulnerable() bool {
+	return len(what.Vulnerabilities) > 0
+}
+
+// HasVulnerabilityOfSeverity tells whether the component has a known vulnerability of one of the severities
+func (what SBOMComponent) HasVulnerabilityOfSeverity(severities ...string) bool {
+	for _, vulnerability := range what.Vulnerabilities {
+		for _, severity := range severities {
+			if strings.EqualFold(vulnerability.Severity, severity) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// VulnerableSBOMComponents are the components of the SBOM of the technical asset with known vulnerabilities
+func (what TechnicalAsset) VulnerableSBOMComponents() []*SBOMComponent {
+	vulnerable := make([]*SBOMComponent, 0)
+	for _, component := range what.SBOMComponents {
+		if component.IsVulnerable() {
+			vulnerable = append(vulnerable, component)
+		}
+	}
+	return vulnerable
+}
@@ -0,0 +1,101 @@
+package types
+
+import (
+	"sort"
+)
+
+// ScannerFinding is a finding of a vulnerability scanner (imported from SARIF or DefectDojo json) mapped to a technical
+// asset, correlated with the modeled risks of the asset sharing its CWE
+type ScannerFinding struct {
+	Source          string       `json:"source,omitempty" yaml:"source,omitempty"` // tool or file the finding was imported from
+	ID              string       `json:"id,omitempty" yaml:"id,omitempty"`         // rule or vulnerability ID of the scanner
+	Title           string       `json:"title,omitempty" yaml:"title,omitempty"`
+	Severity        RiskSeverity `json:"severity" yaml:"severity"`
+	CWE             int          `json:"cwe,omitempty" yaml:"cwe,omitempty"`
+	Location        string       `json:"location,omitempty" yaml:"location,omitempty"`
+	TechnicalAsset  string       `json:"technical_asset,omitempty" yaml:"technical_asset,omitempty"`
+	CorrelatedRisks []string     `json:"correlated_risks,omitempty" yaml:"correlated_risks,omitempty"` // synthetic IDs of the modeled risks
+}
+
+// Exposure combines the modeled risks still at risk and the scanner findings of a technical asset
+type Exposure struct {
+	TechnicalAsset    string            `json:"technical_asset" yaml:"technical_asset"`
+	HighestSeverity   RiskSeverity      `json:"highest_severity" yaml:"highest_severity"`
+	Risks             []string          `json:"risks,omitempty" yaml:"risks,omitempty"`                     // synthetic IDs of the modeled risks still at risk
+	ConfirmedRisks    []string          `json:"confirmed_risks,omitempty" yaml:"confirmed_risks,omitempty"` // modeled risks with correlated findings
+	Findings          []*ScannerFinding `json:"findings,omitempty" yaml:"findings,omitempty"`
+	UnmodeledFindings int               `json:"unmodeled_findings" yaml:"unmodeled_findings"` // findings without correlated risk
+}
+
+// CorrelateScannerFindings links the scanner findings with the generated risks of their technical asset whose
+// category has the CWE of the finding
+func (parsedModel *Model) CorrelateScannerFindings() {
+	for _, finding := range parsedModel.ScannerFindings {
+		finding.CorrelatedRisks = nil
+		if finding.CWE == 0 {
+			continue
+		}
+		for _, risk := range AllRisks(parsedModel) {
+			if risk.MostRelevantTechnicalAssetId != finding.TechnicalAsset {
+				continue
+			}
+			if category := GetRiskCategory(parsedModel, risk.CategoryId); category != nil && category.CWE == finding.CWE {
+				finding.CorrelatedRisks = append(finding.CorrelatedRisks, risk.SyntheticId)
+			}
+		}
+		sort.Strings(finding.CorrelatedRisks)
+	}
+}
+
+// Exposures returns the exposure of the technical assets with scanner findings or risks still at risk, the most
+// severe first
+func (parsedModel *Model) Exposures() []*Exposure {
+	exposures := make(map[string]*Exposure)
+	exposureOf := func(technicalAssetId string, severity RiskSeverity) *Exposure {
+		exposure, ok := exposures[technicalAssetId]
+		if !ok {
+			exposure = &Exposure{TechnicalAsset: technicalAssetId, HighestSeverity: severity}
+			exposures[technicalAssetId] = exposure
+		}
+		if severity > exposure.HighestSeverity {
+			exposure.HighestSeverity = severity
+		}
+		return exposure
+	}
+
+	for _, risk := range ReduceToOnlyStillAtRisk(parsedModel, AllRisks(parsedModel)) {
+		if len(risk.MostRelevantTechnicalAssetId) > 0 {
+			exposure := exposureOf(risk.MostRelevantTechnicalAssetId, risk.Severity)
+			exposure.Risks = append(exposure.Risks, risk.SyntheticId)
+		}
+	}
+	for _, finding := range parsedModel.ScannerFindings {
+		exposure := exposureOf(finding.TechnicalAsset, finding.Severity)
+		exposure.Findings = append(exposure.Findings, finding)
+		if len(finding.CorrelatedRisks) == 0 {
+			exposure.UnmodeledFindings++
+		}
+		for _, riskId := range finding.CorrelatedRisks {
+			if !contains(exposure.ConfirmedRisks, riskId) {
+				exposure.ConfirmedRisks = append(exposure.ConfirmedRisks, riskId)
+			}
+		}
+	}
+
+	result := make([]*Exposure, 0, len(exposures))
+	for _, exposure := range exposures {
+		sort.Strings(exposure.Risks)
+		sort.Strings(exposure.ConfirmedRisks)
+		sort.SliceStable(exposure.Findings, func(i, j int) bool {
+			return exposure.Findings[i].Severity > exposure.Findings[j].Severity
+		})
+		result = append(result, exposure)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].HighestSeverity != result[j].HighestSeverity {
+			return result[i].HighestSeverity > result[j].HighestSeverity
+		}
+		return result[i].TechnicalAsset < result[j].TechnicalAsset
+	})
+	return result
+}
@@ -0,0 +1,82 @@
+package types
+
+import (
+	"sort"
+)
+
+// SecurityControlTypes are the known types of security controls
+var SecurityControlTypes = []string{"waf", "ids", "ips", "edr", "antivirus", "firewall", "backup", "siem", "dlp", "mfa", "vpn", "other"}
+
+// SecurityControl is a modeled control (e.g. WAF, IDS, EDR, backups) protecting technical assets directly or via their trust boundaries
+type SecurityControl struct {
+	Id                  string   `json:"id,omitempty" yaml:"id,omitempty"`
+	Title               string   `json:"title,omitempty" yaml:"title,omitempty"`
+	Description         string   `json:"description,omitempty" yaml:"description,omitempty"`
+	Type                string   `json:"type,omitempty" yaml:"type,omitempty"`
+	LikelihoodReduction int      `json:"likelihood_reduction,omitempty" yaml:"likelihood_reduction,omitempty"` // levels of exploitation likelihood the control takes off mitigated risks
+	Mitigates           []string `json:"mitigates,omitempty" yaml:"mitigates,omitempty"`                       // risk category IDs
+	TechnicalAssets     []string `json:"technical_assets,omitempty" yaml:"technical_assets,omitempty"`
+	TrustBoundaries     []string `json:"trust_boundaries,omitempty" yaml:"trust_boundaries,omitempty"`
+}
+
+// Protects checks whether the control is attached to the technical asset or any trust boundary (recursively) containing it
+func (what SecurityControl) Protects(model *Model, technicalAssetId string) bool {
+	if contains(what.TechnicalAssets, technicalAssetId) {
+		return true
+	}
+	for _, trustBoundaryId := range what.TrustBoundaries {
+		if trustBoundary, ok := model.TrustBoundaries[trustBoundaryId]; ok && contains(trustBoundary.RecursivelyAllTechnicalAssetIDsInside(model), technicalAssetId) {
+			return true
+		}
+	}
+	return false
+}
+
+// SecurityControlsProtecting returns the controls protecting the given technical asset, sorted by title
+func (parsedModel *Model) SecurityControlsProtecting(technicalAssetId string) []*SecurityControl {
+	result := make([]*SecurityControl, 0)
+	for _, control := range parsedModel.SortedSecurityControls() {
+		if control.Protects(parsedModel, technicalAssetId) {
+			result = append(result, control)
+		}
+	}
+	return result
+}
+
+func (parsedModel *Model) SortedSecurityControls() []*SecurityControl {
+	result := make([]*SecurityControl, 0, len(parsedModel.SecurityControls))
+	for _, control := range parsedModel.SecurityControls {
+		result = append(result, control)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Title < result[j].Title
+	})
+	return result
+}
+
+// ApplySecurityControls lowers the likelihood (and with it the severity) of risks mitigated by controls
+// protecting their most relevant technical asset; the strongest control wins
+func (parsedModel *Model) ApplySecurityControls() {
+	for categoryId, risks := range parsedModel.GeneratedRisksByCategory {
+		for _, risk := range risks {
+			reduction := 0
+			for _, control := range parsedModel.SecurityControlsProtecting(risk.MostRelevantTechnicalAssetId) {
+				if !contains(control.Mitigates, categoryId) {
+					continue
+				}
+				risk.AppliedSecurityControls = append(risk.AppliedSecurityControls, control.Id)
+				if control.LikelihoodReduction > reduction {
+					reduction = control.LikelihoodReduction
+				}
+			}
+
+			likelihood := risk.ExploitationLikelihood - RiskExploitationLikelihood(reduction)
+			if likelihood < Unlikely {
+				likelihood = Unlikely
+			}
+			levels := int(CalculateSeverity(likelihood, risk.ExploitationImpact)) - int(CalculateSeverity(risk.ExploitationLikelihood, risk.ExploitationImpact))
+			risk.ExploitationLikelihood = likelihood
+			risk.Severity = risk.Severity.Shift(levels)
+		}
+	}
+}
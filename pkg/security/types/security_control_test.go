@@ -0,0 +1,28 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApplySecurityControls(t *testing.T) {
+	protected := &Risk{MostRelevantTechnicalAssetId: "web", ExploitationLikelihood: VeryLikely, ExploitationImpact: HighImpact, Severity: CalculateSeverity(VeryLikely, HighImpact)}
+	unprotected := &Risk{MostRelevantTechnicalAssetId: "db", ExploitationLikelihood: VeryLikely, ExploitationImpact: HighImpact, Severity: CalculateSeverity(VeryLikely, HighImpact)}
+	model := &Model{
+		TechnicalAssets: map[string]*TechnicalAsset{"web": {Id: "web"}, "db": {Id: "db"}},
+		TrustBoundaries: map[string]*TrustBoundary{"dmz": {Id: "dmz", TechnicalAssetsInside: []string{"web"}}},
+		SecurityControls: map[string]*SecurityControl{
+			"waf": {Id: "waf", Title: "WAF", LikelihoodReduction: 1, Mitigates: []string{"xss"}, TrustBoundaries: []string{"dmz"}},
+		},
+		GeneratedRisksByCategory: map[string][]*Risk{"xss": {protected, unprotected}},
+	}
+
+	model.ApplySecurityControls()
+
+	assert.Equal(t, Likely, protected.ExploitationLikelihood)
+	assert.Equal(t, CalculateSeverity(Likely, HighImpact), protected.Severity)
+	assert.Equal(t, []string{"waf"}, protected.AppliedSecurityControls)
+	assert.Equal(t, VeryLikely, unprotected.ExploitationLikelihood)
+	assert.Empty(t, unprotected.AppliedSecurityControls)
+}
@@ -0,0 +1,54 @@
+package types
+
+import (
+	"fmt"
+)
+
+// RecalibrateSeverities shifts the severity of each generated risk by the number of levels configured
+// for the criticality of its environment (e.g. {"mission-critical": 1, "archive": -1})
+func (parsedModel *Model) RecalibrateSeverities(shifts map[string]int) error {
+	shiftByCriticality := make(map[Criticality]int)
+	for name, shift := range shifts {
+		criticality, parseError := ParseCriticality(name)
+		if parseError != nil {
+			return fmt.Errorf("unknown criticality in severity recalibration: %v", name)
+		}
+		shiftByCriticality[criticality] = shift
+	}
+
+	if len(shiftByCriticality) == 0 {
+		return nil
+	}
+
+	for _, risks := range parsedModel.GeneratedRisksByCategory {
+		for _, risk := range risks {
+			risk.Severity = risk.Severity.Shift(shiftByCriticality[risk.EnvironmentCriticality(parsedModel)])
+		}
+	}
+
+	return nil
+}
+
+// Shift moves the severity up (positive) or down (negative) by the given number of levels, staying within low and critical
+func (what RiskSeverity) Shift(levels int) RiskSeverity {
+	result := int(what) + levels
+	if result < int(LowSeverity) {
+		return LowSeverity
+	}
+	if result > int(CriticalSeverity) {
+		return CriticalSeverity
+	}
+	return RiskSeverity(result)
+}
+
+// EnvironmentCriticality is the criticality of the trust boundary the risk is located in or,
+// if there is none, the business criticality of the model
+func (what Risk) EnvironmentCriticality(model *Model) Criticality {
+	if trustBoundary, ok := model.TrustBoundaries[what.MostRelevantTrustBoundaryId]; ok {
+		return trustBoundary.Criticality
+	}
+	if trustBoundary, ok := model.DirectContainingTrustBoundaryMappedByTechnicalAssetId[what.MostRelevantTechnicalAssetId]; ok && trustBoundary != nil {
+		return trustBoundary.Criticality
+	}
+	return model.BusinessCriticality
+}
@@ -0,0 +1,24 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecalibrateSeverities(t *testing.T) {
+	inBoundary := &Risk{Severity: MediumSeverity, MostRelevantTechnicalAssetId: "a"}
+	outside := &Risk{Severity: HighSeverity, MostRelevantTechnicalAssetId: "b"}
+	model := &Model{
+		BusinessCriticality: MissionCritical,
+		TrustBoundaries:     map[string]*TrustBoundary{"archive": {Id: "archive", Criticality: Archive}},
+		DirectContainingTrustBoundaryMappedByTechnicalAssetId: map[string]*TrustBoundary{"a": {Id: "archive", Criticality: Archive}},
+		GeneratedRisksByCategory:                              map[string][]*Risk{"rule": {inBoundary, outside}},
+	}
+
+	assert.NoError(t, model.RecalibrateSeverities(map[string]int{"mission-critical": 2, "archive": -1}))
+	assert.Equal(t, LowSeverity, inBoundary.Severity)
+	assert.Equal(t, CriticalSeverity, outside.Severity)
+
+	assert.Error(t, model.RecalibrateSeverities(map[string]int{"unknown": 1}))
+}
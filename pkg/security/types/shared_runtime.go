@@ -24,6 +24,11 @@ func (what SharedRuntime) IsTaggedWithBaseTag(baseTag string) bool {
 	return IsTaggedWithBaseTag(what.Tags, baseTag)
 }
 
+// IsTaggedWithAnyInTaxonomy also matches the taxonomy ancestors of the tags
+func (what SharedRuntime) IsTaggedWithAnyInTaxonomy(model *Model, tags ...string) bool {
+	return containsCaseInsensitiveAny(model.TagTaxonomy.Expand(what.Tags), tags...)
+}
+
 func (what SharedRuntime) HighestConfidentiality(model *Model) Confidentiality {
 	highest := Public
 	for _, id := range what.TechnicalAssetsRunning {
@@ -0,0 +1,106 @@
+package types
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+type TagTaxonomyEntry struct {
+	Description string `json:"description,omitempty" yaml:"description,omitempty"`
+	Parent      string `json:"parent,omitempty" yaml:"parent,omitempty"`
+}
+
+// TagTaxonomy defines the allowed tags of a model, keyed by the (lower-case) tag name
+type TagTaxonomy map[string]TagTaxonomyEntry
+
+func (what *TagTaxonomy) LoadFromFile(filename string) error {
+	data, readError := os.ReadFile(filepath.Clean(filename))
+	if readError != nil {
+		return fmt.Errorf("error reading tag taxonomy %q: %w", filename, readError)
+	}
+
+	entries := make(map[string]TagTaxonomyEntry)
+	unmarshalError := yaml.Unmarshal(data, &entries)
+	if unmarshalError != nil {
+		return fmt.Errorf("error parsing tag taxonomy %q: %w", filename, unmarshalError)
+	}
+
+	*what = make(TagTaxonomy)
+	for tag, entry := range entries {
+		entry.Parent = strings.ToLower(strings.TrimSpace(entry.Parent))
+		(*what)[strings.ToLower(strings.TrimSpace(tag))] = entry
+	}
+
+	return what.Validate()
+}
+
+func (what TagTaxonomy) Validate() error {
+	for _, tag := range what.SortedTags() {
+		visited := map[string]bool{tag: true}
+		for parent := what[tag].Parent; len(parent) > 0; parent = what[parent].Parent {
+			if _, ok := what[parent]; !ok {
+				return fmt.Errorf("tag taxonomy references unknown parent tag %q", parent)
+			}
+			if visited[parent] {
+				return fmt.Errorf("tag taxonomy contains a cycle at tag %q", tag)
+			}
+			visited[parent] = true
+		}
+	}
+
+	return nil
+}
+
+func (what TagTaxonomy) IsEmpty() bool {
+	return len(what) == 0
+}
+
+func (what TagTaxonomy) Contains(tag string) bool {
+	_, ok := what[strings.ToLower(strings.TrimSpace(tag))]
+	return ok
+}
+
+func (what TagTaxonomy) Description(tag string) string {
+	return what[strings.ToLower(strings.TrimSpace(tag))].Description
+}
+
+// Ancestors returns the parent chain of a tag, starting with the direct parent
+func (what TagTaxonomy) Ancestors(tag string) []string {
+	result := make([]string, 0)
+	tag = strings.ToLower(strings.TrimSpace(tag))
+	for parent := what[tag].Parent; len(parent) > 0 && !contains(result, parent) && parent != tag; parent = what[parent].Parent {
+		result = append(result, parent)
+	}
+	return result
+}
+
+// Expand returns the given tags along with all their ancestors
+func (what TagTaxonomy) Expand(tags []string) []string {
+	if what.IsEmpty() {
+		return tags
+	}
+
+	result := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		for _, candidate := range append([]string{tag}, what.Ancestors(tag)...) {
+			if !contains(result, candidate) {
+				result = append(result, candidate)
+			}
+		}
+	}
+	return result
+}
+
+func (what TagTaxonomy) SortedTags() []string {
+	tags := make([]string, 0, len(what))
+	for tag := range what {
+		tags = append(tags, tag)
+	}
+	sort.Strings(tags)
+	return tags
+}
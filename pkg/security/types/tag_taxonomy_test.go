@@ -0,0 +1,46 @@
+package types
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTagTaxonomyAncestors(t *testing.T) {
+	taxonomy := TagTaxonomy{
+		"pci":             {Description: "payment card industry"},
+		"cardholder-data": {Parent: "pci"},
+		"pan":             {Parent: "cardholder-data"},
+	}
+
+	assert.NoError(t, taxonomy.Validate())
+	assert.Equal(t, []string{"cardholder-data", "pci"}, taxonomy.Ancestors("pan"))
+	assert.Equal(t, []string{"pan", "cardholder-data", "pci", "other"}, taxonomy.Expand([]string{"pan", "other"}))
+
+	asset := TechnicalAsset{Tags: []string{"pan"}}
+	model := &Model{TagTaxonomy: taxonomy}
+	assert.True(t, asset.IsTaggedWithAnyInTaxonomy(model, "pci"))
+	assert.False(t, asset.IsTaggedWithAny("pci"))
+	assert.False(t, asset.IsTaggedWithAnyInTaxonomy(&Model{}, "pci"))
+}
+
+func TestTagTaxonomyValidate(t *testing.T) {
+	assert.Error(t, TagTaxonomy{"a": {Parent: "missing"}}.Validate())
+	assert.Error(t, TagTaxonomy{"a": {Parent: "b"}, "b": {Parent: "a"}}.Validate())
+}
+
+func TestTagTaxonomyLoadFromFile(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "taxonomy.yaml")
+	assert.NoError(t, os.WriteFile(filename, []byte("PCI:\n  description: payment\ncardholder-data:\n  parent: PCI\n"), 0600))
+
+	taxonomy := make(TagTaxonomy)
+	assert.NoError(t, taxonomy.LoadFromFile(filename))
+	assert.True(t, taxonomy.Contains("pci"))
+	assert.Equal(t, "payment", taxonomy.Description("pci"))
+	assert.Equal(t, []string{"pci"}, taxonomy.Ancestors("cardholder-data"))
+
+	model := &Model{TagTaxonomy: taxonomy, TagsAvailable: []string{"pci", "unknown"}}
+	assert.Error(t, model.CheckTagsAvailableInTaxonomy())
+}
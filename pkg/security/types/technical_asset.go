@@ -7,6 +7,8 @@ package types
 import (
 	"fmt"
 	"sort"
+
+	"github.com/threagile/threagile/pkg/common"
 )
 
 type TechnicalAsset struct {
@@ -37,7 +39,9 @@ type TechnicalAsset struct {
 	DataFormatsAccepted     []DataFormat          `json:"data_formats_accepted,omitempty" yaml:"data_formats_accepted,omitempty"`
 	CommunicationLinks      []*CommunicationLink  `json:"communication_links,omitempty" yaml:"communication_links,omitempty"`
 	DiagramTweakOrder       int                   `json:"diagram_tweak_order,omitempty" yaml:"diagram_tweak_order,omitempty"`
-	RAA                     float64               `json:"raa,omitempty" yaml:"raa,omitempty"` // will be set by separate calculation step
+	SBOM                    string                `json:"sbom,omitempty" yaml:"sbom,omitempty"`
+	SBOMComponents          []*SBOMComponent      `json:"sbom_components,omitempty" yaml:"sbom_components,omitempty"` // will be set by loading the SBOM
+	RAA                     float64               `json:"raa,omitempty" yaml:"raa,omitempty"`                         // will be set by separate calculation step
 }
 
 func (what TechnicalAsset) IsTaggedWithAny(tags ...string) bool {
@@ -48,10 +52,16 @@ func (what TechnicalAsset) IsTaggedWithBaseTag(baseTag string) bool {
 	return IsTaggedWithBaseTag(what.Tags, baseTag)
 }
 
-// first use the tag(s) of the asset itself, then their trust boundaries (recursively up) and then their shared runtime
+// IsTaggedWithAnyInTaxonomy also matches the taxonomy ancestors of the tags
+func (what TechnicalAsset) IsTaggedWithAnyInTaxonomy(model *Model, tags ...string) bool {
+	return containsCaseInsensitiveAny(model.TagTaxonomy.Expand(what.Tags), tags...)
+}
+
+// first use the tag(s) of the asset itself, then their trust boundaries (recursively up) and then their shared runtime,
+// also matching the taxonomy ancestors of the tags
 
 func (what TechnicalAsset) IsTaggedWithAnyTraversingUp(model *Model, tags ...string) bool {
-	if containsCaseInsensitiveAny(what.Tags, tags...) {
+	if what.IsTaggedWithAnyInTaxonomy(model, tags...) {
 		return true
 	}
 	tbID := what.GetTrustBoundaryId(model)
@@ -61,7 +71,7 @@ func (what TechnicalAsset) IsTaggedWithAnyTraversingUp(model *Model, tags ...str
 		}
 	}
 	for _, sr := range model.SharedRuntimes {
-		if contains(sr.TechnicalAssetsRunning, what.Id) && sr.IsTaggedWithAny(tags...) {
+		if contains(sr.TechnicalAssetsRunning, what.Id) && sr.IsTaggedWithAnyInTaxonomy(model, tags...) {
 			return true
 		}
 	}
@@ -283,7 +293,7 @@ func (what TechnicalAsset) HasDirectConnection(parsedModel *Model, otherAssetId
 func (what TechnicalAsset) GeneratedRisks(parsedModel *Model) []*Risk {
 	resultingRisks := make([]*Risk, 0)
 	if len(SortedRiskCategories(parsedModel)) == 0 {
-		fmt.Println("Uh, strange, no risks generated (yet?) and asking for them by tech asset...")
+		fmt.Fprintln(common.LogStdout(), "Uh, strange, no risks generated (yet?) and asking for them by tech asset...")
 	}
 	for _, category := range SortedRiskCategories(parsedModel) {
 		risks := SortedRisksOfCategory(parsedModel, category)
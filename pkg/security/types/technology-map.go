@@ -31,6 +31,10 @@ func (what TechnologyMap) LoadWithConfig(config *common.Config, defaultFilename
 		}
 	}
 
+	for name, technology := range customTechnologies {
+		what[name] = technology
+	}
+
 	if len(config.TechnologyFilename) > 0 {
 		additionalTechnologies := make(TechnologyMap)
 		loadError := additionalTechnologies.LoadFromFile(config.TechnologyFilename)
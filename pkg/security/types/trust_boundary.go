@@ -13,6 +13,7 @@ type TrustBoundary struct {
 	Title                 string            `json:"title,omitempty" yaml:"title,omitempty"`
 	Description           string            `json:"description,omitempty" yaml:"description,omitempty"`
 	Type                  TrustBoundaryType `json:"type,omitempty" yaml:"type,omitempty"`
+	Criticality           Criticality       `json:"criticality,omitempty" yaml:"criticality,omitempty"` // defaults to the business criticality of the model
 	Tags                  []string          `json:"tags,omitempty" yaml:"tags,omitempty"`
 	TechnicalAssetsInside []string          `json:"technical_assets_inside,omitempty" yaml:"technical_assets_inside,omitempty"`
 	TrustBoundariesNested []string          `json:"trust_boundaries_nested,omitempty" yaml:"trust_boundaries_nested,omitempty"`
@@ -32,8 +33,13 @@ func (what TrustBoundary) IsTaggedWithBaseTag(baseTag string) bool {
 	return IsTaggedWithBaseTag(what.Tags, baseTag)
 }
 
+// IsTaggedWithAnyInTaxonomy also matches the taxonomy ancestors of the tags
+func (what TrustBoundary) IsTaggedWithAnyInTaxonomy(model *Model, tags ...string) bool {
+	return containsCaseInsensitiveAny(model.TagTaxonomy.Expand(what.Tags), tags...)
+}
+
 func (what TrustBoundary) IsTaggedWithAnyTraversingUp(model *Model, tags ...string) bool {
-	if what.IsTaggedWithAny(tags...) {
+	if what.IsTaggedWithAnyInTaxonomy(model, tags...) {
 		return true
 	}
 	parentID := what.ParentTrustBoundaryID(model)
@@ -24,7 +24,7 @@ const (
 )
 
 func TrustBoundaryTypeValues() []TypeEnum {
-	return []TypeEnum{
+	values := []TypeEnum{
 		NetworkOnPrem,
 		NetworkDedicatedHoster,
 		NetworkVirtualLAN,
@@ -33,6 +33,10 @@ func TrustBoundaryTypeValues() []TypeEnum {
 		NetworkPolicyNamespaceIsolation,
 		ExecutionEnvironment,
 	}
+	for index := range customTrustBoundaryTypes {
+		values = append(values, TrustBoundaryType(len(TrustBoundaryTypeDescription)+index))
+	}
+	return values
 }
 
 var TrustBoundaryTypeDescription = [...]TypeDescription{
@@ -57,14 +61,23 @@ func ParseTrustBoundary(value string) (trustBoundary TrustBoundaryType, err erro
 
 func (what TrustBoundaryType) String() string {
 	// NOTE: maintain list also in schema.json for validation in IDEs
+	if custom, ok := what.custom(); ok {
+		return custom.Name
+	}
 	return TrustBoundaryTypeDescription[what].Name
 }
 
 func (what TrustBoundaryType) Explain() string {
+	if custom, ok := what.custom(); ok {
+		return custom.Description
+	}
 	return TrustBoundaryTypeDescription[what].Description
 }
 
 func (what TrustBoundaryType) IsNetworkBoundary() bool {
+	if custom, ok := what.custom(); ok {
+		return custom.networkBoundary
+	}
 	return what == NetworkOnPrem || what == NetworkDedicatedHoster || what == NetworkVirtualLAN ||
 		what == NetworkCloudProvider || what == NetworkCloudSecurityGroup || what == NetworkPolicyNamespaceIsolation
 }
@@ -113,6 +126,11 @@ func (what TrustBoundaryType) find(value string) (TrustBoundaryType, error) {
 			return TrustBoundaryType(index), nil
 		}
 	}
+	for index, custom := range customTrustBoundaryTypes {
+		if strings.EqualFold(value, custom.Name) {
+			return TrustBoundaryType(len(TrustBoundaryTypeDescription) + index), nil
+		}
+	}
 
 	return TrustBoundaryType(0), fmt.Errorf("unknown trust boundary type value %q", value)
 }
@@ -0,0 +1,324 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/threagile/threagile/pkg/common"
+	"github.com/threagile/threagile/pkg/input"
+)
+
+// analysisJobTTL is how long finished analysis jobs can be polled (their results stay stored alongside the model)
+const analysisJobTTL = time.Hour
+
+// maxAnalysisJobProgress is how many progress events a job keeps, dropping the oldest ones
+const maxAnalysisJobProgress = 1000
+
+// errModelDeletedDuringAnalysis is the failure of jobs whose model was deleted before their result could be stored
+var errModelDeletedDuringAnalysis = errors.New("model was deleted during the analysis")
+
+const (
+	analysisJobRunning = "running"
+	analysisJobDone    = "done"
+	analysisJobFailed  = "failed"
+)
+
+// analysisJob is an analysis of a stored model running in the background, so that the analysis of large models does
+// not have to complete within a request; the result is stored alongside the model like the one of a direct analysis
+type analysisJob struct {
+	id              string
+	folderNameOfKey string
+	modelId         string
+	modelFolder     string
+	modelHash       string
+	status          string
+	progress        []common.ProgressEvent
+	err             string
+	errCode         errorCode
+	details         []string
+	createdAt       time.Time
+	finishedAt      time.Time
+}
+
+type payloadAnalysisJob struct {
+	Id         string                 `json:"id"`
+	ModelId    string                 `json:"model_id"`
+	Status     string                 `json:"status"`   // running, done (the result can be downloaded) or failed
+	Progress   []payloadProgressEvent `json:"progress"` // the progress of the analysis so far (the latest events of it)
+	Error      string                 `json:"error,omitempty"`
+	Code       errorCode              `json:"code,omitempty"` // of the error, like the one of a failed direct analysis
+	Details    []string               `json:"details,omitempty"`
+	CreatedAt  time.Time              `json:"created_at"`
+	FinishedAt *time.Time             `json:"finished_at,omitempty"`
+}
+
+// payloadProgressEvent is a message reported by the analysis while it progresses
+type payloadProgressEvent struct {
+	Level   string `json:"level"` // info, warning or error
+	Message string `json:"message"`
+}
+
+// payload is a snapshot of the job, which has to be locked by the caller
+func (what *analysisJob) payload() payloadAnalysisJob {
+	payload := payloadAnalysisJob{
+		Id:        what.id,
+		ModelId:   what.modelId,
+		Status:    what.status,
+		Progress:  make([]payloadProgressEvent, 0, len(what.progress)),
+		Error:     what.err,
+		Code:      what.errCode,
+		Details:   what.details,
+		CreatedAt: what.createdAt,
+	}
+	for _, event := range what.progress {
+		payload.Progress = append(payload.Progress, payloadProgressEvent{Level: event.Level, Message: event.Message})
+	}
+	if !what.finishedAt.IsZero() {
+		finishedAt := what.finishedAt
+		payload.FinishedAt = &finishedAt
+	}
+	return payload
+}
+
+// createAnalysisJob starts the analysis of the model in the background, answering the job to poll; a model already
+// analyzed with the same options is answered as done job right away (without counting against the quota), one being
+// analyzed with them as the running job, and a model being analyzed with other ones is a conflict
+func (s *server) createAnalysisJob(ginContext *gin.Context) {
+	folderNameOfKey, key, ok := s.checkTokenToFolderName(ginContext)
+	if !ok {
+		return
+	}
+	dpi, ok := s.requestedDPI(ginContext, folderNameOfKey)
+	if !ok {
+		return
+	}
+
+	s.lockFolder(folderNameOfKey)
+	defer s.unlockFolder(folderNameOfKey)
+	modelInput, yamlText, ok := s.readModel(ginContext, ginContext.Param("model-id"), key, folderNameOfKey)
+	if !ok {
+		return
+	}
+	modelFolder := folderNameForModel(folderNameOfKey, ginContext.Param("model-id"))
+	versionText, commentsData, err := s.outputVersion(modelFolder, key, yamlText)
+	if err != nil {
+		handleErrorInServiceCall(err, ginContext)
+		return
+	}
+	job := &analysisJob{
+		id:              uuid.New().String(),
+		folderNameOfKey: folderNameOfKey,
+		modelId:         ginContext.Param("model-id"),
+		modelFolder:     modelFolder,
+		modelHash:       s.resultHash(versionText, dpi),
+		status:          analysisJobRunning,
+		progress:        make([]common.ProgressEvent, 0),
+		createdAt:       time.Now().UTC(),
+	}
+	_, err = s.storage.Stat(resultFilename(job.modelFolder, job.modelHash))
+	stored := err == nil
+	if stored {
+		job.status, job.finishedAt = analysisJobDone, job.createdAt
+	}
+
+	// the jobs of the model are only created while holding the lock of its key, so no other one starts meanwhile
+	s.analysisJobsLock.Lock()
+	var running *analysisJob
+	for _, other := range s.analysisJobs {
+		if other.status == analysisJobRunning && other.modelFolder == job.modelFolder {
+			running = other
+		}
+	}
+	var payload payloadAnalysisJob
+	if running != nil {
+		payload = running.payload()
+	}
+	s.analysisJobsLock.Unlock()
+	if running != nil && !stored {
+		if running.modelHash != job.modelHash {
+			respondError(ginContext, http.StatusConflict, errorCodeConflict, "model is already being analyzed by analysis job "+running.id)
+			return
+		}
+		ginContext.Header("Location", "/analysis-jobs/"+running.id)
+		ginContext.JSON(http.StatusAccepted, payload)
+		return
+	}
+	if !stored && !s.checkAnalysisQuota(ginContext, folderNameOfKey) {
+		return
+	}
+
+	s.analysisJobsLock.Lock()
+	if s.analysisJobs == nil {
+		s.analysisJobs = make(map[string]*analysisJob)
+	}
+	for id, expired := range s.analysisJobs {
+		if expired.status != analysisJobRunning && time.Since(expired.finishedAt) > analysisJobTTL {
+			delete(s.analysisJobs, id)
+		}
+	}
+	s.analysisJobs[job.id] = job
+	payload = job.payload()
+	s.analysisJobsLock.Unlock()
+
+	if !stored {
+		go s.runAnalysisJob(job, key, modelInput, yamlText, commentsData, dpi)
+	}
+	ginContext.Header("Location", "/analysis-jobs/"+job.id)
+	ginContext.JSON(http.StatusAccepted, payload)
+}
+
+func (s *server) runAnalysisJob(job *analysisJob, key []byte, modelInput input.Model, yamlText string, commentsData []byte, dpi int) {
+	err := s.renderAnalysisJob(job, key, modelInput, yamlText, commentsData, dpi)
+
+	s.analysisJobsLock.Lock()
+	defer s.analysisJobsLock.Unlock()
+	job.finishedAt = time.Now().UTC()
+	if err == nil {
+		job.status = analysisJobDone
+		return
+	}
+	log.Println(err)
+	job.fail(err)
+}
+
+// addProgress records the progress event, dropping the oldest one once there are maxAnalysisJobProgress of them; the
+// job has to be locked by the caller
+func (what *analysisJob) addProgress(event common.ProgressEvent) {
+	if len(what.progress) >= maxAnalysisJobProgress {
+		what.progress = append(what.progress[:0], what.progress[1:]...)
+	}
+	what.progress = append(what.progress, event)
+}
+
+// fail records the error the job failed with, which has to be locked by the caller
+func (what *analysisJob) fail(err error) {
+	what.status = analysisJobFailed
+	var failure *common.Failure
+	switch {
+	case errors.As(err, &failure) && failure.Code == common.ExitCodePolicyGateFailure:
+		what.err, what.errCode, what.details = strings.TrimSpace(err.Error()), errorCodePolicyViolation, failure.Details
+	case errors.As(err, &failure): // the analysis of the model failed, like a direct one fails for invalid models
+		what.err, what.errCode = strings.TrimSpace(err.Error()), errorCodeBadRequest
+	case errors.Is(err, errModelDeletedDuringAnalysis):
+		what.err, what.errCode = err.Error(), errorCodeModelNotFound
+	default:
+		what.err, what.errCode = "unable to analyze model", errorCodeInternal
+	}
+}
+
+// renderAnalysisJob renders the result without holding the lock of the key (so the models stay editable meanwhile)
+// and stores it alongside the model, unless the model was deleted meanwhile
+func (s *server) renderAnalysisJob(job *analysisJob, key []byte, modelInput input.Model, yamlText string, commentsData []byte, dpi int) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			s.errorCount++
+			var isError bool
+			if err, isError = r.(error); !isError {
+				err = fmt.Errorf("%v", r)
+			}
+		}
+	}()
+
+	workspace, err := newTempWorkspace(s.config.TempFolder, "analysis-job")
+	if err != nil {
+		return err
+	}
+	defer workspace.Close()
+	tmpResultFile, err := workspace.CreateFile("threagile-result-*.zip")
+	if err != nil {
+		return err
+	}
+	_ = tmpResultFile.Close()
+
+	outputDir, err := s.renderAnalysisResult(context.Background(), workspace, yamlText, commentsData, dpi, tmpResultFile.Name(), func(event common.ProgressEvent) {
+		s.analysisJobsLock.Lock()
+		defer s.analysisJobsLock.Unlock()
+		job.addProgress(event)
+	})
+	if err != nil {
+		return err
+	}
+
+	s.lockFolder(job.folderNameOfKey)
+	defer s.unlockFolder(job.folderNameOfKey)
+	if _, err := s.storage.Stat(job.modelFolder); os.IsNotExist(err) {
+		return errModelDeletedDuringAnalysis
+	}
+	s.recordAnalysisResult(job.modelFolder, key, job.modelId, modelInput, job.modelHash, outputDir, tmpResultFile.Name())
+	return nil
+}
+
+// analysisJobOfRequest is the job of the path parameter, if it belongs to the key of the token
+func (s *server) analysisJobOfRequest(ginContext *gin.Context) (*analysisJob, []byte, bool) {
+	folderNameOfKey, key, ok := s.checkTokenToFolderName(ginContext)
+	if !ok {
+		return nil, nil, false
+	}
+	s.analysisJobsLock.Lock()
+	job, found := s.analysisJobs[ginContext.Param("analysis-job-id")]
+	s.analysisJobsLock.Unlock()
+	if !found || job.folderNameOfKey != folderNameOfKey {
+		respondError(ginContext, http.StatusNotFound, errorCodeAnalysisJobNotFound, "analysis job not found")
+		return nil, nil, false
+	}
+	return job, key, true
+}
+
+func (s *server) getAnalysisJob(ginContext *gin.Context) {
+	job, _, ok := s.analysisJobOfRequest(ginContext)
+	if !ok {
+		return
+	}
+	s.analysisJobsLock.Lock()
+	payload := job.payload()
+	s.analysisJobsLock.Unlock()
+	ginContext.JSON(http.StatusOK, payload)
+}
+
+// streamAnalysisJobResult answers the zipped outputs of the done job, like the direct analysis of the model does
+func (s *server) streamAnalysisJobResult(ginContext *gin.Context) {
+	job, key, ok := s.analysisJobOfRequest(ginContext)
+	if !ok {
+		return
+	}
+	s.analysisJobsLock.Lock()
+	status := job.status
+	s.analysisJobsLock.Unlock()
+	if status != analysisJobDone {
+		respondError(ginContext, http.StatusConflict, errorCodeConflict, "analysis job is "+status)
+		return
+	}
+
+	s.lockFolder(job.folderNameOfKey)
+	defer s.unlockFolder(job.folderNameOfKey)
+	workspace, err := newTempWorkspace(s.config.TempFolder, "analysis-job-result")
+	if err != nil {
+		handleErrorInServiceCall(err, ginContext)
+		return
+	}
+	defer workspace.Close()
+	tmpResultFile, err := workspace.CreateFile("threagile-result-*.zip")
+	if err != nil {
+		handleErrorInServiceCall(err, ginContext)
+		return
+	}
+	_ = tmpResultFile.Close()
+	restored, err := s.restoreAnalysisResult(job.modelFolder, key, job.modelHash, tmpResultFile.Name())
+	if err != nil {
+		handleErrorInServiceCall(err, ginContext)
+		return
+	}
+	if !restored { // replaced by newer results (see analysisResultsToKeep) or deleted with the model
+		respondError(ginContext, http.StatusNotFound, errorCodeAnalysisJobNotFound, "result of the analysis job is no longer stored")
+		return
+	}
+	ginContext.FileAttachment(tmpResultFile.Name(), "threagile-result.zip")
+}
@@ -0,0 +1,126 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/threagile/threagile/pkg/common"
+)
+
+func TestAnalysisJobOfStoredResult(t *testing.T) {
+	m := newModelTestServer(t, communicationLinkTestModel)
+	ginContext, _ := gin.CreateTestContext(httptest.NewRecorder())
+	ginContext.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+	ginContext.Request.Header.Set("token", m.token)
+	folderNameOfKey, key, ok := m.checkTokenToFolderName(ginContext)
+	assert.True(t, ok)
+	_, yamlText, err := m.decryptModelFile(m.modelFolder, key)
+	assert.NoError(t, err)
+
+	workspace, err := newTempWorkspace(t.TempDir(), "test")
+	assert.NoError(t, err)
+	defer workspace.Close()
+	resultFile := filepath.Join(workspace.Dir, "result.zip")
+	reportFile := filepath.Join(workspace.Dir, "report.pdf")
+	assert.NoError(t, os.WriteFile(reportFile, []byte("report"), 0600))
+	assert.NoError(t, zipFiles(resultFile, []string{reportFile}))
+	assert.NoError(t, m.storeAnalysisResult(m.modelFolder, key, m.resultHash(yamlText, 0), resultFile))
+
+	recorder := m.call(m.createAnalysisJob, http.MethodPost, nil, nil)
+	assert.Equal(t, http.StatusAccepted, recorder.Code)
+	var job payloadAnalysisJob
+	assert.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &job))
+	assert.Equal(t, analysisJobDone, job.Status, "served from the stored result")
+	assert.Equal(t, "/analysis-jobs/"+job.Id, recorder.Header().Get("Location"))
+
+	jobParams := gin.Params{{Key: "analysis-job-id", Value: job.Id}}
+	recorder = m.call(m.getAnalysisJob, http.MethodGet, jobParams, nil)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &job))
+	assert.Equal(t, m.modelID, job.ModelId)
+	assert.NotNil(t, job.FinishedAt)
+
+	recorder = m.call(m.streamAnalysisJobResult, http.MethodGet, jobParams, nil)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	downloaded := filepath.Join(t.TempDir(), "downloaded.zip")
+	assert.NoError(t, os.WriteFile(downloaded, recorder.Body.Bytes(), 0600))
+	files, err := unzip(downloaded, t.TempDir())
+	assert.NoError(t, err)
+	assert.Len(t, files, 1)
+
+	m.analysisJobs["running"] = &analysisJob{id: "running", folderNameOfKey: folderNameOfKey, status: analysisJobRunning}
+	recorder = m.call(m.streamAnalysisJobResult, http.MethodGet, gin.Params{{Key: "analysis-job-id", Value: "running"}}, nil)
+	assert.Equal(t, http.StatusConflict, recorder.Code)
+	m.analysisJobs["other-key"] = &analysisJob{id: "other-key", folderNameOfKey: "other", status: analysisJobDone}
+	recorder = m.call(m.getAnalysisJob, http.MethodGet, gin.Params{{Key: "analysis-job-id", Value: "other-key"}}, nil)
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), string(errorCodeAnalysisJobNotFound))
+}
+
+func TestAnalysisJobsOfModelBeingAnalyzed(t *testing.T) {
+	m := newModelTestServer(t, communicationLinkTestModel)
+	ginContext, _ := gin.CreateTestContext(httptest.NewRecorder())
+	ginContext.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+	ginContext.Request.Header.Set("token", m.token)
+	folderNameOfKey, key, ok := m.checkTokenToFolderName(ginContext)
+	assert.True(t, ok)
+	_, yamlText, err := m.decryptModelFile(m.modelFolder, key)
+	assert.NoError(t, err)
+
+	m.analysisJobs = map[string]*analysisJob{"running": {id: "running", folderNameOfKey: folderNameOfKey, modelFolder: m.modelFolder, modelHash: m.resultHash(yamlText, 0), status: analysisJobRunning}}
+	recorder := m.call(m.createAnalysisJob, http.MethodPost, nil, nil)
+	assert.Equal(t, http.StatusAccepted, recorder.Code)
+	assert.Equal(t, "/analysis-jobs/running", recorder.Header().Get("Location"), "the job analyzing the same model")
+	assert.Len(t, m.analysisJobs, 1)
+
+	m.analysisJobs["running"].modelHash = "of another version"
+	recorder = m.call(m.createAnalysisJob, http.MethodPost, nil, nil)
+	assert.Equal(t, http.StatusConflict, recorder.Code)
+	assert.Len(t, m.analysisJobs, 1)
+}
+
+func TestAnalysisJobFailures(t *testing.T) {
+	for _, test := range []struct {
+		err     error
+		code    errorCode
+		message string
+	}{
+		{common.NewFailure(common.ExitCodeParseError, fmt.Errorf("invalid model\n")), errorCodeBadRequest, "invalid model"},
+		{&common.Failure{Code: common.ExitCodePolicyGateFailure, Err: fmt.Errorf("policy violated"), Details: []string{"no tls"}}, errorCodePolicyViolation, "policy violated"},
+		{errModelDeletedDuringAnalysis, errorCodeModelNotFound, errModelDeletedDuringAnalysis.Error()},
+		{fmt.Errorf("open /tmp/secret/workspace: permission denied"), errorCodeInternal, "unable to analyze model"},
+	} {
+		job := &analysisJob{status: analysisJobRunning}
+		job.fail(test.err)
+		assert.Equal(t, analysisJobFailed, job.status)
+		assert.Equal(t, test.code, job.errCode)
+		assert.Equal(t, test.message, job.err)
+	}
+}
+
+func TestAnalysisJobProgressIsBounded(t *testing.T) {
+	job := &analysisJob{}
+	for index := 0; index < maxAnalysisJobProgress+5; index++ {
+		job.addProgress(common.ProgressEvent{Level: common.ProgressEventInfo, Message: fmt.Sprint(index)})
+	}
+	assert.Len(t, job.progress, maxAnalysisJobProgress)
+	assert.Equal(t, "5", job.payload().Progress[0].Message, "the oldest events dropped")
+}
+
+func TestProgressWriter(t *testing.T) {
+	events := make([]common.ProgressEvent, 0)
+	writer := &progressWriter{progress: func(event common.ProgressEvent) { events = append(events, event) }}
+	_, _ = writer.Write([]byte("Parsing model\n{\"level\":\"info\",\"message\":\"Writing da"))
+	_, _ = writer.Write([]byte("ta flow diagram input\"}\n\n"))
+	_, _ = writer.Write([]byte(`{"level":"warning","message":"unused tag"}`))
+	assert.Equal(t, []common.ProgressEvent{{Level: common.ProgressEventInfo, Message: "Writing data flow diagram input"}}, events, "only complete lines of events")
+	assert.Contains(t, writer.buffer.String(), "Parsing model\n")
+}
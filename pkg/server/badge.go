@@ -0,0 +1,285 @@
+package server
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"html"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+// shareTokenFilename is stored alongside the model and holds the hash of the share token and the key of the model
+// wrapped by it (like the grants do, see model-key.go), so the public read-only endpoints of the model work without the key
+const shareTokenFilename = "share-token.json"
+
+type shareToken struct {
+	Hash       string `json:"hash"`
+	WrappedKey []byte `json:"wrapped_key"`
+}
+
+// badgeMetrics are the risk counts available as badge, each counting the risks still at risk of the given severities
+var badgeMetrics = map[string][]types.RiskSeverity{
+	"critical-risks": {types.CriticalSeverity},
+	"high-risks":     {types.HighSeverity},
+	"elevated-risks": {types.ElevatedSeverity},
+	"medium-risks":   {types.MediumSeverity},
+	"low-risks":      {types.LowSeverity},
+	"risks":          {types.CriticalSeverity, types.HighSeverity, types.ElevatedSeverity, types.MediumSeverity, types.LowSeverity},
+}
+
+var badgeColors = map[types.RiskSeverity]string{
+	types.CriticalSeverity: "#e05d44",
+	types.HighSeverity:     "#fe7d37",
+	types.ElevatedSeverity: "#dfb317",
+	types.MediumSeverity:   "#a4a61d",
+	types.LowSeverity:      "#97ca00",
+}
+
+const badgeColorNoRisks = "#4c1"
+
+func (s *server) createShareToken(ginContext *gin.Context) {
+	folderNameOfKey, key, ok := s.checkTokenToFolderName(ginContext)
+	if !ok {
+		return
+	}
+	s.lockFolder(folderNameOfKey)
+	defer s.unlockFolder(folderNameOfKey)
+	modelFolder, ok := s.checkModelFolder(ginContext, ginContext.Param("model-id"), folderNameOfKey)
+	if !ok {
+		return
+	}
+	err := s.migrateModelKey(modelFolder, key)
+	if err != nil {
+		log.Println(err)
+		respondError(ginContext, http.StatusInternalServerError, errorCodeInternal, "unable to create share token")
+		return
+	}
+	modelKey, err := s.modelCryptoKey(modelFolder, key)
+	if err != nil {
+		log.Println(err)
+		respondError(ginContext, http.StatusInternalServerError, errorCodeInternal, "unable to create share token")
+		return
+	}
+
+	token := make([]byte, keySize)
+	n, err := rand.Read(token)
+	if n != keySize || err != nil {
+		log.Println(err)
+		respondError(ginContext, http.StatusInternalServerError, errorCodeInternal, "unable to create share token")
+		return
+	}
+	wrappedKey, err := encryptWithKey(generateKeyFromAlreadyStrongRandomInput(token), modelKey)
+	var data []byte
+	if err == nil {
+		data, err = json.Marshal(shareToken{Hash: hashSHA256(token), WrappedKey: wrappedKey})
+	}
+	if err == nil {
+		err = s.storage.WriteFile(filepath.Join(modelFolder, shareTokenFilename), data) // replaces (hence revokes) any previous share token
+	}
+	if err != nil {
+		log.Println(err)
+		respondError(ginContext, http.StatusInternalServerError, errorCodeInternal, "unable to create share token")
+		return
+	}
+
+	encodedToken := base64.RawURLEncoding.EncodeToString(token)
+	ginContext.JSON(http.StatusCreated, gin.H{
+		"share_token": encodedToken,
+		"badge":       "/models/" + filepath.Base(modelFolder) + "/badge.svg?metric=critical-risks&share-token=" + encodedToken,
+		"calendar":    "/models/" + filepath.Base(modelFolder) + "/due-dates.ics?share-token=" + encodedToken,
+	})
+}
+
+func (s *server) deleteShareToken(ginContext *gin.Context) {
+	folderNameOfKey, _, ok := s.checkTokenToFolderName(ginContext)
+	if !ok {
+		return
+	}
+	s.lockFolder(folderNameOfKey)
+	defer s.unlockFolder(folderNameOfKey)
+	modelFolder, ok := s.checkModelFolder(ginContext, ginContext.Param("model-id"), folderNameOfKey)
+	if !ok {
+		return
+	}
+	err := s.storage.Remove(filepath.Join(modelFolder, shareTokenFilename))
+	if err != nil {
+		respondError(ginContext, http.StatusNotFound, errorCodeShareTokenNotFound, "share token not found")
+		return
+	}
+	ginContext.JSON(http.StatusOK, gin.H{
+		"message": "share token deleted",
+	})
+}
+
+// checkShareTokenToFolderName finds the key folder of the model the share token (query parameter) was created for, the
+// share token taking the place of the key (see model-key.go)
+func (s *server) checkShareTokenToFolderName(ginContext *gin.Context) (folderNameOfKey string, key []byte, ok bool) {
+	notFound := func() (string, []byte, bool) {
+		respondError(ginContext, http.StatusNotFound, errorCodeShareTokenNotFound, "share token not found")
+		return "", nil, false
+	}
+
+	modelUUID, err := uuid.Parse(ginContext.Param("model-id"))
+	if err != nil {
+		return notFound()
+	}
+	token, err := base64.RawURLEncoding.DecodeString(strings.TrimSpace(ginContext.Query("share-token")))
+	if len(token) != keySize || err != nil {
+		return notFound()
+	}
+
+	candidates, err := s.globKeyFolders(modelUUID.String(), shareTokenFilename)
+	if err != nil {
+		log.Println(err)
+		return notFound()
+	}
+	tokenHash := hashSHA256(token)
+	for _, candidate := range candidates {
+		data, readError := s.storage.ReadFile(candidate)
+		if readError != nil {
+			continue
+		}
+		var stored shareToken
+		if json.Unmarshal(data, &stored) != nil || subtle.ConstantTimeCompare([]byte(stored.Hash), []byte(tokenHash)) != 1 {
+			continue
+		}
+		return filepath.Dir(filepath.Dir(candidate)), token, true
+	}
+	return notFound()
+}
+
+// streamBadge answers the public badge of a model (accessed via its share token) showing a risk count of the current
+// model version, for embedding it into READMEs and dashboards
+func (s *server) streamBadge(ginContext *gin.Context) {
+	metric := ginContext.DefaultQuery("metric", "critical-risks")
+	severities, ok := badgeMetrics[metric]
+	if !ok {
+		respondError(ginContext, http.StatusBadRequest, errorCodeBadRequest, "unknown badge metric: "+metric)
+		return
+	}
+	folderNameOfKey, key, ok := s.checkShareTokenToFolderName(ginContext)
+	if !ok {
+		return
+	}
+	s.lockFolder(folderNameOfKey)
+	defer s.unlockFolder(folderNameOfKey)
+
+	_, yamlText, ok := s.readModel(ginContext, ginContext.Param("model-id"), key, folderNameOfKey)
+	if !ok {
+		return
+	}
+	modelFolder := folderNameForModel(folderNameOfKey, ginContext.Param("model-id"))
+	if s.notModified(ginContext, modelFolder, riskBadge, yamlText, s.config.GraphvizDPI) {
+		return
+	}
+	statistics, ok := s.riskStatistics(ginContext, folderNameOfKey, modelFolder, key, yamlText)
+	if !ok {
+		return
+	}
+	count := countRisksStillAtRisk(statistics, severities)
+	ginContext.Data(http.StatusOK, "image/svg+xml", badgeSVG(strings.ReplaceAll(metric, "-", " "), strconv.Itoa(count), badgeColor(statistics, severities)))
+}
+
+// badgeColor is the color of the highest severity counted
+func badgeColor(statistics types.RiskStatistics, severities []types.RiskSeverity) string {
+	for _, severity := range severities {
+		if countRisksStillAtRisk(statistics, []types.RiskSeverity{severity}) > 0 {
+			return badgeColors[severity]
+		}
+	}
+	return badgeColorNoRisks
+}
+
+// riskStatistics takes the statistics of the stored analysis result of the model version, or else analyzes it in-process
+// (counting against the analysis quota of the key), responding the errors
+func (s *server) riskStatistics(ginContext *gin.Context, folderNameOfKey string, modelFolder string, key []byte, yamlText string) (types.RiskStatistics, bool) {
+	statistics, err := s.restoreOrAnalyzeRiskStatistics(ginContext, folderNameOfKey, modelFolder, key, yamlText)
+	if err != nil {
+		handleErrorInServiceCall(err, ginContext)
+		return statistics, false
+	}
+	return statistics, !ginContext.IsAborted()
+}
+
+func (s *server) restoreOrAnalyzeRiskStatistics(ginContext *gin.Context, folderNameOfKey string, modelFolder string, key []byte, yamlText string) (types.RiskStatistics, error) {
+	var statistics types.RiskStatistics
+	workspace, err := newTempWorkspace(s.config.TempFolder, "badge")
+	if err != nil {
+		return statistics, err
+	}
+	defer workspace.Close()
+	tmpOutputDir, err := workspace.Mkdir("output")
+	if err != nil {
+		return statistics, err
+	}
+
+	versionText, _, err := s.outputVersion(modelFolder, key, yamlText) // of the stored result, though the statistics do not show the comments
+	if err != nil {
+		return statistics, err
+	}
+	restored, err := s.restoreAnalysisResultFile(workspace, modelFolder, key, s.resultHash(versionText, s.config.GraphvizDPI), tmpOutputDir, s.config.JsonStatsFilename)
+	if err != nil {
+		log.Println(err)
+	}
+	if restored {
+		data, err := os.ReadFile(filepath.Clean(filepath.Join(tmpOutputDir, s.config.JsonStatsFilename)))
+		if err != nil {
+			return statistics, err
+		}
+		return statistics, json.Unmarshal(data, &statistics)
+	}
+	if !s.checkAnalysisQuota(ginContext, folderNameOfKey) {
+		return statistics, nil // answered as too many requests
+	}
+
+	tmpModelFile, err := workspace.CreateFile("threagile-badge-*")
+	if err != nil {
+		return statistics, err
+	}
+	err = os.WriteFile(tmpModelFile.Name(), []byte(yamlText), 0400)
+	if err != nil {
+		return statistics, err
+	}
+	readResult, err := s.analyzeInProcess(ginContext.Request.Context(), workspace, tmpModelFile.Name(), tmpOutputDir)
+	if err != nil {
+		return statistics, err
+	}
+	return types.OverallRiskStatistics(readResult.ParsedModel), nil
+}
+
+func countRisksStillAtRisk(statistics types.RiskStatistics, severities []types.RiskSeverity) int {
+	count := 0
+	for _, severity := range severities {
+		for _, status := range []types.RiskStatus{types.Unchecked, types.InDiscussion, types.Accepted, types.InProgress} {
+			count += statistics.Risks[severity.String()][status.String()]
+		}
+	}
+	return count
+}
+
+// badgeSVG renders a badge in the flat style of shields.io, the text widths are estimated for the 11px Verdana font
+func badgeSVG(label string, value string, color string) []byte {
+	labelWidth, valueWidth := 7*len(label)+10, 7*len(value)+10
+	width := labelWidth + valueWidth
+	label, value = html.EscapeString(label), html.EscapeString(value)
+	return []byte(fmt.Sprintf(`<svg xmlns="http://www.w3.org/2000/svg" width="%[1]d" height="20" role="img" aria-label="%[4]s: %[5]s">`+
+		`<title>%[4]s: %[5]s</title>`+
+		`<linearGradient id="s" x2="0" y2="100%%"><stop offset="0" stop-color="#bbb" stop-opacity=".1"/><stop offset="1" stop-opacity=".1"/></linearGradient>`+
+		`<clipPath id="r"><rect width="%[1]d" height="20" rx="3" fill="#fff"/></clipPath>`+
+		`<g clip-path="url(#r)"><rect width="%[2]d" height="20" fill="#555"/><rect x="%[2]d" width="%[3]d" height="20" fill="%[6]s"/><rect width="%[1]d" height="20" fill="url(#s)"/></g>`+
+		`<g fill="#fff" text-anchor="middle" font-family="Verdana,Geneva,DejaVu Sans,sans-serif" font-size="11">`+
+		`<text x="%[7]d" y="15" fill="#010101" fill-opacity=".3">%[4]s</text><text x="%[7]d" y="14">%[4]s</text>`+
+		`<text x="%[8]d" y="15" fill="#010101" fill-opacity=".3">%[5]s</text><text x="%[8]d" y="14">%[5]s</text></g></svg>`,
+		width, labelWidth, valueWidth, label, value, html.EscapeString(color), labelWidth/2, labelWidth+valueWidth/2))
+}
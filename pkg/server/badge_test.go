@@ -0,0 +1,74 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+func TestShareTokenUnwrapsModelKey(t *testing.T) {
+	m := newModelTestServer(t, communicationLinkTestModel)
+	recorder := m.call(m.createShareToken, http.MethodPost, nil, nil)
+	assert.Equal(t, http.StatusCreated, recorder.Code)
+	var response map[string]string
+	assert.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+	assert.Equal(t, "/models/"+m.modelID+"/due-dates.ics?share-token="+response["share_token"], response["calendar"])
+	stored, err := m.storage.ReadFile(filepath.Join(m.modelFolder, shareTokenFilename))
+	assert.NoError(t, err)
+	assert.NotContains(t, string(stored), "xor")
+
+	check := func(shareToken string) (string, []byte, bool) {
+		ginContext, _ := gin.CreateTestContext(httptest.NewRecorder())
+		ginContext.Request = httptest.NewRequest(http.MethodGet, "/models/"+m.modelID+"/badge.svg?share-token="+shareToken, nil)
+		ginContext.Params = gin.Params{{Key: "model-id", Value: m.modelID}}
+		return m.checkShareTokenToFolderName(ginContext)
+	}
+	folderNameOfKey, shareTokenKey, ok := check(response["share_token"])
+	assert.True(t, ok)
+	assert.Equal(t, filepath.Dir(m.modelFolder), folderNameOfKey)
+	_, _, err = m.decryptModelFile(m.modelFolder, shareTokenKey)
+	assert.NoError(t, err)
+	_, _, ok = check(m.token)
+	assert.False(t, ok)
+}
+
+func TestBadgeAndCalendarQuota(t *testing.T) {
+	m := newModelTestServer(t, communicationLinkTestModel)
+	m.config.MaxAnalysesPerHour = 1
+	m.analysesByFolderName = map[string][]int64{filepath.Dir(m.modelFolder): {time.Now().UnixNano()}}
+	recorder := m.call(m.createShareToken, http.MethodPost, nil, nil)
+	var response map[string]string
+	assert.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+
+	for url, handler := range map[string]gin.HandlerFunc{response["badge"]: m.streamBadge, response["calendar"]: m.streamDueDatesICS} {
+		recorder = httptest.NewRecorder()
+		ginContext, _ := gin.CreateTestContext(recorder)
+		ginContext.Request = httptest.NewRequest(http.MethodGet, url, nil)
+		ginContext.Params = gin.Params{{Key: "model-id", Value: m.modelID}}
+		handler(ginContext)
+		assert.Equal(t, http.StatusTooManyRequests, recorder.Code, "rendering counts against the analysis quota: "+url)
+	}
+}
+
+func TestBadgeSVG(t *testing.T) {
+	statistics := types.RiskStatistics{Risks: map[string]map[string]int{
+		types.CriticalSeverity.String(): {types.Unchecked.String(): 1, types.Mitigated.String(): 2},
+		types.HighSeverity.String():     {types.InProgress.String(): 3},
+	}}
+	assert.Equal(t, 1, countRisksStillAtRisk(statistics, badgeMetrics["critical-risks"]))
+	assert.Equal(t, 4, countRisksStillAtRisk(statistics, badgeMetrics["risks"]))
+	assert.Equal(t, badgeColors[types.CriticalSeverity], badgeColor(statistics, badgeMetrics["risks"]), "of the highest severity counted")
+	assert.Equal(t, badgeColorNoRisks, badgeColor(statistics, badgeMetrics["low-risks"]))
+
+	svg := string(badgeSVG("critical risks", "1", badgeColors[types.CriticalSeverity]))
+	assert.True(t, strings.HasPrefix(svg, `<svg xmlns="http://www.w3.org/2000/svg" width="125"`))
+	assert.Contains(t, svg, `<title>critical risks: 1</title>`)
+}
@@ -0,0 +1,105 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/threagile/threagile/pkg/input"
+)
+
+type payloadBulkOperation struct {
+	Operation        string          `yaml:"operation" json:"operation"`                   // create, update or delete
+	Element          string          `yaml:"element" json:"element"`                       // one of bulkElements
+	Id               string          `yaml:"id" json:"id"`                                 // of the element to update or delete
+	TechnicalAssetId string          `yaml:"technical_asset_id" json:"technical_asset_id"` // of the communication link
+	Payload          json.RawMessage `yaml:"payload" json:"payload"`                       // the one of the single request
+}
+
+type payloadBulk struct {
+	Operations []payloadBulkOperation `yaml:"operations" json:"operations"`
+}
+
+// bulkElement has the changes of the single requests of a model element (nil if not supported by the element)
+type bulkElement struct {
+	idParam string
+	create  modelChange
+	update  modelChange
+	remove  modelChange
+}
+
+var bulkElements = map[string]bulkElement{
+	"cover":                 {update: coverUpdate},
+	"overview":              {update: overviewUpdate},
+	"abuse-cases":           {update: abuseCasesUpdate},
+	"security-requirements": {update: securityRequirementsUpdate},
+	"questions":             {update: questionsUpdate},
+	"tags":                  {update: tagsUpdate},
+	"annotations":           {create: annotationCreation, update: annotationsUpdate},
+	"data-asset":            {idParam: "data-asset-id", create: dataAssetCreation, update: dataAssetUpdate, remove: dataAssetDeletion},
+	"technical-asset":       {idParam: "technical-asset-id", create: technicalAssetCreation, update: technicalAssetUpdate, remove: technicalAssetDeletion},
+	"trust-boundary":        {idParam: "trust-boundary-id", create: trustBoundaryCreation, update: trustBoundaryUpdate, remove: trustBoundaryDeletion},
+	"shared-runtime":        {idParam: "shared-runtime-id", create: sharedRuntimeCreation, update: sharedRuntimeUpdate, remove: sharedRuntimeDeletion},
+	"communication-link":    {idParam: "communication-link-id", create: communicationLinkCreation, update: communicationLinkUpdate, remove: communicationLinkDeletion},
+}
+
+// bulkChange applies the operations one after the other (so later ones see the changes of earlier ones) and writes the
+// model only if all of them succeeded, with one history entry
+func (s *server) bulkChange(ginContext *gin.Context) {
+	s.changeModel(ginContext, "Bulk Operations", bulkOperations)
+}
+
+func bulkOperations(modelInput *input.Model, _ gin.Params, bindPayload func(payload any) error) (gin.H, error) {
+	payload := payloadBulk{}
+	err := bindPayload(&payload)
+	if err != nil {
+		log.Println(err)
+		return nil, errUnparsablePayload
+	}
+	results := make([]gin.H, 0, len(payload.Operations))
+	for i, operation := range payload.Operations {
+		result, err := operation.apply(modelInput)
+		if err != nil {
+			var requestErr requestError
+			if errors.As(err, &requestErr) {
+				return nil, requestError{status: requestErr.status, message: fmt.Sprintf("operation %d: %s", i, requestErr.message)}
+			}
+			return nil, fmt.Errorf("operation %d: %w", i, err)
+		}
+		results = append(results, result)
+	}
+	return gin.H{
+		"message": "model updated",
+		"results": results, // the responses of the single requests in the order of the operations
+	}, nil
+}
+
+func (what payloadBulkOperation) apply(modelInput *input.Model) (gin.H, error) {
+	element, ok := bulkElements[what.Element]
+	if !ok {
+		return nil, requestError{status: http.StatusBadRequest, message: fmt.Sprintf("unknown element %q", what.Element)}
+	}
+	var change modelChange
+	switch what.Operation {
+	case "create":
+		change = element.create
+	case "update":
+		change = element.update
+	case "delete":
+		change = element.remove
+	}
+	if change == nil {
+		return nil, requestError{status: http.StatusBadRequest, message: fmt.Sprintf("unsupported operation %q of element %q", what.Operation, what.Element)}
+	}
+	params := gin.Params{}
+	if len(element.idParam) > 0 { // first, as the id of a technical asset is the parameter of its communication links too
+		params = append(params, gin.Param{Key: element.idParam, Value: what.Id})
+	}
+	params = append(params, gin.Param{Key: "technical-asset-id", Value: what.TechnicalAssetId})
+	return change(modelInput, params, func(payload any) error {
+		return json.Unmarshal(what.Payload, payload)
+	})
+}
@@ -0,0 +1,59 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"path/filepath"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/threagile/threagile/pkg/input"
+)
+
+func bulkOperation(operation string, element string, id string, technicalAssetId string, payload interface{}) payloadBulkOperation {
+	data, _ := json.Marshal(payload)
+	return payloadBulkOperation{Operation: operation, Element: element, Id: id, TechnicalAssetId: technicalAssetId, Payload: data}
+}
+
+func TestBulkOperations(t *testing.T) {
+	m := newModelTestServer(t, communicationLinkTestModel)
+	dataAsset := payloadDataAsset{Title: "Payment Data", DataAsset: input.DataAsset{ID: "payment-data", Usage: "business", Quantity: "many",
+		Confidentiality: "strictly-confidential", Integrity: "critical", Availability: "important"}}
+	link := payloadCommunicationLink{Title: "Payments", CommunicationLink: input.CommunicationLink{Target: "db", Protocol: "jdbc-encrypted", Authentication: "credentials",
+		Authorization: "technical-user", Usage: "business", DataAssetsSent: []string{"payment-data"}}}
+
+	recorder := m.call(m.bulkChange, http.MethodPost, nil, payloadBulk{Operations: []payloadBulkOperation{
+		bulkOperation("create", "data-asset", "", "", dataAsset), // used by the link below
+		bulkOperation("create", "communication-link", "", "web-server", link),
+		bulkOperation("update", "cover", "", "", payloadCover{Title: "Bulk Test"}),
+	}})
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	var response struct {
+		Results []map[string]interface{} `json:"results"`
+	}
+	assert.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+	assert.Len(t, response.Results, 3)
+	assert.Equal(t, "web-server>payments", response.Results[1]["id"])
+	history, err := m.storage.ReadDir(filepath.Join(m.modelFolder, "history"))
+	assert.NoError(t, err)
+	assert.Len(t, history, 1)
+
+	recorder = m.call(m.bulkChange, http.MethodPost, nil, payloadBulk{Operations: []payloadBulkOperation{
+		bulkOperation("delete", "communication-link", "web-server>payments", "web-server", nil),
+		bulkOperation("create", "data-asset", "", "", dataAsset), // already exists
+	}})
+	assert.Equal(t, http.StatusConflict, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "operation 1: ")
+
+	recorder = m.call(m.getCommunicationLinks, http.MethodGet, gin.Params{{Key: "technical-asset-id", Value: "web-server"}}, nil)
+	assert.Contains(t, recorder.Body.String(), `"Payments"`, "nothing of a failed bulk is applied")
+	history, err = m.storage.ReadDir(filepath.Join(m.modelFolder, "history"))
+	assert.NoError(t, err)
+	assert.Len(t, history, 1)
+
+	recorder = m.call(m.bulkChange, http.MethodPost, nil, payloadBulk{Operations: []payloadBulkOperation{
+		bulkOperation("delete", "cover", "", "", nil),
+	}})
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+}
@@ -0,0 +1,73 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"path/filepath"
+
+	"github.com/gin-gonic/gin"
+	"github.com/threagile/threagile/pkg/common"
+	"github.com/threagile/threagile/pkg/report"
+)
+
+// streamDueDatesICS answers the public calendar of a model (accessed via its share token) with the due dates of the
+// risks not handled yet and the next review date, so teams can subscribe their calendars to it; as anyone holding the
+// link can read it, it has only the dates and the risk ids
+func (s *server) streamDueDatesICS(ginContext *gin.Context) {
+	folderNameOfKey, key, ok := s.checkShareTokenToFolderName(ginContext)
+	if !ok {
+		return
+	}
+	s.lockFolder(folderNameOfKey)
+	defer s.unlockFolder(folderNameOfKey)
+
+	_, yamlText, ok := s.readModel(ginContext, ginContext.Param("model-id"), key, folderNameOfKey)
+	if !ok {
+		return
+	}
+	modelFolder := folderNameForModel(folderNameOfKey, ginContext.Param("model-id"))
+	if s.notModified(ginContext, modelFolder, dueDatesICS, yamlText, s.config.GraphvizDPI) {
+		return
+	}
+	if !s.checkAnalysisQuota(ginContext, folderNameOfKey) {
+		return
+	}
+	data, err := s.dueDatesICS(ginContext.Request.Context(), ginContext.Param("model-id"), yamlText)
+	if err != nil {
+		handleErrorInServiceCall(err, ginContext)
+		return
+	}
+	ginContext.Data(http.StatusOK, mimeCalendar, data)
+}
+
+// dueDatesICS analyzes the model in-process, as the calendar is not part of the stored analysis result
+func (s *server) dueDatesICS(ctx context.Context, modelId string, yamlText string) ([]byte, error) {
+	workspace, err := newTempWorkspace(s.config.TempFolder, "calendar")
+	if err != nil {
+		return nil, err
+	}
+	defer workspace.Close()
+	tmpOutputDir, err := workspace.Mkdir("output")
+	if err != nil {
+		return nil, err
+	}
+	tmpModelFile, err := workspace.CreateFile("threagile-calendar-*")
+	if err != nil {
+		return nil, err
+	}
+	err = os.WriteFile(tmpModelFile.Name(), []byte(yamlText), 0400)
+	if err != nil {
+		return nil, err
+	}
+	readResult, err := s.analyzeInProcess(ctx, workspace, tmpModelFile.Name(), tmpOutputDir)
+	if err != nil {
+		return nil, err
+	}
+	icsFile := filepath.Join(tmpOutputDir, s.config.IcsDueDatesFilename)
+	err = report.WriteDueDatesICS(common.OSFileSystem{}, readResult.ParsedModel, modelId, true, icsFile)
+	if err != nil {
+		return nil, err
+	}
+	return os.ReadFile(filepath.Clean(icsFile))
+}
@@ -0,0 +1,270 @@
+package server
+
+import (
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/threagile/threagile/pkg/input"
+	"github.com/threagile/threagile/pkg/model"
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+// payloadCommunicationLink is the communication link of the model file with its title, as the model file keys the
+// communication links of a technical asset by title
+type payloadCommunicationLink struct {
+	Title                   string `yaml:"title" json:"title" binding:"required"`
+	input.CommunicationLink `yaml:",inline"`
+}
+
+// communicationLinkId is the id the model parser gives the communication link (keyed by its title in the YAML)
+func communicationLinkId(sourceAssetId string, title string) string {
+	id, _ := model.CreateDataFlowId(sourceAssetId, title) // fails for an invalid expression only, which is a constant one
+	return id
+}
+
+// findTechnicalAsset answers the title (its key in the YAML) of the technical asset of the request
+func findTechnicalAsset(modelInput input.Model, params gin.Params) (title string, technicalAsset input.TechnicalAsset, err error) {
+	for title, technicalAsset := range modelInput.TechnicalAssets {
+		if technicalAsset.ID == params.ByName("technical-asset-id") {
+			return title, technicalAsset, nil
+		}
+	}
+	return "", technicalAsset, requestError{status: http.StatusNotFound, message: "technical asset not found"}
+}
+
+func (s *server) getCommunicationLinks(ginContext *gin.Context) {
+	folderNameOfKey, key, ok := s.checkTokenToFolderName(ginContext)
+	if !ok {
+		return
+	}
+	s.lockFolder(folderNameOfKey)
+	defer s.unlockFolder(folderNameOfKey)
+	modelInput, _, ok := s.readModel(ginContext, ginContext.Param("model-id"), key, folderNameOfKey)
+	if ok {
+		_, technicalAsset, err := findTechnicalAsset(modelInput, ginContext.Params)
+		if err != nil {
+			handleChangeError(err, ginContext)
+			return
+		}
+		ginContext.JSON(http.StatusOK, technicalAsset.CommunicationLinks)
+	}
+}
+
+func (s *server) getCommunicationLink(ginContext *gin.Context) {
+	folderNameOfKey, key, ok := s.checkTokenToFolderName(ginContext)
+	if !ok {
+		return
+	}
+	s.lockFolder(folderNameOfKey)
+	defer s.unlockFolder(folderNameOfKey)
+	modelInput, _, ok := s.readModel(ginContext, ginContext.Param("model-id"), key, folderNameOfKey)
+	if ok {
+		_, technicalAsset, err := findTechnicalAsset(modelInput, ginContext.Params)
+		if err != nil {
+			handleChangeError(err, ginContext)
+			return
+		}
+		// yes, here keyed by title in YAML for better readability in the YAML file itself
+		for title, communicationLink := range technicalAsset.CommunicationLinks {
+			if communicationLinkId(technicalAsset.ID, title) == ginContext.Param("communication-link-id") {
+				ginContext.JSON(http.StatusOK, gin.H{
+					title: communicationLink,
+				})
+				return
+			}
+		}
+		respondError(ginContext, http.StatusNotFound, errorCodeNotFound, "communication link not found")
+	}
+}
+
+func (s *server) createNewCommunicationLink(ginContext *gin.Context) {
+	s.changeModel(ginContext, "Communication Link Creation", communicationLinkCreation)
+}
+
+func communicationLinkCreation(modelInput *input.Model, params gin.Params, bindPayload func(payload any) error) (gin.H, error) {
+	technicalAssetTitle, technicalAsset, err := findTechnicalAsset(*modelInput, params)
+	if err != nil {
+		return nil, err
+	}
+	payload := payloadCommunicationLink{}
+	err = bindPayload(&payload)
+	if err != nil {
+		log.Println(err)
+		return nil, errUnparsablePayload
+	}
+	// yes, here keyed by title in YAML for better readability in the YAML file itself
+	if _, exists := technicalAsset.CommunicationLinks[payload.Title]; exists {
+		return nil, requestError{status: http.StatusConflict, message: "communication link with this title already exists"}
+	}
+	// but later it will in memory keyed by its "id" derived from the title, so do this uniqueness check also
+	id := communicationLinkId(technicalAsset.ID, payload.Title)
+	for title := range technicalAsset.CommunicationLinks {
+		if communicationLinkId(technicalAsset.ID, title) == id {
+			return nil, requestError{status: http.StatusConflict, message: "communication link with this id already exists"}
+		}
+	}
+	communicationLinkInput, err := populateCommunicationLink(*modelInput, payload)
+	if err != nil {
+		return nil, err
+	}
+	if technicalAsset.CommunicationLinks == nil {
+		technicalAsset.CommunicationLinks = make(map[string]input.CommunicationLink)
+	}
+	technicalAsset.CommunicationLinks[payload.Title] = communicationLinkInput
+	modelInput.TechnicalAssets[technicalAssetTitle] = technicalAsset
+	return gin.H{
+		"message": "communication link created",
+		"id":      id,
+	}, nil
+}
+
+func (s *server) setCommunicationLink(ginContext *gin.Context) {
+	s.changeModel(ginContext, "Communication Link Update", communicationLinkUpdate)
+}
+
+func communicationLinkUpdate(modelInput *input.Model, params gin.Params, bindPayload func(payload any) error) (gin.H, error) {
+	_, technicalAsset, err := findTechnicalAsset(*modelInput, params)
+	if err != nil {
+		return nil, err
+	}
+	// yes, here keyed by title in YAML for better readability in the YAML file itself
+	for title := range technicalAsset.CommunicationLinks {
+		oldId := communicationLinkId(technicalAsset.ID, title)
+		if oldId != params.ByName("communication-link-id") {
+			continue
+		}
+		payload := payloadCommunicationLink{}
+		err = bindPayload(&payload)
+		if err != nil {
+			log.Println(err)
+			return nil, errUnparsablePayload
+		}
+		newId := communicationLinkId(technicalAsset.ID, payload.Title)
+		for otherTitle := range technicalAsset.CommunicationLinks {
+			if otherTitle != title && communicationLinkId(technicalAsset.ID, otherTitle) == newId {
+				return nil, requestError{status: http.StatusConflict, message: "communication link with this id already exists"}
+			}
+		}
+		communicationLinkInput, err := populateCommunicationLink(*modelInput, payload)
+		if err != nil {
+			return nil, err
+		}
+		// in order to also update the title, remove the link from the map and re-insert it (with new key)
+		delete(technicalAsset.CommunicationLinks, title)
+		technicalAsset.CommunicationLinks[payload.Title] = communicationLinkInput
+		idChanged := newId != oldId
+		if idChanged { // ID-CHANGE-PROPAGATION
+			for _, individualRiskCat := range modelInput.CustomRiskCategories {
+				if individualRiskCat.RisksIdentified != nil {
+					for individualRiskInstanceTitle, individualRiskInstance := range individualRiskCat.RisksIdentified {
+						if individualRiskInstance.MostRelevantCommunicationLink == oldId { // apply the ID change
+							x := individualRiskCat.RisksIdentified[individualRiskInstanceTitle]
+							x.MostRelevantCommunicationLink = newId
+							individualRiskCat.RisksIdentified[individualRiskInstanceTitle] = x
+						}
+					}
+				}
+			}
+			replaceElementIdInRiskTrackingAndAnnotations(modelInput, oldId, newId, false)
+		}
+		return gin.H{
+			"message":    "communication link updated",
+			"id":         newId,
+			"id_changed": idChanged, // in order to signal to clients, that other model parts might've received updates as well and should be reloaded
+		}, nil
+	}
+	return nil, requestError{status: http.StatusNotFound, message: "communication link not found"}
+}
+
+func (s *server) deleteCommunicationLink(ginContext *gin.Context) {
+	s.changeModel(ginContext, "Communication Link Deletion", communicationLinkDeletion)
+}
+
+func communicationLinkDeletion(modelInput *input.Model, params gin.Params, _ func(payload any) error) (gin.H, error) {
+	_, technicalAsset, err := findTechnicalAsset(*modelInput, params)
+	if err != nil {
+		return nil, err
+	}
+	referencesDeleted := false
+	// yes, here keyed by title in YAML for better readability in the YAML file itself
+	for title := range technicalAsset.CommunicationLinks {
+		id := communicationLinkId(technicalAsset.ID, title)
+		if id != params.ByName("communication-link-id") {
+			continue
+		}
+		// also remove all usages of this communication link !!
+		for _, individualRiskCat := range modelInput.CustomRiskCategories {
+			if individualRiskCat.RisksIdentified != nil {
+				for individualRiskInstanceTitle, individualRiskInstance := range individualRiskCat.RisksIdentified {
+					if individualRiskInstance.MostRelevantCommunicationLink == id { // apply the removal
+						referencesDeleted = true
+						x := individualRiskCat.RisksIdentified[individualRiskInstanceTitle]
+						x.MostRelevantCommunicationLink = ""
+						individualRiskCat.RisksIdentified[individualRiskInstanceTitle] = x
+					}
+				}
+			}
+		}
+		// remove it itself
+		delete(technicalAsset.CommunicationLinks, title)
+		return gin.H{
+			"message":            "communication link deleted",
+			"id":                 id,
+			"references_deleted": referencesDeleted, // in order to signal to clients, that other model parts might've been deleted as well
+		}, nil
+	}
+	return nil, requestError{status: http.StatusNotFound, message: "communication link not found"}
+}
+
+func populateCommunicationLink(modelInput input.Model, payload payloadCommunicationLink) (communicationLinkInput input.CommunicationLink, err error) {
+	if len(strings.TrimSpace(payload.Title)) == 0 {
+		return communicationLinkInput, requestError{status: http.StatusBadRequest, message: "communication link title must not be empty"}
+	}
+	if !checkTechnicalAssetsExisting(modelInput, []string{payload.Target}) {
+		return communicationLinkInput, requestError{status: http.StatusBadRequest, message: "referenced technical asset does not exist"}
+	}
+	if !checkDataAssetsExisting(modelInput, payload.DataAssetsSent) || !checkDataAssetsExisting(modelInput, payload.DataAssetsReceived) {
+		return communicationLinkInput, requestError{status: http.StatusBadRequest, message: "referenced data asset does not exist"}
+	}
+	protocol, err := types.ParseProtocol(payload.Protocol)
+	if err != nil {
+		return communicationLinkInput, err
+	}
+	authentication, err := types.ParseAuthentication(payload.Authentication)
+	if err != nil {
+		return communicationLinkInput, err
+	}
+	authorization, err := types.ParseAuthorization(payload.Authorization)
+	if err != nil {
+		return communicationLinkInput, err
+	}
+	usage, err := types.ParseUsage(payload.Usage)
+	if err != nil {
+		return communicationLinkInput, err
+	}
+	communicationLinkInput = payload.CommunicationLink
+	communicationLinkInput.Protocol = protocol.String()
+	communicationLinkInput.Authentication = authentication.String()
+	communicationLinkInput.Authorization = authorization.String()
+	communicationLinkInput.Tags = lowerCaseAndTrim(payload.Tags)
+	communicationLinkInput.Usage = usage.String()
+	return communicationLinkInput, nil
+}
+
+func checkDataAssetsExisting(modelInput input.Model, dataAssetIDs []string) (ok bool) {
+	for _, dataAssetID := range dataAssetIDs {
+		exists := false
+		for _, val := range modelInput.DataAssets {
+			if val.ID == dataAssetID {
+				exists = true
+				break
+			}
+		}
+		if !exists {
+			return false
+		}
+	}
+	return true
+}
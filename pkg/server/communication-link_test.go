@@ -0,0 +1,74 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/threagile/threagile/pkg/input"
+)
+
+const communicationLinkTestModel = `threagile_version: 1.0.0
+title: Communication Link Test
+data_assets:
+  Customer Data:
+    id: customer-data
+technical_assets:
+  Web Server:
+    id: web-server
+  Database:
+    id: db
+`
+
+func TestCommunicationLinkCRUD(t *testing.T) {
+	m := newModelTestServer(t, communicationLinkTestModel)
+	assetParams := gin.Params{{Key: "technical-asset-id", Value: "web-server"}}
+	payload := payloadCommunicationLink{Title: "Queries", CommunicationLink: input.CommunicationLink{Target: "db", Protocol: "jdbc-encrypted", Authentication: "credentials",
+		Authorization: "technical-user", Usage: "business", DataAssetsSent: []string{"customer-data"}}}
+
+	recorder := m.call(m.createNewCommunicationLink, http.MethodPost, assetParams, payload)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	var created map[string]interface{}
+	assert.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &created))
+	assert.Equal(t, "web-server>queries", created["id"])
+
+	recorder = m.call(m.createNewCommunicationLink, http.MethodPost, assetParams, payload)
+	assert.Equal(t, http.StatusConflict, recorder.Code)
+
+	unknownDataAsset := payload
+	unknownDataAsset.Title = "Other Queries"
+	unknownDataAsset.DataAssetsReceived = []string{"unknown"}
+	recorder = m.call(m.createNewCommunicationLink, http.MethodPost, assetParams, unknownDataAsset)
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+
+	recorder = m.call(m.createNewCommunicationLink, http.MethodPost, gin.Params{{Key: "technical-asset-id", Value: "unknown"}}, payload)
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+
+	linkParams := append(assetParams, gin.Param{Key: "communication-link-id", Value: "web-server>queries"})
+	recorder = m.call(m.getCommunicationLink, http.MethodGet, linkParams, nil)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), `"Queries"`)
+
+	renamed := payload
+	renamed.Title = "SQL Queries"
+	recorder = m.call(m.setCommunicationLink, http.MethodPut, linkParams, renamed)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	var updated map[string]interface{}
+	assert.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &updated))
+	assert.Equal(t, "web-server>sql-queries", updated["id"])
+	assert.Equal(t, true, updated["id_changed"])
+
+	recorder = m.call(m.deleteCommunicationLink, http.MethodDelete, linkParams, nil)
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+	linkParams[1].Value = "web-server>sql-queries"
+	recorder = m.call(m.deleteCommunicationLink, http.MethodDelete, linkParams, nil)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+
+	recorder = m.call(m.getCommunicationLinks, http.MethodGet, assetParams, nil)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	var remaining map[string]interface{}
+	assert.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &remaining))
+	assert.Empty(t, remaining)
+}
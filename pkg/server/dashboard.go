@@ -0,0 +1,340 @@
+package server
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/threagile/threagile/pkg/input"
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+// postureFilename is stored alongside the model on each analysis and change of it when the admin endpoints are enabled,
+// encrypted with a key derived from the admin key (not with the key of the model), so the dashboard can summarize all
+// models without their keys: it holds only counts and dates
+const postureFilename = "posture.json"
+
+const (
+	dashboardDefaultLimit = 10
+	// dashboardMinModelsAnonymized is the number of models a risk category has to occur in to be shown anonymized, so
+	// that a category can not be traced back to a single model
+	dashboardMinModelsAnonymized = 3
+)
+
+type modelPosture struct {
+	Date           string                    `json:"date,omitempty"` // of the model, i.e. of its last review
+	NextReviewDate string                    `json:"next_review_date,omitempty"`
+	AnalyzedAt     time.Time                 `json:"analyzed_at"`
+	Risks          map[string]map[string]int `json:"risks"`      // by severity and status, like the risk statistics
+	Overdue        map[string]int            `json:"overdue"`    // by severity
+	Categories     map[string]int            `json:"categories"` // risks still at risk by category id
+}
+
+type payloadDashboard struct {
+	ModelCount             int                       `json:"model_count"` // of the models analyzed at least once
+	Risks                  map[string]map[string]int `json:"risks"`
+	Overdue                map[string]int            `json:"overdue"`
+	TopRiskCategories      []dashboardRiskCategory   `json:"top_risk_categories"`
+	OldestUnreviewedModels []dashboardModel          `json:"oldest_unreviewed_models"`
+}
+
+type dashboardRiskCategory struct {
+	Category   string `json:"category"`
+	Risks      int    `json:"risks"` // still at risk
+	ModelCount int    `json:"model_count"`
+}
+
+type dashboardModel struct {
+	Model          string    `json:"model"` // the model id, or a pseudonym of it when anonymized
+	Date           string    `json:"date,omitempty"`
+	NextReviewDate string    `json:"next_review_date,omitempty"`
+	ReviewOverdue  bool      `json:"review_overdue"`
+	AnalyzedAt     time.Time `json:"analyzed_at"`
+	RisksAtRisk    int       `json:"risks_at_risk"`
+}
+
+// checkAdminKey checks the header admin-key against the configured admin key, the admin endpoints do not exist
+// without one
+func (s *server) checkAdminKey(ginContext *gin.Context) bool {
+	if len(s.config.ServerAdminKey) == 0 {
+		respondError(ginContext, http.StatusNotFound, errorCodeDisabled, "admin endpoints are disabled")
+		return false
+	}
+	if subtle.ConstantTimeCompare([]byte(ginContext.GetHeader("admin-key")), []byte(s.config.ServerAdminKey)) != 1 {
+		respondError(ginContext, http.StatusUnauthorized, errorCodeUnauthorized, "admin key is invalid")
+		return false
+	}
+	return true
+}
+
+// adminDerivedKey answers the key derived from the admin key for the purpose, so that changing the admin key makes the
+// data of the admin endpoints unreadable (until refreshed) instead of readable with the former key
+func (s *server) adminDerivedKey(purpose string) []byte {
+	mac := hmac.New(sha256.New, []byte(s.config.ServerAdminKey))
+	mac.Write([]byte(purpose))
+	return mac.Sum(nil)
+}
+
+// recordPosture stores the posture of the analyzed model from the stats and risks json files of the analysis output
+func (s *server) recordPosture(modelFolder string, modelInput input.Model, outputDir string) error {
+	if len(s.config.ServerAdminKey) == 0 {
+		return nil
+	}
+	statistics, risks, err := s.readAnalysisOutput(outputDir)
+	if err != nil {
+		return err
+	}
+	return s.writePosture(modelFolder, modelInput, statistics, risks)
+}
+
+func (s *server) writePosture(modelFolder string, modelInput input.Model, statistics types.RiskStatistics, risks []*types.Risk) error {
+	posture := modelPosture{
+		Date:           modelInput.Date,
+		NextReviewDate: modelInput.NextReviewDate,
+		AnalyzedAt:     time.Now().UTC(),
+		Risks:          statistics.Risks,
+		Overdue:        statistics.Overdue,
+		Categories:     make(map[string]int),
+	}
+	for _, risk := range risks {
+		if risk.RiskStatus.IsStillAtRisk() {
+			posture.Categories[risk.CategoryId]++
+		}
+	}
+	data, err := json.Marshal(posture)
+	if err != nil {
+		return err
+	}
+	ciphertext, err := encryptWithKey(s.adminDerivedKey("posture"), data)
+	if err != nil {
+		return err
+	}
+	return s.storage.WriteFile(filepath.Join(modelFolder, postureFilename), ciphertext)
+}
+
+// refreshPosture analyzes the changed model in the background to refresh its posture, so that the dashboard does not
+// wait for the next analysis requested: changes made while one is running are analyzed once it finished (the latest
+// of them only)
+func (s *server) refreshPosture(modelFolder string, yamlText string) {
+	if len(s.config.ServerAdminKey) == 0 {
+		return
+	}
+	s.postureRefreshLock.Lock()
+	defer s.postureRefreshLock.Unlock()
+	if s.postureRefreshes == nil {
+		s.postureRefreshes = make(map[string]*string)
+	}
+	_, running := s.postureRefreshes[modelFolder]
+	s.postureRefreshes[modelFolder] = &yamlText
+	if !running {
+		go s.runPostureRefresh(modelFolder)
+	}
+}
+
+func (s *server) runPostureRefresh(modelFolder string) {
+	for {
+		s.postureRefreshLock.Lock()
+		yamlText := s.postureRefreshes[modelFolder]
+		if yamlText == nil {
+			delete(s.postureRefreshes, modelFolder)
+			s.postureRefreshLock.Unlock()
+			return
+		}
+		s.postureRefreshes[modelFolder] = nil // running, nothing pending
+		s.postureRefreshLock.Unlock()
+
+		err := s.analyzePosture(modelFolder, *yamlText)
+		if err != nil {
+			log.Println("unable to refresh posture:", err) // the dashboard shows the previous posture of the model then
+		}
+	}
+}
+
+func (s *server) analyzePosture(modelFolder string, yamlText string) error {
+	workspace, err := newTempWorkspace(s.config.TempFolder, "posture")
+	if err != nil {
+		return err
+	}
+	defer workspace.Close()
+	tmpOutputDir, err := workspace.Mkdir("output")
+	if err != nil {
+		return err
+	}
+	tmpModelFile, err := workspace.CreateFile("threagile-posture-*")
+	if err != nil {
+		return err
+	}
+	err = os.WriteFile(tmpModelFile.Name(), []byte(yamlText), 0400)
+	if err != nil {
+		return err
+	}
+	readResult, err := s.analyzeInProcess(context.Background(), workspace, tmpModelFile.Name(), tmpOutputDir)
+	if err != nil {
+		return err
+	}
+	if _, err = s.storage.Stat(filepath.Join(modelFolder, s.config.InputFile)); err != nil {
+		return nil // deleted meanwhile
+	}
+	return s.writePosture(modelFolder, *readResult.ModelInput, readResult.Result.Statistics, readResult.Result.Risks)
+}
+
+// readAnalysisOutput reads the stats and risks json files of the analysis output
+func (s *server) readAnalysisOutput(outputDir string) (types.RiskStatistics, []*types.Risk, error) {
+	var statistics types.RiskStatistics
+	data, err := os.ReadFile(filepath.Clean(filepath.Join(outputDir, s.config.JsonStatsFilename)))
+	if err != nil {
+		return statistics, nil, err
+	}
+	err = json.Unmarshal(data, &statistics)
+	if err != nil {
+		return statistics, nil, err
+	}
+	var risks []*types.Risk
+	data, err = os.ReadFile(filepath.Clean(filepath.Join(outputDir, s.config.JsonRisksFilename)))
+	if err != nil {
+		return statistics, nil, err
+	}
+	err = json.Unmarshal(data, &risks)
+	return statistics, risks, err
+}
+
+// dashboard summarizes the risk posture of the models of all keys, as recorded on their last analysis
+func (s *server) dashboard(ginContext *gin.Context) {
+	if !s.checkAdminKey(ginContext) {
+		return
+	}
+	anonymize, err := strconv.ParseBool(ginContext.DefaultQuery("anonymize", "false"))
+	if err != nil {
+		respondError(ginContext, http.StatusBadRequest, errorCodeBadRequest, "anonymize must be true or false")
+		return
+	}
+	limit, err := strconv.Atoi(ginContext.DefaultQuery("limit", strconv.Itoa(dashboardDefaultLimit)))
+	if err != nil || limit < 1 {
+		respondError(ginContext, http.StatusBadRequest, errorCodeBadRequest, "limit must be a positive number")
+		return
+	}
+
+	postureFiles, err := s.globKeyFolders("*", postureFilename)
+	if err != nil {
+		log.Println(err)
+		respondError(ginContext, http.StatusInternalServerError, errorCodeInternal, "unable to collect dashboard")
+		return
+	}
+	ginContext.JSON(http.StatusOK, buildDashboard(s.readPostures(postureFiles), anonymize, s.adminDerivedKey("pseudonym"), limit, time.Now()))
+}
+
+// readPostures reads the posture files by model id, skipping unreadable ones (e.g. of models just being deleted, or
+// stored with a former admin key, which are refreshed with the next analysis or change of the model)
+func (s *server) readPostures(postureFiles []string) map[string]modelPosture {
+	postures := make(map[string]modelPosture)
+	postureKey := s.adminDerivedKey("posture")
+	for _, postureFile := range postureFiles {
+		ciphertext, err := s.storage.ReadFile(postureFile)
+		if err != nil {
+			continue
+		}
+		data, err := decryptWithKey(postureKey, ciphertext)
+		if err != nil {
+			continue
+		}
+		var posture modelPosture
+		if json.Unmarshal(data, &posture) != nil {
+			continue
+		}
+		postures[filepath.Base(filepath.Dir(postureFile))] = posture
+	}
+	return postures
+}
+
+// buildDashboard summarizes the postures, anonymized with pseudonyms of the model ids keyed by the pseudonym key, so
+// that they can not be traced back by hashing the model ids
+func buildDashboard(postures map[string]modelPosture, anonymize bool, pseudonymKey []byte, limit int, now time.Time) payloadDashboard {
+	dashboard := payloadDashboard{
+		ModelCount:             len(postures),
+		Risks:                  make(map[string]map[string]int),
+		Overdue:                make(map[string]int),
+		TopRiskCategories:      make([]dashboardRiskCategory, 0),
+		OldestUnreviewedModels: make([]dashboardModel, 0),
+	}
+	categories := make(map[string]*dashboardRiskCategory)
+	for modelID, posture := range postures {
+		model := dashboardModel{
+			Model:          modelID,
+			Date:           posture.Date,
+			NextReviewDate: posture.NextReviewDate,
+			AnalyzedAt:     posture.AnalyzedAt,
+		}
+		if anonymize {
+			mac := hmac.New(sha256.New, pseudonymKey)
+			mac.Write([]byte(modelID))
+			model.Model = "model-" + hex.EncodeToString(mac.Sum(nil))[:12]
+		}
+		if nextReview, err := time.Parse("2006-01-02", posture.NextReviewDate); err == nil {
+			model.ReviewOverdue = nextReview.AddDate(0, 0, 1).Before(now)
+		}
+		for severity, byStatus := range posture.Risks {
+			if dashboard.Risks[severity] == nil {
+				dashboard.Risks[severity] = make(map[string]int)
+			}
+			for status, count := range byStatus {
+				dashboard.Risks[severity][status] += count
+				if riskStatus, err := types.ParseRiskStatus(status); err == nil && riskStatus.IsStillAtRisk() {
+					model.RisksAtRisk += count
+				}
+			}
+		}
+		for severity, count := range posture.Overdue {
+			dashboard.Overdue[severity] += count
+		}
+		for categoryID, count := range posture.Categories {
+			if categories[categoryID] == nil {
+				categories[categoryID] = &dashboardRiskCategory{Category: categoryID}
+			}
+			categories[categoryID].Risks += count
+			categories[categoryID].ModelCount++
+		}
+		dashboard.OldestUnreviewedModels = append(dashboard.OldestUnreviewedModels, model)
+	}
+
+	for _, category := range categories {
+		if anonymize && category.ModelCount < dashboardMinModelsAnonymized {
+			continue
+		}
+		dashboard.TopRiskCategories = append(dashboard.TopRiskCategories, *category)
+	}
+	sort.Slice(dashboard.TopRiskCategories, func(i, j int) bool {
+		a, b := dashboard.TopRiskCategories[i], dashboard.TopRiskCategories[j]
+		if a.Risks != b.Risks {
+			return a.Risks > b.Risks
+		}
+		if a.ModelCount != b.ModelCount { // the more widespread first
+			return a.ModelCount > b.ModelCount
+		}
+		return a.Category < b.Category
+	})
+	// models without a date were never reviewed, hence come first (the ISO dates sort chronologically as text)
+	sort.Slice(dashboard.OldestUnreviewedModels, func(i, j int) bool {
+		if dashboard.OldestUnreviewedModels[i].Date == dashboard.OldestUnreviewedModels[j].Date {
+			return dashboard.OldestUnreviewedModels[i].Model < dashboard.OldestUnreviewedModels[j].Model
+		}
+		return dashboard.OldestUnreviewedModels[i].Date < dashboard.OldestUnreviewedModels[j].Date
+	})
+	if len(dashboard.TopRiskCategories) > limit {
+		dashboard.TopRiskCategories = dashboard.TopRiskCategories[:limit]
+	}
+	if len(dashboard.OldestUnreviewedModels) > limit {
+		dashboard.OldestUnreviewedModels = dashboard.OldestUnreviewedModels[:limit]
+	}
+	return dashboard
+}
@@ -0,0 +1,136 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/threagile/threagile/pkg/common"
+	"github.com/threagile/threagile/pkg/input"
+	"github.com/threagile/threagile/pkg/model"
+	"github.com/threagile/threagile/pkg/security/types"
+	"gopkg.in/yaml.v3"
+)
+
+func TestDashboard(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	s := &server{
+		config:  &common.Config{ServerFolder: "/server", KeyFolder: "keys", ServerAdminKey: "secret"},
+		storage: newMemoryStorage(),
+	}
+	write := func(keyFolder string, modelID string, posture modelPosture) {
+		data, err := json.Marshal(posture)
+		assert.NoError(t, err)
+		data, err = encryptWithKey(s.adminDerivedKey("posture"), data)
+		assert.NoError(t, err)
+		modelFolder := filepath.Join("/server", "keys", keyFolder, modelID)
+		assert.NoError(t, s.storage.MkdirAll(modelFolder))
+		assert.NoError(t, s.storage.WriteFile(filepath.Join(modelFolder, postureFilename), data))
+	}
+	write("key-a", "model-1", modelPosture{
+		Date:           "2024-01-10",
+		NextReviewDate: "2024-06-01",
+		Risks:          map[string]map[string]int{"critical": {"unchecked": 1, "mitigated": 2}},
+		Overdue:        map[string]int{"critical": 1},
+		Categories:     map[string]int{"sql-nosql-injection": 1},
+	})
+	write("key-b", "model-2", modelPosture{
+		Risks:      map[string]map[string]int{"critical": {"unchecked": 2}, "low": {"accepted": 3}},
+		Categories: map[string]int{"sql-nosql-injection": 2, "missing-waf": 3},
+	})
+
+	call := func(adminKey string, query string) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		ginContext, _ := gin.CreateTestContext(recorder)
+		ginContext.Request = httptest.NewRequest(http.MethodGet, "/meta/dashboard"+query, nil)
+		ginContext.Request.Header.Set("admin-key", adminKey)
+		s.dashboard(ginContext)
+		return recorder
+	}
+	assert.Equal(t, http.StatusUnauthorized, call("wrong", "").Code)
+
+	recorder := call("secret", "")
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	var dashboard payloadDashboard
+	assert.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &dashboard))
+	assert.Equal(t, 2, dashboard.ModelCount)
+	assert.Equal(t, map[string]map[string]int{"critical": {"unchecked": 3, "mitigated": 2}, "low": {"accepted": 3}}, dashboard.Risks)
+	assert.Equal(t, map[string]int{"critical": 1}, dashboard.Overdue)
+	assert.Equal(t, []dashboardRiskCategory{{Category: "sql-nosql-injection", Risks: 3, ModelCount: 2}, {Category: "missing-waf", Risks: 3, ModelCount: 1}}, dashboard.TopRiskCategories)
+	assert.Equal(t, "model-2", dashboard.OldestUnreviewedModels[0].Model, "never reviewed")
+	assert.Equal(t, 5, dashboard.OldestUnreviewedModels[0].RisksAtRisk)
+	assert.True(t, dashboard.OldestUnreviewedModels[1].ReviewOverdue)
+
+	recorder = call("secret", "?anonymize=true&limit=1")
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &dashboard))
+	assert.Empty(t, dashboard.TopRiskCategories, "no category is found in enough models")
+	assert.Len(t, dashboard.OldestUnreviewedModels, 1)
+	assert.True(t, strings.HasPrefix(dashboard.OldestUnreviewedModels[0].Model, "model-"))
+	assert.NotContains(t, recorder.Body.String(), `"model-1"`)
+	assert.NotContains(t, recorder.Body.String(), `"model-2"`)
+	assert.NotContains(t, recorder.Body.String(), hashSHA256([]byte("model-2"))[:12], "not traceable by hashing the model id")
+
+	s.config.ServerAdminKey = "changed"
+	assert.NoError(t, json.Unmarshal(call("changed", "").Body.Bytes(), &dashboard))
+	assert.Zero(t, dashboard.ModelCount, "stored with the former admin key")
+
+	s.config.ServerAdminKey = ""
+	assert.Equal(t, http.StatusNotFound, call("", "").Code)
+}
+
+func TestBuildDashboardReviewOverdue(t *testing.T) {
+	postures := map[string]modelPosture{"model": {NextReviewDate: "2024-03-01"}}
+	assert.False(t, buildDashboard(postures, false, nil, 10, time.Date(2024, 3, 1, 23, 0, 0, 0, time.UTC)).OldestUnreviewedModels[0].ReviewOverdue)
+	assert.True(t, buildDashboard(postures, false, nil, 10, time.Date(2024, 3, 2, 1, 0, 0, 0, time.UTC)).OldestUnreviewedModels[0].ReviewOverdue)
+}
+
+func TestPostureRefreshedOnModelChanges(t *testing.T) {
+	defer func(original func(context.Context, *common.Config, types.ProgressReporter) (*model.ReadResult, error)) {
+		readAndAnalyzeModel = original
+	}(readAndAnalyzeModel)
+	analyzed := make(chan *input.Model, 10)
+	readAndAnalyzeModel = func(ctx context.Context, config *common.Config, progressReporter types.ProgressReporter) (*model.ReadResult, error) {
+		data, err := os.ReadFile(filepath.Clean(config.InputFile))
+		if err != nil {
+			return nil, err
+		}
+		modelInput := new(input.Model)
+		err = yaml.Unmarshal(data, modelInput)
+		if err != nil {
+			return nil, err
+		}
+		analyzed <- modelInput
+		return &model.ReadResult{ModelInput: modelInput, Result: &model.Result{
+			Statistics: types.RiskStatistics{Risks: map[string]map[string]int{"high": {"unchecked": 1}}},
+			Risks:      []*types.Risk{{CategoryId: "missing-waf", RiskStatus: types.Unchecked}},
+		}}, nil
+	}
+
+	m := newModelTestServer(t, "title: Shop\nbusiness_criticality: important\n")
+	m.config.ServerAdminKey = "secret"
+	m.config.TempFolder = t.TempDir()
+	recorder := m.call(m.setOverview, http.MethodPut, nil, payloadOverview{ManagementSummaryComment: "reviewed", BusinessCriticality: "critical"})
+	assert.Equal(t, http.StatusOK, recorder.Code, recorder.Body.String())
+	select {
+	case modelInput := <-analyzed:
+		assert.Equal(t, "critical", modelInput.BusinessCriticality, "the changed model")
+	case <-time.After(10 * time.Second):
+		t.Fatal("posture not refreshed")
+	}
+	assert.Eventually(t, func() bool {
+		return len(m.readPostures([]string{filepath.Join(m.modelFolder, postureFilename)})) == 1
+	}, 10*time.Second, 10*time.Millisecond)
+	posture := m.readPostures([]string{filepath.Join(m.modelFolder, postureFilename)})[filepath.Base(m.modelFolder)]
+	assert.Equal(t, map[string]int{"missing-waf": 1}, posture.Categories)
+	stored, _ := m.storage.ReadFile(filepath.Join(m.modelFolder, postureFilename))
+	assert.NotContains(t, string(stored), "missing-waf", "encrypted")
+}
@@ -0,0 +1,189 @@
+package server
+
+import (
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// An edit session stages the changes of several requests (sending the session id as header) in a copy of the model,
+// which is only validated and written on commit, so that the stored model never passes through invalid intermediate
+// states. The copy is kept encrypted like the model itself in a sub-folder of the model folder.
+const (
+	editSessionHeader       = "edit-session"
+	editSessionsFolder      = "edit-sessions"
+	editSessionBaseFilename = "base" // hash of the model the session began with, to detect changes made outside the session
+	editSessionTimeout      = 2 * time.Hour
+)
+
+// modelFolderOfRequest answers the folder the model of the request is read from and written to, which is the one of the
+// edit session (if the request belongs to one)
+func (s *server) modelFolderOfRequest(ginContext *gin.Context, modelUUID string, folderNameOfKey string) (modelFolder string, inEditSession bool, ok bool) {
+	modelFolder, ok = s.checkModelFolder(ginContext, modelUUID, folderNameOfKey)
+	if !ok {
+		return modelFolder, false, false
+	}
+	editSessionID := ginContext.GetHeader(editSessionHeader)
+	if len(editSessionID) == 0 {
+		return modelFolder, false, true
+	}
+	sessionFolder, ok := s.checkEditSessionFolder(ginContext, modelFolder, editSessionID)
+	return sessionFolder, true, ok
+}
+
+func (s *server) checkEditSessionFolder(ginContext *gin.Context, modelFolder string, editSessionID string) (sessionFolder string, ok bool) {
+	uuidParsed, err := uuid.Parse(editSessionID)
+	if err != nil {
+		respondError(ginContext, http.StatusNotFound, errorCodeNotFound, "edit session not found")
+		return sessionFolder, false
+	}
+	sessionFolder = filepath.Join(modelFolder, editSessionsFolder, uuidParsed.String())
+	info, err := s.storage.Stat(filepath.Join(sessionFolder, s.config.InputFile))
+	if err != nil || time.Since(info.ModTime()) > editSessionTimeout {
+		respondError(ginContext, http.StatusNotFound, errorCodeNotFound, "edit session not found")
+		return sessionFolder, false
+	}
+	return sessionFolder, true
+}
+
+// removeExpiredEditSessions removes the edit sessions of the model without any change since the timeout
+func (s *server) removeExpiredEditSessions(modelFolder string) error {
+	sessions, err := s.storage.ReadDir(filepath.Join(modelFolder, editSessionsFolder))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	for _, session := range sessions {
+		sessionFolder := filepath.Join(modelFolder, editSessionsFolder, session.Name())
+		info, err := s.storage.Stat(filepath.Join(sessionFolder, s.config.InputFile))
+		if err == nil && time.Since(info.ModTime()) <= editSessionTimeout {
+			continue
+		}
+		err = s.storage.RemoveAll(sessionFolder)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *server) beginEditSession(ginContext *gin.Context) {
+	folderNameOfKey, key, ok := s.checkTokenToFolderName(ginContext)
+	if !ok {
+		return
+	}
+	s.lockFolder(folderNameOfKey)
+	defer s.unlockFolder(folderNameOfKey)
+	modelFolder, ok := s.checkModelFolder(ginContext, ginContext.Param("model-id"), folderNameOfKey)
+	if !ok {
+		return
+	}
+	_, yamlText, ok := s.readModelFile(ginContext, modelFolder, key)
+	if !ok {
+		return
+	}
+	err := s.removeExpiredEditSessions(modelFolder)
+	if err != nil {
+		log.Println(err) // the new session works nevertheless
+	}
+	editSessionID := uuid.New().String()
+	sessionFolder := filepath.Join(modelFolder, editSessionsFolder, editSessionID)
+	err = s.storage.MkdirAll(sessionFolder)
+	if err == nil {
+		err = s.storage.WriteFile(filepath.Join(sessionFolder, editSessionBaseFilename), []byte(hashSHA256([]byte(yamlText))))
+	}
+	if err != nil {
+		log.Println(err)
+		respondError(ginContext, http.StatusInternalServerError, errorCodeInternal, "unable to begin edit session")
+		return
+	}
+	if s.writeModelYAML(ginContext, yamlText, key, sessionFolder, "", true) {
+		ginContext.JSON(http.StatusCreated, gin.H{
+			"message":      "edit session begun",
+			"edit_session": editSessionID, // to be sent as header edit-session of the requests belonging to the session
+		})
+	}
+}
+
+// commitEditSession writes the model of the edit session (as one history entry) if it is valid and the stored model
+// has not been changed since the session began, otherwise the session stays open
+func (s *server) commitEditSession(ginContext *gin.Context) {
+	folderNameOfKey, key, ok := s.checkTokenToFolderName(ginContext)
+	if !ok {
+		return
+	}
+	s.lockFolder(folderNameOfKey)
+	defer s.unlockFolder(folderNameOfKey)
+	modelFolder, ok := s.checkModelFolder(ginContext, ginContext.Param("model-id"), folderNameOfKey)
+	if !ok {
+		return
+	}
+	sessionFolder, ok := s.checkEditSessionFolder(ginContext, modelFolder, ginContext.Param("edit-session-id"))
+	if !ok {
+		return
+	}
+	_, storedYAML, ok := s.readModelFile(ginContext, modelFolder, key)
+	if !ok {
+		return
+	}
+	base, err := s.storage.ReadFile(filepath.Join(sessionFolder, editSessionBaseFilename))
+	if err != nil {
+		log.Println(err)
+		respondError(ginContext, http.StatusInternalServerError, errorCodeInternal, "unable to commit edit session")
+		return
+	}
+	if string(base) != hashSHA256([]byte(storedYAML)) {
+		respondError(ginContext, http.StatusConflict, errorCodeConflict, "model has been changed outside of the edit session")
+		return
+	}
+	stagedInput, stagedYAML, ok := s.readModelFile(ginContext, sessionFolder, key)
+	if !ok {
+		return
+	}
+	if !s.checkModelValid(ginContext, stagedInput) {
+		return
+	}
+	if !s.writeModelYAML(ginContext, stagedYAML, key, modelFolder, "Edit Session", false) {
+		return
+	}
+	err = s.storage.RemoveAll(sessionFolder)
+	if err != nil {
+		log.Println(err) // the model is written, the session expires anyway
+	}
+	ginContext.JSON(http.StatusOK, gin.H{
+		"message": "edit session committed",
+	})
+}
+
+func (s *server) abortEditSession(ginContext *gin.Context) {
+	folderNameOfKey, _, ok := s.checkTokenToFolderName(ginContext)
+	if !ok {
+		return
+	}
+	s.lockFolder(folderNameOfKey)
+	defer s.unlockFolder(folderNameOfKey)
+	modelFolder, ok := s.checkModelFolder(ginContext, ginContext.Param("model-id"), folderNameOfKey)
+	if !ok {
+		return
+	}
+	sessionFolder, ok := s.checkEditSessionFolder(ginContext, modelFolder, ginContext.Param("edit-session-id"))
+	if !ok {
+		return
+	}
+	err := s.storage.RemoveAll(sessionFolder)
+	if err != nil {
+		log.Println(err)
+		respondError(ginContext, http.StatusInternalServerError, errorCodeInternal, "unable to abort edit session")
+		return
+	}
+	ginContext.JSON(http.StatusOK, gin.H{
+		"message": "edit session aborted",
+	})
+}
@@ -0,0 +1,77 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/threagile/threagile/pkg/input"
+)
+
+func TestEditSession(t *testing.T) {
+	stub, err := os.ReadFile(filepath.Join("..", "..", "demo", "stub", "threagile.yaml"))
+	assert.NoError(t, err)
+	m := newModelTestServer(t, string(stub))
+
+	recorder := m.call(m.beginEditSession, http.MethodPost, nil, nil)
+	assert.Equal(t, http.StatusCreated, recorder.Code)
+	var begun map[string]string
+	assert.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &begun))
+	sessionParams := gin.Params{{Key: "edit-session-id", Value: begun["edit_session"]}}
+	session := m
+	session.editSession = begun["edit_session"]
+
+	// the intermediate state with a runtime of a missing technical asset is only staged
+	runtimeParams := gin.Params{{Key: "shared-runtime-id", Value: "some-runtime"}}
+	invalidRuntime := payloadSharedRuntime{Title: "Some Shared Runtime", SharedRuntime: input.SharedRuntime{ID: "some-runtime", TechnicalAssetsRunning: []string{"missing-component"}}}
+	recorder = session.call(session.setSharedRuntime, http.MethodPut, runtimeParams, invalidRuntime)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	recorder = session.call(session.getSharedRuntime, http.MethodGet, runtimeParams, nil)
+	assert.Contains(t, recorder.Body.String(), "missing-component")
+	recorder = m.call(m.getSharedRuntime, http.MethodGet, runtimeParams, nil)
+	assert.NotContains(t, recorder.Body.String(), "missing-component")
+
+	recorder = m.call(m.commitEditSession, http.MethodPost, sessionParams, nil)
+	assert.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
+
+	validRuntime := invalidRuntime
+	validRuntime.TechnicalAssetsRunning = []string{"some-component"}
+	recorder = session.call(session.setSharedRuntime, http.MethodPut, runtimeParams, validRuntime)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	recorder = session.call(session.setCover, http.MethodPut, nil, payloadCover{Title: "Edited In Session"})
+	assert.Equal(t, http.StatusOK, recorder.Code)
+
+	recorder = m.call(m.commitEditSession, http.MethodPost, sessionParams, nil)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	recorder = m.call(m.getCover, http.MethodGet, nil, nil)
+	assert.Contains(t, recorder.Body.String(), "Edited In Session")
+	history, err := m.storage.ReadDir(filepath.Join(m.modelFolder, "history"))
+	assert.NoError(t, err)
+	assert.Len(t, history, 1)
+
+	recorder = session.call(session.getCover, http.MethodGet, nil, nil)
+	assert.Equal(t, http.StatusNotFound, recorder.Code, "a committed session is closed")
+}
+
+func TestEditSessionConflictsWithOutsideChanges(t *testing.T) {
+	m := newModelTestServer(t, communicationLinkTestModel)
+	recorder := m.call(m.beginEditSession, http.MethodPost, nil, nil)
+	assert.Equal(t, http.StatusCreated, recorder.Code)
+	var begun map[string]string
+	assert.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &begun))
+	sessionParams := gin.Params{{Key: "edit-session-id", Value: begun["edit_session"]}}
+
+	recorder = m.call(m.setCover, http.MethodPut, nil, payloadCover{Title: "Changed Outside"})
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	recorder = m.call(m.commitEditSession, http.MethodPost, sessionParams, nil)
+	assert.Equal(t, http.StatusConflict, recorder.Code)
+
+	recorder = m.call(m.abortEditSession, http.MethodDelete, sessionParams, nil)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	recorder = m.call(m.abortEditSession, http.MethodDelete, sessionParams, nil)
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+}
@@ -0,0 +1,119 @@
+package server
+
+import (
+	"log"
+	"net/http"
+	"regexp"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// errorCode tells clients the kind of failure to branch on, unlike the message it is stable
+type errorCode string
+
+const (
+	errorCodeBadRequest           errorCode = "bad_request"
+	errorCodeInvalidPayload       errorCode = "invalid_payload"
+	errorCodePayloadTooLarge      errorCode = "payload_too_large"
+	errorCodeUnauthorized         errorCode = "unauthorized"
+	errorCodeForbidden            errorCode = "forbidden"
+	errorCodeNotFound             errorCode = "not_found"
+	errorCodeKeyNotFound          errorCode = "key_not_found"
+	errorCodeTokenNotFound        errorCode = "token_not_found"
+	errorCodeShareTokenNotFound   errorCode = "share_token_not_found"
+	errorCodeGrantNotFound        errorCode = "grant_not_found"
+	errorCodeTenantNotFound       errorCode = "tenant_not_found"
+	errorCodeModelNotFound        errorCode = "model_not_found"
+	errorCodeTemplateNotFound     errorCode = "template_not_found"
+	errorCodeAnalysisJobNotFound  errorCode = "analysis_job_not_found"
+	errorCodeDisabled             errorCode = "disabled"
+	errorCodeConflict             errorCode = "conflict"
+	errorCodeIdempotencyKeyReused errorCode = "idempotency_key_reused"
+	errorCodeInvalidModel         errorCode = "invalid_model"
+	errorCodePolicyViolation      errorCode = "policy_violation"
+	errorCodeQuotaExceeded        errorCode = "quota_exceeded"
+	errorCodeThrottled            errorCode = "throttled"
+	errorCodeInternal             errorCode = "internal_error"
+)
+
+// errorCodes are documented as the values of the code of payloadError in the OpenAPI document
+var errorCodes = []errorCode{
+	errorCodeBadRequest, errorCodeInvalidPayload, errorCodePayloadTooLarge, errorCodeUnauthorized, errorCodeForbidden, errorCodeNotFound,
+	errorCodeKeyNotFound, errorCodeTokenNotFound, errorCodeShareTokenNotFound, errorCodeGrantNotFound, errorCodeTenantNotFound,
+	errorCodeModelNotFound, errorCodeTemplateNotFound, errorCodeAnalysisJobNotFound, errorCodeDisabled, errorCodeConflict, errorCodeIdempotencyKeyReused, errorCodeInvalidModel,
+	errorCodePolicyViolation, errorCodeQuotaExceeded, errorCodeThrottled, errorCodeInternal,
+}
+
+// payloadError is the response of all failed calls
+type payloadError struct {
+	Error         string    `json:"error"` // the message
+	Code          errorCode `json:"code"`
+	Details       []string  `json:"details,omitempty"` // e.g. the problems of an invalid model or payload
+	CorrelationId string    `json:"correlation_id"`    // of the request, as in the server log
+}
+
+const (
+	correlationIdHeader = "X-Correlation-ID"
+	correlationIdKey    = "correlation-id"
+)
+
+// correlationIdPattern is what a correlation id given by the client has to look like to be taken over
+var correlationIdPattern = regexp.MustCompile(`^[A-Za-z0-9._-]{1,64}$`)
+
+// correlation is the middleware giving each request a correlation id (the one of the client, if any), answered as
+// header and in the errors
+func correlation(ginContext *gin.Context) {
+	correlationId(ginContext)
+}
+
+func correlationId(ginContext *gin.Context) string {
+	if id := ginContext.GetString(correlationIdKey); len(id) > 0 {
+		return id
+	}
+	id := ginContext.GetHeader(correlationIdHeader)
+	if !correlationIdPattern.MatchString(id) {
+		id = uuid.New().String()
+	}
+	ginContext.Set(correlationIdKey, id)
+	ginContext.Header(correlationIdHeader, id)
+	return id
+}
+
+// respondError answers the error (aborting the remaining handlers), server errors are logged with the correlation id
+func respondError(ginContext *gin.Context, status int, code errorCode, message string, details ...string) {
+	id := correlationId(ginContext)
+	if status >= http.StatusInternalServerError {
+		log.Printf("request %v failed: %v", id, message)
+	}
+	ginContext.AbortWithStatusJSON(status, payloadError{
+		Error:         message,
+		Code:          code,
+		Details:       details,
+		CorrelationId: id,
+	})
+}
+
+// errorCodeOfStatus is the generic code of the status, for errors without a more specific one
+func errorCodeOfStatus(status int) errorCode {
+	switch status {
+	case http.StatusUnauthorized:
+		return errorCodeUnauthorized
+	case http.StatusForbidden:
+		return errorCodeForbidden
+	case http.StatusNotFound:
+		return errorCodeNotFound
+	case http.StatusConflict:
+		return errorCodeConflict
+	case http.StatusRequestEntityTooLarge:
+		return errorCodePayloadTooLarge
+	case http.StatusUnprocessableEntity:
+		return errorCodeInvalidModel
+	case http.StatusTooManyRequests:
+		return errorCodeThrottled
+	}
+	if status >= http.StatusInternalServerError {
+		return errorCodeInternal
+	}
+	return errorCodeBadRequest
+}
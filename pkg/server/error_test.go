@@ -0,0 +1,51 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRespondErrorWithCorrelationId(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(correlation)
+	router.GET("/fail", func(ginContext *gin.Context) {
+		respondError(ginContext, http.StatusUnprocessableEntity, errorCodeInvalidModel, "invalid model", "first reason", "second reason")
+	})
+	call := func(correlationId string) (*httptest.ResponseRecorder, payloadError) {
+		recorder := httptest.NewRecorder()
+		request := httptest.NewRequest(http.MethodGet, "/fail", nil)
+		if len(correlationId) > 0 {
+			request.Header.Set(correlationIdHeader, correlationId)
+		}
+		router.ServeHTTP(recorder, request)
+		var response payloadError
+		assert.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+		return recorder, response
+	}
+
+	recorder, response := call("")
+	assert.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
+	assert.Equal(t, errorCodeInvalidModel, response.Code)
+	assert.Equal(t, "invalid model", response.Error)
+	assert.Equal(t, []string{"first reason", "second reason"}, response.Details)
+	assert.NotEmpty(t, response.CorrelationId)
+	assert.Equal(t, response.CorrelationId, recorder.Header().Get(correlationIdHeader))
+
+	_, response = call("request-42")
+	assert.Equal(t, "request-42", response.CorrelationId, "the one of the client")
+	_, response = call("not a valid id\n")
+	assert.NotEqual(t, "not a valid id\n", response.CorrelationId)
+}
+
+func TestErrorCodeOfStatus(t *testing.T) {
+	assert.Equal(t, errorCodeNotFound, errorCodeOfStatus(http.StatusNotFound))
+	assert.Equal(t, errorCodeConflict, errorCodeOfStatus(http.StatusConflict))
+	assert.Equal(t, errorCodeInternal, errorCodeOfStatus(http.StatusBadGateway))
+	assert.Equal(t, errorCodeBadRequest, errorCodeOfStatus(http.StatusBadRequest))
+}
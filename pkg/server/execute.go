@@ -5,6 +5,9 @@ Copyright © 2023 NAME HERE <EMAIL ADDRESS>
 package server
 
 import (
+	"bytes"
+	"context"
+	"errors"
 	"fmt"
 	"io"
 	"log"
@@ -12,10 +15,13 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"sort"
 	"strconv"
 	"strings"
 
 	"github.com/gin-gonic/gin"
+	"github.com/threagile/threagile/pkg/common"
+	"github.com/threagile/threagile/pkg/security/types"
 )
 
 func (s *server) analyze(ginContext *gin.Context) {
@@ -23,124 +29,82 @@ func (s *server) analyze(ginContext *gin.Context) {
 }
 
 func (s *server) check(ginContext *gin.Context) {
-	_, ok := s.execute(ginContext, true)
+	_, warnings, ok := s.execute(ginContext, true)
 	if ok {
-		ginContext.JSON(http.StatusOK, gin.H{
-			"message": "model is ok",
+		ginContext.JSON(http.StatusOK, payloadCheck{
+			Message:  "model is ok",
+			Valid:    true,
+			Warnings: warnings,
 		})
 	}
 }
 
-func (s *server) execute(ginContext *gin.Context, dryRun bool) (yamlContent []byte, ok bool) {
+func (s *server) execute(ginContext *gin.Context, dryRun bool) (yamlContent []byte, warnings []string, ok bool) {
 	defer func() {
 		var err error
 		if r := recover(); r != nil {
 			s.errorCount++
 			err = r.(error)
 			log.Println(err)
-			ginContext.JSON(http.StatusBadRequest, gin.H{
-				"error": strings.TrimSpace(err.Error()),
-			})
+			var failure *common.Failure
+			if errors.As(err, &failure) && failure.Code == common.ExitCodePolicyGateFailure {
+				respondError(ginContext, http.StatusUnprocessableEntity, errorCodePolicyViolation, failure.Error(), failure.Details...)
+			} else {
+				respondError(ginContext, http.StatusBadRequest, errorCodeBadRequest, strings.TrimSpace(err.Error()))
+			}
 			ok = false
 		}
 	}()
 
-	dpi, err := strconv.Atoi(ginContext.DefaultQuery("dpi", strconv.Itoa(s.config.GraphvizDPI)))
-	if err != nil {
-		handleErrorInServiceCall(err, ginContext)
-		return yamlContent, false
+	dpi, ok := s.requestedDPI(ginContext, "")
+	if !ok {
+		return yamlContent, warnings, false
 	}
 
-	fileUploaded, header, err := ginContext.Request.FormFile("file")
+	workspace, err := newTempWorkspace(s.config.TempFolder, "execute")
 	if err != nil {
 		handleErrorInServiceCall(err, ginContext)
-		return yamlContent, false
+		return yamlContent, warnings, false
 	}
+	defer workspace.Close()
 
-	if header.Size > 50000000 {
-		msg := "maximum model upload file size exceeded (denial-of-service protection)"
-		log.Println(msg)
-		ginContext.JSON(http.StatusRequestEntityTooLarge, gin.H{
-			"error": msg,
-		})
-		return yamlContent, false
+	yamlFile, ok := s.receiveModelUpload(ginContext, workspace)
+	if !ok {
+		return yamlContent, warnings, false
 	}
 
-	filenameUploaded := strings.TrimSpace(header.Filename)
-
-	tmpInputDir, err := os.MkdirTemp(s.config.TempFolder, "threagile-input-")
+	tmpOutputDir, err := workspace.Mkdir("output")
 	if err != nil {
 		handleErrorInServiceCall(err, ginContext)
-		return yamlContent, false
+		return yamlContent, warnings, false
 	}
-	defer func() { _ = os.RemoveAll(tmpInputDir) }()
 
-	tmpModelFile, err := os.CreateTemp(tmpInputDir, "threagile-model-*")
-	if err != nil {
-		handleErrorInServiceCall(err, ginContext)
-		return yamlContent, false
-	}
-	defer func() { _ = os.Remove(tmpModelFile.Name()) }()
-	_, err = io.Copy(tmpModelFile, fileUploaded)
+	tmpResultFile, err := workspace.CreateFile("threagile-result-*.zip")
 	if err != nil {
 		handleErrorInServiceCall(err, ginContext)
-		return yamlContent, false
+		return yamlContent, warnings, false
 	}
 
-	yamlFile := tmpModelFile.Name()
-
-	if strings.ToLower(filepath.Ext(filenameUploaded)) == ".zip" {
-		// unzip first (including the resources like images etc.)
-		if s.config.Verbose {
-			fmt.Println("Decompressing uploaded archive")
-		}
-		filenamesUnzipped, err := unzip(tmpModelFile.Name(), tmpInputDir)
-		if err != nil {
-			handleErrorInServiceCall(err, ginContext)
-			return yamlContent, false
-		}
-		found := false
-		for _, name := range filenamesUnzipped {
-			if strings.ToLower(filepath.Ext(name)) == ".yaml" {
-				yamlFile = name
-				found = true
-				break
-			}
-		}
-		if !found {
-			panic(fmt.Errorf("no yaml file found in uploaded archive"))
-		}
-	}
-
-	tmpOutputDir, err := os.MkdirTemp(s.config.TempFolder, "threagile-output-")
-	if err != nil {
-		handleErrorInServiceCall(err, ginContext)
-		return yamlContent, false
+	if dryRun {
+		s.doItViaRuntimeCall(ginContext.Request.Context(), workspace, yamlFile, tmpOutputDir, false, false, false, false, false, true, true, true, false, 40)
+	} else {
+		s.doItViaRuntimeCall(ginContext.Request.Context(), workspace, yamlFile, tmpOutputDir, true, true, true, true, true, true, true, true, true, dpi)
 	}
-	defer func() { _ = os.RemoveAll(tmpOutputDir) }()
-
-	tmpResultFile, err := os.CreateTemp(s.config.TempFolder, "threagile-result-*.zip")
+	warnings, err = common.ReadWarnings(filepath.Join(tmpOutputDir, s.config.WarningsFilename))
 	if err != nil {
 		handleErrorInServiceCall(err, ginContext)
-		return yamlContent, false
-	}
-	defer func() { _ = os.Remove(tmpResultFile.Name()) }()
-
-	if dryRun {
-		s.doItViaRuntimeCall(yamlFile, tmpOutputDir, false, false, false, false, false, true, true, true, 40)
-	} else {
-		s.doItViaRuntimeCall(yamlFile, tmpOutputDir, true, true, true, true, true, true, true, true, dpi)
+		return yamlContent, warnings, false
 	}
 
 	yamlContent, err = os.ReadFile(filepath.Clean(yamlFile))
 	if err != nil {
 		handleErrorInServiceCall(err, ginContext)
-		return yamlContent, false
+		return yamlContent, warnings, false
 	}
 	err = os.WriteFile(filepath.Join(tmpOutputDir, s.config.InputFile), yamlContent, 0400)
 	if err != nil {
 		handleErrorInServiceCall(err, ginContext)
-		return yamlContent, false
+		return yamlContent, warnings, false
 	}
 
 	if !dryRun {
@@ -148,6 +112,8 @@ func (s *server) execute(ginContext *gin.Context, dryRun bool) (yamlContent []by
 			filepath.Join(tmpOutputDir, s.config.InputFile),
 			filepath.Join(tmpOutputDir, s.config.DataFlowDiagramFilenamePNG),
 			filepath.Join(tmpOutputDir, s.config.DataAssetDiagramFilenamePNG),
+			filepath.Join(tmpOutputDir, s.config.RiskMatrixFilenamePNG),
+			filepath.Join(tmpOutputDir, s.config.RiskMatrixFilenameSVG),
 			filepath.Join(tmpOutputDir, s.config.ReportFilename),
 			filepath.Join(tmpOutputDir, s.config.ExcelRisksFilename),
 			filepath.Join(tmpOutputDir, s.config.ExcelTagsFilename),
@@ -162,7 +128,7 @@ func (s *server) execute(ginContext *gin.Context, dryRun bool) (yamlContent []by
 		err = zipFiles(tmpResultFile.Name(), files)
 		if err != nil {
 			handleErrorInServiceCall(err, ginContext)
-			return yamlContent, false
+			return yamlContent, warnings, false
 		}
 		if s.config.Verbose {
 			log.Println("Streaming back result file: " + tmpResultFile.Name())
@@ -170,22 +136,211 @@ func (s *server) execute(ginContext *gin.Context, dryRun bool) (yamlContent []by
 		ginContext.FileAttachment(tmpResultFile.Name(), "threagile-result.zip")
 	}
 	s.successCount++
-	return yamlContent, true
+	return yamlContent, warnings, true
+}
+
+// analyzeRisks analyzes the uploaded model in-process with only the risk rules selected by the query parameter rules
+// (wildcards allowed), answering just their risks, which is much faster than a full analysis for interactive clients
+func (s *server) analyzeRisks(ginContext *gin.Context) {
+	onlyRiskRules := make([]string, 0)
+	for _, rule := range strings.Split(ginContext.Query("rules"), ",") {
+		if rule = strings.TrimSpace(rule); len(rule) > 0 {
+			onlyRiskRules = append(onlyRiskRules, rule)
+		}
+	}
+	if len(onlyRiskRules) == 0 {
+		respondError(ginContext, http.StatusBadRequest, errorCodeBadRequest, "query parameter rules is missing")
+		return
+	}
+
+	workspace, err := newTempWorkspace(s.config.TempFolder, "risks")
+	if err != nil {
+		handleErrorInServiceCall(err, ginContext)
+		return
+	}
+	defer workspace.Close()
+
+	yamlFile, ok := s.receiveModelUpload(ginContext, workspace)
+	if !ok {
+		return
+	}
+	tmpOutputDir, err := workspace.Mkdir("output")
+	if err != nil {
+		handleErrorInServiceCall(err, ginContext)
+		return
+	}
+
+	readResult, err := s.analyzeInProcessWithRules(ginContext.Request.Context(), workspace, yamlFile, tmpOutputDir, onlyRiskRules)
+	if err != nil {
+		s.errorCount++
+		respondError(ginContext, http.StatusBadRequest, errorCodeBadRequest, strings.TrimSpace(err.Error()))
+		return
+	}
+	s.successCount++
+	ginContext.JSON(http.StatusOK, types.AllRisks(readResult.ParsedModel))
+}
+
+// receiveModelUpload stores the uploaded model file (or the model of the uploaded archive, unzipped with its images)
+// in the input folder of the workspace, the error response is already sent when it fails
+func (s *server) receiveModelUpload(ginContext *gin.Context, workspace *TempWorkspace) (yamlFile string, ok bool) {
+	fileUploaded, header, err := ginContext.Request.FormFile("file")
+	if err != nil {
+		handleErrorInServiceCall(err, ginContext)
+		return yamlFile, false
+	}
+
+	if header.Size > 50000000 {
+		msg := "maximum model upload file size exceeded (denial-of-service protection)"
+		log.Println(msg)
+		respondError(ginContext, http.StatusRequestEntityTooLarge, errorCodePayloadTooLarge, msg)
+		return yamlFile, false
+	}
+
+	filenameUploaded := strings.TrimSpace(header.Filename)
+
+	tmpInputDir, err := workspace.Mkdir("input")
+	if err != nil {
+		handleErrorInServiceCall(err, ginContext)
+		return yamlFile, false
+	}
+
+	tmpModelFile, err := os.CreateTemp(tmpInputDir, "threagile-model-*")
+	if err != nil {
+		handleErrorInServiceCall(err, ginContext)
+		return yamlFile, false
+	}
+	_, err = io.Copy(tmpModelFile, fileUploaded)
+	if err != nil {
+		handleErrorInServiceCall(err, ginContext)
+		return yamlFile, false
+	}
+
+	yamlFile = tmpModelFile.Name()
+
+	if strings.ToLower(filepath.Ext(filenameUploaded)) == ".zip" {
+		// unzip first (including the resources like images etc.)
+		if s.config.Verbose {
+			log.Println("Decompressing uploaded archive")
+		}
+		filenamesUnzipped, err := unzip(tmpModelFile.Name(), tmpInputDir)
+		if err != nil {
+			handleErrorInServiceCall(err, ginContext)
+			return yamlFile, false
+		}
+		found := false
+		for _, name := range filenamesUnzipped {
+			if ext := strings.ToLower(filepath.Ext(name)); ext == ".yaml" || ext == ".yml" || ext == ".json" {
+				yamlFile = name
+				found = true
+				break
+			}
+		}
+		if !found {
+			respondError(ginContext, http.StatusBadRequest, errorCodeBadRequest, "no yaml or json model file found in uploaded archive")
+			return yamlFile, false
+		}
+	}
+
+	return yamlFile, true
 }
 
 // ultimately to avoid any in-process memory and/or data leaks by the used third party libs like PDF generation: exec and quit
-func (s *server) doItViaRuntimeCall(modelFile string, outputDir string,
-	generateDataFlowDiagram, generateDataAssetDiagram, generateReportPdf, generateRisksExcel, generateTagsExcel, generateRisksJSON, generateTechnicalAssetsJSON, generateStatsJSON bool,
-	dpi int) {
+func (s *server) doItViaRuntimeCall(ctx context.Context, workspace *TempWorkspace, modelFile string, outputDir string,
+	generateDataFlowDiagram, generateDataAssetDiagram, generateReportPdf, generateRisksExcel, generateTagsExcel, generateRisksJSON, generateTechnicalAssetsJSON, generateStatsJSON, generateRiskMatrix bool,
+	dpi int) string {
+	args := s.runtimeCallArgs(workspace, modelFile, outputDir, generateDataFlowDiagram, generateDataAssetDiagram, generateReportPdf, generateRisksExcel, generateTagsExcel, generateRisksJSON, generateTechnicalAssetsJSON, generateStatsJSON, generateRiskMatrix, dpi)
+	return s.runtimeCall(ctx, outputDir, args, nil)
+}
+
+// runtimeCallArgs are the command line arguments of the sub-process of doItViaRuntimeCall, rendering the diagrams with
+// the dpi answered by requestedDPI
+func (s *server) runtimeCallArgs(workspace *TempWorkspace, modelFile string, outputDir string,
+	generateDataFlowDiagram, generateDataAssetDiagram, generateReportPdf, generateRisksExcel, generateTagsExcel, generateRisksJSON, generateTechnicalAssetsJSON, generateStatsJSON, generateRiskMatrix bool,
+	dpi int) []string {
 	// Remember to also add the same args to the exec based sub-process calls!
-	var cmd *exec.Cmd
-	args := []string{"-model", modelFile, "-output", outputDir, "-execute-model-macro", s.config.ExecuteModelMacro, "-raa-run", s.config.RAAPlugin, "-custom-risk-rules-plugins", strings.Join(s.config.RiskRulesPlugins, ","), "-skip-risk-rules", strings.Join(s.config.SkipRiskRules, ","), "-diagram-dpi", strconv.Itoa(dpi)}
+	args := []string{"-model", modelFile, "-output", outputDir, "-execute-model-macro", s.config.ExecuteModelMacro, "-raa-run", s.config.RAAPlugin, "-custom-risk-rules-plugins", strings.Join(s.config.RiskRulesPlugins, ","), "-skip-risk-rules", strings.Join(s.config.SkipRiskRules, ","), "-temp-dir", workspace.Dir}
+	if dpi > 0 {
+		args = append(args, "-diagram-dpi", strconv.Itoa(dpi))
+	} else {
+		args = append(args, "-diagram-max-pixels", strconv.Itoa(s.config.DiagramMaxPixels), "-max-dpi", strconv.Itoa(-dpi))
+	}
+	if len(s.config.RiskCategoryOverridesFile) > 0 {
+		args = append(args, "-risk-category-overrides", s.config.RiskCategoryOverridesFile)
+	}
+	if len(s.config.OwnerDirectoryPlugin) > 0 {
+		args = append(args, "-owner-directory-run", s.config.OwnerDirectoryPlugin)
+	}
+	if len(s.config.DiagramTheme) > 0 {
+		args = append(args, "-diagram-theme", s.config.DiagramTheme)
+	}
+	if len(s.config.DiagramVariants) > 0 {
+		args = append(args, "-diagram-variants", strings.Join(s.config.DiagramVariants, ","))
+	}
+	if len(s.config.FontFile) > 0 {
+		args = append(args, "-font", s.config.FontFile)
+	}
+	if len(s.config.ReportSectionPlugins) > 0 {
+		args = append(args, "-report-section-plugins", strings.Join(s.config.ReportSectionPlugins, ","))
+	}
+	if s.config.OwnerDirectoryStrict {
+		args = append(args, "-owner-directory-strict")
+	}
+	if s.config.StrictRules {
+		args = append(args, "-strict-rules")
+	}
+	if len(s.config.ThreatIntelFeed) > 0 {
+		args = append(args, "-threat-intel-feed", s.config.ThreatIntelFeed)
+	}
+	if len(s.config.TagTaxonomyFilename) > 0 {
+		args = append(args, "-tag-taxonomy", s.config.TagTaxonomyFilename)
+	}
+	for _, pattern := range s.config.LogRedactionPatterns {
+		args = append(args, "-log-redaction-pattern", pattern)
+	}
+	if len(s.config.SeverityRecalibration) > 0 {
+		shifts := make([]string, 0, len(s.config.SeverityRecalibration))
+		for criticality, shift := range s.config.SeverityRecalibration {
+			shifts = append(shifts, criticality+"="+strconv.Itoa(shift))
+		}
+		sort.Strings(shifts)
+		args = append(args, "-severity-recalibration", strings.Join(shifts, ","))
+	}
+	if len(s.config.RiskMerge.Groups) > 0 {
+		names := make([]string, 0, len(s.config.RiskMerge.Groups))
+		for name := range s.config.RiskMerge.Groups {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			args = append(args, "-risk-merge-group", name+"="+strings.Join(s.config.RiskMerge.Groups[name], ","))
+		}
+		args = append(args, "-risk-merge-keys", strings.Join(s.config.RiskMerge.Keys, ","))
+	}
+	if len(s.config.PolicyGateRegoFolder) > 0 {
+		args = append(args, "-policy-gate-rego", s.config.PolicyGateRegoFolder)
+	}
+	if len(s.config.RiskRulesRegoFolder) > 0 {
+		args = append(args, "-custom-risk-rules-rego", s.config.RiskRulesRegoFolder)
+	}
+	if len(s.config.RiskRulesScripts) > 0 {
+		args = append(args, "-custom-risk-rules-scripts", strings.Join(s.config.RiskRulesScripts, ","))
+	}
+	if len(s.config.ScannerFindingsFiles) > 0 {
+		args = append(args, "-scanner-findings", strings.Join(s.config.ScannerFindingsFiles, ","))
+	}
+	if commentsFile := riskCommentsFileOf(workspace); len(commentsFile) > 0 {
+		args = append(args, "-risk-comments", commentsFile)
+	}
+	args = append(args, "-plugin-timeout", strconv.Itoa(s.config.PluginTimeoutSeconds), "-analysis-timeout", strconv.Itoa(s.config.AnalysisTimeoutSeconds), "-render-timeout", strconv.Itoa(s.config.RenderTimeoutSeconds))
 	if s.config.Verbose {
 		args = append(args, "-verbose")
 	}
 	if s.config.IgnoreOrphanedRiskTracking { // TODO why add all them as arguments, when they are also variables on outer level?
 		args = append(args, "-ignore-orphaned-risk-tracking")
 	}
+	if s.config.AutoSeedTagsAvailable {
+		args = append(args, "-auto-seed-tags")
+	}
 	if generateDataFlowDiagram {
 		args = append(args, "-generate-data-flow-diagram")
 	}
@@ -210,20 +365,86 @@ func (s *server) doItViaRuntimeCall(modelFile string, outputDir string,
 	if generateStatsJSON {
 		args = append(args, "-generate-stats-json")
 	}
+	if generateRiskMatrix {
+		args = append(args, "-generate-risk-matrix")
+	}
+	return args
+}
+
+// runtimeCall runs the sub-process with the arguments, panicking with its output when it fails; the progress (if any)
+// gets each line of the output as soon as it is written, i.e. the progress messages of a verbose sub-process
+func (s *server) runtimeCall(ctx context.Context, outputDir string, args []string, progress func(event common.ProgressEvent)) string {
+	var cmd *exec.Cmd
 	self, nameError := os.Executable()
 	if nameError != nil {
 		panic(nameError)
 	}
 
-	cmd = exec.Command(self, args...) // #nosec G204
-	out, err := cmd.CombinedOutput()
+	// the sub-process limits its phases itself, this only ensures a hung one does not block the worker forever
+	// (and that it is killed once the client is gone)
+	timeoutSeconds := 0
+	if s.config.AnalysisTimeoutSeconds > 0 && s.config.RenderTimeoutSeconds > 0 {
+		timeoutSeconds = s.config.AnalysisTimeoutSeconds + s.config.RenderTimeoutSeconds + s.config.PluginTimeoutSeconds
+	}
+	ctx, cancel := common.WithTimeout(ctx, timeoutSeconds)
+	defer cancel()
+
+	cmd = exec.CommandContext(ctx, self, args...) // #nosec G204
+	if progress != nil {
+		cmd.Env = append(os.Environ(), common.ProgressEventsEnvName+"=true")
+	}
+	output := &progressWriter{progress: progress}
+	cmd.Stdout, cmd.Stderr = output, output
+	err := cmd.Run()
+	out := output.buffer.Bytes()
+	var exitError *exec.ExitError
+	if errors.As(err, &exitError) && common.ExitCode(exitError.ExitCode()) == common.ExitCodePolicyGateFailure {
+		// the violations are listed in the failure report, so that the client gets them one by one
+		failure, reportError := common.ReadFailureReport(filepath.Join(outputDir, s.config.FailureFilename))
+		if reportError == nil {
+			panic(failure)
+		}
+	}
+	if errors.As(err, &exitError) {
+		// the sub-process failed the analysis (unlike e.g. it could not be started), telling the stage that failed
+		code := common.ExitCode(exitError.ExitCode())
+		if ctx.Err() != nil {
+			code = common.ExitCodeTimeout
+		}
+		panic(common.NewFailure(code, fmt.Errorf("%s", out)))
+	}
 	if err != nil {
-		panic(fmt.Errorf(string(out)))
+		panic(err)
 	} else {
 		if s.config.Verbose && len(out) > 0 {
-			fmt.Println("---")
-			fmt.Print(string(out))
-			fmt.Println("---")
+			log.Print("---\n" + string(out) + "---")
+		}
+	}
+	return string(out)
+}
+
+// progressWriter collects the output of the sub-process, passing its progress events on as their lines are complete
+type progressWriter struct {
+	buffer   bytes.Buffer
+	line     []byte
+	progress func(event common.ProgressEvent)
+}
+
+func (what *progressWriter) Write(data []byte) (int, error) {
+	what.buffer.Write(data)
+	if what.progress == nil {
+		return len(data), nil
+	}
+	what.line = append(what.line, data...)
+	for {
+		end := bytes.IndexByte(what.line, '\n')
+		if end < 0 {
+			break
+		}
+		if event, ok := common.ParseProgressEvent(strings.TrimSpace(string(what.line[:end]))); ok {
+			what.progress(event)
 		}
+		what.line = what.line[end+1:]
 	}
+	return len(data), nil
 }
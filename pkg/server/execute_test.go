@@ -0,0 +1,172 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/threagile/threagile/pkg/common"
+	"github.com/threagile/threagile/pkg/model"
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+func TestAnalyzeRisksOfSelectedRules(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	config := new(common.Config).Defaults("")
+	config.TempFolder = t.TempDir()
+	config.PluginTimeoutSeconds, config.AnalysisTimeoutSeconds = 0, 0
+	s := &server{config: config}
+
+	modelData, err := os.ReadFile(filepath.Join("..", "..", "demo", "example", "threagile.yaml"))
+	assert.NoError(t, err)
+	upload := func(rules string) *httptest.ResponseRecorder {
+		var body bytes.Buffer
+		writer := multipart.NewWriter(&body)
+		part, _ := writer.CreateFormFile("file", "threagile.yaml")
+		_, _ = part.Write(modelData)
+		_ = writer.Close()
+		recorder := httptest.NewRecorder()
+		ginContext, _ := gin.CreateTestContext(recorder)
+		ginContext.Request = httptest.NewRequest(http.MethodPost, "/direct/risks?rules="+rules, &body)
+		ginContext.Request.Header.Set("Content-Type", writer.FormDataContentType())
+		s.analyzeRisks(ginContext)
+		return recorder
+	}
+
+	recorder := upload("")
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+
+	recorder = upload("unencrypted-*,missing-authentication")
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	var risks []*types.Risk
+	assert.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &risks))
+	assert.NotEmpty(t, risks)
+	for _, risk := range risks {
+		assert.Contains(t, []string{"unencrypted-asset", "unencrypted-communication", "missing-authentication"}, risk.CategoryId)
+	}
+}
+
+func TestAnalyzeInProcessWithoutTemplatingAndHooks(t *testing.T) {
+	config := new(common.Config).Defaults("")
+	config.TempFolder = t.TempDir()
+	config.PluginTimeoutSeconds, config.AnalysisTimeoutSeconds = 0, 0
+	config.IgnoreOrphanedRiskTracking = true
+	config.ModelTemplating = "env"
+	config.PreParseHooks = []string{filepath.Join(t.TempDir(), "missing-hook")}
+	config.PostAnalysisHooks = []string{filepath.Join(t.TempDir(), "missing-hook")}
+	s := &server{config: config}
+	t.Setenv("THREAGILE_TEST_SECRET", "secret")
+
+	workspace, err := newTempWorkspace(config.TempFolder, "test")
+	assert.NoError(t, err)
+	defer workspace.Close()
+	modelFile := filepath.Join(workspace.Dir, "threagile.yaml")
+	modelData, err := os.ReadFile(filepath.Join("..", "..", "demo", "example", "threagile.yaml"))
+	assert.NoError(t, err)
+	modelData = []byte(strings.Replace(string(modelData), "title: Some Example Application", "title: ${THREAGILE_TEST_SECRET}", 1))
+	assert.NoError(t, os.WriteFile(modelFile, modelData, 0600))
+	outputDir, err := workspace.Mkdir("output")
+	assert.NoError(t, err)
+	result, err := s.analyzeInProcess(context.Background(), workspace, modelFile, outputDir)
+	if assert.NoError(t, err, "the hooks of the server are not run") {
+		assert.Equal(t, "${THREAGILE_TEST_SECRET}", result.ParsedModel.Title, "nor is the environment of the server expanded")
+	}
+}
+
+func TestAnalyzeGuarded(t *testing.T) {
+	defer func(original func(context.Context, *common.Config, types.ProgressReporter) (*model.ReadResult, error)) {
+		readAndAnalyzeModel = original
+	}(readAndAnalyzeModel)
+	config := new(common.Config).Defaults("")
+	reporter := common.DefaultProgressReporter{}
+
+	readAndAnalyzeModel = func(context.Context, *common.Config, types.ProgressReporter) (*model.ReadResult, error) {
+		panic("faulty analysis")
+	}
+	_, err := analyzeGuarded(context.Background(), config, reporter)
+	assert.ErrorContains(t, err, "analysis crashed: faulty analysis")
+
+	hung := make(chan struct{})
+	defer close(hung)
+	readAndAnalyzeModel = func(context.Context, *common.Config, types.ProgressReporter) (*model.ReadResult, error) {
+		<-hung // ignoring the context, like a stuck analysis
+		return nil, nil
+	}
+	config.AnalysisTimeoutSeconds = 1
+	_, err = analyzeGuarded(context.Background(), config, reporter)
+	assert.Equal(t, common.ExitCodeTimeout, common.ExitCodeOf(err))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	config.AnalysisTimeoutSeconds = 0
+	_, err = analyzeGuarded(ctx, config, reporter)
+	assert.Equal(t, common.ExitCodeTimeout, common.ExitCodeOf(err), "the request is gone")
+}
+
+func TestAnalyzeInProcessFailsForRulesNotLoaded(t *testing.T) {
+	config := new(common.Config).Defaults("")
+	config.TempFolder = t.TempDir()
+	config.PluginTimeoutSeconds, config.AnalysisTimeoutSeconds = 0, 0
+	config.IgnoreOrphanedRiskTracking = true
+	config.RiskRulesScripts = []string{filepath.Join(t.TempDir(), "missing-rule.js")}
+	s := &server{config: config}
+
+	workspace, err := newTempWorkspace(config.TempFolder, "test")
+	assert.NoError(t, err)
+	defer workspace.Close()
+	modelFile := filepath.Join(workspace.Dir, "threagile.yaml")
+	modelData, err := os.ReadFile(filepath.Join("..", "..", "demo", "example", "threagile.yaml"))
+	assert.NoError(t, err)
+	assert.NoError(t, os.WriteFile(modelFile, modelData, 0600))
+	outputDir, err := workspace.Mkdir("output")
+	assert.NoError(t, err)
+	_, err = s.analyzeInProcess(context.Background(), workspace, modelFile, outputDir)
+	assert.ErrorContains(t, err, `analysis failed: Script risk rule "`+config.RiskRulesScripts[0]+`" not loaded`, "instead of exiting the server")
+}
+
+func TestRuntimeCallArgs(t *testing.T) {
+	config := new(common.Config).Defaults("")
+	config.TempFolder = t.TempDir()
+	config.RiskRulesScripts = []string{"rules/secrets.js", "rules/queues.js"}
+	config.RiskRulesRegoFolder = "rules/rego"
+	config.ScannerFindingsFiles = []string{"scans/zap.sarif", "scans/dojo.json"}
+	config.SeverityRecalibration = map[string]int{"mission-critical": 1, "archive": -1}
+	config.RiskMerge = common.RiskMergeConfig{Groups: map[string][]string{"secrets": {"hardcoded-secret", "accidental-secret-leak"}}, Keys: []string{"technical_asset"}}
+	s := &server{config: config}
+	workspace, err := newTempWorkspace(config.TempFolder, "test")
+	assert.NoError(t, err)
+	defer workspace.Close()
+
+	args := s.runtimeCallArgs(workspace, "threagile.yaml", workspace.Dir, false, false, true, false, false, true, false, false, false, -150)
+	argument := func(name string) string {
+		for i, arg := range args[:len(args)-1] {
+			if arg == name {
+				return args[i+1]
+			}
+		}
+		return ""
+	}
+	assert.Equal(t, "rules/secrets.js,rules/queues.js", argument("-custom-risk-rules-scripts"), "the same rules as the in-process analysis")
+	assert.Equal(t, "rules/rego", argument("-custom-risk-rules-rego"))
+	assert.Equal(t, "scans/zap.sarif,scans/dojo.json", argument("-scanner-findings"))
+	assert.Equal(t, "archive=-1,mission-critical=1", argument("-severity-recalibration"))
+	assert.Equal(t, "secrets=hardcoded-secret,accidental-secret-leak", argument("-risk-merge-group"))
+	assert.Equal(t, "technical_asset", argument("-risk-merge-keys"))
+	assert.Equal(t, strconv.Itoa(config.DiagramMaxPixels), argument("-diagram-max-pixels"))
+	assert.Equal(t, "150", argument("-max-dpi"), "auto-scaled up to the maximum of the key")
+
+	args = s.runtimeCallArgs(workspace, "threagile.yaml", workspace.Dir, false, false, true, false, false, true, false, false, false, 120)
+	assert.Equal(t, "120", argument("-diagram-dpi"))
+	assert.Empty(t, argument("-max-dpi"))
+}
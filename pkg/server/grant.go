@@ -0,0 +1,323 @@
+package server
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// grantsFilename is stored alongside the model and lists its grants, each holding the hash of its grant token and the
+// key of the model wrapped by it (see model-key.go), allowing only the calls of the model its role allows
+const grantsFilename = "grants.json"
+
+// grantTokenHeader is sent instead of the header token by the holders of a grant, like auditors reading the reports
+const grantTokenHeader = "grant-token"
+
+// grantedAccessKey is the key of the grantedAccess in the gin context of requests authorized by a grant token
+const grantedAccessKey = "granted-access"
+
+// grantRole is what a grant allows on its model, each role including the rights of the ones before it
+type grantRole int
+
+const (
+	grantRoleOfMethod grantRole = iota // the default of the routes: read for GET requests, edit for all others
+	grantRoleRead
+	grantRoleEdit
+	grantRoleAdmin // also deleting the model and managing its share token and grants
+)
+
+var grantRoleNames = map[grantRole]string{
+	grantRoleRead:  "read",
+	grantRoleEdit:  "edit",
+	grantRoleAdmin: "admin",
+}
+
+func grantRoleOfName(name string) (grantRole, bool) {
+	for role, roleName := range grantRoleNames {
+		if strings.EqualFold(strings.TrimSpace(name), roleName) {
+			return role, true
+		}
+	}
+	return grantRoleOfMethod, false
+}
+
+type grant struct {
+	Id         string    `json:"id"`
+	Role       string    `json:"role"`
+	Note       string    `json:"note,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+	Hash       string    `json:"hash"`
+	WrappedKey []byte    `json:"wrapped_key"`
+}
+
+type payloadGrant struct {
+	Role string `json:"role"` // read, edit or admin
+	Note string `json:"note"` // like whom the grant is given to
+}
+
+type payloadGrantInfo struct {
+	Id         string    `json:"id"`
+	Role       string    `json:"role"`
+	Note       string    `json:"note,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+	GrantToken string    `json:"grant_token,omitempty"` // answered once when the grant is created
+}
+
+// grantedAccess is what the grant token of a request allows, the grant token taking the place of the key
+type grantedAccess struct {
+	folderNameOfKey string
+	grantToken      []byte
+	modelId         string
+	role            grantRole
+}
+
+// requiredGrantRole is the role grant tokens need for the route
+func (what route) requiredGrantRole() grantRole {
+	if what.role != grantRoleOfMethod {
+		return what.role
+	}
+	if what.method == http.MethodGet {
+		return grantRoleRead
+	}
+	return grantRoleEdit
+}
+
+// hasGrantRole tells whether the request is authorized by a grant token with (at least) the role
+func hasGrantRole(ginContext *gin.Context, role grantRole) bool {
+	value, granted := ginContext.Get(grantedAccessKey)
+	return granted && value.(*grantedAccess).role >= role
+}
+
+// grantable tells whether grant tokens are accepted by the route, which are the token routes of a single model
+func (what route) grantable() bool {
+	return what.auth == tokenAuth && (strings.Contains(what.path, ":model-id") || strings.Contains(what.path, ":analysis-job-id"))
+}
+
+func (s *server) createGrant(ginContext *gin.Context) {
+	folderNameOfKey, key, ok := s.checkTokenToFolderName(ginContext)
+	if !ok {
+		return
+	}
+	payload := payloadGrant{}
+	err := ginContext.BindJSON(&payload)
+	if err != nil {
+		respondError(ginContext, http.StatusBadRequest, errorCodeInvalidPayload, "unable to parse request payload")
+		return
+	}
+	role, ok := grantRoleOfName(payload.Role)
+	if !ok {
+		respondError(ginContext, http.StatusBadRequest, errorCodeBadRequest, "unknown role (read, edit or admin): "+payload.Role)
+		return
+	}
+	s.lockFolder(folderNameOfKey)
+	defer s.unlockFolder(folderNameOfKey)
+	modelFolder, ok := s.checkModelFolder(ginContext, ginContext.Param("model-id"), folderNameOfKey)
+	if !ok {
+		return
+	}
+	grants, err := s.readGrants(modelFolder)
+	if err != nil {
+		log.Println(err)
+		respondError(ginContext, http.StatusInternalServerError, errorCodeInternal, "unable to create grant")
+		return
+	}
+	err = s.migrateModelKey(modelFolder, key)
+	if err != nil {
+		log.Println(err)
+		respondError(ginContext, http.StatusInternalServerError, errorCodeInternal, "unable to create grant")
+		return
+	}
+	modelKey, err := s.modelCryptoKey(modelFolder, key)
+	if err != nil {
+		log.Println(err)
+		respondError(ginContext, http.StatusInternalServerError, errorCodeInternal, "unable to create grant")
+		return
+	}
+
+	token := make([]byte, keySize)
+	n, err := rand.Read(token)
+	if n != keySize || err != nil {
+		log.Println(err)
+		respondError(ginContext, http.StatusInternalServerError, errorCodeInternal, "unable to create grant")
+		return
+	}
+	wrappedKey, err := encryptWithKey(generateKeyFromAlreadyStrongRandomInput(token), modelKey)
+	if err == nil {
+		created := grant{Id: uuid.New().String(), Role: grantRoleNames[role], Note: payload.Note, CreatedAt: time.Now().UTC(), Hash: hashSHA256(token), WrappedKey: wrappedKey}
+		grants = append(grants, created)
+		err = s.writeGrants(modelFolder, grants)
+	}
+	if err != nil {
+		log.Println(err)
+		respondError(ginContext, http.StatusInternalServerError, errorCodeInternal, "unable to create grant")
+		return
+	}
+	info := grants[len(grants)-1].payload()
+	info.GrantToken = base64.RawURLEncoding.EncodeToString(token)
+	ginContext.JSON(http.StatusCreated, info)
+}
+
+func (s *server) listGrants(ginContext *gin.Context) {
+	folderNameOfKey, _, ok := s.checkTokenToFolderName(ginContext)
+	if !ok {
+		return
+	}
+	s.lockFolder(folderNameOfKey)
+	defer s.unlockFolder(folderNameOfKey)
+	modelFolder, ok := s.checkModelFolder(ginContext, ginContext.Param("model-id"), folderNameOfKey)
+	if !ok {
+		return
+	}
+	grants, err := s.readGrants(modelFolder)
+	if err != nil {
+		log.Println(err)
+		respondError(ginContext, http.StatusInternalServerError, errorCodeInternal, "unable to read grants")
+		return
+	}
+	infos := make([]payloadGrantInfo, 0, len(grants))
+	for _, stored := range grants {
+		infos = append(infos, stored.payload())
+	}
+	ginContext.JSON(http.StatusOK, infos)
+}
+
+func (s *server) deleteGrant(ginContext *gin.Context) {
+	folderNameOfKey, _, ok := s.checkTokenToFolderName(ginContext)
+	if !ok {
+		return
+	}
+	s.lockFolder(folderNameOfKey)
+	defer s.unlockFolder(folderNameOfKey)
+	modelFolder, ok := s.checkModelFolder(ginContext, ginContext.Param("model-id"), folderNameOfKey)
+	if !ok {
+		return
+	}
+	grants, err := s.readGrants(modelFolder)
+	if err != nil {
+		log.Println(err)
+		respondError(ginContext, http.StatusInternalServerError, errorCodeInternal, "unable to delete grant")
+		return
+	}
+	remaining := make([]grant, 0, len(grants))
+	for _, stored := range grants {
+		if stored.Id != ginContext.Param("grant-id") {
+			remaining = append(remaining, stored)
+		}
+	}
+	if len(remaining) == len(grants) {
+		respondError(ginContext, http.StatusNotFound, errorCodeGrantNotFound, "grant not found")
+		return
+	}
+	err = s.writeGrants(modelFolder, remaining)
+	if err != nil {
+		log.Println(err)
+		respondError(ginContext, http.StatusInternalServerError, errorCodeInternal, "unable to delete grant")
+		return
+	}
+	ginContext.JSON(http.StatusOK, gin.H{
+		"message": "grant deleted",
+	})
+}
+
+func (what grant) payload() payloadGrantInfo {
+	return payloadGrantInfo{Id: what.Id, Role: what.Role, Note: what.Note, CreatedAt: what.CreatedAt}
+}
+
+// readGrants answers the grants of the model, none if it has no grants file yet
+func (s *server) readGrants(modelFolder string) ([]grant, error) {
+	grants := make([]grant, 0)
+	data, err := s.storage.ReadFile(filepath.Join(modelFolder, grantsFilename))
+	if os.IsNotExist(err) {
+		return grants, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	err = json.Unmarshal(data, &grants)
+	return grants, err
+}
+
+func (s *server) writeGrants(modelFolder string, grants []grant) error {
+	data, err := json.Marshal(grants)
+	if err != nil {
+		return err
+	}
+	return s.storage.WriteFile(filepath.Join(modelFolder, grantsFilename), data)
+}
+
+// grantAuthorization lets requests sending a grant token instead of the token of the key pass as far as the role of the
+// grant allows the route, the handlers then take the grant token as key (see checkTokenToFolderName and model-key.go)
+func (s *server) grantAuthorization(route route) gin.HandlerFunc {
+	return func(ginContext *gin.Context) {
+		encodedToken := strings.TrimSpace(ginContext.GetHeader(grantTokenHeader))
+		if len(encodedToken) == 0 || len(ginContext.GetHeader("token")) > 0 {
+			return // the token of the key has all rights
+		}
+		if !route.grantable() {
+			respondError(ginContext, http.StatusForbidden, errorCodeForbidden, "grant tokens only allow the calls of their model")
+			return
+		}
+		modelId := ginContext.Param("model-id")
+		if len(modelId) == 0 { // the model of the analysis job
+			s.analysisJobsLock.Lock()
+			job, found := s.analysisJobs[ginContext.Param("analysis-job-id")]
+			s.analysisJobsLock.Unlock()
+			if found {
+				modelId = job.modelId
+			}
+		}
+		access, ok := s.checkGrantToken(modelId, encodedToken) // answering the same to unknown analysis jobs, not telling them
+		if !ok {
+			respondError(ginContext, http.StatusNotFound, errorCodeGrantNotFound, "grant token not found")
+			return
+		}
+		if access.role < route.requiredGrantRole() {
+			respondError(ginContext, http.StatusForbidden, errorCodeForbidden, "the "+grantRoleNames[access.role]+" role of the grant does not allow this call")
+			return
+		}
+		ginContext.Set(grantedAccessKey, access)
+	}
+}
+
+// checkGrantToken finds the grant of the model with the token
+func (s *server) checkGrantToken(modelId string, encodedToken string) (*grantedAccess, bool) {
+	modelUUID, err := uuid.Parse(modelId)
+	if err != nil {
+		return nil, false
+	}
+	token, err := base64.RawURLEncoding.DecodeString(encodedToken)
+	if len(token) != keySize || err != nil {
+		return nil, false
+	}
+
+	candidates, err := s.globKeyFolders(modelUUID.String(), grantsFilename)
+	if err != nil {
+		log.Println(err)
+		return nil, false
+	}
+	tokenHash := hashSHA256(token)
+	for _, candidate := range candidates {
+		grants, readError := s.readGrants(filepath.Dir(candidate))
+		if readError != nil {
+			continue
+		}
+		for _, stored := range grants {
+			role, known := grantRoleOfName(stored.Role)
+			if !known || subtle.ConstantTimeCompare([]byte(stored.Hash), []byte(tokenHash)) != 1 {
+				continue
+			}
+			return &grantedAccess{folderNameOfKey: filepath.Dir(filepath.Dir(candidate)), grantToken: token, modelId: modelUUID.String(), role: role}, true
+		}
+	}
+	return nil, false
+}
@@ -0,0 +1,138 @@
+package server
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGrants(t *testing.T) {
+	m := newModelTestServer(t, communicationLinkTestModel)
+	router := gin.New()
+	for _, route := range m.routes() {
+		if route.auth == tokenAuth {
+			router.Handle(route.method, route.path, m.grantAuthorization(route), route.handler)
+		}
+	}
+	call := func(method string, path string, header string, token string, payload any) (*httptest.ResponseRecorder, payloadError) {
+		var body bytes.Buffer
+		if payload != nil {
+			_ = json.NewEncoder(&body).Encode(payload)
+		}
+		request := httptest.NewRequest(method, path, &body)
+		request.Header.Set(header, token)
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, request)
+		var response payloadError
+		_ = json.Unmarshal(recorder.Body.Bytes(), &response)
+		return recorder, response
+	}
+	modelPath := "/models/" + m.modelID
+	createGrant := func(role string) payloadGrantInfo {
+		recorder, _ := call(http.MethodPost, modelPath+"/grants", "token", m.token, payloadGrant{Role: role, Note: "auditor"})
+		assert.Equal(t, http.StatusCreated, recorder.Code)
+		var created payloadGrantInfo
+		assert.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &created))
+		return created
+	}
+	reader, editor := createGrant("read"), createGrant("edit")
+
+	recorder, _ := call(http.MethodGet, modelPath+"/cover", grantTokenHeader, reader.GrantToken, nil)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	cover := payloadCover{Title: "Changed", Date: time.Now()}
+	recorder, response := call(http.MethodPut, modelPath+"/cover", grantTokenHeader, reader.GrantToken, cover)
+	assert.Equal(t, http.StatusForbidden, recorder.Code)
+	assert.Equal(t, errorCodeForbidden, response.Code)
+	recorder, _ = call(http.MethodPut, modelPath+"/cover", grantTokenHeader, editor.GrantToken, cover)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+
+	recorder, _ = call(http.MethodPost, modelPath+"/grants", grantTokenHeader, editor.GrantToken, payloadGrant{Role: "admin"})
+	assert.Equal(t, http.StatusForbidden, recorder.Code, "managing grants needs the admin role")
+	recorder, _ = call(http.MethodGet, "/models", grantTokenHeader, reader.GrantToken, nil)
+	assert.Equal(t, http.StatusForbidden, recorder.Code, "not a call of the model")
+	recorder, response = call(http.MethodGet, "/models/"+uuid.New().String()+"/cover", grantTokenHeader, reader.GrantToken, nil)
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+	assert.Equal(t, errorCodeGrantNotFound, response.Code)
+	recorder, _ = call(http.MethodPost, modelPath+"/grants", "token", m.token, payloadGrant{Role: "owner"})
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+
+	recorder, _ = call(http.MethodPost, modelPath+"/analysis-jobs", grantTokenHeader, reader.GrantToken, nil)
+	assert.Equal(t, http.StatusAccepted, recorder.Code)
+	var job payloadAnalysisJob
+	assert.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &job))
+	recorder, _ = call(http.MethodGet, "/analysis-jobs/"+job.Id, grantTokenHeader, reader.GrantToken, nil)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	_, unknownJob := call(http.MethodGet, "/analysis-jobs/"+uuid.New().String(), grantTokenHeader, reader.GrantToken, nil)
+	_, unknownToken := call(http.MethodGet, "/analysis-jobs/"+job.Id, grantTokenHeader, base64.RawURLEncoding.EncodeToString(make([]byte, keySize)), nil)
+	assert.Equal(t, unknownToken.Code, unknownJob.Code, "not telling whether the analysis job exists")
+	assert.Equal(t, unknownToken.Error, unknownJob.Error)
+
+	recorder, _ = call(http.MethodGet, modelPath+"/grants", "token", m.token, nil)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.NotContains(t, recorder.Body.String(), reader.GrantToken)
+	var grants []payloadGrantInfo
+	assert.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &grants))
+	assert.Len(t, grants, 2)
+
+	recorder, _ = call(http.MethodDelete, modelPath+"/grants/"+reader.Id, "token", m.token, nil)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	recorder, response = call(http.MethodGet, modelPath+"/cover", grantTokenHeader, reader.GrantToken, nil)
+	assert.Equal(t, http.StatusNotFound, recorder.Code, "revoked")
+	assert.Equal(t, errorCodeGrantNotFound, response.Code)
+	recorder, _ = call(http.MethodGet, modelPath+"/cover", grantTokenHeader, editor.GrantToken, nil)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	recorder, _ = call(http.MethodDelete, modelPath+"/grants/"+reader.Id, "token", m.token, nil)
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+}
+
+func TestGrantTokenUnwrapsModelKeyOnly(t *testing.T) {
+	m := newModelTestServer(t, communicationLinkTestModel)
+	ginContext, _ := gin.CreateTestContext(httptest.NewRecorder())
+	ginContext.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+	ginContext.Request.Header.Set("token", m.token)
+	_, key, ok := m.checkTokenToFolderName(ginContext)
+	assert.True(t, ok)
+
+	// a model stored before the models had keys of their own
+	_, yamlText, err := m.decryptModelFile(m.modelFolder, key)
+	assert.NoError(t, err)
+	var compressed bytes.Buffer
+	writer := gzip.NewWriter(&compressed)
+	_, _ = writer.Write([]byte(yamlText))
+	assert.NoError(t, writer.Close())
+	legacy, err := encryptWithKey(m.cryptoKey(key), compressed.Bytes())
+	assert.NoError(t, err)
+	assert.NoError(t, m.storage.WriteFile(filepath.Join(m.modelFolder, m.config.InputFile), legacy))
+	_, _, err = m.decryptModelFile(m.modelFolder, key)
+	assert.NoError(t, err)
+
+	recorder := m.call(m.createGrant, http.MethodPost, nil, payloadGrant{Role: "read"})
+	assert.Equal(t, http.StatusCreated, recorder.Code)
+	var created payloadGrantInfo
+	assert.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &created))
+	grantToken, err := base64.RawURLEncoding.DecodeString(created.GrantToken)
+	assert.NoError(t, err)
+	_, grantYamlText, err := m.decryptModelFile(m.modelFolder, grantToken)
+	assert.NoError(t, err, "migrated to the key of the model")
+	assert.Equal(t, yamlText, grantYamlText)
+
+	stored, err := m.storage.ReadFile(filepath.Join(m.modelFolder, grantsFilename))
+	assert.NoError(t, err)
+	var grants []grant
+	assert.NoError(t, json.Unmarshal(stored, &grants))
+	unwrapped, err := decryptWithKey(generateKeyFromAlreadyStrongRandomInput(grantToken), grants[0].WrappedKey)
+	assert.NoError(t, err)
+	assert.NotEqual(t, m.cryptoKey(key), unwrapped, "the key of the model, not the one of the workspace")
+	_, err = decryptWithKey(unwrapped, legacy)
+	assert.Error(t, err)
+}
@@ -0,0 +1,137 @@
+package server
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"regexp"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	idempotencyKeyHeader     = "Idempotency-Key"
+	idempotentReplayedHeader = "Idempotent-Replayed"
+	idempotencyKeyRetention  = 24 * time.Hour
+	maxIdempotentResponses   = 10000 // kept at most, the oldest ones are evicted first
+)
+
+// idempotencyKeyPattern is what an idempotency key has to look like, e.g. a uuid or the id of a CI job
+var idempotencyKeyPattern = regexp.MustCompile(`^[\x21-\x7e]{1,255}$`)
+
+// idempotentResponse is the response to a request with an idempotency key, replayed when the request is retried
+type idempotentResponse struct {
+	requestHash     string // of the method, path, query and payload, as the key must not be reused for another request
+	done            bool   // false while the first request is still being handled
+	status          int
+	contentType     string
+	body            []byte
+	createdNanoTime int64
+}
+
+// idempotency is the middleware of the creation routes answering a retried request (with the same header
+// Idempotency-Key of the same auth key) with the response of the first one instead of creating another object, only
+// successful responses are kept (for 24 hours, or until evicted by newer ones), so that failed requests (and the ones
+// whose handler panicked) can be retried
+func (s *server) idempotency(ginContext *gin.Context) {
+	idempotencyKey := ginContext.GetHeader(idempotencyKeyHeader)
+	if len(idempotencyKey) == 0 {
+		return
+	}
+	if !idempotencyKeyPattern.MatchString(idempotencyKey) {
+		respondError(ginContext, http.StatusBadRequest, errorCodeBadRequest, "invalid idempotency key: up to 255 printable ascii characters expected")
+		return
+	}
+	folderNameOfKey, _, ok := s.checkTokenToFolderName(ginContext)
+	if !ok {
+		return
+	}
+	body, err := io.ReadAll(ginContext.Request.Body)
+	if err != nil {
+		respondError(ginContext, http.StatusBadRequest, errorCodeInvalidPayload, "unable to read request payload")
+		return
+	}
+	ginContext.Request.Body = io.NopCloser(bytes.NewReader(body)) // for the binding of the handler
+	requestHash := hashSHA256(append([]byte(ginContext.Request.Method+" "+ginContext.Request.URL.RequestURI()+"\n"), body...))
+	storeKey := hashSHA256([]byte(folderNameOfKey + "\n" + idempotencyKey))
+
+	s.idempotencyLock.Lock()
+	s.housekeepingIdempotentResponses()
+	response, exists := s.idempotentResponses[storeKey]
+	createdNanoTime := time.Now().UnixNano()
+	if !exists {
+		s.idempotentResponses[storeKey] = &idempotentResponse{requestHash: requestHash, createdNanoTime: createdNanoTime}
+	}
+	s.idempotencyLock.Unlock()
+	if exists {
+		switch {
+		case response.requestHash != requestHash:
+			respondError(ginContext, http.StatusUnprocessableEntity, errorCodeIdempotencyKeyReused, "idempotency key already used for another request")
+		case !response.done:
+			respondError(ginContext, http.StatusConflict, errorCodeConflict, "request with the same idempotency key still in progress: please try again later")
+		default:
+			ginContext.Header(idempotentReplayedHeader, "true")
+			ginContext.Data(response.status, response.contentType, response.body)
+			ginContext.Abort()
+		}
+		return
+	}
+
+	writer := &recordingResponseWriter{ResponseWriter: ginContext.Writer}
+	ginContext.Writer = writer
+	handled := false
+	defer func() { // also when the handler panics, so that the request is not in progress forever
+		s.idempotencyLock.Lock()
+		defer s.idempotencyLock.Unlock()
+		status := writer.Status()
+		if !handled || status < http.StatusOK || status >= http.StatusMultipleChoices {
+			delete(s.idempotentResponses, storeKey)
+			return
+		}
+		s.idempotentResponses[storeKey] = &idempotentResponse{
+			requestHash:     requestHash,
+			done:            true,
+			status:          status,
+			contentType:     writer.Header().Get("Content-Type"),
+			body:            writer.body.Bytes(),
+			createdNanoTime: createdNanoTime,
+		}
+	}()
+	ginContext.Next()
+	handled = true
+}
+
+func (s *server) housekeepingIdempotentResponses() {
+	cutoff := time.Now().Add(-idempotencyKeyRetention).UnixNano()
+	for storeKey, response := range s.idempotentResponses {
+		if response.createdNanoTime < cutoff {
+			delete(s.idempotentResponses, storeKey)
+		}
+	}
+	for len(s.idempotentResponses) >= maxIdempotentResponses {
+		oldestKey, oldestNanoTime := "", int64(0)
+		for storeKey, response := range s.idempotentResponses {
+			if len(oldestKey) == 0 || response.createdNanoTime < oldestNanoTime {
+				oldestKey, oldestNanoTime = storeKey, response.createdNanoTime
+			}
+		}
+		delete(s.idempotentResponses, oldestKey)
+	}
+}
+
+// recordingResponseWriter keeps a copy of the response body written
+type recordingResponseWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (what *recordingResponseWriter) Write(data []byte) (int, error) {
+	what.body.Write(data)
+	return what.ResponseWriter.Write(data)
+}
+
+func (what *recordingResponseWriter) WriteString(data string) (int, error) {
+	what.body.WriteString(data)
+	return what.ResponseWriter.WriteString(data)
+}
@@ -0,0 +1,99 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/threagile/threagile/pkg/input"
+)
+
+func TestIdempotentCreation(t *testing.T) {
+	m := newModelTestServer(t, communicationLinkTestModel)
+	router := gin.New()
+	router.POST("/models/:model-id/shared-runtimes", m.idempotency, m.createNewSharedRuntime)
+	post := func(idempotencyKey string, payload payloadSharedRuntime) (*httptest.ResponseRecorder, payloadError) {
+		body, _ := json.Marshal(payload)
+		request := httptest.NewRequest(http.MethodPost, "/models/"+m.modelID+"/shared-runtimes", bytes.NewReader(body))
+		request.Header.Set("token", m.token)
+		if len(idempotencyKey) > 0 {
+			request.Header.Set(idempotencyKeyHeader, idempotencyKey)
+		}
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, request)
+		var response payloadError
+		_ = json.Unmarshal(recorder.Body.Bytes(), &response)
+		return recorder, response
+	}
+	cluster := payloadSharedRuntime{Title: "Cluster", SharedRuntime: input.SharedRuntime{ID: "cluster"}}
+
+	first, _ := post("ci-job-42", cluster)
+	assert.Equal(t, http.StatusOK, first.Code)
+	retry, _ := post("ci-job-42", cluster)
+	assert.Equal(t, http.StatusOK, retry.Code, "instead of a conflict")
+	assert.Equal(t, "true", retry.Header().Get(idempotentReplayedHeader))
+	assert.Equal(t, first.Body.String(), retry.Body.String())
+
+	reused, response := post("ci-job-42", payloadSharedRuntime{Title: "Other", SharedRuntime: input.SharedRuntime{ID: "other"}})
+	assert.Equal(t, http.StatusUnprocessableEntity, reused.Code)
+	assert.Equal(t, errorCodeIdempotencyKeyReused, response.Code)
+
+	withoutKey, _ := post("", cluster)
+	assert.Equal(t, http.StatusConflict, withoutKey.Code)
+	failed, _ := post("ci-job-43", cluster)
+	assert.Equal(t, http.StatusConflict, failed.Code)
+	failed, _ = post("ci-job-43", cluster)
+	assert.Empty(t, failed.Header().Get(idempotentReplayedHeader), "failures are not kept")
+
+	invalid, response := post("not valid", cluster)
+	assert.Equal(t, http.StatusBadRequest, invalid.Code)
+	assert.Equal(t, errorCodeBadRequest, response.Code)
+}
+
+func TestIdempotencyReleasesPanickedRequests(t *testing.T) {
+	m := newModelTestServer(t, communicationLinkTestModel)
+	router := gin.New()
+	router.Use(gin.CustomRecovery(func(ginContext *gin.Context, _ any) {
+		ginContext.AbortWithStatus(http.StatusInternalServerError)
+	}))
+	panicking := true
+	router.POST("/models/:model-id/shared-runtimes", m.idempotency, func(ginContext *gin.Context) {
+		if panicking {
+			panic("crashed")
+		}
+		ginContext.JSON(http.StatusOK, gin.H{"query": ginContext.Query("q")})
+	})
+	post := func(query string) *httptest.ResponseRecorder {
+		request := httptest.NewRequest(http.MethodPost, "/models/"+m.modelID+"/shared-runtimes"+query, nil)
+		request.Header.Set("token", m.token)
+		request.Header.Set(idempotencyKeyHeader, "ci-job-42")
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, request)
+		return recorder
+	}
+
+	assert.Equal(t, http.StatusInternalServerError, post("").Code)
+	panicking = false
+	assert.Equal(t, http.StatusOK, post("?q=1").Code, "retried instead of in progress")
+	assert.Equal(t, http.StatusUnprocessableEntity, post("?q=2").Code, "another query is another request")
+	replayed := post("?q=1")
+	assert.Equal(t, http.StatusOK, replayed.Code)
+	assert.Equal(t, "true", replayed.Header().Get(idempotentReplayedHeader))
+}
+
+func TestIdempotentResponsesAreEvicted(t *testing.T) {
+	s := &server{idempotentResponses: make(map[string]*idempotentResponse)}
+	for i := 0; i < maxIdempotentResponses; i++ {
+		s.idempotentResponses[strconv.Itoa(i)] = &idempotentResponse{done: true, createdNanoTime: time.Now().UnixNano() + int64(i)}
+	}
+	s.housekeepingIdempotentResponses()
+	assert.Len(t, s.idempotentResponses, maxIdempotentResponses-1)
+	assert.NotContains(t, s.idempotentResponses, "0", "the oldest one")
+	assert.Contains(t, s.idempotentResponses, "1")
+}
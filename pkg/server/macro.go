@@ -0,0 +1,183 @@
+package server
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/threagile/threagile/pkg/input"
+	"github.com/threagile/threagile/pkg/macros"
+	"github.com/threagile/threagile/pkg/model"
+	"github.com/threagile/threagile/pkg/security/risks"
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+// payloadMacroAnswers are the answers given so far (keyed by question ID); as the server keeps no state of the macros
+// between requests, clients send all of them with each request
+type payloadMacroAnswers struct {
+	Answers macros.MacroAnswers `json:"answers"`
+}
+
+type payloadMacroNextQuestion struct {
+	Question *payloadMacroQuestion `json:"question,omitempty"` // none when all questions are answered
+	Changes  []string              `json:"changes,omitempty"`  // the changes the execution would apply, once all questions are answered
+	Message  string                `json:"message,omitempty"`
+}
+
+type payloadMacroExecution struct {
+	Message string   `json:"message"`
+	Changes []string `json:"changes"`
+}
+
+func (s *server) listModelMacros(ginContext *gin.Context) {
+	folderNameOfKey, key, ok := s.checkTokenToFolderName(ginContext)
+	if !ok {
+		return
+	}
+	s.lockFolder(folderNameOfKey)
+	modelInput, _, ok := s.readModel(ginContext, ginContext.Param("model-id"), key, folderNameOfKey)
+	s.unlockFolder(folderNameOfKey) // the questions are listed for the model read, not blocking other calls meanwhile
+	if !ok {
+		return
+	}
+	parsedModel, err := s.parseModelForMacro(&modelInput)
+	if err != nil {
+		handleChangeError(err, ginContext)
+		return
+	}
+	result := make([]payloadModelMacro, 0)
+	for _, macro := range macros.ListServerMacros() {
+		questions, err := macros.DefaultQuestions(macro, parsedModel)
+		if err != nil {
+			handleErrorInServiceCall(err, ginContext)
+			return
+		}
+		result = append(result, newPayloadModelMacro(macro.GetMacroDetails(), questions))
+	}
+	ginContext.JSON(http.StatusOK, result)
+}
+
+func (s *server) nextMacroQuestion(ginContext *gin.Context) {
+	folderNameOfKey, key, ok := s.checkTokenToFolderName(ginContext)
+	if !ok {
+		return
+	}
+	s.lockFolder(folderNameOfKey)
+	defer s.unlockFolder(folderNameOfKey)
+	modelInput, _, ok := s.readModel(ginContext, ginContext.Param("model-id"), key, folderNameOfKey)
+	if ok {
+		response, err := s.macroNextQuestion(&modelInput, ginContext.Params, ginContext.BindJSON)
+		if err != nil {
+			handleChangeError(err, ginContext)
+			return
+		}
+		ginContext.JSON(http.StatusOK, response)
+	}
+}
+
+// macroNextQuestion replays the answers and answers the next question of the macro, or the changes its execution would
+// apply once all questions are answered, without changing the model
+func (s *server) macroNextQuestion(modelInput *input.Model, params gin.Params, bindPayload func(payload any) error) (payloadMacroNextQuestion, error) {
+	macro, parsedModel, answers, err := s.prepareMacro(modelInput, params, bindPayload)
+	if err != nil {
+		return payloadMacroNextQuestion{}, err
+	}
+	nextQuestion, err := macros.ApplyAnswers(macro, parsedModel, answers)
+	if err != nil {
+		return payloadMacroNextQuestion{}, requestError{status: http.StatusBadRequest, message: err.Error()}
+	}
+	if !nextQuestion.NoMoreQuestions() {
+		question := newPayloadMacroQuestion(nextQuestion)
+		return payloadMacroNextQuestion{Question: &question}, nil
+	}
+	changes, message, validResult, err := macro.GetFinalChangeImpact(modelInput, parsedModel)
+	if err != nil {
+		return payloadMacroNextQuestion{}, err
+	}
+	if !validResult {
+		return payloadMacroNextQuestion{}, requestError{status: http.StatusBadRequest, message: "invalid changes of model macro: " + message}
+	}
+	return payloadMacroNextQuestion{Changes: changes, Message: message}, nil
+}
+
+func (s *server) executeModelMacro(ginContext *gin.Context) {
+	s.changeModel(ginContext, "Model Macro Execution: "+ginContext.Param("macro-id"), s.macroExecution)
+}
+
+// macroExecution answers the questions of the macro, falling back to their default answers, and applies it to the model
+func (s *server) macroExecution(modelInput *input.Model, params gin.Params, bindPayload func(payload any) error) (gin.H, error) {
+	macro, parsedModel, answers, err := s.prepareMacro(modelInput, params, bindPayload)
+	if err != nil {
+		return nil, err
+	}
+	err = macros.ApplyAnswersOrDefaults(macro, parsedModel, answers)
+	if err != nil {
+		return nil, requestError{status: http.StatusBadRequest, message: err.Error()}
+	}
+	changes, message, validResult, err := macro.GetFinalChangeImpact(modelInput, parsedModel)
+	if err != nil {
+		return nil, err
+	}
+	if !validResult {
+		return nil, requestError{status: http.StatusBadRequest, message: "invalid changes of model macro: " + message}
+	}
+	message, validResult, err = macro.Execute(modelInput, parsedModel)
+	if err != nil {
+		return nil, err
+	}
+	if !validResult {
+		return nil, requestError{status: http.StatusBadRequest, message: "model macro failed: " + message}
+	}
+	return gin.H{
+		"message": message,
+		"changes": changes,
+	}, nil
+}
+
+// prepareMacro creates a new instance of the macro of the request (as the macros are stateful) for the parsed model and
+// reads the answers given
+func (s *server) prepareMacro(modelInput *input.Model, params gin.Params, bindPayload func(payload any) error) (macros.Macros, *types.Model, macros.MacroAnswers, error) {
+	macro, err := macros.GetServerMacroByID(params.ByName("macro-id"))
+	if err != nil {
+		return nil, nil, nil, requestError{status: http.StatusNotFound, message: "model macro not found"}
+	}
+	payload := payloadMacroAnswers{}
+	err = bindPayload(&payload)
+	if err != nil {
+		log.Println(err)
+		return nil, nil, nil, errUnparsablePayload
+	}
+	parsedModel, err := s.parseModelForMacro(modelInput)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return macro, parsedModel, payload.Answers, nil
+}
+
+// parseModelForMacro parses the model the macros ask their questions for, which must be valid
+func (s *server) parseModelForMacro(modelInput *input.Model) (*types.Model, error) {
+	parsedModel, err := model.ParseModel(s.config, modelInput, risks.GetBuiltInRiskRules(), s.customRiskRules)
+	if err != nil {
+		return nil, requestError{status: http.StatusUnprocessableEntity, message: "invalid model: " + err.Error()}
+	}
+	return parsedModel, nil
+}
+
+func newPayloadModelMacro(details macros.MacroDetails, questions []macros.MacroQuestion) payloadModelMacro {
+	payload := payloadModelMacro{ID: details.ID, Title: details.Title, Description: details.Description, Questions: make([]payloadMacroQuestion, 0)}
+	for _, question := range questions {
+		payload.Questions = append(payload.Questions, newPayloadMacroQuestion(question))
+	}
+	return payload
+}
+
+func newPayloadMacroQuestion(question macros.MacroQuestion) payloadMacroQuestion {
+	return payloadMacroQuestion{
+		ID:              question.ID,
+		Title:           question.Title,
+		Description:     question.Description,
+		PossibleAnswers: question.PossibleAnswers,
+		MultiSelect:     question.MultiSelect,
+		DefaultAnswer:   question.DefaultAnswer,
+	}
+}
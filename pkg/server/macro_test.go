@@ -0,0 +1,58 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/threagile/threagile/pkg/macros"
+)
+
+func TestModelMacroExecution(t *testing.T) {
+	example, err := os.ReadFile(filepath.Join("..", "..", "demo", "example", "threagile.yaml"))
+	assert.NoError(t, err)
+	m := newModelTestServer(t, string(example))
+	recorder := m.call(m.listModelMacros, http.MethodGet, nil, nil)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), `"add-vault"`)
+	assert.NotContains(t, recorder.Body.String(), `"add-k8s-workloads"`, "reading local files")
+
+	vaultParams := gin.Params{{Key: "macro-id", Value: "add-vault"}}
+	answers := payloadMacroAnswers{Answers: macros.MacroAnswers{"vault-name": {"HashiCorp"}}}
+	recorder = m.call(m.nextMacroQuestion, http.MethodPost, vaultParams, answers)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	var next payloadMacroNextQuestion
+	assert.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &next))
+	assert.Equal(t, "storage-type", next.Question.ID)
+
+	answers.Answers["storage-type"] = []string{"unknown"}
+	recorder = m.call(m.nextMacroQuestion, http.MethodPost, vaultParams, answers)
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+
+	answers.Answers["storage-type"] = []string{"in-memory (no persistent storage of secrets)"}
+	answers.Answers["authentication-type"] = []string{"Certificate"}
+	answers.Answers["clients"] = []string{"apache-webserver"}
+	answers.Answers["within-trust-boundary"] = []string{"No"}
+	recorder = m.call(m.nextMacroQuestion, http.MethodPost, vaultParams, answers)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	next = payloadMacroNextQuestion{}
+	assert.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &next))
+	if assert.NotNil(t, next.Question) {
+		assert.Equal(t, "multi-tenant", next.Question.ID)
+	}
+
+	recorder = m.call(m.executeModelMacro, http.MethodPost, vaultParams, answers)
+	assert.Equal(t, http.StatusOK, recorder.Code, recorder.Body.String())
+	recorder = m.call(m.getTechnicalAsset, http.MethodGet, gin.Params{{Key: "technical-asset-id", Value: "hashicorp-vault"}}, nil)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+
+	recorder = m.call(m.executeModelMacro, http.MethodPost, gin.Params{{Key: "macro-id", Value: "unknown"}}, answers)
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+	k8sAnswers := payloadMacroAnswers{Answers: macros.MacroAnswers{"manifests": {"/"}}}
+	recorder = m.call(m.nextMacroQuestion, http.MethodPost, gin.Params{{Key: "macro-id", Value: "add-k8s-workloads"}}, k8sAnswers)
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+}
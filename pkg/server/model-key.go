@@ -0,0 +1,143 @@
+package server
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Each model has a key of its own its stored data is encrypted with: the holders of the key of the workspace derive it
+// from the crypto key of the workspace key and the model id, the holders of a grant token (or of the share token)
+// unwrap it from the grant. So these tokens never give access to the workspace key (nor to the other models of the
+// workspace). Data stored before the models had keys of their own is still encrypted with the crypto key of the
+// workspace key and re-encrypted with the key of the model when a token is created for it (see migrateModelKey).
+
+// modelFolderOf answers the model folder of the folder, which is the model folder itself or a folder below it (like
+// the ones of the edit sessions)
+func (s *server) modelFolderOf(folder string) (string, error) {
+	for _, root := range s.keyFolderRoots() {
+		relative, err := filepath.Rel(root, folder)
+		if err != nil || relative == "." || strings.HasPrefix(relative, "..") {
+			continue
+		}
+		parts := strings.Split(relative, string(filepath.Separator))
+		if len(parts) >= 2 {
+			return filepath.Join(root, parts[0], parts[1]), nil
+		}
+	}
+	return "", fmt.Errorf("no model folder: %v", folder)
+}
+
+// modelCryptoKey answers the key of the model the folder belongs to, for the key of its workspace or a grant token (or
+// the share token) of it
+func (s *server) modelCryptoKey(folder string, key []byte) ([]byte, error) {
+	modelFolder, err := s.modelFolderOf(folder)
+	if err != nil {
+		return nil, err
+	}
+	if s.folderNameFromKey(key) == filepath.Dir(modelFolder) {
+		mac := hmac.New(sha256.New, s.cryptoKey(key))
+		mac.Write([]byte(filepath.Base(modelFolder)))
+		return mac.Sum(nil), nil
+	}
+	grants, err := s.readGrants(modelFolder)
+	if err != nil {
+		return nil, err
+	}
+	wrappedKeys := make([]shareToken, 0, len(grants)+1)
+	for _, stored := range grants {
+		wrappedKeys = append(wrappedKeys, shareToken{Hash: stored.Hash, WrappedKey: stored.WrappedKey})
+	}
+	data, err := s.storage.ReadFile(filepath.Join(modelFolder, shareTokenFilename))
+	if err == nil {
+		var stored shareToken
+		if json.Unmarshal(data, &stored) == nil {
+			wrappedKeys = append(wrappedKeys, stored)
+		}
+	}
+	tokenHash := hashSHA256(key)
+	for _, wrapped := range wrappedKeys {
+		if subtle.ConstantTimeCompare([]byte(wrapped.Hash), []byte(tokenHash)) == 1 {
+			return decryptWithKey(generateKeyFromAlreadyStrongRandomInput(key), wrapped.WrappedKey)
+		}
+	}
+	return nil, fmt.Errorf("no key of model %v", filepath.Base(modelFolder))
+}
+
+// encryptModelData encrypts data stored in the folder of a model (or below it) with the key of the model
+func (s *server) encryptModelData(folder string, key []byte, plaintext []byte) ([]byte, error) {
+	cryptoKey, err := s.modelCryptoKey(folder, key)
+	if err != nil {
+		return nil, err
+	}
+	return encryptWithKey(cryptoKey, plaintext)
+}
+
+// decryptModelData decrypts data stored in the folder of a model (or below it), falling back to the crypto key of the
+// workspace key for data stored before the model had a key of its own
+func (s *server) decryptModelData(folder string, key []byte, data []byte) ([]byte, error) {
+	cryptoKey, err := s.modelCryptoKey(folder, key)
+	if err != nil {
+		return nil, err
+	}
+	plaintext, err := decryptWithKey(cryptoKey, data)
+	if err != nil && s.isWorkspaceKeyOf(folder, key) {
+		return decryptWithKey(s.cryptoKey(key), data)
+	}
+	return plaintext, err
+}
+
+func (s *server) isWorkspaceKeyOf(folder string, key []byte) bool {
+	modelFolder, err := s.modelFolderOf(folder)
+	return err == nil && s.folderNameFromKey(key) == filepath.Dir(modelFolder)
+}
+
+// migrateModelKey re-encrypts the data of the model still encrypted with the crypto key of the workspace key with the
+// key of the model, so that grant tokens can decrypt all of it
+func (s *server) migrateModelKey(modelFolder string, key []byte) error {
+	if !s.isWorkspaceKeyOf(modelFolder, key) {
+		return nil // a grant is only created for migrated models
+	}
+	cryptoKey, err := s.modelCryptoKey(modelFolder, key)
+	if err != nil {
+		return err
+	}
+	files := []string{filepath.Join(modelFolder, s.config.InputFile), filepath.Join(modelFolder, riskCommentsFilename), filepath.Join(modelFolder, webhookRisksFilename)}
+	for _, pattern := range []string{filepath.Join(editSessionsFolder, "*", s.config.InputFile), filepath.Join("history", "*.backup"), filepath.Join("results", "*.result")} {
+		matches, err := s.storage.Glob(filepath.Join(modelFolder, pattern))
+		if err != nil {
+			return err
+		}
+		files = append(files, matches...)
+	}
+	for _, file := range files {
+		data, err := s.storage.ReadFile(file)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if _, err = decryptWithKey(cryptoKey, data); err == nil {
+			continue // already migrated
+		}
+		plaintext, err := decryptWithKey(s.cryptoKey(key), data)
+		if err != nil {
+			return fmt.Errorf("unable to migrate %v: %w", file, err)
+		}
+		data, err = encryptWithKey(cryptoKey, plaintext)
+		if err != nil {
+			return err
+		}
+		err = s.storage.WriteFile(file, data)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -7,15 +7,14 @@ package server
 import (
 	"bytes"
 	"compress/gzip"
-	"crypto/aes"
-	"crypto/cipher"
-	"crypto/rand"
+	"context"
+	"errors"
 	"fmt"
-	"io"
 	"log"
 	"net/http"
 	"os"
 	"path/filepath"
+	"slices"
 	"sort"
 	"strconv"
 	"strings"
@@ -26,6 +25,7 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"github.com/threagile/threagile/pkg/common"
 	"github.com/threagile/threagile/pkg/docs"
 	"github.com/threagile/threagile/pkg/input"
 	"github.com/threagile/threagile/pkg/security/types"
@@ -44,17 +44,37 @@ func (s *server) createNewModel(ginContext *gin.Context) {
 	}
 	s.lockFolder(folderNameOfKey)
 	defer s.unlockFolder(folderNameOfKey)
+	if !s.checkModelQuota(ginContext, folderNameOfKey) {
+		return
+	}
+
+	aYaml, changeReason := newModelYAML, "New Model Creation"
+	if templateId := ginContext.Query("template"); len(templateId) > 0 {
+		templateYaml, ok := s.readTemplate(ginContext, templateId)
+		if !ok {
+			return
+		}
+		aYaml, changeReason = string(templateYaml), "New Model Creation from Template "+templateId
+	}
 
 	aUuid := uuid.New().String()
-	err := os.Mkdir(folderNameForModel(folderNameOfKey, aUuid), 0700)
+	err := s.storage.Mkdir(folderNameForModel(folderNameOfKey, aUuid))
 	if err != nil {
-		ginContext.JSON(http.StatusInternalServerError, gin.H{
-			"error": "unable to create model",
-		})
+		respondError(ginContext, http.StatusInternalServerError, errorCodeInternal, "unable to create model")
 		return
 	}
 
-	aYaml := `title: New Threat Model
+	ok = s.writeModelYAML(ginContext, aYaml, key, folderNameForModel(folderNameOfKey, aUuid), changeReason, true)
+	if ok {
+		ginContext.JSON(http.StatusCreated, gin.H{
+			"message": "model created",
+			"id":      aUuid,
+		})
+	}
+}
+
+// newModelYAML is the model file of models not created from a template
+var newModelYAML = `title: New Threat Model
 threagile_version: ` + docs.ThreagileVersion + `
 author:
   name: ""
@@ -85,67 +105,126 @@ diagram_tweak_suppress_edge_labels: false
 diagram_tweak_invisible_connections_between_assets: []
 diagram_tweak_same_rank_assets: []`
 
-	ok = s.writeModelYAML(ginContext, aYaml, key, folderNameForModel(folderNameOfKey, aUuid), "New Model Creation", true)
-	if ok {
-		ginContext.JSON(http.StatusCreated, gin.H{
-			"message": "model created",
-			"id":      aUuid,
-		})
-	}
-}
-
 type payloadModels struct {
 	ID                string    `yaml:"id" json:"id"`
 	Title             string    `yaml:"title" json:"title"`
 	TimestampCreated  time.Time `yaml:"timestamp_created" json:"timestamp_created"`
 	TimestampModified time.Time `yaml:"timestamp_modified" json:"timestamp_modified"`
+	Error             string    `yaml:"error,omitempty" json:"error,omitempty"` // when the model can't be read (anymore)
 }
 
-func (s *server) listModels(ginContext *gin.Context) { // TODO currently returns error when any model is no longer valid in syntax, so eventually have some fallback to not just bark on an invalid model...
+const (
+	totalCountHeader = "X-Total-Count"
+
+	sortByModified = "modified"
+	sortByCreated  = "created"
+	sortByTitle    = "title"
+)
+
+// listModels lists the models of the key, newest modified first unless sorted otherwise, a page of them when limited
+// (with the number of all models in the header X-Total-Count), models which can't be read are listed with an error
+func (s *server) listModels(ginContext *gin.Context) {
 	folderNameOfKey, key, ok := s.checkTokenToFolderName(ginContext)
 	if !ok {
 		return
 	}
+	sortBy := ginContext.DefaultQuery("sort", sortByModified)
+	if sortBy != sortByModified && sortBy != sortByCreated && sortBy != sortByTitle {
+		respondError(ginContext, http.StatusBadRequest, errorCodeBadRequest, "sort must be modified, created or title")
+		return
+	}
+	defaultOrder := "desc"
+	if sortBy == sortByTitle {
+		defaultOrder = "asc"
+	}
+	order := ginContext.DefaultQuery("order", defaultOrder)
+	if order != "asc" && order != "desc" {
+		respondError(ginContext, http.StatusBadRequest, errorCodeBadRequest, "order must be asc or desc")
+		return
+	}
+	offset, err := strconv.Atoi(ginContext.DefaultQuery("offset", "0"))
+	if err != nil || offset < 0 {
+		respondError(ginContext, http.StatusBadRequest, errorCodeBadRequest, "offset must be zero or a positive number")
+		return
+	}
+	limit := -1
+	if limitParam, limited := ginContext.GetQuery("limit"); limited {
+		limit, err = strconv.Atoi(limitParam)
+		if err != nil || limit < 1 {
+			respondError(ginContext, http.StatusBadRequest, errorCodeBadRequest, "limit must be a positive number")
+			return
+		}
+	}
 	s.lockFolder(folderNameOfKey)
 	defer s.unlockFolder(folderNameOfKey)
 
-	result := make([]payloadModels, 0)
-	modelFolders, err := os.ReadDir(folderNameOfKey)
+	modelFolders, err := s.storage.ReadDir(folderNameOfKey)
 	if err != nil {
 		log.Println(err)
-		ginContext.JSON(http.StatusNotFound, gin.H{
-			"error": "token not found",
-		})
+		respondError(ginContext, http.StatusNotFound, errorCodeTokenNotFound, "token not found")
 		return
 	}
-	for _, dirEntry := range modelFolders {
-		if dirEntry.IsDir() {
-			modelStat, err := os.Stat(filepath.Join(folderNameOfKey, dirEntry.Name(), s.config.InputFile))
-			if err != nil {
-				log.Println(err)
-				ginContext.JSON(http.StatusNotFound, gin.H{
-					"error": "unable to list model",
-				})
-				return
-			}
-			aModel, _, ok := s.readModel(ginContext, dirEntry.Name(), key, folderNameOfKey)
-			if !ok {
-				return
-			}
-			fileInfo, err := dirEntry.Info()
-			if err != nil {
-				log.Println(err)
-				ginContext.JSON(http.StatusNotFound, gin.H{
-					"error": "unable to get file info",
-				})
-				return
-			}
-			result = append(result, payloadModels{
-				ID:                dirEntry.Name(),
-				Title:             aModel.Title,
-				TimestampCreated:  fileInfo.ModTime(),
-				TimestampModified: modelStat.ModTime(),
-			})
+	result := make([]payloadModels, 0)
+	for _, fileInfo := range modelFolders {
+		if !fileInfo.IsDir() {
+			continue
+		}
+		listed := payloadModels{ID: fileInfo.Name(), TimestampCreated: fileInfo.ModTime(), TimestampModified: fileInfo.ModTime()}
+		modelStat, err := s.storage.Stat(filepath.Join(folderNameOfKey, fileInfo.Name(), s.config.InputFile))
+		if err != nil {
+			log.Println(err)
+			listed.Error = "model file not found"
+		} else {
+			listed.TimestampModified = modelStat.ModTime()
+		}
+		result = append(result, listed)
+	}
+
+	// only the titles of the listed page are read, unless sorted by them
+	readTitle := func(listed *payloadModels) {
+		if len(listed.Error) > 0 {
+			return
+		}
+		aModel, _, err := s.decryptModelFile(filepath.Join(folderNameOfKey, listed.ID), key)
+		if err != nil {
+			log.Println(err)
+			listed.Error = "unable to open model"
+			return
+		}
+		listed.Title = aModel.Title
+	}
+	if sortBy == sortByTitle {
+		for i := range result {
+			readTitle(&result[i])
+		}
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		a, b := result[i], result[j]
+		if order == "desc" {
+			a, b = b, a
+		}
+		switch {
+		case sortBy == sortByTitle && a.Title != b.Title:
+			return a.Title < b.Title
+		case sortBy == sortByCreated && !a.TimestampCreated.Equal(b.TimestampCreated):
+			return a.TimestampCreated.Before(b.TimestampCreated)
+		case sortBy == sortByModified && !a.TimestampModified.Equal(b.TimestampModified):
+			return a.TimestampModified.Before(b.TimestampModified)
+		}
+		return a.ID < b.ID
+	})
+
+	ginContext.Header(totalCountHeader, strconv.Itoa(len(result)))
+	if offset > len(result) {
+		offset = len(result)
+	}
+	result = result[offset:]
+	if limit >= 0 && limit < len(result) {
+		result = result[:limit]
+	}
+	if sortBy != sortByTitle {
+		for i := range result {
+			readTitle(&result[i])
 		}
 	}
 	ginContext.JSON(http.StatusOK, result)
@@ -161,16 +240,12 @@ func (s *server) deleteModel(ginContext *gin.Context) {
 	folder, ok := s.checkModelFolder(ginContext, ginContext.Param("model-id"), folderNameOfKey)
 	if ok {
 		if folder != filepath.Clean(folder) {
-			ginContext.JSON(http.StatusInternalServerError, gin.H{
-				"error": "model-id is weird",
-			})
+			respondError(ginContext, http.StatusInternalServerError, errorCodeInternal, "model-id is weird")
 			return
 		}
-		err := os.RemoveAll(folder)
+		err := s.storage.RemoveAll(folder)
 		if err != nil {
-			ginContext.JSON(http.StatusNotFound, gin.H{
-				"error": "model not found",
-			})
+			respondError(ginContext, http.StatusNotFound, errorCodeModelNotFound, "model not found")
 			return
 		}
 		ginContext.JSON(http.StatusOK, gin.H{
@@ -186,35 +261,24 @@ type payloadCover struct {
 }
 
 func (s *server) setCover(ginContext *gin.Context) {
-	folderNameOfKey, key, ok := s.checkTokenToFolderName(ginContext)
-	if !ok {
-		return
+	s.changeModel(ginContext, "Cover Update", coverUpdate)
+}
+
+func coverUpdate(modelInput *input.Model, _ gin.Params, bindPayload func(payload any) error) (gin.H, error) {
+	payload := payloadCover{}
+	err := bindPayload(&payload)
+	if err != nil {
+		return nil, errUnparsablePayload
 	}
-	s.lockFolder(folderNameOfKey)
-	defer s.unlockFolder(folderNameOfKey)
-	modelInput, _, ok := s.readModel(ginContext, ginContext.Param("model-id"), key, folderNameOfKey)
-	if ok {
-		payload := payloadCover{}
-		err := ginContext.BindJSON(&payload)
-		if err != nil {
-			ginContext.JSON(http.StatusBadRequest, gin.H{
-				"error": "unable to parse request payload",
-			})
-			return
-		}
-		modelInput.Title = payload.Title
-		if !payload.Date.IsZero() {
-			modelInput.Date = payload.Date.Format("2006-01-02")
-		}
-		modelInput.Author.Name = payload.Author.Name
-		modelInput.Author.Homepage = payload.Author.Homepage
-		ok = s.writeModel(ginContext, key, folderNameOfKey, &modelInput, "Cover Update")
-		if ok {
-			ginContext.JSON(http.StatusOK, gin.H{
-				"message": "model updated",
-			})
-		}
+	modelInput.Title = payload.Title
+	if !payload.Date.IsZero() {
+		modelInput.Date = payload.Date.Format("2006-01-02")
 	}
+	modelInput.Author.Name = payload.Author.Name
+	modelInput.Author.Homepage = payload.Author.Homepage
+	return gin.H{
+		"message": "model updated",
+	}, nil
 }
 
 func (s *server) getCover(ginContext *gin.Context) {
@@ -242,41 +306,29 @@ type payloadOverview struct {
 }
 
 func (s *server) setOverview(ginContext *gin.Context) {
-	folderNameOfKey, key, ok := s.checkTokenToFolderName(ginContext)
-	if !ok {
-		return
-	}
-	s.lockFolder(folderNameOfKey)
-	defer s.unlockFolder(folderNameOfKey)
-	modelInput, _, ok := s.readModel(ginContext, ginContext.Param("model-id"), key, folderNameOfKey)
-	if ok {
-		payload := payloadOverview{}
-		err := ginContext.BindJSON(&payload)
-		if err != nil {
-			log.Println(err)
-			ginContext.JSON(http.StatusBadRequest, gin.H{
-				"error": "unable to parse request payload",
-			})
-			return
-		}
-		criticality, err := types.ParseCriticality(payload.BusinessCriticality)
-		if err != nil {
-			handleErrorInServiceCall(err, ginContext)
-			return
-		}
-		modelInput.ManagementSummaryComment = payload.ManagementSummaryComment
-		modelInput.BusinessCriticality = criticality.String()
-		modelInput.BusinessOverview.Description = payload.BusinessOverview.Description
-		modelInput.BusinessOverview.Images = payload.BusinessOverview.Images
-		modelInput.TechnicalOverview.Description = payload.TechnicalOverview.Description
-		modelInput.TechnicalOverview.Images = payload.TechnicalOverview.Images
-		ok = s.writeModel(ginContext, key, folderNameOfKey, &modelInput, "Overview Update")
-		if ok {
-			ginContext.JSON(http.StatusOK, gin.H{
-				"message": "model updated",
-			})
-		}
+	s.changeModel(ginContext, "Overview Update", overviewUpdate)
+}
+
+func overviewUpdate(modelInput *input.Model, _ gin.Params, bindPayload func(payload any) error) (gin.H, error) {
+	payload := payloadOverview{}
+	err := bindPayload(&payload)
+	if err != nil {
+		log.Println(err)
+		return nil, errUnparsablePayload
 	}
+	criticality, err := types.ParseCriticality(payload.BusinessCriticality)
+	if err != nil {
+		return nil, err
+	}
+	modelInput.ManagementSummaryComment = payload.ManagementSummaryComment
+	modelInput.BusinessCriticality = criticality.String()
+	modelInput.BusinessOverview.Description = payload.BusinessOverview.Description
+	modelInput.BusinessOverview.Images = payload.BusinessOverview.Images
+	modelInput.TechnicalOverview.Description = payload.TechnicalOverview.Description
+	modelInput.TechnicalOverview.Images = payload.TechnicalOverview.Images
+	return gin.H{
+		"message": "model updated",
+	}, nil
 }
 
 func (s *server) getOverview(ginContext *gin.Context) {
@@ -300,34 +352,55 @@ func (s *server) getOverview(ginContext *gin.Context) {
 type payloadAbuseCases map[string]string
 
 func (s *server) setAbuseCases(ginContext *gin.Context) {
+	s.changeModel(ginContext, "Abuse Cases Update", abuseCasesUpdate)
+}
+
+func abuseCasesUpdate(modelInput *input.Model, _ gin.Params, bindPayload func(payload any) error) (gin.H, error) {
+	payload := payloadAbuseCases{}
+	err := bindPayload(&payload)
+	if err != nil {
+		log.Println(err)
+		return nil, errUnparsablePayload
+	}
+	modelInput.AbuseCases = payload
+	return gin.H{
+		"message": "model updated",
+	}, nil
+}
+
+func (s *server) getAbuseCases(ginContext *gin.Context) {
 	folderNameOfKey, key, ok := s.checkTokenToFolderName(ginContext)
 	if !ok {
 		return
 	}
 	s.lockFolder(folderNameOfKey)
 	defer s.unlockFolder(folderNameOfKey)
-	modelInput, _, ok := s.readModel(ginContext, ginContext.Param("model-id"), key, folderNameOfKey)
+	aModel, _, ok := s.readModel(ginContext, ginContext.Param("model-id"), key, folderNameOfKey)
 	if ok {
-		payload := payloadAbuseCases{}
-		err := ginContext.BindJSON(&payload)
-		if err != nil {
-			log.Println(err)
-			ginContext.JSON(http.StatusBadRequest, gin.H{
-				"error": "unable to parse request payload",
-			})
-			return
-		}
-		modelInput.AbuseCases = payload
-		ok = s.writeModel(ginContext, key, folderNameOfKey, &modelInput, "Abuse Cases Update")
-		if ok {
-			ginContext.JSON(http.StatusOK, gin.H{
-				"message": "model updated",
-			})
-		}
+		ginContext.JSON(http.StatusOK, aModel.AbuseCases)
 	}
 }
 
-func (s *server) getAbuseCases(ginContext *gin.Context) {
+type payloadSecurityRequirements map[string]string
+
+func (s *server) setSecurityRequirements(ginContext *gin.Context) {
+	s.changeModel(ginContext, "Security Requirements Update", securityRequirementsUpdate)
+}
+
+func securityRequirementsUpdate(modelInput *input.Model, _ gin.Params, bindPayload func(payload any) error) (gin.H, error) {
+	payload := payloadSecurityRequirements{}
+	err := bindPayload(&payload)
+	if err != nil {
+		log.Println(err)
+		return nil, errUnparsablePayload
+	}
+	modelInput.SecurityRequirements = payload
+	return gin.H{
+		"message": "model updated",
+	}, nil
+}
+
+func (s *server) getSecurityRequirements(ginContext *gin.Context) {
 	folderNameOfKey, key, ok := s.checkTokenToFolderName(ginContext)
 	if !ok {
 		return
@@ -336,41 +409,157 @@ func (s *server) getAbuseCases(ginContext *gin.Context) {
 	defer s.unlockFolder(folderNameOfKey)
 	aModel, _, ok := s.readModel(ginContext, ginContext.Param("model-id"), key, folderNameOfKey)
 	if ok {
-		ginContext.JSON(http.StatusOK, aModel.AbuseCases)
+		ginContext.JSON(http.StatusOK, aModel.SecurityRequirements)
 	}
 }
 
-type payloadSecurityRequirements map[string]string
+// payloadQuestions are the answers to the questions of the model by question (empty if not answered yet)
+type payloadQuestions map[string]string
 
-func (s *server) setSecurityRequirements(ginContext *gin.Context) {
+func (s *server) setQuestions(ginContext *gin.Context) {
+	s.changeModel(ginContext, "Questions Update", questionsUpdate)
+}
+
+func questionsUpdate(modelInput *input.Model, _ gin.Params, bindPayload func(payload any) error) (gin.H, error) {
+	payload := payloadQuestions{}
+	err := bindPayload(&payload)
+	if err != nil {
+		log.Println(err)
+		return nil, errUnparsablePayload
+	}
+	modelInput.Questions = payload
+	return gin.H{
+		"message": "model updated",
+	}, nil
+}
+
+func (s *server) getQuestions(ginContext *gin.Context) {
 	folderNameOfKey, key, ok := s.checkTokenToFolderName(ginContext)
 	if !ok {
 		return
 	}
 	s.lockFolder(folderNameOfKey)
 	defer s.unlockFolder(folderNameOfKey)
-	modelInput, _, ok := s.readModel(ginContext, ginContext.Param("model-id"), key, folderNameOfKey)
+	aModel, _, ok := s.readModel(ginContext, ginContext.Param("model-id"), key, folderNameOfKey)
 	if ok {
-		payload := payloadSecurityRequirements{}
-		err := ginContext.BindJSON(&payload)
+		ginContext.JSON(http.StatusOK, aModel.Questions)
+	}
+}
+
+// payloadTags are the tags available in the model (the tags_available of the model file)
+type payloadTags []string
+
+func (s *server) setTags(ginContext *gin.Context) {
+	s.changeModel(ginContext, "Tags Update", tagsUpdate)
+}
+
+// tagsUpdate replaces the available tags, which have to include the ones used on the model elements
+func tagsUpdate(modelInput *input.Model, _ gin.Params, bindPayload func(payload any) error) (gin.H, error) {
+	payload := payloadTags{}
+	err := bindPayload(&payload)
+	if err != nil {
+		log.Println(err)
+		return nil, errUnparsablePayload
+	}
+	tags := make([]string, 0, len(payload))
+	for _, tag := range lowerCaseAndTrim(payload) {
+		if len(tag) > 0 && !slices.Contains(tags, tag) {
+			tags = append(tags, tag)
+		}
+	}
+	check := *modelInput
+	check.TagsAvailable = slices.Clone(tags)
+	if missing := check.SeedTagsAvailable(); len(missing) > 0 {
+		return nil, requestError{status: http.StatusConflict, message: "tags still used by model elements: " + strings.Join(missing, ", ")}
+	}
+	modelInput.TagsAvailable = tags
+	return gin.H{
+		"message": "model updated",
+	}, nil
+}
+
+func (s *server) getTags(ginContext *gin.Context) {
+	folderNameOfKey, key, ok := s.checkTokenToFolderName(ginContext)
+	if !ok {
+		return
+	}
+	s.lockFolder(folderNameOfKey)
+	defer s.unlockFolder(folderNameOfKey)
+	aModel, _, ok := s.readModel(ginContext, ginContext.Param("model-id"), key, folderNameOfKey)
+	if ok {
+		tags := aModel.TagsAvailable
+		if tags == nil {
+			tags = make([]string, 0)
+		}
+		ginContext.JSON(http.StatusOK, tags)
+	}
+}
+
+// payloadAnnotations are the review notes attached to the elements of the model
+type payloadAnnotations []input.ElementAnnotation
+
+func (s *server) setAnnotations(ginContext *gin.Context) {
+	s.changeModel(ginContext, "Annotations Update", annotationsUpdate)
+}
+
+func annotationsUpdate(modelInput *input.Model, _ gin.Params, bindPayload func(payload any) error) (gin.H, error) {
+	payload := payloadAnnotations{}
+	err := bindPayload(&payload)
+	if err != nil {
+		log.Println(err)
+		return nil, errUnparsablePayload
+	}
+	for i, annotation := range payload {
+		payload[i], err = populateAnnotation(annotation)
 		if err != nil {
-			log.Println(err)
-			ginContext.JSON(http.StatusBadRequest, gin.H{
-				"error": "unable to parse request payload",
-			})
-			return
+			return nil, err
 		}
-		modelInput.SecurityRequirements = payload
-		ok = s.writeModel(ginContext, key, folderNameOfKey, &modelInput, "Security Requirements Update")
-		if ok {
-			ginContext.JSON(http.StatusOK, gin.H{
-				"message": "model updated",
-			})
+	}
+	modelInput.Annotations = payload
+	return gin.H{
+		"message": "model updated",
+	}, nil
+}
+
+func (s *server) createNewAnnotation(ginContext *gin.Context) {
+	s.changeModel(ginContext, "Annotation Creation", annotationCreation)
+}
+
+// annotationCreation adds a review note to the model, dated today unless the payload has a date
+func annotationCreation(modelInput *input.Model, _ gin.Params, bindPayload func(payload any) error) (gin.H, error) {
+	payload := input.ElementAnnotation{}
+	err := bindPayload(&payload)
+	if err != nil {
+		log.Println(err)
+		return nil, errUnparsablePayload
+	}
+	if len(payload.Date) == 0 {
+		payload.Date = time.Now().Format("2006-01-02")
+	}
+	annotation, err := populateAnnotation(payload)
+	if err != nil {
+		return nil, err
+	}
+	modelInput.Annotations = append(modelInput.Annotations, annotation)
+	return gin.H{
+		"message": "annotation created",
+	}, nil
+}
+
+func populateAnnotation(payload input.ElementAnnotation) (input.ElementAnnotation, error) {
+	payload.Element = strings.TrimSpace(payload.Element)
+	if len(payload.Element) == 0 {
+		return payload, requestError{status: http.StatusBadRequest, message: "annotation without element"}
+	}
+	if len(payload.Date) > 0 {
+		if _, err := time.Parse("2006-01-02", payload.Date); err != nil {
+			return payload, requestError{status: http.StatusBadRequest, message: fmt.Sprintf("unable to parse date of annotation (expected format: '2006-01-02'): %v", payload.Date)}
 		}
 	}
+	return payload, nil
 }
 
-func (s *server) getSecurityRequirements(ginContext *gin.Context) {
+func (s *server) getAnnotations(ginContext *gin.Context) {
 	folderNameOfKey, key, ok := s.checkTokenToFolderName(ginContext)
 	if !ok {
 		return
@@ -379,23 +568,18 @@ func (s *server) getSecurityRequirements(ginContext *gin.Context) {
 	defer s.unlockFolder(folderNameOfKey)
 	aModel, _, ok := s.readModel(ginContext, ginContext.Param("model-id"), key, folderNameOfKey)
 	if ok {
-		ginContext.JSON(http.StatusOK, aModel.SecurityRequirements)
+		annotations := aModel.Annotations
+		if annotations == nil {
+			annotations = make([]input.ElementAnnotation, 0)
+		}
+		ginContext.JSON(http.StatusOK, annotations)
 	}
 }
 
+// payloadDataAsset is the data asset of the model file with its title, as the model file keys the data assets by title
 type payloadDataAsset struct {
-	Title                  string   `yaml:"title" json:"title"`
-	Id                     string   `yaml:"id" json:"id"`
-	Description            string   `yaml:"description" json:"description"`
-	Usage                  string   `yaml:"usage" json:"usage"`
-	Tags                   []string `yaml:"tags" json:"tags"`
-	Origin                 string   `yaml:"origin" json:"origin"`
-	Owner                  string   `yaml:"owner" json:"owner"`
-	Quantity               string   `yaml:"quantity" json:"quantity"`
-	Confidentiality        string   `yaml:"confidentiality" json:"confidentiality"`
-	Integrity              string   `yaml:"integrity" json:"integrity"`
-	Availability           string   `yaml:"availability" json:"availability"`
-	JustificationCiaRating string   `yaml:"justification_cia_rating" json:"justification_cia_rating"`
+	Title           string `yaml:"title" json:"title" binding:"required"`
+	input.DataAsset `yaml:",inline"`
 }
 
 func (s *server) getDataAssets(ginContext *gin.Context) {
@@ -429,301 +613,247 @@ func (s *server) getDataAsset(ginContext *gin.Context) {
 				return
 			}
 		}
-		ginContext.JSON(http.StatusNotFound, gin.H{
-			"error": "data asset not found",
-		})
+		respondError(ginContext, http.StatusNotFound, errorCodeNotFound, "data asset not found")
 	}
 }
 
 func (s *server) deleteDataAsset(ginContext *gin.Context) {
-	folderNameOfKey, key, ok := s.checkTokenToFolderName(ginContext)
-	if !ok {
-		return
-	}
-	s.lockFolder(folderNameOfKey)
-	defer s.unlockFolder(folderNameOfKey)
-	modelInput, _, ok := s.readModel(ginContext, ginContext.Param("model-id"), key, folderNameOfKey)
-	if ok {
-		referencesDeleted := false
-		// yes, here keyed by title in YAML for better readability in the YAML file itself
-		for title, dataAsset := range modelInput.DataAssets {
-			if dataAsset.ID == ginContext.Param("data-asset-id") {
-				// also remove all usages of this data asset !!
-				for _, techAsset := range modelInput.TechnicalAssets {
-					if techAsset.DataAssetsProcessed != nil {
-						for i, parsedChangeCandidateAsset := range techAsset.DataAssetsProcessed {
-							referencedAsset := fmt.Sprintf("%v", parsedChangeCandidateAsset)
+	s.changeModel(ginContext, "Data Asset Deletion", dataAssetDeletion)
+}
+
+func dataAssetDeletion(modelInput *input.Model, params gin.Params, _ func(payload any) error) (gin.H, error) {
+	referencesDeleted := false
+	// yes, here keyed by title in YAML for better readability in the YAML file itself
+	for title, dataAsset := range modelInput.DataAssets {
+		if dataAsset.ID == params.ByName("data-asset-id") {
+			// also remove all usages of this data asset !!
+			for _, techAsset := range modelInput.TechnicalAssets {
+				if techAsset.DataAssetsProcessed != nil {
+					for i, parsedChangeCandidateAsset := range techAsset.DataAssetsProcessed {
+						referencedAsset := fmt.Sprintf("%v", parsedChangeCandidateAsset)
+						if referencedAsset == dataAsset.ID { // apply the removal
+							referencesDeleted = true
+							// Remove the element at index i
+							// TODO needs more testing
+							copy(techAsset.DataAssetsProcessed[i:], techAsset.DataAssetsProcessed[i+1:])                         // Shift a[i+1:] left one index.
+							techAsset.DataAssetsProcessed[len(techAsset.DataAssetsProcessed)-1] = ""                             // Erase last element (write zero value).
+							techAsset.DataAssetsProcessed = techAsset.DataAssetsProcessed[:len(techAsset.DataAssetsProcessed)-1] // Truncate slice.
+						}
+					}
+				}
+				if techAsset.DataAssetsStored != nil {
+					for i, parsedChangeCandidateAsset := range techAsset.DataAssetsStored {
+						referencedAsset := fmt.Sprintf("%v", parsedChangeCandidateAsset)
+						if referencedAsset == dataAsset.ID { // apply the removal
+							referencesDeleted = true
+							// Remove the element at index i
+							// TODO needs more testing
+							copy(techAsset.DataAssetsStored[i:], techAsset.DataAssetsStored[i+1:])                      // Shift a[i+1:] left one index.
+							techAsset.DataAssetsStored[len(techAsset.DataAssetsStored)-1] = ""                          // Erase last element (write zero value).
+							techAsset.DataAssetsStored = techAsset.DataAssetsStored[:len(techAsset.DataAssetsStored)-1] // Truncate slice.
+						}
+					}
+				}
+				if techAsset.CommunicationLinks != nil {
+					for title, commLink := range techAsset.CommunicationLinks {
+						for i, dataAssetSent := range commLink.DataAssetsSent {
+							referencedAsset := fmt.Sprintf("%v", dataAssetSent)
 							if referencedAsset == dataAsset.ID { // apply the removal
 								referencesDeleted = true
 								// Remove the element at index i
 								// TODO needs more testing
-								copy(techAsset.DataAssetsProcessed[i:], techAsset.DataAssetsProcessed[i+1:])                         // Shift a[i+1:] left one index.
-								techAsset.DataAssetsProcessed[len(techAsset.DataAssetsProcessed)-1] = ""                             // Erase last element (write zero value).
-								techAsset.DataAssetsProcessed = techAsset.DataAssetsProcessed[:len(techAsset.DataAssetsProcessed)-1] // Truncate slice.
+								copy(techAsset.CommunicationLinks[title].DataAssetsSent[i:], techAsset.CommunicationLinks[title].DataAssetsSent[i+1:]) // Shift a[i+1:] left one index.
+								techAsset.CommunicationLinks[title].DataAssetsSent[len(techAsset.CommunicationLinks[title].DataAssetsSent)-1] = ""     // Erase last element (write zero value).
+								x := techAsset.CommunicationLinks[title]
+								x.DataAssetsSent = techAsset.CommunicationLinks[title].DataAssetsSent[:len(techAsset.CommunicationLinks[title].DataAssetsSent)-1] // Truncate slice.
+								techAsset.CommunicationLinks[title] = x
 							}
 						}
-					}
-					if techAsset.DataAssetsStored != nil {
-						for i, parsedChangeCandidateAsset := range techAsset.DataAssetsStored {
-							referencedAsset := fmt.Sprintf("%v", parsedChangeCandidateAsset)
+						for i, dataAssetReceived := range commLink.DataAssetsReceived {
+							referencedAsset := fmt.Sprintf("%v", dataAssetReceived)
 							if referencedAsset == dataAsset.ID { // apply the removal
 								referencesDeleted = true
 								// Remove the element at index i
 								// TODO needs more testing
-								copy(techAsset.DataAssetsStored[i:], techAsset.DataAssetsStored[i+1:])                      // Shift a[i+1:] left one index.
-								techAsset.DataAssetsStored[len(techAsset.DataAssetsStored)-1] = ""                          // Erase last element (write zero value).
-								techAsset.DataAssetsStored = techAsset.DataAssetsStored[:len(techAsset.DataAssetsStored)-1] // Truncate slice.
-							}
-						}
-					}
-					if techAsset.CommunicationLinks != nil {
-						for title, commLink := range techAsset.CommunicationLinks {
-							for i, dataAssetSent := range commLink.DataAssetsSent {
-								referencedAsset := fmt.Sprintf("%v", dataAssetSent)
-								if referencedAsset == dataAsset.ID { // apply the removal
-									referencesDeleted = true
-									// Remove the element at index i
-									// TODO needs more testing
-									copy(techAsset.CommunicationLinks[title].DataAssetsSent[i:], techAsset.CommunicationLinks[title].DataAssetsSent[i+1:]) // Shift a[i+1:] left one index.
-									techAsset.CommunicationLinks[title].DataAssetsSent[len(techAsset.CommunicationLinks[title].DataAssetsSent)-1] = ""     // Erase last element (write zero value).
-									x := techAsset.CommunicationLinks[title]
-									x.DataAssetsSent = techAsset.CommunicationLinks[title].DataAssetsSent[:len(techAsset.CommunicationLinks[title].DataAssetsSent)-1] // Truncate slice.
-									techAsset.CommunicationLinks[title] = x
-								}
-							}
-							for i, dataAssetReceived := range commLink.DataAssetsReceived {
-								referencedAsset := fmt.Sprintf("%v", dataAssetReceived)
-								if referencedAsset == dataAsset.ID { // apply the removal
-									referencesDeleted = true
-									// Remove the element at index i
-									// TODO needs more testing
-									copy(techAsset.CommunicationLinks[title].DataAssetsReceived[i:], techAsset.CommunicationLinks[title].DataAssetsReceived[i+1:]) // Shift a[i+1:] left one index.
-									techAsset.CommunicationLinks[title].DataAssetsReceived[len(techAsset.CommunicationLinks[title].DataAssetsReceived)-1] = ""     // Erase last element (write zero value).
-									x := techAsset.CommunicationLinks[title]
-									x.DataAssetsReceived = techAsset.CommunicationLinks[title].DataAssetsReceived[:len(techAsset.CommunicationLinks[title].DataAssetsReceived)-1] // Truncate slice.
-									techAsset.CommunicationLinks[title] = x
-								}
+								copy(techAsset.CommunicationLinks[title].DataAssetsReceived[i:], techAsset.CommunicationLinks[title].DataAssetsReceived[i+1:]) // Shift a[i+1:] left one index.
+								techAsset.CommunicationLinks[title].DataAssetsReceived[len(techAsset.CommunicationLinks[title].DataAssetsReceived)-1] = ""     // Erase last element (write zero value).
+								x := techAsset.CommunicationLinks[title]
+								x.DataAssetsReceived = techAsset.CommunicationLinks[title].DataAssetsReceived[:len(techAsset.CommunicationLinks[title].DataAssetsReceived)-1] // Truncate slice.
+								techAsset.CommunicationLinks[title] = x
 							}
 						}
 					}
 				}
-				for _, individualRiskCat := range modelInput.CustomRiskCategories {
-					if individualRiskCat.RisksIdentified != nil {
-						for individualRiskInstanceTitle, individualRiskInstance := range individualRiskCat.RisksIdentified {
-							if individualRiskInstance.MostRelevantDataAsset == dataAsset.ID { // apply the removal
-								referencesDeleted = true
-								x := individualRiskCat.RisksIdentified[individualRiskInstanceTitle]
-								x.MostRelevantDataAsset = "" // TODO needs more testing
-								individualRiskCat.RisksIdentified[individualRiskInstanceTitle] = x
-							}
+			}
+			for _, individualRiskCat := range modelInput.CustomRiskCategories {
+				if individualRiskCat.RisksIdentified != nil {
+					for individualRiskInstanceTitle, individualRiskInstance := range individualRiskCat.RisksIdentified {
+						if individualRiskInstance.MostRelevantDataAsset == dataAsset.ID { // apply the removal
+							referencesDeleted = true
+							x := individualRiskCat.RisksIdentified[individualRiskInstanceTitle]
+							x.MostRelevantDataAsset = "" // TODO needs more testing
+							individualRiskCat.RisksIdentified[individualRiskInstanceTitle] = x
 						}
 					}
 				}
-				// remove it itself
-				delete(modelInput.DataAssets, title)
-				ok = s.writeModel(ginContext, key, folderNameOfKey, &modelInput, "Data Asset Deletion")
-				if ok {
-					ginContext.JSON(http.StatusOK, gin.H{
-						"message":            "data asset deleted",
-						"id":                 dataAsset.ID,
-						"references_deleted": referencesDeleted, // in order to signal to clients, that other model parts might've been deleted as well
-					})
-				}
-				return
 			}
+			// remove it itself
+			delete(modelInput.DataAssets, title)
+			return gin.H{
+				"message":            "data asset deleted",
+				"id":                 dataAsset.ID,
+				"references_deleted": referencesDeleted, // in order to signal to clients, that other model parts might've been deleted as well
+			}, nil
 		}
-		ginContext.JSON(http.StatusNotFound, gin.H{
-			"error": "data asset not found",
-		})
 	}
+	return nil, requestError{status: http.StatusNotFound, message: "data asset not found"}
 }
 
 func (s *server) setDataAsset(ginContext *gin.Context) {
-	folderNameOfKey, key, ok := s.checkTokenToFolderName(ginContext)
-	if !ok {
-		return
-	}
-	s.lockFolder(folderNameOfKey)
-	defer s.unlockFolder(folderNameOfKey)
-	modelInput, _, ok := s.readModel(ginContext, ginContext.Param("model-id"), key, folderNameOfKey)
-	if ok {
-		// yes, here keyed by title in YAML for better readability in the YAML file itself
-		for title, dataAsset := range modelInput.DataAssets {
-			if dataAsset.ID == ginContext.Param("data-asset-id") {
-				payload := payloadDataAsset{}
-				err := ginContext.BindJSON(&payload)
-				if err != nil {
-					log.Println(err)
-					ginContext.JSON(http.StatusBadRequest, gin.H{
-						"error": "unable to parse request payload",
-					})
-					return
-				}
-				dataAssetInput, ok := s.populateDataAsset(ginContext, payload)
-				if !ok {
-					return
-				}
-				// in order to also update the title, remove the asset from the map and re-insert it (with new key)
-				delete(modelInput.DataAssets, title)
-				modelInput.DataAssets[payload.Title] = dataAssetInput
-				idChanged := dataAssetInput.ID != dataAsset.ID
-				if idChanged { // ID-CHANGE-PROPAGATION
-					// also update all usages to point to the new (changed) ID !!
-					for techAssetTitle, techAsset := range modelInput.TechnicalAssets {
-						if techAsset.DataAssetsProcessed != nil {
-							for i, parsedChangeCandidateAsset := range techAsset.DataAssetsProcessed {
-								referencedAsset := fmt.Sprintf("%v", parsedChangeCandidateAsset)
-								if referencedAsset == dataAsset.ID { // apply the ID change
-									modelInput.TechnicalAssets[techAssetTitle].DataAssetsProcessed[i] = dataAssetInput.ID
-								}
+	s.changeModel(ginContext, "Data Asset Update", dataAssetUpdate)
+}
+
+func dataAssetUpdate(modelInput *input.Model, params gin.Params, bindPayload func(payload any) error) (gin.H, error) {
+	// yes, here keyed by title in YAML for better readability in the YAML file itself
+	for title, dataAsset := range modelInput.DataAssets {
+		if dataAsset.ID == params.ByName("data-asset-id") {
+			payload := payloadDataAsset{}
+			err := bindPayload(&payload)
+			if err != nil {
+				log.Println(err)
+				return nil, errUnparsablePayload
+			}
+			dataAssetInput, err := populateDataAsset(payload)
+			if err != nil {
+				return nil, err
+			}
+			// in order to also update the title, remove the asset from the map and re-insert it (with new key)
+			delete(modelInput.DataAssets, title)
+			modelInput.DataAssets[payload.Title] = dataAssetInput
+			idChanged := dataAssetInput.ID != dataAsset.ID
+			if idChanged { // ID-CHANGE-PROPAGATION
+				// also update all usages to point to the new (changed) ID !!
+				for techAssetTitle, techAsset := range modelInput.TechnicalAssets {
+					if techAsset.DataAssetsProcessed != nil {
+						for i, parsedChangeCandidateAsset := range techAsset.DataAssetsProcessed {
+							referencedAsset := fmt.Sprintf("%v", parsedChangeCandidateAsset)
+							if referencedAsset == dataAsset.ID { // apply the ID change
+								modelInput.TechnicalAssets[techAssetTitle].DataAssetsProcessed[i] = dataAssetInput.ID
+							}
+						}
+					}
+					if techAsset.DataAssetsStored != nil {
+						for i, parsedChangeCandidateAsset := range techAsset.DataAssetsStored {
+							referencedAsset := fmt.Sprintf("%v", parsedChangeCandidateAsset)
+							if referencedAsset == dataAsset.ID { // apply the ID change
+								modelInput.TechnicalAssets[techAssetTitle].DataAssetsStored[i] = dataAssetInput.ID
 							}
 						}
-						if techAsset.DataAssetsStored != nil {
-							for i, parsedChangeCandidateAsset := range techAsset.DataAssetsStored {
-								referencedAsset := fmt.Sprintf("%v", parsedChangeCandidateAsset)
+					}
+					if techAsset.CommunicationLinks != nil {
+						for title, commLink := range techAsset.CommunicationLinks {
+							for i, dataAssetSent := range commLink.DataAssetsSent {
+								referencedAsset := fmt.Sprintf("%v", dataAssetSent)
 								if referencedAsset == dataAsset.ID { // apply the ID change
-									modelInput.TechnicalAssets[techAssetTitle].DataAssetsStored[i] = dataAssetInput.ID
+									modelInput.TechnicalAssets[techAssetTitle].CommunicationLinks[title].DataAssetsSent[i] = dataAssetInput.ID
 								}
 							}
-						}
-						if techAsset.CommunicationLinks != nil {
-							for title, commLink := range techAsset.CommunicationLinks {
-								for i, dataAssetSent := range commLink.DataAssetsSent {
-									referencedAsset := fmt.Sprintf("%v", dataAssetSent)
-									if referencedAsset == dataAsset.ID { // apply the ID change
-										modelInput.TechnicalAssets[techAssetTitle].CommunicationLinks[title].DataAssetsSent[i] = dataAssetInput.ID
-									}
-								}
-								for i, dataAssetReceived := range commLink.DataAssetsReceived {
-									referencedAsset := fmt.Sprintf("%v", dataAssetReceived)
-									if referencedAsset == dataAsset.ID { // apply the ID change
-										modelInput.TechnicalAssets[techAssetTitle].CommunicationLinks[title].DataAssetsReceived[i] = dataAssetInput.ID
-									}
+							for i, dataAssetReceived := range commLink.DataAssetsReceived {
+								referencedAsset := fmt.Sprintf("%v", dataAssetReceived)
+								if referencedAsset == dataAsset.ID { // apply the ID change
+									modelInput.TechnicalAssets[techAssetTitle].CommunicationLinks[title].DataAssetsReceived[i] = dataAssetInput.ID
 								}
 							}
 						}
 					}
-					for _, individualRiskCat := range modelInput.CustomRiskCategories {
-						if individualRiskCat.RisksIdentified != nil {
-							for individualRiskInstanceTitle, individualRiskInstance := range individualRiskCat.RisksIdentified {
-								if individualRiskInstance.MostRelevantDataAsset == dataAsset.ID { // apply the ID change
-									x := individualRiskCat.RisksIdentified[individualRiskInstanceTitle]
-									x.MostRelevantDataAsset = dataAssetInput.ID // TODO needs more testing
-									individualRiskCat.RisksIdentified[individualRiskInstanceTitle] = x
-								}
+				}
+				for _, individualRiskCat := range modelInput.CustomRiskCategories {
+					if individualRiskCat.RisksIdentified != nil {
+						for individualRiskInstanceTitle, individualRiskInstance := range individualRiskCat.RisksIdentified {
+							if individualRiskInstance.MostRelevantDataAsset == dataAsset.ID { // apply the ID change
+								x := individualRiskCat.RisksIdentified[individualRiskInstanceTitle]
+								x.MostRelevantDataAsset = dataAssetInput.ID // TODO needs more testing
+								individualRiskCat.RisksIdentified[individualRiskInstanceTitle] = x
 							}
 						}
 					}
 				}
-				ok = s.writeModel(ginContext, key, folderNameOfKey, &modelInput, "Data Asset Update")
-				if ok {
-					ginContext.JSON(http.StatusOK, gin.H{
-						"message":    "data asset updated",
-						"id":         dataAssetInput.ID,
-						"id_changed": idChanged, // in order to signal to clients, that other model parts might've received updates as well and should be reloaded
-					})
-				}
-				return
+				replaceElementIdInRiskTrackingAndAnnotations(modelInput, dataAsset.ID, dataAssetInput.ID, false)
 			}
+			return gin.H{
+				"message":    "data asset updated",
+				"id":         dataAssetInput.ID,
+				"id_changed": idChanged, // in order to signal to clients, that other model parts might've received updates as well and should be reloaded
+			}, nil
 		}
-		ginContext.JSON(http.StatusNotFound, gin.H{
-			"error": "data asset not found",
-		})
 	}
+	return nil, requestError{status: http.StatusNotFound, message: "data asset not found"}
 }
 
 func (s *server) createNewDataAsset(ginContext *gin.Context) {
-	folderNameOfKey, key, ok := s.checkTokenToFolderName(ginContext)
-	if !ok {
-		return
+	s.changeModel(ginContext, "Data Asset Creation", dataAssetCreation)
+}
+
+func dataAssetCreation(modelInput *input.Model, _ gin.Params, bindPayload func(payload any) error) (gin.H, error) {
+	payload := payloadDataAsset{}
+	err := bindPayload(&payload)
+	if err != nil {
+		log.Println(err)
+		return nil, errUnparsablePayload
 	}
-	s.lockFolder(folderNameOfKey)
-	defer s.unlockFolder(folderNameOfKey)
-	modelInput, _, ok := s.readModel(ginContext, ginContext.Param("model-id"), key, folderNameOfKey)
-	if ok {
-		payload := payloadDataAsset{}
-		err := ginContext.BindJSON(&payload)
-		if err != nil {
-			log.Println(err)
-			ginContext.JSON(http.StatusBadRequest, gin.H{
-				"error": "unable to parse request payload",
-			})
-			return
-		}
-		// yes, here keyed by title in YAML for better readability in the YAML file itself
-		if _, exists := modelInput.DataAssets[payload.Title]; exists {
-			ginContext.JSON(http.StatusConflict, gin.H{
-				"error": "data asset with this title already exists",
-			})
-			return
-		}
-		// but later it will in memory keyed by its "id", so do this uniqueness check also
-		for _, asset := range modelInput.DataAssets {
-			if asset.ID == payload.Id {
-				ginContext.JSON(http.StatusConflict, gin.H{
-					"error": "data asset with this id already exists",
-				})
-				return
-			}
-		}
-		dataAssetInput, ok := s.populateDataAsset(ginContext, payload)
-		if !ok {
-			return
-		}
-		if modelInput.DataAssets == nil {
-			modelInput.DataAssets = make(map[string]input.DataAsset)
-		}
-		modelInput.DataAssets[payload.Title] = dataAssetInput
-		ok = s.writeModel(ginContext, key, folderNameOfKey, &modelInput, "Data Asset Creation")
-		if ok {
-			ginContext.JSON(http.StatusOK, gin.H{
-				"message": "data asset created",
-				"id":      dataAssetInput.ID,
-			})
+	// yes, here keyed by title in YAML for better readability in the YAML file itself
+	if _, exists := modelInput.DataAssets[payload.Title]; exists {
+		return nil, requestError{status: http.StatusConflict, message: "data asset with this title already exists"}
+	}
+	// but later it will in memory keyed by its "id", so do this uniqueness check also
+	for _, asset := range modelInput.DataAssets {
+		if asset.ID == payload.ID {
+			return nil, requestError{status: http.StatusConflict, message: "data asset with this id already exists"}
 		}
 	}
+	dataAssetInput, err := populateDataAsset(payload)
+	if err != nil {
+		return nil, err
+	}
+	if modelInput.DataAssets == nil {
+		modelInput.DataAssets = make(map[string]input.DataAsset)
+	}
+	modelInput.DataAssets[payload.Title] = dataAssetInput
+	return gin.H{
+		"message": "data asset created",
+		"id":      dataAssetInput.ID,
+	}, nil
 }
 
-func (s *server) populateDataAsset(ginContext *gin.Context, payload payloadDataAsset) (dataAssetInput input.DataAsset, ok bool) {
+func populateDataAsset(payload payloadDataAsset) (dataAssetInput input.DataAsset, err error) {
 	usage, err := types.ParseUsage(payload.Usage)
 	if err != nil {
-		handleErrorInServiceCall(err, ginContext)
-		return dataAssetInput, false
+		return dataAssetInput, err
 	}
 	quantity, err := types.ParseQuantity(payload.Quantity)
 	if err != nil {
-		handleErrorInServiceCall(err, ginContext)
-		return dataAssetInput, false
+		return dataAssetInput, err
 	}
 	confidentiality, err := types.ParseConfidentiality(payload.Confidentiality)
 	if err != nil {
-		handleErrorInServiceCall(err, ginContext)
-		return dataAssetInput, false
+		return dataAssetInput, err
 	}
 	integrity, err := types.ParseCriticality(payload.Integrity)
 	if err != nil {
-		handleErrorInServiceCall(err, ginContext)
-		return dataAssetInput, false
+		return dataAssetInput, err
 	}
 	availability, err := types.ParseCriticality(payload.Availability)
 	if err != nil {
-		handleErrorInServiceCall(err, ginContext)
-		return dataAssetInput, false
-	}
-	dataAssetInput = input.DataAsset{
-		ID:                     payload.Id,
-		Description:            payload.Description,
-		Usage:                  usage.String(),
-		Tags:                   lowerCaseAndTrim(payload.Tags),
-		Origin:                 payload.Origin,
-		Owner:                  payload.Owner,
-		Quantity:               quantity.String(),
-		Confidentiality:        confidentiality.String(),
-		Integrity:              integrity.String(),
-		Availability:           availability.String(),
-		JustificationCiaRating: payload.JustificationCiaRating,
-	}
-	return dataAssetInput, true
+		return dataAssetInput, err
+	}
+	dataAssetInput = payload.DataAsset
+	dataAssetInput.Usage = usage.String()
+	dataAssetInput.Tags = lowerCaseAndTrim(payload.Tags)
+	dataAssetInput.Quantity = quantity.String()
+	dataAssetInput.Confidentiality = confidentiality.String()
+	dataAssetInput.Integrity = integrity.String()
+	dataAssetInput.Availability = availability.String()
+	return dataAssetInput, nil
 }
 
 func (s *server) getTrustBoundaries(ginContext *gin.Context) {
@@ -739,71 +869,54 @@ func (s *server) getTrustBoundaries(ginContext *gin.Context) {
 	}
 }
 
+// payloadSharedRuntime is the shared runtime of the model file with its title, as the model file keys the shared
+// runtimes by title
 type payloadSharedRuntime struct {
-	Title                  string   `yaml:"title" json:"title"`
-	Id                     string   `yaml:"id" json:"id"`
-	Description            string   `yaml:"description" json:"description"`
-	Tags                   []string `yaml:"tags" json:"tags"`
-	TechnicalAssetsRunning []string `yaml:"technical_assets_running" json:"technical_assets_running"`
+	Title               string `yaml:"title" json:"title" binding:"required"`
+	input.SharedRuntime `yaml:",inline"`
 }
 
 func (s *server) setSharedRuntime(ginContext *gin.Context) {
-	folderNameOfKey, key, ok := s.checkTokenToFolderName(ginContext)
-	if !ok {
-		return
-	}
-	s.lockFolder(folderNameOfKey)
-	defer s.unlockFolder(folderNameOfKey)
-	modelInput, _, ok := s.readModel(ginContext, ginContext.Param("model-id"), key, folderNameOfKey)
-	if ok {
-		// yes, here keyed by title in YAML for better readability in the YAML file itself
-		for title, sharedRuntime := range modelInput.SharedRuntimes {
-			if sharedRuntime.ID == ginContext.Param("shared-runtime-id") {
-				payload := payloadSharedRuntime{}
-				err := ginContext.BindJSON(&payload)
-				if err != nil {
-					log.Println(err)
-					ginContext.JSON(http.StatusBadRequest, gin.H{
-						"error": "unable to parse request payload",
-					})
-					return
-				}
-				sharedRuntimeInput, ok := populateSharedRuntime(ginContext, payload)
-				if !ok {
-					return
-				}
-				// in order to also update the title, remove the shared runtime from the map and re-insert it (with new key)
-				delete(modelInput.SharedRuntimes, title)
-				modelInput.SharedRuntimes[payload.Title] = sharedRuntimeInput
-				idChanged := sharedRuntimeInput.ID != sharedRuntime.ID
-				if idChanged { // ID-CHANGE-PROPAGATION
-					for _, individualRiskCat := range modelInput.CustomRiskCategories {
-						if individualRiskCat.RisksIdentified != nil {
-							for individualRiskInstanceTitle, individualRiskInstance := range individualRiskCat.RisksIdentified {
-								if individualRiskInstance.MostRelevantSharedRuntime == sharedRuntime.ID { // apply the ID change
-									x := individualRiskCat.RisksIdentified[individualRiskInstanceTitle]
-									x.MostRelevantSharedRuntime = sharedRuntimeInput.ID // TODO needs more testing
-									individualRiskCat.RisksIdentified[individualRiskInstanceTitle] = x
-								}
+	s.changeModel(ginContext, "Shared Runtime Update", sharedRuntimeUpdate)
+}
+
+func sharedRuntimeUpdate(modelInput *input.Model, params gin.Params, bindPayload func(payload any) error) (gin.H, error) {
+	// yes, here keyed by title in YAML for better readability in the YAML file itself
+	for title, sharedRuntime := range modelInput.SharedRuntimes {
+		if sharedRuntime.ID == params.ByName("shared-runtime-id") {
+			payload := payloadSharedRuntime{}
+			err := bindPayload(&payload)
+			if err != nil {
+				log.Println(err)
+				return nil, errUnparsablePayload
+			}
+			sharedRuntimeInput := populateSharedRuntime(payload)
+			// in order to also update the title, remove the shared runtime from the map and re-insert it (with new key)
+			delete(modelInput.SharedRuntimes, title)
+			modelInput.SharedRuntimes[payload.Title] = sharedRuntimeInput
+			idChanged := sharedRuntimeInput.ID != sharedRuntime.ID
+			if idChanged { // ID-CHANGE-PROPAGATION
+				for _, individualRiskCat := range modelInput.CustomRiskCategories {
+					if individualRiskCat.RisksIdentified != nil {
+						for individualRiskInstanceTitle, individualRiskInstance := range individualRiskCat.RisksIdentified {
+							if individualRiskInstance.MostRelevantSharedRuntime == sharedRuntime.ID { // apply the ID change
+								x := individualRiskCat.RisksIdentified[individualRiskInstanceTitle]
+								x.MostRelevantSharedRuntime = sharedRuntimeInput.ID // TODO needs more testing
+								individualRiskCat.RisksIdentified[individualRiskInstanceTitle] = x
 							}
 						}
 					}
 				}
-				ok = s.writeModel(ginContext, key, folderNameOfKey, &modelInput, "Shared Runtime Update")
-				if ok {
-					ginContext.JSON(http.StatusOK, gin.H{
-						"message":    "shared runtime updated",
-						"id":         sharedRuntimeInput.ID,
-						"id_changed": idChanged, // in order to signal to clients, that other model parts might've received updates as well and should be reloaded
-					})
-				}
-				return
+				replaceElementIdInRiskTrackingAndAnnotations(modelInput, sharedRuntime.ID, sharedRuntimeInput.ID, false)
 			}
+			return gin.H{
+				"message":    "shared runtime updated",
+				"id":         sharedRuntimeInput.ID,
+				"id_changed": idChanged, // in order to signal to clients, that other model parts might've received updates as well and should be reloaded
+			}, nil
 		}
-		ginContext.JSON(http.StatusNotFound, gin.H{
-			"error": "shared runtime not found",
-		})
 	}
+	return nil, requestError{status: http.StatusNotFound, message: "shared runtime not found"}
 }
 
 func (s *server) getSharedRuntime(ginContext *gin.Context) {
@@ -824,68 +937,43 @@ func (s *server) getSharedRuntime(ginContext *gin.Context) {
 				return
 			}
 		}
-		ginContext.JSON(http.StatusNotFound, gin.H{
-			"error": "shared runtime not found",
-		})
+		respondError(ginContext, http.StatusNotFound, errorCodeNotFound, "shared runtime not found")
 	}
 }
 
 func (s *server) createNewSharedRuntime(ginContext *gin.Context) {
-	folderNameOfKey, key, ok := s.checkTokenToFolderName(ginContext)
-	if !ok {
-		return
+	s.changeModel(ginContext, "Shared Runtime Creation", sharedRuntimeCreation)
+}
+
+func sharedRuntimeCreation(modelInput *input.Model, _ gin.Params, bindPayload func(payload any) error) (gin.H, error) {
+	payload := payloadSharedRuntime{}
+	err := bindPayload(&payload)
+	if err != nil {
+		log.Println(err)
+		return nil, errUnparsablePayload
 	}
-	s.lockFolder(folderNameOfKey)
-	defer s.unlockFolder(folderNameOfKey)
-	modelInput, _, ok := s.readModel(ginContext, ginContext.Param("model-id"), key, folderNameOfKey)
-	if ok {
-		payload := payloadSharedRuntime{}
-		err := ginContext.BindJSON(&payload)
-		if err != nil {
-			log.Println(err)
-			ginContext.JSON(http.StatusBadRequest, gin.H{
-				"error": "unable to parse request payload",
-			})
-			return
-		}
-		// yes, here keyed by title in YAML for better readability in the YAML file itself
-		if _, exists := modelInput.SharedRuntimes[payload.Title]; exists {
-			ginContext.JSON(http.StatusConflict, gin.H{
-				"error": "shared runtime with this title already exists",
-			})
-			return
-		}
-		// but later it will in memory keyed by its "id", so do this uniqueness check also
-		for _, sharedRuntime := range modelInput.SharedRuntimes {
-			if sharedRuntime.ID == payload.Id {
-				ginContext.JSON(http.StatusConflict, gin.H{
-					"error": "shared runtime with this id already exists",
-				})
-				return
-			}
-		}
-		if !checkTechnicalAssetsExisting(modelInput, payload.TechnicalAssetsRunning) {
-			ginContext.JSON(http.StatusBadRequest, gin.H{
-				"error": "referenced technical asset does not exist",
-			})
-			return
-		}
-		sharedRuntimeInput, ok := populateSharedRuntime(ginContext, payload)
-		if !ok {
-			return
-		}
-		if modelInput.SharedRuntimes == nil {
-			modelInput.SharedRuntimes = make(map[string]input.SharedRuntime)
-		}
-		modelInput.SharedRuntimes[payload.Title] = sharedRuntimeInput
-		ok = s.writeModel(ginContext, key, folderNameOfKey, &modelInput, "Shared Runtime Creation")
-		if ok {
-			ginContext.JSON(http.StatusOK, gin.H{
-				"message": "shared runtime created",
-				"id":      sharedRuntimeInput.ID,
-			})
+	// yes, here keyed by title in YAML for better readability in the YAML file itself
+	if _, exists := modelInput.SharedRuntimes[payload.Title]; exists {
+		return nil, requestError{status: http.StatusConflict, message: "shared runtime with this title already exists"}
+	}
+	// but later it will in memory keyed by its "id", so do this uniqueness check also
+	for _, sharedRuntime := range modelInput.SharedRuntimes {
+		if sharedRuntime.ID == payload.ID {
+			return nil, requestError{status: http.StatusConflict, message: "shared runtime with this id already exists"}
 		}
 	}
+	if !checkTechnicalAssetsExisting(*modelInput, payload.TechnicalAssetsRunning) {
+		return nil, requestError{status: http.StatusBadRequest, message: "referenced technical asset does not exist"}
+	}
+	sharedRuntimeInput := populateSharedRuntime(payload)
+	if modelInput.SharedRuntimes == nil {
+		modelInput.SharedRuntimes = make(map[string]input.SharedRuntime)
+	}
+	modelInput.SharedRuntimes[payload.Title] = sharedRuntimeInput
+	return gin.H{
+		"message": "shared runtime created",
+		"id":      sharedRuntimeInput.ID,
+	}, nil
 }
 
 func checkTechnicalAssetsExisting(modelInput input.Model, techAssetIDs []string) (ok bool) {
@@ -904,59 +992,44 @@ func checkTechnicalAssetsExisting(modelInput input.Model, techAssetIDs []string)
 	return true
 }
 
-func populateSharedRuntime(_ *gin.Context, payload payloadSharedRuntime) (sharedRuntimeInput input.SharedRuntime, ok bool) {
-	sharedRuntimeInput = input.SharedRuntime{
-		ID:                     payload.Id,
-		Description:            payload.Description,
-		Tags:                   lowerCaseAndTrim(payload.Tags),
-		TechnicalAssetsRunning: payload.TechnicalAssetsRunning,
-	}
-	return sharedRuntimeInput, true
+func populateSharedRuntime(payload payloadSharedRuntime) input.SharedRuntime {
+	sharedRuntimeInput := payload.SharedRuntime
+	sharedRuntimeInput.Tags = lowerCaseAndTrim(payload.Tags)
+	return sharedRuntimeInput
 }
 
 func (s *server) deleteSharedRuntime(ginContext *gin.Context) {
-	folderNameOfKey, key, ok := s.checkTokenToFolderName(ginContext)
-	if !ok {
-		return
-	}
-	s.lockFolder(folderNameOfKey)
-	defer s.unlockFolder(folderNameOfKey)
-	modelInput, _, ok := s.readModel(ginContext, ginContext.Param("model-id"), key, folderNameOfKey)
-	if ok {
-		referencesDeleted := false
-		// yes, here keyed by title in YAML for better readability in the YAML file itself
-		for title, sharedRuntime := range modelInput.SharedRuntimes {
-			if sharedRuntime.ID == ginContext.Param("shared-runtime-id") {
-				// also remove all usages of this shared runtime !!
-				for _, individualRiskCat := range modelInput.CustomRiskCategories {
-					if individualRiskCat.RisksIdentified != nil {
-						for individualRiskInstanceTitle, individualRiskInstance := range individualRiskCat.RisksIdentified {
-							if individualRiskInstance.MostRelevantSharedRuntime == sharedRuntime.ID { // apply the removal
-								referencesDeleted = true
-								x := individualRiskCat.RisksIdentified[individualRiskInstanceTitle]
-								x.MostRelevantSharedRuntime = "" // TODO needs more testing
-								individualRiskCat.RisksIdentified[individualRiskInstanceTitle] = x
-							}
+	s.changeModel(ginContext, "Shared Runtime Deletion", sharedRuntimeDeletion)
+}
+
+func sharedRuntimeDeletion(modelInput *input.Model, params gin.Params, _ func(payload any) error) (gin.H, error) {
+	referencesDeleted := false
+	// yes, here keyed by title in YAML for better readability in the YAML file itself
+	for title, sharedRuntime := range modelInput.SharedRuntimes {
+		if sharedRuntime.ID == params.ByName("shared-runtime-id") {
+			// also remove all usages of this shared runtime !!
+			for _, individualRiskCat := range modelInput.CustomRiskCategories {
+				if individualRiskCat.RisksIdentified != nil {
+					for individualRiskInstanceTitle, individualRiskInstance := range individualRiskCat.RisksIdentified {
+						if individualRiskInstance.MostRelevantSharedRuntime == sharedRuntime.ID { // apply the removal
+							referencesDeleted = true
+							x := individualRiskCat.RisksIdentified[individualRiskInstanceTitle]
+							x.MostRelevantSharedRuntime = "" // TODO needs more testing
+							individualRiskCat.RisksIdentified[individualRiskInstanceTitle] = x
 						}
 					}
 				}
-				// remove it itself
-				delete(modelInput.SharedRuntimes, title)
-				ok = s.writeModel(ginContext, key, folderNameOfKey, &modelInput, "Shared Runtime Deletion")
-				if ok {
-					ginContext.JSON(http.StatusOK, gin.H{
-						"message":            "shared runtime deleted",
-						"id":                 sharedRuntime.ID,
-						"references_deleted": referencesDeleted, // in order to signal to clients, that other model parts might've been deleted as well
-					})
-				}
-				return
 			}
+			// remove it itself
+			delete(modelInput.SharedRuntimes, title)
+			return gin.H{
+				"message":            "shared runtime deleted",
+				"id":                 sharedRuntime.ID,
+				"references_deleted": referencesDeleted, // in order to signal to clients, that other model parts might've been deleted as well
+			}, nil
 		}
-		ginContext.JSON(http.StatusNotFound, gin.H{
-			"error": "shared runtime not found",
-		})
 	}
+	return nil, requestError{status: http.StatusNotFound, message: "shared runtime not found"}
 }
 
 func (s *server) getSharedRuntimes(ginContext *gin.Context) {
@@ -973,104 +1046,126 @@ func (s *server) getSharedRuntimes(ginContext *gin.Context) {
 }
 
 func (s *server) readModel(ginContext *gin.Context, modelUUID string, key []byte, folderNameOfKey string) (modelInputResult input.Model, yamlText string, ok bool) {
-	modelFolder, ok := s.checkModelFolder(ginContext, modelUUID, folderNameOfKey)
+	modelFolder, _, ok := s.modelFolderOfRequest(ginContext, modelUUID, folderNameOfKey)
 	if !ok {
 		return modelInputResult, yamlText, false
 	}
-	cryptoKey := generateKeyFromAlreadyStrongRandomInput(key)
-	block, err := aes.NewCipher(cryptoKey)
-	if err != nil {
-		log.Println(err)
-		ginContext.JSON(http.StatusInternalServerError, gin.H{
-			"error": "unable to open model",
-		})
-		return modelInputResult, yamlText, false
-	}
-	aesGcm, err := cipher.NewGCM(block)
+	return s.readModelFile(ginContext, modelFolder, key)
+}
+
+func (s *server) readModelFile(ginContext *gin.Context, modelFolder string, key []byte) (modelInputResult input.Model, yamlText string, ok bool) {
+	modelInputResult, yamlText, err := s.decryptModelFile(modelFolder, key)
 	if err != nil {
 		log.Println(err)
-		ginContext.JSON(http.StatusInternalServerError, gin.H{
-			"error": "unable to open model",
-		})
+		respondError(ginContext, http.StatusInternalServerError, errorCodeInternal, "unable to open model")
 		return modelInputResult, yamlText, false
 	}
+	return modelInputResult, yamlText, true
+}
 
-	fileBytes, err := os.ReadFile(filepath.Clean(filepath.Join(modelFolder, s.config.InputFile)))
+// decryptModelFile reads the model of the model folder, failing when it can't be decrypted or parsed
+func (s *server) decryptModelFile(modelFolder string, key []byte) (input.Model, string, error) {
+	fileBytes, err := s.storage.ReadFile(filepath.Join(modelFolder, s.config.InputFile))
 	if err != nil {
-		log.Println(err)
-		ginContext.JSON(http.StatusInternalServerError, gin.H{
-			"error": "unable to open model",
-		})
-		return modelInputResult, yamlText, false
+		return input.Model{}, "", err
 	}
-
-	nonce := fileBytes[0:12]
-	ciphertext := fileBytes[12:]
-	plaintext, err := aesGcm.Open(nil, nonce, ciphertext, nil)
+	plaintext, err := s.decryptModelData(modelFolder, key, fileBytes)
 	if err != nil {
-		log.Println(err)
-		ginContext.JSON(http.StatusInternalServerError, gin.H{
-			"error": "unable to open model",
-		})
-		return modelInputResult, yamlText, false
+		return input.Model{}, "", err
 	}
-
 	r, err := gzip.NewReader(bytes.NewReader(plaintext))
 	if err != nil {
-		log.Println(err)
-		ginContext.JSON(http.StatusInternalServerError, gin.H{
-			"error": "unable to open model",
-		})
-		return modelInputResult, yamlText, false
+		return input.Model{}, "", err
 	}
 	buf := new(bytes.Buffer)
 	_, _ = buf.ReadFrom(r)
 	modelInput := new(input.Model).Defaults()
 	yamlBytes := buf.Bytes()
-	err = yaml.Unmarshal(yamlBytes, &modelInput)
+	err = input.Unmarshal(s.config.InputFile, yamlBytes, &modelInput)
 	if err != nil {
-		log.Println(err)
-		ginContext.JSON(http.StatusInternalServerError, gin.H{
-			"error": "unable to open model",
-		})
-		return modelInputResult, yamlText, false
+		return input.Model{}, "", err
 	}
-	return *modelInput, string(yamlBytes), true
+	return *modelInput, string(yamlBytes), nil
 }
 
 func (s *server) writeModel(ginContext *gin.Context, key []byte, folderNameOfKey string, modelInput *input.Model, changeReasonForHistory string) (ok bool) {
-	modelFolder, ok := s.checkModelFolder(ginContext, ginContext.Param("model-id"), folderNameOfKey)
+	modelFolder, inEditSession, ok := s.modelFolderOfRequest(ginContext, ginContext.Param("model-id"), folderNameOfKey)
 	if ok {
+		// edit sessions may pass through invalid states, their model is validated on commit
+		if s.config.ValidateModelOnWrite && !inEditSession && !s.checkModelValid(ginContext, *modelInput) {
+			return false
+		}
 		modelInput.ThreagileVersion = docs.ThreagileVersion
 		yamlBytes, err := yaml.Marshal(modelInput)
 		if err != nil {
 			log.Println(err)
-			ginContext.JSON(http.StatusInternalServerError, gin.H{
-				"error": "unable to write model",
-			})
+			respondError(ginContext, http.StatusInternalServerError, errorCodeInternal, "unable to write model")
 			return false
 		}
 		/*
 			yamlBytes = model.ReformatYAML(yamlBytes)
 		*/
-		return s.writeModelYAML(ginContext, string(yamlBytes), key, modelFolder, changeReasonForHistory, false)
+		return s.writeModelYAML(ginContext, string(yamlBytes), key, modelFolder, changeReasonForHistory, inEditSession)
 	}
 	return false
 }
 
+// modelChange changes the model in memory like a single modifying request does (with the params of the request path
+// and the binding of the request payload), answering the response or the error (a requestError for its status code)
+type modelChange func(modelInput *input.Model, params gin.Params, bindPayload func(payload any) error) (gin.H, error)
+
+// requestError is answered with its status code, other errors of a model change are answered as bad request
+type requestError struct {
+	status  int
+	message string
+}
+
+func (what requestError) Error() string {
+	return what.message
+}
+
+var errUnparsablePayload = requestError{status: http.StatusBadRequest, message: "unable to parse request payload"}
+
+// changeModel applies the change to the model of the request and writes it with one history entry of the reason
+func (s *server) changeModel(ginContext *gin.Context, changeReasonForHistory string, change modelChange) {
+	folderNameOfKey, key, ok := s.checkTokenToFolderName(ginContext)
+	if !ok {
+		return
+	}
+	s.lockFolder(folderNameOfKey)
+	defer s.unlockFolder(folderNameOfKey)
+	modelInput, _, ok := s.readModel(ginContext, ginContext.Param("model-id"), key, folderNameOfKey)
+	if ok {
+		response, err := change(&modelInput, ginContext.Params, ginContext.BindJSON)
+		if err != nil {
+			handleChangeError(err, ginContext)
+			return
+		}
+		ok = s.writeModel(ginContext, key, folderNameOfKey, &modelInput, changeReasonForHistory)
+		if ok {
+			ginContext.JSON(http.StatusOK, response)
+		}
+	}
+}
+
+func handleChangeError(err error, ginContext *gin.Context) {
+	var requestErr requestError
+	if errors.As(err, &requestErr) {
+		respondError(ginContext, requestErr.status, errorCodeOfStatus(requestErr.status), requestErr.message)
+		return
+	}
+	handleErrorInServiceCall(err, ginContext)
+}
+
 func (s *server) checkModelFolder(ginContext *gin.Context, modelUUID string, folderNameOfKey string) (modelFolder string, ok bool) {
 	uuidParsed, err := uuid.Parse(modelUUID)
 	if err != nil {
-		ginContext.JSON(http.StatusNotFound, gin.H{
-			"error": "model not found",
-		})
+		respondError(ginContext, http.StatusNotFound, errorCodeModelNotFound, "model not found")
 		return modelFolder, false
 	}
 	modelFolder = folderNameForModel(folderNameOfKey, uuidParsed.String())
-	if _, err := os.Stat(modelFolder); os.IsNotExist(err) {
-		ginContext.JSON(http.StatusNotFound, gin.H{
-			"error": "model not found",
-		})
+	if _, err := s.storage.Stat(modelFolder); os.IsNotExist(err) {
+		respondError(ginContext, http.StatusNotFound, errorCodeModelNotFound, "model not found")
 		return modelFolder, false
 	}
 	return modelFolder, true
@@ -1085,7 +1180,13 @@ func (s *server) getModel(ginContext *gin.Context) {
 	defer s.unlockFolder(folderNameOfKey)
 	_, yamlText, ok := s.readModel(ginContext, ginContext.Param("model-id"), key, folderNameOfKey)
 	if ok {
-		tmpResultFile, err := os.CreateTemp(s.config.TempFolder, "threagile-*.yaml")
+		workspace, err := newTempWorkspace(s.config.TempFolder, "stream")
+		if err != nil {
+			handleErrorInServiceCall(err, ginContext)
+			return
+		}
+		defer workspace.Close()
+		tmpResultFile, err := workspace.CreateFile("threagile-*.yaml")
 		if err != nil {
 			handleErrorInServiceCall(err, ginContext)
 			return
@@ -1093,12 +1194,9 @@ func (s *server) getModel(ginContext *gin.Context) {
 		err = os.WriteFile(tmpResultFile.Name(), []byte(yamlText), 0400)
 		if err != nil {
 			log.Println(err)
-			ginContext.JSON(http.StatusInternalServerError, gin.H{
-				"error": "unable to stream model file",
-			})
+			respondError(ginContext, http.StatusInternalServerError, errorCodeInternal, "unable to stream model file")
 			return
 		}
-		defer func() { _ = os.Remove(tmpResultFile.Name()) }()
 		ginContext.FileAttachment(tmpResultFile.Name(), s.config.InputFile)
 	}
 }
@@ -1116,10 +1214,22 @@ func (s *server) importModel(ginContext *gin.Context) {
 	_, _, ok = s.readModel(ginContext, aUuid, key, folderNameOfKey)
 	if ok {
 		// first analyze it simply by executing the full risk process (just discard the result) to ensure that everything would work
-		yamlContent, ok := s.execute(ginContext, true)
+		yamlContent, _, ok := s.execute(ginContext, true)
 		if ok {
 			// if we're here, then no problem was raised, so ok to proceed
-			ok = s.writeModelYAML(ginContext, string(yamlContent), key, folderNameForModel(folderNameOfKey, aUuid), "Model Import", false)
+			if input.IsJSON(s.config.InputFile, yamlContent) {
+				// models are stored as yaml, so json imports are converted (there are no comments to lose)
+				modelInput := new(input.Model).Defaults()
+				err := input.Unmarshal(s.config.InputFile, yamlContent, modelInput)
+				if err != nil {
+					handleErrorInServiceCall(err, ginContext)
+					return
+				}
+				ok = s.writeModel(ginContext, key, folderNameOfKey, modelInput, "Model Import")
+			} else {
+				modelFolder, inEditSession, _ := s.modelFolderOfRequest(ginContext, aUuid, folderNameOfKey) // already checked by readModel
+				ok = s.writeModelYAML(ginContext, string(yamlContent), key, modelFolder, "Model Import", inEditSession)
+			}
 			if ok {
 				ginContext.JSON(http.StatusCreated, gin.H{
 					"message": "model imported",
@@ -1144,59 +1254,101 @@ func (s *server) analyzeModelOnServerDirectly(ginContext *gin.Context) {
 				log.Println(err)
 			}
 			log.Println(err)
-			ginContext.JSON(http.StatusBadRequest, gin.H{
-				"error": strings.TrimSpace(err.Error()),
-			})
+			respondError(ginContext, http.StatusBadRequest, errorCodeBadRequest, strings.TrimSpace(err.Error()))
 			ok = false
 		}
 	}()
 
-	dpi, err := strconv.Atoi(ginContext.DefaultQuery("dpi", strconv.Itoa(s.config.GraphvizDPI)))
-	if err != nil {
-		handleErrorInServiceCall(err, ginContext)
+	dpi, ok := s.requestedDPI(ginContext, folderNameOfKey)
+	if !ok {
 		return
 	}
 
-	_, yamlText, ok := s.readModel(ginContext, ginContext.Param("model-id"), key, folderNameOfKey)
+	modelInput, yamlText, ok := s.readModel(ginContext, ginContext.Param("model-id"), key, folderNameOfKey)
 	if !ok {
 		return
 	}
-	tmpModelFile, err := os.CreateTemp(s.config.TempFolder, "threagile-direct-analyze-*")
+	workspace, err := newTempWorkspace(s.config.TempFolder, "direct-analyze")
 	if err != nil {
 		handleErrorInServiceCall(err, ginContext)
 		return
 	}
-	defer func() { _ = os.Remove(tmpModelFile.Name()) }()
-	tmpOutputDir, err := os.MkdirTemp(s.config.TempFolder, "threagile-direct-analyze-")
+	defer workspace.Close()
+	tmpResultFile, err := workspace.CreateFile("threagile-result-*.zip")
 	if err != nil {
 		handleErrorInServiceCall(err, ginContext)
 		return
 	}
-	defer func() { _ = os.RemoveAll(tmpOutputDir) }()
-	tmpResultFile, err := os.CreateTemp(s.config.TempFolder, "threagile-result-*.zip")
+
+	modelFolder := folderNameForModel(folderNameOfKey, ginContext.Param("model-id"))
+	versionText, commentsData, err := s.outputVersion(modelFolder, key, yamlText)
 	if err != nil {
 		handleErrorInServiceCall(err, ginContext)
 		return
 	}
-	defer func() { _ = os.Remove(tmpResultFile.Name()) }()
-
-	err = os.WriteFile(tmpModelFile.Name(), []byte(yamlText), 0400)
+	modelHash := s.resultHash(versionText, dpi)
+	restored, err := s.restoreAnalysisResult(modelFolder, key, modelHash, tmpResultFile.Name())
+	if err != nil {
+		log.Println(err)
+	}
+	if restored {
+		if s.config.Verbose {
+			log.Println("Streaming back stored result file: " + tmpResultFile.Name())
+		}
+		ginContext.FileAttachment(tmpResultFile.Name(), "threagile-result.zip")
+		return
+	}
+	if !s.checkAnalysisQuota(ginContext, folderNameOfKey) {
+		return
+	}
 
-	s.doItViaRuntimeCall(tmpModelFile.Name(), tmpOutputDir, true, true, true, true, true, true, true, true, dpi)
+	tmpOutputDir, err := s.renderAnalysisResult(ginContext.Request.Context(), workspace, yamlText, commentsData, dpi, tmpResultFile.Name(), nil)
 	if err != nil {
 		handleErrorInServiceCall(err, ginContext)
 		return
 	}
+	s.recordAnalysisResult(modelFolder, key, ginContext.Param("model-id"), modelInput, modelHash, tmpOutputDir, tmpResultFile.Name())
+	if s.config.Verbose {
+		log.Println("Streaming back result file: " + tmpResultFile.Name())
+	}
+	ginContext.FileAttachment(tmpResultFile.Name(), "threagile-result.zip")
+}
+
+// renderAnalysisResult analyzes the model in the workspace with the comments of its risks (see outputVersion) and zips
+// its outputs into the result file, answering the output folder; the sub-process failing panics (see runtimeCall), the
+// progress gets its progress events
+func (s *server) renderAnalysisResult(ctx context.Context, workspace *TempWorkspace, yamlText string, commentsData []byte, dpi int, resultFile string, progress func(event common.ProgressEvent)) (string, error) {
+	tmpModelFile, err := workspace.CreateFile("threagile-direct-analyze-*")
+	if err != nil {
+		return "", err
+	}
+	_ = tmpModelFile.Close()
+	tmpOutputDir, err := workspace.Mkdir("output")
+	if err != nil {
+		return "", err
+	}
+	err = os.WriteFile(tmpModelFile.Name(), []byte(yamlText), 0400)
+	if err != nil {
+		return "", err
+	}
+	err = writeRiskCommentsFile(workspace, commentsData)
+	if err != nil {
+		return "", err
+	}
+
+	args := s.runtimeCallArgs(workspace, tmpModelFile.Name(), tmpOutputDir, true, true, true, true, true, true, true, true, true, dpi)
+	s.runtimeCall(ctx, tmpOutputDir, args, progress)
 	err = os.WriteFile(filepath.Join(tmpOutputDir, s.config.InputFile), []byte(yamlText), 0400)
 	if err != nil {
-		handleErrorInServiceCall(err, ginContext)
-		return
+		return "", err
 	}
 
 	files := []string{
 		filepath.Join(tmpOutputDir, s.config.InputFile),
 		filepath.Join(tmpOutputDir, s.config.DataFlowDiagramFilenamePNG),
 		filepath.Join(tmpOutputDir, s.config.DataAssetDiagramFilenamePNG),
+		filepath.Join(tmpOutputDir, s.config.RiskMatrixFilenamePNG),
+		filepath.Join(tmpOutputDir, s.config.RiskMatrixFilenameSVG),
 		filepath.Join(tmpOutputDir, s.config.ReportFilename),
 		filepath.Join(tmpOutputDir, s.config.ExcelRisksFilename),
 		filepath.Join(tmpOutputDir, s.config.ExcelTagsFilename),
@@ -1208,74 +1360,60 @@ func (s *server) analyzeModelOnServerDirectly(ginContext *gin.Context) {
 		files = append(files, filepath.Join(tmpOutputDir, s.config.DataFlowDiagramFilenameDOT))
 		files = append(files, filepath.Join(tmpOutputDir, s.config.DataAssetDiagramFilenameDOT))
 	}
-	err = zipFiles(tmpResultFile.Name(), files)
+	if commentsFile := riskCommentsFileOf(workspace); len(commentsFile) > 0 {
+		files = append(files, commentsFile)
+	}
+	return tmpOutputDir, zipFiles(resultFile, files)
+}
+
+// recordAnalysisResult stores the rendered result alongside the model, records its posture and notifies the webhooks,
+// only logging the failures as the result itself is fine
+func (s *server) recordAnalysisResult(modelFolder string, key []byte, modelId string, modelInput input.Model, modelHash string, outputDir string, resultFile string) {
+	err := s.storeAnalysisResult(modelFolder, key, modelHash, resultFile)
 	if err != nil {
-		handleErrorInServiceCall(err, ginContext)
-		return
+		log.Println(err) // the result is still streamed back, only the next request has to render it again
 	}
-	if s.config.Verbose {
-		fmt.Println("Streaming back result file: " + tmpResultFile.Name())
+	err = s.recordPosture(modelFolder, modelInput, outputDir)
+	if err != nil {
+		log.Println(err) // the dashboard shows the previous posture of the model then
+	}
+	err = s.notifyWebhooks(modelFolder, key, modelId, modelInput, outputDir)
+	if err != nil {
+		log.Println(err) // the webhooks are only a notification, the analysis itself succeeded
 	}
-	ginContext.FileAttachment(tmpResultFile.Name(), "threagile-result.zip")
 }
 
 func (s *server) writeModelYAML(ginContext *gin.Context, yaml string, key []byte, modelFolder string, changeReasonForHistory string, skipBackup bool) (ok bool) {
 	if s.config.Verbose {
-		fmt.Println("about to write " + strconv.Itoa(len(yaml)) + " bytes of yaml into model folder: " + modelFolder)
+		log.Println("about to write " + strconv.Itoa(len(yaml)) + " bytes of yaml into model folder: " + modelFolder)
 	}
 	var b bytes.Buffer
 	w := gzip.NewWriter(&b)
 	_, _ = w.Write([]byte(yaml))
 	_ = w.Close()
-	plaintext := b.Bytes()
-	cryptoKey := generateKeyFromAlreadyStrongRandomInput(key)
-	block, err := aes.NewCipher(cryptoKey)
-	if err != nil {
-		log.Println(err)
-		ginContext.JSON(http.StatusInternalServerError, gin.H{
-			"error": "unable to write model",
-		})
-		return false
-	}
-	// Never use more than 2^32 random nonces with a given key because of the risk of a repeat.
-	nonce := make([]byte, 12)
-	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
-		log.Println(err)
-		ginContext.JSON(http.StatusInternalServerError, gin.H{
-			"error": "unable to write model",
-		})
-		return false
-	}
-	aesGcm, err := cipher.NewGCM(block)
+	ciphertext, err := s.encryptModelData(modelFolder, key, b.Bytes())
 	if err != nil {
 		log.Println(err)
-		ginContext.JSON(http.StatusInternalServerError, gin.H{
-			"error": "unable to write model",
-		})
+		respondError(ginContext, http.StatusInternalServerError, errorCodeInternal, "unable to write model")
 		return false
 	}
-	ciphertext := aesGcm.Seal(nil, nonce, plaintext, nil)
 	if !skipBackup {
 		err = s.backupModelToHistory(modelFolder, changeReasonForHistory)
 		if err != nil {
 			log.Println(err)
-			ginContext.JSON(http.StatusInternalServerError, gin.H{
-				"error": "unable to write model",
-			})
+			respondError(ginContext, http.StatusInternalServerError, errorCodeInternal, "unable to write model")
 			return false
 		}
 	}
-	f, err := os.Create(filepath.Clean(filepath.Join(modelFolder, s.config.InputFile)))
+	err = s.storage.WriteFile(filepath.Join(modelFolder, s.config.InputFile), ciphertext)
 	if err != nil {
 		log.Println(err)
-		ginContext.JSON(http.StatusInternalServerError, gin.H{
-			"error": "unable to write model",
-		})
+		respondError(ginContext, http.StatusInternalServerError, errorCodeInternal, "unable to write model")
 		return false
 	}
-	_, _ = f.Write(nonce)
-	_, _ = f.Write(ciphertext)
-	_ = f.Close()
+	if ownModelFolder, err := s.modelFolderOf(modelFolder); err == nil && ownModelFolder == modelFolder { // not of edit sessions
+		s.refreshPosture(modelFolder, yaml)
+	}
 	return true
 }
 
@@ -1298,23 +1436,23 @@ func (s *server) unlockFolder(folderName string) {
 
 func (s *server) backupModelToHistory(modelFolder string, changeReasonForHistory string) (err error) {
 	historyFolder := filepath.Join(modelFolder, "history")
-	if _, err := os.Stat(historyFolder); os.IsNotExist(err) {
-		err = os.Mkdir(historyFolder, 0700)
+	if _, err := s.storage.Stat(historyFolder); os.IsNotExist(err) {
+		err = s.storage.Mkdir(historyFolder)
 		if err != nil {
 			return err
 		}
 	}
-	inputModel, err := os.ReadFile(filepath.Clean(filepath.Join(modelFolder, s.config.InputFile)))
+	inputModel, err := s.storage.ReadFile(filepath.Join(modelFolder, s.config.InputFile))
 	if err != nil {
 		return err
 	}
 	historyFile := filepath.Join(historyFolder, time.Now().Format("2006-01-02 15:04:05")+" "+changeReasonForHistory+".backup")
-	err = os.WriteFile(filepath.Clean(historyFile), inputModel, 0400)
+	err = s.storage.WriteFile(historyFile, inputModel)
 	if err != nil {
 		return err
 	}
 	// now delete any old files if over limit to keep
-	files, err := os.ReadDir(filepath.Clean(historyFolder))
+	files, err := s.storage.ReadDir(historyFolder)
 	if err != nil {
 		return err
 	}
@@ -1328,7 +1466,7 @@ func (s *server) backupModelToHistory(modelFolder string, changeReasonForHistory
 			if file.Name() != filepath.Clean(file.Name()) {
 				return fmt.Errorf("weird file name %v", file.Name())
 			}
-			err = os.Remove(filepath.Clean(filepath.Join(historyFolder, file.Name())))
+			err = s.storage.Remove(filepath.Join(historyFolder, file.Name()))
 			if err != nil {
 				return err
 			}
@@ -0,0 +1,126 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/threagile/threagile/pkg/input"
+)
+
+func TestListModels(t *testing.T) {
+	m := newModelTestServer(t, communicationLinkTestModel)
+	storage := m.storage.(fileSystemStorage).fileSystem
+	folderNameOfKey := filepath.Dir(m.modelFolder)
+	modelFile, err := storage.ReadFile(filepath.Join(m.modelFolder, m.config.InputFile))
+	assert.NoError(t, err)
+	now := time.Now()
+	addModel := func(id string, data []byte, modified time.Time) {
+		assert.NoError(t, storage.MkdirAll(filepath.Join(folderNameOfKey, id), 0700))
+		assert.NoError(t, storage.WriteFile(filepath.Join(folderNameOfKey, id, m.config.InputFile), data, 0600))
+		assert.NoError(t, storage.Chtimes(filepath.Join(folderNameOfKey, id, m.config.InputFile), modified, modified))
+	}
+	assert.NoError(t, storage.Chtimes(filepath.Join(m.modelFolder, m.config.InputFile), now.Add(-time.Hour), now.Add(-time.Hour)))
+	ginContext, _ := gin.CreateTestContext(httptest.NewRecorder())
+	ginContext.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+	ginContext.Request.Header.Set("token", m.token)
+	_, key, _ := m.checkTokenToFolderName(ginContext)
+	plaintext, err := m.decryptModelData(m.modelFolder, key, modelFile)
+	assert.NoError(t, err)
+	copied, err := m.encryptModelData(filepath.Join(folderNameOfKey, "copy"), key, plaintext) // each model has a key of its own
+	assert.NoError(t, err)
+	addModel("copy", copied, now)
+	addModel("broken", []byte("no model"), now.Add(-time.Minute))
+
+	list := func(query string) (*httptest.ResponseRecorder, []payloadModels) {
+		recorder := httptest.NewRecorder()
+		ginContext, _ := gin.CreateTestContext(recorder)
+		ginContext.Request = httptest.NewRequest(http.MethodGet, "/models"+query, nil)
+		ginContext.Request.Header.Set("token", m.token)
+		m.listModels(ginContext)
+		var models []payloadModels
+		_ = json.Unmarshal(recorder.Body.Bytes(), &models)
+		return recorder, models
+	}
+
+	recorder, models := list("")
+	assert.Equal(t, http.StatusOK, recorder.Code, "despite the broken model")
+	assert.Equal(t, "3", recorder.Header().Get(totalCountHeader))
+	assert.Len(t, models, 3)
+	assert.Equal(t, []string{"copy", "broken", m.modelID}, []string{models[0].ID, models[1].ID, models[2].ID}, "newest modified first")
+	assert.Equal(t, "Communication Link Test", models[0].Title)
+	assert.Equal(t, "unable to open model", models[1].Error)
+	assert.Empty(t, models[2].Error)
+
+	recorder, models = list("?sort=modified&order=asc&offset=1&limit=1")
+	assert.Equal(t, "3", recorder.Header().Get(totalCountHeader))
+	assert.Len(t, models, 1)
+	assert.Equal(t, "broken", models[0].ID)
+
+	_, models = list("?offset=5")
+	assert.Empty(t, models)
+
+	recorder, _ = list("?sort=size")
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+	recorder, _ = list("?limit=0")
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+}
+
+func TestQuestionsAndTags(t *testing.T) {
+	m := newModelTestServer(t, `threagile_version: 1.0.0
+title: Questions And Tags Test
+tags_available: [aws]
+technical_assets:
+  Web Server:
+    id: web-server
+    tags: [aws]
+`)
+
+	recorder := m.call(m.setQuestions, http.MethodPut, nil, payloadQuestions{"Is the vault rotated?": "", "Who operates it?": "Ops"})
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	var questions payloadQuestions
+	assert.NoError(t, json.Unmarshal(m.call(m.getQuestions, http.MethodGet, nil, nil).Body.Bytes(), &questions))
+	assert.Equal(t, payloadQuestions{"Is the vault rotated?": "", "Who operates it?": "Ops"}, questions)
+
+	recorder = m.call(m.setTags, http.MethodPut, nil, payloadTags{"linux"})
+	assert.Equal(t, http.StatusConflict, recorder.Code, "aws is still used")
+	recorder = m.call(m.setTags, http.MethodPut, nil, payloadTags{" Linux ", "aws", "linux"})
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	var tags payloadTags
+	assert.NoError(t, json.Unmarshal(m.call(m.getTags, http.MethodGet, nil, nil).Body.Bytes(), &tags))
+	assert.Equal(t, payloadTags{"linux", "aws"}, tags)
+}
+
+func TestAnnotations(t *testing.T) {
+	m := newModelTestServer(t, `threagile_version: 1.0.0
+title: Annotations Test
+technical_assets:
+  Web Server:
+    id: web-server
+`)
+
+	var annotations payloadAnnotations
+	assert.NoError(t, json.Unmarshal(m.call(m.getAnnotations, http.MethodGet, nil, nil).Body.Bytes(), &annotations))
+	assert.Empty(t, annotations)
+	assert.NotNil(t, annotations, "an empty array")
+
+	recorder := m.call(m.createNewAnnotation, http.MethodPost, nil, input.ElementAnnotation{Element: "web-server", Note: "Is the admin UI exposed?", Author: "Alice"})
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	recorder = m.call(m.createNewAnnotation, http.MethodPost, nil, input.ElementAnnotation{Note: "no element"})
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+	assert.NoError(t, json.Unmarshal(m.call(m.getAnnotations, http.MethodGet, nil, nil).Body.Bytes(), &annotations))
+	assert.Len(t, annotations, 1)
+	assert.Equal(t, time.Now().Format("2006-01-02"), annotations[0].Date, "dated today")
+
+	recorder = m.call(m.setAnnotations, http.MethodPut, nil, payloadAnnotations{{Element: "web-server", Note: "Only internal", Date: "2024-13-01"}})
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+	recorder = m.call(m.setAnnotations, http.MethodPut, nil, payloadAnnotations{{Element: " web-server ", Note: "Only internal", Author: "Bob", Date: "2024-03-02"}})
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.NoError(t, json.Unmarshal(m.call(m.getAnnotations, http.MethodGet, nil, nil).Body.Bytes(), &annotations))
+	assert.Equal(t, payloadAnnotations{{Element: "web-server", Note: "Only internal", Author: "Bob", Date: "2024-03-02"}}, annotations)
+}
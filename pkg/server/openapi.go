@@ -0,0 +1,413 @@
+package server
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"path"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gopkg.in/yaml.v3"
+
+	"github.com/threagile/threagile/pkg/input"
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+// openAPIHeader marks the document as generated, support/openapi.yaml is checked against the routes by the tests
+const openAPIHeader = "# Code generated from the routes of pkg/server/routes.go (make openapi); DO NOT EDIT.\n\n"
+
+type openAPIDocument struct {
+	OpenAPI    string                      `yaml:"openapi"`
+	Info       openAPIInfo                 `yaml:"info"`
+	Servers    []openAPIServer             `yaml:"servers"`
+	Tags       []openAPITag                `yaml:"tags"`
+	Paths      map[string]*openAPIPathItem `yaml:"paths"`
+	Components openAPIComponents           `yaml:"components"`
+}
+
+type openAPIInfo struct {
+	Title       string `yaml:"title"`
+	Description string `yaml:"description"`
+	Version     string `yaml:"version"`
+}
+
+type openAPIServer struct {
+	URL         string `yaml:"url"`
+	Description string `yaml:"description"`
+}
+
+type openAPITag struct {
+	Name        string `yaml:"name"`
+	Description string `yaml:"description"`
+}
+
+type openAPIPathItem struct {
+	Get    *openAPIOperation `yaml:"get,omitempty"`
+	Put    *openAPIOperation `yaml:"put,omitempty"`
+	Post   *openAPIOperation `yaml:"post,omitempty"`
+	Delete *openAPIOperation `yaml:"delete,omitempty"`
+}
+
+type openAPIOperation struct {
+	Tags        []string                   `yaml:"tags"`
+	Summary     string                     `yaml:"summary"`
+	Description string                     `yaml:"description,omitempty"`
+	Security    []map[string][]string      `yaml:"security,omitempty"`
+	Parameters  []openAPIParameter         `yaml:"parameters,omitempty"`
+	RequestBody *openAPIRequestBody        `yaml:"requestBody,omitempty"`
+	Responses   map[string]openAPIResponse `yaml:"responses"`
+}
+
+type openAPIParameter struct {
+	In          string         `yaml:"in"`
+	Name        string         `yaml:"name"`
+	Description string         `yaml:"description,omitempty"`
+	Required    bool           `yaml:"required"`
+	Schema      *openAPISchema `yaml:"schema"`
+}
+
+type openAPIRequestBody struct {
+	Required bool                        `yaml:"required"`
+	Content  map[string]openAPIMediaType `yaml:"content"`
+}
+
+type openAPIResponse struct {
+	Description string                      `yaml:"description"`
+	Content     map[string]openAPIMediaType `yaml:"content,omitempty"`
+}
+
+type openAPIMediaType struct {
+	Schema *openAPISchema `yaml:"schema"`
+}
+
+type openAPISchema struct {
+	Ref                  string                    `yaml:"$ref,omitempty"`
+	Type                 string                    `yaml:"type,omitempty"`
+	Format               string                    `yaml:"format,omitempty"`
+	Items                *openAPISchema            `yaml:"items,omitempty"`
+	Properties           map[string]*openAPISchema `yaml:"properties,omitempty"`
+	AdditionalProperties *openAPISchema            `yaml:"additionalProperties,omitempty"`
+	Enum                 []string                  `yaml:"enum,omitempty"`
+	Required             []string                  `yaml:"required,omitempty"`
+}
+
+type openAPIComponents struct {
+	Schemas         map[string]*openAPISchema        `yaml:"schemas"`
+	SecuritySchemes map[string]openAPISecurityScheme `yaml:"securitySchemes"`
+}
+
+type openAPISecurityScheme struct {
+	Type        string `yaml:"type"`
+	In          string `yaml:"in"`
+	Name        string `yaml:"name"`
+	Description string `yaml:"description"`
+}
+
+// openAPIGrantTokenScheme is accepted by the token routes of a single model as alternative to the token
+const openAPIGrantTokenScheme = "grantToken"
+
+var openAPISecuritySchemes = map[authentication]string{
+	keyAuth:        "key",
+	tokenAuth:      "token",
+	shareTokenAuth: "shareToken",
+	adminAuth:      "admin",
+}
+
+// openAPI generates the OpenAPI document of the routes, the payloads are described by reflecting on their types
+func (s *server) openAPI() ([]byte, error) {
+	document := openAPIDocument{
+		OpenAPI: "3.0.3",
+		Info: openAPIInfo{
+			Title:       "Threagile API",
+			Description: "<b>Threagile API</b> for Agile Threat Modeling: visit <a href=\"https://threagile.io\">https://threagile.io</a> for more information.",
+			Version:     "1.0.0",
+		},
+		Servers: []openAPIServer{{URL: "/", Description: "Threagile Server"}},
+		Tags: []openAPITag{
+			{Name: "direct", Description: "Direct one-shot calls for on-the-fly analyzing and checking of models"},
+			{Name: "meta", Description: "Meta infos about types and version"},
+			{Name: "auth", Description: "Auth calls for crypto key and token management"},
+			{Name: "models", Description: "Persistent model creation and handling stuff"},
+			{Name: "analysis-jobs", Description: "Analyses of persistent models running in the background, for models too large to be analyzed within a request"},
+			{Name: "templates", Description: "Model templates of the server to start new models from"},
+		},
+		Paths: make(map[string]*openAPIPathItem),
+		Components: openAPIComponents{
+			Schemas: make(map[string]*openAPISchema),
+			SecuritySchemes: map[string]openAPISecurityScheme{
+				openAPISecuritySchemes[keyAuth]:        {Type: "apiKey", In: "header", Name: "key", Description: "Auth key (see POST /auth/keys)"},
+				openAPISecuritySchemes[tokenAuth]:      {Type: "apiKey", In: "header", Name: "token", Description: "Time limited token of an auth key (see POST /auth/tokens)"},
+				openAPIGrantTokenScheme:                {Type: "apiKey", In: "header", Name: grantTokenHeader, Description: "Grant token of a model (see POST /models/{model-id}/grants), allowing the calls its role allows (read, edit or admin, each including the rights of the ones before)"},
+				openAPISecuritySchemes[shareTokenAuth]: {Type: "apiKey", In: "query", Name: "share-token", Description: "Share token of a model (see POST /models/{model-id}/share-token)"},
+				openAPISecuritySchemes[adminAuth]:      {Type: "apiKey", In: "header", Name: "admin-key", Description: "Admin key of the server (see config ServerAdminKey)"},
+			},
+		},
+	}
+	schemas := schemaBuilder{components: document.Components.Schemas}
+	errorSchema := schemas.of(reflect.TypeOf(payloadError{}))
+	codeSchema := document.Components.Schemas["server.payloadError"].Properties["code"]
+	for _, code := range errorCodes {
+		codeSchema.Enum = append(codeSchema.Enum, string(code))
+	}
+
+	for _, route := range s.routes() {
+		openAPIPath, pathParameters := openAPIPathOf(route.path)
+		operation := &openAPIOperation{
+			Tags:       []string{route.tag},
+			Summary:    route.summary,
+			Parameters: pathParameters,
+			Responses:  make(map[string]openAPIResponse),
+		}
+		if scheme, ok := openAPISecuritySchemes[route.auth]; ok {
+			operation.Security = []map[string][]string{{scheme: {}}}
+		}
+		if route.grantable() {
+			operation.Security = append(operation.Security, map[string][]string{openAPIGrantTokenScheme: {}})
+			operation.Description = "Grant tokens need the " + grantRoleNames[route.requiredGrantRole()] + " role"
+		}
+		for _, parameter := range route.query {
+			operation.Parameters = append(operation.Parameters, openAPIParameter{
+				In:          "query",
+				Name:        parameter.name,
+				Description: parameter.description,
+				Schema:      &openAPISchema{Type: parameter.schemaType},
+			})
+		}
+		if route.idempotent {
+			operation.Parameters = append(operation.Parameters, openAPIParameter{
+				In:          "header",
+				Name:        idempotencyKeyHeader,
+				Description: "Key of the request (like a uuid), a retry with the same key is answered with the response of the first successful request (for 24 hours) instead of creating another object",
+				Schema:      &openAPISchema{Type: "string"},
+			})
+		}
+
+		if route.upload {
+			operation.RequestBody = &openAPIRequestBody{Required: true, Content: map[string]openAPIMediaType{
+				gin.MIMEMultipartPOSTForm: {Schema: &openAPISchema{Type: "object", Properties: map[string]*openAPISchema{
+					"file": {Type: "string", Format: "binary"},
+				}}},
+			}}
+		} else if route.request != nil {
+			operation.RequestBody = &openAPIRequestBody{Required: true, Content: map[string]openAPIMediaType{
+				gin.MIMEJSON: {Schema: schemas.of(reflect.TypeOf(route.request))},
+			}}
+		}
+
+		status := route.status
+		if status == 0 {
+			status = http.StatusOK
+		}
+		response := openAPIResponse{Description: http.StatusText(status)}
+		if len(route.contentType) > 0 {
+			response.Content = map[string]openAPIMediaType{route.contentType: {Schema: binarySchema(route.contentType)}}
+		} else if route.response != nil {
+			response.Content = map[string]openAPIMediaType{gin.MIMEJSON: {Schema: schemas.of(reflect.TypeOf(route.response))}}
+		} else {
+			response.Content = map[string]openAPIMediaType{gin.MIMEJSON: {Schema: &openAPISchema{Type: "object"}}}
+		}
+		operation.Responses[strconv.Itoa(status)] = response
+		operation.Responses["default"] = openAPIResponse{
+			Description: "Error",
+			Content:     map[string]openAPIMediaType{gin.MIMEJSON: {Schema: errorSchema}},
+		}
+
+		pathItem, ok := document.Paths[openAPIPath]
+		if !ok {
+			pathItem = new(openAPIPathItem)
+			document.Paths[openAPIPath] = pathItem
+		}
+		switch route.method {
+		case http.MethodGet:
+			pathItem.Get = operation
+		case http.MethodPut:
+			pathItem.Put = operation
+		case http.MethodPost:
+			pathItem.Post = operation
+		case http.MethodDelete:
+			pathItem.Delete = operation
+		default:
+			return nil, fmt.Errorf("unsupported method %v of route %v", route.method, route.path)
+		}
+	}
+
+	var buffer bytes.Buffer
+	buffer.WriteString(openAPIHeader)
+	encoder := yaml.NewEncoder(&buffer)
+	encoder.SetIndent(2)
+	err := encoder.Encode(document)
+	if err == nil {
+		err = encoder.Close()
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to generate OpenAPI document: %w", err)
+	}
+	return buffer.Bytes(), nil
+}
+
+// openAPIPathOf converts the gin path parameters (:name) to the OpenAPI ones ({name})
+func openAPIPathOf(ginPath string) (string, []openAPIParameter) {
+	parameters := make([]openAPIParameter, 0)
+	segments := strings.Split(ginPath, "/")
+	for i, segment := range segments {
+		if name, ok := strings.CutPrefix(segment, ":"); ok {
+			segments[i] = "{" + name + "}"
+			parameters = append(parameters, openAPIParameter{In: "path", Name: name, Required: true, Schema: &openAPISchema{Type: "string"}})
+		}
+	}
+	return strings.Join(segments, "/"), parameters
+}
+
+func binarySchema(contentType string) *openAPISchema {
+	if contentType == gin.MIMEYAML || strings.HasPrefix(contentType, "text/") || contentType == mimeSVG {
+		return &openAPISchema{Type: "string"}
+	}
+	return &openAPISchema{Type: "string", Format: "binary"}
+}
+
+// propertyEnums are the values the model parser accepts for the string properties (or the items of the string array
+// properties) of the model types, the technologies are left out as they can be extended by the config
+var propertyEnums = map[reflect.Type]map[string][]types.TypeEnum{
+	reflect.TypeOf(input.DataAsset{}): {
+		"usage":           types.UsageValues(),
+		"quantity":        types.QuantityValues(),
+		"confidentiality": types.ConfidentialityValues(),
+		"integrity":       types.CriticalityValues(),
+		"availability":    types.CriticalityValues(),
+	},
+	reflect.TypeOf(input.TechnicalAsset{}): {
+		"type":                  types.TechnicalAssetTypeValues(),
+		"usage":                 types.UsageValues(),
+		"size":                  types.TechnicalAssetSizeValues(),
+		"machine":               types.TechnicalAssetMachineValues(),
+		"encryption":            types.EncryptionStyleValues(),
+		"confidentiality":       types.ConfidentialityValues(),
+		"integrity":             types.CriticalityValues(),
+		"availability":          types.CriticalityValues(),
+		"data_formats_accepted": types.DataFormatValues(),
+	},
+	reflect.TypeOf(input.CommunicationLink{}): {
+		"protocol":       types.ProtocolValues(),
+		"authentication": types.AuthenticationValues(),
+		"authorization":  types.AuthorizationValues(),
+		"usage":          types.UsageValues(),
+	},
+	reflect.TypeOf(input.TrustBoundary{}): {
+		"type":        types.TrustBoundaryTypeValues(),
+		"criticality": types.CriticalityValues(),
+	},
+	reflect.TypeOf(payloadOverview{}): {
+		"business_criticality": types.CriticalityValues(),
+	},
+}
+
+// schemaBuilder describes the types by their JSON encoding, named structs become (possibly recursive) components
+type schemaBuilder struct {
+	components map[string]*openAPISchema
+}
+
+var (
+	timeType          = reflect.TypeOf(time.Time{})
+	rawMessageType    = reflect.TypeOf(json.RawMessage{})
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+func (what schemaBuilder) of(t reflect.Type) *openAPISchema {
+	if t == timeType {
+		return &openAPISchema{Type: "string", Format: "date-time"}
+	}
+	if t == rawMessageType {
+		return &openAPISchema{} // any value
+	}
+	if t.Kind() != reflect.Pointer && t.Kind() != reflect.Interface &&
+		(t.Implements(jsonMarshalerType) || t.Implements(textMarshalerType) || reflect.PointerTo(t).Implements(textMarshalerType)) {
+		return &openAPISchema{Type: "string"} // the enum types are marshalled by their names
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		return what.of(t.Elem())
+	case reflect.Bool:
+		return &openAPISchema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &openAPISchema{Type: "integer"}
+	case reflect.Float32, reflect.Float64:
+		return &openAPISchema{Type: "number"}
+	case reflect.String:
+		return &openAPISchema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &openAPISchema{Type: "string", Format: "byte"}
+		}
+		return &openAPISchema{Type: "array", Items: what.of(t.Elem())}
+	case reflect.Map:
+		return &openAPISchema{Type: "object", AdditionalProperties: what.of(t.Elem())}
+	case reflect.Struct:
+		if len(t.Name()) == 0 {
+			return what.structOf(t)
+		}
+		name := path.Base(t.PkgPath()) + "." + t.Name()
+		if _, ok := what.components[name]; !ok {
+			what.components[name] = &openAPISchema{} // placeholder for recursive types
+			*what.components[name] = *what.structOf(t)
+		}
+		return &openAPISchema{Ref: "#/components/schemas/" + name}
+	}
+	return &openAPISchema{} // any value
+}
+
+func (what schemaBuilder) structOf(t reflect.Type) *openAPISchema {
+	schema := &openAPISchema{Type: "object", Properties: make(map[string]*openAPISchema)}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if field.Anonymous && len(name) == 0 {
+			embedded := field.Type
+			if embedded.Kind() == reflect.Pointer {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				embeddedSchema := what.structOf(embedded)
+				for property, propertySchema := range embeddedSchema.Properties {
+					schema.Properties[property] = propertySchema
+				}
+				schema.Required = append(schema.Required, embeddedSchema.Required...)
+				continue
+			}
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if len(name) == 0 {
+			name = field.Name
+		}
+		schema.Properties[name] = what.of(field.Type)
+		if values, ok := propertyEnums[t][name]; ok {
+			enumSchema := schema.Properties[name]
+			if enumSchema.Items != nil {
+				enumSchema = enumSchema.Items
+			}
+			enumSchema.Enum = arrayOfStringValues(values)
+		}
+		if slices.Contains(strings.Split(field.Tag.Get("binding"), ","), "required") {
+			schema.Required = append(schema.Required, name)
+		}
+	}
+	return schema
+}
+
+func (s *server) streamOpenAPI(ginContext *gin.Context) {
+	ginContext.Data(http.StatusOK, gin.MIMEYAML, s.openAPIDocument)
+}
@@ -0,0 +1,36 @@
+package server
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/threagile/threagile/pkg/common"
+)
+
+var updateOpenAPI = flag.Bool("update-openapi", false, "regenerate support/openapi.yaml from the routes")
+
+// TestOpenAPIDocumentIsUpToDate fails when the routes or payloads changed without regenerating support/openapi.yaml
+func TestOpenAPIDocumentIsUpToDate(t *testing.T) {
+	s := &server{config: new(common.Config)}
+	document, err := s.openAPI()
+	assert.NoError(t, err)
+
+	filename := filepath.Join("..", "..", "support", "openapi.yaml")
+	if *updateOpenAPI {
+		assert.NoError(t, os.WriteFile(filename, document, 0600))
+	}
+	stored, err := os.ReadFile(filename)
+	assert.NoError(t, err)
+	assert.Equal(t, string(stored), string(document), "support/openapi.yaml is outdated, regenerate it with: make openapi")
+}
+
+func TestOpenAPIPathOf(t *testing.T) {
+	openAPIPath, parameters := openAPIPathOf("/models/:model-id/data-assets/:data-asset-id")
+	assert.Equal(t, "/models/{model-id}/data-assets/{data-asset-id}", openAPIPath)
+	assert.Len(t, parameters, 2)
+	assert.Equal(t, "data-asset-id", parameters[1].Name)
+	assert.True(t, parameters[1].Required)
+}
@@ -0,0 +1,156 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxPayloadProblemsReported limits the problems listed in the error of an invalid payload
+const maxPayloadProblemsReported = 10
+
+// authenticated checks the authentication of the route before the payload is read (the handler checks it again, as
+// only it needs the key)
+func (s *server) authenticated(route route) gin.HandlerFunc {
+	return func(ginContext *gin.Context) {
+		switch route.auth {
+		case keyAuth:
+			_, _, _ = s.checkKeyToFolderName(ginContext)
+		case tokenAuth:
+			_, _, _ = s.checkTokenToFolderName(ginContext)
+		case shareTokenAuth:
+			_, _, _ = s.checkShareTokenToFolderName(ginContext)
+		case adminAuth:
+			_ = s.checkAdminKey(ginContext)
+		}
+	}
+}
+
+// payloadValidation checks the JSON payload against the schema of the request type (the one of the OpenAPI document)
+// before the handler binds it, answering the problems with their path in the payload instead of a generic error
+func payloadValidation(request any) gin.HandlerFunc {
+	schemas := schemaBuilder{components: make(map[string]*openAPISchema)}
+	schema := schemas.of(reflect.TypeOf(request))
+	return func(ginContext *gin.Context) {
+		body, err := io.ReadAll(ginContext.Request.Body)
+		if err != nil {
+			respondError(ginContext, http.StatusBadRequest, errorCodeInvalidPayload, "unable to read request payload")
+			return
+		}
+		ginContext.Request.Body = io.NopCloser(bytes.NewReader(body)) // for the binding of the handler
+
+		decoder := json.NewDecoder(bytes.NewReader(body))
+		decoder.UseNumber()
+		var payload any
+		err = decoder.Decode(&payload)
+		if err != nil {
+			respondError(ginContext, http.StatusBadRequest, errorCodeInvalidPayload, "request payload is no valid json: "+err.Error())
+			return
+		}
+		problems := schemas.validate(schema, payload, "$", make([]string, 0))
+		if len(problems) == 0 {
+			return
+		}
+		if len(problems) > maxPayloadProblemsReported {
+			problems = append(problems[:maxPayloadProblemsReported], "...")
+		}
+		respondError(ginContext, http.StatusBadRequest, errorCodeInvalidPayload, "invalid request payload", problems...)
+	}
+}
+
+// validate appends the problems of the value decoded with json.Number (at the path, like $.tags[1]) to the problems,
+// null is accepted everywhere (except for required properties) as the binding leaves the zero value then
+func (what schemaBuilder) validate(schema *openAPISchema, value any, path string, problems []string) []string {
+	if len(schema.Ref) > 0 {
+		schema = what.components[strings.TrimPrefix(schema.Ref, "#/components/schemas/")]
+	}
+	if value == nil || len(schema.Type) == 0 {
+		return problems
+	}
+	switch schema.Type {
+	case "object":
+		object, ok := value.(map[string]any)
+		if !ok {
+			return append(problems, path+": expected object, got "+jsonTypeOf(value))
+		}
+		names := make([]string, 0, len(object))
+		for name := range object {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range schema.Required {
+			if object[name] == nil {
+				problems = append(problems, path+"."+name+": required")
+			}
+		}
+		for _, name := range names {
+			propertySchema := schema.Properties[name]
+			if propertySchema == nil {
+				propertySchema = schema.AdditionalProperties
+			}
+			if propertySchema != nil { // unknown properties are ignored, like the binding does
+				problems = what.validate(propertySchema, object[name], path+"."+name, problems)
+			}
+		}
+	case "array":
+		array, ok := value.([]any)
+		if !ok {
+			return append(problems, path+": expected array, got "+jsonTypeOf(value))
+		}
+		for i, item := range array {
+			problems = what.validate(schema.Items, item, path+"["+strconv.Itoa(i)+"]", problems)
+		}
+	case "integer":
+		number, ok := value.(json.Number)
+		if !ok {
+			return append(problems, path+": expected integer, got "+jsonTypeOf(value))
+		}
+		if _, err := number.Int64(); err != nil {
+			return append(problems, fmt.Sprintf("%v: expected integer, got %v", path, number))
+		}
+	case "number":
+		if _, ok := value.(json.Number); !ok {
+			return append(problems, path+": expected number, got "+jsonTypeOf(value))
+		}
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			return append(problems, path+": expected boolean, got "+jsonTypeOf(value))
+		}
+	case "string":
+		text, ok := value.(string)
+		if !ok {
+			return append(problems, path+": expected string, got "+jsonTypeOf(value))
+		}
+		if len(text) > 0 && len(schema.Enum) > 0 && !slices.ContainsFunc(schema.Enum, func(value string) bool {
+			return strings.EqualFold(value, strings.TrimSpace(text)) // like the model parser, empty like omitted
+		}) {
+			return append(problems, fmt.Sprintf("%v: expected one of %v, got %q", path, strings.Join(schema.Enum, ", "), text))
+		}
+	}
+	return problems
+}
+
+func jsonTypeOf(value any) string {
+	switch value.(type) {
+	case map[string]any:
+		return "object"
+	case []any:
+		return "array"
+	case json.Number:
+		return "number"
+	case bool:
+		return "boolean"
+	case string:
+		return "string"
+	}
+	return "null"
+}
@@ -0,0 +1,76 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPayloadValidation(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	var bound payloadCommunicationLink
+	router := gin.New()
+	router.POST("/links", payloadValidation(payloadCommunicationLink{}), func(ginContext *gin.Context) {
+		assert.NoError(t, ginContext.BindJSON(&bound))
+		ginContext.JSON(http.StatusOK, gin.H{})
+	})
+	post := func(payload string) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/links", strings.NewReader(payload)))
+		return recorder
+	}
+
+	recorder := post(`{"title": "Link", "tags": ["a", 1], "vpn": "yes", "diagram_tweak_weight": 1.5, "unknown": 1}`)
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "$.diagram_tweak_weight: expected integer, got 1.5")
+	assert.Contains(t, recorder.Body.String(), "$.tags[1]: expected string, got number")
+	assert.Contains(t, recorder.Body.String(), "$.vpn: expected boolean, got string")
+	assert.NotContains(t, recorder.Body.String(), "unknown")
+
+	recorder = post(`{"protocol": "carrier-pigeon", "usage": "", "tags": []}`)
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "$.protocol: expected one of unknown-protocol, http, https")
+	assert.Contains(t, recorder.Body.String(), "$.title: required")
+	assert.NotContains(t, recorder.Body.String(), "$.usage")
+
+	recorder = post(`[]`)
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "$: expected object, got array")
+
+	recorder = post(`{"title": `)
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "no valid json")
+
+	recorder = post(`{"title": "Link", "protocol": "https", "authorization": "Technical-User", "tags": null, "vpn": true, "diagram_tweak_weight": 2}`)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, "Link", bound.Title, "the handler still binds the payload")
+	assert.Equal(t, 2, bound.DiagramTweakWeight)
+}
+
+func TestPayloadValidationAfterAuthentication(t *testing.T) {
+	m := newModelTestServer(t, communicationLinkTestModel)
+	router := gin.New()
+	reached := false
+	for _, route := range m.routes() {
+		if route.method == http.MethodPost && route.path == "/models/:model-id/shared-runtimes" {
+			router.Handle(route.method, route.path, m.authenticated(route), func(ginContext *gin.Context) {
+				reached = true
+			})
+		}
+	}
+	request := httptest.NewRequest(http.MethodPost, "/models/"+m.modelID+"/shared-runtimes", strings.NewReader(`{"title": "Cluster"}`))
+	request.Header.Set("token", "unknown")
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+	assert.False(t, reached, "the payload of unauthenticated requests is not read")
+
+	request = httptest.NewRequest(http.MethodPost, "/models/"+m.modelID+"/shared-runtimes", strings.NewReader(`{"title": "Cluster"}`))
+	request.Header.Set("token", m.token)
+	router.ServeHTTP(httptest.NewRecorder(), request)
+	assert.True(t, reached)
+}
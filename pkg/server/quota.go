@@ -0,0 +1,233 @@
+package server
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// the quotas keep multi-team servers fair: they are tracked per key, so that creating a new token of the key (which
+// invalidates the previous one) does not reset them. The admin sets the quotas of single keys (by the key id answered to
+// their holders by GET /auth/quota), overriding the ones of the tenant and the server.
+
+// keyQuotaFilename is stored (unencrypted) in the key folder by the admin
+const keyQuotaFilename = "quota.json"
+
+var keyIdPattern = regexp.MustCompile(`^[0-9a-f]{128}$`)
+
+// payloadQuota are quotas of a key, 0 means the ones of the tenant or the server apply (or no limit when they are 0)
+type payloadQuota struct {
+	MaxDPI             int `json:"max_dpi"`
+	MaxAnalysesPerHour int `json:"max_analyses_per_hour"`
+	MaxModels          int `json:"max_models"`
+}
+
+// payloadKeyQuota are the quotas applying to the key, with the id of the key the admin sets them by
+type payloadKeyQuota struct {
+	KeyId              string `json:"key_id"`
+	MaxDPI             int    `json:"max_dpi"`
+	MaxAnalysesPerHour int    `json:"max_analyses_per_hour"`
+	MaxModels          int    `json:"max_models"`
+}
+
+// requestedDPI is the dpi query parameter (defaulting to the configured one, or when the server limits the pixels of
+// the diagrams to the negated maximum of the DPI auto-scaled per diagram, 0 for no maximum), rejecting negative ones and
+// the ones above the maximum of the key (an empty folder name of the key for the calls of no key)
+func (s *server) requestedDPI(ginContext *gin.Context, folderNameOfKey string) (int, bool) {
+	if _, given := ginContext.GetQuery("dpi"); !given && s.config.DiagramMaxPixels > 0 {
+		return -s.autoScaledMaxDPI(folderNameOfKey), true
+	}
+	dpi, err := strconv.Atoi(ginContext.DefaultQuery("dpi", strconv.Itoa(s.config.GraphvizDPI)))
+	if err != nil {
+		handleErrorInServiceCall(err, ginContext)
+		return 0, false
+	}
+	if dpi < 0 {
+		respondError(ginContext, http.StatusBadRequest, errorCodeBadRequest, "dpi must not be negative")
+		return 0, false
+	}
+	if maxDPI := s.maxDPI(folderNameOfKey); maxDPI > 0 && dpi > maxDPI {
+		respondError(ginContext, http.StatusBadRequest, errorCodeBadRequest, "dpi exceeds the maximum of "+strconv.Itoa(maxDPI)+" allowed by the server")
+		return 0, false
+	}
+	return dpi, true
+}
+
+// checkAnalysisQuota counts a rendering of the key (i.e. an analysis not served from stored results) against its
+// hourly quota
+func (s *server) checkAnalysisQuota(ginContext *gin.Context, folderNameOfKey string) bool {
+	maxAnalysesPerHour := s.maxAnalysesPerHour(folderNameOfKey)
+	if maxAnalysesPerHour <= 0 {
+		return true
+	}
+	s.throttlerLock.Lock()
+	defer s.throttlerLock.Unlock()
+	if s.analysesByFolderName == nil {
+		s.analysesByFolderName = make(map[string][]int64)
+	}
+
+	now := time.Now().UnixNano()
+	cutoff := now - time.Hour.Nanoseconds()
+	for folderName, timestamps := range s.analysesByFolderName {
+		recent := timestamps[:0]
+		for _, timestamp := range timestamps {
+			if timestamp >= cutoff {
+				recent = append(recent, timestamp)
+			}
+		}
+		if len(recent) == 0 {
+			delete(s.analysesByFolderName, folderName)
+		} else {
+			s.analysesByFolderName[folderName] = recent
+		}
+	}
+
+	timestamps := s.analysesByFolderName[folderNameOfKey]
+	if len(timestamps) < maxAnalysesPerHour {
+		s.analysesByFolderName[folderNameOfKey] = append(timestamps, now)
+		return true
+	}
+	retryAfter := time.Duration(timestamps[0]-cutoff) + time.Second // the oldest one counted expires first
+	ginContext.Header("Retry-After", strconv.Itoa(int(retryAfter.Seconds())))
+	respondError(ginContext, http.StatusTooManyRequests, errorCodeQuotaExceeded, "quota of "+strconv.Itoa(maxAnalysesPerHour)+" analyses per hour exceeded: please wait some time and try again (stored results of unchanged models are still served)")
+	return false
+}
+
+// checkModelQuota allows creating another model of the key
+func (s *server) checkModelQuota(ginContext *gin.Context, folderNameOfKey string) bool {
+	maxModelsPerKey := s.maxModelsPerKey(folderNameOfKey)
+	if maxModelsPerKey <= 0 {
+		return true
+	}
+	modelFolders, err := s.storage.ReadDir(folderNameOfKey)
+	if err != nil {
+		log.Println(err)
+		respondError(ginContext, http.StatusInternalServerError, errorCodeInternal, "unable to create model")
+		return false
+	}
+	modelCount := 0
+	for _, modelFolder := range modelFolders {
+		if modelFolder.IsDir() {
+			modelCount++
+		}
+	}
+	if modelCount < maxModelsPerKey {
+		return true
+	}
+	respondError(ginContext, http.StatusForbidden, errorCodeQuotaExceeded, "quota of "+strconv.Itoa(maxModelsPerKey)+" stored models exceeded: please delete some models first")
+	return false
+}
+
+// keyQuota answers the quotas the admin set for the key, none when the folder name of the key is empty
+func (s *server) keyQuota(folderNameOfKey string) payloadQuota {
+	quota := payloadQuota{}
+	if len(folderNameOfKey) == 0 || s.storage == nil {
+		return quota
+	}
+	data, err := s.storage.ReadFile(filepath.Join(folderNameOfKey, keyQuotaFilename))
+	if err != nil {
+		if !os.IsNotExist(err) {
+			log.Println(err)
+		}
+		return quota
+	}
+	err = json.Unmarshal(data, &quota)
+	if err != nil {
+		log.Println(err)
+		return payloadQuota{}
+	}
+	return quota
+}
+
+func (s *server) maxDPI(folderNameOfKey string) int {
+	if quota := s.keyQuota(folderNameOfKey); quota.MaxDPI > 0 {
+		return quota.MaxDPI
+	}
+	return s.config.MaxGraphvizDPI
+}
+
+// autoScaledMaxDPI is the maximum of the DPI auto-scaled for the key: the smaller one of its quota and the maximum of
+// the server
+func (s *server) autoScaledMaxDPI(folderNameOfKey string) int {
+	maxDPI := s.config.MaxGraphvizDPI
+	if quota := s.keyQuota(folderNameOfKey); quota.MaxDPI > 0 && (maxDPI <= 0 || quota.MaxDPI < maxDPI) {
+		maxDPI = quota.MaxDPI
+	}
+	return maxDPI
+}
+
+// getQuota answers the quotas applying to the key of the token
+func (s *server) getQuota(ginContext *gin.Context) {
+	folderNameOfKey, _, ok := s.checkTokenToFolderName(ginContext)
+	if !ok {
+		return
+	}
+	ginContext.JSON(http.StatusOK, payloadKeyQuota{
+		KeyId:              filepath.Base(folderNameOfKey),
+		MaxDPI:             s.maxDPI(folderNameOfKey),
+		MaxAnalysesPerHour: s.maxAnalysesPerHour(folderNameOfKey),
+		MaxModels:          s.maxModelsPerKey(folderNameOfKey),
+	})
+}
+
+// getKeyQuota answers the quotas the admin set for the key
+func (s *server) getKeyQuota(ginContext *gin.Context) {
+	folderNameOfKey, ok := s.checkAdminKeyFolder(ginContext)
+	if !ok {
+		return
+	}
+	ginContext.JSON(http.StatusOK, s.keyQuota(folderNameOfKey))
+}
+
+// setKeyQuota sets the quotas of the key, overriding the ones of its tenant and the server
+func (s *server) setKeyQuota(ginContext *gin.Context) {
+	folderNameOfKey, ok := s.checkAdminKeyFolder(ginContext)
+	if !ok {
+		return
+	}
+	payload := payloadQuota{}
+	err := ginContext.BindJSON(&payload)
+	if err != nil {
+		respondError(ginContext, http.StatusBadRequest, errorCodeInvalidPayload, "unable to parse request payload")
+		return
+	}
+	if payload.MaxDPI < 0 || payload.MaxAnalysesPerHour < 0 || payload.MaxModels < 0 {
+		respondError(ginContext, http.StatusBadRequest, errorCodeBadRequest, "quotas must not be negative")
+		return
+	}
+	data, err := json.Marshal(payload)
+	if err == nil {
+		err = s.storage.WriteFile(filepath.Join(folderNameOfKey, keyQuotaFilename), data)
+	}
+	if err != nil {
+		log.Println(err)
+		respondError(ginContext, http.StatusInternalServerError, errorCodeInternal, "unable to set quota")
+		return
+	}
+	ginContext.JSON(http.StatusOK, payload)
+}
+
+// checkAdminKeyFolder checks the admin key and answers the folder of the key of the key-id parameter
+func (s *server) checkAdminKeyFolder(ginContext *gin.Context) (string, bool) {
+	if !s.checkAdminKey(ginContext) {
+		return "", false
+	}
+	keyId := ginContext.Param("key-id")
+	if keyIdPattern.MatchString(keyId) {
+		for _, root := range s.keyFolderRoots() {
+			folderNameOfKey := filepath.Join(root, keyId)
+			if info, err := s.storage.Stat(folderNameOfKey); err == nil && info.IsDir() {
+				return folderNameOfKey, true
+			}
+		}
+	}
+	respondError(ginContext, http.StatusNotFound, errorCodeNotFound, "key not found")
+	return "", false
+}
@@ -0,0 +1,143 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/threagile/threagile/pkg/common"
+)
+
+func quotaTestContext(target string) (*gin.Context, *httptest.ResponseRecorder) {
+	recorder := httptest.NewRecorder()
+	ginContext, _ := gin.CreateTestContext(recorder)
+	ginContext.Request = httptest.NewRequest(http.MethodGet, target, nil)
+	return ginContext, recorder
+}
+
+func TestRequestedDPI(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	s := &server{config: &common.Config{GraphvizDPI: 120, MaxGraphvizDPI: 200}}
+
+	ginContext, _ := quotaTestContext("/models/x/data-flow-diagram")
+	dpi, ok := s.requestedDPI(ginContext, "")
+	assert.True(t, ok)
+	assert.Equal(t, 120, dpi)
+
+	ginContext, _ = quotaTestContext("/models/x/data-flow-diagram?dpi=200")
+	dpi, ok = s.requestedDPI(ginContext, "")
+	assert.True(t, ok)
+	assert.Equal(t, 200, dpi)
+
+	ginContext, recorder := quotaTestContext("/models/x/data-flow-diagram?dpi=201")
+	_, ok = s.requestedDPI(ginContext, "")
+	assert.False(t, ok)
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+
+	ginContext, recorder = quotaTestContext("/models/x/data-flow-diagram?dpi=-1")
+	_, ok = s.requestedDPI(ginContext, "")
+	assert.False(t, ok)
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+
+	s.config.DiagramMaxPixels = 4000000
+	ginContext, _ = quotaTestContext("/models/x/data-flow-diagram")
+	dpi, ok = s.requestedDPI(ginContext, "")
+	assert.True(t, ok)
+	assert.Equal(t, -200, dpi, "auto-scaled up to the maximum of the server")
+	ginContext, _ = quotaTestContext("/models/x/data-flow-diagram?dpi=150")
+	dpi, ok = s.requestedDPI(ginContext, "")
+	assert.True(t, ok)
+	assert.Equal(t, 150, dpi)
+}
+
+func TestAnalysisQuotaIsPerKey(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	s := &server{config: &common.Config{MaxAnalysesPerHour: 2}}
+
+	for i := 0; i < 2; i++ {
+		ginContext, _ := quotaTestContext("/models/x/report-pdf")
+		assert.True(t, s.checkAnalysisQuota(ginContext, "key-a"))
+	}
+	ginContext, recorder := quotaTestContext("/models/x/report-pdf")
+	assert.False(t, s.checkAnalysisQuota(ginContext, "key-a"))
+	assert.Equal(t, http.StatusTooManyRequests, recorder.Code)
+	assert.NotEmpty(t, recorder.Header().Get("Retry-After"))
+
+	ginContext, _ = quotaTestContext("/models/x/report-pdf")
+	assert.True(t, s.checkAnalysisQuota(ginContext, "key-b"))
+}
+
+func TestModelQuota(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	s := &server{config: &common.Config{MaxModelsPerKey: 1}, storage: newMemoryStorage()}
+	keyFolder := filepath.Join("/data", "keys", "abc")
+	assert.NoError(t, s.storage.MkdirAll(keyFolder))
+
+	ginContext, _ := quotaTestContext("/models")
+	assert.True(t, s.checkModelQuota(ginContext, keyFolder))
+
+	assert.NoError(t, s.storage.Mkdir(filepath.Join(keyFolder, "model")))
+	ginContext, recorder := quotaTestContext("/models")
+	assert.False(t, s.checkModelQuota(ginContext, keyFolder))
+	assert.Equal(t, http.StatusForbidden, recorder.Code)
+}
+
+func TestKeyQuota(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	s := &server{config: &common.Config{ServerFolder: "/data", KeyFolder: "keys", ServerAdminKey: "secret", MaxGraphvizDPI: 200, MaxAnalysesPerHour: 10}, storage: newMemoryStorage()}
+	keyId := strings.Repeat("ab", 64)
+	keyFolder := filepath.Join("/data", "keys", keyId)
+	assert.NoError(t, s.storage.MkdirAll(keyFolder))
+
+	call := func(handler gin.HandlerFunc, method string, adminKey string, keyId string, payload any) *httptest.ResponseRecorder {
+		var body bytes.Buffer
+		if payload != nil {
+			_ = json.NewEncoder(&body).Encode(payload)
+		}
+		recorder := httptest.NewRecorder()
+		ginContext, _ := gin.CreateTestContext(recorder)
+		ginContext.Request = httptest.NewRequest(method, "/meta/quotas/"+keyId, &body)
+		ginContext.Request.Header.Set("admin-key", adminKey)
+		ginContext.Params = gin.Params{{Key: "key-id", Value: keyId}}
+		handler(ginContext)
+		return recorder
+	}
+	assert.Equal(t, http.StatusUnauthorized, call(s.setKeyQuota, http.MethodPut, "wrong", keyId, payloadQuota{MaxDPI: 300}).Code)
+	assert.Equal(t, http.StatusNotFound, call(s.setKeyQuota, http.MethodPut, "secret", strings.Repeat("cd", 64), payloadQuota{MaxDPI: 300}).Code)
+	assert.Equal(t, http.StatusNotFound, call(s.setKeyQuota, http.MethodPut, "secret", "..", payloadQuota{MaxDPI: 300}).Code)
+	assert.Equal(t, http.StatusBadRequest, call(s.setKeyQuota, http.MethodPut, "secret", keyId, payloadQuota{MaxModels: -1}).Code)
+
+	assert.Equal(t, http.StatusOK, call(s.setKeyQuota, http.MethodPut, "secret", keyId, payloadQuota{MaxDPI: 300}).Code)
+	recorder := call(s.getKeyQuota, http.MethodGet, "secret", keyId, nil)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	var quota payloadQuota
+	assert.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &quota))
+	assert.Equal(t, payloadQuota{MaxDPI: 300}, quota)
+
+	ginContext, _ := quotaTestContext("/models/x/data-flow-diagram?dpi=300")
+	dpi, ok := s.requestedDPI(ginContext, keyFolder)
+	assert.True(t, ok, "the quota of the key overrides the one of the server")
+	assert.Equal(t, 300, dpi)
+	ginContext, recorder = quotaTestContext("/models/x/data-flow-diagram?dpi=300")
+	_, ok = s.requestedDPI(ginContext, filepath.Join("/data", "keys", "other"))
+	assert.False(t, ok)
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+	assert.Equal(t, 10, s.maxAnalysesPerHour(keyFolder), "not overridden")
+
+	s.config.DiagramMaxPixels = 4000000
+	ginContext, _ = quotaTestContext("/models/x/data-flow-diagram")
+	dpi, ok = s.requestedDPI(ginContext, keyFolder)
+	assert.True(t, ok)
+	assert.Equal(t, -200, dpi, "auto-scaled up to the smaller maximum of the server")
+	assert.Equal(t, http.StatusOK, call(s.setKeyQuota, http.MethodPut, "secret", keyId, payloadQuota{MaxDPI: 100}).Code)
+	ginContext, _ = quotaTestContext("/models/x/data-flow-diagram")
+	dpi, ok = s.requestedDPI(ginContext, keyFolder)
+	assert.True(t, ok)
+	assert.Equal(t, -100, dpi, "auto-scaled up to the smaller maximum of the key")
+}
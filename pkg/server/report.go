@@ -5,14 +5,24 @@ Copyright © 2023 NAME HERE <EMAIL ADDRESS>
 package server
 
 import (
+	"context"
+	"encoding/json"
+	"fmt"
 	"log"
 	"net/http"
 	"os"
 	"path/filepath"
+	"runtime/debug"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/threagile/threagile/pkg/common"
+	"github.com/threagile/threagile/pkg/input"
+	"github.com/threagile/threagile/pkg/model"
+	"github.com/threagile/threagile/pkg/report"
+	"github.com/threagile/threagile/pkg/security/types"
 )
 
 type responseType int
@@ -26,8 +36,30 @@ const (
 	risksJSON
 	technicalAssetsJSON
 	statsJSON
+	riskMatrix
+	dataFlowDiagramDOT
+	dataAssetDiagramDOT
+	risksByCategoryJSON
+	risksByTrustBoundaryJSON
+	riskBadge
+	risksSARIF
+	dueDatesICS
 )
 
+// risksOfCategory is the intermediate structure the report chapters are rendered from: the risks of a single category
+// sorted by severity, with the categories sorted by their highest still-at-risk severity
+type risksOfCategory struct {
+	Category *types.RiskCategory `json:"category"`
+	Risks    []*types.Risk       `json:"risks"`
+}
+
+// risksOfTrustBoundary is the slice of risks located in a single trust boundary (see types.Risk.TrustBoundaryId) for
+// the teams responsible for it, the risks outside any trust boundary have no trust boundary
+type risksOfTrustBoundary struct {
+	TrustBoundary *types.TrustBoundary `json:"trust_boundary"`
+	Risks         []*types.Risk        `json:"risks"`
+}
+
 func (s *server) streamDataFlowDiagram(ginContext *gin.Context) {
 	s.streamResponse(ginContext, dataFlowDiagram)
 }
@@ -60,6 +92,30 @@ func (s *server) streamStatsJSON(ginContext *gin.Context) {
 	s.streamResponse(ginContext, statsJSON)
 }
 
+func (s *server) streamRiskMatrix(ginContext *gin.Context) {
+	s.streamResponse(ginContext, riskMatrix)
+}
+
+func (s *server) streamDataFlowDiagramDOT(ginContext *gin.Context) {
+	s.streamResponse(ginContext, dataFlowDiagramDOT)
+}
+
+func (s *server) streamDataAssetDiagramDOT(ginContext *gin.Context) {
+	s.streamResponse(ginContext, dataAssetDiagramDOT)
+}
+
+func (s *server) streamRisksByCategoryJSON(ginContext *gin.Context) {
+	s.streamResponse(ginContext, risksByCategoryJSON)
+}
+
+func (s *server) streamRisksByTrustBoundaryJSON(ginContext *gin.Context) {
+	s.streamResponse(ginContext, risksByTrustBoundaryJSON)
+}
+
+func (s *server) streamRisksSARIF(ginContext *gin.Context) {
+	s.streamResponse(ginContext, risksSARIF)
+}
+
 func (s *server) streamResponse(ginContext *gin.Context, responseType responseType) {
 	folderNameOfKey, key, ok := s.checkTokenToFolderName(ginContext)
 	if !ok {
@@ -75,71 +131,108 @@ func (s *server) streamResponse(ginContext *gin.Context, responseType responseTy
 				log.Println(err)
 			}
 			log.Println(err)
-			ginContext.JSON(http.StatusBadRequest, gin.H{
-				"error": strings.TrimSpace(err.Error()),
-			})
+			respondError(ginContext, http.StatusBadRequest, errorCodeBadRequest, strings.TrimSpace(err.Error()))
 			ok = false
 		}
 	}()
-	dpi, err := strconv.Atoi(ginContext.DefaultQuery("dpi", strconv.Itoa(s.config.GraphvizDPI)))
+	dpi, ok := s.requestedDPI(ginContext, folderNameOfKey)
+	if !ok {
+		return
+	}
+	modelInput, yamlText, ok := s.readModel(ginContext, ginContext.Param("model-id"), key, folderNameOfKey)
+	if !ok {
+		return
+	}
+	modelFolder := folderNameForModel(folderNameOfKey, ginContext.Param("model-id"))
+	versionText, commentsData, err := s.outputVersion(modelFolder, key, yamlText)
 	if err != nil {
 		handleErrorInServiceCall(err, ginContext)
 		return
 	}
-	_, yamlText, ok := s.readModel(ginContext, ginContext.Param("model-id"), key, folderNameOfKey)
-	if !ok {
+	if s.notModified(ginContext, modelFolder, responseType, versionText, dpi) {
+		return
+	}
+	workspace, err := newTempWorkspace(s.config.TempFolder, "render")
+	if err != nil {
+		handleErrorInServiceCall(err, ginContext)
+		return
+	}
+	defer workspace.Close()
+	err = writeRiskCommentsFile(workspace, commentsData)
+	if err != nil {
+		handleErrorInServiceCall(err, ginContext)
 		return
 	}
-	tmpModelFile, err := os.CreateTemp(s.config.TempFolder, "threagile-render-*")
+	tmpModelFile, err := workspace.CreateFile("threagile-render-*")
 	if err != nil {
 		handleErrorInServiceCall(err, ginContext)
 		return
 	}
-	defer func() { _ = os.Remove(tmpModelFile.Name()) }()
-	tmpOutputDir, err := os.MkdirTemp(s.config.TempFolder, "threagile-render-")
+	tmpOutputDir, err := workspace.Mkdir("output")
 	if err != nil {
 		handleErrorInServiceCall(err, ginContext)
 		return
 	}
-	defer func() { _ = os.RemoveAll(tmpOutputDir) }()
+	restored := false
+	if filename := s.storedResultFilename(responseType); len(filename) > 0 {
+		restored, err = s.restoreAnalysisResultFile(workspace, modelFolder, key, s.resultHash(versionText, dpi), tmpOutputDir, filename)
+		if err != nil {
+			log.Println(err)
+		}
+	}
+	if !restored && !s.checkAnalysisQuota(ginContext, folderNameOfKey) {
+		return
+	}
 	err = os.WriteFile(tmpModelFile.Name(), []byte(yamlText), 0400)
 	if responseType == dataFlowDiagram {
-		s.doItViaRuntimeCall(tmpModelFile.Name(), tmpOutputDir, true, false, false, false, false, false, false, false, dpi)
+		if !restored {
+			s.doItViaRuntimeCall(ginContext.Request.Context(), workspace, tmpModelFile.Name(), tmpOutputDir, true, false, false, false, false, false, false, false, false, dpi)
+		}
 		if err != nil {
 			handleErrorInServiceCall(err, ginContext)
 			return
 		}
 		ginContext.File(filepath.Clean(filepath.Join(tmpOutputDir, s.config.DataFlowDiagramFilenamePNG)))
 	} else if responseType == dataAssetDiagram {
-		s.doItViaRuntimeCall(tmpModelFile.Name(), tmpOutputDir, false, true, false, false, false, false, false, false, dpi)
+		if !restored {
+			s.doItViaRuntimeCall(ginContext.Request.Context(), workspace, tmpModelFile.Name(), tmpOutputDir, false, true, false, false, false, false, false, false, false, dpi)
+		}
 		if err != nil {
 			handleErrorInServiceCall(err, ginContext)
 			return
 		}
 		ginContext.File(filepath.Clean(filepath.Join(tmpOutputDir, s.config.DataAssetDiagramFilenamePNG)))
 	} else if responseType == reportPDF {
-		s.doItViaRuntimeCall(tmpModelFile.Name(), tmpOutputDir, false, false, true, false, false, false, false, false, dpi)
+		if !restored {
+			s.doItViaRuntimeCall(ginContext.Request.Context(), workspace, tmpModelFile.Name(), tmpOutputDir, false, false, true, false, false, false, false, false, false, dpi)
+		}
 		if err != nil {
 			handleErrorInServiceCall(err, ginContext)
 			return
 		}
 		ginContext.FileAttachment(filepath.Clean(filepath.Join(tmpOutputDir, s.config.ReportFilename)), s.config.ReportFilename)
 	} else if responseType == risksExcel {
-		s.doItViaRuntimeCall(tmpModelFile.Name(), tmpOutputDir, false, false, false, true, false, false, false, false, dpi)
+		if !restored {
+			s.doItViaRuntimeCall(ginContext.Request.Context(), workspace, tmpModelFile.Name(), tmpOutputDir, false, false, false, true, false, false, false, false, false, dpi)
+		}
 		if err != nil {
 			handleErrorInServiceCall(err, ginContext)
 			return
 		}
 		ginContext.FileAttachment(filepath.Clean(filepath.Join(tmpOutputDir, s.config.ExcelRisksFilename)), s.config.ExcelRisksFilename)
 	} else if responseType == tagsExcel {
-		s.doItViaRuntimeCall(tmpModelFile.Name(), tmpOutputDir, false, false, false, false, true, false, false, false, dpi)
+		if !restored {
+			s.doItViaRuntimeCall(ginContext.Request.Context(), workspace, tmpModelFile.Name(), tmpOutputDir, false, false, false, false, true, false, false, false, false, dpi)
+		}
 		if err != nil {
 			handleErrorInServiceCall(err, ginContext)
 			return
 		}
 		ginContext.FileAttachment(filepath.Clean(filepath.Join(tmpOutputDir, s.config.ExcelTagsFilename)), s.config.ExcelTagsFilename)
 	} else if responseType == risksJSON {
-		s.doItViaRuntimeCall(tmpModelFile.Name(), tmpOutputDir, false, false, false, false, false, true, false, false, dpi)
+		if !restored {
+			s.doItViaRuntimeCall(ginContext.Request.Context(), workspace, tmpModelFile.Name(), tmpOutputDir, false, false, false, false, false, true, false, false, false, dpi)
+		}
 		if err != nil {
 			handleErrorInServiceCall(err, ginContext)
 			return
@@ -149,9 +242,25 @@ func (s *server) streamResponse(ginContext *gin.Context, responseType responseTy
 			handleErrorInServiceCall(err, ginContext)
 			return
 		}
+		if owner := strings.TrimSpace(ginContext.Query("owner")); len(owner) > 0 {
+			jsonData, err = filterRisksByOwner(jsonData, modelInput, owner)
+			if err != nil {
+				handleErrorInServiceCall(err, ginContext)
+				return
+			}
+		}
+		if assignee := strings.TrimSpace(ginContext.Query("assignee")); len(assignee) > 0 {
+			jsonData, err = filterRisksByAssignee(jsonData, assignee)
+			if err != nil {
+				handleErrorInServiceCall(err, ginContext)
+				return
+			}
+		}
 		ginContext.Data(http.StatusOK, "application/json", jsonData) // stream directly with JSON content-type in response instead of file download
 	} else if responseType == technicalAssetsJSON {
-		s.doItViaRuntimeCall(tmpModelFile.Name(), tmpOutputDir, false, false, false, false, false, true, true, false, dpi)
+		if !restored {
+			s.doItViaRuntimeCall(ginContext.Request.Context(), workspace, tmpModelFile.Name(), tmpOutputDir, false, false, false, false, false, true, true, false, false, dpi)
+		}
 		if err != nil {
 			handleErrorInServiceCall(err, ginContext)
 			return
@@ -163,7 +272,9 @@ func (s *server) streamResponse(ginContext *gin.Context, responseType responseTy
 		}
 		ginContext.Data(http.StatusOK, "application/json", jsonData) // stream directly with JSON content-type in response instead of file download
 	} else if responseType == statsJSON {
-		s.doItViaRuntimeCall(tmpModelFile.Name(), tmpOutputDir, false, false, false, false, false, false, false, true, dpi)
+		if !restored {
+			s.doItViaRuntimeCall(ginContext.Request.Context(), workspace, tmpModelFile.Name(), tmpOutputDir, false, false, false, false, false, false, false, true, false, dpi)
+		}
 		if err != nil {
 			handleErrorInServiceCall(err, ginContext)
 			return
@@ -174,5 +285,311 @@ func (s *server) streamResponse(ginContext *gin.Context, responseType responseTy
 			return
 		}
 		ginContext.Data(http.StatusOK, "application/json", jsonData) // stream directly with JSON content-type in response instead of file download
+	} else if responseType == riskMatrix {
+		if !restored {
+			s.doItViaRuntimeCall(ginContext.Request.Context(), workspace, tmpModelFile.Name(), tmpOutputDir, false, false, false, false, false, false, false, false, true, dpi)
+		}
+		if err != nil {
+			handleErrorInServiceCall(err, ginContext)
+			return
+		}
+		ginContext.File(filepath.Clean(filepath.Join(tmpOutputDir, s.config.RiskMatrixFilenamePNG)))
+	} else if responseType == dataFlowDiagramDOT || responseType == dataAssetDiagramDOT {
+		// the DOT sources are only text, so they are generated in-process without the graphviz rendering
+		readResult, err := s.analyzeInProcess(ginContext.Request.Context(), workspace, tmpModelFile.Name(), tmpOutputDir)
+		if err != nil {
+			handleErrorInServiceCall(err, ginContext)
+			return
+		}
+		theme, err := report.LoadDiagramTheme(s.config.DiagramTheme)
+		if err != nil {
+			handleErrorInServiceCall(err, ginContext)
+			return
+		}
+		reporter := common.DefaultProgressReporter{Verbose: s.config.Verbose}
+		dotFilename := filepath.Join(tmpOutputDir, s.config.DataFlowDiagramFilenameDOT)
+		autoScaled := *s.config
+		autoScaled.MaxGraphvizDPI = -dpi // the maximum of the key when the DPI is auto-scaled
+		if responseType == dataFlowDiagramDOT {
+			if dpi <= 0 {
+				dpi = report.DataFlowDiagramDPI(&autoScaled, readResult.ParsedModel)
+			}
+			err = report.WriteDataFlowDiagramGraphvizDOT(common.OSFileSystem{}, readResult.ParsedModel, dotFilename, dpi, false, theme, reporter)
+		} else {
+			if dpi <= 0 {
+				dpi = report.DataAssetDiagramDPI(&autoScaled, readResult.ParsedModel)
+			}
+			dotFilename = filepath.Join(tmpOutputDir, s.config.DataAssetDiagramFilenameDOT)
+			err = report.WriteDataAssetDiagramGraphvizDOT(common.OSFileSystem{}, readResult.ParsedModel, dotFilename, dpi, theme, reporter)
+		}
+		if err != nil {
+			handleErrorInServiceCall(err, ginContext)
+			return
+		}
+		dotData, err := os.ReadFile(filepath.Clean(dotFilename))
+		if err != nil {
+			handleErrorInServiceCall(err, ginContext)
+			return
+		}
+		ginContext.Data(http.StatusOK, "text/vnd.graphviz", dotData)
+	} else if responseType == risksByCategoryJSON {
+		readResult, err := s.analyzeInProcess(ginContext.Request.Context(), workspace, tmpModelFile.Name(), tmpOutputDir)
+		if err != nil {
+			handleErrorInServiceCall(err, ginContext)
+			return
+		}
+		categories := make([]risksOfCategory, 0)
+		for _, category := range types.SortedRiskCategories(readResult.ParsedModel) {
+			categories = append(categories, risksOfCategory{
+				Category: category,
+				Risks:    types.SortedRisksOfCategory(readResult.ParsedModel, category),
+			})
+		}
+		ginContext.JSON(http.StatusOK, categories)
+	} else if responseType == risksByTrustBoundaryJSON {
+		readResult, err := s.analyzeInProcess(ginContext.Request.Context(), workspace, tmpModelFile.Name(), tmpOutputDir)
+		if err != nil {
+			handleErrorInServiceCall(err, ginContext)
+			return
+		}
+		boundaries := make([]risksOfTrustBoundary, 0)
+		if trustBoundaryId, filtered := ginContext.GetQuery("trust-boundary"); filtered {
+			// a single slice for the teams responsible for the trust boundary, an empty id selects the risks outside
+			trustBoundary, ok := readResult.ParsedModel.TrustBoundaries[trustBoundaryId]
+			if len(trustBoundaryId) > 0 && !ok {
+				respondError(ginContext, http.StatusNotFound, errorCodeNotFound, "trust boundary not found")
+				return
+			}
+			boundaries = append(boundaries, risksOfTrustBoundary{
+				TrustBoundary: trustBoundary,
+				Risks:         types.SortedRisksOfTrustBoundary(readResult.ParsedModel, trustBoundaryId),
+			})
+		} else {
+			for _, trustBoundaryId := range types.SortedKeysOfTrustBoundaries(readResult.ParsedModel) {
+				boundaries = append(boundaries, risksOfTrustBoundary{
+					TrustBoundary: readResult.ParsedModel.TrustBoundaries[trustBoundaryId],
+					Risks:         types.SortedRisksOfTrustBoundary(readResult.ParsedModel, trustBoundaryId),
+				})
+			}
+			if outside := types.SortedRisksOfTrustBoundary(readResult.ParsedModel, ""); len(outside) > 0 {
+				boundaries = append(boundaries, risksOfTrustBoundary{Risks: outside})
+			}
+		}
+		ginContext.JSON(http.StatusOK, boundaries)
+	} else if responseType == risksSARIF {
+		// the SARIF log is only text as well, so it is generated in-process
+		readResult, err := s.analyzeInProcess(ginContext.Request.Context(), workspace, tmpModelFile.Name(), tmpOutputDir)
+		if err != nil {
+			handleErrorInServiceCall(err, ginContext)
+			return
+		}
+		sarifFile := filepath.Join(tmpOutputDir, s.config.SarifRisksFilename)
+		err = report.WriteRisksSARIF(common.OSFileSystem{}, readResult.ParsedModel, s.config.InputFile, sarifFile)
+		if err != nil {
+			handleErrorInServiceCall(err, ginContext)
+			return
+		}
+		sarifData, err := os.ReadFile(filepath.Clean(sarifFile))
+		if err != nil {
+			handleErrorInServiceCall(err, ginContext)
+			return
+		}
+		ginContext.Data(http.StatusOK, mimeSARIF, sarifData)
+	}
+}
+
+// notModified sets the caching headers of the response (an ETag of the model version, the query options, the response
+// type, the analysis settings of the server and the day as well as the last modification of the model, but not before
+// today or the start of the server) and answers conditional requests matching them with 304, so
+// clients and proxies do not trigger a full re-render of an unchanged model
+func (s *server) notModified(ginContext *gin.Context, modelFolder string, responseType responseType, yamlText string, dpi int) bool {
+	etag := `"` + s.resultHash(yamlText+"\n"+strconv.Itoa(int(responseType))+"\n"+ginContext.Request.URL.Query().Encode(), dpi) + `"`
+	ginContext.Header("ETag", etag)
+	ginContext.Header("Cache-Control", "private, no-cache")
+
+	var lastModified time.Time
+	if info, err := s.storage.Stat(filepath.Join(modelFolder, s.config.InputFile)); err == nil {
+		lastModified = info.ModTime().UTC().Truncate(time.Second)
+		year, month, day := time.Now().Date()
+		if today := time.Date(year, month, day, 0, 0, 0, 0, time.Local).UTC(); lastModified.Before(today) {
+			lastModified = today // risks may have turned overdue since
+		}
+		if lastModified.Before(s.startedAt) {
+			lastModified = s.startedAt // the config or version of the server may have changed since
+		}
+		ginContext.Header("Last-Modified", lastModified.Format(http.TimeFormat))
+	}
+
+	if ifNoneMatch := ginContext.GetHeader("If-None-Match"); len(ifNoneMatch) > 0 {
+		for _, candidate := range strings.Split(ifNoneMatch, ",") {
+			candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+			if candidate == etag || candidate == "*" {
+				ginContext.Status(http.StatusNotModified)
+				return true
+			}
+		}
+		return false // If-Modified-Since is ignored when If-None-Match is present
+	}
+
+	if ifModifiedSince := ginContext.GetHeader("If-Modified-Since"); len(ifModifiedSince) > 0 && !lastModified.IsZero() {
+		since, err := http.ParseTime(ifModifiedSince)
+		if err == nil && !lastModified.After(since) {
+			ginContext.Status(http.StatusNotModified)
+			return true
+		}
+	}
+	return false
+}
+
+// storedResultFilename is the file of the stored analysis result (see analyzeModelOnServerDirectly) serving the
+// response, empty for responses which are not part of the stored result
+func (s *server) storedResultFilename(responseType responseType) string {
+	switch responseType {
+	case dataFlowDiagram:
+		return s.config.DataFlowDiagramFilenamePNG
+	case dataAssetDiagram:
+		return s.config.DataAssetDiagramFilenamePNG
+	case reportPDF:
+		return s.config.ReportFilename
+	case risksExcel:
+		return s.config.ExcelRisksFilename
+	case tagsExcel:
+		return s.config.ExcelTagsFilename
+	case risksJSON:
+		return s.config.JsonRisksFilename
+	case technicalAssetsJSON:
+		return s.config.JsonTechnicalAssetsFilename
+	case statsJSON:
+		return s.config.JsonStatsFilename
+	case riskMatrix:
+		return s.config.RiskMatrixFilenamePNG
+	}
+	return ""
+}
+
+// analyzeInProcess reads and analyzes the model like the sub-process does, for the intermediate artifacts which
+// do not involve any third party rendering
+func (s *server) analyzeInProcess(ctx context.Context, workspace *TempWorkspace, modelFile string, outputDir string) (*model.ReadResult, error) {
+	return s.analyzeInProcessWithRules(ctx, workspace, modelFile, outputDir, nil)
+}
+
+// analyzeInProcessWithRules is analyzeInProcess running only the given risk rules (all of them if empty)
+func (s *server) analyzeInProcessWithRules(ctx context.Context, workspace *TempWorkspace, modelFile string, outputDir string, onlyRiskRules []string) (*model.ReadResult, error) {
+	config := *s.config
+	if len(onlyRiskRules) > 0 {
+		config.OnlyRiskRules = onlyRiskRules
+		config.IgnoreOrphanedRiskTracking = true // the tracking of the risks of the rules not run is orphaned then
+	}
+	config.FileSystem = nil // as the workspaces are on the file system of the OS, the injected one is the server storage
+	// the models are sent by the clients, which must neither read the environment of the server nor run its commands
+	config.ModelTemplating = ""
+	config.PreParseHooks, config.PostAnalysisHooks = nil, nil
+	config.TempFolder = workspace.Dir
+	config.RiskCommentsFile = riskCommentsFileOf(workspace)
+	config.InputFile = modelFile
+	config.OutputFolder = outputDir
+	reporter := inProcessReporter{DefaultProgressReporter: common.DefaultProgressReporter{Verbose: s.config.Verbose}, errors: new([]string)}
+	readResult, err := analyzeGuarded(ctx, &config, reporter)
+	if err == nil && len(*reporter.errors) > 0 {
+		return nil, fmt.Errorf("analysis failed: %v", strings.Join(*reporter.errors, "; "))
+	}
+	return readResult, err
+}
+
+// inProcessReporter reports like the one of the sub-process, but keeps the errors (e.g. of risk rules, plugins or
+// report sections failing to load) for failing the request instead of exiting like the sub-process does
+type inProcessReporter struct {
+	common.DefaultProgressReporter
+	errors *[]string
+}
+
+func (r inProcessReporter) Error(a ...any) {
+	r.addError(fmt.Sprint(a...))
+}
+
+func (r inProcessReporter) Errorf(format string, a ...any) {
+	r.addError(fmt.Sprintf(format, a...))
+}
+
+func (r inProcessReporter) addError(message string) {
+	*r.errors = append(*r.errors, strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(message), "WARNING:")))
+}
+
+// readAndAnalyzeModel is the analysis run by analyzeGuarded (replaced by tests)
+var readAndAnalyzeModel = model.ReadAndAnalyzeModel
+
+// analyzeGuarded runs the analysis in the server process: the plugins, rego policies and the opa tool still run as
+// processes of their own (killed with the context) and the script rules in their own interpreter, but the analysis
+// itself is guarded, so that neither a crash nor a hang of it takes down the server or blocks the request beyond the
+// analysis timeout
+func analyzeGuarded(ctx context.Context, config *common.Config, progressReporter types.ProgressReporter) (*model.ReadResult, error) {
+	ctx, cancel := common.WithTimeout(ctx, config.AnalysisTimeoutSeconds)
+	defer cancel()
+
+	type outcome struct {
+		readResult *model.ReadResult
+		err        error
+	}
+	done := make(chan outcome, 1) // buffered, so that an analysis finishing after the timeout does not block forever
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				log.Printf("analysis crashed: %v\n%s", r, debug.Stack())
+				done <- outcome{err: fmt.Errorf("analysis crashed: %v", r)}
+			}
+		}()
+		readResult, err := readAndAnalyzeModel(ctx, config, progressReporter)
+		done <- outcome{readResult: readResult, err: err}
+	}()
+
+	select {
+	case result := <-done:
+		return result.readResult, result.err
+	case <-ctx.Done():
+		return nil, common.CheckCanceled(ctx, "analysis")
+	}
+}
+
+func filterRisksByOwner(jsonData []byte, modelInput input.Model, owner string) ([]byte, error) {
+	var allRisks []*types.Risk
+	err := json.Unmarshal(jsonData, &allRisks)
+	if err != nil {
+		return nil, err
+	}
+
+	// only the owners of the assets are needed to assign risks to owners
+	ownerModel := &types.Model{
+		TechnicalAssets: make(map[string]*types.TechnicalAsset),
+		DataAssets:      make(map[string]*types.DataAsset),
+	}
+	for _, asset := range modelInput.TechnicalAssets {
+		ownerModel.TechnicalAssets[asset.ID] = &types.TechnicalAsset{Id: asset.ID, Owner: asset.Owner}
+	}
+	for _, asset := range modelInput.DataAssets {
+		ownerModel.DataAssets[asset.ID] = &types.DataAsset{Id: asset.ID, Owner: asset.Owner}
+	}
+
+	ownerRisks := make([]*types.Risk, 0)
+	for _, risk := range allRisks {
+		if strings.EqualFold(risk.Owner(ownerModel), owner) {
+			ownerRisks = append(ownerRisks, risk)
+		}
+	}
+	return json.Marshal(ownerRisks)
+}
+
+func filterRisksByAssignee(jsonData []byte, assignee string) ([]byte, error) {
+	var allRisks []*types.Risk
+	err := json.Unmarshal(jsonData, &allRisks)
+	if err != nil {
+		return nil, err
+	}
+
+	assignedRisks := make([]*types.Risk, 0)
+	for _, risk := range allRisks {
+		if strings.EqualFold(risk.Assignee, assignee) {
+			assignedRisks = append(assignedRisks, risk)
+		}
 	}
+	return json.Marshal(assignedRisks)
 }
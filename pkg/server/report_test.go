@@ -0,0 +1,34 @@
+package server
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/threagile/threagile/pkg/input"
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+func TestFilterRisksByOwner(t *testing.T) {
+	modelInput := input.Model{
+		TechnicalAssets: map[string]input.TechnicalAsset{"Shop": {ID: "shop", Owner: "Team Shop"}},
+		DataAssets:      map[string]input.DataAsset{"Customers": {ID: "customers", Owner: "Team CRM"}},
+	}
+	jsonData, err := json.Marshal([]*types.Risk{
+		{SyntheticId: "rule@shop", MostRelevantTechnicalAssetId: "shop"},
+		{SyntheticId: "rule@customers", MostRelevantDataAssetId: "customers"},
+	})
+	assert.NoError(t, err)
+
+	filtered, err := filterRisksByOwner(jsonData, modelInput, "team crm")
+	assert.NoError(t, err)
+	var risks []*types.Risk
+	assert.NoError(t, json.Unmarshal(filtered, &risks))
+	assert.Len(t, risks, 1)
+	assert.Equal(t, "rule@customers", risks[0].SyntheticId)
+
+	filtered, err = filterRisksByOwner(jsonData, modelInput, "Team Nobody")
+	assert.NoError(t, err)
+	assert.Equal(t, "[]", string(filtered))
+}
@@ -0,0 +1,206 @@
+/*
+Copyright © 2023 NAME HERE <EMAIL ADDRESS>
+*/
+
+package server
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/threagile/threagile/pkg/common"
+	"github.com/threagile/threagile/pkg/docs"
+)
+
+// analysisResultsToKeep is the number of result versions kept per model, older ones are removed when a new one is stored
+const analysisResultsToKeep = 5
+
+// resultHash identifies the analysis result of a model version rendered with the given options and the analysis settings
+// of the server (so that results survive neither a change of its config nor an upgrade) today, as the due dates of the
+// risk tracking turn overdue from one day to the next
+func (s *server) resultHash(yamlText string, dpi int) string {
+	return resultHashOn(yamlText, dpi, s.analysisSettings(), time.Now())
+}
+
+func resultHashOn(yamlText string, dpi int, settings string, day time.Time) string {
+	return hashSHA256([]byte(yamlText + "\n" + strconv.Itoa(dpi) + "\n" + settings + "\n" + day.Format("2006-01-02")))
+}
+
+// analysisSettings answers the version of the server and its config affecting the analysis results (not the content of
+// plugins and rule files though, which changes with their file names or a restart of the server only)
+func (s *server) analysisSettings() string {
+	settings, _ := json.Marshal(struct {
+		Version                    string
+		BuildTimestamp             string
+		RAAPlugin                  string
+		OwnerDirectoryPlugin       string
+		OwnerDirectoryStrict       bool
+		StrictRules                bool
+		RiskRulesPlugins           []string
+		RiskRulesRegoFolder        string
+		RiskRulesScripts           []string
+		ReportSectionPlugins       []string
+		SkipRiskRules              []string
+		OnlyRiskRules              []string
+		RiskCategoryOverridesFile  string
+		RiskExcel                  common.RiskExcelConfig
+		RiskMerge                  common.RiskMergeConfig
+		ThreatIntelFeed            string
+		SeverityRecalibration      map[string]int
+		TechnologyFilename         string
+		TagTaxonomyFilename        string
+		DiagramMaxPixels           int
+		DiagramTheme               string
+		DiagramVariants            []string
+		FontFile                   string
+		MaxGraphvizDPI             int
+		AddModelTitle              bool
+		KeepDiagramSourceFiles     bool
+		IgnoreOrphanedRiskTracking bool
+		Attractiveness             common.Attractiveness
+	}{
+		docs.ThreagileVersion, s.config.BuildTimestamp, s.config.RAAPlugin, s.config.OwnerDirectoryPlugin,
+		s.config.OwnerDirectoryStrict, s.config.StrictRules, s.config.RiskRulesPlugins, s.config.RiskRulesRegoFolder,
+		s.config.RiskRulesScripts, s.config.ReportSectionPlugins, s.config.SkipRiskRules, s.config.OnlyRiskRules,
+		s.config.RiskCategoryOverridesFile, s.config.RiskExcel, s.config.RiskMerge, s.config.ThreatIntelFeed,
+		s.config.SeverityRecalibration, s.config.TechnologyFilename, s.config.TagTaxonomyFilename,
+		s.config.DiagramMaxPixels, s.config.DiagramTheme, s.config.DiagramVariants, s.config.FontFile,
+		s.config.MaxGraphvizDPI, s.config.AddModelTitle, s.config.KeepDiagramSourceFiles,
+		s.config.IgnoreOrphanedRiskTracking, s.config.Attractiveness,
+	})
+	return string(settings)
+}
+
+func resultFilename(modelFolder string, hash string) string {
+	return filepath.Join(modelFolder, "results", hash+".result")
+}
+
+// storeAnalysisResult persists the zipped analysis result encrypted alongside the model
+func (s *server) storeAnalysisResult(modelFolder string, key []byte, hash string, zipFile string) error {
+	resultsFolder := filepath.Join(modelFolder, "results")
+	err := s.storage.MkdirAll(resultsFolder)
+	if err != nil {
+		return err
+	}
+
+	plaintext, err := os.ReadFile(filepath.Clean(zipFile))
+	if err != nil {
+		return err
+	}
+
+	ciphertext, err := s.encryptModelData(modelFolder, key, plaintext)
+	if err != nil {
+		return err
+	}
+
+	err = s.storage.WriteFile(resultFilename(modelFolder, hash), ciphertext)
+	if err != nil {
+		return err
+	}
+
+	// now delete the oldest results if over limit to keep
+	infos, err := s.storage.ReadDir(resultsFolder)
+	if err != nil {
+		return err
+	}
+	if len(infos) <= analysisResultsToKeep {
+		return nil
+	}
+	sort.Slice(infos, func(i, j int) bool {
+		return infos[i].ModTime().Before(infos[j].ModTime())
+	})
+	for _, info := range infos[:len(infos)-analysisResultsToKeep] {
+		err = s.storage.Remove(filepath.Join(resultsFolder, info.Name()))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// restoreAnalysisResult decrypts the stored analysis result into the zip file, returning false when no result was
+// stored for this model version
+func (s *server) restoreAnalysisResult(modelFolder string, key []byte, hash string, zipFile string) (bool, error) {
+	ciphertext, err := s.storage.ReadFile(resultFilename(modelFolder, hash))
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	plaintext, err := s.decryptModelData(modelFolder, key, ciphertext)
+	if err != nil {
+		return false, err
+	}
+
+	err = os.WriteFile(filepath.Clean(zipFile), plaintext, 0600)
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// restoreAnalysisResultFile extracts the stored analysis result into the output folder, returning false when no result
+// containing the file was stored for this model version
+func (s *server) restoreAnalysisResultFile(workspace *TempWorkspace, modelFolder string, key []byte, hash string, outputDir string, filename string) (bool, error) {
+	tmpResultFile, err := workspace.CreateFile("threagile-result-*.zip")
+	if err != nil {
+		return false, err
+	}
+	_ = tmpResultFile.Close()
+
+	found, err := s.restoreAnalysisResult(modelFolder, key, hash, tmpResultFile.Name())
+	if !found || err != nil {
+		return false, err
+	}
+
+	_, err = unzip(tmpResultFile.Name(), outputDir)
+	if err != nil {
+		return false, err
+	}
+
+	_, err = os.Stat(filepath.Join(outputDir, filename))
+	return err == nil, nil
+}
+
+func encryptWithKey(cryptoKey []byte, plaintext []byte) ([]byte, error) {
+	block, err := aes.NewCipher(cryptoKey)
+	if err != nil {
+		return nil, err
+	}
+	aesGcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	// Never use more than 2^32 random nonces with a given key because of the risk of a repeat.
+	nonce := make([]byte, 12)
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+	return aesGcm.Seal(nonce, nonce, plaintext, nil), nil
+}
+
+func decryptWithKey(cryptoKey []byte, data []byte) ([]byte, error) {
+	block, err := aes.NewCipher(cryptoKey)
+	if err != nil {
+		return nil, err
+	}
+	aesGcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) < 12 {
+		return nil, fmt.Errorf("stored result too short")
+	}
+	return aesGcm.Open(nil, data[0:12], data[12:], nil)
+}
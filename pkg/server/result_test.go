@@ -0,0 +1,88 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResultHashChangesWithTheDay(t *testing.T) {
+	today := time.Date(2024, 3, 1, 8, 0, 0, 0, time.Local)
+	assert.Equal(t, resultHashOn("title: a", 120, "", today), resultHashOn("title: a", 120, "", today.Add(time.Hour)))
+	assert.NotEqual(t, resultHashOn("title: a", 120, "", today), resultHashOn("title: a", 120, "", today.AddDate(0, 0, 1)), "risks may turn overdue")
+	assert.NotEqual(t, resultHashOn("title: a", 120, "", today), resultHashOn("title: a", 100, "", today))
+	assert.NotEqual(t, resultHashOn("title: a", 120, "", today), resultHashOn("title: b", 120, "", today))
+}
+
+func TestNotModifiedSinceYesterday(t *testing.T) {
+	m := newModelTestServer(t, communicationLinkTestModel)
+	lastWeek := time.Now().AddDate(0, 0, -7)
+	assert.NoError(t, m.storage.(fileSystemStorage).fileSystem.Chtimes(filepath.Join(m.modelFolder, m.config.InputFile), lastWeek, lastWeek))
+
+	notModified := func(ifModifiedSince time.Time) bool {
+		ginContext, _ := gin.CreateTestContext(httptest.NewRecorder())
+		ginContext.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+		ginContext.Request.Header.Set("If-Modified-Since", ifModifiedSince.UTC().Format(http.TimeFormat))
+		return m.notModified(ginContext, m.modelFolder, reportPDF, "title: a", 120)
+	}
+	assert.False(t, notModified(lastWeek.AddDate(0, 0, 1)), "risks may have turned overdue since")
+	assert.True(t, notModified(time.Now()))
+}
+
+func TestResultHashChangesWithTheConfig(t *testing.T) {
+	m := newModelTestServer(t, communicationLinkTestModel)
+	hash := m.resultHash("title: a", 120)
+	assert.Equal(t, hash, m.resultHash("title: a", 120))
+
+	m.config.SkipRiskRules = []string{"missing-vault"}
+	skipped := m.resultHash("title: a", 120)
+	assert.NotEqual(t, hash, skipped, "skipped rules")
+	m.config.SeverityRecalibration = map[string]int{"mission-critical": 1}
+	assert.NotEqual(t, skipped, m.resultHash("title: a", 120), "severity recalibration")
+	m.config.SkipRiskRules, m.config.SeverityRecalibration = nil, nil
+	m.config.BuildTimestamp = "20240301120000"
+	assert.NotEqual(t, hash, m.resultHash("title: a", 120), "upgrade")
+
+	m.config.BuildTimestamp = ""
+	m.config.ServerPort = 9000
+	assert.Equal(t, hash, m.resultHash("title: a", 120), "not affecting the analysis")
+}
+
+func TestNotModifiedETag(t *testing.T) {
+	m := newModelTestServer(t, communicationLinkTestModel)
+	request := func(responseType responseType, query string, ifNoneMatch string) (bool, string) {
+		recorder := httptest.NewRecorder()
+		ginContext, _ := gin.CreateTestContext(recorder)
+		ginContext.Request = httptest.NewRequest(http.MethodGet, "/"+query, nil)
+		if len(ifNoneMatch) > 0 {
+			ginContext.Request.Header.Set("If-None-Match", ifNoneMatch)
+		}
+		return m.notModified(ginContext, m.modelFolder, responseType, "title: a", 120), recorder.Header().Get("ETag")
+	}
+
+	notModified, etag := request(reportPDF, "", "")
+	assert.False(t, notModified)
+	notModified, _ = request(reportPDF, "", etag)
+	assert.True(t, notModified)
+	notModified, _ = request(reportPDF, "", `"other", W/`+etag)
+	assert.True(t, notModified, "weak comparison within a list")
+	notModified, _ = request(risksExcel, "", etag)
+	assert.False(t, notModified, "other response type")
+	notModified, _ = request(reportPDF, "?dpi=100", etag)
+	assert.False(t, notModified, "other query")
+
+	m.config.SkipRiskRules = []string{"missing-vault"}
+	notModified, _ = request(reportPDF, "", etag)
+	assert.False(t, notModified, "other analysis settings")
+
+	m.startedAt = time.Now().Add(time.Hour)
+	ginContext, _ := gin.CreateTestContext(httptest.NewRecorder())
+	ginContext.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+	ginContext.Request.Header.Set("If-Modified-Since", time.Now().UTC().Format(http.TimeFormat))
+	assert.False(t, m.notModified(ginContext, m.modelFolder, reportPDF, "title: a", 120), "server restarted since")
+}
@@ -0,0 +1,277 @@
+package server
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+// riskCommentsFilename is stored (encrypted like the model) alongside the model, so the discussion of the risks is kept
+// apart from the model and its risk tracking; the analysis reads it from the workspace (see writeRiskCommentsFile) to
+// show the comments with the risks in its outputs, and it is added to the analysis result
+const riskCommentsFilename = "risk-comments.json"
+
+type payloadRiskComment struct {
+	Author  string `yaml:"author" json:"author"`
+	Text    string `yaml:"text" json:"text" binding:"required"`
+	ReplyTo string `yaml:"reply_to" json:"reply_to"` // id of the comment of the same risk this one answers, if any
+}
+
+// riskComment is a comment as stored, with a hash of the token it was written with (never answered), so that only its
+// writer can delete it besides the holders of the workspace key and of admin grants
+type riskComment struct {
+	types.RiskComment
+	WrittenBy string `json:"written_by,omitempty"`
+}
+
+// riskComments are the comments keyed by synthetic risk id, in the order they were written
+type riskComments map[string][]riskComment
+
+// public answers the comments without the hashes of their writers
+func (what riskComments) public() map[string][]types.RiskComment {
+	comments := make(map[string][]types.RiskComment, len(what))
+	for syntheticRiskId := range what {
+		comments[syntheticRiskId] = publicRiskComments(what[syntheticRiskId])
+	}
+	return comments
+}
+
+func publicRiskComments(comments []riskComment) []types.RiskComment {
+	public := make([]types.RiskComment, 0, len(comments))
+	for _, comment := range comments {
+		public = append(public, comment.RiskComment)
+	}
+	return public
+}
+
+// riskCommentWriter answers the hash identifying the writer of a comment by the key of the request, i.e. the workspace
+// key or the grant token (differing from the hashes the grants are stored with)
+func riskCommentWriter(key []byte) string {
+	return hashSHA256(append([]byte("risk-comment "), key...))
+}
+
+// readRiskComments answers the stored comments of the model, which are none if nothing was commented yet
+func (s *server) readRiskComments(modelFolder string, key []byte) (riskComments, error) {
+	comments := make(riskComments)
+	ciphertext, err := s.storage.ReadFile(filepath.Join(modelFolder, riskCommentsFilename))
+	if os.IsNotExist(err) {
+		return comments, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	plaintext, err := s.decryptModelData(modelFolder, key, ciphertext)
+	if err != nil {
+		return nil, err
+	}
+	err = json.Unmarshal(plaintext, &comments)
+	if err != nil {
+		return nil, err
+	}
+	return comments, nil
+}
+
+func (s *server) writeRiskComments(modelFolder string, key []byte, comments riskComments) error {
+	plaintext, err := json.Marshal(comments)
+	if err != nil {
+		return err
+	}
+	ciphertext, err := s.encryptModelData(modelFolder, key, plaintext)
+	if err != nil {
+		return err
+	}
+	return s.storage.WriteFile(filepath.Join(modelFolder, riskCommentsFilename), ciphertext)
+}
+
+// checkRiskComments answers the model folder and the stored comments of the model of the request
+func (s *server) checkRiskComments(ginContext *gin.Context, folderNameOfKey string, key []byte) (modelFolder string, comments riskComments, ok bool) {
+	modelFolder, ok = s.checkModelFolder(ginContext, ginContext.Param("model-id"), folderNameOfKey)
+	if !ok {
+		return modelFolder, nil, false
+	}
+	comments, err := s.readRiskComments(modelFolder, key)
+	if err != nil {
+		log.Println(err)
+		respondError(ginContext, http.StatusInternalServerError, errorCodeInternal, "unable to read risk comments")
+		return modelFolder, nil, false
+	}
+	return modelFolder, comments, true
+}
+
+func (s *server) getRiskComments(ginContext *gin.Context) {
+	folderNameOfKey, key, ok := s.checkTokenToFolderName(ginContext)
+	if !ok {
+		return
+	}
+	s.lockFolder(folderNameOfKey)
+	defer s.unlockFolder(folderNameOfKey)
+	_, comments, ok := s.checkRiskComments(ginContext, folderNameOfKey, key)
+	if ok {
+		ginContext.JSON(http.StatusOK, comments.public())
+	}
+}
+
+func (s *server) getRiskCommentsOfRisk(ginContext *gin.Context) {
+	folderNameOfKey, key, ok := s.checkTokenToFolderName(ginContext)
+	if !ok {
+		return
+	}
+	s.lockFolder(folderNameOfKey)
+	defer s.unlockFolder(folderNameOfKey)
+	_, comments, ok := s.checkRiskComments(ginContext, folderNameOfKey, key)
+	if ok {
+		ginContext.JSON(http.StatusOK, publicRiskComments(comments[ginContext.Param("synthetic-risk-id")]))
+	}
+}
+
+// createRiskComment comments the risk, which is not checked to exist (like risk tracking entries, comments of risks not
+// identified anymore are kept)
+func (s *server) createRiskComment(ginContext *gin.Context) {
+	folderNameOfKey, key, ok := s.checkTokenToFolderName(ginContext)
+	if !ok {
+		return
+	}
+	s.lockFolder(folderNameOfKey)
+	defer s.unlockFolder(folderNameOfKey)
+	modelFolder, comments, ok := s.checkRiskComments(ginContext, folderNameOfKey, key)
+	if !ok {
+		return
+	}
+	payload := payloadRiskComment{}
+	err := ginContext.BindJSON(&payload)
+	if err != nil {
+		log.Println(err)
+		respondError(ginContext, http.StatusBadRequest, errorCodeInvalidPayload, "unable to parse request payload")
+		return
+	}
+	if len(strings.TrimSpace(payload.Text)) == 0 {
+		respondError(ginContext, http.StatusBadRequest, errorCodeBadRequest, "comment text must not be empty")
+		return
+	}
+	syntheticRiskId := ginContext.Param("synthetic-risk-id")
+	if len(payload.ReplyTo) > 0 && findRiskComment(comments[syntheticRiskId], payload.ReplyTo) < 0 {
+		respondError(ginContext, http.StatusBadRequest, errorCodeBadRequest, "comment replied to does not exist")
+		return
+	}
+	comment := riskComment{
+		RiskComment: types.RiskComment{
+			Id:      uuid.New().String(),
+			Author:  strings.TrimSpace(payload.Author),
+			Text:    payload.Text,
+			ReplyTo: payload.ReplyTo,
+			Date:    time.Now().UTC(),
+		},
+		WrittenBy: riskCommentWriter(key),
+	}
+	comments[syntheticRiskId] = append(comments[syntheticRiskId], comment)
+	err = s.writeRiskComments(modelFolder, key, comments)
+	if err != nil {
+		log.Println(err)
+		respondError(ginContext, http.StatusInternalServerError, errorCodeInternal, "unable to write risk comments")
+		return
+	}
+	ginContext.JSON(http.StatusCreated, gin.H{
+		"message": "risk comment created",
+		"id":      comment.Id,
+	})
+}
+
+// deleteRiskComment deletes the comment together with all replies to it, which only the holders of the workspace key
+// and of admin grants may do for comments (or replies) written by others
+func (s *server) deleteRiskComment(ginContext *gin.Context) {
+	folderNameOfKey, key, ok := s.checkTokenToFolderName(ginContext)
+	if !ok {
+		return
+	}
+	s.lockFolder(folderNameOfKey)
+	defer s.unlockFolder(folderNameOfKey)
+	modelFolder, comments, ok := s.checkRiskComments(ginContext, folderNameOfKey, key)
+	if !ok {
+		return
+	}
+	syntheticRiskId := ginContext.Param("synthetic-risk-id")
+	if findRiskComment(comments[syntheticRiskId], ginContext.Param("comment-id")) < 0 {
+		respondError(ginContext, http.StatusNotFound, errorCodeNotFound, "risk comment not found")
+		return
+	}
+	deleted := map[string]bool{ginContext.Param("comment-id"): true}
+	remaining := make([]riskComment, 0)
+	moderator := s.isWorkspaceKeyOf(modelFolder, key) || hasGrantRole(ginContext, grantRoleAdmin)
+	writer := riskCommentWriter(key)
+	for _, comment := range comments[syntheticRiskId] { // replies are always written after the comment they answer
+		if deleted[comment.Id] || deleted[comment.ReplyTo] {
+			if !moderator && subtle.ConstantTimeCompare([]byte(comment.WrittenBy), []byte(writer)) != 1 {
+				respondError(ginContext, http.StatusForbidden, errorCodeForbidden, "risk comment (or a reply to it) was written by someone else")
+				return
+			}
+			deleted[comment.Id] = true
+			continue
+		}
+		remaining = append(remaining, comment)
+	}
+	if len(remaining) > 0 {
+		comments[syntheticRiskId] = remaining
+	} else {
+		delete(comments, syntheticRiskId)
+	}
+	err := s.writeRiskComments(modelFolder, key, comments)
+	if err != nil {
+		log.Println(err)
+		respondError(ginContext, http.StatusInternalServerError, errorCodeInternal, "unable to write risk comments")
+		return
+	}
+	ginContext.JSON(http.StatusOK, gin.H{
+		"message": "risk comment deleted",
+		"deleted": len(deleted), // including the replies
+	})
+}
+
+func findRiskComment(comments []riskComment, id string) int {
+	for i, comment := range comments {
+		if comment.Id == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// outputVersion answers the text the versions of the outputs of the model are hashed from (see resultHash), which is
+// the model together with the comments of its risks shown in them, and the comments as json (nil without any)
+func (s *server) outputVersion(modelFolder string, key []byte, yamlText string) (string, []byte, error) {
+	comments, err := s.readRiskComments(modelFolder, key)
+	if err != nil || len(comments) == 0 {
+		return yamlText, nil, err
+	}
+	commentsData, err := json.Marshal(comments.public())
+	if err != nil {
+		return "", nil, err
+	}
+	return yamlText + "\n" + string(commentsData), commentsData, nil
+}
+
+// writeRiskCommentsFile writes the comments (if any) into the workspace, where the analysis in it reads them from (see
+// riskCommentsFileOf)
+func writeRiskCommentsFile(workspace *TempWorkspace, commentsData []byte) error {
+	if len(commentsData) == 0 {
+		return nil
+	}
+	return os.WriteFile(filepath.Join(workspace.Dir, riskCommentsFilename), commentsData, 0600)
+}
+
+// riskCommentsFileOf answers the comments file of the workspace, empty if there are no comments
+func riskCommentsFileOf(workspace *TempWorkspace) string {
+	commentsFile := filepath.Join(workspace.Dir, riskCommentsFilename)
+	if _, err := os.Stat(commentsFile); err != nil {
+		return ""
+	}
+	return commentsFile
+}
@@ -0,0 +1,115 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRiskComments(t *testing.T) {
+	m := newModelTestServer(t, communicationLinkTestModel)
+	riskParams := gin.Params{{Key: "synthetic-risk-id", Value: "unencrypted-asset@db"}}
+
+	recorder := m.call(m.createRiskComment, http.MethodPost, riskParams, payloadRiskComment{Author: "Alice", Text: "Is the disk encrypted?"})
+	assert.Equal(t, http.StatusCreated, recorder.Code)
+	var created map[string]string
+	assert.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &created))
+	recorder = m.call(m.createRiskComment, http.MethodPost, riskParams, payloadRiskComment{Author: "Bob", Text: "Yes, by the cloud provider.", ReplyTo: created["id"]})
+	assert.Equal(t, http.StatusCreated, recorder.Code)
+	recorder = m.call(m.createRiskComment, http.MethodPost, riskParams, payloadRiskComment{Author: "Bob", Text: "Reply to nothing", ReplyTo: "unknown"})
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+	recorder = m.call(m.createRiskComment, http.MethodPost, riskParams, payloadRiskComment{Author: "Bob", Text: " "})
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+
+	recorder = m.call(m.getRiskCommentsOfRisk, http.MethodGet, riskParams, nil)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	var thread []riskComment
+	assert.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &thread))
+	assert.Len(t, thread, 2)
+	assert.Equal(t, created["id"], thread[1].ReplyTo)
+	assert.NotContains(t, recorder.Body.String(), "written_by")
+
+	// the analysis shows the comments with the risks, so they are part of the version of its outputs
+	ginContext, _ := gin.CreateTestContext(httptest.NewRecorder())
+	ginContext.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+	ginContext.Request.Header.Set("token", m.token)
+	_, key, ok := m.checkTokenToFolderName(ginContext)
+	assert.True(t, ok)
+	versionText, commentsData, err := m.outputVersion(m.modelFolder, key, communicationLinkTestModel)
+	assert.NoError(t, err)
+	assert.NotEqual(t, communicationLinkTestModel, versionText)
+	assert.NotContains(t, string(commentsData), "written_by")
+	workspace, err := newTempWorkspace(t.TempDir(), "test")
+	assert.NoError(t, err)
+	defer workspace.Close()
+	assert.Empty(t, riskCommentsFileOf(workspace))
+	assert.NoError(t, writeRiskCommentsFile(workspace, commentsData))
+	assert.Contains(t, m.runtimeCallArgs(workspace, "threagile.yaml", workspace.Dir, false, false, true, false, false, false, false, false, false, 0), riskCommentsFileOf(workspace))
+
+	recorder = m.call(m.deleteRiskComment, http.MethodDelete, append(riskParams, gin.Param{Key: "comment-id", Value: created["id"]}), nil)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), `"deleted":2`)
+	recorder = m.call(m.deleteRiskComment, http.MethodDelete, append(riskParams, gin.Param{Key: "comment-id", Value: created["id"]}), nil)
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+
+	recorder = m.call(m.getRiskComments, http.MethodGet, nil, nil)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.JSONEq(t, `{}`, recorder.Body.String())
+}
+
+func TestRiskCommentsDeletedByTheirWriters(t *testing.T) {
+	m := newModelTestServer(t, communicationLinkTestModel)
+	router := gin.New()
+	for _, route := range m.routes() {
+		if route.auth == tokenAuth {
+			router.Handle(route.method, route.path, m.grantAuthorization(route), route.handler)
+		}
+	}
+	call := func(method string, path string, header string, token string, payload any) *httptest.ResponseRecorder {
+		var body bytes.Buffer
+		if payload != nil {
+			_ = json.NewEncoder(&body).Encode(payload)
+		}
+		request := httptest.NewRequest(method, path, &body)
+		request.Header.Set(header, token)
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, request)
+		return recorder
+	}
+	modelPath := "/models/" + m.modelID
+	grantTokens := make(map[string]string)
+	for _, role := range []string{"edit", "admin"} {
+		recorder := call(http.MethodPost, modelPath+"/grants", "token", m.token, payloadGrant{Role: role})
+		var created payloadGrantInfo
+		assert.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &created))
+		grantTokens[role] = created.GrantToken
+	}
+	comment := func(header string, token string, replyTo string) string {
+		recorder := call(http.MethodPost, modelPath+"/risk-comments/missing-waf@shop", header, token, payloadRiskComment{Author: header, Text: "comment", ReplyTo: replyTo})
+		assert.Equal(t, http.StatusCreated, recorder.Code)
+		var created map[string]string
+		assert.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &created))
+		return created["id"]
+	}
+	deleteComment := func(header string, token string, id string) int {
+		return call(http.MethodDelete, modelPath+"/risk-comments/missing-waf@shop/"+id, header, token, nil).Code
+	}
+
+	own := comment(grantTokenHeader, grantTokens["edit"], "")
+	assert.Equal(t, http.StatusOK, deleteComment(grantTokenHeader, grantTokens["edit"], own))
+	others := comment("token", m.token, "")
+	assert.Equal(t, http.StatusForbidden, deleteComment(grantTokenHeader, grantTokens["edit"], others))
+	answered := comment(grantTokenHeader, grantTokens["edit"], "")
+	comment("token", m.token, answered)
+	assert.Equal(t, http.StatusForbidden, deleteComment(grantTokenHeader, grantTokens["edit"], answered), "replied to by someone else")
+	assert.Equal(t, http.StatusOK, deleteComment(grantTokenHeader, grantTokens["admin"], answered))
+	assert.Equal(t, http.StatusOK, deleteComment("token", m.token, others))
+
+	recorder := call(http.MethodGet, modelPath+"/risk-comments", "token", m.token, nil)
+	assert.JSONEq(t, `{}`, recorder.Body.String())
+}
@@ -0,0 +1,297 @@
+package server
+
+import (
+	"net/http"
+	"sort"
+
+	"github.com/gin-gonic/gin"
+	"github.com/threagile/threagile/pkg/docs"
+	"github.com/threagile/threagile/pkg/input"
+	"github.com/threagile/threagile/pkg/macros"
+	"github.com/threagile/threagile/pkg/security/risks"
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+// route is an API endpoint, registered at the router and documented in the generated OpenAPI document (see openapi.go)
+type route struct {
+	method      string
+	path        string // gin syntax, i.e. path parameters as :name
+	handler     gin.HandlerFunc
+	tag         string
+	summary     string
+	auth        authentication
+	role        grantRole // required of grant tokens, defaults to read for GET requests and edit for all others
+	query       []queryParameter
+	upload      bool // expects the model file as multipart form field "file"
+	request     any  // JSON payload, described by its type
+	idempotent  bool // retries with the same header Idempotency-Key are answered with the first response
+	status      int  // of a successful call, defaults to 200
+	response    any  // JSON response, described by its type (nil is any object)
+	contentType string
+}
+
+type authentication int
+
+const (
+	noAuth authentication = iota
+	keyAuth
+	tokenAuth
+	shareTokenAuth
+	adminAuth
+)
+
+type queryParameter struct {
+	name        string
+	schemaType  string
+	description string
+}
+
+var dpiParameter = queryParameter{name: "dpi", schemaType: "integer", description: "The DPI (resolution) to use for the diagram generation"}
+
+const (
+	mimeZip         = "application/zip"
+	mimePDF         = "application/pdf"
+	mimeExcel       = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	mimePNG         = "image/png"
+	mimeSVG         = "image/svg+xml"
+	mimeGraphvizDOT = "text/vnd.graphviz"
+	mimeSARIF       = "application/sarif+json"
+	mimeCalendar    = "text/calendar"
+)
+
+// routes lists all API endpoints, the order is the one of the generated OpenAPI document
+func (s *server) routes() []route {
+	return []route{
+		{method: http.MethodGet, path: "/meta/ping", handler: s.ping, tag: "meta", summary: "Simple health check ping (used as health check in the docker container as well)"},
+		{method: http.MethodGet, path: "/meta/version", handler: s.version, tag: "meta", summary: "Version number", response: payloadVersion{}},
+		{method: http.MethodGet, path: "/meta/types", handler: s.enumTypes, tag: "meta", summary: "Listing of all enum type values", response: map[string][]string{}},
+		{method: http.MethodGet, path: "/meta/risk-rules", handler: s.riskRules, tag: "meta", summary: "Listing of all risk rules (built-in and custom ones) by id", response: []payloadRiskRule{}},
+		{method: http.MethodGet, path: "/meta/model-macros", handler: s.modelMacros, tag: "meta", summary: "Listing of all model macros with their questions (as asked when answering with the defaults)", response: []payloadModelMacro{}},
+		{method: http.MethodGet, path: "/meta/stats", handler: s.stats, tag: "meta", summary: "Server statistics", response: payloadStats{}},
+		{method: http.MethodGet, path: "/meta/dashboard", handler: s.dashboard, tag: "meta", summary: "Risk posture across the models of all keys (as of their last analysis)", auth: adminAuth, query: []queryParameter{{name: "anonymize", schemaType: "boolean", description: "Pseudonyms instead of the model ids and only the risk categories found in several models"}, {name: "limit", schemaType: "integer", description: "Maximum number of top risk categories and oldest unreviewed models (default 10)"}}, response: payloadDashboard{}},
+		{method: http.MethodGet, path: "/meta/quotas/:key-id", handler: s.getKeyQuota, tag: "meta", summary: "Quotas set for the key (0 where the ones of its tenant or the server apply)", auth: adminAuth, response: payloadQuota{}},
+		{method: http.MethodPut, path: "/meta/quotas/:key-id", handler: s.setKeyQuota, tag: "meta", summary: "Set the quotas of the key, overriding the ones of its tenant and the server (0 to not override)", auth: adminAuth, request: payloadQuota{}, response: payloadQuota{}},
+
+		{method: http.MethodPost, path: "/direct/analyze", handler: s.analyze, tag: "direct", summary: "Direct model analyze call, answering the zipped outputs", query: []queryParameter{dpiParameter}, upload: true, contentType: mimeZip},
+		{method: http.MethodPost, path: "/direct/risks", handler: s.analyzeRisks, tag: "direct", summary: "Direct model analyze call running only the selected risk rules, answering their risks", query: []queryParameter{{name: "rules", schemaType: "string", description: "Comma-separated list of the risk rules to run (by their ID, wildcards like unencrypted-* allowed)"}}, upload: true, response: []types.Risk{}},
+		{method: http.MethodPost, path: "/direct/check", handler: s.check, tag: "direct", summary: "Direct model check call", upload: true, response: payloadCheck{}},
+		{method: http.MethodGet, path: "/direct/stub", handler: s.stubFile, tag: "direct", summary: "Stub model file (as a starting point)", contentType: gin.MIMEYAML},
+
+		{method: http.MethodPost, path: "/auth/keys", handler: s.createKey, tag: "auth", summary: "Create a new auth key (of the tenant of the header tenant-secret, which servers with tenants require)", status: http.StatusCreated},
+		{method: http.MethodDelete, path: "/auth/keys", handler: s.deleteKey, tag: "auth", summary: "Delete an auth key together with all its models", auth: keyAuth},
+		{method: http.MethodPost, path: "/auth/tokens", handler: s.createToken, tag: "auth", summary: "Create a new (time limited) token from an auth key", auth: keyAuth, status: http.StatusCreated},
+		{method: http.MethodDelete, path: "/auth/tokens", handler: s.deleteToken, tag: "auth", summary: "Delete a token", auth: tokenAuth},
+		{method: http.MethodGet, path: "/auth/quota", handler: s.getQuota, tag: "auth", summary: "Quotas applying to the key of the token, with the key id the server admin sets them by", auth: tokenAuth, response: payloadKeyQuota{}},
+
+		{method: http.MethodPost, path: "/models", handler: s.createNewModel, tag: "models", summary: "Create a new (empty) model, or a copy of a template", auth: tokenAuth, query: []queryParameter{{name: "template", schemaType: "string", description: "Id of the template to start from (see GET /templates)"}}, status: http.StatusCreated, idempotent: true},
+		{method: http.MethodGet, path: "/models", handler: s.listModels, tag: "models", summary: "List the models (newest modified first), models which can't be read are listed with an error", auth: tokenAuth, query: []queryParameter{{name: "sort", schemaType: "string", description: "Sort by modified (default), created or title"}, {name: "order", schemaType: "string", description: "Sort order asc or desc (default desc, asc for titles)"}, {name: "offset", schemaType: "integer", description: "Number of models to skip"}, {name: "limit", schemaType: "integer", description: "Maximum number of models listed (the number of all is in the header X-Total-Count)"}}, response: []payloadModels{}},
+		{method: http.MethodDelete, path: "/models/:model-id", handler: s.deleteModel, tag: "models", summary: "Delete a model", auth: tokenAuth, role: grantRoleAdmin},
+		{method: http.MethodGet, path: "/models/:model-id", handler: s.getModel, tag: "models", summary: "Model file", auth: tokenAuth, contentType: gin.MIMEYAML},
+		{method: http.MethodPut, path: "/models/:model-id", handler: s.importModel, tag: "models", summary: "Replace the model by a model file (yaml, json or a zip with the model and its images)", auth: tokenAuth, upload: true, status: http.StatusCreated},
+		{method: http.MethodPost, path: "/models/:model-id/bulk", handler: s.bulkChange, tag: "models", summary: "Apply create, update and delete operations of model elements all at once (or none of them when one fails)", auth: tokenAuth, request: payloadBulk{}},
+		{method: http.MethodPost, path: "/models/:model-id/edit-sessions", handler: s.beginEditSession, tag: "models", summary: "Begin an edit session, staging the changes of the requests sending its id as header edit-session until commit", auth: tokenAuth, status: http.StatusCreated},
+		{method: http.MethodPost, path: "/models/:model-id/edit-sessions/:edit-session-id/commit", handler: s.commitEditSession, tag: "models", summary: "Validate and write the changes of an edit session", auth: tokenAuth},
+		{method: http.MethodDelete, path: "/models/:model-id/edit-sessions/:edit-session-id", handler: s.abortEditSession, tag: "models", summary: "Abort an edit session, discarding its changes", auth: tokenAuth},
+		{method: http.MethodGet, path: "/models/:model-id/macros", handler: s.listModelMacros, tag: "models", summary: "Model macros with their questions for the model (as asked when answering with the defaults)", auth: tokenAuth, response: []payloadModelMacro{}},
+		{method: http.MethodPost, path: "/models/:model-id/macros/:macro-id/next-question", handler: s.nextMacroQuestion, tag: "models", summary: "Next question of the model macro after the answers given so far, or the changes it would apply once all are answered", auth: tokenAuth, role: grantRoleRead, request: payloadMacroAnswers{}, response: payloadMacroNextQuestion{}},
+		{method: http.MethodPost, path: "/models/:model-id/macros/:macro-id/execute", handler: s.executeModelMacro, tag: "models", summary: "Apply the model macro with the answers given (the default answers for questions not answered)", auth: tokenAuth, request: payloadMacroAnswers{}, response: payloadMacroExecution{}},
+		{method: http.MethodGet, path: "/models/:model-id/data-flow-diagram", handler: s.streamDataFlowDiagram, tag: "models", summary: "Data flow diagram", auth: tokenAuth, query: []queryParameter{dpiParameter}, contentType: mimePNG},
+		{method: http.MethodGet, path: "/models/:model-id/data-asset-diagram", handler: s.streamDataAssetDiagram, tag: "models", summary: "Data asset diagram", auth: tokenAuth, query: []queryParameter{dpiParameter}, contentType: mimePNG},
+		{method: http.MethodGet, path: "/models/:model-id/report-pdf", handler: s.streamReportPDF, tag: "models", summary: "Report", auth: tokenAuth, query: []queryParameter{dpiParameter}, contentType: mimePDF},
+		{method: http.MethodGet, path: "/models/:model-id/risks-excel", handler: s.streamRisksExcel, tag: "models", summary: "Risks as Excel sheet", auth: tokenAuth, contentType: mimeExcel},
+		{method: http.MethodGet, path: "/models/:model-id/tags-excel", handler: s.streamTagsExcel, tag: "models", summary: "Tags as Excel sheet", auth: tokenAuth, contentType: mimeExcel},
+		{method: http.MethodGet, path: "/models/:model-id/risks", handler: s.streamRisksJSON, tag: "models", summary: "Risks", auth: tokenAuth, query: []queryParameter{{name: "owner", schemaType: "string", description: "Only the risks of the technical assets of the given owner"}, {name: "assignee", schemaType: "string", description: "Only the risks assigned to the given assignee by risk tracking"}}, response: []types.Risk{}},
+		{method: http.MethodGet, path: "/models/:model-id/risks-sarif", handler: s.streamRisksSARIF, tag: "models", summary: "Risks as SARIF 2.1.0 log (with the risk categories as rules)", auth: tokenAuth, contentType: mimeSARIF},
+		{method: http.MethodGet, path: "/models/:model-id/technical-assets", handler: s.streamTechnicalAssetsJSON, tag: "models", summary: "Technical assets (with their RAA) by id", auth: tokenAuth, response: map[string]types.TechnicalAsset{}},
+		{method: http.MethodGet, path: "/models/:model-id/stats", handler: s.streamStatsJSON, tag: "models", summary: "Risk statistics", auth: tokenAuth, response: types.RiskStatistics{}},
+		{method: http.MethodGet, path: "/models/:model-id/risk-matrix.png", handler: s.streamRiskMatrix, tag: "models", summary: "Risk matrix", auth: tokenAuth, contentType: mimePNG},
+		{method: http.MethodGet, path: "/models/:model-id/analysis", handler: s.analyzeModelOnServerDirectly, tag: "models", summary: "Analysis of the model, answering the zipped outputs", auth: tokenAuth, query: []queryParameter{dpiParameter}, contentType: mimeZip},
+		{method: http.MethodPost, path: "/models/:model-id/analysis-jobs", handler: s.createAnalysisJob, tag: "models", summary: "Start the analysis of the model in the background, answering the job to poll (see GET /analysis-jobs/{analysis-job-id})", auth: tokenAuth, role: grantRoleRead, query: []queryParameter{dpiParameter}, status: http.StatusAccepted, response: payloadAnalysisJob{}},
+		{method: http.MethodGet, path: "/models/:model-id/data-flow-diagram.gv", handler: s.streamDataFlowDiagramDOT, tag: "models", summary: "Graphviz source of the data flow diagram", auth: tokenAuth, query: []queryParameter{dpiParameter}, contentType: mimeGraphvizDOT},
+		{method: http.MethodGet, path: "/models/:model-id/data-asset-diagram.gv", handler: s.streamDataAssetDiagramDOT, tag: "models", summary: "Graphviz source of the data asset diagram", auth: tokenAuth, query: []queryParameter{dpiParameter}, contentType: mimeGraphvizDOT},
+		{method: http.MethodGet, path: "/models/:model-id/risk-comments", handler: s.getRiskComments, tag: "models", summary: "Comments of the risks by synthetic risk id", auth: tokenAuth, response: map[string][]types.RiskComment{}},
+		{method: http.MethodGet, path: "/models/:model-id/risk-comments/:synthetic-risk-id", handler: s.getRiskCommentsOfRisk, tag: "models", summary: "Comments of the risk", auth: tokenAuth, response: []types.RiskComment{}},
+		{method: http.MethodPost, path: "/models/:model-id/risk-comments/:synthetic-risk-id", handler: s.createRiskComment, tag: "models", summary: "Comment the risk (or reply to a comment of it)", auth: tokenAuth, request: payloadRiskComment{}, status: http.StatusCreated},
+		{method: http.MethodDelete, path: "/models/:model-id/risk-comments/:synthetic-risk-id/:comment-id", handler: s.deleteRiskComment, tag: "models", summary: "Delete the comment of the risk with all replies to it", auth: tokenAuth},
+		{method: http.MethodGet, path: "/models/:model-id/risks-by-category", handler: s.streamRisksByCategoryJSON, tag: "models", summary: "Risks grouped by risk category", auth: tokenAuth, response: []risksOfCategory{}},
+		{method: http.MethodGet, path: "/models/:model-id/risks-by-trust-boundary", handler: s.streamRisksByTrustBoundaryJSON, tag: "models", summary: "Risks grouped by trust boundary", auth: tokenAuth, query: []queryParameter{{name: "trust-boundary", schemaType: "string", description: "Only the risks of the given trust boundary, an empty id selects the risks outside of any trust boundary"}}, response: []risksOfTrustBoundary{}},
+		{method: http.MethodGet, path: "/models/:model-id/badge.svg", handler: s.streamBadge, tag: "models", summary: "Public risk count badge", auth: shareTokenAuth, query: []queryParameter{{name: "metric", schemaType: "string", description: "critical-risks (default), high-risks, elevated-risks, medium-risks, low-risks or risks"}}, contentType: mimeSVG},
+		{method: http.MethodGet, path: "/models/:model-id/due-dates.ics", handler: s.streamDueDatesICS, tag: "models", summary: "Public calendar of the risk due dates and the next model review (dates and risk ids only)", auth: shareTokenAuth, contentType: mimeCalendar},
+		{method: http.MethodPost, path: "/models/:model-id/share-token", handler: s.createShareToken, tag: "models", summary: "Create a share token for the public read-only endpoints (replacing the previous one)", auth: tokenAuth, role: grantRoleAdmin, status: http.StatusCreated},
+		{method: http.MethodDelete, path: "/models/:model-id/share-token", handler: s.deleteShareToken, tag: "models", summary: "Delete the share token", auth: tokenAuth, role: grantRoleAdmin},
+		{method: http.MethodPost, path: "/models/:model-id/grants", handler: s.createGrant, tag: "models", summary: "Grant read, edit or admin rights on the model, answering the grant token to send as header grant-token instead of token", auth: tokenAuth, role: grantRoleAdmin, request: payloadGrant{}, status: http.StatusCreated, response: payloadGrantInfo{}},
+		{method: http.MethodGet, path: "/models/:model-id/grants", handler: s.listGrants, tag: "models", summary: "Grants of the model (without their tokens)", auth: tokenAuth, role: grantRoleAdmin, response: []payloadGrantInfo{}},
+		{method: http.MethodDelete, path: "/models/:model-id/grants/:grant-id", handler: s.deleteGrant, tag: "models", summary: "Revoke a grant", auth: tokenAuth, role: grantRoleAdmin},
+
+		{method: http.MethodGet, path: "/models/:model-id/cover", handler: s.getCover, tag: "models", summary: "Cover", auth: tokenAuth, response: payloadCover{}},
+		{method: http.MethodPut, path: "/models/:model-id/cover", handler: s.setCover, tag: "models", summary: "Update the cover", auth: tokenAuth, request: payloadCover{}},
+		{method: http.MethodGet, path: "/models/:model-id/overview", handler: s.getOverview, tag: "models", summary: "Overview", auth: tokenAuth, response: payloadOverview{}},
+		{method: http.MethodPut, path: "/models/:model-id/overview", handler: s.setOverview, tag: "models", summary: "Update the overview", auth: tokenAuth, request: payloadOverview{}},
+		{method: http.MethodGet, path: "/models/:model-id/abuse-cases", handler: s.getAbuseCases, tag: "models", summary: "Abuse cases", auth: tokenAuth, response: payloadAbuseCases{}},
+		{method: http.MethodPut, path: "/models/:model-id/abuse-cases", handler: s.setAbuseCases, tag: "models", summary: "Update the abuse cases", auth: tokenAuth, request: payloadAbuseCases{}},
+		{method: http.MethodGet, path: "/models/:model-id/security-requirements", handler: s.getSecurityRequirements, tag: "models", summary: "Security requirements", auth: tokenAuth, response: payloadSecurityRequirements{}},
+		{method: http.MethodPut, path: "/models/:model-id/security-requirements", handler: s.setSecurityRequirements, tag: "models", summary: "Update the security requirements", auth: tokenAuth, request: payloadSecurityRequirements{}},
+		{method: http.MethodGet, path: "/models/:model-id/questions", handler: s.getQuestions, tag: "models", summary: "Questions with their answers (empty if not answered yet)", auth: tokenAuth, response: payloadQuestions{}},
+		{method: http.MethodPut, path: "/models/:model-id/questions", handler: s.setQuestions, tag: "models", summary: "Update the questions and their answers", auth: tokenAuth, request: payloadQuestions{}},
+		{method: http.MethodGet, path: "/models/:model-id/tags", handler: s.getTags, tag: "models", summary: "Tags available in the model", auth: tokenAuth, response: payloadTags{}},
+		{method: http.MethodPut, path: "/models/:model-id/tags", handler: s.setTags, tag: "models", summary: "Replace the tags available in the model (which have to include the ones used by its elements)", auth: tokenAuth, request: payloadTags{}},
+		{method: http.MethodGet, path: "/models/:model-id/annotations", handler: s.getAnnotations, tag: "models", summary: "Review notes attached to the elements of the model", auth: tokenAuth, response: payloadAnnotations{}},
+		{method: http.MethodPost, path: "/models/:model-id/annotations", handler: s.createNewAnnotation, tag: "models", summary: "Attach a review note to an element of the model (dated today unless given)", auth: tokenAuth, request: input.ElementAnnotation{}, idempotent: true},
+		{method: http.MethodPut, path: "/models/:model-id/annotations", handler: s.setAnnotations, tag: "models", summary: "Replace the review notes of the model", auth: tokenAuth, request: payloadAnnotations{}},
+
+		{method: http.MethodGet, path: "/models/:model-id/data-assets", handler: s.getDataAssets, tag: "models", summary: "Data assets by title", auth: tokenAuth, response: map[string]input.DataAsset{}},
+		{method: http.MethodPost, path: "/models/:model-id/data-assets", handler: s.createNewDataAsset, tag: "models", summary: "Create a data asset", auth: tokenAuth, request: payloadDataAsset{}, idempotent: true},
+		{method: http.MethodGet, path: "/models/:model-id/data-assets/:data-asset-id", handler: s.getDataAsset, tag: "models", summary: "Data asset", auth: tokenAuth},
+		{method: http.MethodPut, path: "/models/:model-id/data-assets/:data-asset-id", handler: s.setDataAsset, tag: "models", summary: "Update a data asset", auth: tokenAuth, request: payloadDataAsset{}},
+		{method: http.MethodDelete, path: "/models/:model-id/data-assets/:data-asset-id", handler: s.deleteDataAsset, tag: "models", summary: "Delete a data asset", auth: tokenAuth},
+
+		{method: http.MethodGet, path: "/models/:model-id/technical-assets/:technical-asset-id/communication-links", handler: s.getCommunicationLinks, tag: "models", summary: "Communication links of a technical asset by title", auth: tokenAuth, response: map[string]input.CommunicationLink{}},
+		{method: http.MethodPost, path: "/models/:model-id/technical-assets/:technical-asset-id/communication-links", handler: s.createNewCommunicationLink, tag: "models", summary: "Create a communication link of a technical asset", auth: tokenAuth, request: payloadCommunicationLink{}, idempotent: true},
+		{method: http.MethodGet, path: "/models/:model-id/technical-assets/:technical-asset-id/communication-links/:communication-link-id", handler: s.getCommunicationLink, tag: "models", summary: "Communication link (by its id like technical-asset-id>title-in-kebab-case)", auth: tokenAuth},
+		{method: http.MethodPut, path: "/models/:model-id/technical-assets/:technical-asset-id/communication-links/:communication-link-id", handler: s.setCommunicationLink, tag: "models", summary: "Update a communication link", auth: tokenAuth, request: payloadCommunicationLink{}},
+		{method: http.MethodDelete, path: "/models/:model-id/technical-assets/:technical-asset-id/communication-links/:communication-link-id", handler: s.deleteCommunicationLink, tag: "models", summary: "Delete a communication link", auth: tokenAuth},
+
+		{method: http.MethodPost, path: "/models/:model-id/technical-assets", handler: s.createNewTechnicalAsset, tag: "models", summary: "Create a technical asset", auth: tokenAuth, request: payloadTechnicalAsset{}, idempotent: true},
+		{method: http.MethodGet, path: "/models/:model-id/technical-assets/:technical-asset-id", handler: s.getTechnicalAsset, tag: "models", summary: "Technical asset (as in the model file)", auth: tokenAuth},
+		{method: http.MethodPut, path: "/models/:model-id/technical-assets/:technical-asset-id", handler: s.setTechnicalAsset, tag: "models", summary: "Update a technical asset (keeping its communication links)", auth: tokenAuth, request: payloadTechnicalAsset{}},
+		{method: http.MethodDelete, path: "/models/:model-id/technical-assets/:technical-asset-id", handler: s.deleteTechnicalAsset, tag: "models", summary: "Delete a technical asset together with the communication links targeting it", auth: tokenAuth},
+
+		{method: http.MethodGet, path: "/models/:model-id/trust-boundaries", handler: s.getTrustBoundaries, tag: "models", summary: "Trust boundaries by title", auth: tokenAuth, response: map[string]input.TrustBoundary{}},
+		{method: http.MethodPost, path: "/models/:model-id/trust-boundaries", handler: s.createNewTrustBoundary, tag: "models", summary: "Create a trust boundary", auth: tokenAuth, request: payloadTrustBoundary{}, idempotent: true},
+		{method: http.MethodGet, path: "/models/:model-id/trust-boundaries/:trust-boundary-id", handler: s.getTrustBoundary, tag: "models", summary: "Trust boundary", auth: tokenAuth},
+		{method: http.MethodPut, path: "/models/:model-id/trust-boundaries/:trust-boundary-id", handler: s.setTrustBoundary, tag: "models", summary: "Update a trust boundary", auth: tokenAuth, request: payloadTrustBoundary{}},
+		{method: http.MethodDelete, path: "/models/:model-id/trust-boundaries/:trust-boundary-id", handler: s.deleteTrustBoundary, tag: "models", summary: "Delete a trust boundary", auth: tokenAuth},
+
+		{method: http.MethodGet, path: "/models/:model-id/shared-runtimes", handler: s.getSharedRuntimes, tag: "models", summary: "Shared runtimes by title", auth: tokenAuth, response: map[string]input.SharedRuntime{}},
+		{method: http.MethodPost, path: "/models/:model-id/shared-runtimes", handler: s.createNewSharedRuntime, tag: "models", summary: "Create a shared runtime", auth: tokenAuth, request: payloadSharedRuntime{}, idempotent: true},
+		{method: http.MethodGet, path: "/models/:model-id/shared-runtimes/:shared-runtime-id", handler: s.getSharedRuntime, tag: "models", summary: "Shared runtime", auth: tokenAuth},
+		{method: http.MethodPut, path: "/models/:model-id/shared-runtimes/:shared-runtime-id", handler: s.setSharedRuntime, tag: "models", summary: "Update a shared runtime", auth: tokenAuth, request: payloadSharedRuntime{}},
+		{method: http.MethodDelete, path: "/models/:model-id/shared-runtimes/:shared-runtime-id", handler: s.deleteSharedRuntime, tag: "models", summary: "Delete a shared runtime", auth: tokenAuth},
+
+		{method: http.MethodGet, path: "/analysis-jobs/:analysis-job-id", handler: s.getAnalysisJob, tag: "analysis-jobs", summary: "Status and progress of the analysis job", auth: tokenAuth, response: payloadAnalysisJob{}},
+		{method: http.MethodGet, path: "/analysis-jobs/:analysis-job-id/result", handler: s.streamAnalysisJobResult, tag: "analysis-jobs", summary: "Zipped outputs of the done analysis job", auth: tokenAuth, contentType: mimeZip},
+
+		{method: http.MethodGet, path: "/templates", handler: s.listTemplates, tag: "templates", summary: "List the model templates (of the templates folder and index of the server)", auth: tokenAuth, response: []payloadTemplate{}},
+		{method: http.MethodGet, path: "/templates/:template-id", handler: s.getTemplate, tag: "templates", summary: "Model file of a template", auth: tokenAuth, contentType: gin.MIMEYAML},
+	}
+}
+
+type payloadVersion struct {
+	Version        string `json:"version"`
+	BuildTimestamp string `json:"build_timestamp"`
+}
+
+type payloadStats struct {
+	KeyCount     int `json:"key_count"`
+	ModelCount   int `json:"model_count"`
+	SuccessCount int `json:"success_count"`
+	ErrorCount   int `json:"error_count"`
+}
+
+type payloadCheck struct {
+	Message  string   `json:"message"`
+	Valid    bool     `json:"valid"`
+	Warnings []string `json:"warnings"`
+}
+
+type payloadRiskRule struct {
+	ID            string   `json:"id"`
+	Title         string   `json:"title"`
+	Description   string   `json:"description"`
+	SupportedTags []string `json:"supported_tags"`
+	STRIDE        string   `json:"stride"`
+	CWE           int      `json:"cwe"`
+	Custom        bool     `json:"custom"`
+}
+
+type payloadModelMacro struct {
+	ID          string                 `json:"id"`
+	Title       string                 `json:"title"`
+	Description string                 `json:"description"`
+	Questions   []payloadMacroQuestion `json:"questions"`
+}
+
+type payloadMacroQuestion struct {
+	ID              string   `json:"id"`
+	Title           string   `json:"title"`
+	Description     string   `json:"description"`
+	PossibleAnswers []string `json:"possible_answers"` // any answer if empty
+	MultiSelect     bool     `json:"multi_select"`
+	DefaultAnswer   string   `json:"default_answer"`
+}
+
+func (s *server) ping(ginContext *gin.Context) {
+	ginContext.JSON(http.StatusOK, gin.H{
+		"message": "pong",
+	})
+}
+
+func (s *server) version(ginContext *gin.Context) {
+	ginContext.JSON(http.StatusOK, payloadVersion{
+		Version:        docs.ThreagileVersion,
+		BuildTimestamp: s.config.BuildTimestamp,
+	})
+}
+
+func (s *server) riskRules(ginContext *gin.Context) {
+	rules := make([]payloadRiskRule, 0)
+	builtinRiskRules := risks.GetBuiltInRiskRules()
+	for _, ruleSet := range []types.RiskRules{builtinRiskRules, s.customRiskRules} {
+		for id, rule := range ruleSet {
+			category := rule.Category()
+			rules = append(rules, payloadRiskRule{
+				ID:            id,
+				Title:         category.Title,
+				Description:   category.Description,
+				SupportedTags: rule.SupportedTags(),
+				STRIDE:        category.STRIDE.String(),
+				CWE:           category.CWE,
+				Custom:        builtinRiskRules[id] == nil,
+			})
+		}
+	}
+	sort.Slice(rules, func(i, j int) bool {
+		return rules[i].ID < rules[j].ID
+	})
+	ginContext.JSON(http.StatusOK, rules)
+}
+
+func (s *server) modelMacros(ginContext *gin.Context) {
+	result := make([]payloadModelMacro, 0)
+	emptyModel := new(types.Model)
+	for _, macro := range macros.ListServerMacros() {
+		questions, err := macros.DefaultQuestions(macro, emptyModel)
+		if err != nil {
+			handleErrorInServiceCall(err, ginContext)
+			return
+		}
+		result = append(result, newPayloadModelMacro(macro.GetMacroDetails(), questions))
+	}
+	ginContext.JSON(http.StatusOK, result)
+}
+
+func (s *server) enumTypes(ginContext *gin.Context) {
+	ginContext.JSON(http.StatusOK, gin.H{
+		"quantity":                     arrayOfStringValues(types.QuantityValues()),
+		"confidentiality":              arrayOfStringValues(types.ConfidentialityValues()),
+		"criticality":                  arrayOfStringValues(types.CriticalityValues()),
+		"technical_asset_type":         arrayOfStringValues(types.TechnicalAssetTypeValues()),
+		"technical_asset_size":         arrayOfStringValues(types.TechnicalAssetSizeValues()),
+		"authorization":                arrayOfStringValues(types.AuthorizationValues()),
+		"authentication":               arrayOfStringValues(types.AuthenticationValues()),
+		"usage":                        arrayOfStringValues(types.UsageValues()),
+		"encryption":                   arrayOfStringValues(types.EncryptionStyleValues()),
+		"data_format":                  arrayOfStringValues(types.DataFormatValues()),
+		"protocol":                     arrayOfStringValues(types.ProtocolValues()),
+		"technical_asset_technology":   arrayOfStringValues(types.TechnicalAssetTechnologyValues(s.config)),
+		"technical_asset_machine":      arrayOfStringValues(types.TechnicalAssetMachineValues()),
+		"trust_boundary_type":          arrayOfStringValues(types.TrustBoundaryTypeValues()),
+		"data_breach_probability":      arrayOfStringValues(types.DataBreachProbabilityValues()),
+		"risk_severity":                arrayOfStringValues(types.RiskSeverityValues()),
+		"risk_exploitation_likelihood": arrayOfStringValues(types.RiskExploitationLikelihoodValues()),
+		"risk_exploitation_impact":     arrayOfStringValues(types.RiskExploitationImpactValues()),
+		"risk_function":                arrayOfStringValues(types.RiskFunctionValues()),
+		"risk_status":                  arrayOfStringValues(types.RiskStatusValues()),
+		"stride":                       arrayOfStringValues(types.STRIDEValues()),
+	})
+}
@@ -0,0 +1,47 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/threagile/threagile/pkg/common"
+)
+
+func TestRiskRulesAndModelMacros(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	s := &server{config: new(common.Config)}
+
+	recorder := httptest.NewRecorder()
+	ginContext, _ := gin.CreateTestContext(recorder)
+	s.riskRules(ginContext)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	var rules []payloadRiskRule
+	assert.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rules))
+	assert.NotEmpty(t, rules)
+	for i, rule := range rules {
+		assert.NotEmpty(t, rule.Title, rule.ID)
+		assert.NotEmpty(t, rule.STRIDE, rule.ID)
+		assert.False(t, rule.Custom, rule.ID)
+		if i > 0 {
+			assert.Less(t, rules[i-1].ID, rule.ID)
+		}
+	}
+
+	recorder = httptest.NewRecorder()
+	ginContext, _ = gin.CreateTestContext(recorder)
+	s.modelMacros(ginContext)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	var macros []payloadModelMacro
+	assert.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &macros))
+	assert.NotEmpty(t, macros)
+	assert.Equal(t, "add-build-pipeline", macros[0].ID)
+	assert.Equal(t, "source-repository", macros[0].Questions[0].ID)
+	for _, macro := range macros {
+		assert.NotEqual(t, "add-k8s-workloads", macro.ID, "reading local files")
+	}
+}
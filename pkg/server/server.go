@@ -5,6 +5,7 @@ Copyright © 2023 NAME HERE <EMAIL ADDRESS>
 package server
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"net/http"
@@ -14,12 +15,12 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/threagile/threagile/pkg/common"
 	"github.com/threagile/threagile/pkg/model"
 
 	"github.com/gin-gonic/gin"
-	"github.com/threagile/threagile/pkg/docs"
 	"github.com/threagile/threagile/pkg/security/risks"
 	"github.com/threagile/threagile/pkg/security/types"
 )
@@ -35,19 +36,48 @@ type server struct {
 	mapFolderNameToTokenHash       map[string]string
 	extremeShortTimeoutsForTesting bool
 	locksByFolderName              map[string]*sync.Mutex
+	analysesByFolderName           map[string][]int64 // timestamps of the renderings within the last hour, for the quota
 	customRiskRules                types.RiskRules
+	storage                        storage
+	openAPIDocument                []byte
+	idempotencyLock                sync.Mutex
+	idempotentResponses            map[string]*idempotentResponse // by auth key and idempotency key
+	analysisJobsLock               sync.Mutex
+	analysisJobs                   map[string]*analysisJob
+	startedAt                      time.Time // changes of the config and upgrades take effect with a restart only
+	webhookQueue                   chan webhookDelivery
+	webhookWorkersOnce             sync.Once
+	postureRefreshLock             sync.Mutex
+	postureRefreshes               map[string]*string // the pending model yaml (if any) by model folder of the postures being refreshed
+	templateIndexLock              sync.Mutex
+	templateIndexCache             *templateIndexCache // of the templates index last fetched
 }
 
-func RunServer(config *common.Config) {
+func RunServer(config *common.Config) error {
+	storage, err := newStorage(config)
+	if err != nil {
+		return err
+	}
 	s := &server{
 		config:                         config,
+		storage:                        storage,
 		createdObjectsThrottler:        make(map[string][]int64),
 		mapTokenHashToTimeoutStruct:    make(map[string]timeoutStruct),
 		mapFolderNameToTokenHash:       make(map[string]string),
 		extremeShortTimeoutsForTesting: false,
 		locksByFolderName:              make(map[string]*sync.Mutex),
+		analysesByFolderName:           make(map[string][]int64),
+		idempotentResponses:            make(map[string]*idempotentResponse),
+		analysisJobs:                   make(map[string]*analysisJob),
+		startedAt:                      time.Now().UTC().Truncate(time.Second),
+	}
+	s.openAPIDocument, err = s.openAPI()
+	if err != nil {
+		return err
 	}
+	gin.DefaultWriter, gin.DefaultErrorWriter = common.LogStdout(), common.LogStderr() // the request log redacted too
 	router := gin.Default()
+	router.Use(correlation)
 	router.LoadHTMLGlob(filepath.Join(s.config.ServerFolder, "s", "static", "*.html")) // <==
 	router.GET("/", func(c *gin.Context) {
 		c.HTML(http.StatusOK, "index.html", gin.H{})
@@ -66,7 +96,6 @@ func RunServer(config *common.Config) {
 
 	router.StaticFile("/schema.json", filepath.Join(s.config.AppFolder, "schema.json"))
 	router.StaticFile("/live-templates.txt", filepath.Join(s.config.AppFolder, "live-templates.txt"))
-	router.StaticFile("/openapi.yaml", filepath.Join(s.config.AppFolder, "openapi.yaml"))
 	router.StaticFile("/swagger-ui/", filepath.Join(s.config.ServerFolder, "s", "static", "swagger-ui/index.html"))
 	router.StaticFile("/swagger-ui/index.html", filepath.Join(s.config.ServerFolder, "s", "static", "swagger-ui/index.html"))
 	router.StaticFile("/swagger-ui/oauth2-redirect.html", filepath.Join(s.config.ServerFolder, "s", "static", "swagger-ui/oauth2-redirect.html"))
@@ -78,108 +107,29 @@ func RunServer(config *common.Config) {
 	router.GET("/threagile-example-model.yaml", s.exampleFile)
 	router.GET("/threagile-stub-model.yaml", s.stubFile)
 
-	router.GET("/meta/ping", func(c *gin.Context) {
-		c.JSON(200, gin.H{
-			"message": "pong",
-		})
-	})
-	router.GET("/meta/version", func(c *gin.Context) {
-		c.JSON(200, gin.H{
-			"version":         docs.ThreagileVersion,
-			"build_timestamp": s.config.BuildTimestamp,
-		})
-	})
-	router.GET("/meta/types", func(c *gin.Context) {
-		c.JSON(200, gin.H{
-			"quantity":                     arrayOfStringValues(types.QuantityValues()),
-			"confidentiality":              arrayOfStringValues(types.ConfidentialityValues()),
-			"criticality":                  arrayOfStringValues(types.CriticalityValues()),
-			"technical_asset_type":         arrayOfStringValues(types.TechnicalAssetTypeValues()),
-			"technical_asset_size":         arrayOfStringValues(types.TechnicalAssetSizeValues()),
-			"authorization":                arrayOfStringValues(types.AuthorizationValues()),
-			"authentication":               arrayOfStringValues(types.AuthenticationValues()),
-			"usage":                        arrayOfStringValues(types.UsageValues()),
-			"encryption":                   arrayOfStringValues(types.EncryptionStyleValues()),
-			"data_format":                  arrayOfStringValues(types.DataFormatValues()),
-			"protocol":                     arrayOfStringValues(types.ProtocolValues()),
-			"technical_asset_technology":   arrayOfStringValues(types.TechnicalAssetTechnologyValues(config)),
-			"technical_asset_machine":      arrayOfStringValues(types.TechnicalAssetMachineValues()),
-			"trust_boundary_type":          arrayOfStringValues(types.TrustBoundaryTypeValues()),
-			"data_breach_probability":      arrayOfStringValues(types.DataBreachProbabilityValues()),
-			"risk_severity":                arrayOfStringValues(types.RiskSeverityValues()),
-			"risk_exploitation_likelihood": arrayOfStringValues(types.RiskExploitationLikelihoodValues()),
-			"risk_exploitation_impact":     arrayOfStringValues(types.RiskExploitationImpactValues()),
-			"risk_function":                arrayOfStringValues(types.RiskFunctionValues()),
-			"risk_status":                  arrayOfStringValues(types.RiskStatusValues()),
-			"stride":                       arrayOfStringValues(types.STRIDEValues()),
-		})
-	})
-
-	// TODO router.GET("/meta/risk-rules", listRiskRules)
-	// TODO router.GET("/meta/model-macros", listModelMacros)
-
-	router.GET("/meta/stats", s.stats)
-
-	router.POST("/direct/analyze", s.analyze)
-	router.POST("/direct/check", s.check)
-	router.GET("/direct/stub", s.stubFile)
-
-	router.POST("/auth/keys", s.createKey)
-	router.DELETE("/auth/keys", s.deleteKey)
-	router.POST("/auth/tokens", s.createToken)
-	router.DELETE("/auth/tokens", s.deleteToken)
-
-	router.POST("/models", s.createNewModel)
-	router.GET("/models", s.listModels)
-	router.DELETE("/models/:model-id", s.deleteModel)
-	router.GET("/models/:model-id", s.getModel)
-	router.PUT("/models/:model-id", s.importModel)
-	router.GET("/models/:model-id/data-flow-diagram", s.streamDataFlowDiagram)
-	router.GET("/models/:model-id/data-asset-diagram", s.streamDataAssetDiagram)
-	router.GET("/models/:model-id/report-pdf", s.streamReportPDF)
-	router.GET("/models/:model-id/risks-excel", s.streamRisksExcel)
-	router.GET("/models/:model-id/tags-excel", s.streamTagsExcel)
-	router.GET("/models/:model-id/risks", s.streamRisksJSON)
-	router.GET("/models/:model-id/technical-assets", s.streamTechnicalAssetsJSON)
-	router.GET("/models/:model-id/stats", s.streamStatsJSON)
-	router.GET("/models/:model-id/analysis", s.analyzeModelOnServerDirectly)
-
-	router.GET("/models/:model-id/cover", s.getCover)
-	router.PUT("/models/:model-id/cover", s.setCover)
-	router.GET("/models/:model-id/overview", s.getOverview)
-	router.PUT("/models/:model-id/overview", s.setOverview)
-	//router.GET("/models/:model-id/questions", getQuestions)
-	//router.PUT("/models/:model-id/questions", setQuestions)
-	router.GET("/models/:model-id/abuse-cases", s.getAbuseCases)
-	router.PUT("/models/:model-id/abuse-cases", s.setAbuseCases)
-	router.GET("/models/:model-id/security-requirements", s.getSecurityRequirements)
-	router.PUT("/models/:model-id/security-requirements", s.setSecurityRequirements)
-	//router.GET("/models/:model-id/tags", getTags)
-	//router.PUT("/models/:model-id/tags", setTags)
-
-	router.GET("/models/:model-id/data-assets", s.getDataAssets)
-	router.POST("/models/:model-id/data-assets", s.createNewDataAsset)
-	router.GET("/models/:model-id/data-assets/:data-asset-id", s.getDataAsset)
-	router.PUT("/models/:model-id/data-assets/:data-asset-id", s.setDataAsset)
-	router.DELETE("/models/:model-id/data-assets/:data-asset-id", s.deleteDataAsset)
-
-	router.GET("/models/:model-id/trust-boundaries", s.getTrustBoundaries)
-	//	router.POST("/models/:model-id/trust-boundaries", createNewTrustBoundary)
-	//	router.GET("/models/:model-id/trust-boundaries/:trust-boundary-id", getTrustBoundary)
-	//	router.PUT("/models/:model-id/trust-boundaries/:trust-boundary-id", setTrustBoundary)
-	//	router.DELETE("/models/:model-id/trust-boundaries/:trust-boundary-id", deleteTrustBoundary)
-
-	router.GET("/models/:model-id/shared-runtimes", s.getSharedRuntimes)
-	router.POST("/models/:model-id/shared-runtimes", s.createNewSharedRuntime)
-	router.GET("/models/:model-id/shared-runtimes/:shared-runtime-id", s.getSharedRuntime)
-	router.PUT("/models/:model-id/shared-runtimes/:shared-runtime-id", s.setSharedRuntime)
-	router.DELETE("/models/:model-id/shared-runtimes/:shared-runtime-id", s.deleteSharedRuntime)
+	router.GET("/openapi.yaml", s.streamOpenAPI)
+	for _, route := range s.routes() {
+		handlers := make([]gin.HandlerFunc, 0)
+		if route.auth == tokenAuth {
+			handlers = append(handlers, s.grantAuthorization(route))
+		}
+		if route.idempotent {
+			handlers = append(handlers, s.idempotency)
+		}
+		if route.request != nil {
+			handlers = append(handlers, s.authenticated(route), payloadValidation(route.request))
+		}
+		router.Handle(route.method, route.path, append(handlers, route.handler)...)
+	}
 
 	reporter := common.DefaultProgressReporter{Verbose: s.config.Verbose}
-	s.customRiskRules = model.LoadCustomRiskRules(s.config.RiskRulesPlugins, reporter)
+	go s.runTempWorkspaceJanitor()
 
-	fmt.Println("Threagile s running...")
+	s.customRiskRules = model.LoadCustomRiskRules(context.Background(), s.config.RiskRulesPlugins, s.config.PluginTimeoutSeconds, reporter)
+
+	fmt.Fprintln(common.LogStdout(), "Threagile s running...")
 	_ = router.Run(":" + strconv.Itoa(s.config.ServerPort)) // listen and serve on 0.0.0.0:8080 or whatever port was specified
+	return nil
 }
 
 func (s *server) exampleFile(ginContext *gin.Context) {
@@ -224,14 +174,14 @@ func (s *server) addSupportedTags(input []byte) []byte {
 	}
 	sort.Strings(tags)
 	if s.config.Verbose {
-		fmt.Print("Supported tags of all risk rules: ")
+		fmt.Fprint(common.LogStdout(), "Supported tags of all risk rules: ")
 		for i, tag := range tags {
 			if i > 0 {
-				fmt.Print(", ")
+				fmt.Fprint(common.LogStdout(), ", ")
 			}
-			fmt.Print(tag)
+			fmt.Fprint(common.LogStdout(), tag)
 		}
-		fmt.Println()
+		fmt.Fprintln(common.LogStdout())
 	}
 	replacement := "tags_available:"
 	for _, tag := range tags {
@@ -250,50 +200,47 @@ func arrayOfStringValues(values []types.TypeEnum) []string {
 
 func (s *server) stats(ginContext *gin.Context) {
 	keyCount, modelCount := 0, 0
-	keyFolders, err := os.ReadDir(filepath.Join(s.config.ServerFolder, s.config.KeyFolder))
-	if err != nil {
-		log.Println(err)
-		ginContext.JSON(http.StatusInternalServerError, gin.H{
-			"error": "unable to collect stats",
-		})
-		return
-	}
-	for _, keyFolder := range keyFolders {
-		if len(keyFolder.Name()) == 128 { // it's a sha512 token hash probably, so count it as token folder for the stats
-			keyCount++
-			if keyFolder.Name() != filepath.Clean(keyFolder.Name()) {
-				ginContext.JSON(http.StatusInternalServerError, gin.H{
-					"error": "weird file path",
-				})
-				return
-			}
-			modelFolders, err := os.ReadDir(filepath.Join(s.config.ServerFolder, s.config.KeyFolder, keyFolder.Name()))
-			if err != nil {
-				log.Println(err)
-				ginContext.JSON(http.StatusInternalServerError, gin.H{
-					"error": "unable to collect stats",
-				})
-				return
-			}
-			for _, modelFolder := range modelFolders {
-				if len(modelFolder.Name()) == 36 { // it's a uuid model folder probably, so count it as model folder for the stats
-					modelCount++
+	for _, keyFolderRoot := range s.keyFolderRoots() {
+		keyFolders, err := s.storage.ReadDir(keyFolderRoot)
+		if os.IsNotExist(err) { // the root of a tenant without any key yet
+			continue
+		}
+		if err != nil {
+			log.Println(err)
+			respondError(ginContext, http.StatusInternalServerError, errorCodeInternal, "unable to collect stats")
+			return
+		}
+		for _, keyFolder := range keyFolders {
+			if len(keyFolder.Name()) == 128 { // it's a sha512 token hash probably, so count it as token folder for the stats
+				keyCount++
+				if keyFolder.Name() != filepath.Clean(keyFolder.Name()) {
+					respondError(ginContext, http.StatusInternalServerError, errorCodeInternal, "weird file path")
+					return
+				}
+				modelFolders, err := s.storage.ReadDir(filepath.Join(keyFolderRoot, keyFolder.Name()))
+				if err != nil {
+					log.Println(err)
+					respondError(ginContext, http.StatusInternalServerError, errorCodeInternal, "unable to collect stats")
+					return
+				}
+				for _, modelFolder := range modelFolders {
+					if len(modelFolder.Name()) == 36 { // it's a uuid model folder probably, so count it as model folder for the stats
+						modelCount++
+					}
 				}
 			}
 		}
 	}
 	// TODO collect and deliver more stats (old model count?) and health info
-	ginContext.JSON(http.StatusOK, gin.H{
-		"key_count":     keyCount,
-		"model_count":   modelCount,
-		"success_count": s.successCount,
-		"error_count":   s.errorCount,
+	ginContext.JSON(http.StatusOK, payloadStats{
+		KeyCount:     keyCount,
+		ModelCount:   modelCount,
+		SuccessCount: s.successCount,
+		ErrorCount:   s.errorCount,
 	})
 }
 
 func handleErrorInServiceCall(err error, ginContext *gin.Context) {
 	log.Println(err)
-	ginContext.JSON(http.StatusBadRequest, gin.H{
-		"error": strings.TrimSpace(err.Error()),
-	})
+	respondError(ginContext, http.StatusBadRequest, errorCodeBadRequest, strings.TrimSpace(err.Error()))
 }
@@ -0,0 +1,68 @@
+package server
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/threagile/threagile/pkg/common"
+)
+
+type modelTestServer struct {
+	*server
+	token       string
+	modelID     string
+	modelFolder string
+	editSession string // sent as header, if any
+}
+
+// newModelTestServer creates a server in memory with one model of the yaml, accessible by the returned token
+func newModelTestServer(t *testing.T, yaml string) modelTestServer {
+	gin.SetMode(gin.TestMode)
+	s := &server{
+		config:                      &common.Config{ServerFolder: t.TempDir(), KeyFolder: "keys", InputFile: "threagile.yaml", BackupHistoryFilesToKeep: 10},
+		mapTokenHashToTimeoutStruct: make(map[string]timeoutStruct),
+		mapFolderNameToTokenHash:    make(map[string]string),
+		locksByFolderName:           make(map[string]*sync.Mutex),
+		storage:                     newMemoryStorage(),
+		idempotentResponses:         make(map[string]*idempotentResponse),
+		createdObjectsThrottler:     make(map[string][]int64),
+	}
+	key, xorRand := make([]byte, keySize), make([]byte, keySize)
+	_, _ = rand.Read(key)
+	_, _ = rand.Read(xorRand)
+	token := xor(key, xorRand)
+	s.mapTokenHashToTimeoutStruct[hashSHA256(token)] = timeoutStruct{xorRand: xorRand, createdNanoTime: time.Now().UnixNano(), lastAccessedNanoTime: time.Now().UnixNano()}
+	modelID := uuid.New().String()
+	modelFolder := folderNameForModel(s.folderNameFromKey(key), modelID)
+	assert.NoError(t, s.storage.MkdirAll(modelFolder))
+	ginContext, _ := gin.CreateTestContext(httptest.NewRecorder())
+	assert.True(t, s.writeModelYAML(ginContext, yaml, key, modelFolder, "Test Setup", true))
+	return modelTestServer{server: s, token: base64.RawURLEncoding.EncodeToString(token), modelID: modelID, modelFolder: modelFolder}
+}
+
+// call invokes the handler with the params of its route and the payload as JSON body
+func (m modelTestServer) call(handler gin.HandlerFunc, method string, params gin.Params, payload interface{}) *httptest.ResponseRecorder {
+	var body bytes.Buffer
+	if payload != nil {
+		_ = json.NewEncoder(&body).Encode(payload)
+	}
+	recorder := httptest.NewRecorder()
+	ginContext, _ := gin.CreateTestContext(recorder)
+	ginContext.Request = httptest.NewRequest(method, "/models/"+m.modelID, &body)
+	ginContext.Request.Header.Set("token", m.token)
+	if len(m.editSession) > 0 {
+		ginContext.Request.Header.Set(editSessionHeader, m.editSession)
+	}
+	ginContext.Params = append(gin.Params{{Key: "model-id", Value: m.modelID}}, params...)
+	handler(ginContext)
+	return recorder
+}
@@ -0,0 +1,82 @@
+package server
+
+import (
+	"fmt"
+	"io/fs"
+
+	"github.com/threagile/threagile/pkg/common"
+)
+
+// storage holds the key folders with their models (and everything stored alongside like history, analysis results and
+// share tokens), the paths are the same for all implementations
+type storage interface {
+	Mkdir(path string) error
+	MkdirAll(path string) error
+	Stat(path string) (fs.FileInfo, error)
+	ReadDir(path string) ([]fs.FileInfo, error) // sorted by name
+	ReadFile(path string) ([]byte, error)
+	WriteFile(path string, data []byte) error
+	Remove(path string) error
+	RemoveAll(path string) error
+	Glob(pattern string) ([]string, error)
+}
+
+func newStorage(config *common.Config) (storage, error) {
+	if config.FileSystem != nil {
+		return fileSystemStorage{fileSystem: config.FileSystem}, nil
+	}
+	switch config.ServerStorage {
+	case "", common.ServerStorageFile:
+		return fileSystemStorage{fileSystem: common.OSFileSystem{}}, nil
+	case common.ServerStorageMemory:
+		return newMemoryStorage(), nil
+	}
+	return nil, fmt.Errorf("unknown server storage %q (use %q or %q)", config.ServerStorage, common.ServerStorageFile, common.ServerStorageMemory)
+}
+
+// newMemoryStorage keeps everything in memory only, so that tests and demos neither need a writable server folder nor
+// leave anything behind; all keys and models are gone when the server stops
+func newMemoryStorage() storage {
+	return fileSystemStorage{fileSystem: common.NewMemoryFileSystem()}
+}
+
+// fileSystemStorage keeps everything in the server folder of the file system, readable by the server only
+type fileSystemStorage struct {
+	fileSystem common.FileSystem
+}
+
+func (what fileSystemStorage) Mkdir(path string) error {
+	return what.fileSystem.Mkdir(path, 0700)
+}
+
+func (what fileSystemStorage) MkdirAll(path string) error {
+	return what.fileSystem.MkdirAll(path, 0700)
+}
+
+func (what fileSystemStorage) Stat(path string) (fs.FileInfo, error) {
+	return what.fileSystem.Stat(path)
+}
+
+func (what fileSystemStorage) ReadDir(path string) ([]fs.FileInfo, error) {
+	return what.fileSystem.ReadDir(path)
+}
+
+func (what fileSystemStorage) ReadFile(path string) ([]byte, error) {
+	return what.fileSystem.ReadFile(path)
+}
+
+func (what fileSystemStorage) WriteFile(path string, data []byte) error {
+	return what.fileSystem.WriteFile(path, data, 0600)
+}
+
+func (what fileSystemStorage) Remove(path string) error {
+	return what.fileSystem.Remove(path)
+}
+
+func (what fileSystemStorage) RemoveAll(path string) error {
+	return what.fileSystem.RemoveAll(path)
+}
+
+func (what fileSystemStorage) Glob(pattern string) ([]string, error) {
+	return what.fileSystem.Glob(pattern)
+}
@@ -0,0 +1,314 @@
+package server
+
+import (
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/threagile/threagile/pkg/input"
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+// payloadTechnicalAsset is the technical asset of the model file with its title, as the model file keys the technical
+// assets by title; its communication links are only taken on creation, afterwards they have their own endpoints
+type payloadTechnicalAsset struct {
+	Title                string `yaml:"title" json:"title" binding:"required"`
+	input.TechnicalAsset `yaml:",inline"`
+}
+
+func (s *server) getTechnicalAsset(ginContext *gin.Context) {
+	folderNameOfKey, key, ok := s.checkTokenToFolderName(ginContext)
+	if !ok {
+		return
+	}
+	s.lockFolder(folderNameOfKey)
+	defer s.unlockFolder(folderNameOfKey)
+	modelInput, _, ok := s.readModel(ginContext, ginContext.Param("model-id"), key, folderNameOfKey)
+	if ok {
+		title, technicalAsset, err := findTechnicalAsset(modelInput, ginContext.Params)
+		if err != nil {
+			handleChangeError(err, ginContext)
+			return
+		}
+		// yes, here keyed by title in YAML for better readability in the YAML file itself
+		ginContext.JSON(http.StatusOK, gin.H{
+			title: technicalAsset,
+		})
+	}
+}
+
+func (s *server) createNewTechnicalAsset(ginContext *gin.Context) {
+	s.changeModel(ginContext, "Technical Asset Creation", technicalAssetCreation)
+}
+
+func technicalAssetCreation(modelInput *input.Model, _ gin.Params, bindPayload func(payload any) error) (gin.H, error) {
+	payload := payloadTechnicalAsset{}
+	err := bindPayload(&payload)
+	if err != nil {
+		log.Println(err)
+		return nil, errUnparsablePayload
+	}
+	// yes, here keyed by title in YAML for better readability in the YAML file itself
+	if _, exists := modelInput.TechnicalAssets[payload.Title]; exists {
+		return nil, requestError{status: http.StatusConflict, message: "technical asset with this title already exists"}
+	}
+	// but later it will in memory keyed by its "id", so do this uniqueness check also
+	for _, asset := range modelInput.TechnicalAssets {
+		if asset.ID == payload.ID {
+			return nil, requestError{status: http.StatusConflict, message: "technical asset with this id already exists"}
+		}
+	}
+	technicalAssetInput, err := populateTechnicalAsset(*modelInput, payload)
+	if err != nil {
+		return nil, err
+	}
+	if modelInput.TechnicalAssets == nil {
+		modelInput.TechnicalAssets = make(map[string]input.TechnicalAsset)
+	}
+	links := technicalAssetInput.CommunicationLinks
+	technicalAssetInput.CommunicationLinks = make(map[string]input.CommunicationLink)
+	modelInput.TechnicalAssets[payload.Title] = technicalAssetInput
+	for title, link := range links { // validated when the asset exists, as the links may target the asset itself
+		communicationLinkInput, err := populateCommunicationLink(*modelInput, payloadCommunicationLink{Title: title, CommunicationLink: link})
+		if err != nil {
+			return nil, err
+		}
+		technicalAssetInput.CommunicationLinks[title] = communicationLinkInput
+	}
+	modelInput.TechnicalAssets[payload.Title] = technicalAssetInput
+	return gin.H{
+		"message": "technical asset created",
+		"id":      technicalAssetInput.ID,
+	}, nil
+}
+
+func (s *server) setTechnicalAsset(ginContext *gin.Context) {
+	s.changeModel(ginContext, "Technical Asset Update", technicalAssetUpdate)
+}
+
+func technicalAssetUpdate(modelInput *input.Model, params gin.Params, bindPayload func(payload any) error) (gin.H, error) {
+	title, technicalAsset, err := findTechnicalAsset(*modelInput, params)
+	if err != nil {
+		return nil, err
+	}
+	payload := payloadTechnicalAsset{}
+	err = bindPayload(&payload)
+	if err != nil {
+		log.Println(err)
+		return nil, errUnparsablePayload
+	}
+	if _, exists := modelInput.TechnicalAssets[payload.Title]; exists && payload.Title != title {
+		return nil, requestError{status: http.StatusConflict, message: "technical asset with this title already exists"}
+	}
+	for otherTitle, asset := range modelInput.TechnicalAssets {
+		if otherTitle != title && asset.ID == payload.ID {
+			return nil, requestError{status: http.StatusConflict, message: "technical asset with this id already exists"}
+		}
+	}
+	technicalAssetInput, err := populateTechnicalAsset(*modelInput, payload)
+	if err != nil {
+		return nil, err
+	}
+	technicalAssetInput.CommunicationLinks = technicalAsset.CommunicationLinks
+	// in order to also update the title, remove the asset from the map and re-insert it (with new key)
+	delete(modelInput.TechnicalAssets, title)
+	modelInput.TechnicalAssets[payload.Title] = technicalAssetInput
+	idChanged := technicalAssetInput.ID != technicalAsset.ID
+	if idChanged { // ID-CHANGE-PROPAGATION
+		for _, asset := range modelInput.TechnicalAssets {
+			for linkTitle, link := range asset.CommunicationLinks {
+				if link.Target == technicalAsset.ID { // apply the ID change
+					link.Target = technicalAssetInput.ID
+					asset.CommunicationLinks[linkTitle] = link
+				}
+			}
+		}
+		for _, trustBoundary := range modelInput.TrustBoundaries {
+			replaceId(trustBoundary.TechnicalAssetsInside, technicalAsset.ID, technicalAssetInput.ID)
+		}
+		for _, sharedRuntime := range modelInput.SharedRuntimes {
+			replaceId(sharedRuntime.TechnicalAssetsRunning, technicalAsset.ID, technicalAssetInput.ID)
+		}
+		for _, individualRiskCat := range modelInput.CustomRiskCategories {
+			for individualRiskInstanceTitle, individualRiskInstance := range individualRiskCat.RisksIdentified {
+				if individualRiskInstance.MostRelevantTechnicalAsset == technicalAsset.ID { // apply the ID change
+					x := individualRiskCat.RisksIdentified[individualRiskInstanceTitle]
+					x.MostRelevantTechnicalAsset = technicalAssetInput.ID
+					individualRiskCat.RisksIdentified[individualRiskInstanceTitle] = x
+				}
+			}
+		}
+		for title, finding := range modelInput.PenTestFindings {
+			replaceId(finding.TechnicalAssets, technicalAsset.ID, technicalAssetInput.ID)
+			modelInput.PenTestFindings[title] = finding
+		}
+		replaceElementIdInRiskTrackingAndAnnotations(modelInput, technicalAsset.ID, technicalAssetInput.ID, true)
+	}
+	return gin.H{
+		"message":    "technical asset updated",
+		"id":         technicalAssetInput.ID,
+		"id_changed": idChanged, // in order to signal to clients, that other model parts might've received updates as well and should be reloaded
+	}, nil
+}
+
+func (s *server) deleteTechnicalAsset(ginContext *gin.Context) {
+	s.changeModel(ginContext, "Technical Asset Deletion", technicalAssetDeletion)
+}
+
+func technicalAssetDeletion(modelInput *input.Model, params gin.Params, _ func(payload any) error) (gin.H, error) {
+	title, technicalAsset, err := findTechnicalAsset(*modelInput, params)
+	if err != nil {
+		return nil, err
+	}
+	referencesDeleted := false
+	// also remove all usages of this technical asset !!
+	for _, asset := range modelInput.TechnicalAssets {
+		for linkTitle, link := range asset.CommunicationLinks {
+			if link.Target == technicalAsset.ID { // apply the removal
+				referencesDeleted = true
+				delete(asset.CommunicationLinks, linkTitle)
+			}
+		}
+	}
+	for trustBoundaryTitle, trustBoundary := range modelInput.TrustBoundaries {
+		if ids, removed := removeId(trustBoundary.TechnicalAssetsInside, technicalAsset.ID); removed {
+			referencesDeleted = true
+			trustBoundary.TechnicalAssetsInside = ids
+			modelInput.TrustBoundaries[trustBoundaryTitle] = trustBoundary
+		}
+	}
+	for sharedRuntimeTitle, sharedRuntime := range modelInput.SharedRuntimes {
+		if ids, removed := removeId(sharedRuntime.TechnicalAssetsRunning, technicalAsset.ID); removed {
+			referencesDeleted = true
+			sharedRuntime.TechnicalAssetsRunning = ids
+			modelInput.SharedRuntimes[sharedRuntimeTitle] = sharedRuntime
+		}
+	}
+	for _, individualRiskCat := range modelInput.CustomRiskCategories {
+		for individualRiskInstanceTitle, individualRiskInstance := range individualRiskCat.RisksIdentified {
+			if individualRiskInstance.MostRelevantTechnicalAsset == technicalAsset.ID { // apply the removal
+				referencesDeleted = true
+				x := individualRiskCat.RisksIdentified[individualRiskInstanceTitle]
+				x.MostRelevantTechnicalAsset = ""
+				individualRiskCat.RisksIdentified[individualRiskInstanceTitle] = x
+			}
+		}
+	}
+	// remove it itself
+	delete(modelInput.TechnicalAssets, title)
+	return gin.H{
+		"message":            "technical asset deleted",
+		"id":                 technicalAsset.ID,
+		"references_deleted": referencesDeleted, // in order to signal to clients, that other model parts might've been deleted as well
+	}, nil
+}
+
+func populateTechnicalAsset(modelInput input.Model, payload payloadTechnicalAsset) (technicalAssetInput input.TechnicalAsset, err error) {
+	if len(strings.TrimSpace(payload.Title)) == 0 {
+		return technicalAssetInput, requestError{status: http.StatusBadRequest, message: "technical asset title must not be empty"}
+	}
+	if !checkDataAssetsExisting(modelInput, payload.DataAssetsProcessed) || !checkDataAssetsExisting(modelInput, payload.DataAssetsStored) {
+		return technicalAssetInput, requestError{status: http.StatusBadRequest, message: "referenced data asset does not exist"}
+	}
+	assetType, err := types.ParseTechnicalAssetType(payload.Type)
+	if err != nil {
+		return technicalAssetInput, err
+	}
+	usage, err := types.ParseUsage(payload.Usage)
+	if err != nil {
+		return technicalAssetInput, err
+	}
+	size, err := types.ParseTechnicalAssetSize(payload.Size)
+	if err != nil {
+		return technicalAssetInput, err
+	}
+	machine, err := types.ParseTechnicalAssetMachine(payload.Machine)
+	if err != nil {
+		return technicalAssetInput, err
+	}
+	encryption, err := types.ParseEncryptionStyle(payload.Encryption)
+	if err != nil {
+		return technicalAssetInput, err
+	}
+	confidentiality, err := types.ParseConfidentiality(payload.Confidentiality)
+	if err != nil {
+		return technicalAssetInput, err
+	}
+	integrity, err := types.ParseCriticality(payload.Integrity)
+	if err != nil {
+		return technicalAssetInput, err
+	}
+	availability, err := types.ParseCriticality(payload.Availability)
+	if err != nil {
+		return technicalAssetInput, err
+	}
+	dataFormatsAccepted := make([]string, 0, len(payload.DataFormatsAccepted))
+	for _, value := range payload.DataFormatsAccepted {
+		dataFormat, err := types.ParseDataFormat(value)
+		if err != nil {
+			return technicalAssetInput, err
+		}
+		dataFormatsAccepted = append(dataFormatsAccepted, dataFormat.String())
+	}
+	technicalAssetInput = payload.TechnicalAsset
+	technicalAssetInput.Type = assetType.String()
+	technicalAssetInput.Usage = usage.String()
+	technicalAssetInput.Size = size.String()
+	technicalAssetInput.Machine = machine.String()
+	technicalAssetInput.Encryption = encryption.String()
+	technicalAssetInput.Confidentiality = confidentiality.String()
+	technicalAssetInput.Integrity = integrity.String()
+	technicalAssetInput.Availability = availability.String()
+	technicalAssetInput.DataFormatsAccepted = dataFormatsAccepted
+	technicalAssetInput.Tags = lowerCaseAndTrim(payload.Tags)
+	return technicalAssetInput, nil
+}
+
+// replaceId replaces the id in the referencing ids (in place)
+func replaceId(ids []string, oldId string, newId string) {
+	for i, id := range ids {
+		if id == oldId {
+			ids[i] = newId
+		}
+	}
+}
+
+// replaceElementIdInRiskTrackingAndAnnotations replaces the id of the element in the synthetic risk ids the risk
+// tracking is keyed by and in the elements annotated (which may be risks too), also as source of the communication
+// link ids (like asset>link) for technical assets
+func replaceElementIdInRiskTrackingAndAnnotations(modelInput *input.Model, oldId string, newId string, linkSource bool) {
+	replace := func(id string) string {
+		segments := strings.Split(id, "@")
+		for i, segment := range segments {
+			if i == 0 && len(segments) > 1 {
+				continue // the risk category
+			}
+			if segment == oldId {
+				segments[i] = newId
+			} else if linkTitle, ok := strings.CutPrefix(segment, oldId+">"); ok && linkSource {
+				segments[i] = newId + ">" + linkTitle
+			}
+		}
+		return strings.Join(segments, "@")
+	}
+	riskTracking := make(map[string]input.RiskTracking, len(modelInput.RiskTracking))
+	for syntheticRiskId, tracking := range modelInput.RiskTracking {
+		riskTracking[replace(syntheticRiskId)] = tracking
+	}
+	modelInput.RiskTracking = riskTracking
+	for i := range modelInput.Annotations {
+		modelInput.Annotations[i].Element = replace(modelInput.Annotations[i].Element)
+	}
+}
+
+// removeId answers the referencing ids without the id, and whether it was referenced
+func removeId(ids []string, removedId string) ([]string, bool) {
+	result := make([]string, 0, len(ids))
+	for _, id := range ids {
+		if id != removedId {
+			result = append(result, id)
+		}
+	}
+	return result, len(result) != len(ids)
+}
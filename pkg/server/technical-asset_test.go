@@ -0,0 +1,178 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/threagile/threagile/pkg/input"
+)
+
+const technicalAssetTestModel = `threagile_version: 1.0.0
+title: Technical Asset Test
+data_assets:
+  Customer Data:
+    id: customer-data
+technical_assets:
+  Web Server:
+    id: web-server
+    communication_links:
+      Queries:
+        target: db
+  Database:
+    id: db
+trust_boundaries:
+  Network:
+    id: network
+    type: network-on-prem
+    technical_assets_inside: [web-server, db]
+shared_runtimes:
+  Cluster:
+    id: cluster
+    technical_assets_running: [db]
+`
+
+func TestTechnicalAssetCRUD(t *testing.T) {
+	m := newModelTestServer(t, technicalAssetTestModel)
+	payload := payloadTechnicalAsset{Title: "API", TechnicalAsset: input.TechnicalAsset{ID: "api", Type: "process", Usage: "business", Size: "service",
+		Machine: "container", Encryption: "none", Confidentiality: "internal", Integrity: "important", Availability: "important",
+		Tags: []string{" REST "}, DataAssetsProcessed: []string{"customer-data"}, DataFormatsAccepted: []string{"json"},
+		CommunicationLinks: map[string]input.CommunicationLink{"Reads": {Target: "db", Protocol: "https", Authentication: "none", Authorization: "none", Usage: "business"}}}}
+
+	recorder := m.call(m.createNewTechnicalAsset, http.MethodPost, nil, payload)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	recorder = m.call(m.createNewTechnicalAsset, http.MethodPost, nil, payload)
+	assert.Equal(t, http.StatusConflict, recorder.Code)
+
+	unknownDataAsset := payload
+	unknownDataAsset.Title, unknownDataAsset.ID = "Other", "other"
+	unknownDataAsset.DataAssetsStored = []string{"unknown"}
+	recorder = m.call(m.createNewTechnicalAsset, http.MethodPost, nil, unknownDataAsset)
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+
+	assetParams := gin.Params{{Key: "technical-asset-id", Value: "api"}}
+	recorder = m.call(m.getTechnicalAsset, http.MethodGet, assetParams, nil)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	var created map[string]input.TechnicalAsset
+	assert.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &created))
+	assert.Equal(t, []string{"json"}, created["API"].DataFormatsAccepted)
+	assert.Equal(t, []string{"rest"}, created["API"].Tags)
+	assert.Equal(t, "db", created["API"].CommunicationLinks["Reads"].Target)
+
+	renamed := payloadTechnicalAsset{Title: "Database", TechnicalAsset: input.TechnicalAsset{ID: "database", Type: "datastore", Usage: "business", Size: "component",
+		Machine: "virtual", Encryption: "transparent", Confidentiality: "confidential", Integrity: "critical", Availability: "critical"}}
+	dbParams := gin.Params{{Key: "technical-asset-id", Value: "db"}}
+	recorder = m.call(m.setTechnicalAsset, http.MethodPut, dbParams, renamed)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	var updated map[string]interface{}
+	assert.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &updated))
+	assert.Equal(t, true, updated["id_changed"])
+	var boundaries map[string]input.TrustBoundary
+	var runtimes map[string]input.SharedRuntime
+	var assets map[string]input.TechnicalAsset
+	read := func() {
+		assert.NoError(t, json.Unmarshal(m.call(m.getTrustBoundaries, http.MethodGet, nil, nil).Body.Bytes(), &boundaries))
+		assert.NoError(t, json.Unmarshal(m.call(m.getSharedRuntimes, http.MethodGet, nil, nil).Body.Bytes(), &runtimes))
+		assert.NoError(t, json.Unmarshal(m.call(m.getTechnicalAsset, http.MethodGet, gin.Params{{Key: "technical-asset-id", Value: "web-server"}}, nil).Body.Bytes(), &assets))
+	}
+	read()
+	assert.Equal(t, "database", assets["Web Server"].CommunicationLinks["Queries"].Target)
+	assert.Equal(t, []string{"web-server", "database"}, boundaries["Network"].TechnicalAssetsInside)
+	assert.Equal(t, []string{"database"}, runtimes["Cluster"].TechnicalAssetsRunning)
+	assert.NoError(t, json.Unmarshal(m.call(m.getTechnicalAsset, http.MethodGet, assetParams, nil).Body.Bytes(), &assets))
+	assert.Equal(t, "database", assets["API"].CommunicationLinks["Reads"].Target)
+
+	dbParams[0].Value = "database"
+	recorder = m.call(m.deleteTechnicalAsset, http.MethodDelete, dbParams, nil)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	var deleted map[string]interface{}
+	assert.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &deleted))
+	assert.Equal(t, true, deleted["references_deleted"])
+	boundaries, runtimes, assets = nil, nil, nil
+	read()
+	assert.Empty(t, assets["Web Server"].CommunicationLinks)
+	assert.Equal(t, []string{"web-server"}, boundaries["Network"].TechnicalAssetsInside)
+	assert.Empty(t, runtimes["Cluster"].TechnicalAssetsRunning)
+	recorder = m.call(m.getTechnicalAsset, http.MethodGet, dbParams, nil)
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+}
+
+func TestTechnicalAssetUpdatePropagatesId(t *testing.T) {
+	m := newModelTestServer(t, technicalAssetTestModel+`findings:
+  SQL Injection:
+    id: sqli
+    technical_assets: [db]
+risk_tracking:
+  unencrypted-asset@db:
+    status: accepted
+  missing-authentication@web-server>queries@web-server@db:
+    status: mitigated
+annotations:
+  - element: db
+    note: legacy
+  - element: web-server>queries
+    note: pooled
+`)
+	dbParams := gin.Params{{Key: "technical-asset-id", Value: "db"}}
+	database := payloadTechnicalAsset{Title: "Database", TechnicalAsset: input.TechnicalAsset{ID: "database", Type: "datastore", Usage: "business", Size: "component",
+		Machine: "virtual", Encryption: "none", Confidentiality: "internal", Integrity: "important", Availability: "important"}}
+
+	overwriting := database
+	overwriting.Title = "Web Server"
+	recorder := m.call(m.setTechnicalAsset, http.MethodPut, dbParams, overwriting)
+	assert.Equal(t, http.StatusConflict, recorder.Code, "the title of another asset")
+
+	recorder = m.call(m.setTechnicalAsset, http.MethodPut, dbParams, database)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	ginContext, _ := gin.CreateTestContext(httptest.NewRecorder())
+	ginContext.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+	ginContext.Request.Header.Set("token", m.token)
+	_, key, ok := m.checkTokenToFolderName(ginContext)
+	assert.True(t, ok)
+	modelInput, _, err := m.decryptModelFile(m.modelFolder, key)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"database"}, modelInput.PenTestFindings["SQL Injection"].TechnicalAssets)
+	assert.Contains(t, modelInput.RiskTracking, "unencrypted-asset@database")
+	assert.Contains(t, modelInput.RiskTracking, "missing-authentication@web-server>queries@web-server@database")
+	assert.Equal(t, "database", modelInput.Annotations[0].Element)
+	assert.Equal(t, "web-server>queries", modelInput.Annotations[1].Element)
+
+	webServerParams := gin.Params{{Key: "technical-asset-id", Value: "web-server"}}
+	webServer := payloadTechnicalAsset{Title: "Web Server", TechnicalAsset: input.TechnicalAsset{ID: "web", Type: "process", Usage: "business", Size: "application",
+		Machine: "virtual", Encryption: "none", Confidentiality: "internal", Integrity: "important", Availability: "important"}}
+	recorder = m.call(m.setTechnicalAsset, http.MethodPut, webServerParams, webServer)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	modelInput, _, err = m.decryptModelFile(m.modelFolder, key)
+	assert.NoError(t, err)
+	assert.Contains(t, modelInput.RiskTracking, "missing-authentication@web>queries@web@database")
+	assert.Equal(t, "web>queries", modelInput.Annotations[1].Element)
+}
+
+func TestUpdatesPropagateIdToRiskTrackingAndAnnotations(t *testing.T) {
+	modelInput := new(input.Model).Defaults()
+	modelInput.DataAssets["Customer Data"] = input.DataAsset{ID: "customer-data"}
+	modelInput.SharedRuntimes["Cluster"] = input.SharedRuntime{ID: "cluster"}
+	modelInput.TechnicalAssets["Web Server"] = input.TechnicalAsset{ID: "web-server", CommunicationLinks: map[string]input.CommunicationLink{"Queries": {Target: "db"}}}
+	modelInput.TechnicalAssets["Database"] = input.TechnicalAsset{ID: "db"}
+	modelInput.RiskTracking["unencrypted-communication@web-server>queries@web-server@db"] = input.RiskTracking{Status: "accepted"}
+	modelInput.Annotations = []input.ElementAnnotation{{Element: "customer-data", Note: "pii"}, {Element: "cluster", Note: "shared"}, {Element: "web-server>queries", Note: "pooled"}}
+	update := func(updateFunction func(*input.Model, gin.Params, func(payload any) error) (gin.H, error), params gin.Params, payload any) {
+		_, err := updateFunction(modelInput, params, func(target any) error {
+			data, _ := json.Marshal(payload)
+			return json.Unmarshal(data, target)
+		})
+		assert.NoError(t, err)
+	}
+
+	update(dataAssetUpdate, gin.Params{{Key: "data-asset-id", Value: "customer-data"}}, payloadDataAsset{Title: "Customer Data", DataAsset: input.DataAsset{ID: "customers",
+		Usage: "business", Quantity: "many", Confidentiality: "confidential", Integrity: "critical", Availability: "operational"}})
+	update(sharedRuntimeUpdate, gin.Params{{Key: "shared-runtime-id", Value: "cluster"}}, payloadSharedRuntime{Title: "Cluster", SharedRuntime: input.SharedRuntime{ID: "k8s"}})
+	update(communicationLinkUpdate, gin.Params{{Key: "technical-asset-id", Value: "web-server"}, {Key: "communication-link-id", Value: "web-server>queries"}},
+		payloadCommunicationLink{Title: "SQL", CommunicationLink: input.CommunicationLink{Target: "db", Protocol: "jdbc", Authentication: "none", Authorization: "none", Usage: "business"}})
+
+	assert.Equal(t, []string{"customers", "k8s", "web-server>sql"}, []string{modelInput.Annotations[0].Element, modelInput.Annotations[1].Element, modelInput.Annotations[2].Element})
+	assert.Contains(t, modelInput.RiskTracking, "unencrypted-communication@web-server>sql@web-server@db")
+}
@@ -0,0 +1,279 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/threagile/threagile/pkg/input"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	templateFetchTimeout  = 10 * time.Second
+	maxTemplateSize       = 10 * 1024 * 1024
+	templateIndexCacheTTL = 5 * time.Minute
+)
+
+// templateIdPattern keeps template ids usable in paths and as file names
+var templateIdPattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]{0,127}$`)
+
+// payloadTemplate is a model template offered by the server, from the templates folder or the remote templates index
+type payloadTemplate struct {
+	ID          string `yaml:"id" json:"id"`
+	Title       string `yaml:"title" json:"title"`
+	Description string `yaml:"description" json:"description"`
+	Source      string `yaml:"source" json:"source"` // folder or index
+}
+
+// templateIndexEntry is an entry of the remote templates index (a json array of them), the url of the model file may
+// be relative to the one of the index
+type templateIndexEntry struct {
+	ID          string `json:"id"`
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	URL         string `json:"url"`
+}
+
+// templateIndexCache keeps the templates index, so that it is fetched again only after templateIndexCacheTTL
+type templateIndexCache struct {
+	url       string
+	entries   []templateIndexEntry
+	fetchedAt time.Time
+}
+
+func (s *server) listTemplates(ginContext *gin.Context) {
+	if _, _, ok := s.checkTokenToFolderName(ginContext); !ok {
+		return
+	}
+	if !s.checkTemplatesEnabled(ginContext) {
+		return
+	}
+	templates, err := s.templates()
+	if err != nil {
+		log.Println(err)
+		respondError(ginContext, http.StatusInternalServerError, errorCodeInternal, "unable to list templates")
+		return
+	}
+	ginContext.JSON(http.StatusOK, templates)
+}
+
+func (s *server) getTemplate(ginContext *gin.Context) {
+	if _, _, ok := s.checkTokenToFolderName(ginContext); !ok {
+		return
+	}
+	yamlBytes, ok := s.readTemplate(ginContext, ginContext.Param("template-id"))
+	if ok {
+		ginContext.Data(http.StatusOK, gin.MIMEYAML, yamlBytes)
+	}
+}
+
+func (s *server) checkTemplatesEnabled(ginContext *gin.Context) bool {
+	if len(s.config.TemplatesFolder) == 0 && len(s.config.TemplatesIndexURL) == 0 {
+		respondError(ginContext, http.StatusNotFound, errorCodeDisabled, "no templates configured")
+		return false
+	}
+	return true
+}
+
+// templates lists the templates of the folder followed by the ones of the index not overridden by the folder, the
+// title and description of the folder templates are the ones of their model
+func (s *server) templates() ([]payloadTemplate, error) {
+	templates := make([]payloadTemplate, 0)
+	ids := make(map[string]bool)
+	if len(s.config.TemplatesFolder) > 0 {
+		files, err := os.ReadDir(s.config.TemplatesFolder)
+		if err != nil {
+			return nil, err
+		}
+		for _, file := range files {
+			id, isTemplate := templateIdOfFile(file.Name())
+			if file.IsDir() || !isTemplate || ids[id] {
+				continue
+			}
+			modelInput, _, err := parseTemplate(filepath.Join(s.config.TemplatesFolder, file.Name()))
+			if err != nil {
+				log.Printf("skipping template %v: %v", file.Name(), err)
+				continue
+			}
+			ids[id] = true
+			templates = append(templates, payloadTemplate{ID: id, Title: modelInput.Title, Description: modelInput.BusinessOverview.Description, Source: "folder"})
+		}
+	}
+	if len(s.config.TemplatesIndexURL) > 0 {
+		index, err := s.templateIndex()
+		if err != nil {
+			return nil, err
+		}
+		for _, entry := range index {
+			if ids[entry.ID] {
+				continue
+			}
+			ids[entry.ID] = true
+			templates = append(templates, payloadTemplate{ID: entry.ID, Title: entry.Title, Description: entry.Description, Source: "index"})
+		}
+	}
+	sort.SliceStable(templates, func(i, j int) bool {
+		return templates[i].ID < templates[j].ID
+	})
+	return templates, nil
+}
+
+// readTemplate answers the model file of the template, if it is a valid model
+func (s *server) readTemplate(ginContext *gin.Context, templateId string) ([]byte, bool) {
+	if !s.checkTemplatesEnabled(ginContext) {
+		return nil, false
+	}
+	if !templateIdPattern.MatchString(templateId) {
+		respondError(ginContext, http.StatusNotFound, errorCodeTemplateNotFound, "template not found")
+		return nil, false
+	}
+	if len(s.config.TemplatesFolder) > 0 {
+		for _, extension := range []string{".yaml", ".yml", ".json"} {
+			filename := filepath.Join(s.config.TemplatesFolder, templateId+extension)
+			if _, err := os.Stat(filename); err != nil {
+				continue
+			}
+			_, yamlBytes, err := parseTemplate(filename)
+			if err != nil {
+				log.Println(err)
+				respondError(ginContext, http.StatusInternalServerError, errorCodeInternal, "unable to read template")
+				return nil, false
+			}
+			return yamlBytes, true
+		}
+	}
+	if len(s.config.TemplatesIndexURL) > 0 {
+		index, err := s.templateIndex()
+		if err != nil {
+			log.Println(err)
+			respondError(ginContext, http.StatusInternalServerError, errorCodeInternal, "unable to read template")
+			return nil, false
+		}
+		for _, entry := range index {
+			if entry.ID != templateId {
+				continue
+			}
+			yamlBytes, err := s.fetchTemplate(entry)
+			if err != nil {
+				log.Println(err)
+				respondError(ginContext, http.StatusBadGateway, errorCodeInternal, "unable to fetch template")
+				return nil, false
+			}
+			return yamlBytes, true
+		}
+	}
+	respondError(ginContext, http.StatusNotFound, errorCodeTemplateNotFound, "template not found")
+	return nil, false
+}
+
+// templateIdOfFile is the file name without extension of model files
+func templateIdOfFile(filename string) (string, bool) {
+	extension := strings.ToLower(filepath.Ext(filename))
+	if extension != ".yaml" && extension != ".yml" && extension != ".json" {
+		return "", false
+	}
+	id := strings.TrimSuffix(filename, filepath.Ext(filename))
+	return id, templateIdPattern.MatchString(id)
+}
+
+func parseTemplate(filename string) (*input.Model, []byte, error) {
+	data, err := os.ReadFile(filepath.Clean(filename))
+	if err != nil {
+		return nil, nil, err
+	}
+	return unmarshalTemplate(filename, data)
+}
+
+// unmarshalTemplate parses the model of the template, answering its yaml (as json templates are converted)
+func unmarshalTemplate(filename string, data []byte) (*input.Model, []byte, error) {
+	modelInput := new(input.Model).Defaults()
+	err := input.Unmarshal(filename, data, modelInput)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid template %v: %w", filename, err)
+	}
+	if input.IsJSON(filename, data) {
+		data, err = yaml.Marshal(modelInput)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	return modelInput, data, nil
+}
+
+// templateIndex answers the valid entries of the templates index, fetching it if not cached (failures are not)
+func (s *server) templateIndex() ([]templateIndexEntry, error) {
+	s.templateIndexLock.Lock()
+	defer s.templateIndexLock.Unlock()
+	cache := s.templateIndexCache
+	if cache != nil && cache.url == s.config.TemplatesIndexURL && time.Since(cache.fetchedAt) < templateIndexCacheTTL {
+		return cache.entries, nil
+	}
+
+	data, err := fetch(s.config.TemplatesIndexURL)
+	if err != nil {
+		return nil, err
+	}
+	index := make([]templateIndexEntry, 0)
+	err = json.Unmarshal(data, &index)
+	if err != nil {
+		return nil, fmt.Errorf("invalid templates index %v: %w", s.config.TemplatesIndexURL, err)
+	}
+	valid := make([]templateIndexEntry, 0)
+	for _, entry := range index {
+		if !templateIdPattern.MatchString(entry.ID) || len(entry.URL) == 0 {
+			log.Printf("skipping template %q of the templates index: id or url missing", entry.ID)
+			continue
+		}
+		valid = append(valid, entry)
+	}
+	s.templateIndexCache = &templateIndexCache{url: s.config.TemplatesIndexURL, entries: valid, fetchedAt: time.Now()}
+	return valid, nil
+}
+
+func (s *server) fetchTemplate(entry templateIndexEntry) ([]byte, error) {
+	indexURL, err := url.Parse(s.config.TemplatesIndexURL)
+	if err != nil {
+		return nil, err
+	}
+	templateURL, err := indexURL.Parse(entry.URL)
+	if err != nil {
+		return nil, err
+	}
+	data, err := fetch(templateURL.String())
+	if err != nil {
+		return nil, err
+	}
+	_, yamlBytes, err := unmarshalTemplate(templateURL.Path, data)
+	return yamlBytes, err
+}
+
+func fetch(location string) ([]byte, error) {
+	client := http.Client{Timeout: templateFetchTimeout}
+	response, err := client.Get(location)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = response.Body.Close() }()
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unable to fetch %v: %v", location, response.Status)
+	}
+	data, err := io.ReadAll(io.LimitReader(response.Body, maxTemplateSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > maxTemplateSize {
+		return nil, fmt.Errorf("unable to fetch %v: larger than %d bytes", location, maxTemplateSize)
+	}
+	return data, nil
+}
@@ -0,0 +1,115 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/threagile/threagile/pkg/common"
+)
+
+func TestTemplates(t *testing.T) {
+	m := newModelTestServer(t, communicationLinkTestModel)
+	request := func(handler gin.HandlerFunc, method string, path string, params gin.Params) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		ginContext, _ := gin.CreateTestContext(recorder)
+		ginContext.Request = httptest.NewRequest(method, path, nil)
+		ginContext.Request.Header.Set("token", m.token)
+		ginContext.Params = params
+		handler(ginContext)
+		return recorder
+	}
+	assert.Equal(t, http.StatusNotFound, request(m.listTemplates, http.MethodGet, "/templates", nil).Code, "none configured")
+
+	m.config.TemplatesFolder = t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(m.config.TemplatesFolder, "three-tier.yaml"), []byte("title: Three Tier Web App\nbusiness_overview:\n  description: Frontend, backend and database\n"), 0600))
+	assert.NoError(t, os.WriteFile(filepath.Join(m.config.TemplatesFolder, "broken.yaml"), []byte("title: [\n"), 0600))
+	assert.NoError(t, os.WriteFile(filepath.Join(m.config.TemplatesFolder, "notes.txt"), []byte("no template"), 0600))
+	index := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		switch request.URL.Path {
+		case "/templates.json":
+			_, _ = writer.Write([]byte(`[{"id": "serverless", "title": "Serverless", "url": "models/serverless.json"}, {"id": "three-tier", "title": "Overridden", "url": "three-tier.yaml"}]`))
+		case "/models/serverless.json":
+			_, _ = writer.Write([]byte(`{"title": "Serverless App"}`))
+		default:
+			writer.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer index.Close()
+	m.config.TemplatesIndexURL = index.URL + "/templates.json"
+
+	recorder := request(m.listTemplates, http.MethodGet, "/templates", nil)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	var templates []payloadTemplate
+	assert.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &templates))
+	assert.Equal(t, []payloadTemplate{
+		{ID: "serverless", Title: "Serverless", Source: "index"},
+		{ID: "three-tier", Title: "Three Tier Web App", Description: "Frontend, backend and database", Source: "folder"},
+	}, templates)
+
+	recorder = request(m.getTemplate, http.MethodGet, "/templates/serverless", gin.Params{{Key: "template-id", Value: "serverless"}})
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "title: Serverless App", "converted to yaml")
+	assert.Equal(t, http.StatusNotFound, request(m.getTemplate, http.MethodGet, "/templates/..", gin.Params{{Key: "template-id", Value: ".."}}).Code)
+
+	recorder = request(m.createNewModel, http.MethodPost, "/models?template=three-tier", nil)
+	assert.Equal(t, http.StatusCreated, recorder.Code)
+	var created struct {
+		ID string `json:"id"`
+	}
+	assert.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &created))
+	fromTemplate := m
+	fromTemplate.modelID = created.ID
+	recorder = fromTemplate.call(m.getModel, http.MethodGet, nil, nil)
+	assert.Contains(t, recorder.Body.String(), "title: Three Tier Web App")
+
+	recorder = request(m.createNewModel, http.MethodPost, "/models?template=unknown", nil)
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+}
+
+func TestTemplateIndexIsCached(t *testing.T) {
+	fetches := 0
+	index := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		fetches++
+		_, _ = writer.Write([]byte(`[{"id": "serverless", "title": "Serverless", "url": "models/serverless.json"}]`))
+	}))
+	defer index.Close()
+	s := &server{config: &common.Config{TemplatesIndexURL: index.URL + "/templates.json"}}
+
+	for i := 0; i < 3; i++ {
+		entries, err := s.templateIndex()
+		assert.NoError(t, err)
+		assert.Len(t, entries, 1)
+	}
+	assert.Equal(t, 1, fetches)
+
+	s.templateIndexCache.fetchedAt = time.Now().Add(-templateIndexCacheTTL)
+	_, err := s.templateIndex()
+	assert.NoError(t, err)
+	assert.Equal(t, 2, fetches, "expired")
+}
+
+func TestFetchRejectsTooLargeTemplates(t *testing.T) {
+	templates := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		size := maxTemplateSize
+		if request.URL.Path == "/huge.yaml" {
+			size++
+		}
+		_, _ = writer.Write([]byte(strings.Repeat("#", size)))
+	}))
+	defer templates.Close()
+
+	data, err := fetch(templates.URL + "/large.yaml")
+	assert.NoError(t, err)
+	assert.Len(t, data, maxTemplateSize)
+	_, err = fetch(templates.URL + "/huge.yaml")
+	assert.ErrorContains(t, err, "larger than 10485760 bytes")
+}
@@ -0,0 +1,110 @@
+package server
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"net/http"
+	"path/filepath"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/threagile/threagile/pkg/common"
+)
+
+// the tenants isolate business units sharing a server: their keys (and hence models) are stored below roots of their
+// own, they have quotas of their own and optionally master keys of their own mixed into the encryption
+
+// keyFolderRoots are the folders holding the key folders, the one of the server and the ones of the tenants
+func (s *server) keyFolderRoots() []string {
+	roots := []string{filepath.Join(s.config.ServerFolder, s.config.KeyFolder)}
+	for _, tenant := range s.config.Tenants {
+		roots = append(roots, s.config.TenantKeyFolder(tenant))
+	}
+	return roots
+}
+
+// globKeyFolders globs the pattern (e.g. of files in the model folders) below the key folders of all roots
+func (s *server) globKeyFolders(pattern ...string) ([]string, error) {
+	matches := make([]string, 0)
+	for _, root := range s.keyFolderRoots() {
+		rootMatches, err := s.storage.Glob(filepath.Join(append([]string{root, "*"}, pattern...)...))
+		if err != nil {
+			return nil, err
+		}
+		matches = append(matches, rootMatches...)
+	}
+	return matches, nil
+}
+
+// folderNameFromKey finds the key folder below the root of the tenant of the key, so the tenant is derived from the key
+// (keys of no tenant, as well as unknown keys, are below the root of the server)
+func (s *server) folderNameFromKey(key []byte) string {
+	sha512Hash := hashSHA256(key)
+	for _, tenant := range s.config.Tenants {
+		folderName := filepath.Join(s.config.TenantKeyFolder(tenant), sha512Hash)
+		if _, err := s.storage.Stat(folderName); err == nil {
+			return folderName
+		}
+	}
+	return filepath.Join(s.config.ServerFolder, s.config.KeyFolder, sha512Hash)
+}
+
+// tenantOfFolder answers the tenant of the key folder (or of any folder below it), nil for the keys of no tenant
+func (s *server) tenantOfFolder(folderName string) *common.Tenant {
+	for i, tenant := range s.config.Tenants {
+		if strings.HasPrefix(folderName, s.config.TenantKeyFolder(tenant)+string(filepath.Separator)) {
+			return &s.config.Tenants[i]
+		}
+	}
+	return nil
+}
+
+// tenantOfRequest selects the tenant of a key to create by the header tenant-secret, once tenants are configured keys
+// of no tenant can not be created anymore
+func (s *server) tenantOfRequest(ginContext *gin.Context) (*common.Tenant, bool) {
+	secret := ginContext.GetHeader("tenant-secret")
+	if len(s.config.Tenants) == 0 && len(secret) == 0 {
+		return nil, true
+	}
+	for i, tenant := range s.config.Tenants {
+		if subtle.ConstantTimeCompare([]byte(secret), []byte(tenant.Secret)) == 1 {
+			return &s.config.Tenants[i], true
+		}
+	}
+	respondError(ginContext, http.StatusNotFound, errorCodeTenantNotFound, "tenant not found")
+	return nil, false
+}
+
+func (s *server) maxAnalysesPerHour(folderNameOfKey string) int {
+	if quota := s.keyQuota(folderNameOfKey); quota.MaxAnalysesPerHour > 0 {
+		return quota.MaxAnalysesPerHour
+	}
+	if tenant := s.tenantOfFolder(folderNameOfKey); tenant != nil && tenant.MaxAnalysesPerHour > 0 {
+		return tenant.MaxAnalysesPerHour
+	}
+	return s.config.MaxAnalysesPerHour
+}
+
+func (s *server) maxModelsPerKey(folderNameOfKey string) int {
+	if quota := s.keyQuota(folderNameOfKey); quota.MaxModels > 0 {
+		return quota.MaxModels
+	}
+	if tenant := s.tenantOfFolder(folderNameOfKey); tenant != nil && tenant.MaxModelsPerKey > 0 {
+		return tenant.MaxModelsPerKey
+	}
+	return s.config.MaxModelsPerKey
+}
+
+// cryptoKey derives the encryption key of the stored data of the key, mixing in the master key of its tenant (if any) so
+// the stored data of the tenant can not be decrypted with the key alone
+func (s *server) cryptoKey(key []byte) []byte {
+	cryptoKey := generateKeyFromAlreadyStrongRandomInput(key)
+	tenant := s.tenantOfFolder(s.folderNameFromKey(key))
+	if tenant == nil || len(tenant.MasterKey) == 0 {
+		return cryptoKey
+	}
+	mac := hmac.New(sha256.New, []byte(tenant.MasterKey))
+	mac.Write(cryptoKey)
+	return mac.Sum(nil)
+}
@@ -0,0 +1,102 @@
+package server
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/threagile/threagile/pkg/common"
+)
+
+func newTenantTestServer() *server {
+	return &server{
+		config: &common.Config{ServerFolder: "/server", KeyFolder: "keys", MaxAnalysesPerHour: 10, Tenants: []common.Tenant{
+			{Name: "payments", Secret: "payments-secret", MaxAnalysesPerHour: 2, MasterKey: "payments-master-key"},
+			{Name: "shop", Secret: "shop-secret"},
+		}},
+		createdObjectsThrottler:     make(map[string][]int64),
+		mapTokenHashToTimeoutStruct: make(map[string]timeoutStruct),
+		mapFolderNameToTokenHash:    make(map[string]string),
+		locksByFolderName:           make(map[string]*sync.Mutex),
+		storage:                     newMemoryStorage(),
+	}
+}
+
+func TestTenantIsDerivedFromKey(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	s := newTenantTestServer()
+	createKey := func(tenantSecret string) ([]byte, int) {
+		ginContext, recorder := quotaTestContext("/auth/keys")
+		ginContext.Request.Method = http.MethodPost
+		if len(tenantSecret) > 0 {
+			ginContext.Request.Header.Set("tenant-secret", tenantSecret)
+		}
+		s.createKey(ginContext)
+		var response map[string]string
+		_ = json.Unmarshal(recorder.Body.Bytes(), &response)
+		key, _ := base64.RawURLEncoding.DecodeString(response["key"])
+		return key, recorder.Code
+	}
+
+	_, code := createKey("")
+	assert.Equal(t, http.StatusNotFound, code, "servers with tenants create keys of tenants only")
+	_, code = createKey("unknown")
+	assert.Equal(t, http.StatusNotFound, code)
+
+	paymentsKey, code := createKey("payments-secret")
+	assert.Equal(t, http.StatusCreated, code)
+	shopKey, code := createKey("shop-secret")
+	assert.Equal(t, http.StatusCreated, code)
+
+	paymentsFolder := s.folderNameFromKey(paymentsKey)
+	assert.Equal(t, filepath.Join("/server", "tenants", "payments", "keys", hashSHA256(paymentsKey)), paymentsFolder)
+	assert.Equal(t, "payments", s.tenantOfFolder(folderNameForModel(paymentsFolder, "model")).Name)
+	assert.Equal(t, "shop", s.tenantOfFolder(s.folderNameFromKey(shopKey)).Name)
+	assert.True(t, strings.HasPrefix(s.folderNameFromKey([]byte("unknown")), filepath.Join("/server", "keys")))
+
+	assert.Equal(t, 2, s.maxAnalysesPerHour(paymentsFolder))
+	assert.Equal(t, 10, s.maxAnalysesPerHour(s.folderNameFromKey(shopKey)), "the quota of the server")
+
+	ginContext, recorder := quotaTestContext("/auth/tokens")
+	ginContext.Request.Header.Set("key", base64.RawURLEncoding.EncodeToString(paymentsKey))
+	s.createToken(ginContext)
+	assert.Equal(t, http.StatusCreated, recorder.Code)
+
+	ginContext, recorder = quotaTestContext("/meta/stats")
+	s.stats(ginContext)
+	assert.JSONEq(t, `{"key_count":2,"model_count":0,"success_count":0,"error_count":0}`, recorder.Body.String())
+}
+
+func TestTenantMasterKeyIsMixedIntoEncryption(t *testing.T) {
+	s := newTenantTestServer()
+	key := make([]byte, keySize)
+	copy(key, "some key of the payments tenant")
+	withoutTenant := s.cryptoKey(key)
+	assert.Equal(t, generateKeyFromAlreadyStrongRandomInput(key), withoutTenant)
+
+	assert.NoError(t, s.storage.MkdirAll(filepath.Join(s.config.TenantKeyFolder(s.config.Tenants[0]), hashSHA256(key))))
+	withMasterKey := s.cryptoKey(key)
+	assert.Len(t, withMasterKey, keySize)
+	assert.NotEqual(t, withoutTenant, withMasterKey)
+
+	ciphertext, err := encryptWithKey(withMasterKey, []byte("model"))
+	assert.NoError(t, err)
+	_, err = decryptWithKey(withoutTenant, ciphertext)
+	assert.Error(t, err, "the key alone does not decrypt the data of the tenant")
+}
+
+func TestCheckTenants(t *testing.T) {
+	config := &common.Config{ServerMode: true, ServerFolder: t.TempDir(), KeyFolder: "keys", ServerStorage: common.ServerStorageMemory}
+	config.Tenants = []common.Tenant{{Name: "../escape", Secret: "a"}}
+	assert.ErrorContains(t, config.CheckServerFolder(), "invalid tenant name")
+	config.Tenants = []common.Tenant{{Name: "a", Secret: "same"}, {Name: "b", Secret: "same"}}
+	assert.ErrorContains(t, config.CheckServerFolder(), "secret of its own")
+	config.Tenants = []common.Tenant{{Name: "a", Secret: "a"}, {Name: "a", Secret: "b"}}
+	assert.ErrorContains(t, config.CheckServerFolder(), "duplicate tenant")
+}
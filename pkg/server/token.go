@@ -7,7 +7,6 @@ package server
 import (
 	"crypto/rand"
 	"encoding/base64"
-	"fmt"
 	"log"
 	"net/http"
 	"os"
@@ -30,7 +29,15 @@ type timeoutStruct struct {
 }
 
 func (s *server) createKey(ginContext *gin.Context) {
-	ok := s.checkObjectCreationThrottler(ginContext, "KEY")
+	tenant, ok := s.tenantOfRequest(ginContext)
+	if !ok {
+		return
+	}
+	keysFolder, throttlerType := filepath.Join(s.config.ServerFolder, s.config.KeyFolder), "KEY"
+	if tenant != nil {
+		keysFolder, throttlerType = s.config.TenantKeyFolder(*tenant), "KEY@"+tenant.Name // tenants do not throttle each other
+	}
+	ok = s.checkObjectCreationThrottler(ginContext, throttlerType)
 	if !ok {
 		return
 	}
@@ -41,17 +48,13 @@ func (s *server) createKey(ginContext *gin.Context) {
 	n, err := rand.Read(keyBytesArr[:])
 	if n != keySize || err != nil {
 		log.Println(err)
-		ginContext.JSON(http.StatusInternalServerError, gin.H{
-			"error": "unable to create key",
-		})
+		respondError(ginContext, http.StatusInternalServerError, errorCodeInternal, "unable to create key")
 		return
 	}
-	err = os.MkdirAll(s.folderNameFromKey(keyBytesArr), 0700)
+	err = s.storage.MkdirAll(filepath.Join(keysFolder, hashSHA256(keyBytesArr)))
 	if err != nil {
 		log.Println(err)
-		ginContext.JSON(http.StatusInternalServerError, gin.H{
-			"error": "unable to create key",
-		})
+		respondError(ginContext, http.StatusInternalServerError, errorCodeInternal, "unable to create key")
 		return
 	}
 	ginContext.JSON(http.StatusCreated, gin.H{
@@ -96,9 +99,7 @@ func (s *server) checkObjectCreationThrottler(ginContext *gin.Context, typeName
 		s.createdObjectsThrottler[keyHash] = append(s.createdObjectsThrottler[keyHash], now)
 		return true
 	}
-	ginContext.JSON(http.StatusTooManyRequests, gin.H{
-		"error": "object creation throttling exceeded (denial-of-service protection): please wait some time and try again",
-	})
+	respondError(ginContext, http.StatusTooManyRequests, errorCodeThrottled, "object creation throttling exceeded (denial-of-service protection): please wait some time and try again")
 	return false
 }
 
@@ -109,12 +110,10 @@ func (s *server) deleteKey(ginContext *gin.Context) {
 	}
 	s.globalLock.Lock()
 	defer s.globalLock.Unlock()
-	err := os.RemoveAll(folderName)
+	err := s.storage.RemoveAll(folderName)
 	if err != nil {
 		log.Println("error during key delete: " + err.Error())
-		ginContext.JSON(http.StatusNotFound, gin.H{
-			"error": "key not found",
-		})
+		respondError(ginContext, http.StatusNotFound, errorCodeKeyNotFound, "key not found")
 		return
 	}
 	ginContext.JSON(http.StatusOK, gin.H{
@@ -138,9 +137,7 @@ func (s *server) createToken(ginContext *gin.Context) {
 	n, err := rand.Read(xorBytesArr[:])
 	if n != keySize || err != nil {
 		log.Println(err)
-		ginContext.JSON(http.StatusInternalServerError, gin.H{
-			"error": "unable to create token",
-		})
+		respondError(ginContext, http.StatusInternalServerError, errorCodeInternal, "unable to create token")
 		return
 	}
 	now := time.Now().UnixNano()
@@ -165,9 +162,7 @@ type tokenHeader struct {
 func (s *server) deleteToken(ginContext *gin.Context) {
 	header := tokenHeader{}
 	if err := ginContext.ShouldBindHeader(&header); err != nil {
-		ginContext.JSON(http.StatusNotFound, gin.H{
-			"error": "token not found",
-		})
+		respondError(ginContext, http.StatusNotFound, errorCodeTokenNotFound, "token not found")
 		return
 	}
 	token, err := base64.RawURLEncoding.DecodeString(strings.TrimSpace(header.Token))
@@ -175,9 +170,7 @@ func (s *server) deleteToken(ginContext *gin.Context) {
 		if err != nil {
 			log.Println(err)
 		}
-		ginContext.JSON(http.StatusNotFound, gin.H{
-			"error": "token not found",
-		})
+		respondError(ginContext, http.StatusNotFound, errorCodeTokenNotFound, "token not found")
 		return
 	}
 	s.globalLock.Lock()
@@ -192,9 +185,7 @@ func (s *server) checkKeyToFolderName(ginContext *gin.Context) (folderNameOfKey
 	header := keyHeader{}
 	if err := ginContext.ShouldBindHeader(&header); err != nil {
 		log.Println(err)
-		ginContext.JSON(http.StatusNotFound, gin.H{
-			"error": "key not found",
-		})
+		respondError(ginContext, http.StatusNotFound, errorCodeKeyNotFound, "key not found")
 		return folderNameOfKey, key, false
 	}
 	key, err := base64.RawURLEncoding.DecodeString(strings.TrimSpace(header.Key))
@@ -202,29 +193,27 @@ func (s *server) checkKeyToFolderName(ginContext *gin.Context) (folderNameOfKey
 		if err != nil {
 			log.Println(err)
 		}
-		ginContext.JSON(http.StatusNotFound, gin.H{
-			"error": "key not found",
-		})
+		respondError(ginContext, http.StatusNotFound, errorCodeKeyNotFound, "key not found")
 		return folderNameOfKey, key, false
 	}
 	folderNameOfKey = s.folderNameFromKey(key)
-	if _, err := os.Stat(folderNameOfKey); os.IsNotExist(err) {
+	if _, err := s.storage.Stat(folderNameOfKey); os.IsNotExist(err) {
 		log.Println(err)
-		ginContext.JSON(http.StatusNotFound, gin.H{
-			"error": "key not found",
-		})
+		respondError(ginContext, http.StatusNotFound, errorCodeKeyNotFound, "key not found")
 		return folderNameOfKey, key, false
 	}
 	return folderNameOfKey, key, true
 }
 
 func (s *server) checkTokenToFolderName(ginContext *gin.Context) (folderNameOfKey string, key []byte, ok bool) {
+	if value, granted := ginContext.Get(grantedAccessKey); granted { // authorized by a grant token (see grantAuthorization)
+		access := value.(*grantedAccess)
+		return access.folderNameOfKey, access.grantToken, true
+	}
 	header := tokenHeader{}
 	if err := ginContext.ShouldBindHeader(&header); err != nil {
 		log.Println(err)
-		ginContext.JSON(http.StatusNotFound, gin.H{
-			"error": "token not found",
-		})
+		respondError(ginContext, http.StatusNotFound, errorCodeTokenNotFound, "token not found")
 		return folderNameOfKey, key, false
 	}
 	token, err := base64.RawURLEncoding.DecodeString(strings.TrimSpace(header.Token))
@@ -232,9 +221,7 @@ func (s *server) checkTokenToFolderName(ginContext *gin.Context) (folderNameOfKe
 		if err != nil {
 			log.Println(err)
 		}
-		ginContext.JSON(http.StatusNotFound, gin.H{
-			"error": "token not found",
-		})
+		respondError(ginContext, http.StatusNotFound, errorCodeTokenNotFound, "token not found")
 		return folderNameOfKey, key, false
 	}
 	s.globalLock.Lock()
@@ -245,28 +232,19 @@ func (s *server) checkTokenToFolderName(ginContext *gin.Context) (folderNameOfKe
 		// re-create the key from token
 		key := xor(token, timeoutStruct.xorRand)
 		folderNameOfKey := s.folderNameFromKey(key)
-		if _, err := os.Stat(folderNameOfKey); os.IsNotExist(err) {
+		if _, err := s.storage.Stat(folderNameOfKey); os.IsNotExist(err) {
 			log.Println(err)
-			ginContext.JSON(http.StatusNotFound, gin.H{
-				"error": "token not found",
-			})
+			respondError(ginContext, http.StatusNotFound, errorCodeTokenNotFound, "token not found")
 			return folderNameOfKey, key, false
 		}
 		timeoutStruct.lastAccessedNanoTime = time.Now().UnixNano()
 		return folderNameOfKey, key, true
 	} else {
-		ginContext.JSON(http.StatusNotFound, gin.H{
-			"error": "token not found",
-		})
+		respondError(ginContext, http.StatusNotFound, errorCodeTokenNotFound, "token not found")
 		return folderNameOfKey, key, false
 	}
 }
 
-func (s *server) folderNameFromKey(key []byte) string {
-	sha512Hash := hashSHA256(key)
-	return filepath.Join(s.config.ServerFolder, s.config.KeyFolder, sha512Hash)
-}
-
 func (s *server) housekeepingTokenMaps() {
 	now := time.Now().UnixNano()
 	for tokenHash, val := range s.mapTokenHashToTimeoutStruct {
@@ -274,7 +252,7 @@ func (s *server) housekeepingTokenMaps() {
 			// remove all elements older than 1 minute (= 60000000000 ns) soft
 			// and all elements older than 3 minutes (= 180000000000 ns) hard
 			if now-val.lastAccessedNanoTime > 60000000000 || now-val.createdNanoTime > 180000000000 {
-				fmt.Println("About to remove a token hash from maps")
+				log.Println("About to remove a token hash from maps")
 				s.deleteTokenHashFromMaps(tokenHash)
 			}
 		} else {
@@ -0,0 +1,207 @@
+package server
+
+import (
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/threagile/threagile/pkg/input"
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+// payloadTrustBoundary is the trust boundary of the model file with its title, as the model file keys the trust
+// boundaries by title
+type payloadTrustBoundary struct {
+	Title               string `yaml:"title" json:"title" binding:"required"`
+	input.TrustBoundary `yaml:",inline"`
+}
+
+func (s *server) getTrustBoundary(ginContext *gin.Context) {
+	folderNameOfKey, key, ok := s.checkTokenToFolderName(ginContext)
+	if !ok {
+		return
+	}
+	s.lockFolder(folderNameOfKey)
+	defer s.unlockFolder(folderNameOfKey)
+	modelInput, _, ok := s.readModel(ginContext, ginContext.Param("model-id"), key, folderNameOfKey)
+	if ok {
+		// yes, here keyed by title in YAML for better readability in the YAML file itself
+		for title, trustBoundary := range modelInput.TrustBoundaries {
+			if trustBoundary.ID == ginContext.Param("trust-boundary-id") {
+				ginContext.JSON(http.StatusOK, gin.H{
+					title: trustBoundary,
+				})
+				return
+			}
+		}
+		respondError(ginContext, http.StatusNotFound, errorCodeNotFound, "trust boundary not found")
+	}
+}
+
+func (s *server) createNewTrustBoundary(ginContext *gin.Context) {
+	s.changeModel(ginContext, "Trust Boundary Creation", trustBoundaryCreation)
+}
+
+func trustBoundaryCreation(modelInput *input.Model, _ gin.Params, bindPayload func(payload any) error) (gin.H, error) {
+	payload := payloadTrustBoundary{}
+	err := bindPayload(&payload)
+	if err != nil {
+		log.Println(err)
+		return nil, errUnparsablePayload
+	}
+	// yes, here keyed by title in YAML for better readability in the YAML file itself
+	if _, exists := modelInput.TrustBoundaries[payload.Title]; exists {
+		return nil, requestError{status: http.StatusConflict, message: "trust boundary with this title already exists"}
+	}
+	// but later it will in memory keyed by its "id", so do this uniqueness check also
+	for _, trustBoundary := range modelInput.TrustBoundaries {
+		if trustBoundary.ID == payload.ID {
+			return nil, requestError{status: http.StatusConflict, message: "trust boundary with this id already exists"}
+		}
+	}
+	trustBoundaryInput, err := populateTrustBoundary(*modelInput, payload)
+	if err != nil {
+		return nil, err
+	}
+	if modelInput.TrustBoundaries == nil {
+		modelInput.TrustBoundaries = make(map[string]input.TrustBoundary)
+	}
+	modelInput.TrustBoundaries[payload.Title] = trustBoundaryInput
+	return gin.H{
+		"message": "trust boundary created",
+		"id":      trustBoundaryInput.ID,
+	}, nil
+}
+
+func (s *server) setTrustBoundary(ginContext *gin.Context) {
+	s.changeModel(ginContext, "Trust Boundary Update", trustBoundaryUpdate)
+}
+
+func trustBoundaryUpdate(modelInput *input.Model, params gin.Params, bindPayload func(payload any) error) (gin.H, error) {
+	// yes, here keyed by title in YAML for better readability in the YAML file itself
+	for title, trustBoundary := range modelInput.TrustBoundaries {
+		if trustBoundary.ID != params.ByName("trust-boundary-id") {
+			continue
+		}
+		payload := payloadTrustBoundary{}
+		err := bindPayload(&payload)
+		if err != nil {
+			log.Println(err)
+			return nil, errUnparsablePayload
+		}
+		if _, exists := modelInput.TrustBoundaries[payload.Title]; exists && payload.Title != title {
+			return nil, requestError{status: http.StatusConflict, message: "trust boundary with this title already exists"}
+		}
+		for otherTitle, other := range modelInput.TrustBoundaries {
+			if otherTitle != title && other.ID == payload.ID {
+				return nil, requestError{status: http.StatusConflict, message: "trust boundary with this id already exists"}
+			}
+		}
+		trustBoundaryInput, err := populateTrustBoundary(*modelInput, payload)
+		if err != nil {
+			return nil, err
+		}
+		// in order to also update the title, remove the trust boundary from the map and re-insert it (with new key)
+		delete(modelInput.TrustBoundaries, title)
+		modelInput.TrustBoundaries[payload.Title] = trustBoundaryInput
+		idChanged := trustBoundaryInput.ID != trustBoundary.ID
+		if idChanged { // ID-CHANGE-PROPAGATION
+			for _, other := range modelInput.TrustBoundaries {
+				replaceId(other.TrustBoundariesNested, trustBoundary.ID, trustBoundaryInput.ID)
+			}
+			for _, individualRiskCat := range modelInput.CustomRiskCategories {
+				for individualRiskInstanceTitle, individualRiskInstance := range individualRiskCat.RisksIdentified {
+					if individualRiskInstance.MostRelevantTrustBoundary == trustBoundary.ID { // apply the ID change
+						x := individualRiskCat.RisksIdentified[individualRiskInstanceTitle]
+						x.MostRelevantTrustBoundary = trustBoundaryInput.ID
+						individualRiskCat.RisksIdentified[individualRiskInstanceTitle] = x
+					}
+				}
+			}
+			replaceElementIdInRiskTrackingAndAnnotations(modelInput, trustBoundary.ID, trustBoundaryInput.ID, false)
+		}
+		return gin.H{
+			"message":    "trust boundary updated",
+			"id":         trustBoundaryInput.ID,
+			"id_changed": idChanged, // in order to signal to clients, that other model parts might've received updates as well and should be reloaded
+		}, nil
+	}
+	return nil, requestError{status: http.StatusNotFound, message: "trust boundary not found"}
+}
+
+func (s *server) deleteTrustBoundary(ginContext *gin.Context) {
+	s.changeModel(ginContext, "Trust Boundary Deletion", trustBoundaryDeletion)
+}
+
+func trustBoundaryDeletion(modelInput *input.Model, params gin.Params, _ func(payload any) error) (gin.H, error) {
+	referencesDeleted := false
+	// yes, here keyed by title in YAML for better readability in the YAML file itself
+	for title, trustBoundary := range modelInput.TrustBoundaries {
+		if trustBoundary.ID != params.ByName("trust-boundary-id") {
+			continue
+		}
+		// also remove all usages of this trust boundary !!
+		for otherTitle, other := range modelInput.TrustBoundaries {
+			if ids, removed := removeId(other.TrustBoundariesNested, trustBoundary.ID); removed {
+				referencesDeleted = true
+				other.TrustBoundariesNested = ids
+				modelInput.TrustBoundaries[otherTitle] = other
+			}
+		}
+		for _, individualRiskCat := range modelInput.CustomRiskCategories {
+			for individualRiskInstanceTitle, individualRiskInstance := range individualRiskCat.RisksIdentified {
+				if individualRiskInstance.MostRelevantTrustBoundary == trustBoundary.ID { // apply the removal
+					referencesDeleted = true
+					x := individualRiskCat.RisksIdentified[individualRiskInstanceTitle]
+					x.MostRelevantTrustBoundary = ""
+					individualRiskCat.RisksIdentified[individualRiskInstanceTitle] = x
+				}
+			}
+		}
+		// remove it itself
+		delete(modelInput.TrustBoundaries, title)
+		return gin.H{
+			"message":            "trust boundary deleted",
+			"id":                 trustBoundary.ID,
+			"references_deleted": referencesDeleted, // in order to signal to clients, that other model parts might've been deleted as well
+		}, nil
+	}
+	return nil, requestError{status: http.StatusNotFound, message: "trust boundary not found"}
+}
+
+func populateTrustBoundary(modelInput input.Model, payload payloadTrustBoundary) (trustBoundaryInput input.TrustBoundary, err error) {
+	if len(strings.TrimSpace(payload.Title)) == 0 {
+		return trustBoundaryInput, requestError{status: http.StatusBadRequest, message: "trust boundary title must not be empty"}
+	}
+	if !checkTechnicalAssetsExisting(modelInput, payload.TechnicalAssetsInside) {
+		return trustBoundaryInput, requestError{status: http.StatusBadRequest, message: "referenced technical asset does not exist"}
+	}
+	for _, nestedId := range payload.TrustBoundariesNested {
+		exists := false
+		for _, trustBoundary := range modelInput.TrustBoundaries {
+			if trustBoundary.ID == nestedId && nestedId != payload.ID {
+				exists = true
+				break
+			}
+		}
+		if !exists {
+			return trustBoundaryInput, requestError{status: http.StatusBadRequest, message: "referenced trust boundary does not exist"}
+		}
+	}
+	trustBoundaryType, err := types.ParseTrustBoundary(payload.Type)
+	if err != nil {
+		return trustBoundaryInput, err
+	}
+	trustBoundaryInput = payload.TrustBoundary
+	trustBoundaryInput.Type = trustBoundaryType.String()
+	if len(payload.Criticality) > 0 { // the business criticality otherwise
+		criticality, err := types.ParseCriticality(payload.Criticality)
+		if err != nil {
+			return trustBoundaryInput, err
+		}
+		trustBoundaryInput.Criticality = criticality.String()
+	}
+	trustBoundaryInput.Tags = lowerCaseAndTrim(payload.Tags)
+	return trustBoundaryInput, nil
+}
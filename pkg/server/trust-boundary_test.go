@@ -0,0 +1,54 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/threagile/threagile/pkg/input"
+)
+
+func TestTrustBoundaryCRUD(t *testing.T) {
+	m := newModelTestServer(t, technicalAssetTestModel)
+	payload := payloadTrustBoundary{Title: "Cloud", TrustBoundary: input.TrustBoundary{ID: "cloud", Type: "network-cloud-provider",
+		Criticality: "Critical", TrustBoundariesNested: []string{"network"}}}
+
+	recorder := m.call(m.createNewTrustBoundary, http.MethodPost, nil, payload)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	recorder = m.call(m.createNewTrustBoundary, http.MethodPost, nil, payload)
+	assert.Equal(t, http.StatusConflict, recorder.Code)
+
+	invalid := payload
+	invalid.Title, invalid.ID, invalid.Type = "Other", "other", "unknown"
+	recorder = m.call(m.createNewTrustBoundary, http.MethodPost, nil, invalid)
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+	invalid.Type, invalid.TechnicalAssetsInside = "network-on-prem", []string{"unknown"}
+	recorder = m.call(m.createNewTrustBoundary, http.MethodPost, nil, invalid)
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+
+	renamed := payloadTrustBoundary{Title: "On-Prem", TrustBoundary: input.TrustBoundary{ID: "on-prem", Type: "network-on-prem", TechnicalAssetsInside: []string{"db"}}}
+	networkParams := gin.Params{{Key: "trust-boundary-id", Value: "network"}}
+	overwriting := renamed
+	overwriting.Title = "Cloud"
+	recorder = m.call(m.setTrustBoundary, http.MethodPut, networkParams, overwriting)
+	assert.Equal(t, http.StatusConflict, recorder.Code, "the title of another trust boundary")
+	recorder = m.call(m.setTrustBoundary, http.MethodPut, networkParams, renamed)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	var boundaries map[string]input.TrustBoundary
+	assert.NoError(t, json.Unmarshal(m.call(m.getTrustBoundaries, http.MethodGet, nil, nil).Body.Bytes(), &boundaries))
+	assert.Equal(t, []string{"on-prem"}, boundaries["Cloud"].TrustBoundariesNested)
+	assert.Equal(t, "critical", boundaries["Cloud"].Criticality)
+	assert.NotContains(t, boundaries, "Network")
+
+	recorder = m.call(m.getTrustBoundary, http.MethodGet, networkParams, nil)
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+	networkParams[0].Value = "on-prem"
+	recorder = m.call(m.deleteTrustBoundary, http.MethodDelete, networkParams, nil)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	boundaries = nil
+	assert.NoError(t, json.Unmarshal(m.call(m.getTrustBoundaries, http.MethodGet, nil, nil).Body.Bytes(), &boundaries))
+	assert.Len(t, boundaries, 1)
+	assert.Empty(t, boundaries["Cloud"].TrustBoundariesNested)
+}
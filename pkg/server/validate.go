@@ -0,0 +1,31 @@
+package server
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/threagile/threagile/pkg/input"
+	"github.com/threagile/threagile/pkg/model"
+	"github.com/threagile/threagile/pkg/security/risks"
+)
+
+// modelErrors parses the model like the analysis does (without running the risk rules or rendering anything) and
+// answers the reasons it is invalid for, empty for a valid model
+func (s *server) modelErrors(modelInput input.Model) []string {
+	_, err := model.ParseModel(s.config, &modelInput, risks.GetBuiltInRiskRules(), s.customRiskRules)
+	reasons := make([]string, 0)
+	for _, problem := range model.ModelProblems(err) {
+		reasons = append(reasons, problem.Message)
+	}
+	return reasons
+}
+
+// checkModelValid answers invalid models as unprocessable with the list of reasons
+func (s *server) checkModelValid(ginContext *gin.Context, modelInput input.Model) (ok bool) {
+	reasons := s.modelErrors(modelInput)
+	if len(reasons) > 0 {
+		respondError(ginContext, http.StatusUnprocessableEntity, errorCodeInvalidModel, "invalid model", reasons...)
+		return false
+	}
+	return true
+}
@@ -0,0 +1,41 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/threagile/threagile/pkg/input"
+)
+
+func TestValidateModelOnWrite(t *testing.T) {
+	stub, err := os.ReadFile(filepath.Join("..", "..", "demo", "stub", "threagile.yaml"))
+	assert.NoError(t, err)
+	m := newModelTestServer(t, string(stub))
+	m.config.ValidateModelOnWrite = true
+
+	runtimeParams := gin.Params{{Key: "shared-runtime-id", Value: "some-runtime"}}
+	invalidRuntime := payloadSharedRuntime{Title: "Some Shared Runtime", SharedRuntime: input.SharedRuntime{ID: "some-runtime", TechnicalAssetsRunning: []string{"missing-component"}}}
+	recorder := m.call(m.setSharedRuntime, http.MethodPut, runtimeParams, invalidRuntime)
+	assert.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
+	var rejected payloadError
+	assert.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rejected))
+	assert.Equal(t, errorCodeInvalidModel, rejected.Code)
+	assert.Len(t, rejected.Details, 1)
+	assert.Contains(t, rejected.Details[0], "missing-component")
+	recorder = m.call(m.getSharedRuntime, http.MethodGet, runtimeParams, nil)
+	assert.NotContains(t, recorder.Body.String(), "missing-component")
+
+	validRuntime := invalidRuntime
+	validRuntime.TechnicalAssetsRunning = []string{"some-component"}
+	recorder = m.call(m.setSharedRuntime, http.MethodPut, runtimeParams, validRuntime)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+
+	m.config.ValidateModelOnWrite = false
+	recorder = m.call(m.setSharedRuntime, http.MethodPut, runtimeParams, invalidRuntime)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+}
@@ -0,0 +1,218 @@
+package server
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/threagile/threagile/pkg/input"
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+// webhookRisksFilename is stored (encrypted like the model, as the synthetic ids name the assets) alongside the model
+// on each analysis when webhooks are configured, holding the critical risks still at risk to find the new ones next time
+const webhookRisksFilename = "webhook-risks"
+
+const (
+	webhookTimeout    = 10 * time.Second
+	webhookWorkers    = 4   // delivering the webhooks concurrently
+	webhookQueueSize  = 100 // deliveries waiting for a worker, further ones are dropped
+	webhookMaxRetries = 3   // of a delivery failed by a network error, a server error or too many requests
+)
+
+// webhookRetryDelay is doubled on each retry of a delivery
+var webhookRetryDelay = 5 * time.Second
+
+const (
+	webhookEventAnalysisCompleted = "analysis-completed"
+	webhookEventNewCriticalRisks  = "new-critical-risks"
+)
+
+type payloadWebhook struct {
+	Event            string                    `json:"event"`
+	ModelId          string                    `json:"model_id"`
+	Title            string                    `json:"title"`
+	AnalyzedAt       time.Time                 `json:"analyzed_at"`
+	Risks            map[string]map[string]int `json:"risks"` // by severity and status, like the risk statistics
+	Overdue          map[string]int            `json:"overdue"`
+	NewCriticalRisks []string                  `json:"new_critical_risks"` // synthetic ids, compared to the previous analysis
+}
+
+// webhookDelivery is a webhook waiting to be delivered to the url
+type webhookDelivery struct {
+	url   string
+	event string
+	body  []byte
+}
+
+// notifyWebhooks posts the summary of the analysis of the stored model to the configured webhooks, or only when critical
+// risks appeared compared to the previous analysis if configured so; the delivery happens in the background (retried
+// a few times) and its failures are only logged, as the analysis itself succeeded
+func (s *server) notifyWebhooks(modelFolder string, key []byte, modelId string, modelInput input.Model, outputDir string) error {
+	if len(s.config.WebhookURLs) == 0 {
+		return nil
+	}
+	statistics, risks, err := s.readAnalysisOutput(outputDir)
+	if err != nil {
+		return err
+	}
+
+	criticalRisks := make([]string, 0)
+	for _, risk := range risks {
+		if risk.Severity == types.CriticalSeverity && risk.RiskStatus.IsStillAtRisk() {
+			criticalRisks = append(criticalRisks, risk.SyntheticId)
+		}
+	}
+	sort.Strings(criticalRisks)
+	previousCriticalRisks, err := s.readWebhookRisks(modelFolder, key)
+	if err != nil {
+		return err
+	}
+	err = s.writeWebhookRisks(modelFolder, key, criticalRisks)
+	if err != nil {
+		return err
+	}
+
+	payload := payloadWebhook{
+		Event:            webhookEventAnalysisCompleted,
+		ModelId:          modelId,
+		Title:            modelInput.Title,
+		AnalyzedAt:       time.Now().UTC(),
+		Risks:            statistics.Risks,
+		Overdue:          statistics.Overdue,
+		NewCriticalRisks: make([]string, 0),
+	}
+	for _, risk := range criticalRisks {
+		if !previousCriticalRisks[risk] {
+			payload.NewCriticalRisks = append(payload.NewCriticalRisks, risk)
+		}
+	}
+	if len(payload.NewCriticalRisks) > 0 {
+		payload.Event = webhookEventNewCriticalRisks
+	} else if s.config.WebhookNewCriticalOnly {
+		return nil
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	s.webhookWorkersOnce.Do(func() {
+		s.webhookQueue = make(chan webhookDelivery, webhookQueueSize)
+		for i := 0; i < webhookWorkers; i++ {
+			go s.runWebhookWorker()
+		}
+	})
+	for _, url := range s.config.WebhookURLs {
+		select {
+		case s.webhookQueue <- webhookDelivery{url: url, event: payload.Event, body: body}:
+		default:
+			log.Println("webhook queue full, dropped the " + payload.Event + " webhook of model " + modelId)
+		}
+	}
+	return nil
+}
+
+func (s *server) runWebhookWorker() {
+	for delivery := range s.webhookQueue {
+		err := s.deliverWebhookWithRetries(delivery)
+		if err != nil {
+			log.Println(err)
+		}
+	}
+}
+
+func (s *server) deliverWebhookWithRetries(delivery webhookDelivery) error {
+	delay := webhookRetryDelay
+	for retry := 0; ; retry++ {
+		retryable, err := s.deliverWebhook(delivery.url, delivery.event, delivery.body)
+		if err == nil || !retryable || retry == webhookMaxRetries {
+			return err
+		}
+		time.Sleep(delay)
+		delay *= 2
+	}
+}
+
+// deliverWebhook posts the webhook once, telling whether a failure is worth a retry
+func (s *server) deliverWebhook(url string, event string, body []byte) (bool, error) {
+	request, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return false, fmt.Errorf("unable to create webhook request: %w", err)
+	}
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("X-Threagile-Event", event)
+	if len(s.config.WebhookSecret) > 0 {
+		timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+		request.Header.Set("X-Threagile-Timestamp", timestamp)
+		request.Header.Set("X-Threagile-Signature", webhookSignature(s.config.WebhookSecret, timestamp, body))
+	}
+
+	client := http.Client{Timeout: webhookTimeout}
+	response, err := client.Do(request)
+	if err != nil {
+		return true, fmt.Errorf("unable to deliver webhook: %w", err)
+	}
+	defer func() { _ = response.Body.Close() }()
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		retryable := response.StatusCode >= 500 || response.StatusCode == http.StatusTooManyRequests
+		return retryable, fmt.Errorf("unable to deliver webhook to %v: %v", request.URL.Redacted(), response.Status)
+	}
+	return false, nil
+}
+
+// webhookSignature lets the receivers verify the webhooks were sent by the server, like the usual webhooks of payment
+// providers: the hex HMAC-SHA256 of the timestamp (header X-Threagile-Timestamp, in unix seconds) and the body joined
+// by a dot, prefixed with the algorithm. Receivers should reject old timestamps, so that deliveries cannot be replayed.
+func webhookSignature(secret string, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	_, _ = mac.Write([]byte(timestamp + "."))
+	_, _ = mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func (s *server) readWebhookRisks(modelFolder string, key []byte) (map[string]bool, error) {
+	risks := make(map[string]bool)
+	ciphertext, err := s.storage.ReadFile(filepath.Join(modelFolder, webhookRisksFilename))
+	if os.IsNotExist(err) {
+		return risks, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	plaintext, err := s.decryptModelData(modelFolder, key, ciphertext)
+	if err != nil {
+		return nil, err
+	}
+	var ids []string
+	err = json.Unmarshal(plaintext, &ids)
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range ids {
+		risks[id] = true
+	}
+	return risks, nil
+}
+
+func (s *server) writeWebhookRisks(modelFolder string, key []byte, risks []string) error {
+	plaintext, err := json.Marshal(risks)
+	if err != nil {
+		return err
+	}
+	ciphertext, err := s.encryptModelData(modelFolder, key, plaintext)
+	if err != nil {
+		return err
+	}
+	return s.storage.WriteFile(filepath.Join(modelFolder, webhookRisksFilename), ciphertext)
+}
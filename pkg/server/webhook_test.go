@@ -0,0 +1,120 @@
+package server
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/threagile/threagile/pkg/common"
+	"github.com/threagile/threagile/pkg/input"
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+func TestNotifyWebhooks(t *testing.T) {
+	type delivery struct {
+		header http.Header
+		body   []byte
+	}
+	deliveries := make(chan delivery, 10)
+	receiver := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		body, _ := io.ReadAll(request.Body)
+		deliveries <- delivery{header: request.Header, body: body}
+	}))
+	defer receiver.Close()
+
+	s := &server{
+		config: &common.Config{
+			ServerFolder: "/server", KeyFolder: "keys", JsonStatsFilename: "stats.json", JsonRisksFilename: "risks.json",
+			WebhookURLs: []string{receiver.URL}, WebhookSecret: "secret",
+		},
+		storage: newMemoryStorage(),
+	}
+	key := []byte("0123456789abcdef0123456789abcdef")
+	modelFolder := folderNameForModel(s.folderNameFromKey(key), "model")
+	assert.NoError(t, s.storage.MkdirAll(modelFolder))
+	outputDir := t.TempDir()
+	analyze := func(risks ...*types.Risk) *payloadWebhook {
+		data, _ := json.Marshal(types.RiskStatistics{Risks: map[string]map[string]int{"critical": {"unchecked": len(risks)}}})
+		assert.NoError(t, os.WriteFile(filepath.Join(outputDir, "stats.json"), data, 0600))
+		data, _ = json.Marshal(risks)
+		assert.NoError(t, os.WriteFile(filepath.Join(outputDir, "risks.json"), data, 0600))
+		assert.NoError(t, s.notifyWebhooks(modelFolder, key, "model", input.Model{Title: "Shop"}, outputDir))
+		select {
+		case received := <-deliveries:
+			timestamp, err := strconv.ParseInt(received.header.Get("X-Threagile-Timestamp"), 10, 64)
+			assert.NoError(t, err)
+			assert.WithinDuration(t, time.Now(), time.Unix(timestamp, 0), time.Minute)
+			assert.Equal(t, webhookSignature("secret", received.header.Get("X-Threagile-Timestamp"), received.body), received.header.Get("X-Threagile-Signature"))
+			var payload payloadWebhook
+			assert.NoError(t, json.Unmarshal(received.body, &payload))
+			assert.Equal(t, payload.Event, received.header.Get("X-Threagile-Event"))
+			return &payload
+		case <-time.After(200 * time.Millisecond):
+			return nil
+		}
+	}
+	xss := &types.Risk{SyntheticId: "xss@shop", Severity: types.CriticalSeverity, RiskStatus: types.Unchecked}
+	sqli := &types.Risk{SyntheticId: "sqli@shop", Severity: types.CriticalSeverity, RiskStatus: types.Unchecked}
+
+	payload := analyze(xss)
+	assert.Equal(t, webhookEventNewCriticalRisks, payload.Event)
+	assert.Equal(t, "model", payload.ModelId)
+	assert.Equal(t, "Shop", payload.Title)
+	assert.Equal(t, []string{"xss@shop"}, payload.NewCriticalRisks)
+	assert.Equal(t, map[string]map[string]int{"critical": {"unchecked": 1}}, payload.Risks)
+
+	payload = analyze(xss)
+	assert.Equal(t, webhookEventAnalysisCompleted, payload.Event)
+	assert.Empty(t, payload.NewCriticalRisks)
+
+	s.config.WebhookNewCriticalOnly = true
+	assert.Nil(t, analyze(xss), "no new critical risk")
+	payload = analyze(xss, sqli)
+	assert.Equal(t, []string{"sqli@shop"}, payload.NewCriticalRisks)
+
+	xss.RiskStatus = types.Mitigated
+	assert.Nil(t, analyze(xss, sqli))
+	xss.RiskStatus = types.Unchecked
+	payload = analyze(xss, sqli)
+	assert.Equal(t, []string{"xss@shop"}, payload.NewCriticalRisks, "critical again after being mitigated")
+
+	ciphertext, err := s.storage.ReadFile(filepath.Join(modelFolder, webhookRisksFilename))
+	assert.NoError(t, err)
+	assert.NotContains(t, string(ciphertext), "xss@shop", "encrypted")
+}
+
+func TestWebhookRetries(t *testing.T) {
+	webhookRetryDelay = time.Millisecond
+	defer func() { webhookRetryDelay = 5 * time.Second }()
+	var attempts atomic.Int32
+	failures := int32(2)
+	status := http.StatusServiceUnavailable
+	receiver := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		if attempts.Add(1) <= failures {
+			writer.WriteHeader(status)
+		}
+	}))
+	defer receiver.Close()
+	s := &server{config: &common.Config{}}
+
+	assert.NoError(t, s.deliverWebhookWithRetries(webhookDelivery{url: receiver.URL, event: webhookEventAnalysisCompleted}))
+	assert.Equal(t, int32(3), attempts.Load())
+
+	attempts.Store(0)
+	failures = webhookMaxRetries + 1
+	assert.Error(t, s.deliverWebhookWithRetries(webhookDelivery{url: receiver.URL, event: webhookEventAnalysisCompleted}))
+	assert.Equal(t, int32(webhookMaxRetries+1), attempts.Load())
+
+	attempts.Store(0)
+	status = http.StatusNotFound
+	assert.Error(t, s.deliverWebhookWithRetries(webhookDelivery{url: receiver.URL, event: webhookEventAnalysisCompleted}))
+	assert.Equal(t, int32(1), attempts.Load(), "not retried")
+}
@@ -0,0 +1,113 @@
+package server
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const tempWorkspacePrefix = "threagile-"
+
+// TempWorkspace is the working directory of a single request below the temp folder; all temporary files of the request
+// (including those of the analysis sub-process) are created in there, so that closing it removes everything at once
+type TempWorkspace struct {
+	Dir string
+}
+
+func newTempWorkspace(tempFolder string, purpose string) (*TempWorkspace, error) {
+	dir, err := os.MkdirTemp(tempFolder, tempWorkspacePrefix+purpose+"-")
+	if err != nil {
+		return nil, fmt.Errorf("unable to create temp workspace: %w", err)
+	}
+	return &TempWorkspace{Dir: dir}, nil
+}
+
+// CreateFile creates a new file in the workspace like os.CreateTemp
+func (what *TempWorkspace) CreateFile(pattern string) (*os.File, error) {
+	return os.CreateTemp(what.Dir, pattern)
+}
+
+// Mkdir creates a sub-folder of the workspace and returns its path
+func (what *TempWorkspace) Mkdir(name string) (string, error) {
+	dir := filepath.Join(what.Dir, name)
+	return dir, os.Mkdir(dir, 0700)
+}
+
+func (what *TempWorkspace) Close() {
+	err := os.RemoveAll(what.Dir)
+	if err != nil {
+		log.Println("unable to remove temp workspace: " + err.Error())
+	}
+}
+
+// cleanStaleTempWorkspaces removes the workspaces (and loose temp files) of threagile in the temp folder of which nothing
+// was modified within the ttl, e.g. left behind when the server or a sub-process crashed mid-render
+func cleanStaleTempWorkspaces(tempFolder string, ttl time.Duration, now time.Time) (int, error) {
+	entries, err := os.ReadDir(tempFolder)
+	if err != nil {
+		return 0, err
+	}
+
+	removed := 0
+	for _, entry := range entries {
+		if !strings.HasPrefix(entry.Name(), tempWorkspacePrefix) {
+			continue
+		}
+		if modifiedSince(filepath.Join(tempFolder, entry.Name()), now.Add(-ttl)) {
+			continue
+		}
+
+		removeError := os.RemoveAll(filepath.Join(tempFolder, entry.Name()))
+		if removeError != nil {
+			log.Println("unable to remove stale temp workspace: " + removeError.Error())
+			continue
+		}
+		removed++
+	}
+	return removed, nil
+}
+
+// modifiedSince checks whether the file or anything inside the folder (as a render writing into a sub-folder only
+// leaves the mtime of the workspace itself unchanged) was modified after the given time, or could not be checked
+func modifiedSince(path string, since time.Time) bool {
+	modified := false
+	walkError := filepath.WalkDir(path, func(_ string, entry os.DirEntry, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil // removed meanwhile
+			}
+			return err
+		}
+		info, infoError := entry.Info()
+		if infoError != nil {
+			return nil // removed meanwhile
+		}
+		if info.ModTime().After(since) {
+			modified = true
+			return filepath.SkipAll
+		}
+		return nil
+	})
+	return modified || walkError != nil
+}
+
+// runTempWorkspaceJanitor cleans the stale temp workspaces on start and then periodically for the lifetime of the server
+func (s *server) runTempWorkspaceJanitor() {
+	if s.config.TempWorkspaceTTLMinutes <= 0 {
+		return
+	}
+
+	ttl := time.Duration(s.config.TempWorkspaceTTLMinutes) * time.Minute
+	for {
+		removed, err := cleanStaleTempWorkspaces(s.config.TempFolder, ttl, time.Now())
+		if err != nil {
+			log.Println("unable to clean stale temp workspaces: " + err.Error())
+		} else if removed > 0 && s.config.Verbose {
+			log.Printf("Removed %d stale temp workspaces", removed)
+		}
+		time.Sleep(ttl / 4)
+	}
+}
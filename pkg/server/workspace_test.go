@@ -0,0 +1,44 @@
+package server
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCleanStaleTempWorkspaces(t *testing.T) {
+	tempFolder := t.TempDir()
+
+	stale, err := newTempWorkspace(tempFolder, "render")
+	assert.NoError(t, err)
+	staleOutput, err := stale.Mkdir("output")
+	assert.NoError(t, err)
+	active, err := newTempWorkspace(tempFolder, "render")
+	assert.NoError(t, err)
+	activeOutput, err := active.Mkdir("output")
+	assert.NoError(t, err)
+	fresh, err := newTempWorkspace(tempFolder, "render")
+	assert.NoError(t, err)
+	foreign := filepath.Join(tempFolder, "other-app")
+	assert.NoError(t, os.Mkdir(foreign, 0700))
+
+	assert.NoError(t, os.WriteFile(filepath.Join(activeOutput, "report.pdf"), []byte("rendering"), 0600))
+	old := time.Now().Add(-3 * time.Hour)
+	for _, folder := range []string{staleOutput, stale.Dir, activeOutput, active.Dir, foreign} {
+		assert.NoError(t, os.Chtimes(folder, old, old))
+	}
+
+	removed, err := cleanStaleTempWorkspaces(tempFolder, 2*time.Hour, time.Now())
+	assert.NoError(t, err)
+	assert.Equal(t, 1, removed)
+	assert.NoDirExists(t, stale.Dir)
+	assert.DirExists(t, active.Dir, "a file written recently in a sub-folder")
+	assert.DirExists(t, fresh.Dir)
+	assert.DirExists(t, foreign)
+
+	fresh.Close()
+	assert.NoDirExists(t, fresh.Dir)
+}